@@ -0,0 +1,163 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AgentUpgradeKind is the kind of the AgentUpgrade.
+	AgentUpgradeKind = "AgentUpgrade"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={fleet,fleet-cluster},shortName=au
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.spec.version`,name="Version",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=="Succeeded")].status`,name="Succeeded",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+
+// AgentUpgrade rolls out a version of the member agent to the member clusters in a sequence of named stages, e.g. the
+// canary clusters first, then the rest of the clusters in one region after another.
+//
+// The hub agent asks the member agents of a stage to upgrade themselves, at most a number of them at a time, and the
+// next stage starts once all the member agents of the stage report the version and are joined and healthy. A member
+// agent upgrades itself by updating the image of its own deployment, which it only does when it is started with the
+// --agent-deployment flag. The progress of each stage is recorded in the status.
+//
+// At most one agent upgrade should exist at a time, as the upgrades do not coordinate with each other.
+type AgentUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of AgentUpgrade.
+	// +required
+	Spec AgentUpgradeSpec `json:"spec"`
+
+	// The observed status of AgentUpgrade.
+	// +optional
+	Status AgentUpgradeStatus `json:"status,omitempty"`
+}
+
+// AgentUpgradeSpec defines the version of the member agent and the stages of the upgrade.
+type AgentUpgradeSpec struct {
+	// AgentUpgradeTarget is the version of the member agent to upgrade to and its container image.
+	AgentUpgradeTarget `json:",inline"`
+
+	// Stages are the stages in which the member agents are upgraded, in order. The member agents of the clusters which
+	// no stage selects are not upgraded.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=31
+	// +listType=map
+	// +listMapKey=name
+	// +required
+	Stages []AgentUpgradeStage `json:"stages"`
+}
+
+// AgentUpgradeStage describes a stage of the upgrade.
+type AgentUpgradeStage struct {
+	// Name is the name of the stage, which is unique in the upgrade.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +required
+	Name string `json:"name"`
+
+	// LabelSelector selects the member clusters of the stage by their labels. A cluster selected by more than one
+	// stage belongs to the first of them. An empty selector selects all the clusters that the earlier stages do not
+	// select.
+	// +required
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+
+	// ClusterGroup is the name of a ClusterGroup. If set, the stage only selects the member clusters in the group which
+	// its label selector selects, e.g. all of them with an empty label selector.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ClusterGroup string `json:"clusterGroup,omitempty"`
+
+	// MaxConcurrency is the maximum number of the member agents of the stage which are upgraded at the same time.
+	// Default is 1.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+}
+
+// AgentUpgradeStatus defines the observed state of the AgentUpgrade.
+type AgentUpgradeStatus struct {
+	// ObservedGeneration is the generation of the upgrade which the status is computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Stages are the progress of each stage of the upgrade.
+	// +optional
+	Stages []AgentUpgradeStageStatus `json:"stages,omitempty"`
+
+	// Conditions is an array of current observed conditions of the upgrade.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// AgentUpgradeStageStatus is the progress of a stage of the upgrade.
+type AgentUpgradeStageStatus struct {
+	// StageName is the name of the stage.
+	// +required
+	StageName string `json:"stageName"`
+
+	// Clusters are the names of the clusters of the stage.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// UpgradedClusters are the names of the clusters of the stage whose member agents run the version and are joined
+	// and healthy.
+	// +optional
+	UpgradedClusters []string `json:"upgradedClusters,omitempty"`
+
+	// State is the state of the stage.
+	// +required
+	State AgentUpgradeStageState `json:"state"`
+}
+
+// AgentUpgradeStageState is the state of a stage of the upgrade.
+// +enum
+type AgentUpgradeStageState string
+
+const (
+	// AgentUpgradeStageStatePending means the stage waits for the earlier stages to succeed.
+	AgentUpgradeStageStatePending AgentUpgradeStageState = "Pending"
+
+	// AgentUpgradeStageStateUpgrading means the member agents of the stage are being upgraded.
+	AgentUpgradeStageStateUpgrading AgentUpgradeStageState = "Upgrading"
+
+	// AgentUpgradeStageStateSucceeded means all the member agents of the stage are upgraded.
+	AgentUpgradeStageStateSucceeded AgentUpgradeStageState = "Succeeded"
+)
+
+// AgentUpgradeConditionType identifies a specific condition of the AgentUpgrade.
+type AgentUpgradeConditionType string
+
+const (
+	// AgentUpgradeConditionTypeSucceeded indicates whether the member agents of all the stages are upgraded.
+	// Its condition status can be one of the following:
+	// - "True" means all the stages have succeeded.
+	// - "False" means a stage is still upgrading, or the stages are invalid.
+	AgentUpgradeConditionTypeSucceeded AgentUpgradeConditionType = "Succeeded"
+)
+
+// AgentUpgradeList contains a list of AgentUpgrade.
+// +kubebuilder:object:root=true
+type AgentUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AgentUpgrade `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AgentUpgrade{}, &AgentUpgradeList{})
+}
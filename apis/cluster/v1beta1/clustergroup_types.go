@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterGroupKind is the kind of the ClusterGroup.
+	ClusterGroupKind = "ClusterGroup"
+
+	// ClusterGroupLabelPrefix is the prefix of the labels which the hub agent keeps on the member clusters of each
+	// cluster group; the label of a group is the prefix followed by the name of the group, e.g.
+	// "group.kubernetes-fleet.io/prod-eu", and its value is always "true".
+	ClusterGroupLabelPrefix = "group.kubernetes-fleet.io/"
+
+	// ClusterGroupFinalizer is added by the hub agent to the cluster groups, so that the labels of a group are removed
+	// from its member clusters before the group is deleted.
+	ClusterGroupFinalizer = "kubernetes-fleet.io/cluster-group-cleanup"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={fleet,fleet-cluster},shortName=cg
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.status.clusterCount`,name="Clusters",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+
+// ClusterGroup is a named set of member clusters, e.g. prod-eu or edge-retail, which is defined once and referenced by
+// name in the cluster affinity terms of the placement policies and in the stages of the staged update runs, instead of
+// duplicating the same label selectors.
+//
+// The hub agent labels every member cluster of the group with the label of the group, so that the placements are
+// rescheduled as the clusters join or leave the group, the same as when the labels of the clusters change.
+//
+// The name of a group must be a valid label name, i.e. at most 63 characters.
+type ClusterGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of ClusterGroup.
+	// +required
+	Spec ClusterGroupSpec `json:"spec"`
+
+	// The observed status of ClusterGroup.
+	// +optional
+	Status ClusterGroupStatus `json:"status,omitempty"`
+}
+
+// ClusterGroupSpec defines the member clusters of the group. A cluster is in the group if it's listed by name or
+// selected by the cluster selector.
+type ClusterGroupSpec struct {
+	// Clusters are the names of the member clusters in the group.
+	// +kubebuilder:validation:MaxItems=1000
+	// +listType=set
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ClusterSelector selects the member clusters in the group by their labels. The labels of the cluster groups are
+	// ignored when the clusters are selected, i.e. a group cannot be defined by the membership of other groups. No
+	// cluster is selected if it is not set.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// ClusterGroupStatus defines the observed state of the ClusterGroup.
+type ClusterGroupStatus struct {
+	// ObservedGeneration is the generation of the group which the member clusters are resolved for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Clusters are the sorted names of the member clusters in the group.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ClusterCount is the number of the member clusters in the group.
+	// +optional
+	ClusterCount int `json:"clusterCount,omitempty"`
+}
+
+// ClusterGroupList contains a list of ClusterGroup.
+// +kubebuilder:object:root=true
+type ClusterGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterGroup{}, &ClusterGroupList{})
+}
@@ -83,6 +83,11 @@ type AgentStatus struct {
 	// Last time we received a heartbeat from the member agent.
 	// +optional
 	LastReceivedHeartbeat metav1.Time `json:"lastReceivedHeartbeat,omitempty"`
+
+	// Version is the version of the member agent, which is set when the agent is built; it is used by the agent
+	// upgrades to track which clusters run the target version.
+	// +optional
+	Version string `json:"version,omitempty"`
 }
 
 // AgentConditionType identifies a specific condition on the Agent.
@@ -107,6 +112,7 @@ const (
 	MemberClusterKind                = "MemberCluster"
 	MemberClusterResource            = "memberclusters"
 	InternalMemberClusterKind        = "InternalMemberCluster"
+	InternalMemberClusterResource    = "internalmemberclusters"
 	ClusterResourcePlacementResource = "clusterresourceplacements"
 )
 
@@ -45,6 +45,29 @@ type InternalMemberClusterSpec struct {
 	// How often (in seconds) for the member cluster to send a heartbeat to the hub cluster. Default: 60 seconds. Min: 1 second. Max: 10 minutes.
 	// +optional
 	HeartbeatPeriodSeconds int32 `json:"heartbeatPeriodSeconds,omitempty"`
+
+	// ApplyLimits limit how fast the member agent applies the works to the member cluster; they are copied from the
+	// spec of the MemberCluster.
+	// +optional
+	ApplyLimits *ApplyLimits `json:"applyLimits,omitempty"`
+
+	// AgentUpgrade is the version of the member agent that the member agent is asked to upgrade itself to; it is set
+	// by the hub agent as an agent upgrade reaches the member cluster.
+	// +optional
+	AgentUpgrade *AgentUpgradeTarget `json:"agentUpgrade,omitempty"`
+}
+
+// AgentUpgradeTarget is the version of the member agent to upgrade to.
+type AgentUpgradeTarget struct {
+	// Version is the version of the member agent, which the member agent reports once it is upgraded.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Version string `json:"version"`
+
+	// Image is the container image of the member agent of the version.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Image string `json:"image"`
 }
 
 // InternalMemberClusterStatus defines the observed state of InternalMemberCluster.
@@ -67,6 +90,11 @@ type InternalMemberClusterStatus struct {
 	// AgentStatus is an array of current observed status, each corresponding to one member agent running in the member cluster.
 	// +optional
 	AgentStatus []AgentStatus `json:"agentStatus,omitempty"`
+
+	// ManifestEncryptionPublicKey is the base64 encoded X25519 public key of the member cluster with which the hub agent
+	// encrypts the sensitive manifests in the works. It is populated by the member agent.
+	// +optional
+	ManifestEncryptionPublicKey string `json:"manifestEncryptionPublicKey,omitempty"`
 }
 
 //+kubebuilder:object:root=true
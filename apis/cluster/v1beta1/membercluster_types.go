@@ -61,6 +61,47 @@ type MemberClusterSpec struct {
 	// +kubebuilder:validation:MaxItems=100
 	// +optional
 	Taints []Taint `json:"taints,omitempty"`
+
+	// Connectivity advertises how the hub components reach the API server of the member cluster, e.g. when the
+	// member cluster is air-gapped or egress-restricted. The hub agent honors it when it connects to the member
+	// cluster, e.g. to bootstrap the member agent on a Cluster API cluster.
+	// +optional
+	Connectivity *MemberClusterConnectivity `json:"connectivity,omitempty"`
+
+	// ApplyLimits limit how fast the member agent applies the works to the member cluster, so that the hub can
+	// match them to the size of the cluster, e.g. a small edge cluster applies fewer works at a time than a cluster of
+	// hundreds of nodes. The member agent applies the works with its own defaults if it is not set.
+	// +optional
+	ApplyLimits *ApplyLimits `json:"applyLimits,omitempty"`
+}
+
+// ApplyLimits are the limits of the member agent applying the works to the member cluster.
+type ApplyLimits struct {
+	// MaxConcurrency is the max number of works the member agent applies at the same time. It is capped by the
+	// concurrency the member agent is started with.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+
+	// QPS is the max number of manifests the member agent applies to the member cluster per second.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+}
+
+// MemberClusterConnectivity describes how to reach the API server of a member cluster.
+type MemberClusterConnectivity struct {
+	// ProxyURL is the URL of the http, https or socks5 proxy through which the API server of the member cluster
+	// is reached.
+	// +kubebuilder:validation:Pattern=`^(http|https|socks5)://.+$`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// PrivateEndpoint is the https URL of the private endpoint, e.g. a private link, of the API server of the
+	// member cluster. The serving certificate is still verified against the public host name of the API server.
+	// +kubebuilder:validation:Pattern=`^https://.+$`
+	// +optional
+	PrivateEndpoint string `json:"privateEndpoint,omitempty"`
 }
 
 // PropertyName is the name of a cluster property; it should be a Kubernetes label name.
@@ -107,6 +148,11 @@ type MemberClusterStatus struct {
 	// AgentStatus is an array of current observed status, each corresponding to one member agent running in the member cluster.
 	// +optional
 	AgentStatus []AgentStatus `json:"agentStatus,omitempty"`
+
+	// ManifestEncryptionPublicKey is the base64 encoded X25519 public key of the member cluster with which the hub agent
+	// encrypts the sensitive manifests in the works. It is copied from the corresponding InternalMemberCluster object.
+	// +optional
+	ManifestEncryptionPublicKey string `json:"manifestEncryptionPublicKey,omitempty"`
 }
 
 // Taint attached to MemberCluster has the "effect" on
@@ -3,6 +3,9 @@ package v1alpha1
 const (
 	fleetPrefix = "kubernetes-fleet.io/"
 
+	// ClusterMetadataOverrideKind is the kind of the ClusterMetadataOverride.
+	ClusterMetadataOverrideKind = "ClusterMetadataOverride"
+
 	// ClusterResourceOverrideKind is the kind of the ClusterResourceOverride.
 	ClusterResourceOverrideKind = "ClusterResourceOverride"
 
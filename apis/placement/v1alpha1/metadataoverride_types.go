@@ -0,0 +1,117 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=cmo,categories={fleet,fleet-placement}
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterMetadataOverride adds labels and annotations to the selected resources on the target clusters.
+//
+// It is a lightweight alternative to the ClusterResourceOverride and ResourceOverride for the overrides which only
+// change the metadata of the resources: the resources are selected with wildcards instead of by name, and the labels
+// and annotations are merged into the resources as the works are generated, without JSON patches or snapshots.
+// The ClusterMetadataOverride is applied after the ClusterResourceOverride and ResourceOverride, so it wins when they
+// set the same label or annotation; the changes take effect on all the placements at once instead of being rolled
+// out with their rollout strategies.
+type ClusterMetadataOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of ClusterMetadataOverrideSpec.
+	// +required
+	Spec ClusterMetadataOverrideSpec `json:"spec"`
+}
+
+// ClusterMetadataOverrideSpec defines the desired state of the ClusterMetadataOverride.
+type ClusterMetadataOverrideSpec struct {
+	// ResourceSelectors is an array of selectors used to select the resources, cluster scoped or namespace scoped.
+	// The selectors are `ORed`.
+	// You can have 1-20 selectors.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=20
+	// +required
+	ResourceSelectors []MetadataResourceSelector `json:"resourceSelectors"`
+
+	// MetadataOverrideRules defines an array of rules to be applied on the selected resources.
+	// The order of the rules determines the override order.
+	// When there are two rules setting the same label or annotation on the target cluster, the last one will win.
+	// You can have 1-20 rules.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=20
+	// +required
+	MetadataOverrideRules []MetadataOverrideRule `json:"metadataOverrideRules"`
+}
+
+// MetadataResourceSelector is used to select the resources whose metadata is overridden.
+// All the fields are `ANDed`. In other words, a resource must match all the fields to be selected.
+// Each field is a shell file name pattern, e.g. `*` matches any value and `web-*` matches the values starting with
+// `web-`.
+type MetadataResourceSelector struct {
+	// Group name of the resource.
+	// Use an empty string to select resources under the core API group (e.g., services).
+	// +required
+	Group string `json:"group"`
+
+	// Version of the resource. An empty version matches any version.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind of the resource.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Kind string `json:"kind"`
+
+	// Namespace of the resource. An empty namespace matches the cluster scoped resources and the namespace scoped
+	// resources in any namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource. An empty name matches any name.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// MetadataOverrideRule defines the labels and annotations to add to the selected resources on the target clusters.
+type MetadataOverrideRule struct {
+	// ClusterSelectors selects the target clusters.
+	// The resources will be overridden before applying to the matching clusters.
+	// An empty clusterSelector selects ALL the member clusters.
+	// A nil clusterSelector selects NO member clusters.
+	// +optional
+	ClusterSelector *placementv1beta1.ClusterSelector `json:"clusterSelector,omitempty"`
+
+	// Labels are added to the selected resources; the existing labels of the same keys are replaced.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the selected resources; the existing annotations of the same keys are replaced.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ClusterMetadataOverrideList contains a list of ClusterMetadataOverride.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterMetadataOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterMetadataOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterMetadataOverride{}, &ClusterMetadataOverrideList{})
+}
@@ -15,6 +15,91 @@ import (
 	"go.goms.io/fleet/apis/placement/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMetadataOverride) DeepCopyInto(out *ClusterMetadataOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMetadataOverride.
+func (in *ClusterMetadataOverride) DeepCopy() *ClusterMetadataOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMetadataOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMetadataOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMetadataOverrideList) DeepCopyInto(out *ClusterMetadataOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterMetadataOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMetadataOverrideList.
+func (in *ClusterMetadataOverrideList) DeepCopy() *ClusterMetadataOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMetadataOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterMetadataOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMetadataOverrideSpec) DeepCopyInto(out *ClusterMetadataOverrideSpec) {
+	*out = *in
+	if in.ResourceSelectors != nil {
+		in, out := &in.ResourceSelectors, &out.ResourceSelectors
+		*out = make([]MetadataResourceSelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataOverrideRules != nil {
+		in, out := &in.MetadataOverrideRules, &out.MetadataOverrideRules
+		*out = make([]MetadataOverrideRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMetadataOverrideSpec.
+func (in *ClusterMetadataOverrideSpec) DeepCopy() *ClusterMetadataOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMetadataOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterResourceOverride) DeepCopyInto(out *ClusterResourceOverride) {
 	*out = *in
@@ -195,6 +280,55 @@ func (in *JSONPatchOverride) DeepCopy() *JSONPatchOverride {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataOverrideRule) DeepCopyInto(out *MetadataOverrideRule) {
+	*out = *in
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1beta1.ClusterSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataOverrideRule.
+func (in *MetadataOverrideRule) DeepCopy() *MetadataOverrideRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataOverrideRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataResourceSelector) DeepCopyInto(out *MetadataResourceSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataResourceSelector.
+func (in *MetadataResourceSelector) DeepCopy() *MetadataResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OverridePolicy) DeepCopyInto(out *OverridePolicy) {
 	*out = *in
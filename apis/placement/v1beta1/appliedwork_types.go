@@ -52,6 +52,58 @@ type AppliedWorkStatus struct {
 	// However, the resource will not be undeleted, so it can be removed from this list and eventual consistency is preserved.
 	// +optional
 	AppliedResources []AppliedResourceMeta `json:"appliedResources,omitempty"`
+
+	// AuditRecords are the most recent creates, updates and deletes performed by the member agent on the resources
+	// of the Work, oldest first. Older records are dropped when the limit is reached.
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	AuditRecords []AppliedResourceAuditRecord `json:"auditRecords,omitempty"`
+}
+
+// AuditOperation is the operation performed by the member agent on a resource.
+// +enum
+type AuditOperation string
+
+const (
+	// AuditOperationCreate means the resource was created.
+	AuditOperationCreate AuditOperation = "Create"
+
+	// AuditOperationUpdate means the resource was updated.
+	AuditOperationUpdate AuditOperation = "Update"
+
+	// AuditOperationDelete means the resource was deleted.
+	AuditOperationDelete AuditOperation = "Delete"
+)
+
+// AppliedResourceAuditRecord records who performed which operation on a resource, when and on behalf of which
+// placement.
+type AppliedResourceAuditRecord struct {
+	WorkResourceIdentifier `json:",inline"`
+
+	// Operation is the operation performed on the resource.
+	// +kubebuilder:validation:Enum=Create;Update;Delete
+	// +required
+	Operation AuditOperation `json:"operation"`
+
+	// Actor is the field manager used by the member agent to perform the operation.
+	// +required
+	Actor string `json:"actor"`
+
+	// Placement is the name of the placement which the resource is propagated by.
+	// +optional
+	Placement string `json:"placement,omitempty"`
+
+	// ResourceSnapshotIndex is the index of the resource snapshot which the resource is propagated from.
+	// +optional
+	ResourceSnapshotIndex string `json:"resourceSnapshotIndex,omitempty"`
+
+	// DiffSummary summarizes the fields changed by the operation.
+	// +optional
+	DiffSummary string `json:"diffSummary,omitempty"`
+
+	// Time is when the operation was performed.
+	// +required
+	Time metav1.Time `json:"time"`
 }
 
 // AppliedResourceMeta represents the group, version, resource, name and namespace of a resource.
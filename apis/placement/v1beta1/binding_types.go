@@ -66,6 +66,12 @@ type ResourceBindingSpec struct {
 	// and is owned by other appliers.
 	// +optional
 	ApplyStrategy *ApplyStrategy `json:"applyStrategy,omitempty"`
+
+	// Priority is the priority of the placement when the binding is rolled out; it is passed on to the works.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
 }
 
 // BindingState is the state of the binding.
@@ -93,6 +99,19 @@ type ResourceBindingStatus struct {
 	// +optional
 	FailedPlacements []FailedResourcePlacement `json:"failedPlacements,omitempty"`
 
+	// FailedPlacementsOverflow is the number of the failed resource placements which are left out of FailedPlacements
+	// as there are more than 100; the reported ones are the first 100 sorted by their GVKs, namespaces and names.
+	// +optional
+	FailedPlacementsOverflow int32 `json:"failedPlacementsOverflow,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=100
+
+	// LoadBalancers are the load balancers of the placed services of the LoadBalancer type on the given cluster,
+	// sorted by the namespaces and names of the services. Note that we only include 100 load balancers even if there
+	// are more than 100.
+	// +optional
+	LoadBalancers []PlacedLoadBalancer `json:"loadBalancers,omitempty"`
+
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	// +listType=map
@@ -131,6 +150,27 @@ const (
 	// - "Unknown" means it is unknown.
 	ResourceBindingWorkSynchronized ResourceBindingConditionType = "WorkSynchronized"
 
+	// ResourceBindingWorkSyncThrottled indicates that the writes of the works to the target cluster's namespace are
+	// throttled by the per-member rate limit of the hub agent.
+	// It is only set when the writes are throttled, and its condition status can be:
+	// - "True" means some works are not created or updated yet because the rate limit of the target cluster is
+	// exhausted; they are written once it allows.
+	ResourceBindingWorkSyncThrottled ResourceBindingConditionType = "WorkSyncThrottled"
+
+	// ResourceBindingClusterGone indicates that the target cluster has left the fleet or is leaving it, so that its
+	// namespace no longer accepts the works of the binding.
+	// It is only set when the target cluster is gone, and its condition status can be:
+	// - "True" means the works are no longer synchronized to the target cluster; the binding stays in this terminal
+	// state until the scheduler reschedules it away from the cluster.
+	ResourceBindingClusterGone ResourceBindingConditionType = "ClusterGone"
+
+	// ResourceBindingResourcesDeleted indicates the progress of the deletion of the resources removed from the binding,
+	// i.e. the resources which are no longer selected, or all the resources once the binding is being deleted.
+	// It is only set once some works of the binding are deleted, and its condition status can be one of the following:
+	// - "True" means all the works removed from the binding are deleted, along with their resources in the target cluster.
+	// - "False" means some works are still being deleted, as their resources are being removed from the target cluster.
+	ResourceBindingResourcesDeleted ResourceBindingConditionType = "ResourcesDeleted"
+
 	// ResourceBindingApplied indicates the applied condition of the given resources.
 	// Its condition status can be one of the following:
 	// - "True" means all the resources are created in the target cluster.
@@ -144,6 +184,14 @@ const (
 	// - "False" means not all the resources are available in the target cluster yet.
 	// - "Unknown" means we haven't finished the apply yet so that we cannot check the resource availability.
 	ResourceBindingAvailable ResourceBindingConditionType = "Available"
+
+	// ResourceBindingCanary indicates the canary phase of the binding when the placement uses the Canary rollout
+	// strategy.
+	// It is only set on the bindings of the canary clusters, and its condition status can be one of the following:
+	// - "True" means the latest resources are available on the target cluster for the soak time.
+	// - "False" means the latest resources are still rolling out to or soaking on the target cluster; the reason tells
+	// which.
+	ResourceBindingCanary ResourceBindingConditionType = "Canary"
 )
 
 // ClusterResourceBindingList is a collection of ClusterResourceBinding.
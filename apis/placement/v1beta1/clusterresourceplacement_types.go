@@ -92,8 +92,144 @@ type ClusterResourcePlacementSpec struct {
 	// +kubebuilder:default=10
 	// +optional
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// SchedulingGates is an opaque list of values that, if specified, block the scheduler from binding the selected
+	// resources to any member cluster, like the scheduling gates of a pod. External controllers remove their gates once
+	// the prerequisites of the placement, e.g. a budget approval or a security review, are complete, and the placement
+	// is scheduled once no gate is left.
+	// The scheduling gates can only be set when the ClusterResourcePlacement is created; afterward they can only be
+	// removed.
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SchedulingGates []PlacementSchedulingGate `json:"schedulingGates,omitempty"`
+
+	// BindingGates are the gates which external controllers, e.g. the ones recording the changes of the placement in a
+	// CMDB or a ticketing system, must open on each binding of the placement before it passes a point of its lifecycle,
+	// i.e. before the resources are first applied to a member cluster, or removed from a cluster which is not selected
+	// anymore. An external controller opens its gate on a binding by setting a condition of the name of the gate to
+	// True in the status of the binding, or vetoes the change by setting it to False.
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	BindingGates []PlacementBindingGate `json:"bindingGates,omitempty"`
+
+	// Priority is the priority of the placement on the member clusters. The member agents apply the works of the
+	// placements of higher priorities first when many works are waiting to be applied, e.g. when a member agent
+	// catches up after a restart, so that the critical placements like security patches are not queued behind the bulk
+	// ones. The priority is passed on to the member clusters when the placement rolls out the resources to them.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// ActivationWindow schedules when the placement is active, e.g. for the batch workloads placed nightly. The
+	// placement keeps selecting the clusters and rolling out the resources as usual, but the works are only generated
+	// and kept on the target clusters while the placement is active; outside the window, the placed resources are
+	// removed or left behind according to the inactive action of the window.
+	// The placement is always active if the window is not set.
+	// +optional
+	ActivationWindow *ActivationWindow `json:"activationWindow,omitempty"`
+}
+
+// ActivationWindow describes when a placement is active.
+type ActivationWindow struct {
+	// ActivateAt is the time from which the placement is active; the placement is active from its creation if it is
+	// not set.
+	// +optional
+	ActivateAt *metav1.Time `json:"activateAt,omitempty"`
+
+	// DeactivateAt is the time from which the placement is no longer active; the placement is active indefinitely if
+	// it is not set.
+	// +optional
+	DeactivateAt *metav1.Time `json:"deactivateAt,omitempty"`
+
+	// Daily limits the placement to be active only in a window of every day between the activateAt and deactivateAt
+	// times.
+	// +optional
+	Daily *DailyWindow `json:"daily,omitempty"`
+
+	// InactiveAction describes what happens to the placed resources while the placement is inactive. Default is
+	// "Prune".
+	// "Prune" deletes the placed resources from the target clusters.
+	// "Orphan" leaves the placed resources on the target clusters, but they are no longer managed by Fleet until the
+	// placement is active again.
+	// +kubebuilder:validation:Enum=Prune;Orphan
+	// +kubebuilder:default=Prune
+	// +optional
+	InactiveAction InactiveActionType `json:"inactiveAction,omitempty"`
+}
+
+// DailyWindow is a window of time of every day in UTC. The window spans midnight if it ends earlier than it starts,
+// e.g. from 22:00 to 06:00, and lasts the whole day if it ends when it starts.
+type DailyWindow struct {
+	// Start is the time of the day when the window starts, in the HH:MM format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +required
+	Start string `json:"start"`
+
+	// End is the time of the day when the window ends, in the HH:MM format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +required
+	End string `json:"end"`
+}
+
+// InactiveActionType describes what happens to the placed resources while their placement is inactive.
+// +enum
+type InactiveActionType string
+
+const (
+	// InactiveActionPrune deletes the placed resources while their placement is inactive.
+	InactiveActionPrune InactiveActionType = "Prune"
+
+	// InactiveActionOrphan leaves the placed resources unmanaged while their placement is inactive.
+	InactiveActionOrphan InactiveActionType = "Orphan"
+)
+
+// PlacementSchedulingGate is a gate which must be removed before the placement is scheduled.
+type PlacementSchedulingGate struct {
+	// Name of the scheduling gate, e.g. "example.com/budget-approval".
+	// Each scheduling gate must have a unique name.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=316
+	// +required
+	Name string `json:"name"`
 }
 
+// PlacementBindingGate is a gate which must be opened on a binding of the placement before the binding passes a point
+// of its lifecycle.
+type PlacementBindingGate struct {
+	// Name of the binding gate, which is also the type of the binding condition that opens the gate, e.g.
+	// "example.com/change-recorded".
+	// Each binding gate must have a unique name.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=316
+	// +required
+	Name string `json:"name"`
+
+	// Point is the point of the lifecycle of the bindings which the gate holds back.
+	// +kubebuilder:validation:Enum=BeforeApply;BeforeUnbind
+	// +required
+	Point BindingGatePoint `json:"point"`
+}
+
+// BindingGatePoint is a point of the lifecycle of a binding which a binding gate holds back.
+// +enum
+type BindingGatePoint string
+
+const (
+	// BeforeApplyBindingGatePoint holds back a binding before the resources are first applied to its member cluster,
+	// i.e. the binding of a newly selected cluster is not bound until the gate is opened.
+	BeforeApplyBindingGatePoint BindingGatePoint = "BeforeApply"
+
+	// BeforeUnbindBindingGatePoint holds back a binding before the resources are removed from its member cluster
+	// when the cluster is not selected anymore, i.e. the unscheduled binding is not deleted until the gate is opened.
+	BeforeUnbindBindingGatePoint BindingGatePoint = "BeforeUnbind"
+)
+
 // ClusterResourceSelector is used to select cluster scoped resources as the target resources to be placed.
 // If a namespace is selected, ALL the resources under the namespace are selected automatically.
 // All the fields are `ANDed`. In other words, a resource must match all the fields to be selected.
@@ -169,6 +305,93 @@ type PlacementPolicy struct {
 	// +kubebuilder:validation:MaxItems=100
 	// +optional
 	Tolerations []Toleration `json:"tolerations,omitempty"`
+
+	// ResourceRequirements declares the aggregate amount of the compute resources, e.g. cpu and memory, which the
+	// selected resources need on each cluster they are placed on, so that they do not have to be inspected one by
+	// one. The scheduler only picks the clusters whose available resources reported in their status can accommodate
+	// the requirements, and the requirements count towards the resource request limits of the placement quotas of
+	// the tenants of the placement.
+	// Only valid if the placement type is "PickAll" or "PickN".
+	// +optional
+	ResourceRequirements corev1.ResourceList `json:"resourceRequirements,omitempty"`
+
+	// SchedulerProfile is the name of the scheduler profile, i.e. the set of the scheduler plugins and their weights,
+	// which the scheduler picks the clusters with. The profiles are configured with the hub agent; the default profile
+	// is used if it is empty.
+	// Only valid if the placement type is "PickAll" or "PickN".
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	SchedulerProfile string `json:"schedulerProfile,omitempty"`
+
+	// Priority is the scheduling priority of the placement. When the scheduler cannot find enough clusters for a
+	// placement of the PickN placement type, it preempts the placements of lower priorities, i.e. removes them from the
+	// clusters whose available resources cannot accommodate the resource requirements of the placement otherwise, and
+	// picks these clusters instead. Only the placements which declare their resource requirements are preempted, as the
+	// scheduler cannot tell how much of the resources the others would free, and the placements of the PickFixed
+	// placement type are never preempted. It is not to be confused with the priority in the spec of the placement,
+	// which orders the works on the member clusters. Defaults to 0, which never preempts other placements.
+	// Only valid if the placement type is "PickAll" or "PickN".
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// ScoreWeights are the relative weights of the scores the built-in score plugins give the clusters. If set, the
+	// scheduler multiplies the scores by their weights, and prefers the clusters with the highest sum of the weighted
+	// scores; otherwise the topology spread score always comes first, then the affinity score, and the resource usage
+	// score last.
+	// Only valid if the placement type is "PickN".
+	// +optional
+	ScoreWeights *ScoreWeights `json:"scoreWeights,omitempty"`
+
+	// NetworkProximity prefers the clusters close to an anchor, i.e. a member cluster or the hub cluster, by their
+	// regions and zones, e.g. to keep a latency-sensitive workload close to its data source. The regions and zones of
+	// the member clusters are read from their well-known topology labels, i.e. "topology.kubernetes.io/region" and
+	// "topology.kubernetes.io/zone".
+	// Only valid if the placement type is "PickN".
+	// +optional
+	NetworkProximity *NetworkProximity `json:"networkProximity,omitempty"`
+}
+
+// NetworkProximity prefers the clusters in the same region or zone as an anchor.
+type NetworkProximity struct {
+	// AnchorCluster is the name of the member cluster to stay close to. The hub cluster is the anchor if it is not
+	// set, in which case the region and zone of the hub cluster need to be configured with the hub agent.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	AnchorCluster string `json:"anchorCluster,omitempty"`
+
+	// Weight is the affinity score of the clusters in the same zone as the anchor; the clusters in the same region
+	// but another zone score half of it, and the other clusters score 0.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +required
+	Weight int32 `json:"weight"`
+}
+
+// ScoreWeights are the relative weights of the scores the built-in score plugins give the clusters. A score whose
+// weight is not set is weighted 1; a score weighted 0 does not count.
+type ScoreWeights struct {
+	// Affinity is the weight of the affinity score, i.e. the sum of the weights of the preferred cluster affinity
+	// terms a cluster matches, including the scores of the property sorters, and of its network proximity score.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Affinity *int32 `json:"affinity,omitempty"`
+
+	// TopologySpread is the weight of the topology spread score, i.e. how much placing the resources on a cluster
+	// would skew the spread across the topology domains.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	TopologySpread *int32 `json:"topologySpread,omitempty"`
+
+	// ResourceUsage is the weight of the resource usage score, i.e. how much of the allocatable CPU and memory of a
+	// cluster is still available, normalized to a score between 0 and 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ResourceUsage *int32 `json:"resourceUsage,omitempty"`
 }
 
 // Affinity is a group of cluster affinity scheduling rules. More to be added.
@@ -330,6 +553,13 @@ type PropertySorter struct {
 }
 
 type ClusterSelectorTerm struct {
+	// ClusterGroup is the name of a ClusterGroup. Only the member clusters in the group are selected.
+	//
+	// If you specify a cluster group along with label or property selectors in the same term, the results are AND'd.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ClusterGroup string `json:"clusterGroup,omitempty"`
+
 	// LabelSelector is a label query over all the joined member clusters. Clusters matching
 	// the query are selected.
 	//
@@ -410,22 +640,67 @@ const (
 
 // RolloutStrategy describes how to roll out a new change in selected resources to target clusters.
 type RolloutStrategy struct {
-	// Type of rollout. The only supported type is "RollingUpdate". Default is "RollingUpdate".
+	// Type of rollout. The supported types are "RollingUpdate" and "Canary". Default is "RollingUpdate".
 	// +optional
-	// +kubebuilder:validation:Enum=RollingUpdate
+	// +kubebuilder:validation:Enum=RollingUpdate;Canary
 	// +kubebuilder:default=RollingUpdate
 	Type RolloutStrategyType `json:"type,omitempty"`
 
-	// Rolling update config params. Present only if RolloutStrategyType = RollingUpdate.
+	// Rolling update config params. Present only if RolloutStrategyType = RollingUpdate or Canary.
 	// +optional
 	RollingUpdate *RollingUpdateConfig `json:"rollingUpdate,omitempty"`
 
+	// Canary config params. Present only if RolloutStrategyType = Canary.
+	// +optional
+	Canary *CanaryConfig `json:"canary,omitempty"`
+
+	// StagedUpdateRunName is the name of the ClusterStagedUpdateRun whose stages the new resources are rolled out in.
+	// The clusters of each stage are rolled out following the rolling update config params, and the clusters of the
+	// later stages are left as they are until the earlier stages succeed. The new resources are not rolled out to any
+	// cluster while the run does not exist.
+	// +optional
+	StagedUpdateRunName string `json:"stagedUpdateRunName,omitempty"`
+
 	// ApplyStrategy describes how to resolve the conflict if the resource to be placed already exists in the target cluster
 	// and is owned by other appliers.
 	// +optional
 	ApplyStrategy *ApplyStrategy `json:"applyStrategy,omitempty"`
+
+	// DeletionStrategy describes how the placed resources are deleted from the target clusters when the placement is
+	// deleted.
+	// +optional
+	DeletionStrategy *DeletionStrategy `json:"deletionStrategy,omitempty"`
+}
+
+// DeletionStrategy describes how the placed resources are deleted from the target clusters when the placement is deleted.
+type DeletionStrategy struct {
+	// Type of deletion. Default is "Immediate".
+	// "Immediate" deletes the placed resources from all the target clusters at once, in no particular order.
+	// "ReverseOrder" deletes the placed resources in waves: from the target clusters in the reverse order in which they
+	// were selected, `MaxUnavailable` clusters at a time, and on each cluster in the reverse order of their dependencies,
+	// i.e. the namespaced resources such as the custom resources and the workloads first, then the other cluster scoped
+	// resources, then the custom resource definitions and the namespaces last. The deletion of a wave starts once the
+	// resources of the previous wave are gone, so that no namespace is stuck terminating on a custom resource whose
+	// definition or controller has been deleted first.
+	// +kubebuilder:validation:Enum=Immediate;ReverseOrder
+	// +kubebuilder:default=Immediate
+	// +optional
+	Type DeletionStrategyType `json:"type,omitempty"`
 }
 
+// DeletionStrategyType describes the type of the strategy used to delete the placed resources.
+// +enum
+type DeletionStrategyType string
+
+const (
+	// DeletionStrategyTypeImmediate deletes the placed resources from all the target clusters at once.
+	DeletionStrategyTypeImmediate DeletionStrategyType = "Immediate"
+
+	// DeletionStrategyTypeReverseOrder deletes the placed resources in waves in the reverse order of the clusters and
+	// of the dependencies of the resources.
+	DeletionStrategyTypeReverseOrder DeletionStrategyType = "ReverseOrder"
+)
+
 // ApplyStrategy describes how to resolve the conflict if the resource to be placed already exists in the target cluster
 // and whether it's allowed to be co-owned by other non-fleet appliers.
 // Note: If multiple CRPs try to place the same resource with different apply strategy, the later ones will fail with the
@@ -448,6 +723,125 @@ type ApplyStrategy struct {
 	// ServerSideApplyConfig defines the configuration for server side apply. It is honored only when type is ServerSideApply.
 	// +optional
 	ServerSideApplyConfig *ServerSideApplyConfig `json:"serverSideApplyConfig,omitempty"`
+
+	// AllowedResources restricts the resources that the member agents may create or update when applying the resources
+	// of this placement, as a defense in depth beyond the RBAC permissions of the member agents.
+	// The resources outside the allow list fail to apply with the reason ResourceNotAllowed.
+	// If not set, all the resources are allowed.
+	// +optional
+	AllowedResources *ApplyAllowList `json:"allowedResources,omitempty"`
+
+	// AvailabilityRules decide when the resources that fleet does not know how to track are available, e.g. the
+	// Crossplane claims or the Terraform workspaces which provision infrastructure, by the conditions they report.
+	// The first rule matching a resource takes precedence over the built-in availability checks.
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	AvailabilityRules []AvailabilityRule `json:"availabilityRules,omitempty"`
+
+	// ProtectNamespaces adds a deletion protection finalizer to the namespaces placed by this placement on the member
+	// clusters, so that other tooling cannot remove them while the placement is active. The member agents release the
+	// finalizer when they delete the namespaces, e.g. when the placement is deleted, the namespaces are no longer selected
+	// or the placement is evicted from the cluster.
+	// Note that the finalizer keeps the namespace object only; the resources in a namespace which is deleted out of band
+	// are still removed by Kubernetes.
+	// +optional
+	ProtectNamespaces bool `json:"protectNamespaces,omitempty"`
+
+	// SkipMissingStorageClass makes the member agents skip the PersistentVolumeClaims whose storage class does not
+	// exist on the member cluster, instead of creating claims which cannot be provisioned. The skipped claims are
+	// reported as skipped rather than failed, and are placed once the storage class is created on the cluster.
+	// +optional
+	SkipMissingStorageClass bool `json:"skipMissingStorageClass,omitempty"`
+
+	// StatusReportingScope controls how much status the member agents report in the works of this placement, so that
+	// the placements of many resources to many clusters can trade observability for the size of the hub etcd.
+	// Default to WorkloadSummaries.
+	// +kubebuilder:validation:Enum=ConditionsOnly;WorkloadSummaries;DriftDetails
+	// +optional
+	StatusReportingScope StatusReportingScope `json:"statusReportingScope,omitempty"`
+}
+
+// StatusReportingScope describes how much status the member agents report in the works of a placement.
+// +enum
+type StatusReportingScope string
+
+const (
+	// StatusReportingScopeConditionsOnly reports the conditions of the works, and the conditions of only the resources
+	// which are not applied or not available; the resources which are applied and available are left out.
+	StatusReportingScopeConditionsOnly StatusReportingScope = "ConditionsOnly"
+
+	// StatusReportingScopeWorkloadSummaries reports the conditions of all the resources, and the summaries of the
+	// workloads, e.g. the addresses of the load balancers of the services.
+	StatusReportingScopeWorkloadSummaries StatusReportingScope = "WorkloadSummaries"
+
+	// StatusReportingScopeDriftDetails reports everything in WorkloadSummaries, and the fields of the resources that
+	// the member agents revert after they are changed on the member clusters out of band.
+	StatusReportingScopeDriftDetails StatusReportingScope = "DriftDetails"
+)
+
+// AvailabilityRule regards a kind of resources as available when they report a condition as true, or as soon as they
+// are applied if they are apply-only.
+type AvailabilityRule struct {
+	// Group is the API group of the resources; use an empty string for the core group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Kind is the kind of the resources. If empty, all the kinds of the group match.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// ConditionType is the type of the condition in the status of the resources which must be true for them to be
+	// available. The condition, and the status.observedGeneration if reported, must observe the latest generation of
+	// the resources. Default: Ready.
+	// +kubebuilder:default=Ready
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+
+	// Provisioning marks the resources as provisioning infrastructure, which can take long to become available. While
+	// they are not available, the placement reports the Provisioning reason in its Available conditions instead of
+	// the reasons for the resources which are not available yet.
+	// +optional
+	Provisioning bool `json:"provisioning,omitempty"`
+
+	// ApplyOnly marks the resources as apply-only: they are regarded as available as soon as they are applied and
+	// their availability is never tracked, so they never hold back the Available condition of the placement, e.g.
+	// the custom resources whose controllers are slow to report their status. ConditionType and Provisioning are
+	// ignored if it is set.
+	// +optional
+	ApplyOnly bool `json:"applyOnly,omitempty"`
+}
+
+// ApplyAllowList is the list of namespaces and resource kinds that the member agents may apply.
+// A resource is allowed only if both its namespace and its kind are allowed.
+type ApplyAllowList struct {
+	// Namespaces is the list of namespaces in which the namespaced resources may be applied. The namespaces in the list
+	// may be applied as well. It does not restrict the other cluster scoped resources.
+	// If empty, all the namespaces are allowed.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Kinds is the list of resource kinds that may be applied.
+	// If empty, all the kinds are allowed.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Kinds []AllowedResourceKind `json:"kinds,omitempty"`
+}
+
+// AllowedResourceKind identifies a kind of resources that may be applied.
+type AllowedResourceKind struct {
+	// Group is the API group of the resources; use an empty string for the core group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version is the API version of the resources. If empty, all the versions are allowed.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind is the kind of the resources.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Kind string `json:"kind"`
 }
 
 // ApplyStrategyType describes the type of the strategy used to resolve the conflict if the resource to be placed already
@@ -464,6 +858,8 @@ const (
 	// ApplyStrategyTypeServerSideApply will use server-side apply to resolve conflicts between the resource to be placed
 	// and the existing resource in the target cluster.
 	// Details: https://kubernetes.io/docs/reference/using-api/server-side-apply
+	// The fields of the existing resource which were client-side applied by fleet and are in the resource to be placed
+	// are migrated to the server-side apply field manager first, so that they do not conflict with fleet itself.
 	ApplyStrategyTypeServerSideApply ApplyStrategyType = "ServerSideApply"
 )
 
@@ -488,8 +884,34 @@ const (
 	// RollingUpdateRolloutStrategyType replaces the old placed resource using rolling update
 	// i.e. gradually create the new one while replace the old ones.
 	RollingUpdateRolloutStrategyType RolloutStrategyType = "RollingUpdate"
+
+	// CanaryRolloutStrategyType first rolls the new resources out to a few canary clusters using rolling update, waits
+	// for them to be available and to soak, then rolls the new resources out to the rest of the clusters.
+	CanaryRolloutStrategyType RolloutStrategyType = "Canary"
 )
 
+// CanaryConfig contains the config to control the desired behavior of the canary rollout.
+type CanaryConfig struct {
+	// Clusters is the number of the target clusters which receive the new resources first.
+	// Value can be an absolute number (ex: 5) or a percentage of the desired number of clusters (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up, and the minimum is 1.
+	// The clusters which already run the new resources are picked first, then the others by their names.
+	// Defaults to 10%.
+	// +kubebuilder:default="10%"
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Pattern="^((100|[0-9]{1,2})%|[0-9]+)$"
+	// +optional
+	Clusters *intstr.IntOrString `json:"clusters,omitempty"`
+
+	// SoakSeconds is how long the new resources must stay available on all the canary clusters before they are rolled
+	// out to the rest of the clusters.
+	// Default is 300.
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SoakSeconds *int `json:"soakSeconds,omitempty"`
+}
+
 // RollingUpdateConfig contains the config to control the desired behavior of rolling update.
 type RollingUpdateConfig struct {
 	// The maximum number of clusters that can be unavailable during the rolling update
@@ -563,6 +985,24 @@ type ClusterResourcePlacementStatus struct {
 	// +optional
 	PlacementStatuses []ResourcePlacementStatus `json:"placementStatuses,omitempty"`
 
+	// PlacementStatusSummary is set when the status is compacted for a placement which selects many clusters. In this
+	// case, PlacementStatuses only contains the placement statuses of the unhealthy clusters and the clusters which
+	// cannot be scheduled, and the placement status on every selected cluster is kept in a PerClusterPlacementStatus
+	// object instead, which is named after the placement in the reserved namespace of the cluster and labeled with
+	// `CRPTrackingLabel`.
+	// To get the placement statuses on all the selected clusters, use the following command:
+	// `kubectl get PerClusterPlacementStatus -A --selector=kubernetes-fleet.io/parent-CRP=$PlacementName`
+	// +optional
+	PlacementStatusSummary *PlacementStatusSummary `json:"placementStatusSummary,omitempty"`
+
+	// RemovingClusters contains the clusters which are not selected by the placement anymore but still have the
+	// selected resources, e.g. after the number of clusters of a PickN placement is reduced, sorted by the cluster name.
+	// The resources are removed from those clusters as the rollout strategy allows, and a cluster is removed from the
+	// list once its resources are deleted.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	RemovingClusters []ClusterRemovalStatus `json:"removingClusters,omitempty"`
+
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	// +listType=map
@@ -573,6 +1013,49 @@ type ClusterResourcePlacementStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// PlacementStatusSummary summarizes the placement statuses of a ClusterResourcePlacement whose status is compacted.
+type PlacementStatusSummary struct {
+	// SelectedClusters is the number of the clusters selected by the placement, each of which has a
+	// PerClusterPlacementStatus object.
+	// +required
+	SelectedClusters int32 `json:"selectedClusters"`
+
+	// UnhealthyClusters is the number of the selected clusters which have a false condition or failed resource
+	// placements; their placement statuses are kept in PlacementStatuses.
+	// +required
+	UnhealthyClusters int32 `json:"unhealthyClusters"`
+}
+
+// ClusterRemovalPhase is the phase of removing the selected resources from a cluster which is not selected anymore.
+// +enum
+type ClusterRemovalPhase string
+
+const (
+	// ClusterRemovalPhasePending means that the resources are waiting to be removed as the rollout strategy does not
+	// allow more clusters to become unavailable yet.
+	ClusterRemovalPhasePending ClusterRemovalPhase = "Pending"
+
+	// ClusterRemovalPhaseDeleting means that the resources are being deleted from the cluster.
+	ClusterRemovalPhaseDeleting ClusterRemovalPhase = "Deleting"
+)
+
+// ClusterRemovalStatus describes the progress of removing the selected resources from a cluster which is not selected
+// by the placement anymore.
+type ClusterRemovalStatus struct {
+	// ClusterName is the name of the cluster.
+	// +required
+	ClusterName string `json:"clusterName"`
+
+	// Phase is the phase of the removal.
+	// +kubebuilder:validation:Enum=Pending;Deleting
+	// +required
+	Phase ClusterRemovalPhase `json:"phase"`
+
+	// Message is a human readable message about the progress of the removal.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ResourceIdentifier identifies one Kubernetes resource.
 type ResourceIdentifier struct {
 	// Group is the group name of the selected resource.
@@ -611,7 +1094,7 @@ type EnvelopeIdentifier struct {
 	Namespace string `json:"namespace,omitempty"`
 
 	// Type of the envelope object.
-	// +kubebuilder:validation:Enum=ConfigMap
+	// +kubebuilder:validation:Enum=ConfigMap;FluxSource
 	// +kubebuilder:default=ConfigMap
 	// +optional
 	Type EnvelopeType `json:"type"`
@@ -624,6 +1107,10 @@ type EnvelopeType string
 const (
 	// ConfigMapEnvelopeType means the envelope object is of type `ConfigMap`.
 	ConfigMapEnvelopeType EnvelopeType = "ConfigMap"
+
+	// FluxSourceEnvelopeType means the envelope object is of type `ConfigMap` which contains the Flux objects that
+	// reconcile a Flux source.
+	FluxSourceEnvelopeType EnvelopeType = "FluxSource"
 )
 
 // ResourcePlacementStatus represents the placement status of selected resources for one target cluster.
@@ -655,6 +1142,21 @@ type ResourcePlacementStatus struct {
 	// +optional
 	FailedPlacements []FailedResourcePlacement `json:"failedPlacements,omitempty"`
 
+	// FailedPlacementsOverflow is the number of the failed resource placements which are left out of FailedPlacements
+	// as there are more than 100; the reported ones are the first 100 sorted by their GVKs, namespaces and names.
+	// This field is only meaningful if the `ClusterName` is not empty.
+	// +optional
+	FailedPlacementsOverflow int32 `json:"failedPlacementsOverflow,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=100
+
+	// LoadBalancers are the load balancers of the placed services of the LoadBalancer type on the given cluster,
+	// sorted by the namespaces and names of the services. Note that we only include 100 load balancers even if there
+	// are more than 100.
+	// This field is only meaningful if the `ClusterName` is not empty.
+	// +optional
+	LoadBalancers []PlacedLoadBalancer `json:"loadBalancers,omitempty"`
+
 	// Conditions is an array of current observed conditions for ResourcePlacementStatus.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -671,6 +1173,17 @@ type FailedResourcePlacement struct {
 	Condition metav1.Condition `json:"condition"`
 }
 
+// PlacedLoadBalancer is the load balancer of a placed service of the LoadBalancer type on a member cluster.
+type PlacedLoadBalancer struct {
+	// The placed service.
+	// +required
+	ResourceIdentifier `json:",inline"`
+
+	// The status of the load balancer of the service.
+	// +required
+	ServiceLoadBalancerStatus `json:",inline"`
+}
+
 // Toleration allows ClusterResourcePlacement to tolerate any taint that matches
 // the triple <key,value,effect> using the matching operator <operator>.
 type Toleration struct {
@@ -757,6 +1270,22 @@ const (
 	// array.
 	// - "Unknown" means we haven't finished the apply yet so that we cannot check the resource availability.
 	ClusterResourcePlacementAvailableConditionType ClusterResourcePlacementConditionType = "ClusterResourcePlacementAvailable"
+
+	// ClusterResourcePlacementDeletingConditionType indicates the progress of deleting the placed resources from the
+	// target clusters in waves when the ClusterResourcePlacement is deleted with the ReverseOrder deletion strategy.
+	// Its condition status can be one of the following:
+	// - "True" means the placed resources are being deleted; the message tells the clusters of the current wave and
+	// the number of the clusters left.
+	ClusterResourcePlacementDeletingConditionType ClusterResourcePlacementConditionType = "ClusterResourcePlacementDeleting"
+
+	// ClusterResourcePlacementCanaryConditionType indicates the progress of rolling out the latest resources to the
+	// canary clusters when the ClusterResourcePlacement uses the Canary rollout strategy.
+	// Its condition status can be one of the following:
+	// - "True" means the latest resources are available on all the canary clusters for the soak time, and are rolled
+	// out to the rest of the clusters.
+	// - "False" means the latest resources are still rolling out to or soaking on the canary clusters; the message tells
+	// how many canary clusters are done.
+	ClusterResourcePlacementCanaryConditionType ClusterResourcePlacementConditionType = "ClusterResourcePlacementCanary"
 )
 
 // ResourcePlacementConditionType defines a specific condition of a resource placement.
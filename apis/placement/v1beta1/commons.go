@@ -12,6 +12,7 @@ const (
 	ClusterResourceSnapshotKind         = "ClusterResourceSnapshot"
 	ClusterSchedulingPolicySnapshotKind = "ClusterSchedulingPolicySnapshot"
 	WorkKind                            = "Work"
+	WorkResource                        = "works"
 	AppliedWorkKind                     = "AppliedWork"
 )
 
@@ -24,14 +25,55 @@ const (
 	// MemberClusterFinalizer is used to make sure that we handle gc of all the member cluster resources on the hub cluster.
 	MemberClusterFinalizer = fleetPrefix + "membercluster-finalizer"
 
+	// MemberClusterNameLabel is the label that contains the name of the member cluster which an object on the hub cluster,
+	// e.g. a certificate signing request created by the member agent, belongs to.
+	MemberClusterNameLabel = fleetPrefix + "member-cluster-name"
+
+	// ClusterAPIAutoRegisterLabel is the label with which a Cluster API Cluster opts in to be registered as a member
+	// cluster automatically once it is provisioned.
+	ClusterAPIAutoRegisterLabel = fleetPrefix + "auto-register"
+
+	// ClusterAPIRegistrationFinalizer is used to make sure that the member cluster registered for a Cluster API Cluster
+	// is removed from the fleet before the Cluster is deleted.
+	ClusterAPIRegistrationFinalizer = fleetPrefix + "cluster-api-registration-cleanup"
+
+	// ClusterAPIClusterNamespaceLabel is the label that contains the namespace of the Cluster API Cluster which a member
+	// cluster is registered for.
+	ClusterAPIClusterNamespaceLabel = fleetPrefix + "cluster-api-cluster-namespace"
+
+	// ClusterAPIClusterNameLabel is the label that contains the name of the Cluster API Cluster which a member cluster
+	// is registered for.
+	ClusterAPIClusterNameLabel = fleetPrefix + "cluster-api-cluster-name"
+
+	// ClusterAPIBootstrapHashAnnotation is the annotation on a member cluster registered for a Cluster API Cluster that
+	// records the hash of the bootstrap manifests last applied to the cluster.
+	ClusterAPIBootstrapHashAnnotation = fleetPrefix + "cluster-api-bootstrap-hash"
+
 	// WorkFinalizer is used by the work generator to make sure that the binding is not deleted until the work objects
 	// it generates are all deleted, or used by the work controller to make sure the work has been deleted in the member
 	// cluster.
 	WorkFinalizer = fleetPrefix + "work-cleanup"
 
+	// NamespaceProtectionFinalizer is added by the member agent to the namespaces placed by the placements which
+	// protect their namespaces, so that the namespaces are not removed until the member agent releases them.
+	NamespaceProtectionFinalizer = fleetPrefix + "namespace-protection"
+
 	// CRPTrackingLabel is the label that points to the cluster resource policy that creates a resource binding.
 	CRPTrackingLabel = fleetPrefix + "parent-CRP"
 
+	// ShardLabel is the label that pins a cluster resource placement to a shard, which one replica of the hub agent
+	// holds at a time, when the placements are sharded; the value is the index of the shard modulo the number of the
+	// shards.
+	ShardLabel = fleetPrefix + "shard"
+
+	// RelayedFromLabel is added by the member agent of a member cluster which is itself a fleet hub to the cluster
+	// resource placements it creates to relay the placements of the upper hub to its own member clusters; the value is
+	// the namespace of the member cluster on the upper hub.
+	RelayedFromLabel = fleetPrefix + "relayed-from"
+
+	// ObservationTrackingLabel is the label that points to the cluster resource observation that creates a work.
+	ObservationTrackingLabel = fleetPrefix + "parent-observation"
+
 	// IsLatestSnapshotLabel tells if the snapshot is the latest one.
 	IsLatestSnapshotLabel = fleetPrefix + "is-latest-snapshot"
 
@@ -42,12 +84,16 @@ const (
 	// The name of the first work is {crpName}-work.
 	FirstWorkNameFmt = "%s-work"
 
+	// ObservationWorkNameFmt is the format of the name of the work generated for a cluster resource observation.
+	// The name of the work is {observationName}-observation.
+	ObservationWorkNameFmt = "%s-observation"
+
 	// WorkNameWithSubindexFmt is the format of the name of a work generated with resource snapshot with subindex.
 	// The name of the first work is {crpName}-{subindex}.
 	WorkNameWithSubindexFmt = "%s-%d"
 
 	// WorkNameWithConfigEnvelopeFmt is the format of the name of a work generated with config envelop.
-	// The format is {workPrefix}-configMap-uuid
+	// The format is {workPrefix}-configmap-{envelopeHash}, where the envelope hash is the one in EnvelopeHashLabel.
 	WorkNameWithConfigEnvelopeFmt = "%s-configmap-%s"
 
 	// ParentResourceSnapshotIndexLabel is the label applied to work that contains the index of the resource snapshot that generates the work.
@@ -64,6 +110,11 @@ const (
 	// we need to apply to the member cluster instead of the configMap itself.
 	EnvelopeConfigMapAnnotation = fleetPrefix + "envelope-configmap"
 
+	// EnvelopeFluxSourceAnnotation is the annotation that indicates the configMap is an envelope configMap that contains
+	// Flux Kustomizations and HelmReleases which reconcile the Flux source referenced by the annotation value in the
+	// format of {kind}/{name}, e.g. GitRepository/podinfo, on the member cluster.
+	EnvelopeFluxSourceAnnotation = fleetPrefix + "envelope-flux-source"
+
 	// EnvelopeTypeLabel is the label that marks the work object as generated from an envelope object.
 	// The value of the annotation is the type of the envelope object.
 	EnvelopeTypeLabel = fleetPrefix + "envelope-work"
@@ -72,11 +123,43 @@ const (
 	EnvelopeNamespaceLabel = fleetPrefix + "envelope-namespace"
 
 	// EnvelopeNameLabel is the label that contains the name of the envelope object that the work is generated from.
+	// It is only set when the name is a valid label value; EnvelopeNameAnnotation always contains the name.
 	EnvelopeNameLabel = fleetPrefix + "envelope-name"
 
+	// EnvelopeNameAnnotation is the annotation that contains the name of the envelope object that the work is generated
+	// from, which may be too long for a label value.
+	EnvelopeNameAnnotation = fleetPrefix + "envelope-name"
+
+	// EnvelopeHashLabel is the label that contains the hash of the type, namespace and name of the envelope object that
+	// the work is generated from, with which the work of the envelope object is looked up.
+	EnvelopeHashLabel = fleetPrefix + "envelope-hash"
+
+	// ServiceExportAnnotation is the annotation on a Service which exports the Service with the multi-cluster services
+	// of fleet networking from every member cluster it is placed on, when its value is "true".
+	ServiceExportAnnotation = fleetPrefix + "service-export"
+
+	// DeletionStrategyAnnotation is the annotation which the hub agent sets on a work before deleting it to tell the
+	// member agent how to delete the resources applied by the work; the value is the type of the deletion strategy of
+	// the placement, e.g. ReverseOrder.
+	DeletionStrategyAnnotation = fleetPrefix + "deletion-strategy"
+
+	// OrphanResourcesAnnotation is the annotation which the hub agent sets on a work before deleting it to tell the
+	// member agent to leave the resources applied by the work on the member cluster, when its value is "true".
+	OrphanResourcesAnnotation = fleetPrefix + "orphan-resources"
+
 	// PreviousBindingStateAnnotation is the annotation that records the previous state of a binding.
 	// This is used to remember if an "unscheduled" binding was moved from a "bound" state or a "scheduled" state.
 	PreviousBindingStateAnnotation = fleetPrefix + "previous-binding-state"
+
+	// UnscheduledGenerationAnnotation is the annotation which the scheduler sets on a binding as it marks the binding
+	// unscheduled; it records the generation of the binding which the change of the state advances it to. The rollout
+	// controller only deletes an unscheduled binding whose generation matches, so that a binding is only removed from
+	// its cluster on an explicit decision of the scheduler.
+	UnscheduledGenerationAnnotation = fleetPrefix + "unscheduled-generation"
+
+	// StagedUpdateApprovalAnnotation is the annotation on a placement which approves a stage of its staged update run;
+	// the value is the resource snapshot index of the placement and the name of the stage joined by a slash.
+	StagedUpdateApprovalAnnotation = fleetPrefix + "approved-stage"
 )
 
 // NamespacedName comprises a resource name, with a mandatory namespace.
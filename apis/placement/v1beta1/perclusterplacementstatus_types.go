@@ -0,0 +1,57 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PerClusterPlacementStatusKind is the kind of the PerClusterPlacementStatus.
+	PerClusterPlacementStatusKind = "PerClusterPlacementStatus"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Namespaced",shortName=pcps,categories={fleet,fleet-placement}
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.placementStatus.clusterName`,name="Cluster",type=string
+// +kubebuilder:printcolumn:JSONPath=`.placementStatus.conditions[?(@.type=="Applied")].status`,name="Applied",type=string
+// +kubebuilder:printcolumn:JSONPath=`.placementStatus.conditions[?(@.type=="Available")].status`,name="Available",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PerClusterPlacementStatus is the placement status of a ClusterResourcePlacement on one selected cluster, which the
+// hub agent writes when it compacts the status of a placement selecting many clusters, so that the placement object
+// stays small and fast to read and write.
+//
+// It is named after the placement in the reserved namespace of the cluster, and must have the following label:
+//   - `CRPTrackingLabel` which points to the placement.
+//
+// It is owned by the placement, and is deleted when the placement is deleted, when the cluster is no longer selected,
+// or when the status of the placement is no longer compacted.
+type PerClusterPlacementStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// PlacementStatus is the placement status on the cluster, the same as the one the placement would have in its
+	// placementStatuses if its status was not compacted.
+	// +required
+	PlacementStatus ResourcePlacementStatus `json:"placementStatus"`
+}
+
+// PerClusterPlacementStatusList contains a list of PerClusterPlacementStatus.
+// +kubebuilder:resource:scope="Namespaced"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PerClusterPlacementStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PerClusterPlacementStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PerClusterPlacementStatus{}, &PerClusterPlacementStatusList{})
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterResourcePlacementPromotionKind is the kind of the ClusterResourcePlacementPromotion.
+	ClusterResourcePlacementPromotionKind = "ClusterResourcePlacementPromotion"
+
+	// PromotedFromAnnotation is the annotation on a placement created by a promotion; the value is the name of the
+	// placement it is promoted from.
+	PromotedFromAnnotation = fleetPrefix + "promoted-from"
+
+	// PromotedFromResourceSnapshotAnnotation is the annotation on a placement created by a promotion; the value is the
+	// name of the latest resource snapshot of the placement it is promoted from, at the time of the promotion.
+	PromotedFromResourceSnapshotAnnotation = fleetPrefix + "promoted-from-resource-snapshot"
+
+	// PromotionAnnotation is the annotation on a placement created by a promotion; the value is the name of the
+	// ClusterResourcePlacementPromotion.
+	PromotionAnnotation = fleetPrefix + "promotion"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=crpp,categories={fleet,fleet-placement}
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.spec.sourcePlacementName`,name="Source",type=string
+// +kubebuilder:printcolumn:JSONPath=`.spec.targetPlacementName`,name="Target",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.sourceResourceSnapshotName`,name="Resource-Snapshot",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=="Promoted")].status`,name="Promoted",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterResourcePlacementPromotion clones a ClusterResourcePlacement into a new one with another placement policy,
+// e.g. to promote a placement which is verified on the staging clusters to the production clusters.
+//
+// The hub agent creates the target placement once, with the spec of the source placement and the policy of the
+// promotion, and annotates it with the source placement and its latest resource snapshot, so that where the target
+// placement comes from can be traced. The overrides select the resources and the clusters rather than the placements,
+// so the overrides of the source placement apply to the target placement as well on the clusters they select.
+type ClusterResourcePlacementPromotion struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of ClusterResourcePlacementPromotion.
+	// +required
+	Spec ClusterResourcePlacementPromotionSpec `json:"spec"`
+
+	// The observed status of ClusterResourcePlacementPromotion.
+	// +optional
+	Status ClusterResourcePlacementPromotionStatus `json:"status,omitempty"`
+}
+
+// ClusterResourcePlacementPromotionSpec defines the placement to promote and the placement to create.
+type ClusterResourcePlacementPromotionSpec struct {
+	// SourcePlacementName is the name of the ClusterResourcePlacement to promote.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	SourcePlacementName string `json:"sourcePlacementName"`
+
+	// TargetPlacementName is the name of the ClusterResourcePlacement to create. It must not exist unless it was
+	// created by this promotion.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	TargetPlacementName string `json:"targetPlacementName"`
+
+	// Policy is the placement policy of the target placement, e.g. the cluster affinity which selects the production
+	// clusters. The policy of the source placement is kept if it is not set.
+	// +optional
+	Policy *PlacementPolicy `json:"policy,omitempty"`
+}
+
+// ClusterResourcePlacementPromotionStatus defines the observed state of the ClusterResourcePlacementPromotion.
+type ClusterResourcePlacementPromotionStatus struct {
+	// SourceResourceSnapshotName is the name of the latest resource snapshot of the source placement when the target
+	// placement is created.
+	// +optional
+	SourceResourceSnapshotName string `json:"sourceResourceSnapshotName,omitempty"`
+
+	// SourceResourceHash is the hash of the resources in the source resource snapshot.
+	// +optional
+	SourceResourceHash string `json:"sourceResourceHash,omitempty"`
+
+	// TargetResourceSnapshotName is the name of the latest resource snapshot of the target placement.
+	// +optional
+	TargetResourceSnapshotName string `json:"targetResourceSnapshotName,omitempty"`
+
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+
+	// Conditions is an array of current observed conditions for ClusterResourcePlacementPromotion.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ClusterResourcePlacementPromotionConditionType identifies a specific condition of the
+// ClusterResourcePlacementPromotion.
+type ClusterResourcePlacementPromotionConditionType string
+
+const (
+	// ClusterResourcePlacementPromotionConditionTypePromoted indicates whether the source placement is promoted.
+	// Its condition status can be one of the following:
+	// - "True" means the target placement is created and its latest resource snapshot has the same resources as the
+	// source resource snapshot.
+	// - "False" means the target placement cannot be created, e.g. the source placement is not found or another
+	// placement has the name of the target, or the target placement has not snapshot the same resources yet.
+	ClusterResourcePlacementPromotionConditionTypePromoted ClusterResourcePlacementPromotionConditionType = "Promoted"
+)
+
+// ClusterResourcePlacementPromotionList contains a list of ClusterResourcePlacementPromotion.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterResourcePlacementPromotionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterResourcePlacementPromotion `json:"items"`
+}
+
+// SetConditions sets the conditions for a ClusterResourcePlacementPromotion.
+func (m *ClusterResourcePlacementPromotion) SetConditions(conditions ...metav1.Condition) {
+	for _, c := range conditions {
+		meta.SetStatusCondition(&m.Status.Conditions, c)
+	}
+}
+
+// GetCondition gets the condition for a ClusterResourcePlacementPromotion.
+func (m *ClusterResourcePlacementPromotion) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(m.Status.Conditions, conditionType)
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterResourcePlacementPromotion{}, &ClusterResourcePlacementPromotionList{})
+}
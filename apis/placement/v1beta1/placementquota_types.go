@@ -0,0 +1,79 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PlacementQuotaKind is the kind of the PlacementQuota.
+	PlacementQuotaKind = "PlacementQuota"
+)
+
+// +genclient
+// +genclient:Namespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Namespaced",shortName=pq,categories={fleet,fleet-placement}
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.spec.maxClusters`,name="Max-Clusters",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.spec.maxResources`,name="Max-Resources",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlacementQuota limits the breadth of the placements of the tenant which owns its namespace, so that one team cannot
+// consume the whole fleet. The placements of the tenant are the ClusterResourcePlacements which select the namespace.
+//
+// The scheduler does not pick a new cluster for a placement of the tenant once the placements of the tenant target as
+// many clusters as the quota allows, and the hub agent does not generate the works of a placement on a cluster once
+// the placements of the tenant place as many resources as the quota allows. When a namespace has more than one quota,
+// all of them are enforced.
+type PlacementQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of PlacementQuota.
+	// +required
+	Spec PlacementQuotaSpec `json:"spec"`
+}
+
+// PlacementQuotaSpec defines the limits of the quota.
+type PlacementQuotaSpec struct {
+	// MaxClusters is the maximum number of member clusters that the placements of the tenant may target altogether;
+	// a cluster targeted by more than one placement is counted once. It is enforced when the placements of the PickAll
+	// and PickN placement types are scheduled. No limit is enforced if it is not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxClusters *int32 `json:"maxClusters,omitempty"`
+
+	// MaxResources is the maximum number of resources that the placements of the tenant may place altogether; a
+	// resource is counted once for each cluster it is placed on, and an envelope object is counted as one resource. It
+	// is enforced when the works of the placements are generated. No limit is enforced if it is not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxResources *int32 `json:"maxResources,omitempty"`
+
+	// MaxResourceRequests is the maximum amount of each compute resource, e.g. cpu and memory, that the placements of
+	// the tenant may declare in their resource requirements altogether; the requirements of a placement are counted
+	// once for each cluster it targets. It is enforced when the placements of the PickAll and PickN placement types
+	// are scheduled. The resources not listed are not limited.
+	// +optional
+	MaxResourceRequests corev1.ResourceList `json:"maxResourceRequests,omitempty"`
+}
+
+// PlacementQuotaList contains a list of PlacementQuota.
+// +kubebuilder:resource:scope="Namespaced"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PlacementQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlacementQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PlacementQuota{}, &PlacementQuotaList{})
+}
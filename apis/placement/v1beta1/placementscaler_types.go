@@ -0,0 +1,184 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PlacementScalerKind is the kind of the PlacementScaler.
+	PlacementScalerKind = "PlacementScaler"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=psc,categories={fleet,fleet-placement}
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.spec.placementName`,name="Placement",type=string
+// +kubebuilder:printcolumn:JSONPath=`.spec.minClusters`,name="Min",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.spec.maxClusters`,name="Max",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.status.desiredClusters`,name="Desired",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.status.metricValue`,name="Metric",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=="Active")].status`,name="Active",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlacementScaler scales the number of clusters that a ClusterResourcePlacement of the PickN placement type picks
+// with an external metric, e.g. the requests per second of a workload reported by Prometheus, so that the number of
+// clusters serving the workload follows the demand.
+//
+// The hub agent reads the metric periodically and sets the numberOfClusters of the placement policy to the metric
+// value divided by the target value per cluster, rounded up and bounded by the minimum and maximum number of
+// clusters. The number of clusters is not reduced until the scale down stabilization window has passed since the
+// last time the placement was scaled.
+type PlacementScaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of PlacementScaler.
+	// +required
+	Spec PlacementScalerSpec `json:"spec"`
+
+	// The observed status of PlacementScaler.
+	// +optional
+	Status PlacementScalerStatus `json:"status,omitempty"`
+}
+
+// PlacementScalerSpec defines the placement to scale, its bounds and the metric it is scaled with.
+type PlacementScalerSpec struct {
+	// PlacementName is the name of the ClusterResourcePlacement to scale. Its placement type must be PickN.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	PlacementName string `json:"placementName"`
+
+	// MinClusters is the minimum number of clusters that the placement picks. Default: 1.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinClusters *int32 `json:"minClusters,omitempty"`
+
+	// MaxClusters is the maximum number of clusters that the placement picks. It must not be less than MinClusters.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	MaxClusters int32 `json:"maxClusters"`
+
+	// Metric is the external metric that the placement is scaled with.
+	// +required
+	Metric ExternalMetric `json:"metric"`
+
+	// Interval is how often the metric is read. Default: 30 seconds.
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// ScaleDownStabilizationWindow is how long the number of clusters is kept after the placement is scaled before it
+	// can be reduced, which prevents the placement from flapping when the metric fluctuates. Default: 5 minutes.
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+// ExternalMetric is a metric outside of the fleet, and the value of the metric that one cluster can serve.
+type ExternalMetric struct {
+	// Prometheus is the Prometheus query whose result is the value of the metric.
+	// +required
+	Prometheus *PrometheusMetric `json:"prometheus"`
+
+	// TargetValuePerCluster is the value of the metric that one cluster serves, e.g. 100 when a cluster serves 100
+	// requests per second. It must be positive.
+	// +required
+	TargetValuePerCluster resource.Quantity `json:"targetValuePerCluster"`
+}
+
+// PrometheusMetric is a PromQL query against a Prometheus server. The query must return a scalar or a vector with at
+// most one sample; an empty vector is regarded as zero.
+type PrometheusMetric struct {
+	// ServerAddress is the HTTP(S) address of the Prometheus server, e.g. http://prometheus.monitoring:9090.
+	// +kubebuilder:validation:Pattern="^https?://"
+	// +required
+	ServerAddress string `json:"serverAddress"`
+
+	// Query is the PromQL query, e.g. sum(rate(http_requests_total{app="web"}[2m])).
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Query string `json:"query"`
+
+	// SecretRef is the secret which is used to authenticate to the Prometheus server, with either the token key for a
+	// bearer token or the username and password keys for the basic authentication.
+	// +optional
+	SecretRef *NamespacedName `json:"secretRef,omitempty"`
+}
+
+// PlacementScalerStatus defines the observed state of the PlacementScaler.
+type PlacementScalerStatus struct {
+	// MetricValue is the value of the metric that was read last time.
+	// +optional
+	MetricValue *resource.Quantity `json:"metricValue,omitempty"`
+
+	// DesiredClusters is the number of clusters that the placement is scaled to.
+	// +optional
+	DesiredClusters *int32 `json:"desiredClusters,omitempty"`
+
+	// LastScaleTime is the last time the number of clusters of the placement was changed.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+
+	// Conditions is an array of current observed conditions for PlacementScaler.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// PlacementScalerConditionType identifies a specific condition of the PlacementScaler.
+type PlacementScalerConditionType string
+
+const (
+	// PlacementScalerConditionTypeActive indicates whether the placement is scaled with the metric.
+	// Its condition status can be one of the following:
+	// - "True" means the metric is read and the placement picks the desired number of clusters, or the scale down is
+	// being stabilized.
+	// - "False" means the metric cannot be read or the placement cannot be scaled, e.g. it is not found or its
+	// placement type is not PickN. The number of clusters of the placement is left as it is.
+	PlacementScalerConditionTypeActive PlacementScalerConditionType = "Active"
+)
+
+// PlacementScalerList contains a list of PlacementScaler.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PlacementScalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlacementScaler `json:"items"`
+}
+
+// SetConditions sets the conditions for a PlacementScaler.
+func (m *PlacementScaler) SetConditions(conditions ...metav1.Condition) {
+	for _, c := range conditions {
+		meta.SetStatusCondition(&m.Status.Conditions, c)
+	}
+}
+
+// GetCondition gets the condition for a PlacementScaler.
+func (m *PlacementScaler) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(m.Status.Conditions, conditionType)
+}
+
+func init() {
+	SchemeBuilder.Register(&PlacementScaler{}, &PlacementScalerList{})
+}
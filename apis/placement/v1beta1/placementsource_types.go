@@ -0,0 +1,213 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PlacementSourceKind is the kind of the PlacementSource.
+	PlacementSourceKind = "PlacementSource"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=ps,categories={fleet,fleet-placement}
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.metadata.generation`,name="Gen",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.observedRevision`,name="Revision",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=="Rendered")].status`,name="Rendered",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlacementSource is a source of the resources to place, e.g. a Git repository or an OCI artifact, whose manifests the hub agent renders
+// so that they do not have to be applied to the hub cluster first.
+//
+// A ClusterResourcePlacement places the rendered resources of a source by selecting the source by name with a
+// resource selector whose group is placement.kubernetes-fleet.io and kind is PlacementSource. The rendered resources
+// are placed as they are, together with the other selected resources, instead of the PlacementSource itself.
+type PlacementSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of PlacementSource.
+	// +required
+	Spec PlacementSourceSpec `json:"spec"`
+
+	// The observed status of PlacementSource.
+	// +optional
+	Status PlacementSourceStatus `json:"status,omitempty"`
+}
+
+// PlacementSourceSpec defines where the manifests of the resources come from.
+type PlacementSourceSpec struct {
+	// Git is the Git repository whose manifests are rendered. Git and OCI are mutually exclusive.
+	// +optional
+	Git *GitSource `json:"git,omitempty"`
+
+	// OCI is the OCI artifact, i.e. an ORAS bundle or a Helm chart, whose manifests are rendered. Git and OCI are
+	// mutually exclusive.
+	// +optional
+	OCI *OCISource `json:"oci,omitempty"`
+
+	// Interval is how often the source is checked for a new revision. Default: 5 minutes.
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// GitSource is a path in a revision of a Git repository. All the YAML and JSON files under the path, including the
+// ones in its subdirectories, are rendered as the resources.
+type GitSource struct {
+	// URL is the HTTP(S) URL of the Git repository.
+	// +kubebuilder:validation:Pattern="^https?://"
+	// +required
+	URL string `json:"url"`
+
+	// Revision is the branch, the tag or the commit to render. Default: the default branch of the repository.
+	// +kubebuilder:default="HEAD"
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Path is the directory in the repository whose manifests are rendered. Default: the root of the repository.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// SecretRef is the secret with the username and password keys which are used to authenticate to the repository.
+	// The secret must be in the fleet-system namespace.
+	// +optional
+	SecretRef *NamespacedName `json:"secretRef,omitempty"`
+}
+
+// OCISource is an OCI artifact in a registry, which is either an ORAS bundle or a Helm chart.
+//
+// The files of an ORAS bundle are the layers titled with the org.opencontainers.image.title annotation; the
+// directories pushed by oras, i.e. the layers annotated with io.deis.oras.content.unpack, are unpacked. All the YAML
+// and JSON files under the path are rendered as the resources, the same as the ones of a Git source.
+//
+// A Helm chart is the artifact with the Helm chart config media type, which is templated with the helm command line.
+type OCISource struct {
+	// URL is the repository of the artifact in the format of oci://<registry>/<repository>.
+	// +kubebuilder:validation:Pattern="^oci://[^/]+/.+"
+	// +required
+	URL string `json:"url"`
+
+	// Tag is the tag of the artifact to render. It is ignored when the digest is specified. Default: latest.
+	// +kubebuilder:default="latest"
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// Digest pins the artifact to render, e.g. sha256:<hex>. The artifact is rendered only when its manifest matches
+	// the digest.
+	// +kubebuilder:validation:Pattern="^sha256:[a-f0-9]{64}$"
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Path is the directory in the ORAS bundle whose manifests are rendered. Default: the root of the bundle.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// SecretRef is the secret with the username and password keys which are used to authenticate to the registry.
+	// The secret must be in the fleet-system namespace.
+	// +optional
+	SecretRef *NamespacedName `json:"secretRef,omitempty"`
+
+	// Verify verifies the signature of the artifact before it is rendered.
+	// +optional
+	Verify *OCISignatureVerification `json:"verify,omitempty"`
+
+	// Helm is how the artifact is templated when it is a Helm chart.
+	// +optional
+	Helm *HelmTemplateOptions `json:"helm,omitempty"`
+}
+
+// OCISignatureVerification verifies the cosign signature of an OCI artifact, which is stored with the
+// sha256-<hex>.sig tag in the repository of the artifact.
+type OCISignatureVerification struct {
+	// PublicKey is the PEM encoded ECDSA, RSA or Ed25519 public key, one of whose signatures of the artifact digest
+	// must be valid.
+	// +required
+	PublicKey string `json:"publicKey"`
+}
+
+// HelmTemplateOptions are the options to template a Helm chart.
+type HelmTemplateOptions struct {
+	// ReleaseName is the name of the release. Default: the name of the chart.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// ReleaseNamespace is the namespace of the release. Default: default.
+	// +optional
+	ReleaseNamespace string `json:"releaseNamespace,omitempty"`
+
+	// Values are the values set as strings when the chart is templated, keyed by their paths, e.g. image.tag.
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// PlacementSourceStatus defines the observed state of the PlacementSource.
+type PlacementSourceStatus struct {
+	// ObservedRevision is the resolved revision, e.g. the commit of a Git repository or the digest of an OCI
+	// artifact, that the resources are rendered from.
+	// +optional
+	ObservedRevision string `json:"observedRevision,omitempty"`
+
+	// Resources are the resources rendered from the observed revision.
+	// +optional
+	Resources []ResourceContent `json:"resources,omitempty"`
+
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+
+	// Conditions is an array of current observed conditions for PlacementSource.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// PlacementSourceConditionType identifies a specific condition of the PlacementSource.
+type PlacementSourceConditionType string
+
+const (
+	// PlacementSourceConditionTypeRendered indicates whether the resources of the latest revision are rendered.
+	// Its condition status can be one of the following:
+	// - "True" means the resources of the observed revision are rendered.
+	// - "False" means the source cannot be fetched or its manifests are invalid. The resources rendered from the
+	// previous revision, if any, are kept.
+	PlacementSourceConditionTypeRendered PlacementSourceConditionType = "Rendered"
+)
+
+// PlacementSourceList contains a list of PlacementSource.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PlacementSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlacementSource `json:"items"`
+}
+
+// SetConditions sets the conditions for a PlacementSource.
+func (m *PlacementSource) SetConditions(conditions ...metav1.Condition) {
+	for _, c := range conditions {
+		meta.SetStatusCondition(&m.Status.Conditions, c)
+	}
+}
+
+// GetCondition gets the condition for a PlacementSource.
+func (m *PlacementSource) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(m.Status.Conditions, conditionType)
+}
+
+func init() {
+	SchemeBuilder.Register(&PlacementSource{}, &PlacementSourceList{})
+}
@@ -6,6 +6,7 @@ Licensed under the MIT license.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -88,6 +89,16 @@ type SchedulingPolicySnapshotStatus struct {
 	// add the clusters that can provide the most insight to the list first.
 	// +optional
 	ClusterDecisions []ClusterDecision `json:"targetClusters,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=1000
+	// ClusterExplanations explains why the member clusters are not picked in the last scheduling cycle
+	// which runs the filter plugins, i.e., which plugin filters a cluster out and why, or the score of a
+	// cluster which passes the filters but does not score high enough. Unlike the decisions on the
+	// unselected clusters, the explanations are kept until the plugins run again, so that they can be
+	// inspected long after the scheduling cycle. Not all the member clusters are guaranteed to be listed
+	// due to the size limit; the clusters filtered out are listed first.
+	// +optional
+	ClusterExplanations []ClusterExplanation `json:"clusterExplanations,omitempty"`
 }
 
 // SchedulingPolicySnapshotConditionType identifies a specific condition of the SchedulingPolicySnapshot.
@@ -101,6 +112,13 @@ const (
 	// - "False" means we did not fully satisfy the placement requirement of the corresponding SchedulingPolicySnapshot.
 	// - "Unknown" means the status of the scheduling is unknown.
 	PolicySnapshotScheduled SchedulingPolicySnapshotConditionType = "Scheduled"
+
+	// PolicySnapshotPreempted indicates whether the placement is preempted from some of its clusters by the placements
+	// of higher priorities.
+	// Its condition status can be one of the following:
+	// - "True" means the scheduler has removed the placement from the clusters in the message of the condition, so that
+	// a placement of a higher priority can be placed on them.
+	PolicySnapshotPreempted SchedulingPolicySnapshotConditionType = "Preempted"
 )
 
 // ClusterDecision represents a decision from a placement
@@ -125,6 +143,30 @@ type ClusterDecision struct {
 	Reason string `json:"reason"`
 }
 
+// ClusterExplanation explains why a cluster is not picked by the scheduler.
+type ClusterExplanation struct {
+	// ClusterName is the name of the member cluster.
+	// +required
+	ClusterName string `json:"clusterName"`
+
+	// Plugin is the name of the filter plugin which filters the cluster out; it is empty if the cluster
+	// passes the filters but does not score high enough.
+	// +optional
+	Plugin string `json:"plugin,omitempty"`
+
+	// ClusterScore is the score of the cluster if it passes the filters.
+	// +optional
+	ClusterScore *ClusterScore `json:"clusterScore,omitempty"`
+
+	// Reason is the reason why the cluster is not picked.
+	// +required
+	Reason string `json:"reason"`
+
+	// LastTransitionTime is the last time the cluster was explained with another plugin, score or reason.
+	// +required
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
 // ClusterScore represents the score of the cluster calculated by the scheduler.
 type ClusterScore struct {
 	// AffinityScore represents the affinity score of the cluster calculated by the last
@@ -157,6 +199,22 @@ func (m *ClusterSchedulingPolicySnapshot) Tolerations() []Toleration {
 	return nil
 }
 
+// ResourceRequirements returns the resource requirements of the placement on each cluster in the policy snapshot.
+func (m *ClusterSchedulingPolicySnapshot) ResourceRequirements() corev1.ResourceList {
+	if m.Spec.Policy != nil {
+		return m.Spec.Policy.ResourceRequirements
+	}
+	return nil
+}
+
+// Priority returns the scheduling priority of the placement in the policy snapshot.
+func (m *ClusterSchedulingPolicySnapshot) Priority() int32 {
+	if m.Spec.Policy != nil {
+		return m.Spec.Policy.Priority
+	}
+	return 0
+}
+
 // SetConditions sets the given conditions on the ClusterSchedulingPolicySnapshot.
 func (m *ClusterSchedulingPolicySnapshot) SetConditions(conditions ...metav1.Condition) {
 	for _, c := range conditions {
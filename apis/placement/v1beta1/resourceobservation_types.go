@@ -0,0 +1,189 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterResourceObservationKind is the kind of the ClusterResourceObservation.
+	ClusterResourceObservationKind = "ClusterResourceObservation"
+
+	// MaxObservedResourcesPerCluster is the maximum number of the observed resources which are listed for each member
+	// cluster; the rest of them are only counted.
+	MaxObservedResourcesPerCluster = 1000
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=cobs,categories={fleet,fleet-placement}
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.metadata.generation`,name="Gen",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.observedResourceCount`,name="Resources",type=integer
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterResourceObservation is a read-only placement: instead of placing resources onto the member clusters, it
+// selects the resources which already exist on the member clusters, whether they are placed by Fleet or not, and the
+// member agents report the presence and the health of the selected resources back to the hub cluster, so that the
+// workloads of the whole fleet can be inventoried in one place.
+//
+// Nothing is applied to, changed on or deleted from the member clusters for an observation.
+type ClusterResourceObservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of ClusterResourceObservation.
+	// +required
+	Spec ClusterResourceObservationSpec `json:"spec"`
+
+	// The observed status of ClusterResourceObservation.
+	// +optional
+	Status ClusterResourceObservationStatus `json:"status,omitempty"`
+}
+
+// ClusterResourceObservationSpec defines the resources to observe and the member clusters to observe them on.
+type ClusterResourceObservationSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=100
+
+	// ResourceSelectors is an array of selectors used to select the resources on the member clusters. The selectors
+	// are `ORed`.
+	// You can have 1-100 selectors.
+	// +required
+	ResourceSelectors []ObservedResourceSelector `json:"resourceSelectors"`
+
+	// ClusterSelector selects the member clusters to observe by their labels. All the member clusters are observed if
+	// it is not set.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// ObservedResourceSelector selects the resources of a kind on the member clusters. All the fields are `ANDed`.
+type ObservedResourceSelector struct {
+	// Group name of the resource.
+	// Use an empty string to select resources under the core API group (e.g., services).
+	// +required
+	Group string `json:"group"`
+
+	// Version of the resource.
+	// +required
+	Version string `json:"version"`
+
+	// Kind of the resource.
+	// +required
+	Kind string `json:"kind"`
+
+	// Namespace of the resources to select. The resources in all the namespaces are selected if it is empty. It is
+	// ignored for the cluster scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the resource to select. The resources of all the names are selected if it is empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// A label query over the resources to select. The resources of all the labels are selected if it is not set.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// ObservedResourceHealth is the health of an observed resource.
+// +enum
+type ObservedResourceHealth string
+
+const (
+	// ObservedResourceHealthy means that the resource is available, e.g. all the replicas of a deployment are
+	// available, or that the resource is a data resource, e.g. a configMap, which is always available.
+	ObservedResourceHealthy ObservedResourceHealth = "Healthy"
+
+	// ObservedResourceUnhealthy means that the resource is not available yet, e.g. some replicas of a deployment are
+	// not available.
+	ObservedResourceUnhealthy ObservedResourceHealth = "Unhealthy"
+
+	// ObservedResourceHealthUnknown means that the member agent does not know how to track the health of the resource.
+	ObservedResourceHealthUnknown ObservedResourceHealth = "Unknown"
+)
+
+// ObservedResource is a resource found on a member cluster.
+type ObservedResource struct {
+	// Group is the group of the resource.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version is the version of the resource.
+	// +required
+	Version string `json:"version"`
+
+	// Kind is the kind of the resource.
+	// +required
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the resource; the resource is cluster scoped if it is empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the resource.
+	// +required
+	Name string `json:"name"`
+
+	// Health is the health of the resource.
+	// +kubebuilder:validation:Enum=Healthy;Unhealthy;Unknown
+	// +required
+	Health ObservedResourceHealth `json:"health"`
+}
+
+// ClusterResourceObservationStatus defines the observed state of the ClusterResourceObservation.
+type ClusterResourceObservationStatus struct {
+	// ObservedGeneration is the generation of the observation which the member clusters are observed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ObservedResourceCount is the number of the selected resources found on all the observed member clusters.
+	// +optional
+	ObservedResourceCount int `json:"observedResourceCount,omitempty"`
+
+	// Clusters are the statuses of the observed member clusters, sorted by the cluster names.
+	// +optional
+	Clusters []ClusterObservationStatus `json:"clusters,omitempty"`
+}
+
+// ClusterObservationStatus is the status of an observed member cluster.
+type ClusterObservationStatus struct {
+	// ClusterName is the name of the member cluster.
+	// +required
+	ClusterName string `json:"clusterName"`
+
+	// ObservedResourceCount is the number of the selected resources found on the member cluster.
+	// +optional
+	ObservedResourceCount int `json:"observedResourceCount,omitempty"`
+
+	// ObservedResources are the selected resources found on the member cluster. The unhealthy resources are listed
+	// first; at most 1000 resources are listed.
+	// +optional
+	ObservedResources []ObservedResource `json:"observedResources,omitempty"`
+
+	// Conditions is an array of current observed conditions of the member cluster.
+	// The only condition type is Observed, which is True when all the selectors are resolved on the member cluster.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ClusterResourceObservationList contains a list of ClusterResourceObservation.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterResourceObservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterResourceObservation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterResourceObservation{}, &ClusterResourceObservationList{})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterSchedulingExplainKind is the kind of the ClusterSchedulingExplain.
+	ClusterSchedulingExplainKind = "ClusterSchedulingExplain"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=cse,categories={fleet,fleet-placement}
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.spec.placementName`,name="Placement",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.policySnapshotName`,name="Policy-Snapshot",type=string
+// +kubebuilder:printcolumn:JSONPath=`.status.conditions[?(@.type=="Explained")].status`,name="Explained",type=string
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterSchedulingExplain explains how the scheduler schedules a ClusterResourcePlacement, i.e., why each member
+// cluster is picked or not.
+//
+// The scheduler runs a scheduling cycle for the placement without creating or updating any binding, and records the
+// result of every cluster, including the clusters filtered out and their scores, in the status. The scheduling cycle
+// runs once for each generation of the ClusterSchedulingExplain; to explain the placement again, e.g. after the
+// clusters change, update the spec or recreate the object.
+type ClusterSchedulingExplain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of ClusterSchedulingExplain.
+	// +required
+	Spec ClusterSchedulingExplainSpec `json:"spec"`
+
+	// The observed status of ClusterSchedulingExplain.
+	// +optional
+	Status ClusterSchedulingExplainStatus `json:"status,omitempty"`
+}
+
+// ClusterSchedulingExplainSpec defines the placement to explain.
+type ClusterSchedulingExplainSpec struct {
+	// PlacementName is the name of the ClusterResourcePlacement to explain.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	PlacementName string `json:"placementName"`
+
+	// Policy is a placement policy to explain the placement with instead of its current policy, i.e., a dry run of a
+	// policy change; the bindings of the placement are treated as scheduled by an older policy, as they would be
+	// after the change. The latest scheduling policy snapshot of the placement is explained if it is not set.
+	// +optional
+	Policy *PlacementPolicy `json:"policy,omitempty"`
+}
+
+// ClusterSchedulingExplainStatus defines the observed state of the ClusterSchedulingExplain.
+type ClusterSchedulingExplainStatus struct {
+	// PolicySnapshotName is the name of the scheduling policy snapshot explained; it is empty if the placement is
+	// explained with the policy in the spec.
+	// +optional
+	PolicySnapshotName string `json:"policySnapshotName,omitempty"`
+
+	// Clusters are the scheduling decisions on all the member clusters, as the scheduler would make them in the
+	// scheduling cycle: the clusters already picked by the placement, the clusters which would be picked, the
+	// clusters which pass the filters but do not score high enough, with their scores, and the clusters which are
+	// filtered out, with the reasons.
+	// +kubebuilder:validation:MaxItems=1000
+	// +optional
+	Clusters []ClusterDecision `json:"clusters,omitempty"`
+
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+
+	// Conditions is an array of current observed conditions for ClusterSchedulingExplain.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// ClusterSchedulingExplainConditionType identifies a specific condition of the ClusterSchedulingExplain.
+type ClusterSchedulingExplainConditionType string
+
+const (
+	// ClusterSchedulingExplainConditionTypeExplained indicates whether the placement is explained.
+	// Its condition status can be one of the following:
+	// - "True" means the scheduling cycle has run and the decisions are recorded in the status.
+	// - "False" means the placement cannot be explained, e.g. the placement is not found or has not been scheduled
+	// yet, or it selects a scheduler profile which is not configured.
+	ClusterSchedulingExplainConditionTypeExplained ClusterSchedulingExplainConditionType = "Explained"
+)
+
+// ClusterSchedulingExplainList contains a list of ClusterSchedulingExplain.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterSchedulingExplainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSchedulingExplain `json:"items"`
+}
+
+// SetConditions sets the conditions for a ClusterSchedulingExplain.
+func (m *ClusterSchedulingExplain) SetConditions(conditions ...metav1.Condition) {
+	for _, c := range conditions {
+		meta.SetStatusCondition(&m.Status.Conditions, c)
+	}
+}
+
+// GetCondition gets the condition for a ClusterSchedulingExplain.
+func (m *ClusterSchedulingExplain) GetCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(m.Status.Conditions, conditionType)
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSchedulingExplain{}, &ClusterSchedulingExplainList{})
+}
@@ -0,0 +1,188 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ClusterStagedUpdateRunKind is the kind of the ClusterStagedUpdateRun.
+	ClusterStagedUpdateRunKind = "ClusterStagedUpdateRun"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster",shortName=csur,categories={fleet,fleet-placement}
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=`.metadata.creationTimestamp`,name="Age",type=date
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterStagedUpdateRun defines a sequence of named stages in which the new versions of the selected resources are
+// rolled out to the clusters, e.g. the canary clusters first, then the rest of the clusters in one region after
+// another. Any number of ClusterResourcePlacements can reference the same run by name in their rollout strategies, so
+// that the rollout sequence is defined once and standardized across the fleet.
+//
+// Each time a referencing placement has a new resource snapshot, the hub agent starts a new run of the stages for the
+// placement: the clusters of a stage are rolled out following the rolling update config of the placement, and the
+// next stage starts once all of them are available and the stage has soaked. A stage can also wait for an approval
+// before it starts. The progress of each placement is recorded in the status.
+type ClusterStagedUpdateRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// The desired state of ClusterStagedUpdateRun.
+	// +required
+	Spec StagedUpdateRunSpec `json:"spec"`
+
+	// The observed status of ClusterStagedUpdateRun.
+	// +optional
+	Status StagedUpdateRunStatus `json:"status,omitempty"`
+}
+
+// StagedUpdateRunSpec defines the stages of the run.
+type StagedUpdateRunSpec struct {
+	// Stages are the stages in which the clusters are rolled out, in order. The clusters which no stage selects are
+	// rolled out after all the stages complete.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=31
+	// +listType=map
+	// +listMapKey=name
+	// +required
+	Stages []StageConfig `json:"stages"`
+}
+
+// StageConfig describes a stage of the run.
+type StageConfig struct {
+	// Name is the name of the stage, which is unique in the run.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +required
+	Name string `json:"name"`
+
+	// LabelSelector selects the member clusters of the stage by their labels among the clusters that the placement
+	// picks. A cluster selected by more than one stage belongs to the first of them. An empty selector selects all the
+	// clusters that the earlier stages do not select.
+	// +required
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+
+	// ClusterGroup is the name of a ClusterGroup. If set, the stage only selects the member clusters in the group which
+	// its label selector selects, e.g. all of them with an empty label selector.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ClusterGroup string `json:"clusterGroup,omitempty"`
+
+	// RequireApproval tells the stage to wait for an approval before it starts. A stage is approved for a placement
+	// by annotating the placement with the StagedUpdateApprovalAnnotation, whose value is the resource snapshot index
+	// of the placement and the name of the stage joined by a slash, e.g. "3/production".
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// SoakTime is how long the stage waits after all its clusters are available before the next stage starts.
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +optional
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+}
+
+// StagedUpdateRunStatus defines the observed state of the ClusterStagedUpdateRun.
+type StagedUpdateRunStatus struct {
+	// PlacementStatuses are the progress of the run for each placement that references it.
+	// +listType=map
+	// +listMapKey=placementName
+	// +optional
+	PlacementStatuses []PlacementUpdateRunStatus `json:"placementStatuses,omitempty"`
+}
+
+// PlacementUpdateRunStatus is the progress of the run for a placement.
+type PlacementUpdateRunStatus struct {
+	// PlacementName is the name of the ClusterResourcePlacement.
+	// +required
+	PlacementName string `json:"placementName"`
+
+	// ResourceSnapshotIndex is the index of the resource snapshot of the placement which is being rolled out.
+	// +required
+	ResourceSnapshotIndex string `json:"resourceSnapshotIndex"`
+
+	// Stages are the progress of each stage of the run.
+	// +optional
+	Stages []StageUpdatingStatus `json:"stages,omitempty"`
+}
+
+// StageUpdatingStatus is the progress of a stage of the run for a placement.
+type StageUpdatingStatus struct {
+	// StageName is the name of the stage.
+	// +required
+	StageName string `json:"stageName"`
+
+	// Clusters are the names of the clusters of the stage.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// State is the state of the stage.
+	// +required
+	State StageUpdatingState `json:"state"`
+
+	// StartTime is the time when the stage started rolling out its clusters.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// AvailableTime is the time when all the clusters of the stage became available, when the stage started soaking.
+	// +optional
+	AvailableTime *metav1.Time `json:"availableTime,omitempty"`
+
+	// EndTime is the time when the stage succeeded.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// StageUpdatingState is the state of a stage of the run.
+// +enum
+type StageUpdatingState string
+
+const (
+	// StageUpdatingStatePending means the stage waits for the earlier stages to succeed.
+	StageUpdatingStatePending StageUpdatingState = "Pending"
+
+	// StageUpdatingStateWaitingForApproval means the earlier stages have succeeded and the stage waits for an approval
+	// before it starts.
+	StageUpdatingStateWaitingForApproval StageUpdatingState = "WaitingForApproval"
+
+	// StageUpdatingStateUpdating means the clusters of the stage are being rolled out.
+	StageUpdatingStateUpdating StageUpdatingState = "Updating"
+
+	// StageUpdatingStateSoaking means all the clusters of the stage are available and the stage waits for its soak
+	// time before the next stage starts.
+	StageUpdatingStateSoaking StageUpdatingState = "Soaking"
+
+	// StageUpdatingStateSucceeded means the stage has succeeded.
+	StageUpdatingStateSucceeded StageUpdatingState = "Succeeded"
+)
+
+// ClusterStagedUpdateRunList contains a list of ClusterStagedUpdateRun.
+// +kubebuilder:resource:scope="Cluster"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterStagedUpdateRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterStagedUpdateRun `json:"items"`
+}
+
+// GetPlacementStatus gets the progress of the run for the placement.
+func (m *ClusterStagedUpdateRun) GetPlacementStatus(placementName string) *PlacementUpdateRunStatus {
+	for i := range m.Status.PlacementStatuses {
+		if m.Status.PlacementStatuses[i].PlacementName == placementName {
+			return &m.Status.PlacementStatuses[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterStagedUpdateRun{}, &ClusterStagedUpdateRunList{})
+}
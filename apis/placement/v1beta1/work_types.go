@@ -34,11 +34,44 @@ const (
 	// LastAppliedConfigAnnotation is to record the last applied configuration on the object.
 	LastAppliedConfigAnnotation = fleetPrefix + "last-applied-configuration"
 
+	// WorkSignatureAnnotation is the annotation that contains the base64 encoded signature of the content of the work
+	// signed by the hub agent.
+	WorkSignatureAnnotation = fleetPrefix + "signature"
+
+	// WorkSignatureKeyIDAnnotation is the annotation that contains the ID of the key which signs the work.
+	WorkSignatureKeyIDAnnotation = fleetPrefix + "signature-key-id"
+
+	// ApplyStrategyAnnotation is the annotation that preserves the apply strategy of a work, which has no counterpart in
+	// the sig-multicluster Work API, when the work is converted to the upstream API.
+	ApplyStrategyAnnotation = fleetPrefix + "apply-strategy"
+
+	// SealManifestAnnotation is the annotation that users set to "true" on a secret to have its data encrypted in the
+	// works with the key of the target member cluster.
+	SealManifestAnnotation = fleetPrefix + "seal"
+
+	// SealedManifestAnnotation is the annotation that marks a manifest in a work as sealed. Its value is the ID of the
+	// member cluster key with which the manifest is sealed.
+	SealedManifestAnnotation = fleetPrefix + "sealed-key-id"
+
+	// OwnerPlacementAnnotation is the annotation that the member agent sets on the resources it applies to record the
+	// placement which owns them on the member cluster, so that the resources are not applied by another placement,
+	// possibly from another hub, at the same time.
+	OwnerPlacementAnnotation = fleetPrefix + "owner-placement"
+
+	// ProbeLoadBalancerAnnotation is the annotation that users set to "true" on a service of the LoadBalancer type to
+	// have the member agent regard the service as available only when its load balancer accepts TCP connections on
+	// all the TCP ports of the service.
+	ProbeLoadBalancerAnnotation = fleetPrefix + "probe-load-balancer"
+
 	// WorkConditionTypeApplied represents workload in Work is applied successfully on the spoke cluster.
 	WorkConditionTypeApplied = "Applied"
 
 	// WorkConditionTypeAvailable represents workload in Work is available on the spoke cluster.
 	WorkConditionTypeAvailable = "Available"
+
+	// WorkConditionTypeObserved represents the resources selected by the observations of the Work are observed on the
+	// spoke cluster.
+	WorkConditionTypeObserved = "Observed"
 )
 
 // This api is copied from https://github.com/kubernetes-sigs/work-api/blob/master/pkg/apis/v1alpha1/work_types.go.
@@ -53,6 +86,19 @@ type WorkSpec struct {
 	// and is owned by other appliers.
 	// +optional
 	ApplyStrategy *ApplyStrategy `json:"applyStrategy,omitempty"`
+
+	// Observations select the existing resources on the spoke cluster whose presence and health are reported in the
+	// status of the Work; nothing is applied for them.
+	// +kubebuilder:validation:MaxItems=100
+	// +optional
+	Observations []ObservedResourceSelector `json:"observations,omitempty"`
+
+	// Priority is the priority of the placement of the work; the member agent applies the works of higher priorities
+	// first when many works are waiting to be applied.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
 }
 
 // WorkloadTemplate represents the manifest workload to be deployed on spoke cluster
@@ -84,6 +130,15 @@ type WorkStatus struct {
 	// spoke cluster.
 	// +optional
 	ManifestConditions []ManifestCondition `json:"manifestConditions,omitempty"`
+
+	// ObservedResourceCount is the number of the resources selected by the observations on the spoke cluster.
+	// +optional
+	ObservedResourceCount int `json:"observedResourceCount,omitempty"`
+
+	// ObservedResources are the resources selected by the observations on the spoke cluster. The unhealthy resources
+	// are listed first; at most 1000 resources are listed.
+	// +optional
+	ObservedResources []ObservedResource `json:"observedResources,omitempty"`
 }
 
 // WorkResourceIdentifier provides the identifiers needed to interact with any arbitrary object.
@@ -123,6 +178,53 @@ type ManifestCondition struct {
 	// Conditions represents the conditions of this resource on spoke cluster
 	// +required
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// LoadBalancer is the status of the load balancer of the resource if it is a service of the LoadBalancer type
+	// which is assigned an IP address or a DNS name.
+	// +optional
+	LoadBalancer *ServiceLoadBalancerStatus `json:"loadBalancer,omitempty"`
+
+	// Drift is the last change made to the resource on the spoke cluster out of band which the member agent reverted.
+	// It is only reported if the status reporting scope of the work is DriftDetails.
+	// +optional
+	Drift *ManifestDrift `json:"drift,omitempty"`
+}
+
+// ManifestDrift is a change made to a resource on the spoke cluster out of band, i.e. not through its work, which the
+// member agent reverted.
+type ManifestDrift struct {
+	// ObservedTime is when the member agent found and reverted the change.
+	// +required
+	ObservedTime metav1.Time `json:"observedTime"`
+
+	// +kubebuilder:validation:MaxItems=20
+
+	// Fields are the paths of the changed fields, e.g. "spec.replicas" or "metadata.labels"; at most 20 fields are
+	// reported.
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ServiceLoadBalancerStatus is the status of the load balancer of a service of the LoadBalancer type.
+type ServiceLoadBalancerStatus struct {
+	// Ingress are the IP addresses or the DNS names at which the load balancer is reachable.
+	// +optional
+	Ingress []LoadBalancerIngress `json:"ingress,omitempty"`
+
+	// Ports are the ports of the service which the load balancer exposes.
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+// LoadBalancerIngress is an IP address or a DNS name at which a load balancer is reachable.
+type LoadBalancerIngress struct {
+	// IP is set for the load balancers which are reachable at IP addresses.
+	// +optional
+	IP string `json:"ip,omitempty"`
+
+	// Hostname is set for the load balancers which are reachable at DNS names, e.g. the AWS load balancers.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
 }
 
 // +genclient
@@ -10,11 +10,40 @@ Licensed under the MIT license.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivationWindow) DeepCopyInto(out *ActivationWindow) {
+	*out = *in
+	if in.ActivateAt != nil {
+		in, out := &in.ActivateAt, &out.ActivateAt
+		*out = (*in).DeepCopy()
+	}
+	if in.DeactivateAt != nil {
+		in, out := &in.DeactivateAt, &out.DeactivateAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Daily != nil {
+		in, out := &in.Daily, &out.Daily
+		*out = new(DailyWindow)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivationWindow.
+func (in *ActivationWindow) DeepCopy() *ActivationWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivationWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Affinity) DeepCopyInto(out *Affinity) {
 	*out = *in
@@ -35,6 +64,38 @@ func (in *Affinity) DeepCopy() *Affinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedResourceKind) DeepCopyInto(out *AllowedResourceKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedResourceKind.
+func (in *AllowedResourceKind) DeepCopy() *AllowedResourceKind {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedResourceKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedResourceAuditRecord) DeepCopyInto(out *AppliedResourceAuditRecord) {
+	*out = *in
+	out.WorkResourceIdentifier = in.WorkResourceIdentifier
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedResourceAuditRecord.
+func (in *AppliedResourceAuditRecord) DeepCopy() *AppliedResourceAuditRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedResourceAuditRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppliedResourceMeta) DeepCopyInto(out *AppliedResourceMeta) {
 	*out = *in
@@ -133,6 +194,13 @@ func (in *AppliedWorkStatus) DeepCopyInto(out *AppliedWorkStatus) {
 		*out = make([]AppliedResourceMeta, len(*in))
 		copy(*out, *in)
 	}
+	if in.AuditRecords != nil {
+		in, out := &in.AuditRecords, &out.AuditRecords
+		*out = make([]AppliedResourceAuditRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedWorkStatus.
@@ -145,6 +213,31 @@ func (in *AppliedWorkStatus) DeepCopy() *AppliedWorkStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyAllowList) DeepCopyInto(out *ApplyAllowList) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]AllowedResourceKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyAllowList.
+func (in *ApplyAllowList) DeepCopy() *ApplyAllowList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyAllowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplyStrategy) DeepCopyInto(out *ApplyStrategy) {
 	*out = *in
@@ -153,6 +246,16 @@ func (in *ApplyStrategy) DeepCopyInto(out *ApplyStrategy) {
 		*out = new(ServerSideApplyConfig)
 		**out = **in
 	}
+	if in.AllowedResources != nil {
+		in, out := &in.AllowedResources, &out.AllowedResources
+		*out = new(ApplyAllowList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AvailabilityRules != nil {
+		in, out := &in.AvailabilityRules, &out.AvailabilityRules
+		*out = make([]AvailabilityRule, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyStrategy.
@@ -165,6 +268,46 @@ func (in *ApplyStrategy) DeepCopy() *ApplyStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvailabilityRule) DeepCopyInto(out *AvailabilityRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvailabilityRule.
+func (in *AvailabilityRule) DeepCopy() *AvailabilityRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AvailabilityRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryConfig) DeepCopyInto(out *CanaryConfig) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.SoakSeconds != nil {
+		in, out := &in.SoakSeconds, &out.SoakSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryConfig.
+func (in *CanaryConfig) DeepCopy() *CanaryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterAffinity) DeepCopyInto(out *ClusterAffinity) {
 	*out = *in
@@ -212,6 +355,69 @@ func (in *ClusterDecision) DeepCopy() *ClusterDecision {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterExplanation) DeepCopyInto(out *ClusterExplanation) {
+	*out = *in
+	if in.ClusterScore != nil {
+		in, out := &in.ClusterScore, &out.ClusterScore
+		*out = new(ClusterScore)
+		(*in).DeepCopyInto(*out)
+	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterExplanation.
+func (in *ClusterExplanation) DeepCopy() *ClusterExplanation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterExplanation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterObservationStatus) DeepCopyInto(out *ClusterObservationStatus) {
+	*out = *in
+	if in.ObservedResources != nil {
+		in, out := &in.ObservedResources, &out.ObservedResources
+		*out = make([]ObservedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterObservationStatus.
+func (in *ClusterObservationStatus) DeepCopy() *ClusterObservationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterObservationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRemovalStatus) DeepCopyInto(out *ClusterRemovalStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRemovalStatus.
+func (in *ClusterRemovalStatus) DeepCopy() *ClusterRemovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRemovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterResourceBinding) DeepCopyInto(out *ClusterResourceBinding) {
 	*out = *in
@@ -272,7 +478,7 @@ func (in *ClusterResourceBindingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourcePlacement) DeepCopyInto(out *ClusterResourcePlacement) {
+func (in *ClusterResourceObservation) DeepCopyInto(out *ClusterResourceObservation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -280,18 +486,18 @@ func (in *ClusterResourcePlacement) DeepCopyInto(out *ClusterResourcePlacement)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacement.
-func (in *ClusterResourcePlacement) DeepCopy() *ClusterResourcePlacement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceObservation.
+func (in *ClusterResourceObservation) DeepCopy() *ClusterResourceObservation {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterResourcePlacement)
+	out := new(ClusterResourceObservation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterResourcePlacement) DeepCopyObject() runtime.Object {
+func (in *ClusterResourceObservation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -299,31 +505,31 @@ func (in *ClusterResourcePlacement) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourcePlacementList) DeepCopyInto(out *ClusterResourcePlacementList) {
+func (in *ClusterResourceObservationList) DeepCopyInto(out *ClusterResourceObservationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterResourcePlacement, len(*in))
+		*out = make([]ClusterResourceObservation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementList.
-func (in *ClusterResourcePlacementList) DeepCopy() *ClusterResourcePlacementList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceObservationList.
+func (in *ClusterResourceObservationList) DeepCopy() *ClusterResourceObservationList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterResourcePlacementList)
+	out := new(ClusterResourceObservationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterResourcePlacementList) DeepCopyObject() runtime.Object {
+func (in *ClusterResourceObservationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -331,96 +537,56 @@ func (in *ClusterResourcePlacementList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourcePlacementSpec) DeepCopyInto(out *ClusterResourcePlacementSpec) {
+func (in *ClusterResourceObservationSpec) DeepCopyInto(out *ClusterResourceObservationSpec) {
 	*out = *in
 	if in.ResourceSelectors != nil {
 		in, out := &in.ResourceSelectors, &out.ResourceSelectors
-		*out = make([]ClusterResourceSelector, len(*in))
+		*out = make([]ObservedResourceSelector, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Policy != nil {
-		in, out := &in.Policy, &out.Policy
-		*out = new(PlacementPolicy)
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Strategy.DeepCopyInto(&out.Strategy)
-	if in.RevisionHistoryLimit != nil {
-		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
-		*out = new(int32)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementSpec.
-func (in *ClusterResourcePlacementSpec) DeepCopy() *ClusterResourcePlacementSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceObservationSpec.
+func (in *ClusterResourceObservationSpec) DeepCopy() *ClusterResourceObservationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterResourcePlacementSpec)
+	out := new(ClusterResourceObservationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourcePlacementStatus) DeepCopyInto(out *ClusterResourcePlacementStatus) {
+func (in *ClusterResourceObservationStatus) DeepCopyInto(out *ClusterResourceObservationStatus) {
 	*out = *in
-	if in.SelectedResources != nil {
-		in, out := &in.SelectedResources, &out.SelectedResources
-		*out = make([]ResourceIdentifier, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.PlacementStatuses != nil {
-		in, out := &in.PlacementStatuses, &out.PlacementStatuses
-		*out = make([]ResourcePlacementStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterObservationStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementStatus.
-func (in *ClusterResourcePlacementStatus) DeepCopy() *ClusterResourcePlacementStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterResourcePlacementStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourceSelector) DeepCopyInto(out *ClusterResourceSelector) {
-	*out = *in
-	if in.LabelSelector != nil {
-		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSelector.
-func (in *ClusterResourceSelector) DeepCopy() *ClusterResourceSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceObservationStatus.
+func (in *ClusterResourceObservationStatus) DeepCopy() *ClusterResourceObservationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterResourceSelector)
+	out := new(ClusterResourceObservationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourceSnapshot) DeepCopyInto(out *ClusterResourceSnapshot) {
+func (in *ClusterResourcePlacement) DeepCopyInto(out *ClusterResourcePlacement) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -428,18 +594,18 @@ func (in *ClusterResourceSnapshot) DeepCopyInto(out *ClusterResourceSnapshot) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSnapshot.
-func (in *ClusterResourceSnapshot) DeepCopy() *ClusterResourceSnapshot {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacement.
+func (in *ClusterResourcePlacement) DeepCopy() *ClusterResourcePlacement {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterResourceSnapshot)
+	out := new(ClusterResourcePlacement)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterResourceSnapshot) DeepCopyObject() runtime.Object {
+func (in *ClusterResourcePlacement) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -447,31 +613,31 @@ func (in *ClusterResourceSnapshot) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterResourceSnapshotList) DeepCopyInto(out *ClusterResourceSnapshotList) {
+func (in *ClusterResourcePlacementList) DeepCopyInto(out *ClusterResourcePlacementList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterResourceSnapshot, len(*in))
+		*out = make([]ClusterResourcePlacement, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSnapshotList.
-func (in *ClusterResourceSnapshotList) DeepCopy() *ClusterResourceSnapshotList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementList.
+func (in *ClusterResourcePlacementList) DeepCopy() *ClusterResourcePlacementList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterResourceSnapshotList)
+	out := new(ClusterResourcePlacementList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterResourceSnapshotList) DeepCopyObject() runtime.Object {
+func (in *ClusterResourcePlacementList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -479,7 +645,7 @@ func (in *ClusterResourceSnapshotList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSchedulingPolicySnapshot) DeepCopyInto(out *ClusterSchedulingPolicySnapshot) {
+func (in *ClusterResourcePlacementPromotion) DeepCopyInto(out *ClusterResourcePlacementPromotion) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -487,18 +653,18 @@ func (in *ClusterSchedulingPolicySnapshot) DeepCopyInto(out *ClusterSchedulingPo
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingPolicySnapshot.
-func (in *ClusterSchedulingPolicySnapshot) DeepCopy() *ClusterSchedulingPolicySnapshot {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementPromotion.
+func (in *ClusterResourcePlacementPromotion) DeepCopy() *ClusterResourcePlacementPromotion {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSchedulingPolicySnapshot)
+	out := new(ClusterResourcePlacementPromotion)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterSchedulingPolicySnapshot) DeepCopyObject() runtime.Object {
+func (in *ClusterResourcePlacementPromotion) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -506,31 +672,31 @@ func (in *ClusterSchedulingPolicySnapshot) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSchedulingPolicySnapshotList) DeepCopyInto(out *ClusterSchedulingPolicySnapshotList) {
+func (in *ClusterResourcePlacementPromotionList) DeepCopyInto(out *ClusterResourcePlacementPromotionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterSchedulingPolicySnapshot, len(*in))
+		*out = make([]ClusterResourcePlacementPromotion, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingPolicySnapshotList.
-func (in *ClusterSchedulingPolicySnapshotList) DeepCopy() *ClusterSchedulingPolicySnapshotList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementPromotionList.
+func (in *ClusterResourcePlacementPromotionList) DeepCopy() *ClusterResourcePlacementPromotionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSchedulingPolicySnapshotList)
+	out := new(ClusterResourcePlacementPromotionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterSchedulingPolicySnapshotList) DeepCopyObject() runtime.Object {
+func (in *ClusterResourcePlacementPromotionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -538,134 +704,1322 @@ func (in *ClusterSchedulingPolicySnapshotList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterScore) DeepCopyInto(out *ClusterScore) {
+func (in *ClusterResourcePlacementPromotionSpec) DeepCopyInto(out *ClusterResourcePlacementPromotionSpec) {
 	*out = *in
-	if in.AffinityScore != nil {
-		in, out := &in.AffinityScore, &out.AffinityScore
-		*out = new(int32)
-		**out = **in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PlacementPolicy)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.TopologySpreadScore != nil {
-		in, out := &in.TopologySpreadScore, &out.TopologySpreadScore
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementPromotionSpec.
+func (in *ClusterResourcePlacementPromotionSpec) DeepCopy() *ClusterResourcePlacementPromotionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourcePlacementPromotionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourcePlacementPromotionStatus) DeepCopyInto(out *ClusterResourcePlacementPromotionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementPromotionStatus.
+func (in *ClusterResourcePlacementPromotionStatus) DeepCopy() *ClusterResourcePlacementPromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourcePlacementPromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourcePlacementSpec) DeepCopyInto(out *ClusterResourcePlacementSpec) {
+	*out = *in
+	if in.ResourceSelectors != nil {
+		in, out := &in.ResourceSelectors, &out.ResourceSelectors
+		*out = make([]ClusterResourceSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PlacementPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Strategy.DeepCopyInto(&out.Strategy)
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SchedulingGates != nil {
+		in, out := &in.SchedulingGates, &out.SchedulingGates
+		*out = make([]PlacementSchedulingGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.BindingGates != nil {
+		in, out := &in.BindingGates, &out.BindingGates
+		*out = make([]PlacementBindingGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActivationWindow != nil {
+		in, out := &in.ActivationWindow, &out.ActivationWindow
+		*out = new(ActivationWindow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementSpec.
+func (in *ClusterResourcePlacementSpec) DeepCopy() *ClusterResourcePlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourcePlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourcePlacementStatus) DeepCopyInto(out *ClusterResourcePlacementStatus) {
+	*out = *in
+	if in.SelectedResources != nil {
+		in, out := &in.SelectedResources, &out.SelectedResources
+		*out = make([]ResourceIdentifier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementStatuses != nil {
+		in, out := &in.PlacementStatuses, &out.PlacementStatuses
+		*out = make([]ResourcePlacementStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementStatusSummary != nil {
+		in, out := &in.PlacementStatusSummary, &out.PlacementStatusSummary
+		*out = new(PlacementStatusSummary)
+		**out = **in
+	}
+	if in.RemovingClusters != nil {
+		in, out := &in.RemovingClusters, &out.RemovingClusters
+		*out = make([]ClusterRemovalStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourcePlacementStatus.
+func (in *ClusterResourcePlacementStatus) DeepCopy() *ClusterResourcePlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourcePlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceSelector) DeepCopyInto(out *ClusterResourceSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSelector.
+func (in *ClusterResourceSelector) DeepCopy() *ClusterResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceSnapshot) DeepCopyInto(out *ClusterResourceSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSnapshot.
+func (in *ClusterResourceSnapshot) DeepCopy() *ClusterResourceSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterResourceSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceSnapshotList) DeepCopyInto(out *ClusterResourceSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterResourceSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceSnapshotList.
+func (in *ClusterResourceSnapshotList) DeepCopy() *ClusterResourceSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterResourceSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingExplain) DeepCopyInto(out *ClusterSchedulingExplain) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingExplain.
+func (in *ClusterSchedulingExplain) DeepCopy() *ClusterSchedulingExplain {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingExplain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSchedulingExplain) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingExplainList) DeepCopyInto(out *ClusterSchedulingExplainList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSchedulingExplain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingExplainList.
+func (in *ClusterSchedulingExplainList) DeepCopy() *ClusterSchedulingExplainList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingExplainList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSchedulingExplainList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingExplainSpec) DeepCopyInto(out *ClusterSchedulingExplainSpec) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PlacementPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingExplainSpec.
+func (in *ClusterSchedulingExplainSpec) DeepCopy() *ClusterSchedulingExplainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingExplainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingExplainStatus) DeepCopyInto(out *ClusterSchedulingExplainStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingExplainStatus.
+func (in *ClusterSchedulingExplainStatus) DeepCopy() *ClusterSchedulingExplainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingExplainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingPolicySnapshot) DeepCopyInto(out *ClusterSchedulingPolicySnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingPolicySnapshot.
+func (in *ClusterSchedulingPolicySnapshot) DeepCopy() *ClusterSchedulingPolicySnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingPolicySnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSchedulingPolicySnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingPolicySnapshotList) DeepCopyInto(out *ClusterSchedulingPolicySnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSchedulingPolicySnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingPolicySnapshotList.
+func (in *ClusterSchedulingPolicySnapshotList) DeepCopy() *ClusterSchedulingPolicySnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingPolicySnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSchedulingPolicySnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScore) DeepCopyInto(out *ClusterScore) {
+	*out = *in
+	if in.AffinityScore != nil {
+		in, out := &in.AffinityScore, &out.AffinityScore
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologySpreadScore != nil {
+		in, out := &in.TopologySpreadScore, &out.TopologySpreadScore
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScore.
+func (in *ClusterScore) DeepCopy() *ClusterScore {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterScore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSelector) DeepCopyInto(out *ClusterSelector) {
+	*out = *in
+	if in.ClusterSelectorTerms != nil {
+		in, out := &in.ClusterSelectorTerms, &out.ClusterSelectorTerms
+		*out = make([]ClusterSelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSelector.
+func (in *ClusterSelector) DeepCopy() *ClusterSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSelectorTerm) DeepCopyInto(out *ClusterSelectorTerm) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PropertySelector != nil {
+		in, out := &in.PropertySelector, &out.PropertySelector
+		*out = new(PropertySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PropertySorter != nil {
+		in, out := &in.PropertySorter, &out.PropertySorter
+		*out = new(PropertySorter)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSelectorTerm.
+func (in *ClusterSelectorTerm) DeepCopy() *ClusterSelectorTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSelectorTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStagedUpdateRun) DeepCopyInto(out *ClusterStagedUpdateRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStagedUpdateRun.
+func (in *ClusterStagedUpdateRun) DeepCopy() *ClusterStagedUpdateRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStagedUpdateRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStagedUpdateRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStagedUpdateRunList) DeepCopyInto(out *ClusterStagedUpdateRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterStagedUpdateRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStagedUpdateRunList.
+func (in *ClusterStagedUpdateRunList) DeepCopy() *ClusterStagedUpdateRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStagedUpdateRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStagedUpdateRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DailyWindow) DeepCopyInto(out *DailyWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DailyWindow.
+func (in *DailyWindow) DeepCopy() *DailyWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DailyWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionStrategy) DeepCopyInto(out *DeletionStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionStrategy.
+func (in *DeletionStrategy) DeepCopy() *DeletionStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvelopeIdentifier) DeepCopyInto(out *EnvelopeIdentifier) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvelopeIdentifier.
+func (in *EnvelopeIdentifier) DeepCopy() *EnvelopeIdentifier {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvelopeIdentifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalMetric) DeepCopyInto(out *ExternalMetric) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	out.TargetValuePerCluster = in.TargetValuePerCluster.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalMetric.
+func (in *ExternalMetric) DeepCopy() *ExternalMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedResourcePlacement) DeepCopyInto(out *FailedResourcePlacement) {
+	*out = *in
+	in.ResourceIdentifier.DeepCopyInto(&out.ResourceIdentifier)
+	in.Condition.DeepCopyInto(&out.Condition)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedResourcePlacement.
+func (in *FailedResourcePlacement) DeepCopy() *FailedResourcePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedResourcePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitSource) DeepCopyInto(out *GitSource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(NamespacedName)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitSource.
+func (in *GitSource) DeepCopy() *GitSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmTemplateOptions) DeepCopyInto(out *HelmTemplateOptions) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmTemplateOptions.
+func (in *HelmTemplateOptions) DeepCopy() *HelmTemplateOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmTemplateOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerIngress) DeepCopyInto(out *LoadBalancerIngress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerIngress.
+func (in *LoadBalancerIngress) DeepCopy() *LoadBalancerIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Manifest) DeepCopyInto(out *Manifest) {
+	*out = *in
+	in.RawExtension.DeepCopyInto(&out.RawExtension)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Manifest.
+func (in *Manifest) DeepCopy() *Manifest {
+	if in == nil {
+		return nil
+	}
+	out := new(Manifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestCondition) DeepCopyInto(out *ManifestCondition) {
+	*out = *in
+	out.Identifier = in.Identifier
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(ServiceLoadBalancerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ManifestDrift)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestCondition.
+func (in *ManifestCondition) DeepCopy() *ManifestCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestDrift) DeepCopyInto(out *ManifestDrift) {
+	*out = *in
+	in.ObservedTime.DeepCopyInto(&out.ObservedTime)
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestDrift.
+func (in *ManifestDrift) DeepCopy() *ManifestDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedName.
+func (in *NamespacedName) DeepCopy() *NamespacedName {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedName)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkProximity) DeepCopyInto(out *NetworkProximity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkProximity.
+func (in *NetworkProximity) DeepCopy() *NetworkProximity {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkProximity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCISignatureVerification) DeepCopyInto(out *OCISignatureVerification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCISignatureVerification.
+func (in *OCISignatureVerification) DeepCopy() *OCISignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(OCISignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCISource) DeepCopyInto(out *OCISource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(NamespacedName)
+		**out = **in
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(OCISignatureVerification)
+		**out = **in
+	}
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(HelmTemplateOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCISource.
+func (in *OCISource) DeepCopy() *OCISource {
+	if in == nil {
+		return nil
+	}
+	out := new(OCISource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedResource) DeepCopyInto(out *ObservedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedResource.
+func (in *ObservedResource) DeepCopy() *ObservedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedResourceSelector) DeepCopyInto(out *ObservedResourceSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedResourceSelector.
+func (in *ObservedResourceSelector) DeepCopy() *ObservedResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerClusterPlacementStatus) DeepCopyInto(out *PerClusterPlacementStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.PlacementStatus.DeepCopyInto(&out.PlacementStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerClusterPlacementStatus.
+func (in *PerClusterPlacementStatus) DeepCopy() *PerClusterPlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PerClusterPlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PerClusterPlacementStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerClusterPlacementStatusList) DeepCopyInto(out *PerClusterPlacementStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PerClusterPlacementStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerClusterPlacementStatusList.
+func (in *PerClusterPlacementStatusList) DeepCopy() *PerClusterPlacementStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(PerClusterPlacementStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PerClusterPlacementStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacedLoadBalancer) DeepCopyInto(out *PlacedLoadBalancer) {
+	*out = *in
+	in.ResourceIdentifier.DeepCopyInto(&out.ResourceIdentifier)
+	in.ServiceLoadBalancerStatus.DeepCopyInto(&out.ServiceLoadBalancerStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacedLoadBalancer.
+func (in *PlacedLoadBalancer) DeepCopy() *PlacedLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacedLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementPolicy) DeepCopyInto(out *PlacementPolicy) {
+	*out = *in
+	if in.ClusterNames != nil {
+		in, out := &in.ClusterNames, &out.ClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NumberOfClusters != nil {
+		in, out := &in.NumberOfClusters, &out.NumberOfClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]Toleration, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceRequirements != nil {
+		in, out := &in.ResourceRequirements, &out.ResourceRequirements
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.ScoreWeights != nil {
+		in, out := &in.ScoreWeights, &out.ScoreWeights
+		*out = new(ScoreWeights)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkProximity != nil {
+		in, out := &in.NetworkProximity, &out.NetworkProximity
+		*out = new(NetworkProximity)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementPolicy.
+func (in *PlacementPolicy) DeepCopy() *PlacementPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementQuota) DeepCopyInto(out *PlacementQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementQuota.
+func (in *PlacementQuota) DeepCopy() *PlacementQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementQuotaList) DeepCopyInto(out *PlacementQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlacementQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementQuotaList.
+func (in *PlacementQuotaList) DeepCopy() *PlacementQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementQuotaSpec) DeepCopyInto(out *PlacementQuotaSpec) {
+	*out = *in
+	if in.MaxClusters != nil {
+		in, out := &in.MaxClusters, &out.MaxClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxResourceRequests != nil {
+		in, out := &in.MaxResourceRequests, &out.MaxResourceRequests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementQuotaSpec.
+func (in *PlacementQuotaSpec) DeepCopy() *PlacementQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScaler) DeepCopyInto(out *PlacementScaler) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScaler.
+func (in *PlacementScaler) DeepCopy() *PlacementScaler {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScaler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementScaler) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScalerList) DeepCopyInto(out *PlacementScalerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlacementScaler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScalerList.
+func (in *PlacementScalerList) DeepCopy() *PlacementScalerList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScalerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementScalerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScalerSpec) DeepCopyInto(out *PlacementScalerSpec) {
+	*out = *in
+	if in.MinClusters != nil {
+		in, out := &in.MinClusters, &out.MinClusters
+		*out = new(int32)
+		**out = **in
+	}
+	in.Metric.DeepCopyInto(&out.Metric)
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScalerSpec.
+func (in *PlacementScalerSpec) DeepCopy() *PlacementScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScalerStatus) DeepCopyInto(out *PlacementScalerStatus) {
+	*out = *in
+	if in.MetricValue != nil {
+		in, out := &in.MetricValue, &out.MetricValue
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DesiredClusters != nil {
+		in, out := &in.DesiredClusters, &out.DesiredClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScore.
-func (in *ClusterScore) DeepCopy() *ClusterScore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScalerStatus.
+func (in *PlacementScalerStatus) DeepCopy() *PlacementScalerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterScore)
+	out := new(PlacementScalerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSelector) DeepCopyInto(out *ClusterSelector) {
+func (in *PlacementBindingGate) DeepCopyInto(out *PlacementBindingGate) {
 	*out = *in
-	if in.ClusterSelectorTerms != nil {
-		in, out := &in.ClusterSelectorTerms, &out.ClusterSelectorTerms
-		*out = make([]ClusterSelectorTerm, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSelector.
-func (in *ClusterSelector) DeepCopy() *ClusterSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementBindingGate.
+func (in *PlacementBindingGate) DeepCopy() *PlacementBindingGate {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSelector)
+	out := new(PlacementBindingGate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSelectorTerm) DeepCopyInto(out *ClusterSelectorTerm) {
+func (in *PlacementSchedulingGate) DeepCopyInto(out *PlacementSchedulingGate) {
 	*out = *in
-	if in.LabelSelector != nil {
-		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PropertySelector != nil {
-		in, out := &in.PropertySelector, &out.PropertySelector
-		*out = new(PropertySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.PropertySorter != nil {
-		in, out := &in.PropertySorter, &out.PropertySorter
-		*out = new(PropertySorter)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSelectorTerm.
-func (in *ClusterSelectorTerm) DeepCopy() *ClusterSelectorTerm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSchedulingGate.
+func (in *PlacementSchedulingGate) DeepCopy() *PlacementSchedulingGate {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSelectorTerm)
+	out := new(PlacementSchedulingGate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvelopeIdentifier) DeepCopyInto(out *EnvelopeIdentifier) {
+func (in *PlacementSource) DeepCopyInto(out *PlacementSource) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvelopeIdentifier.
-func (in *EnvelopeIdentifier) DeepCopy() *EnvelopeIdentifier {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSource.
+func (in *PlacementSource) DeepCopy() *PlacementSource {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvelopeIdentifier)
+	out := new(PlacementSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FailedResourcePlacement) DeepCopyInto(out *FailedResourcePlacement) {
+func (in *PlacementSourceList) DeepCopyInto(out *PlacementSourceList) {
 	*out = *in
-	in.ResourceIdentifier.DeepCopyInto(&out.ResourceIdentifier)
-	in.Condition.DeepCopyInto(&out.Condition)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlacementSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedResourcePlacement.
-func (in *FailedResourcePlacement) DeepCopy() *FailedResourcePlacement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSourceList.
+func (in *PlacementSourceList) DeepCopy() *PlacementSourceList {
 	if in == nil {
 		return nil
 	}
-	out := new(FailedResourcePlacement)
+	out := new(PlacementSourceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Manifest) DeepCopyInto(out *Manifest) {
+func (in *PlacementSourceSpec) DeepCopyInto(out *PlacementSourceSpec) {
 	*out = *in
-	in.RawExtension.DeepCopyInto(&out.RawExtension)
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCISource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Manifest.
-func (in *Manifest) DeepCopy() *Manifest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSourceSpec.
+func (in *PlacementSourceSpec) DeepCopy() *PlacementSourceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Manifest)
+	out := new(PlacementSourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManifestCondition) DeepCopyInto(out *ManifestCondition) {
+func (in *PlacementSourceStatus) DeepCopyInto(out *PlacementSourceStatus) {
 	*out = *in
-	out.Identifier = in.Identifier
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceContent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -675,69 +2029,49 @@ func (in *ManifestCondition) DeepCopyInto(out *ManifestCondition) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestCondition.
-func (in *ManifestCondition) DeepCopy() *ManifestCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSourceStatus.
+func (in *PlacementSourceStatus) DeepCopy() *PlacementSourceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ManifestCondition)
+	out := new(PlacementSourceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
+func (in *PlacementStatusSummary) DeepCopyInto(out *PlacementStatusSummary) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedName.
-func (in *NamespacedName) DeepCopy() *NamespacedName {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementStatusSummary.
+func (in *PlacementStatusSummary) DeepCopy() *PlacementStatusSummary {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespacedName)
+	out := new(PlacementStatusSummary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PlacementPolicy) DeepCopyInto(out *PlacementPolicy) {
+func (in *PlacementUpdateRunStatus) DeepCopyInto(out *PlacementUpdateRunStatus) {
 	*out = *in
-	if in.ClusterNames != nil {
-		in, out := &in.ClusterNames, &out.ClusterNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.NumberOfClusters != nil {
-		in, out := &in.NumberOfClusters, &out.NumberOfClusters
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]TopologySpreadConstraint, len(*in))
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]StageUpdatingStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]Toleration, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementPolicy.
-func (in *PlacementPolicy) DeepCopy() *PlacementPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementUpdateRunStatus.
+func (in *PlacementUpdateRunStatus) DeepCopy() *PlacementUpdateRunStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PlacementPolicy)
+	out := new(PlacementUpdateRunStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -758,6 +2092,26 @@ func (in *PreferredClusterSelector) DeepCopy() *PreferredClusterSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusMetric) DeepCopyInto(out *PrometheusMetric) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(NamespacedName)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusMetric.
+func (in *PrometheusMetric) DeepCopy() *PrometheusMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PropertySelector) DeepCopyInto(out *PropertySelector) {
 	*out = *in
@@ -856,6 +2210,13 @@ func (in *ResourceBindingStatus) DeepCopyInto(out *ResourceBindingStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LoadBalancers != nil {
+		in, out := &in.LoadBalancers, &out.LoadBalancers
+		*out = make([]PlacedLoadBalancer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -931,6 +2292,13 @@ func (in *ResourcePlacementStatus) DeepCopyInto(out *ResourcePlacementStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LoadBalancers != nil {
+		in, out := &in.LoadBalancers, &out.LoadBalancers
+		*out = make([]PlacedLoadBalancer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -1032,11 +2400,21 @@ func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
 		*out = new(RollingUpdateConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ApplyStrategy != nil {
 		in, out := &in.ApplyStrategy, &out.ApplyStrategy
 		*out = new(ApplyStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeletionStrategy != nil {
+		in, out := &in.DeletionStrategy, &out.DeletionStrategy
+		*out = new(DeletionStrategy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
@@ -1091,6 +2469,13 @@ func (in *SchedulingPolicySnapshotStatus) DeepCopyInto(out *SchedulingPolicySnap
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ClusterExplanations != nil {
+		in, out := &in.ClusterExplanations, &out.ClusterExplanations
+		*out = make([]ClusterExplanation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingPolicySnapshotStatus.
@@ -1103,6 +2488,36 @@ func (in *SchedulingPolicySnapshotStatus) DeepCopy() *SchedulingPolicySnapshotSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScoreWeights) DeepCopyInto(out *ScoreWeights) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologySpread != nil {
+		in, out := &in.TopologySpread, &out.TopologySpread
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScoreWeights.
+func (in *ScoreWeights) DeepCopy() *ScoreWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(ScoreWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerSideApplyConfig) DeepCopyInto(out *ServerSideApplyConfig) {
 	*out = *in
@@ -1118,6 +2533,128 @@ func (in *ServerSideApplyConfig) DeepCopy() *ServerSideApplyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceLoadBalancerStatus) DeepCopyInto(out *ServiceLoadBalancerStatus) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]LoadBalancerIngress, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceLoadBalancerStatus.
+func (in *ServiceLoadBalancerStatus) DeepCopy() *ServiceLoadBalancerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceLoadBalancerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageConfig) DeepCopyInto(out *StageConfig) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageConfig.
+func (in *StageConfig) DeepCopy() *StageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageUpdatingStatus) DeepCopyInto(out *StageUpdatingStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AvailableTime != nil {
+		in, out := &in.AvailableTime, &out.AvailableTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageUpdatingStatus.
+func (in *StageUpdatingStatus) DeepCopy() *StageUpdatingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StageUpdatingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedUpdateRunSpec) DeepCopyInto(out *StagedUpdateRunSpec) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]StageConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedUpdateRunSpec.
+func (in *StagedUpdateRunSpec) DeepCopy() *StagedUpdateRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedUpdateRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StagedUpdateRunStatus) DeepCopyInto(out *StagedUpdateRunStatus) {
+	*out = *in
+	if in.PlacementStatuses != nil {
+		in, out := &in.PlacementStatuses, &out.PlacementStatuses
+		*out = make([]PlacementUpdateRunStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StagedUpdateRunStatus.
+func (in *StagedUpdateRunStatus) DeepCopy() *StagedUpdateRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StagedUpdateRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Toleration) DeepCopyInto(out *Toleration) {
 	*out = *in
@@ -1236,6 +2773,13 @@ func (in *WorkSpec) DeepCopyInto(out *WorkSpec) {
 		*out = new(ApplyStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Observations != nil {
+		in, out := &in.Observations, &out.Observations
+		*out = make([]ObservedResourceSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkSpec.
@@ -1265,6 +2809,11 @@ func (in *WorkStatus) DeepCopyInto(out *WorkStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ObservedResources != nil {
+		in, out := &in.ObservedResources, &out.ObservedResources
+		*out = make([]ObservedResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkStatus.
@@ -14,7 +14,9 @@ import (
 	"k8s.io/klog/v2"
 
 	"go.goms.io/fleet/pkg/authtoken"
+	"go.goms.io/fleet/pkg/authtoken/providers/aws"
 	"go.goms.io/fleet/pkg/authtoken/providers/azure"
+	"go.goms.io/fleet/pkg/authtoken/providers/oidc"
 	"go.goms.io/fleet/pkg/authtoken/providers/secret"
 	"go.goms.io/fleet/pkg/interfaces"
 )
@@ -51,10 +53,16 @@ func parseArgs() (interfaces.AuthTokenProvider, error) {
 
 	var clientID string
 	var scope string
+	var useWorkloadIdentity bool
+	var tenantID string
 	azureCmd := &cobra.Command{
 		Use:  "azure",
 		Args: cobra.NoArgs,
 		Run: func(_ *cobra.Command, args []string) {
+			if useWorkloadIdentity {
+				tokenProvider = azure.NewWorkloadIdentity(clientID, tenantID, "", scope)
+				return
+			}
 			tokenProvider = azure.New(clientID, scope)
 		},
 	}
@@ -63,9 +71,41 @@ func parseArgs() (interfaces.AuthTokenProvider, error) {
 	// TODO: this scope argument is specific for Azure provider. We should allow registering and parsing provider specific argument
 	// in provider level, instead of global level.
 	azureCmd.Flags().StringVar(&scope, "scope", "", "Azure AAD token scope (optional)")
+	azureCmd.Flags().BoolVar(&useWorkloadIdentity, "use-workload-identity", false, "Use the Azure workload identity instead of the managed identity (optional)")
+	azureCmd.Flags().StringVar(&tenantID, "tenantid", "", "Azure AAD tenant ID of the workload identity, read from AZURE_TENANT_ID if not set (optional)")
 	_ = azureCmd.MarkFlagRequired("clientid")
 
-	rootCmd.AddCommand(secretCmd, azureCmd)
+	var clusterName string
+	var roleARN string
+	var region string
+	awsCmd := &cobra.Command{
+		Use:  "aws",
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, args []string) {
+			tokenProvider = aws.New(clusterName, roleARN, region)
+		},
+	}
+
+	awsCmd.Flags().StringVar(&clusterName, "cluster-name", "", "EKS hub cluster name (required)")
+	awsCmd.Flags().StringVar(&roleARN, "role-arn", "", "IAM role ARN, read from AWS_ROLE_ARN if not set (optional)")
+	awsCmd.Flags().StringVar(&region, "region", "", "AWS region of the hub cluster, read from AWS_REGION if not set (optional)")
+	_ = awsCmd.MarkFlagRequired("cluster-name")
+
+	var tokenFile string
+	var audience string
+	oidcCmd := &cobra.Command{
+		Use:  "oidc",
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, args []string) {
+			tokenProvider = oidc.New(tokenFile, audience)
+		},
+	}
+
+	oidcCmd.Flags().StringVar(&tokenFile, "token-file", "", "Path of the OIDC token file, e.g. a projected service account token (required)")
+	oidcCmd.Flags().StringVar(&audience, "audience", "", "Audience the token must be issued for (optional)")
+	_ = oidcCmd.MarkFlagRequired("token-file")
+
+	rootCmd.AddCommand(secretCmd, azureCmd, awsCmd, oidcCmd)
 	err = rootCmd.Execute()
 	if err != nil {
 		return nil, err
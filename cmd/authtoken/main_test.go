@@ -6,7 +6,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"go.goms.io/fleet/pkg/authtoken/providers/aws"
 	"go.goms.io/fleet/pkg/authtoken/providers/azure"
+	"go.goms.io/fleet/pkg/authtoken/providers/oidc"
 )
 
 func TestParseArgs(t *testing.T) {
@@ -36,4 +38,48 @@ func TestParseArgs(t *testing.T) {
 		assert.Equal(t, true, ok)
 		assert.Equal(t, "6dae42f8-4368-4678-94ff-3960e28e3630", azTokenProvider.Scope)
 	})
+	t.Run("azure workload identity", func(t *testing.T) {
+		os.Args = []string{"refreshtoken", "azure", "--clientid=test-client-id", "--use-workload-identity", "--tenantid=test-tenant-id"}
+		t.Cleanup(func() {
+			os.Args = nil
+		})
+		tokenProvider, err := parseArgs()
+		assert.NotNil(t, tokenProvider)
+		assert.Nil(t, err)
+
+		wiTokenProvider, ok := tokenProvider.(*azure.WorkloadIdentityAuthTokenProvider)
+		assert.Equal(t, true, ok)
+		assert.Equal(t, "test-client-id", wiTokenProvider.ClientID)
+		assert.Equal(t, "test-tenant-id", wiTokenProvider.TenantID)
+		assert.Equal(t, "6dae42f8-4368-4678-94ff-3960e28e3630", wiTokenProvider.Scope)
+	})
+	t.Run("aws", func(t *testing.T) {
+		os.Args = []string{"refreshtoken", "aws", "--cluster-name=hub", "--role-arn=test-role", "--region=us-west-2"}
+		t.Cleanup(func() {
+			os.Args = nil
+		})
+		tokenProvider, err := parseArgs()
+		assert.NotNil(t, tokenProvider)
+		assert.Nil(t, err)
+
+		irsaTokenProvider, ok := tokenProvider.(*aws.IRSAAuthTokenProvider)
+		assert.Equal(t, true, ok)
+		assert.Equal(t, "hub", irsaTokenProvider.ClusterName)
+		assert.Equal(t, "test-role", irsaTokenProvider.RoleARN)
+		assert.Equal(t, "us-west-2", irsaTokenProvider.Region)
+	})
+	t.Run("oidc", func(t *testing.T) {
+		os.Args = []string{"refreshtoken", "oidc", "--token-file=/var/run/secrets/tokens/fleet", "--audience=fleet"}
+		t.Cleanup(func() {
+			os.Args = nil
+		})
+		tokenProvider, err := parseArgs()
+		assert.NotNil(t, tokenProvider)
+		assert.Nil(t, err)
+
+		oidcTokenProvider, ok := tokenProvider.(*oidc.AuthTokenProvider)
+		assert.Equal(t, true, ok)
+		assert.Equal(t, "/var/run/secrets/tokens/fleet", oidcTokenProvider.TokenFilePath)
+		assert.Equal(t, "fleet", oidcTokenProvider.Audience)
+	})
 }
@@ -0,0 +1,107 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"go.goms.io/fleet/pkg/benchmark"
+)
+
+func newCommand(stdout io.Writer, newClient func(kubeconfig string) (client.Client, error)) *cobra.Command {
+	var kubeconfig string
+	var output string
+	var simulateOnly bool
+	cfg := benchmark.Config{}
+	cmd := &cobra.Command{
+		Use:   "fleet-bench",
+		Short: "Measure the scheduling and work generation throughput of a fleet hub agent",
+		Long: "fleet-bench creates synthetic member clusters and cluster resource placements on a hub cluster, e.g. a kind " +
+			"cluster or an envtest API server which the hub agent runs against, and measures how long the hub agent takes " +
+			"to schedule the placements and to generate their works. The member agents of the synthetic clusters are faked, " +
+			"so no member cluster is needed. With --simulate-only, it only simulates the synthetic clusters until interrupted, " +
+			"so that the hub agent can be exercised at scale with other placements.",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if output != "text" && output != "json" {
+				return fmt.Errorf("unsupported output format %q, must be text or json", output)
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+			c, err := newClient(kubeconfig)
+			if err != nil {
+				return err
+			}
+			if simulateOnly {
+				return benchmark.NewRunner(c, cfg).Simulate(cmd.Context())
+			}
+			result, err := benchmark.NewRunner(c, cfg).Run(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if output == "json" {
+				return result.WriteJSON(stdout)
+			}
+			return result.WriteText(stdout)
+		},
+	}
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Kubeconfig of the hub cluster (optional, defaults to the in-cluster or the default kubeconfig)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Format of the result, text or json (optional)")
+	cmd.Flags().StringVar(&cfg.RunID, "run-id", utilrand.String(5), "ID of the run, which prefixes the names of the created objects (optional, defaults to a random one)")
+	cmd.Flags().IntVar(&cfg.Clusters, "clusters", 100, "Number of the synthetic member clusters")
+	cmd.Flags().IntVar(&cfg.Placements, "placements", 100, "Number of the cluster resource placements")
+	cmd.Flags().Int32Var(&cfg.ClustersPerPlacement, "clusters-per-placement", 0, "Number of the clusters each placement picks; 0 picks all the clusters")
+	cmd.Flags().IntVar(&cfg.ResourcesPerPlacement, "resources-per-placement", 1, "Number of the config maps each placement selects along with their namespace")
+	cmd.Flags().DurationVar(&cfg.Timeout, "timeout", 10*time.Minute, "How long to wait for the clusters to join and for the placements to be synchronized")
+	cmd.Flags().DurationVar(&cfg.PollInterval, "poll-interval", 500*time.Millisecond, "How often the placements are checked, which bounds the precision of the latencies")
+	cmd.Flags().BoolVar(&cfg.Cleanup, "cleanup", true, "Delete the created objects once the run is over")
+	cmd.Flags().BoolVar(&cfg.ConsumeWorks, "consume-works", false, "Report the works of the synthetic clusters as applied and available, and measure how long the placements take to be available")
+	cmd.Flags().BoolVar(&cfg.ReportProperties, "report-properties", false, "Report synthetic node counts and resource usages of the synthetic clusters with their heartbeats")
+	cmd.Flags().BoolVar(&simulateOnly, "simulate-only", false, "Only create the synthetic clusters and run their fake member agents until interrupted, without any placement")
+	return cmd
+}
+
+// newHubClient returns a client of the hub cluster in the kubeconfig, or the default one if it is empty.
+func newHubClient(kubeconfig string) (client.Client, error) {
+	var restConfig *rest.Config
+	var err error
+	if kubeconfig != "" {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		restConfig, err = config.GetConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	// the benchmark must not be throttled by the client
+	restConfig.QPS, restConfig.Burst = 1000, 2000
+	return client.New(restConfig, client.Options{Scheme: benchmark.Scheme})
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := newCommand(os.Stdout, newHubClient).ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		cancel()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCommand_InvalidArgs(t *testing.T) {
+	tests := map[string][]string{
+		"unsupported output":              {"--output", "yaml"},
+		"no clusters":                     {"--clusters", "0"},
+		"too many clusters per placement": {"--clusters", "2", "--clusters-per-placement", "3"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := newCommand(&stdout, func(string) (client.Client, error) {
+				t.Fatalf("the hub client is created for invalid arguments")
+				return nil, errors.New("unreachable")
+			})
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err == nil {
+				t.Errorf("Execute() = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"go.goms.io/fleet/pkg/migration"
+)
+
+// errUnsupported is returned in the strict mode when any construct is dropped in the conversion.
+var errUnsupported = errors.New("some constructs are not supported by fleet; see the report")
+
+func newCommand(stdout, stderr io.Writer) *cobra.Command {
+	var filenames []string
+	var outputPath string
+	var reportPath string
+	var strict bool
+	cmd := &cobra.Command{
+		Use:   "fleet-migrate",
+		Short: "Convert Karmada and KubeFed policies into fleet placements and overrides",
+		Long: "fleet-migrate converts the Karmada propagation and override policies and the KubeFed federated objects " +
+			"into cluster resource placements, overrides and, for KubeFed, the resources rendered from the templates. " +
+			"It reports the constructs which fleet does not support, so that they can be reviewed before switching to fleet.",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			objs, err := readObjects(filenames)
+			if err != nil {
+				return err
+			}
+			result := migration.Convert(objs)
+
+			out := stdout
+			if outputPath != "" {
+				file, err := os.Create(outputPath)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				out = file
+			}
+			if err := migration.Write(out, result.Objects); err != nil {
+				return err
+			}
+
+			report := stderr
+			if reportPath != "" {
+				file, err := os.Create(reportPath)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				report = file
+			}
+			if err := writeReport(report, len(objs), result); err != nil {
+				return err
+			}
+			if strict && result.HasUnsupported() {
+				return errUnsupported
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVarP(&filenames, "filename", "f", nil, "Files or directories of the Karmada and KubeFed objects to convert, or - for the standard input (required)")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "File to write the converted objects to (optional, defaults to the standard output)")
+	cmd.Flags().StringVar(&reportPath, "report", "", "File to write the conversion report to (optional, defaults to the standard error)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit with an error if any construct is not supported by fleet (optional)")
+	_ = cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+// readObjects reads the objects in the files, and the YAML and JSON files in the directories recursively.
+func readObjects(filenames []string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decode := func(name string, r io.Reader) error {
+		decoded, err := migration.Decode(r)
+		if err != nil {
+			return fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+		objs = append(objs, decoded...)
+		return nil
+	}
+	for _, filename := range filenames {
+		if filename == "-" {
+			if err := decode("the standard input", os.Stdin); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := filepath.WalkDir(filename, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			// the files given explicitly are read regardless of their extensions
+			if path != filename {
+				switch strings.ToLower(filepath.Ext(path)) {
+				case ".yaml", ".yml", ".json":
+				default:
+					return nil
+				}
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			return decode(path, file)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+func writeReport(w io.Writer, total int, result *migration.Result) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Converted %d objects into %d fleet objects with %d findings.\n", total, len(result.Objects), len(result.Findings))
+	for _, finding := range result.Findings {
+		fmt.Fprintln(&b, finding.String())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func main() {
+	if err := newCommand(os.Stdout, os.Stderr).Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.goms.io/fleet/pkg/migration"
+)
+
+func TestCommand(t *testing.T) {
+	tests := map[string]struct {
+		args        []string
+		wantErr     error
+		wantObjects int
+	}{
+		"karmada policies": {
+			args:        []string{"-f", "testdata/karmada.yaml"},
+			wantObjects: 3,
+		},
+		"directory": {
+			args:        []string{"-f", "testdata"},
+			wantObjects: 7,
+		},
+		"strict": {
+			args:        []string{"-f", "testdata/kubefed.yaml", "--strict"},
+			wantErr:     errUnsupported,
+			wantObjects: 4,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := newCommand(&stdout, &stderr)
+			cmd.SetArgs(tc.args)
+			if err := cmd.Execute(); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Execute() = %v, want %v", err, tc.wantErr)
+			}
+			objs, err := migration.Decode(&stdout)
+			if err != nil {
+				t.Fatalf("failed to decode the output: %v", err)
+			}
+			if len(objs) != tc.wantObjects {
+				t.Errorf("got %d objects, want %d", len(objs), tc.wantObjects)
+			}
+			if !strings.HasPrefix(stderr.String(), "Converted ") {
+				t.Errorf("report = %q, want a summary", stderr.String())
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +35,9 @@ import (
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/cmd/hubagent/options"
 	"go.goms.io/fleet/cmd/hubagent/workload"
+	"go.goms.io/fleet/pkg/controllers/agentupgrade"
+	"go.goms.io/fleet/pkg/controllers/clustergroup"
+	"go.goms.io/fleet/pkg/controllers/membercertificate"
 	mcv1alpha1 "go.goms.io/fleet/pkg/controllers/membercluster/v1alpha1"
 	mcv1beta1 "go.goms.io/fleet/pkg/controllers/membercluster/v1beta1"
 	fleetmetrics "go.goms.io/fleet/pkg/metrics"
@@ -72,7 +76,11 @@ func init() {
 
 	metrics.Registry.MustRegister(fleetmetrics.JoinResultMetrics, fleetmetrics.LeaveResultMetrics,
 		fleetmetrics.PlacementApplyFailedCount, fleetmetrics.PlacementApplySucceedCount,
-		fleetmetrics.SchedulingCycleDurationMilliseconds, fleetmetrics.SchedulerActiveWorkers)
+		fleetmetrics.SchedulingCycleDurationMilliseconds, fleetmetrics.SchedulerActiveWorkers,
+		fleetmetrics.SchedulerScoreCacheHits, fleetmetrics.SchedulerScoreCacheMisses,
+		fleetmetrics.WorkGeneratorOverrideCacheHits, fleetmetrics.WorkGeneratorOverrideCacheMisses,
+		fleetmetrics.PlacementAvailabilitySamples, fleetmetrics.ClusterPlacementAvailabilitySamples,
+		fleetmetrics.PlacementAvailabilitySLI, fleetmetrics.ClusterPlacementAvailabilitySLI)
 }
 
 func main() {
@@ -101,13 +109,14 @@ func main() {
 			SyncPeriod: &opts.ResyncPeriod.Duration,
 		},
 		LeaderElection:             opts.LeaderElection.LeaderElect,
-		LeaderElectionID:           opts.LeaderElection.ResourceName,
+		LeaderElectionID:           opts.LeaderElectionID(),
 		LeaderElectionNamespace:    opts.LeaderElection.ResourceNamespace,
 		LeaderElectionResourceLock: opts.LeaderElection.ResourceLock,
 		HealthProbeBindAddress:     opts.HealthProbeAddress,
 		Metrics: metricsserver.Options{
 			BindAddress: opts.MetricsBindAddress,
 		},
+		PprofBindAddress: opts.PprofBindAddress,
 		WebhookServer: ctrlwebhook.NewServer(ctrlwebhook.Options{
 			Port:    FleetWebhookPort,
 			CertDir: FleetWebhookCertDir,
@@ -118,8 +127,9 @@ func main() {
 		exitWithErrorFunc()
 	}
 
-	klog.V(2).InfoS("starting hubagent")
-	if opts.EnableV1Alpha1APIs {
+	klog.V(2).InfoS("starting hubagent", "controllers", opts.EnabledControllers())
+	memberClusterControllerEnabled := opts.IsControllerEnabled(options.MemberClusterController)
+	if opts.EnableV1Alpha1APIs && memberClusterControllerEnabled {
 		klog.Info("Setting up memberCluster v1alpha1 controller")
 		if err = (&mcv1alpha1.Reconciler{
 			Client:                  mgr.GetClient(),
@@ -129,7 +139,7 @@ func main() {
 			exitWithErrorFunc()
 		}
 	}
-	if opts.EnableV1Beta1APIs {
+	if opts.EnableV1Beta1APIs && memberClusterControllerEnabled {
 		klog.Info("Setting up memberCluster v1beta1 controller")
 		if err = (&mcv1beta1.Reconciler{
 			Client:                  mgr.GetClient(),
@@ -139,6 +149,30 @@ func main() {
 			klog.ErrorS(err, "unable to create v1beta1 controller", "controller", "MemberCluster")
 			exitWithErrorFunc()
 		}
+		klog.Info("Setting up cluster group controller")
+		if err = (&clustergroup.Reconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "ClusterGroup")
+			exitWithErrorFunc()
+		}
+		klog.Info("Setting up agent upgrade controller")
+		if err = (&agentupgrade.Reconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "AgentUpgrade")
+			exitWithErrorFunc()
+		}
+		if opts.EnableMemberCertificateApproval {
+			klog.Info("Setting up member certificate controller")
+			if err = (&membercertificate.Reconciler{
+				Client:                 mgr.GetClient(),
+				MaxCertificateValidity: opts.MaxMemberCertificateValidity.Duration,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "unable to create controller", "controller", "MemberCertificate")
+				exitWithErrorFunc()
+			}
+		}
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -152,7 +186,8 @@ func main() {
 
 	if opts.EnableWebhook {
 		whiteListedUsers := strings.Split(opts.WhiteListedUsers, ",")
-		if err := SetupWebhook(mgr, options.WebhookClientConnectionType(opts.WebhookClientConnectionType), opts.WebhookServiceName, whiteListedUsers, opts.EnableGuardRail, opts.EnableV1Beta1APIs); err != nil {
+		if err := SetupWebhook(mgr, options.WebhookClientConnectionType(opts.WebhookClientConnectionType), opts.WebhookServiceName, whiteListedUsers,
+			options.WebhookCertMode(strings.ToLower(opts.WebhookCertMode)), opts.WebhookCertValidity.Duration, opts.EnableGuardRail, opts.EnableV1Beta1APIs); err != nil {
 			klog.ErrorS(err, "unable to set up webhook")
 			exitWithErrorFunc()
 		}
@@ -184,9 +219,10 @@ func main() {
 }
 
 // SetupWebhook generates the webhook cert and then set up the webhook configurator.
-func SetupWebhook(mgr manager.Manager, webhookClientConnectionType options.WebhookClientConnectionType, webhookServiceName string, whiteListedUsers []string, enableGuardRail, isFleetV1Beta1API bool) error {
-	// Generate self-signed key and crt files in FleetWebhookCertDir for the webhook server to start.
-	w, err := webhook.NewWebhookConfig(mgr, webhookServiceName, FleetWebhookPort, &webhookClientConnectionType, FleetWebhookCertDir, enableGuardRail)
+func SetupWebhook(mgr manager.Manager, webhookClientConnectionType options.WebhookClientConnectionType, webhookServiceName string, whiteListedUsers []string,
+	webhookCertMode options.WebhookCertMode, webhookCertValidity time.Duration, enableGuardRail, isFleetV1Beta1API bool) error {
+	// Generate self-signed key and crt files in FleetWebhookCertDir for the webhook server to start unless they are issued by cert-manager.
+	w, err := webhook.NewWebhookConfig(mgr, webhookServiceName, FleetWebhookPort, &webhookClientConnectionType, webhookCertMode, FleetWebhookCertDir, webhookCertValidity, enableGuardRail)
 	if err != nil {
 		klog.ErrorS(err, "fail to generate WebhookConfig")
 		return err
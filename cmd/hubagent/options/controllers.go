@@ -0,0 +1,80 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package options
+
+import (
+	"sort"
+	"strings"
+)
+
+// The names of the controllers, or the groups of the controllers which must run in the same process, that can be
+// enabled or disabled with the --controllers flag.
+const (
+	// MemberClusterController is the member cluster controller, along with the cluster group, the agent upgrade, the
+	// member certificate and the Cluster API registration controllers.
+	MemberClusterController = "membercluster"
+	// ClusterResourcePlacementController is the cluster resource placement controller, along with its watchers, the
+	// resource change detector and the optional placement controllers, e.g. the placement sources, the scalers and the
+	// resource observations.
+	ClusterResourcePlacementController = "clusterresourceplacement"
+	// RolloutController is the rollout controller.
+	RolloutController = "rollout"
+	// WorkGeneratorController is the work generator.
+	WorkGeneratorController = "workgenerator"
+	// SchedulerController is the scheduler, along with its watchers.
+	SchedulerController = "scheduler"
+	// OverriderController is the cluster resource override and the resource override controllers.
+	OverriderController = "overrider"
+)
+
+// KnownControllers are the controllers that can be enabled or disabled, which are all enabled by default.
+var KnownControllers = []string{
+	MemberClusterController,
+	ClusterResourcePlacementController,
+	RolloutController,
+	WorkGeneratorController,
+	SchedulerController,
+	OverriderController,
+}
+
+// IsControllerEnabled returns if the controller is enabled by the Controllers option.
+func (o *Options) IsControllerEnabled(name string) bool {
+	hasStar := false
+	for _, ctrl := range o.Controllers {
+		switch {
+		case ctrl == name:
+			return true
+		case ctrl == "-"+name:
+			return false
+		case ctrl == "*":
+			hasStar = true
+		}
+	}
+	return hasStar
+}
+
+// EnabledControllers returns the sorted names of the enabled controllers.
+func (o *Options) EnabledControllers() []string {
+	var enabled []string
+	for _, name := range KnownControllers {
+		if o.IsControllerEnabled(name) {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
+
+// LeaderElectionID returns the name of the leader election resource. The processes which run different subsets of
+// the controllers elect their leaders independently, so the enabled controllers prefix the configured name unless all
+// of them are enabled.
+func (o *Options) LeaderElectionID() string {
+	enabled := o.EnabledControllers()
+	if len(enabled) == len(KnownControllers) {
+		return o.LeaderElection.ResourceName
+	}
+	return strings.Join(enabled, "-") + "." + o.LeaderElection.ResourceName
+}
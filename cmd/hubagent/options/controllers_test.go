@@ -0,0 +1,53 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package options
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnabledControllers(t *testing.T) {
+	testCases := map[string]struct {
+		controllers          []string
+		wantEnabled          []string
+		wantLeaderElectionID string
+	}{
+		"all controllers": {
+			controllers:          []string{"*"},
+			wantEnabled:          []string{"clusterresourceplacement", "membercluster", "overrider", "rollout", "scheduler", "workgenerator"},
+			wantLeaderElectionID: "hub.fleet.azure.com",
+		},
+		"only the scheduler": {
+			controllers:          []string{"scheduler"},
+			wantEnabled:          []string{"scheduler"},
+			wantLeaderElectionID: "scheduler.hub.fleet.azure.com",
+		},
+		"all but the scheduler and the work generator": {
+			controllers:          []string{"-scheduler", "-workgenerator", "*"},
+			wantEnabled:          []string{"clusterresourceplacement", "membercluster", "overrider", "rollout"},
+			wantLeaderElectionID: "clusterresourceplacement-membercluster-overrider-rollout.hub.fleet.azure.com",
+		},
+		"the first item wins": {
+			controllers:          []string{"workgenerator", "-workgenerator", "rollout"},
+			wantEnabled:          []string{"rollout", "workgenerator"},
+			wantLeaderElectionID: "rollout-workgenerator.hub.fleet.azure.com",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			o := &Options{Controllers: tc.controllers}
+			o.LeaderElection.ResourceName = "hub.fleet.azure.com"
+			if diff := cmp.Diff(tc.wantEnabled, o.EnabledControllers()); diff != "" {
+				t.Errorf("EnabledControllers() mismatch (-want, +got):\n%s", diff)
+			}
+			if got := o.LeaderElectionID(); got != tc.wantLeaderElectionID {
+				t.Errorf("LeaderElectionID() = %s, want %s", got, tc.wantLeaderElectionID)
+			}
+		})
+	}
+}
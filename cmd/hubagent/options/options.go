@@ -7,6 +7,7 @@ package options
 
 import (
 	"flag"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +34,12 @@ type Options struct {
 	// It can be set to "0" to disable the metrics serving.
 	// Defaults to ":8080".
 	MetricsBindAddress string
+	// PprofBindAddress is the TCP address that the controller should bind to for serving the pprof endpoints.
+	// It is empty by default, i.e. the pprof endpoints are not served.
+	PprofBindAddress string
+	// PlacementStatusBindAddress is the TCP address that the hub agent binds to for serving the placement detail of
+	// the cluster resource placements on each member cluster. It is empty by default, i.e. the detail is not served.
+	PlacementStatusBindAddress string
 	// EnableWebhook indicates if we will run a webhook
 	EnableWebhook bool
 	// Webhook service name
@@ -43,6 +50,11 @@ type Options struct {
 	WhiteListedUsers string
 	// Sets the connection type for the webhook.
 	WebhookClientConnectionType string
+	// WebhookCertMode sets how the serving certificate of the webhook is provisioned and rotated.
+	WebhookCertMode string
+	// WebhookCertValidity is the validity duration of the self-signed webhook serving certificate.
+	// The certificate is rotated before it expires.
+	WebhookCertValidity metav1.Duration
 	// NetworkingAgentsEnabled indicates if we enable network agents
 	NetworkingAgentsEnabled bool
 	// ClusterUnhealthyThreshold is the duration of failure for the cluster to be considered unhealthy.
@@ -57,6 +69,10 @@ type Options struct {
 	// AllowedPropagatingAPIs indicates semicolon separated resources that should be allowed for propagating.
 	// This is mutually exclusive with SkippedPropagatingAPIs.
 	AllowedPropagatingAPIs string
+	// MetadataOnlyAPIs indicates semicolon separated resources that are watched with metadata-only informers, so
+	// that the hub agent caches the metadata of their objects only and fetches the full objects when it takes the
+	// resource snapshots.
+	MetadataOnlyAPIs string
 	// SkippedPropagatingNamespaces is a list of namespaces that will be skipped for propagating.
 	SkippedPropagatingNamespaces string
 	// HubQPS is the QPS to use while talking with hub-apiserver. Default is 20.0.
@@ -79,6 +95,116 @@ type Options struct {
 	EnableV1Alpha1APIs bool
 	// EnableV1Beta1APIs enables the agents to watch the v1beta1 CRs.
 	EnableV1Beta1APIs bool
+	// SelectedResourcesValidationMode decides how the CRP controller handles the selected resources which fail the
+	// dry-run validation before the resource snapshots are created. It can be Disabled, Warn or Reject.
+	SelectedResourcesValidationMode string
+	// OverrideProtectedPaths indicates semicolon separated JSON pointer paths that the overrides are never allowed to modify.
+	OverrideProtectedPaths string
+	// EnableMemberCertificateApproval enables the hub agent to approve the certificate signing requests that the member
+	// agents create to rotate their client certificates, and to revoke their access when the member clusters are removed.
+	EnableMemberCertificateApproval bool
+	// MaxMemberCertificateValidity is the max validity of a member agent client certificate that the hub agent approves.
+	MaxMemberCertificateValidity metav1.Duration
+	// WorkSigningKeyFile is the PEM encoded ECDSA or Ed25519 private key file with which the hub agent signs the content of the works.
+	WorkSigningKeyFile string
+	// WorkSigningAzureKeyVaultKeyURL is the URL of the versioned EC P-256 Azure Key Vault key with which the hub agent
+	// signs the content of the works.
+	WorkSigningAzureKeyVaultKeyURL string
+	// SealAllSecrets makes the hub agent seal all the secrets in the works with the keys of the member clusters, instead
+	// of only the ones annotated with kubernetes-fleet.io/seal.
+	SealAllSecrets bool
+	// EnableArgoCDHealthBridge enables the controller which makes the placements own their bindings, so that Argo CD
+	// shows the placement status per cluster in its resource tree.
+	EnableArgoCDHealthBridge bool
+	// EnableClusterAPIRegistration enables the controller which registers the Cluster API clusters labeled with
+	// kubernetes-fleet.io/auto-register=true as member clusters and bootstraps the member agent onto them.
+	EnableClusterAPIRegistration bool
+	// ClusterAPIHubServerURL is the URL of the hub API server that the member agents of the Cluster API clusters connect to.
+	ClusterAPIHubServerURL string
+	// ClusterAPIBootstrapConfigMap is the namespace/name of the configMap whose data are the templates of the manifests,
+	// e.g. the member agent, applied to each registered Cluster API cluster.
+	ClusterAPIBootstrapConfigMap string
+	// EnablePlacementSources enables the controller which renders the manifests of the placement sources, i.e. Git
+	// repositories and OCI artifacts, so that the cluster resource placements can select them.
+	EnablePlacementSources bool
+	// CloudEventsSinkURL is the HTTP endpoint that the lifecycle transitions of the cluster resource placements are
+	// posted to as CloudEvents. The events are not emitted if it is empty.
+	CloudEventsSinkURL string
+	// PlacementStatusExportNamespace is the namespace which the rollout status summaries of the cluster resource
+	// placements are exported to as config maps. The summaries are not exported if it is empty.
+	PlacementStatusExportNamespace string
+	// EnableRestoreMode enables the controllers which adopt the dependents of the objects restored from a hub backup,
+	// e.g. the works of the restored bindings, instead of letting the garbage collector delete them, and makes the work
+	// generator adopt the restored works of a placement by their labels instead of creating duplicates of them.
+	EnableRestoreMode bool
+	// EnablePlacementScalers enables the controller which scales the number of clusters of the PickN placements with
+	// the external metrics of their placement scalers.
+	EnablePlacementScalers bool
+	// EnableResourceObservations enables the controller which observes the resources selected by the cluster resource
+	// observations on the member clusters and reports them in the status of the observations.
+	EnableResourceObservations bool
+	// EnablePlacementPromotions enables the controller which promotes the cluster resource placements, i.e. creates
+	// new placements from them with the policies of their promotions.
+	EnablePlacementPromotions bool
+	// EnableSchedulingExplains enables the controller which explains how the scheduler schedules the cluster resource
+	// placements with the cluster scheduling explains; it only runs along with the scheduler.
+	EnableSchedulingExplains bool
+	// EnableMemberEventForwarding enables the controller which attaches the events that the member agents forward to
+	// the works, e.g. the failures of the pods of a placed deployment, to the placements and the bindings of the works.
+	EnableMemberEventForwarding bool
+	// EnablePlacementSharding makes the replicas of the hub agent shard the cluster resource placements, so that each
+	// replica schedules, rolls out and generates the works of its own placements instead of the leader doing all.
+	EnablePlacementSharding bool
+	// PlacementShardLeaseDuration is the duration of the leases with which the replicas announce that they are alive
+	// and hold the shards; the shards of a replica move to the other replicas once its leases expire.
+	PlacementShardLeaseDuration metav1.Duration
+	// PlacementShardCount is the number of the shards which the placements are split into when they are sharded.
+	PlacementShardCount int
+	// BindingStatusBatchInterval is the interval over which the work generator batches and coalesces the status
+	// writes of the bindings; the status of a binding is written in its reconcile if it is zero.
+	BindingStatusBatchInterval metav1.Duration
+	// MemberWorkWriteQPS is the rate at which the work generator writes the works to the namespace of each member
+	// cluster; the writes are not limited if it is 0.
+	MemberWorkWriteQPS float64
+	// MemberWorkWriteBurst is the number of the works which the work generator can write to the namespace of a member
+	// cluster at once when MemberWorkWriteQPS is set.
+	MemberWorkWriteBurst int
+	// OverrideRenderCacheSize is the max number of the resources patched by the override rules which the work
+	// generator caches; the patched resources are not cached if it is 0.
+	OverrideRenderCacheSize int
+	// EnableMetadataOverrides enables the cluster metadata overrides, which add labels and annotations to the
+	// resources selected with wildcards on the target clusters.
+	EnableMetadataOverrides bool
+	// PlacementStatusCompactionThreshold is the number of the selected clusters above which the status of a
+	// placement is compacted; it's disabled if it is 0.
+	PlacementStatusCompactionThreshold int
+	// EnableAdaptivePlacementResync adapts the intervals at which the placements whose rollout has not completed are
+	// resynced to their stability instead of resyncing them at fixed intervals.
+	EnableAdaptivePlacementResync bool
+	// PlacementResyncMinInterval is the interval at which a placement which has just started failing is resynced when
+	// EnableAdaptivePlacementResync is set.
+	PlacementResyncMinInterval metav1.Duration
+	// PlacementResyncMaxInterval is the longest interval at which a placement which has been available for long is
+	// resynced when EnableAdaptivePlacementResync is set.
+	PlacementResyncMaxInterval metav1.Duration
+	// ResourceSnapshotMemoryBudgetMB is the number of MiB that the serialized selected resources of a placement may
+	// take before they are snapshotted; it's disabled if it is 0.
+	ResourceSnapshotMemoryBudgetMB int
+	// PlacementStatusStaleTimeout is how long the member agent of a cluster may not send heartbeats before the
+	// placement statuses on the cluster are marked as stale; it's disabled if it is 0.
+	PlacementStatusStaleTimeout metav1.Duration
+	// PlacementSLIWindows are the windows over which the availability SLIs of the cluster resource placements and the
+	// member clusters are exported as metrics; the SLIs are not exported if it is empty.
+	PlacementSLIWindows []time.Duration
+	// PlacementSLISampleInterval is the interval at which the availability of the bindings is sampled for the SLIs.
+	PlacementSLISampleInterval metav1.Duration
+	// SchedulerProfilesConfigFile is the YAML file of the scheduler profiles, i.e. the sets of the scheduler plugins and
+	// their weights, which the cluster resource placements can select by name besides the default profile.
+	SchedulerProfilesConfigFile string
+	// HubRegion and HubZone are the region and zone of the hub cluster, which the scheduler prefers the clusters close
+	// to for the cluster resource placements whose network proximity does not name an anchor cluster.
+	HubRegion string
+	HubZone   string
 }
 
 // NewOptions builds an empty options.
@@ -90,6 +216,7 @@ func NewOptions() *Options {
 			ResourceNamespace: utils.FleetSystemNamespace,
 			ResourceName:      "136224848560.hub.fleet.azure.com",
 		},
+		Controllers:                   []string{"*"},
 		MaxConcurrentClusterPlacement: 10,
 		ConcurrentResourceChangeSyncs: 1,
 		MaxFleetSizeSupported:         100,
@@ -102,6 +229,9 @@ func (o *Options) AddFlags(flags *flag.FlagSet) {
 	flags.StringVar(&o.HealthProbeAddress, "health-probe-bind-address", ":8081",
 		"The IP address on which to listen for the --secure-port port.")
 	flags.StringVar(&o.MetricsBindAddress, "metrics-bind-address", ":8080", "The TCP address that the controller should bind to for serving prometheus metrics(e.g. 127.0.0.1:8088, :8088)")
+	flags.StringVar(&o.PprofBindAddress, "pprof-bind-address", "", "The TCP address that the controller should bind to for serving the pprof endpoints(e.g. 127.0.0.1:6060). The pprof endpoints are disabled if it is empty.")
+	flags.StringVar(&o.PlacementStatusBindAddress, "placement-status-bind-address", "",
+		"The TCP address that the hub agent binds to for serving the placement detail of the cluster resource placements on each member cluster (e.g. 127.0.0.1:8090), including the status of every work and manifest, and the preview of the works which the placements would place on them. The requests are not authenticated, so bind it to the loopback interface and reach it by port forwarding. The placement detail is not served if it is empty.")
 	flags.BoolVar(&o.LeaderElection.LeaderElect, "leader-elect", false, "Start a leader election client and gain leadership before executing the main loop. Enable this when running replicated components for high availability.")
 	flags.DurationVar(&o.LeaderElection.LeaseDuration.Duration, "leader-lease-duration", 15*time.Second, "This is effectively the maximum duration that a leader can be stopped before someone else will replace it.")
 	flag.StringVar(&o.LeaderElection.ResourceNamespace, "leader-election-namespace", utils.FleetSystemNamespace, "The namespace in which the leader election resource will be created.")
@@ -111,6 +241,8 @@ func (o *Options) AddFlags(flags *flag.FlagSet) {
 	flag.BoolVar(&o.EnableGuardRail, "enable-guard-rail", false, "If set, the fleet guard rail webhook configurations are enabled.")
 	flag.StringVar(&o.WhiteListedUsers, "whitelisted-users", "", "If set, white listed users can modify fleet related resources.")
 	flag.StringVar(&o.WebhookClientConnectionType, "webhook-client-connection-type", "url", "Sets the connection type used by the webhook client. Only URL or Service is valid.")
+	flag.StringVar(&o.WebhookCertMode, "webhook-cert-mode", "selfsigned", "Sets how the webhook serving certificate is provisioned. Only selfsigned or certmanager is valid.")
+	flags.DurationVar(&o.WebhookCertValidity.Duration, "webhook-cert-validity", 365*24*time.Hour, "The validity duration of the self-signed webhook serving certificate, which is rotated before it expires.")
 	flag.BoolVar(&o.NetworkingAgentsEnabled, "networking-agents-enabled", false, "Whether the networking agents are enabled or not.")
 	flags.DurationVar(&o.ClusterUnhealthyThreshold.Duration, "cluster-unhealthy-threshold", 60*time.Second, "The duration for a member cluster to be in a degraded state before considered unhealthy.")
 	flags.DurationVar(&o.WorkPendingGracePeriod.Duration, "work-pending-grace-period", 15*time.Second,
@@ -123,6 +255,7 @@ func (o *Options) AddFlags(flags *flag.FlagSet) {
 		"<group> for skip resources with a specific API group(e.g. networking.k8s.io),\n"+
 		"<group>/<version> for skip resources with a specific API version(e.g. networking.k8s.io/v1beta1),\n"+
 		"<group>/<version>/<kind>,<kind> for skip one or more specific resource(e.g. networking.k8s.io/v1beta1/Ingress,IngressClass) where the kinds are case-insensitive.")
+	flags.StringVar(&o.MetadataOnlyAPIs, "metadata-only-apis", "", "Semicolon separated resources that are watched with metadata-only informers, e.g. v1/Secret,ConfigMap, so that the hub agent caches the metadata of their objects only and fetches the full objects from the API server when it takes the resource snapshots. Supported formats are the same as --skipped-propagating-apis.")
 	flags.StringVar(&o.SkippedPropagatingNamespaces, "skipped-propagating-namespaces", "",
 		"Comma-separated namespaces that should be skipped from propagating in addition to the default skipped namespaces(fleet-system, namespaces prefixed by kube- and fleet-work-).")
 	flags.Float64Var(&o.HubQPS, "hub-api-qps", 250, "QPS to use while talking with fleet-apiserver. Doesn't cover events and node heartbeat apis which rate limiting is controlled by a different set of flags.")
@@ -133,6 +266,100 @@ func (o *Options) AddFlags(flags *flag.FlagSet) {
 	flags.IntVar(&o.MaxFleetSizeSupported, "max-fleet-size", 100, "The max number of member clusters supported in this fleet")
 	flags.BoolVar(&o.EnableV1Alpha1APIs, "enable-v1alpha1-apis", false, "If set, the agents will watch for the v1alpha1 APIs.")
 	flags.BoolVar(&o.EnableV1Beta1APIs, "enable-v1beta1-apis", true, "If set, the agents will watch for the v1beta1 APIs.")
+	flags.StringVar(&o.SelectedResourcesValidationMode, "selected-resources-validation-mode", "Disabled",
+		"Sets how the selected resources of a cluster resource placement are validated before the resource snapshots are created. Only Disabled, Warn or Reject is valid.")
+	flags.StringVar(&o.OverrideProtectedPaths, "override-protected-paths", "", "Semicolon separated JSON pointer paths that the clusterResourceOverrides and resourceOverrides are not allowed to modify, "+
+		"including their parent and child paths; the /metadata/labels and /metadata/annotations keys also apply to the clusterMetadataOverrides. \"*\" matches any single path segment (e.g. /spec/template/spec/containers/*/image;/spec/template/spec/securityContext).")
+	flags.BoolVar(&o.EnableMemberCertificateApproval, "enable-member-certificate-approval", false,
+		"If set, the hub agent approves the certificate signing requests of the member agent client certificates and revokes their access when the member clusters are removed.")
+	flags.DurationVar(&o.MaxMemberCertificateValidity.Duration, "max-member-certificate-validity", 24*time.Hour,
+		"The max validity of a member agent client certificate that the hub agent approves. It cannot be less than 10m.")
+	flags.StringVar(&o.WorkSigningKeyFile, "work-signing-key-file", "",
+		"If set, the hub agent signs the content of the works with the PEM encoded ECDSA or Ed25519 private key in the file, so that the member agents can verify it before applying.")
+	flags.StringVar(&o.WorkSigningAzureKeyVaultKeyURL, "work-signing-azure-key-vault-key-url", "",
+		"If set, the hub agent signs the content of the works with the EC P-256 Azure Key Vault key, e.g. https://<vault>.vault.azure.net/keys/<name>/<version>.")
+	flags.BoolVar(&o.SealAllSecrets, "seal-all-secrets", false,
+		"If set, the hub agent encrypts the data of all the secrets in the works with the keys of the member clusters, instead of only the ones annotated with kubernetes-fleet.io/seal.")
+	flags.BoolVar(&o.EnableArgoCDHealthBridge, "enable-argocd-health-bridge", false,
+		"If set, the hub agent adds an owner reference to the placement on each of its bindings, so that Argo CD shows the placement status per cluster in its resource tree.")
+	flags.BoolVar(&o.EnableClusterAPIRegistration, "enable-cluster-api-registration", false,
+		"If set, the hub agent registers the Cluster API clusters labeled with kubernetes-fleet.io/auto-register=true as member clusters once they are provisioned, and deregisters them when they are deleted.")
+	flags.StringVar(&o.ClusterAPIHubServerURL, "cluster-api-hub-server-url", "",
+		"The URL of the hub API server that the member agents of the registered Cluster API clusters connect to.")
+	flags.StringVar(&o.ClusterAPIBootstrapConfigMap, "cluster-api-bootstrap-configmap", "",
+		"The namespace/name of the configMap whose data are the Go templates of the manifests, e.g. the member agent, applied to each registered Cluster API cluster.")
+	flags.BoolVar(&o.EnablePlacementSources, "enable-placement-sources", false,
+		"If set, the hub agent renders the manifests of the placement sources, i.e. Git repositories and OCI artifacts, into resources that the cluster resource placements select by the placement source name. The git and helm executables must be in the PATH to render the Git sources and the Helm charts.")
+	flags.StringVar(&o.CloudEventsSinkURL, "cloudevents-sink-url", "",
+		"If set, the hub agent posts the lifecycle transitions of the cluster resource placements, e.g. scheduled, applied, available and failed, as CloudEvents to the HTTP endpoint.")
+	flags.StringVar(&o.PlacementStatusExportNamespace, "placement-status-export-namespace", "",
+		"If set, the hub agent writes the rollout status summary of each cluster resource placement to the config map of the same name in the namespace, e.g. for a GitOps pipeline to commit them to a Git repository. The namespace needs to exist.")
+	flags.BoolVar(&o.EnableRestoreMode, "enable-restore-mode", false,
+		"If set, the hub agent adopts the dependents of the objects restored from a hub backup, e.g. by Velero, whose owner references point to the old UIDs or are stripped, so that the restored placements keep their placed resources on the member clusters. The work generator also adopts the restored works of a placement on a member cluster by their labels, e.g. when their binding is recreated under another name, instead of creating duplicates of them.")
+	flags.BoolVar(&o.EnablePlacementScalers, "enable-placement-scalers", false,
+		"If set, the hub agent scales the number of clusters of the PickN cluster resource placements with the external metrics, e.g. the Prometheus queries, of the placement scalers.")
+	flags.BoolVar(&o.EnableResourceObservations, "enable-resource-observations", false,
+		"If set, the hub agent observes the resources selected by the cluster resource observations on the member clusters, whether they are placed by Fleet or not, and reports their presence and health in the status of the observations. Nothing is applied to the member clusters for the observations.")
+	flags.BoolVar(&o.EnablePlacementPromotions, "enable-placement-promotions", false,
+		"If set, the hub agent promotes the cluster resource placements with the cluster resource placement promotions, i.e. creates a new placement from a placement with the placement policy of its promotion, e.g. to promote a placement from the staging clusters to the production clusters, and annotates the new placement with the placement and the resource snapshot it is promoted from.")
+	flags.BoolVar(&o.EnableSchedulingExplains, "enable-scheduling-explains", false,
+		"If set, the scheduler explains the scheduling of the cluster resource placements with the cluster scheduling explains, i.e. runs a scheduling cycle for a placement without creating or updating any binding, and reports why each member cluster is picked or not, with the scores of the clusters, in the status of the explain.")
+	flags.BoolVar(&o.EnableMemberEventForwarding, "enable-member-event-forwarding", false,
+		"If set, the hub agent attaches the warning events that the member agents forward to the works, e.g. the failed scheduling or the crash loops of the pods of a placed deployment, to the cluster resource placements and the bindings of the works. The member agents forward the events only if they run with --forward-events.")
+	flags.BoolVar(&o.EnablePlacementSharding, "enable-placement-sharding", false,
+		"If set, the replicas of the hub agent shard the cluster resource placements by the hash of their names or their kubernetes-fleet.io/shard labels, and each replica schedules, rolls out and generates the works of the placements in the shards it holds. The shards are rebalanced when the replicas change.")
+	flags.DurationVar(&o.PlacementShardLeaseDuration.Duration, "placement-shard-lease-duration", 15*time.Second,
+		"The duration of the leases with which the replicas of the hub agent announce that they are alive and hold the shards when the placements are sharded; the shards of a replica move to the others once its leases expire.")
+	flags.IntVar(&o.PlacementShardCount, "placement-shard-count", 16,
+		"The number of the shards which the cluster resource placements are split into when they are sharded. Each shard is held by one replica at a time through its lease; set it to a multiple of the replicas for an even spread.")
+	flags.DurationVar(&o.BindingStatusBatchInterval.Duration, "binding-status-batch-interval", 500*time.Millisecond,
+		"The interval over which the work generator batches and coalesces the status writes of the cluster resource bindings, so that only the latest status of a binding is written. Set it to 0 to write the status of a binding in every reconcile.")
+	flags.Float64Var(&o.MemberWorkWriteQPS, "member-work-write-qps", 0,
+		"If set, the rate at which the work generator creates, updates and deletes the works of each member cluster, so that a burst of writes on the hub does not overwhelm the API server of a small member cluster; the throttled bindings report a WorkSyncThrottled condition. Set it to 0 to disable the limit.")
+	flags.IntVar(&o.MemberWorkWriteBurst, "member-work-write-burst", 20,
+		"The number of the works which the work generator can write to a member cluster at once when --member-work-write-qps is set.")
+	flags.IntVar(&o.OverrideRenderCacheSize, "override-render-cache-size", 5000,
+		"The max number of the resources patched by the override rules which the work generator caches by the content of the resources and the overrides, so that an override shared by many clusters is only rendered once. Set it to 0 to disable the cache.")
+	flags.BoolVar(&o.EnableMetadataOverrides, "enable-metadata-overrides", false,
+		"If set, the work generator applies the ClusterMetadataOverrides, which add labels and annotations to the resources selected with wildcards on the target clusters, after the other overrides. The ClusterMetadataOverride CRD needs to be installed.")
+	flags.BoolVar(&o.EnableAdaptivePlacementResync, "enable-adaptive-placement-resync", false,
+		"If set, the cluster resource placements whose rollout has not completed are resynced less often the longer they have been available, and more often when they have just started failing, instead of at fixed intervals.")
+	flags.DurationVar(&o.PlacementResyncMinInterval.Duration, "placement-resync-min-interval", 15*time.Second,
+		"The interval at which a cluster resource placement which has just started failing is resynced when --enable-adaptive-placement-resync is set.")
+	flags.DurationVar(&o.PlacementResyncMaxInterval.Duration, "placement-resync-max-interval", time.Hour,
+		"The longest interval at which a cluster resource placement which has been available for long is resynced when --enable-adaptive-placement-resync is set.")
+	flags.IntVar(&o.PlacementStatusCompactionThreshold, "placement-status-compaction-threshold", 0,
+		"If set, the cluster resource placements which select more clusters than the threshold keep only the placement statuses of the unhealthy clusters along with a summary, and the placement status on every selected cluster is written to a PerClusterPlacementStatus in the reserved namespace of the cluster. Set it to 0 to disable the compaction.")
+	flags.DurationVar(&o.PlacementStatusStaleTimeout.Duration, "placement-status-stale-timeout", 5*time.Minute,
+		"How long the member agent of a cluster may not send heartbeats before the applied and available conditions of the cluster resource placements on the cluster are marked as stale instead of showing the last reported status as current. Set it to 0 to disable it.")
+	flags.IntVar(&o.ResourceSnapshotMemoryBudgetMB, "resource-snapshot-memory-budget-mb", 0,
+		"If set, the number of MiB that the serialized resources selected by a cluster resource placement may take; the placements which select more are rejected with an InvalidResourceSelectors condition instead of being snapshotted, so that a single giant selection cannot exhaust the memory of the hub agent. Set it to 0 to disable the budget.")
+	flags.Func("placement-sli-windows", "If set, a comma separated list of the windows, e.g. 1h,24h, over which the hub agent exports the availability SLIs of the cluster resource placements and the member clusters as metrics, i.e. the fraction of the sample intervals in which the bindings were available.",
+		func(value string) error {
+			o.PlacementSLIWindows = nil
+			for _, item := range strings.Split(value, ",") {
+				window, err := time.ParseDuration(strings.TrimSpace(item))
+				if err != nil {
+					return err
+				}
+				o.PlacementSLIWindows = append(o.PlacementSLIWindows, window)
+			}
+			return nil
+		})
+	flags.DurationVar(&o.PlacementSLISampleInterval.Duration, "placement-sli-sample-interval", time.Minute,
+		"The interval at which the hub agent samples whether the bindings are available for the availability SLIs when --placement-sli-windows is set.")
+	flags.StringVar(&o.SchedulerProfilesConfigFile, "scheduler-profiles-config-file", "",
+		"If set, the YAML file of the scheduler profiles, each of which names the scheduler plugins it enables and their weights, that the cluster resource placements can select with their schedulerProfile policy field besides the default profile.")
+	flags.StringVar(&o.HubRegion, "hub-region", "",
+		"The region of the hub cluster, e.g. eastus. The scheduler prefers the member clusters whose topology.kubernetes.io/region label matches it for the cluster resource placements whose networkProximity policy field does not name an anchor cluster.")
+	flags.StringVar(&o.HubZone, "hub-zone", "",
+		"The zone of the hub cluster, e.g. eastus-1. The scheduler prefers the member clusters whose topology.kubernetes.io/zone label matches it the most when --hub-region is set.")
+	flags.Func("controllers", "A comma separated list of the controllers to enable, where '*' enables all the controllers, 'foo' enables 'foo' and '-foo' disables 'foo'; the first item for a controller wins. "+
+		"The known controllers are "+strings.Join(KnownControllers, ", ")+". The processes which enable different controllers elect their leaders independently, so that the controllers can be split across deployments. Defaults to '*'.",
+		func(value string) error {
+			o.Controllers = strings.Split(value, ",")
+			return nil
+		})
 
 	o.RateLimiterOpts.AddFlags(flags)
 }
@@ -6,11 +6,26 @@ Licensed under the MIT license.
 package options
 
 import (
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"go.goms.io/fleet/pkg/utils"
 )
 
+// validSelectedResourcesValidationModes are the modes supported by the clusterResourcePlacement controller.
+var validSelectedResourcesValidationModes = map[string]bool{
+	"Disabled": true,
+	"Warn":     true,
+	"Reject":   true,
+}
+
+// minMemberCertificateValidity is the min validity of a client certificate issued by the kube-apiserver-client signer.
+const minMemberCertificateValidity = 10 * time.Minute
+
 // TODO: Clean up the validations we don't need and add the ones we need
 
 // Validate checks Options and return a slice of found errs.
@@ -30,6 +45,10 @@ func (o *Options) Validate() field.ErrorList {
 		errs = append(errs, field.Invalid(newPath.Child("AllowedPropagatingAPIs"), o.AllowedPropagatingAPIs, "Invalid API string"))
 	}
 
+	if err := utils.NewResourceConfig(true).Parse(o.MetadataOnlyAPIs); err != nil {
+		errs = append(errs, field.Invalid(newPath.Child("MetadataOnlyAPIs"), o.MetadataOnlyAPIs, "Invalid API string"))
+	}
+
 	if o.ClusterUnhealthyThreshold.Duration <= 0 {
 		errs = append(errs, field.Invalid(newPath.Child("ClusterUnhealthyThreshold"), o.ClusterUnhealthyThreshold, "Must be greater than 0"))
 	}
@@ -46,9 +65,133 @@ func (o *Options) Validate() field.ErrorList {
 		errs = append(errs, field.Invalid(newPath.Child("WebhookClientConnectionType"), o.WebhookClientConnectionType, err.Error()))
 	}
 
+	certMode, err := parseWebhookCertModeString(o.WebhookCertMode)
+	if err != nil {
+		errs = append(errs, field.Invalid(newPath.Child("WebhookCertMode"), o.WebhookCertMode, err.Error()))
+	}
+	if certMode == SelfSigned && o.WebhookCertValidity.Duration <= 0 {
+		errs = append(errs, field.Invalid(newPath.Child("WebhookCertValidity"), o.WebhookCertValidity, "Must be greater than 0"))
+	}
+
 	if !o.EnableV1Alpha1APIs && !o.EnableV1Beta1APIs {
 		errs = append(errs, field.Required(newPath.Child("EnableV1Alpha1APIs"), "Either EnableV1Alpha1APIs or EnableV1Beta1APIs is required"))
 	}
 
+	if !validSelectedResourcesValidationModes[o.SelectedResourcesValidationMode] {
+		errs = append(errs, field.Invalid(newPath.Child("SelectedResourcesValidationMode"), o.SelectedResourcesValidationMode, "Must be Disabled, Warn or Reject"))
+	}
+
+	if o.EnableMemberCertificateApproval && o.MaxMemberCertificateValidity.Duration < minMemberCertificateValidity {
+		errs = append(errs, field.Invalid(newPath.Child("MaxMemberCertificateValidity"), o.MaxMemberCertificateValidity, "Must be at least 10m"))
+	}
+
+	if o.WorkSigningKeyFile != "" && o.WorkSigningAzureKeyVaultKeyURL != "" {
+		errs = append(errs, field.Invalid(newPath.Child("WorkSigningAzureKeyVaultKeyURL"), o.WorkSigningAzureKeyVaultKeyURL, "Cannot be set together with WorkSigningKeyFile"))
+	}
+
+	if o.EnableClusterAPIRegistration && o.ClusterAPIHubServerURL == "" {
+		errs = append(errs, field.Required(newPath.Child("ClusterAPIHubServerURL"), "Hub server URL is required when the Cluster API registration is enabled"))
+	}
+	if o.ClusterAPIBootstrapConfigMap != "" {
+		if namespace, name, ok := strings.Cut(o.ClusterAPIBootstrapConfigMap, "/"); !ok || namespace == "" || name == "" {
+			errs = append(errs, field.Invalid(newPath.Child("ClusterAPIBootstrapConfigMap"), o.ClusterAPIBootstrapConfigMap, "Must be in the format of namespace/name"))
+		}
+	}
+
+	if o.CloudEventsSinkURL != "" {
+		if sinkURL, err := url.ParseRequestURI(o.CloudEventsSinkURL); err != nil || (sinkURL.Scheme != "http" && sinkURL.Scheme != "https") || sinkURL.Host == "" {
+			errs = append(errs, field.Invalid(newPath.Child("CloudEventsSinkURL"), o.CloudEventsSinkURL, "Must be an absolute HTTP or HTTPS URL"))
+		}
+	}
+	if o.PlacementStatusExportNamespace != "" {
+		for _, msg := range validation.IsDNS1123Label(o.PlacementStatusExportNamespace) {
+			errs = append(errs, field.Invalid(newPath.Child("PlacementStatusExportNamespace"), o.PlacementStatusExportNamespace, msg))
+		}
+	}
+
+	if o.EnablePlacementSharding {
+		if o.EnableV1Alpha1APIs {
+			errs = append(errs, field.Invalid(newPath.Child("EnablePlacementSharding"), o.EnablePlacementSharding, "Cannot be set together with EnableV1Alpha1APIs"))
+		}
+		if o.PlacementShardLeaseDuration.Duration < time.Second {
+			errs = append(errs, field.Invalid(newPath.Child("PlacementShardLeaseDuration"), o.PlacementShardLeaseDuration, "Must be at least 1s"))
+		}
+		if o.PlacementShardCount < 1 {
+			errs = append(errs, field.Invalid(newPath.Child("PlacementShardCount"), o.PlacementShardCount, "Must be at least 1"))
+		}
+	}
+
+	knownControllers := make(map[string]bool, len(KnownControllers))
+	for _, name := range KnownControllers {
+		knownControllers[name] = true
+	}
+	for _, ctrl := range o.Controllers {
+		if ctrl != "*" && !knownControllers[strings.TrimPrefix(ctrl, "-")] {
+			errs = append(errs, field.NotSupported(newPath.Child("Controllers"), ctrl, append([]string{"*"}, KnownControllers...)))
+		}
+	}
+	switch enabled := len(o.EnabledControllers()); {
+	case enabled == 0:
+		errs = append(errs, field.Invalid(newPath.Child("Controllers"), o.Controllers, "At least one controller must be enabled"))
+	case enabled < len(KnownControllers) && o.EnablePlacementSharding:
+		// the replicas of all the processes would announce themselves with the same shard leases
+		errs = append(errs, field.Invalid(newPath.Child("Controllers"), o.Controllers, "Cannot disable any controller when EnablePlacementSharding is set"))
+	}
+
+	if o.BindingStatusBatchInterval.Duration < 0 {
+		errs = append(errs, field.Invalid(newPath.Child("BindingStatusBatchInterval"), o.BindingStatusBatchInterval, "Must not be negative"))
+	}
+
+	if o.MemberWorkWriteQPS < 0 {
+		errs = append(errs, field.Invalid(newPath.Child("MemberWorkWriteQPS"), o.MemberWorkWriteQPS, "Must not be negative"))
+	}
+
+	if o.MemberWorkWriteQPS > 0 && o.MemberWorkWriteBurst < 1 {
+		errs = append(errs, field.Invalid(newPath.Child("MemberWorkWriteBurst"), o.MemberWorkWriteBurst, "Must be positive when MemberWorkWriteQPS is set"))
+	}
+
+	if o.OverrideRenderCacheSize < 0 {
+		errs = append(errs, field.Invalid(newPath.Child("OverrideRenderCacheSize"), o.OverrideRenderCacheSize, "Must not be negative"))
+	}
+
+	if o.PlacementStatusCompactionThreshold < 0 {
+		errs = append(errs, field.Invalid(newPath.Child("PlacementStatusCompactionThreshold"), o.PlacementStatusCompactionThreshold, "Must not be negative"))
+	}
+
+	if o.PlacementStatusStaleTimeout.Duration < 0 {
+		errs = append(errs, field.Invalid(newPath.Child("PlacementStatusStaleTimeout"), o.PlacementStatusStaleTimeout, "Must not be negative"))
+	}
+
+	if len(o.PlacementSLIWindows) > 0 {
+		if o.PlacementSLISampleInterval.Duration <= 0 {
+			errs = append(errs, field.Invalid(newPath.Child("PlacementSLISampleInterval"), o.PlacementSLISampleInterval, "Must be positive when PlacementSLIWindows is set"))
+		}
+		for _, window := range o.PlacementSLIWindows {
+			if window < o.PlacementSLISampleInterval.Duration {
+				errs = append(errs, field.Invalid(newPath.Child("PlacementSLIWindows"), window.String(), "Must not be shorter than PlacementSLISampleInterval"))
+			}
+		}
+	}
+
+	if o.ResourceSnapshotMemoryBudgetMB < 0 {
+		errs = append(errs, field.Invalid(newPath.Child("ResourceSnapshotMemoryBudgetMB"), o.ResourceSnapshotMemoryBudgetMB, "Must not be negative"))
+	}
+
+	if o.EnableAdaptivePlacementResync {
+		if o.PlacementResyncMinInterval.Duration <= 0 {
+			errs = append(errs, field.Invalid(newPath.Child("PlacementResyncMinInterval"), o.PlacementResyncMinInterval, "Must be positive when EnableAdaptivePlacementResync is set"))
+		}
+		if o.PlacementResyncMaxInterval.Duration < o.PlacementResyncMinInterval.Duration {
+			errs = append(errs, field.Invalid(newPath.Child("PlacementResyncMaxInterval"), o.PlacementResyncMaxInterval, "Must not be less than PlacementResyncMinInterval"))
+		}
+	}
+
+	for _, path := range strings.Split(o.OverrideProtectedPaths, ";") {
+		if len(path) > 0 && !strings.HasPrefix(path, "/") {
+			errs = append(errs, field.Invalid(newPath.Child("OverrideProtectedPaths"), o.OverrideProtectedPaths, "Each path must start with /"))
+			break
+		}
+	}
+
 	return errs
 }
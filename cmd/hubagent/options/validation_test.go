@@ -20,11 +20,15 @@ type ModifyOptions func(option *Options)
 // newTestOptions creates an Options with default parameters.
 func newTestOptions(modifyOptions ModifyOptions) Options {
 	option := Options{
-		SkippedPropagatingAPIs:      "fleet.azure.com;multicluster.x-k8s.io",
-		WorkPendingGracePeriod:      metav1.Duration{Duration: 10 * time.Second},
-		ClusterUnhealthyThreshold:   metav1.Duration{Duration: 1 * time.Second},
-		WebhookClientConnectionType: "url",
-		EnableV1Alpha1APIs:          true,
+		Controllers:                     []string{"*"},
+		SkippedPropagatingAPIs:          "fleet.azure.com;multicluster.x-k8s.io",
+		WorkPendingGracePeriod:          metav1.Duration{Duration: 10 * time.Second},
+		ClusterUnhealthyThreshold:       metav1.Duration{Duration: 1 * time.Second},
+		WebhookClientConnectionType:     "url",
+		WebhookCertMode:                 "selfsigned",
+		WebhookCertValidity:             metav1.Duration{Duration: 24 * time.Hour},
+		EnableV1Alpha1APIs:              true,
+		SelectedResourcesValidationMode: "Disabled",
 	}
 
 	if modifyOptions != nil {
@@ -73,6 +77,25 @@ func TestValidateControllerManagerConfiguration(t *testing.T) {
 			}),
 			want: field.ErrorList{field.Invalid(newPath.Child("WebhookClientConnectionType"), "invalid", `must be "service" or "url"`)},
 		},
+		"invalid WebhookCertMode": {
+			opt: newTestOptions(func(option *Options) {
+				option.WebhookCertMode = "invalid"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("WebhookCertMode"), "invalid", `must be "selfsigned" or "certmanager"`)},
+		},
+		"invalid WebhookCertValidity": {
+			opt: newTestOptions(func(option *Options) {
+				option.WebhookCertValidity.Duration = 0
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("WebhookCertValidity"), metav1.Duration{}, "Must be greater than 0")},
+		},
+		"WebhookCertValidity is ignored with cert-manager": {
+			opt: newTestOptions(func(option *Options) {
+				option.WebhookCertMode = "CertManager"
+				option.WebhookCertValidity.Duration = 0
+			}),
+			want: field.ErrorList{},
+		},
 		"WebhookServiceName is empty": {
 			opt: newTestOptions(func(option *Options) {
 				option.EnableWebhook = true
@@ -80,6 +103,210 @@ func TestValidateControllerManagerConfiguration(t *testing.T) {
 			}),
 			want: field.ErrorList{field.Invalid(newPath.Child("WebhookServiceName"), "", "Webhook service name is required when webhook is enabled")},
 		},
+		"invalid SelectedResourcesValidationMode": {
+			opt: newTestOptions(func(option *Options) {
+				option.SelectedResourcesValidationMode = "invalid"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("SelectedResourcesValidationMode"), "invalid", "Must be Disabled, Warn or Reject")},
+		},
+		"valid OverrideProtectedPaths": {
+			opt: newTestOptions(func(option *Options) {
+				option.OverrideProtectedPaths = "/spec/template/spec/containers/*/image;/spec/template/spec/securityContext;"
+			}),
+			want: field.ErrorList{},
+		},
+		"invalid OverrideProtectedPaths": {
+			opt: newTestOptions(func(option *Options) {
+				option.OverrideProtectedPaths = "/spec/template;spec/replicas"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("OverrideProtectedPaths"), "/spec/template;spec/replicas", "Each path must start with /")},
+		},
+		"invalid MaxMemberCertificateValidity": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableMemberCertificateApproval = true
+				option.MaxMemberCertificateValidity.Duration = time.Minute
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("MaxMemberCertificateValidity"), metav1.Duration{Duration: time.Minute}, "Must be at least 10m")},
+		},
+		"both WorkSigningKeyFile and WorkSigningAzureKeyVaultKeyURL are set": {
+			opt: newTestOptions(func(option *Options) {
+				option.WorkSigningKeyFile = "/etc/fleet/work-signing/key.pem"
+				option.WorkSigningAzureKeyVaultKeyURL = "https://vault.vault.azure.net/keys/fleet/v1"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("WorkSigningAzureKeyVaultKeyURL"), "https://vault.vault.azure.net/keys/fleet/v1", "Cannot be set together with WorkSigningKeyFile")},
+		},
+		"ClusterAPIHubServerURL is empty": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableClusterAPIRegistration = true
+			}),
+			want: field.ErrorList{field.Required(newPath.Child("ClusterAPIHubServerURL"), "Hub server URL is required when the Cluster API registration is enabled")},
+		},
+		"invalid ClusterAPIBootstrapConfigMap": {
+			opt: newTestOptions(func(option *Options) {
+				option.ClusterAPIBootstrapConfigMap = "member-agent-bootstrap"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("ClusterAPIBootstrapConfigMap"), "member-agent-bootstrap", "Must be in the format of namespace/name")},
+		},
+		"invalid CloudEventsSinkURL": {
+			opt: newTestOptions(func(option *Options) {
+				option.CloudEventsSinkURL = "broker.knative-eventing.svc"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("CloudEventsSinkURL"), "broker.knative-eventing.svc", "Must be an absolute HTTP or HTTPS URL")},
+		},
+		"invalid PlacementStatusExportNamespace": {
+			opt: newTestOptions(func(option *Options) {
+				option.PlacementStatusExportNamespace = "fleet_status"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementStatusExportNamespace"), "fleet_status",
+				"a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')")},
+		},
+		"invalid PlacementSLISampleInterval": {
+			opt: newTestOptions(func(option *Options) {
+				option.PlacementSLIWindows = []time.Duration{time.Hour}
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementSLISampleInterval"), metav1.Duration{}, "Must be positive when PlacementSLIWindows is set")},
+		},
+		"PlacementSLIWindows shorter than PlacementSLISampleInterval": {
+			opt: newTestOptions(func(option *Options) {
+				option.PlacementSLIWindows = []time.Duration{30 * time.Second, time.Hour}
+				option.PlacementSLISampleInterval = metav1.Duration{Duration: time.Minute}
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementSLIWindows"), "30s", "Must not be shorter than PlacementSLISampleInterval")},
+		},
+		"EnablePlacementSharding is set together with EnableV1Alpha1APIs": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnablePlacementSharding = true
+				option.PlacementShardLeaseDuration.Duration = 15 * time.Second
+				option.PlacementShardCount = 16
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("EnablePlacementSharding"), true, "Cannot be set together with EnableV1Alpha1APIs")},
+		},
+		"invalid PlacementShardLeaseDuration": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableV1Alpha1APIs = false
+				option.EnableV1Beta1APIs = true
+				option.EnablePlacementSharding = true
+				option.PlacementShardCount = 16
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementShardLeaseDuration"), metav1.Duration{}, "Must be at least 1s")},
+		},
+		"invalid PlacementShardCount": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableV1Alpha1APIs = false
+				option.EnableV1Beta1APIs = true
+				option.EnablePlacementSharding = true
+				option.PlacementShardLeaseDuration.Duration = 15 * time.Second
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementShardCount"), 0, "Must be at least 1")},
+		},
+		"disabled controller": {
+			opt: newTestOptions(func(option *Options) {
+				option.Controllers = []string{"-scheduler", "*"}
+			}),
+			want: field.ErrorList{},
+		},
+		"unknown controller": {
+			opt: newTestOptions(func(option *Options) {
+				option.Controllers = []string{"-foo", "*"}
+			}),
+			want: field.ErrorList{field.NotSupported(newPath.Child("Controllers"), "-foo", append([]string{"*"}, KnownControllers...))},
+		},
+		"no controller enabled": {
+			opt: newTestOptions(func(option *Options) {
+				option.Controllers = []string{""}
+			}),
+			want: field.ErrorList{
+				field.NotSupported(newPath.Child("Controllers"), "", append([]string{"*"}, KnownControllers...)),
+				field.Invalid(newPath.Child("Controllers"), []string{""}, "At least one controller must be enabled"),
+			},
+		},
+		"controller disabled with EnablePlacementSharding": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableV1Alpha1APIs = false
+				option.EnableV1Beta1APIs = true
+				option.EnablePlacementSharding = true
+				option.PlacementShardLeaseDuration.Duration = 15 * time.Second
+				option.PlacementShardCount = 16
+				option.Controllers = []string{"scheduler"}
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("Controllers"), []string{"scheduler"}, "Cannot disable any controller when EnablePlacementSharding is set")},
+		},
+		"invalid MetadataOnlyAPIs": {
+			opt: newTestOptions(func(options *Options) {
+				options.MetadataOnlyAPIs = "a/b/c/d?"
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("MetadataOnlyAPIs"), "a/b/c/d?", "Invalid API string")},
+		},
+		"negative BindingStatusBatchInterval": {
+			opt: newTestOptions(func(option *Options) {
+				option.BindingStatusBatchInterval.Duration = -time.Second
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("BindingStatusBatchInterval"), metav1.Duration{Duration: -time.Second}, "Must not be negative")},
+		},
+		"negative MemberWorkWriteQPS": {
+			opt: newTestOptions(func(option *Options) {
+				option.MemberWorkWriteQPS = -1
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("MemberWorkWriteQPS"), float64(-1), "Must not be negative")},
+		},
+		"zero MemberWorkWriteBurst with MemberWorkWriteQPS": {
+			opt: newTestOptions(func(option *Options) {
+				option.MemberWorkWriteQPS = 5
+				option.MemberWorkWriteBurst = 0
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("MemberWorkWriteBurst"), 0, "Must be positive when MemberWorkWriteQPS is set")},
+		},
+		"negative OverrideRenderCacheSize": {
+			opt: newTestOptions(func(option *Options) {
+				option.OverrideRenderCacheSize = -1
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("OverrideRenderCacheSize"), -1, "Must not be negative")},
+		},
+		"negative ResourceSnapshotMemoryBudgetMB": {
+			opt: newTestOptions(func(option *Options) {
+				option.ResourceSnapshotMemoryBudgetMB = -1
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("ResourceSnapshotMemoryBudgetMB"), -1, "Must not be negative")},
+		},
+		"negative PlacementStatusCompactionThreshold": {
+			opt: newTestOptions(func(option *Options) {
+				option.PlacementStatusCompactionThreshold = -1
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementStatusCompactionThreshold"), -1, "Must not be negative")},
+		},
+		"negative PlacementStatusStaleTimeout": {
+			opt: newTestOptions(func(option *Options) {
+				option.PlacementStatusStaleTimeout.Duration = -time.Minute
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementStatusStaleTimeout"), metav1.Duration{Duration: -time.Minute}, "Must not be negative")},
+		},
+		"non-positive PlacementResyncMinInterval with EnableAdaptivePlacementResync": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableAdaptivePlacementResync = true
+				option.PlacementResyncMaxInterval.Duration = time.Hour
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementResyncMinInterval"), metav1.Duration{}, "Must be positive when EnableAdaptivePlacementResync is set")},
+		},
+		"PlacementResyncMaxInterval less than PlacementResyncMinInterval": {
+			opt: newTestOptions(func(option *Options) {
+				option.EnableAdaptivePlacementResync = true
+				option.PlacementResyncMinInterval.Duration = time.Minute
+				option.PlacementResyncMaxInterval.Duration = time.Second
+			}),
+			want: field.ErrorList{field.Invalid(newPath.Child("PlacementResyncMaxInterval"), metav1.Duration{Duration: time.Second}, "Must not be less than PlacementResyncMinInterval")},
+		},
+		"PlacementResyncMinInterval is ignored when EnableAdaptivePlacementResync is not set": {
+			opt: newTestOptions(func(option *Options) {
+				option.PlacementResyncMinInterval.Duration = -time.Minute
+			}),
+			want: field.ErrorList{},
+		},
+		"MaxMemberCertificateValidity is ignored when the approval is disabled": {
+			opt: newTestOptions(func(option *Options) {
+				option.MaxMemberCertificateValidity.Duration = time.Minute
+			}),
+			want: field.ErrorList{},
+		},
 	}
 
 	for name, tc := range testCases {
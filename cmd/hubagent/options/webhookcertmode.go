@@ -0,0 +1,37 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package options
+
+import (
+	"errors"
+	"strings"
+)
+
+// WebhookCertMode is the way the serving certificate of the fleet webhook is provisioned.
+type WebhookCertMode string
+
+const (
+	// SelfSigned generates a self-signed serving certificate, injects its CA bundle into the webhook configurations
+	// and rotates it before it expires.
+	SelfSigned WebhookCertMode = "selfsigned"
+	// CertManager relies on cert-manager to issue the serving certificate and to inject the CA bundle.
+	CertManager WebhookCertMode = "certmanager"
+)
+
+var (
+	certModesMap = map[string]WebhookCertMode{
+		"selfsigned":  SelfSigned,
+		"certmanager": CertManager,
+	}
+)
+
+func parseWebhookCertModeString(str string) (WebhookCertMode, error) {
+	t, ok := certModesMap[strings.ToLower(str)]
+	if !ok {
+		return "", errors.New("must be \"selfsigned\" or \"certmanager\"")
+	}
+	return t, nil
+}
@@ -8,15 +8,23 @@ package workload
 import (
 	"context"
 	"math"
+	"net/http"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	workv1alpha1 "sigs.k8s.io/work-api/pkg/apis/v1alpha1"
 
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
@@ -24,14 +32,27 @@ import (
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/cmd/hubagent/options"
+	"go.goms.io/fleet/pkg/controllers/argocdhealth"
+	"go.goms.io/fleet/pkg/controllers/capiregistration"
 	"go.goms.io/fleet/pkg/controllers/clusterresourcebindingwatcher"
 	"go.goms.io/fleet/pkg/controllers/clusterresourceplacement"
 	"go.goms.io/fleet/pkg/controllers/clusterresourceplacementwatcher"
 	"go.goms.io/fleet/pkg/controllers/clusterschedulingpolicysnapshot"
 	"go.goms.io/fleet/pkg/controllers/memberclusterplacement"
+	"go.goms.io/fleet/pkg/controllers/memberevents"
 	"go.goms.io/fleet/pkg/controllers/overrider"
+	"go.goms.io/fleet/pkg/controllers/placementevents"
+	"go.goms.io/fleet/pkg/controllers/placementpromotion"
+	"go.goms.io/fleet/pkg/controllers/placementscaler"
+	"go.goms.io/fleet/pkg/controllers/placementsli"
+	"go.goms.io/fleet/pkg/controllers/placementsource"
 	"go.goms.io/fleet/pkg/controllers/resourcechange"
+	"go.goms.io/fleet/pkg/controllers/resourceobservation"
+	"go.goms.io/fleet/pkg/controllers/restoreadoption"
 	"go.goms.io/fleet/pkg/controllers/rollout"
+	"go.goms.io/fleet/pkg/controllers/schedulingexplain"
+	"go.goms.io/fleet/pkg/controllers/stagedupdaterun"
+	"go.goms.io/fleet/pkg/controllers/statusexport"
 	"go.goms.io/fleet/pkg/controllers/workgenerator"
 	"go.goms.io/fleet/pkg/resourcewatcher"
 	"go.goms.io/fleet/pkg/scheduler"
@@ -42,10 +63,14 @@ import (
 	schedulercrpwatcher "go.goms.io/fleet/pkg/scheduler/watchers/clusterresourceplacement"
 	schedulercspswatcher "go.goms.io/fleet/pkg/scheduler/watchers/clusterschedulingpolicysnapshot"
 	"go.goms.io/fleet/pkg/scheduler/watchers/membercluster"
+	"go.goms.io/fleet/pkg/sharding"
+	"go.goms.io/fleet/pkg/statusserver"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/index"
 	"go.goms.io/fleet/pkg/utils/informer"
 	"go.goms.io/fleet/pkg/utils/validator"
+	"go.goms.io/fleet/pkg/utils/worksigning"
 )
 
 const (
@@ -55,6 +80,7 @@ const (
 
 	resourceChangeControllerName = "resource-change-controller"
 	mcPlacementControllerName    = "memberCluster-placement-controller"
+	memberEventControllerName    = "member-event-controller"
 
 	schedulerQueueName = "scheduler-queue"
 )
@@ -108,6 +134,20 @@ func SetupControllers(ctx context.Context, wg *sync.WaitGroup, mgr ctrl.Manager,
 				return err
 			}
 		}
+		if opts.PlacementStatusCompactionThreshold > 0 {
+			gvk := placementv1beta1.GroupVersion.WithKind(placementv1beta1.PerClusterPlacementStatusKind)
+			if err = utils.CheckCRDInstalled(discoverClient, gvk); err != nil {
+				klog.ErrorS(err, "unable to find the required CRD", "GVK", gvk)
+				return err
+			}
+		}
+		if opts.EnableMetadataOverrides {
+			gvk := placementv1alpha1.GroupVersion.WithKind(placementv1alpha1.ClusterMetadataOverrideKind)
+			if err = utils.CheckCRDInstalled(discoverClient, gvk); err != nil {
+				klog.ErrorS(err, "unable to find the required CRD", "GVK", gvk)
+				return err
+			}
+		}
 	}
 
 	// AllowedPropagatingAPIs and SkippedPropagatingAPIs are mutually exclusive.
@@ -134,20 +174,82 @@ func SetupControllers(ctx context.Context, wg *sync.WaitGroup, mgr ctrl.Manager,
 	}
 
 	// the manager for all the dynamically created informers
-	dynamicInformerManager := informer.NewInformerManager(dynamicClient, opts.ResyncPeriod.Duration, ctx.Done())
+	var informerOpts []informer.Option
+	if opts.MetadataOnlyAPIs != "" {
+		metadataClient, err := metadata.NewForConfig(config)
+		if err != nil {
+			klog.ErrorS(err, "unable to create the metadata client")
+			return err
+		}
+		metadataOnlyConfig := utils.NewResourceConfig(true)
+		if err := metadataOnlyConfig.Parse(opts.MetadataOnlyAPIs); err != nil {
+			// The program will never go here because the parameters have been checked.
+			return err
+		}
+		informerOpts = append(informerOpts, informer.WithMetadataOnlyResources(metadataClient, func(gvk schema.GroupVersionKind) bool {
+			return !metadataOnlyConfig.IsResourceDisabled(gvk)
+		}))
+	}
+	// the resource change detector discovers the scopes of the resources when it watches them, so the processes
+	// which do not run it look them up with the REST mapper instead
+	crpControllerEnabled := opts.IsControllerEnabled(options.ClusterResourcePlacementController)
+	if !crpControllerEnabled {
+		informerOpts = append(informerOpts, informer.WithRESTMapperScopes(mgr.GetRESTMapper()))
+	}
+	dynamicInformerManager := informer.NewInformerManager(dynamicClient, opts.ResyncPeriod.Duration, ctx.Done(), informerOpts...)
 	validator.ResourceInformer = dynamicInformerManager // webhook needs this to check resource scope
 	validator.RestMapper = mgr.GetRESTMapper()          // webhook needs this to validate GVK of resource selector
 
+	// setup the paths which the overrides are not allowed to modify
+	for _, path := range strings.Split(opts.OverrideProtectedPaths, ";") {
+		if len(path) > 0 {
+			klog.InfoS("user specified a path protected from the overrides", "path", path)
+			validator.OverrideProtectedPaths = append(validator.OverrideProtectedPaths, path)
+		}
+	}
+
+	// set up the sharder which shards the placements across the replicas
+	var sharder *sharding.Sharder
+	if opts.EnablePlacementSharding {
+		identity, err := os.Hostname()
+		if err != nil {
+			klog.ErrorS(err, "unable to get the identity of the replica for sharding")
+			return err
+		}
+		leaseClient, err := coordinationv1client.NewForConfig(config)
+		if err != nil {
+			klog.ErrorS(err, "unable to create the lease client for sharding")
+			return err
+		}
+		klog.InfoS("Setting up the placement sharder", "identity", identity, "shardCount", opts.PlacementShardCount)
+		sharder = sharding.NewSharder(mgr.GetClient(), mgr.GetAPIReader(), leaseClient, utils.FleetSystemNamespace, identity, opts.PlacementShardCount, opts.PlacementShardLeaseDuration.Duration)
+		if err := mgr.Add(sharder); err != nil {
+			klog.ErrorS(err, "Unable to set up the placement sharder")
+			return err
+		}
+	}
+
 	// Set up  a custom controller to reconcile cluster resource placement
 	crpc := &clusterresourceplacement.Reconciler{
-		Client:            mgr.GetClient(),
-		Recorder:          mgr.GetEventRecorderFor(crpControllerName),
-		RestMapper:        mgr.GetRESTMapper(),
-		InformerManager:   dynamicInformerManager,
-		ResourceConfig:    resourceConfig,
-		SkippedNamespaces: skippedNamespaces,
-		Scheme:            mgr.GetScheme(),
-		UncachedReader:    mgr.GetAPIReader(),
+		Client:                          mgr.GetClient(),
+		Recorder:                        mgr.GetEventRecorderFor(crpControllerName),
+		RestMapper:                      mgr.GetRESTMapper(),
+		InformerManager:                 dynamicInformerManager,
+		ResourceConfig:                  resourceConfig,
+		SkippedNamespaces:               skippedNamespaces,
+		Scheme:                          mgr.GetScheme(),
+		UncachedReader:                  mgr.GetAPIReader(),
+		SelectedResourcesValidationMode: clusterresourceplacement.SelectedResourcesValidationMode(opts.SelectedResourcesValidationMode),
+		Sharder:                         sharder,
+		StatusCompactionThreshold:       opts.PlacementStatusCompactionThreshold,
+		ResourceSnapshotMemoryBudget:    int64(opts.ResourceSnapshotMemoryBudgetMB) << 20,
+		StatusStaleTimeout:              opts.PlacementStatusStaleTimeout.Duration,
+	}
+	if opts.EnableAdaptivePlacementResync {
+		crpc.AdaptiveResync = &clusterresourceplacement.AdaptiveResync{
+			MinInterval: opts.PlacementResyncMinInterval.Duration,
+			MaxInterval: opts.PlacementResyncMaxInterval.Duration,
+		}
 	}
 
 	rateLimiter := options.DefaultControllerRateLimiter(opts.RateLimiterOpts)
@@ -188,128 +290,352 @@ func SetupControllers(ctx context.Context, wg *sync.WaitGroup, mgr ctrl.Manager,
 	}
 
 	if opts.EnableV1Beta1APIs {
-		klog.Info("Setting up clusterResourcePlacement watcher")
-		if err := (&clusterresourceplacementwatcher.Reconciler{
-			PlacementController: clusterResourcePlacementControllerV1Beta1,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up the clusterResourcePlacement watcher")
-			return err
-		}
+		if crpControllerEnabled {
+			klog.Info("Setting up clusterResourcePlacement watcher")
+			if err := (&clusterresourceplacementwatcher.Reconciler{
+				PlacementController: clusterResourcePlacementControllerV1Beta1,
+				Sharder:             sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up the clusterResourcePlacement watcher")
+				return err
+			}
 
-		klog.Info("Setting up clusterResourceBinding watcher")
-		if err := (&clusterresourcebindingwatcher.Reconciler{
-			PlacementController: clusterResourcePlacementControllerV1Beta1,
-			Client:              mgr.GetClient(),
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up the clusterResourceBinding watcher")
-			return err
+			klog.Info("Setting up clusterResourceBinding watcher")
+			if err := (&clusterresourcebindingwatcher.Reconciler{
+				PlacementController: clusterResourcePlacementControllerV1Beta1,
+				Client:              mgr.GetClient(),
+				Sharder:             sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up the clusterResourceBinding watcher")
+				return err
+			}
+
+			klog.Info("Setting up clusterSchedulingPolicySnapshot watcher")
+			if err := (&clusterschedulingpolicysnapshot.Reconciler{
+				Client:              mgr.GetClient(),
+				PlacementController: clusterResourcePlacementControllerV1Beta1,
+				Sharder:             sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up the clusterSchedulingPolicySnapshot watcher")
+				return err
+			}
 		}
 
-		klog.Info("Setting up clusterSchedulingPolicySnapshot watcher")
-		if err := (&clusterschedulingpolicysnapshot.Reconciler{
-			Client:              mgr.GetClient(),
-			PlacementController: clusterResourcePlacementControllerV1Beta1,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up the clusterSchedulingPolicySnapshot watcher")
+		if err := addFieldIndexes(ctx, mgr.GetFieldIndexer(), opts); err != nil {
+			klog.ErrorS(err, "Unable to add the field indexes")
 			return err
 		}
 
-		// Set up  a new controller to do rollout resources according to CRP rollout strategy
-		klog.Info("Setting up rollout controller")
-		if err := (&rollout.Reconciler{
-			Client:                  mgr.GetClient(),
-			UncachedReader:          mgr.GetAPIReader(),
-			MaxConcurrentReconciles: int(math.Ceil(float64(opts.MaxFleetSizeSupported)/30) * math.Ceil(float64(opts.MaxConcurrentClusterPlacement)/10)),
-			InformerManager:         dynamicInformerManager,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up rollout controller")
-			return err
+		if opts.IsControllerEnabled(options.RolloutController) {
+			// Set up  a new controller to do rollout resources according to CRP rollout strategy
+			klog.Info("Setting up rollout controller")
+			if err := (&rollout.Reconciler{
+				Client:                  mgr.GetClient(),
+				UncachedReader:          mgr.GetAPIReader(),
+				MaxConcurrentReconciles: int(math.Ceil(float64(opts.MaxFleetSizeSupported)/30) * math.Ceil(float64(opts.MaxConcurrentClusterPlacement)/10)),
+				InformerManager:         dynamicInformerManager,
+				Sharder:                 sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up rollout controller")
+				return err
+			}
+			// the rollout controller holds back the placements with staged update runs until their stages start
+			klog.Info("Setting up staged update run controller")
+			if err := (&stagedupdaterun.Reconciler{
+				Client: mgr.GetClient(),
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up staged update run controller")
+				return err
+			}
 		}
 
-		// Set up the work generator
-		klog.Info("Setting up work generator")
-		if err := (&workgenerator.Reconciler{
-			Client:                  mgr.GetClient(),
-			MaxConcurrentReconciles: int(math.Ceil(float64(opts.MaxFleetSizeSupported)/10) * math.Ceil(float64(opts.MaxConcurrentClusterPlacement)/10)),
-			InformerManager:         dynamicInformerManager,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up work generator")
-			return err
+		if opts.IsControllerEnabled(options.WorkGeneratorController) {
+			// Set up the work generator
+			klog.Info("Setting up work generator")
+			signer, err := newWorkSigner(ctx, opts)
+			if err != nil {
+				klog.ErrorS(err, "Unable to set up the work signer")
+				return err
+			}
+			if err := (&workgenerator.Reconciler{
+				Client:                  mgr.GetClient(),
+				MaxConcurrentReconciles: int(math.Ceil(float64(opts.MaxFleetSizeSupported)/10) * math.Ceil(float64(opts.MaxConcurrentClusterPlacement)/10)),
+				InformerManager:         dynamicInformerManager,
+				Signer:                  signer,
+				SealAllSecrets:          opts.SealAllSecrets,
+				Sharder:                 sharder,
+				StatusBatchInterval:     opts.BindingStatusBatchInterval.Duration,
+				MemberWriteQPS:          opts.MemberWorkWriteQPS,
+				MemberWriteBurst:        opts.MemberWorkWriteBurst,
+				AdoptRestoredWorks:      opts.EnableRestoreMode,
+				OverrideRenderCacheSize: opts.OverrideRenderCacheSize,
+				EnableMetadataOverrides: opts.EnableMetadataOverrides,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up work generator")
+				return err
+			}
 		}
 
-		// Set up the scheduler
-		klog.Info("Setting up scheduler")
-		defaultProfile := profile.NewDefaultProfile()
-		defaultFramework := framework.NewFramework(defaultProfile, mgr)
-		defaultSchedulingQueue := queue.NewSimpleClusterResourcePlacementSchedulingQueue(
-			queue.WithName(schedulerQueueName),
-		)
-		// we use one scheduler for every 10 concurrent placement
-		defaultScheduler := scheduler.NewScheduler("DefaultScheduler", defaultFramework, defaultSchedulingQueue, mgr,
-			int(math.Ceil(float64(opts.MaxFleetSizeSupported)/50)*math.Ceil(float64(opts.MaxConcurrentClusterPlacement)/10)))
-		klog.Info("Starting the scheduler")
-		// Scheduler must run in a separate goroutine as Run() is a blocking call.
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-
-			// Run() blocks and is set to exit on context cancellation.
-			defaultScheduler.Run(ctx)
-
-			klog.InfoS("The scheduler has exited")
-		}()
-
-		// Set up the watchers for the controller
-		klog.Info("Setting up the clusterResourcePlacement watcher for scheduler")
-		if err := (&schedulercrpwatcher.Reconciler{
-			Client:             mgr.GetClient(),
-			SchedulerWorkQueue: defaultSchedulingQueue,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up clusterResourcePlacement watcher for scheduler")
-			return err
+		if opts.PlacementStatusBindAddress != "" {
+			// the placement status server runs on every replica and renders the works with its own reconcilers,
+			// which only read from the cache
+			klog.Info("Setting up the placement status server")
+			statusServer := statusserver.New(mgr.GetClient(), opts.PlacementStatusBindAddress).WithRenderer(
+				&rollout.Reconciler{
+					Client:          mgr.GetClient(),
+					InformerManager: dynamicInformerManager,
+				},
+				&workgenerator.Reconciler{
+					Client:                  mgr.GetClient(),
+					SealAllSecrets:          opts.SealAllSecrets,
+					EnableMetadataOverrides: opts.EnableMetadataOverrides,
+				})
+			if err := mgr.Add(statusServer); err != nil {
+				klog.ErrorS(err, "Unable to set up the placement status server")
+				return err
+			}
 		}
 
-		klog.Info("Setting up the clusterSchedulingPolicySnapshot watcher for scheduler")
-		if err := (&schedulercspswatcher.Reconciler{
-			Client:             mgr.GetClient(),
-			SchedulerWorkQueue: defaultSchedulingQueue,
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up clusterSchedulingPolicySnapshot watcher for scheduler")
-			return err
+		if crpControllerEnabled {
+			if opts.EnableArgoCDHealthBridge {
+				klog.Info("Setting up the Argo CD health bridge")
+				if err := (&argocdhealth.Reconciler{
+					Client: mgr.GetClient(),
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the Argo CD health bridge")
+					return err
+				}
+			}
+
+			if opts.EnablePlacementSources {
+				klog.Info("Setting up the placement source controller")
+				if err := (&placementsource.Reconciler{
+					Client:              mgr.GetClient(),
+					PlacementController: clusterResourcePlacementControllerV1Beta1,
+					GitFetcher:          &placementsource.GitFetcher{},
+					OCIFetcher:          &placementsource.OCIFetcher{},
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the placement source controller")
+					return err
+				}
+			}
+
+			if opts.CloudEventsSinkURL != "" {
+				klog.InfoS("Setting up the placement event emitter", "sink", opts.CloudEventsSinkURL)
+				if err := mgr.Add(placementevents.NewEmitter(mgr.GetCache(), &placementevents.HTTPSink{URL: opts.CloudEventsSinkURL}, rateLimiter)); err != nil {
+					klog.ErrorS(err, "Unable to set up the placement event emitter")
+					return err
+				}
+			}
+
+			if opts.PlacementStatusExportNamespace != "" {
+				klog.InfoS("Setting up the placement status export controller", "namespace", opts.PlacementStatusExportNamespace)
+				if err := (&statusexport.Reconciler{
+					Client:         mgr.GetClient(),
+					UncachedReader: mgr.GetAPIReader(),
+					Namespace:      opts.PlacementStatusExportNamespace,
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the placement status export controller")
+					return err
+				}
+			}
+
+			if len(opts.PlacementSLIWindows) > 0 {
+				klog.InfoS("Setting up the placement SLI sampler", "windows", opts.PlacementSLIWindows, "interval", opts.PlacementSLISampleInterval.Duration)
+				if err := mgr.Add(placementsli.NewSampler(mgr.GetClient(), opts.PlacementSLISampleInterval.Duration, opts.PlacementSLIWindows)); err != nil {
+					klog.ErrorS(err, "Unable to set up the placement SLI sampler")
+					return err
+				}
+			}
+
+			if opts.EnableRestoreMode {
+				klog.Info("Setting up the restore adoption controllers")
+				if err := (&restoreadoption.PlacementReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the restore adoption controller for the clusterResourcePlacements")
+					return err
+				}
+				if err := (&restoreadoption.BindingReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the restore adoption controller for the clusterResourceBindings")
+					return err
+				}
+				if err := (&restoreadoption.MemberClusterReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the restore adoption controller for the memberClusters")
+					return err
+				}
+			}
+
+			if opts.EnablePlacementScalers {
+				klog.Info("Setting up the placement scaler controller")
+				if err := (&placementscaler.Reconciler{
+					Client:  mgr.GetClient(),
+					Metrics: &placementscaler.PrometheusClient{HTTPClient: &http.Client{Timeout: 30 * time.Second}},
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the placement scaler controller")
+					return err
+				}
+			}
+
+			if opts.EnableResourceObservations {
+				klog.Info("Setting up the resource observation controller")
+				if err := (&resourceobservation.Reconciler{
+					Client: mgr.GetClient(),
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the resource observation controller")
+					return err
+				}
+			}
+
+			if opts.EnablePlacementPromotions {
+				klog.Info("Setting up the placement promotion controller")
+				if err := (&placementpromotion.Reconciler{
+					Client: mgr.GetClient(),
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the placement promotion controller")
+					return err
+				}
+			}
+
+			if opts.EnableMemberEventForwarding {
+				klog.Info("Setting up the member event controller")
+				if err := (&memberevents.Reconciler{
+					Client:   mgr.GetClient(),
+					Recorder: mgr.GetEventRecorderFor(memberEventControllerName),
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the member event controller")
+					return err
+				}
+			}
 		}
 
-		klog.Info("Setting up the memberCluster watcher for scheduler")
-		if err := (&membercluster.Reconciler{
-			Client:                    mgr.GetClient(),
-			SchedulerWorkQueue:        defaultSchedulingQueue,
-			ClusterEligibilityChecker: clustereligibilitychecker.New(),
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up memberCluster watcher for scheduler")
-			return err
+		if opts.EnableClusterAPIRegistration && opts.IsControllerEnabled(options.MemberClusterController) {
+			klog.Info("Setting up the Cluster API registration controller")
+			if err := utils.CheckCRDInstalled(discoverClient, capiregistration.ClusterGVK); err != nil {
+				klog.ErrorS(err, "unable to find the required CRD", "GVK", capiregistration.ClusterGVK)
+				return err
+			}
+			var bootstrapConfigMap *types.NamespacedName
+			if namespace, name, ok := strings.Cut(opts.ClusterAPIBootstrapConfigMap, "/"); ok {
+				bootstrapConfigMap = &types.NamespacedName{Namespace: namespace, Name: name}
+			}
+			if err := (&capiregistration.Reconciler{
+				Client:             mgr.GetClient(),
+				HubServerURL:       opts.ClusterAPIHubServerURL,
+				IdentityNamespace:  utils.FleetSystemNamespace,
+				BootstrapConfigMap: bootstrapConfigMap,
+				NewMemberClient:    capiregistration.NewMemberClient,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up the Cluster API registration controller")
+				return err
+			}
 		}
 
-		// Set up the controllers for overriding resources.
-		klog.Info("Setting up the clusterResourceOverride controller")
-		if err := (&overrider.ClusterResourceReconciler{
-			Reconciler: overrider.Reconciler{
-				Client: mgr.GetClient(),
-			},
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up clusterResourceOverride controller")
-			return err
+		if opts.IsControllerEnabled(options.SchedulerController) {
+			// Set up the scheduler
+			klog.Info("Setting up scheduler")
+			profileOpts := profile.Options{HubRegion: opts.HubRegion, HubZone: opts.HubZone}
+			defaultProfile := profile.NewDefaultProfile(profileOpts)
+			defaultFramework := framework.NewFramework(defaultProfile, mgr)
+			defaultSchedulingQueue := queue.NewSimpleClusterResourcePlacementSchedulingQueue(
+				queue.WithName(schedulerQueueName),
+			)
+			// we use one scheduler for every 10 concurrent placement
+			defaultScheduler := scheduler.NewScheduler("DefaultScheduler", defaultFramework, defaultSchedulingQueue, mgr,
+				int(math.Ceil(float64(opts.MaxFleetSizeSupported)/50)*math.Ceil(float64(opts.MaxConcurrentClusterPlacement)/10)), sharder)
+			var profileFrameworks map[string]framework.Framework
+			if opts.SchedulerProfilesConfigFile != "" {
+				var err error
+				profileFrameworks, err = newProfileFrameworks(opts.SchedulerProfilesConfigFile, profileOpts, mgr)
+				if err != nil {
+					klog.ErrorS(err, "Unable to set up the scheduler profiles", "configFile", opts.SchedulerProfilesConfigFile)
+					return err
+				}
+				profileFrameworks[defaultProfile.Name()] = defaultFramework
+				defaultScheduler.WithProfileFrameworks(profileFrameworks)
+			}
+			if opts.EnableSchedulingExplains {
+				// the explains run the scheduling cycles with the same frameworks as the scheduler
+				klog.Info("Setting up the scheduling explain controller")
+				if err := (&schedulingexplain.Reconciler{
+					Client:            mgr.GetClient(),
+					DefaultFramework:  defaultFramework,
+					ProfileFrameworks: profileFrameworks,
+				}).SetupWithManager(mgr); err != nil {
+					klog.ErrorS(err, "Unable to set up the scheduling explain controller")
+					return err
+				}
+			}
+			klog.Info("Starting the scheduler")
+			// Scheduler must run in a separate goroutine as Run() is a blocking call.
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				// Run() blocks and is set to exit on context cancellation.
+				defaultScheduler.Run(ctx)
+
+				klog.InfoS("The scheduler has exited")
+			}()
+
+			// Set up the watchers for the controller
+			klog.Info("Setting up the clusterResourcePlacement watcher for scheduler")
+			if err := (&schedulercrpwatcher.Reconciler{
+				Client:             mgr.GetClient(),
+				SchedulerWorkQueue: defaultSchedulingQueue,
+				Sharder:            sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up clusterResourcePlacement watcher for scheduler")
+				return err
+			}
+
+			klog.Info("Setting up the clusterSchedulingPolicySnapshot watcher for scheduler")
+			if err := (&schedulercspswatcher.Reconciler{
+				Client:             mgr.GetClient(),
+				SchedulerWorkQueue: defaultSchedulingQueue,
+				Sharder:            sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up clusterSchedulingPolicySnapshot watcher for scheduler")
+				return err
+			}
+
+			klog.Info("Setting up the memberCluster watcher for scheduler")
+			if err := (&membercluster.Reconciler{
+				Client:                    mgr.GetClient(),
+				SchedulerWorkQueue:        defaultSchedulingQueue,
+				ClusterEligibilityChecker: clustereligibilitychecker.New(),
+				Sharder:                   sharder,
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up memberCluster watcher for scheduler")
+				return err
+			}
 		}
 
-		klog.Info("Setting up the resourceOverride controller")
-		if err := (&overrider.ResourceReconciler{
-			Reconciler: overrider.Reconciler{
-				Client: mgr.GetClient(),
-			},
-		}).SetupWithManager(mgr); err != nil {
-			klog.ErrorS(err, "Unable to set up resourceOverride controller")
-			return err
+		if opts.IsControllerEnabled(options.OverriderController) {
+			// Set up the controllers for overriding resources.
+			klog.Info("Setting up the clusterResourceOverride controller")
+			if err := (&overrider.ClusterResourceReconciler{
+				Reconciler: overrider.Reconciler{
+					Client: mgr.GetClient(),
+				},
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up clusterResourceOverride controller")
+				return err
+			}
+
+			klog.Info("Setting up the resourceOverride controller")
+			if err := (&overrider.ResourceReconciler{
+				Reconciler: overrider.Reconciler{
+					Client: mgr.GetClient(),
+				},
+			}).SetupWithManager(mgr); err != nil {
+				klog.ErrorS(err, "Unable to set up resourceOverride controller")
+				return err
+			}
 		}
 	}
 
+	if !crpControllerEnabled {
+		return nil
+	}
 	// Set up a runner that starts all the custom controllers we created above
 	resourceChangeDetector := &resourcewatcher.ChangeDetector{
 		DiscoveryClient: discoverClient,
@@ -323,6 +649,7 @@ func SetupControllers(ctx context.Context, wg *sync.WaitGroup, mgr ctrl.Manager,
 		SkippedNamespaces:                          skippedNamespaces,
 		ConcurrentClusterPlacementWorker:           int(math.Ceil(float64(opts.MaxConcurrentClusterPlacement) / 10)),
 		ConcurrentResourceChangeWorker:             opts.ConcurrentResourceChangeSyncs,
+		Sharder:                                    sharder,
 	}
 
 	if err := mgr.Add(resourceChangeDetector); err != nil {
@@ -331,3 +658,67 @@ func SetupControllers(ctx context.Context, wg *sync.WaitGroup, mgr ctrl.Manager,
 	}
 	return nil
 }
+
+// addFieldIndexes adds the field indexes with which the enabled rollout controller and work generator look up their
+// objects in the cache; each index is added once as the controllers share the cache.
+func addFieldIndexes(ctx context.Context, indexer client.FieldIndexer, opts *options.Options) error {
+	// the placement status server renders the works as the rollout controller and the work generator do, so it needs
+	// their indexes even if both the controllers are disabled
+	renderEnabled := opts.PlacementStatusBindAddress != ""
+	rolloutEnabled := opts.IsControllerEnabled(options.RolloutController) || renderEnabled
+	workGeneratorEnabled := opts.IsControllerEnabled(options.WorkGeneratorController) || renderEnabled
+	if rolloutEnabled || workGeneratorEnabled {
+		if err := index.AddResourceSnapshotGroupIndex(ctx, indexer); err != nil {
+			return err
+		}
+	}
+	if rolloutEnabled {
+		if err := index.AddResourceSnapshotCRPIndex(ctx, indexer); err != nil {
+			return err
+		}
+	}
+	if workGeneratorEnabled {
+		if err := index.AddBindingCRPIndex(ctx, indexer); err != nil {
+			return err
+		}
+		if err := index.AddWorkBindingIndex(ctx, indexer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newWorkSigner returns the signer of the content of the works according to the options, or nil if signing is disabled.
+func newWorkSigner(ctx context.Context, opts *options.Options) (worksigning.Signer, error) {
+	switch {
+	case opts.WorkSigningKeyFile != "":
+		return worksigning.NewKeyFileSigner(opts.WorkSigningKeyFile)
+	case opts.WorkSigningAzureKeyVaultKeyURL != "":
+		credential, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, err
+		}
+		return worksigning.NewAzureKeyVaultSigner(ctx, opts.WorkSigningAzureKeyVaultKeyURL, credential)
+	default:
+		return nil, nil
+	}
+}
+
+// newProfileFrameworks returns the scheduling frameworks of the scheduler profiles in the config file, keyed by the
+// profile names.
+func newProfileFrameworks(configFile string, profileOpts profile.Options, mgr ctrl.Manager) (map[string]framework.Framework, error) {
+	config, err := profile.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := profile.NewProfiles(config, profile.NewRegistry(profileOpts))
+	if err != nil {
+		return nil, err
+	}
+	frameworks := make(map[string]framework.Framework, len(profiles)+1)
+	for _, p := range profiles {
+		klog.InfoS("Setting up the scheduler profile", "profile", p.Name())
+		frameworks[p.Name()] = framework.NewFramework(p, mgr)
+	}
+	return frameworks, nil
+}
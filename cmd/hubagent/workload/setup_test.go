@@ -0,0 +1,82 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workload
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"go.goms.io/fleet/cmd/hubagent/options"
+	"go.goms.io/fleet/pkg/utils/index"
+)
+
+// fieldRecorder records the names of the indexed fields and rejects a field which is indexed twice, as the cache does.
+type fieldRecorder struct {
+	t      *testing.T
+	fields []string
+}
+
+func (f *fieldRecorder) IndexField(_ context.Context, _ client.Object, field string, _ client.IndexerFunc) error {
+	for _, indexed := range f.fields {
+		if indexed == field {
+			f.t.Fatalf("IndexField(%s) is called twice", field)
+		}
+	}
+	f.fields = append(f.fields, field)
+	return nil
+}
+
+func TestAddFieldIndexes(t *testing.T) {
+	allIndexes := []string{index.BindingCRPField, index.ResourceSnapshotCRPField, index.ResourceSnapshotGroupField, index.WorkBindingField}
+	testCases := map[string]struct {
+		controllers                []string
+		placementStatusBindAddress string
+		want                       []string
+	}{
+		"all controllers": {
+			controllers: []string{"*"},
+			want:        allIndexes,
+		},
+		"only the rollout controller": {
+			controllers: []string{options.RolloutController},
+			want:        []string{index.ResourceSnapshotCRPField, index.ResourceSnapshotGroupField},
+		},
+		"only the work generator": {
+			controllers: []string{options.WorkGeneratorController},
+			want:        []string{index.BindingCRPField, index.ResourceSnapshotGroupField, index.WorkBindingField},
+		},
+		"neither the rollout controller nor the work generator": {
+			controllers: []string{"*", "-" + options.RolloutController, "-" + options.WorkGeneratorController},
+		},
+		"placement status server without the rollout controller and the work generator": {
+			controllers:                []string{"*", "-" + options.RolloutController, "-" + options.WorkGeneratorController},
+			placementStatusBindAddress: ":8090",
+			want:                       allIndexes,
+		},
+		"placement status server with all controllers": {
+			controllers:                []string{"*"},
+			placementStatusBindAddress: ":8090",
+			want:                       allIndexes,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			opts := &options.Options{Controllers: tc.controllers, PlacementStatusBindAddress: tc.placementStatusBindAddress}
+			indexer := &fieldRecorder{t: t}
+			if err := addFieldIndexes(context.Background(), indexer, opts); err != nil {
+				t.Fatalf("addFieldIndexes() = %v, want no error", err)
+			}
+			sort.Strings(indexer.fields)
+			if diff := cmp.Diff(tc.want, indexer.fields); diff != "" {
+				t.Errorf("addFieldIndexes() indexed fields mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -21,10 +21,12 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
@@ -41,6 +43,7 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+	"go.goms.io/fleet/pkg/certrotation"
 	imcv1alpha1 "go.goms.io/fleet/pkg/controllers/internalmembercluster/v1alpha1"
 	imcv1beta1 "go.goms.io/fleet/pkg/controllers/internalmembercluster/v1beta1"
 	"go.goms.io/fleet/pkg/controllers/work"
@@ -50,6 +53,8 @@ import (
 	"go.goms.io/fleet/pkg/propertyprovider/azure"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/httpclient"
+	"go.goms.io/fleet/pkg/utils/manifestsealing"
+	"go.goms.io/fleet/pkg/utils/worksigning"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -66,6 +71,7 @@ var (
 	hubMetricsAddr       = flag.String("hub-metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	probeAddr            = flag.String("health-probe-bind-address", ":8091", "The address the probe endpoint binds to.")
 	metricsAddr          = flag.String("metrics-bind-address", ":8090", "The address the metric endpoint binds to.")
+	pprofAddr            = flag.String("pprof-bind-address", "", "The address the pprof endpoints bind to. The pprof endpoints are disabled if it is empty.")
 	enableLeaderElection = flag.Bool("leader-elect", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	leaderElectionNamespace = flag.String("leader-election-namespace", "kube-system", "The namespace in which the leader election resource will be created.")
@@ -73,6 +79,25 @@ var (
 	enableV1Beta1APIs       = flag.Bool("enable-v1beta1-apis", false, "If set, the agents will watch for the v1beta1 APIs.")
 	propertyProvider        = flag.String("property-provider", "none", "The property provider to use for the agent.")
 	region                  = flag.String("region", "", "The region where the member cluster resides.")
+	enableCertRotation      = flag.Bool("enable-client-certificate-rotation", false,
+		"If set, the member agent renews its client certificate through the certificate signing requests on the hub cluster. It requires use-ca-auth.")
+	clientCertValidity = flag.Duration("client-certificate-validity", 24*time.Hour, "The validity of the client certificates requested by the member agent.")
+	auditLogPath       = flag.String("audit-log-path", "",
+		"If set, the member agent writes an audit record of every resource it creates, updates or deletes as a line of JSON to the file; use - for stdout.")
+	workVerificationKeyFiles = flag.String("work-verification-public-key-files", "",
+		"Comma separated PEM encoded public key files. If set, the member agent only applies the works signed by the hub agent with one of the keys.")
+	enableManifestDecryption = flag.Bool("enable-manifest-decryption", false,
+		"If set, the member agent publishes its manifest encryption key to the hub cluster and decrypts the secrets sealed by the hub agent.")
+	manifestDecryptionKeySecret = flag.String("manifest-decryption-key-secret", "fleet-system/fleet-manifest-decryption-key",
+		"The namespace/name of the secret in the member cluster which stores the manifest decryption key.")
+	relayPlacements = flag.Bool("relay-placements", false,
+		"If set, the member cluster is itself a fleet hub and the member agent relays the placed resources to all of its member clusters through cluster resource placements.")
+	enableFaultInjection = flag.Bool("enable-fault-injection", false,
+		"Developer only: if set, the member agent injects the faults requested by the fault-injection.kubernetes-fleet.io annotations of the works. Never enable it in production.")
+	forwardEvents = flag.Bool("forward-events", false,
+		"If set, the member agent forwards the warning events of the placed resources and of the objects they own, e.g. the failed scheduling or the crash loops of the pods of a placed deployment, to their works on the hub cluster.")
+	agentDeployment = flag.String("agent-deployment", "",
+		"The namespace/name of the deployment of the member agent in the member cluster. If set, the member agent upgrades itself to the version requested by the hub cluster by updating the image of the deployment.")
 )
 
 func init() {
@@ -109,6 +134,17 @@ func main() {
 		klog.ErrorS(errors.New("hub server api cannot be empty"), "Failed to read URL for the hub cluster")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
+	if *enableCertRotation {
+		if !*useCertificateAuth {
+			klog.ErrorS(errors.New("enable-client-certificate-rotation requires use-ca-auth"), "Invalid certificate rotation flags")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		if err := bootstrapIdentity(); err != nil {
+			klog.ErrorS(err, "Failed to bootstrap the client certificate")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
+
 	hubConfig, err := buildHubConfig(hubURL, *useCertificateAuth, *tlsClientInsecure)
 	if err != nil {
 		klog.ErrorS(err, "Failed to build Kubernetes client configuration for the hub cluster")
@@ -154,6 +190,7 @@ func main() {
 			Port: 9443,
 		}),
 		HealthProbeBindAddress:  *probeAddr,
+		PprofBindAddress:        *pprofAddr,
 		LeaderElection:          hubOpts.LeaderElection,
 		LeaderElectionNamespace: *leaderElectionNamespace,
 		LeaderElectionID:        "136224848560.member.fleet.azure.com",
@@ -253,9 +290,27 @@ func buildHubConfig(hubURL string, useCertificateAuth bool, tlsClientInsecure bo
 			return httpclient.NewCustomHeadersRoundTripper(http.Header(h), rt)
 		}
 	}
+
+	// The member agents in air-gapped or egress-restricted clusters can reach the hub's API server through a proxy
+	// specified by "HUB_PROXY_URL" and/or a private endpoint, e.g. a private link, specified by "HUB_PRIVATE_ENDPOINT".
+	if err := httpclient.ApplyConnectivity(hubConfig, os.Getenv("HUB_PROXY_URL"), os.Getenv("HUB_PRIVATE_ENDPOINT")); err != nil {
+		klog.ErrorS(err, "Failed to configure the connectivity to the hub cluster")
+		return nil, err
+	}
 	return hubConfig, nil
 }
 
+// bootstrapIdentity copies the bootstrap client certificate to the identity paths managed by the rotator if the
+// "BOOTSTRAP_IDENTITY_CERT" and "BOOTSTRAP_IDENTITY_KEY" are set.
+func bootstrapIdentity() error {
+	bootstrapCertFile := os.Getenv("BOOTSTRAP_IDENTITY_CERT")
+	bootstrapKeyFile := os.Getenv("BOOTSTRAP_IDENTITY_KEY")
+	if bootstrapCertFile == "" || bootstrapKeyFile == "" {
+		return nil
+	}
+	return certrotation.BootstrapIdentity(os.Getenv("IDENTITY_CERT"), os.Getenv("IDENTITY_KEY"), bootstrapCertFile, bootstrapKeyFile)
+}
+
 // Start the member controllers with the supplied config
 func Start(ctx context.Context, hubCfg, memberConfig *rest.Config, hubOpts, memberOpts ctrl.Options) error {
 	hubMgr, err := ctrl.NewManager(hubCfg, hubOpts)
@@ -286,6 +341,20 @@ func Start(ctx context.Context, hubCfg, memberConfig *rest.Config, hubOpts, memb
 		return err
 	}
 
+	if *enableCertRotation {
+		hubClientSet, err := kubernetes.NewForConfig(hubCfg)
+		if err != nil {
+			klog.ErrorS(err, "Failed to create hub client set")
+			return err
+		}
+		mcName := os.Getenv("MEMBER_CLUSTER_NAME")
+		rotator := certrotation.NewRotator(hubClientSet, mcName, hubCfg.TLSClientConfig.CertFile, hubCfg.TLSClientConfig.KeyFile, *clientCertValidity)
+		if err := hubMgr.Add(rotator); err != nil {
+			klog.ErrorS(err, "Failed to set up the client certificate rotator")
+			return err
+		}
+	}
+
 	spokeDynamicClient, err := dynamic.NewForConfig(memberConfig)
 	if err != nil {
 		klog.ErrorS(err, "Failed to create spoke dynamic client")
@@ -353,11 +422,72 @@ func Start(ctx context.Context, hubCfg, memberConfig *rest.Config, hubOpts, memb
 			spokeDynamicClient,
 			memberMgr.GetClient(),
 			restMapper, hubMgr.GetEventRecorderFor("work_controller"), 5, targetNS)
+		if *auditLogPath != "" {
+			auditLog := os.Stdout
+			if *auditLogPath != "-" {
+				auditLog, err = os.OpenFile(*auditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+				if err != nil {
+					klog.ErrorS(err, "Failed to open the audit log file", "path", *auditLogPath)
+					return err
+				}
+			}
+			workController.WithAuditSink(work.NewJSONAuditSink(auditLog))
+		}
+		if *workVerificationKeyFiles != "" {
+			verifier, err := worksigning.NewVerifier(strings.Split(*workVerificationKeyFiles, ","))
+			if err != nil {
+				klog.ErrorS(err, "Failed to load the work verification public keys")
+				return err
+			}
+			workController.WithSignatureVerifier(verifier)
+		}
+		var manifestEncryptionPublicKey string
+		if *enableManifestDecryption {
+			namespace, name, ok := strings.Cut(*manifestDecryptionKeySecret, "/")
+			if !ok {
+				err := fmt.Errorf("invalid manifest decryption key secret %q, want namespace/name", *manifestDecryptionKeySecret)
+				klog.ErrorS(err, "Failed to load the manifest decryption key")
+				return err
+			}
+			memberClientSet, err := kubernetes.NewForConfig(memberMgr.GetConfig())
+			if err != nil {
+				klog.ErrorS(err, "Failed to create the member cluster client set")
+				return err
+			}
+			decryptionKey, err := manifestsealing.LoadOrCreatePrivateKey(ctx, memberClientSet, namespace, name)
+			if err != nil {
+				klog.ErrorS(err, "Failed to load the manifest decryption key")
+				return err
+			}
+			workController.WithManifestDecryptionKey(decryptionKey)
+			manifestEncryptionPublicKey = manifestsealing.EncodePublicKey(decryptionKey.PublicKey())
+		}
+		if *relayPlacements {
+			// the member cluster must be a fleet hub to relay the placements
+			gvk := placementv1beta1.GroupVersion.WithKind(placementv1beta1.ClusterResourcePlacementKind)
+			if err = utils.CheckCRDInstalled(discoverClient, gvk); err != nil {
+				klog.ErrorS(err, "unable to find the required CRD to relay the placements", "GVK", gvk)
+				return err
+			}
+			workController.WithPlacementRelay()
+		}
+		if *enableFaultInjection {
+			klog.Warning("The fault injection is enabled; the member agent must not be used in production")
+			workController.WithFaultInjection()
+		}
 
 		if err = workController.SetupWithManager(hubMgr); err != nil {
 			klog.ErrorS(err, "Failed to create v1beta1 controller", "controller", "work")
 			return err
 		}
+		if *forwardEvents {
+			klog.Info("Setting up the event forwarder")
+			if err = work.NewEventForwarder(memberMgr.GetClient(), spokeDynamicClient, restMapper,
+				hubMgr.GetClient(), hubMgr.GetEventRecorderFor("work_controller"), targetNS).SetupWithManager(memberMgr); err != nil {
+				klog.ErrorS(err, "Failed to create v1beta1 controller", "controller", "event-forwarder")
+				return err
+			}
+		}
 
 		klog.Info("Setting up the internalMemberCluster v1beta1 controller")
 		// Set up a provider provider (if applicable).
@@ -386,6 +516,16 @@ func Start(ctx context.Context, hubCfg, memberConfig *rest.Config, hubOpts, memb
 			klog.ErrorS(err, "Failed to create InternalMemberCluster v1beta1 reconciler")
 			return fmt.Errorf("failed to create InternalMemberCluster v1beta1 reconciler: %w", err)
 		}
+		imcReconciler.WithManifestEncryptionPublicKey(manifestEncryptionPublicKey)
+		if *agentDeployment != "" {
+			namespace, name, ok := strings.Cut(*agentDeployment, "/")
+			if !ok {
+				err := fmt.Errorf("invalid agent deployment %q, want namespace/name", *agentDeployment)
+				klog.ErrorS(err, "Failed to set up the member agent upgrades")
+				return err
+			}
+			imcReconciler.WithAgentDeployment(types.NamespacedName{Namespace: namespace, Name: name})
+		}
 		if err := imcReconciler.SetupWithManager(hubMgr); err != nil {
 			klog.ErrorS(err, "Failed to set up InternalMemberCluster v1beta1 controller with the controller manager")
 			return fmt.Errorf("failed to set up InternalMemberCluster v1beta1 controller with the controller manager: %w", err)
@@ -142,4 +142,21 @@ func Test_buildHubConfig(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, config.WrapTransport)
 	})
+	t.Run("use proxy and private endpoint - success", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", "./testdata/token")
+		t.Setenv("HUB_PROXY_URL", "socks5://proxy.domain.com:1080")
+		t.Setenv("HUB_PRIVATE_ENDPOINT", "https://hub.privatelink.domain.com")
+		config, err := buildHubConfig("https://hub.domain.com", false, false)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://hub.privatelink.domain.com", config.Host)
+		assert.Equal(t, "hub.domain.com", config.TLSClientConfig.ServerName)
+		assert.NotNil(t, config.Proxy)
+	})
+	t.Run("use invalid proxy - fail", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", "./testdata/token")
+		t.Setenv("HUB_PROXY_URL", "ftp://proxy.domain.com")
+		config, err := buildHubConfig("https://hub.domain.com", false, false)
+		assert.Nil(t, config)
+		assert.NotNil(t, err)
+	})
 }
@@ -32,7 +32,9 @@ require (
 	k8s.io/metrics v0.25.2
 	k8s.io/utils v0.0.0-20240502163921-fe8a2dddb1d0
 	sigs.k8s.io/controller-runtime v0.18.4
+	sigs.k8s.io/structured-merge-diff/v4 v4.4.1
 	sigs.k8s.io/work-api v0.0.0-20220407021756-586d707fdb2c
+	sigs.k8s.io/yaml v1.4.0
 )
 
 require (
@@ -95,8 +97,6 @@ require (
 	k8s.io/kube-openapi v0.0.0-20240521193020-835d969ad83a // indirect
 	knative.dev/pkg v0.0.0-20231010144348-ca8c009405dd // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
-	sigs.k8s.io/structured-merge-diff/v4 v4.4.1 // indirect
-	sigs.k8s.io/yaml v1.4.0 // indirect
 )
 
 replace (
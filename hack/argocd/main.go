@@ -0,0 +1,44 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Command argocd generates the argocd-cm configMap patch which installs the health checks of the fleet placement
+// APIs into Argo CD.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"go.goms.io/fleet/pkg/utils/argocd"
+)
+
+var namespace = flag.String("namespace", "argocd", "The namespace in which Argo CD is installed.")
+
+func main() {
+	flag.Parse()
+	customizations, err := argocd.ResourceCustomizations()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate the health checks: %v\n", err)
+		os.Exit(1)
+	}
+	patch, err := yaml.Marshal(&corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-cm",
+			Namespace: *namespace,
+		},
+		Data: customizations,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode the argocd-cm patch: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(string(patch))
+}
@@ -0,0 +1,259 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package aws
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"go.goms.io/fleet/pkg/interfaces"
+)
+
+const (
+	// the environment variables injected by the EKS pod identity webhook for IAM roles for service accounts (IRSA).
+	roleARNEnv              = "AWS_ROLE_ARN"
+	webIdentityTokenFileEnv = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	regionEnv               = "AWS_REGION"
+	defaultRegionEnv        = "AWS_DEFAULT_REGION"
+
+	stsAPIVersion = "2011-06-15"
+	// clusterIDHeader is the header that binds the presigned request to an EKS cluster, see aws-iam-authenticator.
+	clusterIDHeader = "x-k8s-aws-id"
+	tokenPrefix     = "k8s-aws-v1."
+	// presignedURLExpiration is how long the presigned GetCallerIdentity request is valid. The EKS api server
+	// accepts the token for 15 minutes after it is signed regardless of this value.
+	presignedURLExpiration = 60
+	// tokenLifetime is a bit shorter than the 15 minutes the EKS api server accepts the token for.
+	tokenLifetime = 14 * time.Minute
+	// emptyPayloadHash is the hex encoded sha256 hash of an empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// IRSAAuthTokenProvider fetches an EKS token with the IAM role for service accounts (IRSA). It exchanges the projected
+// service account token for temporary credentials of the IAM role, and uses them to presign a STS GetCallerIdentity
+// request which the EKS api server accepts as a bearer token.
+type IRSAAuthTokenProvider struct {
+	ClusterName   string
+	RoleARN       string
+	Region        string
+	TokenFilePath string
+
+	// stsEndpoint overrides the regional STS endpoint in tests.
+	stsEndpoint string
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// New creates an IRSA token provider for the EKS cluster. The role ARN, region and token file are read from the
+// environment variables injected by the EKS pod identity webhook if they are not set.
+func New(clusterName, roleARN, region string) interfaces.AuthTokenProvider {
+	if roleARN == "" {
+		roleARN = os.Getenv(roleARNEnv)
+	}
+	if region == "" {
+		region = os.Getenv(regionEnv)
+	}
+	if region == "" {
+		region = os.Getenv(defaultRegionEnv)
+	}
+	return &IRSAAuthTokenProvider{
+		ClusterName:   clusterName,
+		RoleARN:       roleARN,
+		Region:        region,
+		TokenFilePath: os.Getenv(webIdentityTokenFileEnv),
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		now:           time.Now,
+	}
+}
+
+// credentials are the temporary credentials returned by STS.
+type credentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+type assumeRoleWithWebIdentityResponse struct {
+	Credentials credentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+}
+
+// FetchToken gets a new token to make request to the associated fleet' hub cluster.
+func (a *IRSAAuthTokenProvider) FetchToken(ctx context.Context) (interfaces.AuthToken, error) {
+	token := interfaces.AuthToken{}
+	if a.ClusterName == "" || a.RoleARN == "" || a.Region == "" || a.TokenFilePath == "" {
+		return token, fmt.Errorf("cluster name, role ARN, region and web identity token file are required, got cluster name %q, role ARN %q, region %q, token file %q",
+			a.ClusterName, a.RoleARN, a.Region, a.TokenFilePath)
+	}
+
+	klog.V(2).InfoS("FetchToken with IRSA", "roleARN", a.RoleARN, "region", a.Region)
+	var creds credentials
+	err := retry.OnError(retry.DefaultBackoff,
+		func(err error) bool {
+			return ctx.Err() == nil
+		}, func() error {
+			var err error
+			creds, err = a.assumeRoleWithWebIdentity(ctx)
+			if err != nil {
+				klog.ErrorS(err, "Failed to assume role with web identity", "roleARN", a.RoleARN)
+			}
+			return err
+		})
+	if err != nil {
+		return token, fmt.Errorf("failed to get the temporary credentials: %w", err)
+	}
+
+	signedAt := a.now().UTC()
+	presignedURL, err := a.presignGetCallerIdentity(creds, signedAt)
+	if err != nil {
+		return token, err
+	}
+	token.Token = tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presignedURL))
+	token.ExpiresOn = signedAt.Add(tokenLifetime)
+	// the token cannot outlive the credentials that sign it
+	if !creds.Expiration.IsZero() && creds.Expiration.Before(token.ExpiresOn) {
+		token.ExpiresOn = creds.Expiration
+	}
+	return token, nil
+}
+
+func (a *IRSAAuthTokenProvider) endpoint() string {
+	if a.stsEndpoint != "" {
+		return a.stsEndpoint
+	}
+	return fmt.Sprintf("https://sts.%s.amazonaws.com", a.Region)
+}
+
+// assumeRoleWithWebIdentity exchanges the projected service account token for the temporary credentials of the role.
+// The request does not need to be signed.
+func (a *IRSAAuthTokenProvider) assumeRoleWithWebIdentity(ctx context.Context) (credentials, error) {
+	webIdentityToken, err := os.ReadFile(a.TokenFilePath)
+	if err != nil {
+		return credentials{}, fmt.Errorf("cannot read the web identity token file: %w", err)
+	}
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {stsAPIVersion},
+		"RoleArn":          {a.RoleARN},
+		"RoleSessionName":  {fmt.Sprintf("fleet-member-agent-%d", a.now().Unix())},
+		"WebIdentityToken": {strings.TrimSpace(string(webIdentityToken))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint()+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return credentials{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return credentials{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return credentials{}, fmt.Errorf("sts returned status %d: %s", resp.StatusCode, string(body))
+	}
+	var result assumeRoleWithWebIdentityResponse
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return credentials{}, fmt.Errorf("cannot parse the sts response: %w", err)
+	}
+	if result.Credentials.AccessKeyID == "" || result.Credentials.SecretAccessKey == "" {
+		return credentials{}, errors.New("sts returned empty credentials")
+	}
+	return result.Credentials, nil
+}
+
+// presignGetCallerIdentity presigns a STS GetCallerIdentity request with the AWS signature version 4, binding it to the
+// cluster with the x-k8s-aws-id header, the same way as aws-iam-authenticator does.
+func (a *IRSAAuthTokenProvider) presignGetCallerIdentity(creds credentials, signedAt time.Time) (string, error) {
+	endpoint, err := url.Parse(a.endpoint())
+	if err != nil {
+		return "", fmt.Errorf("invalid sts endpoint: %w", err)
+	}
+	amzDate := signedAt.Format("20060102T150405Z")
+	date := signedAt.Format("20060102")
+	credentialScope := fmt.Sprintf("%s/%s/sts/aws4_request", date, a.Region)
+	signedHeaders := "host;" + clusterIDHeader
+
+	query := map[string]string{
+		"Action":              "GetCallerIdentity",
+		"Version":             stsAPIVersion,
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    creds.AccessKeyID + "/" + credentialScope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       fmt.Sprint(presignedURLExpiration),
+		"X-Amz-SignedHeaders": signedHeaders,
+	}
+	if creds.SessionToken != "" {
+		query["X-Amz-Security-Token"] = creds.SessionToken
+	}
+	canonicalQuery := canonicalQueryString(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		"/",
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n" + clusterIDHeader + ":" + a.ClusterName + "\n",
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, a.Region)
+	signingKey = hmacSHA256(signingKey, "sts")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return fmt.Sprintf("%s://%s/?%s&X-Amz-Signature=%s", endpoint.Scheme, endpoint.Host, canonicalQuery, signature), nil
+}
+
+// canonicalQueryString encodes the query parameters sorted by name as required by the AWS signature version 4.
+func canonicalQueryString(query map[string]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, awsURIEncode(k)+"="+awsURIEncode(query[k]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode encodes every byte except the unreserved characters, and encodes the space as %20 instead of +.
+func awsURIEncode(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
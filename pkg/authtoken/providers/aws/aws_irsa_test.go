@@ -0,0 +1,103 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stsResponse = `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>AKIDEXAMPLE</AccessKeyId>
+      <SecretAccessKey>wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY</SecretAccessKey>
+      <SessionToken>session token</SessionToken>
+      <Expiration>2024-01-01T01:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`
+
+func TestFetchToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.Nil(t, os.WriteFile(tokenFile, []byte("web-identity-token\n"), 0600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/fleet", r.Form.Get("RoleArn"))
+		assert.Equal(t, "web-identity-token", r.Form.Get("WebIdentityToken"))
+		_, _ = w.Write([]byte(stsResponse))
+	}))
+	defer server.Close()
+
+	signedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := &IRSAAuthTokenProvider{
+		ClusterName:   "hub",
+		RoleARN:       "arn:aws:iam::123456789012:role/fleet",
+		Region:        "us-west-2",
+		TokenFilePath: tokenFile,
+		stsEndpoint:   server.URL,
+		httpClient:    server.Client(),
+		now:           func() time.Time { return signedAt },
+	}
+	token, err := provider.FetchToken(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, signedAt.Add(tokenLifetime), token.ExpiresOn)
+	assert.True(t, strings.HasPrefix(token.Token, tokenPrefix))
+
+	presignedURL, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token.Token, tokenPrefix))
+	assert.Nil(t, err)
+	u, err := url.Parse(string(presignedURL))
+	assert.Nil(t, err)
+	query := u.Query()
+	assert.Equal(t, "GetCallerIdentity", query.Get("Action"))
+	assert.Equal(t, "AKIDEXAMPLE/20240101/us-west-2/sts/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20240101T000000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "host;x-k8s-aws-id", query.Get("X-Amz-SignedHeaders"))
+	assert.Equal(t, "session token", query.Get("X-Amz-Security-Token"))
+	assert.Len(t, query.Get("X-Amz-Signature"), 64)
+	// the space in the session token must be encoded as %20 to match the signature
+	assert.Contains(t, u.RawQuery, "X-Amz-Security-Token=session%20token")
+}
+
+func TestFetchTokenMissingConfiguration(t *testing.T) {
+	provider := &IRSAAuthTokenProvider{ClusterName: "hub", Region: "us-west-2"}
+	_, err := provider.FetchToken(context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestFetchTokenCredentialsExpireFirst(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.Nil(t, os.WriteFile(tokenFile, []byte("web-identity-token"), 0600))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(stsResponse))
+	}))
+	defer server.Close()
+
+	signedAt := time.Date(2024, 1, 1, 0, 55, 0, 0, time.UTC)
+	provider := &IRSAAuthTokenProvider{
+		ClusterName:   "hub",
+		RoleARN:       "arn:aws:iam::123456789012:role/fleet",
+		Region:        "us-west-2",
+		TokenFilePath: tokenFile,
+		stsEndpoint:   server.URL,
+		httpClient:    server.Client(),
+		now:           func() time.Time { return signedAt },
+	}
+	token, err := provider.FetchToken(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), token.ExpiresOn.UTC())
+}
@@ -46,17 +46,24 @@ func (a *AuthTokenProvider) FetchToken(ctx context.Context) (interfaces.AuthToke
 	if err != nil {
 		return token, fmt.Errorf("failed to create managed identity cred: %w", err)
 	}
+	return fetchToken(ctx, credential, a.Scope)
+}
+
+// fetchToken gets a token of the scope with the credential and retries on errors until the context is done.
+func fetchToken(ctx context.Context, credential azcore.TokenCredential, scope string) (interfaces.AuthToken, error) {
+	token := interfaces.AuthToken{}
 	var azToken azcore.AccessToken
+	var err error
 	err = retry.OnError(retry.DefaultBackoff,
 		func(err error) bool {
 			return ctx.Err() == nil
 		}, func() error {
 			klog.V(2).InfoS("GetToken start", "credential", credential)
 			azToken, err = credential.GetToken(ctx, policy.TokenRequestOptions{
-				Scopes: []string{a.Scope},
+				Scopes: []string{scope},
 			})
 			if err != nil {
-				klog.ErrorS(err, "Failed to GetToken", "scope", a.Scope)
+				klog.ErrorS(err, "Failed to GetToken", "scope", scope)
 			}
 			return err
 		})
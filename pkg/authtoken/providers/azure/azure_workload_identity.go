@@ -0,0 +1,54 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"k8s.io/klog/v2"
+
+	"go.goms.io/fleet/pkg/interfaces"
+)
+
+// WorkloadIdentityAuthTokenProvider fetches the token with the Azure workload identity, which exchanges the federated
+// service account token projected into the pod for an AAD token, so no long-lived secret is needed.
+type WorkloadIdentityAuthTokenProvider struct {
+	ClientID string
+	// TenantID and TokenFilePath are read from the AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE environment
+	// variables injected by the Azure workload identity webhook if they are not set.
+	TenantID      string
+	TokenFilePath string
+	Scope         string
+}
+
+func NewWorkloadIdentity(clientID, tenantID, tokenFilePath, scope string) interfaces.AuthTokenProvider {
+	if scope == "" {
+		scope = aksScope
+	}
+	return &WorkloadIdentityAuthTokenProvider{
+		ClientID:      clientID,
+		TenantID:      tenantID,
+		TokenFilePath: tokenFilePath,
+		Scope:         scope,
+	}
+}
+
+// FetchToken gets a new token to make request to the associated fleet' hub cluster.
+func (a *WorkloadIdentityAuthTokenProvider) FetchToken(ctx context.Context) (interfaces.AuthToken, error) {
+	opts := &azidentity.WorkloadIdentityCredentialOptions{
+		ClientID:      a.ClientID,
+		TenantID:      a.TenantID,
+		TokenFilePath: a.TokenFilePath,
+	}
+
+	klog.V(2).InfoS("FetchToken with workload identity", "client ID", a.ClientID)
+	credential, err := azidentity.NewWorkloadIdentityCredential(opts)
+	if err != nil {
+		return interfaces.AuthToken{}, fmt.Errorf("failed to create workload identity cred: %w", err)
+	}
+	return fetchToken(ctx, credential, a.Scope)
+}
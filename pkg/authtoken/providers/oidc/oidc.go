@@ -0,0 +1,116 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"go.goms.io/fleet/pkg/interfaces"
+)
+
+// AuthTokenProvider reads an OIDC token, e.g. a projected service account token whose issuer is trusted by the hub
+// cluster api server, from a file which is refreshed by the kubelet or another agent.
+type AuthTokenProvider struct {
+	TokenFilePath string
+	// Audience is the audience that the token must be issued for, the check is skipped if it is empty.
+	Audience string
+}
+
+func New(tokenFilePath, audience string) interfaces.AuthTokenProvider {
+	return &AuthTokenProvider{
+		TokenFilePath: tokenFilePath,
+		Audience:      audience,
+	}
+}
+
+// claims are the JWT claims that we care about. The aud claim can be either a string or a list of strings.
+type claims struct {
+	Expiry   int64           `json:"exp"`
+	Audience json.RawMessage `json:"aud"`
+}
+
+// FetchToken gets a new token to make request to the associated fleet' hub cluster.
+func (o *AuthTokenProvider) FetchToken(_ context.Context) (interfaces.AuthToken, error) {
+	token := interfaces.AuthToken{}
+	klog.V(2).InfoS("fetching OIDC token from file", "path", o.TokenFilePath)
+	content, err := os.ReadFile(o.TokenFilePath)
+	if err != nil {
+		return token, fmt.Errorf("cannot read the token file: %w", err)
+	}
+	rawToken := strings.TrimSpace(string(content))
+	if len(rawToken) == 0 {
+		return token, fmt.Errorf("the token file %s is empty", o.TokenFilePath)
+	}
+
+	// The token is verified by the hub cluster api server, we only parse the claims to know when to refresh it.
+	c, err := parseClaims(rawToken)
+	if err != nil {
+		return token, err
+	}
+	if o.Audience != "" {
+		audiences, err := c.audiences()
+		if err != nil {
+			return token, err
+		}
+		if !contains(audiences, o.Audience) {
+			return token, fmt.Errorf("the token is issued for audiences %v instead of %s", audiences, o.Audience)
+		}
+	}
+	if c.Expiry == 0 {
+		return token, errors.New("the token does not have the exp claim")
+	}
+	token.Token = rawToken
+	token.ExpiresOn = time.Unix(c.Expiry, 0)
+	return token, nil
+}
+
+func parseClaims(rawToken string) (*claims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("the token is not a JWT, it has %d parts instead of 3", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode the token payload: %w", err)
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, fmt.Errorf("cannot parse the token claims: %w", err)
+	}
+	return &c, nil
+}
+
+func (c *claims) audiences() ([]string, error) {
+	if len(c.Audience) == 0 {
+		return nil, nil
+	}
+	var single string
+	if err := json.Unmarshal(c.Audience, &single); err == nil {
+		return []string{single}, nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(c.Audience, &multiple); err != nil {
+		return nil, fmt.Errorf("cannot parse the aud claim: %w", err)
+	}
+	return multiple, nil
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func jwtWithClaims(claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+	return header + "." + payload + ".signature"
+}
+
+func TestFetchToken(t *testing.T) {
+	tests := map[string]struct {
+		token         string
+		audience      string
+		wantErr       bool
+		wantExpiresOn time.Time
+	}{
+		"valid token": {
+			token:         jwtWithClaims(`{"exp":1704067200,"aud":"fleet"}`),
+			wantExpiresOn: time.Unix(1704067200, 0),
+		},
+		"valid token with one of the audiences": {
+			token:         jwtWithClaims(`{"exp":1704067200,"aud":["api","fleet"]}`),
+			audience:      "fleet",
+			wantExpiresOn: time.Unix(1704067200, 0),
+		},
+		"token issued for another audience": {
+			token:    jwtWithClaims(`{"exp":1704067200,"aud":"api"}`),
+			audience: "fleet",
+			wantErr:  true,
+		},
+		"token without exp": {
+			token:   jwtWithClaims(`{"aud":"fleet"}`),
+			wantErr: true,
+		},
+		"not a JWT": {
+			token:   "opaque-token",
+			wantErr: true,
+		},
+		"empty file": {
+			token:   "",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tokenFile := filepath.Join(t.TempDir(), "token")
+			assert.Nil(t, os.WriteFile(tokenFile, []byte(tc.token+"\n"), 0600))
+			got, err := New(tokenFile, tc.audience).FetchToken(context.Background())
+			if tc.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.token, got.Token)
+			assert.Equal(t, tc.wantExpiresOn, got.ExpiresOn)
+		})
+	}
+}
@@ -0,0 +1,435 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package benchmark generates a synthetic fleet and synthetic placements on a hub cluster, e.g. a kind cluster or an
+// envtest API server which the hub agent runs against, and measures how fast the hub agent schedules the placements
+// and generates the works for them, so that the throughput can be compared across releases.
+//
+// No member agent is needed: the member agents of the synthetic clusters are faked by updating the status of their
+// internal member clusters, so the works are generated but never applied. The fake member agents can also report
+// synthetic properties of the clusters, and consume the works by reporting them as applied and available, so that the
+// property-based scheduling and the rollouts are exercised at scale too.
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+const (
+	// RunLabel is the label of the objects created by a benchmark run; its value is the ID of the run.
+	RunLabel = "benchmark.fleet.azure.com/run"
+
+	// heartbeatInterval is how often the fake member agents send their heartbeats.
+	heartbeatInterval = 30 * time.Second
+)
+
+// Scheme is the scheme of the objects created by the benchmark.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(clusterv1beta1.AddToScheme(Scheme))
+	utilruntime.Must(placementv1beta1.AddToScheme(Scheme))
+}
+
+// Config is the configuration of a benchmark run.
+type Config struct {
+	// RunID identifies the run; it prefixes the names of the created objects.
+	RunID string
+	// Clusters is the number of the synthetic member clusters.
+	Clusters int
+	// Placements is the number of the cluster resource placements.
+	Placements int
+	// ClustersPerPlacement is the number of the clusters each placement picks; zero picks all the clusters.
+	ClustersPerPlacement int32
+	// ResourcesPerPlacement is the number of the config maps in the namespace each placement selects.
+	ResourcesPerPlacement int
+	// Timeout is how long to wait for all the placements to be scheduled and synchronized.
+	Timeout time.Duration
+	// PollInterval is how often the placements are checked; it bounds the precision of the latencies.
+	PollInterval time.Duration
+	// Cleanup deletes the created objects once the run is over.
+	Cleanup bool
+	// ConsumeWorks makes the fake member agents report the works of the synthetic clusters as applied and available,
+	// every PollInterval, so that the placements are rolled out and become available; the run then waits for the
+	// placements to be available rather than synchronized.
+	ConsumeWorks bool
+	// ReportProperties makes the fake member agents report synthetic properties and resource usages of the clusters
+	// with their heartbeats, e.g. for the placements with property-based scheduling.
+	ReportProperties bool
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.RunID == "" {
+		errs = append(errs, errors.New("the run ID must not be empty"))
+	}
+	if c.Clusters < 1 {
+		errs = append(errs, fmt.Errorf("the number of clusters %d must be positive", c.Clusters))
+	}
+	if c.Placements < 1 {
+		errs = append(errs, fmt.Errorf("the number of placements %d must be positive", c.Placements))
+	}
+	if c.ClustersPerPlacement < 0 || int(c.ClustersPerPlacement) > c.Clusters {
+		errs = append(errs, fmt.Errorf("the number of clusters per placement %d must be between 0 and the number of clusters %d", c.ClustersPerPlacement, c.Clusters))
+	}
+	if c.ResourcesPerPlacement < 0 {
+		errs = append(errs, fmt.Errorf("the number of resources per placement %d must not be negative", c.ResourcesPerPlacement))
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("the timeout %v must be positive", c.Timeout))
+	}
+	if c.PollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("the poll interval %v must be positive", c.PollInterval))
+	}
+	return errors.Join(errs...)
+}
+
+// Runner runs a benchmark against a hub cluster.
+type Runner struct {
+	client client.Client
+	config Config
+	now    func() time.Time
+}
+
+// NewRunner returns a runner which creates the objects with the client; the client must use the Scheme.
+func NewRunner(c client.Client, config Config) *Runner {
+	return &Runner{
+		client: c,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run creates the synthetic clusters, waits for them to join, creates the placements and measures how long the
+// hub agent takes to schedule them, to generate their works and, if the works are consumed, to make them available.
+// The placements which are not done before the timeout are reported as incomplete.
+func (r *Runner) Run(ctx context.Context) (*Result, error) {
+	if err := r.config.Validate(); err != nil {
+		return nil, err
+	}
+	var result *Result
+	err := r.withSyntheticClusters(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = r.runPlacements(ctx)
+		return err
+	})
+	return result, err
+}
+
+// Simulate creates the synthetic clusters, waits for them to join and runs their fake member agents until the context
+// is done, without creating any placement, so that the hub agent can be exercised at scale with other placements.
+func (r *Runner) Simulate(ctx context.Context) error {
+	if err := r.config.Validate(); err != nil {
+		return err
+	}
+	return r.withSyntheticClusters(ctx, func(ctx context.Context) error {
+		klog.InfoS("Simulating the member clusters until interrupted", "run", r.config.RunID, "count", r.config.Clusters)
+		<-ctx.Done()
+		return nil
+	})
+}
+
+// withSyntheticClusters creates the synthetic clusters, runs their fake member agents and waits for them to join
+// before running the function; the created objects are deleted afterwards if the cleanup is enabled.
+func (r *Runner) withSyntheticClusters(ctx context.Context, run func(ctx context.Context) error) error {
+	agentCtx, stopAgents := context.WithCancel(context.WithoutCancel(ctx))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.runFakeMemberAgents(agentCtx)
+	}()
+	defer func() {
+		stopAgents()
+		wg.Wait()
+	}()
+	if r.config.Cleanup {
+		defer func() {
+			// the fake member agents are still running, so that the clusters can leave
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+			defer cancel()
+			if err := r.cleanup(cleanupCtx); err != nil {
+				klog.ErrorS(err, "Failed to clean up the benchmark objects", "run", r.config.RunID)
+			}
+		}()
+	}
+
+	klog.InfoS("Creating the member clusters", "run", r.config.RunID, "count", r.config.Clusters)
+	for i := 0; i < r.config.Clusters; i++ {
+		if err := r.client.Create(ctx, r.memberCluster(i)); err != nil {
+			return fmt.Errorf("failed to create member cluster %d: %w", i, err)
+		}
+	}
+	if err := r.waitForClustersToJoin(ctx); err != nil {
+		return err
+	}
+	return run(ctx)
+}
+
+// runPlacements creates the placements and records when they are done with each stage.
+func (r *Runner) runPlacements(ctx context.Context) (*Result, error) {
+	klog.InfoS("Creating the placements", "run", r.config.RunID, "count", r.config.Placements)
+	rec := newRecorder(r.config.Placements, r.config.ConsumeWorks)
+	start := r.now()
+	for i := 0; i < r.config.Placements; i++ {
+		for _, obj := range r.placementResources(i) {
+			if err := r.client.Create(ctx, obj); err != nil {
+				return nil, fmt.Errorf("failed to create %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+			}
+		}
+		crp := r.placement(i)
+		if err := r.client.Create(ctx, crp); err != nil {
+			return nil, fmt.Errorf("failed to create placement %s: %w", crp.Name, err)
+		}
+		rec.created(crp.Name, r.now())
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+	err := wait.PollUntilContextCancel(pollCtx, r.config.PollInterval, true, func(ctx context.Context) (bool, error) {
+		var crps placementv1beta1.ClusterResourcePlacementList
+		if err := r.client.List(ctx, &crps, client.MatchingLabels{RunLabel: r.config.RunID}); err != nil {
+			klog.ErrorS(err, "Failed to list the placements", "run", r.config.RunID)
+			return false, nil
+		}
+		now := r.now()
+		for i := range crps.Items {
+			rec.observe(&crps.Items[i], now)
+		}
+		return rec.done(), nil
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return nil, err
+	}
+	return rec.result(r.config.Clusters, r.now().Sub(start)), nil
+}
+
+// waitForClustersToJoin waits until the hub agent marks all the member clusters as joined.
+func (r *Runner) waitForClustersToJoin(ctx context.Context) error {
+	klog.InfoS("Waiting for the member clusters to join", "run", r.config.RunID)
+	joinCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+	if err := wait.PollUntilContextCancel(joinCtx, r.config.PollInterval, true, func(ctx context.Context) (bool, error) {
+		var mcs clusterv1beta1.MemberClusterList
+		if err := r.client.List(ctx, &mcs, client.MatchingLabels{RunLabel: r.config.RunID}); err != nil {
+			klog.ErrorS(err, "Failed to list the member clusters", "run", r.config.RunID)
+			return false, nil
+		}
+		joined := 0
+		for i := range mcs.Items {
+			if meta.IsStatusConditionTrue(mcs.Items[i].Status.Conditions, string(clusterv1beta1.ConditionTypeMemberClusterJoined)) {
+				joined++
+				continue
+			}
+			// do not wait for the next heartbeat to join
+			r.heartbeat(ctx, mcs.Items[i].Name)
+		}
+		return joined == r.config.Clusters, nil
+	}); err != nil {
+		return fmt.Errorf("the member clusters did not join: %w", err)
+	}
+	return nil
+}
+
+// cleanup deletes the objects created by the run and waits for the member clusters to leave.
+func (r *Runner) cleanup(ctx context.Context) error {
+	klog.InfoS("Deleting the benchmark objects", "run", r.config.RunID)
+	runLabel := client.MatchingLabels{RunLabel: r.config.RunID}
+	for _, obj := range []client.Object{
+		&placementv1beta1.ClusterResourcePlacement{},
+		&corev1.Namespace{},
+		&clusterv1beta1.MemberCluster{},
+	} {
+		if err := r.client.DeleteAllOf(ctx, obj, runLabel); err != nil {
+			return fmt.Errorf("failed to delete the %T objects: %w", obj, err)
+		}
+	}
+	return wait.PollUntilContextCancel(ctx, r.config.PollInterval, true, func(ctx context.Context) (bool, error) {
+		var mcs clusterv1beta1.MemberClusterList
+		if err := r.client.List(ctx, &mcs, runLabel); err != nil {
+			return false, nil
+		}
+		// do not wait for the next heartbeat to leave
+		for i := range mcs.Items {
+			r.heartbeat(ctx, mcs.Items[i].Name)
+		}
+		return len(mcs.Items) == 0, nil
+	})
+}
+
+// runFakeMemberAgents sends the heartbeats of the member agents of the synthetic clusters, and consumes their works if
+// enabled, until the context is done.
+func (r *Runner) runFakeMemberAgents(ctx context.Context) {
+	var wg sync.WaitGroup
+	if r.config.ConsumeWorks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.UntilWithContext(ctx, func(ctx context.Context) {
+				for i := 0; i < r.config.Clusters; i++ {
+					if err := r.consumeWorks(ctx, r.clusterName(i)); err != nil && ctx.Err() == nil {
+						klog.V(2).InfoS("Failed to consume the works of a fake member agent", "memberCluster", r.clusterName(i), "error", err)
+					}
+				}
+			}, r.config.PollInterval)
+		}()
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for i := 0; i < r.config.Clusters; i++ {
+			r.heartbeat(ctx, r.clusterName(i))
+		}
+	}, heartbeatInterval)
+	wg.Wait()
+}
+
+// heartbeat updates the status of the internal member cluster of a synthetic cluster the way its member agent would:
+// the agent joins or leaves according to the desired state, and is always healthy.
+func (r *Runner) heartbeat(ctx context.Context, name string) {
+	if err := r.updateAgentStatus(ctx, name); err != nil && ctx.Err() == nil {
+		klog.V(2).InfoS("Failed to send the heartbeat of a fake member agent", "memberCluster", name, "error", err)
+	}
+}
+
+func (r *Runner) updateAgentStatus(ctx context.Context, name string) error {
+	var imc clusterv1beta1.InternalMemberCluster
+	key := client.ObjectKey{Namespace: fmt.Sprintf(utils.NamespaceNameFormat, name), Name: name}
+	if err := r.client.Get(ctx, key, &imc); err != nil {
+		// the internal member cluster is not created by the hub agent yet, or is deleted
+		return client.IgnoreNotFound(err)
+	}
+	joined := metav1.Condition{
+		Type:               string(clusterv1beta1.AgentJoined),
+		Status:             metav1.ConditionTrue,
+		Reason:             "BenchmarkAgentJoined",
+		ObservedGeneration: imc.Generation,
+	}
+	if imc.Spec.State == clusterv1beta1.ClusterStateLeave {
+		joined.Status = metav1.ConditionFalse
+		joined.Reason = "BenchmarkAgentLeft"
+	}
+	imc.SetConditionsWithType(clusterv1beta1.MemberAgent, joined, metav1.Condition{
+		Type:               string(clusterv1beta1.AgentHealthy),
+		Status:             metav1.ConditionTrue,
+		Reason:             "BenchmarkAgentHealthy",
+		ObservedGeneration: imc.Generation,
+	})
+	imc.GetAgentStatus(clusterv1beta1.MemberAgent).LastReceivedHeartbeat = metav1.NewTime(r.now())
+	if r.config.ReportProperties {
+		r.setSyntheticProperties(&imc)
+	}
+	if err := r.client.Status().Update(ctx, &imc); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *Runner) clusterName(i int) string {
+	return fmt.Sprintf("bench-%s-cluster-%d", r.config.RunID, i)
+}
+
+func (r *Runner) placementName(i int) string {
+	return fmt.Sprintf("bench-%s-crp-%d", r.config.RunID, i)
+}
+
+// memberCluster returns the i-th synthetic member cluster.
+func (r *Runner) memberCluster(i int) *clusterv1beta1.MemberCluster {
+	return &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   r.clusterName(i),
+			Labels: map[string]string{RunLabel: r.config.RunID},
+		},
+		Spec: clusterv1beta1.MemberClusterSpec{
+			Identity: rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      r.clusterName(i),
+				Namespace: utils.FleetSystemNamespace,
+			},
+			HeartbeatPeriodSeconds: int32(heartbeatInterval.Seconds()),
+		},
+	}
+}
+
+// placementResources returns the namespace which the i-th placement selects and the config maps in it.
+func (r *Runner) placementResources(i int) []client.Object {
+	name := r.placementName(i)
+	objs := []client.Object{
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{RunLabel: r.config.RunID},
+			},
+		},
+	}
+	for j := 0; j < r.config.ResourcesPerPlacement; j++ {
+		objs = append(objs, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("config-%d", j),
+				Namespace: name,
+			},
+			Data: map[string]string{"index": fmt.Sprint(j)},
+		})
+	}
+	return objs
+}
+
+// placement returns the i-th placement, which places its namespace on the synthetic clusters of the run only.
+func (r *Runner) placement(i int) *placementv1beta1.ClusterResourcePlacement {
+	policy := &placementv1beta1.PlacementPolicy{
+		PlacementType: placementv1beta1.PickAllPlacementType,
+		Affinity: &placementv1beta1.Affinity{
+			ClusterAffinity: &placementv1beta1.ClusterAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &placementv1beta1.ClusterSelector{
+					ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{
+						{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{RunLabel: r.config.RunID}}},
+					},
+				},
+			},
+		},
+	}
+	if r.config.ClustersPerPlacement > 0 {
+		policy.PlacementType = placementv1beta1.PickNPlacementType
+		policy.NumberOfClusters = ptr.To(r.config.ClustersPerPlacement)
+	}
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   r.placementName(i),
+			Labels: map[string]string{RunLabel: r.config.RunID},
+		},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{
+				{
+					Group:   "",
+					Version: "v1",
+					Kind:    "Namespace",
+					Name:    r.placementName(i),
+				},
+			},
+			Policy: policy,
+		},
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func validConfig() Config {
+	return Config{
+		RunID:                 "test",
+		Clusters:              3,
+		Placements:            2,
+		ClustersPerPlacement:  2,
+		ResourcesPerPlacement: 1,
+		Timeout:               time.Minute,
+		PollInterval:          time.Second,
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		mutate  func(c *Config)
+		wantErr bool
+	}{
+		"valid config": {
+			mutate: func(_ *Config) {},
+		},
+		"pick all clusters": {
+			mutate: func(c *Config) { c.ClustersPerPlacement = 0 },
+		},
+		"empty run ID": {
+			mutate:  func(c *Config) { c.RunID = "" },
+			wantErr: true,
+		},
+		"no clusters": {
+			mutate:  func(c *Config) { c.Clusters = 0 },
+			wantErr: true,
+		},
+		"more clusters per placement than clusters": {
+			mutate:  func(c *Config) { c.ClustersPerPlacement = 4 },
+			wantErr: true,
+		},
+		"zero poll interval": {
+			mutate:  func(c *Config) { c.PollInterval = 0 },
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := validConfig()
+			tc.mutate(&c)
+			if err := c.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateAgentStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newIMC := func(state clusterv1beta1.ClusterState) *clusterv1beta1.InternalMemberCluster {
+		return &clusterv1beta1.InternalMemberCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "cluster",
+				Namespace:  fmt.Sprintf(utils.NamespaceNameFormat, "cluster"),
+				Generation: 2,
+			},
+			Spec: clusterv1beta1.InternalMemberClusterSpec{State: state},
+		}
+	}
+	tests := map[string]struct {
+		imc        *clusterv1beta1.InternalMemberCluster
+		wantJoined metav1.ConditionStatus
+	}{
+		"joining cluster": {
+			imc:        newIMC(clusterv1beta1.ClusterStateJoin),
+			wantJoined: metav1.ConditionTrue,
+		},
+		"leaving cluster": {
+			imc:        newIMC(clusterv1beta1.ClusterStateLeave),
+			wantJoined: metav1.ConditionFalse,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(tc.imc).WithStatusSubresource(tc.imc).Build()
+			r := NewRunner(c, validConfig())
+			r.now = func() time.Time { return now }
+			if err := r.updateAgentStatus(context.Background(), "cluster"); err != nil {
+				t.Fatalf("updateAgentStatus() = %v, want nil", err)
+			}
+
+			var got clusterv1beta1.InternalMemberCluster
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(tc.imc), &got); err != nil {
+				t.Fatalf("failed to get the internal member cluster: %v", err)
+			}
+			want := []clusterv1beta1.AgentStatus{
+				{
+					Type: clusterv1beta1.MemberAgent,
+					Conditions: []metav1.Condition{
+						{Type: string(clusterv1beta1.AgentJoined), Status: tc.wantJoined, ObservedGeneration: 2},
+						{Type: string(clusterv1beta1.AgentHealthy), Status: metav1.ConditionTrue, ObservedGeneration: 2},
+					},
+					LastReceivedHeartbeat: metav1.NewTime(now),
+				},
+			}
+			if diff := cmp.Diff(want, got.Status.AgentStatus, cmpopts.IgnoreFields(metav1.Condition{}, "Reason", "LastTransitionTime")); diff != "" {
+				t.Errorf("agent status mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// the internal member cluster is not created yet
+	r := NewRunner(fake.NewClientBuilder().WithScheme(Scheme).Build(), validConfig())
+	if err := r.updateAgentStatus(context.Background(), "cluster"); err != nil {
+		t.Errorf("updateAgentStatus() = %v, want nil for a missing internal member cluster", err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	crp := func(name string, generation int64, conds ...placementv1beta1.ClusterResourcePlacementConditionType) *placementv1beta1.ClusterResourcePlacement {
+		p := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: name, Generation: generation}}
+		for _, c := range conds {
+			p.Status.Conditions = append(p.Status.Conditions, metav1.Condition{Type: string(c), Status: metav1.ConditionTrue, ObservedGeneration: 1})
+		}
+		return p
+	}
+	scheduled := placementv1beta1.ClusterResourcePlacementScheduledConditionType
+	synchronized := placementv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType
+
+	rec := newRecorder(3, false)
+	rec.created("crp-0", start)
+	rec.created("crp-1", start.Add(time.Second))
+	rec.created("crp-2", start.Add(2*time.Second))
+
+	rec.observe(crp("crp-0", 1, scheduled), start.Add(2*time.Second))
+	// the conditions of an older generation do not count
+	rec.observe(crp("crp-1", 2, scheduled, synchronized), start.Add(2*time.Second))
+	// a placement which is not created by the run is ignored
+	rec.observe(crp("other", 1, scheduled, synchronized), start.Add(2*time.Second))
+	if rec.done() {
+		t.Fatalf("done() = true, want false")
+	}
+	rec.observe(crp("crp-0", 1, scheduled, synchronized), start.Add(4*time.Second))
+	rec.observe(crp("crp-1", 1, scheduled, synchronized), start.Add(4*time.Second))
+
+	want := &Result{
+		Clusters:   3,
+		Placements: 3,
+		Scheduling: Latency{
+			Count: 2, P50: 2 * time.Second, P90: 3 * time.Second, P99: 3 * time.Second, Max: 3 * time.Second, Throughput: 0.2,
+		},
+		WorkGeneration: Latency{
+			Count: 2, P50: 3 * time.Second, P90: 4 * time.Second, P99: 4 * time.Second, Max: 4 * time.Second, Throughput: 0.2,
+		},
+		Duration: 10 * time.Second,
+	}
+	got := rec.result(3, 10*time.Second)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("result() mismatch (-want, +got):\n%s", diff)
+	}
+	if got.Incomplete() != 1 {
+		t.Errorf("Incomplete() = %d, want 1", got.Incomplete())
+	}
+}
+
+func TestPlacement(t *testing.T) {
+	r := NewRunner(nil, validConfig())
+	got := r.placement(1)
+	if got.Name != "bench-test-crp-1" || got.Labels[RunLabel] != "test" {
+		t.Errorf("placement() = %s with labels %v, want bench-test-crp-1 labeled with the run", got.Name, got.Labels)
+	}
+	if got.Spec.Policy.PlacementType != placementv1beta1.PickNPlacementType || *got.Spec.Policy.NumberOfClusters != 2 {
+		t.Errorf("placement() policy = %+v, want picking 2 clusters", got.Spec.Policy)
+	}
+	if selected := got.Spec.ResourceSelectors[0].Name; selected != "bench-test-crp-1" {
+		t.Errorf("placement() selects namespace %s, want bench-test-crp-1", selected)
+	}
+	if objs := r.placementResources(1); len(objs) != 2 {
+		t.Errorf("placementResources() = %d objects, want the namespace and 1 config map", len(objs))
+	}
+}
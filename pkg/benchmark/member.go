@@ -0,0 +1,130 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/propertyprovider"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+const (
+	// The synthetic clusters have between minSyntheticNodes and maxSyntheticNodes nodes of the same size.
+	minSyntheticNodes = 3
+	maxSyntheticNodes = 20
+	// syntheticNodeCPU and syntheticNodeMemory are the capacity of a synthetic node; a tenth of it is reserved.
+	syntheticNodeCPU    = 8
+	syntheticNodeMemory = 32 << 30
+)
+
+// setSyntheticProperties sets the properties and the resource usage of the synthetic cluster: the sizes of the clusters
+// are spread deterministically by their names, and the available resources of a cluster change with every heartbeat,
+// as if the workloads on it come and go.
+func (r *Runner) setSyntheticProperties(imc *clusterv1beta1.InternalMemberCluster) {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(imc.Name))
+	seed := int64(h.Sum32())
+	now := metav1.NewTime(r.now())
+	heartbeats := now.Unix() / int64(heartbeatInterval.Seconds())
+
+	nodes := minSyntheticNodes + seed%(maxSyntheticNodes-minSyntheticNodes+1)
+	// between 20% and 80% of the allocatable resources are available
+	availablePercent := 20 + (seed+heartbeats)%61
+	resources := func(perNode int64, format resource.Format) (capacity, allocatable, available resource.Quantity) {
+		total := nodes * perNode * 1000
+		allocatableMilli := total * 9 / 10
+		return *resource.NewMilliQuantity(total, format),
+			*resource.NewMilliQuantity(allocatableMilli, format),
+			*resource.NewMilliQuantity(allocatableMilli*availablePercent/100, format)
+	}
+	cpuCapacity, cpuAllocatable, cpuAvailable := resources(syntheticNodeCPU, resource.DecimalSI)
+	memoryCapacity, memoryAllocatable, memoryAvailable := resources(syntheticNodeMemory, resource.BinarySI)
+
+	imc.Status.Properties = map[clusterv1beta1.PropertyName]clusterv1beta1.PropertyValue{
+		propertyprovider.NodeCountProperty: {Value: fmt.Sprint(nodes), ObservationTime: now},
+	}
+	imc.Status.ResourceUsage = clusterv1beta1.ResourceUsage{
+		Capacity:        corev1.ResourceList{corev1.ResourceCPU: cpuCapacity, corev1.ResourceMemory: memoryCapacity},
+		Allocatable:     corev1.ResourceList{corev1.ResourceCPU: cpuAllocatable, corev1.ResourceMemory: memoryAllocatable},
+		Available:       corev1.ResourceList{corev1.ResourceCPU: cpuAvailable, corev1.ResourceMemory: memoryAvailable},
+		ObservationTime: now,
+	}
+}
+
+// consumeWorks reports the works of the synthetic cluster as applied and available the way its member agent would,
+// without applying their manifests anywhere; the works already reported for their current generations are skipped.
+func (r *Runner) consumeWorks(ctx context.Context, name string) error {
+	var works placementv1beta1.WorkList
+	if err := r.client.List(ctx, &works, client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, name))); err != nil {
+		return err
+	}
+	for i := range works.Items {
+		w := &works.Items[i]
+		if w.DeletionTimestamp != nil ||
+			condition.IsConditionStatusTrue(meta.FindStatusCondition(w.Status.Conditions, placementv1beta1.WorkConditionTypeAvailable), w.Generation) {
+			continue
+		}
+		consumeWork(w)
+		if err := r.client.Status().Update(ctx, w); err != nil && !apierrors.IsConflict(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// consumeWork sets the status of the work as if all of its manifests are applied and available.
+func consumeWork(w *placementv1beta1.Work) {
+	conditions := func() []metav1.Condition {
+		return []metav1.Condition{
+			{
+				Type:               placementv1beta1.WorkConditionTypeApplied,
+				Status:             metav1.ConditionTrue,
+				Reason:             work.WorkAppliedCompletedReason,
+				ObservedGeneration: w.Generation,
+			},
+			{
+				Type:               placementv1beta1.WorkConditionTypeAvailable,
+				Status:             metav1.ConditionTrue,
+				Reason:             work.WorkAvailableReason,
+				ObservedGeneration: w.Generation,
+			},
+		}
+	}
+	manifestConditions := make([]placementv1beta1.ManifestCondition, 0, len(w.Spec.Workload.Manifests))
+	for i, manifest := range w.Spec.Workload.Manifests {
+		identifier := placementv1beta1.WorkResourceIdentifier{Ordinal: i}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(manifest.Raw); err == nil {
+			gvk := obj.GroupVersionKind()
+			identifier.Group, identifier.Version, identifier.Kind = gvk.Group, gvk.Version, gvk.Kind
+			identifier.Namespace, identifier.Name = obj.GetNamespace(), obj.GetName()
+		}
+		manifestCondition := placementv1beta1.ManifestCondition{Identifier: identifier}
+		for _, cond := range conditions() {
+			meta.SetStatusCondition(&manifestCondition.Conditions, cond)
+		}
+		manifestConditions = append(manifestConditions, manifestCondition)
+	}
+	w.Status.ManifestConditions = manifestConditions
+	for _, cond := range conditions() {
+		meta.SetStatusCondition(&w.Status.Conditions, cond)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/propertyprovider"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func TestSetSyntheticProperties(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRunner(nil, validConfig())
+	r.now = func() time.Time { return now }
+	propertiesOf := func(name string) *clusterv1beta1.InternalMemberCluster {
+		imc := &clusterv1beta1.InternalMemberCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		r.setSyntheticProperties(imc)
+		return imc
+	}
+
+	first, again := propertiesOf("bench-test-cluster-0"), propertiesOf("bench-test-cluster-0")
+	if diff := cmp.Diff(first.Status, again.Status); diff != "" {
+		t.Errorf("setSyntheticProperties() is not deterministic (-first, +again):\n%s", diff)
+	}
+	for i := 0; i < 20; i++ {
+		imc := propertiesOf(fmt.Sprintf("bench-test-cluster-%d", i))
+		var nodes int64
+		if _, err := fmt.Sscan(imc.Status.Properties[propertyprovider.NodeCountProperty].Value, &nodes); err != nil {
+			t.Fatalf("the node count %q is not a number: %v", imc.Status.Properties[propertyprovider.NodeCountProperty].Value, err)
+		}
+		if nodes < minSyntheticNodes || nodes > maxSyntheticNodes {
+			t.Errorf("the node count of %s = %d, want between %d and %d", imc.Name, nodes, minSyntheticNodes, maxSyntheticNodes)
+		}
+		usage := imc.Status.ResourceUsage
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			capacity, allocatable, available := usage.Capacity[name], usage.Allocatable[name], usage.Available[name]
+			if available.Cmp(allocatable) > 0 || allocatable.Cmp(capacity) > 0 || available.Sign() <= 0 {
+				t.Errorf("the %s of %s = %s available, %s allocatable and %s capacity, want 0 < available <= allocatable <= capacity",
+					name, imc.Name, available.String(), allocatable.String(), capacity.String())
+			}
+		}
+	}
+
+	// the available resources change with the heartbeats
+	r.now = func() time.Time { return now.Add(heartbeatInterval) }
+	later := propertiesOf("bench-test-cluster-0")
+	if later.Status.ResourceUsage.Available.Cpu().Equal(*first.Status.ResourceUsage.Available.Cpu()) {
+		t.Errorf("the available CPU did not change with the next heartbeat, want changed")
+	}
+}
+
+func TestConsumeWorks(t *testing.T) {
+	namespace := fmt.Sprintf(utils.NamespaceNameFormat, "cluster")
+	newWork := func(name string, generation int64, conditions ...metav1.Condition) *placementv1beta1.Work {
+		return &placementv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: generation},
+			Spec: placementv1beta1.WorkSpec{
+				Workload: placementv1beta1.WorkloadTemplate{
+					Manifests: []placementv1beta1.Manifest{
+						{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`)}},
+					},
+				},
+			},
+			Status: placementv1beta1.WorkStatus{Conditions: conditions},
+		}
+	}
+	pending := newWork("pending", 2)
+	consumed := newWork("consumed", 1, metav1.Condition{
+		Type:               placementv1beta1.WorkConditionTypeAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Consumed",
+		ObservedGeneration: 1,
+	})
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(pending, consumed).WithStatusSubresource(pending, consumed).Build()
+	r := NewRunner(c, validConfig())
+	if err := r.consumeWorks(context.Background(), "cluster"); err != nil {
+		t.Fatalf("consumeWorks() = %v, want nil", err)
+	}
+
+	var got placementv1beta1.Work
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(pending), &got); err != nil {
+		t.Fatalf("failed to get the work: %v", err)
+	}
+	wantConditions := []metav1.Condition{
+		{Type: placementv1beta1.WorkConditionTypeApplied, Status: metav1.ConditionTrue, Reason: work.WorkAppliedCompletedReason, ObservedGeneration: 2},
+		{Type: placementv1beta1.WorkConditionTypeAvailable, Status: metav1.ConditionTrue, Reason: work.WorkAvailableReason, ObservedGeneration: 2},
+	}
+	ignoreTime := cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")
+	if diff := cmp.Diff(wantConditions, got.Status.Conditions, ignoreTime); diff != "" {
+		t.Errorf("work conditions mismatch (-want, +got):\n%s", diff)
+	}
+	wantManifestConditions := []placementv1beta1.ManifestCondition{
+		{
+			Identifier: placementv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "config"},
+			Conditions: wantConditions,
+		},
+	}
+	if diff := cmp.Diff(wantManifestConditions, got.Status.ManifestConditions, ignoreTime); diff != "" {
+		t.Errorf("manifest conditions mismatch (-want, +got):\n%s", diff)
+	}
+
+	// the work which is already available for its generation is left alone
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(consumed), &got); err != nil {
+		t.Fatalf("failed to get the work: %v", err)
+	}
+	if len(got.Status.Conditions) != 1 || got.Status.Conditions[0].Reason != "Consumed" {
+		t.Errorf("the consumed work conditions = %+v, want unchanged", got.Status.Conditions)
+	}
+}
+
+func TestRecorderAvailability(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	available := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp-0", Generation: 1}}
+	available.Status.Conditions = []metav1.Condition{
+		{Type: string(placementv1beta1.ClusterResourcePlacementAvailableConditionType), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+	}
+	synchronized := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp-1", Generation: 1}}
+	synchronized.Status.Conditions = []metav1.Condition{
+		{Type: string(placementv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+	}
+
+	rec := newRecorder(2, true)
+	rec.created("crp-0", start)
+	rec.created("crp-1", start)
+	rec.observe(available, start.Add(time.Second))
+	rec.observe(synchronized, start.Add(time.Second))
+	if rec.done() {
+		t.Errorf("done() = true, want false as crp-1 is not available")
+	}
+
+	got := rec.result(3, 10*time.Second)
+	wantDone := Latency{Count: 1, P50: time.Second, P90: time.Second, P99: time.Second, Max: time.Second, Throughput: 0.1}
+	if diff := cmp.Diff(&wantDone, got.Availability); diff != "" {
+		t.Errorf("result() availability mismatch (-want, +got):\n%s", diff)
+	}
+	// the available placement is scheduled and synchronized, even if it is not observed
+	if got.Scheduling.Count != 2 || got.WorkGeneration.Count != 2 {
+		t.Errorf("result() scheduling and work generation counts = %d, %d, want 2, 2", got.Scheduling.Count, got.WorkGeneration.Count)
+	}
+	if got.Incomplete() != 1 {
+		t.Errorf("Incomplete() = %d, want 1", got.Incomplete())
+	}
+}
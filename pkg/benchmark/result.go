@@ -0,0 +1,205 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// Latency summarizes the latencies of the placements for a stage, i.e. the time from the creation of a placement
+// until the stage is done for it.
+type Latency struct {
+	// Count is the number of the placements which are done with the stage.
+	Count int `json:"count"`
+	// P50, P90, P99 and Max are the percentiles of the latencies.
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+	// Throughput is the number of the placements done with the stage per second.
+	Throughput float64 `json:"throughput"`
+}
+
+// Result is the result of a benchmark run.
+type Result struct {
+	// Clusters is the number of the member clusters.
+	Clusters int `json:"clusters"`
+	// Placements is the number of the placements.
+	Placements int `json:"placements"`
+	// Scheduling summarizes how long the placements take to be scheduled.
+	Scheduling Latency `json:"scheduling"`
+	// WorkGeneration summarizes how long the placements take to have their works generated, i.e. to be
+	// synchronized.
+	WorkGeneration Latency `json:"workGeneration"`
+	// Availability summarizes how long the placements take to be available; it is only measured when the works are
+	// consumed by the fake member agents.
+	Availability *Latency `json:"availability,omitempty"`
+	// Duration is the time from the creation of the first placement until all of them are done, or the run times
+	// out.
+	Duration time.Duration `json:"duration"`
+}
+
+// Incomplete returns the number of the placements which are not done before the run times out, i.e. not available if
+// the availability is measured, or not synchronized otherwise.
+func (r *Result) Incomplete() int {
+	if r.Availability != nil {
+		return r.Placements - r.Availability.Count
+	}
+	return r.Placements - r.WorkGeneration.Count
+}
+
+// WriteText writes the result as a table.
+func (r *Result) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Clusters: %d, placements: %d, duration: %v, incomplete: %d\n", r.Clusters, r.Placements, r.Duration.Round(time.Millisecond), r.Incomplete())
+	fmt.Fprintln(tw, "STAGE\tCOUNT\tP50\tP90\tP99\tMAX\tPLACEMENTS/S")
+	type stage struct {
+		name    string
+		latency Latency
+	}
+	stages := []stage{
+		{name: "Scheduling", latency: r.Scheduling},
+		{name: "WorkGeneration", latency: r.WorkGeneration},
+	}
+	if r.Availability != nil {
+		stages = append(stages, stage{name: "Availability", latency: *r.Availability})
+	}
+	for _, stage := range stages {
+		l := stage.latency
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\t%v\t%v\t%.2f\n", stage.name, l.Count,
+			l.P50.Round(time.Millisecond), l.P90.Round(time.Millisecond), l.P99.Round(time.Millisecond), l.Max.Round(time.Millisecond), l.Throughput)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the result as JSON, so that the results of the releases can be compared by tools.
+func (r *Result) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// recorder records when the placements are created and when they are done with each stage.
+type recorder struct {
+	createdAt    map[string]time.Time
+	scheduled    map[string]time.Duration
+	synchronized map[string]time.Duration
+	// available is nil if the availability is not measured.
+	available map[string]time.Duration
+}
+
+func newRecorder(placements int, measureAvailability bool) *recorder {
+	rec := &recorder{
+		createdAt:    make(map[string]time.Time, placements),
+		scheduled:    make(map[string]time.Duration, placements),
+		synchronized: make(map[string]time.Duration, placements),
+	}
+	if measureAvailability {
+		rec.available = make(map[string]time.Duration, placements)
+	}
+	return rec
+}
+
+// created records the creation of a placement.
+func (r *recorder) created(name string, at time.Time) {
+	r.createdAt[name] = at
+}
+
+// observe records the stages which the placement is done with at the time it is observed.
+func (r *recorder) observe(crp *placementv1beta1.ClusterResourcePlacement, now time.Time) {
+	createdAt, found := r.createdAt[crp.Name]
+	if !found {
+		return
+	}
+	doneWith := func(condType placementv1beta1.ClusterResourcePlacementConditionType) bool {
+		cond := meta.FindStatusCondition(crp.Status.Conditions, string(condType))
+		return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == crp.Generation
+	}
+	if _, recorded := r.scheduled[crp.Name]; !recorded && doneWith(placementv1beta1.ClusterResourcePlacementScheduledConditionType) {
+		r.scheduled[crp.Name] = now.Sub(createdAt)
+	}
+	if _, recorded := r.synchronized[crp.Name]; !recorded && doneWith(placementv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType) {
+		r.synchronized[crp.Name] = now.Sub(createdAt)
+		// the placement is scheduled before its works are generated, even if it is not observed
+		if _, recorded := r.scheduled[crp.Name]; !recorded {
+			r.scheduled[crp.Name] = now.Sub(createdAt)
+		}
+	}
+	if r.available == nil {
+		return
+	}
+	if _, recorded := r.available[crp.Name]; !recorded && doneWith(placementv1beta1.ClusterResourcePlacementAvailableConditionType) {
+		r.available[crp.Name] = now.Sub(createdAt)
+		// the works of the placement are generated before they are available, even if it is not observed
+		if _, recorded := r.synchronized[crp.Name]; !recorded {
+			r.synchronized[crp.Name] = now.Sub(createdAt)
+		}
+		if _, recorded := r.scheduled[crp.Name]; !recorded {
+			r.scheduled[crp.Name] = now.Sub(createdAt)
+		}
+	}
+}
+
+// done returns true if all the placements are available if the availability is measured, or synchronized otherwise.
+func (r *recorder) done() bool {
+	if r.available != nil {
+		return len(r.available) == len(r.createdAt)
+	}
+	return len(r.synchronized) == len(r.createdAt)
+}
+
+// result summarizes the recorded latencies; the throughputs are computed over the given duration of the run.
+func (r *recorder) result(clusters int, duration time.Duration) *Result {
+	result := &Result{
+		Clusters:       clusters,
+		Placements:     len(r.createdAt),
+		Scheduling:     summarize(r.scheduled, duration),
+		WorkGeneration: summarize(r.synchronized, duration),
+		Duration:       duration,
+	}
+	if r.available != nil {
+		availability := summarize(r.available, duration)
+		result.Availability = &availability
+	}
+	return result
+}
+
+// summarize returns the percentiles of the latencies with the nearest-rank method.
+func summarize(latencies map[string]time.Duration, duration time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sorted := make([]time.Duration, 0, len(latencies))
+	for _, l := range latencies {
+		sorted = append(sorted, l)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p int) time.Duration {
+		rank := (p*len(sorted) + 99) / 100
+		return sorted[rank-1]
+	}
+	l := Latency{
+		Count: len(sorted),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   sorted[len(sorted)-1],
+	}
+	if duration > 0 {
+		l.Throughput = float64(len(sorted)) / duration.Seconds()
+	}
+	return l
+}
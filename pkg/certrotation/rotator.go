@@ -0,0 +1,249 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package certrotation features a rotator that renews the client certificate which the member agent uses to access
+// the hub cluster through the certificate signing request (CSR) flow of the hub cluster.
+package certrotation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/certificate/csr"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// rotationThreshold is the fraction of the certificate lifetime after which the certificate is renewed.
+	rotationThreshold = 0.8
+	// certificateIssueTimeout is how long we wait for the hub cluster to issue the certificate.
+	certificateIssueTimeout = 5 * time.Minute
+)
+
+var (
+	// rotationRetryInterval is how long we wait before retrying a failed rotation.
+	rotationRetryInterval = time.Minute
+)
+
+// Rotator renews the client certificate of the member agent before it expires. It creates a certificate signing
+// request for the same identity on the hub cluster with the current certificate, waits for the hub agent to approve it,
+// and replaces the certificate and key files in place. The hub client picks up the new certificate as it reloads the
+// files for new connections.
+type Rotator struct {
+	// HubClient is the client of the hub cluster which authenticates with the current client certificate.
+	HubClient kubernetes.Interface
+	// MemberClusterName is the name of the member cluster that the member agent joins the fleet as.
+	MemberClusterName string
+	// CertFile and KeyFile are the paths of the client certificate and key files.
+	CertFile string
+	KeyFile  string
+	// Validity is the requested validity of the new client certificate.
+	Validity time.Duration
+
+	now func() time.Time
+}
+
+// NewRotator creates a new client certificate rotator.
+func NewRotator(hubClient kubernetes.Interface, memberClusterName, certFile, keyFile string, validity time.Duration) *Rotator {
+	return &Rotator{
+		HubClient:         hubClient,
+		MemberClusterName: memberClusterName,
+		CertFile:          certFile,
+		KeyFile:           keyFile,
+		Validity:          validity,
+		now:               time.Now,
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, as every replica of the member agent has its
+// own client certificate.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start keeps rotating the client certificate until the context is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	klog.V(2).InfoS("Starting the client certificate rotator", "certFile", r.CertFile, "validity", r.Validity)
+	defer klog.V(2).InfoS("The client certificate rotator is stopped")
+	for {
+		delay, err := r.timeUntilRotation()
+		if err != nil {
+			klog.ErrorS(err, "Failed to read the current client certificate, rotating it now", "certFile", r.CertFile)
+			delay = 0
+		}
+		if delay > 0 {
+			klog.V(2).InfoS("Waiting to rotate the client certificate", "delay", delay)
+			if !sleep(ctx, delay) {
+				return nil
+			}
+		}
+		if err := r.rotate(ctx); err != nil {
+			klog.ErrorS(err, "Failed to rotate the client certificate, will retry", "retryInterval", rotationRetryInterval)
+			if !sleep(ctx, rotationRetryInterval) {
+				return nil
+			}
+		}
+	}
+}
+
+// timeUntilRotation returns how long to wait until the current client certificate needs to be renewed.
+func (r *Rotator) timeUntilRotation() (time.Duration, error) {
+	certs, err := certutil.CertsFromFile(r.CertFile)
+	if err != nil {
+		return 0, err
+	}
+	cert := certs[0]
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	rotateAt := cert.NotBefore.Add(time.Duration(float64(lifetime) * rotationThreshold))
+	return rotateAt.Sub(r.now()), nil
+}
+
+// rotate requests a new client certificate of the same identity and replaces the current certificate and key files.
+func (r *Rotator) rotate(ctx context.Context) error {
+	certs, err := certutil.CertsFromFile(r.CertFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the current client certificate: %w", err)
+	}
+	// the hub agent only approves the certificate of the member cluster identity without any group
+	subject := pkix.Name{CommonName: certs[0].Subject.CommonName}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate the private key: %w", err)
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the private key: %w", err)
+	}
+	csrPEM, err := certutil.MakeCSR(privateKey, &subject, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to generate the certificate request: %w", err)
+	}
+
+	req := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("fleet-member-%s-", r.MemberClusterName),
+			Labels: map[string]string{
+				placementv1beta1.MemberClusterNameLabel: r.MemberClusterName,
+			},
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           csrPEM,
+			SignerName:        certificatesv1.KubeAPIServerClientSignerName,
+			ExpirationSeconds: csr.DurationToExpirationSeconds(r.Validity),
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}
+	created, err := r.HubClient.CertificatesV1().CertificateSigningRequests().Create(ctx, req, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create the certificate signing request: %w", err)
+	}
+	klog.V(2).InfoS("Created the certificate signing request", "csr", created.Name, "commonName", subject.CommonName)
+
+	waitCtx, cancel := context.WithTimeout(ctx, certificateIssueTimeout)
+	defer cancel()
+	certPEM, err := csr.WaitForCertificate(waitCtx, r.HubClient, created.Name, created.UID)
+	if err != nil {
+		return fmt.Errorf("failed to wait for the certificate of the signing request %s: %w", created.Name, err)
+	}
+	if _, err := certutil.ParseCertsPEM(certPEM); err != nil {
+		return fmt.Errorf("the issued certificate is invalid: %w", err)
+	}
+
+	// the files are replaced one by one, so the old certificate is briefly paired with the new key; client-go fails
+	// to load the mismatched pair and keeps using the one it loaded before until the certificate is replaced too
+	if err := writeFileAtomically(r.KeyFile, keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write the key file: %w", err)
+	}
+	if err := writeFileAtomically(r.CertFile, certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write the certificate file: %w", err)
+	}
+	klog.V(2).InfoS("Rotated the client certificate", "csr", created.Name)
+	return nil
+}
+
+// BootstrapIdentity copies the bootstrap client certificate and key to the paths that the rotator manages if there is
+// no client certificate yet, e.g. when the pod starts with an empty volume and the bootstrap identity is mounted from
+// a read-only secret.
+func BootstrapIdentity(certFile, keyFile, bootstrapCertFile, bootstrapKeyFile string) error {
+	if _, err := os.Stat(certFile); err == nil {
+		klog.V(2).InfoS("The client certificate already exists, skip bootstrapping", "certFile", certFile)
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	keyPEM, err := os.ReadFile(bootstrapKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the bootstrap key: %w", err)
+	}
+	certPEM, err := os.ReadFile(bootstrapCertFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the bootstrap certificate: %w", err)
+	}
+	if err := writeFileAtomically(keyFile, keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to write the key file: %w", err)
+	}
+	if err := writeFileAtomically(certFile, certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write the certificate file: %w", err)
+	}
+	klog.V(2).InfoS("Bootstrapped the client certificate", "certFile", certFile, "bootstrapCertFile", bootstrapCertFile)
+	return nil
+}
+
+// writeFileAtomically writes the data to a temporary file in the same directory and renames it to the path, so that
+// the readers never see a partially written file.
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sleep waits for the duration and returns false if the context is done before that.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
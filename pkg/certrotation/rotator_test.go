@@ -0,0 +1,190 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package certrotation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const testCommonName = "fleet:member:member-1"
+
+// newTestCert creates a self-signed certificate valid between notBefore and notAfter, and its private key.
+func newTestCert(t *testing.T, notBefore, notAfter time.Time) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: testCommonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create the certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal the key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTimeUntilRotation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		notBefore time.Time
+		notAfter  time.Time
+		want      time.Duration
+	}{
+		"new certificate": {
+			notBefore: now,
+			notAfter:  now.Add(10 * time.Hour),
+			want:      8 * time.Hour,
+		},
+		"certificate to rotate": {
+			notBefore: now.Add(-9 * time.Hour),
+			notAfter:  now.Add(time.Hour),
+			want:      -time.Hour,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			certFile := filepath.Join(t.TempDir(), "tls.crt")
+			certPEM, _ := newTestCert(t, tc.notBefore, tc.notAfter)
+			if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+				t.Fatalf("failed to write the certificate: %v", err)
+			}
+			r := &Rotator{CertFile: certFile, now: func() time.Time { return now }}
+			got, err := r.timeUntilRotation()
+			if err != nil {
+				t.Fatalf("timeUntilRotation() = %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Errorf("timeUntilRotation() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	now := time.Now()
+	oldCertPEM, oldKeyPEM := newTestCert(t, now.Add(-time.Hour), now.Add(time.Minute))
+	if err := os.WriteFile(certFile, oldCertPEM, 0600); err != nil {
+		t.Fatalf("failed to write the certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, oldKeyPEM, 0600); err != nil {
+		t.Fatalf("failed to write the key: %v", err)
+	}
+	newCertPEM, _ := newTestCert(t, now, now.Add(time.Hour))
+
+	hubClient := fake.NewSimpleClientset()
+	// the fake client does not generate the names, and the hub agent and the signer are simulated by the reactor
+	hubClient.PrependReactor("create", "certificatesigningrequests", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		req := action.(k8stesting.CreateAction).GetObject().(*certificatesv1.CertificateSigningRequest)
+		if req.Labels[placementv1beta1.MemberClusterNameLabel] != "member-1" {
+			t.Errorf("csr labels = %v, want the member cluster name label", req.Labels)
+		}
+		block, _ := pem.Decode(req.Spec.Request)
+		if block == nil {
+			t.Fatalf("the csr is not PEM encoded")
+		}
+		request, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			t.Errorf("failed to parse the csr: %v", err)
+		} else if request.Subject.CommonName != testCommonName || len(request.Subject.Organization) != 0 {
+			t.Errorf("csr subject = %v, want common name %s only", request.Subject, testCommonName)
+		}
+		req.Name = req.GenerateName + "abcde"
+		req.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+			{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue, LastUpdateTime: metav1.Now()},
+		}
+		req.Status.Certificate = newCertPEM
+		return false, req, nil
+	})
+
+	r := NewRotator(hubClient, "member-1", certFile, keyFile, time.Hour)
+	if err := r.rotate(context.Background()); err != nil {
+		t.Fatalf("rotate() = %v, want nil", err)
+	}
+	gotCertPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		t.Fatalf("failed to read the certificate: %v", err)
+	}
+	if string(gotCertPEM) != string(newCertPEM) {
+		t.Errorf("certificate is not rotated")
+	}
+	gotKeyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatalf("failed to read the key: %v", err)
+	}
+	if string(gotKeyPEM) == string(oldKeyPEM) {
+		t.Errorf("key is not rotated")
+	}
+}
+
+func TestBootstrapIdentity(t *testing.T) {
+	bootstrapDir := t.TempDir()
+	bootstrapCertFile := filepath.Join(bootstrapDir, "tls.crt")
+	bootstrapKeyFile := filepath.Join(bootstrapDir, "tls.key")
+	now := time.Now()
+	certPEM, keyPEM := newTestCert(t, now, now.Add(time.Hour))
+	if err := os.WriteFile(bootstrapCertFile, certPEM, 0600); err != nil {
+		t.Fatalf("failed to write the certificate: %v", err)
+	}
+	if err := os.WriteFile(bootstrapKeyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("failed to write the key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "identity", "tls.crt")
+	keyFile := filepath.Join(dir, "identity", "tls.key")
+	if err := BootstrapIdentity(certFile, keyFile, bootstrapCertFile, bootstrapKeyFile); err != nil {
+		t.Fatalf("BootstrapIdentity() = %v, want nil", err)
+	}
+	got, err := os.ReadFile(certFile)
+	if err != nil || string(got) != string(certPEM) {
+		t.Fatalf("certificate file = %s, %v, want the bootstrap certificate", got, err)
+	}
+
+	// the existing certificate is not overwritten by the bootstrap one
+	rotatedCertPEM, _ := newTestCert(t, now, now.Add(2*time.Hour))
+	if err := os.WriteFile(certFile, rotatedCertPEM, 0600); err != nil {
+		t.Fatalf("failed to write the certificate: %v", err)
+	}
+	if err := BootstrapIdentity(certFile, keyFile, bootstrapCertFile, bootstrapKeyFile); err != nil {
+		t.Fatalf("BootstrapIdentity() = %v, want nil", err)
+	}
+	got, err = os.ReadFile(certFile)
+	if err != nil || string(got) != string(rotatedCertPEM) {
+		t.Fatalf("certificate file = %s, %v, want the rotated certificate", got, err)
+	}
+}
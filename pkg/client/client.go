@@ -0,0 +1,87 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package client features the typed clients and informers of all the fleet APIs, and the helpers to place resources
+// with the cluster resource placements and track their status, so that the platform teams do not need to access the
+// fleet APIs as unstructured objects.
+//
+// A typical usage is:
+//
+//	c, err := client.New(config)
+//	if err != nil {
+//		return err
+//	}
+//	crp, err := client.ApplyCRPAndWait(ctx, c, &placementv1beta1.ClusterResourcePlacement{...})
+//	if err != nil {
+//		return err
+//	}
+//	statuses, err := client.GetPerClusterStatus(ctx, c, crp.Name)
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "go.goms.io/fleet/apis/cluster/v1"
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1 "go.goms.io/fleet/apis/placement/v1"
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+)
+
+var (
+	// SchemeBuilder registers all the fleet APIs.
+	SchemeBuilder = runtime.NewSchemeBuilder(
+		clusterv1.AddToScheme,
+		clusterv1beta1.AddToScheme,
+		placementv1.AddToScheme,
+		placementv1alpha1.AddToScheme,
+		placementv1beta1.AddToScheme,
+		fleetv1alpha1.AddToScheme,
+	)
+	// AddToScheme adds all the fleet APIs to the scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+
+	// Scheme contains the Kubernetes built-in APIs and all the fleet APIs.
+	Scheme = runtime.NewScheme()
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(AddToScheme(Scheme))
+}
+
+// New returns a typed client of the hub cluster which reads and writes the fleet objects, e.g. the cluster resource
+// placements and the member clusters, as their Go types.
+func New(config *rest.Config) (runtimeclient.Client, error) {
+	c, err := runtimeclient.New(config, runtimeclient.Options{Scheme: Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the fleet client: %w", err)
+	}
+	return c, nil
+}
+
+// NewInformers returns the typed informers of the hub cluster, which are started by Start and looked up by the Go
+// types of the objects, e.g. GetInformer(ctx, &placementv1beta1.ClusterResourcePlacement{}). The informers resync
+// every resyncPeriod; zero means the default period.
+func NewInformers(config *rest.Config, resyncPeriod time.Duration) (cache.Cache, error) {
+	opts := cache.Options{Scheme: Scheme}
+	if resyncPeriod > 0 {
+		opts.SyncPeriod = &resyncPeriod
+	}
+	informers, err := cache.New(config, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the fleet informers: %w", err)
+	}
+	return informers, nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// pollInterval is how often the helpers check the status of a placement.
+var pollInterval = 2 * time.Second
+
+// ClusterStatus is the status of a placement on a member cluster. The conditions which are not observed for the
+// latest generation of the placement are treated as false.
+type ClusterStatus struct {
+	// ClusterName is the name of the member cluster.
+	ClusterName string
+	// RolloutStarted is true if the latest resources are being rolled out to the cluster.
+	RolloutStarted bool
+	// Overridden is true if the overrides are applied to the resources of the cluster.
+	Overridden bool
+	// WorkSynchronized is true if the works of the cluster are up to date.
+	WorkSynchronized bool
+	// Applied is true if the resources are applied on the cluster.
+	Applied bool
+	// Available is true if the resources are available on the cluster.
+	Available bool
+	// FailedPlacements are the resources which fail to apply or are not available on the cluster.
+	FailedPlacements []placementv1beta1.FailedResourcePlacement
+	// FailedPlacementsOverflow is the number of the failed placements which are left out of FailedPlacements.
+	FailedPlacementsOverflow int32
+	// LoadBalancers are the load balancers of the placed services of the LoadBalancer type on the cluster.
+	LoadBalancers []placementv1beta1.PlacedLoadBalancer
+	// Conditions are the conditions of the placement on the cluster.
+	Conditions []metav1.Condition
+}
+
+// GetPerClusterStatus returns the status of the placement on each member cluster it selects, keyed by the names of
+// the clusters. If the status of the placement is compacted, the statuses are read from its
+// PerClusterPlacementStatuses.
+func GetPerClusterStatus(ctx context.Context, c runtimeclient.Reader, name string) (map[string]ClusterStatus, error) {
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, &crp); err != nil {
+		return nil, err
+	}
+	placementStatuses := crp.Status.PlacementStatuses
+	if crp.Status.PlacementStatusSummary != nil {
+		var perClusterStatuses placementv1beta1.PerClusterPlacementStatusList
+		if err := c.List(ctx, &perClusterStatuses, runtimeclient.MatchingLabels{placementv1beta1.CRPTrackingLabel: name}); err != nil {
+			return nil, err
+		}
+		placementStatuses = make([]placementv1beta1.ResourcePlacementStatus, 0, len(perClusterStatuses.Items))
+		for i := range perClusterStatuses.Items {
+			placementStatuses = append(placementStatuses, perClusterStatuses.Items[i].PlacementStatus)
+		}
+	}
+	return perClusterStatus(&crp, placementStatuses), nil
+}
+
+func perClusterStatus(crp *placementv1beta1.ClusterResourcePlacement, placementStatuses []placementv1beta1.ResourcePlacementStatus) map[string]ClusterStatus {
+	statuses := make(map[string]ClusterStatus, len(placementStatuses))
+	for _, placementStatus := range placementStatuses {
+		// the placement statuses without cluster names report the clusters which cannot be scheduled
+		if placementStatus.ClusterName == "" {
+			continue
+		}
+		isTrue := func(conditionType placementv1beta1.ResourcePlacementConditionType) bool {
+			return condition.IsConditionStatusTrue(meta.FindStatusCondition(placementStatus.Conditions, string(conditionType)), crp.Generation)
+		}
+		statuses[placementStatus.ClusterName] = ClusterStatus{
+			ClusterName:              placementStatus.ClusterName,
+			RolloutStarted:           isTrue(placementv1beta1.ResourceRolloutStartedConditionType),
+			Overridden:               isTrue(placementv1beta1.ResourceOverriddenConditionType),
+			WorkSynchronized:         isTrue(placementv1beta1.ResourceWorkSynchronizedConditionType),
+			Applied:                  isTrue(placementv1beta1.ResourcesAppliedConditionType),
+			Available:                isTrue(placementv1beta1.ResourcesAvailableConditionType),
+			FailedPlacements:         placementStatus.FailedPlacements,
+			FailedPlacementsOverflow: placementStatus.FailedPlacementsOverflow,
+			LoadBalancers:            placementStatus.LoadBalancers,
+			Conditions:               placementStatus.Conditions,
+		}
+	}
+	return statuses
+}
+
+// WaitForCRPAvailable waits until the resources of the placement are available on all the selected clusters for its
+// latest generation, and returns the placement. It waits until the context is done, and the returned error then
+// describes why the placement is not available yet.
+func WaitForCRPAvailable(ctx context.Context, c runtimeclient.Reader, name string) (*placementv1beta1.ClusterResourcePlacement, error) {
+	crp := &placementv1beta1.ClusterResourcePlacement{}
+	var notAvailableErr error
+	err := wait.PollUntilContextCancel(ctx, pollInterval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, crp); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, err
+			}
+			// retry the transient errors
+			notAvailableErr = err
+			return false, nil
+		}
+		if crp.DeletionTimestamp != nil {
+			return false, fmt.Errorf("clusterResourcePlacement %s is being deleted", name)
+		}
+		if condition.IsConditionStatusTrue(crp.GetCondition(string(placementv1beta1.ClusterResourcePlacementAvailableConditionType)), crp.Generation) {
+			return true, nil
+		}
+		notAvailableErr = notAvailableReason(crp)
+		return false, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil && notAvailableErr != nil {
+			return crp, fmt.Errorf("clusterResourcePlacement %s is not available: %w", name, notAvailableErr)
+		}
+		return crp, err
+	}
+	return crp, nil
+}
+
+// notAvailableReason returns the first condition of the placement which is not true for its latest generation.
+func notAvailableReason(crp *placementv1beta1.ClusterResourcePlacement) error {
+	for _, conditionType := range []placementv1beta1.ClusterResourcePlacementConditionType{
+		placementv1beta1.ClusterResourcePlacementScheduledConditionType,
+		placementv1beta1.ClusterResourcePlacementRolloutStartedConditionType,
+		placementv1beta1.ClusterResourcePlacementOverriddenConditionType,
+		placementv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType,
+		placementv1beta1.ClusterResourcePlacementAppliedConditionType,
+		placementv1beta1.ClusterResourcePlacementAvailableConditionType,
+	} {
+		cond := crp.GetCondition(string(conditionType))
+		if cond == nil || cond.ObservedGeneration != crp.Generation {
+			return fmt.Errorf("condition %s is not observed for generation %d yet", conditionType, crp.Generation)
+		}
+		if cond.Status != metav1.ConditionTrue {
+			return fmt.Errorf("condition %s is %s: %s", conditionType, cond.Status, cond.Message)
+		}
+	}
+	return fmt.Errorf("condition %s is not true", placementv1beta1.ClusterResourcePlacementAvailableConditionType)
+}
+
+// ApplyCRPAndWait creates the placement, or updates the spec, labels and annotations of the existing one, and waits
+// until the resources are available on all the selected clusters. It returns the placement with its latest status.
+func ApplyCRPAndWait(ctx context.Context, c runtimeclient.Client, crp *placementv1beta1.ClusterResourcePlacement) (*placementv1beta1.ClusterResourcePlacement, error) {
+	if err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		var current placementv1beta1.ClusterResourcePlacement
+		if err := c.Get(ctx, types.NamespacedName{Name: crp.Name}, &current); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			return c.Create(ctx, crp.DeepCopy())
+		}
+		current.Spec = *crp.Spec.DeepCopy()
+		current.Labels = mergeStringMap(current.Labels, crp.Labels)
+		current.Annotations = mergeStringMap(current.Annotations, crp.Annotations)
+		return c.Update(ctx, &current)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to apply clusterResourcePlacement %s: %w", crp.Name, err)
+	}
+	return WaitForCRPAvailable(ctx, c, crp.Name)
+}
+
+func mergeStringMap(current, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		return current
+	}
+	if current == nil {
+		current = make(map[string]string, len(desired))
+	}
+	for k, v := range desired {
+		current[k] = v
+	}
+	return current
+}
@@ -0,0 +1,258 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const testCRPName = "app"
+
+func init() {
+	pollInterval = 10 * time.Millisecond
+}
+
+func testCondition(conditionType string, status metav1.ConditionStatus, generation int64) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status, ObservedGeneration: generation, Reason: "TestReason", Message: "test message"}
+}
+
+func availableConditions(generation int64) []metav1.Condition {
+	var conditions []metav1.Condition
+	for _, conditionType := range []placementv1beta1.ClusterResourcePlacementConditionType{
+		placementv1beta1.ClusterResourcePlacementScheduledConditionType,
+		placementv1beta1.ClusterResourcePlacementRolloutStartedConditionType,
+		placementv1beta1.ClusterResourcePlacementOverriddenConditionType,
+		placementv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType,
+		placementv1beta1.ClusterResourcePlacementAppliedConditionType,
+		placementv1beta1.ClusterResourcePlacementAvailableConditionType,
+	} {
+		conditions = append(conditions, testCondition(string(conditionType), metav1.ConditionTrue, generation))
+	}
+	return conditions
+}
+
+func placement(generation int64, conditions []metav1.Condition) *placementv1beta1.ClusterResourcePlacement {
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: testCRPName, Generation: generation},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "", Version: "v1", Kind: "Namespace", Name: "app"}},
+		},
+		Status: placementv1beta1.ClusterResourcePlacementStatus{Conditions: conditions},
+	}
+}
+
+func newFakeClient(objs ...runtimeclient.Object) runtimeclient.Client {
+	return fake.NewClientBuilder().WithScheme(Scheme).WithObjects(objs...).
+		WithStatusSubresource(&placementv1beta1.ClusterResourcePlacement{}).Build()
+}
+
+func TestGetPerClusterStatus(t *testing.T) {
+	crp := placement(2, nil)
+	crp.Status.PlacementStatuses = []placementv1beta1.ResourcePlacementStatus{
+		{
+			ClusterName: "member-1",
+			Conditions: []metav1.Condition{
+				testCondition(string(placementv1beta1.ResourceRolloutStartedConditionType), metav1.ConditionTrue, 2),
+				testCondition(string(placementv1beta1.ResourceOverriddenConditionType), metav1.ConditionTrue, 2),
+				testCondition(string(placementv1beta1.ResourceWorkSynchronizedConditionType), metav1.ConditionTrue, 2),
+				testCondition(string(placementv1beta1.ResourcesAppliedConditionType), metav1.ConditionTrue, 2),
+				testCondition(string(placementv1beta1.ResourcesAvailableConditionType), metav1.ConditionTrue, 2),
+			},
+		},
+		{
+			ClusterName: "member-2",
+			Conditions: []metav1.Condition{
+				testCondition(string(placementv1beta1.ResourceRolloutStartedConditionType), metav1.ConditionTrue, 2),
+				testCondition(string(placementv1beta1.ResourcesAppliedConditionType), metav1.ConditionFalse, 2),
+				// stale
+				testCondition(string(placementv1beta1.ResourcesAvailableConditionType), metav1.ConditionTrue, 1),
+			},
+			FailedPlacements: []placementv1beta1.FailedResourcePlacement{{
+				ResourceIdentifier: placementv1beta1.ResourceIdentifier{Version: "v1", Kind: "ConfigMap", Name: "settings", Namespace: "app"},
+			}},
+		},
+		{
+			// the unscheduled clusters have no names
+			Conditions: []metav1.Condition{testCondition(string(placementv1beta1.ResourceScheduledConditionType), metav1.ConditionFalse, 2)},
+		},
+	}
+	want := map[string]ClusterStatus{
+		"member-1": {
+			ClusterName:      "member-1",
+			RolloutStarted:   true,
+			Overridden:       true,
+			WorkSynchronized: true,
+			Applied:          true,
+			Available:        true,
+		},
+		"member-2": {
+			ClusterName:    "member-2",
+			RolloutStarted: true,
+			FailedPlacements: []placementv1beta1.FailedResourcePlacement{{
+				ResourceIdentifier: placementv1beta1.ResourceIdentifier{Version: "v1", Kind: "ConfigMap", Name: "settings", Namespace: "app"},
+			}},
+		},
+	}
+
+	got, err := GetPerClusterStatus(context.Background(), newFakeClient(crp), testCRPName)
+	if err != nil {
+		t.Fatalf("GetPerClusterStatus() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(ClusterStatus{}, "Conditions")); diff != "" {
+		t.Errorf("GetPerClusterStatus() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGetPerClusterStatus_Compacted(t *testing.T) {
+	crp := placement(2, nil)
+	unhealthy := placementv1beta1.ResourcePlacementStatus{
+		ClusterName: "member-2",
+		Conditions:  []metav1.Condition{testCondition(string(placementv1beta1.ResourcesAppliedConditionType), metav1.ConditionFalse, 2)},
+	}
+	crp.Status.PlacementStatuses = []placementv1beta1.ResourcePlacementStatus{unhealthy}
+	crp.Status.PlacementStatusSummary = &placementv1beta1.PlacementStatusSummary{SelectedClusters: 2, UnhealthyClusters: 1}
+	perClusterStatus := func(status placementv1beta1.ResourcePlacementStatus) *placementv1beta1.PerClusterPlacementStatus {
+		return &placementv1beta1.PerClusterPlacementStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testCRPName,
+				Namespace: "fleet-member-" + status.ClusterName,
+				Labels:    map[string]string{placementv1beta1.CRPTrackingLabel: testCRPName},
+			},
+			PlacementStatus: status,
+		}
+	}
+	healthy := placementv1beta1.ResourcePlacementStatus{
+		ClusterName: "member-1",
+		Conditions:  []metav1.Condition{testCondition(string(placementv1beta1.ResourcesAppliedConditionType), metav1.ConditionTrue, 2)},
+	}
+	want := map[string]ClusterStatus{
+		"member-1": {ClusterName: "member-1", Applied: true},
+		"member-2": {ClusterName: "member-2"},
+	}
+
+	got, err := GetPerClusterStatus(context.Background(), newFakeClient(crp, perClusterStatus(healthy), perClusterStatus(unhealthy)), testCRPName)
+	if err != nil {
+		t.Fatalf("GetPerClusterStatus() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(ClusterStatus{}, "Conditions")); diff != "" {
+		t.Errorf("GetPerClusterStatus() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWaitForCRPAvailable(t *testing.T) {
+	// the available condition is not reported when the resources fail to apply
+	notAvailable := availableConditions(2)[:5]
+	notAvailable[4] = testCondition(string(placementv1beta1.ClusterResourcePlacementAppliedConditionType), metav1.ConditionFalse, 2)
+	tests := map[string]struct {
+		crp     *placementv1beta1.ClusterResourcePlacement
+		wantErr string
+	}{
+		"available": {
+			crp: placement(2, availableConditions(2)),
+		},
+		"available for an older generation": {
+			crp:     placement(2, availableConditions(1)),
+			wantErr: "condition ClusterResourcePlacementScheduled is not observed for generation 2 yet",
+		},
+		"failed to apply": {
+			crp:     placement(2, notAvailable),
+			wantErr: "condition ClusterResourcePlacementApplied is False: test message",
+		},
+		"not found": {
+			wantErr: "not found",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var objs []runtimeclient.Object
+			if tc.crp != nil {
+				objs = append(objs, tc.crp)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			_, err := WaitForCRPAvailable(ctx, newFakeClient(objs...), testCRPName)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("WaitForCRPAvailable() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("WaitForCRPAvailable() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyCRPAndWait(t *testing.T) {
+	tests := map[string]struct {
+		existing *placementv1beta1.ClusterResourcePlacement
+	}{
+		"create": {},
+		"update": {
+			existing: &placementv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{Name: testCRPName, Labels: map[string]string{"team": "platform"}},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "", Version: "v1", Kind: "Namespace", Name: "old"}},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var objs []runtimeclient.Object
+			if tc.existing != nil {
+				objs = append(objs, tc.existing)
+			}
+			c := newFakeClient(objs...)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// report the placement as available once the spec is applied, as the hub controllers do
+			go func() {
+				for ctx.Err() == nil {
+					var crp placementv1beta1.ClusterResourcePlacement
+					if err := c.Get(ctx, types.NamespacedName{Name: testCRPName}, &crp); err == nil && crp.Spec.ResourceSelectors[0].Name == "app" {
+						crp.Status.Conditions = availableConditions(crp.Generation)
+						if err := c.Status().Update(ctx, &crp); err == nil {
+							return
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+
+			desired := placement(0, nil)
+			desired.Labels = map[string]string{"app": "web"}
+			got, err := ApplyCRPAndWait(ctx, c, desired)
+			if err != nil {
+				t.Fatalf("ApplyCRPAndWait() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(desired.Spec, got.Spec); diff != "" {
+				t.Errorf("ApplyCRPAndWait() spec mismatch (-want, +got):\n%s", diff)
+			}
+			wantLabels := map[string]string{"app": "web"}
+			if tc.existing != nil {
+				wantLabels["team"] = "platform"
+			}
+			if diff := cmp.Diff(wantLabels, got.Labels); diff != "" {
+				t.Errorf("ApplyCRPAndWait() labels mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
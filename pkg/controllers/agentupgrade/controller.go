@@ -0,0 +1,325 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package agentupgrade features a controller that rolls out a version of the member agent to the member clusters in
+// stages, by asking the member agents of each stage to upgrade themselves through their internal member clusters.
+package agentupgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/clustergroup"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// The reasons of the Succeeded condition of an agent upgrade.
+	conditionReasonInvalidStages  = "InvalidStages"
+	conditionReasonStageUpgrading = "StageUpgrading"
+	conditionReasonUpgraded       = "Upgraded"
+)
+
+// Reconciler reconciles an agent upgrade. It asks the member agents of the first stage which is not upgraded yet to
+// upgrade themselves, at most the max concurrency of the stage at a time, and records the progress in the status.
+type Reconciler struct {
+	Client client.Client
+}
+
+// Reconcile advances the agent upgrade as the member agents of its stages are upgraded.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("AgentUpgrade reconciliation starts", "agentUpgrade", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("AgentUpgrade reconciliation ends", "agentUpgrade", req.Name, "latency", latency)
+	}()
+
+	var upgrade clusterv1beta1.AgentUpgrade
+	if err := r.Client.Get(ctx, req.NamespacedName, &upgrade); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the agent upgrade", "agentUpgrade", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !upgrade.DeletionTimestamp.IsZero() {
+		// the member agents which have been asked to upgrade still do, as the upgrades are not rolled back
+		return ctrl.Result{}, nil
+	}
+
+	status := clusterv1beta1.AgentUpgradeStatus{
+		ObservedGeneration: upgrade.Generation,
+		// keep the last transition time of the condition if its status is not changed
+		Conditions: upgrade.Status.DeepCopy().Conditions,
+	}
+	stageClusters, err := r.assignStages(ctx, &upgrade)
+	switch {
+	case errors.Is(err, controller.ErrUserError):
+		klog.ErrorS(err, "Invalid agent upgrade", "agentUpgrade", req.Name)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               string(clusterv1beta1.AgentUpgradeConditionTypeSucceeded),
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionReasonInvalidStages,
+			Message:            err.Error(),
+			ObservedGeneration: upgrade.Generation,
+		})
+		return ctrl.Result{}, r.updateStatus(ctx, &upgrade, status)
+	case err != nil:
+		return ctrl.Result{}, err
+	}
+
+	upgradingStage := ""
+	for i, stage := range upgrade.Spec.Stages {
+		stageStatus := clusterv1beta1.AgentUpgradeStageStatus{
+			StageName: stage.Name,
+			Clusters:  clusterNames(stageClusters[i]),
+		}
+		var pending []*clusterv1beta1.MemberCluster
+		for _, cluster := range stageClusters[i] {
+			if isUpgraded(cluster, upgrade.Spec.Version) {
+				stageStatus.UpgradedClusters = append(stageStatus.UpgradedClusters, cluster.Name)
+			} else {
+				pending = append(pending, cluster)
+			}
+		}
+		switch {
+		case upgradingStage != "":
+			// the stage waits for the earlier stages even if its member agents are upgraded by other means
+			stageStatus.State = clusterv1beta1.AgentUpgradeStageStatePending
+		case len(pending) == 0:
+			stageStatus.State = clusterv1beta1.AgentUpgradeStageStateSucceeded
+		default:
+			stageStatus.State = clusterv1beta1.AgentUpgradeStageStateUpgrading
+			upgradingStage = stage.Name
+			if err := r.requestUpgrades(ctx, &upgrade, stage, pending); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		status.Stages = append(status.Stages, stageStatus)
+	}
+
+	succeeded := metav1.Condition{
+		Type:               string(clusterv1beta1.AgentUpgradeConditionTypeSucceeded),
+		Status:             metav1.ConditionTrue,
+		Reason:             conditionReasonUpgraded,
+		Message:            fmt.Sprintf("The member agents of all the stages run version %s", upgrade.Spec.Version),
+		ObservedGeneration: upgrade.Generation,
+	}
+	if upgradingStage != "" {
+		succeeded.Status = metav1.ConditionFalse
+		succeeded.Reason = conditionReasonStageUpgrading
+		succeeded.Message = fmt.Sprintf("The member agents of stage %s are being upgraded to version %s", upgradingStage, upgrade.Spec.Version)
+	}
+	meta.SetStatusCondition(&status.Conditions, succeeded)
+	return ctrl.Result{}, r.updateStatus(ctx, &upgrade, status)
+}
+
+// assignStages returns the member clusters of each stage of the upgrade, sorted by their names. A cluster belongs to
+// the first stage which selects it, and the clusters which are leaving the fleet belong to no stage.
+func (r *Reconciler) assignStages(ctx context.Context, upgrade *clusterv1beta1.AgentUpgrade) ([][]*clusterv1beta1.MemberCluster, error) {
+	selectors := make([]labels.Selector, len(upgrade.Spec.Stages))
+	for i := range upgrade.Spec.Stages {
+		selector, err := stageSelector(&upgrade.Spec.Stages[i])
+		if err != nil {
+			return nil, controller.NewUserError(err)
+		}
+		selectors[i] = selector
+	}
+
+	var clusterList clusterv1beta1.MemberClusterList
+	if err := r.Client.List(ctx, &clusterList); err != nil {
+		klog.ErrorS(err, "Failed to list the member clusters", "agentUpgrade", klog.KObj(upgrade))
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	sort.Slice(clusterList.Items, func(i, j int) bool {
+		return clusterList.Items[i].Name < clusterList.Items[j].Name
+	})
+	stageClusters := make([][]*clusterv1beta1.MemberCluster, len(upgrade.Spec.Stages))
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for j, selector := range selectors {
+			if selector.Matches(labels.Set(cluster.Labels)) {
+				stageClusters[j] = append(stageClusters[j], cluster)
+				break
+			}
+		}
+	}
+	return stageClusters, nil
+}
+
+// stageSelector returns the selector of the member clusters of the stage.
+func stageSelector(stage *clusterv1beta1.AgentUpgradeStage) (labels.Selector, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&stage.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector of stage %s: %w", stage.Name, err)
+	}
+	if stage.ClusterGroup == "" {
+		return selector, nil
+	}
+	requirement, err := clustergroup.Requirement(stage.ClusterGroup)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster group of stage %s: %w", stage.Name, err)
+	}
+	return selector.Add(*requirement), nil
+}
+
+// isUpgraded tells if the member agent of the cluster runs the version, and is joined and healthy.
+func isUpgraded(cluster *clusterv1beta1.MemberCluster, version string) bool {
+	agentStatus := cluster.GetAgentStatus(clusterv1beta1.MemberAgent)
+	if agentStatus == nil || agentStatus.Version != version {
+		return false
+	}
+	return meta.IsStatusConditionTrue(agentStatus.Conditions, string(clusterv1beta1.AgentJoined)) &&
+		meta.IsStatusConditionTrue(agentStatus.Conditions, string(clusterv1beta1.AgentHealthy))
+}
+
+// requestUpgrades asks the member agents of the clusters of the stage which are not upgraded yet to upgrade
+// themselves, so that at most the max concurrency of the stage are being upgraded at a time. A member agent which has
+// been asked to upgrade but is not joined and healthy with the version, e.g. as its new image cannot be pulled, keeps
+// counting against the max concurrency, which holds the upgrade back until it recovers.
+func (r *Reconciler) requestUpgrades(ctx context.Context, upgrade *clusterv1beta1.AgentUpgrade, stage clusterv1beta1.AgentUpgradeStage, pending []*clusterv1beta1.MemberCluster) error {
+	maxConcurrency := int(stage.MaxConcurrency)
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+	var toRequest []*clusterv1beta1.InternalMemberCluster
+	inProgress := 0
+	for _, cluster := range pending {
+		var imc clusterv1beta1.InternalMemberCluster
+		key := types.NamespacedName{Namespace: fmt.Sprintf(utils.NamespaceNameFormat, cluster.Name), Name: cluster.Name}
+		if err := r.Client.Get(ctx, key, &imc); err != nil {
+			if apierrors.IsNotFound(err) {
+				// the cluster is joining; it is upgraded once its internal member cluster is created
+				continue
+			}
+			klog.ErrorS(err, "Failed to get the internal member cluster", "agentUpgrade", klog.KObj(upgrade), "internalMemberCluster", key)
+			return controller.NewAPIServerError(true, err)
+		}
+		if imc.Spec.AgentUpgrade != nil && *imc.Spec.AgentUpgrade == upgrade.Spec.AgentUpgradeTarget {
+			inProgress++
+			continue
+		}
+		toRequest = append(toRequest, &imc)
+	}
+	for _, imc := range toRequest {
+		if inProgress >= maxConcurrency {
+			break
+		}
+		target := upgrade.Spec.AgentUpgradeTarget
+		imc.Spec.AgentUpgrade = &target
+		if err := r.Client.Update(ctx, imc); err != nil {
+			klog.ErrorS(err, "Failed to request the member agent upgrade", "agentUpgrade", klog.KObj(upgrade), "internalMemberCluster", klog.KObj(imc))
+			return controller.NewUpdateIgnoreConflictError(err)
+		}
+		klog.V(2).InfoS("Requested the member agent upgrade", "agentUpgrade", klog.KObj(upgrade), "internalMemberCluster", klog.KObj(imc), "version", target.Version)
+		inProgress++
+	}
+	return nil
+}
+
+// updateStatus updates the status of the upgrade if it is changed.
+func (r *Reconciler) updateStatus(ctx context.Context, upgrade *clusterv1beta1.AgentUpgrade, status clusterv1beta1.AgentUpgradeStatus) error {
+	if equality.Semantic.DeepEqual(upgrade.Status, status) {
+		return nil
+	}
+	upgrade.Status = status
+	if err := r.Client.Status().Update(ctx, upgrade); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the agent upgrade", "agentUpgrade", klog.KObj(upgrade))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return nil
+}
+
+// clusterNames returns the names of the clusters.
+func clusterNames(clusters []*clusterv1beta1.MemberCluster) []string {
+	var names []string
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
+
+// SetupWithManager sets up the controller with the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("agent-upgrade-controller").
+		For(&clusterv1beta1.AgentUpgrade{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the stages advance as the member agents report their versions, and change as the clusters join, leave or
+		// are relabeled
+		Watches(&clusterv1beta1.MemberCluster{}, handler.EnqueueRequestsFromMapFunc(r.allUpgrades), builder.WithPredicates(upgradeStateChangedPredicate)).
+		Complete(r)
+}
+
+// upgradeStateChangedPredicate filters the member cluster events which may advance the agent upgrades, ignoring e.g.
+// the heartbeats and the property updates.
+var upgradeStateChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCluster, oldOK := e.ObjectOld.(*clusterv1beta1.MemberCluster)
+		newCluster, newOK := e.ObjectNew.(*clusterv1beta1.MemberCluster)
+		if !oldOK || !newOK {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
+			oldCluster.DeletionTimestamp.IsZero() != newCluster.DeletionTimestamp.IsZero() ||
+			upgradeState(oldCluster) != upgradeState(newCluster)
+	},
+}
+
+// agentState is the state of a member agent which decides if it is upgraded.
+type agentState struct {
+	version string
+	joined  bool
+	healthy bool
+}
+
+// upgradeState returns the state of the member agent of the cluster.
+func upgradeState(cluster *clusterv1beta1.MemberCluster) agentState {
+	agentStatus := cluster.GetAgentStatus(clusterv1beta1.MemberAgent)
+	if agentStatus == nil {
+		return agentState{}
+	}
+	return agentState{
+		version: agentStatus.Version,
+		joined:  meta.IsStatusConditionTrue(agentStatus.Conditions, string(clusterv1beta1.AgentJoined)),
+		healthy: meta.IsStatusConditionTrue(agentStatus.Conditions, string(clusterv1beta1.AgentHealthy)),
+	}
+}
+
+// allUpgrades maps a member cluster to all the agent upgrades.
+func (r *Reconciler) allUpgrades(ctx context.Context, _ client.Object) []reconcile.Request {
+	var upgradeList clusterv1beta1.AgentUpgradeList
+	if err := r.Client.List(ctx, &upgradeList); err != nil {
+		klog.ErrorS(err, "Failed to list the agent upgrades")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(upgradeList.Items))
+	for i := range upgradeList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&upgradeList.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,213 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package agentupgrade
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+const (
+	upgradeName = "upgrade-v2"
+	oldVersion  = "v1"
+	newVersion  = "v2"
+)
+
+var target = clusterv1beta1.AgentUpgradeTarget{Version: newVersion, Image: "example.azurecr.io/member-agent:v2"}
+
+func newCluster(name string, labels map[string]string, version string) *clusterv1beta1.MemberCluster {
+	return &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: clusterv1beta1.MemberClusterStatus{
+			AgentStatus: []clusterv1beta1.AgentStatus{
+				{
+					Type:    clusterv1beta1.MemberAgent,
+					Version: version,
+					Conditions: []metav1.Condition{
+						{Type: string(clusterv1beta1.AgentJoined), Status: metav1.ConditionTrue, Reason: "Joined"},
+						{Type: string(clusterv1beta1.AgentHealthy), Status: metav1.ConditionTrue, Reason: "Healthy"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newIMC(name string, agentUpgrade *clusterv1beta1.AgentUpgradeTarget) *clusterv1beta1.InternalMemberCluster {
+	return &clusterv1beta1.InternalMemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: fmt.Sprintf(utils.NamespaceNameFormat, name)},
+		Spec:       clusterv1beta1.InternalMemberClusterSpec{AgentUpgrade: agentUpgrade},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	canary := map[string]string{"ring": "canary"}
+	upgrade := &clusterv1beta1.AgentUpgrade{
+		ObjectMeta: metav1.ObjectMeta{Name: upgradeName, Generation: 1},
+		Spec: clusterv1beta1.AgentUpgradeSpec{
+			AgentUpgradeTarget: target,
+			Stages: []clusterv1beta1.AgentUpgradeStage{
+				{Name: "canary", LabelSelector: metav1.LabelSelector{MatchLabels: canary}, MaxConcurrency: 1},
+				{Name: "prod", MaxConcurrency: 2},
+			},
+		},
+	}
+	tests := map[string]struct {
+		objects []client.Object
+		// wantRequested are the clusters asked to upgrade after the reconciliation.
+		wantRequested []string
+		wantStages    []clusterv1beta1.AgentUpgradeStageStatus
+		wantSucceeded metav1.ConditionStatus
+	}{
+		"the first stage is upgraded one cluster at a time": {
+			objects: []client.Object{
+				newCluster("canary-1", canary, oldVersion), newIMC("canary-1", nil),
+				newCluster("canary-2", canary, oldVersion), newIMC("canary-2", nil),
+				newCluster("prod-1", nil, oldVersion), newIMC("prod-1", nil),
+			},
+			wantRequested: []string{"canary-1"},
+			wantStages: []clusterv1beta1.AgentUpgradeStageStatus{
+				{StageName: "canary", Clusters: []string{"canary-1", "canary-2"}, State: clusterv1beta1.AgentUpgradeStageStateUpgrading},
+				{StageName: "prod", Clusters: []string{"prod-1"}, State: clusterv1beta1.AgentUpgradeStageStatePending},
+			},
+			wantSucceeded: metav1.ConditionFalse,
+		},
+		"the next cluster waits for the one being upgraded": {
+			objects: []client.Object{
+				newCluster("canary-1", canary, oldVersion), newIMC("canary-1", &target),
+				newCluster("canary-2", canary, oldVersion), newIMC("canary-2", nil),
+			},
+			wantRequested: []string{"canary-1"},
+			wantStages: []clusterv1beta1.AgentUpgradeStageStatus{
+				{StageName: "canary", Clusters: []string{"canary-1", "canary-2"}, State: clusterv1beta1.AgentUpgradeStageStateUpgrading},
+				{StageName: "prod", State: clusterv1beta1.AgentUpgradeStageStatePending},
+			},
+			wantSucceeded: metav1.ConditionFalse,
+		},
+		"the next stage starts once the first stage is upgraded": {
+			objects: []client.Object{
+				newCluster("canary-1", canary, newVersion), newIMC("canary-1", &target),
+				newCluster("prod-1", nil, oldVersion), newIMC("prod-1", nil),
+				newCluster("prod-2", nil, oldVersion), newIMC("prod-2", nil),
+				newCluster("prod-3", nil, oldVersion), newIMC("prod-3", nil),
+			},
+			wantRequested: []string{"canary-1", "prod-1", "prod-2"},
+			wantStages: []clusterv1beta1.AgentUpgradeStageStatus{
+				{StageName: "canary", Clusters: []string{"canary-1"}, UpgradedClusters: []string{"canary-1"}, State: clusterv1beta1.AgentUpgradeStageStateSucceeded},
+				{StageName: "prod", Clusters: []string{"prod-1", "prod-2", "prod-3"}, State: clusterv1beta1.AgentUpgradeStageStateUpgrading},
+			},
+			wantSucceeded: metav1.ConditionFalse,
+		},
+		"all the stages are upgraded": {
+			objects: []client.Object{
+				newCluster("canary-1", canary, newVersion), newIMC("canary-1", &target),
+				newCluster("prod-1", nil, newVersion), newIMC("prod-1", &target),
+			},
+			wantRequested: []string{"canary-1", "prod-1"},
+			wantStages: []clusterv1beta1.AgentUpgradeStageStatus{
+				{StageName: "canary", Clusters: []string{"canary-1"}, UpgradedClusters: []string{"canary-1"}, State: clusterv1beta1.AgentUpgradeStageStateSucceeded},
+				{StageName: "prod", Clusters: []string{"prod-1"}, UpgradedClusters: []string{"prod-1"}, State: clusterv1beta1.AgentUpgradeStageStateSucceeded},
+			},
+			wantSucceeded: metav1.ConditionTrue,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tc.objects, upgrade.DeepCopy())...).
+				WithStatusSubresource(&clusterv1beta1.AgentUpgrade{}).Build()
+			r := &Reconciler{Client: fakeClient}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: upgradeName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want nil", err)
+			}
+
+			var imcList clusterv1beta1.InternalMemberClusterList
+			if err := fakeClient.List(context.Background(), &imcList); err != nil {
+				t.Fatalf("List() = %v, want nil", err)
+			}
+			var gotRequested []string
+			for _, imc := range imcList.Items {
+				if imc.Spec.AgentUpgrade != nil && *imc.Spec.AgentUpgrade == target {
+					gotRequested = append(gotRequested, imc.Name)
+				}
+			}
+			if diff := cmp.Diff(tc.wantRequested, gotRequested); diff != "" {
+				t.Errorf("requested upgrades mismatch (-want, +got):\n%s", diff)
+			}
+
+			var got clusterv1beta1.AgentUpgrade
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: upgradeName}, &got); err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.wantStages, got.Status.Stages); diff != "" {
+				t.Errorf("stage status mismatch (-want, +got):\n%s", diff)
+			}
+			cond := got.Status.Conditions
+			if len(cond) != 1 || cond[0].Status != tc.wantSucceeded {
+				t.Errorf("conditions = %v, want a Succeeded condition of status %s", cond, tc.wantSucceeded)
+			}
+		})
+	}
+}
+
+func TestStageSelector(t *testing.T) {
+	tests := map[string]struct {
+		stage         clusterv1beta1.AgentUpgradeStage
+		clusterLabels map[string]string
+		want          bool
+		wantErr       bool
+	}{
+		"an empty selector selects all the clusters": {
+			clusterLabels: map[string]string{"env": "prod"},
+			want:          true,
+		},
+		"the cluster is in the group": {
+			stage:         clusterv1beta1.AgentUpgradeStage{ClusterGroup: "eu", LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+			clusterLabels: map[string]string{"env": "prod", clusterv1beta1.ClusterGroupLabelPrefix + "eu": "true"},
+			want:          true,
+		},
+		"the cluster is not in the group": {
+			stage:         clusterv1beta1.AgentUpgradeStage{ClusterGroup: "eu"},
+			clusterLabels: map[string]string{"env": "prod"},
+		},
+		"invalid label selector": {
+			stage: clusterv1beta1.AgentUpgradeStage{LabelSelector: metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}},
+			}},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector, err := stageSelector(&tc.stage)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("stageSelector() = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := selector.Matches(labels.Set(tc.clusterLabels)); got != tc.want {
+				t.Errorf("stageSelector().Matches() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package argocdhealth features a controller that bridges the per-cluster placement status into the resource tree of
+// Argo CD when Argo CD manages the placements on the hub cluster.
+package argocdhealth
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// Reconciler adds an owner reference to the placement on each of its bindings. Argo CD builds the resource tree of an
+// application from the owner references, so that it shows the bindings, and their health per cluster, as the children
+// of the placement it manages.
+//
+// The owner reference is neither a controller reference nor blocks the owner deletion, as the lifecycle of the
+// bindings is still managed by the scheduler.
+type Reconciler struct {
+	Client client.Client
+}
+
+// Reconcile makes sure that the binding is owned by its placement.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("ArgoCDHealth reconciliation starts", "clusterResourceBinding", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ArgoCDHealth reconciliation ends", "clusterResourceBinding", req.Name, "latency", latency)
+	}()
+
+	var binding placementv1beta1.ClusterResourceBinding
+	if err := r.Client.Get(ctx, req.NamespacedName, &binding); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the binding", "clusterResourceBinding", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	crpName := binding.Labels[placementv1beta1.CRPTrackingLabel]
+	if !binding.DeletionTimestamp.IsZero() || crpName == "" {
+		return ctrl.Result{}, nil
+	}
+
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: crpName}, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(2).InfoS("The placement of the binding is gone", "clusterResourceBinding", req.Name, "clusterResourcePlacement", crpName)
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the placement", "clusterResourcePlacement", crpName)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !setPlacementOwnerReference(&binding, &crp) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Client.Update(ctx, &binding); err != nil {
+		klog.ErrorS(err, "Failed to add the placement owner reference to the binding", "clusterResourceBinding", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	klog.V(2).InfoS("Added the placement owner reference to the binding", "clusterResourceBinding", req.Name, "clusterResourcePlacement", crpName)
+	return ctrl.Result{}, nil
+}
+
+// setPlacementOwnerReference sets the owner reference to the placement on the binding, replacing the one to a former
+// placement with the same name. It returns whether the binding is changed.
+func setPlacementOwnerReference(binding *placementv1beta1.ClusterResourceBinding, crp *placementv1beta1.ClusterResourcePlacement) bool {
+	ownerRef := metav1.OwnerReference{
+		APIVersion: placementv1beta1.GroupVersion.String(),
+		Kind:       placementv1beta1.ClusterResourcePlacementKind,
+		Name:       crp.Name,
+		UID:        crp.UID,
+	}
+	ownerRefs := binding.GetOwnerReferences()
+	for i := range ownerRefs {
+		if ownerRefs[i].APIVersion != ownerRef.APIVersion || ownerRefs[i].Kind != ownerRef.Kind || ownerRefs[i].Name != ownerRef.Name {
+			continue
+		}
+		if ownerRefs[i].UID == ownerRef.UID {
+			return false
+		}
+		ownerRefs[i] = ownerRef
+		binding.SetOwnerReferences(ownerRefs)
+		return true
+	}
+	binding.SetOwnerReferences(append(ownerRefs, ownerRef))
+	return true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("argocd-health-controller").
+		For(&placementv1beta1.ClusterResourceBinding{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
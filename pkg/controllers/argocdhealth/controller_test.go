@@ -0,0 +1,105 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package argocdhealth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	crpName     = "test-crp"
+	bindingName = "test-crp-member-1"
+)
+
+var crpOwnerRef = metav1.OwnerReference{
+	APIVersion: placementv1beta1.GroupVersion.String(),
+	Kind:       placementv1beta1.ClusterResourcePlacementKind,
+	Name:       crpName,
+	UID:        "crp-uid",
+}
+
+func TestReconcile(t *testing.T) {
+	crp := &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: crpName, UID: "crp-uid"},
+	}
+	otherOwnerRef := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"}
+	tests := map[string]struct {
+		ownerRefs     []metav1.OwnerReference
+		crpLabel      string
+		crp           *placementv1beta1.ClusterResourcePlacement
+		wantOwnerRefs []metav1.OwnerReference
+	}{
+		"owner reference is added": {
+			ownerRefs:     []metav1.OwnerReference{otherOwnerRef},
+			crpLabel:      crpName,
+			crp:           crp,
+			wantOwnerRefs: []metav1.OwnerReference{otherOwnerRef, crpOwnerRef},
+		},
+		"owner reference is already set": {
+			ownerRefs:     []metav1.OwnerReference{crpOwnerRef},
+			crpLabel:      crpName,
+			crp:           crp,
+			wantOwnerRefs: []metav1.OwnerReference{crpOwnerRef},
+		},
+		"owner reference to a former placement is replaced": {
+			ownerRefs: []metav1.OwnerReference{
+				{APIVersion: crpOwnerRef.APIVersion, Kind: crpOwnerRef.Kind, Name: crpName, UID: "former-crp-uid"},
+			},
+			crpLabel:      crpName,
+			crp:           crp,
+			wantOwnerRefs: []metav1.OwnerReference{crpOwnerRef},
+		},
+		"binding without the placement label": {
+			crp: crp,
+		},
+		"placement is gone": {
+			crpLabel: crpName,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := placementv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the scheme: %v", err)
+			}
+			binding := &placementv1beta1.ClusterResourceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            bindingName,
+					OwnerReferences: tc.ownerRefs,
+				},
+			}
+			if tc.crpLabel != "" {
+				binding.Labels = map[string]string{placementv1beta1.CRPTrackingLabel: tc.crpLabel}
+			}
+			objects := []client.Object{binding}
+			if tc.crp != nil {
+				objects = append(objects, tc.crp)
+			}
+			r := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: bindingName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want nil", err)
+			}
+			var got placementv1beta1.ClusterResourceBinding
+			if err := r.Client.Get(context.Background(), types.NamespacedName{Name: bindingName}, &got); err != nil {
+				t.Fatalf("failed to get the binding: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantOwnerRefs, got.OwnerReferences); diff != "" {
+				t.Errorf("binding owner references mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
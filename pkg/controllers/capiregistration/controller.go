@@ -0,0 +1,487 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package capiregistration features a controller that registers the clusters provisioned by Cluster API as member
+// clusters of the fleet, bootstraps the member agent onto them, and removes them from the fleet when they are deleted.
+package capiregistration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/httpclient"
+)
+
+const (
+	// identityNameFormat is the format of the name of the hub service account which is the identity of a registered
+	// member cluster.
+	identityNameFormat = "fleet-member-agent-%s"
+	// kubeconfigSecretNameFormat is the format of the name of the secret in which Cluster API stores the kubeconfig of
+	// a cluster, and kubeconfigSecretKey is the key of the kubeconfig in the secret.
+	kubeconfigSecretNameFormat = "%s-kubeconfig"
+	kubeconfigSecretKey        = "value"
+	// provisionedPhase is the phase of a Cluster API Cluster whose infrastructure and control plane are ready.
+	provisionedPhase = "Provisioned"
+
+	// HubTokenSecretName is the name of the secret on the member cluster which holds the token of the member cluster
+	// identity, in the fleet-system namespace, which the secret token provider of the member agent reads.
+	HubTokenSecretName = "hub-kubeconfig-secret"
+
+	// tokenPollInterval is how often the controller checks if the token of a new identity is populated.
+	tokenPollInterval = 5 * time.Second
+	// deregistrationTimeout is how long the controller waits for the member agent to leave the fleet before it lets
+	// Cluster API delete the cluster anyway.
+	deregistrationTimeout = 5 * time.Minute
+)
+
+// ClusterGVK is the GVK of the Cluster API Cluster.
+var ClusterGVK = schema.GroupVersionKind{Group: "cluster.x-k8s.io", Version: "v1beta1", Kind: "Cluster"}
+
+// BootstrapData is the data with which the bootstrap manifest templates are rendered.
+type BootstrapData struct {
+	// MemberClusterName is the name of the member cluster registered for the Cluster API Cluster.
+	MemberClusterName string
+	// HubServerURL is the URL of the hub API server.
+	HubServerURL string
+	// HubCA is the base64 encoded certificate authority data of the hub API server.
+	HubCA string
+	// HubTokenSecretName and HubTokenSecretNamespace locate the secret which holds the hub token on the member cluster.
+	HubTokenSecretName      string
+	HubTokenSecretNamespace string
+}
+
+// Reconciler reconciles a Cluster API Cluster labeled with kubernetes-fleet.io/auto-register=true. Once the cluster is
+// provisioned, it
+//   - creates a service account on the hub cluster as the identity of the member cluster;
+//   - creates a member cluster with the same name as the Cluster API Cluster;
+//   - copies the token of the identity into the member cluster, and applies the bootstrap manifests, e.g. the member
+//     agent, rendered from the templates in the bootstrap configMap.
+//
+// When the Cluster is deleted, the member cluster and its identity are removed before Cluster API tears the cluster
+// down, so that the member agent has a chance to leave the fleet gracefully.
+type Reconciler struct {
+	Client client.Client
+	// HubServerURL is the URL of the hub API server that the member agents connect to.
+	HubServerURL string
+	// IdentityNamespace is the namespace of the service accounts which are the identities of the member clusters.
+	IdentityNamespace string
+	// BootstrapConfigMap, if set, is the configMap whose data are the Go templates of the manifests applied to each
+	// new member cluster, rendered with BootstrapData.
+	BootstrapConfigMap *types.NamespacedName
+	// NewMemberClient builds the client of the member cluster from its kubeconfig, reaching its API server as the
+	// connectivity of the member cluster advertises.
+	NewMemberClient func(kubeconfig []byte, connectivity *clusterv1beta1.MemberClusterConnectivity) (client.Client, error)
+}
+
+// Reconcile registers the Cluster API Cluster as a member cluster, or deregisters it.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	clusterRef := klog.KRef(req.Namespace, req.Name)
+	klog.V(2).InfoS("ClusterAPIRegistration reconciliation starts", "cluster", clusterRef)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ClusterAPIRegistration reconciliation ends", "cluster", clusterRef, "latency", latency)
+	}()
+
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ClusterGVK)
+	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the cluster", "cluster", clusterRef)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+
+	if !cluster.GetDeletionTimestamp().IsZero() || cluster.GetLabels()[placementv1beta1.ClusterAPIAutoRegisterLabel] != "true" {
+		if !controllerutil.ContainsFinalizer(cluster, placementv1beta1.ClusterAPIRegistrationFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		return r.deregister(ctx, cluster)
+	}
+
+	if !controllerutil.ContainsFinalizer(cluster, placementv1beta1.ClusterAPIRegistrationFinalizer) {
+		controllerutil.AddFinalizer(cluster, placementv1beta1.ClusterAPIRegistrationFinalizer)
+		if err := r.Client.Update(ctx, cluster); err != nil {
+			klog.ErrorS(err, "Failed to add the registration finalizer to the cluster", "cluster", clusterRef)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+	}
+	if !isProvisioned(cluster) {
+		klog.V(2).InfoS("Waiting for the cluster to be provisioned", "cluster", clusterRef)
+		return ctrl.Result{}, nil
+	}
+
+	token, hubCA, err := r.ensureIdentity(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if token == nil {
+		klog.V(2).InfoS("Waiting for the token of the member cluster identity", "cluster", clusterRef)
+		return ctrl.Result{RequeueAfter: tokenPollInterval}, nil
+	}
+	mc, err := r.ensureMemberCluster(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.bootstrap(ctx, cluster, mc, token, hubCA); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// isProvisioned tells if the control plane of the Cluster API Cluster is ready to be bootstrapped.
+func isProvisioned(cluster *unstructured.Unstructured) bool {
+	phase, _, _ := unstructured.NestedString(cluster.Object, "status", "phase")
+	controlPlaneReady, _, _ := unstructured.NestedBool(cluster.Object, "status", "controlPlaneReady")
+	return phase == provisionedPhase && controlPlaneReady
+}
+
+// ensureIdentity makes sure that the service account of the member cluster identity and its token exist. It returns
+// nil token if the token is not populated yet.
+func (r *Reconciler) ensureIdentity(ctx context.Context, cluster *unstructured.Unstructured) ([]byte, []byte, error) {
+	name := fmt.Sprintf(identityNameFormat, cluster.GetName())
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: r.IdentityNamespace,
+			Labels:    clusterLabels(cluster),
+		},
+	}
+	if err := r.Client.Create(ctx, sa); err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.ErrorS(err, "Failed to create the member cluster identity", "serviceAccount", klog.KObj(sa))
+		return nil, nil, controller.NewAPIServerError(false, err)
+	}
+
+	// the long-lived token is used as the member agent cannot request a new one from outside the hub cluster
+	var secret corev1.Secret
+	secretKey := types.NamespacedName{Namespace: r.IdentityNamespace, Name: name}
+	if err := r.Client.Get(ctx, secretKey, &secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the token of the member cluster identity", "secret", secretKey)
+			return nil, nil, controller.NewAPIServerError(true, err)
+		}
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   r.IdentityNamespace,
+				Labels:      clusterLabels(cluster),
+				Annotations: map[string]string{corev1.ServiceAccountNameKey: name},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+		}
+		if err := r.Client.Create(ctx, &secret); err != nil && !apierrors.IsAlreadyExists(err) {
+			klog.ErrorS(err, "Failed to create the token of the member cluster identity", "secret", secretKey)
+			return nil, nil, controller.NewAPIServerError(false, err)
+		}
+		return nil, nil, nil
+	}
+	if len(secret.Data[corev1.ServiceAccountTokenKey]) == 0 {
+		return nil, nil, nil
+	}
+	return secret.Data[corev1.ServiceAccountTokenKey], secret.Data[corev1.ServiceAccountRootCAKey], nil
+}
+
+// ensureMemberCluster makes sure that the member cluster is registered for the Cluster API Cluster.
+func (r *Reconciler) ensureMemberCluster(ctx context.Context, cluster *unstructured.Unstructured) (*clusterv1beta1.MemberCluster, error) {
+	var mc clusterv1beta1.MemberCluster
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cluster.GetName()}, &mc)
+	switch {
+	case err == nil:
+		if !isRegisteredFor(&mc, cluster) {
+			err := fmt.Errorf("member cluster %s is not registered for the Cluster API cluster %s/%s", mc.Name, cluster.GetNamespace(), cluster.GetName())
+			klog.ErrorS(err, "Member cluster name is taken", "memberCluster", klog.KObj(&mc), "cluster", klog.KObj(cluster))
+			return nil, controller.NewUserError(err)
+		}
+		return &mc, nil
+	case !apierrors.IsNotFound(err):
+		klog.ErrorS(err, "Failed to get the member cluster", "memberCluster", cluster.GetName())
+		return nil, controller.NewAPIServerError(true, err)
+	}
+
+	mc = clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   cluster.GetName(),
+			Labels: clusterLabels(cluster),
+		},
+		Spec: clusterv1beta1.MemberClusterSpec{
+			Identity: rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      fmt.Sprintf(identityNameFormat, cluster.GetName()),
+				Namespace: r.IdentityNamespace,
+			},
+		},
+	}
+	if err := r.Client.Create(ctx, &mc); err != nil {
+		klog.ErrorS(err, "Failed to register the member cluster", "memberCluster", klog.KObj(&mc))
+		return nil, controller.NewAPIServerError(false, err)
+	}
+	klog.V(2).InfoS("Registered the member cluster", "memberCluster", klog.KObj(&mc), "cluster", klog.KObj(cluster))
+	return &mc, nil
+}
+
+// bootstrap applies the hub token and the bootstrap manifests to the member cluster, unless they are not changed
+// since the last time.
+func (r *Reconciler) bootstrap(ctx context.Context, cluster *unstructured.Unstructured, mc *clusterv1beta1.MemberCluster, token, hubCA []byte) error {
+	objs, err := r.renderBootstrapManifests(ctx, mc.Name, token, hubCA)
+	if err != nil {
+		return err
+	}
+	hash, err := hashObjects(objs)
+	if err != nil {
+		return controller.NewUnexpectedBehaviorError(err)
+	}
+	if mc.Annotations[placementv1beta1.ClusterAPIBootstrapHashAnnotation] == hash {
+		return nil
+	}
+
+	var kubeconfig corev1.Secret
+	kubeconfigKey := types.NamespacedName{Namespace: cluster.GetNamespace(), Name: fmt.Sprintf(kubeconfigSecretNameFormat, cluster.GetName())}
+	if err := r.Client.Get(ctx, kubeconfigKey, &kubeconfig); err != nil {
+		klog.ErrorS(err, "Failed to get the kubeconfig of the cluster", "secret", kubeconfigKey)
+		return controller.NewAPIServerError(true, err)
+	}
+	memberClient, err := r.NewMemberClient(kubeconfig.Data[kubeconfigSecretKey], mc.Spec.Connectivity)
+	if err != nil {
+		klog.ErrorS(err, "Failed to build the client of the member cluster", "memberCluster", klog.KObj(mc))
+		return controller.NewUnexpectedBehaviorError(err)
+	}
+	for _, obj := range objs {
+		if err := applyObject(ctx, memberClient, obj); err != nil {
+			klog.ErrorS(err, "Failed to apply the bootstrap manifest to the member cluster", "memberCluster", klog.KObj(mc),
+				"gvk", obj.GroupVersionKind(), "manifest", klog.KObj(obj))
+			return controller.NewAPIServerError(false, err)
+		}
+	}
+
+	if mc.Annotations == nil {
+		mc.Annotations = map[string]string{}
+	}
+	mc.Annotations[placementv1beta1.ClusterAPIBootstrapHashAnnotation] = hash
+	if err := r.Client.Update(ctx, mc); err != nil {
+		klog.ErrorS(err, "Failed to record the bootstrap of the member cluster", "memberCluster", klog.KObj(mc))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	klog.V(2).InfoS("Bootstrapped the member cluster", "memberCluster", klog.KObj(mc), "numberOfManifests", len(objs))
+	return nil
+}
+
+// renderBootstrapManifests returns the fleet-system namespace and the hub token secret, followed by the manifests
+// rendered from the bootstrap configMap in the order of their keys.
+func (r *Reconciler) renderBootstrapManifests(ctx context.Context, mcName string, token, hubCA []byte) ([]*unstructured.Unstructured, error) {
+	namespace := &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: utils.FleetSystemNamespace},
+	}
+	tokenSecret := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: HubTokenSecretName, Namespace: utils.FleetSystemNamespace},
+		Data:       map[string][]byte{"token": token},
+	}
+	var objs []*unstructured.Unstructured
+	for _, obj := range []runtime.Object{namespace, tokenSecret} {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, controller.NewUnexpectedBehaviorError(err)
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: content})
+	}
+	if r.BootstrapConfigMap == nil {
+		return objs, nil
+	}
+
+	var cm corev1.ConfigMap
+	if err := r.Client.Get(ctx, *r.BootstrapConfigMap, &cm); err != nil {
+		klog.ErrorS(err, "Failed to get the bootstrap configMap", "configMap", r.BootstrapConfigMap)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	data := BootstrapData{
+		MemberClusterName:       mcName,
+		HubServerURL:            r.HubServerURL,
+		HubCA:                   base64.StdEncoding.EncodeToString(hubCA),
+		HubTokenSecretName:      HubTokenSecretName,
+		HubTokenSecretNamespace: utils.FleetSystemNamespace,
+	}
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		obj, err := renderManifest(key, cm.Data[key], data)
+		if err != nil {
+			klog.ErrorS(err, "Bootstrap configMap has invalid manifest", "configMap", r.BootstrapConfigMap, "key", key)
+			return nil, controller.NewUserError(err)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// renderManifest renders a single manifest from the Go template.
+func renderManifest(key, manifestTemplate string, data BootstrapData) (*unstructured.Unstructured, error) {
+	tmpl, err := template.New(key).Option("missingkey=error").Parse(manifestTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the template in key %s: %w", key, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render the template in key %s: %w", key, err)
+	}
+	content, err := yaml.YAMLToJSON(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest in key %s: %w", key, err)
+	}
+	var obj unstructured.Unstructured
+	if err := obj.UnmarshalJSON(content); err != nil {
+		return nil, fmt.Errorf("invalid manifest in key %s: %w", key, err)
+	}
+	return &obj, nil
+}
+
+// applyObject creates the object on the member cluster or overwrites the existing one.
+func applyObject(ctx context.Context, memberClient client.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := memberClient.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		return memberClient.Create(ctx, obj.DeepCopy())
+	case err != nil:
+		return err
+	}
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return memberClient.Update(ctx, obj)
+}
+
+// deregister removes the member cluster and its identity, and then releases the Cluster API Cluster.
+func (r *Reconciler) deregister(ctx context.Context, cluster *unstructured.Unstructured) (ctrl.Result, error) {
+	var mc clusterv1beta1.MemberCluster
+	err := r.Client.Get(ctx, types.NamespacedName{Name: cluster.GetName()}, &mc)
+	switch {
+	case err == nil && isRegisteredFor(&mc, cluster):
+		if mc.DeletionTimestamp.IsZero() {
+			if err := r.Client.Delete(ctx, &mc); err != nil && !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to deregister the member cluster", "memberCluster", klog.KObj(&mc))
+				return ctrl.Result{}, controller.NewAPIServerError(false, err)
+			}
+			klog.V(2).InfoS("Deregistering the member cluster", "memberCluster", klog.KObj(&mc), "cluster", klog.KObj(cluster))
+			return ctrl.Result{RequeueAfter: tokenPollInterval}, nil
+		}
+		if time.Since(mc.DeletionTimestamp.Time) < deregistrationTimeout {
+			klog.V(2).InfoS("Waiting for the member cluster to leave the fleet", "memberCluster", klog.KObj(&mc))
+			return ctrl.Result{RequeueAfter: tokenPollInterval}, nil
+		}
+		klog.V(2).InfoS("Timed out waiting for the member cluster to leave the fleet", "memberCluster", klog.KObj(&mc))
+	case err != nil && !apierrors.IsNotFound(err):
+		klog.ErrorS(err, "Failed to get the member cluster", "memberCluster", cluster.GetName())
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+
+	// the token secret is garbage collected with its service account
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(identityNameFormat, cluster.GetName()), Namespace: r.IdentityNamespace},
+	}
+	if err := r.Client.Delete(ctx, sa); err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to delete the member cluster identity", "serviceAccount", klog.KObj(sa))
+		return ctrl.Result{}, controller.NewAPIServerError(false, err)
+	}
+	controllerutil.RemoveFinalizer(cluster, placementv1beta1.ClusterAPIRegistrationFinalizer)
+	if err := r.Client.Update(ctx, cluster); err != nil {
+		klog.ErrorS(err, "Failed to remove the registration finalizer from the cluster", "cluster", klog.KObj(cluster))
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	klog.V(2).InfoS("Deregistered the cluster", "cluster", klog.KObj(cluster))
+	return ctrl.Result{}, nil
+}
+
+func clusterLabels(cluster *unstructured.Unstructured) map[string]string {
+	return map[string]string{
+		placementv1beta1.ClusterAPIClusterNamespaceLabel: cluster.GetNamespace(),
+		placementv1beta1.ClusterAPIClusterNameLabel:      cluster.GetName(),
+	}
+}
+
+func isRegisteredFor(mc *clusterv1beta1.MemberCluster, cluster *unstructured.Unstructured) bool {
+	return mc.Labels[placementv1beta1.ClusterAPIClusterNamespaceLabel] == cluster.GetNamespace() &&
+		mc.Labels[placementv1beta1.ClusterAPIClusterNameLabel] == cluster.GetName()
+}
+
+func hashObjects(objs []*unstructured.Unstructured) (string, error) {
+	hash := sha256.New()
+	for _, obj := range objs {
+		content, err := obj.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// NewMemberClient builds the client of a member cluster from its kubeconfig, which reaches the API server of the
+// member cluster through the proxy or the private endpoint of its connectivity, if any.
+func NewMemberClient(kubeconfig []byte, connectivity *clusterv1beta1.MemberClusterConnectivity) (client.Client, error) {
+	restConfig, err := memberRESTConfig(kubeconfig, connectivity)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{})
+}
+
+// memberRESTConfig builds the rest config of a member cluster from its kubeconfig and its connectivity.
+func memberRESTConfig(kubeconfig []byte, connectivity *clusterv1beta1.MemberClusterConnectivity) (*rest.Config, error) {
+	if len(kubeconfig) == 0 {
+		return nil, errors.New("the kubeconfig is empty")
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	if connectivity != nil {
+		if err := httpclient.ApplyConnectivity(restConfig, connectivity.ProxyURL, connectivity.PrivateEndpoint); err != nil {
+			return nil, fmt.Errorf("invalid connectivity of the member cluster: %w", err)
+		}
+	}
+	return restConfig, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ClusterGVK)
+	return ctrl.NewControllerManagedBy(mgr).Named("cluster-api-registration-controller").
+		For(cluster).
+		Complete(r)
+}
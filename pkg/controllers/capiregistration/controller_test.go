@@ -0,0 +1,347 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package capiregistration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	testClusterName      = "workload-1"
+	testClusterNamespace = "capi-clusters"
+	testIdentityNS       = "fleet-system"
+)
+
+var (
+	clusterKey    = types.NamespacedName{Namespace: testClusterNamespace, Name: testClusterName}
+	identityKey   = types.NamespacedName{Namespace: testIdentityNS, Name: "fleet-member-agent-" + testClusterName}
+	bootstrapKey  = types.NamespacedName{Namespace: testIdentityNS, Name: "member-agent-bootstrap"}
+	agentTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: member-agent-config
+  namespace: {{ .HubTokenSecretNamespace }}
+data:
+  memberClusterName: {{ .MemberClusterName }}
+  hubURL: {{ .HubServerURL }}
+`
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the cluster APIs to the scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the core APIs to the scheme: %v", err)
+	}
+	return scheme
+}
+
+func newTestCluster(labels map[string]string, phase string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ClusterGVK)
+	cluster.SetNamespace(testClusterNamespace)
+	cluster.SetName(testClusterName)
+	cluster.SetLabels(labels)
+	cluster.Object["status"] = map[string]interface{}{
+		"phase":             phase,
+		"controlPlaneReady": phase == provisionedPhase,
+	}
+	return cluster
+}
+
+func newTestReconciler(hubClient, memberClient client.Client) *Reconciler {
+	return &Reconciler{
+		Client:             hubClient,
+		HubServerURL:       "https://hub.example.com",
+		IdentityNamespace:  testIdentityNS,
+		BootstrapConfigMap: &bootstrapKey,
+		NewMemberClient: func(kubeconfig []byte, _ *clusterv1beta1.MemberClusterConnectivity) (client.Client, error) {
+			if string(kubeconfig) != "kubeconfig" {
+				return nil, errors.New("unexpected kubeconfig")
+			}
+			return memberClient, nil
+		},
+	}
+}
+
+func reconcileCluster(t *testing.T, r *Reconciler) ctrl.Result {
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: clusterKey})
+	if err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	return result
+}
+
+func TestReconcileRegistersAndDeregistersCluster(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	hubClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newTestCluster(map[string]string{placementv1beta1.ClusterAPIAutoRegisterLabel: "true"}, provisionedPhase),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testClusterNamespace, Name: testClusterName + "-kubeconfig"},
+			Data:       map[string][]byte{"value": []byte("kubeconfig")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: bootstrapKey.Namespace, Name: bootstrapKey.Name},
+			Data:       map[string]string{"agent.yaml": agentTemplate},
+		},
+	).Build()
+	memberClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := newTestReconciler(hubClient, memberClient)
+
+	// the first reconciliation creates the identity and waits for its token
+	if result := reconcileCluster(t, r); result.RequeueAfter == 0 {
+		t.Fatalf("Reconcile() = %+v, want requeue to wait for the token", result)
+	}
+	var tokenSecret corev1.Secret
+	if err := hubClient.Get(ctx, identityKey, &tokenSecret); err != nil {
+		t.Fatalf("failed to get the identity token secret: %v", err)
+	}
+	if err := hubClient.Get(ctx, identityKey, &corev1.ServiceAccount{}); err != nil {
+		t.Fatalf("failed to get the identity service account: %v", err)
+	}
+	tokenSecret.Data = map[string][]byte{
+		corev1.ServiceAccountTokenKey:  []byte("hub-token"),
+		corev1.ServiceAccountRootCAKey: []byte("hub-ca"),
+	}
+	if err := hubClient.Update(ctx, &tokenSecret); err != nil {
+		t.Fatalf("failed to populate the identity token: %v", err)
+	}
+
+	// the second reconciliation registers and bootstraps the member cluster
+	reconcileCluster(t, r)
+	var mc clusterv1beta1.MemberCluster
+	if err := hubClient.Get(ctx, types.NamespacedName{Name: testClusterName}, &mc); err != nil {
+		t.Fatalf("failed to get the member cluster: %v", err)
+	}
+	wantIdentity := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: identityKey.Name, Namespace: identityKey.Namespace}
+	if diff := cmp.Diff(wantIdentity, mc.Spec.Identity); diff != "" {
+		t.Errorf("member cluster identity mismatch (-want, +got):\n%s", diff)
+	}
+	if mc.Annotations[placementv1beta1.ClusterAPIBootstrapHashAnnotation] == "" {
+		t.Errorf("member cluster has no bootstrap hash")
+	}
+	var hubToken corev1.Secret
+	if err := memberClient.Get(ctx, types.NamespacedName{Namespace: utils.FleetSystemNamespace, Name: HubTokenSecretName}, &hubToken); err != nil {
+		t.Fatalf("failed to get the hub token on the member cluster: %v", err)
+	}
+	if got := string(hubToken.Data["token"]); got != "hub-token" {
+		t.Errorf("hub token on the member cluster = %q, want %q", got, "hub-token")
+	}
+	var agentConfig corev1.ConfigMap
+	if err := memberClient.Get(ctx, types.NamespacedName{Namespace: utils.FleetSystemNamespace, Name: "member-agent-config"}, &agentConfig); err != nil {
+		t.Fatalf("failed to get the rendered bootstrap manifest on the member cluster: %v", err)
+	}
+	wantData := map[string]string{"memberClusterName": testClusterName, "hubURL": "https://hub.example.com"}
+	if diff := cmp.Diff(wantData, agentConfig.Data); diff != "" {
+		t.Errorf("rendered bootstrap manifest mismatch (-want, +got):\n%s", diff)
+	}
+
+	// the deletion of the cluster removes the member cluster and its identity
+	cluster := newTestCluster(nil, provisionedPhase)
+	if err := hubClient.Delete(ctx, cluster); err != nil {
+		t.Fatalf("failed to delete the cluster: %v", err)
+	}
+	reconcileCluster(t, r)
+	if err := hubClient.Get(ctx, types.NamespacedName{Name: testClusterName}, &mc); !apierrors.IsNotFound(err) {
+		t.Errorf("member cluster is not deregistered: %v", err)
+	}
+	reconcileCluster(t, r)
+	if err := hubClient.Get(ctx, identityKey, &corev1.ServiceAccount{}); !apierrors.IsNotFound(err) {
+		t.Errorf("member cluster identity is not deleted: %v", err)
+	}
+	if err := hubClient.Get(ctx, clusterKey, cluster); !apierrors.IsNotFound(err) {
+		t.Errorf("cluster is not released: %v", err)
+	}
+}
+
+func TestReconcileSkipsCluster(t *testing.T) {
+	tests := map[string]struct {
+		cluster *unstructured.Unstructured
+	}{
+		"cluster does not opt in": {
+			cluster: newTestCluster(nil, provisionedPhase),
+		},
+		"cluster is still provisioning": {
+			cluster: newTestCluster(map[string]string{placementv1beta1.ClusterAPIAutoRegisterLabel: "true"}, "Provisioning"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			hubClient := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(tc.cluster).Build()
+			r := newTestReconciler(hubClient, nil)
+			if result := reconcileCluster(t, r); result.RequeueAfter != 0 {
+				t.Errorf("Reconcile() = %+v, want no requeue", result)
+			}
+			var sas corev1.ServiceAccountList
+			if err := hubClient.List(context.Background(), &sas); err != nil {
+				t.Fatalf("failed to list the service accounts: %v", err)
+			}
+			if len(sas.Items) != 0 {
+				t.Errorf("Reconcile() created identities %v, want none", sas.Items)
+			}
+		})
+	}
+}
+
+func TestEnsureMemberClusterNameTaken(t *testing.T) {
+	mc := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName},
+	}
+	hubClient := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(mc).Build()
+	r := newTestReconciler(hubClient, nil)
+	_, err := r.ensureMemberCluster(context.Background(), newTestCluster(nil, provisionedPhase))
+	if !errors.Is(err, controller.ErrUserError) {
+		t.Errorf("ensureMemberCluster() = %v, want user error", err)
+	}
+}
+
+func TestRenderManifest(t *testing.T) {
+	data := BootstrapData{MemberClusterName: testClusterName, HubTokenSecretNamespace: utils.FleetSystemNamespace, HubServerURL: "https://hub"}
+	if _, err := renderManifest("agent.yaml", agentTemplate, data); err != nil {
+		t.Errorf("renderManifest() = %v, want nil", err)
+	}
+	if _, err := renderManifest("agent.yaml", "name: {{ .Unknown }}", data); err == nil {
+		t.Errorf("renderManifest() = nil, want error for an unknown field")
+	}
+	if _, err := renderManifest("agent.yaml", "name: [", data); err == nil {
+		t.Errorf("renderManifest() = nil, want error for invalid YAML")
+	}
+}
+
+func TestBootstrapHonorsConnectivity(t *testing.T) {
+	scheme := newTestScheme(t)
+	hubClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testClusterNamespace, Name: testClusterName + "-kubeconfig"},
+			Data:       map[string][]byte{"value": []byte("kubeconfig")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: bootstrapKey.Namespace, Name: bootstrapKey.Name},
+			Data:       map[string]string{"agent.yaml": agentTemplate},
+		},
+	).Build()
+	connectivity := &clusterv1beta1.MemberClusterConnectivity{ProxyURL: "socks5://proxy.example.com:1080"}
+	mc := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testClusterName},
+		Spec:       clusterv1beta1.MemberClusterSpec{Connectivity: connectivity},
+	}
+	if err := hubClient.Create(context.Background(), mc); err != nil {
+		t.Fatalf("failed to create the member cluster: %v", err)
+	}
+	r := newTestReconciler(hubClient, fake.NewClientBuilder().WithScheme(scheme).Build())
+	var got *clusterv1beta1.MemberClusterConnectivity
+	newMemberClient := r.NewMemberClient
+	r.NewMemberClient = func(kubeconfig []byte, connectivity *clusterv1beta1.MemberClusterConnectivity) (client.Client, error) {
+		got = connectivity
+		return newMemberClient(kubeconfig, connectivity)
+	}
+	if err := r.bootstrap(context.Background(), newTestCluster(nil, provisionedPhase), mc, []byte("hub-token"), []byte("hub-ca")); err != nil {
+		t.Fatalf("bootstrap() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(connectivity, got); diff != "" {
+		t.Errorf("connectivity of the member client mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestMemberRESTConfig(t *testing.T) {
+	const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: member
+  cluster:
+    server: https://member.example.com:6443
+contexts:
+- name: member
+  context:
+    cluster: member
+    user: admin
+current-context: member
+users:
+- name: admin
+  user:
+    token: token
+`
+	tests := map[string]struct {
+		connectivity   *clusterv1beta1.MemberClusterConnectivity
+		wantHost       string
+		wantServerName string
+		wantProxy      string
+		wantErr        bool
+	}{
+		"no connectivity": {
+			wantHost: "https://member.example.com:6443",
+		},
+		"proxy": {
+			connectivity: &clusterv1beta1.MemberClusterConnectivity{ProxyURL: "http://proxy.example.com:3128"},
+			wantHost:     "https://member.example.com:6443",
+			wantProxy:    "http://proxy.example.com:3128",
+		},
+		"private endpoint": {
+			connectivity:   &clusterv1beta1.MemberClusterConnectivity{PrivateEndpoint: "https://10.0.0.4:6443"},
+			wantHost:       "https://10.0.0.4:6443",
+			wantServerName: "member.example.com",
+		},
+		"invalid proxy": {
+			connectivity: &clusterv1beta1.MemberClusterConnectivity{ProxyURL: "ftp://proxy.example.com"},
+			wantErr:      true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := memberRESTConfig([]byte(kubeconfig), tc.connectivity)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("memberRESTConfig() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if config.Host != tc.wantHost {
+				t.Errorf("host = %s, want %s", config.Host, tc.wantHost)
+			}
+			if config.TLSClientConfig.ServerName != tc.wantServerName {
+				t.Errorf("server name = %s, want %s", config.TLSClientConfig.ServerName, tc.wantServerName)
+			}
+			var gotProxy string
+			if config.Proxy != nil {
+				proxy, err := config.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "member.example.com:6443"}})
+				if err != nil {
+					t.Fatalf("failed to get the proxy: %v", err)
+				}
+				gotProxy = proxy.String()
+			}
+			if gotProxy != tc.wantProxy {
+				t.Errorf("proxy = %s, want %s", gotProxy, tc.wantProxy)
+			}
+		})
+	}
+	if _, err := memberRESTConfig(nil, nil); err == nil {
+		t.Errorf("memberRESTConfig() = nil, want error for an empty kubeconfig")
+	}
+}
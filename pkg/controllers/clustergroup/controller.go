@@ -0,0 +1,164 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package clustergroup features a controller that keeps the label of each cluster group on its member clusters, so
+// that the placements can select the clusters of a group by its name.
+package clustergroup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	"go.goms.io/fleet/pkg/utils/clustergroup"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// Reconciler reconciles a cluster group. It labels the member clusters of the group with the label of the group,
+// removes the label from the other clusters, and records the members in the status of the group.
+type Reconciler struct {
+	Client client.Client
+}
+
+// Reconcile resolves the member clusters of the cluster group and updates their labels.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("ClusterGroup reconciliation starts", "clusterGroup", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ClusterGroup reconciliation ends", "clusterGroup", req.Name, "latency", latency)
+	}()
+
+	var group clusterv1beta1.ClusterGroup
+	if err := r.Client.Get(ctx, req.NamespacedName, &group); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the cluster group", "clusterGroup", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if err := clustergroup.Validate(group.Name); err != nil {
+		// the clusters cannot be labeled with the group, which must be recreated with a shorter name
+		klog.ErrorS(controller.NewUserError(err), "Invalid cluster group", "clusterGroup", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if !group.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&group, clusterv1beta1.ClusterGroupFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		// remove the label of the group from all the clusters before the group is gone
+		if _, err := r.syncMembers(ctx, &group, true); err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&group, clusterv1beta1.ClusterGroupFinalizer)
+		if err := r.Client.Update(ctx, &group); err != nil {
+			klog.ErrorS(err, "Failed to remove the finalizer of the cluster group", "clusterGroup", req.Name)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+		klog.V(2).InfoS("Removed the cluster group from its member clusters", "clusterGroup", req.Name)
+		return ctrl.Result{}, nil
+	}
+	if controllerutil.AddFinalizer(&group, clusterv1beta1.ClusterGroupFinalizer) {
+		if err := r.Client.Update(ctx, &group); err != nil {
+			klog.ErrorS(err, "Failed to add the finalizer to the cluster group", "clusterGroup", req.Name)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+	}
+
+	members, err := r.syncMembers(ctx, &group, false)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	status := clusterv1beta1.ClusterGroupStatus{
+		ObservedGeneration: group.Generation,
+		Clusters:           members,
+		ClusterCount:       len(members),
+	}
+	if equality.Semantic.DeepEqual(group.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	group.Status = status
+	if err := r.Client.Status().Update(ctx, &group); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the cluster group", "clusterGroup", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncMembers labels the member clusters of the group with the label of the group and removes the label from the
+// other clusters, or from all the clusters if the group is being deleted; it returns the sorted names of the members.
+func (r *Reconciler) syncMembers(ctx context.Context, group *clusterv1beta1.ClusterGroup, deleting bool) ([]string, error) {
+	var clusterList clusterv1beta1.MemberClusterList
+	if err := r.Client.List(ctx, &clusterList); err != nil {
+		klog.ErrorS(err, "Failed to list the member clusters", "clusterGroup", klog.KObj(group))
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	members := make([]string, 0)
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		member := false
+		if !deleting {
+			var err error
+			if member, err = clustergroup.IsMember(group, cluster); err != nil {
+				// the selector should have been rejected by the API server
+				klog.ErrorS(err, "Invalid cluster group", "clusterGroup", klog.KObj(group))
+				return nil, controller.NewUserError(err)
+			}
+		}
+		if member {
+			members = append(members, cluster.Name)
+		}
+		original := cluster.DeepCopy()
+		if !clustergroup.SetMembership(cluster, group.Name, member) {
+			continue
+		}
+		if err := r.Client.Patch(ctx, cluster, client.MergeFrom(original)); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			klog.ErrorS(err, "Failed to update the cluster group label of the member cluster", "clusterGroup", klog.KObj(group), "memberCluster", klog.KObj(cluster))
+			return nil, controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Updated the cluster group label of the member cluster", "clusterGroup", klog.KObj(group), "memberCluster", klog.KObj(cluster), "member", member)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// SetupWithManager sets up the controller with the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("cluster-group-controller").
+		For(&clusterv1beta1.ClusterGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the members of the groups change as the clusters join, leave or are relabeled
+		Watches(&clusterv1beta1.MemberCluster{}, handler.EnqueueRequestsFromMapFunc(r.allGroups), builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(r)
+}
+
+// allGroups maps a member cluster to all the cluster groups.
+func (r *Reconciler) allGroups(ctx context.Context, _ client.Object) []reconcile.Request {
+	var groupList clusterv1beta1.ClusterGroupList
+	if err := r.Client.List(ctx, &groupList); err != nil {
+		klog.ErrorS(err, "Failed to list the cluster groups")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(groupList.Items))
+	for i := range groupList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&groupList.Items[i])})
+	}
+	return requests
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clustergroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+)
+
+const groupName = "prod-eu"
+
+func newCluster(name string, labels map[string]string) *clusterv1beta1.MemberCluster {
+	return &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestReconcile(t *testing.T) {
+	groupLabel := clusterv1beta1.ClusterGroupLabelPrefix + groupName
+	group := &clusterv1beta1.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: groupName, Generation: 2},
+		Spec: clusterv1beta1.ClusterGroupSpec{
+			Clusters:        []string{"static"},
+			ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod", "region": "eu"}},
+		},
+	}
+	deletingGroup := group.DeepCopy()
+	deletingGroup.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	deletingGroup.Finalizers = []string{clusterv1beta1.ClusterGroupFinalizer}
+	tests := map[string]struct {
+		group *clusterv1beta1.ClusterGroup
+		// wantMembers are the clusters labeled with the group after the reconciliation.
+		wantMembers []string
+		// wantStatus is the status of the group after the reconciliation, if it still exists.
+		wantStatus *clusterv1beta1.ClusterGroupStatus
+	}{
+		"the clusters listed or selected are labeled": {
+			group:       group,
+			wantMembers: []string{"prod-eu-1", "static"},
+			wantStatus: &clusterv1beta1.ClusterGroupStatus{
+				ObservedGeneration: 2,
+				Clusters:           []string{"prod-eu-1", "static"},
+				ClusterCount:       2,
+			},
+		},
+		"the labels are removed when the group is deleted": {
+			group: deletingGroup,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			objects := []client.Object{
+				tc.group,
+				newCluster("static", nil),
+				newCluster("prod-eu-1", map[string]string{"env": "prod", "region": "eu"}),
+				// the cluster is no longer selected after its labels are changed
+				newCluster("prod-us-1", map[string]string{"env": "prod", "region": "us", groupLabel: "true"}),
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+				WithStatusSubresource(&clusterv1beta1.ClusterGroup{}).Build()
+			r := &Reconciler{Client: fakeClient}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: groupName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want nil", err)
+			}
+
+			var clusterList clusterv1beta1.MemberClusterList
+			if err := fakeClient.List(context.Background(), &clusterList, client.MatchingLabels{groupLabel: "true"}); err != nil {
+				t.Fatalf("List() = %v, want nil", err)
+			}
+			var gotMembers []string
+			for _, cluster := range clusterList.Items {
+				gotMembers = append(gotMembers, cluster.Name)
+			}
+			if diff := cmp.Diff(tc.wantMembers, gotMembers); diff != "" {
+				t.Errorf("Reconcile() labeled clusters mismatch (-want, +got):\n%s", diff)
+			}
+
+			var gotGroup clusterv1beta1.ClusterGroup
+			err := fakeClient.Get(context.Background(), types.NamespacedName{Name: groupName}, &gotGroup)
+			if tc.wantStatus == nil {
+				// the fake client removes the deleting group once its finalizer is gone
+				if err == nil {
+					t.Errorf("Get() = %+v, want the group deleted", gotGroup)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(*tc.wantStatus, gotGroup.Status); diff != "" {
+				t.Errorf("Reconcile() status mismatch (-want, +got):\n%s", diff)
+			}
+			if gotGroup.Finalizers == nil {
+				t.Errorf("Reconcile() finalizers = nil, want %s", clusterv1beta1.ClusterGroupFinalizer)
+			}
+		})
+	}
+}
@@ -14,10 +14,12 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrloption "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
 )
@@ -29,6 +31,10 @@ type Reconciler struct {
 	// PlacementController maintains a rate limited queue which used to store
 	// the name of the clusterResourcePlacement and a reconcile function to consume the items in queue.
 	PlacementController controller.Controller
+
+	// Sharder shards the placements across the replicas of the hub agent if set; the watcher then runs on every
+	// replica, as the placement controller skips the placements of the other replicas.
+	Sharder *sharding.Sharder
 }
 
 // Reconcile reconciles the clusterResourceBinding.
@@ -48,12 +54,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, controller.NewAPIServerError(true, client.IgnoreNotFound(err))
 	}
 
-	// Check if the cluster resource binding has been deleted.
-	// Normally this would not happen as the event filter is set to filter out all deletion events.
-	if binding.DeletionTimestamp != nil {
-		// The cluster resource binding has been deleted; ignore it.
-		return ctrl.Result{}, nil
-	}
+	// The deleting bindings are not skipped, as the placement reports the progress of removing the resources from the
+	// clusters which are not selected anymore.
 
 	// Fetch the CRP name from the CRPTrackingLabel on ClusterResourceBinding.
 	crpName := binding.Labels[fleetv1beta1.CRPTrackingLabel]
@@ -67,8 +69,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
-	// Enqueue the CRP name for reconciling.
-	r.PlacementController.Enqueue(crpName)
+	// Enqueue the CRP name for reconciling; the binding status changes are processed after the user-triggered ones.
+	r.PlacementController.EnqueueWithPriority(crpName, controller.PriorityLow)
 	return ctrl.Result{}, nil
 }
 
@@ -99,11 +101,12 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 				klog.ErrorS(err, "Failed to process update event")
 				return false
 			}
-			return areConditionsUpdated(oldBinding, newBinding)
+			return areConditionsUpdated(oldBinding, newBinding) || isRemovalUpdated(oldBinding, newBinding)
 		},
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(ctrloption.Options{NeedLeaderElection: sharding.ControllerLeaderElection(r.Sharder)}).
 		For(&fleetv1beta1.ClusterResourceBinding{}).
 		WithEventFilter(customPredicate).
 		Complete(r)
@@ -120,3 +123,13 @@ func areConditionsUpdated(oldBinding, newBinding *fleetv1beta1.ClusterResourceBi
 	}
 	return false
 }
+
+// isRemovalUpdated tells if the binding starts being deleted or the progress of deleting its resources changes.
+func isRemovalUpdated(oldBinding, newBinding *fleetv1beta1.ClusterResourceBinding) bool {
+	if oldBinding.DeletionTimestamp.IsZero() != newBinding.DeletionTimestamp.IsZero() {
+		return true
+	}
+	oldCond := oldBinding.GetCondition(string(fleetv1beta1.ResourceBindingResourcesDeleted))
+	newCond := newBinding.GetCondition(string(fleetv1beta1.ResourceBindingResourcesDeleted))
+	return !condition.EqualCondition(oldCond, newCond) || (oldCond != nil && newCond != nil && oldCond.Message != newCond.Message)
+}
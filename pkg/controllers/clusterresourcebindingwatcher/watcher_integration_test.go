@@ -123,6 +123,11 @@ var _ = Describe("Test ClusterResourceBinding Watcher - update status", Serial,
 		validateWhenUpdateClusterResourceBindingStatusWithCondition(fleetv1beta1.ResourceBindingAvailable, crb.Generation, metav1.ConditionFalse, testReason1)
 	})
 
+	It("Should enqueue the clusterResourcePlacement name for reconciling, when clusterResourceBinding status changes - ResourcesDeleted", func() {
+		validateWhenUpdateClusterResourceBindingStatusWithCondition(fleetv1beta1.ResourceBindingResourcesDeleted, crb.Generation, metav1.ConditionFalse, testReason1)
+		validateWhenUpdateClusterResourceBindingStatusWithCondition(fleetv1beta1.ResourceBindingResourcesDeleted, crb.Generation, metav1.ConditionTrue, testReason1)
+	})
+
 	It("Should enqueue the clusterResourcePlacement name for reconciling, when condition's observed generation changes", func() {
 		validateWhenUpdateClusterResourceBindingStatusWithCondition(fleetv1beta1.ResourceBindingRolloutStarted, crb.Generation+1, metav1.ConditionFalse, testReason1)
 	})
@@ -0,0 +1,67 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// setCanaryCondition sets the canary condition of the placement by aggregating the Canary conditions which the
+// rollout controller sets on the bindings of the canary clusters. The condition is removed if the placement does not
+// use the Canary rollout strategy or no binding is a canary one.
+func setCanaryCondition(crp *fleetv1beta1.ClusterResourcePlacement, resourceBindingMap map[string]*fleetv1beta1.ClusterResourceBinding) {
+	conditionType := string(fleetv1beta1.ClusterResourcePlacementCanaryConditionType)
+	if crp.Spec.Strategy.Type != fleetv1beta1.CanaryRolloutStrategyType {
+		meta.RemoveStatusCondition(&crp.Status.Conditions, conditionType)
+		return
+	}
+	canaryNumber, succeededNumber, soakingNumber := 0, 0, 0
+	for _, binding := range resourceBindingMap {
+		bindingCondition := binding.GetCondition(string(fleetv1beta1.ResourceBindingCanary))
+		if bindingCondition == nil {
+			continue
+		}
+		canaryNumber++
+		if bindingCondition.ObservedGeneration != binding.Generation {
+			// the binding is being updated to the latest resources
+			continue
+		}
+		switch bindingCondition.Reason {
+		case condition.CanarySucceededReason:
+			succeededNumber++
+		case condition.CanarySoakingReason:
+			soakingNumber++
+		}
+	}
+	if canaryNumber == 0 {
+		meta.RemoveStatusCondition(&crp.Status.Conditions, conditionType)
+		return
+	}
+
+	cond := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             condition.CanaryRollingOutReason,
+		Message:            fmt.Sprintf("The latest resources are rolling out to the canary clusters; %d of %d canary clusters have soaked", succeededNumber, canaryNumber),
+		ObservedGeneration: crp.Generation,
+	}
+	switch {
+	case succeededNumber == canaryNumber:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = condition.CanarySucceededReason
+		cond.Message = fmt.Sprintf("The latest resources have soaked on all the %d canary clusters and are rolling out to the rest of the clusters", canaryNumber)
+	case succeededNumber+soakingNumber == canaryNumber:
+		cond.Reason = condition.CanarySoakingReason
+		cond.Message = fmt.Sprintf("The latest resources are soaking on the canary clusters; %d of %d canary clusters have soaked", succeededNumber, canaryNumber)
+	}
+	crp.SetConditions(cond)
+}
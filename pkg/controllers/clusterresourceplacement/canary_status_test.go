@@ -0,0 +1,109 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestSetCanaryCondition(t *testing.T) {
+	newBinding := func(generation int64, reason string, observedGeneration int64) *fleetv1beta1.ClusterResourceBinding {
+		binding := &fleetv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Generation: generation}}
+		if reason != "" {
+			binding.SetConditions(metav1.Condition{
+				Type:               string(fleetv1beta1.ResourceBindingCanary),
+				Status:             metav1.ConditionFalse,
+				Reason:             reason,
+				ObservedGeneration: observedGeneration,
+			})
+		}
+		return binding
+	}
+	existingCondition := metav1.Condition{
+		Type:   string(fleetv1beta1.ClusterResourcePlacementCanaryConditionType),
+		Status: metav1.ConditionFalse,
+		Reason: condition.CanarySoakingReason,
+	}
+	tests := map[string]struct {
+		strategyType       fleetv1beta1.RolloutStrategyType
+		bindings           map[string]*fleetv1beta1.ClusterResourceBinding
+		existingConditions []metav1.Condition
+		want               []metav1.Condition
+	}{
+		"rolling update strategy": {
+			strategyType:       fleetv1beta1.RollingUpdateRolloutStrategyType,
+			existingConditions: []metav1.Condition{existingCondition},
+		},
+		"no canary binding": {
+			strategyType:       fleetv1beta1.CanaryRolloutStrategyType,
+			bindings:           map[string]*fleetv1beta1.ClusterResourceBinding{"member-1": newBinding(1, "", 0)},
+			existingConditions: []metav1.Condition{existingCondition},
+		},
+		"canary clusters are rolling out": {
+			strategyType: fleetv1beta1.CanaryRolloutStrategyType,
+			bindings: map[string]*fleetv1beta1.ClusterResourceBinding{
+				"member-1": newBinding(1, condition.CanarySucceededReason, 1),
+				"member-2": newBinding(2, condition.CanarySucceededReason, 1),
+				"member-3": newBinding(1, "", 0),
+			},
+			want: []metav1.Condition{{
+				Type:               string(fleetv1beta1.ClusterResourcePlacementCanaryConditionType),
+				Status:             metav1.ConditionFalse,
+				Reason:             condition.CanaryRollingOutReason,
+				ObservedGeneration: 1,
+			}},
+		},
+		"canary clusters are soaking": {
+			strategyType: fleetv1beta1.CanaryRolloutStrategyType,
+			bindings: map[string]*fleetv1beta1.ClusterResourceBinding{
+				"member-1": newBinding(1, condition.CanarySucceededReason, 1),
+				"member-2": newBinding(1, condition.CanarySoakingReason, 1),
+			},
+			want: []metav1.Condition{{
+				Type:               string(fleetv1beta1.ClusterResourcePlacementCanaryConditionType),
+				Status:             metav1.ConditionFalse,
+				Reason:             condition.CanarySoakingReason,
+				ObservedGeneration: 1,
+			}},
+		},
+		"canary clusters have soaked": {
+			strategyType: fleetv1beta1.CanaryRolloutStrategyType,
+			bindings: map[string]*fleetv1beta1.ClusterResourceBinding{
+				"member-1": newBinding(1, condition.CanarySucceededReason, 1),
+				"member-2": newBinding(1, "", 0),
+			},
+			existingConditions: []metav1.Condition{existingCondition},
+			want: []metav1.Condition{{
+				Type:               string(fleetv1beta1.ClusterResourcePlacementCanaryConditionType),
+				Status:             metav1.ConditionTrue,
+				Reason:             condition.CanarySucceededReason,
+				ObservedGeneration: 1,
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crp := &fleetv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Generation: 1},
+				Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+					Strategy: fleetv1beta1.RolloutStrategy{Type: tc.strategyType},
+				},
+				Status: fleetv1beta1.ClusterResourcePlacementStatus{Conditions: tc.existingConditions},
+			}
+			setCanaryCondition(crp, tc.bindings)
+			if diff := cmp.Diff(tc.want, crp.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "Message"), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("setCanaryCondition() conditions mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
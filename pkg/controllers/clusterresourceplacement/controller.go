@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/annotations"
 	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
@@ -62,6 +64,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, key controller.QueueKey) (ct
 		return ctrl.Result{}, controller.NewAPIServerError(true, err)
 	}
 
+	if !r.Sharder.OwnsPlacement(&crp) {
+		klog.V(4).InfoS("Ignoring clusterResourcePlacement owned by another shard", "clusterResourcePlacement", name)
+		return ctrl.Result{}, nil
+	}
+
 	if crp.ObjectMeta.DeletionTimestamp != nil {
 		return r.handleDelete(ctx, &crp)
 	}
@@ -84,6 +91,15 @@ func (r *Reconciler) handleDelete(ctx context.Context, crp *fleetv1beta1.Cluster
 		klog.V(4).InfoS("clusterResourcePlacement is being deleted and no cleanup work needs to be done by the CRP controller, waiting for the scheduler to cleanup the bindings", "clusterResourcePlacement", crpKObj)
 		return ctrl.Result{}, nil
 	}
+	if utils.IsReverseOrderDeletion(crp) {
+		done, err := r.deleteBindingsInWaves(ctx, crp)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: deletionWaveRequeueInterval}, nil
+		}
+	}
 	klog.V(2).InfoS("Removing snapshots created by clusterResourcePlacement", "clusterResourcePlacement", crpKObj)
 	if err := r.deleteClusterSchedulingPolicySnapshots(ctx, crp); err != nil {
 		return ctrl.Result{}, err
@@ -169,8 +185,27 @@ func (r *Reconciler) handleUpdate(ctx context.Context, crp *fleetv1beta1.Cluster
 			Message:            fmt.Sprintf("The resource selectors are invalid: %v", err),
 			ObservedGeneration: crp.Generation,
 		}
-		crp.SetConditions(scheduleCondition)
-		if updateErr := r.Client.Status().Update(ctx, crp); updateErr != nil {
+		if updateErr := controller.UpdateStatusWithRetry(ctx, r.Client, crp, func(crp *fleetv1beta1.ClusterResourcePlacement) {
+			crp.SetConditions(scheduleCondition)
+		}); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to update the status", "clusterResourcePlacement", crpKObj)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(updateErr)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// dry-run the selected resources before cutting any snapshot
+	if err := r.validateSelectedResourcesForPlacement(crp, selectedResources, selectedResourceIDs); err != nil {
+		scheduleCondition := metav1.Condition{
+			Status:             metav1.ConditionFalse,
+			Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
+			Reason:             InvalidSelectedResourcesReason,
+			Message:            fmt.Sprintf("The selected resources are invalid: %v", err),
+			ObservedGeneration: crp.Generation,
+		}
+		if updateErr := controller.UpdateStatusWithRetry(ctx, r.Client, crp, func(crp *fleetv1beta1.ClusterResourcePlacement) {
+			crp.SetConditions(scheduleCondition)
+		}); updateErr != nil {
 			klog.ErrorS(updateErr, "Failed to update the status", "clusterResourcePlacement", crpKObj)
 			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(updateErr)
 		}
@@ -196,7 +231,11 @@ func (r *Reconciler) handleUpdate(ctx context.Context, crp *fleetv1beta1.Cluster
 		return ctrl.Result{}, err
 	}
 
-	if err := r.Client.Status().Update(ctx, crp); err != nil {
+	// the status of the placement is only written by this controller, so that it is kept as a whole on conflicts
+	status := crp.Status.DeepCopy()
+	if err := controller.UpdateStatusWithRetry(ctx, r.Client, crp, func(crp *fleetv1beta1.ClusterResourcePlacement) {
+		crp.Status = *status.DeepCopy()
+	}); err != nil {
 		klog.ErrorS(err, "Failed to update the status", "clusterResourcePlacement", crpKObj)
 		return ctrl.Result{}, err
 	}
@@ -220,6 +259,15 @@ func (r *Reconciler) handleUpdate(ctx context.Context, crp *fleetv1beta1.Cluster
 			klog.V(2).InfoS("Placement rollout has finished and resources are available", "clusterResourcePlacement", crpKObj, "generation", crp.Generation)
 			r.Recorder.Event(crp, corev1.EventTypeNormal, "PlacementRolloutCompleted", "Resources are available in the selected clusters")
 		}
+		if len(crp.Status.RemovingClusters) > 0 {
+			klog.V(2).InfoS("Placement is still removing the resources from the unselected clusters and requeue the request",
+				"clusterResourcePlacement", crpKObj, "removingClusters", len(crp.Status.RemovingClusters))
+			return ctrl.Result{RequeueAfter: removingClustersRequeueInterval}, nil
+		}
+		if r.StatusStaleTimeout > 0 {
+			// a cluster becoming unreachable changes no binding, so check again when the status may become stale
+			return ctrl.Result{RequeueAfter: r.StatusStaleTimeout}, nil
+		}
 		// We don't need to requeue any request now by watching the binding changes
 		return ctrl.Result{}, nil
 	}
@@ -235,16 +283,42 @@ func (r *Reconciler) handleUpdate(ctx context.Context, crp *fleetv1beta1.Cluster
 		// When isClusterScheduled is false, either scheduler has not finished the scheduling or none of the clusters could be selected.
 		// Once the policy snapshot status changes, the policy snapshot watcher should enqueue the request.
 		// Here we requeue the request to prevent a bug in the watcher.
+		requeueAfter := r.AdaptiveResync.interval(crp, 5*time.Minute, time.Now())
 		klog.V(2).InfoS("Scheduler has not scheduled any cluster yet and requeue the request as a backup",
-			"clusterResourcePlacement", crpKObj, "scheduledCondition", crp.GetCondition(string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType)), "generation", crp.Generation)
-		return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+			"clusterResourcePlacement", crpKObj, "scheduledCondition", crp.GetCondition(string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType)), "generation", crp.Generation, "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
-	klog.V(2).InfoS("Placement rollout has not finished yet and requeue the request", "clusterResourcePlacement", crpKObj, "status", crp.Status, "generation", crp.Generation)
+	requeueAfter := r.AdaptiveResync.interval(crp, 1*time.Minute, time.Now())
+	if r.StatusStaleTimeout > 0 && requeueAfter > r.StatusStaleTimeout {
+		requeueAfter = r.StatusStaleTimeout
+	}
+	klog.V(2).InfoS("Placement rollout has not finished yet and requeue the request", "clusterResourcePlacement", crpKObj, "status", crp.Status, "generation", crp.Generation, "requeueAfter", requeueAfter)
 	// we need to requeue the request to update the status of the resources eg, failedManifests.
 	// The binding status won't be changed.
 	// TODO: once we move to populate the failedManifests from the binding, no need to requeue.
-	return ctrl.Result{RequeueAfter: 1 * time.Minute}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// validateSelectedResourcesForPlacement validates the selected resources according to the configured validation mode.
+// It returns an error only when the mode is Reject and the selected resources are invalid.
+func (r *Reconciler) validateSelectedResourcesForPlacement(crp *fleetv1beta1.ClusterResourcePlacement,
+	selectedResources []fleetv1beta1.ResourceContent, selectedResourceIDs []fleetv1beta1.ResourceIdentifier) error {
+	if r.SelectedResourcesValidationMode != SelectedResourcesValidationModeWarn && r.SelectedResourcesValidationMode != SelectedResourcesValidationModeReject {
+		return nil
+	}
+	crpKObj := klog.KObj(crp)
+	err := validateSelectedResources(crp, selectedResources, selectedResourceIDs)
+	if err == nil {
+		return nil
+	}
+	if r.SelectedResourcesValidationMode == SelectedResourcesValidationModeWarn {
+		klog.V(2).InfoS("The selected resources are invalid and continue to create the snapshots", "clusterResourcePlacement", crpKObj, "error", err)
+		r.Recorder.Event(crp, corev1.EventTypeWarning, InvalidSelectedResourcesReason, fmt.Sprintf("The selected resources are invalid: %v", err))
+		return nil
+	}
+	klog.ErrorS(err, "The selected resources are invalid and stop creating the snapshots", "clusterResourcePlacement", crpKObj)
+	return err
 }
 
 func (r *Reconciler) getOrCreateClusterSchedulingPolicySnapshot(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, revisionHistoryLimit int) (*fleetv1beta1.ClusterSchedulingPolicySnapshot, error) {
@@ -411,7 +485,7 @@ func (r *Reconciler) deleteRedundantResourceSnapshots(ctx context.Context, crp *
 }
 
 func (r *Reconciler) getOrCreateClusterResourceSnapshot(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, envelopeObjCount int, resourceSnapshotSpec *fleetv1beta1.ResourceSnapshotSpec, revisionHistoryLimit int) (*fleetv1beta1.ClusterResourceSnapshot, error) {
-	resourceHash, err := resource.HashOf(resourceSnapshotSpec)
+	resourceHash, err := hashOfResourceSnapshotSpec(resourceSnapshotSpec)
 	crpKObj := klog.KObj(crp)
 	if err != nil {
 		klog.ErrorS(err, "Failed to generate resource hash of crp", "clusterResourcePlacement", crpKObj)
@@ -913,6 +987,9 @@ func (r *Reconciler) setPlacementStatus(ctx context.Context, crp *fleetv1beta1.C
 		// The undeleted resources on these old clusters could lead to failed synchronized or applied condition.
 		// Today, we only track the resources progress if the same cluster is selected again.
 		crp.Status.PlacementStatuses = []fleetv1beta1.ResourcePlacementStatus{}
+		// The perClusterPlacementStatuses are kept, so that they are updated instead of recreated once the scheduling
+		// completes.
+		crp.Status.PlacementStatusSummary = nil
 		return false, nil
 	}
 
@@ -920,6 +997,19 @@ func (r *Reconciler) setPlacementStatus(ctx context.Context, crp *fleetv1beta1.C
 }
 
 func buildScheduledCondition(crp *fleetv1beta1.ClusterResourcePlacement, latestSchedulingPolicySnapshot *fleetv1beta1.ClusterSchedulingPolicySnapshot) metav1.Condition {
+	if len(crp.Spec.SchedulingGates) > 0 {
+		gates := make([]string, len(crp.Spec.SchedulingGates))
+		for i := range crp.Spec.SchedulingGates {
+			gates[i] = crp.Spec.SchedulingGates[i].Name
+		}
+		return metav1.Condition{
+			Status:             metav1.ConditionUnknown,
+			Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
+			Reason:             SchedulingGatedReason,
+			Message:            fmt.Sprintf("Scheduling is blocked by the scheduling gates: %s", strings.Join(gates, ", ")),
+			ObservedGeneration: crp.Generation,
+		}
+	}
 	scheduledCondition := latestSchedulingPolicySnapshot.GetCondition(string(fleetv1beta1.PolicySnapshotScheduled))
 
 	if scheduledCondition == nil ||
@@ -937,11 +1027,18 @@ func buildScheduledCondition(crp *fleetv1beta1.ClusterResourcePlacement, latestS
 			ObservedGeneration: crp.Generation,
 		}
 	}
+	message := scheduledCondition.Message
+	// explain why the placement lost some of its clusters if the scheduler has preempted it for a placement of a
+	// higher priority
+	if preemptedCondition := latestSchedulingPolicySnapshot.GetCondition(string(fleetv1beta1.PolicySnapshotPreempted)); preemptedCondition != nil &&
+		preemptedCondition.Status == metav1.ConditionTrue {
+		message = fmt.Sprintf("%s; %s", message, preemptedCondition.Message)
+	}
 	return metav1.Condition{
 		Status:             scheduledCondition.Status,
 		Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
 		Reason:             scheduledCondition.Reason,
-		Message:            scheduledCondition.Message,
+		Message:            message,
 		ObservedGeneration: crp.Generation,
 	}
 }
@@ -3265,3 +3265,106 @@ func TestIsRolloutComplete(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildScheduledCondition(t *testing.T) {
+	scheduledPolicySnapshot := &fleetv1beta1.ClusterSchedulingPolicySnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       fmt.Sprintf(fleetv1beta1.PolicySnapshotNameFmt, testName, 0),
+			Generation: 1,
+		},
+		Status: fleetv1beta1.SchedulingPolicySnapshotStatus{
+			ObservedCRPGeneration: crpGeneration,
+			Conditions: []metav1.Condition{
+				{
+					Status:             metav1.ConditionTrue,
+					Type:               string(fleetv1beta1.PolicySnapshotScheduled),
+					Reason:             "Scheduled",
+					Message:            "found all the clusters needed",
+					ObservedGeneration: 1,
+				},
+			},
+		},
+	}
+	tests := map[string]struct {
+		schedulingGates []fleetv1beta1.PlacementSchedulingGate
+		policySnapshot  *fleetv1beta1.ClusterSchedulingPolicySnapshot
+		want            metav1.Condition
+	}{
+		"the placement is scheduled": {
+			policySnapshot: scheduledPolicySnapshot,
+			want: metav1.Condition{
+				Status:             metav1.ConditionTrue,
+				Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
+				Reason:             "Scheduled",
+				Message:            "found all the clusters needed",
+				ObservedGeneration: crpGeneration,
+			},
+		},
+		"the placement is preempted": {
+			policySnapshot: func() *fleetv1beta1.ClusterSchedulingPolicySnapshot {
+				snapshot := scheduledPolicySnapshot.DeepCopy()
+				snapshot.Status.Conditions[0].Status = metav1.ConditionFalse
+				snapshot.Status.Conditions[0].Reason = "Unfulfilled"
+				snapshot.Status.Conditions[0].Message = "found 2 clusters instead"
+				snapshot.SetConditions(metav1.Condition{
+					Status:             metav1.ConditionTrue,
+					Type:               string(fleetv1beta1.PolicySnapshotPreempted),
+					Reason:             "PreemptedByHigherPriorityPlacement",
+					Message:            "The placement is preempted from cluster(s) [member-1] by clusterResourcePlacement critical of priority 100",
+					ObservedGeneration: 1,
+				})
+				return snapshot
+			}(),
+			want: metav1.Condition{
+				Status:             metav1.ConditionFalse,
+				Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
+				Reason:             "Unfulfilled",
+				Message:            "found 2 clusters instead; The placement is preempted from cluster(s) [member-1] by clusterResourcePlacement critical of priority 100",
+				ObservedGeneration: crpGeneration,
+			},
+		},
+		"the scheduling has not completed": {
+			policySnapshot: &fleetv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(fleetv1beta1.PolicySnapshotNameFmt, testName, 0)},
+			},
+			want: metav1.Condition{
+				Status:             metav1.ConditionUnknown,
+				Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
+				Reason:             SchedulingUnknownReason,
+				Message:            "Scheduling has not completed",
+				ObservedGeneration: crpGeneration,
+			},
+		},
+		"the placement has scheduling gates": {
+			schedulingGates: []fleetv1beta1.PlacementSchedulingGate{
+				{Name: "example.com/budget-approval"},
+				{Name: "example.com/security-review"},
+			},
+			policySnapshot: scheduledPolicySnapshot,
+			want: metav1.Condition{
+				Status:             metav1.ConditionUnknown,
+				Type:               string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType),
+				Reason:             SchedulingGatedReason,
+				Message:            "Scheduling is blocked by the scheduling gates: example.com/budget-approval, example.com/security-review",
+				ObservedGeneration: crpGeneration,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crp := &fleetv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       testName,
+					Generation: crpGeneration,
+				},
+				Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+					SchedulingGates: tc.schedulingGates,
+				},
+			}
+			got := buildScheduledCondition(crp, tc.policySnapshot)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("buildScheduledCondition() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/defaulter"
+)
+
+const (
+	// DeletingInWavesReason is the reason string of the deleting condition of a placement whose placed resources are
+	// being deleted in waves.
+	DeletingInWavesReason = "DeletingInWaves"
+
+	// deletionWaveRequeueInterval is the interval at which a placement whose placed resources are being deleted in
+	// waves checks whether the current wave is done.
+	deletionWaveRequeueInterval = 5 * time.Second
+)
+
+// deleteBindingsInWaves deletes the bindings of a placement with the ReverseOrder deletion strategy in waves of
+// MaxUnavailable bindings, in the reverse order in which the clusters were selected, and reports the progress on the
+// placement. A wave is done once all of its bindings are gone, which happens after the work generator has deleted
+// their works and the member agents have deleted the resources of the works.
+// It returns true once all the bindings of the placement are gone.
+func (r *Reconciler) deleteBindingsInWaves(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement) (bool, error) {
+	crpKObj := klog.KObj(crp)
+	bindingList := &fleetv1beta1.ClusterResourceBindingList{}
+	if err := r.UncachedReader.List(ctx, bindingList, client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crp.Name}); err != nil {
+		klog.ErrorS(err, "Failed to list all clusterResourceBindings", "clusterResourcePlacement", crpKObj)
+		return false, controller.NewAPIServerError(false, err)
+	}
+	if len(bindingList.Items) == 0 {
+		return true, nil
+	}
+
+	bindings := make([]*fleetv1beta1.ClusterResourceBinding, len(bindingList.Items))
+	for i := range bindingList.Items {
+		bindings[i] = &bindingList.Items[i]
+	}
+	sortBindingsInReverseRolloutOrder(bindings)
+
+	// the bindings which are being deleted form the current wave; the next wave starts once they are all gone
+	var wave []*fleetv1beta1.ClusterResourceBinding
+	for _, binding := range bindings {
+		if binding.DeletionTimestamp != nil {
+			wave = append(wave, binding)
+		}
+	}
+	if len(wave) == 0 {
+		wave = bindings[:deletionWaveSize(crp, len(bindings))]
+		for _, binding := range wave {
+			if err := r.Client.Delete(ctx, binding); err != nil && !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to delete clusterResourceBinding", "clusterResourcePlacement", crpKObj, "clusterResourceBinding", klog.KObj(binding))
+				return false, controller.NewAPIServerError(false, err)
+			}
+		}
+		klog.V(2).InfoS("Started a deletion wave", "clusterResourcePlacement", crpKObj, "numberOfBindings", len(wave), "numberOfBindingsLeft", len(bindings)-len(wave))
+	}
+
+	clusters := make([]string, len(wave))
+	for i, binding := range wave {
+		clusters[i] = binding.Spec.TargetCluster
+	}
+	deletingCondition := metav1.Condition{
+		Status:             metav1.ConditionTrue,
+		Type:               string(fleetv1beta1.ClusterResourcePlacementDeletingConditionType),
+		Reason:             DeletingInWavesReason,
+		Message:            fmt.Sprintf("Deleting the placed resources from the clusters %v; %d more cluster(s) are waiting for their wave", clusters, len(bindings)-len(wave)),
+		ObservedGeneration: crp.Generation,
+	}
+	if !condition.EqualCondition(crp.GetCondition(deletingCondition.Type), &deletingCondition) {
+		if err := controller.UpdateStatusWithRetry(ctx, r.Client, crp, func(crp *fleetv1beta1.ClusterResourcePlacement) {
+			crp.SetConditions(deletingCondition)
+		}); err != nil {
+			klog.ErrorS(err, "Failed to update the status", "clusterResourcePlacement", crpKObj)
+			return false, controller.NewUpdateIgnoreConflictError(err)
+		}
+	}
+	return false, nil
+}
+
+// sortBindingsInReverseRolloutOrder sorts the bindings in the reverse order in which their clusters were selected and
+// rolled out, i.e. the binding created last comes first.
+func sortBindingsInReverseRolloutOrder(bindings []*fleetv1beta1.ClusterResourceBinding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		if !bindings[i].CreationTimestamp.Equal(&bindings[j].CreationTimestamp) {
+			return bindings[j].CreationTimestamp.Before(&bindings[i].CreationTimestamp)
+		}
+		return bindings[i].Name > bindings[j].Name
+	})
+}
+
+// deletionWaveSize returns the number of the bindings deleted in a wave, i.e. the MaxUnavailable of the placement
+// scaled to the number of the bindings left, and at least one.
+func deletionWaveSize(crp *fleetv1beta1.ClusterResourcePlacement, numberOfBindings int) int {
+	maxUnavailable := intstr.FromString(defaulter.DefaultMaxUnavailableValue)
+	if crp.Spec.Strategy.RollingUpdate != nil && crp.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+		maxUnavailable = *crp.Spec.Strategy.RollingUpdate.MaxUnavailable
+	}
+	size, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, numberOfBindings, true)
+	if err != nil || size < 1 {
+		return 1
+	}
+	return min(size, numberOfBindings)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestDeletionWaveSize(t *testing.T) {
+	tests := map[string]struct {
+		maxUnavailable   *intstr.IntOrString
+		numberOfBindings int
+		want             int
+	}{
+		"default max unavailable": {
+			numberOfBindings: 8,
+			want:             2,
+		},
+		"default max unavailable of a few bindings": {
+			numberOfBindings: 2,
+			want:             1,
+		},
+		"absolute max unavailable": {
+			maxUnavailable:   ptr.To(intstr.FromInt(3)),
+			numberOfBindings: 10,
+			want:             3,
+		},
+		"absolute max unavailable above the number of bindings": {
+			maxUnavailable:   ptr.To(intstr.FromInt(3)),
+			numberOfBindings: 2,
+			want:             2,
+		},
+		"zero max unavailable": {
+			maxUnavailable:   ptr.To(intstr.FromInt(0)),
+			numberOfBindings: 2,
+			want:             1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crp := &fleetv1beta1.ClusterResourcePlacement{}
+			if tc.maxUnavailable != nil {
+				crp.Spec.Strategy.RollingUpdate = &fleetv1beta1.RollingUpdateConfig{MaxUnavailable: tc.maxUnavailable}
+			}
+			if got := deletionWaveSize(crp, tc.numberOfBindings); got != tc.want {
+				t.Errorf("deletionWaveSize() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteBindingsInWaves(t *testing.T) {
+	now := time.Now()
+	newBinding := func(cluster string, createdAgo time.Duration) *fleetv1beta1.ClusterResourceBinding {
+		return &fleetv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              testName + "-" + cluster,
+				Labels:            map[string]string{fleetv1beta1.CRPTrackingLabel: testName},
+				CreationTimestamp: metav1.NewTime(now.Add(-createdAgo)),
+				Finalizers:        []string{fleetv1beta1.WorkFinalizer},
+			},
+			Spec: fleetv1beta1.ResourceBindingSpec{TargetCluster: cluster},
+		}
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              testName,
+			DeletionTimestamp: &metav1.Time{Time: now},
+			Finalizers:        []string{fleetv1beta1.ClusterResourcePlacementCleanupFinalizer},
+		},
+		Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+			Strategy: fleetv1beta1.RolloutStrategy{
+				RollingUpdate:    &fleetv1beta1.RollingUpdateConfig{MaxUnavailable: ptr.To(intstr.FromInt(2))},
+				DeletionStrategy: &fleetv1beta1.DeletionStrategy{Type: fleetv1beta1.DeletionStrategyTypeReverseOrder},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(serviceScheme(t)).
+		WithObjects(crp, newBinding("member-1", 3*time.Hour), newBinding("member-2", 2*time.Hour), newBinding("member-3", time.Hour)).
+		WithStatusSubresource(crp).
+		Build()
+	r := Reconciler{Client: fakeClient, UncachedReader: fakeClient}
+	ctx := context.Background()
+
+	deletingBindings := func() []string {
+		bindingList := &fleetv1beta1.ClusterResourceBindingList{}
+		if err := fakeClient.List(ctx, bindingList); err != nil {
+			t.Fatalf("failed to list the bindings: %v", err)
+		}
+		var got []string
+		for i := range bindingList.Items {
+			if bindingList.Items[i].DeletionTimestamp != nil {
+				got = append(got, bindingList.Items[i].Spec.TargetCluster)
+			}
+		}
+		return got
+	}
+	removeFinalizers := func() {
+		bindingList := &fleetv1beta1.ClusterResourceBindingList{}
+		if err := fakeClient.List(ctx, bindingList); err != nil {
+			t.Fatalf("failed to list the bindings: %v", err)
+		}
+		for i := range bindingList.Items {
+			if bindingList.Items[i].DeletionTimestamp != nil {
+				bindingList.Items[i].Finalizers = nil
+				if err := fakeClient.Update(ctx, &bindingList.Items[i]); err != nil {
+					t.Fatalf("failed to remove the finalizer of the binding: %v", err)
+				}
+			}
+		}
+	}
+	reconcile := func(wantDone bool) {
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: testName}, crp); err != nil {
+			t.Fatalf("failed to get the placement: %v", err)
+		}
+		if done, err := r.deleteBindingsInWaves(ctx, crp); err != nil || done != wantDone {
+			t.Fatalf("deleteBindingsInWaves() = %v, %v, want %v, nil", done, err, wantDone)
+		}
+	}
+
+	// the first wave deletes the bindings created last
+	reconcile(false)
+	if diff := cmp.Diff([]string{"member-2", "member-3"}, deletingBindings()); diff != "" {
+		t.Errorf("deleting bindings of the first wave mismatch (-want, +got):\n%s", diff)
+	}
+	wantCondition := metav1.Condition{
+		Type:    string(fleetv1beta1.ClusterResourcePlacementDeletingConditionType),
+		Status:  metav1.ConditionTrue,
+		Reason:  DeletingInWavesReason,
+		Message: "Deleting the placed resources from the clusters [member-3 member-2]; 1 more cluster(s) are waiting for their wave",
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: testName}, crp); err != nil {
+		t.Fatalf("failed to get the placement: %v", err)
+	}
+	if diff := cmp.Diff(wantCondition, *crp.GetCondition(wantCondition.Type), cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime", "ObservedGeneration")); diff != "" {
+		t.Errorf("deleting condition mismatch (-want, +got):\n%s", diff)
+	}
+
+	// the next wave waits for the current one to be gone
+	reconcile(false)
+	if diff := cmp.Diff([]string{"member-2", "member-3"}, deletingBindings()); diff != "" {
+		t.Errorf("deleting bindings of the unfinished first wave mismatch (-want, +got):\n%s", diff)
+	}
+
+	removeFinalizers()
+	reconcile(false)
+	if diff := cmp.Diff([]string{"member-1"}, deletingBindings()); diff != "" {
+		t.Errorf("deleting bindings of the second wave mismatch (-want, +got):\n%s", diff)
+	}
+
+	removeFinalizers()
+	reconcile(true)
+}
@@ -23,6 +23,7 @@ import (
 
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/pkg/metrics"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/controller"
 	"go.goms.io/fleet/pkg/utils/informer"
@@ -55,9 +56,38 @@ type Reconciler struct {
 	// SkippedNamespaces contains the namespaces that we should not propagate.
 	SkippedNamespaces map[string]bool
 
+	// SelectedResourcesValidationMode decides whether and how the selected resources are validated before the
+	// resource snapshots are created. It's only used by v1beta1 APIs.
+	SelectedResourcesValidationMode SelectedResourcesValidationMode
+
 	Recorder record.EventRecorder
 
 	Scheme *runtime.Scheme
+
+	// Sharder shards the placements across the replicas of the hub agent if set; the placements of the other replicas
+	// are skipped. It's only used by v1beta1 APIs.
+	Sharder *sharding.Sharder
+
+	// StatusCompactionThreshold is the number of the selected clusters above which the status of a placement is
+	// compacted, i.e. only the placement statuses of the unhealthy clusters are kept in the placement and the ones of
+	// all the selected clusters are written to the perClusterPlacementStatuses. It's disabled if it is 0. It's only
+	// used by v1beta1 APIs.
+	StatusCompactionThreshold int
+
+	// AdaptiveResync adapts the intervals at which the placements whose rollout has not completed are resynced to
+	// their stability if set; they are resynced at fixed intervals otherwise. It's only used by v1beta1 APIs.
+	AdaptiveResync *AdaptiveResync
+
+	// ResourceSnapshotMemoryBudget is the number of bytes that the serialized selected resources of a placement may
+	// take; the placements which select more are rejected before their resources are snapshotted, so that a single
+	// giant selection cannot exhaust the memory of the hub agent. It's disabled if it is 0. It's only used by v1beta1
+	// APIs.
+	ResourceSnapshotMemoryBudget int64
+
+	// StatusStaleTimeout is how long the member agent of a selected cluster may not send heartbeats before the applied
+	// and available conditions of its placement status are marked as stale. It's disabled if it is 0. It's only used
+	// by v1beta1 APIs.
+	StatusStaleTimeout time.Duration
 }
 
 // ReconcileV1Alpha1 reconciles v1aplha1 APIs.
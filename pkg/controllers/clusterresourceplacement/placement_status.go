@@ -8,6 +8,8 @@ package clusterresourceplacement
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +28,9 @@ const (
 	InvalidResourceSelectorsReason = "InvalidResourceSelectors"
 	// SchedulingUnknownReason is the reason string of placement condition when the schedule status is unknown.
 	SchedulingUnknownReason = "SchedulePending"
+	// SchedulingGatedReason is the reason string of placement condition when the placement is not scheduled as it still
+	// has scheduling gates.
+	SchedulingGatedReason = "SchedulingGated"
 
 	// ApplyFailedReason is the reason string of placement condition when the selected resources fail to apply.
 	ApplyFailedReason = "ApplyFailed"
@@ -35,6 +40,16 @@ const (
 	ApplySucceededReason = "ApplySucceeded"
 )
 
+const (
+	// maxRemovingClusters is the max number of the clusters reported in the removing clusters of the placement
+	// status, which is limited by the API.
+	maxRemovingClusters = 100
+
+	// removingClustersRequeueInterval is the interval at which a placement with removing clusters checks whether the
+	// bindings of those clusters are gone, as the binding watcher does not see the bindings being removed.
+	removingClustersRequeueInterval = 15 * time.Second
+)
+
 // ResourcePlacementStatus condition reasons and message formats
 const (
 	// ResourceScheduleSucceededReason is the reason string of placement condition when the selected resources are scheduled.
@@ -65,13 +80,30 @@ func (r *Reconciler) setResourceConditions(ctx context.Context, crp *fleetv1beta
 	}
 
 	oldResourcePlacementStatusMap := buildResourcePlacementStatusMap(crp)
-	resourceBindingMap, err := r.buildClusterResourceBindings(ctx, crp, latestSchedulingPolicySnapshot)
+	perClusterStatuses, err := r.listPerClusterPlacementStatuses(ctx, crp)
+	if err != nil {
+		return false, err
+	}
+	// the placement statuses of the healthy clusters are only kept in the perClusterPlacementStatuses when the status
+	// is compacted
+	for _, perClusterStatus := range perClusterStatuses {
+		clusterName := perClusterStatus.PlacementStatus.ClusterName
+		if _, ok := oldResourcePlacementStatusMap[clusterName]; !ok && clusterName != "" && len(perClusterStatus.PlacementStatus.Conditions) > 0 {
+			oldResourcePlacementStatusMap[clusterName] = perClusterStatus.PlacementStatus.Conditions
+		}
+	}
+	resourceBindingMap, removingClusters, err := r.buildClusterResourceBindings(ctx, crp, latestSchedulingPolicySnapshot)
+	if err != nil {
+		return false, err
+	}
+	crp.Status.RemovingClusters = removingClusters
+	unreachableClusters, err := r.findUnreachableClusters(ctx, selected, time.Now())
 	if err != nil {
 		return false, err
 	}
 
-	// record the total count per status for each condition
-	var clusterConditionStatusRes [condition.TotalCondition][condition.TotalConditionStatus]int
+	// collect the per cluster conditions to aggregate for each condition
+	var clusterConditions [condition.TotalCondition][]condition.ChildCondition
 
 	for _, c := range selected {
 		var rps fleetv1beta1.ResourcePlacementStatus
@@ -84,6 +116,12 @@ func (r *Reconciler) setResourceConditions(ctx context.Context, crp *fleetv1beta
 		}
 		rps.ClusterName = c.ClusterName
 		oldConditions, ok := oldResourcePlacementStatusMap[c.ClusterName]
+		lastHeartbeat, isUnreachable := unreachableClusters[c.ClusterName]
+		var lastConditions []metav1.Condition
+		if isUnreachable {
+			// the old conditions are updated in place below
+			lastConditions = append(lastConditions, oldConditions...)
+		}
 		if ok {
 			// update the lastTransitionTime considering the existing condition status instead of overwriting
 			rps.Conditions = oldConditions
@@ -93,15 +131,14 @@ func (r *Reconciler) setResourceConditions(ctx context.Context, crp *fleetv1beta
 		if err != nil {
 			return false, err
 		}
+		if isUnreachable {
+			res = markStatusStale(crp, &rps, lastConditions, lastHeartbeat, res)
+			klog.V(2).InfoS("Marked the placement status of the unreachable cluster as stale", "clusterResourcePlacement", klog.KObj(crp),
+				"cluster", c.ClusterName, "lastHeartbeat", lastHeartbeat)
+		}
 		for i := range res {
-			switch res[i] {
-			case metav1.ConditionTrue:
-				clusterConditionStatusRes[i][condition.TrueConditionStatus]++
-			case metav1.ConditionFalse:
-				clusterConditionStatusRes[i][condition.FalseConditionStatus]++
-			case metav1.ConditionUnknown:
-				clusterConditionStatusRes[i][condition.UnknownConditionStatus]++
-			}
+			cond := metav1.Condition{Status: res[i], ObservedGeneration: crp.Generation}
+			clusterConditions[i] = append(clusterConditions[i], condition.ChildCondition{Name: c.ClusterName, Generation: crp.Generation, Condition: &cond})
 		}
 		// The resources can be changed without updating the crp spec.
 		// To reflect the latest resource conditions, we reset the renaming conditions.
@@ -130,6 +167,9 @@ func (r *Reconciler) setResourceConditions(ctx context.Context, crp *fleetv1beta
 		klog.V(2).InfoS("Populated the resource placement status for the unscheduled cluster", "clusterResourcePlacement", klog.KObj(crp), "cluster", unselected[i].ClusterName)
 	}
 	crp.Status.PlacementStatuses = placementStatuses
+	if err := r.compactPlacementStatuses(ctx, crp, perClusterStatuses); err != nil {
+		return false, err
+	}
 
 	if !isClusterScheduled {
 		// It covers one special case: CRP selects a cluster which joins (resource are applied) and then leaves.
@@ -142,14 +182,18 @@ func (r *Reconciler) setResourceConditions(ctx context.Context, crp *fleetv1beta
 
 	i := condition.RolloutStartedCondition
 	for ; i < condition.TotalCondition; i++ {
-		if clusterConditionStatusRes[i][condition.UnknownConditionStatus] > 0 {
-			crp.SetConditions(i.UnknownClusterResourcePlacementCondition(crp.Generation, clusterConditionStatusRes[i][condition.UnknownConditionStatus]))
+		cond := i.ClusterResourcePlacementAggregator().Aggregate(clusterConditions[i], crp.Generation)
+		if cond.Status == metav1.ConditionUnknown {
+			crp.SetConditions(cond)
 			break
-		} else if clusterConditionStatusRes[i][condition.FalseConditionStatus] > 0 {
-			crp.SetConditions(i.FalseClusterResourcePlacementCondition(crp.Generation, clusterConditionStatusRes[i][condition.FalseConditionStatus]))
+		} else if cond.Status == metav1.ConditionFalse {
+			if i == condition.AvailableCondition && isProvisioning(placementStatuses) {
+				cond.Reason = condition.ProvisioningReason
+				cond.Message = fmt.Sprintf("The selected resources in %d cluster(s) are still provisioning", countStatus(clusterConditions[i], metav1.ConditionFalse))
+			}
+			crp.SetConditions(cond)
 			break
 		} else {
-			cond := i.TrueClusterResourcePlacementCondition(crp.Generation, clusterConditionStatusRes[i][condition.TrueConditionStatus])
 			if i == condition.OverriddenCondition {
 				hasOverride := false
 				for _, status := range placementStatuses {
@@ -172,12 +216,37 @@ func (r *Reconciler) setResourceConditions(ctx context.Context, crp *fleetv1beta
 		// To reflect the latest resource conditions, we reset the renaming conditions.
 		meta.RemoveStatusCondition(&crp.Status.Conditions, string(i.ClusterResourcePlacementConditionType()))
 	}
+	setCanaryCondition(crp, resourceBindingMap)
 	klog.V(2).InfoS("Populated the placement conditions", "clusterResourcePlacement", klog.KObj(crp))
 
 	return true, nil
 }
 
-func (r *Reconciler) buildClusterResourceBindings(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, latestSchedulingPolicySnapshot *fleetv1beta1.ClusterSchedulingPolicySnapshot) (map[string]*fleetv1beta1.ClusterResourceBinding, error) {
+// countStatus returns the number of the clusters whose condition has the status.
+func countStatus(clusterConditions []condition.ChildCondition, status metav1.ConditionStatus) int {
+	count := 0
+	for _, c := range clusterConditions {
+		if c.Status() == status {
+			count++
+		}
+	}
+	return count
+}
+
+// isProvisioning returns true if the resources are still provisioning in all the clusters where they are not available.
+func isProvisioning(placementStatuses []fleetv1beta1.ResourcePlacementStatus) bool {
+	for _, status := range placementStatuses {
+		cond := meta.FindStatusCondition(status.Conditions, string(fleetv1beta1.ResourcesAvailableConditionType))
+		if cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason != condition.ProvisioningReason {
+			return false
+		}
+	}
+	return true
+}
+
+// buildClusterResourceBindings returns the bindings of the latest scheduling policy snapshot keyed by their target
+// clusters, and the removal progress of the clusters which are not selected anymore.
+func (r *Reconciler) buildClusterResourceBindings(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, latestSchedulingPolicySnapshot *fleetv1beta1.ClusterSchedulingPolicySnapshot) (map[string]*fleetv1beta1.ClusterResourceBinding, []fleetv1beta1.ClusterRemovalStatus, error) {
 	// List all bindings derived from the CRP.
 	bindingList := &fleetv1beta1.ClusterResourceBindingList{}
 	listOptions := client.MatchingLabels{
@@ -186,7 +255,7 @@ func (r *Reconciler) buildClusterResourceBindings(ctx context.Context, crp *flee
 	crpKObj := klog.KObj(crp)
 	if err := r.Client.List(ctx, bindingList, listOptions); err != nil {
 		klog.ErrorS(err, "Failed to list all bindings", "clusterResourcePlacement", crpKObj)
-		return nil, controller.NewAPIServerError(true, err)
+		return nil, nil, controller.NewAPIServerError(true, err)
 	}
 
 	res := make(map[string]*fleetv1beta1.ClusterResourceBinding, len(bindingList.Items))
@@ -211,7 +280,62 @@ func (r *Reconciler) buildClusterResourceBindings(ctx context.Context, crp *flee
 		}
 		res[bindings[i].Spec.TargetCluster] = &bindings[i]
 	}
-	return res, nil
+	return res, buildClusterRemovalStatuses(bindings, latestSchedulingPolicySnapshot.Status.ClusterDecisions), nil
+}
+
+// buildClusterRemovalStatuses returns the removal progress of the clusters which are not selected by the scheduler
+// and only have unscheduled or deleting bindings, sorted by the cluster name.
+func buildClusterRemovalStatuses(bindings []fleetv1beta1.ClusterResourceBinding, decisions []fleetv1beta1.ClusterDecision) []fleetv1beta1.ClusterRemovalStatus {
+	selected := make(map[string]bool)
+	for _, decision := range decisions {
+		if decision.Selected {
+			selected[decision.ClusterName] = true
+		}
+	}
+	removing := make(map[string]fleetv1beta1.ClusterRemovalStatus)
+	for i := range bindings {
+		binding := &bindings[i]
+		clusterName := binding.Spec.TargetCluster
+		if clusterName == "" {
+			continue
+		}
+		switch {
+		case !binding.DeletionTimestamp.IsZero():
+			message := "The resources are being deleted from the cluster"
+			if cond := binding.GetCondition(string(fleetv1beta1.ResourceBindingResourcesDeleted)); cond != nil && cond.Message != "" {
+				message = cond.Message
+			}
+			removing[clusterName] = fleetv1beta1.ClusterRemovalStatus{
+				ClusterName: clusterName,
+				Phase:       fleetv1beta1.ClusterRemovalPhaseDeleting,
+				Message:     message,
+			}
+		case binding.Spec.State == fleetv1beta1.BindingStateUnscheduled:
+			if status, ok := removing[clusterName]; ok && status.Phase == fleetv1beta1.ClusterRemovalPhaseDeleting {
+				continue
+			}
+			removing[clusterName] = fleetv1beta1.ClusterRemovalStatus{
+				ClusterName: clusterName,
+				Phase:       fleetv1beta1.ClusterRemovalPhasePending,
+				Message:     "The resources are waiting to be removed as the rollout strategy does not allow more clusters to become unavailable yet",
+			}
+		default:
+			selected[clusterName] = true
+		}
+	}
+	var res []fleetv1beta1.ClusterRemovalStatus
+	for clusterName, status := range removing {
+		if !selected[clusterName] {
+			res = append(res, status)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ClusterName < res[j].ClusterName
+	})
+	if len(res) > maxRemovingClusters {
+		res = res[:maxRemovingClusters]
+	}
+	return res
 }
 
 // setResourcePlacementStatusPerCluster sets the resource related fields for each cluster.
@@ -232,6 +356,8 @@ func (r *Reconciler) setResourcePlacementStatusPerCluster(crp *fleetv1beta1.Clus
 		meta.SetStatusCondition(&status.Conditions, condition.RolloutStartedCondition.UnknownResourceConditionPerCluster(crp.Generation))
 		return []metav1.ConditionStatus{metav1.ConditionUnknown}, nil
 	}
+	// the load balancers exist on the cluster no matter which resource snapshot is being rolled out
+	status.LoadBalancers = binding.Status.LoadBalancers
 
 	res := make([]metav1.ConditionStatus, 0, condition.TotalCondition)
 	// There are few cases:
@@ -259,6 +385,7 @@ func (r *Reconciler) setResourcePlacementStatusPerCluster(crp *fleetv1beta1.Clus
 			case condition.AppliedCondition, condition.AvailableCondition:
 				if bindingCond.Status == metav1.ConditionFalse {
 					status.FailedPlacements = binding.Status.FailedPlacements
+					status.FailedPlacementsOverflow = binding.Status.FailedPlacementsOverflow
 				}
 			}
 			cond := metav1.Condition{
@@ -3416,7 +3416,7 @@ func TestBuildClusterResourceBindings(t *testing.T) {
 			r := Reconciler{
 				Client: fakeClient,
 			}
-			got, err := r.buildClusterResourceBindings(ctx, &crp, &policySnapshot)
+			got, _, err := r.buildClusterResourceBindings(ctx, &crp, &policySnapshot)
 			if err != nil {
 				t.Fatalf("buildClusterResourceBindings() got err %v, want nil", err)
 			}
@@ -3427,6 +3427,82 @@ func TestBuildClusterResourceBindings(t *testing.T) {
 	}
 }
 
+func TestBuildClusterRemovalStatuses(t *testing.T) {
+	deletionTime := &metav1.Time{Time: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)}
+	binding := func(cluster string, state fleetv1beta1.BindingState, deleting bool, conditions ...metav1.Condition) fleetv1beta1.ClusterResourceBinding {
+		b := fleetv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding-" + cluster},
+			Spec:       fleetv1beta1.ResourceBindingSpec{State: state, TargetCluster: cluster},
+			Status:     fleetv1beta1.ResourceBindingStatus{Conditions: conditions},
+		}
+		if deleting {
+			b.DeletionTimestamp = deletionTime
+		}
+		return b
+	}
+	resourcesDeleting := metav1.Condition{
+		Type:    string(fleetv1beta1.ResourceBindingResourcesDeleted),
+		Status:  metav1.ConditionFalse,
+		Reason:  condition.ResourcesDeletingReason,
+		Message: "2 works are still being deleted along with their resources in the member cluster member-2",
+	}
+	pendingMessage := "The resources are waiting to be removed as the rollout strategy does not allow more clusters to become unavailable yet"
+	tests := map[string]struct {
+		bindings  []fleetv1beta1.ClusterResourceBinding
+		decisions []fleetv1beta1.ClusterDecision
+		want      []fleetv1beta1.ClusterRemovalStatus
+	}{
+		"no removing clusters": {
+			bindings: []fleetv1beta1.ClusterResourceBinding{
+				binding("member-1", fleetv1beta1.BindingStateBound, false),
+				binding("member-2", fleetv1beta1.BindingStateScheduled, false),
+			},
+		},
+		"pending and deleting clusters sorted by name": {
+			bindings: []fleetv1beta1.ClusterResourceBinding{
+				binding("member-3", fleetv1beta1.BindingStateUnscheduled, false),
+				binding("member-1", fleetv1beta1.BindingStateBound, false),
+				binding("member-2", fleetv1beta1.BindingStateUnscheduled, true, resourcesDeleting),
+				binding("member-4", fleetv1beta1.BindingStateBound, true),
+			},
+			want: []fleetv1beta1.ClusterRemovalStatus{
+				{ClusterName: "member-2", Phase: fleetv1beta1.ClusterRemovalPhaseDeleting, Message: resourcesDeleting.Message},
+				{ClusterName: "member-3", Phase: fleetv1beta1.ClusterRemovalPhasePending, Message: pendingMessage},
+				{ClusterName: "member-4", Phase: fleetv1beta1.ClusterRemovalPhaseDeleting, Message: "The resources are being deleted from the cluster"},
+			},
+		},
+		"deleting binding wins over the unscheduled one of the same cluster": {
+			bindings: []fleetv1beta1.ClusterResourceBinding{
+				binding("member-2", fleetv1beta1.BindingStateUnscheduled, true, resourcesDeleting),
+				binding("member-2", fleetv1beta1.BindingStateUnscheduled, false),
+			},
+			want: []fleetv1beta1.ClusterRemovalStatus{
+				{ClusterName: "member-2", Phase: fleetv1beta1.ClusterRemovalPhaseDeleting, Message: resourcesDeleting.Message},
+			},
+		},
+		"cluster selected again": {
+			bindings: []fleetv1beta1.ClusterResourceBinding{
+				binding("member-1", fleetv1beta1.BindingStateUnscheduled, true),
+				binding("member-1", fleetv1beta1.BindingStateScheduled, false),
+			},
+		},
+		"cluster selected by the scheduler before its new binding is created": {
+			bindings: []fleetv1beta1.ClusterResourceBinding{
+				binding("member-1", fleetv1beta1.BindingStateBound, true),
+			},
+			decisions: []fleetv1beta1.ClusterDecision{{ClusterName: "member-1", Selected: true}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := buildClusterRemovalStatuses(tt.bindings, tt.decisions)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("buildClusterRemovalStatuses() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestSetResourcePlacementStatusPerCluster(t *testing.T) {
 	resourceSnapshotName := "snapshot-1"
 	cluster := "member-1"
@@ -4112,3 +4188,43 @@ func TestSetResourcePlacementStatusPerCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestIsProvisioning(t *testing.T) {
+	available := func(status metav1.ConditionStatus, reason string) fleetv1beta1.ResourcePlacementStatus {
+		return fleetv1beta1.ResourcePlacementStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(fleetv1beta1.ResourcesAvailableConditionType),
+					Status: status,
+					Reason: reason,
+				},
+			},
+		}
+	}
+	tests := map[string]struct {
+		statuses []fleetv1beta1.ResourcePlacementStatus
+		want     bool
+	}{
+		"all unavailable clusters are provisioning": {
+			statuses: []fleetv1beta1.ResourcePlacementStatus{
+				available(metav1.ConditionTrue, condition.AllWorkAvailableReason),
+				available(metav1.ConditionFalse, condition.ProvisioningReason),
+			},
+			want: true,
+		},
+		"one cluster is not available for another reason": {
+			statuses: []fleetv1beta1.ResourcePlacementStatus{
+				available(metav1.ConditionFalse, condition.ProvisioningReason),
+				available(metav1.ConditionFalse, condition.WorkNotAvailableReason),
+			},
+			want: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isProvisioning(tc.statuses); got != tc.want {
+				t.Errorf("isProvisioning() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
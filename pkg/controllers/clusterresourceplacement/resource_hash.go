@@ -0,0 +1,51 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// hashOfResourceSnapshotSpec returns the same hash as resource.HashOf(spec), but it streams the selected resources
+// into the hash one at a time instead of marshaling the whole spec at once, so that hashing a large selection does
+// not hold another copy of all the selected resources in memory.
+func hashOfResourceSnapshotSpec(spec *fleetv1beta1.ResourceSnapshotSpec) (string, error) {
+	hash := sha256.New()
+	if spec.SelectedResources == nil {
+		// a nil slice is marshaled as null
+		_, _ = io.WriteString(hash, `{"selectedResources":null}`)
+		return fmt.Sprintf("%x", hash.Sum(nil)), nil
+	}
+	_, _ = io.WriteString(hash, `{"selectedResources":[`)
+	var compacted, escaped bytes.Buffer
+	for i := range spec.SelectedResources {
+		if i > 0 {
+			_, _ = io.WriteString(hash, ",")
+		}
+		raw := spec.SelectedResources[i].Raw
+		if raw == nil {
+			// the same as runtime.RawExtension.MarshalJSON
+			_, _ = io.WriteString(hash, "null")
+			continue
+		}
+		// json.Marshal compacts the output of the json.Marshaler and escapes the HTML characters in it
+		compacted.Reset()
+		if err := json.Compact(&compacted, raw); err != nil {
+			return "", fmt.Errorf("failed to compact the selected resource #%d: %w", i, err)
+		}
+		escaped.Reset()
+		json.HTMLEscape(&escaped, compacted.Bytes())
+		_, _ = hash.Write(escaped.Bytes())
+	}
+	_, _ = io.WriteString(hash, "]}")
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
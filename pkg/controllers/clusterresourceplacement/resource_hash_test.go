@@ -0,0 +1,55 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/resource"
+)
+
+func TestHashOfResourceSnapshotSpec(t *testing.T) {
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "config", "namespace": "app"},
+		"data":       map[string]interface{}{"index.html": "<html>&nbsp; </html>"},
+	}}
+	configMapContent, err := generateResourceContent(configMap)
+	if err != nil {
+		t.Fatalf("failed to generate the resource content: %v", err)
+	}
+	tests := map[string]*fleetv1beta1.ResourceSnapshotSpec{
+		"nil selected resources":   {},
+		"empty selected resources": {SelectedResources: []fleetv1beta1.ResourceContent{}},
+		"selected resources": {
+			SelectedResources: []fleetv1beta1.ResourceContent{
+				*configMapContent,
+				{RawExtension: runtime.RawExtension{Raw: []byte("{\n  \"apiVersion\": \"v1\",\n  \"kind\": \"Namespace\",\n  \"metadata\": {\"name\": \"app\", \"annotations\": {\"note\": \"<b>&\u2028</b>\"}}\n}\n")}},
+				{},
+			},
+		},
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			want, err := resource.HashOf(spec)
+			if err != nil {
+				t.Fatalf("HashOf() = %v, want nil", err)
+			}
+			got, err := hashOfResourceSnapshotSpec(spec)
+			if err != nil {
+				t.Fatalf("hashOfResourceSnapshotSpec() = %v, want nil", err)
+			}
+			if got != want {
+				t.Errorf("hashOfResourceSnapshotSpec() = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -6,18 +6,22 @@ Licensed under the MIT license.
 package clusterresourceplacement
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	workv1alpha1 "sigs.k8s.io/work-api/pkg/apis/v1alpha1"
@@ -72,6 +76,25 @@ func convertResourceSelector(old []fleetv1alpha1.ClusterResourceSelector) []flee
 // gatherSelectedResource gets all the resources according to the resource selector.
 func (r *Reconciler) gatherSelectedResource(placement string, selectors []fleetv1beta1.ClusterResourceSelector) ([]runtime.Object, error) {
 	var resources []runtime.Object
+	if err := r.visitSelectedResources(placement, selectors, func(objs []runtime.Object) error {
+		resources = append(resources, objs...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	// sort the resources in strict order so that we will get the stable list of manifest so that
+	// the generated work object doesn't change between reconcile loops
+	sortResources(resources)
+
+	return resources, nil
+}
+
+// resourceVisitor is called with the selected resources batch by batch as they are fetched.
+type resourceVisitor func(objs []runtime.Object) error
+
+// visitSelectedResources fetches the resources according to the resource selectors and passes them to visit batch by
+// batch, e.g. the objects of one resource type in one namespace at a time; it stops as soon as visit returns an error.
+func (r *Reconciler) visitSelectedResources(placement string, selectors []fleetv1beta1.ClusterResourceSelector, visit resourceVisitor) error {
 	for _, selector := range selectors {
 		gvk := schema.GroupVersionKind{
 			Group:   selector.Group,
@@ -79,50 +102,134 @@ func (r *Reconciler) gatherSelectedResource(placement string, selectors []fleetv
 			Kind:    selector.Kind,
 		}
 
+		// the placement sources are selected by their rendered resources even though the fleet APIs are never selected
+		if utils.IsPlacementSourceSelector(selector) {
+			objs, err := r.fetchPlacementSourceResources(selector, placement)
+			if err != nil {
+				return err
+			}
+			if err := visit(objs); err != nil {
+				return err
+			}
+			continue
+		}
 		if r.ResourceConfig.IsResourceDisabled(gvk) {
 			klog.V(2).InfoS("Skip select resource", "group version kind", gvk.String())
 			continue
 		}
-		var objs []runtime.Object
-		var err error
 		if gvk == utils.NamespaceGVK {
-			objs, err = r.fetchNamespaceResources(selector, placement)
-		} else {
-			objs, err = r.fetchClusterScopedResources(selector, placement)
+			if err := r.fetchNamespaceResources(selector, placement, visit); err != nil {
+				return err
+			}
+			continue
 		}
+		objs, err := r.fetchClusterScopedResources(selector, placement)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := visit(objs); err != nil {
+			return err
 		}
-		resources = append(resources, objs...)
 	}
-	// sort the resources in strict order so that we will get the stable list of manifest so that
-	// the generated work object doesn't change between reconcile loops
-	sortResources(resources)
-
-	return resources, nil
+	return nil
 }
 func sortResources(resources []runtime.Object) {
-	sort.Slice(resources, func(i, j int) bool {
-		obj1 := resources[i].DeepCopyObject().(*unstructured.Unstructured)
-		obj2 := resources[j].DeepCopyObject().(*unstructured.Unstructured)
-		if obj1.GetObjectKind().GroupVersionKind().String() == utils.NamespaceMetaGVK.String() || obj2.GetObjectKind().GroupVersionKind().String() == utils.NamespaceMetaGVK.String() {
-			return obj1.GetObjectKind().GroupVersionKind().String() == utils.NamespaceMetaGVK.String()
-		}
-		if obj1.GetObjectKind().GroupVersionKind().String() == utils.CRDMetaGVK.String() || obj2.GetObjectKind().GroupVersionKind().String() == utils.CRDMetaGVK.String() {
-			return obj1.GetObjectKind().GroupVersionKind().String() == utils.CRDMetaGVK.String()
-		}
-		// compare group/version;kind
-		gvkComp := strings.Compare(obj1.GroupVersionKind().String(), obj2.GroupVersionKind().String())
-		if gvkComp > 0 {
-			return true
+	// the keys are computed once per object instead of per comparison, so that sorting a large selection does not
+	// copy the objects over and over again
+	keys := make([]resourceSortKey, len(resources))
+	for i := range resources {
+		keys[i] = newResourceSortKey(resources[i])
+	}
+	sort.Sort(resourcesByKey{resources: resources, keys: keys})
+}
+
+// resourceSortKey is the key by which the selected resources are sorted.
+type resourceSortKey struct {
+	// applyOrder puts the resources which the others depend on in front.
+	applyOrder     int
+	gvk            string
+	namespacedName string
+}
+
+// applyOrders are the orders in which the resources which the others depend on are applied; the namespaces and the
+// custom resource definitions go first, and the storage classes go before the persistent volume claims which refer to
+// them, which in turn go before the workloads which mount them. The other resources go last.
+var applyOrders = map[string]int{
+	utils.NamespaceMetaGVK.String():             0,
+	utils.CRDMetaGVK.String():                   1,
+	utils.StorageClassMetaGVK.String():          2,
+	utils.PersistentVolumeClaimMetaGVK.String(): 3,
+}
+
+func newResourceSortKey(obj runtime.Object) resourceSortKey {
+	key := resourceSortKey{gvk: obj.GetObjectKind().GroupVersionKind().String()}
+	if order, found := applyOrders[key.gvk]; found {
+		key.applyOrder = order
+	} else {
+		key.applyOrder = len(applyOrders)
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		key.namespacedName = fmt.Sprintf("%s/%s", accessor.GetNamespace(), accessor.GetName())
+	}
+	return key
+}
+
+// resourcesByKey sorts the resources along with their keys.
+type resourcesByKey struct {
+	resources []runtime.Object
+	keys      []resourceSortKey
+}
+
+func (s resourcesByKey) Len() int { return len(s.resources) }
+
+func (s resourcesByKey) Swap(i, j int) {
+	s.resources[i], s.resources[j] = s.resources[j], s.resources[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+func (s resourcesByKey) Less(i, j int) bool {
+	return s.keys[i].less(s.keys[j])
+}
+
+// less returns whether the resource of the key goes before the resource of the other key.
+func (key1 resourceSortKey) less(key2 resourceSortKey) bool {
+	if key1.applyOrder != key2.applyOrder {
+		return key1.applyOrder < key2.applyOrder
+	}
+	// compare group/version;kind
+	gvkComp := strings.Compare(key1.gvk, key2.gvk)
+	if gvkComp > 0 {
+		return true
+	}
+	if gvkComp < 0 {
+		return false
+	}
+	// same gvk, compare namespace/name
+	return strings.Compare(key1.namespacedName, key2.namespacedName) > 0
+}
+
+// fetchPlacementSourceResources retrieves the resources rendered by the placement source that the selector selects.
+func (r *Reconciler) fetchPlacementSourceResources(selector fleetv1beta1.ClusterResourceSelector, placeName string) ([]runtime.Object, error) {
+	klog.V(2).InfoS("start to fetch the rendered resources of the placement source", "selector", selector, "placement", placeName)
+	var source fleetv1beta1.PlacementSource
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: selector.Name}, &source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, controller.NewUserError(fmt.Errorf("the placement source %s selected by the placement %s is not found", selector.Name, placeName))
 		}
-		if gvkComp < 0 {
-			return false
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	if source.Status.ObservedRevision == "" {
+		return nil, controller.NewExpectedBehaviorError(fmt.Errorf("the placement source %s has not been rendered yet", selector.Name))
+	}
+	objs := make([]runtime.Object, 0, len(source.Status.Resources))
+	for i := range source.Status.Resources {
+		var obj unstructured.Unstructured
+		if err := obj.UnmarshalJSON(source.Status.Resources[i].Raw); err != nil {
+			return nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("failed to decode a rendered resource of the placement source %s: %w", selector.Name, err))
 		}
-		// same gvk, compare namespace/name
-		return strings.Compare(fmt.Sprintf("%s/%s", obj1.GetNamespace(), obj1.GetName()),
-			fmt.Sprintf("%s/%s", obj2.GetNamespace(), obj2.GetName())) > 0
-	})
+		objs = append(objs, &obj)
+	}
+	return objs, nil
 }
 
 // fetchClusterScopedResources retrieves the objects based on the selector.
@@ -164,7 +271,7 @@ func (r *Reconciler) fetchClusterScopedResources(selector fleetv1beta1.ClusterRe
 				"selector", selector, "placeName", placeName, "resource name", uObj.GetName())
 			return []runtime.Object{}, nil
 		}
-		return []runtime.Object{obj}, nil
+		return r.fetchFullObjects(gvr, "", []runtime.Object{obj})
 	}
 
 	var labelSelector labels.Selector
@@ -194,22 +301,20 @@ func (r *Reconciler) fetchClusterScopedResources(selector fleetv1beta1.ClusterRe
 		selectedObjs = append(selectedObjs, objects[i])
 	}
 
-	return selectedObjs, nil
+	return r.fetchFullObjects(gvr, "", selectedObjs)
 }
 
-// fetchNamespaceResources retrieves all the objects for a ClusterResourceSelector that is for namespace.
-func (r *Reconciler) fetchNamespaceResources(selector fleetv1beta1.ClusterResourceSelector, placeName string) ([]runtime.Object, error) {
+// fetchNamespaceResources retrieves all the objects for a ClusterResourceSelector that is for namespace, and passes
+// them to visit.
+func (r *Reconciler) fetchNamespaceResources(selector fleetv1beta1.ClusterResourceSelector, placeName string, visit resourceVisitor) error {
 	klog.V(2).InfoS("start to fetch the namespace resources by the selector", "selector", selector)
-	var resources []runtime.Object
-
 	if len(selector.Name) != 0 {
 		// just a single namespace
-		objs, err := r.fetchAllResourcesInOneNamespace(selector.Name, placeName)
-		if err != nil {
+		if err := r.fetchAllResourcesInOneNamespace(selector.Name, placeName, visit); err != nil {
 			klog.ErrorS(err, "failed to fetch all the selected resource in a namespace", "namespace", selector.Name)
-			return nil, err
+			return err
 		}
-		return objs, err
+		return nil
 	}
 
 	// go through each namespace
@@ -220,36 +325,33 @@ func (r *Reconciler) fetchNamespaceResources(selector fleetv1beta1.ClusterResour
 	} else {
 		labelSelector, err = metav1.LabelSelectorAsSelector(selector.LabelSelector)
 		if err != nil {
-			return nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("cannot convert the label selector to a selector: %w", err))
+			return controller.NewUnexpectedBehaviorError(fmt.Errorf("cannot convert the label selector to a selector: %w", err))
 		}
 	}
 	namespaces, err := r.InformerManager.Lister(utils.NamespaceGVR).List(labelSelector)
 	if err != nil {
-		return nil, controller.NewAPIServerError(true, fmt.Errorf("cannot list all the namespaces given the label selector: %w", err))
+		return controller.NewAPIServerError(true, fmt.Errorf("cannot list all the namespaces given the label selector: %w", err))
 	}
 
 	for _, namespace := range namespaces {
 		ns, err := meta.Accessor(namespace)
 		if err != nil {
-			return nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("cannot get the name of a namespace object: %w", err))
+			return controller.NewUnexpectedBehaviorError(fmt.Errorf("cannot get the name of a namespace object: %w", err))
 		}
-		objs, err := r.fetchAllResourcesInOneNamespace(ns.GetName(), placeName)
-		if err != nil {
+		if err := r.fetchAllResourcesInOneNamespace(ns.GetName(), placeName, visit); err != nil {
 			klog.ErrorS(err, "failed to fetch all the selected resource in a namespace", "namespace", ns.GetName())
-			return nil, err
+			return err
 		}
-		resources = append(resources, objs...)
 	}
-	return resources, nil
+	return nil
 }
 
-// fetchAllResourcesInOneNamespace retrieves all the objects inside a single namespace which includes the namespace itself.
-func (r *Reconciler) fetchAllResourcesInOneNamespace(namespaceName string, placeName string) ([]runtime.Object, error) {
-	var resources []runtime.Object
-
+// fetchAllResourcesInOneNamespace retrieves all the objects inside a single namespace which includes the namespace
+// itself, and passes them to visit one resource type at a time.
+func (r *Reconciler) fetchAllResourcesInOneNamespace(namespaceName string, placeName string, visit resourceVisitor) error {
 	if !utils.ShouldPropagateNamespace(namespaceName, r.SkippedNamespaces) {
 		err := fmt.Errorf("invalid clusterRresourcePlacement %s: namespace %s is not allowed to propagate", placeName, namespaceName)
-		return nil, controller.NewUserError(err)
+		return controller.NewUserError(err)
 	}
 
 	klog.V(2).InfoS("start to fetch all the resources inside a namespace", "namespace", namespaceName)
@@ -257,16 +359,22 @@ func (r *Reconciler) fetchAllResourcesInOneNamespace(namespaceName string, place
 	obj, err := r.InformerManager.Lister(utils.NamespaceGVR).Get(namespaceName)
 	if err != nil {
 		klog.ErrorS(err, "cannot get the namespace", "namespace", namespaceName)
-		return nil, controller.NewAPIServerError(true, client.IgnoreNotFound(err))
+		return controller.NewAPIServerError(true, client.IgnoreNotFound(err))
 	}
 	nameSpaceObj := obj.DeepCopyObject().(*unstructured.Unstructured)
 	if nameSpaceObj.GetDeletionTimestamp() != nil {
 		// skip a to be deleted namespace
 		klog.V(2).InfoS("skip the deleting namespace resources by the selector",
 			"placeName", placeName, "namespace", namespaceName)
-		return resources, nil
+		return nil
+	}
+	nsObjs, err := r.fetchFullObjects(utils.NamespaceGVR, "", []runtime.Object{obj})
+	if err != nil {
+		return err
+	}
+	if err := visit(nsObjs); err != nil {
+		return err
 	}
-	resources = append(resources, obj)
 
 	trackedResource := r.InformerManager.GetNameSpaceScopedResources()
 	for _, gvr := range trackedResource {
@@ -274,27 +382,80 @@ func (r *Reconciler) fetchAllResourcesInOneNamespace(namespaceName string, place
 			continue
 		}
 		if !r.InformerManager.IsInformerSynced(gvr) {
-			return nil, controller.NewExpectedBehaviorError(fmt.Errorf("informer cache for %+v is not synced yet", gvr))
+			return controller.NewExpectedBehaviorError(fmt.Errorf("informer cache for %+v is not synced yet", gvr))
 		}
 		lister := r.InformerManager.Lister(gvr)
 		objs, err := lister.ByNamespace(namespaceName).List(labels.Everything())
 		if err != nil {
-			return nil, controller.NewAPIServerError(true, fmt.Errorf("cannot list all the objects of type %+v in namespace %s: %w", gvr, namespaceName, err))
+			return controller.NewAPIServerError(true, fmt.Errorf("cannot list all the objects of type %+v in namespace %s: %w", gvr, namespaceName, err))
 		}
+		if objs, err = r.fetchFullObjects(gvr, namespaceName, objs); err != nil {
+			return err
+		}
+		resources := make([]runtime.Object, 0, len(objs))
 		for _, obj := range objs {
 			uObj := obj.DeepCopyObject().(*unstructured.Unstructured)
 			shouldInclude, err := utils.ShouldPropagateObj(r.InformerManager, uObj)
 			if err != nil {
 				klog.ErrorS(err, "cannot determine if we should propagate an object", "object", klog.KObj(uObj))
-				return nil, err
+				return err
 			}
 			if shouldInclude {
 				resources = append(resources, obj)
 			}
 		}
+		if err := visit(resources); err != nil {
+			return err
+		}
 	}
 
-	return resources, nil
+	return nil
+}
+
+// fetchFullObjects returns the full objects of the objects of the resource read from the informer cache. The cache
+// has the metadata of the objects of a metadata-only resource only, so they are fetched from the API server, with a
+// single get for one object or a single list for more; the objects which are gone meanwhile are skipped.
+func (r *Reconciler) fetchFullObjects(gvr schema.GroupVersionResource, namespace string, objs []runtime.Object) ([]runtime.Object, error) {
+	if len(objs) == 0 || !r.InformerManager.IsMetadataOnly(gvr) {
+		return objs, nil
+	}
+	var resourceClient dynamic.ResourceInterface = r.InformerManager.GetClient().Resource(gvr)
+	if namespace != "" {
+		resourceClient = r.InformerManager.GetClient().Resource(gvr).Namespace(namespace)
+	}
+	names := make(map[string]bool, len(objs))
+	var name string
+	for _, obj := range objs {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("cannot get the name of an object of type %+v: %w", gvr, err))
+		}
+		name = objMeta.GetName()
+		names[name] = true
+	}
+
+	if len(names) == 1 {
+		obj, err := resourceClient.Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return []runtime.Object{}, nil
+			}
+			return nil, controller.NewAPIServerError(false, fmt.Errorf("cannot get the object %s of type %+v: %w", name, gvr, err))
+		}
+		return []runtime.Object{obj}, nil
+	}
+	klog.V(2).InfoS("Fetching the full objects of a metadata-only resource", "gvr", gvr, "namespace", namespace, "count", len(objs))
+	list, err := resourceClient.List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, controller.NewAPIServerError(false, fmt.Errorf("cannot list the objects of type %+v in namespace %q: %w", gvr, namespace, err))
+	}
+	fullObjs := make([]runtime.Object, 0, len(objs))
+	for i := range list.Items {
+		if names[list.Items[i].GetName()] {
+			fullObjs = append(fullObjs, &list.Items[i])
+		}
+	}
+	return fullObjs, nil
 }
 
 // shouldSelectResource returns whether a resource should be selected for propagation.
@@ -408,37 +569,84 @@ func generateResourceContent(object *unstructured.Unstructured) (*fleetv1beta1.R
 	}, nil
 }
 
+// selectedResource is a selected resource which is serialized as soon as it is fetched.
+type selectedResource struct {
+	key        resourceSortKey
+	content    fleetv1beta1.ResourceContent
+	identifier fleetv1beta1.ResourceIdentifier
+	isEnvelope bool
+}
+
+// resourceSerializer serializes the selected resources batch by batch as they are fetched, so that only the
+// serialized resources are held in memory instead of all the selected objects, and fails as soon as the serialized
+// resources exceed the budget if it is set.
+type resourceSerializer struct {
+	budget    int64
+	totalSize int64
+	resources []selectedResource
+}
+
+// serialize is the resourceVisitor which serializes a batch of the selected resources.
+func (s *resourceSerializer) serialize(objs []runtime.Object) error {
+	for i := range objs {
+		unstructuredObj := objs[i].DeepCopyObject().(*unstructured.Unstructured)
+		// drop the reference to the object so that the objects which are not in the informer cache, e.g. the ones
+		// fetched for the metadata-only resources, can be garbage collected while the rest are serialized
+		objs[i] = nil
+		gvk := unstructuredObj.GroupVersionKind()
+		resource := selectedResource{
+			key: newResourceSortKey(unstructuredObj),
+			identifier: fleetv1beta1.ResourceIdentifier{
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Kind:      gvk.Kind,
+				Name:      unstructuredObj.GetName(),
+				Namespace: unstructuredObj.GetNamespace(),
+			},
+		}
+		_, resource.isEnvelope = utils.GetEnvelopeType(unstructuredObj)
+		rc, err := generateResourceContent(unstructuredObj)
+		if err != nil {
+			return err
+		}
+		s.totalSize += int64(len(rc.Raw))
+		if s.budget > 0 && s.totalSize > s.budget {
+			return controller.NewUserError(fmt.Errorf("the first %d selected resources take %d bytes, which exceeds the resource snapshot memory budget of %d bytes; select fewer resources or split them across placements",
+				len(s.resources)+1, s.totalSize, s.budget))
+		}
+		resource.content = *rc
+		s.resources = append(s.resources, resource)
+	}
+	return nil
+}
+
 // selectResourcesForPlacement selects the resources according to the placement resourceSelectors.
 // It also generates an array of resource content and resource identifier based on the selected resources.
 // It also returns the number of envelope configmaps so the CRP controller can have the right expectation of the number of work objects.
+// The selected objects are serialized as they are fetched and released once serialized, and the selection stops with
+// a user error as soon as the serialized resources exceed the resource snapshot memory budget if it is set, before
+// the rest of the resources are fetched.
 func (r *Reconciler) selectResourcesForPlacement(placement *fleetv1beta1.ClusterResourcePlacement) (int, []fleetv1beta1.ResourceContent, []fleetv1beta1.ResourceIdentifier, error) {
-	envelopeObjCount := 0
-	selectedObjects, err := r.gatherSelectedResource(placement.GetName(), placement.Spec.ResourceSelectors)
-	if err != nil {
+	serializer := &resourceSerializer{budget: r.ResourceSnapshotMemoryBudget}
+	if err := r.visitSelectedResources(placement.GetName(), placement.Spec.ResourceSelectors, serializer.serialize); err != nil {
 		return 0, nil, nil, err
 	}
+	// sort the resources in strict order so that we will get the stable list of manifest so that
+	// the generated work object doesn't change between reconcile loops
+	selected := serializer.resources
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].key.less(selected[j].key)
+	})
 
-	resources := make([]fleetv1beta1.ResourceContent, len(selectedObjects))
-	resourcesIDs := make([]fleetv1beta1.ResourceIdentifier, len(selectedObjects))
-	for i, obj := range selectedObjects {
-		unstructuredObj := obj.DeepCopyObject().(*unstructured.Unstructured)
-		rc, err := generateResourceContent(unstructuredObj)
-		if err != nil {
-			return 0, nil, nil, err
-		}
-		if unstructuredObj.GetObjectKind().GroupVersionKind() == utils.ConfigMapGVK &&
-			len(unstructuredObj.GetAnnotations()[fleetv1beta1.EnvelopeConfigMapAnnotation]) != 0 {
+	envelopeObjCount := 0
+	resources := make([]fleetv1beta1.ResourceContent, len(selected))
+	resourcesIDs := make([]fleetv1beta1.ResourceIdentifier, len(selected))
+	for i := range selected {
+		if selected[i].isEnvelope {
 			envelopeObjCount++
 		}
-		resources[i] = *rc
-		ri := fleetv1beta1.ResourceIdentifier{
-			Group:     unstructuredObj.GroupVersionKind().Group,
-			Version:   unstructuredObj.GroupVersionKind().Version,
-			Kind:      unstructuredObj.GroupVersionKind().Kind,
-			Name:      unstructuredObj.GetName(),
-			Namespace: unstructuredObj.GetNamespace(),
-		}
-		resourcesIDs[i] = ri
+		resources[i] = selected[i].content
+		resourcesIDs[i] = selected[i].identifier
 	}
 	return envelopeObjCount, resources, resourcesIDs, nil
 }
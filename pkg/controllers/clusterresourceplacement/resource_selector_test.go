@@ -6,6 +6,8 @@ Licensed under the MIT license.
 package clusterresourceplacement
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,12 +19,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	workv1alpha1 "sigs.k8s.io/work-api/pkg/apis/v1alpha1"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/fleettest"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
 )
 
 func TestGenerateManifest(t *testing.T) {
@@ -841,6 +849,29 @@ func TestSortResource(t *testing.T) {
 		},
 	}
 
+	// Create the StorageClass object
+	storageClass := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "storage.k8s.io/v1",
+			"kind":       "StorageClass",
+			"metadata": map[string]interface{}{
+				"name": "test-storageclass",
+			},
+		},
+	}
+
+	// Create the PersistentVolumeClaim object
+	pvc := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "PersistentVolumeClaim",
+			"metadata": map[string]interface{}{
+				"name":      "test-pvc",
+				"namespace": "test",
+			},
+		},
+	}
+
 	tests := map[string]struct {
 		resources []runtime.Object
 		want      []runtime.Object
@@ -857,6 +888,10 @@ func TestSortResource(t *testing.T) {
 			resources: []runtime.Object{deployment, clusterRole, crd, namespace},
 			want:      []runtime.Object{namespace, crd, clusterRole, deployment},
 		},
+		"should gather selected resources with StorageClass and PersistentVolumeClaim before the workloads": {
+			resources: []runtime.Object{deployment, pvc, clusterRole, storageClass, crd, namespace},
+			want:      []runtime.Object{namespace, crd, storageClass, pvc, clusterRole, deployment},
+		},
 	}
 
 	for testName, tt := range tests {
@@ -871,3 +906,220 @@ func TestSortResource(t *testing.T) {
 		})
 	}
 }
+
+func TestGatherSelectedResourceFromPlacementSource(t *testing.T) {
+	selector := fleetv1beta1.ClusterResourceSelector{
+		Group:   fleetv1beta1.GroupVersion.Group,
+		Version: fleetv1beta1.GroupVersion.Version,
+		Kind:    fleetv1beta1.PlacementSourceKind,
+		Name:    "app-source",
+	}
+	tests := map[string]struct {
+		source  *fleetv1beta1.PlacementSource
+		want    []runtime.Object
+		wantErr error
+	}{
+		"rendered placement source": {
+			source: &fleetv1beta1.PlacementSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-source"},
+				Status: fleetv1beta1.PlacementSourceStatus{
+					ObservedRevision: "rev-1",
+					Resources: []fleetv1beta1.ResourceContent{
+						{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`)}},
+						{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)}},
+					},
+				},
+			},
+			want: []runtime.Object{
+				&unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": "app"}}},
+				&unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "config", "namespace": "app"}}},
+			},
+		},
+		"placement source not rendered yet": {
+			source: &fleetv1beta1.PlacementSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-source"},
+			},
+			wantErr: controller.ErrExpectedBehavior,
+		},
+		"placement source not found": {
+			wantErr: controller.ErrUserError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(serviceScheme(t))
+			if tc.source != nil {
+				builder = builder.WithObjects(tc.source)
+			}
+			r := Reconciler{
+				Client:         builder.Build(),
+				ResourceConfig: utils.NewResourceConfig(false),
+			}
+			got, err := r.gatherSelectedResource("test-crp", []fleetv1beta1.ClusterResourceSelector{selector})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("gatherSelectedResource() = %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("gatherSelectedResource() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelectResourcesForPlacementMemoryBudget(t *testing.T) {
+	source := &fleetv1beta1.PlacementSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-source"},
+		Status: fleetv1beta1.PlacementSourceStatus{
+			ObservedRevision: "rev-1",
+			Resources: []fleetv1beta1.ResourceContent{
+				{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`)}},
+				{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)}},
+			},
+		},
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-crp"},
+		Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []fleetv1beta1.ClusterResourceSelector{
+				{
+					Group:   fleetv1beta1.GroupVersion.Group,
+					Version: fleetv1beta1.GroupVersion.Version,
+					Kind:    fleetv1beta1.PlacementSourceKind,
+					Name:    "app-source",
+				},
+			},
+		},
+	}
+	tests := map[string]struct {
+		budget    int64
+		wantCount int
+		wantErr   error
+	}{
+		"no budget": {
+			wantCount: 2,
+		},
+		"within the budget": {
+			budget:    1024,
+			wantCount: 2,
+		},
+		"exceeding the budget": {
+			budget:  100,
+			wantErr: controller.ErrUserError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := Reconciler{
+				Client:                       fake.NewClientBuilder().WithScheme(serviceScheme(t)).WithObjects(source).Build(),
+				ResourceConfig:               utils.NewResourceConfig(false),
+				ResourceSnapshotMemoryBudget: tc.budget,
+			}
+			_, resources, resourceIDs, err := r.selectResourcesForPlacement(crp)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("selectResourcesForPlacement() = %v, want %v", err, tc.wantErr)
+			}
+			if len(resources) != tc.wantCount || len(resourceIDs) != tc.wantCount {
+				t.Errorf("selectResourcesForPlacement() = %d resources and %d identifiers, want %d", len(resources), len(resourceIDs), tc.wantCount)
+			}
+		})
+	}
+}
+
+// TestSelectResourcesForPlacementStopsAtMemoryBudget verifies that the selection stops as soon as the budget is
+// exceeded, without fetching the resources of the rest of the selectors.
+func TestSelectResourcesForPlacementStopsAtMemoryBudget(t *testing.T) {
+	source := &fleetv1beta1.PlacementSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-source"},
+		Status: fleetv1beta1.PlacementSourceStatus{
+			ObservedRevision: "rev-1",
+			Resources: []fleetv1beta1.ResourceContent{
+				{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`)}},
+			},
+		},
+	}
+	sourceSelector := func(name string) fleetv1beta1.ClusterResourceSelector {
+		return fleetv1beta1.ClusterResourceSelector{
+			Group:   fleetv1beta1.GroupVersion.Group,
+			Version: fleetv1beta1.GroupVersion.Version,
+			Kind:    fleetv1beta1.PlacementSourceKind,
+			Name:    name,
+		}
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-crp"},
+		Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+			// the second source is never fetched, otherwise the selection would fail as it is not found
+			ResourceSelectors: []fleetv1beta1.ClusterResourceSelector{sourceSelector("app-source"), sourceSelector("missing-source")},
+		},
+	}
+	r := Reconciler{
+		Client:                       fake.NewClientBuilder().WithScheme(serviceScheme(t)).WithObjects(source).Build(),
+		ResourceConfig:               utils.NewResourceConfig(false),
+		ResourceSnapshotMemoryBudget: 10,
+	}
+	_, _, _, err := r.selectResourcesForPlacement(crp)
+	if !errors.Is(err, controller.ErrUserError) || !strings.Contains(err.Error(), "exceeds the resource snapshot memory budget") {
+		t.Fatalf("selectResourcesForPlacement() = %v, want the memory budget error", err)
+	}
+}
+
+func TestFetchFullObjects(t *testing.T) {
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	newSecret := func(name string, withData bool) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "app"},
+		}}
+		if withData {
+			obj.Object["data"] = map[string]interface{}{"key": "dmFsdWU="}
+		}
+		return obj
+	}
+	tests := map[string]struct {
+		metadataOnly bool
+		objs         []runtime.Object
+		want         []runtime.Object
+	}{
+		"full objects are returned as is": {
+			objs: []runtime.Object{newSecret("secret-1", false)},
+			want: []runtime.Object{newSecret("secret-1", false)},
+		},
+		"single metadata-only object": {
+			metadataOnly: true,
+			objs:         []runtime.Object{newSecret("secret-1", false)},
+			want:         []runtime.Object{newSecret("secret-1", true)},
+		},
+		"multiple metadata-only objects": {
+			metadataOnly: true,
+			objs:         []runtime.Object{newSecret("secret-1", false), newSecret("secret-2", false)},
+			want:         []runtime.Object{newSecret("secret-1", true), newSecret("secret-2", true)},
+		},
+		"metadata-only object deleted meanwhile": {
+			metadataOnly: true,
+			objs:         []runtime.Object{newSecret("deleted", false)},
+			want:         []runtime.Object{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+				map[schema.GroupVersionResource]string{secretGVR: "SecretList"},
+				newSecret("secret-1", true), newSecret("secret-2", true), newSecret("secret-3", true))
+			r := Reconciler{
+				InformerManager: &fleettest.FakeInformerManager{
+					MetadataOnlyResources: map[schema.GroupVersionResource]bool{secretGVR: tc.metadataOnly},
+					DynamicClient:         dynamicClient,
+				},
+			}
+			got, err := r.fetchFullObjects(secretGVR, "app", tc.objs)
+			if err != nil {
+				t.Fatalf("fetchFullObjects() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("fetchFullObjects() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"bytes"
+	"fmt"
+
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// SelectedResourcesValidationMode describes what the CRP controller does when the selected resources fail the
+// validation before the resource snapshots are created.
+type SelectedResourcesValidationMode string
+
+const (
+	// SelectedResourcesValidationModeDisabled skips the validation of the selected resources.
+	SelectedResourcesValidationModeDisabled SelectedResourcesValidationMode = "Disabled"
+	// SelectedResourcesValidationModeWarn emits a warning event on the CRP when the selected resources are invalid,
+	// and continues to create the resource snapshots.
+	SelectedResourcesValidationModeWarn SelectedResourcesValidationMode = "Warn"
+	// SelectedResourcesValidationModeReject stops the CRP from creating the resource snapshots when the selected
+	// resources are invalid.
+	SelectedResourcesValidationModeReject SelectedResourcesValidationMode = "Reject"
+)
+
+// InvalidSelectedResourcesReason is the reason string of placement condition when the selected resources fail the validation.
+const InvalidSelectedResourcesReason = "InvalidSelectedResources"
+
+// All the selected resources of a work object, including the enveloped ones, are stored in the work object as a whole.
+// We reject any selected resource that cannot fit into one work object. It uses the same 1MB soft limit as the api server
+// test mentioned in the resourceSnapshotResourceSizeLimit.
+var selectedResourceSizeLimit = 1 << 20 // 1MB
+
+// lastAppliedConfigSizeLimit is the max size of the manifest that can be stored in the last applied configuration
+// annotation when the client side apply strategy is used.
+var lastAppliedConfigSizeLimit = apivalidation.TotalAnnotationSizeLimitB
+
+// validateSelectedResources performs a dry-run of the selected resources against the placement apply strategy
+// before any resource snapshot is created. It checks that
+// 1. the same resource is not selected more than once;
+// 2. each selected resource can fit into a work object;
+// 3. the enveloped resources can be extracted from the envelope configMap without any duplicate key;
+// 4. the selected resource can be applied by the client side apply with the last applied configuration annotation.
+// It returns a user error aggregating all the violations found.
+func validateSelectedResources(crp *fleetv1beta1.ClusterResourcePlacement, resources []fleetv1beta1.ResourceContent, ids []fleetv1beta1.ResourceIdentifier) error {
+	applyStrategyType := fleetv1beta1.ApplyStrategyTypeClientSideApply
+	if crp.Spec.Strategy.ApplyStrategy != nil && crp.Spec.Strategy.ApplyStrategy.Type != "" {
+		applyStrategyType = crp.Spec.Strategy.ApplyStrategy.Type
+	}
+
+	allErr := make([]error, 0)
+	seen := make(map[fleetv1beta1.ResourceIdentifier]bool, len(ids))
+	for i := range ids {
+		if seen[ids[i]] {
+			allErr = append(allErr, fmt.Errorf("resource %s is selected more than once", formatResourceIdentifier(ids[i])))
+			continue
+		}
+		seen[ids[i]] = true
+	}
+
+	for i := range resources {
+		raw := resources[i].Raw
+		if len(raw) > selectedResourceSizeLimit {
+			allErr = append(allErr, fmt.Errorf("resource %s size %d bytes exceeds the work size limit %d bytes", formatResourceIdentifier(ids[i]), len(raw), selectedResourceSizeLimit))
+			continue
+		}
+		var uResource unstructured.Unstructured
+		if err := uResource.UnmarshalJSON(raw); err != nil {
+			allErr = append(allErr, fmt.Errorf("resource %s cannot be serialized: %w", formatResourceIdentifier(ids[i]), err))
+			continue
+		}
+		if envelopeType, isEnvelope := utils.GetEnvelopeType(&uResource); isEnvelope {
+			allErr = append(allErr, validateEnvelopedResources(&uResource, envelopeType, applyStrategyType)...)
+			continue
+		}
+		if applyStrategyType == fleetv1beta1.ApplyStrategyTypeClientSideApply && len(raw) > lastAppliedConfigSizeLimit {
+			allErr = append(allErr, fmt.Errorf("resource %s size %d bytes exceeds the last applied configuration limit %d bytes of the client side apply, consider using the server side apply",
+				formatResourceIdentifier(ids[i]), len(raw), lastAppliedConfigSizeLimit))
+		}
+	}
+	if len(allErr) == 0 {
+		return nil
+	}
+	return controller.NewUserError(utilerrors.NewAggregate(allErr))
+}
+
+// validateEnvelopedResources validates the resources wrapped in the envelope configMap the same way as the work generator
+// extracts them.
+func validateEnvelopedResources(envelope *unstructured.Unstructured, envelopeType fleetv1beta1.EnvelopeType, applyStrategyType fleetv1beta1.ApplyStrategyType) []error {
+	envelopeRef := fmt.Sprintf("envelope configMap %s/%s", envelope.GetNamespace(), envelope.GetName())
+	data, _, err := unstructured.NestedStringMap(envelope.Object, "data")
+	if err != nil {
+		return []error{fmt.Errorf("%s has invalid data: %w", envelopeRef, err)}
+	}
+	var sourceRef utils.FluxSourceReference
+	isFluxSource := envelopeType == fleetv1beta1.FluxSourceEnvelopeType
+	if isFluxSource {
+		if sourceRef, err = utils.ParseFluxSourceReference(envelope.GetAnnotations()[fleetv1beta1.EnvelopeFluxSourceAnnotation]); err != nil {
+			return []error{fmt.Errorf("%s has invalid flux source: %w", envelopeRef, err)}
+		}
+	}
+	var allErr []error
+	totalSize := 0
+	for key, value := range data {
+		// use the strict mode so that any duplicate key is rejected
+		content, err := yaml.YAMLToJSONStrict([]byte(value))
+		if err != nil {
+			allErr = append(allErr, fmt.Errorf("%s has invalid content in key %s: %w", envelopeRef, key, err))
+			continue
+		}
+		var uObj unstructured.Unstructured
+		if err := uObj.UnmarshalJSON(bytes.TrimSpace(content)); err != nil {
+			allErr = append(allErr, fmt.Errorf("%s has invalid resource in key %s: %w", envelopeRef, key, err))
+			continue
+		}
+		if uObj.GetName() == "" {
+			allErr = append(allErr, fmt.Errorf("%s has a resource without name in key %s", envelopeRef, key))
+			continue
+		}
+		if isFluxSource {
+			if err := utils.BindFluxSourceReference(&uObj, sourceRef); err != nil {
+				allErr = append(allErr, fmt.Errorf("%s has resource in key %s which cannot reconcile the flux source: %w", envelopeRef, key, err))
+				continue
+			}
+		}
+		if applyStrategyType == fleetv1beta1.ApplyStrategyTypeClientSideApply && len(content) > lastAppliedConfigSizeLimit {
+			allErr = append(allErr, fmt.Errorf("%s has resource in key %s whose size %d bytes exceeds the last applied configuration limit %d bytes of the client side apply, consider using the server side apply",
+				envelopeRef, key, len(content), lastAppliedConfigSizeLimit))
+		}
+		totalSize += len(content)
+	}
+	if totalSize > selectedResourceSizeLimit {
+		allErr = append(allErr, fmt.Errorf("%s wraps resources with total size %d bytes which exceeds the work size limit %d bytes", envelopeRef, totalSize, selectedResourceSizeLimit))
+	}
+	klog.V(4).InfoS("Validated the enveloped resources", "envelope", klog.KObj(envelope), "numberOfResources", len(data), "numberOfErrors", len(allErr))
+	return allErr
+}
+
+func formatResourceIdentifier(id fleetv1beta1.ResourceIdentifier) string {
+	gvk := fmt.Sprintf("%s/%s, Kind=%s", id.Group, id.Version, id.Kind)
+	if id.Namespace == "" {
+		return fmt.Sprintf("%s %s", gvk, id.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", gvk, id.Namespace, id.Name)
+}
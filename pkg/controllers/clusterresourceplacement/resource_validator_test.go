@@ -0,0 +1,187 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/test/utils/resource"
+)
+
+func TestValidateSelectedResources(t *testing.T) {
+	// test service is 383 bytes in size.
+	serviceResourceContent := *resource.ServiceResourceContentForTest(t)
+	serviceID := fleetv1beta1.ResourceIdentifier{Version: "v1", Kind: "Service", Name: "svc-name", Namespace: "svc-namespace"}
+	// test secret is 152 bytes in size.
+	secretResourceContent := *resource.SecretResourceContentForTest(t)
+	secretID := fleetv1beta1.ResourceIdentifier{Version: "v1", Kind: "Secret", Name: "secret-name", Namespace: "secret-namespace"}
+
+	envelope := func(annotations map[string]string, data map[string]string) fleetv1beta1.ResourceContent {
+		cm := corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "envelope",
+				Namespace:   "app",
+				Annotations: annotations,
+			},
+			Data: data,
+		}
+		return *resource.CreateResourceContentForTest(t, cm)
+	}
+	envelopeConfigMap := func(data map[string]string) fleetv1beta1.ResourceContent {
+		return envelope(map[string]string{fleetv1beta1.EnvelopeConfigMapAnnotation: "true"}, data)
+	}
+	fluxSourceEnvelope := func(sourceRef string, data map[string]string) fleetv1beta1.ResourceContent {
+		return envelope(map[string]string{fleetv1beta1.EnvelopeFluxSourceAnnotation: sourceRef}, data)
+	}
+	envelopeID := fleetv1beta1.ResourceIdentifier{Version: "v1", Kind: "ConfigMap", Name: "envelope", Namespace: "app"}
+	validEnvelopedResource := "apiVersion: v1\nkind: ResourceQuota\nmetadata:\n  name: quota\n  namespace: app\n"
+	kustomization := "apiVersion: kustomize.toolkit.fluxcd.io/v1\nkind: Kustomization\nmetadata:\n  name: podinfo\n  namespace: app\nspec:\n  path: ./kustomize\n"
+
+	tests := []struct {
+		name                       string
+		applyStrategy              *fleetv1beta1.ApplyStrategy
+		selectedResourceSizeLimit  int
+		lastAppliedConfigSizeLimit int
+		resources                  []fleetv1beta1.ResourceContent
+		ids                        []fleetv1beta1.ResourceIdentifier
+		wantErrs                   []string
+	}{
+		{
+			name:      "valid resources",
+			resources: []fleetv1beta1.ResourceContent{secretResourceContent, serviceResourceContent},
+			ids:       []fleetv1beta1.ResourceIdentifier{secretID, serviceID},
+		},
+		{
+			name:      "same resource is selected twice",
+			resources: []fleetv1beta1.ResourceContent{serviceResourceContent, serviceResourceContent},
+			ids:       []fleetv1beta1.ResourceIdentifier{serviceID, serviceID},
+			wantErrs:  []string{"resource /v1, Kind=Service svc-namespace/svc-name is selected more than once"},
+		},
+		{
+			name:                      "resource exceeds the work size limit",
+			selectedResourceSizeLimit: 200,
+			resources:                 []fleetv1beta1.ResourceContent{secretResourceContent, serviceResourceContent},
+			ids:                       []fleetv1beta1.ResourceIdentifier{secretID, serviceID},
+			wantErrs:                  []string{"resource /v1, Kind=Service svc-namespace/svc-name size", "exceeds the work size limit 200 bytes"},
+		},
+		{
+			name:                       "resource exceeds the last applied configuration limit with client side apply",
+			lastAppliedConfigSizeLimit: 200,
+			resources:                  []fleetv1beta1.ResourceContent{secretResourceContent, serviceResourceContent},
+			ids:                        []fleetv1beta1.ResourceIdentifier{secretID, serviceID},
+			wantErrs:                   []string{"exceeds the last applied configuration limit 200 bytes"},
+		},
+		{
+			name:                       "resource exceeds the last applied configuration limit with server side apply",
+			applyStrategy:              &fleetv1beta1.ApplyStrategy{Type: fleetv1beta1.ApplyStrategyTypeServerSideApply},
+			lastAppliedConfigSizeLimit: 200,
+			resources:                  []fleetv1beta1.ResourceContent{secretResourceContent, serviceResourceContent},
+			ids:                        []fleetv1beta1.ResourceIdentifier{secretID, serviceID},
+		},
+		{
+			name:      "valid enveloped resources",
+			resources: []fleetv1beta1.ResourceContent{envelopeConfigMap(map[string]string{"quota.yaml": validEnvelopedResource})},
+			ids:       []fleetv1beta1.ResourceIdentifier{envelopeID},
+		},
+		{
+			name: "enveloped resource has duplicate keys",
+			resources: []fleetv1beta1.ResourceContent{envelopeConfigMap(map[string]string{
+				"quota.yaml": "apiVersion: v1\nkind: ResourceQuota\nkind: LimitRange\nmetadata:\n  name: quota\n",
+			})},
+			ids:      []fleetv1beta1.ResourceIdentifier{envelopeID},
+			wantErrs: []string{"envelope configMap app/envelope has invalid content in key quota.yaml"},
+		},
+		{
+			name: "enveloped resource has no name",
+			resources: []fleetv1beta1.ResourceContent{envelopeConfigMap(map[string]string{
+				"quota.yaml": "apiVersion: v1\nkind: ResourceQuota\nmetadata:\n  namespace: app\n",
+			})},
+			ids:      []fleetv1beta1.ResourceIdentifier{envelopeID},
+			wantErrs: []string{"envelope configMap app/envelope has a resource without name in key quota.yaml"},
+		},
+		{
+			name:      "valid flux source envelope",
+			resources: []fleetv1beta1.ResourceContent{fluxSourceEnvelope("GitRepository/podinfo", map[string]string{"kustomization.yaml": kustomization})},
+			ids:       []fleetv1beta1.ResourceIdentifier{envelopeID},
+		},
+		{
+			name:      "flux source envelope has invalid source",
+			resources: []fleetv1beta1.ResourceContent{fluxSourceEnvelope("podinfo", map[string]string{"kustomization.yaml": kustomization})},
+			ids:       []fleetv1beta1.ResourceIdentifier{envelopeID},
+			wantErrs:  []string{"envelope configMap app/envelope has invalid flux source"},
+		},
+		{
+			name: "flux source envelope has resource referencing another source",
+			resources: []fleetv1beta1.ResourceContent{fluxSourceEnvelope("GitRepository/podinfo", map[string]string{
+				"kustomization.yaml": kustomization + "  sourceRef:\n    kind: OCIRepository\n    name: podinfo\n",
+			})},
+			ids:      []fleetv1beta1.ResourceIdentifier{envelopeID},
+			wantErrs: []string{"has resource in key kustomization.yaml which cannot reconcile the flux source"},
+		},
+		{
+			name:                      "envelope exceeds the work size limit",
+			selectedResourceSizeLimit: 150,
+			resources: []fleetv1beta1.ResourceContent{envelopeConfigMap(map[string]string{
+				"quota1.yaml": validEnvelopedResource,
+				"quota2.yaml": strings.ReplaceAll(validEnvelopedResource, "name: quota", "name: quota2"),
+			})},
+			ids: []fleetv1beta1.ResourceIdentifier{envelopeID},
+			wantErrs: []string{
+				"resource /v1, Kind=ConfigMap app/envelope size",
+			},
+		},
+	}
+	originalSelectedResourceSizeLimit := selectedResourceSizeLimit
+	originalLastAppliedConfigSizeLimit := lastAppliedConfigSizeLimit
+	defer func() {
+		selectedResourceSizeLimit = originalSelectedResourceSizeLimit
+		lastAppliedConfigSizeLimit = originalLastAppliedConfigSizeLimit
+	}()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			selectedResourceSizeLimit = originalSelectedResourceSizeLimit
+			if tc.selectedResourceSizeLimit != 0 {
+				selectedResourceSizeLimit = tc.selectedResourceSizeLimit
+			}
+			lastAppliedConfigSizeLimit = originalLastAppliedConfigSizeLimit
+			if tc.lastAppliedConfigSizeLimit != 0 {
+				lastAppliedConfigSizeLimit = tc.lastAppliedConfigSizeLimit
+			}
+			crp := &fleetv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{Name: testName},
+				Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+					Strategy: fleetv1beta1.RolloutStrategy{ApplyStrategy: tc.applyStrategy},
+				},
+			}
+			err := validateSelectedResources(crp, tc.resources, tc.ids)
+			if len(tc.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("validateSelectedResources() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, controller.ErrUserError) {
+				t.Fatalf("validateSelectedResources() = %v, want user error", err)
+			}
+			for _, want := range tc.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validateSelectedResources() = %v, want error containing %q", err, want)
+				}
+			}
+		})
+	}
+}
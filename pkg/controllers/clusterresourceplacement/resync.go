@@ -0,0 +1,68 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// AdaptiveResync adapts the interval at which a placement whose rollout has not completed is resynced to how stable
+// the placement is: the placements which have been available for long, e.g. the PickN placements which cannot find
+// enough clusters, are resynced less often, and the placements which have just started failing are resynced more
+// often, so that the hub spends its load where it matters.
+type AdaptiveResync struct {
+	// MinInterval is the interval at which a placement which has just started failing is resynced.
+	MinInterval time.Duration
+	// MaxInterval is the longest interval at which a placement which has been available for long is resynced.
+	MaxInterval time.Duration
+}
+
+// interval returns the interval after which the placement is resynced instead of the given base interval:
+//   - a placement which has been available for a duration is resynced at a quarter of the duration, no more often than
+//     the base interval and no less often than MaxInterval;
+//   - a placement which has been failing for a duration is resynced at a quarter of the duration, no more often than
+//     MinInterval and no less often than the base interval;
+//   - the other placements, e.g. the ones which are still rolling out, are resynced at the base interval.
+func (a *AdaptiveResync) interval(crp *fleetv1beta1.ClusterResourcePlacement, base time.Duration, now time.Time) time.Duration {
+	if a == nil {
+		return base
+	}
+	availableCond := crp.GetCondition(string(fleetv1beta1.ClusterResourcePlacementAvailableConditionType))
+	if condition.IsConditionStatusTrue(availableCond, crp.Generation) {
+		return clampDuration(now.Sub(availableCond.LastTransitionTime.Time)/4, base, max(base, a.MaxInterval))
+	}
+	if failingSince := failingSince(crp); failingSince != nil {
+		return clampDuration(now.Sub(failingSince.Time)/4, min(a.MinInterval, base), base)
+	}
+	return base
+}
+
+// failingSince returns the earliest time since which a resource condition of the placement has been false, or nil if
+// none of them is false. The RolloutStarted condition is not a failure, as it is false when the rollout is blocked by
+// the rollout strategy.
+func failingSince(crp *fleetv1beta1.ClusterResourcePlacement) *metav1.Time {
+	var since *metav1.Time
+	for i := condition.OverriddenCondition; i < condition.TotalCondition; i++ {
+		cond := crp.GetCondition(string(i.ClusterResourcePlacementConditionType()))
+		if !condition.IsConditionStatusFalse(cond, crp.Generation) {
+			continue
+		}
+		if since == nil || cond.LastTransitionTime.Before(since) {
+			since = &cond.LastTransitionTime
+		}
+	}
+	return since
+}
+
+// clampDuration returns d limited to the range between lower and upper.
+func clampDuration(d, lower, upper time.Duration) time.Duration {
+	return min(max(d, lower), upper)
+}
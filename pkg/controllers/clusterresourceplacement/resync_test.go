@@ -0,0 +1,101 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestAdaptiveResyncInterval(t *testing.T) {
+	now := time.Now()
+	adaptiveResync := &AdaptiveResync{MinInterval: 15 * time.Second, MaxInterval: time.Hour}
+	cond := func(conditionType fleetv1beta1.ClusterResourcePlacementConditionType, status metav1.ConditionStatus, since time.Duration, generation int64) metav1.Condition {
+		return metav1.Condition{
+			Type:               string(conditionType),
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+			ObservedGeneration: generation,
+		}
+	}
+	tests := map[string]struct {
+		adaptiveResync *AdaptiveResync
+		conditions     []metav1.Condition
+		want           time.Duration
+	}{
+		"adaptive resync disabled": {
+			conditions: []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAvailableConditionType, metav1.ConditionTrue, 4*time.Hour, 1)},
+			want:       time.Minute,
+		},
+		"available for long": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAvailableConditionType, metav1.ConditionTrue, 8*time.Hour, 1)},
+			want:           time.Hour,
+		},
+		"available for a while": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAvailableConditionType, metav1.ConditionTrue, 40*time.Minute, 1)},
+			want:           10 * time.Minute,
+		},
+		"just available": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAvailableConditionType, metav1.ConditionTrue, time.Minute, 1)},
+			want:           time.Minute,
+		},
+		"available of an old generation": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAvailableConditionType, metav1.ConditionTrue, 8*time.Hour, 0)},
+			want:           time.Minute,
+		},
+		"just started failing": {
+			adaptiveResync: adaptiveResync,
+			conditions: []metav1.Condition{
+				cond(fleetv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType, metav1.ConditionTrue, time.Hour, 1),
+				cond(fleetv1beta1.ClusterResourcePlacementAppliedConditionType, metav1.ConditionFalse, 10*time.Second, 1),
+			},
+			want: 15 * time.Second,
+		},
+		"failing for a while": {
+			adaptiveResync: adaptiveResync,
+			conditions: []metav1.Condition{
+				cond(fleetv1beta1.ClusterResourcePlacementAppliedConditionType, metav1.ConditionFalse, 2*time.Minute, 1),
+				cond(fleetv1beta1.ClusterResourcePlacementAvailableConditionType, metav1.ConditionFalse, 10*time.Second, 1),
+			},
+			want: 30 * time.Second,
+		},
+		"failing for long": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAppliedConditionType, metav1.ConditionFalse, time.Hour, 1)},
+			want:           time.Minute,
+		},
+		"rollout blocked by the strategy": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementRolloutStartedConditionType, metav1.ConditionFalse, 10*time.Second, 1)},
+			want:           time.Minute,
+		},
+		"rolling out": {
+			adaptiveResync: adaptiveResync,
+			conditions:     []metav1.Condition{cond(fleetv1beta1.ClusterResourcePlacementAppliedConditionType, metav1.ConditionUnknown, 10*time.Second, 1)},
+			want:           time.Minute,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crp := &fleetv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Generation: 1},
+				Status:     fleetv1beta1.ClusterResourcePlacementStatus{Conditions: tc.conditions},
+			}
+			got := tc.adaptiveResync.interval(crp, time.Minute, now)
+			if got != tc.want {
+				t.Errorf("interval() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// findUnreachableClusters returns the last heartbeats of the selected clusters whose member agents have not sent
+// heartbeats for longer than the StatusStaleTimeout, keyed by the cluster names.
+// The clusters which have left the fleet or whose member agents have never reported are not unreachable.
+func (r *Reconciler) findUnreachableClusters(ctx context.Context, selected []*fleetv1beta1.ClusterDecision, now time.Time) (map[string]metav1.Time, error) {
+	if r.StatusStaleTimeout <= 0 {
+		return nil, nil
+	}
+	unreachable := make(map[string]metav1.Time)
+	for _, decision := range selected {
+		cluster := &clusterv1beta1.MemberCluster{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: decision.ClusterName}, cluster); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			klog.ErrorS(err, "Failed to get the memberCluster", "memberCluster", decision.ClusterName)
+			return nil, controller.NewAPIServerError(true, err)
+		}
+		agentStatus := cluster.GetAgentStatus(clusterv1beta1.MemberAgent)
+		if agentStatus == nil || agentStatus.LastReceivedHeartbeat.IsZero() {
+			continue
+		}
+		if now.Sub(agentStatus.LastReceivedHeartbeat.Time) > r.StatusStaleTimeout {
+			unreachable[decision.ClusterName] = agentStatus.LastReceivedHeartbeat
+		}
+	}
+	return unreachable, nil
+}
+
+// markStatusStale marks the applied and available conditions of the placement status of an unreachable cluster as
+// stale, so that the last status reported by the cluster is not taken as the current one.
+// The conditions become unknown while the last reported status and the last heartbeat of the cluster are kept in
+// their messages. It returns the updated status of each resource condition.
+func markStatusStale(crp *fleetv1beta1.ClusterResourcePlacement, status *fleetv1beta1.ResourcePlacementStatus, oldConditions []metav1.Condition,
+	lastHeartbeat metav1.Time, res []metav1.ConditionStatus) []metav1.ConditionStatus {
+	for _, i := range []condition.ResourceCondition{condition.AppliedCondition, condition.AvailableCondition} {
+		if int(i) >= len(res) {
+			break
+		}
+		conditionType := string(i.ResourcePlacementConditionType())
+		cur := meta.FindStatusCondition(status.Conditions, conditionType)
+		if cur == nil {
+			break
+		}
+		cond := metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: crp.Generation,
+			Reason:             condition.StaleStatusReason,
+			Message: fmt.Sprintf("The cluster is unreachable as its member agent has not sent heartbeats since %s; the last reported status is %s with reason %s",
+				lastHeartbeat.UTC().Format(time.RFC3339), cur.Status, cur.Reason),
+		}
+		// keep the time when the condition became stale
+		if old := meta.FindStatusCondition(oldConditions, conditionType); old != nil && old.Reason == condition.StaleStatusReason {
+			cond.LastTransitionTime = old.LastTransitionTime
+		}
+		meta.SetStatusCondition(&status.Conditions, cond)
+		res[i] = metav1.ConditionUnknown
+	}
+	return res
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestFindUnreachableClusters(t *testing.T) {
+	// the heartbeats are serialized in seconds
+	now := time.Now().Truncate(time.Second)
+	newCluster := func(name string, lastHeartbeat time.Time) *clusterv1beta1.MemberCluster {
+		cluster := &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if !lastHeartbeat.IsZero() {
+			cluster.Status.AgentStatus = []clusterv1beta1.AgentStatus{
+				{Type: clusterv1beta1.MemberAgent, LastReceivedHeartbeat: metav1.NewTime(lastHeartbeat)},
+			}
+		}
+		return cluster
+	}
+	scheme := runtime.NewScheme()
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCluster("reachable", now.Add(-time.Minute)),
+		newCluster("unreachable", now.Add(-10*time.Minute)),
+		newCluster("not-reported", time.Time{}),
+	).Build()
+	selected := []*fleetv1beta1.ClusterDecision{
+		{ClusterName: "reachable", Selected: true},
+		{ClusterName: "unreachable", Selected: true},
+		{ClusterName: "not-reported", Selected: true},
+		{ClusterName: "gone", Selected: true},
+	}
+
+	tests := map[string]struct {
+		timeout time.Duration
+		want    map[string]metav1.Time
+	}{
+		"disabled": {},
+		"the cluster without recent heartbeats is unreachable": {
+			timeout: 5 * time.Minute,
+			want:    map[string]metav1.Time{"unreachable": metav1.NewTime(now.Add(-10 * time.Minute))},
+		},
+		"no cluster is unreachable with a longer timeout": {
+			timeout: time.Hour,
+			want:    map[string]metav1.Time{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{Client: fakeClient, StatusStaleTimeout: tc.timeout}
+			got, err := r.findUnreachableClusters(context.Background(), selected, now)
+			if err != nil {
+				t.Fatalf("findUnreachableClusters() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.Comparer(func(a, b metav1.Time) bool { return a.Time.Equal(b.Time) })); diff != "" {
+				t.Errorf("findUnreachableClusters() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarkStatusStale(t *testing.T) {
+	crp := &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp", Generation: 2}}
+	lastHeartbeat := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	staleSince := metav1.NewTime(time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC))
+	newStatus := func(statuses ...metav1.ConditionStatus) *fleetv1beta1.ResourcePlacementStatus {
+		status := &fleetv1beta1.ResourcePlacementStatus{ClusterName: "member-1"}
+		for i, s := range statuses {
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:               string(condition.ResourceCondition(i).ResourcePlacementConditionType()),
+				Status:             s,
+				Reason:             "Reason",
+				ObservedGeneration: crp.Generation,
+			})
+		}
+		return status
+	}
+	allTrue := []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue}
+	tests := map[string]struct {
+		res           []metav1.ConditionStatus
+		oldConditions []metav1.Condition
+		want          []metav1.ConditionStatus
+		wantStale     []fleetv1beta1.ResourcePlacementConditionType
+		wantSince     *metav1.Time
+	}{
+		"the applied and available conditions are stale": {
+			res:       allTrue,
+			want:      []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionUnknown, metav1.ConditionUnknown},
+			wantStale: []fleetv1beta1.ResourcePlacementConditionType{fleetv1beta1.ResourcesAppliedConditionType, fleetv1beta1.ResourcesAvailableConditionType},
+		},
+		"the failed applied condition is stale": {
+			res:       []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionFalse},
+			want:      []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionUnknown},
+			wantStale: []fleetv1beta1.ResourcePlacementConditionType{fleetv1beta1.ResourcesAppliedConditionType},
+		},
+		"the conditions before the applied condition are kept": {
+			res:  []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionUnknown},
+			want: []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionUnknown},
+		},
+		"the time when the conditions became stale is kept": {
+			res: allTrue,
+			oldConditions: []metav1.Condition{
+				{Type: string(fleetv1beta1.ResourcesAppliedConditionType), Status: metav1.ConditionUnknown, Reason: condition.StaleStatusReason, LastTransitionTime: staleSince},
+				{Type: string(fleetv1beta1.ResourcesAvailableConditionType), Status: metav1.ConditionUnknown, Reason: condition.StaleStatusReason, LastTransitionTime: staleSince},
+			},
+			want:      []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionTrue, metav1.ConditionUnknown, metav1.ConditionUnknown},
+			wantStale: []fleetv1beta1.ResourcePlacementConditionType{fleetv1beta1.ResourcesAppliedConditionType, fleetv1beta1.ResourcesAvailableConditionType},
+			wantSince: &staleSince,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			status := newStatus(tc.res...)
+			got := markStatusStale(crp, status, tc.oldConditions, lastHeartbeat, append([]metav1.ConditionStatus(nil), tc.res...))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("markStatusStale() mismatch (-want, +got):\n%s", diff)
+			}
+			for _, conditionType := range tc.wantStale {
+				cond := meta.FindStatusCondition(status.Conditions, string(conditionType))
+				if cond == nil || cond.Status != metav1.ConditionUnknown || cond.Reason != condition.StaleStatusReason {
+					t.Errorf("condition %s = %+v, want stale", conditionType, cond)
+					continue
+				}
+				if tc.wantSince != nil && !cond.LastTransitionTime.Equal(tc.wantSince) {
+					t.Errorf("condition %s became stale at %v, want %v", conditionType, cond.LastTransitionTime, tc.wantSince)
+				}
+			}
+			if !isPlacementStatusHealthy(status) != (len(tc.wantStale) > 0) {
+				t.Errorf("isPlacementStatusHealthy() = %v, want %v", isPlacementStatusHealthy(status), len(tc.wantStale) == 0)
+			}
+		})
+	}
+}
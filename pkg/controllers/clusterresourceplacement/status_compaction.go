@@ -0,0 +1,163 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// listPerClusterPlacementStatuses returns the perClusterPlacementStatuses of the crp keyed by their namespaces.
+// They are only listed if the status of the crp can be or was compacted, so that no informer is started for them
+// when the compaction is disabled.
+func (r *Reconciler) listPerClusterPlacementStatuses(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement) (map[string]*fleetv1beta1.PerClusterPlacementStatus, error) {
+	if r.StatusCompactionThreshold <= 0 && crp.Status.PlacementStatusSummary == nil {
+		return nil, nil
+	}
+	statusList := &fleetv1beta1.PerClusterPlacementStatusList{}
+	if err := r.Client.List(ctx, statusList, client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crp.Name}); err != nil {
+		klog.ErrorS(err, "Failed to list the perClusterPlacementStatuses", "clusterResourcePlacement", klog.KObj(crp))
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	statuses := make(map[string]*fleetv1beta1.PerClusterPlacementStatus, len(statusList.Items))
+	for i := range statusList.Items {
+		statuses[statusList.Items[i].Namespace] = &statusList.Items[i]
+	}
+	return statuses, nil
+}
+
+// compactPlacementStatuses compacts the placement statuses of the crp if it selects more clusters than the
+// compaction threshold: the placement status on every selected cluster is written to its perClusterPlacementStatus,
+// and only the ones which are not healthy are kept in the crp along with a summary. Otherwise, the existing
+// perClusterPlacementStatuses are deleted.
+func (r *Reconciler) compactPlacementStatuses(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement,
+	existing map[string]*fleetv1beta1.PerClusterPlacementStatus) error {
+	selectedClusters := 0
+	for i := range crp.Status.PlacementStatuses {
+		if crp.Status.PlacementStatuses[i].ClusterName != "" {
+			selectedClusters++
+		}
+	}
+	if r.StatusCompactionThreshold <= 0 || selectedClusters <= r.StatusCompactionThreshold {
+		crp.Status.PlacementStatusSummary = nil
+		return r.deleteStalePerClusterPlacementStatuses(ctx, crp, existing, nil)
+	}
+
+	selected := make(map[string]bool, selectedClusters)
+	inline := make([]fleetv1beta1.ResourcePlacementStatus, 0)
+	unhealthyClusters := 0
+	for i := range crp.Status.PlacementStatuses {
+		status := &crp.Status.PlacementStatuses[i]
+		healthy := isPlacementStatusHealthy(status)
+		if !healthy {
+			inline = append(inline, *status)
+		}
+		if status.ClusterName == "" {
+			continue
+		}
+		if !healthy {
+			unhealthyClusters++
+		}
+		namespace := fmt.Sprintf(utils.NamespaceNameFormat, status.ClusterName)
+		selected[namespace] = true
+		if err := r.writePerClusterPlacementStatus(ctx, crp, namespace, status, existing[namespace]); err != nil {
+			return err
+		}
+	}
+	if err := r.deleteStalePerClusterPlacementStatuses(ctx, crp, existing, selected); err != nil {
+		return err
+	}
+	crp.Status.PlacementStatuses = inline
+	crp.Status.PlacementStatusSummary = &fleetv1beta1.PlacementStatusSummary{
+		SelectedClusters:  int32(selectedClusters),
+		UnhealthyClusters: int32(unhealthyClusters),
+	}
+	klog.V(2).InfoS("Compacted the placement statuses", "clusterResourcePlacement", klog.KObj(crp), "selectedClusters", selectedClusters, "unhealthyClusters", unhealthyClusters)
+	return nil
+}
+
+// writePerClusterPlacementStatus creates or updates the perClusterPlacementStatus of the crp in the namespace if the
+// placement status has changed.
+func (r *Reconciler) writePerClusterPlacementStatus(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, namespace string,
+	status *fleetv1beta1.ResourcePlacementStatus, existing *fleetv1beta1.PerClusterPlacementStatus) error {
+	crpKObj := klog.KObj(crp)
+	if existing != nil {
+		if equality.Semantic.DeepEqual(existing.PlacementStatus, *status) {
+			return nil
+		}
+		updated := existing.DeepCopy()
+		updated.PlacementStatus = *status
+		if err := r.Client.Update(ctx, updated); err != nil {
+			klog.ErrorS(err, "Failed to update the perClusterPlacementStatus", "clusterResourcePlacement", crpKObj, "perClusterPlacementStatus", klog.KObj(updated))
+			return controller.NewUpdateIgnoreConflictError(err)
+		}
+		return nil
+	}
+
+	perClusterStatus := &fleetv1beta1.PerClusterPlacementStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      crp.Name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				fleetv1beta1.CRPTrackingLabel: crp.Name,
+			},
+		},
+		PlacementStatus: *status,
+	}
+	if err := controllerutil.SetControllerReference(crp, perClusterStatus, r.Scheme); err != nil {
+		klog.ErrorS(err, "Failed to set owner reference", "perClusterPlacementStatus", klog.KObj(perClusterStatus))
+		// should never happen
+		return controller.NewUnexpectedBehaviorError(err)
+	}
+	if err := r.Client.Create(ctx, perClusterStatus); err != nil {
+		klog.ErrorS(err, "Failed to create the perClusterPlacementStatus", "clusterResourcePlacement", crpKObj, "perClusterPlacementStatus", klog.KObj(perClusterStatus))
+		return controller.NewCreateIgnoreAlreadyExistError(err)
+	}
+	return nil
+}
+
+// deleteStalePerClusterPlacementStatuses deletes the existing perClusterPlacementStatuses whose namespaces are not
+// kept.
+func (r *Reconciler) deleteStalePerClusterPlacementStatuses(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement,
+	existing map[string]*fleetv1beta1.PerClusterPlacementStatus, keep map[string]bool) error {
+	for namespace, perClusterStatus := range existing {
+		if keep[namespace] {
+			continue
+		}
+		if err := r.Client.Delete(ctx, perClusterStatus); err != nil {
+			klog.ErrorS(err, "Failed to delete the perClusterPlacementStatus", "clusterResourcePlacement", klog.KObj(crp), "perClusterPlacementStatus", klog.KObj(perClusterStatus))
+			if err = controller.NewDeleteIgnoreNotFoundError(err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isPlacementStatusHealthy returns true if the placement status is of a scheduled cluster, and none of its conditions
+// is false or stale and no resource fails to be placed; the clusters which are still being rolled out are healthy.
+func isPlacementStatusHealthy(status *fleetv1beta1.ResourcePlacementStatus) bool {
+	if status.ClusterName == "" || len(status.FailedPlacements) > 0 {
+		return false
+	}
+	for i := range status.Conditions {
+		if status.Conditions[i].Status == metav1.ConditionFalse || status.Conditions[i].Reason == condition.StaleStatusReason {
+			return false
+		}
+	}
+	return true
+}
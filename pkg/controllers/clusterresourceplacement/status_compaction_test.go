@@ -0,0 +1,139 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clusterresourceplacement
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func TestCompactPlacementStatuses(t *testing.T) {
+	healthy := fleetv1beta1.ResourcePlacementStatus{
+		ClusterName: "member-1",
+		Conditions: []metav1.Condition{
+			{Type: string(fleetv1beta1.ResourceScheduledConditionType), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+			{Type: string(fleetv1beta1.ResourcesAppliedConditionType), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+		},
+	}
+	rollingOut := fleetv1beta1.ResourcePlacementStatus{
+		ClusterName: "member-2",
+		Conditions: []metav1.Condition{
+			{Type: string(fleetv1beta1.ResourceScheduledConditionType), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+			{Type: string(fleetv1beta1.ResourcesAppliedConditionType), Status: metav1.ConditionUnknown, ObservedGeneration: 1},
+		},
+	}
+	failed := fleetv1beta1.ResourcePlacementStatus{
+		ClusterName: "member-3",
+		Conditions: []metav1.Condition{
+			{Type: string(fleetv1beta1.ResourceScheduledConditionType), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+			{Type: string(fleetv1beta1.ResourcesAppliedConditionType), Status: metav1.ConditionFalse, ObservedGeneration: 1},
+		},
+	}
+	unscheduled := fleetv1beta1.ResourcePlacementStatus{
+		Conditions: []metav1.Condition{
+			{Type: string(fleetv1beta1.ResourceScheduledConditionType), Status: metav1.ConditionFalse, ObservedGeneration: 1},
+		},
+	}
+	perClusterStatus := func(status fleetv1beta1.ResourcePlacementStatus) *fleetv1beta1.PerClusterPlacementStatus {
+		return &fleetv1beta1.PerClusterPlacementStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: fmt.Sprintf(utils.NamespaceNameFormat, status.ClusterName),
+				Labels:    map[string]string{fleetv1beta1.CRPTrackingLabel: testName},
+			},
+			PlacementStatus: status,
+		}
+	}
+	// the cluster is no longer selected
+	stale := fleetv1beta1.ResourcePlacementStatus{ClusterName: "member-4"}
+
+	tests := map[string]struct {
+		threshold        int
+		existing         []fleetv1beta1.ResourcePlacementStatus
+		wantInline       []fleetv1beta1.ResourcePlacementStatus
+		wantSummary      *fleetv1beta1.PlacementStatusSummary
+		wantPerClusterOf []fleetv1beta1.ResourcePlacementStatus
+	}{
+		"compacted": {
+			threshold:        2,
+			existing:         []fleetv1beta1.ResourcePlacementStatus{{ClusterName: "member-1"}, stale},
+			wantInline:       []fleetv1beta1.ResourcePlacementStatus{failed, unscheduled},
+			wantSummary:      &fleetv1beta1.PlacementStatusSummary{SelectedClusters: 3, UnhealthyClusters: 1},
+			wantPerClusterOf: []fleetv1beta1.ResourcePlacementStatus{healthy, rollingOut, failed},
+		},
+		"not more clusters than the threshold": {
+			threshold:  3,
+			existing:   []fleetv1beta1.ResourcePlacementStatus{healthy, stale},
+			wantInline: []fleetv1beta1.ResourcePlacementStatus{healthy, rollingOut, failed, unscheduled},
+		},
+		"compaction disabled": {
+			existing:   []fleetv1beta1.ResourcePlacementStatus{healthy},
+			wantInline: []fleetv1beta1.ResourcePlacementStatus{healthy, rollingOut, failed, unscheduled},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			crp := &fleetv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Generation: 1},
+				Status: fleetv1beta1.ClusterResourcePlacementStatus{
+					PlacementStatuses: []fleetv1beta1.ResourcePlacementStatus{healthy, rollingOut, failed, unscheduled},
+					// the status was compacted before
+					PlacementStatusSummary: &fleetv1beta1.PlacementStatusSummary{},
+				},
+			}
+			var objects []client.Object
+			for _, status := range tc.existing {
+				objects = append(objects, perClusterStatus(status))
+			}
+			scheme := serviceScheme(t)
+			r := Reconciler{
+				Client:                    fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+				Scheme:                    scheme,
+				StatusCompactionThreshold: tc.threshold,
+			}
+			ctx := context.Background()
+			existing, err := r.listPerClusterPlacementStatuses(ctx, crp)
+			if err != nil {
+				t.Fatalf("listPerClusterPlacementStatuses() = %v, want nil", err)
+			}
+			if err := r.compactPlacementStatuses(ctx, crp, existing); err != nil {
+				t.Fatalf("compactPlacementStatuses() = %v, want nil", err)
+			}
+
+			if diff := cmp.Diff(tc.wantInline, crp.Status.PlacementStatuses); diff != "" {
+				t.Errorf("placementStatuses mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantSummary, crp.Status.PlacementStatusSummary); diff != "" {
+				t.Errorf("placementStatusSummary mismatch (-want, +got):\n%s", diff)
+			}
+			var gotList fleetv1beta1.PerClusterPlacementStatusList
+			if err := r.Client.List(ctx, &gotList); err != nil {
+				t.Fatalf("failed to list the perClusterPlacementStatuses: %v", err)
+			}
+			var got []fleetv1beta1.ResourcePlacementStatus
+			for _, perClusterStatus := range gotList.Items {
+				if perClusterStatus.Labels[fleetv1beta1.CRPTrackingLabel] != testName || perClusterStatus.Name != testName {
+					t.Errorf("perClusterPlacementStatus %s/%s is not named or labeled after the placement", perClusterStatus.Namespace, perClusterStatus.Name)
+				}
+				got = append(got, perClusterStatus.PlacementStatus)
+			}
+			sortByCluster := cmpopts.SortSlices(func(s1, s2 fleetv1beta1.ResourcePlacementStatus) bool { return s1.ClusterName < s2.ClusterName })
+			if diff := cmp.Diff(tc.wantPerClusterOf, got, sortByCluster); diff != "" {
+				t.Errorf("perClusterPlacementStatuses mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -12,9 +12,12 @@ import (
 
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrloption "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils/controller"
 )
 
@@ -23,6 +26,9 @@ type Reconciler struct {
 	// PlacementController maintains a rate limited queue which used to store
 	// the name of the clusterResourcePlacement and a reconcile function to consume the items in queue.
 	PlacementController controller.Controller
+	// Sharder shards the placements across the replicas of the hub agent if set; the watcher then runs on every
+	// replica and enqueues the placements that the replica takes over after a rebalance.
+	Sharder *sharding.Sharder
 }
 
 // Reconcile triggers a single CRP reconcile round if CRP has changed.
@@ -40,8 +46,12 @@ func (r *Reconciler) Reconcile(_ context.Context, req ctrl.Request) (ctrl.Result
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(ctrloption.Options{NeedLeaderElection: sharding.ControllerLeaderElection(r.Sharder)}).
 		For(&fleetv1beta1.ClusterResourcePlacement{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
-		Complete(r)
+		WithEventFilter(predicate.GenerationChangedPredicate{})
+	if r.Sharder != nil {
+		b = b.WatchesRawSource(r.Sharder.Subscribe(&handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
@@ -15,10 +15,12 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrloption "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils/controller"
 )
 
@@ -28,6 +30,10 @@ type Reconciler struct {
 
 	// PlacementController exposes the placement queue for the reconciler to push to.
 	PlacementController controller.Controller
+
+	// Sharder shards the placements across the replicas of the hub agent if set; the watcher then runs on every
+	// replica, as the placement controller skips the placements of the other replicas.
+	Sharder *sharding.Sharder
 }
 
 // Reconcile triggers a single CRP reconcile round when scheduling policy has changed.
@@ -58,13 +64,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, controller.NewUnexpectedBehaviorError(err)
 	}
 
-	r.PlacementController.Enqueue(crp)
+	// Only the status updates of the snapshot are watched, which are processed after the user-triggered changes.
+	r.PlacementController.EnqueueWithPriority(crp, controller.PriorityLow)
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(ctrloption.Options{NeedLeaderElection: sharding.ControllerLeaderElection(r.Sharder)}).
 		For(&fleetv1beta1.ClusterSchedulingPolicySnapshot{}).
 		WithEventFilter(predicate.Funcs{
 			// skipping delete and create events so that CRP controller does not need to update the status.
@@ -16,7 +16,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -26,6 +25,7 @@ import (
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/pkg/controllers/workv1alpha1"
 	"go.goms.io/fleet/pkg/metrics"
+	"go.goms.io/fleet/pkg/utils/controller"
 )
 
 // Reconciler reconciles a InternalMemberCluster object in the member cluster.
@@ -185,19 +185,18 @@ func (r *Reconciler) updateResourceStats(ctx context.Context, imc *fleetv1alpha1
 	return nil
 }
 
-// updateInternalMemberClusterWithRetry updates InternalMemberCluster status.
+// updateInternalMemberClusterWithRetry updates the InternalMemberCluster status reported by the member agent.
+//
+// On conflicts, only the fields owned by the member agent are written onto the latest object so that the status
+// reported by the other agents is kept.
 func (r *Reconciler) updateInternalMemberClusterWithRetry(ctx context.Context, imc *fleetv1alpha1.InternalMemberCluster) error {
 	klog.V(2).InfoS("updateInternalMemberClusterWithRetry", "InternalMemberCluster", klog.KObj(imc))
-	backOffPeriod := retry.DefaultBackoff
-	backOffPeriod.Cap = time.Second * time.Duration(imc.Spec.HeartbeatPeriodSeconds)
-
-	return retry.OnError(backOffPeriod,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
-		},
-		func() error {
-			return r.hubClient.Status().Update(ctx, imc)
-		})
+	resourceUsage := imc.Status.ResourceUsage.DeepCopy()
+	agentStatus := imc.GetAgentStatus(fleetv1alpha1.MemberAgent).DeepCopy()
+	return controller.UpdateStatusWithRetry(ctx, r.hubClient, imc, func(imc *fleetv1alpha1.InternalMemberCluster) {
+		imc.Status.ResourceUsage = *resourceUsage.DeepCopy()
+		*imc.GetAgentStatus(fleetv1alpha1.MemberAgent) = *agentStatus.DeepCopy()
+	})
 }
 
 // updateMemberAgentHeartBeat is used to update member agent heart beat for Internal member cluster.
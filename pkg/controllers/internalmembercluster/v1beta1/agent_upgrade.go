@@ -0,0 +1,75 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	"go.goms.io/fleet/pkg/version"
+)
+
+const (
+	// EventReasonAgentUpgradeStarted is the event reason when the member agent updates the image of its deployment
+	// to upgrade itself.
+	EventReasonAgentUpgradeStarted = "AgentUpgradeStarted"
+)
+
+// upgradeAgent updates the image of the deployment of the member agent when the hub agent asks the agent to upgrade
+// itself to another version; the new pods of the deployment then report the version to the hub.
+func (r *Reconciler) upgradeAgent(ctx context.Context, imc *clusterv1beta1.InternalMemberCluster) error {
+	target := imc.Spec.AgentUpgrade
+	if target == nil || target.Version == version.Version {
+		return nil
+	}
+	if r.agentDeployment == nil {
+		klog.V(2).InfoS("The member agent is not allowed to upgrade itself", "InternalMemberCluster", klog.KObj(imc),
+			"version", version.Version, "targetVersion", target.Version)
+		return nil
+	}
+
+	deployments := r.rawMemberClientSet.AppsV1().Deployments(r.agentDeployment.Namespace)
+	deploy, err := deployments.Get(ctx, r.agentDeployment.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the deployment %s of the member agent: %w", r.agentDeployment, err)
+	}
+	container := agentContainer(deploy.Spec.Template.Spec.Containers, deploy.Name)
+	if container == nil {
+		return fmt.Errorf("failed to find the container of the member agent in the deployment %s", r.agentDeployment)
+	}
+	if container.Image == target.Image {
+		// the deployment is being rolled out; the version is reported by its new pods
+		klog.V(2).InfoS("The member agent is being upgraded", "InternalMemberCluster", klog.KObj(imc), "targetVersion", target.Version)
+		return nil
+	}
+	container.Image = target.Image
+	if _, err := deployments.Update(ctx, deploy, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update the image of the deployment %s of the member agent: %w", r.agentDeployment, err)
+	}
+	klog.V(2).InfoS("Started to upgrade the member agent", "InternalMemberCluster", klog.KObj(imc),
+		"version", version.Version, "targetVersion", target.Version, "image", target.Image)
+	r.recorder.Eventf(imc, corev1.EventTypeNormal, EventReasonAgentUpgradeStarted, "upgrading the member agent from %s to %s", version.Version, target.Version)
+	return nil
+}
+
+// agentContainer returns the container of the member agent in the pod template of its deployment, i.e. the container
+// named after the deployment, or the only container.
+func agentContainer(containers []corev1.Container, deploymentName string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == deploymentName {
+			return &containers[i]
+		}
+	}
+	if len(containers) == 1 {
+		return &containers[0]
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/version"
+)
+
+func TestUpgradeAgent(t *testing.T) {
+	agentDeployment := types.NamespacedName{Namespace: "fleet-system", Name: "member-agent"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: agentDeployment.Namespace, Name: agentDeployment.Name},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "sidecar", Image: "sidecar:v1"},
+						{Name: "member-agent", Image: "member-agent:" + version.Version},
+					},
+				},
+			},
+		},
+	}
+	tests := map[string]struct {
+		target          *clusterv1beta1.AgentUpgradeTarget
+		agentDeployment *types.NamespacedName
+		wantImage       string
+	}{
+		"no upgrade": {
+			agentDeployment: &agentDeployment,
+			wantImage:       "member-agent:" + version.Version,
+		},
+		"the agent runs the version": {
+			target:          &clusterv1beta1.AgentUpgradeTarget{Version: version.Version, Image: "member-agent:other"},
+			agentDeployment: &agentDeployment,
+			wantImage:       "member-agent:" + version.Version,
+		},
+		"the agent is not allowed to upgrade itself": {
+			target:    &clusterv1beta1.AgentUpgradeTarget{Version: "v1.2.3", Image: "member-agent:v1.2.3"},
+			wantImage: "member-agent:" + version.Version,
+		},
+		"the agent upgrades itself": {
+			target:          &clusterv1beta1.AgentUpgradeTarget{Version: "v1.2.3", Image: "member-agent:v1.2.3"},
+			agentDeployment: &agentDeployment,
+			wantImage:       "member-agent:v1.2.3",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientSet := fake.NewSimpleClientset(deployment.DeepCopy())
+			r := &Reconciler{
+				rawMemberClientSet: clientSet,
+				agentDeployment:    tt.agentDeployment,
+				recorder:           utils.NewFakeRecorder(1),
+			}
+			imc := &clusterv1beta1.InternalMemberCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-member-cluster-1", Name: "cluster-1"},
+				Spec:       clusterv1beta1.InternalMemberClusterSpec{AgentUpgrade: tt.target},
+			}
+			if err := r.upgradeAgent(context.Background(), imc); err != nil {
+				t.Fatalf("upgradeAgent() = %v, want nil", err)
+			}
+			got, err := clientSet.AppsV1().Deployments(agentDeployment.Namespace).Get(context.Background(), agentDeployment.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get the deployment: %v", err)
+			}
+			if image := got.Spec.Template.Spec.Containers[1].Image; image != tt.wantImage {
+				t.Errorf("upgradeAgent() image = %s, want %s", image, tt.wantImage)
+			}
+			if image := got.Spec.Template.Spec.Containers[0].Image; image != "sidecar:v1" {
+				t.Errorf("upgradeAgent() sidecar image = %s, want unchanged", image)
+			}
+		})
+	}
+}
@@ -17,11 +17,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -33,6 +33,8 @@ import (
 	"go.goms.io/fleet/pkg/metrics"
 	"go.goms.io/fleet/pkg/propertyprovider"
 	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/version"
 )
 
 // propertyProviderConfig is a group of settings for configuring the the property provider.
@@ -77,7 +79,7 @@ type Reconciler struct {
 	//
 	// This client allows the controller to directly send requests to specific endpoints,
 	// specifically to allow health/readiness probes on the API server.
-	rawMemberClientSet *kubernetes.Clientset
+	rawMemberClientSet kubernetes.Interface
 
 	// the join/leave agent maintains the list of controllers in the member cluster
 	// so that it can make sure that all the agents on the member cluster have joined/left
@@ -97,6 +99,14 @@ type Reconciler struct {
 	// The property provider configuration.
 	propertyProviderCfg *propertyProviderConfig
 
+	// manifestEncryptionPublicKey is the base64 encoded public key of the member cluster which the agent publishes so
+	// that the hub agent can seal the secrets in the works for this member cluster.
+	manifestEncryptionPublicKey string
+
+	// agentDeployment is the deployment of the member agent, whose image the agent updates when the hub agent asks it
+	// to upgrade itself; the agent does not upgrade itself if it is not set.
+	agentDeployment *types.NamespacedName
+
 	recorder record.EventRecorder
 }
 
@@ -186,6 +196,19 @@ func NewReconciler(globalCtx context.Context,
 	}, nil
 }
 
+// WithManifestEncryptionPublicKey makes the reconciler publish the manifest encryption key of the member cluster in
+// the internal member cluster status.
+func (r *Reconciler) WithManifestEncryptionPublicKey(publicKey string) *Reconciler {
+	r.manifestEncryptionPublicKey = publicKey
+	return r
+}
+
+// WithAgentDeployment allows the member agent to upgrade itself by updating the image of its deployment.
+func (r *Reconciler) WithAgentDeployment(deployment types.NamespacedName) *Reconciler {
+	r.agentDeployment = &deployment
+	return r
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	startTime := time.Now()
 	klog.V(2).InfoS("InternalMemberCluster reconciliation starts", "InternalMemberCluster", req.NamespacedName)
@@ -202,13 +225,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	switch imc.Spec.State {
 	case clusterv1beta1.ClusterStateJoin:
+		r.workController.SetApplyLimits(imc.Spec.ApplyLimits)
 		if err := r.startAgents(ctx, &imc); err != nil {
 			return ctrl.Result{}, err
 		}
 		updateMemberAgentHeartBeat(&imc)
+		imc.Status.ManifestEncryptionPublicKey = r.manifestEncryptionPublicKey
 		updateHealthErr := r.updateHealth(ctx, &imc)
 		clusterPropertyCollectionErr := r.connectToPropertyProvider(ctx, &imc)
 		r.markInternalMemberClusterJoined(&imc)
+		imc.GetAgentStatus(clusterv1beta1.MemberAgent).Version = version.Version
 		if err := r.updateInternalMemberClusterWithRetry(ctx, &imc); err != nil {
 			if apierrors.IsConflict(err) {
 				klog.V(2).InfoS("Failed to update status due to conflicts", "imc", klog.KObj(&imc))
@@ -217,6 +243,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			}
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
+		// upgrade the agent after its status is reported, so that the hub sees the version that it upgrades from
+		if err := r.upgradeAgent(ctx, &imc); err != nil {
+			klog.ErrorS(err, "Failed to upgrade the member agent", "imc", klog.KObj(&imc))
+			return ctrl.Result{}, err
+		}
 		if updateHealthErr != nil {
 			klog.ErrorS(updateHealthErr, "Failed to update health", "imc", klog.KObj(&imc))
 			return ctrl.Result{}, updateHealthErr
@@ -539,19 +570,22 @@ func (r *Reconciler) updateResourceStats(ctx context.Context, imc *clusterv1beta
 	return nil
 }
 
-// updateInternalMemberClusterWithRetry updates InternalMemberCluster status.
+// updateInternalMemberClusterWithRetry updates the InternalMemberCluster status reported by the member agent.
+//
+// On conflicts, only the fields owned by the member agent are written onto the latest object so that the status
+// reported by the other agents is kept.
 func (r *Reconciler) updateInternalMemberClusterWithRetry(ctx context.Context, imc *clusterv1beta1.InternalMemberCluster) error {
 	klog.V(2).InfoS("Updating InternalMemberCluster status with retries", "InternalMemberCluster", klog.KObj(imc))
-	backOffPeriod := retry.DefaultBackoff
-	backOffPeriod.Cap = time.Second * time.Duration(imc.Spec.HeartbeatPeriodSeconds)
-
-	return retry.OnError(backOffPeriod,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
-		},
-		func() error {
-			return r.hubClient.Status().Update(ctx, imc)
-		})
+	status := imc.Status.DeepCopy()
+	agentStatus := imc.GetAgentStatus(clusterv1beta1.MemberAgent).DeepCopy()
+	return controller.UpdateStatusWithRetry(ctx, r.hubClient, imc, func(imc *clusterv1beta1.InternalMemberCluster) {
+		reported := status.DeepCopy()
+		imc.Status.Conditions = reported.Conditions
+		imc.Status.Properties = reported.Properties
+		imc.Status.ResourceUsage = reported.ResourceUsage
+		imc.Status.ManifestEncryptionPublicKey = reported.ManifestEncryptionPublicKey
+		*imc.GetAgentStatus(clusterv1beta1.MemberAgent) = *agentStatus.DeepCopy()
+	})
 }
 
 // updateMemberAgentHeartBeat is used to update member agent heart beat for Internal member cluster.
@@ -216,6 +216,47 @@ func TestUpdateInternalMemberClusterWithRetry(t *testing.T) {
 	}
 }
 
+func TestUpdateInternalMemberClusterWithRetryOnConflict(t *testing.T) {
+	memberAgentStatus := clusterv1beta1.AgentStatus{
+		Type:       clusterv1beta1.MemberAgent,
+		Conditions: []metav1.Condition{{Type: string(clusterv1beta1.AgentJoined), Status: metav1.ConditionTrue, Reason: EventReasonInternalMemberClusterJoined}},
+	}
+	otherAgentStatus := clusterv1beta1.AgentStatus{
+		Type:       clusterv1beta1.MultiClusterServiceAgent,
+		Conditions: []metav1.Condition{{Type: string(clusterv1beta1.AgentJoined), Status: metav1.ConditionTrue, Reason: "Joined"}},
+	}
+	imc := &clusterv1beta1.InternalMemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "imc", Generation: 1},
+		Status:     clusterv1beta1.InternalMemberClusterStatus{AgentStatus: []clusterv1beta1.AgentStatus{memberAgentStatus}},
+	}
+	var updated *clusterv1beta1.InternalMemberCluster
+	r := &Reconciler{hubClient: &test.MockClient{
+		MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			// another agent has reported its status in the meantime
+			latest := obj.(*clusterv1beta1.InternalMemberCluster)
+			latest.ObjectMeta = metav1.ObjectMeta{Name: "imc", Generation: 1}
+			latest.Status = clusterv1beta1.InternalMemberClusterStatus{AgentStatus: []clusterv1beta1.AgentStatus{otherAgentStatus}}
+			return nil
+		},
+		MockStatusUpdate: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if updated == nil {
+				updated = &clusterv1beta1.InternalMemberCluster{}
+				return apierrors.NewConflict(schema.GroupResource{}, "imc", errors.New("conflict"))
+			}
+			updated = obj.(*clusterv1beta1.InternalMemberCluster).DeepCopy()
+			return nil
+		},
+	}}
+
+	if err := r.updateInternalMemberClusterWithRetry(context.Background(), imc); err != nil {
+		t.Fatalf("updateInternalMemberClusterWithRetry() = %v, want nil", err)
+	}
+	want := []clusterv1beta1.AgentStatus{otherAgentStatus, memberAgentStatus}
+	if diff := cmp.Diff(want, updated.Status.AgentStatus); diff != "" {
+		t.Errorf("updateInternalMemberClusterWithRetry() agent status mismatch (-want, +got):\n%s", diff)
+	}
+}
+
 func TestSetConditionWithType(t *testing.T) {
 	testCases := map[string]struct {
 		internalMemberCluster *clusterv1beta1.InternalMemberCluster
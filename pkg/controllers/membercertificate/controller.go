@@ -0,0 +1,302 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package membercertificate features a controller that issues short-lived client certificates to the member agents
+// through the certificate signing request (CSR) flow of the hub cluster, and cuts the member agents off when their
+// member clusters are removed from the fleet.
+package membercertificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// CSRRequesterClusterRoleName is the name of the cluster role which allows the member agents to request their
+	// client certificates.
+	CSRRequesterClusterRoleName = "fleet-member-csr-requester"
+	// csrRequesterClusterRoleBindingNameFormat is the format of the name of the cluster role binding which grants
+	// the identity of a member cluster the CSRRequesterClusterRoleName cluster role.
+	csrRequesterClusterRoleBindingNameFormat = "fleet-member-csr-requester-%s"
+
+	// the reasons of the CSR approval conditions.
+	approvedReason             = "FleetMemberAgentAutoApproved"
+	deniedReason               = "FleetMemberAgentInvalidRequest"
+	memberClusterRemovedReason = "FleetMemberClusterRemoved"
+)
+
+var (
+	// allowedUsages are the key usages that a member agent client certificate can have.
+	allowedUsages = sets.New[certificatesv1.KeyUsage](
+		certificatesv1.UsageDigitalSignature,
+		certificatesv1.UsageKeyEncipherment,
+		certificatesv1.UsageClientAuth,
+	)
+
+	csrRequesterRule = rbacv1.PolicyRule{
+		Verbs:     []string{"create", "get", "list", "watch"},
+		APIGroups: []string{certificatesv1.GroupName},
+		Resources: []string{"certificatesigningrequests"},
+	}
+)
+
+// Reconciler reconciles a member cluster and the certificate signing requests of its member agent. It
+//   - grants the member cluster identity the permission to create certificate signing requests;
+//   - approves the certificate signing requests that renew the client certificate of the member cluster identity;
+//   - denies all the pending certificate signing requests and revokes the permission when the member cluster is removed.
+//
+// As there is no way to revoke a client certificate in Kubernetes, the member agent is cut off by the removal of all
+// its permissions on the hub cluster, and the short validity of the certificates makes sure that a compromised
+// certificate cannot be used for long.
+type Reconciler struct {
+	Client client.Client
+	// MaxCertificateValidity is the max validity of a client certificate that the controller approves.
+	MaxCertificateValidity time.Duration
+}
+
+// Reconcile reconciles the member cluster and the certificate signing requests of its member agent.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("MemberCertificate reconciliation starts", "memberCluster", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("MemberCertificate reconciliation ends", "memberCluster", req.Name, "latency", latency)
+	}()
+
+	var mc clusterv1beta1.MemberCluster
+	if err := r.Client.Get(ctx, req.NamespacedName, &mc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the member cluster", "memberCluster", req.Name)
+			return ctrl.Result{}, controller.NewAPIServerError(true, err)
+		}
+		klog.V(2).InfoS("The member cluster is removed, revoking its access", "memberCluster", req.Name)
+		return ctrl.Result{}, r.revoke(ctx, req.Name)
+	}
+	if !mc.DeletionTimestamp.IsZero() {
+		klog.V(2).InfoS("The member cluster is leaving, revoking its access", "memberCluster", req.Name)
+		return ctrl.Result{}, r.revoke(ctx, req.Name)
+	}
+
+	if err := r.ensureCSRRequesterPermission(ctx, &mc); err != nil {
+		return ctrl.Result{}, err
+	}
+	csrs, err := r.listPendingCSRs(ctx, mc.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range csrs {
+		csr := &csrs[i]
+		if err := validateCSR(csr, &mc, r.MaxCertificateValidity); err != nil {
+			klog.V(2).InfoS("Denying the invalid certificate signing request", "memberCluster", req.Name, "csr", csr.Name, "reason", err.Error())
+			if err := r.updateApproval(ctx, csr, certificatesv1.CertificateDenied, deniedReason, err.Error()); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		if err := r.updateApproval(ctx, csr, certificatesv1.CertificateApproved, approvedReason,
+			"The client certificate of the fleet member agent is approved by the hub agent"); err != nil {
+			return ctrl.Result{}, err
+		}
+		klog.V(2).InfoS("Approved the certificate signing request", "memberCluster", req.Name, "csr", csr.Name)
+	}
+	return ctrl.Result{}, nil
+}
+
+// revoke denies all the pending certificate signing requests of the member cluster and removes the permission to
+// create new ones.
+func (r *Reconciler) revoke(ctx context.Context, mcName string) error {
+	csrs, err := r.listPendingCSRs(ctx, mcName)
+	if err != nil {
+		return err
+	}
+	for i := range csrs {
+		if err := r.updateApproval(ctx, &csrs[i], certificatesv1.CertificateDenied, memberClusterRemovedReason,
+			fmt.Sprintf("The member cluster %s is removed from the fleet", mcName)); err != nil {
+			return err
+		}
+		klog.V(2).InfoS("Denied the certificate signing request of the removed member cluster", "memberCluster", mcName, "csr", csrs[i].Name)
+	}
+
+	binding := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf(csrRequesterClusterRoleBindingNameFormat, mcName),
+		},
+	}
+	if err := r.Client.Delete(ctx, &binding); err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to delete the cluster role binding", "memberCluster", mcName, "clusterRoleBinding", binding.Name)
+		return controller.NewAPIServerError(false, err)
+	}
+	return nil
+}
+
+// ensureCSRRequesterPermission makes sure that the member cluster identity can create certificate signing requests.
+func (r *Reconciler) ensureCSRRequesterPermission(ctx context.Context, mc *clusterv1beta1.MemberCluster) error {
+	role := rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: CSRRequesterClusterRoleName},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, &role, func() error {
+		role.Labels = map[string]string{utils.LabelFleetObj: utils.LabelFleetObjValue}
+		role.Rules = []rbacv1.PolicyRule{csrRequesterRule}
+		return nil
+	}); err != nil {
+		klog.ErrorS(err, "Failed to create or update the cluster role", "clusterRole", role.Name)
+		return controller.NewAPIServerError(false, err)
+	}
+
+	binding := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf(csrRequesterClusterRoleBindingNameFormat, mc.Name),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, &binding, func() error {
+		binding.Labels = map[string]string{
+			utils.LabelFleetObj:                     utils.LabelFleetObjValue,
+			placementv1beta1.MemberClusterNameLabel: mc.Name,
+		}
+		// the binding is garbage collected together with the member cluster
+		binding.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(mc, clusterv1beta1.GroupVersion.WithKind(clusterv1beta1.MemberClusterKind))}
+		binding.Subjects = []rbacv1.Subject{mc.Spec.Identity}
+		binding.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     CSRRequesterClusterRoleName,
+		}
+		return nil
+	}); err != nil {
+		klog.ErrorS(err, "Failed to create or update the cluster role binding", "memberCluster", klog.KObj(mc), "clusterRoleBinding", binding.Name)
+		return controller.NewAPIServerError(false, err)
+	}
+	return nil
+}
+
+// listPendingCSRs lists the certificate signing requests of the member cluster which are neither approved nor denied.
+func (r *Reconciler) listPendingCSRs(ctx context.Context, mcName string) ([]certificatesv1.CertificateSigningRequest, error) {
+	var csrList certificatesv1.CertificateSigningRequestList
+	if err := r.Client.List(ctx, &csrList, client.MatchingLabels{placementv1beta1.MemberClusterNameLabel: mcName}); err != nil {
+		klog.ErrorS(err, "Failed to list the certificate signing requests", "memberCluster", mcName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	pending := make([]certificatesv1.CertificateSigningRequest, 0, len(csrList.Items))
+	for i := range csrList.Items {
+		if isPending(&csrList.Items[i]) {
+			pending = append(pending, csrList.Items[i])
+		}
+	}
+	return pending, nil
+}
+
+// updateApproval approves or denies the certificate signing request.
+func (r *Reconciler) updateApproval(ctx context.Context, csr *certificatesv1.CertificateSigningRequest,
+	conditionType certificatesv1.RequestConditionType, reason, message string) error {
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:           conditionType,
+		Status:         corev1.ConditionTrue,
+		Reason:         reason,
+		Message:        message,
+		LastUpdateTime: metav1.Now(),
+	})
+	if err := r.Client.SubResource("approval").Update(ctx, csr); err != nil {
+		klog.ErrorS(err, "Failed to update the approval of the certificate signing request", "csr", csr.Name, "type", conditionType)
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return nil
+}
+
+// isPending returns true if the certificate signing request is neither approved nor denied.
+func isPending(csr *certificatesv1.CertificateSigningRequest) bool {
+	for _, c := range csr.Status.Conditions {
+		if c.Type == certificatesv1.CertificateApproved || c.Type == certificatesv1.CertificateDenied {
+			return false
+		}
+	}
+	return true
+}
+
+// validateCSR checks that the certificate signing request is created by the member agent with the identity of the
+// member cluster, and asks for a short-lived client certificate of the same identity without any other privilege.
+func validateCSR(csr *certificatesv1.CertificateSigningRequest, mc *clusterv1beta1.MemberCluster, maxValidity time.Duration) error {
+	identity := mc.Spec.Identity
+	if identity.Kind != rbacv1.UserKind {
+		return fmt.Errorf("the identity of the member cluster is a %s instead of a %s", identity.Kind, rbacv1.UserKind)
+	}
+	if csr.Spec.SignerName != certificatesv1.KubeAPIServerClientSignerName {
+		return fmt.Errorf("the signer %s is not %s", csr.Spec.SignerName, certificatesv1.KubeAPIServerClientSignerName)
+	}
+	if csr.Spec.Username != identity.Name {
+		return fmt.Errorf("the request is created by %s instead of the member cluster identity %s", csr.Spec.Username, identity.Name)
+	}
+	if len(csr.Spec.Usages) == 0 || !allowedUsages.HasAll(csr.Spec.Usages...) {
+		return fmt.Errorf("the usages %v are not a subset of %v", csr.Spec.Usages, sets.List(allowedUsages))
+	}
+	if csr.Spec.ExpirationSeconds == nil {
+		return errors.New("the expiration of the certificate is not set")
+	}
+	if validity := time.Duration(*csr.Spec.ExpirationSeconds) * time.Second; validity > maxValidity {
+		return fmt.Errorf("the validity %s of the certificate is longer than %s", validity, maxValidity)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return errors.New("the request is not a PEM encoded certificate request")
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("the certificate request cannot be parsed: %w", err)
+	}
+	if err := request.CheckSignature(); err != nil {
+		return fmt.Errorf("the signature of the certificate request is invalid: %w", err)
+	}
+	if request.Subject.CommonName != identity.Name {
+		return fmt.Errorf("the common name %s is not the member cluster identity %s", request.Subject.CommonName, identity.Name)
+	}
+	// the organizations are the groups of the user, which may grant extra privileges
+	if len(request.Subject.Organization) != 0 {
+		return fmt.Errorf("the certificate request cannot have any organization, got %v", request.Subject.Organization)
+	}
+	if len(request.DNSNames) != 0 || len(request.IPAddresses) != 0 || len(request.EmailAddresses) != 0 || len(request.URIs) != 0 {
+		return errors.New("the certificate request cannot have any subject alternative name")
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("member-certificate-controller").
+		For(&clusterv1beta1.MemberCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&certificatesv1.CertificateSigningRequest{}, handler.EnqueueRequestsFromMapFunc(
+			func(_ context.Context, obj client.Object) []reconcile.Request {
+				mcName, ok := obj.GetLabels()[placementv1beta1.MemberClusterNameLabel]
+				if !ok {
+					return nil
+				}
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: mcName}}}
+			})).
+		Complete(r)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package membercertificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testMemberClusterName = "member-1"
+	testIdentity          = "fleet:member:member-1"
+)
+
+func newTestMemberCluster() *clusterv1beta1.MemberCluster {
+	return &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: testMemberClusterName},
+		Spec: clusterv1beta1.MemberClusterSpec{
+			Identity: rbacv1.Subject{Kind: rbacv1.UserKind, Name: testIdentity},
+		},
+	}
+}
+
+func newTestCSR(t *testing.T, name string, subject pkix.Name, modify func(csr *certificatesv1.CertificateSigningRequest)) *certificatesv1.CertificateSigningRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+	if err != nil {
+		t.Fatalf("failed to create the certificate request: %v", err)
+	}
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{placementv1beta1.MemberClusterNameLabel: testMemberClusterName},
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName:        certificatesv1.KubeAPIServerClientSignerName,
+			ExpirationSeconds: ptr.To(int32(3600)),
+			Usages:            []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+			Username:          testIdentity,
+		},
+	}
+	if modify != nil {
+		modify(csr)
+	}
+	return csr
+}
+
+func TestValidateCSR(t *testing.T) {
+	tests := map[string]struct {
+		subject pkix.Name
+		modify  func(csr *certificatesv1.CertificateSigningRequest)
+		modifyM func(mc *clusterv1beta1.MemberCluster)
+		wantErr string
+	}{
+		"valid request": {
+			subject: pkix.Name{CommonName: testIdentity},
+		},
+		"identity is not a user": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modifyM: func(mc *clusterv1beta1.MemberCluster) {
+				mc.Spec.Identity.Kind = rbacv1.ServiceAccountKind
+			},
+			wantErr: "instead of a User",
+		},
+		"wrong signer": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modify: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Spec.SignerName = certificatesv1.KubeletServingSignerName
+			},
+			wantErr: "the signer",
+		},
+		"created by another user": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modify: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Spec.Username = "fleet:member:member-2"
+			},
+			wantErr: "instead of the member cluster identity",
+		},
+		"server auth usage": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modify: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Spec.Usages = append(csr.Spec.Usages, certificatesv1.UsageServerAuth)
+			},
+			wantErr: "the usages",
+		},
+		"no expiration": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modify: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Spec.ExpirationSeconds = nil
+			},
+			wantErr: "the expiration of the certificate is not set",
+		},
+		"validity too long": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modify: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Spec.ExpirationSeconds = ptr.To(int32(48 * 3600))
+			},
+			wantErr: "is longer than",
+		},
+		"another common name": {
+			subject: pkix.Name{CommonName: "fleet:member:member-2"},
+			wantErr: "the common name",
+		},
+		"with organization": {
+			subject: pkix.Name{CommonName: testIdentity, Organization: []string{"system:masters"}},
+			wantErr: "cannot have any organization",
+		},
+		"not a certificate request": {
+			subject: pkix.Name{CommonName: testIdentity},
+			modify: func(csr *certificatesv1.CertificateSigningRequest) {
+				csr.Spec.Request = []byte("invalid")
+			},
+			wantErr: "not a PEM encoded certificate request",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mc := newTestMemberCluster()
+			if tc.modifyM != nil {
+				tc.modifyM(mc)
+			}
+			err := validateCSR(newTestCSR(t, "csr", tc.subject, tc.modify), mc, 24*time.Hour)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateCSR() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("validateCSR() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func approvalOf(t *testing.T, c client.Client, name string) certificatesv1.RequestConditionType {
+	var csr certificatesv1.CertificateSigningRequest
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name}, &csr); err != nil {
+		t.Fatalf("failed to get the csr %s: %v", name, err)
+	}
+	for _, cond := range csr.Status.Conditions {
+		if cond.Type == certificatesv1.CertificateApproved || cond.Type == certificatesv1.CertificateDenied {
+			return cond.Type
+		}
+	}
+	return ""
+}
+
+func newFakeReconciler(t *testing.T, objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the client-go scheme: %v", err)
+	}
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the cluster scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&certificatesv1.CertificateSigningRequest{}).
+		Build()
+	return &Reconciler{Client: fakeClient, MaxCertificateValidity: 24 * time.Hour}
+}
+
+func TestReconcile(t *testing.T) {
+	mc := newTestMemberCluster()
+	validCSR := newTestCSR(t, "valid", pkix.Name{CommonName: testIdentity}, nil)
+	invalidCSR := newTestCSR(t, "invalid", pkix.Name{CommonName: testIdentity, Organization: []string{"system:masters"}}, nil)
+	r := newFakeReconciler(t, mc, validCSR, invalidCSR)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testMemberClusterName}}); err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	if got := approvalOf(t, r.Client, "valid"); got != certificatesv1.CertificateApproved {
+		t.Errorf("approval of the valid csr = %q, want %q", got, certificatesv1.CertificateApproved)
+	}
+	if got := approvalOf(t, r.Client, "invalid"); got != certificatesv1.CertificateDenied {
+		t.Errorf("approval of the invalid csr = %q, want %q", got, certificatesv1.CertificateDenied)
+	}
+	var binding rbacv1.ClusterRoleBinding
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: "fleet-member-csr-requester-" + testMemberClusterName}, &binding); err != nil {
+		t.Fatalf("failed to get the cluster role binding: %v", err)
+	}
+	if len(binding.Subjects) != 1 || binding.Subjects[0] != mc.Spec.Identity {
+		t.Errorf("cluster role binding subjects = %v, want %v", binding.Subjects, mc.Spec.Identity)
+	}
+}
+
+func TestReconcile_MemberClusterRemoved(t *testing.T) {
+	pendingCSR := newTestCSR(t, "pending", pkix.Name{CommonName: testIdentity}, nil)
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet-member-csr-requester-" + testMemberClusterName},
+	}
+	r := newFakeReconciler(t, pendingCSR, binding)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testMemberClusterName}}); err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	if got := approvalOf(t, r.Client, "pending"); got != certificatesv1.CertificateDenied {
+		t.Errorf("approval of the pending csr = %q, want %q", got, certificatesv1.CertificateDenied)
+	}
+	err := r.Client.Get(context.Background(), types.NamespacedName{Name: binding.Name}, &rbacv1.ClusterRoleBinding{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("get the cluster role binding = %v, want not found", err)
+	}
+}
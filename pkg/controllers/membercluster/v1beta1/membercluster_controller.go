@@ -45,6 +45,7 @@ const (
 	eventReasonRoleBindingUpdated     = "RoleBindingUpdated"
 	eventReasonIMCCreated             = "InternalMemberClusterCreated"
 	eventReasonIMCSpecUpdated         = "InternalMemberClusterSpecUpdated"
+	eventReasonIdentityConflict       = "IdentityConflict"
 	reasonMemberClusterReadyToJoin    = "MemberClusterReadyToJoin"
 	reasonMemberClusterNotReadyToJoin = "MemberClusterNotReadyToJoin"
 	reasonMemberClusterJoined         = "MemberClusterJoined"
@@ -52,6 +53,16 @@ const (
 	reasonMemberClusterUnknown        = "MemberClusterJoinStateUnknown"
 )
 
+// memberAgentRules are the permissions of the member agents in their reserved namespaces on the hub cluster.
+var memberAgentRules = []rbacv1.PolicyRule{
+	utils.MemberAgentWorkRule,
+	utils.MemberAgentWorkStatusRule,
+	utils.MemberAgentMembershipRule,
+	utils.MemberAgentMembershipStatusRule,
+	utils.FleetNetworkRule,
+	utils.EventRule,
+}
+
 // Reconciler reconciles a MemberCluster object
 type Reconciler struct {
 	client.Client
@@ -226,6 +237,10 @@ func (r *Reconciler) join(ctx context.Context, mc *clusterv1beta1.MemberCluster,
 		return fmt.Errorf("failed to sync role: %w", err)
 	}
 
+	if err := r.checkIdentityConflict(ctx, mc); err != nil {
+		return err
+	}
+
 	err = r.syncRoleBinding(ctx, mc, namespaceName, roleName)
 	if err != nil {
 		return fmt.Errorf("failed to sync role binding: %w", err)
@@ -297,6 +312,34 @@ func (r *Reconciler) syncNamespace(ctx context.Context, mc *clusterv1beta1.Membe
 	return namespaceName, nil
 }
 
+// checkIdentityConflict makes sure that the identity of the member cluster is not used by any other member cluster.
+// Otherwise, the member agent of one cluster could access the reserved namespace of the other one. The member cluster
+// created first keeps the identity.
+func (r *Reconciler) checkIdentityConflict(ctx context.Context, mc *clusterv1beta1.MemberCluster) error {
+	var mcList clusterv1beta1.MemberClusterList
+	if err := r.Client.List(ctx, &mcList); err != nil {
+		return controller.NewAPIServerError(true, err)
+	}
+	for i := range mcList.Items {
+		other := &mcList.Items[i]
+		if other.Name == mc.Name || !other.DeletionTimestamp.IsZero() || !sameSubject(other.Spec.Identity, mc.Spec.Identity) {
+			continue
+		}
+		if other.CreationTimestamp.Before(&mc.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&mc.CreationTimestamp) && other.Name < mc.Name) {
+			r.recorder.Eventf(mc, corev1.EventTypeWarning, eventReasonIdentityConflict, "identity is already used by member cluster %s", other.Name)
+			klog.V(2).InfoS("The identity is already used by another member cluster", "memberCluster", klog.KObj(mc), "otherMemberCluster", klog.KObj(other), "subject", mc.Spec.Identity)
+			return controller.NewUserError(fmt.Errorf("the identity %s %s is already used by member cluster %s", mc.Spec.Identity.Kind, mc.Spec.Identity.Name, other.Name))
+		}
+	}
+	return nil
+}
+
+// sameSubject returns true if the two subjects refer to the same identity.
+func sameSubject(a, b rbacv1.Subject) bool {
+	return a.Kind == b.Kind && a.Name == b.Name && a.Namespace == b.Namespace
+}
+
 // syncRole creates or updates the role for member cluster to access its namespace in hub cluster.
 func (r *Reconciler) syncRole(ctx context.Context, mc *clusterv1beta1.MemberCluster, namespaceName string) (string, error) {
 	klog.V(2).InfoS("Sync the role for the member cluster", "memberCluster", klog.KObj(mc))
@@ -308,7 +351,8 @@ func (r *Reconciler) syncRole(ctx context.Context, mc *clusterv1beta1.MemberClus
 			Namespace:       namespaceName,
 			OwnerReferences: []metav1.OwnerReference{*toOwnerReference(mc)},
 		},
-		Rules: []rbacv1.PolicyRule{utils.FleetClusterRule, utils.FleetPlacementRule, utils.FleetNetworkRule, utils.EventRule},
+		// The member agent can only access its own works and membership in its reserved namespace.
+		Rules: memberAgentRules,
 	}
 
 	// Creates role if not found.
@@ -381,7 +425,7 @@ func (r *Reconciler) syncRoleBinding(ctx context.Context, mc *clusterv1beta1.Mem
 	currentRoleBinding.Subjects = expectedRoleBinding.Subjects
 	currentRoleBinding.RoleRef = expectedRoleBinding.RoleRef
 	klog.V(2).InfoS("updating role binding", "memberCluster", klog.KObj(mc), "subject", mc.Spec.Identity)
-	if err := r.Client.Update(ctx, &expectedRoleBinding, client.FieldOwner(utils.MCControllerFieldManagerName)); err != nil {
+	if err := r.Client.Update(ctx, &currentRoleBinding, client.FieldOwner(utils.MCControllerFieldManagerName)); err != nil {
 		return fmt.Errorf("failed to update role binding %s: %w", roleBindingName, err)
 	}
 	r.recorder.Event(mc, corev1.EventTypeNormal, eventReasonRoleBindingUpdated, "role binding was updated")
@@ -401,6 +445,7 @@ func (r *Reconciler) syncInternalMemberCluster(ctx context.Context, mc *clusterv
 		},
 		Spec: clusterv1beta1.InternalMemberClusterSpec{
 			HeartbeatPeriodSeconds: mc.Spec.HeartbeatPeriodSeconds,
+			ApplyLimits:            mc.Spec.ApplyLimits,
 		},
 	}
 	if mc.GetDeletionTimestamp().IsZero() {
@@ -420,6 +465,9 @@ func (r *Reconciler) syncInternalMemberCluster(ctx context.Context, mc *clusterv
 		return &expectedImc, nil
 	}
 
+	// The agent upgrade is set by the agent upgrade controller instead of synced from the member cluster.
+	expectedImc.Spec.AgentUpgrade = currentImc.Spec.AgentUpgrade
+
 	// Updates internal member cluster if currentImc != expectedImc.
 	if reflect.DeepEqual(currentImc.Spec, expectedImc.Spec) {
 		return currentImc, nil
@@ -460,6 +508,8 @@ func (r *Reconciler) syncInternalMemberClusterStatus(imc *clusterv1beta1.Interna
 	}
 	// Copy the cluster properties.
 	mc.Status.Properties = imc.Status.Properties
+	// Copy the manifest encryption key.
+	mc.Status.ManifestEncryptionPublicKey = imc.Status.ManifestEncryptionPublicKey
 }
 
 // updateMemberClusterStatus is used to update member cluster status.
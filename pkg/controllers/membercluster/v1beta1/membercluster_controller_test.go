@@ -214,7 +214,7 @@ func TestSyncRole(t *testing.T) {
 								Name:      "fleet-role-mc1",
 								Namespace: namespace1,
 							},
-							Rules: []rbacv1.PolicyRule{utils.FleetClusterRule, utils.FleetPlacementRule, utils.FleetNetworkRule, utils.EventRule},
+							Rules: memberAgentRules,
 						}
 						return nil
 					},
@@ -498,6 +498,13 @@ func TestSyncRoleBinding(t *testing.T) {
 			r: &Reconciler{
 				Client: &test.MockClient{
 					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						o := obj.(*rbacv1.RoleBinding)
+						*o = rbacv1.RoleBinding{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "fleet-rolebinding-mc6",
+								Namespace: "fleet-mc6",
+							},
+						}
 						return nil
 					},
 					MockUpdate: updateMock},
@@ -529,6 +536,64 @@ func TestSyncRoleBinding(t *testing.T) {
 	}
 }
 
+func TestCheckIdentityConflict(t *testing.T) {
+	identity := rbacv1.Subject{Kind: "User", Name: "MemberClusterIdentity"}
+	earlier := metav1.NewTime(time.Now().Add(-time.Hour))
+	now := metav1.NewTime(time.Now())
+	newMemberCluster := func(name string, created metav1.Time, identity rbacv1.Subject) clusterv1beta1.MemberCluster {
+		return clusterv1beta1.MemberCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created},
+			Spec:       clusterv1beta1.MemberClusterSpec{Identity: identity},
+		}
+	}
+
+	tests := map[string]struct {
+		memberCluster clusterv1beta1.MemberCluster
+		others        []clusterv1beta1.MemberCluster
+		wantedError   string
+	}{
+		"identity is not used by others": {
+			memberCluster: newMemberCluster("mc1", now, identity),
+			others:        []clusterv1beta1.MemberCluster{newMemberCluster("mc2", earlier, rbacv1.Subject{Kind: "User", Name: "OtherIdentity"})},
+		},
+		"identity is used by an earlier member cluster": {
+			memberCluster: newMemberCluster("mc1", now, identity),
+			others:        []clusterv1beta1.MemberCluster{newMemberCluster("mc2", earlier, identity)},
+			wantedError:   "is already used by member cluster mc2",
+		},
+		"identity is used by a later member cluster": {
+			memberCluster: newMemberCluster("mc1", earlier, identity),
+			others:        []clusterv1beta1.MemberCluster{newMemberCluster("mc2", now, identity)},
+		},
+		"identity is used by a member cluster created at the same time with a smaller name": {
+			memberCluster: newMemberCluster("mc2", now, identity),
+			others:        []clusterv1beta1.MemberCluster{newMemberCluster("mc1", now, identity)},
+			wantedError:   "is already used by member cluster mc1",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			r := &Reconciler{
+				Client: &test.MockClient{
+					MockList: func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+						o := list.(*clusterv1beta1.MemberClusterList)
+						o.Items = append([]clusterv1beta1.MemberCluster{tt.memberCluster}, tt.others...)
+						return nil
+					},
+				},
+				recorder: utils.NewFakeRecorder(1),
+			}
+			err := r.checkIdentityConflict(context.Background(), &tt.memberCluster)
+			if tt.wantedError == "" {
+				assert.Equal(t, err, nil, utils.TestCaseMsg, testName)
+			} else {
+				assert.Contains(t, err.Error(), tt.wantedError, utils.TestCaseMsg, testName)
+			}
+		})
+	}
+}
+
 func TestSyncInternalMemberCluster(t *testing.T) {
 	deleteTime := metav1.Now()
 	updateMock := func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
@@ -724,6 +789,7 @@ func TestSyncInternalMemberClusterStatus(t *testing.T) {
 							LastTransitionTime: now,
 						},
 					},
+					ManifestEncryptionPublicKey: "bWFuaWZlc3QtZW5jcnlwdGlvbi1rZXk=",
 					Properties: map[clusterv1beta1.PropertyName]clusterv1beta1.PropertyValue{
 						clusterPropertyName1: {
 							Value:           clusterPropertyValue1,
@@ -797,6 +863,7 @@ func TestSyncInternalMemberClusterStatus(t *testing.T) {
 							Message: propertyProviderConditionMessage2,
 						},
 					},
+					ManifestEncryptionPublicKey: "bWFuaWZlc3QtZW5jcnlwdGlvbi1rZXk=",
 					Properties: map[clusterv1beta1.PropertyName]clusterv1beta1.PropertyValue{
 						clusterPropertyName1: {
 							Value:           clusterPropertyValue1,
@@ -1450,6 +1517,8 @@ func TestSyncInternalMemberClusterStatus(t *testing.T) {
 
 			// Compare the properties (if present).
 			assert.Equal(t, tt.wantedMemberCluster.Status.Properties, tt.memberCluster.Status.Properties)
+			// Compare the manifest encryption key.
+			assert.Equal(t, tt.wantedMemberCluster.Status.ManifestEncryptionPublicKey, tt.memberCluster.Status.ManifestEncryptionPublicKey)
 			// Compare the resource usage.
 			assert.Equal(t, tt.wantedMemberCluster.Status.ResourceUsage, tt.memberCluster.Status.ResourceUsage)
 			// Compare the agent status.
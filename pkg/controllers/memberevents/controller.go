@@ -0,0 +1,116 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package memberevents features a controller that attaches the events forwarded by the member agents to the works on
+// the hub cluster, e.g. the failures of the pods of a placed deployment, to the cluster resource placements and the
+// bindings of the works, so that the root causes of the failures are visible without access to the member clusters.
+package memberevents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// Reconciler records the events forwarded to a work by its member agent on the cluster resource placement and the
+// binding of the work.
+type Reconciler struct {
+	Client   client.Client
+	Recorder record.EventRecorder
+}
+
+// Reconcile attaches the forwarded event to the placement and the binding of its work.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var evt corev1.Event
+	if err := r.Client.Get(ctx, req.NamespacedName, &evt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !isForwardedWorkEvent(&evt) {
+		return ctrl.Result{}, nil
+	}
+	var w fleetv1beta1.Work
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: evt.InvolvedObject.Namespace, Name: evt.InvolvedObject.Name}, &w); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+
+	cluster := strings.TrimPrefix(w.Namespace, fmt.Sprintf(utils.NamespaceNameFormat, ""))
+	annotations := map[string]string{work.ForwardedEventObjectAnnotation: evt.Annotations[work.ForwardedEventObjectAnnotation]}
+	if crpName := w.Labels[fleetv1beta1.CRPTrackingLabel]; crpName != "" {
+		var crp fleetv1beta1.ClusterResourcePlacement
+		switch err := r.Client.Get(ctx, types.NamespacedName{Name: crpName}, &crp); {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return ctrl.Result{}, controller.NewAPIServerError(true, err)
+		default:
+			r.Recorder.AnnotatedEventf(&crp, annotations, evt.Type, evt.Reason, "Member cluster %s: %s", cluster, evt.Message)
+		}
+	}
+	if bindingName := w.Labels[fleetv1beta1.ParentBindingLabel]; bindingName != "" {
+		var binding fleetv1beta1.ClusterResourceBinding
+		switch err := r.Client.Get(ctx, types.NamespacedName{Name: bindingName}, &binding); {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return ctrl.Result{}, controller.NewAPIServerError(true, err)
+		default:
+			r.Recorder.AnnotatedEventf(&binding, annotations, evt.Type, evt.Reason, "Member cluster %s: %s", cluster, evt.Message)
+		}
+	}
+	klog.V(2).InfoS("Attached a forwarded member event to the placement", "event", klog.KObj(&evt), "work", klog.KObj(&w), "reason", evt.Reason)
+	return ctrl.Result{}, nil
+}
+
+// isForwardedWorkEvent tells if the event is forwarded to a work by its member agent.
+func isForwardedWorkEvent(evt *corev1.Event) bool {
+	_, forwarded := evt.Annotations[work.ForwardedEventObjectAnnotation]
+	return forwarded && evt.InvolvedObject.Kind == fleetv1beta1.WorkKind &&
+		strings.HasPrefix(evt.InvolvedObject.APIVersion, fleetv1beta1.GroupVersion.Group+"/")
+}
+
+// SetupWithManager sets up the controller with the manager; the forwarded events are attached when they are created
+// and when they recur.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("member-event-controller").
+		For(&corev1.Event{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				evt, ok := e.Object.(*corev1.Event)
+				return ok && isForwardedWorkEvent(evt)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldEvt, oldOK := e.ObjectOld.(*corev1.Event)
+				newEvt, newOK := e.ObjectNew.(*corev1.Event)
+				return oldOK && newOK && isForwardedWorkEvent(newEvt) && oldEvt.Count != newEvt.Count
+			},
+			DeleteFunc: func(event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Complete(r)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package memberevents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func TestReconcile(t *testing.T) {
+	namespace := fmt.Sprintf(utils.NamespaceNameFormat, "member-1")
+	crp := &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp"}}
+	binding := &fleetv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: "crp-member-1"}}
+	w := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crp-work",
+			Namespace: namespace,
+			Labels: map[string]string{
+				fleetv1beta1.CRPTrackingLabel:   crp.Name,
+				fleetv1beta1.ParentBindingLabel: binding.Name,
+			},
+		},
+	}
+	newEvent := func(name string, annotated bool, involved corev1.ObjectReference) *corev1.Event {
+		evt := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace},
+			InvolvedObject: involved,
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			Message:        "Pod app/web-x2x5q: 0/3 nodes are available",
+		}
+		if annotated {
+			evt.Annotations = map[string]string{work.ForwardedEventObjectAnnotation: "Pod app/web-x2x5q"}
+		}
+		return evt
+	}
+	workRef := corev1.ObjectReference{APIVersion: fleetv1beta1.GroupVersion.String(), Kind: fleetv1beta1.WorkKind, Namespace: namespace, Name: w.Name}
+	wantEvent := "Warning FailedScheduling Member cluster member-1: Pod app/web-x2x5q: 0/3 nodes are available " +
+		"map[kubernetes-fleet.io/forwarded-event-object:Pod app/web-x2x5q]"
+
+	tests := map[string]struct {
+		objects []client.Object
+		event   *corev1.Event
+		want    []string
+	}{
+		"forwarded event": {
+			objects: []client.Object{crp, binding, w},
+			event:   newEvent("forwarded", true, workRef),
+			want:    []string{wantEvent, wantEvent},
+		},
+		"forwarded event of a work whose binding is gone": {
+			objects: []client.Object{crp, w},
+			event:   newEvent("forwarded", true, workRef),
+			want:    []string{wantEvent},
+		},
+		"forwarded event of a work which is gone": {
+			objects: []client.Object{crp, binding},
+			event:   newEvent("forwarded", true, workRef),
+		},
+		"event not forwarded": {
+			objects: []client.Object{crp, binding, w},
+			event:   newEvent("local", false, workRef),
+		},
+		"forwarded event of another object": {
+			objects: []client.Object{crp, binding, w},
+			event: newEvent("other", true, corev1.ObjectReference{
+				APIVersion: "v1", Kind: "ConfigMap", Namespace: namespace, Name: w.Name,
+			}),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tt.objects, tt.event)...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{Client: fakeClient, Recorder: recorder}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: tt.event.Namespace, Name: tt.event.Name}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() = %v, want nil", err)
+			}
+			close(recorder.Events)
+			var got []string
+			for evt := range recorder.Events {
+				got = append(got, evt)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Reconcile() recorded events mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementevents features an emitter which publishes the lifecycle transitions of the cluster resource
+// placements as CloudEvents, so that the external workflow engines can react to them without polling the hub.
+package placementevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	emitterName = "placement-event-emitter"
+	// emitterWorkers is the number of the workers which send the events concurrently.
+	emitterWorkers = 4
+)
+
+// Emitter watches the cluster resource placements and sends their lifecycle transitions to the sink. The events are
+// queued and retried with the rate limiter; only the leader emits the events.
+type Emitter struct {
+	cache      cache.Cache
+	sink       Sink
+	controller controller.Controller
+}
+
+// NewEmitter returns an emitter which sends the events of the placements in the cache to the sink.
+func NewEmitter(cache cache.Cache, sink Sink, rateLimiter workqueue.RateLimiter) *Emitter {
+	e := &Emitter{cache: cache, sink: sink}
+	e.controller = controller.NewController(emitterName, func(obj interface{}) (controller.QueueKey, error) {
+		return obj, nil
+	}, e.send, rateLimiter)
+	return e
+}
+
+// Start watches the placements and sends their events until the context is done.
+func (e *Emitter) Start(ctx context.Context) error {
+	informer, err := e.cache.GetInformer(ctx, &placementv1beta1.ClusterResourcePlacement{})
+	if err != nil {
+		return fmt.Errorf("failed to get the informer of the cluster resource placements: %w", err)
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCRP, oldOK := oldObj.(*placementv1beta1.ClusterResourcePlacement)
+			newCRP, newOK := newObj.(*placementv1beta1.ClusterResourcePlacement)
+			if !oldOK || !newOK {
+				return
+			}
+			for _, event := range placementEvents(oldCRP, newCRP) {
+				klog.V(2).InfoS("Emitting a placement event", "clusterResourcePlacement", newCRP.Name, "type", event.Type, "cluster", event.Cluster)
+				e.controller.Enqueue(event)
+			}
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to watch the cluster resource placements: %w", err)
+	}
+	return e.controller.Run(ctx, emitterWorkers)
+}
+
+// NeedLeaderElection makes only the leader emit the events.
+func (e *Emitter) NeedLeaderElection() bool {
+	return true
+}
+
+// send sends the event in the queue to the sink.
+func (e *Emitter) send(ctx context.Context, key controller.QueueKey) (ctrl.Result, error) {
+	event, ok := key.(Event)
+	if !ok {
+		klog.ErrorS(controller.NewUnexpectedBehaviorError(fmt.Errorf("got a key %+v not of type Event", key)), "Dropping an invalid placement event")
+		return ctrl.Result{}, nil
+	}
+	if err := e.sink.Send(ctx, event); err != nil {
+		if errors.Is(err, ErrPermanentSendFailure) {
+			klog.ErrorS(err, "Dropping a placement event rejected by the sink", "clusterResourcePlacement", event.Placement, "type", event.Type, "id", event.ID())
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to send a placement event", "clusterResourcePlacement", event.Placement, "type", event.Type, "id", event.ID())
+		return ctrl.Result{}, err
+	}
+	klog.V(2).InfoS("Sent a placement event", "clusterResourcePlacement", event.Placement, "type", event.Type, "id", event.ID())
+	return ctrl.Result{}, nil
+}
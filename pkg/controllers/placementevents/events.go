@@ -0,0 +1,161 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementevents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// The types of the CloudEvents emitted for the lifecycle transitions of a cluster resource placement.
+const (
+	// EventTypeScheduled is emitted when the placement is scheduled for its latest generation.
+	EventTypeScheduled = "io.kubernetes-fleet.placement.scheduled"
+	// EventTypeRolloutStageAdvanced is emitted when the rollout of the placement reaches a cluster.
+	EventTypeRolloutStageAdvanced = "io.kubernetes-fleet.placement.rolloutStageAdvanced"
+	// EventTypeApplied is emitted when the resources are applied on all the selected clusters.
+	EventTypeApplied = "io.kubernetes-fleet.placement.applied"
+	// EventTypeAvailable is emitted when the resources are available on all the selected clusters.
+	EventTypeAvailable = "io.kubernetes-fleet.placement.available"
+	// EventTypeFailed is emitted when a condition of the placement turns false, e.g. the resources fail to apply.
+	EventTypeFailed = "io.kubernetes-fleet.placement.failed"
+	// EventTypeEvicted is emitted when a cluster is removed from the placement.
+	EventTypeEvicted = "io.kubernetes-fleet.placement.evicted"
+)
+
+// Event is a lifecycle transition of a cluster resource placement. It is comparable so that it can be queued.
+type Event struct {
+	// Type is the CloudEvents type of the event.
+	Type string
+	// Placement is the name of the cluster resource placement.
+	Placement string
+	// PlacementUID is the UID of the cluster resource placement.
+	PlacementUID string
+	// Generation is the generation of the placement which the transition is observed for.
+	Generation int64
+	// Cluster is the member cluster of a per cluster transition.
+	Cluster string
+	// ConditionType, Reason and Message describe the condition of the transition.
+	ConditionType string
+	Reason        string
+	Message       string
+	// Time is when the transition happened.
+	Time time.Time
+}
+
+// ID returns the CloudEvents id of the event, which is deterministic so that the sink can deduplicate the retries.
+func (e Event) ID() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d/%s/%s/%s", e.PlacementUID, e.Type, e.Generation, e.Cluster, e.ConditionType, e.Time.UTC().Format(time.RFC3339))))
+	return hex.EncodeToString(sum[:16])
+}
+
+// failureConditionTypes are the placement conditions whose false status means the placement fails. The false
+// RolloutStarted condition is excluded, as it only means the rollout is blocked by the rollout strategy.
+var failureConditionTypes = []placementv1beta1.ClusterResourcePlacementConditionType{
+	placementv1beta1.ClusterResourcePlacementScheduledConditionType,
+	placementv1beta1.ClusterResourcePlacementOverriddenConditionType,
+	placementv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType,
+	placementv1beta1.ClusterResourcePlacementAppliedConditionType,
+	placementv1beta1.ClusterResourcePlacementAvailableConditionType,
+}
+
+// placementEvents returns the lifecycle transitions between the old and the new status of a placement.
+func placementEvents(oldCRP, newCRP *placementv1beta1.ClusterResourcePlacement) []Event {
+	var events []Event
+	newEvent := func(eventType, cluster string, cond *metav1.Condition) Event {
+		return Event{
+			Type:          eventType,
+			Placement:     newCRP.Name,
+			PlacementUID:  string(newCRP.UID),
+			Generation:    cond.ObservedGeneration,
+			Cluster:       cluster,
+			ConditionType: cond.Type,
+			Reason:        cond.Reason,
+			Message:       cond.Message,
+			Time:          cond.LastTransitionTime.Time,
+		}
+	}
+
+	for _, transition := range []struct {
+		conditionType placementv1beta1.ClusterResourcePlacementConditionType
+		eventType     string
+	}{
+		{placementv1beta1.ClusterResourcePlacementScheduledConditionType, EventTypeScheduled},
+		{placementv1beta1.ClusterResourcePlacementAppliedConditionType, EventTypeApplied},
+		{placementv1beta1.ClusterResourcePlacementAvailableConditionType, EventTypeAvailable},
+	} {
+		oldCond := meta.FindStatusCondition(oldCRP.Status.Conditions, string(transition.conditionType))
+		newCond := meta.FindStatusCondition(newCRP.Status.Conditions, string(transition.conditionType))
+		if isTransitionedTo(oldCond, newCond, metav1.ConditionTrue, newCRP.Generation) {
+			events = append(events, newEvent(transition.eventType, "", newCond))
+		}
+	}
+	for _, conditionType := range failureConditionTypes {
+		oldCond := meta.FindStatusCondition(oldCRP.Status.Conditions, string(conditionType))
+		newCond := meta.FindStatusCondition(newCRP.Status.Conditions, string(conditionType))
+		if isTransitionedTo(oldCond, newCond, metav1.ConditionFalse, newCRP.Generation) {
+			events = append(events, newEvent(EventTypeFailed, "", newCond))
+		}
+	}
+
+	// The placement statuses of the healthy clusters are not kept in the placement when its status is compacted, so
+	// the per-cluster transitions cannot be told from the placement statuses.
+	if oldCRP.Status.PlacementStatusSummary != nil || newCRP.Status.PlacementStatusSummary != nil {
+		return events
+	}
+	oldStatuses := make(map[string]*placementv1beta1.ResourcePlacementStatus, len(oldCRP.Status.PlacementStatuses))
+	for i := range oldCRP.Status.PlacementStatuses {
+		if cluster := oldCRP.Status.PlacementStatuses[i].ClusterName; cluster != "" {
+			oldStatuses[cluster] = &oldCRP.Status.PlacementStatuses[i]
+		}
+	}
+	newClusters := make(map[string]bool, len(newCRP.Status.PlacementStatuses))
+	for i := range newCRP.Status.PlacementStatuses {
+		status := &newCRP.Status.PlacementStatuses[i]
+		if status.ClusterName == "" {
+			continue
+		}
+		newClusters[status.ClusterName] = true
+		var oldCond *metav1.Condition
+		if oldStatus, ok := oldStatuses[status.ClusterName]; ok {
+			oldCond = meta.FindStatusCondition(oldStatus.Conditions, string(placementv1beta1.ResourceRolloutStartedConditionType))
+		}
+		newCond := meta.FindStatusCondition(status.Conditions, string(placementv1beta1.ResourceRolloutStartedConditionType))
+		if isTransitionedTo(oldCond, newCond, metav1.ConditionTrue, newCRP.Generation) {
+			events = append(events, newEvent(EventTypeRolloutStageAdvanced, status.ClusterName, newCond))
+		}
+	}
+	for i := range oldCRP.Status.PlacementStatuses {
+		cluster := oldCRP.Status.PlacementStatuses[i].ClusterName
+		if cluster == "" || newClusters[cluster] {
+			continue
+		}
+		events = append(events, Event{
+			Type:         EventTypeEvicted,
+			Placement:    newCRP.Name,
+			PlacementUID: string(newCRP.UID),
+			Generation:   newCRP.Generation,
+			Cluster:      cluster,
+			Time:         time.Now(),
+		})
+	}
+	return events
+}
+
+// isTransitionedTo returns true if the condition newly has the status for the generation.
+func isTransitionedTo(oldCond, newCond *metav1.Condition, status metav1.ConditionStatus, generation int64) bool {
+	if newCond == nil || newCond.Status != status || newCond.ObservedGeneration != generation {
+		return false
+	}
+	return oldCond == nil || oldCond.Status != status || oldCond.ObservedGeneration != newCond.ObservedGeneration
+}
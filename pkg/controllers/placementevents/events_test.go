@@ -0,0 +1,149 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementevents
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testCRPName = "app"
+	testUID     = "crp-uid"
+)
+
+var transitionTime = metav1.NewTime(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+
+func condition(conditionType string, status metav1.ConditionStatus, generation int64) metav1.Condition {
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             "TestReason",
+		LastTransitionTime: transitionTime,
+	}
+}
+
+func placement(generation int64, conditions []metav1.Condition, statuses ...placementv1beta1.ResourcePlacementStatus) *placementv1beta1.ClusterResourcePlacement {
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: testCRPName, UID: testUID, Generation: generation},
+		Status: placementv1beta1.ClusterResourcePlacementStatus{
+			Conditions:        conditions,
+			PlacementStatuses: statuses,
+		},
+	}
+}
+
+func clusterStatus(cluster string, conditions ...metav1.Condition) placementv1beta1.ResourcePlacementStatus {
+	return placementv1beta1.ResourcePlacementStatus{ClusterName: cluster, Conditions: conditions}
+}
+
+func wantEvent(eventType, cluster, conditionType string, generation int64) Event {
+	return Event{
+		Type:          eventType,
+		Placement:     testCRPName,
+		PlacementUID:  testUID,
+		Generation:    generation,
+		Cluster:       cluster,
+		ConditionType: conditionType,
+		Reason:        "TestReason",
+		Time:          transitionTime.Time,
+	}
+}
+
+func TestPlacementEvents(t *testing.T) {
+	scheduled := string(placementv1beta1.ClusterResourcePlacementScheduledConditionType)
+	applied := string(placementv1beta1.ClusterResourcePlacementAppliedConditionType)
+	available := string(placementv1beta1.ClusterResourcePlacementAvailableConditionType)
+	rolloutStarted := string(placementv1beta1.ResourceRolloutStartedConditionType)
+	tests := map[string]struct {
+		oldCRP *placementv1beta1.ClusterResourcePlacement
+		newCRP *placementv1beta1.ClusterResourcePlacement
+		want   []Event
+	}{
+		"scheduled": {
+			oldCRP: placement(1, nil),
+			newCRP: placement(1, []metav1.Condition{condition(scheduled, metav1.ConditionTrue, 1)}),
+			want:   []Event{wantEvent(EventTypeScheduled, "", scheduled, 1)},
+		},
+		"scheduled for a new generation": {
+			oldCRP: placement(2, []metav1.Condition{condition(scheduled, metav1.ConditionTrue, 1)}),
+			newCRP: placement(2, []metav1.Condition{condition(scheduled, metav1.ConditionTrue, 2)}),
+			want:   []Event{wantEvent(EventTypeScheduled, "", scheduled, 2)},
+		},
+		"unchanged conditions": {
+			oldCRP: placement(1, []metav1.Condition{condition(scheduled, metav1.ConditionTrue, 1)}),
+			newCRP: placement(1, []metav1.Condition{condition(scheduled, metav1.ConditionTrue, 1)}),
+		},
+		"stale condition": {
+			oldCRP: placement(2, nil),
+			newCRP: placement(2, []metav1.Condition{condition(scheduled, metav1.ConditionTrue, 1)}),
+		},
+		"applied and available": {
+			oldCRP: placement(1, []metav1.Condition{condition(applied, metav1.ConditionUnknown, 1)}),
+			newCRP: placement(1, []metav1.Condition{condition(applied, metav1.ConditionTrue, 1), condition(available, metav1.ConditionTrue, 1)}),
+			want: []Event{
+				wantEvent(EventTypeApplied, "", applied, 1),
+				wantEvent(EventTypeAvailable, "", available, 1),
+			},
+		},
+		"failed to apply": {
+			oldCRP: placement(1, []metav1.Condition{condition(applied, metav1.ConditionUnknown, 1)}),
+			newCRP: placement(1, []metav1.Condition{condition(applied, metav1.ConditionFalse, 1)}),
+			want:   []Event{wantEvent(EventTypeFailed, "", applied, 1)},
+		},
+		"rollout reaches a cluster": {
+			oldCRP: placement(1, nil, clusterStatus("member-1", condition(rolloutStarted, metav1.ConditionTrue, 1)), clusterStatus("member-2")),
+			newCRP: placement(1, nil, clusterStatus("member-1", condition(rolloutStarted, metav1.ConditionTrue, 1)), clusterStatus("member-2", condition(rolloutStarted, metav1.ConditionTrue, 1))),
+			want:   []Event{wantEvent(EventTypeRolloutStageAdvanced, "member-2", rolloutStarted, 1)},
+		},
+		"cluster evicted": {
+			oldCRP: placement(1, nil, clusterStatus("member-1"), clusterStatus("member-2")),
+			newCRP: placement(1, nil, clusterStatus("member-1")),
+			want:   []Event{{Type: EventTypeEvicted, Placement: testCRPName, PlacementUID: testUID, Generation: 1, Cluster: "member-2"}},
+		},
+		"compacted status": {
+			oldCRP: placement(1, []metav1.Condition{condition(applied, metav1.ConditionUnknown, 1)}, clusterStatus("member-1"), clusterStatus("member-2")),
+			newCRP: func() *placementv1beta1.ClusterResourcePlacement {
+				crp := placement(1, []metav1.Condition{condition(applied, metav1.ConditionTrue, 1)}, clusterStatus("member-1"))
+				crp.Status.PlacementStatusSummary = &placementv1beta1.PlacementStatusSummary{SelectedClusters: 2, UnhealthyClusters: 1}
+				return crp
+			}(),
+			// the healthy member-2 is not evicted but compacted
+			want: []Event{wantEvent(EventTypeApplied, "", applied, 1)},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := placementEvents(tc.oldCRP, tc.newCRP)
+			// the eviction time is when the eviction is observed
+			ignoreEvictionTime := cmp.FilterPath(func(p cmp.Path) bool {
+				return p.Last().String() == ".Time"
+			}, cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) || x.IsZero() || y.IsZero() }))
+			if diff := cmp.Diff(tc.want, got, ignoreEvictionTime, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("placementEvents() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEventID(t *testing.T) {
+	event := wantEvent(EventTypeApplied, "", "Applied", 1)
+	if event.ID() != event.ID() {
+		t.Errorf("ID() is not deterministic")
+	}
+	other := event
+	other.Generation = 2
+	if event.ID() == other.ID() {
+		t.Errorf("ID() = %s for different events, want different ids", event.ID())
+	}
+}
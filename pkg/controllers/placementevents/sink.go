@@ -0,0 +1,115 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+)
+
+// ErrPermanentSendFailure indicates that the sink rejects the event, which is not retried.
+var ErrPermanentSendFailure = errors.New("the sink rejected the event")
+
+// Sink receives the placement events.
+type Sink interface {
+	// Send delivers the event to the sink.
+	Send(ctx context.Context, event Event) error
+}
+
+// cloudEvent is a CloudEvent in the structured content mode.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            eventData `json:"data"`
+}
+
+// eventData is the data of a placement event.
+type eventData struct {
+	Placement     string `json:"placement"`
+	Generation    int64  `json:"generation"`
+	Cluster       string `json:"cluster,omitempty"`
+	ConditionType string `json:"conditionType,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// HTTPSink posts the events as CloudEvents in the structured content mode to an HTTP endpoint, e.g. a Knative
+// broker or an Argo Events webhook.
+type HTTPSink struct {
+	// URL is the endpoint of the sink.
+	URL string
+	// Client is the client to connect to the sink. Default: http.DefaultClient.
+	Client *http.Client
+}
+
+// Send posts the event to the sink. The client errors, except for the throttling, are permanent.
+func (s *HTTPSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(toCloudEvent(event))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", cloudEventsContentType)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", ErrPermanentSendFailure, resp.Status)
+	default:
+		return fmt.Errorf("the sink returned %s", resp.Status)
+	}
+}
+
+func toCloudEvent(event Event) cloudEvent {
+	return cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              event.ID(),
+		Source:          fmt.Sprintf("/apis/%s/clusterresourceplacements/%s", placementv1beta1.GroupVersion.String(), event.Placement),
+		Type:            event.Type,
+		Subject:         event.Placement,
+		Time:            event.Time.UTC(),
+		DataContentType: "application/json",
+		Data: eventData{
+			Placement:     event.Placement,
+			Generation:    event.Generation,
+			Cluster:       event.Cluster,
+			ConditionType: event.ConditionType,
+			Reason:        event.Reason,
+			Message:       event.Message,
+		},
+	}
+}
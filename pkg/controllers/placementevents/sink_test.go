@@ -0,0 +1,128 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeSink struct {
+	err  error
+	sent []Event
+}
+
+func (s *fakeSink) Send(_ context.Context, event Event) error {
+	s.sent = append(s.sent, event)
+	return s.err
+}
+
+func TestHTTPSinkSend(t *testing.T) {
+	event := wantEvent(EventTypeApplied, "", "ClusterResourcePlacementApplied", 1)
+	tests := map[string]struct {
+		status          int
+		wantErr         bool
+		wantPermanent   bool
+		wantCloudEvents bool
+	}{
+		"accepted": {
+			status:          http.StatusAccepted,
+			wantCloudEvents: true,
+		},
+		"rejected": {
+			status:        http.StatusBadRequest,
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		"throttled": {
+			status:  http.StatusTooManyRequests,
+			wantErr: true,
+		},
+		"unavailable": {
+			status:  http.StatusServiceUnavailable,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]interface{}
+			var contentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				contentType = req.Header.Get("Content-Type")
+				_ = json.NewDecoder(req.Body).Decode(&got)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			err := (&HTTPSink{URL: server.URL}).Send(context.Background(), event)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Send() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if errors.Is(err, ErrPermanentSendFailure) != tc.wantPermanent {
+				t.Errorf("Send() = %v, want permanent failure %v", err, tc.wantPermanent)
+			}
+			if !tc.wantCloudEvents {
+				return
+			}
+			if contentType != cloudEventsContentType {
+				t.Errorf("content type = %s, want %s", contentType, cloudEventsContentType)
+			}
+			want := map[string]interface{}{
+				"specversion":     "1.0",
+				"id":              event.ID(),
+				"source":          "/apis/placement.kubernetes-fleet.io/v1beta1/clusterresourceplacements/" + testCRPName,
+				"type":            EventTypeApplied,
+				"subject":         testCRPName,
+				"time":            "2024-06-01T00:00:00Z",
+				"datacontenttype": "application/json",
+				"data": map[string]interface{}{
+					"placement":     testCRPName,
+					"generation":    float64(1),
+					"conditionType": "ClusterResourcePlacementApplied",
+					"reason":        "TestReason",
+				},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("CloudEvent mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEmitterSend(t *testing.T) {
+	event := wantEvent(EventTypeFailed, "", "ClusterResourcePlacementApplied", 1)
+	tests := map[string]struct {
+		sinkErr error
+		wantErr bool
+	}{
+		"sent": {},
+		"retried on a transient failure": {
+			sinkErr: errors.New("connection refused"),
+			wantErr: true,
+		},
+		"dropped on a permanent failure": {
+			sinkErr: ErrPermanentSendFailure,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sink := &fakeSink{err: tc.sinkErr}
+			e := &Emitter{sink: sink}
+			if _, err := e.send(context.Background(), event); (err != nil) != tc.wantErr {
+				t.Errorf("send() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff([]Event{event}, sink.sent); diff != "" {
+				t.Errorf("sent events mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementpromotion features a controller that promotes the cluster resource placements, i.e. clones them
+// into new placements with other placement policies, and traces the new placements back to the resource snapshots
+// they are promoted from.
+package placementpromotion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// the reasons of the Promoted condition.
+	promotedReason         = "Promoted"
+	sourceNotFoundReason   = "SourceNotFound"
+	sourceNotReadyReason   = "SourceNotReady"
+	targetConflictReason   = "TargetConflict"
+	targetDeletedReason    = "TargetDeleted"
+	invalidTargetReason    = "InvalidTarget"
+	targetPendingReason    = "TargetPending"
+	resourcesChangedReason = "ResourcesChanged"
+)
+
+// Reconciler reconciles a cluster resource placement promotion. It creates the target placement from the source
+// placement once, and reports whether the target placement has snapshot the resources it is promoted with.
+type Reconciler struct {
+	Client client.Client
+}
+
+// Reconcile creates the target placement of the promotion if it does not exist yet, and updates the status of the
+// promotion with the latest resource snapshot of the target placement.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("ClusterResourcePlacementPromotion reconciliation starts", "promotion", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ClusterResourcePlacementPromotion reconciliation ends", "promotion", req.Name, "latency", latency)
+	}()
+
+	var promotion placementv1beta1.ClusterResourcePlacementPromotion
+	if err := r.Client.Get(ctx, req.NamespacedName, &promotion); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the promotion", "promotion", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !promotion.DeletionTimestamp.IsZero() {
+		// the target placement is left as it is, as it is not owned by the promotion
+		return ctrl.Result{}, nil
+	}
+
+	var target placementv1beta1.ClusterResourcePlacement
+	err := r.Client.Get(ctx, types.NamespacedName{Name: promotion.Spec.TargetPlacementName}, &target)
+	switch {
+	case err == nil && target.Annotations[placementv1beta1.PromotionAnnotation] != promotion.Name:
+		klog.V(2).InfoS("The target placement is not created by the promotion", "promotion", req.Name, "clusterResourcePlacement", target.Name)
+		return ctrl.Result{}, r.updateNotPromoted(ctx, &promotion, targetConflictReason,
+			fmt.Sprintf("clusterResourcePlacement %s already exists and is not created by the promotion", target.Name))
+	case err == nil:
+		// the target placement is created once; it is not updated when the source placement or the promotion changes
+	case !apierrors.IsNotFound(err):
+		klog.ErrorS(err, "Failed to get the target placement", "promotion", req.Name, "clusterResourcePlacement", promotion.Spec.TargetPlacementName)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	case promotion.Status.SourceResourceSnapshotName != "":
+		// the target placement is not created again once it is deleted after the promotion
+		return ctrl.Result{}, r.updateNotPromoted(ctx, &promotion, targetDeletedReason,
+			fmt.Sprintf("clusterResourcePlacement %s is deleted after the promotion", promotion.Spec.TargetPlacementName))
+	default:
+		created, reason, message, err := r.createTarget(ctx, &promotion)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if created == nil {
+			return ctrl.Result{}, r.updateNotPromoted(ctx, &promotion, reason, message)
+		}
+		target = *created
+	}
+
+	sourceSnapshotName := target.Annotations[placementv1beta1.PromotedFromResourceSnapshotAnnotation]
+	if promotion.Status.SourceResourceSnapshotName != sourceSnapshotName || promotion.Status.SourceResourceHash == "" {
+		promotion.Status.SourceResourceSnapshotName = sourceSnapshotName
+		promotion.Status.SourceResourceHash = ""
+		var sourceSnapshot placementv1beta1.ClusterResourceSnapshot
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: sourceSnapshotName}, &sourceSnapshot); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the source resource snapshot", "promotion", req.Name, "clusterResourceSnapshot", sourceSnapshotName)
+			return ctrl.Result{}, controller.NewAPIServerError(true, err)
+		}
+		// the snapshot may have been deleted by the revision history limit of the source placement
+		promotion.Status.SourceResourceHash = sourceSnapshot.Annotations[placementv1beta1.ResourceGroupHashAnnotation]
+	}
+
+	targetSnapshot, err := r.fetchLatestResourceSnapshot(ctx, target.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	condition := metav1.Condition{
+		Type:               string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: promotion.Generation,
+	}
+	promotion.Status.TargetResourceSnapshotName = ""
+	switch {
+	case targetSnapshot == nil:
+		condition.Reason = targetPendingReason
+		condition.Message = fmt.Sprintf("clusterResourcePlacement %s has not snapshot its resources yet", target.Name)
+	case promotion.Status.SourceResourceHash != "" &&
+		targetSnapshot.Annotations[placementv1beta1.ResourceGroupHashAnnotation] != promotion.Status.SourceResourceHash:
+		promotion.Status.TargetResourceSnapshotName = targetSnapshot.Name
+		condition.Reason = resourcesChangedReason
+		condition.Message = fmt.Sprintf("The resources of clusterResourceSnapshot %s of clusterResourcePlacement %s differ from clusterResourceSnapshot %s that it is promoted from, as the selected resources have changed since the promotion",
+			targetSnapshot.Name, target.Name, sourceSnapshotName)
+	default:
+		promotion.Status.TargetResourceSnapshotName = targetSnapshot.Name
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = promotedReason
+		condition.Message = fmt.Sprintf("clusterResourcePlacement %s is promoted to clusterResourcePlacement %s with clusterResourceSnapshot %s",
+			promotion.Spec.SourcePlacementName, target.Name, sourceSnapshotName)
+	}
+	promotion.SetConditions(condition)
+	if err := r.Client.Status().Update(ctx, &promotion); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the promotion", "promotion", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// createTarget creates the target placement from the source placement and its latest resource snapshot. It returns
+// nil and the reason and the message of the Promoted condition if the target placement cannot be created for now.
+func (r *Reconciler) createTarget(ctx context.Context, promotion *placementv1beta1.ClusterResourcePlacementPromotion) (*placementv1beta1.ClusterResourcePlacement, string, string, error) {
+	var source placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: promotion.Spec.SourcePlacementName}, &source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, sourceNotFoundReason, fmt.Sprintf("clusterResourcePlacement %s is not found", promotion.Spec.SourcePlacementName), nil
+		}
+		klog.ErrorS(err, "Failed to get the source placement", "promotion", klog.KObj(promotion), "clusterResourcePlacement", promotion.Spec.SourcePlacementName)
+		return nil, "", "", controller.NewAPIServerError(true, err)
+	}
+	if !source.DeletionTimestamp.IsZero() {
+		return nil, sourceNotFoundReason, fmt.Sprintf("clusterResourcePlacement %s is being deleted", source.Name), nil
+	}
+	sourceSnapshot, err := r.fetchLatestResourceSnapshot(ctx, source.Name)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if sourceSnapshot == nil {
+		return nil, sourceNotReadyReason, fmt.Sprintf("clusterResourcePlacement %s has not snapshot its resources yet", source.Name), nil
+	}
+
+	target := buildTarget(promotion, &source, sourceSnapshot)
+	if err := r.Client.Create(ctx, target); err != nil {
+		if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+			// the webhook rejects the target placement, e.g. as the policy of the promotion is invalid
+			klog.V(2).InfoS("The target placement is rejected", "promotion", klog.KObj(promotion), "clusterResourcePlacement", target.Name, "error", err)
+			return nil, invalidTargetReason, err.Error(), nil
+		}
+		klog.ErrorS(err, "Failed to create the target placement", "promotion", klog.KObj(promotion), "clusterResourcePlacement", target.Name)
+		return nil, "", "", controller.NewAPIServerError(false, err)
+	}
+	klog.V(2).InfoS("Created the target placement", "promotion", klog.KObj(promotion), "source", source.Name, "target", target.Name, "clusterResourceSnapshot", sourceSnapshot.Name)
+	return target, "", "", nil
+}
+
+// buildTarget builds the target placement with the spec of the source placement, the policy of the promotion, and
+// the annotations which trace it back to the source placement and its resource snapshot.
+func buildTarget(promotion *placementv1beta1.ClusterResourcePlacementPromotion, source *placementv1beta1.ClusterResourcePlacement,
+	sourceSnapshot *placementv1beta1.ClusterResourceSnapshot) *placementv1beta1.ClusterResourcePlacement {
+	target := &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: promotion.Spec.TargetPlacementName,
+			Annotations: map[string]string{
+				placementv1beta1.PromotedFromAnnotation:                 source.Name,
+				placementv1beta1.PromotedFromResourceSnapshotAnnotation: sourceSnapshot.Name,
+				placementv1beta1.PromotionAnnotation:                    promotion.Name,
+			},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	if promotion.Spec.Policy != nil {
+		target.Spec.Policy = promotion.Spec.Policy.DeepCopy()
+	}
+	return target
+}
+
+// fetchLatestResourceSnapshot returns the master resource snapshot of the latest resource snapshot group of the
+// placement, or nil if there is none.
+func (r *Reconciler) fetchLatestResourceSnapshot(ctx context.Context, crpName string) (*placementv1beta1.ClusterResourceSnapshot, error) {
+	var snapshotList placementv1beta1.ClusterResourceSnapshotList
+	if err := r.Client.List(ctx, &snapshotList, client.MatchingLabels{
+		placementv1beta1.CRPTrackingLabel:      crpName,
+		placementv1beta1.IsLatestSnapshotLabel: "true",
+	}); err != nil {
+		klog.ErrorS(err, "Failed to list the latest resource snapshots of the placement", "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	for i := range snapshotList.Items {
+		// only the master snapshot has this annotation
+		if len(snapshotList.Items[i].Annotations[placementv1beta1.ResourceGroupHashAnnotation]) != 0 {
+			return &snapshotList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// updateNotPromoted records why the source placement is not promoted in the Promoted condition.
+func (r *Reconciler) updateNotPromoted(ctx context.Context, promotion *placementv1beta1.ClusterResourcePlacementPromotion, reason, message string) error {
+	promotion.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted),
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: promotion.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, promotion); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the promotion", "promotion", klog.KObj(promotion))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return nil
+}
+
+// promotionsOfSnapshot maps a resource snapshot to the promotions whose source or target placement it belongs to, as
+// the target placement is created once the source placement snapshots its resources, and the promotion completes once
+// the target placement does.
+func (r *Reconciler) promotionsOfSnapshot(ctx context.Context, obj client.Object) []reconcile.Request {
+	crpName := obj.GetLabels()[placementv1beta1.CRPTrackingLabel]
+	if crpName == "" {
+		return nil
+	}
+	var promotionList placementv1beta1.ClusterResourcePlacementPromotionList
+	if err := r.Client.List(ctx, &promotionList); err != nil {
+		klog.ErrorS(err, "Failed to list the promotions", "clusterResourceSnapshot", klog.KObj(obj))
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range promotionList.Items {
+		spec := &promotionList.Items[i].Spec
+		if spec.SourcePlacementName == crpName || spec.TargetPlacementName == crpName {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: promotionList.Items[i].Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("placement-promotion-controller").
+		For(&placementv1beta1.ClusterResourcePlacementPromotion{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the snapshots are created when the placements are created and deleted when the placements are deleted
+		Watches(&placementv1beta1.ClusterResourceSnapshot{}, handler.EnqueueRequestsFromMapFunc(r.promotionsOfSnapshot)).
+		Complete(r)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementpromotion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testPromotionName = "web-to-prod"
+	testSourceName    = "web-staging"
+	testTargetName    = "web-prod"
+)
+
+func newTestPromotion(sourceSnapshotName string) *placementv1beta1.ClusterResourcePlacementPromotion {
+	return &placementv1beta1.ClusterResourcePlacementPromotion{
+		ObjectMeta: metav1.ObjectMeta{Name: testPromotionName, Generation: 1},
+		Spec: placementv1beta1.ClusterResourcePlacementPromotionSpec{
+			SourcePlacementName: testSourceName,
+			TargetPlacementName: testTargetName,
+			Policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickNPlacementType,
+				NumberOfClusters: ptr.To(int32(10)),
+			},
+		},
+		Status: placementv1beta1.ClusterResourcePlacementPromotionStatus{SourceResourceSnapshotName: sourceSnapshotName},
+	}
+}
+
+func newTestCRP(name string, annotations map[string]string) *placementv1beta1.ClusterResourcePlacement {
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "", Version: "v1", Kind: "Namespace", Name: "web"}},
+			Policy: &placementv1beta1.PlacementPolicy{
+				PlacementType: placementv1beta1.PickAllPlacementType,
+				Affinity: &placementv1beta1.Affinity{ClusterAffinity: &placementv1beta1.ClusterAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &placementv1beta1.ClusterSelector{
+						ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}}}},
+					},
+				}},
+			},
+			RevisionHistoryLimit: ptr.To(int32(5)),
+		},
+	}
+}
+
+func newTestSnapshot(name, crpName, hash string) *placementv1beta1.ClusterResourceSnapshot {
+	return &placementv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				placementv1beta1.CRPTrackingLabel:      crpName,
+				placementv1beta1.IsLatestSnapshotLabel: "true",
+			},
+			Annotations: map[string]string{placementv1beta1.ResourceGroupHashAnnotation: hash},
+		},
+	}
+}
+
+func promotedAnnotations() map[string]string {
+	return map[string]string{
+		placementv1beta1.PromotedFromAnnotation:                 testSourceName,
+		placementv1beta1.PromotedFromResourceSnapshotAnnotation: "web-staging-3-snapshot",
+		placementv1beta1.PromotionAnnotation:                    testPromotionName,
+	}
+}
+
+func newTestReconciler(t *testing.T, objects ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	if err := placementv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement APIs to the scheme: %v", err)
+	}
+	return &Reconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithStatusSubresource(&placementv1beta1.ClusterResourcePlacementPromotion{}).Build(),
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	sourceSnapshot := newTestSnapshot("web-staging-3-snapshot", testSourceName, "hash-1")
+	tests := map[string]struct {
+		promotion      *placementv1beta1.ClusterResourcePlacementPromotion
+		objects        []client.Object
+		wantTarget     *placementv1beta1.ClusterResourcePlacement
+		wantStatus     placementv1beta1.ClusterResourcePlacementPromotionStatus
+		wantConditions []metav1.Condition
+	}{
+		"create the target": {
+			promotion: newTestPromotion(""),
+			objects:   []client.Object{newTestCRP(testSourceName, nil), sourceSnapshot},
+			wantTarget: func() *placementv1beta1.ClusterResourcePlacement {
+				crp := newTestCRP(testTargetName, promotedAnnotations())
+				crp.Spec.Policy = newTestPromotion("").Spec.Policy
+				return crp
+			}(),
+			wantStatus: placementv1beta1.ClusterResourcePlacementPromotionStatus{
+				SourceResourceSnapshotName: "web-staging-3-snapshot",
+				SourceResourceHash:         "hash-1",
+			},
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: targetPendingReason}},
+		},
+		"keep the policy of the source": {
+			promotion: func() *placementv1beta1.ClusterResourcePlacementPromotion {
+				promotion := newTestPromotion("")
+				promotion.Spec.Policy = nil
+				return promotion
+			}(),
+			objects:    []client.Object{newTestCRP(testSourceName, nil), sourceSnapshot},
+			wantTarget: newTestCRP(testTargetName, promotedAnnotations()),
+			wantStatus: placementv1beta1.ClusterResourcePlacementPromotionStatus{
+				SourceResourceSnapshotName: "web-staging-3-snapshot",
+				SourceResourceHash:         "hash-1",
+			},
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: targetPendingReason}},
+		},
+		"target snapshots the same resources": {
+			promotion: newTestPromotion(""),
+			objects: []client.Object{
+				newTestCRP(testSourceName, nil), sourceSnapshot,
+				newTestCRP(testTargetName, promotedAnnotations()), newTestSnapshot("web-prod-0-snapshot", testTargetName, "hash-1"),
+			},
+			wantTarget: newTestCRP(testTargetName, promotedAnnotations()),
+			wantStatus: placementv1beta1.ClusterResourcePlacementPromotionStatus{
+				SourceResourceSnapshotName: "web-staging-3-snapshot",
+				SourceResourceHash:         "hash-1",
+				TargetResourceSnapshotName: "web-prod-0-snapshot",
+			},
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionTrue, Reason: promotedReason}},
+		},
+		"resources changed since the promotion": {
+			promotion: newTestPromotion("web-staging-3-snapshot"),
+			objects: []client.Object{
+				newTestCRP(testSourceName, nil), sourceSnapshot,
+				newTestCRP(testTargetName, promotedAnnotations()), newTestSnapshot("web-prod-1-snapshot", testTargetName, "hash-2"),
+			},
+			wantTarget: newTestCRP(testTargetName, promotedAnnotations()),
+			wantStatus: placementv1beta1.ClusterResourcePlacementPromotionStatus{
+				SourceResourceSnapshotName: "web-staging-3-snapshot",
+				SourceResourceHash:         "hash-1",
+				TargetResourceSnapshotName: "web-prod-1-snapshot",
+			},
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: resourcesChangedReason}},
+		},
+		"target is not created by the promotion": {
+			promotion:      newTestPromotion(""),
+			objects:        []client.Object{newTestCRP(testSourceName, nil), sourceSnapshot, newTestCRP(testTargetName, nil)},
+			wantTarget:     newTestCRP(testTargetName, nil),
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: targetConflictReason}},
+		},
+		"source is not found": {
+			promotion:      newTestPromotion(""),
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: sourceNotFoundReason}},
+		},
+		"source has no resource snapshot": {
+			promotion:      newTestPromotion(""),
+			objects:        []client.Object{newTestCRP(testSourceName, nil)},
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: sourceNotReadyReason}},
+		},
+		"target is deleted after the promotion": {
+			promotion:      newTestPromotion("web-staging-3-snapshot"),
+			objects:        []client.Object{newTestCRP(testSourceName, nil), sourceSnapshot},
+			wantStatus:     placementv1beta1.ClusterResourcePlacementPromotionStatus{SourceResourceSnapshotName: "web-staging-3-snapshot"},
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.ClusterResourcePlacementPromotionConditionTypePromoted), Status: metav1.ConditionFalse, Reason: targetDeletedReason}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestReconciler(t, append(tt.objects, tt.promotion)...)
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testPromotionName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want no error", err)
+			}
+
+			var target placementv1beta1.ClusterResourcePlacement
+			err := r.Client.Get(ctx, types.NamespacedName{Name: testTargetName}, &target)
+			switch {
+			case tt.wantTarget == nil && !apierrors.IsNotFound(err):
+				t.Errorf("Get(target) = %v, want not found", err)
+			case tt.wantTarget != nil && err != nil:
+				t.Fatalf("Get(target) = %v, want no error", err)
+			case tt.wantTarget != nil:
+				if diff := cmp.Diff(tt.wantTarget, &target, cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion")); diff != "" {
+					t.Errorf("target mismatch (-want, +got):\n%s", diff)
+				}
+			}
+
+			var got placementv1beta1.ClusterResourcePlacementPromotion
+			if err := r.Client.Get(ctx, types.NamespacedName{Name: testPromotionName}, &got); err != nil {
+				t.Fatalf("Get(promotion) = %v, want no error", err)
+			}
+			tt.wantStatus.Conditions = tt.wantConditions
+			if diff := cmp.Diff(tt.wantStatus, got.Status, cmpopts.IgnoreFields(metav1.Condition{}, "Message", "LastTransitionTime", "ObservedGeneration")); diff != "" {
+				t.Errorf("status mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementscaler features a controller that scales the number of clusters of the cluster resource placements
+// with external metrics, e.g. the Prometheus queries, within the bounds of their placement scalers.
+package placementscaler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// the reasons of the Active condition.
+	scaledReason               = "Scaled"
+	scaleDownStabilizingReason = "ScaleDownStabilizing"
+	invalidScalerReason        = "InvalidScaler"
+	invalidPlacementReason     = "InvalidPlacement"
+	metricUnavailableReason    = "MetricUnavailable"
+
+	defaultInterval                     = 30 * time.Second
+	defaultScaleDownStabilizationWindow = 5 * time.Minute
+)
+
+// MetricsClient reads the value of an external metric.
+type MetricsClient interface {
+	// Query returns the current value of the Prometheus metric.
+	Query(ctx context.Context, metric *placementv1beta1.PrometheusMetric, credentials map[string][]byte) (float64, error)
+}
+
+// Reconciler reconciles a placement scaler. It reads the metric of the scaler periodically and sets the number of
+// clusters of its placement accordingly.
+type Reconciler struct {
+	Client client.Client
+	// Metrics reads the metrics of the scalers.
+	Metrics MetricsClient
+
+	now func() time.Time
+}
+
+// Reconcile scales the placement of the placement scaler with the latest value of its metric.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("PlacementScaler reconciliation starts", "placementScaler", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("PlacementScaler reconciliation ends", "placementScaler", req.Name, "latency", latency)
+	}()
+
+	var scaler placementv1beta1.PlacementScaler
+	if err := r.Client.Get(ctx, req.NamespacedName, &scaler); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the placement scaler", "placementScaler", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !scaler.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	interval := defaultInterval
+	if scaler.Spec.Interval != nil && scaler.Spec.Interval.Duration > 0 {
+		interval = scaler.Spec.Interval.Duration
+	}
+
+	minClusters, err := validateScaler(&scaler.Spec)
+	if err != nil {
+		klog.ErrorS(err, "Invalid placement scaler", "placementScaler", req.Name)
+		// the scaler is reconciled again when its spec is fixed
+		return ctrl.Result{}, r.updateInactive(ctx, &scaler, invalidScalerReason, err)
+	}
+
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: scaler.Spec.PlacementName}, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(2).InfoS("The placement of the placement scaler is not found", "placementScaler", req.Name, "clusterResourcePlacement", scaler.Spec.PlacementName)
+			return ctrl.Result{RequeueAfter: interval}, r.updateInactive(ctx, &scaler, invalidPlacementReason,
+				fmt.Errorf("clusterResourcePlacement %s is not found", scaler.Spec.PlacementName))
+		}
+		klog.ErrorS(err, "Failed to get the placement of the placement scaler", "placementScaler", req.Name, "clusterResourcePlacement", scaler.Spec.PlacementName)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if crp.Spec.Policy == nil || crp.Spec.Policy.PlacementType != placementv1beta1.PickNPlacementType {
+		err := fmt.Errorf("the placement type of clusterResourcePlacement %s is not %s", crp.Name, placementv1beta1.PickNPlacementType)
+		klog.V(2).InfoS("The placement of the placement scaler cannot be scaled", "placementScaler", req.Name, "clusterResourcePlacement", crp.Name)
+		return ctrl.Result{RequeueAfter: interval}, r.updateInactive(ctx, &scaler, invalidPlacementReason, err)
+	}
+
+	credentials, err := r.getCredentials(ctx, scaler.Spec.Metric.Prometheus.SecretRef)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get the credentials of the metric", "placementScaler", req.Name)
+		return ctrl.Result{}, r.updateInactive(ctx, &scaler, metricUnavailableReason, err)
+	}
+	value, err := r.Metrics.Query(ctx, scaler.Spec.Metric.Prometheus, credentials)
+	if err != nil {
+		klog.ErrorS(err, "Failed to read the metric of the placement scaler", "placementScaler", req.Name)
+		return ctrl.Result{}, r.updateInactive(ctx, &scaler, metricUnavailableReason, err)
+	}
+
+	now := r.clock()
+	metricValue := toQuantity(value)
+	current := ptr.Deref(crp.Spec.Policy.NumberOfClusters, 0)
+	desired := desiredClusters(value, scaler.Spec.Metric.TargetValuePerCluster.AsApproximateFloat64(), minClusters, scaler.Spec.MaxClusters)
+	reason, message := scaledReason, fmt.Sprintf("Scaled clusterResourcePlacement %s to %d clusters with the metric value %s", crp.Name, desired, metricValue.String())
+	if desired < current {
+		window := defaultScaleDownStabilizationWindow
+		if scaler.Spec.ScaleDownStabilizationWindow != nil {
+			window = scaler.Spec.ScaleDownStabilizationWindow.Duration
+		}
+		if scaler.Status.LastScaleTime != nil && now.Sub(scaler.Status.LastScaleTime.Time) < window {
+			reason = scaleDownStabilizingReason
+			message = fmt.Sprintf("Keeping clusterResourcePlacement %s at %d clusters until %s before scaling down to %d clusters",
+				crp.Name, current, scaler.Status.LastScaleTime.Add(window).UTC().Format(time.RFC3339), desired)
+			desired = current
+		}
+	}
+	if desired != current {
+		crp.Spec.Policy.NumberOfClusters = ptr.To(desired)
+		if err := r.Client.Update(ctx, &crp); err != nil {
+			klog.ErrorS(err, "Failed to scale the placement", "placementScaler", req.Name, "clusterResourcePlacement", crp.Name, "numberOfClusters", desired)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+		klog.V(2).InfoS("Scaled the placement", "placementScaler", req.Name, "clusterResourcePlacement", crp.Name, "from", current, "to", desired, "metricValue", value)
+		scaler.Status.LastScaleTime = &metav1.Time{Time: now}
+	}
+
+	scaler.Status.MetricValue = metricValue
+	scaler.Status.DesiredClusters = ptr.To(desired)
+	scaler.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.PlacementScalerConditionTypeActive),
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: scaler.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, &scaler); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the placement scaler", "placementScaler", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// validateScaler validates the bounds and the metric of the scaler, and returns the minimum number of clusters.
+func validateScaler(spec *placementv1beta1.PlacementScalerSpec) (int32, error) {
+	minClusters := ptr.Deref(spec.MinClusters, 1)
+	if minClusters > spec.MaxClusters {
+		return 0, controller.NewUserError(fmt.Errorf("the minimum number of clusters %d is greater than the maximum %d", minClusters, spec.MaxClusters))
+	}
+	if spec.Metric.Prometheus == nil {
+		return 0, controller.NewUserError(errors.New("the metric has no source specified"))
+	}
+	if spec.Metric.TargetValuePerCluster.Sign() <= 0 {
+		return 0, controller.NewUserError(fmt.Errorf("the target value per cluster %s is not positive", spec.Metric.TargetValuePerCluster.String()))
+	}
+	return minClusters, nil
+}
+
+// desiredClusters returns the number of clusters which serve the metric value with the target value per cluster,
+// bounded by the minimum and maximum number of clusters.
+func desiredClusters(value, targetPerCluster float64, minClusters, maxClusters int32) int32 {
+	desired := math.Ceil(value / targetPerCluster)
+	switch {
+	case desired < float64(minClusters):
+		return minClusters
+	case desired > float64(maxClusters):
+		return maxClusters
+	}
+	return int32(desired)
+}
+
+// toQuantity converts the metric value to a quantity with up to three decimal places.
+func toQuantity(value float64) *resource.Quantity {
+	return resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI)
+}
+
+func (r *Reconciler) getCredentials(ctx context.Context, ref *placementv1beta1.NamespacedName) (map[string][]byte, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	return secret.Data, nil
+}
+
+// updateInactive records why the placement is not scaled in the Active condition, leaving the number of clusters of
+// the placement as it is.
+func (r *Reconciler) updateInactive(ctx context.Context, scaler *placementv1beta1.PlacementScaler, reason string, scaleErr error) error {
+	scaler.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.PlacementScalerConditionTypeActive),
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            scaleErr.Error(),
+		ObservedGeneration: scaler.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, scaler); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the placement scaler", "placementScaler", klog.KObj(scaler))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	if reason == invalidPlacementReason {
+		// the placement is checked again after the interval
+		return nil
+	}
+	return scaleErr
+}
+
+func (r *Reconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("placement-scaler-controller").
+		For(&placementv1beta1.PlacementScaler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
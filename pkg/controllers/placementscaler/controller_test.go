@@ -0,0 +1,287 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementscaler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	testcontroller "go.goms.io/fleet/test/utils/controller"
+)
+
+const (
+	testScalerName = "web-scaler"
+	testCRPName    = "web"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeMetrics returns the value of the metric.
+type fakeMetrics struct {
+	value       float64
+	err         error
+	credentials map[string][]byte
+}
+
+func (f *fakeMetrics) Query(_ context.Context, _ *placementv1beta1.PrometheusMetric, credentials map[string][]byte) (float64, error) {
+	f.credentials = credentials
+	return f.value, f.err
+}
+
+func newTestScaler() *placementv1beta1.PlacementScaler {
+	return &placementv1beta1.PlacementScaler{
+		ObjectMeta: metav1.ObjectMeta{Name: testScalerName, Generation: 1},
+		Spec: placementv1beta1.PlacementScalerSpec{
+			PlacementName: testCRPName,
+			MinClusters:   ptr.To(int32(2)),
+			MaxClusters:   5,
+			Metric: placementv1beta1.ExternalMetric{
+				Prometheus: &placementv1beta1.PrometheusMetric{
+					ServerAddress: "http://prometheus.monitoring:9090",
+					Query:         `sum(rate(http_requests_total{app="web"}[2m]))`,
+					SecretRef:     &placementv1beta1.NamespacedName{Namespace: "fleet-system", Name: "prometheus"},
+				},
+				TargetValuePerCluster: resource.MustParse("100"),
+			},
+			Interval:                     &metav1.Duration{Duration: time.Minute},
+			ScaleDownStabilizationWindow: &metav1.Duration{Duration: 5 * time.Minute},
+		},
+	}
+}
+
+func newTestCRP(placementType placementv1beta1.PlacementType, numberOfClusters int32) *placementv1beta1.ClusterResourcePlacement {
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: testCRPName},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "", Version: "v1", Kind: "Namespace", Name: "web"}},
+			Policy:            &placementv1beta1.PlacementPolicy{PlacementType: placementType, NumberOfClusters: ptr.To(numberOfClusters)},
+		},
+	}
+}
+
+func newTestReconciler(t *testing.T, metrics MetricsClient, objects ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	if err := placementv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement APIs to the scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the core APIs to the scheme: %v", err)
+	}
+	return &Reconciler{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&placementv1beta1.PlacementScaler{}).Build(),
+		Metrics: metrics,
+		now:     func() time.Time { return testNow },
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-system", Name: "prometheus"},
+		Data:       map[string][]byte{tokenKey: []byte("token")},
+	}
+	tests := map[string]struct {
+		crp              *placementv1beta1.ClusterResourcePlacement
+		lastScaleTime    *metav1.Time
+		metrics          *fakeMetrics
+		wantErr          bool
+		wantClusters     int32
+		wantDesired      *int32
+		wantLastScale    *metav1.Time
+		wantMetricValue  string
+		wantConditions   []metav1.Condition
+		wantRequeueAfter time.Duration
+	}{
+		"scale up": {
+			crp:              newTestCRP(placementv1beta1.PickNPlacementType, 2),
+			metrics:          &fakeMetrics{value: 250.5},
+			wantClusters:     3,
+			wantDesired:      ptr.To(int32(3)),
+			wantLastScale:    &metav1.Time{Time: testNow},
+			wantMetricValue:  "250500m",
+			wantConditions:   []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionTrue, Reason: scaledReason}},
+			wantRequeueAfter: time.Minute,
+		},
+		"bounded by the maximum": {
+			crp:              newTestCRP(placementv1beta1.PickNPlacementType, 2),
+			metrics:          &fakeMetrics{value: 10000},
+			wantClusters:     5,
+			wantDesired:      ptr.To(int32(5)),
+			wantLastScale:    &metav1.Time{Time: testNow},
+			wantMetricValue:  "10k",
+			wantConditions:   []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionTrue, Reason: scaledReason}},
+			wantRequeueAfter: time.Minute,
+		},
+		"scale down after the stabilization window": {
+			crp:              newTestCRP(placementv1beta1.PickNPlacementType, 4),
+			lastScaleTime:    &metav1.Time{Time: testNow.Add(-10 * time.Minute)},
+			metrics:          &fakeMetrics{value: 0},
+			wantClusters:     2,
+			wantDesired:      ptr.To(int32(2)),
+			wantLastScale:    &metav1.Time{Time: testNow},
+			wantMetricValue:  "0",
+			wantConditions:   []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionTrue, Reason: scaledReason}},
+			wantRequeueAfter: time.Minute,
+		},
+		"scale down in the stabilization window": {
+			crp:              newTestCRP(placementv1beta1.PickNPlacementType, 4),
+			lastScaleTime:    &metav1.Time{Time: testNow.Add(-time.Minute)},
+			metrics:          &fakeMetrics{value: 150},
+			wantClusters:     4,
+			wantDesired:      ptr.To(int32(4)),
+			wantLastScale:    &metav1.Time{Time: testNow.Add(-time.Minute)},
+			wantMetricValue:  "150",
+			wantConditions:   []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionTrue, Reason: scaleDownStabilizingReason}},
+			wantRequeueAfter: time.Minute,
+		},
+		"metric unavailable": {
+			crp:            newTestCRP(placementv1beta1.PickNPlacementType, 3),
+			metrics:        &fakeMetrics{err: errors.New("connection refused")},
+			wantErr:        true,
+			wantClusters:   3,
+			wantConditions: []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionFalse, Reason: metricUnavailableReason}},
+		},
+		"placement not found": {
+			metrics:          &fakeMetrics{value: 250},
+			wantConditions:   []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionFalse, Reason: invalidPlacementReason}},
+			wantRequeueAfter: time.Minute,
+		},
+		"placement not pickN": {
+			crp:              newTestCRP(placementv1beta1.PickAllPlacementType, 0),
+			metrics:          &fakeMetrics{value: 250},
+			wantConditions:   []metav1.Condition{{Type: string(placementv1beta1.PlacementScalerConditionTypeActive), Status: metav1.ConditionFalse, Reason: invalidPlacementReason}},
+			wantRequeueAfter: time.Minute,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scaler := newTestScaler()
+			scaler.Status.LastScaleTime = tc.lastScaleTime
+			objects := []client.Object{scaler, secret}
+			if tc.crp != nil {
+				objects = append(objects, tc.crp)
+			}
+			r := newTestReconciler(t, tc.metrics, objects...)
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testScalerName}})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Reconcile() = %v, want error %t", err, tc.wantErr)
+			}
+			if result.RequeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Reconcile() requeueAfter = %v, want %v", result.RequeueAfter, tc.wantRequeueAfter)
+			}
+
+			var gotScaler placementv1beta1.PlacementScaler
+			if err := r.Client.Get(context.Background(), types.NamespacedName{Name: testScalerName}, &gotScaler); err != nil {
+				t.Fatalf("failed to get the placement scaler: %v", err)
+			}
+			if diff := testcontroller.CompareConditions(tc.wantConditions, gotScaler.Status.Conditions); diff != "" {
+				t.Errorf("conditions mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantDesired, gotScaler.Status.DesiredClusters); diff != "" {
+				t.Errorf("desiredClusters mismatch (-want, +got):\n%s", diff)
+			}
+			if !tc.wantLastScale.Equal(gotScaler.Status.LastScaleTime) {
+				t.Errorf("lastScaleTime = %v, want %v", gotScaler.Status.LastScaleTime, tc.wantLastScale)
+			}
+			if tc.wantMetricValue != "" {
+				if gotScaler.Status.MetricValue == nil || gotScaler.Status.MetricValue.String() != tc.wantMetricValue {
+					t.Errorf("metricValue = %v, want %s", gotScaler.Status.MetricValue, tc.wantMetricValue)
+				}
+			}
+			if tc.crp == nil {
+				return
+			}
+			if diff := cmp.Diff(secret.Data, tc.metrics.credentials); tc.crp.Spec.Policy.PlacementType == placementv1beta1.PickNPlacementType && diff != "" {
+				t.Errorf("credentials mismatch (-want, +got):\n%s", diff)
+			}
+			var gotCRP placementv1beta1.ClusterResourcePlacement
+			if err := r.Client.Get(context.Background(), types.NamespacedName{Name: testCRPName}, &gotCRP); err != nil {
+				t.Fatalf("failed to get the placement: %v", err)
+			}
+			if got := ptr.Deref(gotCRP.Spec.Policy.NumberOfClusters, 0); got != tc.wantClusters {
+				t.Errorf("numberOfClusters = %d, want %d", got, tc.wantClusters)
+			}
+		})
+	}
+}
+
+func TestValidateScaler(t *testing.T) {
+	tests := map[string]struct {
+		mutate  func(spec *placementv1beta1.PlacementScalerSpec)
+		want    int32
+		wantErr bool
+	}{
+		"valid": {
+			mutate: func(_ *placementv1beta1.PlacementScalerSpec) {},
+			want:   2,
+		},
+		"default minimum": {
+			mutate: func(spec *placementv1beta1.PlacementScalerSpec) { spec.MinClusters = nil },
+			want:   1,
+		},
+		"minimum greater than maximum": {
+			mutate:  func(spec *placementv1beta1.PlacementScalerSpec) { spec.MinClusters = ptr.To(int32(6)) },
+			wantErr: true,
+		},
+		"no metric source": {
+			mutate:  func(spec *placementv1beta1.PlacementScalerSpec) { spec.Metric.Prometheus = nil },
+			wantErr: true,
+		},
+		"zero target value": {
+			mutate: func(spec *placementv1beta1.PlacementScalerSpec) {
+				spec.Metric.TargetValuePerCluster = resource.MustParse("0")
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := newTestScaler().Spec
+			tc.mutate(&spec)
+			got, err := validateScaler(&spec)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("validateScaler() = %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("validateScaler() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDesiredClusters(t *testing.T) {
+	tests := map[string]struct {
+		value float64
+		want  int32
+	}{
+		"below the minimum": {value: 10, want: 2},
+		"rounded up":        {value: 301, want: 4},
+		"exact":             {value: 400, want: 4},
+		"above the maximum": {value: 900, want: 5},
+		"negative":          {value: -100, want: 2},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := desiredClusters(tc.value, 100, 2, 5); got != tc.want {
+				t.Errorf("desiredClusters(%v) = %d, want %d", tc.value, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementscaler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// the keys of the credentials in the secret of a Prometheus metric.
+	tokenKey    = "token"
+	usernameKey = "username"
+	passwordKey = "password"
+
+	// maxResponseSize bounds the size of the query responses that are read.
+	maxResponseSize = 1 << 20
+)
+
+// prometheusResponse is the response of the Prometheus instant query API.
+type prometheusResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// PrometheusClient reads the metrics with the instant query API of the Prometheus servers.
+type PrometheusClient struct {
+	// HTTPClient is the client to connect to the Prometheus servers. Default: http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Query returns the value of the query, which must be a scalar or a vector with at most one sample. An empty vector,
+// e.g. when there is no traffic, is regarded as zero.
+func (p *PrometheusClient) Query(ctx context.Context, metric *placementv1beta1.PrometheusMetric, credentials map[string][]byte) (float64, error) {
+	queryURL, err := url.JoinPath(metric.ServerAddress, "api/v1/query")
+	if err != nil {
+		return 0, fmt.Errorf("invalid Prometheus server address %q: %w", metric.ServerAddress, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, queryURL, strings.NewReader(url.Values{"query": {metric.Query}}.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	switch {
+	case len(credentials[tokenKey]) > 0:
+		req.Header.Set("Authorization", "Bearer "+string(credentials[tokenKey]))
+	case len(credentials[usernameKey]) > 0:
+		req.SetBasicAuth(string(credentials[usernameKey]), string(credentials[passwordKey]))
+	}
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	var result prometheusResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("the Prometheus server returned %s: %w", resp.Status, err)
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("the query failed with %s: %s", result.ErrorType, result.Error)
+	}
+	return parseQueryResult(result.Data.ResultType, result.Data.Result)
+}
+
+// parseQueryResult returns the value of a scalar or a vector with at most one sample.
+func parseQueryResult(resultType string, result json.RawMessage) (float64, error) {
+	// a sample value is a pair of the timestamp and the value in a string
+	var value [2]interface{}
+	switch resultType {
+	case "scalar":
+		if err := json.Unmarshal(result, &value); err != nil {
+			return 0, fmt.Errorf("invalid scalar result: %w", err)
+		}
+	case "vector":
+		var samples []struct {
+			Value [2]interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(result, &samples); err != nil {
+			return 0, fmt.Errorf("invalid vector result: %w", err)
+		}
+		switch len(samples) {
+		case 0:
+			return 0, nil
+		case 1:
+			value = samples[0].Value
+		default:
+			return 0, fmt.Errorf("the query returned %d samples, want at most 1", len(samples))
+		}
+	default:
+		return 0, fmt.Errorf("the query returned a %s, want a scalar or a vector", resultType)
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", value[1])
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample value %q: %w", s, err)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("the query returned %s", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementscaler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestPrometheusClientQuery(t *testing.T) {
+	const query = `sum(rate(http_requests_total[2m]))`
+	tests := map[string]struct {
+		response    string
+		credentials map[string][]byte
+		wantAuth    string
+		want        float64
+		wantErr     bool
+	}{
+		"vector": {
+			response:    `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1717243200.000,"250.5"]}]}}`,
+			credentials: map[string][]byte{tokenKey: []byte("secret")},
+			wantAuth:    "Bearer secret",
+			want:        250.5,
+		},
+		"empty vector": {
+			response: `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			want:     0,
+		},
+		"scalar": {
+			response:    `{"status":"success","data":{"resultType":"scalar","result":[1717243200.000,"42"]}}`,
+			credentials: map[string][]byte{usernameKey: []byte("user"), passwordKey: []byte("pass")},
+			wantAuth:    "Basic dXNlcjpwYXNz",
+			want:        42,
+		},
+		"multiple samples": {
+			response: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"a":"1"},"value":[1,"1"]},{"metric":{"a":"2"},"value":[1,"2"]}]}}`,
+			wantErr:  true,
+		},
+		"not a number": {
+			response: `{"status":"success","data":{"resultType":"scalar","result":[1717243200.000,"NaN"]}}`,
+			wantErr:  true,
+		},
+		"matrix": {
+			response: `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			wantErr:  true,
+		},
+		"query error": {
+			response: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			wantErr:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/query" || r.FormValue("query") != query {
+					t.Errorf("request = %s %s, want the instant query", r.URL.Path, r.FormValue("query"))
+				}
+				if got := r.Header.Get("Authorization"); got != tc.wantAuth {
+					t.Errorf("Authorization = %q, want %q", got, tc.wantAuth)
+				}
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			p := &PrometheusClient{HTTPClient: server.Client()}
+			got, err := p.Query(context.Background(), &placementv1beta1.PrometheusMetric{ServerAddress: server.URL, Query: query}, tc.credentials)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Query() = %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Query() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementsli features a sampler which exports the availability SLIs of the cluster resource placements, i.e.
+// the fraction of the sample intervals in which the bindings were fully available, per placement and per member
+// cluster, so that the platform teams can define SLOs on the reliability of the placements from the metrics.
+package placementsli
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/metrics"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+const (
+	// maxBuckets is the max number of the buckets which the samples of a placement or a cluster are kept in, so that
+	// the memory of a long window does not grow with the number of the sample intervals in it.
+	maxBuckets = 1440
+)
+
+// Sampler samples whether the bindings are available every interval, and exports the fraction of the available
+// samples of each placement and each member cluster over the windows. Only the leader samples the bindings.
+type Sampler struct {
+	client   client.Reader
+	interval time.Duration
+	windows  []time.Duration
+	// bucketWidth is the width of the buckets which the samples are kept in, which is the interval unless the
+	// longest window holds more than maxBuckets intervals.
+	bucketWidth time.Duration
+
+	placements map[string]*history
+	clusters   map[string]*history
+}
+
+// NewSampler returns a sampler which samples the bindings in the client every interval and exports their
+// availability over the windows.
+func NewSampler(c client.Reader, interval time.Duration, windows []time.Duration) *Sampler {
+	s := &Sampler{
+		client:      c,
+		interval:    interval,
+		windows:     windows,
+		bucketWidth: interval,
+		placements:  make(map[string]*history),
+		clusters:    make(map[string]*history),
+	}
+	if maxWindow := s.maxWindow(); maxWindow/maxBuckets > s.bucketWidth {
+		s.bucketWidth = maxWindow / maxBuckets
+	}
+	return s
+}
+
+// Start samples the bindings every interval until the context is done.
+func (s *Sampler) Start(ctx context.Context) error {
+	klog.V(2).InfoS("Starting the placement SLI sampler", "interval", s.interval, "windows", s.windows, "bucketWidth", s.bucketWidth)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(2).InfoS("The placement SLI sampler is stopped")
+			return nil
+		case <-ticker.C:
+			s.sample(ctx, time.Now())
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader sample the bindings, so that each interval is sampled once.
+func (s *Sampler) NeedLeaderElection() bool {
+	return true
+}
+
+// sample records whether each binding is available, and exports the SLIs of the placements and the clusters. The
+// placements and the clusters which have no sample in the longest window any more are no longer exported.
+func (s *Sampler) sample(ctx context.Context, now time.Time) {
+	var bindingList placementv1beta1.ClusterResourceBindingList
+	if err := s.client.List(ctx, &bindingList); err != nil {
+		// the interval is left out of the SLIs rather than counted either way
+		klog.ErrorS(err, "Failed to list the bindings to sample the placement SLIs")
+		return
+	}
+	placementSamples := make(map[string]*bucket)
+	clusterSamples := make(map[string]*bucket)
+	for i := range bindingList.Items {
+		binding := &bindingList.Items[i]
+		crpName := binding.Labels[placementv1beta1.CRPTrackingLabel]
+		if crpName == "" || !binding.DeletionTimestamp.IsZero() ||
+			(binding.Spec.State != placementv1beta1.BindingStateScheduled && binding.Spec.State != placementv1beta1.BindingStateBound) {
+			continue
+		}
+		available := isBindingAvailable(binding)
+		addSample(placementSamples, crpName, available)
+		addSample(clusterSamples, binding.Spec.TargetCluster, available)
+		metrics.PlacementAvailabilitySamples.WithLabelValues(crpName, strconv.FormatBool(available)).Inc()
+		metrics.ClusterPlacementAvailabilitySamples.WithLabelValues(binding.Spec.TargetCluster, strconv.FormatBool(available)).Inc()
+	}
+	s.export(now, s.placements, placementSamples, metrics.PlacementAvailabilitySLI, metrics.PlacementAvailabilitySamples)
+	s.export(now, s.clusters, clusterSamples, metrics.ClusterPlacementAvailabilitySLI, metrics.ClusterPlacementAvailabilitySamples)
+	klog.V(2).InfoS("Sampled the placement SLIs", "bindings", len(bindingList.Items), "placements", len(s.placements), "clusters", len(s.clusters))
+}
+
+// export adds the samples to the histories and sets the SLI of every window of each history; the histories with no
+// sample left in the longest window are dropped along with their metrics.
+func (s *Sampler) export(now time.Time, histories map[string]*history, samples map[string]*bucket,
+	sli *prometheus.GaugeVec, sampleCounts *prometheus.CounterVec) {
+	for name, sample := range samples {
+		h, ok := histories[name]
+		if !ok {
+			h = &history{}
+			histories[name] = h
+		}
+		h.add(now.Truncate(s.bucketWidth), sample.available, sample.total)
+	}
+	maxWindow := s.maxWindow()
+	for name, h := range histories {
+		h.trim(now.Add(-maxWindow))
+		if len(h.buckets) == 0 {
+			for _, window := range s.windows {
+				sli.DeleteLabelValues(name, formatWindow(window))
+			}
+			sampleCounts.DeleteLabelValues(name, "true")
+			sampleCounts.DeleteLabelValues(name, "false")
+			delete(histories, name)
+			continue
+		}
+		for _, window := range s.windows {
+			if ratio, ok := h.ratio(now.Add(-window)); ok {
+				sli.WithLabelValues(name, formatWindow(window)).Set(ratio)
+			} else {
+				sli.DeleteLabelValues(name, formatWindow(window))
+			}
+		}
+	}
+}
+
+func (s *Sampler) maxWindow() time.Duration {
+	var maxWindow time.Duration
+	for _, window := range s.windows {
+		if window > maxWindow {
+			maxWindow = window
+		}
+	}
+	return maxWindow
+}
+
+// isBindingAvailable returns true if all the resources of the binding are available on its target cluster, i.e. its
+// Available condition is true for its current generation.
+func isBindingAvailable(binding *placementv1beta1.ClusterResourceBinding) bool {
+	return condition.IsConditionStatusTrue(binding.GetCondition(string(placementv1beta1.ResourceBindingAvailable)), binding.Generation)
+}
+
+func addSample(samples map[string]*bucket, name string, available bool) {
+	sample, ok := samples[name]
+	if !ok {
+		sample = &bucket{}
+		samples[name] = sample
+	}
+	sample.total++
+	if available {
+		sample.available++
+	}
+}
+
+// formatWindow formats the window as the value of the window label, e.g. 1h instead of 1h0m0s.
+func formatWindow(window time.Duration) string {
+	s := window.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// bucket is the number of the available samples and all the samples which start in the same bucket width.
+type bucket struct {
+	start     time.Time
+	available int
+	total     int
+}
+
+// history is the samples of a placement or a cluster, in the buckets ordered by their start times.
+type history struct {
+	buckets []bucket
+}
+
+// add adds the samples to the bucket which starts at the given time, which must not be before the last bucket.
+func (h *history) add(start time.Time, available, total int) {
+	if n := len(h.buckets); n > 0 && h.buckets[n-1].start.Equal(start) {
+		h.buckets[n-1].available += available
+		h.buckets[n-1].total += total
+		return
+	}
+	h.buckets = append(h.buckets, bucket{start: start, available: available, total: total})
+}
+
+// trim drops the buckets which start before the cutoff.
+func (h *history) trim(cutoff time.Time) {
+	i := 0
+	for i < len(h.buckets) && h.buckets[i].start.Before(cutoff) {
+		i++
+	}
+	h.buckets = h.buckets[i:]
+}
+
+// ratio returns the fraction of the available samples in the buckets which start at or after the cutoff, and false if
+// there is no sample in them.
+func (h *history) ratio(cutoff time.Time) (float64, bool) {
+	available, total := 0, 0
+	for _, b := range h.buckets {
+		if b.start.Before(cutoff) {
+			continue
+		}
+		available += b.available
+		total += b.total
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(available) / float64(total), true
+}
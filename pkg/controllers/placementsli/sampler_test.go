@@ -0,0 +1,190 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/metrics"
+)
+
+func TestFormatWindow(t *testing.T) {
+	tests := map[string]struct {
+		window time.Duration
+		want   string
+	}{
+		"hours":               {window: 720 * time.Hour, want: "720h"},
+		"minutes":             {window: 30 * time.Minute, want: "30m"},
+		"hours and minutes":   {window: 90 * time.Minute, want: "1h30m"},
+		"seconds":             {window: 45 * time.Second, want: "45s"},
+		"minutes and seconds": {window: 90 * time.Second, want: "1m30s"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatWindow(tc.window); got != tc.want {
+				t.Errorf("formatWindow(%v) = %q, want %q", tc.window, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := &history{}
+	h.add(start, 1, 1)
+	h.add(start, 0, 1)
+	h.add(start.Add(time.Minute), 1, 2)
+	h.add(start.Add(2*time.Minute), 2, 2)
+	wantBuckets := []bucket{
+		{start: start, available: 1, total: 2},
+		{start: start.Add(time.Minute), available: 1, total: 2},
+		{start: start.Add(2 * time.Minute), available: 2, total: 2},
+	}
+	if diff := cmp.Diff(wantBuckets, h.buckets, cmp.AllowUnexported(bucket{})); diff != "" {
+		t.Errorf("add() buckets mismatch (-want, +got):\n%s", diff)
+	}
+
+	ratioTests := map[string]struct {
+		cutoff    time.Time
+		wantRatio float64
+		wantOK    bool
+	}{
+		"all the buckets":  {cutoff: start, wantRatio: 4.0 / 6, wantOK: true},
+		"the last buckets": {cutoff: start.Add(time.Minute), wantRatio: 3.0 / 4, wantOK: true},
+		"no bucket":        {cutoff: start.Add(3 * time.Minute)},
+	}
+	for name, tc := range ratioTests {
+		t.Run(name, func(t *testing.T) {
+			gotRatio, gotOK := h.ratio(tc.cutoff)
+			if gotRatio != tc.wantRatio || gotOK != tc.wantOK {
+				t.Errorf("ratio(%v) = %v, %t, want %v, %t", tc.cutoff, gotRatio, gotOK, tc.wantRatio, tc.wantOK)
+			}
+		})
+	}
+
+	h.trim(start.Add(90 * time.Second))
+	if diff := cmp.Diff(wantBuckets[2:], h.buckets, cmp.AllowUnexported(bucket{})); diff != "" {
+		t.Errorf("trim() buckets mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestNewSampler(t *testing.T) {
+	tests := map[string]struct {
+		interval        time.Duration
+		windows         []time.Duration
+		wantBucketWidth time.Duration
+	}{
+		"short windows": {
+			interval:        time.Minute,
+			windows:         []time.Duration{time.Hour, 24 * time.Hour},
+			wantBucketWidth: time.Minute,
+		},
+		"long window": {
+			interval:        time.Minute,
+			windows:         []time.Duration{time.Hour, 720 * time.Hour},
+			wantBucketWidth: 30 * time.Minute,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := NewSampler(nil, tc.interval, tc.windows).bucketWidth; got != tc.wantBucketWidth {
+				t.Errorf("NewSampler().bucketWidth = %v, want %v", got, tc.wantBucketWidth)
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	newBinding := func(name, crpName, cluster string, state placementv1beta1.BindingState, available bool) *placementv1beta1.ClusterResourceBinding {
+		binding := &placementv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Generation: 1,
+				Labels:     map[string]string{placementv1beta1.CRPTrackingLabel: crpName},
+			},
+			Spec: placementv1beta1.ResourceBindingSpec{State: state, TargetCluster: cluster},
+		}
+		if available {
+			binding.Status.Conditions = []metav1.Condition{{
+				Type:               string(placementv1beta1.ResourceBindingAvailable),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 1,
+			}}
+		}
+		return binding
+	}
+	bindings := []client.Object{
+		newBinding("sli-crp-1-member-1", "sli-crp-1", "sli-member-1", placementv1beta1.BindingStateBound, true),
+		newBinding("sli-crp-1-member-2", "sli-crp-1", "sli-member-2", placementv1beta1.BindingStateBound, false),
+		newBinding("sli-crp-2-member-1", "sli-crp-2", "sli-member-1", placementv1beta1.BindingStateScheduled, false),
+		newBinding("sli-crp-2-member-2", "sli-crp-2", "sli-member-2", placementv1beta1.BindingStateUnscheduled, false),
+		newBinding("sli-crp-3-member-1", "", "sli-member-1", placementv1beta1.BindingStateBound, false),
+	}
+	scheme := runtime.NewScheme()
+	if err := placementv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(bindings...).Build()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSampler(fakeClient, time.Minute, []time.Duration{time.Hour, 24 * time.Hour})
+	s.sample(context.Background(), start)
+	s.sample(context.Background(), start.Add(time.Minute))
+
+	wantSLIs := map[string]float64{
+		"sli-crp-1": 0.5,
+		"sli-crp-2": 0,
+	}
+	for name, want := range wantSLIs {
+		for _, window := range []string{"1h", "24h"} {
+			if got := testutil.ToFloat64(metrics.PlacementAvailabilitySLI.WithLabelValues(name, window)); got != want {
+				t.Errorf("placement %s SLI over %s = %v, want %v", name, window, got, want)
+			}
+		}
+	}
+	wantClusterSLIs := map[string]float64{
+		"sli-member-1": 0.5,
+		"sli-member-2": 0,
+	}
+	for name, want := range wantClusterSLIs {
+		if got := testutil.ToFloat64(metrics.ClusterPlacementAvailabilitySLI.WithLabelValues(name, "1h")); got != want {
+			t.Errorf("cluster %s SLI over 1h = %v, want %v", name, got, want)
+		}
+	}
+	if got := testutil.ToFloat64(metrics.PlacementAvailabilitySamples.WithLabelValues("sli-crp-1", "true")); got != 2 {
+		t.Errorf("placement sli-crp-1 available samples = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(metrics.PlacementAvailabilitySamples.WithLabelValues("sli-crp-2", "false")); got != 2 {
+		t.Errorf("placement sli-crp-2 unavailable samples = %v, want 2", got)
+	}
+
+	// the placements and the clusters which have no sample in the longest window are no longer exported
+	for _, binding := range bindings {
+		if err := fakeClient.Delete(context.Background(), binding); err != nil {
+			t.Fatalf("failed to delete binding %s: %v", binding.GetName(), err)
+		}
+	}
+	s.sample(context.Background(), start.Add(25*time.Hour))
+	if len(s.placements) != 0 || len(s.clusters) != 0 {
+		t.Errorf("sample() kept %d placements and %d clusters, want none", len(s.placements), len(s.clusters))
+	}
+	if got := testutil.CollectAndCount(metrics.PlacementAvailabilitySLI); got != 0 {
+		t.Errorf("placement SLI series = %d, want 0", got)
+	}
+	if got := testutil.CollectAndCount(metrics.ClusterPlacementAvailabilitySamples); got != 0 {
+		t.Errorf("cluster sample series = %d, want 0", got)
+	}
+}
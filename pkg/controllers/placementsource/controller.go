@@ -0,0 +1,244 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementsource features a controller that renders the manifests of the placement sources, e.g. Git
+// repositories and OCI artifacts, into resources that the cluster resource placements select and place.
+package placementsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/validator"
+)
+
+const (
+	// the reasons of the Rendered condition.
+	renderedReason       = "Rendered"
+	invalidSourceReason  = "InvalidSource"
+	fetchFailedReason    = "FetchFailed"
+	renderFailedReason   = "RenderFailed"
+	defaultCheckInterval = 5 * time.Minute
+
+	// the keys of the basic auth credentials in the secret of a source.
+	usernameKey = "username"
+	passwordKey = "password"
+)
+
+// Fetcher fetches the manifests of a kind of placement sources.
+type Fetcher interface {
+	// Resolve returns the latest revision of the source, e.g. the commit of a Git branch or the digest of an OCI tag,
+	// without fetching it.
+	Resolve(ctx context.Context, spec *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte) (string, error)
+	// Fetch fetches the revision of the source into the directory and returns the directory whose manifests are
+	// rendered.
+	Fetch(ctx context.Context, spec *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte, revision, dir string) (string, error)
+}
+
+// Reconciler reconciles a placement source. It renders the manifests of the latest revision of the source into its
+// status, and triggers the cluster resource placements which select the source to cut new resource snapshots.
+type Reconciler struct {
+	Client client.Client
+	// PlacementController exposes the placement queue for the reconciler to push to.
+	PlacementController controller.Controller
+	// GitFetcher fetches the Git sources.
+	GitFetcher Fetcher
+	// OCIFetcher fetches the OCI sources.
+	OCIFetcher Fetcher
+}
+
+// Reconcile renders the latest revision of the placement source.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("PlacementSource reconciliation starts", "placementSource", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("PlacementSource reconciliation ends", "placementSource", req.Name, "latency", latency)
+	}()
+
+	var source placementv1beta1.PlacementSource
+	if err := r.Client.Get(ctx, req.NamespacedName, &source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the placement source", "placementSource", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !source.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	interval := defaultCheckInterval
+	if source.Spec.Interval != nil && source.Spec.Interval.Duration > 0 {
+		interval = source.Spec.Interval.Duration
+	}
+
+	fetcher, credentialsRef, err := r.fetcherFor(&source.Spec)
+	if err != nil {
+		klog.ErrorS(err, "Invalid placement source", "placementSource", req.Name)
+		return ctrl.Result{}, r.updateRenderFailure(ctx, &source, invalidSourceReason, err)
+	}
+	credentials, err := r.getCredentials(ctx, credentialsRef)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get the credentials of the placement source", "placementSource", req.Name)
+		return ctrl.Result{}, r.updateRenderFailure(ctx, &source, fetchFailedReason, err)
+	}
+	revision, err := fetcher.Resolve(ctx, &source.Spec, credentials)
+	if err != nil {
+		klog.ErrorS(err, "Failed to resolve the revision of the placement source", "placementSource", req.Name)
+		return ctrl.Result{}, r.updateRenderFailure(ctx, &source, fetchFailedReason, err)
+	}
+	rendered := source.GetCondition(string(placementv1beta1.PlacementSourceConditionTypeRendered))
+	if revision == source.Status.ObservedRevision && rendered != nil && rendered.Status == metav1.ConditionTrue &&
+		rendered.ObservedGeneration == source.Generation {
+		klog.V(2).InfoS("The placement source is up to date", "placementSource", req.Name, "revision", revision)
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "placement-source-")
+	if err != nil {
+		return ctrl.Result{}, controller.NewUnexpectedBehaviorError(err)
+	}
+	defer os.RemoveAll(dir)
+	manifestDir, err := fetcher.Fetch(ctx, &source.Spec, credentials, revision, dir)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch the placement source", "placementSource", req.Name, "revision", revision)
+		return ctrl.Result{}, r.updateRenderFailure(ctx, &source, fetchFailedReason, err)
+	}
+	resources, err := renderSourceDir(dir, manifestDir)
+	if err != nil {
+		klog.ErrorS(err, "Failed to render the placement source", "placementSource", req.Name, "revision", revision)
+		return ctrl.Result{}, r.updateRenderFailure(ctx, &source, renderFailedReason, err)
+	}
+	source.Status.ObservedRevision = revision
+	source.Status.Resources = resources
+	source.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.PlacementSourceConditionTypeRendered),
+		Status:             metav1.ConditionTrue,
+		Reason:             renderedReason,
+		Message:            fmt.Sprintf("Rendered %d resources of revision %s", len(resources), revision),
+		ObservedGeneration: source.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, &source); err != nil {
+		klog.ErrorS(err, "Failed to update the rendered resources of the placement source", "placementSource", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	klog.V(2).InfoS("Rendered the placement source", "placementSource", req.Name, "revision", revision, "numberOfResources", len(resources))
+
+	if err := r.enqueueSelectingPlacements(ctx, source.Name); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// fetcherFor returns the fetcher of the source and the secret of its credentials. The secret is checked again here as
+// the source may have been created before the validating webhook was installed.
+func (r *Reconciler) fetcherFor(spec *placementv1beta1.PlacementSourceSpec) (Fetcher, *placementv1beta1.NamespacedName, error) {
+	var fetcher Fetcher
+	var secretRef *placementv1beta1.NamespacedName
+	switch {
+	case spec.Git != nil && spec.OCI != nil:
+		return nil, nil, controller.NewUserError(errors.New("the git and oci sources are mutually exclusive"))
+	case spec.Git != nil:
+		fetcher, secretRef = r.GitFetcher, spec.Git.SecretRef
+	case spec.OCI != nil:
+		fetcher, secretRef = r.OCIFetcher, spec.OCI.SecretRef
+	default:
+		return nil, nil, controller.NewUserError(errors.New("the placement source has no source specified"))
+	}
+	if err := validator.ValidatePlacementSourceSecretRef(secretRef); err != nil {
+		return nil, nil, controller.NewUserError(err)
+	}
+	return fetcher, secretRef, nil
+}
+
+func (r *Reconciler) getCredentials(ctx context.Context, ref *placementv1beta1.NamespacedName) (map[string][]byte, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	var secret corev1.Secret
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	return secret.Data, nil
+}
+
+// renderSourceDir renders the manifests in the manifest directory, which must be in the directory of the source.
+func renderSourceDir(dir, manifestDir string) ([]placementv1beta1.ResourceContent, error) {
+	if rel, err := filepath.Rel(dir, manifestDir); err != nil || !filepath.IsLocal(rel) {
+		return nil, controller.NewUserError(fmt.Errorf("the manifest directory %s is outside of the source", manifestDir))
+	}
+	// the manifest directory can be a symbolic link in the source, which must not point out of the source either
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := filepath.EvalSymlinks(manifestDir)
+	if err != nil {
+		return nil, controller.NewUserError(fmt.Errorf("the manifest directory %s is not found in the source: %w", manifestDir, err))
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+		return nil, controller.NewUserError(fmt.Errorf("the manifest directory %s is outside of the source", manifestDir))
+	}
+	return renderManifests(manifestDir)
+}
+
+// updateRenderFailure records the failure in the Rendered condition, keeping the resources rendered before.
+func (r *Reconciler) updateRenderFailure(ctx context.Context, source *placementv1beta1.PlacementSource, reason string, renderErr error) error {
+	source.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.PlacementSourceConditionTypeRendered),
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            renderErr.Error(),
+		ObservedGeneration: source.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, source); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the placement source", "placementSource", klog.KObj(source))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return renderErr
+}
+
+// enqueueSelectingPlacements triggers the cluster resource placements which select the source.
+func (r *Reconciler) enqueueSelectingPlacements(ctx context.Context, sourceName string) error {
+	var crpList placementv1beta1.ClusterResourcePlacementList
+	if err := r.Client.List(ctx, &crpList); err != nil {
+		klog.ErrorS(err, "Failed to list the cluster resource placements")
+		return controller.NewAPIServerError(true, err)
+	}
+	for i := range crpList.Items {
+		for _, selector := range crpList.Items[i].Spec.ResourceSelectors {
+			if utils.IsPlacementSourceSelector(selector) && selector.Name == sourceName {
+				klog.V(2).InfoS("Triggering the placement of the placement source", "placementSource", sourceName, "clusterResourcePlacement", crpList.Items[i].Name)
+				r.PlacementController.Enqueue(crpList.Items[i].Name)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("placement-source-controller").
+		For(&placementv1beta1.PlacementSource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	testcontroller "go.goms.io/fleet/test/utils/controller"
+)
+
+const (
+	testSourceName = "app-source"
+	testCRPName    = "app-crp"
+)
+
+// fakeFetcher serves the files of the revision it resolves to.
+type fakeFetcher struct {
+	revision    string
+	files       map[string]string
+	fetchErr    error
+	credentials map[string][]byte
+	fetchCount  int
+}
+
+func (f *fakeFetcher) Resolve(_ context.Context, _ *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte) (string, error) {
+	f.credentials = credentials
+	return f.revision, nil
+}
+
+func (f *fakeFetcher) Fetch(_ context.Context, _ *placementv1beta1.PlacementSourceSpec, _ map[string][]byte, _, dir string) (string, error) {
+	f.fetchCount++
+	if f.fetchErr != nil {
+		return "", f.fetchErr
+	}
+	for name, content := range f.files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+func newTestSource() *placementv1beta1.PlacementSource {
+	return &placementv1beta1.PlacementSource{
+		ObjectMeta: metav1.ObjectMeta{Name: testSourceName, Generation: 1},
+		Spec: placementv1beta1.PlacementSourceSpec{
+			Git: &placementv1beta1.GitSource{
+				URL:       "https://example.com/app.git",
+				SecretRef: &placementv1beta1.NamespacedName{Namespace: "fleet-system", Name: "git-credentials"},
+			},
+			Interval: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+}
+
+func newTestReconciler(t *testing.T, fetcher Fetcher, objects ...client.Object) (*Reconciler, *testcontroller.FakeController) {
+	scheme := runtime.NewScheme()
+	if err := placementv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement APIs to the scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the core APIs to the scheme: %v", err)
+	}
+	placementController := &testcontroller.FakeController{}
+	return &Reconciler{
+		Client:              fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&placementv1beta1.PlacementSource{}).Build(),
+		PlacementController: placementController,
+		GitFetcher:          fetcher,
+	}, placementController
+}
+
+func getSource(t *testing.T, r *Reconciler) *placementv1beta1.PlacementSource {
+	var source placementv1beta1.PlacementSource
+	if err := r.Client.Get(context.Background(), types.NamespacedName{Name: testSourceName}, &source); err != nil {
+		t.Fatalf("failed to get the placement source: %v", err)
+	}
+	return &source
+}
+
+func TestReconcileRendersSource(t *testing.T) {
+	fetcher := &fakeFetcher{
+		revision: "rev-1",
+		files:    map[string]string{"namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n"},
+	}
+	crp := &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: testCRPName},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{
+				{
+					Group:   placementv1beta1.GroupVersion.Group,
+					Version: placementv1beta1.GroupVersion.Version,
+					Kind:    placementv1beta1.PlacementSourceKind,
+					Name:    testSourceName,
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-system", Name: "git-credentials"},
+		Data:       map[string][]byte{usernameKey: []byte("user"), passwordKey: []byte("token")},
+	}
+	r, placementController := newTestReconciler(t, fetcher, newTestSource(), crp, secret)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: testSourceName}}
+
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	if result.RequeueAfter != time.Minute {
+		t.Errorf("Reconcile() = %+v, want requeue after the interval", result)
+	}
+	if diff := cmp.Diff(secret.Data, fetcher.credentials); diff != "" {
+		t.Errorf("credentials mismatch (-want, +got):\n%s", diff)
+	}
+	source := getSource(t, r)
+	if source.Status.ObservedRevision != "rev-1" {
+		t.Errorf("observed revision = %s, want rev-1", source.Status.ObservedRevision)
+	}
+	if len(source.Status.Resources) != 1 || string(source.Status.Resources[0].Raw) != `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}` {
+		t.Errorf("rendered resources = %v, want the namespace", source.Status.Resources)
+	}
+	wantConditions := []metav1.Condition{{Type: string(placementv1beta1.PlacementSourceConditionTypeRendered), Status: metav1.ConditionTrue, Reason: renderedReason}}
+	if diff := testcontroller.CompareConditions(wantConditions, source.Status.Conditions); diff != "" {
+		t.Errorf("conditions mismatch (-want, +got):\n%s", diff)
+	}
+	if got := placementController.Key(); got != testCRPName {
+		t.Errorf("enqueued placement = %q, want %q", got, testCRPName)
+	}
+
+	// the source is not fetched again when its revision does not change
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	if fetcher.fetchCount != 1 {
+		t.Errorf("fetch count = %d, want 1", fetcher.fetchCount)
+	}
+
+	// a failure to fetch a new revision keeps the resources rendered before
+	fetcher.revision = "rev-2"
+	fetcher.fetchErr = errors.New("connection refused")
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Fatalf("Reconcile() = nil, want error")
+	}
+	source = getSource(t, r)
+	if source.Status.ObservedRevision != "rev-1" || len(source.Status.Resources) != 1 {
+		t.Errorf("status = %+v, want the resources of rev-1", source.Status)
+	}
+	wantConditions = []metav1.Condition{{Type: string(placementv1beta1.PlacementSourceConditionTypeRendered), Status: metav1.ConditionFalse, Reason: fetchFailedReason}}
+	if diff := testcontroller.CompareConditions(wantConditions, source.Status.Conditions); diff != "" {
+		t.Errorf("conditions mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestReconcileInvalidSource(t *testing.T) {
+	tests := map[string]func(spec *placementv1beta1.PlacementSourceSpec){
+		"no source": func(spec *placementv1beta1.PlacementSourceSpec) {
+			spec.Git = nil
+		},
+		"both git and oci sources": func(spec *placementv1beta1.PlacementSourceSpec) {
+			spec.OCI = &placementv1beta1.OCISource{URL: "oci://example.com/app"}
+		},
+		"secret outside of the fleet system namespace": func(spec *placementv1beta1.PlacementSourceSpec) {
+			spec.Git.SecretRef = &placementv1beta1.NamespacedName{Namespace: "kube-system", Name: "bootstrap-token"}
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			source := newTestSource()
+			mutate(&source.Spec)
+			r, _ := newTestReconciler(t, &fakeFetcher{}, source)
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testSourceName}}); err == nil {
+				t.Fatalf("Reconcile() = nil, want error")
+			}
+			wantConditions := []metav1.Condition{{Type: string(placementv1beta1.PlacementSourceConditionTypeRendered), Status: metav1.ConditionFalse, Reason: invalidSourceReason}}
+			if diff := testcontroller.CompareConditions(wantConditions, getSource(t, r).Status.Conditions); diff != "" {
+				t.Errorf("conditions mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
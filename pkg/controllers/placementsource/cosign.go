@@ -0,0 +1,109 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	cosignPayloadMediaType        = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureAnnotation     = "dev.cosignproject.cosign/signature"
+	cosignSignatureTagSuffix      = ".sig"
+	maxCosignSignaturePayloadSize = 1024 * 1024
+)
+
+// cosignPayload is the simple signing payload that cosign signs for an artifact.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// verifySignature verifies that one of the cosign signatures of the artifact, which are stored with the
+// sha256-<hex>.sig tag, is signed by the public key for the digest of the artifact.
+func verifySignature(ctx context.Context, repo *ociRepository, digest, publicKeyPEM string) error {
+	publicKey, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+	signatureTag := strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix
+	manifest, _, err := repo.getManifest(ctx, signatureTag)
+	if err != nil {
+		return fmt.Errorf("failed to get the signatures of the artifact %s: %w", digest, err)
+	}
+	for _, layer := range manifest.Layers {
+		encodedSignature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if layer.MediaType != cosignPayloadMediaType || !ok {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			continue
+		}
+		payload, err := repo.getBlob(ctx, layer, maxCosignSignaturePayloadSize)
+		if err != nil {
+			return err
+		}
+		if !verifyWithPublicKey(publicKey, payload, signature) {
+			continue
+		}
+		var signed cosignPayload
+		if err := json.Unmarshal(payload, &signed); err != nil {
+			continue
+		}
+		if signed.Critical.Image.DockerManifestDigest == digest {
+			return nil
+		}
+	}
+	return fmt.Errorf("the artifact %s has no valid signature of the public key", digest)
+}
+
+func parsePublicKey(publicKeyPEM string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.New("the public key is not PEM encoded")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key: %w", err)
+	}
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T, only ECDSA, RSA and Ed25519 keys are supported", publicKey)
+	}
+}
+
+// verifyWithPublicKey verifies the signature of the payload the same way as cosign, i.e. the SHA-256 digest of the
+// payload is signed by an ECDSA or RSA key and the payload itself is signed by an Ed25519 key.
+func verifyWithPublicKey(publicKey crypto.PublicKey, payload, signature []byte) bool {
+	digest := sha256.Sum256(payload)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, signature)
+	}
+	return false
+}
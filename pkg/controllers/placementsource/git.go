@@ -0,0 +1,133 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// defaultGitRevision is the revision which points to the default branch of a repository.
+	defaultGitRevision = "HEAD"
+)
+
+// commitPattern matches a full commit hash.
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// defaultGitProtocols are the transports which the Git sources may use by default.
+var defaultGitProtocols = []string{"http", "https"}
+
+// GitFetcher fetches the Git sources with the git command line.
+type GitFetcher struct {
+	// GitPath is the path of the git executable. Default: git in the PATH.
+	GitPath string
+	// AllowedProtocols are the transports which git may use to fetch a source, e.g. https; all the others, including
+	// file and ext, are refused. Default: http and https.
+	AllowedProtocols []string
+}
+
+// Resolve returns the commit that the revision of the Git source points to.
+func (g *GitFetcher) Resolve(ctx context.Context, spec *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte) (string, error) {
+	revision := gitRevision(spec.Git)
+	if commitPattern.MatchString(revision) {
+		return revision, nil
+	}
+	out, err := g.run(ctx, "", credentials, "ls-remote", "--", spec.Git.URL, revision, revision+"^{}")
+	if err != nil {
+		return "", err
+	}
+	// prefer the commit a branch or HEAD points to, then the commit a tag peels to
+	refs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if commit, ref, ok := strings.Cut(line, "\t"); ok {
+			refs[ref] = commit
+		}
+	}
+	for _, ref := range []string{revision, "refs/heads/" + revision, "refs/tags/" + revision + "^{}", "refs/tags/" + revision} {
+		if commit, ok := refs[ref]; ok {
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("revision %s is not found in the Git repository %s", revision, spec.Git.URL)
+}
+
+// Fetch checks out the commit of the Git source into the directory.
+func (g *GitFetcher) Fetch(ctx context.Context, spec *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte, revision, dir string) (string, error) {
+	if _, err := g.run(ctx, dir, nil, "init", "--quiet"); err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, dir, credentials, "fetch", "--quiet", "--depth", "1", "--", spec.Git.URL, revision); err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, dir, nil, "checkout", "--quiet", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.FromSlash(spec.Git.Path)), nil
+}
+
+// run runs the git command in the directory. The credentials are passed as an HTTP header through the environment
+// of the git process, so that they never show up in its command line, the URL or the error messages.
+func (g *GitFetcher) run(ctx context.Context, dir string, credentials map[string][]byte, args ...string) (string, error) {
+	gitPath := g.GitPath
+	if gitPath == "" {
+		gitPath = "git"
+	}
+	cmd := exec.CommandContext(ctx, gitPath, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_CONFIG_NOSYSTEM=1", "GIT_PROTOCOL_FROM_USER=0")
+	cmd.Env = append(cmd.Env, g.configEnv(credentials)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// configEnv returns the environment variables which set the git configurations of a command: only the allowed
+// protocols can be used, and the credentials, if any, are sent as a Basic authorization header.
+func (g *GitFetcher) configEnv(credentials map[string][]byte) []string {
+	protocols := g.AllowedProtocols
+	if len(protocols) == 0 {
+		protocols = defaultGitProtocols
+	}
+	configs := [][2]string{{"protocol.allow", "never"}}
+	for _, protocol := range protocols {
+		configs = append(configs, [2]string{"protocol." + protocol + ".allow", "always"})
+	}
+	if len(credentials[usernameKey]) != 0 || len(credentials[passwordKey]) != 0 {
+		auth := base64.StdEncoding.EncodeToString([]byte(string(credentials[usernameKey]) + ":" + string(credentials[passwordKey])))
+		configs = append(configs, [2]string{"http.extraHeader", "Authorization: Basic " + auth})
+	}
+	env := []string{fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(configs))}
+	for i, config := range configs {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", i, config[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", i, config[1]))
+	}
+	return env
+}
+
+func gitRevision(source *placementv1beta1.GitSource) string {
+	if source.Revision == "" {
+		return defaultGitRevision
+	}
+	return source.Revision
+}
@@ -0,0 +1,478 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	ociManifestMediaType        = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType     = "application/vnd.docker.distribution.manifest.v2+json"
+	helmChartConfigMediaType    = "application/vnd.cncf.helm.config.v1+json"
+	helmChartContentMediaType   = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	ociTitleAnnotation          = "org.opencontainers.image.title"
+	orasUnpackAnnotation        = "io.deis.oras.content.unpack"
+	defaultOCITag               = "latest"
+	defaultHelmReleaseNamespace = "default"
+
+	// the sub directories of the source directory where the artifacts are unpacked and rendered.
+	ociBundleDir    = "bundle"
+	helmChartDir    = "chart"
+	helmRenderedDir = "rendered"
+)
+
+// maxArtifactSize is the max total size of the blobs of an artifact, including the unpacked files.
+var maxArtifactSize int64 = 64 * 1024 * 1024
+
+var challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// ociDescriptor describes a blob in a registry.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an OCI image manifest, which is the format of the ORAS bundles, the Helm charts and the cosign
+// signatures.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// OCIFetcher fetches the OCI sources from the registries with the OCI distribution API.
+type OCIFetcher struct {
+	// HTTPClient is the client to connect to the registries. Default: http.DefaultClient.
+	HTTPClient *http.Client
+	// HelmPath is the path of the helm executable which templates the Helm charts. Default: helm in the PATH.
+	HelmPath string
+}
+
+// Resolve returns the digest of the artifact that the tag points to, or the pinned digest.
+func (o *OCIFetcher) Resolve(ctx context.Context, spec *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte) (string, error) {
+	if spec.OCI.Digest != "" {
+		return spec.OCI.Digest, nil
+	}
+	repo, err := o.newRepository(spec.OCI.URL, credentials)
+	if err != nil {
+		return "", err
+	}
+	_, digest, err := repo.getManifest(ctx, ociTag(spec.OCI))
+	return digest, err
+}
+
+// Fetch verifies the artifact of the digest and unpacks it, or templates it when it is a Helm chart, into the
+// directory.
+func (o *OCIFetcher) Fetch(ctx context.Context, spec *placementv1beta1.PlacementSourceSpec, credentials map[string][]byte, revision, dir string) (string, error) {
+	repo, err := o.newRepository(spec.OCI.URL, credentials)
+	if err != nil {
+		return "", err
+	}
+	manifest, digest, err := repo.getManifest(ctx, revision)
+	if err != nil {
+		return "", err
+	}
+	if digest != revision {
+		return "", fmt.Errorf("the digest %s of the artifact does not match %s", digest, revision)
+	}
+	if spec.OCI.Verify != nil {
+		if err := verifySignature(ctx, repo, digest, spec.OCI.Verify.PublicKey); err != nil {
+			return "", err
+		}
+	}
+	if manifest.Config.MediaType == helmChartConfigMediaType {
+		return o.templateChart(ctx, repo, manifest, spec.OCI.Helm, dir)
+	}
+	return unpackBundle(ctx, repo, manifest, spec.OCI.Path, filepath.Join(dir, ociBundleDir))
+}
+
+func (o *OCIFetcher) newRepository(rawURL string, credentials map[string][]byte) (*ociRepository, error) {
+	httpClient := o.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	registry, name, ok := strings.Cut(strings.TrimPrefix(rawURL, "oci://"), "/")
+	if !strings.HasPrefix(rawURL, "oci://") || !ok || registry == "" || name == "" {
+		return nil, fmt.Errorf("invalid OCI repository URL %s", rawURL)
+	}
+	// the same defaults as the docker command line
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	return &ociRepository{
+		client:   httpClient,
+		registry: registry,
+		name:     name,
+		username: string(credentials[usernameKey]),
+		password: string(credentials[passwordKey]),
+	}, nil
+}
+
+// unpackBundle writes the titled layers of an ORAS bundle into the directory and returns the directory of the path.
+func unpackBundle(ctx context.Context, repo *ociRepository, manifest *ociManifest, path, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	budget := maxArtifactSize
+	for _, layer := range manifest.Layers {
+		title := layer.Annotations[ociTitleAnnotation]
+		if title == "" {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(title)) {
+			return "", fmt.Errorf("the title %s of the layer %s is not a local path", title, layer.Digest)
+		}
+		blob, err := repo.getBlob(ctx, layer, budget)
+		if err != nil {
+			return "", err
+		}
+		budget -= int64(len(blob))
+		// oras packs a directory into a gzipped tarball whose entries are prefixed with the directory name
+		if layer.Annotations[orasUnpackAnnotation] == "true" {
+			unpacked, err := untarGzip(bytes.NewReader(blob), dir, budget)
+			if err != nil {
+				return "", fmt.Errorf("failed to unpack the layer %s: %w", title, err)
+			}
+			budget -= unpacked
+			continue
+		}
+		file := filepath.Join(dir, filepath.FromSlash(title))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(file, blob, 0o600); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(path)), nil
+}
+
+// templateChart templates the Helm chart with the helm command line and returns the directory of the rendered
+// manifests.
+func (o *OCIFetcher) templateChart(ctx context.Context, repo *ociRepository, manifest *ociManifest, options *placementv1beta1.HelmTemplateOptions, dir string) (string, error) {
+	var chartLayer *ociDescriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == helmChartContentMediaType {
+			chartLayer = &manifest.Layers[i]
+			break
+		}
+	}
+	if chartLayer == nil {
+		return "", errors.New("the Helm chart artifact has no chart content layer")
+	}
+	blob, err := repo.getBlob(ctx, *chartLayer, maxArtifactSize)
+	if err != nil {
+		return "", err
+	}
+	chartDir := filepath.Join(dir, helmChartDir)
+	if _, err := untarGzip(bytes.NewReader(blob), chartDir, maxArtifactSize-int64(len(blob))); err != nil {
+		return "", fmt.Errorf("failed to unpack the Helm chart: %w", err)
+	}
+	// a chart is packed into a directory named after the chart
+	entries, err := os.ReadDir(chartDir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return "", errors.New("the Helm chart must have exactly one top level directory")
+	}
+	chartName := entries[0].Name()
+
+	releaseName, releaseNamespace := chartName, defaultHelmReleaseNamespace
+	var values map[string]string
+	if options != nil {
+		if options.ReleaseName != "" {
+			releaseName = options.ReleaseName
+		}
+		if options.ReleaseNamespace != "" {
+			releaseNamespace = options.ReleaseNamespace
+		}
+		values = options.Values
+	}
+	args := []string{"template", releaseName, filepath.Join(chartDir, chartName), "--namespace", releaseNamespace, "--include-crds"}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, "--set-string", key+"="+values[key])
+	}
+
+	helmPath := o.HelmPath
+	if helmPath == "" {
+		helmPath = "helm"
+	}
+	cmd := exec.CommandContext(ctx, helmPath, args...)
+	cmd.Env = append(os.Environ(), "HELM_CACHE_HOME="+filepath.Join(dir, ".helm"), "HELM_CONFIG_HOME="+filepath.Join(dir, ".helm"), "HELM_DATA_HOME="+filepath.Join(dir, ".helm"))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("helm template failed: %s", strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("helm template failed: %w", err)
+	}
+	renderedDir := filepath.Join(dir, helmRenderedDir)
+	if err := os.MkdirAll(renderedDir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(renderedDir, "manifests.yaml"), stdout.Bytes(), 0o600); err != nil {
+		return "", err
+	}
+	return renderedDir, nil
+}
+
+// untarGzip unpacks the regular files and the directories of a gzipped tarball into the directory and returns the
+// total size of the files. The other entries, e.g. the symbolic links, are skipped.
+func untarGzip(r io.Reader, dir string, limit int64) (int64, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	var total int64
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		name := filepath.FromSlash(strings.TrimPrefix(header.Name, "./"))
+		if name == "" || name == "." {
+			continue
+		}
+		if !filepath.IsLocal(name) {
+			return total, fmt.Errorf("the entry %s is not a local path", header.Name)
+		}
+		path := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return total, err
+			}
+		case tar.TypeReg:
+			if total+header.Size > limit {
+				return total, fmt.Errorf("the artifact exceeds the size limit %d bytes", maxArtifactSize)
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return total, err
+			}
+			content, err := io.ReadAll(io.LimitReader(tr, header.Size))
+			if err != nil {
+				return total, err
+			}
+			if err := os.WriteFile(path, content, 0o600); err != nil {
+				return total, err
+			}
+			total += int64(len(content))
+		}
+	}
+}
+
+// ociRepository is a repository in a registry, which supports the anonymous, basic and bearer token authentication.
+type ociRepository struct {
+	client   *http.Client
+	registry string
+	name     string
+	username string
+	password string
+	// useBasic is set when the registry challenges for the basic authentication.
+	useBasic bool
+	// token is the bearer token issued by the token service of the registry.
+	token string
+}
+
+// getManifest returns the manifest of the reference, i.e. a tag or a digest, and the digest of the manifest.
+func (r *ociRepository) getManifest(ctx context.Context, reference string) (*ociManifest, string, error) {
+	resp, err := r.get(ctx, "/manifests/"+reference, ociManifestMediaType+", "+dockerManifestMediaType)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	// the manifests are small, and 4MiB is the limit of most registries
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, "", fmt.Errorf("invalid manifest %s:%s: %w", r.name, reference, err)
+	}
+	return &manifest, digest, nil
+}
+
+// getBlob returns the content of the blob after it is verified against the digest.
+func (r *ociRepository) getBlob(ctx context.Context, desc ociDescriptor, limit int64) ([]byte, error) {
+	if desc.Size > limit {
+		return nil, fmt.Errorf("the artifact exceeds the size limit %d bytes", maxArtifactSize)
+	}
+	algorithm, expected, _ := strings.Cut(desc.Digest, ":")
+	if algorithm != "sha256" {
+		return nil, fmt.Errorf("unsupported digest %s of the blob", desc.Digest)
+	}
+	resp, err := r.get(ctx, "/blobs/"+desc.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	blob, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(blob)) > limit {
+		return nil, fmt.Errorf("the artifact exceeds the size limit %d bytes", maxArtifactSize)
+	}
+	if sum := sha256.Sum256(blob); hex.EncodeToString(sum[:]) != expected {
+		return nil, fmt.Errorf("the content of the blob does not match its digest %s", desc.Digest)
+	}
+	return blob, nil
+}
+
+// get sends a GET request to the repository, and authenticates once when the registry challenges it.
+func (r *ociRepository) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	endpoint := fmt.Sprintf("https://%s/v2/%s%s", r.registry, r.name, path)
+	resp, err := r.do(ctx, endpoint, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && !r.useBasic && r.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := r.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = r.do(ctx, endpoint, accept); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s returned %s", endpoint, resp.Status)
+	}
+	return resp, nil
+}
+
+func (r *ociRepository) do(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	switch {
+	case r.token != "":
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	case r.useBasic:
+		req.SetBasicAuth(r.username, r.password)
+	}
+	return r.client.Do(req)
+}
+
+// authenticate answers the challenge of the registry, getting a bearer token from its token service if needed.
+func (r *ociRepository) authenticate(ctx context.Context, challenge string) error {
+	scheme, paramString, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if r.username == "" && r.password == "" {
+			return fmt.Errorf("the registry %s requires credentials", r.registry)
+		}
+		r.useBasic = true
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("unsupported authentication challenge %q of the registry %s", challenge, r.registry)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(paramString, -1) {
+		params[match[1]] = match[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return fmt.Errorf("invalid token realm %q of the registry %s", params["realm"], r.registry)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", r.name)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if r.username != "" || r.password != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the token service of the registry %s returned %s", r.registry, resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&token); err != nil {
+		return fmt.Errorf("invalid token of the registry %s: %w", r.registry, err)
+	}
+	r.token = token.Token
+	if r.token == "" {
+		r.token = token.AccessToken
+	}
+	if r.token == "" {
+		return fmt.Errorf("the token service of the registry %s returned no token", r.registry)
+	}
+	return nil
+}
+
+func ociTag(source *placementv1beta1.OCISource) string {
+	if source.Tag == "" {
+		return defaultOCITag
+	}
+	return source.Tag
+}
@@ -0,0 +1,323 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testRepository = "fleet/app"
+	testToken      = "test-token"
+)
+
+// testRegistry is an in-memory registry which serves one repository and requires a bearer token when the
+// credentials are set.
+type testRegistry struct {
+	server      *httptest.Server
+	manifests   map[string][]byte
+	blobs       map[string][]byte
+	credentials map[string][]byte
+}
+
+func newTestRegistry(t *testing.T) *testRegistry {
+	r := &testRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		username, password, _ := req.BasicAuth()
+		if username != string(r.credentials[usernameKey]) || password != string(r.credentials[passwordKey]) ||
+			req.URL.Query().Get("scope") != "repository:"+testRepository+":pull" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+	})
+	mux.HandleFunc("/v2/"+testRepository+"/", func(w http.ResponseWriter, req *http.Request) {
+		if r.credentials != nil && req.Header.Get("Authorization") != "Bearer "+testToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		kind, reference, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/"+testRepository+"/"), "/")
+		var content []byte
+		switch kind {
+		case "manifests":
+			content = r.manifests[reference]
+		case "blobs":
+			content = r.blobs[reference]
+		}
+		if content == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	})
+	r.server = httptest.NewTLSServer(mux)
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *testRegistry) url() string {
+	return "oci://" + strings.TrimPrefix(r.server.URL, "https://") + "/" + testRepository
+}
+
+func (r *testRegistry) fetcher() *OCIFetcher {
+	return &OCIFetcher{HTTPClient: r.server.Client()}
+}
+
+func (r *testRegistry) pushBlob(mediaType string, content []byte, annotations map[string]string) ociDescriptor {
+	digest := sha256Digest(content)
+	r.blobs[digest] = content
+	return ociDescriptor{MediaType: mediaType, Digest: digest, Size: int64(len(content)), Annotations: annotations}
+}
+
+// pushManifest pushes the manifest with the tag and returns its digest.
+func (r *testRegistry) pushManifest(t *testing.T, tag string, manifest ociManifest) string {
+	manifest.MediaType = ociManifestMediaType
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("failed to marshal the manifest: %v", err)
+	}
+	digest := sha256Digest(content)
+	r.manifests[tag] = content
+	r.manifests[digest] = content
+	return digest
+}
+
+// pushBundle pushes an ORAS bundle with a file and a directory.
+func (r *testRegistry) pushBundle(t *testing.T, tag string) string {
+	config := r.pushBlob("application/vnd.oci.image.config.v1+json", []byte("{}"), nil)
+	file := r.pushBlob("application/vnd.oci.image.layer.v1.tar", []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n"),
+		map[string]string{ociTitleAnnotation: "app/namespace.yaml"})
+	dir := r.pushBlob("application/vnd.oci.image.layer.v1.tar+gzip", tarGzip(t, map[string]string{
+		"app/config/config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  namespace: app\n",
+	}), map[string]string{ociTitleAnnotation: "app/config", orasUnpackAnnotation: "true"})
+	return r.pushManifest(t, tag, ociManifest{Config: config, Layers: []ociDescriptor{file, dir}})
+}
+
+// sign pushes a cosign signature of the digest signed by the key.
+func (r *testRegistry) sign(t *testing.T, digest string, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, testRepository, digest))
+	payloadDigest := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, payloadDigest[:])
+	if err != nil {
+		t.Fatalf("failed to sign the payload: %v", err)
+	}
+	config := r.pushBlob("application/vnd.oci.image.config.v1+json", []byte("{}"), nil)
+	layer := r.pushBlob(cosignPayloadMediaType, payload, map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)})
+	r.pushManifest(t, strings.Replace(digest, ":", "-", 1)+cosignSignatureTagSuffix, ociManifest{Config: config, Layers: []ociDescriptor{layer}})
+}
+
+func sha256Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func tarGzip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("failed to write the tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write the tar content: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close the tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to close the gzip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newSigningKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal the public key: %v", err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestOCIFetcherResolve(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.credentials = map[string][]byte{usernameKey: []byte("user"), passwordKey: []byte("password")}
+	digest := registry.pushBundle(t, "v1")
+	pinned := "sha256:" + strings.Repeat("a", 64)
+	tests := map[string]struct {
+		source      placementv1beta1.OCISource
+		credentials map[string][]byte
+		want        string
+		wantErr     bool
+	}{
+		"tag": {
+			source:      placementv1beta1.OCISource{Tag: "v1"},
+			credentials: registry.credentials,
+			want:        digest,
+		},
+		"pinned digest": {
+			source: placementv1beta1.OCISource{Tag: "v1", Digest: pinned},
+			want:   pinned,
+		},
+		"unknown tag": {
+			source:      placementv1beta1.OCISource{Tag: "v2"},
+			credentials: registry.credentials,
+			wantErr:     true,
+		},
+		"invalid credentials": {
+			source:      placementv1beta1.OCISource{Tag: "v1"},
+			credentials: map[string][]byte{usernameKey: []byte("user"), passwordKey: []byte("wrong")},
+			wantErr:     true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.source.URL = registry.url()
+			spec := &placementv1beta1.PlacementSourceSpec{OCI: &tc.source}
+			got, err := registry.fetcher().Resolve(context.Background(), spec, tc.credentials)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Resolve() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Resolve() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOCIFetcherFetchBundle(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest := registry.pushBundle(t, "v1")
+	key, publicKey := newSigningKey(t)
+	_, otherPublicKey := newSigningKey(t)
+	registry.sign(t, digest, key)
+	unsignedDigest := registry.pushManifest(t, "unsigned", ociManifest{Layers: []ociDescriptor{}})
+	tests := map[string]struct {
+		revision string
+		verify   *placementv1beta1.OCISignatureVerification
+		want     []string
+		wantErr  bool
+	}{
+		"unverified bundle": {
+			revision: digest,
+			want: []string{
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`,
+				`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`,
+			},
+		},
+		"verified bundle": {
+			revision: digest,
+			verify:   &placementv1beta1.OCISignatureVerification{PublicKey: publicKey},
+			want: []string{
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`,
+				`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`,
+			},
+		},
+		"bundle signed by another key": {
+			revision: digest,
+			verify:   &placementv1beta1.OCISignatureVerification{PublicKey: otherPublicKey},
+			wantErr:  true,
+		},
+		"unsigned bundle": {
+			revision: unsignedDigest,
+			verify:   &placementv1beta1.OCISignatureVerification{PublicKey: publicKey},
+			wantErr:  true,
+		},
+		"unknown digest": {
+			revision: "sha256:" + strings.Repeat("a", 64),
+			wantErr:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &placementv1beta1.PlacementSourceSpec{OCI: &placementv1beta1.OCISource{URL: registry.url(), Path: "app", Verify: tc.verify}}
+			dir := t.TempDir()
+			manifestDir, err := registry.fetcher().Fetch(context.Background(), spec, nil, tc.revision, dir)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Fetch() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, renderedStrings(t, manifestDir)); diff != "" {
+				t.Errorf("fetched manifests mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOCIFetcherFetchDigestMismatch(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.pushBundle(t, "v1")
+	// the registry serves another manifest for the pinned digest
+	pinned := "sha256:" + strings.Repeat("b", 64)
+	registry.manifests[pinned] = registry.manifests["v1"]
+	spec := &placementv1beta1.PlacementSourceSpec{OCI: &placementv1beta1.OCISource{URL: registry.url(), Digest: pinned}}
+	if _, err := registry.fetcher().Fetch(context.Background(), spec, nil, pinned, t.TempDir()); err == nil {
+		t.Errorf("Fetch() = nil, want error for the digest mismatch")
+	}
+}
+
+func TestOCIFetcherFetchHelmChart(t *testing.T) {
+	if _, err := exec.LookPath("helm"); err != nil {
+		t.Skip("helm is not installed")
+	}
+	registry := newTestRegistry(t)
+	config := registry.pushBlob(helmChartConfigMediaType, []byte(`{"name":"app","version":"0.1.0"}`), nil)
+	chart := registry.pushBlob(helmChartContentMediaType, tarGzip(t, map[string]string{
+		"app/Chart.yaml":            "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"app/templates/config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n  namespace: {{ .Release.Namespace }}\ndata:\n  tag: {{ .Values.image.tag | quote }}\n",
+	}), nil)
+	digest := registry.pushManifest(t, "0.1.0", ociManifest{Config: config, Layers: []ociDescriptor{chart}})
+	spec := &placementv1beta1.PlacementSourceSpec{OCI: &placementv1beta1.OCISource{
+		URL:  registry.url(),
+		Helm: &placementv1beta1.HelmTemplateOptions{ReleaseNamespace: "app", Values: map[string]string{"image.tag": "1.0"}},
+	}}
+	manifestDir, err := registry.fetcher().Fetch(context.Background(), spec, nil, digest, t.TempDir())
+	if err != nil {
+		t.Fatalf("Fetch() = %v, want nil", err)
+	}
+	want := []string{`{"apiVersion":"v1","data":{"tag":"1.0"},"kind":"ConfigMap","metadata":{"name":"app","namespace":"app"}}`}
+	if diff := cmp.Diff(want, renderedStrings(t, manifestDir)); diff != "" {
+		t.Errorf("templated manifests mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUntarGzipRejectsNonLocalPaths(t *testing.T) {
+	archive := tarGzip(t, map[string]string{"../escape.yaml": "kind: ConfigMap\n"})
+	if _, err := untarGzip(bytes.NewReader(archive), t.TempDir(), maxArtifactSize); err == nil {
+		t.Errorf("untarGzip() = nil, want error for a path outside of the directory")
+	}
+}
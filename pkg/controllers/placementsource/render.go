@@ -0,0 +1,129 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// kustomizeGroup is the API group of the kustomization files, which are not Kubernetes resources.
+	kustomizeGroup = "kustomize.config.k8s.io"
+)
+
+// maxRenderedSize is the max total size of the rendered resources, which are stored in the status of the placement
+// source and must fit in the size limit of an object in etcd.
+var maxRenderedSize = 1024 * 1024
+
+// renderManifests renders all the YAML and JSON files under the directory in the lexical order of their paths. A
+// file can contain multiple YAML documents. Symbolic links and other non-regular files are skipped, so that a
+// source cannot pull in the files outside of the directory.
+func renderManifests(dir string) ([]placementv1beta1.ResourceContent, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	var resources []placementv1beta1.ResourceContent
+	totalSize := 0
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if !isWithin(root, path) {
+			return controller.NewUserError(fmt.Errorf("the manifest file %s is outside of the source", rel))
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rendered, err := renderFile(content)
+		if err != nil {
+			return controller.NewUserError(fmt.Errorf("invalid manifest file %s: %w", rel, err))
+		}
+		for i := range rendered {
+			totalSize += len(rendered[i].Raw)
+		}
+		resources = append(resources, rendered...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if totalSize > maxRenderedSize {
+		return nil, controller.NewUserError(fmt.Errorf("the rendered resources with total size %d bytes exceed the limit %d bytes", totalSize, maxRenderedSize))
+	}
+	return resources, nil
+}
+
+// renderFile renders the resources in the YAML documents or the JSON object of a file.
+func renderFile(content []byte) ([]placementv1beta1.ResourceContent, error) {
+	var resources []placementv1beta1.ResourceContent
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				return resources, nil
+			}
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		uObj := &unstructured.Unstructured{Object: obj}
+		gvk := uObj.GroupVersionKind()
+		if gvk.Group == kustomizeGroup {
+			continue
+		}
+		if gvk.Kind == "" || gvk.Version == "" || uObj.GetName() == "" {
+			return nil, fmt.Errorf("resource %s %s has no apiVersion, kind or name", gvk, uObj.GetName())
+		}
+		raw, err := uObj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, placementv1beta1.ResourceContent{RawExtension: runtime.RawExtension{Raw: bytes.TrimSpace(raw)}})
+	}
+}
+
+// isWithin tells if the path, with all the symbolic links resolved, is in the root directory.
+func isWithin(root, path string) bool {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, resolved)
+	return err == nil && filepath.IsLocal(rel)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package placementsource
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create the directory of %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func renderedStrings(t *testing.T, dir string) []string {
+	resources, err := renderManifests(dir)
+	if err != nil {
+		t.Fatalf("renderManifests() = %v, want nil", err)
+	}
+	got := make([]string, len(resources))
+	for i := range resources {
+		got[i] = string(resources[i].Raw)
+	}
+	return got
+}
+
+func TestRenderManifests(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"b/namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n",
+		"a/config.yml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+  namespace: app
+---
+# an empty document
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+  namespace: app
+`,
+		"c/role.json":          `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"reader"}}`,
+		"kustomization.yaml":   "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- b\n",
+		"README.md":            "not a manifest",
+		".github/workflow.yml": "name: ci\n",
+	})
+	want := []string{
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"first","namespace":"app"}}`,
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"second","namespace":"app"}}`,
+		`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`,
+		`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"reader"}}`,
+	}
+	if diff := cmp.Diff(want, renderedStrings(t, dir)); diff != "" {
+		t.Errorf("renderManifests() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRenderManifestsInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"resource without a name": {
+			"config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  namespace: app\n",
+		},
+		"resource without a kind": {
+			"config.yaml": "apiVersion: v1\nmetadata:\n  name: config\n",
+		},
+		"malformed YAML": {
+			"config.yaml": "apiVersion: v1\nkind: [\n",
+		},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, files)
+			if _, err := renderManifests(dir); !errors.Is(err, controller.ErrUserError) {
+				t.Errorf("renderManifests() = %v, want user error", err)
+			}
+		})
+	}
+}
+
+func TestRenderManifestsSizeLimit(t *testing.T) {
+	defer func(size int) { maxRenderedSize = size }(maxRenderedSize)
+	maxRenderedSize = 32
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n",
+	})
+	if _, err := renderManifests(dir); !errors.Is(err, controller.ErrUserError) {
+		t.Errorf("renderManifests() = %v, want user error", err)
+	}
+}
+
+func TestRenderSourceDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n",
+	})
+	if _, err := renderSourceDir(dir, filepath.Join(dir, "app")); err != nil {
+		t.Errorf("renderSourceDir() = %v, want nil", err)
+	}
+	if _, err := renderSourceDir(dir, filepath.Join(dir, "..")); !errors.Is(err, controller.ErrUserError) {
+		t.Errorf("renderSourceDir() = %v, want user error for a directory outside of the source", err)
+	}
+}
+
+func TestRenderManifestsSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	writeFiles(t, outside, map[string]string{
+		"secret.yaml": "apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n",
+	})
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n",
+	})
+	for name, target := range map[string]string{
+		"app/secret.yaml":   filepath.Join(outside, "secret.yaml"),
+		"app/outside":       outside,
+		"app/namespace.yml": filepath.Join(dir, "app", "namespace.yaml"),
+		"linked":            outside,
+		"linked-app":        filepath.Join(dir, "app"),
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Fatalf("failed to create the symbolic link %s: %v", name, err)
+		}
+	}
+
+	want := []string{`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`}
+	if diff := cmp.Diff(want, renderedStrings(t, filepath.Join(dir, "app"))); diff != "" {
+		t.Errorf("renderManifests() mismatch (-want, +got):\n%s", diff)
+	}
+	if _, err := renderSourceDir(dir, filepath.Join(dir, "linked")); !errors.Is(err, controller.ErrUserError) {
+		t.Errorf("renderSourceDir() = %v, want user error for a symbolic link out of the source", err)
+	}
+	if _, err := renderSourceDir(dir, filepath.Join(dir, "linked-app")); err != nil {
+		t.Errorf("renderSourceDir() = %v, want nil for a symbolic link in the source", err)
+	}
+}
@@ -18,10 +18,10 @@ import (
 
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+	"go.goms.io/fleet/pkg/fleettest"
 	"go.goms.io/fleet/pkg/utils/controller"
 	"go.goms.io/fleet/pkg/utils/keys"
 	"go.goms.io/fleet/pkg/utils/validator"
-	"go.goms.io/fleet/test/utils/informer"
 )
 
 var _ controller.Controller = &fakeController{}
@@ -41,6 +41,10 @@ func (w *fakeController) Enqueue(obj interface{}) {
 	w.QueueObj = append(w.QueueObj, obj.(string))
 }
 
+func (w *fakeController) EnqueueWithPriority(obj interface{}, _ controller.Priority) {
+	w.Enqueue(obj)
+}
+
 func TestFindPlacementsSelectedDeletedResV1Alpha1(t *testing.T) {
 	deletedRes := fleetv1alpha1.ResourceIdentifier{
 		Group:     "abc",
@@ -633,7 +637,7 @@ func TestCollectAllAffectedPlacementsV1Alpha1(t *testing.T) {
 				crpList = append(crpList, &unstructured.Unstructured{Object: uMap})
 			}
 			uRes, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(tt.res)
-			validator.ResourceInformer = &informer.FakeManager{}
+			validator.ResourceInformer = &fleettest.FakeInformerManager{}
 			got := collectAllAffectedPlacementsV1Alpha1(&unstructured.Unstructured{Object: uRes}, crpList)
 			if !reflect.DeepEqual(got, tt.wantCrp) {
 				t.Errorf("test case `%s` got = %v, wantResult %v", name, got, tt.wantCrp)
@@ -1001,7 +1005,7 @@ func TestCollectAllAffectedPlacementsV1Beta1(t *testing.T) {
 				crpList = append(crpList, &unstructured.Unstructured{Object: uMap})
 			}
 			uRes, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(tt.res)
-			validator.ResourceInformer = &informer.FakeManager{}
+			validator.ResourceInformer = &fleettest.FakeInformerManager{}
 			got := collectAllAffectedPlacementsV1Beta1(&unstructured.Unstructured{Object: uRes}, crpList)
 			if !reflect.DeepEqual(got, tt.wantCrp) {
 				t.Errorf("test case `%s` got = %v, wantResult %v", name, got, tt.wantCrp)
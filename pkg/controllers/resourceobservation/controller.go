@@ -0,0 +1,260 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package resourceobservation features a controller that observes the resources selected by the cluster resource
+// observations on the member clusters, through the works which carry the observations to the member agents, and
+// aggregates the observed resources reported in the status of the works.
+package resourceobservation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// Reconciler reconciles a cluster resource observation. It creates a work carrying the resource selectors of the
+// observation in the namespace of each observed member cluster, deletes the works of the clusters which are no longer
+// observed, and aggregates the resources reported in the status of the works into the status of the observation.
+type Reconciler struct {
+	Client client.Client
+}
+
+// Reconcile syncs the works of the cluster resource observation and updates its status.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("ClusterResourceObservation reconciliation starts", "clusterResourceObservation", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ClusterResourceObservation reconciliation ends", "clusterResourceObservation", req.Name, "latency", latency)
+	}()
+
+	var observation fleetv1beta1.ClusterResourceObservation
+	if err := r.Client.Get(ctx, req.NamespacedName, &observation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the cluster resource observation", "clusterResourceObservation", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !observation.DeletionTimestamp.IsZero() {
+		// the works are garbage collected with the observation which owns them
+		return ctrl.Result{}, nil
+	}
+
+	clusters, err := r.observedClusters(ctx, &observation)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	works, err := r.syncWorks(ctx, &observation, clusters)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := buildObservationStatus(&observation, clusters, works)
+	if equality.Semantic.DeepEqual(observation.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	observation.Status = status
+	if err := r.Client.Status().Update(ctx, &observation); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the cluster resource observation", "clusterResourceObservation", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// observedClusters returns the sorted names of the joined member clusters selected by the observation.
+func (r *Reconciler) observedClusters(ctx context.Context, observation *fleetv1beta1.ClusterResourceObservation) ([]string, error) {
+	selector := labels.Everything()
+	if observation.Spec.ClusterSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(observation.Spec.ClusterSelector); err != nil {
+			// the selector should have been rejected by the API server
+			klog.ErrorS(controller.NewUserError(err), "Invalid cluster selector", "clusterResourceObservation", klog.KObj(observation))
+			return nil, nil
+		}
+	}
+	var clusterList clusterv1beta1.MemberClusterList
+	if err := r.Client.List(ctx, &clusterList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		klog.ErrorS(err, "Failed to list the member clusters", "clusterResourceObservation", klog.KObj(observation))
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	clusters := make([]string, 0, len(clusterList.Items))
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if !cluster.DeletionTimestamp.IsZero() || !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(clusterv1beta1.ConditionTypeMemberClusterJoined)) {
+			continue
+		}
+		clusters = append(clusters, cluster.Name)
+	}
+	sort.Strings(clusters)
+	return clusters, nil
+}
+
+// syncWorks creates or updates the work of the observation on each observed cluster and deletes the works on the other
+// clusters. It returns the works of the observation keyed by the names of the observed clusters.
+func (r *Reconciler) syncWorks(ctx context.Context, observation *fleetv1beta1.ClusterResourceObservation, clusters []string) (map[string]*fleetv1beta1.Work, error) {
+	var workList fleetv1beta1.WorkList
+	if err := r.Client.List(ctx, &workList, client.MatchingLabels{fleetv1beta1.ObservationTrackingLabel: observation.Name}); err != nil {
+		klog.ErrorS(err, "Failed to list the works of the cluster resource observation", "clusterResourceObservation", klog.KObj(observation))
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	observed := make(map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		observed[fmt.Sprintf(utils.NamespaceNameFormat, cluster)] = true
+	}
+	existing := make(map[string]*fleetv1beta1.Work, len(workList.Items))
+	for i := range workList.Items {
+		work := &workList.Items[i]
+		if observed[work.Namespace] {
+			existing[work.Namespace] = work
+			continue
+		}
+		if err := r.Client.Delete(ctx, work); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the work of the cluster which is no longer observed", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+			return nil, controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Deleted the work of the cluster which is no longer observed", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+	}
+
+	works := make(map[string]*fleetv1beta1.Work, len(clusters))
+	for _, cluster := range clusters {
+		namespace := fmt.Sprintf(utils.NamespaceNameFormat, cluster)
+		work := existing[namespace]
+		if work == nil {
+			work = &fleetv1beta1.Work{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf(fleetv1beta1.ObservationWorkNameFmt, observation.Name),
+					Namespace: namespace,
+					Labels: map[string]string{
+						fleetv1beta1.ObservationTrackingLabel: observation.Name,
+						// the finalizer of the work is removed by the hub agent when the member cluster leaves
+						utils.LabelFleetObj: utils.LabelFleetObjValue,
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						*metav1.NewControllerRef(observation, fleetv1beta1.GroupVersion.WithKind(fleetv1beta1.ClusterResourceObservationKind)),
+					},
+				},
+				Spec: fleetv1beta1.WorkSpec{Observations: observation.Spec.ResourceSelectors},
+			}
+			if err := r.Client.Create(ctx, work); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					// the work is either not created by the observation or not in the cache yet; it is left alone
+					// until the next reconciliation
+					klog.ErrorS(err, "A work of the same name exists on the cluster", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+					continue
+				}
+				klog.ErrorS(err, "Failed to create the work of the observed cluster", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+				return nil, controller.NewAPIServerError(false, err)
+			}
+			klog.V(2).InfoS("Created the work of the observed cluster", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+		} else if !equality.Semantic.DeepEqual(work.Spec.Observations, observation.Spec.ResourceSelectors) {
+			work.Spec.Observations = observation.Spec.ResourceSelectors
+			if err := r.Client.Update(ctx, work); err != nil {
+				klog.ErrorS(err, "Failed to update the work of the observed cluster", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+				return nil, controller.NewUpdateIgnoreConflictError(err)
+			}
+			klog.V(2).InfoS("Updated the work of the observed cluster", "clusterResourceObservation", klog.KObj(observation), "work", klog.KObj(work))
+		}
+		works[cluster] = work
+	}
+	return works, nil
+}
+
+// buildObservationStatus aggregates the resources reported in the status of the works into the status of the
+// observation. The resources of a work are only reported once the member agent observes its latest generation.
+func buildObservationStatus(observation *fleetv1beta1.ClusterResourceObservation, clusters []string, works map[string]*fleetv1beta1.Work) fleetv1beta1.ClusterResourceObservationStatus {
+	status := fleetv1beta1.ClusterResourceObservationStatus{
+		ObservedGeneration: observation.Generation,
+	}
+	for _, cluster := range clusters {
+		clusterStatus := fleetv1beta1.ClusterObservationStatus{ClusterName: cluster}
+		work := works[cluster]
+		if work != nil {
+			observedCond := meta.FindStatusCondition(work.Status.Conditions, fleetv1beta1.WorkConditionTypeObserved)
+			if observedCond != nil && observedCond.ObservedGeneration == work.Generation {
+				clusterStatus.ObservedResourceCount = work.Status.ObservedResourceCount
+				clusterStatus.ObservedResources = work.Status.ObservedResources
+				clusterStatus.Conditions = []metav1.Condition{{
+					Type:               observedCond.Type,
+					Status:             observedCond.Status,
+					Reason:             observedCond.Reason,
+					Message:            observedCond.Message,
+					LastTransitionTime: observedCond.LastTransitionTime,
+					ObservedGeneration: observation.Generation,
+				}}
+			}
+		}
+		status.ObservedResourceCount += clusterStatus.ObservedResourceCount
+		status.Clusters = append(status.Clusters, clusterStatus)
+	}
+	return status
+}
+
+// SetupWithManager sets up the controller with the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the observed clusters change as the clusters join, leave or are relabeled
+	clusterPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, oldOK := e.ObjectOld.(*clusterv1beta1.MemberCluster)
+			newCluster, newOK := e.ObjectNew.(*clusterv1beta1.MemberCluster)
+			if !oldOK || !newOK {
+				return false
+			}
+			joinedType := string(clusterv1beta1.ConditionTypeMemberClusterJoined)
+			return !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
+				meta.IsStatusConditionTrue(oldCluster.Status.Conditions, joinedType) != meta.IsStatusConditionTrue(newCluster.Status.Conditions, joinedType)
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).Named("cluster-resource-observation-controller").
+		For(&fleetv1beta1.ClusterResourceObservation{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the member agents report the observed resources in the status of the works
+		Watches(&fleetv1beta1.Work{}, handler.EnqueueRequestsFromMapFunc(workToObservation)).
+		Watches(&clusterv1beta1.MemberCluster{}, handler.EnqueueRequestsFromMapFunc(r.allObservations), builder.WithPredicates(clusterPredicate)).
+		Complete(r)
+}
+
+// workToObservation maps a work to the observation which creates it, if any.
+func workToObservation(_ context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[fleetv1beta1.ObservationTrackingLabel]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+// allObservations maps a member cluster to all the cluster resource observations.
+func (r *Reconciler) allObservations(ctx context.Context, _ client.Object) []reconcile.Request {
+	var observationList fleetv1beta1.ClusterResourceObservationList
+	if err := r.Client.List(ctx, &observationList); err != nil {
+		klog.ErrorS(err, "Failed to list the cluster resource observations")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(observationList.Items))
+	for i := range observationList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&observationList.Items[i])})
+	}
+	return requests
+}
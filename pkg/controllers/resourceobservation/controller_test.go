@@ -0,0 +1,163 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resourceobservation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+const observationName = "web-inventory"
+
+func newCluster(name string, joined bool, labels map[string]string) *clusterv1beta1.MemberCluster {
+	cluster := &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	status := metav1.ConditionFalse
+	if joined {
+		status = metav1.ConditionTrue
+	}
+	cluster.Status.Conditions = []metav1.Condition{{Type: string(clusterv1beta1.ConditionTypeMemberClusterJoined), Status: status}}
+	return cluster
+}
+
+func newObservationWork(cluster string, generation int64, observed ...fleetv1beta1.ObservedResource) *fleetv1beta1.Work {
+	work := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       fmt.Sprintf(fleetv1beta1.ObservationWorkNameFmt, observationName),
+			Namespace:  fmt.Sprintf(utils.NamespaceNameFormat, cluster),
+			Labels:     map[string]string{fleetv1beta1.ObservationTrackingLabel: observationName},
+			Generation: generation,
+		},
+	}
+	if len(observed) > 0 {
+		work.Status = fleetv1beta1.WorkStatus{
+			Conditions: []metav1.Condition{{
+				Type:               fleetv1beta1.WorkConditionTypeObserved,
+				Status:             metav1.ConditionTrue,
+				Reason:             "ResourcesObserved",
+				ObservedGeneration: generation,
+			}},
+			ObservedResourceCount: len(observed),
+			ObservedResources:     observed,
+		}
+	}
+	return work
+}
+
+func TestReconcile(t *testing.T) {
+	selectors := []fleetv1beta1.ObservedResourceSelector{{Group: "apps", Version: "v1", Kind: "Deployment"}}
+	observation := &fleetv1beta1.ClusterResourceObservation{
+		ObjectMeta: metav1.ObjectMeta{Name: observationName, Generation: 3},
+		Spec: fleetv1beta1.ClusterResourceObservationSpec{
+			ResourceSelectors: selectors,
+			ClusterSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+	}
+	web := fleetv1beta1.ObservedResource{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "app", Name: "web", Health: fleetv1beta1.ObservedResourceHealthy}
+	prod := map[string]string{"env": "prod"}
+	tests := map[string]struct {
+		objects []client.Object
+		// wantWorkClusters are the clusters which have the work of the observation after the reconciliation.
+		wantWorkClusters []string
+		wantStatus       fleetv1beta1.ClusterResourceObservationStatus
+	}{
+		"the works are created on the joined clusters selected": {
+			objects: []client.Object{
+				newCluster("prod-1", true, prod),
+				newCluster("prod-2", false, prod),
+				newCluster("dev-1", true, map[string]string{"env": "dev"}),
+			},
+			wantWorkClusters: []string{"prod-1"},
+			wantStatus: fleetv1beta1.ClusterResourceObservationStatus{
+				ObservedGeneration: 3,
+				Clusters:           []fleetv1beta1.ClusterObservationStatus{{ClusterName: "prod-1"}},
+			},
+		},
+		"the observed resources are aggregated and the works of the clusters no longer observed are deleted": {
+			objects: []client.Object{
+				newCluster("prod-1", true, prod),
+				newCluster("prod-2", true, prod),
+				newCluster("dev-1", true, map[string]string{"env": "dev"}),
+				newObservationWork("prod-1", 1, web),
+				// the resources observed for an earlier generation of the work are not reported
+				newObservationWork("prod-2", 2),
+				newObservationWork("dev-1", 1, web),
+			},
+			wantWorkClusters: []string{"prod-1", "prod-2"},
+			wantStatus: fleetv1beta1.ClusterResourceObservationStatus{
+				ObservedGeneration:    3,
+				ObservedResourceCount: 1,
+				Clusters: []fleetv1beta1.ClusterObservationStatus{
+					{
+						ClusterName:           "prod-1",
+						ObservedResourceCount: 1,
+						ObservedResources:     []fleetv1beta1.ObservedResource{web},
+						Conditions: []metav1.Condition{{
+							Type:               fleetv1beta1.WorkConditionTypeObserved,
+							Status:             metav1.ConditionTrue,
+							Reason:             "ResourcesObserved",
+							ObservedGeneration: 3,
+						}},
+					},
+					{ClusterName: "prod-2"},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tc.objects, observation.DeepCopy())...).
+				WithStatusSubresource(&fleetv1beta1.ClusterResourceObservation{}).Build()
+			r := &Reconciler{Client: fakeClient}
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: observationName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want nil", err)
+			}
+
+			var workList fleetv1beta1.WorkList
+			if err := fakeClient.List(context.Background(), &workList, client.MatchingLabels{fleetv1beta1.ObservationTrackingLabel: observationName}); err != nil {
+				t.Fatalf("List() = %v, want nil", err)
+			}
+			var gotWorkClusters []string
+			for _, work := range workList.Items {
+				gotWorkClusters = append(gotWorkClusters, work.Namespace[len(fmt.Sprintf(utils.NamespaceNameFormat, "")):])
+				if diff := cmp.Diff(selectors, work.Spec.Observations); diff != "" {
+					t.Errorf("Reconcile() work %s observations mismatch (-want, +got):\n%s", work.Namespace, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.wantWorkClusters, gotWorkClusters); diff != "" {
+				t.Errorf("Reconcile() clusters with works mismatch (-want, +got):\n%s", diff)
+			}
+
+			var gotObservation fleetv1beta1.ClusterResourceObservation
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: observationName}, &gotObservation); err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.wantStatus, gotObservation.Status, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("Reconcile() status mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
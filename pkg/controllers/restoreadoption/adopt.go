@@ -0,0 +1,81 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package restoreadoption features the controllers of the hub restore mode. When the hub is restored from a backup,
+// e.g. by Velero, the restored objects get new UIDs, and the owner references of their restored dependents either
+// point to the old UIDs or are stripped. The garbage collector would delete such dependents, e.g. the works, which in
+// turn removes the placed resources from the member clusters. The controllers adopt the dependents by pointing their
+// owner references to the restored owners instead, so that the hub controllers pick up the restored state as is
+// rather than recreating everything.
+package restoreadoption
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// adopt points the owner reference of the object, which has the same group, kind and name as the owner, to the owner.
+// If the object has no such owner reference, it is added when addIfMissing is set. It returns true if the object is
+// changed.
+func adopt(obj client.Object, owner metav1.OwnerReference, addIfMissing bool) bool {
+	ownerGroup := groupOf(owner.APIVersion)
+	refs := obj.GetOwnerReferences()
+	for i := range refs {
+		if groupOf(refs[i].APIVersion) != ownerGroup || refs[i].Kind != owner.Kind || refs[i].Name != owner.Name {
+			continue
+		}
+		if refs[i].UID == owner.UID {
+			return false
+		}
+		refs[i].UID = owner.UID
+		refs[i].APIVersion = owner.APIVersion
+		obj.SetOwnerReferences(refs)
+		return true
+	}
+	if !addIfMissing {
+		return false
+	}
+	obj.SetOwnerReferences(append(refs, owner))
+	return true
+}
+
+func groupOf(apiVersion string) string {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return apiVersion
+	}
+	return gv.Group
+}
+
+// adoptAll adopts the objects by the owner and updates the changed ones.
+func adoptAll(ctx context.Context, c client.Client, owner metav1.OwnerReference, addIfMissing bool, objs ...client.Object) error {
+	for _, obj := range objs {
+		if !adopt(obj, owner, addIfMissing) {
+			continue
+		}
+		if err := c.Update(ctx, obj); err != nil {
+			klog.ErrorS(err, "Failed to adopt a restored object", "owner", owner.Name, "ownerKind", owner.Kind, "object", klog.KObj(obj))
+			return controller.NewUpdateIgnoreConflictError(err)
+		}
+		klog.V(2).InfoS("Adopted a restored object", "owner", owner.Name, "ownerKind", owner.Kind, "ownerUID", owner.UID, "object", klog.KObj(obj), "type", fmt.Sprintf("%T", obj))
+	}
+	return nil
+}
+
+// onCreation only passes the creation of the dependents, which is how the restore brings them back.
+var onCreation = predicate.Funcs{
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package restoreadoption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// PlacementReconciler adopts the restored policy and resource snapshots of a cluster resource placement. The owner
+// references of the bindings, which are optional, are only corrected when they point to the old placement.
+type PlacementReconciler struct {
+	Client client.Client
+}
+
+// Reconcile adopts the dependents of the cluster resource placement.
+func (r *PlacementReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("Restore adoption of the clusterResourcePlacement starts", "clusterResourcePlacement", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("Restore adoption of the clusterResourcePlacement ends", "clusterResourcePlacement", req.Name, "latency", latency)
+	}()
+
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, req.NamespacedName, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if crp.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	owner := metav1.OwnerReference{
+		APIVersion:         placementv1beta1.GroupVersion.String(),
+		Kind:               placementv1beta1.ClusterResourcePlacementKind,
+		Name:               crp.Name,
+		UID:                crp.UID,
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}
+	matchingLabels := client.MatchingLabels{placementv1beta1.CRPTrackingLabel: crp.Name}
+
+	var policySnapshots placementv1beta1.ClusterSchedulingPolicySnapshotList
+	if err := r.Client.List(ctx, &policySnapshots, matchingLabels); err != nil {
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	var resourceSnapshots placementv1beta1.ClusterResourceSnapshotList
+	if err := r.Client.List(ctx, &resourceSnapshots, matchingLabels); err != nil {
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	snapshots := make([]client.Object, 0, len(policySnapshots.Items)+len(resourceSnapshots.Items))
+	for i := range policySnapshots.Items {
+		snapshots = append(snapshots, &policySnapshots.Items[i])
+	}
+	for i := range resourceSnapshots.Items {
+		snapshots = append(snapshots, &resourceSnapshots.Items[i])
+	}
+	if err := adoptAll(ctx, r.Client, owner, true, snapshots...); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var bindings placementv1beta1.ClusterResourceBindingList
+	if err := r.Client.List(ctx, &bindings, matchingLabels); err != nil {
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	objs := make([]client.Object, 0, len(bindings.Items))
+	for i := range bindings.Items {
+		objs = append(objs, &bindings.Items[i])
+	}
+	return ctrl.Result{}, adoptAll(ctx, r.Client, owner, false, objs...)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PlacementReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toPlacement := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		if crpName := obj.GetLabels()[placementv1beta1.CRPTrackingLabel]; crpName != "" {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: crpName}}}
+		}
+		return nil
+	})
+	return ctrl.NewControllerManagedBy(mgr).Named("restore-adoption-placement-controller").
+		For(&placementv1beta1.ClusterResourcePlacement{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&placementv1beta1.ClusterSchedulingPolicySnapshot{}, toPlacement, builder.WithPredicates(onCreation)).
+		Watches(&placementv1beta1.ClusterResourceSnapshot{}, toPlacement, builder.WithPredicates(onCreation)).
+		Watches(&placementv1beta1.ClusterResourceBinding{}, toPlacement, builder.WithPredicates(onCreation)).
+		Complete(r)
+}
+
+// BindingReconciler adopts the restored works of a cluster resource binding.
+type BindingReconciler struct {
+	Client client.Client
+}
+
+// Reconcile adopts the works of the cluster resource binding.
+func (r *BindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("Restore adoption of the clusterResourceBinding starts", "clusterResourceBinding", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("Restore adoption of the clusterResourceBinding ends", "clusterResourceBinding", req.Name, "latency", latency)
+	}()
+
+	var binding placementv1beta1.ClusterResourceBinding
+	if err := r.Client.Get(ctx, req.NamespacedName, &binding); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if binding.DeletionTimestamp != nil || binding.Spec.TargetCluster == "" {
+		return ctrl.Result{}, nil
+	}
+	var works placementv1beta1.WorkList
+	if err := r.Client.List(ctx, &works, client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, binding.Spec.TargetCluster)),
+		client.MatchingLabels{placementv1beta1.ParentBindingLabel: binding.Name}); err != nil {
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	objs := make([]client.Object, 0, len(works.Items))
+	for i := range works.Items {
+		objs = append(objs, &works.Items[i])
+	}
+	owner := metav1.OwnerReference{
+		APIVersion:         placementv1beta1.GroupVersion.String(),
+		Kind:               placementv1beta1.ClusterResourceBindingKind,
+		Name:               binding.Name,
+		UID:                binding.UID,
+		BlockOwnerDeletion: ptr.To(true),
+	}
+	return ctrl.Result{}, adoptAll(ctx, r.Client, owner, true, objs...)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BindingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("restore-adoption-binding-controller").
+		For(&placementv1beta1.ClusterResourceBinding{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&placementv1beta1.Work{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+			if bindingName := obj.GetLabels()[placementv1beta1.ParentBindingLabel]; bindingName != "" {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: bindingName}}}
+			}
+			return nil
+		}), builder.WithPredicates(onCreation)).
+		Complete(r)
+}
+
+// MemberClusterReconciler adopts the restored namespace, internal member cluster, role and role binding of a member
+// cluster.
+type MemberClusterReconciler struct {
+	Client client.Client
+}
+
+// Reconcile adopts the dependents of the member cluster.
+func (r *MemberClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("Restore adoption of the memberCluster starts", "memberCluster", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("Restore adoption of the memberCluster ends", "memberCluster", req.Name, "latency", latency)
+	}()
+
+	var mc clusterv1beta1.MemberCluster
+	if err := r.Client.Get(ctx, req.NamespacedName, &mc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if mc.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	namespaceName := fmt.Sprintf(utils.NamespaceNameFormat, mc.Name)
+	dependents := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}},
+		&clusterv1beta1.InternalMemberCluster{ObjectMeta: metav1.ObjectMeta{Name: mc.Name, Namespace: namespaceName}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(utils.RoleNameFormat, mc.Name), Namespace: namespaceName}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(utils.RoleBindingNameFormat, mc.Name), Namespace: namespaceName}},
+	}
+	objs := make([]client.Object, 0, len(dependents))
+	for _, obj := range dependents {
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				// the member cluster controller creates the missing ones
+				continue
+			}
+			return ctrl.Result{}, controller.NewAPIServerError(true, err)
+		}
+		objs = append(objs, obj)
+	}
+	owner := metav1.OwnerReference{
+		APIVersion: clusterv1beta1.GroupVersion.String(),
+		Kind:       clusterv1beta1.MemberClusterKind,
+		Name:       mc.Name,
+		UID:        mc.UID,
+		Controller: ptr.To(true),
+	}
+	return ctrl.Result{}, adoptAll(ctx, r.Client, owner, true, objs...)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MemberClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	namespacePrefix := fmt.Sprintf(utils.NamespaceNameFormat, "")
+	toMemberCluster := func(namespaceName string) []reconcile.Request {
+		if mcName, ok := strings.CutPrefix(namespaceName, namespacePrefix); ok && mcName != "" {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: mcName}}}
+		}
+		return nil
+	}
+	byNamespace := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		return toMemberCluster(obj.GetNamespace())
+	})
+	return ctrl.NewControllerManagedBy(mgr).Named("restore-adoption-member-cluster-controller").
+		For(&clusterv1beta1.MemberCluster{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+			return toMemberCluster(obj.GetName())
+		}), builder.WithPredicates(onCreation)).
+		Watches(&clusterv1beta1.InternalMemberCluster{}, byNamespace, builder.WithPredicates(onCreation)).
+		Watches(&rbacv1.Role{}, byNamespace, builder.WithPredicates(onCreation)).
+		Watches(&rbacv1.RoleBinding{}, byNamespace, builder.WithPredicates(onCreation)).
+		Complete(r)
+}
@@ -0,0 +1,260 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package restoreadoption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testCRPName     = "app"
+	testBindingName = "app-member-1"
+	testMCName      = "member-1"
+	testNamespace   = "fleet-member-member-1"
+	oldUID          = types.UID("old-uid")
+	newUID          = types.UID("new-uid")
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the client-go scheme: %v", err)
+	}
+	if err := placementv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement scheme: %v", err)
+	}
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the cluster scheme: %v", err)
+	}
+	return scheme
+}
+
+func ownerRef(kind, name string, uid types.UID) metav1.OwnerReference {
+	apiVersion := placementv1beta1.GroupVersion.String()
+	if kind == clusterv1beta1.MemberClusterKind {
+		apiVersion = clusterv1beta1.GroupVersion.String()
+	}
+	return metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: uid}
+}
+
+func TestAdopt(t *testing.T) {
+	owner := ownerRef(placementv1beta1.ClusterResourceBindingKind, testBindingName, newUID)
+	other := ownerRef(placementv1beta1.ClusterResourcePlacementKind, testCRPName, oldUID)
+	tests := map[string]struct {
+		refs         []metav1.OwnerReference
+		addIfMissing bool
+		wantRefs     []metav1.OwnerReference
+		wantChanged  bool
+	}{
+		"stale owner reference": {
+			refs:        []metav1.OwnerReference{other, ownerRef(placementv1beta1.ClusterResourceBindingKind, testBindingName, oldUID)},
+			wantRefs:    []metav1.OwnerReference{other, owner},
+			wantChanged: true,
+		},
+		"stale owner reference of an older version": {
+			refs: []metav1.OwnerReference{{
+				APIVersion: placementv1beta1.GroupVersion.Group + "/v1alpha1",
+				Kind:       placementv1beta1.ClusterResourceBindingKind,
+				Name:       testBindingName,
+				UID:        oldUID,
+			}},
+			wantRefs:    []metav1.OwnerReference{owner},
+			wantChanged: true,
+		},
+		"adopted": {
+			refs:     []metav1.OwnerReference{owner},
+			wantRefs: []metav1.OwnerReference{owner},
+		},
+		"missing owner reference": {
+			refs:         []metav1.OwnerReference{other},
+			addIfMissing: true,
+			wantRefs:     []metav1.OwnerReference{other, owner},
+			wantChanged:  true,
+		},
+		"missing optional owner reference": {
+			refs:     []metav1.OwnerReference{other},
+			wantRefs: []metav1.OwnerReference{other},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			work := &placementv1beta1.Work{ObjectMeta: metav1.ObjectMeta{OwnerReferences: tc.refs}}
+			if got := adopt(work, owner, tc.addIfMissing); got != tc.wantChanged {
+				t.Errorf("adopt() = %v, want %v", got, tc.wantChanged)
+			}
+			if diff := cmp.Diff(tc.wantRefs, work.OwnerReferences); diff != "" {
+				t.Errorf("owner references mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlacementReconcile(t *testing.T) {
+	crp := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: testCRPName, UID: newUID}}
+	labels := map[string]string{placementv1beta1.CRPTrackingLabel: testCRPName}
+	staleRef := ownerRef(placementv1beta1.ClusterResourcePlacementKind, testCRPName, oldUID)
+	policySnapshot := &placementv1beta1.ClusterSchedulingPolicySnapshot{ObjectMeta: metav1.ObjectMeta{
+		Name: testCRPName + "-0", Labels: labels, OwnerReferences: []metav1.OwnerReference{staleRef},
+	}}
+	// Velero strips the owner references of the restored snapshot
+	resourceSnapshot := &placementv1beta1.ClusterResourceSnapshot{ObjectMeta: metav1.ObjectMeta{Name: testCRPName + "-0-snapshot", Labels: labels}}
+	staleBinding := &placementv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{
+		Name: testBindingName, Labels: labels, OwnerReferences: []metav1.OwnerReference{staleRef},
+	}}
+	unownedBinding := &placementv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: testCRPName + "-member-2", Labels: labels}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).
+		WithObjects(crp, policySnapshot, resourceSnapshot, staleBinding, unownedBinding).Build()
+
+	r := &PlacementReconciler{Client: fakeClient}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testCRPName}}); err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	wantRef := metav1.OwnerReference{
+		APIVersion:         placementv1beta1.GroupVersion.String(),
+		Kind:               placementv1beta1.ClusterResourcePlacementKind,
+		Name:               testCRPName,
+		UID:                newUID,
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}
+	tests := map[string]struct {
+		obj      client.Object
+		wantRefs []metav1.OwnerReference
+	}{
+		"policy snapshot with a stale owner reference": {
+			obj:      &placementv1beta1.ClusterSchedulingPolicySnapshot{ObjectMeta: metav1.ObjectMeta{Name: policySnapshot.Name}},
+			wantRefs: []metav1.OwnerReference{ownerRef(placementv1beta1.ClusterResourcePlacementKind, testCRPName, newUID)},
+		},
+		"resource snapshot without owner references": {
+			obj:      &placementv1beta1.ClusterResourceSnapshot{ObjectMeta: metav1.ObjectMeta{Name: resourceSnapshot.Name}},
+			wantRefs: []metav1.OwnerReference{wantRef},
+		},
+		"binding with a stale owner reference": {
+			obj:      &placementv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: staleBinding.Name}},
+			wantRefs: []metav1.OwnerReference{ownerRef(placementv1beta1.ClusterResourcePlacementKind, testCRPName, newUID)},
+		},
+		"binding without owner references": {
+			obj: &placementv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: unownedBinding.Name}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(tc.obj), tc.obj); err != nil {
+				t.Fatalf("failed to get %s: %v", tc.obj.GetName(), err)
+			}
+			if diff := cmp.Diff(tc.wantRefs, tc.obj.GetOwnerReferences()); diff != "" {
+				t.Errorf("owner references mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBindingReconcile(t *testing.T) {
+	binding := &placementv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: testBindingName, UID: newUID},
+		Spec:       placementv1beta1.ResourceBindingSpec{TargetCluster: testMCName},
+	}
+	labels := map[string]string{placementv1beta1.ParentBindingLabel: testBindingName}
+	staleWork := &placementv1beta1.Work{ObjectMeta: metav1.ObjectMeta{
+		Name: testBindingName + "-work", Namespace: testNamespace, Labels: labels,
+		OwnerReferences: []metav1.OwnerReference{ownerRef(placementv1beta1.ClusterResourceBindingKind, testBindingName, oldUID)},
+	}}
+	strippedWork := &placementv1beta1.Work{ObjectMeta: metav1.ObjectMeta{
+		Name: testBindingName + "-configmap-envelope", Namespace: testNamespace, Labels: labels,
+	}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(binding, staleWork, strippedWork).Build()
+
+	r := &BindingReconciler{Client: fakeClient}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testBindingName}}); err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	tests := map[string]struct {
+		name     string
+		wantRefs []metav1.OwnerReference
+	}{
+		"work with a stale owner reference": {
+			name:     staleWork.Name,
+			wantRefs: []metav1.OwnerReference{ownerRef(placementv1beta1.ClusterResourceBindingKind, testBindingName, newUID)},
+		},
+		"work without owner references": {
+			name: strippedWork.Name,
+			wantRefs: []metav1.OwnerReference{{
+				APIVersion:         placementv1beta1.GroupVersion.String(),
+				Kind:               placementv1beta1.ClusterResourceBindingKind,
+				Name:               testBindingName,
+				UID:                newUID,
+				BlockOwnerDeletion: ptr.To(true),
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var work placementv1beta1.Work
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: tc.name, Namespace: testNamespace}, &work); err != nil {
+				t.Fatalf("failed to get work %s: %v", tc.name, err)
+			}
+			if diff := cmp.Diff(tc.wantRefs, work.OwnerReferences); diff != "" {
+				t.Errorf("owner references mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMemberClusterReconcile(t *testing.T) {
+	mc := &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: testMCName, UID: newUID}}
+	staleRefs := []metav1.OwnerReference{{
+		APIVersion: clusterv1beta1.GroupVersion.String(),
+		Kind:       clusterv1beta1.MemberClusterKind,
+		Name:       testMCName,
+		UID:        oldUID,
+		Controller: ptr.To(true),
+	}}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace, OwnerReferences: staleRefs}}
+	imc := &clusterv1beta1.InternalMemberCluster{ObjectMeta: metav1.ObjectMeta{Name: testMCName, Namespace: testNamespace, OwnerReferences: staleRefs}}
+	// the role binding is not restored yet
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "fleet-role-" + testMCName, Namespace: testNamespace}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(mc, namespace, imc, role).Build()
+
+	r := &MemberClusterReconciler{Client: fakeClient}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: testMCName}}); err != nil {
+		t.Fatalf("Reconcile() = %v, want nil", err)
+	}
+	wantRefs := []metav1.OwnerReference{{
+		APIVersion: clusterv1beta1.GroupVersion.String(),
+		Kind:       clusterv1beta1.MemberClusterKind,
+		Name:       testMCName,
+		UID:        newUID,
+		Controller: ptr.To(true),
+	}}
+	for _, obj := range []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}},
+		&clusterv1beta1.InternalMemberCluster{ObjectMeta: metav1.ObjectMeta{Name: testMCName, Namespace: testNamespace}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: role.Name, Namespace: testNamespace}},
+	} {
+		if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("failed to get %T %s: %v", obj, obj.GetName(), err)
+		}
+		if diff := cmp.Diff(wantRefs, obj.GetOwnerReferences()); diff != "" {
+			t.Errorf("owner references of %T mismatch (-want, +got):\n%s", obj, diff)
+		}
+	}
+}
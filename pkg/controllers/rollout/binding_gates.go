@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// fleetBindingConditionTypes are the types of the binding conditions written by the fleet controllers; the other
+// conditions of a binding are written by the external controllers, e.g. to open the binding gates.
+var fleetBindingConditionTypes = sets.New(
+	string(fleetv1beta1.ResourceBindingRolloutStarted),
+	string(fleetv1beta1.ResourceBindingOverridden),
+	string(fleetv1beta1.ResourceBindingWorkSynchronized),
+	string(fleetv1beta1.ResourceBindingWorkSyncThrottled),
+	string(fleetv1beta1.ResourceBindingClusterGone),
+	string(fleetv1beta1.ResourceBindingResourcesDeleted),
+	string(fleetv1beta1.ResourceBindingApplied),
+	string(fleetv1beta1.ResourceBindingAvailable),
+)
+
+// closedBindingGates returns the names of the binding gates of the placement at the lifecycle point which are not
+// opened on the binding yet, i.e. the binding does not have a True condition of their names.
+func closedBindingGates(crp *fleetv1beta1.ClusterResourcePlacement, binding *fleetv1beta1.ClusterResourceBinding, point fleetv1beta1.BindingGatePoint) []string {
+	var closed []string
+	for _, gate := range crp.Spec.BindingGates {
+		if gate.Point != point {
+			continue
+		}
+		// the gates are opened once per binding, whichever generation of the binding they are opened on
+		if cond := binding.GetCondition(gate.Name); cond == nil || cond.Status != metav1.ConditionTrue {
+			closed = append(closed, gate.Name)
+		}
+	}
+	return closed
+}
+
+// externalConditionsChangedPredicate triggers the rollout when the conditions of a binding written by the external
+// controllers change, e.g. when a binding gate is opened, as the status changes do not change the generation.
+var externalConditionsChangedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldBinding, oldOK := e.ObjectOld.(*fleetv1beta1.ClusterResourceBinding)
+		newBinding, newOK := e.ObjectNew.(*fleetv1beta1.ClusterResourceBinding)
+		if !oldOK || !newOK {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(externalConditionStatuses(oldBinding), externalConditionStatuses(newBinding))
+	},
+}
+
+// externalConditionStatuses returns the statuses of the binding conditions written by the external controllers keyed
+// by their types.
+func externalConditionStatuses(binding *fleetv1beta1.ClusterResourceBinding) map[string]string {
+	statuses := make(map[string]string)
+	for _, cond := range binding.Status.Conditions {
+		if !fleetBindingConditionTypes.Has(cond.Type) {
+			statuses[cond.Type] = string(cond.Status)
+		}
+	}
+	return statuses
+}
+
+// bindingGatesRemovedPredicate triggers the rollout when a binding gate is removed from a placement, so that the
+// bindings which it holds back are rolled out.
+var bindingGatesRemovedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCRP, oldOK := e.ObjectOld.(*fleetv1beta1.ClusterResourcePlacement)
+		newCRP, newOK := e.ObjectNew.(*fleetv1beta1.ClusterResourcePlacement)
+		if !oldOK || !newOK {
+			return false
+		}
+		remaining := sets.New[fleetv1beta1.PlacementBindingGate](newCRP.Spec.BindingGates...)
+		for _, gate := range oldCRP.Spec.BindingGates {
+			if !remaining.Has(gate) {
+				return true
+			}
+		}
+		return false
+	},
+}
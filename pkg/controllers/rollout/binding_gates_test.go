@@ -0,0 +1,159 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	recordedGate = "example.com/change-recorded"
+	releasedGate = "example.com/change-released"
+)
+
+var bindingGates = []fleetv1beta1.PlacementBindingGate{
+	{Name: recordedGate, Point: fleetv1beta1.BeforeApplyBindingGatePoint},
+	{Name: releasedGate, Point: fleetv1beta1.BeforeUnbindBindingGatePoint},
+}
+
+func withGateCondition(binding *fleetv1beta1.ClusterResourceBinding, gate string, status metav1.ConditionStatus) *fleetv1beta1.ClusterResourceBinding {
+	binding.SetConditions(metav1.Condition{Type: gate, Status: status, Reason: "Test"})
+	return binding
+}
+
+func TestClosedBindingGates(t *testing.T) {
+	crp := &fleetv1beta1.ClusterResourcePlacement{Spec: fleetv1beta1.ClusterResourcePlacementSpec{BindingGates: bindingGates}}
+	tests := map[string]struct {
+		binding *fleetv1beta1.ClusterResourceBinding
+		point   fleetv1beta1.BindingGatePoint
+		want    []string
+	}{
+		"the gate is not opened": {
+			binding: generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1),
+			point:   fleetv1beta1.BeforeApplyBindingGatePoint,
+			want:    []string{recordedGate},
+		},
+		"the gate is opened": {
+			binding: withGateCondition(generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1), recordedGate, metav1.ConditionTrue),
+			point:   fleetv1beta1.BeforeApplyBindingGatePoint,
+		},
+		"the change is vetoed": {
+			binding: withGateCondition(generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1), recordedGate, metav1.ConditionFalse),
+			point:   fleetv1beta1.BeforeApplyBindingGatePoint,
+			want:    []string{recordedGate},
+		},
+		"the gate of another point is opened": {
+			binding: withGateCondition(generateClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster1), recordedGate, metav1.ConditionTrue),
+			point:   fleetv1beta1.BeforeUnbindBindingGatePoint,
+			want:    []string{releasedGate},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := closedBindingGates(crp, tt.binding, tt.point)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("closedBindingGates() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPickBindingsToRollWithBindingGates(t *testing.T) {
+	crp := clusterResourcePlacementForTest("test", createPlacementPolicyForTest(fleetv1beta1.PickAllPlacementType, 0))
+	crp.Spec.BindingGates = bindingGates
+	allBindings := []*fleetv1beta1.ClusterResourceBinding{
+		generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1),
+		withGateCondition(generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster2), recordedGate, metav1.ConditionTrue),
+		generateClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster3),
+		withGateCondition(generateClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster4), releasedGate, metav1.ConditionTrue),
+	}
+	r := Reconciler{Client: fake.NewClientBuilder().WithScheme(serviceScheme(t)).Build()}
+	resourceSnapshot := &fleetv1beta1.ClusterResourceSnapshot{ObjectMeta: metav1.ObjectMeta{Name: "snapshot-1"}}
+
+	toBeUpdated, stale, needRoll, err := r.pickBindingsToRoll(context.Background(), allBindings, resourceSnapshot, crp, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("pickBindingsToRoll() = %v, want nil", err)
+	}
+	if !needRoll {
+		t.Errorf("pickBindingsToRoll() = needRoll false, want true")
+	}
+	var gotUpdated []string
+	for _, b := range toBeUpdated {
+		gotUpdated = append(gotUpdated, b.currentBinding.Spec.TargetCluster)
+	}
+	// the binding of cluster-1 is not bound and the one of cluster-3 is not removed until the gates are opened
+	if diff := cmp.Diff([]string{cluster2, cluster4}, gotUpdated, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("pickBindingsToRoll() toBeUpdatedBindings mismatch (-want, +got):\n%s", diff)
+	}
+	if len(stale) != 1 || stale[0].currentBinding.Spec.TargetCluster != cluster1 {
+		t.Fatalf("pickBindingsToRoll() staleUnselectedBindings = %v, want the binding of %s", stale, cluster1)
+	}
+	if diff := cmp.Diff([]string{recordedGate}, stale[0].closedGates); diff != "" {
+		t.Errorf("pickBindingsToRoll() closed gates mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestExternalConditionsChangedPredicate(t *testing.T) {
+	binding := generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1)
+	tests := map[string]struct {
+		newBinding *fleetv1beta1.ClusterResourceBinding
+		want       bool
+	}{
+		"a gate is opened": {
+			newBinding: withGateCondition(binding.DeepCopy(), recordedGate, metav1.ConditionTrue),
+			want:       true,
+		},
+		"a fleet condition is changed": {
+			newBinding: withGateCondition(binding.DeepCopy(), string(fleetv1beta1.ResourceBindingRolloutStarted), metav1.ConditionTrue),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := externalConditionsChangedPredicate.Update(event.UpdateEvent{ObjectOld: binding, ObjectNew: tt.newBinding})
+			if got != tt.want {
+				t.Errorf("externalConditionsChangedPredicate.Update() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindingGatesRemovedPredicate(t *testing.T) {
+	crp := &fleetv1beta1.ClusterResourcePlacement{Spec: fleetv1beta1.ClusterResourcePlacementSpec{BindingGates: bindingGates}}
+	tests := map[string]struct {
+		newGates []fleetv1beta1.PlacementBindingGate
+		want     bool
+	}{
+		"no gate is removed": {
+			newGates: bindingGates,
+		},
+		"a gate is added": {
+			newGates: append([]fleetv1beta1.PlacementBindingGate{{Name: "example.com/other", Point: fleetv1beta1.BeforeApplyBindingGatePoint}}, bindingGates...),
+		},
+		"a gate is removed": {
+			newGates: bindingGates[1:],
+			want:     true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			newCRP := crp.DeepCopy()
+			newCRP.Spec.BindingGates = tt.newGates
+			got := bindingGatesRemovedPredicate.Update(event.UpdateEvent{ObjectOld: crp, ObjectNew: newCRP})
+			if got != tt.want {
+				t.Errorf("bindingGatesRemovedPredicate.Update() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
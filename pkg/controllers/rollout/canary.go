@@ -0,0 +1,168 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// canaryPlan is the plan of rolling out the latest resource snapshot of a placement with the Canary rollout strategy.
+type canaryPlan struct {
+	// conditions are the desired Canary conditions of the bindings, keyed by the binding names.
+	conditions map[string]metav1.Condition
+	// releasedClusters are the clusters which the latest resource snapshot can be rolled out to; it is nil once the
+	// canary clusters have soaked, when all the clusters are released.
+	releasedClusters sets.Set[string]
+	// soakRemaining is the shortest time left for a canary cluster to finish soaking, if any is soaking.
+	soakRemaining time.Duration
+}
+
+// computeCanaryPlan computes the canary plan of the placement; it returns nil if the placement does not use the
+// Canary rollout strategy.
+//
+// The canary is done once the configured number of the target clusters run the latest resource snapshot and have been
+// available for the soak time, so that the clusters rolled out after the canary do not hold back the rollout again.
+// Until then, the latest resource snapshot is only rolled out to the canary clusters, which are the target clusters
+// already running the latest resource snapshot, then the others in the order of their names.
+func computeCanaryPlan(crp *fleetv1beta1.ClusterResourcePlacement, allBindings []*fleetv1beta1.ClusterResourceBinding,
+	latestResourceSnapshot *fleetv1beta1.ClusterResourceSnapshot, now time.Time) *canaryPlan {
+	if crp.Spec.Strategy.Type != fleetv1beta1.CanaryRolloutStrategyType || crp.Spec.Strategy.Canary == nil {
+		return nil
+	}
+	soak := time.Duration(*crp.Spec.Strategy.Canary.SoakSeconds) * time.Second
+	targetBindings := make([]*fleetv1beta1.ClusterResourceBinding, 0, len(allBindings))
+	soakedNumber := 0
+	for _, binding := range allBindings {
+		if (binding.Spec.State != fleetv1beta1.BindingStateScheduled && binding.Spec.State != fleetv1beta1.BindingStateBound) ||
+			!binding.DeletionTimestamp.IsZero() {
+			continue
+		}
+		targetBindings = append(targetBindings, binding)
+		if cond, _ := buildCanaryCondition(binding, latestResourceSnapshot, soak, now); cond.Reason == condition.CanarySucceededReason {
+			soakedNumber++
+		}
+	}
+	plan := &canaryPlan{conditions: make(map[string]metav1.Condition)}
+	if len(targetBindings) == 0 {
+		return plan
+	}
+	canaryNumber, _ := intstr.GetScaledValueFromIntOrPercent(crp.Spec.Strategy.Canary.Clusters, len(targetBindings), true)
+	if canaryNumber < 1 {
+		canaryNumber = 1
+	}
+	if canaryNumber > len(targetBindings) {
+		canaryNumber = len(targetBindings)
+	}
+
+	if soakedNumber >= canaryNumber {
+		// the canary is done, only refresh the conditions of the canary clusters
+		for _, binding := range targetBindings {
+			if binding.GetCondition(string(fleetv1beta1.ResourceBindingCanary)) == nil {
+				continue
+			}
+			cond, _ := buildCanaryCondition(binding, latestResourceSnapshot, soak, now)
+			plan.conditions[binding.Name] = cond
+		}
+		return plan
+	}
+
+	sort.SliceStable(targetBindings, func(i, j int) bool {
+		iLatest := targetBindings[i].Spec.ResourceSnapshotName == latestResourceSnapshot.Name
+		jLatest := targetBindings[j].Spec.ResourceSnapshotName == latestResourceSnapshot.Name
+		if iLatest != jLatest {
+			return iLatest
+		}
+		return targetBindings[i].Spec.TargetCluster < targetBindings[j].Spec.TargetCluster
+	})
+	plan.releasedClusters = sets.New[string]()
+	for _, binding := range targetBindings[:canaryNumber] {
+		cond, remaining := buildCanaryCondition(binding, latestResourceSnapshot, soak, now)
+		plan.conditions[binding.Name] = cond
+		plan.releasedClusters.Insert(binding.Spec.TargetCluster)
+		if remaining > 0 && (plan.soakRemaining == 0 || remaining < plan.soakRemaining) {
+			plan.soakRemaining = remaining
+		}
+	}
+	klog.V(2).InfoS("Rolling out the latest resources to the canary clusters", "clusterResourcePlacement", klog.KObj(crp),
+		"canaryClusters", sets.List(plan.releasedClusters), "soakedNumber", soakedNumber, "canaryNumber", canaryNumber)
+	return plan
+}
+
+// buildCanaryCondition builds the Canary condition of a binding and returns the time left for it to finish soaking.
+func buildCanaryCondition(binding *fleetv1beta1.ClusterResourceBinding, latestResourceSnapshot *fleetv1beta1.ClusterResourceSnapshot,
+	soak time.Duration, now time.Time) (metav1.Condition, time.Duration) {
+	cond := metav1.Condition{
+		Type:               string(fleetv1beta1.ResourceBindingCanary),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: binding.Generation,
+		Reason:             condition.CanaryRollingOutReason,
+		Message:            "The latest resources are rolling out to the canary cluster",
+	}
+	if binding.Spec.State != fleetv1beta1.BindingStateBound || binding.Spec.ResourceSnapshotName != latestResourceSnapshot.Name {
+		return cond, 0
+	}
+	availableCondition := binding.GetCondition(string(fleetv1beta1.ResourceBindingAvailable))
+	if !condition.IsConditionStatusTrue(availableCondition, binding.Generation) {
+		return cond, 0
+	}
+	if elapsed := now.Sub(availableCondition.LastTransitionTime.Time); elapsed < soak {
+		cond.Reason = condition.CanarySoakingReason
+		cond.Message = fmt.Sprintf("The latest resources are available on the canary cluster and need to soak for %d seconds", int(soak.Seconds()))
+		return cond, soak - elapsed
+	}
+	cond.Status = metav1.ConditionTrue
+	cond.Reason = condition.CanarySucceededReason
+	cond.Message = fmt.Sprintf("The latest resources have been available on the canary cluster for %d seconds", int(soak.Seconds()))
+	return cond, 0
+}
+
+// updateCanaryConditions sets the Canary conditions of the canary plan on the bindings and removes them from the other
+// bindings, skipping the bindings which are being updated in this round; they are handled in the next one.
+func (r *Reconciler) updateCanaryConditions(ctx context.Context, allBindings []*fleetv1beta1.ClusterResourceBinding, plan *canaryPlan, skipped sets.Set[string]) error {
+	errs, cctx := errgroup.WithContext(ctx)
+	for _, binding := range allBindings {
+		if skipped.Has(binding.Name) || !binding.DeletionTimestamp.IsZero() {
+			continue
+		}
+		existing := binding.GetCondition(string(fleetv1beta1.ResourceBindingCanary))
+		desired, ok := plan.conditions[binding.Name]
+		switch {
+		case !ok && existing == nil:
+			continue
+		case ok && condition.EqualCondition(existing, &desired):
+			continue
+		}
+		errs.Go(func() error {
+			if err := controller.UpdateStatusWithRetry(cctx, r.Client, binding, func(binding *fleetv1beta1.ClusterResourceBinding) {
+				if ok {
+					binding.SetConditions(desired)
+				} else {
+					meta.RemoveStatusCondition(&binding.Status.Conditions, string(fleetv1beta1.ResourceBindingCanary))
+				}
+			}); err != nil {
+				klog.ErrorS(err, "Failed to update the canary condition of a binding", "clusterResourceBinding", klog.KObj(binding))
+				return controller.NewUpdateIgnoreConflictError(err)
+			}
+			klog.V(2).InfoS("Updated the canary condition of a binding", "clusterResourceBinding", klog.KObj(binding), "canary", ok)
+			return nil
+		})
+	}
+	return errs.Wait()
+}
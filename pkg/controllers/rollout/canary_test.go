@@ -0,0 +1,226 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestComputeCanaryPlan(t *testing.T) {
+	now := time.Now()
+	latestResourceSnapshot := &fleetv1beta1.ClusterResourceSnapshot{ObjectMeta: metav1.ObjectMeta{Name: "test-crp-2-snapshot"}}
+	newCanaryCRP := func(clusters intstr.IntOrString) *fleetv1beta1.ClusterResourcePlacement {
+		return &fleetv1beta1.ClusterResourcePlacement{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-crp"},
+			Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+				Strategy: fleetv1beta1.RolloutStrategy{
+					Type: fleetv1beta1.CanaryRolloutStrategyType,
+					Canary: &fleetv1beta1.CanaryConfig{
+						Clusters:    ptr.To(clusters),
+						SoakSeconds: ptr.To(300),
+					},
+				},
+			},
+		}
+	}
+	// newBinding returns a binding which has been available for the given time; it is not available if the time is 0.
+	newBinding := func(cluster string, state fleetv1beta1.BindingState, snapshotName string, availableFor time.Duration) *fleetv1beta1.ClusterResourceBinding {
+		binding := &fleetv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding-" + cluster, Generation: 1},
+			Spec: fleetv1beta1.ResourceBindingSpec{
+				State:                state,
+				TargetCluster:        cluster,
+				ResourceSnapshotName: snapshotName,
+			},
+		}
+		if availableFor > 0 {
+			binding.Status.Conditions = []metav1.Condition{{
+				Type:               string(fleetv1beta1.ResourceBindingAvailable),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: 1,
+				LastTransitionTime: metav1.NewTime(now.Add(-availableFor)),
+			}}
+		}
+		return binding
+	}
+	withCanaryCondition := func(binding *fleetv1beta1.ClusterResourceBinding) *fleetv1beta1.ClusterResourceBinding {
+		binding.SetConditions(metav1.Condition{
+			Type:   string(fleetv1beta1.ResourceBindingCanary),
+			Status: metav1.ConditionFalse,
+			Reason: condition.CanarySoakingReason,
+		})
+		return binding
+	}
+	tests := map[string]struct {
+		crp                  *fleetv1beta1.ClusterResourcePlacement
+		bindings             []*fleetv1beta1.ClusterResourceBinding
+		wantNil              bool
+		wantReasons          map[string]string
+		wantReleasedClusters sets.Set[string]
+		wantSoakRemaining    time.Duration
+	}{
+		"rolling update strategy": {
+			crp: &fleetv1beta1.ClusterResourcePlacement{
+				Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+					Strategy: fleetv1beta1.RolloutStrategy{Type: fleetv1beta1.RollingUpdateRolloutStrategyType},
+				},
+			},
+			bindings: []*fleetv1beta1.ClusterResourceBinding{newBinding(cluster1, fleetv1beta1.BindingStateScheduled, "", 0)},
+			wantNil:  true,
+		},
+		"new rollout picks the canary clusters by names": {
+			crp: newCanaryCRP(intstr.FromString("50%")),
+			bindings: []*fleetv1beta1.ClusterResourceBinding{
+				newBinding(cluster3, fleetv1beta1.BindingStateScheduled, "", 0),
+				newBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-1-snapshot", time.Hour),
+				newBinding(cluster2, fleetv1beta1.BindingStateBound, "test-crp-1-snapshot", time.Hour),
+			},
+			wantReasons: map[string]string{
+				"binding-" + cluster1: condition.CanaryRollingOutReason,
+				"binding-" + cluster2: condition.CanaryRollingOutReason,
+			},
+			wantReleasedClusters: sets.New(cluster1, cluster2),
+		},
+		"the clusters running the latest snapshot are picked first": {
+			crp: newCanaryCRP(intstr.FromInt(1)),
+			bindings: []*fleetv1beta1.ClusterResourceBinding{
+				newBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-1-snapshot", time.Hour),
+				newBinding(cluster2, fleetv1beta1.BindingStateBound, "test-crp-2-snapshot", time.Minute),
+			},
+			wantReasons: map[string]string{
+				"binding-" + cluster2: condition.CanarySoakingReason,
+			},
+			wantReleasedClusters: sets.New(cluster2),
+			wantSoakRemaining:    4 * time.Minute,
+		},
+		"deleting and unscheduled bindings are not canary clusters": {
+			crp: newCanaryCRP(intstr.FromInt(1)),
+			bindings: []*fleetv1beta1.ClusterResourceBinding{
+				func() *fleetv1beta1.ClusterResourceBinding {
+					binding := newBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-1-snapshot", time.Hour)
+					binding.DeletionTimestamp = &metav1.Time{Time: now}
+					return binding
+				}(),
+				newBinding(cluster2, fleetv1beta1.BindingStateUnscheduled, "test-crp-1-snapshot", time.Hour),
+				newBinding(cluster3, fleetv1beta1.BindingStateScheduled, "", 0),
+			},
+			wantReasons: map[string]string{
+				"binding-" + cluster3: condition.CanaryRollingOutReason,
+			},
+			wantReleasedClusters: sets.New(cluster3),
+		},
+		"the canary clusters have soaked": {
+			crp: newCanaryCRP(intstr.FromInt(1)),
+			bindings: []*fleetv1beta1.ClusterResourceBinding{
+				withCanaryCondition(newBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-2-snapshot", time.Hour)),
+				newBinding(cluster2, fleetv1beta1.BindingStateBound, "test-crp-1-snapshot", time.Hour),
+			},
+			wantReasons: map[string]string{
+				"binding-" + cluster1: condition.CanarySucceededReason,
+			},
+		},
+		"the clusters rolled out after the canary do not hold back the rollout": {
+			crp: newCanaryCRP(intstr.FromInt(1)),
+			bindings: []*fleetv1beta1.ClusterResourceBinding{
+				newBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-2-snapshot", time.Minute),
+				withCanaryCondition(newBinding(cluster2, fleetv1beta1.BindingStateBound, "test-crp-2-snapshot", time.Hour)),
+				newBinding(cluster3, fleetv1beta1.BindingStateBound, "test-crp-1-snapshot", time.Hour),
+			},
+			wantReasons: map[string]string{
+				"binding-" + cluster2: condition.CanarySucceededReason,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := computeCanaryPlan(tc.crp, tc.bindings, latestResourceSnapshot, now)
+			if tc.wantNil {
+				if got != nil {
+					t.Fatalf("computeCanaryPlan() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("computeCanaryPlan() = nil, want a plan")
+			}
+			gotReasons := make(map[string]string, len(got.conditions))
+			for name, cond := range got.conditions {
+				gotReasons[name] = cond.Reason
+			}
+			if diff := cmp.Diff(tc.wantReasons, gotReasons); diff != "" {
+				t.Errorf("computeCanaryPlan() condition reasons mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantReleasedClusters, got.releasedClusters); diff != "" {
+				t.Errorf("computeCanaryPlan() releasedClusters mismatch (-want, +got):\n%s", diff)
+			}
+			if got.soakRemaining != tc.wantSoakRemaining {
+				t.Errorf("computeCanaryPlan() soakRemaining = %v, want %v", got.soakRemaining, tc.wantSoakRemaining)
+			}
+		})
+	}
+}
+
+func TestUpdateCanaryConditions(t *testing.T) {
+	canaryCondition := metav1.Condition{
+		Type:               string(fleetv1beta1.ResourceBindingCanary),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: 1,
+		Reason:             condition.CanaryRollingOutReason,
+	}
+	newBinding := func(name string, conditions ...metav1.Condition) *fleetv1beta1.ClusterResourceBinding {
+		return &fleetv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+			Status:     fleetv1beta1.ResourceBindingStatus{Conditions: conditions},
+		}
+	}
+	bindings := []*fleetv1beta1.ClusterResourceBinding{
+		newBinding("canary"),
+		newBinding("no-longer-canary", canaryCondition),
+		newBinding("updating"),
+	}
+	plan := &canaryPlan{conditions: map[string]metav1.Condition{
+		"canary":   canaryCondition,
+		"updating": canaryCondition,
+	}}
+	objects := make([]client.Object, 0, len(bindings))
+	for _, binding := range bindings {
+		objects = append(objects, binding)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(serviceScheme(t)).WithObjects(objects...).
+		WithStatusSubresource(&fleetv1beta1.ClusterResourceBinding{}).Build()
+	r := Reconciler{Client: fakeClient}
+	if err := r.updateCanaryConditions(context.Background(), bindings, plan, sets.New("updating")); err != nil {
+		t.Fatalf("updateCanaryConditions() got error %v, want nil", err)
+	}
+	want := map[string][]metav1.Condition{
+		"canary":           {canaryCondition},
+		"no-longer-canary": nil,
+		"updating":         nil,
+	}
+	for name, wantConditions := range want {
+		var binding fleetv1beta1.ClusterResourceBinding
+		if err := fakeClient.Get(context.Background(), client.ObjectKey{Name: name}, &binding); err != nil {
+			t.Fatalf("failed to get binding %s: %v", name, err)
+		}
+		if diff := cmp.Diff(wantConditions, binding.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"), cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("binding %s conditions mismatch (-want, +got):\n%s", name, diff)
+		}
+	}
+}
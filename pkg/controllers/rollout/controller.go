@@ -9,7 +9,9 @@ package rollout
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -18,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -33,9 +36,11 @@ import (
 	fleetv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
 	"go.goms.io/fleet/pkg/utils/defaulter"
+	"go.goms.io/fleet/pkg/utils/index"
 	"go.goms.io/fleet/pkg/utils/informer"
 )
 
@@ -49,6 +54,9 @@ type Reconciler struct {
 	// the informer contains the cache for all the resources we need.
 	// to check the resource scope
 	InformerManager informer.Manager
+	// Sharder shards the placements across the replicas of the hub agent if set; the placements of the other replicas
+	// are skipped.
+	Sharder *sharding.Sharder
 }
 
 // Reconcile triggers a single binding reconcile round.
@@ -72,15 +80,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req runtime.Request) (runtim
 		klog.ErrorS(err, "Failed to get clusterResourcePlacement", "clusterResourcePlacement", crpName)
 		return runtime.Result{}, controller.NewAPIServerError(true, err)
 	}
+	if !r.Sharder.OwnsPlacement(&crp) {
+		klog.V(4).InfoS("Ignoring clusterResourcePlacement owned by another shard", "clusterResourcePlacement", crpName)
+		return runtime.Result{}, nil
+	}
 	// check that the crp is not being deleted
 	if crp.DeletionTimestamp != nil {
 		klog.V(2).InfoS("Ignoring clusterResourcePlacement that is being deleted", "clusterResourcePlacement", crpName)
 		return runtime.Result{}, nil
 	}
 
-	// check that it's actually rollingUpdate strategy
+	// check that it's actually rollingUpdate or canary strategy
 	// TODO: support the rollout all at once type of RolloutStrategy
-	if crp.Spec.Strategy.Type != fleetv1beta1.RollingUpdateRolloutStrategyType {
+	if crp.Spec.Strategy.Type != fleetv1beta1.RollingUpdateRolloutStrategyType && crp.Spec.Strategy.Type != fleetv1beta1.CanaryRolloutStrategyType {
 		klog.V(2).InfoS("Ignoring clusterResourcePlacement with non-rolling-update strategy", "clusterResourcePlacement", crpName)
 		return runtime.Result{}, nil
 	}
@@ -113,6 +125,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req runtime.Request) (runtim
 		return runtime.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
+	// leave out the scheduled bindings which may be superseded by a newer scheduling policy, and the unscheduled
+	// bindings which the scheduler has not decided to remove in their current generations
+	allBindings, fenced, err := r.fenceSupersededBindings(ctx, crpName, allBindings)
+	if err != nil {
+		return runtime.Result{}, err
+	}
+
 	// find the latest clusterResourceSnapshot.
 	latestResourceSnapshot, err := r.fetchLatestResourceSnapshot(ctx, crpName)
 	if err != nil {
@@ -131,9 +150,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req runtime.Request) (runtim
 		return runtime.Result{}, err
 	}
 
+	releasedClusters, err := r.fetchReleasedClusters(ctx, &crp, latestResourceSnapshot)
+	if err != nil {
+		return runtime.Result{}, err
+	}
+	// hold back the clusters other than the canary clusters until the canary clusters have soaked
+	canary := computeCanaryPlan(&crp, allBindings, latestResourceSnapshot, time.Now())
+	if canary != nil && canary.releasedClusters != nil {
+		if releasedClusters == nil {
+			releasedClusters = canary.releasedClusters
+		} else {
+			releasedClusters = releasedClusters.Intersection(canary.releasedClusters)
+		}
+	}
+
 	// pick the bindings to be updated according to the rollout plan
 	// staleBoundBindings is a list of "Bound" bindings and are not selected in this round because of the rollout strategy.
-	toBeUpdatedBindings, staleBoundBindings, needRoll, err := r.pickBindingsToRoll(ctx, allBindings, latestResourceSnapshot, &crp, matchedCRO, matchedRO)
+	toBeUpdatedBindings, staleBoundBindings, needRoll, err := r.pickBindingsToRoll(ctx, allBindings, latestResourceSnapshot, &crp, matchedCRO, matchedRO, releasedClusters)
 	if err != nil {
 		klog.ErrorS(err, "Failed to pick the bindings to roll", "clusterResourcePlacement", crpName)
 		return runtime.Result{}, err
@@ -144,7 +177,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req runtime.Request) (runtim
 		// There is a corner case that rollout controller succeeds to update the binding spec to the latest one,
 		// but fails to update the binding conditions when it reconciled it last time.
 		// Here it will correct the binding status just in case this happens last time.
-		return runtime.Result{}, r.checkAndUpdateStaleBindingsStatus(ctx, allBindings)
+		if err := r.checkAndUpdateStaleBindingsStatus(ctx, allBindings); err != nil {
+			return runtime.Result{}, err
+		}
+		if canary != nil {
+			if err := r.updateCanaryConditions(ctx, allBindings, canary, sets.New[string]()); err != nil {
+				return runtime.Result{}, err
+			}
+			if canary.soakRemaining > 0 {
+				// nothing changes on the bindings when the canary clusters finish soaking
+				return runtime.Result{RequeueAfter: canary.soakRemaining}, nil
+			}
+		}
+		if fenced {
+			// the scheduler does not update the bindings after it finishes scheduling the latest policy snapshot
+			return runtime.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		return runtime.Result{}, nil
 	}
 	klog.V(2).InfoS("Picked the bindings to be updated", "clusterResourcePlacement", crpName, "numberOfBindings", len(toBeUpdatedBindings), "numberOfStaleBindings", len(staleBoundBindings))
 
@@ -156,6 +205,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req runtime.Request) (runtim
 	}
 	klog.V(2).InfoS("Successfully updated status of the stale bindings", "clusterResourcePlacement", crpName, "numberOfStaleBindings", len(staleBoundBindings))
 
+	if canary != nil {
+		// the bindings to be updated are left to the next round to avoid conflicting with their updates
+		updating := sets.New[string]()
+		for _, binding := range toBeUpdatedBindings {
+			updating.Insert(binding.currentBinding.Name)
+		}
+		if err := r.updateCanaryConditions(ctx, allBindings, canary, updating); err != nil {
+			return runtime.Result{}, err
+		}
+	}
+
 	// Update all the bindings in parallel according to the rollout plan.
 	// We need to requeue the request regardless if the binding updates succeed or not
 	// to avoid the case that the rollout process stalling because the time based binding readiness does not trigger any event.
@@ -191,12 +251,14 @@ func (r *Reconciler) checkAndUpdateStaleBindingsStatus(ctx context.Context, bind
 // fetchLatestResourceSnapshot lists all the latest clusterResourceSnapshots associated with a CRP and returns the master clusterResourceSnapshot.
 func (r *Reconciler) fetchLatestResourceSnapshot(ctx context.Context, crpName string) (*fleetv1beta1.ClusterResourceSnapshot, error) {
 	var latestResourceSnapshot *fleetv1beta1.ClusterResourceSnapshot
+	crpMatcher := client.MatchingFields{
+		index.ResourceSnapshotCRPField: crpName,
+	}
 	latestResourceLabelMatcher := client.MatchingLabels{
 		fleetv1beta1.IsLatestSnapshotLabel: "true",
-		fleetv1beta1.CRPTrackingLabel:      crpName,
 	}
 	resourceSnapshotList := &fleetv1beta1.ClusterResourceSnapshotList{}
-	if err := r.Client.List(ctx, resourceSnapshotList, latestResourceLabelMatcher); err != nil {
+	if err := r.Client.List(ctx, resourceSnapshotList, crpMatcher, latestResourceLabelMatcher); err != nil {
 		klog.ErrorS(err, "Failed to list the latest clusterResourceSnapshot associated with the clusterResourcePlacement",
 			"clusterResourcePlacement", crpName)
 		return nil, controller.NewAPIServerError(true, err)
@@ -274,6 +336,7 @@ func waitForResourcesToCleanUp(allBindings []*fleetv1beta1.ClusterResourceBindin
 type toBeUpdatedBinding struct {
 	currentBinding *fleetv1beta1.ClusterResourceBinding
 	desiredBinding *fleetv1beta1.ClusterResourceBinding // only valid for scheduled or bound binding
+	closedGates    []string                             // the binding gates which hold back a scheduled binding
 }
 
 func createUpdateInfo(binding *fleetv1beta1.ClusterResourceBinding, crp *fleetv1beta1.ClusterResourcePlacement,
@@ -283,6 +346,7 @@ func createUpdateInfo(binding *fleetv1beta1.ClusterResourceBinding, crp *fleetv1
 	desiredBinding.Spec.ResourceSnapshotName = latestResourceSnapshot.Name
 	// update the resource apply strategy when controller rolls out the new changes
 	desiredBinding.Spec.ApplyStrategy = crp.Spec.Strategy.ApplyStrategy
+	desiredBinding.Spec.Priority = crp.Spec.Priority
 	desiredBinding.Spec.ClusterResourceOverrideSnapshots = cro
 	desiredBinding.Spec.ResourceOverrideSnapshots = ro
 	return toBeUpdatedBinding{
@@ -298,8 +362,11 @@ func createUpdateInfo(binding *fleetv1beta1.ClusterResourceBinding, crp *fleetv1
 // if there are out of sync bindings.
 // Thus, it also returns a bool indicating whether there are out of sync bindings to be rolled to differentiate those
 // two cases.
+// The bindings of the clusters out of the releasedClusters are left out of date as the staged update run of the CRP has
+// not reached them yet, or they are not canary clusters while the canary is in progress; all the clusters are released
+// if releasedClusters is nil.
 func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*fleetv1beta1.ClusterResourceBinding, latestResourceSnapshot *fleetv1beta1.ClusterResourceSnapshot, crp *fleetv1beta1.ClusterResourcePlacement,
-	matchedCROs []*fleetv1alpha1.ClusterResourceOverrideSnapshot, matchedROs []*fleetv1alpha1.ResourceOverrideSnapshot) ([]toBeUpdatedBinding, []toBeUpdatedBinding, bool, error) {
+	matchedCROs []*fleetv1alpha1.ClusterResourceOverrideSnapshot, matchedROs []*fleetv1alpha1.ResourceOverrideSnapshot, releasedClusters sets.Set[string]) ([]toBeUpdatedBinding, []toBeUpdatedBinding, bool, error) {
 	// Those are the bindings that are chosen by the scheduler to be applied to selected clusters.
 	// They include the bindings that are already applied to the clusters and the bindings that are newly selected by the scheduler.
 	schedulerTargetedBinds := make([]*fleetv1beta1.ClusterResourceBinding, 0)
@@ -321,6 +388,11 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 	// Those are the bindings that are candidates to be removed during the rolling phase.
 	removeCandidates := make([]toBeUpdatedBinding, 0)
 
+	// Those are the bindings that are a sub-set of the candidates to be removed but are not ready.
+	// We can safely remove those bindings regardless of the maxNumberToRemove as we won't reduce the total available
+	// number of bindings.
+	notReadyRemoveCandidates := make([]toBeUpdatedBinding, 0)
+
 	// Those are the bindings that are candidates to be updated to latest resources during the rolling phase.
 	updateCandidates := make([]toBeUpdatedBinding, 0)
 
@@ -329,6 +401,13 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 	// minimum AvailableNumber of copies as we won't reduce the total unavailable number of bindings.
 	applyFailedUpdateCandidates := make([]toBeUpdatedBinding, 0)
 
+	// Those are the bindings that are to be updated but are held back because the staged update run or the canary has not reached
+	// their clusters, or the binding gates of the placement are not opened on them yet.
+	heldBackCandidates := make([]toBeUpdatedBinding, 0)
+	isReleased := func(binding *fleetv1beta1.ClusterResourceBinding) bool {
+		return releasedClusters == nil || releasedClusters.Has(binding.Spec.TargetCluster)
+	}
+
 	// calculate the cutoff time for a binding to be applied before so that it can be considered ready
 	readyTimeCutOff := time.Now().Add(-time.Duration(*crp.Spec.Strategy.RollingUpdate.UnavailablePeriodSeconds) * time.Second)
 
@@ -354,10 +433,19 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 				readyBindings = append(readyBindings, binding)
 			}
 			if binding.DeletionTimestamp.IsZero() {
+				if gates := closedBindingGates(crp, binding, fleetv1beta1.BeforeUnbindBindingGatePoint); len(gates) > 0 {
+					// the resources are kept on the cluster until the external controllers open their gates
+					klog.V(3).InfoS("Found an unscheduled binding held back by the binding gates", "clusterResourcePlacement", crpKObj, "binding", bindingKObj, "bindingGates", gates)
+					continue
+				}
 				// it's not been deleted yet, so it is a removal candidate
 				klog.V(3).InfoS("Found a not yet deleted unscheduled binding", "clusterResourcePlacement", crpKObj, "binding", bindingKObj)
 				// The desired binding is nil for the removeCandidates.
-				removeCandidates = append(removeCandidates, toBeUpdatedBinding{currentBinding: binding})
+				if bindingReady {
+					removeCandidates = append(removeCandidates, toBeUpdatedBinding{currentBinding: binding})
+				} else {
+					notReadyRemoveCandidates = append(notReadyRemoveCandidates, toBeUpdatedBinding{currentBinding: binding})
+				}
 			} else if bindingReady {
 				// it is being deleted, it can be removed from the cluster at any time, so it can be unavailable at any time
 				canBeUnavailableBindings = append(canBeUnavailableBindings, binding)
@@ -372,6 +460,18 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 			if err != nil {
 				return nil, nil, false, err
 			}
+			if !isReleased(binding) {
+				klog.V(3).InfoS("Found a scheduled binding held back by the staged update run or the canary", "clusterResourcePlacement", crpKObj, "binding", bindingKObj)
+				heldBackCandidates = append(heldBackCandidates, createUpdateInfo(binding, crp, latestResourceSnapshot, cro, ro))
+				continue
+			}
+			if gates := closedBindingGates(crp, binding, fleetv1beta1.BeforeApplyBindingGatePoint); len(gates) > 0 {
+				klog.V(3).InfoS("Found a scheduled binding held back by the binding gates", "clusterResourcePlacement", crpKObj, "binding", bindingKObj, "bindingGates", gates)
+				updateInfo := createUpdateInfo(binding, crp, latestResourceSnapshot, cro, ro)
+				updateInfo.closedGates = gates
+				heldBackCandidates = append(heldBackCandidates, updateInfo)
+				continue
+			}
 			boundingCandidates = append(boundingCandidates, createUpdateInfo(binding, crp, latestResourceSnapshot, cro, ro))
 
 		case fleetv1beta1.BindingStateBound:
@@ -399,7 +499,10 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 			// The binding needs update if it's not pointing to the latest resource resourceBinding or the overrides.
 			if binding.Spec.ResourceSnapshotName != latestResourceSnapshot.Name || !equality.Semantic.DeepEqual(binding.Spec.ClusterResourceOverrideSnapshots, cro) || !equality.Semantic.DeepEqual(binding.Spec.ResourceOverrideSnapshots, ro) {
 				updateInfo := createUpdateInfo(binding, crp, latestResourceSnapshot, cro, ro)
-				if bindingFailed {
+				if !isReleased(binding) {
+					klog.V(3).InfoS("Found a bound binding held back by the staged update run or the canary", "clusterResourcePlacement", crpKObj, "binding", bindingKObj)
+					heldBackCandidates = append(heldBackCandidates, updateInfo)
+				} else if bindingFailed {
 					// the binding has been applied but failed to apply, we can safely update it to latest resources without affecting max unavailable count
 					applyFailedUpdateCandidates = append(applyFailedUpdateCandidates, updateInfo)
 				} else {
@@ -413,11 +516,12 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 	klog.V(2).InfoS("Calculated the targetNumber", "clusterResourcePlacement", crpKObj,
 		"targetNumber", targetNumber, "readyBindingNumber", len(readyBindings), "canBeUnavailableBindingNumber", len(canBeUnavailableBindings),
 		"canBeReadyBindingNumber", len(canBeReadyBindings), "boundingCandidateNumber", len(boundingCandidates),
-		"removeCandidateNumber", len(removeCandidates), "updateCandidateNumber", len(updateCandidates), "applyFailedUpdateCandidateNumber", len(applyFailedUpdateCandidates))
+		"removeCandidateNumber", len(removeCandidates), "notReadyRemoveCandidateNumber", len(notReadyRemoveCandidates), "updateCandidateNumber", len(updateCandidates), "applyFailedUpdateCandidateNumber", len(applyFailedUpdateCandidates),
+		"heldBackCandidateNumber", len(heldBackCandidates))
 
 	// the list of bindings that are to be updated by this rolling phase
 	toBeUpdatedBindingList := make([]toBeUpdatedBinding, 0)
-	if len(removeCandidates)+len(updateCandidates)+len(boundingCandidates)+len(applyFailedUpdateCandidates) == 0 {
+	if len(removeCandidates)+len(notReadyRemoveCandidates)+len(updateCandidates)+len(boundingCandidates)+len(applyFailedUpdateCandidates)+len(heldBackCandidates) == 0 {
 		return toBeUpdatedBindingList, nil, false, nil
 	}
 
@@ -434,6 +538,13 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 
 	// we can still update the bindings that are failed to apply already regardless of the maxNumberToRemove
 	toBeUpdatedBindingList = append(toBeUpdatedBindingList, applyFailedUpdateCandidates...)
+	// and remove the bindings that are not ready on the clusters which are not selected anymore for the same reason
+	toBeUpdatedBindingList = append(toBeUpdatedBindingList, notReadyRemoveCandidates...)
+	// the ready bindings are removed within the budget in the order of their target clusters, so that the same
+	// clusters are cleaned up first no matter how the bindings are listed
+	sort.SliceStable(removeCandidates, func(i, j int) bool {
+		return removeCandidates[i].currentBinding.Spec.TargetCluster < removeCandidates[j].currentBinding.Spec.TargetCluster
+	})
 
 	// updateCandidateUnselectedIndex stores the last index of the updateCandidate which are not selected to be updated.
 	// The rolloutStarted condition of these elements from this index should be updated.
@@ -471,7 +582,8 @@ func (r *Reconciler) pickBindingsToRoll(ctx context.Context, allBindings []*flee
 		toBeUpdatedBindingList = append(toBeUpdatedBindingList, boundingCandidates[boundingCandidatesUnselectedIndex])
 	}
 
-	staleUnselectedBinding := make([]toBeUpdatedBinding, 0)
+	staleUnselectedBinding := make([]toBeUpdatedBinding, 0, len(heldBackCandidates))
+	staleUnselectedBinding = append(staleUnselectedBinding, heldBackCandidates...)
 	if updateCandidateUnselectedIndex < len(updateCandidates) {
 		staleUnselectedBinding = append(staleUnselectedBinding, updateCandidates[updateCandidateUnselectedIndex:]...)
 	}
@@ -582,8 +694,11 @@ func (r *Reconciler) updateBindings(ctx context.Context, bindings []toBeUpdatedB
 // It reconciles on the CRP when a new resource resourceBinding is created or an existing resource binding is created/updated.
 func (r *Reconciler) SetupWithManager(mgr runtime.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor("rollout-controller")
-	return runtime.NewControllerManagedBy(mgr).Named("rollout-controller").
-		WithOptions(ctrl.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}). // set the max number of concurrent reconciles
+	b := runtime.NewControllerManagedBy(mgr).Named("rollout-controller").
+		WithOptions(ctrl.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles, // set the max number of concurrent reconciles
+			NeedLeaderElection:      sharding.ControllerLeaderElection(r.Sharder),
+		}).
 		Watches(&fleetv1beta1.ClusterResourceSnapshot{}, handler.Funcs{
 			CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
 				klog.V(2).InfoS("Handling a resourceSnapshot create event", "resourceSnapshot", klog.KObj(e.Object))
@@ -607,8 +722,14 @@ func (r *Reconciler) SetupWithManager(mgr runtime.Manager) error {
 				klog.V(2).InfoS("Handling a resourceBinding generic event", "resourceBinding", klog.KObj(e.Object))
 				handleResourceBinding(e.Object, q)
 			},
-		}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+		}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, externalConditionsChangedPredicate))).
+		// roll out the bindings which the binding gates removed from the placement no longer hold back
+		Watches(&fleetv1beta1.ClusterResourcePlacement{}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(bindingGatesRemovedPredicate))
+	if r.Sharder != nil {
+		// rollout the placements that the replica takes over after a rebalance
+		b = b.WatchesRawSource(r.Sharder.Subscribe(&handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
 // handleResourceSnapshot parse the resourceBinding label and annotation and enqueue the CRP name associated with the resource resourceBinding
@@ -681,6 +802,18 @@ func (r *Reconciler) updateStaleBindingsStatus(ctx context.Context, staleBinding
 				"Found a stale binding with unexpected state", "clusterResourceBinding", klog.KObj(binding.currentBinding))
 			continue
 		}
+		if len(binding.closedGates) > 0 {
+			errs.Go(func() error {
+				return r.setRolloutStartedCondition(cctx, binding.currentBinding, metav1.Condition{
+					Type:               string(fleetv1beta1.ResourceBindingRolloutStarted),
+					Status:             metav1.ConditionFalse,
+					ObservedGeneration: binding.currentBinding.Generation,
+					Reason:             condition.RolloutNotStartedYetReason,
+					Message:            fmt.Sprintf("The resources are not applied until the binding gates are opened: %s", strings.Join(binding.closedGates, ", ")),
+				})
+			})
+			continue
+		}
 		errs.Go(func() error {
 			return r.updateBindingStatus(cctx, binding.currentBinding, false)
 		})
@@ -705,8 +838,15 @@ func (r *Reconciler) updateBindingStatus(ctx context.Context, binding *fleetv1be
 			Message:            "Detected the new changes on the resources and started the rollout process",
 		}
 	}
-	binding.SetConditions(cond)
-	if err := r.Client.Status().Update(ctx, binding); err != nil {
+	return r.setRolloutStartedCondition(ctx, binding, cond)
+}
+
+// setRolloutStartedCondition sets the RolloutStarted condition of the binding.
+func (r *Reconciler) setRolloutStartedCondition(ctx context.Context, binding *fleetv1beta1.ClusterResourceBinding, cond metav1.Condition) error {
+	// the other conditions of the binding are written by the work generator, which are kept on conflicts
+	if err := controller.UpdateStatusWithRetry(ctx, r.Client, binding, func(binding *fleetv1beta1.ClusterResourceBinding) {
+		binding.SetConditions(cond)
+	}); err != nil {
 		klog.ErrorS(err, "Failed to update binding status", "clusterResourceBinding", klog.KObj(binding), "condition", cond)
 		return controller.NewUpdateIgnoreConflictError(err)
 	}
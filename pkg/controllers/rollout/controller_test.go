@@ -16,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +38,7 @@ var (
 	cluster2 = "cluster-2"
 	cluster3 = "cluster-3"
 	cluster4 = "cluster-4"
+	cluster6 = "cluster-6"
 	cluster5 = "cluster-5"
 
 	cmpOptions = []cmp.Option{
@@ -763,6 +765,7 @@ func TestPickBindingsToRoll(t *testing.T) {
 		matchedCROs                 []*fleetv1alpha1.ClusterResourceOverrideSnapshot
 		matchedROs                  []*fleetv1alpha1.ResourceOverrideSnapshot
 		clusters                    []clusterv1beta1.MemberCluster
+		releasedClusters            sets.Set[string]
 		wantTobeUpdatedBindings     []int
 		wantDesiredBindingsSpec     []fleetv1beta1.ResourceBindingSpec // used to construct the want toBeUpdatedBindings
 		wantStaleUnselectedBindings []int
@@ -1307,7 +1310,8 @@ func TestPickBindingsToRoll(t *testing.T) {
 			latestResourceSnapshotName: "snapshot-1",
 			crp: clusterResourcePlacementForTest("test",
 				createPlacementPolicyForTest(fleetv1beta1.PickNPlacementType, 4)),
-			wantTobeUpdatedBindings: []int{0, 2},
+			// the unscheduled bindings are not ready so that they are removed regardless of the budget.
+			wantTobeUpdatedBindings: []int{1, 3, 0, 2},
 			// empty list as unscheduled bindings will be removed and are not tracked in the CRP today.
 			wantStaleUnselectedBindings: []int{},
 			wantDesiredBindingsSpec: []fleetv1beta1.ResourceBindingSpec{
@@ -1328,6 +1332,74 @@ func TestPickBindingsToRoll(t *testing.T) {
 			},
 			wantNeedRoll: true,
 		},
+		"test remove unscheduled bindings within the budget in the order of the clusters": {
+			allBindings: []*fleetv1beta1.ClusterResourceBinding{
+				generateAvailableClusterResourceBinding(fleetv1beta1.BindingStateBound, "snapshot-1", cluster1),
+				generateAvailableClusterResourceBinding(fleetv1beta1.BindingStateBound, "snapshot-1", cluster2),
+				generateAvailableClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster5),
+				generateAvailableClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster4),
+				generateAvailableClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster3),
+				generateClusterResourceBinding(fleetv1beta1.BindingStateUnscheduled, "snapshot-1", cluster6),
+			},
+			latestResourceSnapshotName: "snapshot-1",
+			crp: clusterResourcePlacementForTest("test",
+				createPlacementPolicyForTest(fleetv1beta1.PickNPlacementType, 4)),
+			// the not ready binding is removed regardless of the budget, and two of the ready ones are removed
+			// within the budget as at least 3 bindings have to be available.
+			wantTobeUpdatedBindings:     []int{5, 4, 3},
+			wantStaleUnselectedBindings: []int{},
+			wantNeedRoll:                true,
+		},
+		"test with bindings held back by the staged update run": {
+			allBindings: []*fleetv1beta1.ClusterResourceBinding{
+				generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1),
+				generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster2),
+				generateClusterResourceBinding(fleetv1beta1.BindingStateBound, "snapshot-1", cluster3),
+			},
+			latestResourceSnapshotName: "snapshot-2",
+			crp: clusterResourcePlacementForTest("test",
+				createPlacementPolicyForTest(fleetv1beta1.PickAllPlacementType, 0)),
+			releasedClusters:            sets.New(cluster1),
+			wantTobeUpdatedBindings:     []int{0},
+			wantStaleUnselectedBindings: []int{1, 2},
+			wantDesiredBindingsSpec: []fleetv1beta1.ResourceBindingSpec{
+				{
+					State:                fleetv1beta1.BindingStateBound,
+					TargetCluster:        cluster1,
+					ResourceSnapshotName: "snapshot-2",
+				},
+				{
+					State:                fleetv1beta1.BindingStateBound,
+					TargetCluster:        cluster2,
+					ResourceSnapshotName: "snapshot-2",
+				},
+				{
+					State:                fleetv1beta1.BindingStateBound,
+					TargetCluster:        cluster3,
+					ResourceSnapshotName: "snapshot-2",
+				},
+			},
+			wantNeedRoll: true,
+		},
+		"test with all bindings held back by the staged update run": {
+			allBindings: []*fleetv1beta1.ClusterResourceBinding{
+				generateClusterResourceBinding(fleetv1beta1.BindingStateScheduled, "snapshot-1", cluster1),
+			},
+			latestResourceSnapshotName: "snapshot-2",
+			crp: clusterResourcePlacementForTest("test",
+				createPlacementPolicyForTest(fleetv1beta1.PickAllPlacementType, 0)),
+			releasedClusters:            sets.New[string](),
+			wantTobeUpdatedBindings:     []int{},
+			wantStaleUnselectedBindings: []int{0},
+			wantDesiredBindingsSpec: []fleetv1beta1.ResourceBindingSpec{
+				{
+					State:                fleetv1beta1.BindingStateBound,
+					TargetCluster:        cluster1,
+					ResourceSnapshotName: "snapshot-2",
+				},
+			},
+			wantNeedRoll: true,
+		},
 		"test overrides and the cluster is not found": {
 			allBindings: []*fleetv1beta1.ClusterResourceBinding{
 				generateClusterResourceBinding(fleetv1beta1.BindingStateBound, "snapshot-1", cluster1),
@@ -1386,7 +1458,7 @@ func TestPickBindingsToRoll(t *testing.T) {
 					Name: tt.latestResourceSnapshotName,
 				},
 			}
-			gotUpdatedBindings, gotStaleUnselectedBindings, gotNeedRoll, err := r.pickBindingsToRoll(context.Background(), tt.allBindings, resourceSnapshot, tt.crp, tt.matchedCROs, tt.matchedROs)
+			gotUpdatedBindings, gotStaleUnselectedBindings, gotNeedRoll, err := r.pickBindingsToRoll(context.Background(), tt.allBindings, resourceSnapshot, tt.crp, tt.matchedCROs, tt.matchedROs, tt.releasedClusters)
 			if (err != nil) != (tt.wantErr != nil) || err != nil && !errors.Is(err, tt.wantErr) {
 				t.Fatalf("pickBindingsToRoll() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -1397,6 +1469,10 @@ func TestPickBindingsToRoll(t *testing.T) {
 			wantTobeUpdatedBindings := make([]toBeUpdatedBinding, len(tt.wantTobeUpdatedBindings))
 			for i, index := range tt.wantTobeUpdatedBindings {
 				wantTobeUpdatedBindings[i].currentBinding = tt.allBindings[index]
+				if tt.allBindings[index].Spec.State == fleetv1beta1.BindingStateUnscheduled {
+					// the desired binding is nil for the bindings to be removed.
+					continue
+				}
 				wantTobeUpdatedBindings[i].desiredBinding = tt.allBindings[index].DeepCopy()
 				wantTobeUpdatedBindings[i].desiredBinding.Spec = tt.wantDesiredBindingsSpec[index]
 			}
@@ -1486,6 +1562,15 @@ func generateFailedToApplyClusterResourceBinding(state fleetv1beta1.BindingState
 	return binding
 }
 
+func generateAvailableClusterResourceBinding(state fleetv1beta1.BindingState, resourceSnapshotName, targetCluster string) *fleetv1beta1.ClusterResourceBinding {
+	binding := generateClusterResourceBinding(state, resourceSnapshotName, targetCluster)
+	binding.Status.Conditions = append(binding.Status.Conditions, metav1.Condition{
+		Type:   string(fleetv1beta1.ResourceBindingAvailable),
+		Status: metav1.ConditionTrue,
+	})
+	return binding
+}
+
 func TestUpdateStaleBindingsStatus(t *testing.T) {
 	currentTime := time.Now()
 	oldTransitionTime := metav1.NewTime(currentTime.Add(-1 * time.Hour))
@@ -0,0 +1,120 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"strconv"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/annotations"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// fenceSupersededBindings returns the bindings that the rollout controller can act on, and whether any binding is
+// fenced off.
+//
+// The scheduler and the rollout controller run concurrently, so the policy of the placement may change again, e.g.
+// with rapid policy edits, after the scheduler has scheduled a binding and before the rollout controller binds it.
+// A scheduled binding is fenced off and left for the scheduler to schedule again instead of being bound when
+//   - the scheduling policy snapshot which the binding is scheduled with is no longer the latest one; or
+//   - the scheduler has not finished scheduling the latest policy snapshot for the current generation of the placement.
+//
+// The scheduled bindings which do not record their scheduling policy snapshot are not fenced off.
+//
+// An unscheduled binding is also fenced off instead of being removed from its cluster if its generation has advanced
+// past the one which the scheduler recorded as it marked the binding unscheduled, as the binding is no longer in the
+// state the scheduler decided, e.g. it is written by a scheduler which is not running with a consistent view. The
+// fenced off unscheduled bindings do not need a requeue; they are handled again on their next change.
+// The unscheduled bindings which do not record the generation are not fenced off.
+func (r *Reconciler) fenceSupersededBindings(ctx context.Context, crpName string, allBindings []*fleetv1beta1.ClusterResourceBinding) ([]*fleetv1beta1.ClusterResourceBinding, bool, error) {
+	needsFencing := false
+	for _, binding := range allBindings {
+		if (binding.Spec.State == fleetv1beta1.BindingStateScheduled && binding.Spec.SchedulingPolicySnapshotName != "") ||
+			isUnscheduleUnconfirmed(binding) {
+			needsFencing = true
+			break
+		}
+	}
+	if !needsFencing {
+		return allBindings, false, nil
+	}
+
+	latestPolicySnapshot, err := r.fetchLatestPolicySnapshot(ctx, crpName)
+	if err != nil {
+		return nil, false, err
+	}
+	fencedBindings := make([]*fleetv1beta1.ClusterResourceBinding, 0, len(allBindings))
+	fenced := false
+	for _, binding := range allBindings {
+		if isUnscheduleUnconfirmed(binding) {
+			klog.V(2).InfoS("Fenced off an unscheduled binding which has changed since the scheduler unscheduled it", "clusterResourcePlacement", crpName,
+				"clusterResourceBinding", klog.KObj(binding), "generation", binding.Generation,
+				"unscheduledGeneration", binding.GetAnnotations()[fleetv1beta1.UnscheduledGenerationAnnotation])
+			continue
+		}
+		if isBindingSuperseded(binding, latestPolicySnapshot) {
+			klog.V(2).InfoS("Fenced off a scheduled binding which may be superseded by a newer scheduling policy", "clusterResourcePlacement", crpName,
+				"clusterResourceBinding", klog.KObj(binding), "schedulingPolicySnapshot", binding.Spec.SchedulingPolicySnapshotName,
+				"latestSchedulingPolicySnapshot", klog.KObj(latestPolicySnapshot))
+			fenced = true
+			continue
+		}
+		fencedBindings = append(fencedBindings, binding)
+	}
+	return fencedBindings, fenced, nil
+}
+
+// fetchLatestPolicySnapshot returns the latest scheduling policy snapshot of the placement, or nil if there is none,
+// e.g. when a new policy snapshot is being created.
+// The snapshot is read from the API server directly as the bindings are, so that it is at least as new as them.
+func (r *Reconciler) fetchLatestPolicySnapshot(ctx context.Context, crpName string) (*fleetv1beta1.ClusterSchedulingPolicySnapshot, error) {
+	policySnapshotList := &fleetv1beta1.ClusterSchedulingPolicySnapshotList{}
+	if err := r.UncachedReader.List(ctx, policySnapshotList, client.MatchingLabels{
+		fleetv1beta1.CRPTrackingLabel:      crpName,
+		fleetv1beta1.IsLatestSnapshotLabel: strconv.FormatBool(true),
+	}); err != nil {
+		klog.ErrorS(err, "Failed to list the latest clusterSchedulingPolicySnapshot associated with the clusterResourcePlacement",
+			"clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(false, err)
+	}
+	if len(policySnapshotList.Items) != 1 {
+		klog.V(2).InfoS("Cannot find the only latest associated clusterSchedulingPolicySnapshot", "clusterResourcePlacement", crpName,
+			"numberOfSnapshots", len(policySnapshotList.Items))
+		return nil, nil
+	}
+	return &policySnapshotList.Items[0], nil
+}
+
+// isBindingSuperseded tells if the scheduled binding may be superseded by the latest scheduling policy snapshot.
+func isBindingSuperseded(binding *fleetv1beta1.ClusterResourceBinding, latestPolicySnapshot *fleetv1beta1.ClusterSchedulingPolicySnapshot) bool {
+	if binding.Spec.State != fleetv1beta1.BindingStateScheduled || binding.Spec.SchedulingPolicySnapshotName == "" {
+		return false
+	}
+	if latestPolicySnapshot == nil || binding.Spec.SchedulingPolicySnapshotName != latestPolicySnapshot.Name {
+		return true
+	}
+	crpGeneration, err := annotations.ExtractObservedCRPGenerationFromPolicySnapshot(latestPolicySnapshot)
+	if err != nil {
+		klog.ErrorS(controller.NewUnexpectedBehaviorError(err), "Failed to find the generation of the clusterResourcePlacement of the clusterSchedulingPolicySnapshot",
+			"clusterSchedulingPolicySnapshot", klog.KObj(latestPolicySnapshot))
+		return true
+	}
+	return latestPolicySnapshot.Status.ObservedCRPGeneration != crpGeneration
+}
+
+// isUnscheduleUnconfirmed tells if the unscheduled binding records the generation which the scheduler unscheduled it
+// at, and that generation is not the current one of the binding.
+func isUnscheduleUnconfirmed(binding *fleetv1beta1.ClusterResourceBinding) bool {
+	if binding.Spec.State != fleetv1beta1.BindingStateUnscheduled || !binding.DeletionTimestamp.IsZero() {
+		return false
+	}
+	unscheduledGeneration, found := binding.GetAnnotations()[fleetv1beta1.UnscheduledGenerationAnnotation]
+	return found && unscheduledGeneration != strconv.FormatInt(binding.Generation, 10)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func newFencingPolicySnapshot(name string, isLatest bool, crpGeneration string, observedCRPGeneration int64) *fleetv1beta1.ClusterSchedulingPolicySnapshot {
+	latest := "false"
+	if isLatest {
+		latest = "true"
+	}
+	return &fleetv1beta1.ClusterSchedulingPolicySnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				fleetv1beta1.CRPTrackingLabel:      "test-crp",
+				fleetv1beta1.IsLatestSnapshotLabel: latest,
+			},
+			Annotations: map[string]string{fleetv1beta1.CRPGenerationAnnotation: crpGeneration},
+		},
+		Status: fleetv1beta1.SchedulingPolicySnapshotStatus{ObservedCRPGeneration: observedCRPGeneration},
+	}
+}
+
+func newFencingBinding(name string, state fleetv1beta1.BindingState, policySnapshotName string) *fleetv1beta1.ClusterResourceBinding {
+	return &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: "test-crp"},
+		},
+		Spec: fleetv1beta1.ResourceBindingSpec{
+			State:                        state,
+			TargetCluster:                name,
+			SchedulingPolicySnapshotName: policySnapshotName,
+		},
+	}
+}
+
+func newUnscheduledFencingBinding(name string, generation int64, unscheduledGeneration string) *fleetv1beta1.ClusterResourceBinding {
+	binding := newFencingBinding(name, fleetv1beta1.BindingStateUnscheduled, "test-crp-1")
+	binding.Generation = generation
+	if unscheduledGeneration != "" {
+		binding.Annotations = map[string]string{fleetv1beta1.UnscheduledGenerationAnnotation: unscheduledGeneration}
+	}
+	return binding
+}
+
+func TestIsUnscheduleUnconfirmed(t *testing.T) {
+	tests := map[string]struct {
+		binding *fleetv1beta1.ClusterResourceBinding
+		want    bool
+	}{
+		"unscheduled at the current generation": {
+			binding: newUnscheduledFencingBinding(cluster1, 3, "3"),
+		},
+		"changed since it is unscheduled": {
+			binding: newUnscheduledFencingBinding(cluster1, 4, "3"),
+			want:    true,
+		},
+		"unscheduled without the generation": {
+			binding: newUnscheduledFencingBinding(cluster1, 4, ""),
+		},
+		"bound binding": {
+			binding: newFencingBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-1"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isUnscheduleUnconfirmed(tc.binding); got != tc.want {
+				t.Errorf("isUnscheduleUnconfirmed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsBindingSuperseded(t *testing.T) {
+	tests := map[string]struct {
+		binding        *fleetv1beta1.ClusterResourceBinding
+		policySnapshot *fleetv1beta1.ClusterSchedulingPolicySnapshot
+		want           bool
+	}{
+		"scheduled with the latest policy snapshot": {
+			binding:        newFencingBinding(cluster1, fleetv1beta1.BindingStateScheduled, "test-crp-1"),
+			policySnapshot: newFencingPolicySnapshot("test-crp-1", true, "2", 2),
+		},
+		"scheduled with a superseded policy snapshot": {
+			binding:        newFencingBinding(cluster1, fleetv1beta1.BindingStateScheduled, "test-crp-0"),
+			policySnapshot: newFencingPolicySnapshot("test-crp-1", true, "2", 2),
+			want:           true,
+		},
+		"scheduled before the scheduler catches up with the latest placement generation": {
+			binding:        newFencingBinding(cluster1, fleetv1beta1.BindingStateScheduled, "test-crp-1"),
+			policySnapshot: newFencingPolicySnapshot("test-crp-1", true, "3", 2),
+			want:           true,
+		},
+		"scheduled while no policy snapshot is the latest": {
+			binding: newFencingBinding(cluster1, fleetv1beta1.BindingStateScheduled, "test-crp-1"),
+			want:    true,
+		},
+		"scheduled without the policy snapshot": {
+			binding: newFencingBinding(cluster1, fleetv1beta1.BindingStateScheduled, ""),
+		},
+		"bound with a superseded policy snapshot": {
+			binding:        newFencingBinding(cluster1, fleetv1beta1.BindingStateBound, "test-crp-0"),
+			policySnapshot: newFencingPolicySnapshot("test-crp-1", true, "2", 2),
+		},
+		"unscheduled with a superseded policy snapshot": {
+			binding:        newFencingBinding(cluster1, fleetv1beta1.BindingStateUnscheduled, "test-crp-0"),
+			policySnapshot: newFencingPolicySnapshot("test-crp-1", true, "2", 2),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isBindingSuperseded(tc.binding, tc.policySnapshot); got != tc.want {
+				t.Errorf("isBindingSuperseded() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFenceSupersededBindings(t *testing.T) {
+	scheduled := newFencingBinding(cluster1, fleetv1beta1.BindingStateScheduled, "test-crp-1")
+	superseded := newFencingBinding(cluster2, fleetv1beta1.BindingStateScheduled, "test-crp-0")
+	bound := newFencingBinding(cluster3, fleetv1beta1.BindingStateBound, "test-crp-0")
+	tests := map[string]struct {
+		bindings        []*fleetv1beta1.ClusterResourceBinding
+		policySnapshots []client.Object
+		wantBindings    []string
+		wantFenced      bool
+	}{
+		"no scheduled binding": {
+			bindings:     []*fleetv1beta1.ClusterResourceBinding{bound},
+			wantBindings: []string{cluster3},
+		},
+		"the superseded scheduled binding is fenced off": {
+			bindings: []*fleetv1beta1.ClusterResourceBinding{scheduled, superseded, bound},
+			policySnapshots: []client.Object{
+				newFencingPolicySnapshot("test-crp-0", false, "1", 1),
+				newFencingPolicySnapshot("test-crp-1", true, "2", 2),
+			},
+			wantBindings: []string{cluster1, cluster3},
+			wantFenced:   true,
+		},
+		"the unscheduled binding changed since it is unscheduled is fenced off": {
+			bindings: []*fleetv1beta1.ClusterResourceBinding{
+				bound,
+				newUnscheduledFencingBinding(cluster4, 3, "3"),
+				newUnscheduledFencingBinding(cluster5, 4, "3"),
+			},
+			policySnapshots: []client.Object{
+				newFencingPolicySnapshot("test-crp-1", true, "2", 2),
+			},
+			wantBindings: []string{cluster3, cluster4},
+		},
+		"all the scheduled bindings are fenced off while the latest policy snapshot is being created": {
+			bindings: []*fleetv1beta1.ClusterResourceBinding{scheduled, superseded, bound},
+			policySnapshots: []client.Object{
+				newFencingPolicySnapshot("test-crp-0", false, "1", 1),
+			},
+			wantBindings: []string{cluster3},
+			wantFenced:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.policySnapshots...).Build()
+			r := &Reconciler{Client: fakeClient, UncachedReader: fakeClient}
+
+			got, gotFenced, err := r.fenceSupersededBindings(context.Background(), "test-crp", tc.bindings)
+			if err != nil {
+				t.Fatalf("fenceSupersededBindings() = %v, want nil", err)
+			}
+			if gotFenced != tc.wantFenced {
+				t.Errorf("fenceSupersededBindings() fenced = %v, want %v", gotFenced, tc.wantFenced)
+			}
+			gotBindings := make([]string, 0, len(got))
+			for _, binding := range got {
+				gotBindings = append(gotBindings, binding.Name)
+			}
+			if diff := cmp.Diff(tc.wantBindings, gotBindings); diff != "" {
+				t.Errorf("fenceSupersededBindings() bindings mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
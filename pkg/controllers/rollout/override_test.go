@@ -22,8 +22,9 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/fleettest"
 	"go.goms.io/fleet/pkg/utils/controller"
-	"go.goms.io/fleet/test/utils/informer"
+	"go.goms.io/fleet/pkg/utils/index"
 	"go.goms.io/fleet/test/utils/resource"
 )
 
@@ -46,7 +47,7 @@ func serviceScheme(t *testing.T) *runtime.Scheme {
 }
 
 func TestFetchAllMatchingOverridesForResourceSnapshot(t *testing.T) {
-	fakeInformer := informer.FakeManager{
+	fakeInformer := fleettest.FakeInformerManager{
 		APIResources: map[schema.GroupVersionKind]bool{
 			{
 				Group:   "",
@@ -283,6 +284,52 @@ func TestFetchAllMatchingOverridesForResourceSnapshot(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "single resource snapshot with ro selecting the same resource in another namespace",
+			master: &placementv1beta1.ClusterResourceSnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf(placementv1beta1.ResourceSnapshotNameFmt, crpName, 0),
+					Labels: map[string]string{
+						placementv1beta1.ResourceIndexLabel: "0",
+						placementv1beta1.CRPTrackingLabel:   crpName,
+					},
+					Annotations: map[string]string{
+						placementv1beta1.ResourceGroupHashAnnotation:         "abc",
+						placementv1beta1.NumberOfResourceSnapshotsAnnotation: "1",
+					},
+				},
+				Spec: placementv1beta1.ResourceSnapshotSpec{
+					SelectedResources: []placementv1beta1.ResourceContent{
+						*resource.ServiceResourceContentForTest(t),
+					},
+				},
+			},
+			roList: []placementv1alpha1.ResourceOverrideSnapshot{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "ro-1",
+						Namespace: "other-namespace",
+						Labels: map[string]string{
+							placementv1beta1.IsLatestSnapshotLabel: "true",
+						},
+					},
+					Spec: placementv1alpha1.ResourceOverrideSnapshotSpec{
+						OverrideSpec: placementv1alpha1.ResourceOverrideSpec{
+							ResourceSelectors: []placementv1alpha1.ResourceSelector{
+								{
+									Group:   "",
+									Version: "v1",
+									Kind:    "Service",
+									Name:    "svc-name",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantCRO: []*placementv1alpha1.ClusterResourceOverrideSnapshot{},
+			wantRO:  []*placementv1alpha1.ResourceOverrideSnapshot{},
+		},
 		{
 			name: "single resource snapshot with matched stale cro and ro snapshot",
 			master: &placementv1beta1.ClusterResourceSnapshot{
@@ -779,6 +826,7 @@ func TestFetchAllMatchingOverridesForResourceSnapshot(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().
 				WithScheme(scheme).
 				WithObjects(objects...).
+				WithIndex(&placementv1beta1.ClusterResourceSnapshot{}, index.ResourceSnapshotGroupField, index.ResourceSnapshotGroup).
 				Build()
 			r := Reconciler{
 				Client:          fakeClient,
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/defaulter"
+)
+
+// DesiredBinding returns the binding of the placement to the cluster as the controller would update it to roll out
+// the latest resource snapshot of the placement, i.e. with the latest resource snapshot, apply strategy and matching
+// overrides, without updating any binding.
+// The binding is built from the current binding of the placement to the cluster if there is one; otherwise it is the
+// binding which the placement would have if it selected the cluster, which has no name.
+func (r *Reconciler) DesiredBinding(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, clusterName string) (*fleetv1beta1.ClusterResourceBinding, error) {
+	crpName := crp.Name
+	bindingList := &fleetv1beta1.ClusterResourceBindingList{}
+	if err := r.Client.List(ctx, bindingList, client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crpName}); err != nil {
+		klog.ErrorS(err, "Failed to list all the bindings associated with the clusterResourcePlacement",
+			"clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crpName},
+		},
+		Spec: fleetv1beta1.ResourceBindingSpec{TargetCluster: clusterName},
+	}
+	for i := range bindingList.Items {
+		if b := &bindingList.Items[i]; b.Spec.TargetCluster == clusterName && b.DeletionTimestamp.IsZero() {
+			binding = b
+			break
+		}
+	}
+
+	latestResourceSnapshot, err := r.fetchLatestResourceSnapshot(ctx, crpName)
+	if err != nil {
+		return nil, err
+	}
+	// fill out all the default values for CRP just in case the mutation webhook is not enabled.
+	crp = crp.DeepCopy()
+	defaulter.SetDefaultsClusterResourcePlacement(crp)
+	matchedCRO, matchedRO, err := r.fetchAllMatchingOverridesForResourceSnapshot(ctx, crpName, latestResourceSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	cro, ro, err := r.pickFromResourceMatchedOverridesForTargetCluster(ctx, binding, matchedCRO, matchedRO)
+	if err != nil {
+		return nil, err
+	}
+	return createUpdateInfo(binding, crp, latestResourceSnapshot, cro, ro).desiredBinding, nil
+}
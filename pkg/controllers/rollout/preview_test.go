@@ -0,0 +1,108 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/defaulter"
+	"go.goms.io/fleet/pkg/utils/index"
+)
+
+func TestDesiredBinding(t *testing.T) {
+	applyStrategy := &fleetv1beta1.ApplyStrategy{Type: fleetv1beta1.ApplyStrategyTypeServerSideApply}
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: "crp"},
+		Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+			Strategy: fleetv1beta1.RolloutStrategy{ApplyStrategy: applyStrategy},
+			Priority: 10,
+		},
+	}
+	newSnapshot := func(name string, latest bool) *fleetv1beta1.ClusterResourceSnapshot {
+		return &fleetv1beta1.ClusterResourceSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					fleetv1beta1.CRPTrackingLabel:      crp.Name,
+					fleetv1beta1.IsLatestSnapshotLabel: map[bool]string{true: "true", false: "false"}[latest],
+				},
+				Annotations: map[string]string{fleetv1beta1.ResourceGroupHashAnnotation: "hash"},
+			},
+		}
+	}
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "crp-cluster-1",
+			Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crp.Name},
+		},
+		Spec: fleetv1beta1.ResourceBindingSpec{
+			State:                fleetv1beta1.BindingStateBound,
+			TargetCluster:        "cluster-1",
+			ResourceSnapshotName: "crp-0-snapshot",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(serviceScheme(t)).
+		WithObjects(crp, binding, newSnapshot("crp-0-snapshot", false), newSnapshot("crp-1-snapshot", true)).
+		WithIndex(&fleetv1beta1.ClusterResourceSnapshot{}, index.ResourceSnapshotCRPField, index.ResourceSnapshotCRP).
+		Build()
+	r := &Reconciler{Client: fakeClient}
+	// the apply strategy of the placement is defaulted as the rollout does
+	defaultedCRP := crp.DeepCopy()
+	defaulter.SetDefaultsClusterResourcePlacement(defaultedCRP)
+	wantApplyStrategy := defaultedCRP.Spec.Strategy.ApplyStrategy
+
+	tests := map[string]struct {
+		cluster string
+		want    *fleetv1beta1.ClusterResourceBinding
+	}{
+		"the cluster is selected": {
+			cluster: "cluster-1",
+			want: &fleetv1beta1.ClusterResourceBinding{
+				ObjectMeta: binding.ObjectMeta,
+				Spec: fleetv1beta1.ResourceBindingSpec{
+					State:                fleetv1beta1.BindingStateBound,
+					TargetCluster:        "cluster-1",
+					ResourceSnapshotName: "crp-1-snapshot",
+					ApplyStrategy:        wantApplyStrategy,
+					Priority:             10,
+				},
+			},
+		},
+		"the cluster is not selected": {
+			cluster: "cluster-2",
+			want: &fleetv1beta1.ClusterResourceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crp.Name},
+				},
+				Spec: fleetv1beta1.ResourceBindingSpec{
+					State:                fleetv1beta1.BindingStateBound,
+					TargetCluster:        "cluster-2",
+					ResourceSnapshotName: "crp-1-snapshot",
+					ApplyStrategy:        wantApplyStrategy,
+					Priority:             10,
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := r.DesiredBinding(context.Background(), crp, tt.cluster)
+			if err != nil {
+				t.Fatalf("DesiredBinding() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpOptions...); diff != "" {
+				t.Errorf("DesiredBinding() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
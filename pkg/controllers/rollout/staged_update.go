@@ -0,0 +1,57 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// fetchReleasedClusters returns the clusters which the staged update run of the placement allows the latest resource
+// snapshot to be rolled out to, i.e. the clusters of the stages which have started.
+// It returns nil if the placement has no staged update run or the run has succeeded, when all the clusters are released.
+func (r *Reconciler) fetchReleasedClusters(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement,
+	latestResourceSnapshot *fleetv1beta1.ClusterResourceSnapshot) (sets.Set[string], error) {
+	runName := crp.Spec.Strategy.StagedUpdateRunName
+	if runName == "" {
+		return nil, nil
+	}
+	released := sets.New[string]()
+	var run fleetv1beta1.ClusterStagedUpdateRun
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: runName}, &run); err != nil {
+		if errors.IsNotFound(err) {
+			klog.V(2).InfoS("The staged update run of the clusterResourcePlacement is not found", "clusterResourcePlacement", klog.KObj(crp), "clusterStagedUpdateRun", runName)
+			return released, nil
+		}
+		klog.ErrorS(err, "Failed to get the staged update run", "clusterResourcePlacement", klog.KObj(crp), "clusterStagedUpdateRun", runName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	status := run.GetPlacementStatus(crp.Name)
+	if status == nil || status.ResourceSnapshotIndex != latestResourceSnapshot.Labels[fleetv1beta1.ResourceIndexLabel] {
+		// the run has not started for the latest resource snapshot yet
+		return released, nil
+	}
+	succeeded := true
+	for _, stage := range status.Stages {
+		switch stage.State {
+		case fleetv1beta1.StageUpdatingStateUpdating, fleetv1beta1.StageUpdatingStateSoaking, fleetv1beta1.StageUpdatingStateSucceeded:
+			released.Insert(stage.Clusters...)
+		}
+		succeeded = succeeded && stage.State == fleetv1beta1.StageUpdatingStateSucceeded
+	}
+	if succeeded {
+		// the clusters which no stage selects are rolled out after all the stages
+		return nil, nil
+	}
+	return released, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestFetchReleasedClusters(t *testing.T) {
+	latestResourceSnapshot := &fleetv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-crp-2-snapshot",
+			Labels: map[string]string{fleetv1beta1.ResourceIndexLabel: "2"},
+		},
+	}
+	newRun := func(index string, states ...fleetv1beta1.StageUpdatingState) *fleetv1beta1.ClusterStagedUpdateRun {
+		run := &fleetv1beta1.ClusterStagedUpdateRun{ObjectMeta: metav1.ObjectMeta{Name: "run"}}
+		status := fleetv1beta1.PlacementUpdateRunStatus{PlacementName: "test-crp", ResourceSnapshotIndex: index}
+		clusters := [][]string{{cluster1}, {cluster2, cluster3}}
+		for i, state := range states {
+			status.Stages = append(status.Stages, fleetv1beta1.StageUpdatingStatus{Clusters: clusters[i], State: state})
+		}
+		run.Status.PlacementStatuses = []fleetv1beta1.PlacementUpdateRunStatus{status}
+		return run
+	}
+	tests := map[string]struct {
+		runName string
+		run     *fleetv1beta1.ClusterStagedUpdateRun
+		want    sets.Set[string]
+	}{
+		"no staged update run": {},
+		"the staged update run is not found": {
+			runName: "run",
+			want:    sets.New[string](),
+		},
+		"the run has not started for the latest resource snapshot": {
+			runName: "run",
+			run:     newRun("1", fleetv1beta1.StageUpdatingStateSucceeded, fleetv1beta1.StageUpdatingStateSucceeded),
+			want:    sets.New[string](),
+		},
+		"the first stage is waiting for approval": {
+			runName: "run",
+			run:     newRun("2", fleetv1beta1.StageUpdatingStateWaitingForApproval, fleetv1beta1.StageUpdatingStatePending),
+			want:    sets.New[string](),
+		},
+		"the second stage is updating": {
+			runName: "run",
+			run:     newRun("2", fleetv1beta1.StageUpdatingStateSucceeded, fleetv1beta1.StageUpdatingStateUpdating),
+			want:    sets.New(cluster1, cluster2, cluster3),
+		},
+		"the first stage is soaking": {
+			runName: "run",
+			run:     newRun("2", fleetv1beta1.StageUpdatingStateSoaking, fleetv1beta1.StageUpdatingStatePending),
+			want:    sets.New(cluster1),
+		},
+		"all the stages have succeeded": {
+			runName: "run",
+			run:     newRun("2", fleetv1beta1.StageUpdatingStateSucceeded, fleetv1beta1.StageUpdatingStateSucceeded),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var objects []client.Object
+			if tc.run != nil {
+				objects = append(objects, tc.run)
+			}
+			r := Reconciler{Client: fake.NewClientBuilder().WithScheme(serviceScheme(t)).WithObjects(objects...).Build()}
+			crp := &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "test-crp"}}
+			crp.Spec.Strategy.StagedUpdateRunName = tc.runName
+			got, err := r.fetchReleasedClusters(context.Background(), crp, latestResourceSnapshot)
+			if err != nil {
+				t.Fatalf("fetchReleasedClusters() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("fetchReleasedClusters() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
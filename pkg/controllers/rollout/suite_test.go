@@ -29,6 +29,7 @@ import (
 
 	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/index"
 )
 
 var (
@@ -93,6 +94,10 @@ var _ = BeforeSuite(func() {
 	By("set k8s client same as the controller manager")
 	k8sClient = mgr.GetClient()
 
+	// add the field indexes that the hub agent adds for the rollout controller
+	Expect(index.AddResourceSnapshotGroupIndex(ctx, mgr.GetFieldIndexer())).Should(Succeed())
+	Expect(index.AddResourceSnapshotCRPIndex(ctx, mgr.GetFieldIndexer())).Should(Succeed())
+
 	// setup our main reconciler
 	err = (&Reconciler{
 		Client:         k8sClient,
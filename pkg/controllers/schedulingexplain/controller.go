@@ -0,0 +1,221 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package schedulingexplain features a controller that explains how the scheduler schedules the cluster resource
+// placements, i.e., runs scheduling cycles for them without creating any binding and records why each cluster is
+// picked or not.
+package schedulingexplain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/resource"
+)
+
+const (
+	// the reasons of the Explained condition.
+	explainedReason                = "Explained"
+	placementNotFoundReason        = "PlacementNotFound"
+	policySnapshotNotFoundReason   = "PolicySnapshotNotFound"
+	invalidPolicyReason            = "InvalidPolicy"
+	schedulerProfileNotFoundReason = "SchedulerProfileNotFound"
+)
+
+// Reconciler reconciles a cluster scheduling explain. It runs a scheduling cycle for the placement with the
+// scheduling framework of the scheduler profile the placement selects, once for each generation of the explain.
+type Reconciler struct {
+	Client client.Client
+	// DefaultFramework is the scheduling framework of the default scheduler profile.
+	DefaultFramework framework.Framework
+	// ProfileFrameworks are the scheduling frameworks of the scheduler profiles which the placements can select by
+	// name, keyed by the profile names.
+	ProfileFrameworks map[string]framework.Framework
+}
+
+// Reconcile explains the placement of the cluster scheduling explain if its current generation is not explained yet.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("ClusterSchedulingExplain reconciliation starts", "explain", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ClusterSchedulingExplain reconciliation ends", "explain", req.Name, "latency", latency)
+	}()
+
+	var explain placementv1beta1.ClusterSchedulingExplain
+	if err := r.Client.Get(ctx, req.NamespacedName, &explain); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the explain", "explain", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !explain.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if cond := explain.GetCondition(string(placementv1beta1.ClusterSchedulingExplainConditionTypeExplained)); cond != nil && cond.ObservedGeneration == explain.Generation {
+		// the scheduling cycle runs once for each generation
+		return ctrl.Result{}, nil
+	}
+
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: explain.Spec.PlacementName}, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.updateNotExplained(ctx, &explain, placementNotFoundReason,
+				fmt.Sprintf("clusterResourcePlacement %s is not found", explain.Spec.PlacementName))
+		}
+		klog.ErrorS(err, "Failed to get the placement", "explain", req.Name, "clusterResourcePlacement", explain.Spec.PlacementName)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+
+	latest, err := r.lookupLatestPolicySnapshot(ctx, crp.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	policy := latest
+	if explain.Spec.Policy != nil {
+		if explain.Spec.Policy.PlacementType == placementv1beta1.PickNPlacementType && explain.Spec.Policy.NumberOfClusters == nil {
+			return ctrl.Result{}, r.updateNotExplained(ctx, &explain, invalidPolicyReason,
+				"the number of clusters needs to be set for the policy of the PickN placement type")
+		}
+		if policy, err = buildPolicySnapshot(&explain, &crp); err != nil {
+			klog.ErrorS(err, "Failed to build the policy snapshot to explain", "explain", req.Name)
+			return ctrl.Result{}, controller.NewUnexpectedBehaviorError(err)
+		}
+	} else if policy == nil {
+		return ctrl.Result{}, r.updateNotExplained(ctx, &explain, policySnapshotNotFoundReason,
+			fmt.Sprintf("clusterResourcePlacement %s has no scheduling policy snapshot yet", crp.Name))
+	}
+
+	f := r.DefaultFramework
+	if profileName := policyProfileName(policy); profileName != "" {
+		var found bool
+		if f, found = r.ProfileFrameworks[profileName]; !found {
+			return ctrl.Result{}, r.updateNotExplained(ctx, &explain, schedulerProfileNotFoundReason,
+				fmt.Sprintf("The scheduler profile %s is not configured with the scheduler", profileName))
+		}
+	}
+	decisions, err := f.ExplainSchedulingFor(ctx, crp.Name, policy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to explain the scheduling of the placement", "explain", req.Name, "clusterResourcePlacement", crp.Name)
+		return ctrl.Result{}, err
+	}
+
+	selected := 0
+	for i := range decisions {
+		if decisions[i].Selected {
+			selected++
+		}
+	}
+	explain.Status.PolicySnapshotName = ""
+	if explain.Spec.Policy == nil {
+		explain.Status.PolicySnapshotName = policy.Name
+	}
+	explain.Status.Clusters = decisions
+	explain.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.ClusterSchedulingExplainConditionTypeExplained),
+		Status:             metav1.ConditionTrue,
+		Reason:             explainedReason,
+		Message:            fmt.Sprintf("%d of the %d cluster(s) explained are picked by the placement or would be picked", selected, len(decisions)),
+		ObservedGeneration: explain.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, &explain); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the explain", "explain", req.Name)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// lookupLatestPolicySnapshot returns the latest policy snapshot of the placement, or nil if there is none.
+func (r *Reconciler) lookupLatestPolicySnapshot(ctx context.Context, crpName string) (*placementv1beta1.ClusterSchedulingPolicySnapshot, error) {
+	var snapshotList placementv1beta1.ClusterSchedulingPolicySnapshotList
+	if err := r.Client.List(ctx, &snapshotList, client.MatchingLabels{
+		placementv1beta1.CRPTrackingLabel:      crpName,
+		placementv1beta1.IsLatestSnapshotLabel: strconv.FormatBool(true),
+	}); err != nil {
+		klog.ErrorS(err, "Failed to list the latest policy snapshots of the placement", "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	if len(snapshotList.Items) == 0 {
+		return nil, nil
+	}
+	return &snapshotList.Items[0], nil
+}
+
+// buildPolicySnapshot builds an in-memory policy snapshot of the placement with the policy of the explain, the way
+// the placement controller would snapshot the policy if the placement were updated with it.
+func buildPolicySnapshot(explain *placementv1beta1.ClusterSchedulingExplain, crp *placementv1beta1.ClusterResourcePlacement) (*placementv1beta1.ClusterSchedulingPolicySnapshot, error) {
+	policy := explain.Spec.Policy.DeepCopy()
+	policy.NumberOfClusters = nil
+	policyHash, err := resource.HashOf(policy)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			// the name does not match any binding, so that all the bindings are treated as obsolete
+			Name:   explain.Name,
+			Labels: map[string]string{placementv1beta1.CRPTrackingLabel: crp.Name},
+			Annotations: map[string]string{
+				placementv1beta1.CRPGenerationAnnotation: strconv.FormatInt(crp.Generation, 10),
+			},
+		},
+		Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+			Policy:     policy,
+			PolicyHash: []byte(policyHash),
+		},
+	}
+	if explain.Spec.Policy.PlacementType == placementv1beta1.PickNPlacementType {
+		snapshot.Annotations[placementv1beta1.NumberOfClustersAnnotation] = strconv.Itoa(int(*explain.Spec.Policy.NumberOfClusters))
+	}
+	return snapshot, nil
+}
+
+// policyProfileName returns the name of the scheduler profile a policy snapshot selects, or empty for the default one.
+func policyProfileName(policy *placementv1beta1.ClusterSchedulingPolicySnapshot) string {
+	if policy.Spec.Policy == nil {
+		return ""
+	}
+	return policy.Spec.Policy.SchedulerProfile
+}
+
+// updateNotExplained records why the placement cannot be explained in the Explained condition.
+func (r *Reconciler) updateNotExplained(ctx context.Context, explain *placementv1beta1.ClusterSchedulingExplain, reason, message string) error {
+	explain.Status.PolicySnapshotName = ""
+	explain.Status.Clusters = nil
+	explain.SetConditions(metav1.Condition{
+		Type:               string(placementv1beta1.ClusterSchedulingExplainConditionTypeExplained),
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: explain.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, explain); err != nil {
+		klog.ErrorS(err, "Failed to update the status of the explain", "explain", klog.KObj(explain))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("scheduling-explain-controller").
+		For(&placementv1beta1.ClusterSchedulingExplain{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
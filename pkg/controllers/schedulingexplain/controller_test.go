@@ -0,0 +1,206 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package schedulingexplain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+const (
+	testExplainName  = "why-not-prod"
+	testCRPName      = "web"
+	testPolicyName   = "web-1"
+	testProfileName  = "least-loaded"
+	testClusterName  = "member-1"
+	testExplainedMsg = "picked by scheduling policy"
+)
+
+// fakeFramework is a scheduling framework which records the policy snapshot it explains.
+type fakeFramework struct {
+	framework.Framework
+	explained *placementv1beta1.ClusterSchedulingPolicySnapshot
+}
+
+func (f *fakeFramework) ExplainSchedulingFor(_ context.Context, _ string, policy *placementv1beta1.ClusterSchedulingPolicySnapshot) ([]placementv1beta1.ClusterDecision, error) {
+	f.explained = policy
+	return []placementv1beta1.ClusterDecision{{ClusterName: testClusterName, Selected: true, Reason: testExplainedMsg}}, nil
+}
+
+func newTestExplain(policy *placementv1beta1.PlacementPolicy, conditions ...metav1.Condition) *placementv1beta1.ClusterSchedulingExplain {
+	return &placementv1beta1.ClusterSchedulingExplain{
+		ObjectMeta: metav1.ObjectMeta{Name: testExplainName, Generation: 2},
+		Spec:       placementv1beta1.ClusterSchedulingExplainSpec{PlacementName: testCRPName, Policy: policy},
+		Status:     placementv1beta1.ClusterSchedulingExplainStatus{Conditions: conditions},
+	}
+}
+
+func newTestPolicySnapshot(profileName string) *placementv1beta1.ClusterSchedulingPolicySnapshot {
+	return &placementv1beta1.ClusterSchedulingPolicySnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testPolicyName,
+			Labels: map[string]string{
+				placementv1beta1.CRPTrackingLabel:      testCRPName,
+				placementv1beta1.IsLatestSnapshotLabel: "true",
+			},
+		},
+		Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+			Policy: &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType, SchedulerProfile: profileName},
+		},
+	}
+}
+
+func explainedCondition(status metav1.ConditionStatus, reason string) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(placementv1beta1.ClusterSchedulingExplainConditionTypeExplained),
+		Status:             status,
+		Reason:             reason,
+		ObservedGeneration: 2,
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	crp := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: testCRPName, Generation: 3}}
+	explainedClusters := []placementv1beta1.ClusterDecision{{ClusterName: testClusterName, Selected: true, Reason: testExplainedMsg}}
+	tests := map[string]struct {
+		explain       *placementv1beta1.ClusterSchedulingExplain
+		objects       []client.Object
+		wantExplained *placementv1beta1.ClusterSchedulingPolicySnapshot
+		wantStatus    placementv1beta1.ClusterSchedulingExplainStatus
+	}{
+		"explain the latest policy snapshot": {
+			explain:       newTestExplain(nil),
+			objects:       []client.Object{crp, newTestPolicySnapshot("")},
+			wantExplained: newTestPolicySnapshot(""),
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				PolicySnapshotName: testPolicyName,
+				Clusters:           explainedClusters,
+				Conditions:         []metav1.Condition{explainedCondition(metav1.ConditionTrue, explainedReason)},
+			},
+		},
+		"explain with the framework of the scheduler profile": {
+			explain:       newTestExplain(nil),
+			objects:       []client.Object{crp, newTestPolicySnapshot(testProfileName)},
+			wantExplained: newTestPolicySnapshot(testProfileName),
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				PolicySnapshotName: testPolicyName,
+				Clusters:           explainedClusters,
+				Conditions:         []metav1.Condition{explainedCondition(metav1.ConditionTrue, explainedReason)},
+			},
+		},
+		"explain the policy of the explain": {
+			explain: newTestExplain(&placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickNPlacementType,
+				NumberOfClusters: ptr.To(int32(3)),
+			}),
+			objects: []client.Object{crp, newTestPolicySnapshot("")},
+			wantExplained: &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   testExplainName,
+					Labels: map[string]string{placementv1beta1.CRPTrackingLabel: testCRPName},
+					Annotations: map[string]string{
+						placementv1beta1.CRPGenerationAnnotation:    "3",
+						placementv1beta1.NumberOfClustersAnnotation: "3",
+					},
+				},
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickNPlacementType},
+				},
+			},
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				Clusters:   explainedClusters,
+				Conditions: []metav1.Condition{explainedCondition(metav1.ConditionTrue, explainedReason)},
+			},
+		},
+		"the generation is explained already": {
+			explain: newTestExplain(nil, explainedCondition(metav1.ConditionFalse, placementNotFoundReason)),
+			objects: []client.Object{crp, newTestPolicySnapshot("")},
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				Conditions: []metav1.Condition{explainedCondition(metav1.ConditionFalse, placementNotFoundReason)},
+			},
+		},
+		"placement is not found": {
+			explain: newTestExplain(nil),
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				Conditions: []metav1.Condition{explainedCondition(metav1.ConditionFalse, placementNotFoundReason)},
+			},
+		},
+		"placement has no policy snapshot": {
+			explain: newTestExplain(nil),
+			objects: []client.Object{crp},
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				Conditions: []metav1.Condition{explainedCondition(metav1.ConditionFalse, policySnapshotNotFoundReason)},
+			},
+		},
+		"scheduler profile is not configured": {
+			explain: newTestExplain(nil),
+			objects: []client.Object{crp, newTestPolicySnapshot("unknown")},
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				Conditions: []metav1.Condition{explainedCondition(metav1.ConditionFalse, schedulerProfileNotFoundReason)},
+			},
+		},
+		"policy of the PickN placement type without the number of clusters": {
+			explain: newTestExplain(&placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickNPlacementType}),
+			objects: []client.Object{crp},
+			wantStatus: placementv1beta1.ClusterSchedulingExplainStatus{
+				Conditions: []metav1.Condition{explainedCondition(metav1.ConditionFalse, invalidPolicyReason)},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := placementv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the placement APIs to the scheme: %v", err)
+			}
+			defaultFramework, profileFramework := &fakeFramework{}, &fakeFramework{}
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tt.objects, tt.explain)...).
+					WithStatusSubresource(&placementv1beta1.ClusterSchedulingExplain{}).Build(),
+				DefaultFramework:  defaultFramework,
+				ProfileFrameworks: map[string]framework.Framework{testProfileName: profileFramework},
+			}
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testExplainName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want no error", err)
+			}
+
+			explained := defaultFramework.explained
+			if profileFramework.explained != nil {
+				explained = profileFramework.explained
+				if tt.wantExplained.Spec.Policy.SchedulerProfile != testProfileName {
+					t.Errorf("Reconcile() explained with the framework of scheduler profile %s, want the default one", testProfileName)
+				}
+			}
+			if diff := cmp.Diff(tt.wantExplained, explained,
+				cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion"),
+				cmpopts.IgnoreFields(placementv1beta1.SchedulingPolicySnapshotSpec{}, "PolicyHash")); diff != "" {
+				t.Errorf("explained policy snapshot mismatch (-want, +got):\n%s", diff)
+			}
+
+			var got placementv1beta1.ClusterSchedulingExplain
+			if err := r.Client.Get(ctx, types.NamespacedName{Name: testExplainName}, &got); err != nil {
+				t.Fatalf("Get(explain) = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tt.wantStatus, got.Status, cmpopts.IgnoreFields(metav1.Condition{}, "Message", "LastTransitionTime")); diff != "" {
+				t.Errorf("status mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
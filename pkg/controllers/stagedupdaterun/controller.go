@@ -0,0 +1,354 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package stagedupdaterun features a controller that runs the stages of the staged update runs for the cluster
+// resource placements which reference them, and records the progress of each placement.
+package stagedupdaterun
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/clustergroup"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// Reconciler reconciles a staged update run. It advances the stages of the run for each placement which references it
+// and records their progress in the status of the run; the rollout controller then rolls out the clusters of the
+// stages which have started.
+type Reconciler struct {
+	Client client.Client
+
+	now func() time.Time
+}
+
+// Reconcile advances the stages of the staged update run for the placements which reference it.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("ClusterStagedUpdateRun reconciliation starts", "clusterStagedUpdateRun", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("ClusterStagedUpdateRun reconciliation ends", "clusterStagedUpdateRun", req.Name, "latency", latency)
+	}()
+
+	var run placementv1beta1.ClusterStagedUpdateRun
+	if err := r.Client.Get(ctx, req.NamespacedName, &run); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the staged update run", "clusterStagedUpdateRun", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !run.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	selectors, err := stageSelectors(run.Spec.Stages)
+	if err != nil {
+		// the run is reconciled again when its spec is fixed
+		klog.ErrorS(err, "Invalid staged update run", "clusterStagedUpdateRun", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	var crpList placementv1beta1.ClusterResourcePlacementList
+	if err := r.Client.List(ctx, &crpList); err != nil {
+		klog.ErrorS(err, "Failed to list the placements", "clusterStagedUpdateRun", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	var clusterList clusterv1beta1.MemberClusterList
+	if err := r.Client.List(ctx, &clusterList); err != nil {
+		klog.ErrorS(err, "Failed to list the member clusters", "clusterStagedUpdateRun", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	clusterLabels := make(map[string]labels.Set, len(clusterList.Items))
+	for _, cluster := range clusterList.Items {
+		clusterLabels[cluster.Name] = cluster.Labels
+	}
+
+	now := r.clock()
+	var requeueAfter time.Duration
+	statuses := make([]placementv1beta1.PlacementUpdateRunStatus, 0)
+	for i := range crpList.Items {
+		crp := &crpList.Items[i]
+		if crp.Spec.Strategy.StagedUpdateRunName != run.Name || !crp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		status, after, err := r.runPlacement(ctx, &run, selectors, crp, clusterLabels, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if status == nil {
+			continue
+		}
+		statuses = append(statuses, *status)
+		if after > 0 && (requeueAfter == 0 || after < requeueAfter) {
+			requeueAfter = after
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].PlacementName < statuses[j].PlacementName
+	})
+
+	if !equality.Semantic.DeepEqual(run.Status.PlacementStatuses, statuses) {
+		run.Status.PlacementStatuses = statuses
+		if err := r.Client.Status().Update(ctx, &run); err != nil {
+			klog.ErrorS(err, "Failed to update the status of the staged update run", "clusterStagedUpdateRun", req.Name)
+			return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// runPlacement advances the stages of the run for the latest resource snapshot of the placement, and returns its
+// progress and how long to wait before the soaking stage, if any, succeeds.
+// It returns nil if the placement has no resource snapshot yet.
+func (r *Reconciler) runPlacement(ctx context.Context, run *placementv1beta1.ClusterStagedUpdateRun, selectors []labels.Selector,
+	crp *placementv1beta1.ClusterResourcePlacement, clusterLabels map[string]labels.Set, now time.Time) (*placementv1beta1.PlacementUpdateRunStatus, time.Duration, error) {
+	crpKObj := klog.KObj(crp)
+	latest, err := r.fetchLatestResourceSnapshot(ctx, crp.Name)
+	if err != nil || latest == nil {
+		return nil, 0, err
+	}
+	index := latest.Labels[placementv1beta1.ResourceIndexLabel]
+
+	var bindingList placementv1beta1.ClusterResourceBindingList
+	if err := r.Client.List(ctx, &bindingList, client.MatchingLabels{placementv1beta1.CRPTrackingLabel: crp.Name}); err != nil {
+		klog.ErrorS(err, "Failed to list the bindings of the placement", "clusterResourcePlacement", crpKObj)
+		return nil, 0, controller.NewAPIServerError(true, err)
+	}
+	targets := make(map[string]*placementv1beta1.ClusterResourceBinding)
+	for i := range bindingList.Items {
+		binding := &bindingList.Items[i]
+		if binding.DeletionTimestamp.IsZero() &&
+			(binding.Spec.State == placementv1beta1.BindingStateScheduled || binding.Spec.State == placementv1beta1.BindingStateBound) {
+			targets[binding.Spec.TargetCluster] = binding
+		}
+	}
+	stageClusters := groupClustersByStage(selectors, targets, clusterLabels)
+
+	oldStatus := run.GetPlacementStatus(crp.Name)
+	if oldStatus != nil && oldStatus.ResourceSnapshotIndex != index {
+		klog.V(2).InfoS("Starting a new staged update run for the placement", "clusterStagedUpdateRun", klog.KObj(run),
+			"clusterResourcePlacement", crpKObj, "resourceSnapshotIndex", index)
+		oldStatus = nil
+	}
+	status := &placementv1beta1.PlacementUpdateRunStatus{
+		PlacementName:         crp.Name,
+		ResourceSnapshotIndex: index,
+		Stages:                make([]placementv1beta1.StageUpdatingStatus, len(run.Spec.Stages)),
+	}
+	blocked := false
+	var requeueAfter time.Duration
+	for i := range run.Spec.Stages {
+		stage := &run.Spec.Stages[i]
+		stageStatus := &status.Stages[i]
+		stageStatus.StageName = stage.Name
+		stageStatus.Clusters = stageClusters[i]
+		stageStatus.State = placementv1beta1.StageUpdatingStatePending
+		if blocked {
+			// the stage waits for the earlier stages
+			continue
+		}
+		if oldStatus != nil {
+			for _, old := range oldStatus.Stages {
+				if old.StageName == stage.Name {
+					stageStatus.State, stageStatus.StartTime, stageStatus.AvailableTime, stageStatus.EndTime = old.State, old.StartTime, old.AvailableTime, old.EndTime
+					break
+				}
+			}
+		}
+		approved := crp.Annotations[placementv1beta1.StagedUpdateApprovalAnnotation] == fmt.Sprintf("%s/%s", index, stage.Name)
+		blocked, requeueAfter = advanceStage(stage, stageStatus, approved, isStageAvailable(stageStatus.Clusters, targets, latest.Name), now)
+	}
+	return status, requeueAfter, nil
+}
+
+// advanceStage moves the stage forward as far as it can go, and returns whether the later stages have to wait for it
+// and how long to wait before its soak time passes.
+func advanceStage(stage *placementv1beta1.StageConfig, status *placementv1beta1.StageUpdatingStatus, approved, available bool, now time.Time) (bool, time.Duration) {
+	switch status.State {
+	case placementv1beta1.StageUpdatingStateSucceeded:
+		return false, 0
+	case placementv1beta1.StageUpdatingStatePending, placementv1beta1.StageUpdatingStateWaitingForApproval:
+		if stage.RequireApproval && !approved {
+			status.State = placementv1beta1.StageUpdatingStateWaitingForApproval
+			return true, 0
+		}
+		status.State = placementv1beta1.StageUpdatingStateUpdating
+		status.StartTime = &metav1.Time{Time: now}
+	}
+	if status.State == placementv1beta1.StageUpdatingStateUpdating {
+		if !available {
+			return true, 0
+		}
+		status.State = placementv1beta1.StageUpdatingStateSoaking
+		status.AvailableTime = &metav1.Time{Time: now}
+	}
+	var soakTime time.Duration
+	if stage.SoakTime != nil {
+		soakTime = stage.SoakTime.Duration
+	}
+	if status.AvailableTime != nil {
+		if remaining := status.AvailableTime.Add(soakTime).Sub(now); remaining > 0 {
+			return true, remaining
+		}
+	}
+	status.State = placementv1beta1.StageUpdatingStateSucceeded
+	status.EndTime = &metav1.Time{Time: now}
+	return false, 0
+}
+
+// isStageAvailable tells if all the clusters of the stage are bound to the latest resource snapshot and available.
+func isStageAvailable(clusters []string, targets map[string]*placementv1beta1.ClusterResourceBinding, latestResourceSnapshotName string) bool {
+	for _, cluster := range clusters {
+		binding := targets[cluster]
+		if binding.Spec.State != placementv1beta1.BindingStateBound || binding.Spec.ResourceSnapshotName != latestResourceSnapshotName {
+			return false
+		}
+		if !condition.IsConditionStatusTrue(binding.GetCondition(string(placementv1beta1.ResourceBindingAvailable)), binding.Generation) {
+			return false
+		}
+	}
+	return true
+}
+
+// stageSelectors converts the label selectors of the stages.
+func stageSelectors(stages []placementv1beta1.StageConfig) ([]labels.Selector, error) {
+	selectors := make([]labels.Selector, len(stages))
+	for i := range stages {
+		selector, err := metav1.LabelSelectorAsSelector(&stages[i].LabelSelector)
+		if err != nil {
+			return nil, controller.NewUserError(fmt.Errorf("invalid label selector of stage %s: %w", stages[i].Name, err))
+		}
+		if stages[i].ClusterGroup != "" {
+			requirement, err := clustergroup.Requirement(stages[i].ClusterGroup)
+			if err != nil {
+				return nil, controller.NewUserError(fmt.Errorf("invalid cluster group of stage %s: %w", stages[i].Name, err))
+			}
+			selector = selector.Add(*requirement)
+		}
+		selectors[i] = selector
+	}
+	return selectors, nil
+}
+
+// groupClustersByStage returns the sorted names of the target clusters of each stage. A cluster belongs to the first
+// stage which selects it, and the clusters which no stage selects are left out.
+func groupClustersByStage(selectors []labels.Selector, targets map[string]*placementv1beta1.ClusterResourceBinding, clusterLabels map[string]labels.Set) [][]string {
+	clusters := make([]string, 0, len(targets))
+	for cluster := range targets {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	stageClusters := make([][]string, len(selectors))
+	for _, cluster := range clusters {
+		for i, selector := range selectors {
+			if selector.Matches(clusterLabels[cluster]) {
+				stageClusters[i] = append(stageClusters[i], cluster)
+				break
+			}
+		}
+	}
+	return stageClusters
+}
+
+// fetchLatestResourceSnapshot returns the master resource snapshot of the latest resource snapshot group of the
+// placement, or nil if there is none.
+func (r *Reconciler) fetchLatestResourceSnapshot(ctx context.Context, crpName string) (*placementv1beta1.ClusterResourceSnapshot, error) {
+	var snapshotList placementv1beta1.ClusterResourceSnapshotList
+	if err := r.Client.List(ctx, &snapshotList, client.MatchingLabels{
+		placementv1beta1.CRPTrackingLabel:      crpName,
+		placementv1beta1.IsLatestSnapshotLabel: "true",
+	}); err != nil {
+		klog.ErrorS(err, "Failed to list the latest resource snapshots of the placement", "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	for i := range snapshotList.Items {
+		// only the master snapshot has this annotation
+		if len(snapshotList.Items[i].Annotations[placementv1beta1.ResourceGroupHashAnnotation]) != 0 {
+			return &snapshotList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *Reconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("staged-update-run-controller").
+		For(&placementv1beta1.ClusterStagedUpdateRun{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&placementv1beta1.ClusterResourcePlacement{}, handler.Funcs{
+			CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+				enqueueRunOf(e.Object, q)
+			},
+			UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+				// the run which the placement no longer references drops the placement from its status
+				enqueueRunOf(e.ObjectOld, q)
+				enqueueRunOf(e.ObjectNew, q)
+			},
+			DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+				enqueueRunOf(e.Object, q)
+			},
+		}).
+		// the stages advance as the bindings are rolled out and become available
+		Watches(&placementv1beta1.ClusterResourceBinding{}, handler.EnqueueRequestsFromMapFunc(r.runOfPlacementOf)).
+		// a new run starts for each new resource snapshot
+		Watches(&placementv1beta1.ClusterResourceSnapshot{}, handler.EnqueueRequestsFromMapFunc(r.runOfPlacementOf)).
+		Complete(r)
+}
+
+// enqueueRunOf enqueues the staged update run which the placement references, if any.
+func enqueueRunOf(obj client.Object, q workqueue.RateLimitingInterface) {
+	crp, ok := obj.(*placementv1beta1.ClusterResourcePlacement)
+	if !ok || crp.Spec.Strategy.StagedUpdateRunName == "" {
+		return
+	}
+	q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: crp.Spec.Strategy.StagedUpdateRunName}})
+}
+
+// runOfPlacementOf maps an object of a placement, e.g. a binding, to the staged update run which the placement
+// references, if any.
+func (r *Reconciler) runOfPlacementOf(ctx context.Context, obj client.Object) []reconcile.Request {
+	crpName := obj.GetLabels()[placementv1beta1.CRPTrackingLabel]
+	if crpName == "" {
+		return nil
+	}
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: crpName}, &crp); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the placement", "clusterResourcePlacement", crpName, "object", klog.KObj(obj))
+		}
+		return nil
+	}
+	if crp.Spec.Strategy.StagedUpdateRunName == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: crp.Spec.Strategy.StagedUpdateRunName}}}
+}
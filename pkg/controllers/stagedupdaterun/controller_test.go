@@ -0,0 +1,253 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package stagedupdaterun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testRunName = "canary-first"
+	testCRPName = "web"
+)
+
+var testNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestRun() *placementv1beta1.ClusterStagedUpdateRun {
+	return &placementv1beta1.ClusterStagedUpdateRun{
+		ObjectMeta: metav1.ObjectMeta{Name: testRunName, Generation: 1},
+		Spec: placementv1beta1.StagedUpdateRunSpec{
+			Stages: []placementv1beta1.StageConfig{
+				{
+					Name:          "canary",
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"environment": "canary"}},
+					SoakTime:      &metav1.Duration{Duration: time.Hour},
+				},
+				{
+					Name:            "production",
+					LabelSelector:   metav1.LabelSelector{},
+					RequireApproval: true,
+				},
+			},
+		},
+	}
+}
+
+func newTestBinding(cluster, resourceSnapshotName string, available bool) *placementv1beta1.ClusterResourceBinding {
+	binding := &placementv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testCRPName + "-" + cluster,
+			Labels:     map[string]string{placementv1beta1.CRPTrackingLabel: testCRPName},
+			Generation: 1,
+		},
+		Spec: placementv1beta1.ResourceBindingSpec{
+			State:                placementv1beta1.BindingStateBound,
+			TargetCluster:        cluster,
+			ResourceSnapshotName: resourceSnapshotName,
+		},
+	}
+	if available {
+		binding.Status.Conditions = []metav1.Condition{
+			{Type: string(placementv1beta1.ResourceBindingAvailable), Status: metav1.ConditionTrue, ObservedGeneration: 1, Reason: "Available"},
+		}
+	}
+	return binding
+}
+
+func TestReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := placementv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement scheme: %v", err)
+	}
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the cluster scheme: %v", err)
+	}
+	crp := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: testCRPName}}
+	crp.Spec.Strategy.StagedUpdateRunName = testRunName
+	otherCRP := &placementv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	snapshot := &placementv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testCRPName + "-2-snapshot",
+			Labels: map[string]string{
+				placementv1beta1.CRPTrackingLabel:      testCRPName,
+				placementv1beta1.IsLatestSnapshotLabel: "true",
+				placementv1beta1.ResourceIndexLabel:    "2",
+			},
+			Annotations: map[string]string{placementv1beta1.ResourceGroupHashAnnotation: "hash"},
+		},
+	}
+	objects := []client.Object{
+		newTestRun(), crp, otherCRP, snapshot,
+		&clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: "canary-1", Labels: map[string]string{"environment": "canary"}}},
+		&clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: "prod-1", Labels: map[string]string{"environment": "production"}}},
+		newTestBinding("canary-1", snapshot.Name, true),
+		newTestBinding("prod-1", testCRPName+"-1-snapshot", true),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(&placementv1beta1.ClusterStagedUpdateRun{}).Build()
+	now := testNow
+	r := &Reconciler{Client: fakeClient, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	reconcileAndCheck := func(wantRequeue time.Duration, want []placementv1beta1.StageUpdatingStatus) {
+		t.Helper()
+		got, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testRunName}})
+		if err != nil {
+			t.Fatalf("Reconcile() = %v, want nil", err)
+		}
+		if got.RequeueAfter != wantRequeue {
+			t.Errorf("Reconcile() requeueAfter = %v, want %v", got.RequeueAfter, wantRequeue)
+		}
+		var run placementv1beta1.ClusterStagedUpdateRun
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: testRunName}, &run); err != nil {
+			t.Fatalf("failed to get the run: %v", err)
+		}
+		wantStatuses := []placementv1beta1.PlacementUpdateRunStatus{{PlacementName: testCRPName, ResourceSnapshotIndex: "2", Stages: want}}
+		if diff := cmp.Diff(wantStatuses, run.Status.PlacementStatuses); diff != "" {
+			t.Errorf("placement statuses mismatch (-want, +got):\n%s", diff)
+		}
+	}
+	at := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: testNow.Add(d)}
+	}
+
+	// the canary stage starts and soaks as its cluster is already available
+	reconcileAndCheck(time.Hour, []placementv1beta1.StageUpdatingStatus{
+		{StageName: "canary", Clusters: []string{"canary-1"}, State: placementv1beta1.StageUpdatingStateSoaking, StartTime: at(0), AvailableTime: at(0)},
+		{StageName: "production", Clusters: []string{"prod-1"}, State: placementv1beta1.StageUpdatingStatePending},
+	})
+
+	// the production stage waits for the approval after the canary stage soaks
+	now = testNow.Add(2 * time.Hour)
+	reconcileAndCheck(0, []placementv1beta1.StageUpdatingStatus{
+		{StageName: "canary", Clusters: []string{"canary-1"}, State: placementv1beta1.StageUpdatingStateSucceeded, StartTime: at(0), AvailableTime: at(0), EndTime: at(2 * time.Hour)},
+		{StageName: "production", Clusters: []string{"prod-1"}, State: placementv1beta1.StageUpdatingStateWaitingForApproval},
+	})
+
+	// an approval of another resource snapshot does not count
+	crp.Annotations = map[string]string{placementv1beta1.StagedUpdateApprovalAnnotation: "1/production"}
+	if err := fakeClient.Update(ctx, crp); err != nil {
+		t.Fatalf("failed to approve the stage: %v", err)
+	}
+	reconcileAndCheck(0, []placementv1beta1.StageUpdatingStatus{
+		{StageName: "canary", Clusters: []string{"canary-1"}, State: placementv1beta1.StageUpdatingStateSucceeded, StartTime: at(0), AvailableTime: at(0), EndTime: at(2 * time.Hour)},
+		{StageName: "production", Clusters: []string{"prod-1"}, State: placementv1beta1.StageUpdatingStateWaitingForApproval},
+	})
+
+	// the production stage starts once approved
+	now = testNow.Add(3 * time.Hour)
+	crp.Annotations = map[string]string{placementv1beta1.StagedUpdateApprovalAnnotation: "2/production"}
+	if err := fakeClient.Update(ctx, crp); err != nil {
+		t.Fatalf("failed to approve the stage: %v", err)
+	}
+	reconcileAndCheck(0, []placementv1beta1.StageUpdatingStatus{
+		{StageName: "canary", Clusters: []string{"canary-1"}, State: placementv1beta1.StageUpdatingStateSucceeded, StartTime: at(0), AvailableTime: at(0), EndTime: at(2 * time.Hour)},
+		{StageName: "production", Clusters: []string{"prod-1"}, State: placementv1beta1.StageUpdatingStateUpdating, StartTime: at(3 * time.Hour)},
+	})
+}
+
+func TestAdvanceStage(t *testing.T) {
+	soakingSince := &metav1.Time{Time: testNow.Add(-10 * time.Minute)}
+	tests := map[string]struct {
+		stage       placementv1beta1.StageConfig
+		status      placementv1beta1.StageUpdatingStatus
+		approved    bool
+		available   bool
+		wantState   placementv1beta1.StageUpdatingState
+		wantBlocked bool
+		wantRequeue time.Duration
+	}{
+		"a pending stage without approval starts and succeeds once available": {
+			status:    placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStatePending},
+			available: true,
+			wantState: placementv1beta1.StageUpdatingStateSucceeded,
+		},
+		"a pending stage waits for the approval": {
+			stage:       placementv1beta1.StageConfig{RequireApproval: true},
+			status:      placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStatePending},
+			available:   true,
+			wantState:   placementv1beta1.StageUpdatingStateWaitingForApproval,
+			wantBlocked: true,
+		},
+		"an approved stage starts": {
+			stage:       placementv1beta1.StageConfig{RequireApproval: true},
+			status:      placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStateWaitingForApproval},
+			approved:    true,
+			wantState:   placementv1beta1.StageUpdatingStateUpdating,
+			wantBlocked: true,
+		},
+		"an updating stage waits for its clusters to be available": {
+			status:      placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStateUpdating},
+			wantState:   placementv1beta1.StageUpdatingStateUpdating,
+			wantBlocked: true,
+		},
+		"a soaking stage waits for its soak time": {
+			stage:       placementv1beta1.StageConfig{SoakTime: &metav1.Duration{Duration: time.Hour}},
+			status:      placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStateSoaking, AvailableTime: soakingSince},
+			wantState:   placementv1beta1.StageUpdatingStateSoaking,
+			wantBlocked: true,
+			wantRequeue: 50 * time.Minute,
+		},
+		"a soaking stage succeeds after its soak time": {
+			stage:     placementv1beta1.StageConfig{SoakTime: &metav1.Duration{Duration: 5 * time.Minute}},
+			status:    placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStateSoaking, AvailableTime: soakingSince},
+			wantState: placementv1beta1.StageUpdatingStateSucceeded,
+		},
+		"a succeeded stage stays succeeded": {
+			status:    placementv1beta1.StageUpdatingStatus{State: placementv1beta1.StageUpdatingStateSucceeded},
+			wantState: placementv1beta1.StageUpdatingStateSucceeded,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			blocked, requeue := advanceStage(&tc.stage, &tc.status, tc.approved, tc.available, testNow)
+			if tc.status.State != tc.wantState {
+				t.Errorf("advanceStage() state = %s, want %s", tc.status.State, tc.wantState)
+			}
+			if blocked != tc.wantBlocked || requeue != tc.wantRequeue {
+				t.Errorf("advanceStage() = (%v, %v), want (%v, %v)", blocked, requeue, tc.wantBlocked, tc.wantRequeue)
+			}
+		})
+	}
+}
+
+func TestGroupClustersByStage(t *testing.T) {
+	stages := []placementv1beta1.StageConfig{
+		{Name: "canary", LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"environment": "canary"}}},
+		{Name: "east", LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "east"}}},
+		{Name: "retail", ClusterGroup: "edge-retail"},
+	}
+	selectors, err := stageSelectors(stages)
+	if err != nil {
+		t.Fatalf("stageSelectors() = %v, want nil", err)
+	}
+	targets := map[string]*placementv1beta1.ClusterResourceBinding{"east-canary": nil, "east-1": nil, "west-1": nil, "east-2": nil, "west-2": nil}
+	clusterLabels := map[string]labels.Set{
+		"east-canary": {"environment": "canary", "region": "east"},
+		"east-1":      {"region": "east"},
+		"east-2":      {"region": "east"},
+		"west-1":      {"region": "west"},
+		"west-2":      {"region": "west", clusterv1beta1.ClusterGroupLabelPrefix + "edge-retail": "true"},
+	}
+	want := [][]string{{"east-canary"}, {"east-1", "east-2"}, {"west-2"}}
+	if diff := cmp.Diff(want, groupClustersByStage(selectors, targets, clusterLabels)); diff != "" {
+		t.Errorf("groupClustersByStage() mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package statusexport features a controller that exports the rollout status of the cluster resource placements as
+// config maps in a designated namespace, so that other tooling, e.g. a GitOps pipeline, can commit them to a Git
+// repository and show the rollout results in its change workflow.
+package statusexport
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// StatusDataKey is the key of the rollout status summary in the data of the config maps.
+	StatusDataKey = "status.yaml"
+)
+
+// Reconciler exports the rollout status summary of a placement as the config map of the same name in the export
+// namespace. The config map is owned by the placement, so that it is garbage collected with the placement.
+type Reconciler struct {
+	Client client.Client
+	// UncachedReader reads the config maps from the API server, so that the config maps of the hub cluster are not
+	// cached by the hub agent.
+	UncachedReader client.Reader
+	// Namespace is the namespace which the config maps are written to; it needs to exist.
+	Namespace string
+}
+
+// placementSummary is the rollout status summary of a placement; it leaves out the timestamps so that it only
+// changes when the rollout does.
+type placementSummary struct {
+	Placement             string             `json:"placement"`
+	Generation            int64              `json:"generation"`
+	ObservedResourceIndex string             `json:"observedResourceIndex,omitempty"`
+	Conditions            []conditionSummary `json:"conditions,omitempty"`
+	SelectedClusters      *int32             `json:"selectedClusters,omitempty"`
+	UnhealthyClusters     *int32             `json:"unhealthyClusters,omitempty"`
+	Clusters              []clusterSummary   `json:"clusters,omitempty"`
+}
+
+// clusterSummary is the rollout status summary of a placement on a cluster.
+type clusterSummary struct {
+	Name             string             `json:"name"`
+	Conditions       []conditionSummary `json:"conditions,omitempty"`
+	FailedPlacements int                `json:"failedPlacements,omitempty"`
+}
+
+// conditionSummary is a condition without its timestamp and message.
+type conditionSummary struct {
+	Type               string                 `json:"type"`
+	Status             metav1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+}
+
+// Reconcile writes the rollout status summary of the placement to its config map if it is changed.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	startTime := time.Now()
+	klog.V(2).InfoS("StatusExport reconciliation starts", "clusterResourcePlacement", req.Name)
+	defer func() {
+		latency := time.Since(startTime).Milliseconds()
+		klog.V(2).InfoS("StatusExport reconciliation ends", "clusterResourcePlacement", req.Name, "latency", latency)
+	}()
+
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, req.NamespacedName, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the config map is garbage collected with the placement
+			return ctrl.Result{}, nil
+		}
+		klog.ErrorS(err, "Failed to get the placement", "clusterResourcePlacement", req.Name)
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !crp.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	data, err := yaml.Marshal(summarize(&crp))
+	if err != nil {
+		klog.ErrorS(err, "Failed to marshal the status summary", "clusterResourcePlacement", req.Name)
+		return ctrl.Result{}, controller.NewUnexpectedBehaviorError(err)
+	}
+	desired := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      crp.Name,
+			Namespace: r.Namespace,
+			Labels:    map[string]string{placementv1beta1.CRPTrackingLabel: crp.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         placementv1beta1.GroupVersion.String(),
+				Kind:               placementv1beta1.ClusterResourcePlacementKind,
+				Name:               crp.Name,
+				UID:                crp.UID,
+				BlockOwnerDeletion: ptr.To(false),
+			}},
+		},
+		Data: map[string]string{StatusDataKey: string(data)},
+	}
+	configMapRef := klog.KObj(desired)
+
+	var current corev1.ConfigMap
+	if err := r.UncachedReader.Get(ctx, client.ObjectKeyFromObject(desired), &current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the status config map", "configMap", configMapRef)
+			return ctrl.Result{}, controller.NewAPIServerError(false, err)
+		}
+		if err := r.Client.Create(ctx, desired); err != nil {
+			klog.ErrorS(err, "Failed to create the status config map", "configMap", configMapRef)
+			return ctrl.Result{}, controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Created the status config map", "clusterResourcePlacement", req.Name, "configMap", configMapRef)
+		return ctrl.Result{}, nil
+	}
+	if equality.Semantic.DeepEqual(current.Data, desired.Data) &&
+		equality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		equality.Semantic.DeepEqual(current.OwnerReferences, desired.OwnerReferences) {
+		return ctrl.Result{}, nil
+	}
+	current.Labels, current.OwnerReferences, current.Data = desired.Labels, desired.OwnerReferences, desired.Data
+	if err := r.Client.Update(ctx, &current); err != nil {
+		klog.ErrorS(err, "Failed to update the status config map", "configMap", configMapRef)
+		return ctrl.Result{}, controller.NewUpdateIgnoreConflictError(err)
+	}
+	klog.V(2).InfoS("Updated the status config map", "clusterResourcePlacement", req.Name, "configMap", configMapRef)
+	return ctrl.Result{}, nil
+}
+
+// summarize returns the rollout status summary of a placement, with the clusters sorted by their names.
+func summarize(crp *placementv1beta1.ClusterResourcePlacement) *placementSummary {
+	summary := &placementSummary{
+		Placement:             crp.Name,
+		Generation:            crp.Generation,
+		ObservedResourceIndex: crp.Status.ObservedResourceIndex,
+		Conditions:            summarizeConditions(crp.Status.Conditions),
+	}
+	if s := crp.Status.PlacementStatusSummary; s != nil {
+		summary.SelectedClusters = ptr.To(s.SelectedClusters)
+		summary.UnhealthyClusters = ptr.To(s.UnhealthyClusters)
+	}
+	for i := range crp.Status.PlacementStatuses {
+		status := &crp.Status.PlacementStatuses[i]
+		if status.ClusterName == "" {
+			// the placement has not picked enough clusters
+			continue
+		}
+		summary.Clusters = append(summary.Clusters, clusterSummary{
+			Name:             status.ClusterName,
+			Conditions:       summarizeConditions(status.Conditions),
+			FailedPlacements: len(status.FailedPlacements) + int(status.FailedPlacementsOverflow),
+		})
+	}
+	sort.Slice(summary.Clusters, func(i, j int) bool {
+		return summary.Clusters[i].Name < summary.Clusters[j].Name
+	})
+	return summary
+}
+
+func summarizeConditions(conditions []metav1.Condition) []conditionSummary {
+	if len(conditions) == 0 {
+		return nil
+	}
+	summaries := make([]conditionSummary, 0, len(conditions))
+	for _, c := range conditions {
+		summaries = append(summaries, conditionSummary{
+			Type:               c.Type,
+			Status:             c.Status,
+			Reason:             c.Reason,
+			ObservedGeneration: c.ObservedGeneration,
+		})
+	}
+	return summaries
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("status-export-controller").
+		For(&placementv1beta1.ClusterResourcePlacement{}).
+		Complete(r)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package statusexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	testCRPName   = "web"
+	testNamespace = "fleet-status"
+)
+
+func newTestCRP() *placementv1beta1.ClusterResourcePlacement {
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: testCRPName, Generation: 2, UID: "crp-uid"},
+		Status: placementv1beta1.ClusterResourcePlacementStatus{
+			ObservedResourceIndex: "1",
+			Conditions: []metav1.Condition{{
+				Type:               string(placementv1beta1.ClusterResourcePlacementAvailableConditionType),
+				Status:             metav1.ConditionTrue,
+				Reason:             "ResourceAvailable",
+				Message:            "all the resources are available",
+				ObservedGeneration: 2,
+				LastTransitionTime: metav1.Now(),
+			}},
+			PlacementStatuses: []placementv1beta1.ResourcePlacementStatus{
+				{
+					ClusterName: "member-2",
+					FailedPlacements: []placementv1beta1.FailedResourcePlacement{
+						{ResourceIdentifier: placementv1beta1.ResourceIdentifier{Kind: "ConfigMap", Name: "app"}},
+					},
+					FailedPlacementsOverflow: 2,
+				},
+				{
+					ClusterName: "member-1",
+					Conditions: []metav1.Condition{{
+						Type:               string(placementv1beta1.ResourcesAvailableConditionType),
+						Status:             metav1.ConditionTrue,
+						Reason:             "ResourceAvailable",
+						ObservedGeneration: 2,
+					}},
+				},
+				{
+					// a PickN placement which has not picked enough clusters
+					Conditions: []metav1.Condition{{
+						Type:   string(placementv1beta1.ResourceScheduledConditionType),
+						Status: metav1.ConditionFalse,
+						Reason: "ScheduleFailed",
+					}},
+				},
+			},
+		},
+	}
+}
+
+const wantStatusData = `clusters:
+- conditions:
+  - observedGeneration: 2
+    reason: ResourceAvailable
+    status: "True"
+    type: Available
+  name: member-1
+- failedPlacements: 3
+  name: member-2
+conditions:
+- observedGeneration: 2
+  reason: ResourceAvailable
+  status: "True"
+  type: ClusterResourcePlacementAvailable
+generation: 2
+observedResourceIndex: "1"
+placement: web
+`
+
+func TestReconcile(t *testing.T) {
+	wantLabels := map[string]string{placementv1beta1.CRPTrackingLabel: testCRPName}
+	tests := map[string]struct {
+		objects []client.Object
+	}{
+		"create the config map": {
+			objects: []client.Object{newTestCRP()},
+		},
+		"update the config map": {
+			objects: []client.Object{
+				newTestCRP(),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: testCRPName, Namespace: testNamespace},
+					Data:       map[string]string{StatusDataKey: "placement: web\n"},
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := placementv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the placement APIs to the scheme: %v", err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the core APIs to the scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objects...).Build()
+			r := &Reconciler{Client: fakeClient, UncachedReader: fakeClient, Namespace: testNamespace}
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testCRPName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want no error", err)
+			}
+
+			var got corev1.ConfigMap
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: testCRPName, Namespace: testNamespace}, &got); err != nil {
+				t.Fatalf("Get(configMap) = %v, want no error", err)
+			}
+			if diff := cmp.Diff(wantStatusData, got.Data[StatusDataKey]); diff != "" {
+				t.Errorf("status data mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantLabels, got.Labels); diff != "" {
+				t.Errorf("labels mismatch (-want, +got):\n%s", diff)
+			}
+			if len(got.OwnerReferences) != 1 || got.OwnerReferences[0].UID != "crp-uid" {
+				t.Errorf("owner references = %v, want the placement", got.OwnerReferences)
+			}
+
+			// the config map is not written again when the summary is unchanged
+			resourceVersion := got.ResourceVersion
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testCRPName}}); err != nil {
+				t.Fatalf("Reconcile() = %v, want no error", err)
+			}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: testCRPName, Namespace: testNamespace}, &got); err != nil {
+				t.Fatalf("Get(configMap) = %v, want no error", err)
+			}
+			if got.ResourceVersion != resourceVersion {
+				t.Errorf("config map resource version = %s, want unchanged %s", got.ResourceVersion, resourceVersion)
+			}
+		})
+	}
+}
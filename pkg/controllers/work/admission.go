@@ -0,0 +1,58 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// applyDeniedByWebhookRequeueInterval is the interval at which a work whose manifests are all denied by the admission
+// webhooks on the member cluster is applied again.
+const applyDeniedByWebhookRequeueInterval = time.Minute
+
+// webhookDeniedMessagePrefix is how the API server starts the message of a request denied by an admission webhook, which
+// is followed by the quoted name of the webhook, " denied the request" and the message of the webhook, if any.
+const webhookDeniedMessagePrefix = "admission webhook "
+
+// isDeniedByWebhook tells if the request to the member cluster is denied by an admission webhook.
+func isDeniedByWebhook(err error) bool {
+	status, ok := err.(apierrors.APIStatus)
+	if !ok {
+		return false
+	}
+	message := status.Status().Message
+	return strings.HasPrefix(message, webhookDeniedMessagePrefix) && strings.Contains(message, " denied the request")
+}
+
+// newApplyError returns the action and the error of a failed request to write the manifest to the member cluster.
+// A manifest denied by an admission webhook is a user error which retries do not fix until either the manifest or the
+// webhook changes; the message of the webhook is kept in the error so that it's reported back to the user.
+func newApplyError(err error) (ApplyAction, error) {
+	if isDeniedByWebhook(err) {
+		return applyDeniedByWebhookAction, controller.NewUserError(err)
+	}
+	return errorApplyAction, controller.NewAPIServerError(false, err)
+}
+
+// isAllDeniedByWebhook tells if all the manifests which failed to be applied are denied by the admission webhooks.
+func isAllDeniedByWebhook(results []applyResult) bool {
+	denied := false
+	for _, result := range results {
+		if result.applyErr == nil {
+			continue
+		}
+		if result.action != applyDeniedByWebhookAction {
+			return false
+		}
+		denied = true
+	}
+	return denied
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+func TestNewApplyError(t *testing.T) {
+	deniedErr := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Message: `admission webhook "validate.example.com" denied the request: replicas must not exceed 10`,
+	}}
+	tests := map[string]struct {
+		err        error
+		wantAction ApplyAction
+		wantErr    error
+	}{
+		"denied by an admission webhook": {
+			err:        deniedErr,
+			wantAction: applyDeniedByWebhookAction,
+			wantErr:    controller.ErrUserError,
+		},
+		"denied by an admission webhook without explanation": {
+			err: &apierrors.StatusError{ErrStatus: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusBadRequest,
+				Message: `admission webhook "validate.example.com" denied the request without explanation`,
+			}},
+			wantAction: applyDeniedByWebhookAction,
+			wantErr:    controller.ErrUserError,
+		},
+		"forbidden by RBAC": {
+			err:        apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app", errors.New("no permission")),
+			wantAction: errorApplyAction,
+			wantErr:    controller.ErrAPIServerError,
+		},
+		"failed to call the admission webhook": {
+			err:        apierrors.NewInternalError(errors.New(`failed calling webhook "validate.example.com": context deadline exceeded`)),
+			wantAction: errorApplyAction,
+			wantErr:    controller.ErrAPIServerError,
+		},
+		"not an API status error": {
+			err:        errors.New("admission webhook \"validate.example.com\" denied the request"),
+			wantAction: errorApplyAction,
+			wantErr:    controller.ErrAPIServerError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotAction, gotErr := newApplyError(tc.err)
+			if gotAction != tc.wantAction {
+				t.Errorf("newApplyError() action = %v, want %v", gotAction, tc.wantAction)
+			}
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("newApplyError() = %v, want %v", gotErr, tc.wantErr)
+			}
+		})
+	}
+
+	// the message of the webhook is kept
+	if _, err := newApplyError(deniedErr); !strings.Contains(err.Error(), deniedErr.ErrStatus.Message) {
+		t.Errorf("newApplyError() = %v, want the message of the webhook %q", err, deniedErr.ErrStatus.Message)
+	}
+}
+
+func TestIsAllDeniedByWebhook(t *testing.T) {
+	deniedErr := controller.NewUserError(errors.New("denied"))
+	tests := map[string]struct {
+		results []applyResult
+		want    bool
+	}{
+		"no failure": {
+			results: []applyResult{{action: manifestCreatedAction}},
+		},
+		"all failures are denied by the webhooks": {
+			results: []applyResult{
+				{action: manifestCreatedAction},
+				{action: applyDeniedByWebhookAction, applyErr: deniedErr},
+				{action: applyDeniedByWebhookAction, applyErr: deniedErr},
+			},
+			want: true,
+		},
+		"some failures are not denied by the webhooks": {
+			results: []applyResult{
+				{action: applyDeniedByWebhookAction, applyErr: deniedErr},
+				{action: errorApplyAction, applyErr: errors.New("timeout")},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isAllDeniedByWebhook(tc.results); got != tc.want {
+				t.Errorf("isAllDeniedByWebhook() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildManifestConditionDeniedByWebhook(t *testing.T) {
+	conditions := buildManifestCondition(controller.NewUserError(errors.New("denied")), applyDeniedByWebhookAction, 1)
+	for _, cond := range conditions {
+		if cond.Type == placementv1beta1.WorkConditionTypeApplied && (cond.Status != metav1.ConditionFalse || cond.Reason != ApplyDeniedByWebhookReason) {
+			t.Errorf("applied condition = %+v, want false with reason %s", cond, ApplyDeniedByWebhookReason)
+		}
+	}
+}
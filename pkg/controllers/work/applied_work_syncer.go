@@ -107,8 +107,9 @@ func (r *ApplyWorkReconciler) generateDiff(ctx context.Context, work *fleetv1bet
 	return newRes, staleRes, nil
 }
 
-func (r *ApplyWorkReconciler) deleteStaleManifest(ctx context.Context, staleManifests []fleetv1beta1.AppliedResourceMeta, owner metav1.OwnerReference) error {
+func (r *ApplyWorkReconciler) deleteStaleManifest(ctx context.Context, staleManifests []fleetv1beta1.AppliedResourceMeta, owner metav1.OwnerReference) ([]auditEntry, error) {
 	var errs []error
+	var auditEntries []auditEntry
 
 	for _, staleManifest := range staleManifests {
 		gvr := schema.GroupVersionResource{
@@ -143,24 +144,44 @@ func (r *ApplyWorkReconciler) deleteStaleManifest(ctx context.Context, staleMani
 			continue
 		}
 		if len(newOwners) == 0 {
+			if releaseNamespaceProtection(uObj, newOwners) {
+				klog.V(2).InfoS("release the deletion protection of the staled namespace", "manifest", staleManifest, "owner", owner)
+				if _, err = r.spokeDynamicClient.Resource(gvr).Update(ctx, uObj, metav1.UpdateOptions{FieldManager: workFieldManagerName}); err != nil {
+					klog.ErrorS(err, "failed to release the deletion protection of the staled namespace", "manifest", staleManifest, "owner", owner)
+					errs = append(errs, err)
+					continue
+				}
+			}
 			klog.V(2).InfoS("delete the staled manifest", "manifest", staleManifest, "owner", owner)
 			err = r.spokeDynamicClient.Resource(gvr).Namespace(staleManifest.Namespace).
 				Delete(ctx, staleManifest.Name, metav1.DeleteOptions{})
 			if err != nil && !apierrors.IsNotFound(err) {
 				klog.ErrorS(err, "failed to delete the staled manifest", "manifest", staleManifest, "owner", owner)
 				errs = append(errs, err)
+			} else if err == nil {
+				auditEntries = append(auditEntries, auditEntry{
+					identifier: staleManifest.WorkResourceIdentifier,
+					operation:  fleetv1beta1.AuditOperationDelete,
+				})
 			}
 		} else {
 			klog.V(2).InfoS("remove the owner reference from the staled manifest", "manifest", staleManifest, "owner", owner)
 			uObj.SetOwnerReferences(newOwners)
+			releaseNamespaceProtection(uObj, newOwners)
 			_, err = r.spokeDynamicClient.Resource(gvr).Namespace(staleManifest.Namespace).Update(ctx, uObj, metav1.UpdateOptions{FieldManager: workFieldManagerName})
 			if err != nil {
 				klog.ErrorS(err, "failed to remove the owner reference from manifest", "manifest", staleManifest, "owner", owner)
 				errs = append(errs, err)
+			} else {
+				auditEntries = append(auditEntries, auditEntry{
+					identifier:  staleManifest.WorkResourceIdentifier,
+					operation:   fleetv1beta1.AuditOperationUpdate,
+					diffSummary: "changed metadata.ownerReferences",
+				})
 			}
 		}
 	}
-	return utilerrors.NewAggregate(errs)
+	return auditEntries, utilerrors.NewAggregate(errs)
 }
 
 // isSameResourceIdentifier returns true if a and b identifies the same object.
@@ -300,7 +300,7 @@ func TestDeleteStaleManifest(t *testing.T) {
 			r := &ApplyWorkReconciler{
 				spokeDynamicClient: tt.spokeDynamicClient,
 			}
-			gotErr := r.deleteStaleManifest(context.Background(), tt.staleManifests, tt.owner)
+			_, gotErr := r.deleteStaleManifest(context.Background(), tt.staleManifests, tt.owner)
 			if tt.wantErr == nil {
 				if gotErr != nil {
 					t.Errorf("test case `%s` didn't return the exepected error,  want no error, got error = %+v ", name, gotErr)
@@ -26,7 +26,9 @@ import (
 
 // Applier is the interface to apply the resources on the member clusters.
 type Applier interface {
-	ApplyUnstructured(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy, gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (*unstructured.Unstructured, ApplyAction, error)
+	// ApplyUnstructured applies the manifest and returns the object on the member cluster after and before the apply.
+	// The object before the apply is nil when the resource does not exist yet.
+	ApplyUnstructured(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy, gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured, ApplyAction, error)
 }
 
 // serverSideApply uses server side apply to apply the manifest.
@@ -40,7 +42,8 @@ func serverSideApply(ctx context.Context, client dynamic.Interface, force bool,
 	manifestRes, err := client.Resource(gvr).Namespace(manifestObj.GetNamespace()).Apply(ctx, manifestObj.GetName(), manifestObj, options)
 	if err != nil {
 		klog.ErrorS(err, "Failed to apply object", "gvr", gvr, "manifest", manifestRef)
-		return nil, errorApplyAction, controller.NewAPIServerError(false, err)
+		action, err := newApplyError(err)
+		return nil, action, err
 	}
 	klog.V(2).InfoS("Manifest apply succeeded", "gvr", gvr, "manifest", manifestRef)
 	return manifestRes, manifestServerSideAppliedAction, nil
@@ -112,3 +115,37 @@ func validateOwnerReference(ctx context.Context, hubClient client.Client, namesp
 	}
 	return "", nil
 }
+
+// validateAllowedResource checks if the manifest is allowed by the allow list of the apply strategy.
+// The namespaces themselves are checked against the namespaces of the allow list by their names.
+func validateAllowedResource(allowList *fleetv1beta1.ApplyAllowList, manifestObj *unstructured.Unstructured) error {
+	if allowList == nil {
+		return nil
+	}
+	gvk := manifestObj.GroupVersionKind()
+	if len(allowList.Kinds) > 0 {
+		allowed := false
+		for _, kind := range allowList.Kinds {
+			if kind.Group == gvk.Group && kind.Kind == gvk.Kind && (kind.Version == "" || kind.Version == gvk.Version) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("kind %s is not allowed by the apply strategy of the placement", gvk)
+		}
+	}
+	namespace := manifestObj.GetNamespace()
+	if gvk.Group == "" && gvk.Kind == "Namespace" {
+		namespace = manifestObj.GetName()
+	}
+	if len(allowList.Namespaces) > 0 && namespace != "" {
+		for _, allowedNamespace := range allowList.Namespaces {
+			if allowedNamespace == namespace {
+				return nil
+			}
+		}
+		return fmt.Errorf("namespace %s is not allowed by the apply strategy of the placement", namespace)
+	}
+	return nil
+}
@@ -30,27 +30,28 @@ type ClientSideApplier struct {
 // ApplyUnstructured determines if an unstructured manifest object can & should be applied. It first validates
 // the size of the last modified annotation of the manifest, it removes the annotation if the size crosses the annotation size threshold
 // and then creates/updates the resource on the cluster using server side apply instead of three-way merge patch.
-func (applier *ClientSideApplier) ApplyUnstructured(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy, gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (*unstructured.Unstructured, ApplyAction, error) {
+func (applier *ClientSideApplier) ApplyUnstructured(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy, gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured, ApplyAction, error) {
 	manifestRef := klog.KObj(manifestObj)
 
 	// compute the hash without taking into consider the last applied annotation
 	if err := setManifestHashAnnotation(manifestObj); err != nil {
-		return nil, errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+		return nil, nil, errorApplyAction, controller.NewUnexpectedBehaviorError(err)
 	}
 
 	// extract the common create procedure to reuse
-	var createFunc = func() (*unstructured.Unstructured, ApplyAction, error) {
+	var createFunc = func() (*unstructured.Unstructured, *unstructured.Unstructured, ApplyAction, error) {
 		// record the raw manifest with the hash annotation in the manifest
 		if _, err := setModifiedConfigurationAnnotation(manifestObj); err != nil {
-			return nil, errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+			return nil, nil, errorApplyAction, controller.NewUnexpectedBehaviorError(err)
 		}
 		actual, err := applier.SpokeDynamicClient.Resource(gvr).Namespace(manifestObj.GetNamespace()).Create(
 			ctx, manifestObj, metav1.CreateOptions{FieldManager: workFieldManagerName})
 		if err == nil {
 			klog.V(2).InfoS("successfully created the manifest", "gvr", gvr, "manifest", manifestRef)
-			return actual, manifestCreatedAction, nil
+			return actual, nil, manifestCreatedAction, nil
 		}
-		return nil, errorApplyAction, controller.NewAPIServerError(false, err)
+		action, err := newApplyError(err)
+		return nil, nil, action, err
 	}
 
 	// support resources with generated name
@@ -65,14 +66,19 @@ func (applier *ClientSideApplier) ApplyUnstructured(ctx context.Context, applySt
 	case errors.IsNotFound(err):
 		return createFunc()
 	case err != nil:
-		return nil, errorApplyAction, controller.NewAPIServerError(false, err)
+		return nil, nil, errorApplyAction, controller.NewAPIServerError(false, err)
 	}
 
 	result, err := validateOwnerReference(ctx, applier.HubClient, applier.WorkNamespace, applyStrategy, curObj.GetOwnerReferences())
 	if err != nil {
 		klog.ErrorS(err, "Skip applying a manifest", "result", result,
 			"gvr", gvr, "manifest", manifestRef, "applyStrategy", applyStrategy, "ownerReferences", curObj.GetOwnerReferences())
-		return nil, result, err
+		return nil, curObj, result, err
+	}
+	if result, err := validatePlacementOwnership(manifestObj, curObj); err != nil {
+		klog.ErrorS(err, "Skip applying a manifest owned by another placement", "result", result,
+			"gvr", gvr, "manifest", manifestRef, "ownerPlacement", curObj.GetAnnotations()[fleetv1beta1.OwnerPlacementAnnotation])
+		return nil, curObj, result, err
 	}
 
 	// We only try to update the object if its spec hash value has changed.
@@ -83,17 +89,21 @@ func (applier *ClientSideApplier) ApplyUnstructured(ctx context.Context, applySt
 		// record the raw manifest with the hash annotation in the manifest.
 		isModifiedConfigAnnotationNotEmpty, err := setModifiedConfigurationAnnotation(manifestObj)
 		if err != nil {
-			return nil, errorApplyAction, err
+			return nil, curObj, errorApplyAction, err
 		}
+		var appliedObj *unstructured.Unstructured
+		var action ApplyAction
 		if !isModifiedConfigAnnotationNotEmpty {
 			klog.V(2).InfoS("Using server side apply for manifest", "gvr", gvr, "manifest", manifestRef)
-			return serverSideApply(ctx, applier.SpokeDynamicClient, true, gvr, manifestObj)
+			appliedObj, action, err = serverSideApply(ctx, applier.SpokeDynamicClient, true, gvr, manifestObj)
+		} else {
+			klog.V(2).InfoS("Using three way merge for manifest", "gvr", gvr, "manifest", manifestRef)
+			appliedObj, action, err = applier.patchCurrentResource(ctx, gvr, manifestObj, curObj)
 		}
-		klog.V(2).InfoS("Using three way merge for manifest", "gvr", gvr, "manifest", manifestRef)
-		return applier.patchCurrentResource(ctx, gvr, manifestObj, curObj)
+		return appliedObj, curObj, action, err
 	}
 
-	return curObj, errorApplyAction, nil
+	return curObj, curObj, errorApplyAction, nil
 }
 
 // patchCurrentResource uses three-way merge to patch the current resource with the new manifest we get from the work.
@@ -119,7 +129,8 @@ func (applier *ClientSideApplier) patchCurrentResource(ctx context.Context, gvr
 		Patch(ctx, manifestObj.GetName(), patch.Type(), data, metav1.PatchOptions{FieldManager: workFieldManagerName})
 	if patchErr != nil {
 		klog.ErrorS(patchErr, "Failed to patch the manifest", "gvr", gvr, "manifest", manifestRef)
-		return nil, errorApplyAction, controller.NewAPIServerError(false, patchErr)
+		action, err := newApplyError(patchErr)
+		return nil, action, err
 	}
 	klog.V(2).InfoS("Manifest patch succeeded", "gvr", gvr, "manifest", manifestRef)
 	return manifestObj, manifestThreeWayMergePatchAction, nil
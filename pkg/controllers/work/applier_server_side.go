@@ -28,29 +28,41 @@ type ServerSideApplier struct {
 }
 
 // ApplyUnstructured applies the manifest to the cluster using server side apply according to the given apply strategy.
-func (applier *ServerSideApplier) ApplyUnstructured(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy, gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (*unstructured.Unstructured, ApplyAction, error) {
+func (applier *ServerSideApplier) ApplyUnstructured(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy, gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured, ApplyAction, error) {
 	force := applyStrategy.ServerSideApplyConfig.ForceConflicts
 
 	manifestRef := klog.KObj(manifestObj)
 	// support resources with generated name
 	if manifestObj.GetName() == "" && manifestObj.GetGenerateName() != "" {
 		klog.V(2).InfoS("Create the resource with generated name regardless", "gvr", gvr, "manifest", manifestRef)
-		return serverSideApply(ctx, applier.SpokeDynamicClient, force, gvr, manifestObj)
+		appliedObj, action, err := serverSideApply(ctx, applier.SpokeDynamicClient, force, gvr, manifestObj)
+		return appliedObj, nil, action, err
 	}
 
 	curObj, err := applier.SpokeDynamicClient.Resource(gvr).Namespace(manifestObj.GetNamespace()).Get(ctx, manifestObj.GetName(), metav1.GetOptions{})
 	switch {
 	case errors.IsNotFound(err):
-		return serverSideApply(ctx, applier.SpokeDynamicClient, force, gvr, manifestObj)
+		appliedObj, action, err := serverSideApply(ctx, applier.SpokeDynamicClient, force, gvr, manifestObj)
+		return appliedObj, nil, action, err
 	case err != nil:
-		return nil, errorApplyAction, controller.NewAPIServerError(false, err)
+		return nil, nil, errorApplyAction, controller.NewAPIServerError(false, err)
 	}
 
 	result, err := validateOwnerReference(ctx, applier.HubClient, applier.WorkNamespace, applyStrategy, curObj.GetOwnerReferences())
 	if err != nil {
 		klog.ErrorS(err, "Skip applying a manifest", "result", result,
 			"gvr", gvr, "manifest", manifestRef, "applyStrategy", applyStrategy, "ownerReferences", curObj.GetOwnerReferences())
-		return nil, result, err
+		return nil, curObj, result, err
 	}
-	return serverSideApply(ctx, applier.SpokeDynamicClient, force, gvr, manifestObj)
+	if result, err := validatePlacementOwnership(manifestObj, curObj); err != nil {
+		klog.ErrorS(err, "Skip applying a manifest owned by another placement", "result", result,
+			"gvr", gvr, "manifest", manifestRef, "ownerPlacement", curObj.GetAnnotations()[fleetv1beta1.OwnerPlacementAnnotation])
+		return nil, curObj, result, err
+	}
+	// the resource may be client side applied by an older member agent or with the client side apply strategy
+	if err := migrateFieldManagers(ctx, applier.SpokeDynamicClient, gvr, manifestObj, curObj); err != nil {
+		return nil, curObj, errorApplyAction, err
+	}
+	appliedObj, action, err := serverSideApply(ctx, applier.SpokeDynamicClient, force, gvr, manifestObj)
+	return appliedObj, curObj, action, err
 }
@@ -244,7 +244,7 @@ func TestApplyUnstructured(t *testing.T) {
 			}
 
 			// We don't check the returned unstructured object because the fake client always return the same object we pass in.
-			_, gotApplyAction, err := applier.ApplyUnstructured(ctx, applyStrategy, gvr, tc.manifest)
+			_, _, gotApplyAction, err := applier.ApplyUnstructured(ctx, applyStrategy, gvr, tc.manifest)
 			if gotErr, wantErr := err != nil, tc.wantErr != nil; gotErr != wantErr || !errors.Is(err, tc.wantErr) {
 				t.Fatalf("ApplyUnstructured() got error %v, want error %v", err, tc.wantErr)
 			}
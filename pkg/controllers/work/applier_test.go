@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -425,3 +426,73 @@ func TestValidateOwnerReference(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAllowedResource(t *testing.T) {
+	deployment := &unstructured.Unstructured{}
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("app")
+	deployment.SetName("web")
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName("app")
+	clusterRole := &unstructured.Unstructured{}
+	clusterRole.SetAPIVersion("rbac.authorization.k8s.io/v1")
+	clusterRole.SetKind("ClusterRole")
+	clusterRole.SetName("admin")
+
+	allowList := &placementv1beta1.ApplyAllowList{
+		Namespaces: []string{"app"},
+		Kinds: []placementv1beta1.AllowedResourceKind{
+			{Group: "apps", Kind: "Deployment"},
+			{Version: "v1", Kind: "Namespace"},
+		},
+	}
+	tests := map[string]struct {
+		allowList *placementv1beta1.ApplyAllowList
+		obj       *unstructured.Unstructured
+		wantErr   bool
+	}{
+		"no allow list": {
+			obj: clusterRole,
+		},
+		"allowed namespaced resource": {
+			allowList: allowList,
+			obj:       deployment,
+		},
+		"allowed namespace": {
+			allowList: allowList,
+			obj:       namespace,
+		},
+		"kind is not allowed": {
+			allowList: allowList,
+			obj:       clusterRole,
+			wantErr:   true,
+		},
+		"version is not allowed": {
+			allowList: &placementv1beta1.ApplyAllowList{
+				Kinds: []placementv1beta1.AllowedResourceKind{{Group: "apps", Version: "v1beta1", Kind: "Deployment"}},
+			},
+			obj:     deployment,
+			wantErr: true,
+		},
+		"namespace is not allowed": {
+			allowList: &placementv1beta1.ApplyAllowList{Namespaces: []string{"other"}},
+			obj:       deployment,
+			wantErr:   true,
+		},
+		"cluster scoped resource is not restricted by the namespaces": {
+			allowList: &placementv1beta1.ApplyAllowList{Namespaces: []string{"other"}},
+			obj:       clusterRole,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAllowedResource(tc.allowList, tc.obj)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("validateAllowedResource() = %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -23,7 +23,9 @@ package work
 
 import (
 	"context"
+	"crypto/ecdh"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/atomic"
@@ -39,6 +41,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -48,13 +51,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	fleetnetworkingv1alpha1 "go.goms.io/fleet-networking/api/v1alpha1"
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/metrics"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
 	"go.goms.io/fleet/pkg/utils/defaulter"
+	"go.goms.io/fleet/pkg/utils/manifestsealing"
 	"go.goms.io/fleet/pkg/utils/resource"
+	"go.goms.io/fleet/pkg/utils/worksigning"
 )
 
 const (
@@ -63,15 +69,21 @@ const (
 
 // WorkCondition condition reasons
 const (
-	workAppliedFailedReason       = "WorkAppliedFailed"
-	workAppliedCompletedReason    = "WorkAppliedCompleted"
-	workNotAvailableYetReason     = "WorkNotAvailableYet"
+	// WorkAppliedFailedReason is the reason string of condition when some manifests of the work failed to apply.
+	WorkAppliedFailedReason = "WorkAppliedFailed"
+	// WorkAppliedCompletedReason is the reason string of condition when all the manifests of the work are applied.
+	WorkAppliedCompletedReason = "WorkAppliedCompleted"
+	// WorkNotAvailableYetReason is the reason string of condition when some manifests of the work are not available yet.
+	WorkNotAvailableYetReason     = "WorkNotAvailableYet"
 	workAvailabilityUnknownReason = "WorkAvailabilityUnknown"
 	// WorkAvailableReason is the reason string of condition when the manifest is available.
 	WorkAvailableReason = "WorkAvailable"
 	// WorkNotTrackableReason is the reason string of condition when the manifest is already up to date but we don't have
 	// a way to track its availabilities.
 	WorkNotTrackableReason = "WorkNotTrackable"
+	// WorkProvisioningReason is the reason string of condition when the manifests which are not available yet are all
+	// provisioning infrastructure according to their availability rules.
+	WorkProvisioningReason = "WorkProvisioning"
 	// ManifestApplyFailedReason is the reason string of condition when it failed to apply manifest.
 	ManifestApplyFailedReason = "ManifestApplyFailed"
 	// ApplyConflictBetweenPlacementsReason is the reason string of condition when the manifest is owned by multiple placements,
@@ -83,6 +95,28 @@ const (
 	// ManifestAlreadyUpToDateReason is the reason string of condition when the manifest is already up to date.
 	ManifestAlreadyUpToDateReason  = "ManifestAlreadyUpToDate"
 	manifestAlreadyUpToDateMessage = "Manifest is already up to date"
+	// ResourceNotAllowedReason is the reason string of condition when the manifest is not allowed by the allow list of
+	// the apply strategy.
+	ResourceNotAllowedReason = "ResourceNotAllowed"
+	// ManifestRecreatedReason is the reason string of condition when the manifest, which was applied before, is
+	// created again as it was deleted from the member cluster out of band, e.g. along with its namespace.
+	ManifestRecreatedReason = "ManifestRecreated"
+	// ResourcesRecreatedReason is the reason string of the event on the work when some of its manifests are recreated.
+	ResourcesRecreatedReason = "ResourcesRecreated"
+	// OwnershipConflictReason is the reason string of condition when the manifest is owned by another placement on
+	// the member cluster, possibly from another hub.
+	OwnershipConflictReason = "OwnershipConflict"
+	// WaitingForCRDVersionReason is the reason string of condition when the custom resource is not applied until its
+	// version, which the definition placed in the same work serves, is served by the member cluster.
+	WaitingForCRDVersionReason = "WaitingForCRDVersion"
+	// ApplyDeniedByWebhookReason is the reason string of condition when the manifest is denied by an admission webhook
+	// on the member cluster.
+	ApplyDeniedByWebhookReason = "ApplyDeniedByWebhook"
+	// WorkSignatureVerificationFailedReason is the reason string of condition when the signature of the work cannot be verified.
+	WorkSignatureVerificationFailedReason = "WorkSignatureVerificationFailed"
+	// ManifestSkippedReason is the reason string of the condition when the manifest is skipped on purpose, e.g. a
+	// persistentVolumeClaim whose storage class does not exist on the member cluster.
+	ManifestSkippedReason = "ManifestSkipped"
 	// ManifestNeedsUpdateReason is the reason string of condition when the manifest needs to be updated.
 	ManifestNeedsUpdateReason  = "ManifestNeedsUpdate"
 	manifestNeedsUpdateMessage = "Manifest has just been updated and in the processing of checking its availability"
@@ -99,6 +133,12 @@ type ApplyWorkReconciler struct {
 	workNameSpace      string
 	joined             *atomic.Bool
 	appliers           map[fleetv1beta1.ApplyStrategyType]Applier
+	auditSink          AuditSink
+	verifier           *worksigning.Verifier
+	decryptionKey      *ecdh.PrivateKey
+	relayPlacements    bool
+	faults             *faultInjector
+	applyLimiter       *applyLimiter
 }
 
 func NewApplyWorkReconciler(hubClient client.Client, spokeDynamicClient dynamic.Interface, spokeClient client.Client,
@@ -112,9 +152,31 @@ func NewApplyWorkReconciler(hubClient client.Client, spokeDynamicClient dynamic.
 		concurrency:        concurrency,
 		workNameSpace:      workNameSpace,
 		joined:             atomic.NewBool(false),
+		applyLimiter:       newApplyLimiter(),
 	}
 }
 
+// WithSignatureVerifier makes the reconciler refuse to apply the works which are not signed by the keys trusted by
+// the verifier.
+func (r *ApplyWorkReconciler) WithSignatureVerifier(verifier *worksigning.Verifier) *ApplyWorkReconciler {
+	r.verifier = verifier
+	return r
+}
+
+// WithManifestDecryptionKey makes the reconciler decrypt the sealed manifests with the private key of the member
+// cluster before applying them.
+func (r *ApplyWorkReconciler) WithManifestDecryptionKey(key *ecdh.PrivateKey) *ApplyWorkReconciler {
+	r.decryptionKey = key
+	return r
+}
+
+// WithAuditSink ships the audit records of the resources applied by the reconciler to the sink besides the
+// appliedWork status.
+func (r *ApplyWorkReconciler) WithAuditSink(sink AuditSink) *ApplyWorkReconciler {
+	r.auditSink = sink
+	return r
+}
+
 // ApplyAction represents the action we take to apply the manifest.
 // It is used only internally to track the result of the apply function.
 // +enum
@@ -140,14 +202,58 @@ const (
 	// manifestAlreadyOwnedByOthers indicates that the manifest is already owned by other non-fleet applier.
 	manifestAlreadyOwnedByOthers ApplyAction = "ManifestAlreadyOwnedByOthers"
 
+	// ownershipConflictAction indicates that it fails to apply the manifest as it's owned by another placement on the
+	// member cluster.
+	ownershipConflictAction ApplyAction = "OwnershipConflict"
+
+	// applyDeniedByWebhookAction indicates that it fails to apply the manifest as an admission webhook on the member
+	// cluster denies it.
+	applyDeniedByWebhookAction ApplyAction = "ApplyDeniedByWebhook"
+
+	// resourceNotAllowedAction indicates that the manifest is not allowed by the allow list of the apply strategy.
+	resourceNotAllowedAction ApplyAction = "ResourceNotAllowed"
+
+	// manifestRecreatedAction indicates that we created the manifest again as it was deleted out of band.
+	manifestRecreatedAction ApplyAction = "ManifestRecreated"
+
+	// waitingForCRDVersionAction indicates that the custom resource waits for its definition to serve its version.
+	waitingForCRDVersionAction ApplyAction = "WaitingForCRDVersion"
+
 	// manifestNotAvailableYetAction indicates that we still need to wait for the manifest to be available.
 	manifestNotAvailableYetAction ApplyAction = "ManifestNotAvailableYet"
 
+	// manifestProvisioningAction indicates that we still need to wait for the manifest which provisions infrastructure
+	// to be available, according to its availability rule.
+	manifestProvisioningAction ApplyAction = "ManifestProvisioning"
+
 	// manifestNotTrackableAction indicates that the manifest is already up to date but we don't have a way to track its availabilities.
 	manifestNotTrackableAction ApplyAction = "ManifestNotTrackable"
 
+	// manifestPausedAction indicates that the workload is paused or suspended on purpose, so it is regarded as
+	// available instead of blocking the rollout while its controller does not progress it.
+	manifestPausedAction ApplyAction = "ManifestPaused"
+
+	// manifestScaledToZeroAction indicates that the workload is scaled to zero replicas, so it is available without
+	// any replica.
+	manifestScaledToZeroAction ApplyAction = "ManifestScaledToZero"
+
 	// manifestAvailableAction indicates that the manifest is available.
 	manifestAvailableAction ApplyAction = "ManifestAvailable"
+
+	// manifestApplyOnlyAction indicates that the manifest is apply-only by its availability rule, so it is regarded as
+	// available once applied without tracking its availability.
+	manifestApplyOnlyAction ApplyAction = "ManifestApplyOnly"
+
+	// manifestSkippedAction indicates that the manifest is not applied on purpose, e.g. a persistentVolumeClaim whose
+	// storage class does not exist on the member cluster, so it is regarded as available.
+	manifestSkippedAction ApplyAction = "ManifestSkipped"
+)
+
+const (
+	// fluxReadyConditionType is the condition type with which the Flux objects report their readiness.
+	fluxReadyConditionType = "Ready"
+	// defaultAvailabilityRuleConditionType is the condition type checked by the availability rules by default.
+	defaultAvailabilityRuleConditionType = "Ready"
 )
 
 // applyResult contains the result of a manifest being applied.
@@ -156,6 +262,12 @@ type applyResult struct {
 	generation int64
 	action     ApplyAction
 	applyErr   error
+	// audit is set when the manifest is created or updated on the member cluster.
+	audit *auditEntry
+	// created is set when the manifest did not exist on the member cluster before it was applied.
+	created bool
+	// loadBalancer is set when the manifest is a service of the LoadBalancer type which is assigned an address.
+	loadBalancer *fleetv1beta1.ServiceLoadBalancerStatus
 }
 
 // Reconcile implement the control loop logic for Work object.
@@ -187,9 +299,25 @@ func (r *ApplyWorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Handle deleting work, garbage collect the resources
 	if !work.DeletionTimestamp.IsZero() {
 		klog.V(2).InfoS("Resource is in the process of being deleted", work.Kind, logObjRef)
+		r.faults.forget(work.UID)
 		return r.garbageCollectAppliedWork(ctx, work)
 	}
 
+	// verify the signature before touching the member cluster so that a work injected into the hub cluster is never applied
+	if r.verifier != nil {
+		if err := r.verifier.VerifyWork(work); err != nil {
+			return ctrl.Result{}, r.rejectUnverifiedWork(ctx, work, err)
+		}
+	}
+
+	// inject the faults requested by the annotations of the work if the fault injection is enabled
+	faults := r.faults.faultsOf(work)
+	if faults.dropApply() {
+		klog.InfoS("Dropped the apply of the work by the injected fault", "work", logObjRef)
+		return ctrl.Result{RequeueAfter: droppedApplyRequeueInterval}, nil
+	}
+	ctx = contextWithWorkFaults(ctx, faults)
+
 	// set default value so that the following call can skip checking nil
 	// TODO, could be removed once we have the defaulting webhook with fail policy.
 	// Make sure these conditions are met before moving
@@ -210,8 +338,24 @@ func (r *ApplyWorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		BlockOwnerDeletion: ptr.To(false),
 	}
 
-	// apply the manifests to the member cluster
-	results := r.applyManifests(ctx, work.Spec.Workload.Manifests, owner, work.Spec.ApplyStrategy)
+	// apply the manifests to the member cluster within the limits set by the hub
+	if err := r.applyLimiter.acquire(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+	results := r.applyManifests(ctx, work.Spec.Workload.Manifests, owner, work.Labels[fleetv1beta1.CRPTrackingLabel], work.Spec.ApplyStrategy)
+	r.applyLimiter.release()
+
+	// the changes made to the member cluster are audited even if the reconciliation returns early, as they are not
+	// made again by the next reconciliation; the entries are cleared once they are kept in the appliedWork status.
+	var auditEntries []auditEntry
+	for _, result := range results {
+		if result.audit != nil {
+			auditEntries = append(auditEntries, *result.audit)
+		}
+	}
+	defer func() {
+		r.persistAuditRecords(ctx, work, appliedWork.GetName(), auditEntries)
+	}()
 
 	// collect the latency from the work update time to now.
 	lastUpdateTime, ok := work.GetAnnotations()[utils.LastWorkUpdateTimeAnnotationKey]
@@ -228,14 +372,43 @@ func (r *ApplyWorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		klog.V(2).InfoS("Work has no last update time", "work", work.GetName())
 	}
 
+	// find the manifests deleted from the member cluster out of band before the work condition is rebuilt
+	recreated := markRecreatedManifests(results, work.Status.ManifestConditions)
+
 	// generate the work condition based on the manifest apply result
 	errs := constructWorkCondition(results, work)
 
-	// update the work status
-	if err = r.client.Status().Update(ctx, work, &client.SubResourceUpdateOptions{}); err != nil {
+	// relay the work to the member fleet and aggregate its availability before the work status is reported
+	if r.relayPlacements {
+		if err := r.relayWork(ctx, work); err != nil {
+			klog.ErrorS(err, "Failed to relay the work to the member fleet", "work", logObjRef)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// report the resources selected by the observations of the work
+	r.observeResources(ctx, work)
+
+	if err := faults.delayStatus(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// update the work status, which is only written by the member agent and is kept as a whole on conflicts
+	status := work.Status.DeepCopy()
+	scopeWorkStatus(status, work.Spec.ApplyStrategy)
+	manifestConditions := work.Status.ManifestConditions
+	if err = controller.UpdateStatusWithRetry(ctx, r.client, work, func(work *fleetv1beta1.Work) {
+		work.Status = *status.DeepCopy()
+	}); err != nil {
 		klog.ErrorS(err, "Failed to update work status", "work", logObjRef)
 		return ctrl.Result{}, err
 	}
+	// the applied resources are synced with all the manifests, including the ones left out of the reported status
+	work.Status.ManifestConditions = manifestConditions
+	if len(recreated) > 0 {
+		klog.InfoS("Recreated the resources deleted from the cluster out of band", "work", logObjRef, "resources", recreated)
+		r.recorder.Event(work, v1.EventTypeWarning, ResourcesRecreatedReason, recreatedResourcesMessage(recreated))
+	}
 	if len(errs) == 0 {
 		klog.InfoS("Successfully applied the work to the cluster", "work", logObjRef)
 		r.recorder.Event(work, v1.EventTypeNormal, "ApplyWorkSucceed", "apply the work successfully")
@@ -248,7 +421,9 @@ func (r *ApplyWorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 	// delete all the manifests that should not be in the cluster.
-	if err = r.deleteStaleManifest(ctx, staleRes, owner); err != nil {
+	deleteAuditEntries, err := r.deleteStaleManifest(ctx, staleRes, owner)
+	auditEntries = append(auditEntries, deleteAuditEntries...)
+	if err != nil {
 		klog.ErrorS(err, "Resource garbage-collection incomplete; some Work owned resources could not be deleted", work.Kind, logObjRef)
 		// we can't proceed to update the applied
 		return ctrl.Result{}, err
@@ -259,13 +434,23 @@ func (r *ApplyWorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 	// update the appliedWork with the new work after the stales are deleted
+	auditRecords := buildAuditRecords(work, auditEntries, time.Now())
 	appliedWork.Status.AppliedResources = newRes
+	appliedWork.Status.AuditRecords = appendAuditRecords(appliedWork.Status.AuditRecords, auditRecords)
 	if err = r.spokeClient.Status().Update(ctx, appliedWork, &client.SubResourceUpdateOptions{}); err != nil {
 		klog.ErrorS(err, "Failed to update appliedWork status", appliedWork.Kind, appliedWork.GetName())
 		return ctrl.Result{}, err
 	}
+	r.shipAuditRecords(work, auditRecords)
+	auditEntries = nil
 
 	if err = utilerrors.NewAggregate(errs); err != nil {
+		if isAllDeniedByWebhook(results) {
+			// retrying right away does not help until the manifests or the webhooks are changed
+			klog.ErrorS(err, "Manifest apply is denied by the admission webhooks; the message is queued again for reconciliation later",
+				"work", logObjRef, "requeueAfter", applyDeniedByWebhookRequeueInterval)
+			return ctrl.Result{RequeueAfter: applyDeniedByWebhookRequeueInterval}, nil
+		}
 		klog.ErrorS(err, "Manifest apply incomplete; the message is queued again for reconciliation",
 			"work", logObjRef)
 		return ctrl.Result{}, err
@@ -276,6 +461,14 @@ func (r *ApplyWorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		klog.V(2).InfoS("Work is not available yet, check again", "work", logObjRef, "availableCond", availableCond)
 		return ctrl.Result{RequeueAfter: time.Second * 3}, nil
 	}
+	if len(work.Spec.Observations) > 0 {
+		// the observed resources are reported more often as they are not applied by the member agent.
+		return ctrl.Result{RequeueAfter: observationRequeueInterval}, nil
+	}
+	if hasSkippedManifests(results) {
+		// check again soon whether the skipped manifests can be applied now
+		return ctrl.Result{RequeueAfter: skippedManifestRequeueInterval}, nil
+	}
 	// the work is available (might due to not trackable) but we still periodically reconcile to make sure the
 	// member cluster state is in sync with the work in case the resources on the member cluster is removed/changed.
 	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
@@ -287,8 +480,23 @@ func (r *ApplyWorkReconciler) garbageCollectAppliedWork(ctx context.Context, wor
 	if !controllerutil.ContainsFinalizer(work, fleetv1beta1.WorkFinalizer) {
 		return ctrl.Result{}, nil
 	}
-	// delete the appliedWork which will remove all the manifests associated with it
-	// TODO: allow orphaned manifest
+	if work.GetAnnotations()[fleetv1beta1.OrphanResourcesAnnotation] == "true" {
+		// the garbage collector removes the owner references of the appliedWork from the resources before deleting it
+		deletePolicy = metav1.DeletePropagationOrphan
+	} else if work.GetAnnotations()[fleetv1beta1.DeletionStrategyAnnotation] == string(fleetv1beta1.DeletionStrategyTypeReverseOrder) {
+		done, err := r.deleteAppliedResourcesInReverseOrder(ctx, work)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: deletionWaveRequeueInterval}, nil
+		}
+	}
+	// the protected namespaces would be stuck terminating once they are garbage collected along with the appliedWork
+	if err := r.releaseProtectedNamespaces(ctx, work); err != nil {
+		return ctrl.Result{}, err
+	}
+	// delete the appliedWork which will remove all the manifests associated with it unless they are orphaned
 	appliedWork := fleetv1beta1.AppliedWork{
 		ObjectMeta: metav1.ObjectMeta{Name: work.Name},
 	}
@@ -302,6 +510,14 @@ func (r *ApplyWorkReconciler) garbageCollectAppliedWork(ctx context.Context, wor
 	default:
 		klog.InfoS("Successfully deleted the appliedWork", "appliedWork", work.Name)
 	}
+	// the relayed placement is deleted along with the last work of the placement, or stops selecting the manifests of the work
+	if r.relayPlacements {
+		if crpName := work.GetLabels()[fleetv1beta1.CRPTrackingLabel]; crpName != "" {
+			if _, err := r.syncRelayedPlacement(ctx, crpName); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
 	controllerutil.RemoveFinalizer(work, fleetv1beta1.WorkFinalizer)
 	return ctrl.Result{}, r.client.Update(ctx, work, &client.UpdateOptions{})
 }
@@ -349,13 +565,27 @@ func (r *ApplyWorkReconciler) ensureAppliedWork(ctx context.Context, work *fleet
 }
 
 // applyManifests processes a given set of Manifests by: setting ownership, validating the manifest, and passing it on for application to the cluster.
-func (r *ApplyWorkReconciler) applyManifests(ctx context.Context, manifests []fleetv1beta1.Manifest, owner metav1.OwnerReference, applyStrategy *fleetv1beta1.ApplyStrategy) []applyResult {
-	var appliedObj *unstructured.Unstructured
+// The manifests are marked as owned by the placement, if any, which the work belongs to.
+func (r *ApplyWorkReconciler) applyManifests(ctx context.Context, manifests []fleetv1beta1.Manifest, owner metav1.OwnerReference, placement string, applyStrategy *fleetv1beta1.ApplyStrategy) []applyResult {
+	var appliedObj, curObj *unstructured.Unstructured
 
 	results := make([]applyResult, len(manifests))
+	decoded := make([]decodedManifest, len(manifests))
 	for index, manifest := range manifests {
+		decoded[index].gvr, decoded[index].obj, decoded[index].err = r.decodeManifest(manifest)
+	}
+	crdVersions := newCRDVersionGate(decoded)
+	for index := range manifests {
 		var result applyResult
-		gvr, rawObj, err := r.decodeManifest(manifest)
+		gvr, rawObj, err := decoded[index].gvr, decoded[index].obj, decoded[index].err
+		if waitErr := crdVersions.wait(ctx, r.spokeDynamicClient, rawObj, err); waitErr != nil {
+			result.applyErr = waitErr
+			result.action = waitingForCRDVersionAction
+			result.identifier = buildResourceIdentifier(index, rawObj, gvr)
+			klog.V(2).InfoS("Manifest waits for its custom resource definition to serve its version", "gvk", rawObj.GroupVersionKind(), "manifest", klog.KObj(rawObj), "reason", waitErr)
+			results[index] = result
+			continue
+		}
 		switch {
 		case err != nil:
 			result.applyErr = err
@@ -371,15 +601,48 @@ func (r *ApplyWorkReconciler) applyManifests(ctx context.Context, manifests []fl
 			}
 
 		default:
+			if applyStrategy != nil {
+				if err := validateAllowedResource(applyStrategy.AllowedResources, rawObj); err != nil {
+					result.applyErr = controller.NewUserError(err)
+					result.action = resourceNotAllowedAction
+					result.identifier = buildResourceIdentifier(index, rawObj, gvr)
+					klog.ErrorS(err, "Manifest is not allowed to be applied", "gvr", gvr, "manifest", klog.KObj(rawObj))
+					break
+				}
+			}
+			if storageClass, err := r.findMissingStorageClass(ctx, applyStrategy, gvr, rawObj); err != nil || storageClass != "" {
+				result.identifier = buildResourceIdentifier(index, rawObj, gvr)
+				if err != nil {
+					result.applyErr = err
+					result.action = errorApplyAction
+					break
+				}
+				result.action = manifestSkippedAction
+				klog.V(2).InfoS("Skipped the persistentVolumeClaim as its storage class does not exist", "manifest", klog.KObj(rawObj), "storageClass", storageClass)
+				break
+			}
 			addOwnerRef(owner, rawObj)
-			appliedObj, result.action, result.applyErr = r.applyUnstructuredAndTrackAvailability(ctx, gvr, rawObj, applyStrategy)
+			setOwnerPlacementAnnotation(rawObj, placement)
+			setNamespaceProtectionFinalizer(applyStrategy, rawObj)
+			if faultErr := workFaultsFromContext(ctx).apiError(); faultErr != nil {
+				appliedObj, curObj, result.action, result.applyErr = nil, nil, errorApplyAction, faultErr
+			} else if waitErr := r.applyLimiter.waitManifest(ctx); waitErr != nil {
+				appliedObj, curObj, result.action, result.applyErr = nil, nil, errorApplyAction, waitErr
+			} else {
+				appliedObj, curObj, result.action, result.applyErr = r.applyUnstructuredAndTrackAvailability(ctx, gvr, rawObj, applyStrategy)
+			}
 			result.identifier = buildResourceIdentifier(index, rawObj, gvr)
+			result.audit = buildApplyAuditEntry(result.identifier, curObj, appliedObj)
 			logObjRef := klog.ObjectRef{
 				Name:      result.identifier.Name,
 				Namespace: result.identifier.Namespace,
 			}
 			if result.applyErr == nil {
 				result.generation = appliedObj.GetGeneration()
+				result.created = curObj == nil && rawObj.GetName() != ""
+				if gvr == utils.ServiceGVR {
+					result.loadBalancer = buildLoadBalancerStatus(appliedObj)
+				}
 				klog.V(2).InfoS("Apply manifest succeeded", "gvr", gvr, "manifest", logObjRef,
 					"action", result.action, "applyStrategy", applyStrategy, "new ObservedGeneration", result.generation)
 			} else {
@@ -398,6 +661,11 @@ func (r *ApplyWorkReconciler) decodeManifest(manifest fleetv1beta1.Manifest) (sc
 	if err != nil {
 		return schema.GroupVersionResource{}, nil, fmt.Errorf("failed to decode object: %w", err)
 	}
+	if manifestsealing.IsSealed(unstructuredObj) {
+		if err := manifestsealing.Unseal(unstructuredObj, r.decryptionKey); err != nil {
+			return schema.GroupVersionResource{}, unstructuredObj, fmt.Errorf("failed to unseal object: %w", err)
+		}
+	}
 
 	mapping, err := r.restMapper.RESTMapping(unstructuredObj.GroupVersionKind().GroupKind(), unstructuredObj.GroupVersionKind().Version)
 	if err != nil {
@@ -410,29 +678,90 @@ func (r *ApplyWorkReconciler) decodeManifest(manifest fleetv1beta1.Manifest) (sc
 // applyUnstructuredAndTrackAvailability determines if an unstructured manifest object can & should be applied. It first validates
 // the size of the last modified annotation of the manifest, it removes the annotation if the size crosses the annotation size threshold
 // and then creates/updates the resource on the cluster using server side apply instead of three-way merge patch.
+// It returns the object after and before the apply.
 func (r *ApplyWorkReconciler) applyUnstructuredAndTrackAvailability(ctx context.Context, gvr schema.GroupVersionResource,
-	manifestObj *unstructured.Unstructured, applyStrategy *fleetv1beta1.ApplyStrategy) (*unstructured.Unstructured, ApplyAction, error) {
+	manifestObj *unstructured.Unstructured, applyStrategy *fleetv1beta1.ApplyStrategy) (*unstructured.Unstructured, *unstructured.Unstructured, ApplyAction, error) {
 	objManifest := klog.KObj(manifestObj)
 	applier := r.appliers[applyStrategy.Type]
 	if applier == nil {
 		err := fmt.Errorf("unknown apply strategy type %s", applyStrategy.Type)
 		klog.ErrorS(err, "Apply strategy type is unsupported", "gvr", gvr, "manifest", objManifest, "applyStrategyType", applyStrategy.Type)
-		return nil, errorApplyAction, controller.NewUserError(err)
+		return nil, nil, errorApplyAction, controller.NewUserError(err)
 	}
 
-	curObj, applyActionRes, err := applier.ApplyUnstructured(ctx, applyStrategy, gvr, manifestObj)
+	curObj, prevObj, applyActionRes, err := applier.ApplyUnstructured(ctx, applyStrategy, gvr, manifestObj)
 	if err != nil {
 		klog.ErrorS(err, "Failed to apply the manifest", "gvr", gvr, "manifest", objManifest, "applyStrategyType", applyStrategy.Type)
-		return nil, applyActionRes, err // do not overwrite the applyActionRes
+		return nil, nil, applyActionRes, err // do not overwrite the applyActionRes
 	}
 	klog.V(2).InfoS("Applied the manifest", "gvr", gvr, "manifest", objManifest, "applyStrategyType", applyStrategy.Type)
 
 	// the manifest is already up to date, we just need to track its availability
-	applyActionRes, err = trackResourceAvailability(gvr, curObj)
-	return curObj, applyActionRes, err
+	applyActionRes, err = trackResourceAvailability(gvr, curObj, applyStrategy.AvailabilityRules)
+	return curObj, prevObj, applyActionRes, err
+}
+
+// rejectUnverifiedWork marks the work as not applied because its signature cannot be verified.
+// It returns an error to retry as the hub agent may sign the work later without changing its generation.
+func (r *ApplyWorkReconciler) rejectUnverifiedWork(ctx context.Context, work *fleetv1beta1.Work, verifyErr error) error {
+	klog.ErrorS(verifyErr, "Refuse to apply the work whose signature cannot be verified", "work", klog.KObj(work))
+	r.recorder.Event(work, v1.EventTypeWarning, WorkSignatureVerificationFailedReason, verifyErr.Error())
+	appliedCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeApplied,
+		Status:             metav1.ConditionFalse,
+		Reason:             WorkSignatureVerificationFailedReason,
+		Message:            fmt.Sprintf("Work signature verification failed: %v", verifyErr),
+		ObservedGeneration: work.Generation,
+	}
+	if err := controller.UpdateStatusWithRetry(ctx, r.client, work, func(work *fleetv1beta1.Work) {
+		meta.SetStatusCondition(&work.Status.Conditions, appliedCondition)
+	}); err != nil {
+		klog.ErrorS(err, "Failed to update work status", "work", klog.KObj(work))
+		return controller.NewAPIServerError(false, err)
+	}
+	return controller.NewUserError(verifyErr)
+}
+
+// persistAuditRecords ships the audit entries and appends them to the status of the appliedWork, which is fetched
+// again as the reconciliation may have returned because of a conflict on it.
+func (r *ApplyWorkReconciler) persistAuditRecords(ctx context.Context, work *fleetv1beta1.Work, appliedWorkName string, entries []auditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	records := buildAuditRecords(work, entries, time.Now())
+	r.shipAuditRecords(work, records)
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		appliedWork := &fleetv1beta1.AppliedWork{}
+		if err := r.spokeClient.Get(ctx, types.NamespacedName{Name: appliedWorkName}, appliedWork); err != nil {
+			return err
+		}
+		appliedWork.Status.AuditRecords = appendAuditRecords(appliedWork.Status.AuditRecords, records)
+		return r.spokeClient.Status().Update(ctx, appliedWork, &client.SubResourceUpdateOptions{})
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to persist the audit records", "work", klog.KObj(work), "appliedWork", appliedWorkName, "numberOfRecords", len(records))
+	}
 }
 
-func trackResourceAvailability(gvr schema.GroupVersionResource, curObj *unstructured.Unstructured) (ApplyAction, error) {
+// shipAuditRecords writes the audit records to the audit sink if there is one.
+func (r *ApplyWorkReconciler) shipAuditRecords(work *fleetv1beta1.Work, records []fleetv1beta1.AppliedResourceAuditRecord) {
+	if r.auditSink == nil || len(records) == 0 {
+		return
+	}
+	if err := r.auditSink.Write(work, records); err != nil {
+		// the records are still kept in the appliedWork status
+		klog.ErrorS(err, "Failed to ship the audit records", "work", klog.KObj(work), "numberOfRecords", len(records))
+	}
+}
+
+func trackResourceAvailability(gvr schema.GroupVersionResource, curObj *unstructured.Unstructured, rules []fleetv1beta1.AvailabilityRule) (ApplyAction, error) {
+	if rule := findAvailabilityRule(rules, curObj); rule != nil {
+		if rule.ApplyOnly {
+			klog.V(2).InfoS("Resource is apply-only by its availability rule", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj))
+			return manifestApplyOnlyAction, nil
+		}
+		return trackAvailabilityByRule(rule, curObj)
+	}
 	switch gvr {
 	case utils.DeploymentGVR:
 		return trackDeploymentAvailability(curObj)
@@ -443,10 +772,22 @@ func trackResourceAvailability(gvr schema.GroupVersionResource, curObj *unstruct
 	case utils.DaemonSettGVR:
 		return trackDaemonSetAvailability(curObj)
 
+	case utils.JobGVR, utils.CronJobGVR:
+		return trackSuspendableAvailability(curObj)
+
 	case utils.ServiceGVR:
 		return trackServiceAvailability(curObj)
 
+	case utils.ServiceExportGVR:
+		return trackServiceExportAvailability(curObj)
+
+	case utils.PersistentVolumeClaimGVR:
+		return trackPersistentVolumeClaimAvailability(curObj)
+
 	default:
+		if utils.IsFluxGroup(gvr.Group) {
+			return trackFluxAvailability(curObj)
+		}
 		if isDataResource(gvr) {
 			klog.V(2).InfoS("Data resources are available immediately", "gvr", gvr, "resource", klog.KObj(curObj))
 			return manifestAvailableAction, nil
@@ -461,13 +802,28 @@ func trackDeploymentAvailability(curObj *unstructured.Unstructured) (ApplyAction
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &deployment); err != nil {
 		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
 	}
+	if !isStatusOfCurrentGeneration(curObj, deployment.Generation, deployment.Status.ObservedGeneration) {
+		return manifestNotAvailableYetAction, nil
+	}
+	if deployment.Spec.Paused {
+		klog.V(2).InfoS("Deployment is paused", "deployment", klog.KObj(curObj))
+		return manifestPausedAction, nil
+	}
 	requiredReplicas := int32(1)
 	if deployment.Spec.Replicas != nil {
 		requiredReplicas = *deployment.Spec.Replicas
 	}
-	if deployment.Status.ObservedGeneration == deployment.Generation &&
-		requiredReplicas == deployment.Status.AvailableReplicas &&
-		requiredReplicas == deployment.Status.UpdatedReplicas {
+	if requiredReplicas == 0 && deployment.Status.Replicas == 0 {
+		klog.V(2).InfoS("Deployment is scaled to zero", "deployment", klog.KObj(curObj))
+		return manifestScaledToZeroAction, nil
+	}
+	// the available replicas count the replicas of the old revisions as well, so a deployment is available only when
+	// no replica of the old revisions is left, i.e. all of its replicas are updated and available.
+	// The available replicas only count the replicas which have been ready for minReadySeconds.
+	if requiredReplicas == deployment.Status.AvailableReplicas &&
+		requiredReplicas == deployment.Status.UpdatedReplicas &&
+		requiredReplicas == deployment.Status.Replicas &&
+		isDeploymentSettled(&deployment) {
 		klog.V(2).InfoS("Deployment is available", "deployment", klog.KObj(curObj))
 		return manifestAvailableAction, nil
 	}
@@ -475,19 +831,56 @@ func trackDeploymentAvailability(curObj *unstructured.Unstructured) (ApplyAction
 	return manifestNotAvailableYetAction, nil
 }
 
+// isDeploymentSettled tells if the conditions of the deployment, which its controller sets honoring minReadySeconds
+// and progressDeadlineSeconds, agree that the deployment is available, as `kubectl rollout status` does. The deployment
+// is not available if it does not have the minimum availability, fails to create its replicas, or exceeds its progress
+// deadline, even if the counts of its replicas settle.
+func isDeploymentSettled(deployment *appv1.Deployment) bool {
+	for _, cond := range deployment.Status.Conditions {
+		switch {
+		case cond.Type == appv1.DeploymentAvailable && cond.Status != v1.ConditionTrue,
+			cond.Type == appv1.DeploymentProgressing && cond.Status == v1.ConditionFalse,
+			cond.Type == appv1.DeploymentReplicaFailure && cond.Status == v1.ConditionTrue:
+			klog.V(2).InfoS("The conditions of the deployment do not settle yet", "deployment", klog.KObj(deployment),
+				"conditionType", cond.Type, "status", cond.Status, "reason", cond.Reason)
+			return false
+		}
+	}
+	return true
+}
+
+// isStatusOfCurrentGeneration tells if the status of the workload is reported for its current generation. The status
+// of a workload which is just updated still describes its previous spec until its controller observes the update, so
+// its availability is not judged by the stale status.
+func isStatusOfCurrentGeneration(curObj *unstructured.Unstructured, generation, observedGeneration int64) bool {
+	if observedGeneration != generation {
+		klog.V(2).InfoS("The status of the workload is not reported for its current generation yet", "gvk", curObj.GroupVersionKind(),
+			"resource", klog.KObj(curObj), "generation", generation, "observedGeneration", observedGeneration)
+		return false
+	}
+	return true
+}
+
 func trackStatefulSetAvailability(curObj *unstructured.Unstructured) (ApplyAction, error) {
 	var statefulSet appv1.StatefulSet
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &statefulSet); err != nil {
 		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
 	}
+	if !isStatusOfCurrentGeneration(curObj, statefulSet.Generation, statefulSet.Status.ObservedGeneration) {
+		return manifestNotAvailableYetAction, nil
+	}
 	// a statefulSet is available if all the replicas are available and the currentReplicas is equal to the updatedReplicas
 	// which means there is no more update in progress.
 	requiredReplicas := int32(1)
 	if statefulSet.Spec.Replicas != nil {
 		requiredReplicas = *statefulSet.Spec.Replicas
 	}
-	if statefulSet.Status.ObservedGeneration == statefulSet.Generation &&
-		statefulSet.Status.AvailableReplicas == requiredReplicas &&
+	if requiredReplicas == 0 && statefulSet.Status.Replicas == 0 {
+		klog.V(2).InfoS("StatefulSet is scaled to zero", "statefulSet", klog.KObj(curObj))
+		return manifestScaledToZeroAction, nil
+	}
+	if statefulSet.Status.AvailableReplicas == requiredReplicas &&
+		statefulSet.Status.Replicas == requiredReplicas &&
 		statefulSet.Status.CurrentReplicas == statefulSet.Status.UpdatedReplicas &&
 		statefulSet.Status.CurrentRevision == statefulSet.Status.UpdateRevision {
 		klog.V(2).InfoS("StatefulSet is available", "statefulSet", klog.KObj(curObj))
@@ -502,10 +895,17 @@ func trackDaemonSetAvailability(curObj *unstructured.Unstructured) (ApplyAction,
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &daemonSet); err != nil {
 		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
 	}
+	if !isStatusOfCurrentGeneration(curObj, daemonSet.Generation, daemonSet.Status.ObservedGeneration) {
+		return manifestNotAvailableYetAction, nil
+	}
 	// a daemonSet is available if all the desired replicas (equal to all node suit for this Daemonset)
-	// are available and the currentReplicas is equal to the updatedReplicas which means there is no more update in progress.
-	if daemonSet.Status.ObservedGeneration == daemonSet.Generation &&
-		daemonSet.Status.NumberAvailable == daemonSet.Status.DesiredNumberScheduled &&
+	// are updated and available, and the currentReplicas is equal to the updatedReplicas which means there is no more
+	// update in progress.
+	// The available replicas only count the replicas which have been ready for minReadySeconds, as `kubectl rollout
+	// status` does.
+	if daemonSet.Status.NumberAvailable == daemonSet.Status.DesiredNumberScheduled &&
+		daemonSet.Status.NumberUnavailable == 0 &&
+		daemonSet.Status.UpdatedNumberScheduled == daemonSet.Status.DesiredNumberScheduled &&
 		daemonSet.Status.CurrentNumberScheduled == daemonSet.Status.UpdatedNumberScheduled {
 		klog.V(2).InfoS("DaemonSet is available", "daemonSet", klog.KObj(curObj))
 		return manifestAvailableAction, nil
@@ -514,6 +914,21 @@ func trackDaemonSetAvailability(curObj *unstructured.Unstructured) (ApplyAction,
 	return manifestNotAvailableYetAction, nil
 }
 
+// trackSuspendableAvailability regards a suspended job or cronJob as paused; the availability of the others is not
+// trackable.
+func trackSuspendableAvailability(curObj *unstructured.Unstructured) (ApplyAction, error) {
+	suspend, _, err := unstructured.NestedBool(curObj.Object, "spec", "suspend")
+	if err != nil {
+		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+	}
+	if suspend {
+		klog.V(2).InfoS("Resource is suspended", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj))
+		return manifestPausedAction, nil
+	}
+	klog.V(2).InfoS("We don't know how to track the availability of the resource", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj))
+	return manifestNotTrackableAction, nil
+}
+
 func trackServiceAvailability(curObj *unstructured.Unstructured) (ApplyAction, error) {
 	var service v1.Service
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &service); err != nil {
@@ -534,14 +949,7 @@ func trackServiceAvailability(curObj *unstructured.Unstructured) (ApplyAction, e
 		return manifestNotAvailableYetAction, nil
 
 	case v1.ServiceTypeLoadBalancer:
-		// we regard a loadBalancer service as available if it has at least one IP or hostname
-		if len(service.Status.LoadBalancer.Ingress) > 0 &&
-			(len(service.Status.LoadBalancer.Ingress[0].IP) > 0 || len(service.Status.LoadBalancer.Ingress[0].Hostname) > 0) {
-			klog.V(2).InfoS("LoadBalancer service is available", "service", klog.KObj(curObj))
-			return manifestAvailableAction, nil
-		}
-		klog.V(2).InfoS("Still need to wait for loadBalancer service to be available", "service", klog.KObj(curObj))
-		return manifestNotAvailableYetAction, nil
+		return trackLoadBalancerAvailability(&service), nil
 	}
 
 	// we don't know how to track the availability of when the service type is externalName
@@ -549,6 +957,102 @@ func trackServiceAvailability(curObj *unstructured.Unstructured) (ApplyAction, e
 	return manifestNotTrackableAction, nil
 }
 
+// trackPersistentVolumeClaimAvailability regards a persistentVolumeClaim as available once it is bound to a volume.
+// Note that a claim of a storage class which binds the volumes on the first consumer stays pending until a pod uses it.
+func trackPersistentVolumeClaimAvailability(curObj *unstructured.Unstructured) (ApplyAction, error) {
+	var pvc v1.PersistentVolumeClaim
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &pvc); err != nil {
+		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+	}
+	if pvc.Status.Phase == v1.ClaimBound {
+		klog.V(2).InfoS("PersistentVolumeClaim is bound", "persistentVolumeClaim", klog.KObj(curObj))
+		return manifestAvailableAction, nil
+	}
+	klog.V(2).InfoS("Still need to wait for persistentVolumeClaim to be bound", "persistentVolumeClaim", klog.KObj(curObj), "phase", pvc.Status.Phase)
+	return manifestNotAvailableYetAction, nil
+}
+
+// trackServiceExportAvailability regards a serviceExport as available when the fleet networking agents have validated
+// the exported service and imported it into the serviceImport on the hub cluster without any conflict with the
+// services exported from the other member clusters, i.e. the service is reachable through the serviceImport.
+func trackServiceExportAvailability(curObj *unstructured.Unstructured) (ApplyAction, error) {
+	var serviceExport fleetnetworkingv1alpha1.ServiceExport
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &serviceExport); err != nil {
+		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+	}
+	validCond := meta.FindStatusCondition(serviceExport.Status.Conditions, string(fleetnetworkingv1alpha1.ServiceExportValid))
+	conflictCond := meta.FindStatusCondition(serviceExport.Status.Conditions, string(fleetnetworkingv1alpha1.ServiceExportConflict))
+	if condition.IsConditionStatusTrue(validCond, serviceExport.Generation) && condition.IsConditionStatusFalse(conflictCond, serviceExport.Generation) {
+		klog.V(2).InfoS("ServiceExport is available", "serviceExport", klog.KObj(curObj))
+		return manifestAvailableAction, nil
+	}
+	klog.V(2).InfoS("Still need to wait for serviceExport to be imported", "serviceExport", klog.KObj(curObj))
+	return manifestNotAvailableYetAction, nil
+}
+
+// conditionedObject is the common part of the objects which report their state with conditions, e.g. the Flux objects
+// and the Crossplane claims.
+type conditionedObject struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            struct {
+		ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+		Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// trackFluxAvailability regards a Flux object, e.g. a Kustomization, a HelmRelease or a source, as available when
+// Flux has reconciled its current generation and reports it ready.
+func trackFluxAvailability(curObj *unstructured.Unstructured) (ApplyAction, error) {
+	var fluxObj conditionedObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &fluxObj); err != nil {
+		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+	}
+	if fluxObj.Status.ObservedGeneration == fluxObj.Generation && meta.IsStatusConditionTrue(fluxObj.Status.Conditions, fluxReadyConditionType) {
+		klog.V(2).InfoS("Flux object is ready", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj))
+		return manifestAvailableAction, nil
+	}
+	klog.V(2).InfoS("Still need to wait for Flux object to be ready", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj))
+	return manifestNotAvailableYetAction, nil
+}
+
+// findAvailabilityRule returns the first availability rule which matches the group and the kind of the object.
+func findAvailabilityRule(rules []fleetv1beta1.AvailabilityRule, curObj *unstructured.Unstructured) *fleetv1beta1.AvailabilityRule {
+	gvk := curObj.GroupVersionKind()
+	for i := range rules {
+		if rules[i].Group == gvk.Group && (rules[i].Kind == "" || rules[i].Kind == gvk.Kind) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// trackAvailabilityByRule regards an object as available when the condition of the rule is true for its current
+// generation. The object is provisioning instead of not available yet if the rule says so.
+func trackAvailabilityByRule(rule *fleetv1beta1.AvailabilityRule, curObj *unstructured.Unstructured) (ApplyAction, error) {
+	var obj conditionedObject
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(curObj.Object, &obj); err != nil {
+		return errorApplyAction, controller.NewUnexpectedBehaviorError(err)
+	}
+	conditionType := rule.ConditionType
+	if conditionType == "" {
+		conditionType = defaultAvailabilityRuleConditionType
+	}
+	// not all the controllers report the observed generations, e.g. Crossplane, so they are checked only if reported
+	cond := meta.FindStatusCondition(obj.Status.Conditions, conditionType)
+	if cond != nil && cond.Status == metav1.ConditionTrue &&
+		(cond.ObservedGeneration == 0 || cond.ObservedGeneration == obj.Generation) &&
+		(obj.Status.ObservedGeneration == 0 || obj.Status.ObservedGeneration == obj.Generation) {
+		klog.V(2).InfoS("Resource is available by its availability rule", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj), "conditionType", conditionType)
+		return manifestAvailableAction, nil
+	}
+	if rule.Provisioning {
+		klog.V(2).InfoS("Still need to wait for the resource to provision", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj), "conditionType", conditionType)
+		return manifestProvisioningAction, nil
+	}
+	klog.V(2).InfoS("Still need to wait for the resource to be available by its availability rule", "gvk", curObj.GroupVersionKind(), "resource", klog.KObj(curObj), "conditionType", conditionType)
+	return manifestNotAvailableYetAction, nil
+}
+
 // isDataResource checks if the resource is a data resource which means it is available immediately after creation.
 func isDataResource(gvr schema.GroupVersionResource) bool {
 	switch gvr {
@@ -566,6 +1070,8 @@ func isDataResource(gvr schema.GroupVersionResource) bool {
 		return true
 	case utils.ClusterRoleBindingGVR:
 		return true
+	case utils.StorageClassGVR:
+		return true
 	}
 	return false
 }
@@ -574,6 +1080,9 @@ func isDataResource(gvr schema.GroupVersionResource) bool {
 // TODO: special handle no results
 func constructWorkCondition(results []applyResult, work *fleetv1beta1.Work) []error {
 	var errs []error
+	reportDrift := statusReportingScope(work.Spec.ApplyStrategy) == fleetv1beta1.StatusReportingScopeDriftDetails
+	workApplied := isWorkApplied(work)
+	now := time.Now()
 	// Update manifestCondition based on the results.
 	manifestConditions := make([]fleetv1beta1.ManifestCondition, len(results))
 	for index, result := range results {
@@ -582,7 +1091,8 @@ func constructWorkCondition(results []applyResult, work *fleetv1beta1.Work) []er
 		}
 		newConditions := buildManifestCondition(result.applyErr, result.action, result.generation)
 		manifestCondition := fleetv1beta1.ManifestCondition{
-			Identifier: result.identifier,
+			Identifier:   result.identifier,
+			LoadBalancer: result.loadBalancer,
 		}
 		existingManifestCondition := findManifestConditionByIdentifier(result.identifier, work.Status.ManifestConditions)
 		if existingManifestCondition != nil {
@@ -592,6 +1102,9 @@ func constructWorkCondition(results []applyResult, work *fleetv1beta1.Work) []er
 		for _, condition := range newConditions {
 			meta.SetStatusCondition(&manifestCondition.Conditions, condition)
 		}
+		if reportDrift {
+			manifestCondition.Drift = buildManifestDrift(&results[index], existingManifestCondition, workApplied, now)
+		}
 		manifestConditions[index] = manifestCondition
 	}
 
@@ -604,6 +1117,45 @@ func constructWorkCondition(results []applyResult, work *fleetv1beta1.Work) []er
 	return errs
 }
 
+// maxRecreatedResourcesInEvent is the max number of the recreated resources named in the event of a work.
+const maxRecreatedResourcesInEvent = 10
+
+// markRecreatedManifests marks the manifests which were applied before but are created again, as they were deleted
+// from the member cluster out of band, e.g. when their namespace is deleted, so that the interference is reported
+// instead of being silently undone. It returns the recreated resources.
+func markRecreatedManifests(results []applyResult, manifestConditions []fleetv1beta1.ManifestCondition) []string {
+	var recreated []string
+	for i := range results {
+		if !results[i].created {
+			continue
+		}
+		existing := findManifestConditionByIdentifier(results[i].identifier, manifestConditions)
+		if existing == nil || !meta.IsStatusConditionTrue(existing.Conditions, fleetv1beta1.WorkConditionTypeApplied) {
+			continue
+		}
+		results[i].action = manifestRecreatedAction
+		recreated = append(recreated, describeResource(results[i].identifier))
+	}
+	return recreated
+}
+
+// recreatedResourcesMessage returns the message of the event which reports the recreated resources.
+func recreatedResourcesMessage(recreated []string) string {
+	if len(recreated) > maxRecreatedResourcesInEvent {
+		return fmt.Sprintf("Recreated %d resources deleted from the member cluster out of band: %s and %d more",
+			len(recreated), strings.Join(recreated[:maxRecreatedResourcesInEvent], ", "), len(recreated)-maxRecreatedResourcesInEvent)
+	}
+	return fmt.Sprintf("Recreated %d resources deleted from the member cluster out of band: %s", len(recreated), strings.Join(recreated, ", "))
+}
+
+// describeResource returns the kind and the namespaced name of the resource.
+func describeResource(identifier fleetv1beta1.WorkResourceIdentifier) string {
+	if identifier.Namespace == "" {
+		return fmt.Sprintf("%s %s", identifier.Kind, identifier.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", identifier.Kind, identifier.Namespace, identifier.Name)
+}
+
 // Join starts to reconcile
 func (r *ApplyWorkReconciler) Join(_ context.Context) error {
 	if !r.joined.Load() {
@@ -662,6 +1214,7 @@ func (r *ApplyWorkReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(ctrloption.Options{
 			MaxConcurrentReconciles: r.concurrency,
+			NewQueue:                r.newWorkQueue,
 		}).
 		For(&fleetv1beta1.Work{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
@@ -787,6 +1340,14 @@ func buildManifestCondition(err error, action ApplyAction, observedGeneration in
 			applyCondition.Reason = ApplyConflictBetweenPlacementsReason
 		case manifestAlreadyOwnedByOthers:
 			applyCondition.Reason = ManifestsAlreadyOwnedByOthersReason
+		case ownershipConflictAction:
+			applyCondition.Reason = OwnershipConflictReason
+		case applyDeniedByWebhookAction:
+			applyCondition.Reason = ApplyDeniedByWebhookReason
+		case resourceNotAllowedAction:
+			applyCondition.Reason = ResourceNotAllowedReason
+		case waitingForCRDVersionAction:
+			applyCondition.Reason = WaitingForCRDVersionReason
 		default:
 			applyCondition.Reason = ManifestApplyFailedReason
 		}
@@ -806,6 +1367,13 @@ func buildManifestCondition(err error, action ApplyAction, observedGeneration in
 			availableCondition.Reason = ManifestNeedsUpdateReason
 			availableCondition.Message = manifestNeedsUpdateMessage
 
+		case manifestRecreatedAction:
+			applyCondition.Reason = ManifestRecreatedReason
+			applyCondition.Message = "Manifest is created again as it was deleted from the member cluster out of band"
+			availableCondition.Status = metav1.ConditionUnknown
+			availableCondition.Reason = ManifestNeedsUpdateReason
+			availableCondition.Message = manifestNeedsUpdateMessage
+
 		case manifestThreeWayMergePatchAction:
 			applyCondition.Reason = string(manifestThreeWayMergePatchAction)
 			applyCondition.Message = "Manifest is patched successfully"
@@ -834,6 +1402,41 @@ func buildManifestCondition(err error, action ApplyAction, observedGeneration in
 			availableCondition.Reason = string(manifestNotAvailableYetAction)
 			availableCondition.Message = "Manifest is trackable but not available yet"
 
+		case manifestProvisioningAction:
+			applyCondition.Reason = ManifestAlreadyUpToDateReason
+			applyCondition.Message = manifestAlreadyUpToDateMessage
+			availableCondition.Status = metav1.ConditionFalse
+			availableCondition.Reason = string(manifestProvisioningAction)
+			availableCondition.Message = "Manifest is still provisioning"
+
+		case manifestPausedAction:
+			applyCondition.Reason = ManifestAlreadyUpToDateReason
+			applyCondition.Message = manifestAlreadyUpToDateMessage
+			availableCondition.Status = metav1.ConditionTrue
+			availableCondition.Reason = string(manifestPausedAction)
+			availableCondition.Message = "Manifest is paused or suspended on purpose, so it is regarded as available"
+
+		case manifestScaledToZeroAction:
+			applyCondition.Reason = ManifestAlreadyUpToDateReason
+			applyCondition.Message = manifestAlreadyUpToDateMessage
+			availableCondition.Status = metav1.ConditionTrue
+			availableCondition.Reason = string(manifestScaledToZeroAction)
+			availableCondition.Message = "Manifest is scaled to zero replicas, so it is available without any replica"
+
+		case manifestApplyOnlyAction:
+			applyCondition.Reason = ManifestAlreadyUpToDateReason
+			applyCondition.Message = manifestAlreadyUpToDateMessage
+			availableCondition.Status = metav1.ConditionTrue
+			availableCondition.Reason = string(manifestApplyOnlyAction)
+			availableCondition.Message = "Manifest is apply-only, so it is available once applied without tracking its availability"
+
+		case manifestSkippedAction:
+			applyCondition.Reason = ManifestSkippedReason
+			applyCondition.Message = "Manifest is skipped as the storage class it refers to does not exist on the member cluster"
+			availableCondition.Status = metav1.ConditionTrue
+			availableCondition.Reason = ManifestSkippedReason
+			availableCondition.Message = "Manifest is skipped on purpose, so it is regarded as available"
+
 		// we cannot stuck at unknown so we have to mark it as true
 		case manifestNotTrackableAction:
 			applyCondition.Reason = ManifestAlreadyUpToDateReason
@@ -871,15 +1474,15 @@ func buildWorkCondition(manifestConditions []fleetv1beta1.ManifestCondition, obs
 		if meta.IsStatusConditionFalse(manifestCond.Conditions, fleetv1beta1.WorkConditionTypeApplied) {
 			// we mark the entire work applied condition to false if one of the manifests is applied failed
 			applyCondition.Status = metav1.ConditionFalse
-			applyCondition.Reason = workAppliedFailedReason
+			applyCondition.Reason = WorkAppliedFailedReason
 			applyCondition.Message = fmt.Sprintf("Apply manifest %+v failed", manifestCond.Identifier)
 			availableCondition.Status = metav1.ConditionUnknown
-			availableCondition.Reason = workAppliedFailedReason
+			availableCondition.Reason = WorkAppliedFailedReason
 			return []metav1.Condition{applyCondition, availableCondition}
 		}
 	}
 	applyCondition.Status = metav1.ConditionTrue
-	applyCondition.Reason = workAppliedCompletedReason
+	applyCondition.Reason = WorkAppliedCompletedReason
 	applyCondition.Message = "Work is applied successfully"
 	// we mark the entire work available condition to unknown if one of the manifests is not known yet
 	for _, manifestCond := range manifestConditions {
@@ -892,14 +1495,25 @@ func buildWorkCondition(manifestConditions []fleetv1beta1.ManifestCondition, obs
 		}
 	}
 	// now that there is no unknown, we mark the entire work available condition to false if one of the manifests is not applied yet
+	// the work is provisioning only if all the manifests which are not available yet are provisioning
 	for _, manifestCond := range manifestConditions {
 		cond := meta.FindStatusCondition(manifestCond.Conditions, fleetv1beta1.WorkConditionTypeAvailable)
-		if cond.Status == metav1.ConditionFalse {
-			availableCondition.Status = metav1.ConditionFalse
-			availableCondition.Reason = workNotAvailableYetReason
+		if cond.Status != metav1.ConditionFalse {
+			continue
+		}
+		availableCondition.Status = metav1.ConditionFalse
+		if cond.Reason != string(manifestProvisioningAction) {
+			availableCondition.Reason = WorkNotAvailableYetReason
 			availableCondition.Message = fmt.Sprintf("Manifest %+v is not available yet", manifestCond.Identifier)
 			return []metav1.Condition{applyCondition, availableCondition}
 		}
+		if availableCondition.Reason == "" {
+			availableCondition.Reason = WorkProvisioningReason
+			availableCondition.Message = fmt.Sprintf("Manifest %+v is still provisioning", manifestCond.Identifier)
+		}
+	}
+	if availableCondition.Status == metav1.ConditionFalse {
+		return []metav1.Condition{applyCondition, availableCondition}
 	}
 	// now that all the conditions are true, we mark the entire work available condition reason to be not trackable if one of the manifests is not trackable
 	trackable := true
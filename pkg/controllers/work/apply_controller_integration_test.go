@@ -103,7 +103,7 @@ var _ = Describe("Work Controller", func() {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
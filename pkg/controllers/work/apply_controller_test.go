@@ -23,10 +23,17 @@ package work
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -54,6 +61,7 @@ import (
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/worksigning"
 	testcontroller "go.goms.io/fleet/test/utils/controller"
 )
 
@@ -452,6 +460,22 @@ func TestBuildManifestCondition(t *testing.T) {
 				},
 			},
 		},
+		"TestResourceNotAllowed": {
+			err:    errors.New("test error"),
+			action: resourceNotAllowedAction,
+			want: []metav1.Condition{
+				{
+					Type:   fleetv1beta1.WorkConditionTypeApplied,
+					Status: metav1.ConditionFalse,
+					Reason: ResourceNotAllowedReason,
+				},
+				{
+					Type:   fleetv1beta1.WorkConditionTypeAvailable,
+					Status: metav1.ConditionUnknown,
+					Reason: ManifestApplyFailedReason,
+				},
+			},
+		},
 	}
 
 	for name, tt := range tests {
@@ -490,12 +514,12 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionFalse,
-					Reason: workAppliedFailedReason,
+					Reason: WorkAppliedFailedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
 					Status: metav1.ConditionUnknown,
-					Reason: workAppliedFailedReason,
+					Reason: WorkAppliedFailedReason,
 				},
 			},
 		},
@@ -536,12 +560,12 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionFalse,
-					Reason: workAppliedFailedReason,
+					Reason: WorkAppliedFailedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
 					Status: metav1.ConditionUnknown,
-					Reason: workAppliedFailedReason,
+					Reason: WorkAppliedFailedReason,
 				},
 			},
 		},
@@ -569,7 +593,7 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
@@ -601,12 +625,12 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
 					Status: metav1.ConditionFalse,
-					Reason: workNotAvailableYetReason,
+					Reason: WorkNotAvailableYetReason,
 				},
 			},
 		},
@@ -649,12 +673,12 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
 					Status: metav1.ConditionFalse,
-					Reason: workNotAvailableYetReason,
+					Reason: WorkNotAvailableYetReason,
 				},
 			},
 		},
@@ -713,7 +737,7 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
@@ -761,7 +785,7 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
@@ -770,6 +794,150 @@ func TestGenerateWorkCondition(t *testing.T) {
 				},
 			},
 		},
+		"Test applied all succeeded and one of two apply-only": {
+			manifestConditions: []fleetv1beta1.ManifestCondition{
+				{
+					Identifier: fleetv1beta1.WorkResourceIdentifier{
+						Ordinal: 1,
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   fleetv1beta1.WorkConditionTypeApplied,
+							Status: metav1.ConditionTrue,
+						},
+						{
+							Type:   fleetv1beta1.WorkConditionTypeAvailable,
+							Status: metav1.ConditionTrue,
+							Reason: string(manifestAvailableAction),
+						},
+					},
+				},
+				{
+					Identifier: fleetv1beta1.WorkResourceIdentifier{
+						Ordinal: 2,
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   fleetv1beta1.WorkConditionTypeApplied,
+							Status: metav1.ConditionTrue,
+						},
+						{
+							Type:   fleetv1beta1.WorkConditionTypeAvailable,
+							Status: metav1.ConditionTrue,
+							Reason: string(manifestApplyOnlyAction),
+						},
+					},
+				},
+			},
+			expected: []metav1.Condition{
+				{
+					Type:   fleetv1beta1.WorkConditionTypeApplied,
+					Status: metav1.ConditionTrue,
+					Reason: WorkAppliedCompletedReason,
+				},
+				{
+					Type:   fleetv1beta1.WorkConditionTypeAvailable,
+					Status: metav1.ConditionTrue,
+					Reason: WorkAvailableReason,
+				},
+			},
+		},
+		"Test applied all succeeded but one of two provisioning": {
+			manifestConditions: []fleetv1beta1.ManifestCondition{
+				{
+					Identifier: fleetv1beta1.WorkResourceIdentifier{
+						Ordinal: 1,
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   fleetv1beta1.WorkConditionTypeApplied,
+							Status: metav1.ConditionTrue,
+						},
+						{
+							Type:   fleetv1beta1.WorkConditionTypeAvailable,
+							Status: metav1.ConditionTrue,
+							Reason: string(manifestAvailableAction),
+						},
+					},
+				},
+				{
+					Identifier: fleetv1beta1.WorkResourceIdentifier{
+						Ordinal: 2,
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   fleetv1beta1.WorkConditionTypeApplied,
+							Status: metav1.ConditionTrue,
+						},
+						{
+							Type:   fleetv1beta1.WorkConditionTypeAvailable,
+							Status: metav1.ConditionFalse,
+							Reason: string(manifestProvisioningAction),
+						},
+					},
+				},
+			},
+			expected: []metav1.Condition{
+				{
+					Type:   fleetv1beta1.WorkConditionTypeApplied,
+					Status: metav1.ConditionTrue,
+					Reason: WorkAppliedCompletedReason,
+				},
+				{
+					Type:   fleetv1beta1.WorkConditionTypeAvailable,
+					Status: metav1.ConditionFalse,
+					Reason: WorkProvisioningReason,
+				},
+			},
+		},
+		"Test applied all succeeded but one provisioning, one unavailable": {
+			manifestConditions: []fleetv1beta1.ManifestCondition{
+				{
+					Identifier: fleetv1beta1.WorkResourceIdentifier{
+						Ordinal: 1,
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   fleetv1beta1.WorkConditionTypeApplied,
+							Status: metav1.ConditionTrue,
+						},
+						{
+							Type:   fleetv1beta1.WorkConditionTypeAvailable,
+							Status: metav1.ConditionFalse,
+							Reason: string(manifestProvisioningAction),
+						},
+					},
+				},
+				{
+					Identifier: fleetv1beta1.WorkResourceIdentifier{
+						Ordinal: 2,
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   fleetv1beta1.WorkConditionTypeApplied,
+							Status: metav1.ConditionTrue,
+						},
+						{
+							Type:   fleetv1beta1.WorkConditionTypeAvailable,
+							Status: metav1.ConditionFalse,
+							Reason: string(manifestNotAvailableYetAction),
+						},
+					},
+				},
+			},
+			expected: []metav1.Condition{
+				{
+					Type:   fleetv1beta1.WorkConditionTypeApplied,
+					Status: metav1.ConditionTrue,
+					Reason: WorkAppliedCompletedReason,
+				},
+				{
+					Type:   fleetv1beta1.WorkConditionTypeAvailable,
+					Status: metav1.ConditionFalse,
+					Reason: WorkNotAvailableYetReason,
+				},
+			},
+		},
 		"Test applied all available": {
 			manifestConditions: []fleetv1beta1.ManifestCondition{
 				{
@@ -809,7 +977,7 @@ func TestGenerateWorkCondition(t *testing.T) {
 				{
 					Type:   fleetv1beta1.WorkConditionTypeApplied,
 					Status: metav1.ConditionTrue,
-					Reason: workAppliedCompletedReason,
+					Reason: WorkAppliedCompletedReason,
 				},
 				{
 					Type:   fleetv1beta1.WorkConditionTypeAvailable,
@@ -828,6 +996,100 @@ func TestGenerateWorkCondition(t *testing.T) {
 	}
 }
 
+func TestTrackAvailabilityByRule(t *testing.T) {
+	claim := func(generation int64, conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "database.example.org/v1alpha1",
+				"kind":       "PostgreSQLInstance",
+				"metadata": map[string]interface{}{
+					"generation": generation,
+					"name":       "app-db",
+					"namespace":  "app",
+				},
+				"status": map[string]interface{}{
+					"conditions": conditions,
+				},
+			},
+		}
+	}
+	ready := map[string]interface{}{"type": "Ready", "status": "True"}
+	notReady := map[string]interface{}{"type": "Ready", "status": "False", "reason": "Creating"}
+	synced := map[string]interface{}{"type": "Synced", "status": "True", "observedGeneration": int64(2)}
+	staleSynced := map[string]interface{}{"type": "Synced", "status": "True", "observedGeneration": int64(1)}
+	tests := map[string]struct {
+		rules []fleetv1beta1.AvailabilityRule
+		obj   *unstructured.Unstructured
+		want  ApplyAction
+	}{
+		"ready": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", Kind: "PostgreSQLInstance"}},
+			obj:   claim(1, ready),
+			want:  manifestAvailableAction,
+		},
+		"not ready": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org"}},
+			obj:   claim(1, notReady),
+			want:  manifestNotAvailableYetAction,
+		},
+		"provisioning": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", Provisioning: true}},
+			obj:   claim(1, notReady),
+			want:  manifestProvisioningAction,
+		},
+		"no conditions yet": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", Provisioning: true}},
+			obj:   claim(1),
+			want:  manifestProvisioningAction,
+		},
+		"custom condition type": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", ConditionType: "Synced"}},
+			obj:   claim(2, notReady, synced),
+			want:  manifestAvailableAction,
+		},
+		"condition not observing the latest generation": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", ConditionType: "Synced"}},
+			obj:   claim(2, staleSynced),
+			want:  manifestNotAvailableYetAction,
+		},
+		"first matching rule wins": {
+			rules: []fleetv1beta1.AvailabilityRule{
+				{Group: "database.example.org", Kind: "PostgreSQLInstance", ConditionType: "Synced"},
+				{Group: "database.example.org"},
+			},
+			obj:  claim(2, ready, synced),
+			want: manifestAvailableAction,
+		},
+		"apply-only": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", Kind: "PostgreSQLInstance", ApplyOnly: true}},
+			obj:   claim(1, notReady),
+			want:  manifestApplyOnlyAction,
+		},
+		"apply-only ignores the provisioning": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", Provisioning: true, ApplyOnly: true}},
+			obj:   claim(1),
+			want:  manifestApplyOnlyAction,
+		},
+		"kind not matching falls back to the built-in checks": {
+			rules: []fleetv1beta1.AvailabilityRule{{Group: "database.example.org", Kind: "MySQLInstance"}},
+			obj:   claim(1, ready),
+			want:  manifestNotTrackableAction,
+		},
+	}
+	gvr := schema.GroupVersionResource{Group: "database.example.org", Version: "v1alpha1", Resource: "postgresqlinstances"}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := trackResourceAvailability(gvr, tc.obj, tc.rules)
+			if err != nil {
+				t.Fatalf("trackResourceAvailability() = %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Errorf("trackResourceAvailability() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestIsDataResource(t *testing.T) {
 	tests := map[string]struct {
 		gvr  schema.GroupVersionResource
@@ -944,6 +1206,7 @@ func TestTrackResourceAvailability(t *testing.T) {
 					},
 					"status": map[string]interface{}{
 						"observedGeneration": 1,
+						"replicas":           3,
 						"availableReplicas":  3,
 						"updatedReplicas":    3,
 					},
@@ -964,6 +1227,7 @@ func TestTrackResourceAvailability(t *testing.T) {
 					},
 					"status": map[string]interface{}{
 						"observedGeneration": 1,
+						"replicas":           1,
 						"availableReplicas":  1,
 						"updatedReplicas":    1,
 					},
@@ -985,257 +1249,909 @@ func TestTrackResourceAvailability(t *testing.T) {
 					"spec": map[string]interface{}{
 						"replicas": 3,
 					},
-					"status": map[string]interface{}{
-						"observedGeneration": 1,
-						"availableReplicas":  3,
-						"updatedReplicas":    3,
+					"status": map[string]interface{}{
+						"observedGeneration": 1,
+						"availableReplicas":  3,
+						"updatedReplicas":    3,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment not available as not enough available": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"availableReplicas":  2,
+						"updatedReplicas":    3,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment not available as not enough updated": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"availableReplicas":  3,
+						"updatedReplicas":    2,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment not available as the replicas of the old revision are left": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           6,
+						"availableReplicas":  3,
+						"updatedReplicas":    3,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment available with minReadySeconds": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas":        3,
+						"minReadySeconds": 30,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           3,
+						"readyReplicas":      3,
+						"availableReplicas":  3,
+						"updatedReplicas":    3,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Available",
+								"status": "True",
+								"reason": "MinimumReplicasAvailable",
+							},
+							map[string]interface{}{
+								"type":   "Progressing",
+								"status": "True",
+								"reason": "NewReplicaSetAvailable",
+							},
+						},
+					},
+				},
+			},
+			expected: manifestAvailableAction,
+			err:      nil,
+		},
+		"Test Deployment not available as the ready replicas are not ready for minReadySeconds yet": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas":        3,
+						"minReadySeconds": 30,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           3,
+						"readyReplicas":      3,
+						"availableReplicas":  2,
+						"updatedReplicas":    3,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Available",
+								"status": "True",
+								"reason": "MinimumReplicasAvailable",
+							},
+							map[string]interface{}{
+								"type":   "Progressing",
+								"status": "True",
+								"reason": "ReplicaSetUpdated",
+							},
+						},
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment not available as it does not have the minimum availability": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas":        3,
+						"minReadySeconds": 30,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           3,
+						"readyReplicas":      3,
+						"availableReplicas":  3,
+						"updatedReplicas":    3,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Available",
+								"status": "False",
+								"reason": "MinimumReplicasUnavailable",
+							},
+							map[string]interface{}{
+								"type":   "Progressing",
+								"status": "True",
+								"reason": "NewReplicaSetAvailable",
+							},
+						},
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment not available as it exceeds its progress deadline": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas":        3,
+						"minReadySeconds": 30,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           3,
+						"readyReplicas":      3,
+						"availableReplicas":  3,
+						"updatedReplicas":    3,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Available",
+								"status": "True",
+								"reason": "MinimumReplicasAvailable",
+							},
+							map[string]interface{}{
+								"type":   "Progressing",
+								"status": "False",
+								"reason": "ProgressDeadlineExceeded",
+							},
+						},
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment not available as it fails to create its replicas": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas":        3,
+						"minReadySeconds": 30,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           3,
+						"readyReplicas":      3,
+						"availableReplicas":  3,
+						"updatedReplicas":    3,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Available",
+								"status": "True",
+								"reason": "MinimumReplicasAvailable",
+							},
+							map[string]interface{}{
+								"type":   "ReplicaFailure",
+								"status": "True",
+								"reason": "FailedCreate",
+							},
+						},
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test StatefulSet available": {
+			gvr: utils.StatefulSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "StatefulSet",
+					"metadata": map[string]interface{}{
+						"generation": 5,
+						"name":       "test-statefulset",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 5,
+						"replicas":           3,
+						"availableReplicas":  3,
+						"currentReplicas":    3,
+						"updatedReplicas":    3,
+					},
+				},
+			},
+			expected: manifestAvailableAction,
+			err:      nil,
+		},
+		"Test StatefulSet not available as it is scaling down": {
+			gvr: utils.StatefulSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "StatefulSet",
+					"metadata": map[string]interface{}{
+						"generation": 5,
+						"name":       "test-statefulset",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 5,
+						"replicas":           4,
+						"availableReplicas":  3,
+						"currentReplicas":    4,
+						"updatedReplicas":    4,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test StatefulSet not available": {
+			gvr: utils.StatefulSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "StatefulSet",
+					"metadata": map[string]interface{}{
+						"generation": 3,
+						"name":       "test-statefulset",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 3,
+						"availableReplicas":  2,
+						"currentReplicas":    3,
+						"updatedReplicas":    3,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test StatefulSet observed old generation": {
+			gvr: utils.StatefulSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "StatefulSet",
+					"metadata": map[string]interface{}{
+						"generation": 3,
+						"name":       "test-statefulset",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"availableReplicas":  2,
+						"currentReplicas":    3,
+						"updatedReplicas":    3,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test DaemonSet Available": {
+			gvr: utils.DaemonSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "DaemonSet",
+					"metadata": map[string]interface{}{
+						"generation": 1,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration":     1,
+						"numberAvailable":        1,
+						"desiredNumberScheduled": 1,
+						"currentNumberScheduled": 1,
+						"updatedNumberScheduled": 1,
+					},
+				},
+			},
+			expected: manifestAvailableAction,
+			err:      nil,
+		},
+		"Test DaemonSet not available": {
+			gvr: utils.DaemonSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "DaemonSet",
+					"metadata": map[string]interface{}{
+						"generation": 1,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration":     1,
+						"numberAvailable":        0,
+						"desiredNumberScheduled": 1,
+						"currentNumberScheduled": 1,
+						"updatedNumberScheduled": 1,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test DaemonSet not available as not all nodes are updated": {
+			gvr: utils.DaemonSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "DaemonSet",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration":     2,
+						"numberAvailable":        3,
+						"desiredNumberScheduled": 3,
+						"currentNumberScheduled": 3,
+						"updatedNumberScheduled": 1,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test DaemonSet not observe current generation": {
+			gvr: utils.DaemonSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "DaemonSet",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration":     1,
+						"numberAvailable":        0,
+						"desiredNumberScheduled": 1,
+						"currentNumberScheduled": 1,
+						"updatedNumberScheduled": 1,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test Deployment paused": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 3,
+						"paused":   true,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           3,
+						"availableReplicas":  3,
+						"updatedReplicas":    0,
+					},
+				},
+			},
+			expected: manifestPausedAction,
+			err:      nil,
+		},
+		"Test Deployment scaled to zero": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 0,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+					},
+				},
+			},
+			expected: manifestScaledToZeroAction,
+			err:      nil,
+		},
+		"Test Deployment still scaling to zero": {
+			gvr: utils.DeploymentGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-deployment",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 0,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 2,
+						"replicas":           1,
+					},
+				},
+			},
+			expected: manifestNotAvailableYetAction,
+			err:      nil,
+		},
+		"Test StatefulSet scaled to zero": {
+			gvr: utils.StatefulSettGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "StatefulSet",
+					"metadata": map[string]interface{}{
+						"generation": 4,
+						"name":       "test-statefulset",
+					},
+					"spec": map[string]interface{}{
+						"replicas": 0,
+					},
+					"status": map[string]interface{}{
+						"observedGeneration": 4,
+						"currentRevision":    "test-statefulset-1",
+						"updateRevision":     "test-statefulset-2",
+					},
+				},
+			},
+			expected: manifestScaledToZeroAction,
+			err:      nil,
+		},
+		"Test Job suspended": {
+			gvr: utils.JobGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "batch/v1",
+					"kind":       "Job",
+					"spec": map[string]interface{}{
+						"suspend": true,
+					},
+				},
+			},
+			expected: manifestPausedAction,
+			err:      nil,
+		},
+		"Test CronJob suspended": {
+			gvr: utils.CronJobGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "batch/v1",
+					"kind":       "CronJob",
+					"spec": map[string]interface{}{
+						"suspend": true,
+					},
+				},
+			},
+			expected: manifestPausedAction,
+			err:      nil,
+		},
+		"Test CronJob not trackable": {
+			gvr: utils.CronJobGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "batch/v1",
+					"kind":       "CronJob",
+					"spec": map[string]interface{}{
+						"schedule": "*/5 * * * *",
+					},
+				},
+			},
+			expected: manifestNotTrackableAction,
+			err:      nil,
+		},
+		"Test Job not trackable": {
+			gvr: utils.JobGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "batch/v1",
+					"kind":       "Job",
+					"status": map[string]interface{}{
+						"succeeded": 2,
+						"ready":     1,
+					},
+				},
+			},
+			expected: manifestNotTrackableAction,
+			err:      nil,
+		},
+		"Test configMap is considered ready after it is applied": {
+			gvr: utils.ConfigMapGVR,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name":      "test-configmap",
+						"namespace": "default",
+					},
+					"data": map[string]interface{}{
+						"key": "value",
 					},
 				},
 			},
-			expected: manifestNotAvailableYetAction,
+			expected: manifestAvailableAction,
 			err:      nil,
 		},
-		"Test Deployment not available as not enough available": {
-			gvr: utils.DeploymentGVR,
+		"Test secret is considered ready after it is applied": {
+			gvr: utils.ConfigMapGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "Deployment",
+					"apiVersion": "v1",
+					"kind":       "Secret",
 					"metadata": map[string]interface{}{
-						"generation": 2,
-						"name":       "test-deployment",
-					},
-					"spec": map[string]interface{}{
-						"replicas": 3,
+						"name":      "test-configmap",
+						"namespace": "default",
 					},
-					"status": map[string]interface{}{
-						"observedGeneration": 2,
-						"availableReplicas":  2,
-						"updatedReplicas":    3,
+					"data": map[string]interface{}{
+						"key": "value",
 					},
 				},
 			},
-			expected: manifestNotAvailableYetAction,
+			expected: manifestAvailableAction,
 			err:      nil,
 		},
-		"Test Deployment not available as not enough updated": {
-			gvr: utils.DeploymentGVR,
+		"Test namespace is considered ready after it is applied": {
+			gvr: utils.ConfigMapGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "Deployment",
+					"apiVersion": "v1",
+					"kind":       "NameSpace",
 					"metadata": map[string]interface{}{
-						"generation": 2,
-						"name":       "test-deployment",
-					},
-					"spec": map[string]interface{}{
-						"replicas": 3,
-					},
-					"status": map[string]interface{}{
-						"observedGeneration": 2,
-						"availableReplicas":  3,
-						"updatedReplicas":    2,
+						"name":      "test-namespae",
+						"namespace": "default",
 					},
 				},
 			},
-			expected: manifestNotAvailableYetAction,
+			expected: manifestAvailableAction,
 			err:      nil,
 		},
-		"Test StatefulSet available": {
-			gvr: utils.StatefulSettGVR,
+		"Test Flux Kustomization ready": {
+			gvr: schema.GroupVersionResource{
+				Group:    utils.FluxKustomizeGroup,
+				Version:  "v1",
+				Resource: "kustomizations",
+			},
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "StatefulSet",
+					"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+					"kind":       "Kustomization",
 					"metadata": map[string]interface{}{
-						"generation": 5,
-						"name":       "test-statefulset",
-					},
-					"spec": map[string]interface{}{
-						"replicas": 3,
+						"generation": 1,
+						"name":       "test-kustomization",
+						"namespace":  "flux-system",
 					},
 					"status": map[string]interface{}{
-						"observedGeneration": 5,
-						"availableReplicas":  3,
-						"currentReplicas":    3,
-						"updatedReplicas":    3,
+						"observedGeneration": 1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Ready",
+								"status": "True",
+							},
+						},
 					},
 				},
 			},
 			expected: manifestAvailableAction,
 			err:      nil,
 		},
-		"Test StatefulSet not available": {
-			gvr: utils.StatefulSettGVR,
+		"Test Flux Kustomization not ready": {
+			gvr: schema.GroupVersionResource{
+				Group:    utils.FluxKustomizeGroup,
+				Version:  "v1",
+				Resource: "kustomizations",
+			},
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "StatefulSet",
+					"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+					"kind":       "Kustomization",
 					"metadata": map[string]interface{}{
-						"generation": 3,
-						"name":       "test-statefulset",
-					},
-					"spec": map[string]interface{}{
-						"replicas": 3,
+						"generation": 1,
+						"name":       "test-kustomization",
+						"namespace":  "flux-system",
 					},
 					"status": map[string]interface{}{
-						"observedGeneration": 3,
-						"availableReplicas":  2,
-						"currentReplicas":    3,
-						"updatedReplicas":    3,
+						"observedGeneration": 1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Ready",
+								"status": "False",
+							},
+						},
 					},
 				},
 			},
 			expected: manifestNotAvailableYetAction,
 			err:      nil,
 		},
-		"Test StatefulSet observed old generation": {
-			gvr: utils.StatefulSettGVR,
+		"Test Flux Kustomization not observe the latest generation": {
+			gvr: schema.GroupVersionResource{
+				Group:    utils.FluxKustomizeGroup,
+				Version:  "v1",
+				Resource: "kustomizations",
+			},
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "StatefulSet",
+					"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+					"kind":       "Kustomization",
 					"metadata": map[string]interface{}{
-						"generation": 3,
-						"name":       "test-statefulset",
-					},
-					"spec": map[string]interface{}{
-						"replicas": 3,
+						"generation": 2,
+						"name":       "test-kustomization",
+						"namespace":  "flux-system",
 					},
 					"status": map[string]interface{}{
-						"observedGeneration": 2,
-						"availableReplicas":  2,
-						"currentReplicas":    3,
-						"updatedReplicas":    3,
+						"observedGeneration": 1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":   "Ready",
+								"status": "True",
+							},
+						},
 					},
 				},
 			},
 			expected: manifestNotAvailableYetAction,
 			err:      nil,
 		},
-		"Test DaemonSet Available": {
-			gvr: utils.DaemonSettGVR,
+		"Test ServiceExport imported": {
+			gvr: utils.ServiceExportGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "DaemonSet",
+					"apiVersion": "networking.fleet.azure.com/v1alpha1",
+					"kind":       "ServiceExport",
 					"metadata": map[string]interface{}{
 						"generation": 1,
+						"name":       "test-service",
+						"namespace":  "default",
 					},
 					"status": map[string]interface{}{
-						"observedGeneration":     1,
-						"numberAvailable":        1,
-						"desiredNumberScheduled": 1,
-						"currentNumberScheduled": 1,
-						"updatedNumberScheduled": 1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":               "Valid",
+								"status":             "True",
+								"observedGeneration": 1,
+							},
+							map[string]interface{}{
+								"type":               "Conflict",
+								"status":             "False",
+								"observedGeneration": 1,
+							},
+						},
 					},
 				},
 			},
 			expected: manifestAvailableAction,
 			err:      nil,
 		},
-		"Test DaemonSet not available": {
-			gvr: utils.DaemonSettGVR,
+		"Test ServiceExport in conflict": {
+			gvr: utils.ServiceExportGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "DaemonSet",
+					"apiVersion": "networking.fleet.azure.com/v1alpha1",
+					"kind":       "ServiceExport",
 					"metadata": map[string]interface{}{
 						"generation": 1,
+						"name":       "test-service",
+						"namespace":  "default",
 					},
 					"status": map[string]interface{}{
-						"observedGeneration":     1,
-						"numberAvailable":        0,
-						"desiredNumberScheduled": 1,
-						"currentNumberScheduled": 1,
-						"updatedNumberScheduled": 1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":               "Valid",
+								"status":             "True",
+								"observedGeneration": 1,
+							},
+							map[string]interface{}{
+								"type":               "Conflict",
+								"status":             "True",
+								"observedGeneration": 1,
+							},
+						},
 					},
 				},
 			},
 			expected: manifestNotAvailableYetAction,
 			err:      nil,
 		},
-		"Test DaemonSet not observe current generation": {
-			gvr: utils.DaemonSettGVR,
+		"Test ServiceExport invalid": {
+			gvr: utils.ServiceExportGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "apps/v1",
-					"kind":       "DaemonSet",
+					"apiVersion": "networking.fleet.azure.com/v1alpha1",
+					"kind":       "ServiceExport",
 					"metadata": map[string]interface{}{
-						"generation": 2,
+						"generation": 1,
+						"name":       "test-service",
+						"namespace":  "default",
 					},
 					"status": map[string]interface{}{
-						"observedGeneration":     1,
-						"numberAvailable":        0,
-						"desiredNumberScheduled": 1,
-						"currentNumberScheduled": 1,
-						"updatedNumberScheduled": 1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":               "Valid",
+								"status":             "False",
+								"observedGeneration": 1,
+							},
+							map[string]interface{}{
+								"type":               "Conflict",
+								"status":             "False",
+								"observedGeneration": 1,
+							},
+						},
 					},
 				},
 			},
 			expected: manifestNotAvailableYetAction,
 			err:      nil,
 		},
-		"Test Job not trackable": {
-			gvr: utils.JobGVR,
+		"Test ServiceExport not observe the latest generation": {
+			gvr: utils.ServiceExportGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "batch/v1",
-					"kind":       "Job",
+					"apiVersion": "networking.fleet.azure.com/v1alpha1",
+					"kind":       "ServiceExport",
+					"metadata": map[string]interface{}{
+						"generation": 2,
+						"name":       "test-service",
+						"namespace":  "default",
+					},
 					"status": map[string]interface{}{
-						"succeeded": 2,
-						"ready":     1,
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"type":               "Valid",
+								"status":             "True",
+								"observedGeneration": 1,
+							},
+							map[string]interface{}{
+								"type":               "Conflict",
+								"status":             "False",
+								"observedGeneration": 1,
+							},
+						},
 					},
 				},
 			},
-			expected: manifestNotTrackableAction,
+			expected: manifestNotAvailableYetAction,
 			err:      nil,
 		},
-		"Test configMap is considered ready after it is applied": {
-			gvr: utils.ConfigMapGVR,
+		"Test PersistentVolumeClaim bound": {
+			gvr: utils.PersistentVolumeClaimGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
 					"apiVersion": "v1",
-					"kind":       "ConfigMap",
+					"kind":       "PersistentVolumeClaim",
 					"metadata": map[string]interface{}{
-						"name":      "test-configmap",
+						"name":      "test-pvc",
 						"namespace": "default",
 					},
-					"data": map[string]interface{}{
-						"key": "value",
+					"status": map[string]interface{}{
+						"phase": "Bound",
 					},
 				},
 			},
 			expected: manifestAvailableAction,
 			err:      nil,
 		},
-		"Test secret is considered ready after it is applied": {
-			gvr: utils.ConfigMapGVR,
+		"Test PersistentVolumeClaim pending": {
+			gvr: utils.PersistentVolumeClaimGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
 					"apiVersion": "v1",
-					"kind":       "Secret",
+					"kind":       "PersistentVolumeClaim",
 					"metadata": map[string]interface{}{
-						"name":      "test-configmap",
+						"name":      "test-pvc",
 						"namespace": "default",
 					},
-					"data": map[string]interface{}{
-						"key": "value",
+					"status": map[string]interface{}{
+						"phase": "Pending",
 					},
 				},
 			},
-			expected: manifestAvailableAction,
+			expected: manifestNotAvailableYetAction,
 			err:      nil,
 		},
-		"Test namespace is considered ready after it is applied": {
-			gvr: utils.ConfigMapGVR,
+		"Test StorageClass": {
+			gvr: utils.StorageClassGVR,
 			obj: &unstructured.Unstructured{
 				Object: map[string]interface{}{
-					"apiVersion": "v1",
-					"kind":       "NameSpace",
+					"apiVersion":  "storage.k8s.io/v1",
+					"kind":        "StorageClass",
+					"provisioner": "disk.csi.azure.com",
 					"metadata": map[string]interface{}{
-						"name":      "test-namespae",
-						"namespace": "default",
+						"name": "managed-csi",
 					},
 				},
 			},
@@ -1256,7 +2172,7 @@ func TestTrackResourceAvailability(t *testing.T) {
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			action, err := trackResourceAvailability(tt.gvr, tt.obj)
+			action, err := trackResourceAvailability(tt.gvr, tt.obj, nil)
 			assert.Equal(t, tt.expected, action, "action not matching in test %s", name)
 			assert.Equal(t, errors.Is(err, tt.err), true, "applyErr not matching in test %s", name)
 		})
@@ -1838,7 +2754,7 @@ func TestApplyUnstructuredAndTrackAvailability(t *testing.T) {
 				Type:             fleetv1beta1.ApplyStrategyTypeClientSideApply,
 				AllowCoOwnership: testCase.allowCoOwnership,
 			}
-			applyResult, applyAction, err := r.applyUnstructuredAndTrackAvailability(context.Background(), utils.DeploymentGVR, testCase.workObj, strategy)
+			applyResult, _, applyAction, err := r.applyUnstructuredAndTrackAvailability(context.Background(), utils.DeploymentGVR, testCase.workObj, strategy)
 			assert.Equalf(t, testCase.resultAction, applyAction, "updated boolean not matching for Testcase %s", testName)
 			if testCase.resultErr != nil {
 				assert.Containsf(t, err.Error(), testCase.resultErr.Error(), "error not matching for Testcase %s", testName)
@@ -1970,7 +2886,7 @@ func TestApplyManifest(t *testing.T) {
 				},
 			}
 			applyStrategy := &fleetv1beta1.ApplyStrategy{Type: fleetv1beta1.ApplyStrategyTypeClientSideApply}
-			resultList := r.applyManifests(context.Background(), testCase.manifestList, ownerRef, applyStrategy)
+			resultList := r.applyManifests(context.Background(), testCase.manifestList, ownerRef, "", applyStrategy)
 			for _, result := range resultList {
 				if testCase.wantErr != nil {
 					assert.Containsf(t, result.applyErr.Error(), testCase.wantErr.Error(), "Incorrect error for Testcase %s", testName)
@@ -2084,12 +3000,46 @@ func TestReconcile(t *testing.T) {
 		return true, nil, errors.New(failMsg)
 	})
 
+	signingKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	publicKeyDER, _ := x509.MarshalPKIXPublicKey(signingKey.Public())
+	publicKeyFile := filepath.Join(t.TempDir(), "key.pub")
+	if err := os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}), 0600); err != nil {
+		t.Fatalf("failed to write the public key: %v", err)
+	}
+	verifier, err := worksigning.NewVerifier([]string{publicKeyFile})
+	if err != nil {
+		t.Fatalf("failed to create the verifier: %v", err)
+	}
+
 	testCases := map[string]struct {
 		reconciler ApplyWorkReconciler
 		req        ctrl.Request
 		wantErr    error
 		requeue    bool
 	}{
+		"work is not signed / fail": {
+			reconciler: ApplyWorkReconciler{
+				client: &test.MockClient{
+					MockGet: getMock,
+					MockStatusUpdate: func(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						work := obj.(*fleetv1beta1.Work)
+						cond := meta.FindStatusCondition(work.Status.Conditions, fleetv1beta1.WorkConditionTypeApplied)
+						if cond == nil || cond.Reason != WorkSignatureVerificationFailedReason {
+							return fmt.Errorf("unexpected applied condition %+v", cond)
+						}
+						return nil
+					},
+				},
+				spokeDynamicClient: happyDynamicClient,
+				spokeClient:        &test.MockClient{},
+				restMapper:         testMapper{},
+				recorder:           utils.NewFakeRecorder(1),
+				joined:             atomic.NewBool(true),
+				verifier:           verifier,
+			},
+			req:     req,
+			wantErr: errors.New("the work is not signed"),
+		},
 		"controller is being stopped": {
 			reconciler: ApplyWorkReconciler{
 				client:             &test.MockClient{},
@@ -2363,3 +3313,77 @@ func createLargeObj() (*unstructured.Unstructured, error) {
 	}
 	return &largeObj, nil
 }
+
+func TestMarkRecreatedManifests(t *testing.T) {
+	namespace := fleetv1beta1.WorkResourceIdentifier{Ordinal: 0, Version: "v1", Kind: "Namespace", Name: "app"}
+	configMap := fleetv1beta1.WorkResourceIdentifier{Ordinal: 1, Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "config"}
+	secret := fleetv1beta1.WorkResourceIdentifier{Ordinal: 2, Version: "v1", Kind: "Secret", Namespace: "app", Name: "secret"}
+	appliedCondition := func(identifier fleetv1beta1.WorkResourceIdentifier, status metav1.ConditionStatus) fleetv1beta1.ManifestCondition {
+		return fleetv1beta1.ManifestCondition{
+			Identifier: identifier,
+			Conditions: []metav1.Condition{{Type: fleetv1beta1.WorkConditionTypeApplied, Status: status}},
+		}
+	}
+	tests := map[string]struct {
+		results            []applyResult
+		manifestConditions []fleetv1beta1.ManifestCondition
+		wantRecreated      []string
+		wantActions        []ApplyAction
+	}{
+		"the namespace and its contents are deleted out of band": {
+			results: []applyResult{
+				{identifier: namespace, action: manifestServerSideAppliedAction, created: true},
+				{identifier: configMap, action: manifestServerSideAppliedAction, created: true},
+				{identifier: secret, action: manifestAvailableAction},
+			},
+			manifestConditions: []fleetv1beta1.ManifestCondition{
+				appliedCondition(namespace, metav1.ConditionTrue),
+				appliedCondition(configMap, metav1.ConditionTrue),
+				appliedCondition(secret, metav1.ConditionTrue),
+			},
+			wantRecreated: []string{"Namespace app", "ConfigMap app/config"},
+			wantActions:   []ApplyAction{manifestRecreatedAction, manifestRecreatedAction, manifestAvailableAction},
+		},
+		"the manifests are created for the first time": {
+			results: []applyResult{
+				{identifier: namespace, action: manifestCreatedAction, created: true},
+				{identifier: configMap, action: manifestCreatedAction, created: true},
+			},
+			wantActions: []ApplyAction{manifestCreatedAction, manifestCreatedAction},
+		},
+		"the manifest failed to apply before": {
+			results: []applyResult{
+				{identifier: configMap, action: manifestServerSideAppliedAction, created: true},
+			},
+			manifestConditions: []fleetv1beta1.ManifestCondition{appliedCondition(configMap, metav1.ConditionFalse)},
+			wantActions:        []ApplyAction{manifestServerSideAppliedAction},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotRecreated := markRecreatedManifests(tc.results, tc.manifestConditions)
+			if !reflect.DeepEqual(gotRecreated, tc.wantRecreated) {
+				t.Errorf("markRecreatedManifests() = %v, want %v", gotRecreated, tc.wantRecreated)
+			}
+			for i := range tc.results {
+				if tc.results[i].action != tc.wantActions[i] {
+					t.Errorf("action of result %d = %s, want %s", i, tc.results[i].action, tc.wantActions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecreatedResourcesMessage(t *testing.T) {
+	var recreated []string
+	for i := 0; i < maxRecreatedResourcesInEvent+2; i++ {
+		recreated = append(recreated, fmt.Sprintf("ConfigMap app/config-%d", i))
+	}
+	got := recreatedResourcesMessage(recreated)
+	want := "Recreated 12 resources deleted from the member cluster out of band: ConfigMap app/config-0, ConfigMap app/config-1, " +
+		"ConfigMap app/config-2, ConfigMap app/config-3, ConfigMap app/config-4, ConfigMap app/config-5, ConfigMap app/config-6, " +
+		"ConfigMap app/config-7, ConfigMap app/config-8, ConfigMap app/config-9 and 2 more"
+	if got != want {
+		t.Errorf("recreatedResourcesMessage() = %q, want %q", got, want)
+	}
+}
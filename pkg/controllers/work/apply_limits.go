@@ -0,0 +1,122 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+)
+
+// applyLimiter limits the applies of the works to the member cluster by the limits that the hub sets for the cluster.
+// The applies are only limited by the concurrency of the reconciler until the hub sets the limits.
+type applyLimiter struct {
+	mu sync.Mutex
+	// maxConcurrency is the max number of works applied at the same time; zero means no limit.
+	maxConcurrency int
+	// running is the number of works being applied.
+	running int
+	// changed is closed and replaced whenever a work is done or the limits change, to wake up the waiting applies.
+	changed chan struct{}
+	// manifests limits the rate of the manifest applies.
+	manifests *rate.Limiter
+}
+
+// newApplyLimiter returns a limiter which does not limit the applies until the limits are set.
+func newApplyLimiter() *applyLimiter {
+	return &applyLimiter{
+		changed:   make(chan struct{}),
+		manifests: rate.NewLimiter(rate.Inf, 1),
+	}
+}
+
+// SetApplyLimits makes the reconciler apply the works within the limits that the hub sets for the member cluster;
+// nil limits remove them.
+func (r *ApplyWorkReconciler) SetApplyLimits(limits *clusterv1beta1.ApplyLimits) {
+	r.applyLimiter.setLimits(limits)
+}
+
+// setLimits replaces the limits; the works being applied are not interrupted.
+func (l *applyLimiter) setLimits(limits *clusterv1beta1.ApplyLimits) {
+	if l == nil {
+		return
+	}
+	var maxConcurrency int
+	qps := rate.Inf
+	if limits != nil {
+		maxConcurrency = int(limits.MaxConcurrency)
+		if limits.QPS > 0 {
+			qps = rate.Limit(limits.QPS)
+		}
+	}
+	burst := 1
+	if qps != rate.Inf {
+		burst = int(qps)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxConcurrency == maxConcurrency && l.manifests.Limit() == qps {
+		return
+	}
+	klog.InfoS("Updating the limits of the work applies", "maxConcurrency", maxConcurrency, "qps", float64(qps))
+	l.maxConcurrency = maxConcurrency
+	l.manifests.SetLimit(qps)
+	l.manifests.SetBurst(burst)
+	l.broadcast()
+}
+
+// acquire blocks until the work can be applied within the concurrency limit, or the context is done; the caller must
+// call release once the work is applied.
+func (l *applyLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		if l.maxConcurrency == 0 || l.running < l.maxConcurrency {
+			l.running++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// release frees the slot of an applied work.
+func (l *applyLimiter) release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running--
+	l.broadcast()
+}
+
+// waitManifest blocks until the next manifest can be applied within the rate limit, or the context is done.
+func (l *applyLimiter) waitManifest(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	return l.manifests.Wait(ctx)
+}
+
+// broadcast wakes up the waiting applies; the caller must hold the lock.
+func (l *applyLimiter) broadcast() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
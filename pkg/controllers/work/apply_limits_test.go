@@ -0,0 +1,100 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+)
+
+func TestApplyLimiterConcurrency(t *testing.T) {
+	l := newApplyLimiter()
+	l.setLimits(&clusterv1beta1.ApplyLimits{MaxConcurrency: 1})
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() = %v, want nil", err)
+	}
+
+	// the second apply waits until the first one is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() over the limit = %v, want %v", err, context.DeadlineExceeded)
+	}
+	acquired := make(chan error)
+	go func() {
+		acquired <- l.acquire(context.Background())
+	}()
+	l.release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("acquire() after the release = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("acquire() is not woken up by the release")
+	}
+
+	// raising the limit wakes up the waiting applies
+	go func() {
+		acquired <- l.acquire(context.Background())
+	}()
+	l.setLimits(&clusterv1beta1.ApplyLimits{MaxConcurrency: 2})
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("acquire() after raising the limit = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("acquire() is not woken up by raising the limit")
+	}
+
+	// removing the limits lets all the applies through
+	l.setLimits(nil)
+	for i := 0; i < 5; i++ {
+		if err := l.acquire(ctx); err != nil {
+			t.Fatalf("acquire() without the limits = %v, want nil", err)
+		}
+	}
+}
+
+func TestApplyLimiterQPS(t *testing.T) {
+	l := newApplyLimiter()
+	if got := l.manifests.Limit(); got != rate.Inf {
+		t.Errorf("the default manifest rate = %v, want unlimited", got)
+	}
+	l.setLimits(&clusterv1beta1.ApplyLimits{QPS: 2})
+	if got, gotBurst := l.manifests.Limit(), l.manifests.Burst(); got != 2 || gotBurst != 2 {
+		t.Errorf("the manifest rate = %v with burst %d, want 2 with burst 2", got, gotBurst)
+	}
+	for i := 0; i < 2; i++ {
+		if err := l.waitManifest(context.Background()); err != nil {
+			t.Fatalf("waitManifest() within the burst = %v, want nil", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.waitManifest(ctx); err == nil {
+		t.Errorf("waitManifest() over the rate = nil, want an error as the wait exceeds the deadline")
+	}
+}
+
+func TestApplyLimiterDisabled(t *testing.T) {
+	var l *applyLimiter
+	l.setLimits(&clusterv1beta1.ApplyLimits{MaxConcurrency: 1, QPS: 1})
+	if err := l.acquire(context.Background()); err != nil {
+		t.Errorf("acquire() = %v, want nil", err)
+	}
+	l.release()
+	if err := l.waitManifest(context.Background()); err != nil {
+		t.Errorf("waitManifest() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// maxAuditRecords is the maximum number of audit records kept in the status of an appliedWork.
+	maxAuditRecords = 50
+
+	// auditDiffDepth is how deep we descend into the objects when summarizing the changed fields.
+	auditDiffDepth = 2
+)
+
+// auditIgnoredFields are the fields that the API server changes on every write, which are left out of the diff summary.
+var auditIgnoredFields = map[string]bool{
+	"metadata.resourceVersion": true,
+	"metadata.generation":      true,
+	"metadata.managedFields":   true,
+	"status":                   true,
+}
+
+// auditEntry is a write performed by the member agent on a resource, which is turned into an audit record.
+type auditEntry struct {
+	identifier  fleetv1beta1.WorkResourceIdentifier
+	operation   fleetv1beta1.AuditOperation
+	diffSummary string
+	// changedFields are the fields changed by an update, sorted.
+	changedFields []string
+}
+
+// AuditSink ships the audit records of the member agent, e.g. to a log pipeline, for compliance evidence.
+type AuditSink interface {
+	Write(work *fleetv1beta1.Work, records []fleetv1beta1.AppliedResourceAuditRecord) error
+}
+
+// auditLogEntry is an audit record together with the work on behalf of which the operation was performed.
+type auditLogEntry struct {
+	WorkNamespace string `json:"workNamespace"`
+	WorkName      string `json:"workName"`
+	fleetv1beta1.AppliedResourceAuditRecord
+}
+
+// jsonAuditSink writes every audit record as a line of JSON.
+type jsonAuditSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONAuditSink returns an AuditSink which writes every audit record as a line of JSON to the writer.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{encoder: json.NewEncoder(w)}
+}
+
+// Write implements the AuditSink interface.
+func (s *jsonAuditSink) Write(work *fleetv1beta1.Work, records []fleetv1beta1.AppliedResourceAuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range records {
+		entry := auditLogEntry{
+			WorkNamespace:              work.Namespace,
+			WorkName:                   work.Name,
+			AppliedResourceAuditRecord: record,
+		}
+		if err := s.encoder.Encode(&entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildApplyAuditEntry returns the audit entry of applying a manifest given the object before and after the apply.
+// It returns nil if the apply did not change the object.
+func buildApplyAuditEntry(identifier fleetv1beta1.WorkResourceIdentifier, before, after *unstructured.Unstructured) *auditEntry {
+	if after == nil {
+		return nil
+	}
+	if before == nil {
+		return &auditEntry{identifier: identifier, operation: fleetv1beta1.AuditOperationCreate}
+	}
+	if before.GetResourceVersion() == after.GetResourceVersion() {
+		return nil
+	}
+	changed := changedFields(before, after)
+	return &auditEntry{
+		identifier:    identifier,
+		operation:     fleetv1beta1.AuditOperationUpdate,
+		diffSummary:   summarizeDiff(changed),
+		changedFields: changed,
+	}
+}
+
+// changedFields lists the fields which are different between the two objects, sorted.
+func changedFields(before, after *unstructured.Unstructured) []string {
+	var changed []string
+	diffFields("", before.Object, after.Object, auditDiffDepth, &changed)
+	sort.Strings(changed)
+	return changed
+}
+
+// summarizeDiff summarizes the changed fields.
+func summarizeDiff(changed []string) string {
+	if len(changed) == 0 {
+		return ""
+	}
+	return "changed " + strings.Join(changed, ", ")
+}
+
+func diffFields(prefix string, before, after map[string]interface{}, depth int, changed *[]string) {
+	keys := make(map[string]bool, len(before)+len(after))
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if auditIgnoredFields[path] || equality.Semantic.DeepEqual(before[k], after[k]) {
+			continue
+		}
+		beforeMap, isBeforeMap := before[k].(map[string]interface{})
+		afterMap, isAfterMap := after[k].(map[string]interface{})
+		if depth > 1 && isBeforeMap && isAfterMap {
+			diffFields(path, beforeMap, afterMap, depth-1, changed)
+			continue
+		}
+		*changed = append(*changed, path)
+	}
+}
+
+// buildAuditRecords turns the audit entries into the audit records of the work.
+func buildAuditRecords(work *fleetv1beta1.Work, entries []auditEntry, now time.Time) []fleetv1beta1.AppliedResourceAuditRecord {
+	records := make([]fleetv1beta1.AppliedResourceAuditRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, fleetv1beta1.AppliedResourceAuditRecord{
+			WorkResourceIdentifier: entry.identifier,
+			Operation:              entry.operation,
+			Actor:                  workFieldManagerName,
+			Placement:              work.GetLabels()[fleetv1beta1.CRPTrackingLabel],
+			ResourceSnapshotIndex:  work.GetLabels()[fleetv1beta1.ParentResourceSnapshotIndexLabel],
+			DiffSummary:            entry.diffSummary,
+			Time:                   metav1.NewTime(now),
+		})
+	}
+	return records
+}
+
+// appendAuditRecords appends the new audit records and drops the oldest ones beyond the limit.
+func appendAuditRecords(existing, records []fleetv1beta1.AppliedResourceAuditRecord) []fleetv1beta1.AppliedResourceAuditRecord {
+	all := append(existing, records...)
+	if len(all) > maxAuditRecords {
+		all = all[len(all)-maxAuditRecords:]
+	}
+	return all
+}
@@ -0,0 +1,273 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/atomic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	testingclient "k8s.io/client-go/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func newAuditTestDeployment(resourceVersion string, replicas int64, labels map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":            "app",
+				"namespace":       "default",
+				"resourceVersion": resourceVersion,
+				"labels":          labels,
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+			},
+			"status": map[string]interface{}{
+				"replicas": replicas,
+			},
+		},
+	}
+}
+
+func TestBuildApplyAuditEntry(t *testing.T) {
+	identifier := fleetv1beta1.WorkResourceIdentifier{Group: "apps", Version: "v1", Kind: "Deployment", Name: "app", Namespace: "default"}
+	labels := map[string]interface{}{"app": "test"}
+	tests := map[string]struct {
+		before *unstructured.Unstructured
+		after  *unstructured.Unstructured
+		want   *auditEntry
+	}{
+		"apply failed": {
+			before: newAuditTestDeployment("1", 1, labels),
+		},
+		"resource is created": {
+			after: newAuditTestDeployment("1", 1, labels),
+			want:  &auditEntry{identifier: identifier, operation: fleetv1beta1.AuditOperationCreate},
+		},
+		"resource is not changed": {
+			before: newAuditTestDeployment("1", 1, labels),
+			after:  newAuditTestDeployment("1", 1, labels),
+		},
+		"resource is updated": {
+			before: newAuditTestDeployment("1", 1, labels),
+			after:  newAuditTestDeployment("2", 3, map[string]interface{}{"app": "new"}),
+			want: &auditEntry{
+				identifier:    identifier,
+				operation:     fleetv1beta1.AuditOperationUpdate,
+				diffSummary:   "changed metadata.labels, spec.replicas",
+				changedFields: []string{"metadata.labels", "spec.replicas"},
+			},
+		},
+		"only the ignored fields are updated": {
+			before: newAuditTestDeployment("1", 1, labels),
+			after:  newAuditTestDeployment("2", 1, labels),
+			want: &auditEntry{
+				identifier: identifier,
+				operation:  fleetv1beta1.AuditOperationUpdate,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := buildApplyAuditEntry(identifier, tc.before, tc.after)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(auditEntry{})); diff != "" {
+				t.Errorf("buildApplyAuditEntry() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAppendAuditRecords(t *testing.T) {
+	newRecords := func(start, count int) []fleetv1beta1.AppliedResourceAuditRecord {
+		records := make([]fleetv1beta1.AppliedResourceAuditRecord, 0, count)
+		for i := start; i < start+count; i++ {
+			records = append(records, fleetv1beta1.AppliedResourceAuditRecord{
+				WorkResourceIdentifier: fleetv1beta1.WorkResourceIdentifier{Name: fmt.Sprintf("resource-%d", i)},
+			})
+		}
+		return records
+	}
+	tests := map[string]struct {
+		existing []fleetv1beta1.AppliedResourceAuditRecord
+		records  []fleetv1beta1.AppliedResourceAuditRecord
+		want     []fleetv1beta1.AppliedResourceAuditRecord
+	}{
+		"below the limit": {
+			existing: newRecords(0, 2),
+			records:  newRecords(2, 3),
+			want:     newRecords(0, 5),
+		},
+		"no new records": {
+			existing: newRecords(0, 2),
+			want:     newRecords(0, 2),
+		},
+		"the oldest records are dropped": {
+			existing: newRecords(0, maxAuditRecords),
+			records:  newRecords(maxAuditRecords, 3),
+			want:     newRecords(3, maxAuditRecords),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := appendAuditRecords(tc.existing, tc.records)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("appendAuditRecords() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	work := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crp-work",
+			Namespace: "fleet-member-cluster-1",
+			Labels: map[string]string{
+				fleetv1beta1.CRPTrackingLabel:                 "crp",
+				fleetv1beta1.ParentResourceSnapshotIndexLabel: "3",
+			},
+		},
+	}
+	entries := []auditEntry{
+		{
+			identifier: fleetv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: "app"},
+			operation:  fleetv1beta1.AuditOperationDelete,
+		},
+	}
+	records := buildAuditRecords(work, entries, now)
+	wantRecord := fleetv1beta1.AppliedResourceAuditRecord{
+		WorkResourceIdentifier: entries[0].identifier,
+		Operation:              fleetv1beta1.AuditOperationDelete,
+		Actor:                  workFieldManagerName,
+		Placement:              "crp",
+		ResourceSnapshotIndex:  "3",
+		Time:                   metav1.NewTime(now),
+	}
+	if diff := cmp.Diff([]fleetv1beta1.AppliedResourceAuditRecord{wantRecord}, records); diff != "" {
+		t.Fatalf("buildAuditRecords() mismatch (-want, +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := NewJSONAuditSink(&buf).Write(work, records); err != nil {
+		t.Fatalf("Write() = %v, want nil", err)
+	}
+	var got auditLogEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal the audit log entry %q: %v", buf.String(), err)
+	}
+	want := auditLogEntry{
+		WorkNamespace:              work.Namespace,
+		WorkName:                   work.Name,
+		AppliedResourceAuditRecord: wantRecord,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("audit log entry mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestReconcilePersistsAuditRecordsOnEarlyReturn(t *testing.T) {
+	ctx := context.Background()
+	workNamespace := "fleet-member-cluster-1"
+	workName := "work"
+	staleDeployment := fleetv1beta1.AppliedResourceMeta{
+		WorkResourceIdentifier: fleetv1beta1.WorkResourceIdentifier{
+			Group:     utils.DeploymentGVR.Group,
+			Version:   utils.DeploymentGVR.Version,
+			Kind:      "Deployment",
+			Resource:  utils.DeploymentGVR.Resource,
+			Name:      "stale",
+			Namespace: "default",
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() = %v, want nil", err)
+	}
+	hubClient := ctrlfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&fleetv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{Namespace: workNamespace, Name: workName, Finalizers: []string{fleetv1beta1.WorkFinalizer}},
+			Spec: fleetv1beta1.WorkSpec{
+				Workload:      fleetv1beta1.WorkloadTemplate{Manifests: []fleetv1beta1.Manifest{testManifest}},
+				ApplyStrategy: &fleetv1beta1.ApplyStrategy{Type: fleetv1beta1.ApplyStrategyTypeClientSideApply},
+			},
+		}).
+		WithStatusSubresource(&fleetv1beta1.Work{}).Build()
+	spokeClient := ctrlfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&fleetv1beta1.AppliedWork{
+			ObjectMeta: metav1.ObjectMeta{Name: workName},
+			Spec:       fleetv1beta1.AppliedWorkSpec{WorkName: workName, WorkNamespace: workNamespace},
+			Status:     fleetv1beta1.AppliedWorkStatus{AppliedResources: []fleetv1beta1.AppliedResourceMeta{staleDeployment}},
+		}).
+		WithStatusSubresource(&fleetv1beta1.AppliedWork{}).Build()
+	// the manifest of the work is created while the stale deployment fails to be garbage-collected
+	spokeDynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	spokeDynamicClient.PrependReactor("get", "deployments", func(action testingclient.Action) (bool, runtime.Object, error) {
+		if action.(testingclient.GetAction).GetName() == staleDeployment.Name {
+			return true, nil, errors.New("failed to get the stale deployment")
+		}
+		return false, nil, nil
+	})
+	var sink bytes.Buffer
+	r := ApplyWorkReconciler{
+		client:             hubClient,
+		spokeDynamicClient: spokeDynamicClient,
+		spokeClient:        spokeClient,
+		restMapper:         testMapper{},
+		recorder:           utils.NewFakeRecorder(2),
+		joined:             atomic.NewBool(true),
+		workNameSpace:      workNamespace,
+		auditSink:          NewJSONAuditSink(&sink),
+	}
+	r.appliers = map[fleetv1beta1.ApplyStrategyType]Applier{
+		fleetv1beta1.ApplyStrategyTypeClientSideApply: &ClientSideApplier{
+			HubClient:          r.client,
+			WorkNamespace:      r.workNameSpace,
+			SpokeDynamicClient: r.spokeDynamicClient,
+		},
+	}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: workNamespace, Name: workName}}); err == nil {
+		t.Fatalf("Reconcile() = nil, want the garbage-collection error")
+	}
+
+	appliedWork := &fleetv1beta1.AppliedWork{}
+	if err := spokeClient.Get(ctx, types.NamespacedName{Name: workName}, appliedWork); err != nil {
+		t.Fatalf("Get() = %v, want nil", err)
+	}
+	if len(appliedWork.Status.AuditRecords) != 1 {
+		t.Fatalf("Reconcile() persisted audit records %+v, want the creation of the manifest", appliedWork.Status.AuditRecords)
+	}
+	record := appliedWork.Status.AuditRecords[0]
+	if record.Operation != fleetv1beta1.AuditOperationCreate || record.Name != testDeployment.Name {
+		t.Errorf("Reconcile() persisted audit record %+v, want the creation of %s", record, testDeployment.Name)
+	}
+	if !bytes.Contains(sink.Bytes(), []byte(testDeployment.Name)) {
+		t.Errorf("Reconcile() shipped audit records %q, want the creation of %s", sink.String(), testDeployment.Name)
+	}
+	if diff := cmp.Diff([]fleetv1beta1.AppliedResourceMeta{staleDeployment}, appliedWork.Status.AppliedResources); diff != "" {
+		t.Errorf("Reconcile() applied resources mismatch (-want, +got):\n%s", diff)
+	}
+}
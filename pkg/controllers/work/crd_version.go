@@ -0,0 +1,136 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// errCRDVersionNotServed indicates that the member cluster does not serve the version of a custom resource yet, while
+// the custom resource definition placed in the same work serves it.
+var errCRDVersionNotServed = errors.New("the version of the custom resource is not served by the member cluster yet")
+
+var (
+	// crdGK is the group kind of the custom resource definitions.
+	crdGK = apiextensionsv1.Kind("CustomResourceDefinition")
+	// crdGVR is the group version resource of the custom resource definitions.
+	crdGVR = apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+)
+
+// decodedManifest is a manifest of a work decoded into the object to apply.
+type decodedManifest struct {
+	gvr schema.GroupVersionResource
+	obj *unstructured.Unstructured
+	err error
+}
+
+// crdVersionGate holds back the custom resources of a work whose version is served by a custom resource definition
+// placed in the same work, but not by the member cluster yet, e.g. when the placement moves the custom resources to a
+// new version of their definition. The custom resources are applied once the member cluster applies the definition and
+// serves their version, instead of failing with the version not being served in the middle of the rollout.
+type crdVersionGate struct {
+	// crds are the custom resource definitions placed in the work, by the group kind of their custom resources.
+	crds map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition
+	// checked are the results of checking the versions on the member cluster, so that each definition is read once
+	// per apply.
+	checked map[schema.GroupVersionKind]error
+}
+
+// newCRDVersionGate builds the gate of the custom resource definitions among the decoded manifests of a work.
+func newCRDVersionGate(decoded []decodedManifest) *crdVersionGate {
+	g := &crdVersionGate{
+		crds:    make(map[schema.GroupKind]*apiextensionsv1.CustomResourceDefinition),
+		checked: make(map[schema.GroupVersionKind]error),
+	}
+	for _, manifest := range decoded {
+		if manifest.err != nil || manifest.obj.GroupVersionKind().GroupKind() != crdGK {
+			continue
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(manifest.obj.Object, crd); err != nil {
+			klog.ErrorS(err, "Failed to convert the custom resource definition", "crd", klog.KObj(manifest.obj))
+			continue
+		}
+		g.crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd
+	}
+	return g
+}
+
+// wait returns an errCRDVersionNotServed error if the custom resource must wait for the member cluster to serve its
+// version, or nil if it is applied as usual. decodeErr is the error of decoding the custom resource.
+func (g *crdVersionGate) wait(ctx context.Context, dynamicClient dynamic.Interface, obj *unstructured.Unstructured, decodeErr error) error {
+	if obj == nil || len(g.crds) == 0 {
+		return nil
+	}
+	gvk := obj.GroupVersionKind()
+	crd, ok := g.crds[gvk.GroupKind()]
+	if !ok || !servesVersion(crd, gvk.Version) {
+		return nil
+	}
+	if decodeErr != nil && !meta.IsNoMatchError(decodeErr) {
+		return nil
+	}
+	if err, ok := g.checked[gvk]; ok {
+		return err
+	}
+	err := checkCRDVersionServed(ctx, dynamicClient, crd.Name, gvk.Version)
+	if err == nil && decodeErr != nil {
+		// the definition serves the version, but the member cluster has not discovered it yet
+		err = fmt.Errorf("%w: version %s of the custom resource definition %s is not discovered yet", errCRDVersionNotServed, gvk.Version, crd.Name)
+	}
+	g.checked[gvk] = err
+	return err
+}
+
+// checkCRDVersionServed returns an errCRDVersionNotServed error if the custom resource definition on the member
+// cluster is not established or does not serve the version yet. A failure to read the definition is left to the
+// apply of the custom resource, which reports its own error.
+func checkCRDVersionServed(ctx context.Context, dynamicClient dynamic.Interface, name, version string) error {
+	obj, err := dynamicClient.Resource(crdGVR).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: the custom resource definition %s is not created yet", errCRDVersionNotServed, name)
+	case err != nil:
+		klog.ErrorS(err, "Failed to get the custom resource definition", "crd", name)
+		return nil
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+		klog.ErrorS(err, "Failed to convert the custom resource definition", "crd", name)
+		return nil
+	}
+	if !servesVersion(crd, version) {
+		return fmt.Errorf("%w: the custom resource definition %s does not serve version %s yet", errCRDVersionNotServed, name, version)
+	}
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the custom resource definition %s is not established yet", errCRDVersionNotServed, name)
+}
+
+// servesVersion tells if the custom resource definition serves the version.
+func servesVersion(crd *apiextensionsv1.CustomResourceDefinition, version string) bool {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return v.Served
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestCRDVersionGateWait(t *testing.T) {
+	newCRD := func(established bool, versions ...string) *unstructured.Unstructured {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+				Scope: apiextensionsv1.NamespaceScoped,
+			},
+		}
+		for _, v := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v, Served: true})
+		}
+		if established {
+			crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}}
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+		if err != nil {
+			t.Fatalf("failed to convert the custom resource definition: %v", err)
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+	newWidget := func(version string) *unstructured.Unstructured {
+		widget := &unstructured.Unstructured{}
+		widget.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: version, Kind: "Widget"})
+		widget.SetNamespace("app")
+		widget.SetName("widget")
+		return widget
+	}
+	noMatchErr := &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "example.com", Kind: "Widget"}, SearchedVersions: []string{"v2"}}
+
+	tests := map[string]struct {
+		workCRD   *unstructured.Unstructured
+		liveCRD   *unstructured.Unstructured
+		obj       *unstructured.Unstructured
+		decodeErr error
+		wantWait  bool
+	}{
+		"no definition in the work": {
+			liveCRD: newCRD(true, "v1"),
+			obj:     newWidget("v2"),
+		},
+		"the definition in the work does not serve the version": {
+			workCRD: newCRD(false, "v1"),
+			liveCRD: newCRD(true, "v1"),
+			obj:     newWidget("v2"),
+		},
+		"the member cluster serves the version": {
+			workCRD: newCRD(false, "v1", "v2"),
+			liveCRD: newCRD(true, "v1", "v2"),
+			obj:     newWidget("v2"),
+		},
+		"the definition is not created on the member cluster": {
+			workCRD:  newCRD(false, "v1", "v2"),
+			obj:      newWidget("v2"),
+			wantWait: true,
+		},
+		"the definition on the member cluster does not serve the version": {
+			workCRD:  newCRD(false, "v1", "v2"),
+			liveCRD:  newCRD(true, "v1"),
+			obj:      newWidget("v2"),
+			wantWait: true,
+		},
+		"the definition on the member cluster is not established": {
+			workCRD:  newCRD(false, "v1", "v2"),
+			liveCRD:  newCRD(false, "v1", "v2"),
+			obj:      newWidget("v2"),
+			wantWait: true,
+		},
+		"the version is not discovered yet": {
+			workCRD:   newCRD(false, "v1", "v2"),
+			liveCRD:   newCRD(true, "v1", "v2"),
+			obj:       newWidget("v2"),
+			decodeErr: noMatchErr,
+			wantWait:  true,
+		},
+		"the manifest fails to decode for another reason": {
+			workCRD:   newCRD(false, "v1", "v2"),
+			obj:       newWidget("v2"),
+			decodeErr: errors.New("failed to unseal object"),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var decoded []decodedManifest
+			if tc.workCRD != nil {
+				decoded = append(decoded, decodedManifest{gvr: crdGVR, obj: tc.workCRD})
+			}
+			decoded = append(decoded, decodedManifest{obj: tc.obj, err: tc.decodeErr})
+			var liveObjs []runtime.Object
+			if tc.liveCRD != nil {
+				liveObjs = append(liveObjs, tc.liveCRD)
+			}
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"}, liveObjs...)
+
+			err := newCRDVersionGate(decoded).wait(context.Background(), dynamicClient, tc.obj, tc.decodeErr)
+			if gotWait := errors.Is(err, errCRDVersionNotServed); gotWait != tc.wantWait || (!gotWait && err != nil) {
+				t.Errorf("wait() = %v, want waiting %v", err, tc.wantWait)
+			}
+		})
+	}
+}
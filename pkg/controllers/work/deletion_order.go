@@ -0,0 +1,115 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// The waves in which the resources applied by a work are deleted when the work is deleted in the reverse order; the
+// resources are deleted in the reverse order of their dependencies, so that no namespace is stuck terminating on a
+// custom resource whose definition is gone.
+const (
+	// namespacedResourcesDeletionWave deletes the namespaced resources, e.g. the custom resources and the workloads.
+	namespacedResourcesDeletionWave = iota
+	// clusterScopedResourcesDeletionWave deletes the cluster scoped resources other than the custom resource
+	// definitions and the namespaces.
+	clusterScopedResourcesDeletionWave
+	// crdDeletionWave deletes the custom resource definitions.
+	crdDeletionWave
+	// namespaceDeletionWave deletes the namespaces.
+	namespaceDeletionWave
+	// totalDeletionWaves is the number of the deletion waves.
+	totalDeletionWaves
+)
+
+// deletionWaveRequeueInterval is the interval at which a deleting work checks whether the resources of the current
+// deletion wave are gone.
+const deletionWaveRequeueInterval = 5 * time.Second
+
+// deletionWaveOf returns the wave in which the applied resource is deleted.
+func deletionWaveOf(identifier fleetv1beta1.WorkResourceIdentifier) int {
+	switch {
+	case identifier.Group == "" && identifier.Kind == "Namespace":
+		return namespaceDeletionWave
+	case identifier.Group == "apiextensions.k8s.io" && identifier.Kind == "CustomResourceDefinition":
+		return crdDeletionWave
+	case identifier.Namespace == "":
+		return clusterScopedResourcesDeletionWave
+	default:
+		return namespacedResourcesDeletionWave
+	}
+}
+
+// deleteAppliedResourcesInReverseOrder deletes the resources applied by the deleting work in the reverse order of
+// their dependencies, one wave at a time; the next wave starts once the resources of the current wave which the work
+// owns are gone. The resources that other works own as well are left to them, as when the stale manifests are deleted.
+// It returns true once all the applied resources are gone.
+func (r *ApplyWorkReconciler) deleteAppliedResourcesInReverseOrder(ctx context.Context, work *fleetv1beta1.Work) (bool, error) {
+	appliedWork := &fleetv1beta1.AppliedWork{}
+	if err := r.spokeClient.Get(ctx, types.NamespacedName{Name: work.Name}, appliedWork); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		klog.ErrorS(err, "Failed to retrieve the appliedWork", "appliedWork", work.Name)
+		return false, controller.NewAPIServerError(false, err)
+	}
+	owner := metav1.OwnerReference{
+		APIVersion:         fleetv1beta1.GroupVersion.String(),
+		Kind:               fleetv1beta1.AppliedWorkKind,
+		Name:               appliedWork.GetName(),
+		UID:                appliedWork.GetUID(),
+		BlockOwnerDeletion: ptr.To(false),
+	}
+
+	waves := make([][]fleetv1beta1.AppliedResourceMeta, totalDeletionWaves)
+	for _, resourceMeta := range appliedWork.Status.AppliedResources {
+		wave := deletionWaveOf(resourceMeta.WorkResourceIdentifier)
+		waves[wave] = append(waves[wave], resourceMeta)
+	}
+	for wave := range waves {
+		var toDelete []fleetv1beta1.AppliedResourceMeta
+		remaining := 0
+		for _, resourceMeta := range waves[wave] {
+			gvr := schema.GroupVersionResource{Group: resourceMeta.Group, Version: resourceMeta.Version, Resource: resourceMeta.Resource}
+			obj, err := r.spokeDynamicClient.Resource(gvr).Namespace(resourceMeta.Namespace).Get(ctx, resourceMeta.Name, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err):
+				continue
+			case err != nil:
+				klog.ErrorS(err, "Failed to get the applied resource", "work", klog.KObj(work), "resource", resourceMeta.WorkResourceIdentifier)
+				return false, err
+			}
+			if indexOwnerRef(obj.GetOwnerReferences(), owner) == -1 {
+				continue
+			}
+			remaining++
+			if obj.GetDeletionTimestamp() == nil {
+				toDelete = append(toDelete, resourceMeta)
+			}
+		}
+		if remaining == 0 {
+			continue
+		}
+		klog.V(2).InfoS("Deleting the applied resources in the reverse order", "work", klog.KObj(work), "wave", wave,
+			"numberOfRemainingResources", remaining, "numberOfResourcesToDelete", len(toDelete))
+		auditEntries, err := r.deleteStaleManifest(ctx, toDelete, owner)
+		r.shipAuditRecords(work, buildAuditRecords(work, auditEntries, time.Now()))
+		return false, err
+	}
+	return true, nil
+}
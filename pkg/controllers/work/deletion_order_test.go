@@ -0,0 +1,118 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestDeletionWaveOf(t *testing.T) {
+	tests := map[string]struct {
+		identifier fleetv1beta1.WorkResourceIdentifier
+		want       int
+	}{
+		"custom resource": {
+			identifier: fleetv1beta1.WorkResourceIdentifier{Group: "example.com", Version: "v1", Kind: "Widget", Namespace: "app", Name: "widget"},
+			want:       namespacedResourcesDeletionWave,
+		},
+		"workload": {
+			identifier: fleetv1beta1.WorkResourceIdentifier{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "app", Name: "web"},
+			want:       namespacedResourcesDeletionWave,
+		},
+		"cluster scoped resource": {
+			identifier: fleetv1beta1.WorkResourceIdentifier{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "reader"},
+			want:       clusterScopedResourcesDeletionWave,
+		},
+		"custom resource definition": {
+			identifier: fleetv1beta1.WorkResourceIdentifier{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition", Name: "widgets.example.com"},
+			want:       crdDeletionWave,
+		},
+		"namespace": {
+			identifier: fleetv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "Namespace", Name: "app"},
+			want:       namespaceDeletionWave,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := deletionWaveOf(tc.identifier); got != tc.want {
+				t.Errorf("deletionWaveOf() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteAppliedResourcesInReverseOrder(t *testing.T) {
+	appliedWork := &fleetv1beta1.AppliedWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "work-1", UID: "applied-work-uid"},
+		Status: fleetv1beta1.AppliedWorkStatus{
+			AppliedResources: []fleetv1beta1.AppliedResourceMeta{
+				{WorkResourceIdentifier: fleetv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "Namespace", Resource: "namespaces", Name: "app"}},
+				{WorkResourceIdentifier: fleetv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "ConfigMap", Resource: "configmaps", Namespace: "app", Name: "config"}},
+			},
+		},
+	}
+	owner := metav1.OwnerReference{APIVersion: fleetv1beta1.GroupVersion.String(), Kind: fleetv1beta1.AppliedWorkKind, Name: "work-1", UID: "applied-work-uid"}
+	newObject := func(kind, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetOwnerReferences([]metav1.OwnerReference{owner})
+		return obj
+	}
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newObject("Namespace", "", "app"), newObject("ConfigMap", "app", "config"))
+	r := &ApplyWorkReconciler{
+		spokeClient:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(appliedWork).Build(),
+		spokeDynamicClient: dynamicClient,
+	}
+	work := &fleetv1beta1.Work{ObjectMeta: metav1.ObjectMeta{Name: "work-1", Namespace: "fleet-member-cluster-1"}}
+	ctx := context.Background()
+	namespaceGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	configMapGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	// the config map is deleted before the namespace
+	if done, err := r.deleteAppliedResourcesInReverseOrder(ctx, work); err != nil || done {
+		t.Fatalf("deleteAppliedResourcesInReverseOrder() = %v, %v, want false, nil", done, err)
+	}
+	if _, err := dynamicClient.Resource(configMapGVR).Namespace("app").Get(ctx, "config", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("get the config map = %v, want not found", err)
+	}
+	if _, err := dynamicClient.Resource(namespaceGVR).Get(ctx, "app", metav1.GetOptions{}); err != nil {
+		t.Errorf("get the namespace = %v, want nil", err)
+	}
+
+	if done, err := r.deleteAppliedResourcesInReverseOrder(ctx, work); err != nil || done {
+		t.Fatalf("deleteAppliedResourcesInReverseOrder() = %v, %v, want false, nil", done, err)
+	}
+	if _, err := dynamicClient.Resource(namespaceGVR).Get(ctx, "app", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("get the namespace = %v, want not found", err)
+	}
+
+	if done, err := r.deleteAppliedResourcesInReverseOrder(ctx, work); err != nil || !done {
+		t.Fatalf("deleteAppliedResourcesInReverseOrder() = %v, %v, want true, nil", done, err)
+	}
+
+	// a work without the appliedWork has nothing to delete
+	if done, err := r.deleteAppliedResourcesInReverseOrder(ctx, &fleetv1beta1.Work{ObjectMeta: metav1.ObjectMeta{Name: "work-2", Namespace: "fleet-member-cluster-1"}}); err != nil || !done {
+		t.Errorf("deleteAppliedResourcesInReverseOrder() without the appliedWork = %v, %v, want true, nil", done, err)
+	}
+}
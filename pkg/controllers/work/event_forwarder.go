@@ -0,0 +1,185 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// ForwardedEventObjectAnnotation is the annotation of the events which the member agent forwards from the member
+	// cluster to the works on the hub cluster; its value is the object on the member cluster the event is about, e.g.
+	// "Pod app/web-7d4b9c-x2x5q".
+	ForwardedEventObjectAnnotation = "kubernetes-fleet.io/forwarded-event-object"
+
+	// maxForwardedOwnerHops is how many owners are followed from the object of an event to find the placed object,
+	// e.g. from a pod to its replica set and then to the placed deployment.
+	maxForwardedOwnerHops = 3
+)
+
+// forwardedEventReasons are the reasons of the warning events on the member cluster which are forwarded to the hub
+// cluster, as they usually tell the root causes of the placed resources not becoming available.
+var forwardedEventReasons = sets.New(
+	"FailedCreate",
+	"FailedScheduling",
+	"Unhealthy",
+	"BackOff",
+	"OOMKilled",
+)
+
+// EventForwarder forwards the important warning events of the resources placed on the member cluster, and of the
+// objects they own, e.g. the pods of a placed deployment, to the hub cluster as the events of their works, so that
+// the root causes of the failures are visible without access to the member cluster.
+type EventForwarder struct {
+	memberClient       client.Client
+	spokeDynamicClient dynamic.Interface
+	restMapper         meta.RESTMapper
+	hubClient          client.Client
+	hubRecorder        record.EventRecorder
+	workNamespace      string
+}
+
+// NewEventForwarder returns an event forwarder which reads the events from the member cluster, and records them on
+// the works in the reserved namespace of the member cluster on the hub cluster.
+func NewEventForwarder(memberClient client.Client, spokeDynamicClient dynamic.Interface, restMapper meta.RESTMapper,
+	hubClient client.Client, hubRecorder record.EventRecorder, workNamespace string) *EventForwarder {
+	return &EventForwarder{
+		memberClient:       memberClient,
+		spokeDynamicClient: spokeDynamicClient,
+		restMapper:         restMapper,
+		hubClient:          hubClient,
+		hubRecorder:        hubRecorder,
+		workNamespace:      workNamespace,
+	}
+}
+
+// Reconcile forwards the event on the member cluster to the work of the placed object it is about.
+func (f *EventForwarder) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var evt corev1.Event
+	if err := f.memberClient.Get(ctx, req.NamespacedName, &evt); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if !isForwardedEvent(&evt) {
+		return ctrl.Result{}, nil
+	}
+	workName, err := f.placingWork(ctx, evt.InvolvedObject)
+	if err != nil || workName == "" {
+		return ctrl.Result{}, err
+	}
+	var work fleetv1beta1.Work
+	if err := f.hubClient.Get(ctx, types.NamespacedName{Namespace: f.workNamespace, Name: workName}, &work); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, controller.NewAPIServerError(false, err)
+	}
+	object := forwardedObjectRef(evt.InvolvedObject)
+	klog.V(2).InfoS("Forwarding an event of a placed object to the hub", "event", klog.KObj(&evt), "reason", evt.Reason, "object", object, "work", klog.KObj(&work))
+	f.hubRecorder.AnnotatedEventf(&work, map[string]string{ForwardedEventObjectAnnotation: object},
+		corev1.EventTypeWarning, evt.Reason, "%s: %s", object, evt.Message)
+	return ctrl.Result{}, nil
+}
+
+// placingWork returns the name of the work which placed the object, or one of its owners within
+// maxForwardedOwnerHops, on the member cluster; it returns an empty name if the object is not placed by the fleet.
+func (f *EventForwarder) placingWork(ctx context.Context, ref corev1.ObjectReference) (string, error) {
+	namespace := ref.Namespace
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	name := ref.Name
+	for hop := 0; hop <= maxForwardedOwnerHops; hop++ {
+		mapping, err := f.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			klog.V(2).InfoS("Skipping the event of an object whose kind is not served", "gvk", gvk, "error", err)
+			return "", nil
+		}
+		resource := f.spokeDynamicClient.Resource(mapping.Resource)
+		var getter dynamic.ResourceInterface = resource
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			getter = resource.Namespace(namespace)
+		}
+		obj, err := getter.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", controller.NewAPIServerError(false, err)
+		}
+		owners := obj.GetOwnerReferences()
+		for _, owner := range owners {
+			if owner.APIVersion == fleetv1beta1.GroupVersion.String() && owner.Kind == fleetv1beta1.AppliedWorkKind {
+				return owner.Name, nil
+			}
+		}
+		next := metav1.GetControllerOfNoCopy(obj)
+		if next == nil {
+			return "", nil
+		}
+		gvk = schema.FromAPIVersionAndKind(next.APIVersion, next.Kind)
+		name = next.Name
+	}
+	return "", nil
+}
+
+// isForwardedEvent tells if the event on the member cluster is forwarded to the hub cluster.
+func isForwardedEvent(evt *corev1.Event) bool {
+	return evt.Type == corev1.EventTypeWarning && forwardedEventReasons.Has(evt.Reason)
+}
+
+// forwardedObjectRef returns the reference of the object of a forwarded event, e.g. "Pod app/web-7d4b9c-x2x5q".
+func forwardedObjectRef(ref corev1.ObjectReference) string {
+	if ref.Namespace == "" {
+		return fmt.Sprintf("%s %s", ref.Kind, ref.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+}
+
+// SetupWithManager sets up the event forwarder with the manager of the member cluster; the events are forwarded when
+// they are created and when they recur.
+func (f *EventForwarder) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("event-forwarder").
+		For(&corev1.Event{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				evt, ok := e.Object.(*corev1.Event)
+				return ok && isForwardedEvent(evt)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldEvt, oldOK := e.ObjectOld.(*corev1.Event)
+				newEvt, newOK := e.ObjectNew.(*corev1.Event)
+				return oldOK && newOK && isForwardedEvent(newEvt) && oldEvt.Count != newEvt.Count
+			},
+			DeleteFunc: func(event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Complete(f)
+}
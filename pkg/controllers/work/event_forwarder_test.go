@@ -0,0 +1,119 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestPlacingWork(t *testing.T) {
+	newObject := func(apiVersion, kind, name string, owner *metav1.OwnerReference) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace("app")
+		obj.SetName(name)
+		if owner != nil {
+			obj.SetOwnerReferences([]metav1.OwnerReference{*owner})
+		}
+		return obj
+	}
+	controllerOf := func(apiVersion, kind, name string) *metav1.OwnerReference {
+		return &metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, Controller: ptr.To(true)}
+	}
+	deployment := newObject("apps/v1", "Deployment", "web", &metav1.OwnerReference{
+		APIVersion: fleetv1beta1.GroupVersion.String(),
+		Kind:       fleetv1beta1.AppliedWorkKind,
+		Name:       "crp-work",
+	})
+	replicaSet := newObject("apps/v1", "ReplicaSet", "web-7d4b9c", controllerOf("apps/v1", "Deployment", "web"))
+	pod := newObject("v1", "Pod", "web-7d4b9c-x2x5q", controllerOf("apps/v1", "ReplicaSet", "web-7d4b9c"))
+	unplacedPod := newObject("v1", "Pod", "standalone", nil)
+	// a pod owned by the objects four levels away from the placed object
+	deepPod := newObject("v1", "Pod", "deep", controllerOf("v1", "Pod", "web-7d4b9c-x2x5q-child"))
+	child := newObject("v1", "Pod", "web-7d4b9c-x2x5q-child", controllerOf("v1", "Pod", "web-7d4b9c-x2x5q"))
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), deployment, replicaSet, pod, unplacedPod, deepPod, child)
+	f := NewEventForwarder(nil, dynamicClient, restMapper, nil, nil, "fleet-member-cluster")
+
+	tests := map[string]struct {
+		ref  corev1.ObjectReference
+		want string
+	}{
+		"placed object": {
+			ref:  corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "app", Name: "web"},
+			want: "crp-work",
+		},
+		"pod of a placed deployment": {
+			ref:  corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "web-7d4b9c-x2x5q"},
+			want: "crp-work",
+		},
+		"object not placed": {
+			ref: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "standalone"},
+		},
+		"placed object beyond the max owner hops": {
+			ref: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "deep"},
+		},
+		"object not found": {
+			ref: corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "gone"},
+		},
+		"kind not served": {
+			ref: corev1.ObjectReference{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "app", Name: "web"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := f.placingWork(context.Background(), tt.ref)
+			if err != nil {
+				t.Fatalf("placingWork() = %v, want nil", err)
+			}
+			if got != tt.want {
+				t.Errorf("placingWork() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsForwardedEvent(t *testing.T) {
+	tests := map[string]struct {
+		evt  corev1.Event
+		want bool
+	}{
+		"warning with a forwarded reason": {
+			evt:  corev1.Event{Type: corev1.EventTypeWarning, Reason: "FailedScheduling"},
+			want: true,
+		},
+		"warning with another reason": {
+			evt: corev1.Event{Type: corev1.EventTypeWarning, Reason: "FailedMount"},
+		},
+		"normal event": {
+			evt: corev1.Event{Type: corev1.EventTypeNormal, Reason: "BackOff"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isForwardedEvent(&tt.evt); got != tt.want {
+				t.Errorf("isForwardedEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
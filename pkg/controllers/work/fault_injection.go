@@ -0,0 +1,202 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// The annotations on the works which inject faults into the member agent, when it runs with the fault injection
+// enabled; they are meant for the developers to validate the behaviors of the fleet under partial failures, and are
+// ignored otherwise.
+const (
+	faultInjectionAnnotationPrefix = "fault-injection.kubernetes-fleet.io/"
+
+	// DropApplyEveryAnnotation drops every Nth apply of the work, where N is the value of the annotation: the
+	// reconciliation returns without applying the manifests or reporting the status, as if the apply was lost.
+	DropApplyEveryAnnotation = faultInjectionAnnotationPrefix + "drop-apply-every"
+
+	// DelayStatusAnnotation delays the status reporting of the work by the duration which is the value of the
+	// annotation, e.g. 30s; the delay is capped at maxInjectedStatusDelay.
+	DelayStatusAnnotation = faultInjectionAnnotationPrefix + "delay-status"
+
+	// APIErrorEveryAnnotation fails every Nth manifest apply of the work with an internal server error (HTTP 500) of
+	// the member cluster, where N is the value of the annotation.
+	APIErrorEveryAnnotation = faultInjectionAnnotationPrefix + "api-error-every"
+
+	// maxInjectedStatusDelay caps the delay of the status reporting so that a worker is not blocked for too long.
+	maxInjectedStatusDelay = 5 * time.Minute
+
+	// droppedApplyRequeueInterval is when a work whose apply is dropped is reconciled again.
+	droppedApplyRequeueInterval = 5 * time.Second
+)
+
+// errInjectedFault is the cause of the internal server errors injected by APIErrorEveryAnnotation.
+var errInjectedFault = errors.New("fault injected by the " + APIErrorEveryAnnotation + " annotation")
+
+// faultInjector counts the applies of the works to inject the faults requested by their annotations.
+type faultInjector struct {
+	mu sync.Mutex
+	// counters are the numbers of the reconciliations and the manifest applies of the works, keyed by their UIDs.
+	counters map[types.UID]*faultCounters
+}
+
+// faultCounters are the numbers of the reconciliations and the manifest applies of a work.
+type faultCounters struct {
+	reconciles int64
+	applies    int64
+}
+
+// workFaults are the faults injected into a reconciliation of a work; a nil workFaults injects no faults.
+type workFaults struct {
+	injector       *faultInjector
+	uid            types.UID
+	dropApplyEvery int64
+	apiErrorEvery  int64
+	statusDelay    time.Duration
+}
+
+// workFaultsKey is the context key of the workFaults of the work being reconciled.
+type workFaultsKey struct{}
+
+// WithFaultInjection makes the reconciler inject the faults requested by the annotations of the works.
+//
+// It is for the development and the testing of the fleet only, and must never be enabled in production.
+func (r *ApplyWorkReconciler) WithFaultInjection() *ApplyWorkReconciler {
+	r.faults = &faultInjector{counters: make(map[types.UID]*faultCounters)}
+	return r
+}
+
+// faultsOf returns the faults requested by the annotations of the work, or nil if there are none or the fault
+// injection is disabled; the annotations with invalid values are ignored.
+func (f *faultInjector) faultsOf(work *fleetv1beta1.Work) *workFaults {
+	if f == nil {
+		return nil
+	}
+	faults := &workFaults{injector: f, uid: work.UID}
+	annotations := work.GetAnnotations()
+	var err error
+	if value, ok := annotations[DropApplyEveryAnnotation]; ok {
+		if faults.dropApplyEvery, err = parseEvery(value); err != nil {
+			klog.ErrorS(err, "Ignoring the invalid fault injection annotation", "work", klog.KObj(work), "annotation", DropApplyEveryAnnotation)
+		}
+	}
+	if value, ok := annotations[APIErrorEveryAnnotation]; ok {
+		if faults.apiErrorEvery, err = parseEvery(value); err != nil {
+			klog.ErrorS(err, "Ignoring the invalid fault injection annotation", "work", klog.KObj(work), "annotation", APIErrorEveryAnnotation)
+		}
+	}
+	if value, ok := annotations[DelayStatusAnnotation]; ok {
+		if faults.statusDelay, err = time.ParseDuration(value); err != nil || faults.statusDelay < 0 {
+			klog.ErrorS(err, "Ignoring the invalid fault injection annotation", "work", klog.KObj(work), "annotation", DelayStatusAnnotation)
+			faults.statusDelay = 0
+		}
+		if faults.statusDelay > maxInjectedStatusDelay {
+			faults.statusDelay = maxInjectedStatusDelay
+		}
+	}
+	if faults.dropApplyEvery == 0 && faults.apiErrorEvery == 0 && faults.statusDelay == 0 {
+		return nil
+	}
+	return faults
+}
+
+// forget drops the counters of the work, e.g. once it is deleted.
+func (f *faultInjector) forget(uid types.UID) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.counters, uid)
+}
+
+// count increments the counter of the work selected by the function, and returns the new count.
+func (f *faultInjector) count(uid types.UID, counter func(*faultCounters) *int64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	counters, ok := f.counters[uid]
+	if !ok {
+		counters = &faultCounters{}
+		f.counters[uid] = counters
+	}
+	n := counter(counters)
+	*n++
+	return *n
+}
+
+// dropApply returns whether the apply of the work in this reconciliation is dropped.
+func (w *workFaults) dropApply() bool {
+	if w == nil || w.dropApplyEvery == 0 {
+		return false
+	}
+	n := w.injector.count(w.uid, func(c *faultCounters) *int64 { return &c.reconciles })
+	return n%w.dropApplyEvery == 0
+}
+
+// apiError returns the internal server error injected into the next manifest apply of the work, or nil.
+func (w *workFaults) apiError() error {
+	if w == nil || w.apiErrorEvery == 0 {
+		return nil
+	}
+	n := w.injector.count(w.uid, func(c *faultCounters) *int64 { return &c.applies })
+	if n%w.apiErrorEvery != 0 {
+		return nil
+	}
+	return apierrors.NewInternalError(errInjectedFault)
+}
+
+// delayStatus blocks for the injected delay of the status reporting, or until the context is done.
+func (w *workFaults) delayStatus(ctx context.Context) error {
+	if w == nil || w.statusDelay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(w.statusDelay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// contextWithWorkFaults returns the context carrying the faults of the work being reconciled.
+func contextWithWorkFaults(ctx context.Context, faults *workFaults) context.Context {
+	if faults == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, workFaultsKey{}, faults)
+}
+
+// workFaultsFromContext returns the faults of the work being reconciled, or nil.
+func workFaultsFromContext(ctx context.Context) *workFaults {
+	faults, _ := ctx.Value(workFaultsKey{}).(*workFaults)
+	return faults
+}
+
+// parseEvery parses the N of an "every Nth" fault, which must be a positive integer.
+func parseEvery(value string) (int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%d is not a positive integer", n)
+	}
+	return n, nil
+}
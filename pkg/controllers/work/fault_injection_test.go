@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func newFaultInjectedWork(annotations map[string]string) *fleetv1beta1.Work {
+	return &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{Name: "work", Namespace: "fleet-member-member-1", UID: "work-uid", Annotations: annotations},
+	}
+}
+
+func TestFaultsOf(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		want        *workFaults
+	}{
+		"no fault injection annotations": {
+			annotations: map[string]string{"other": "1"},
+		},
+		"all the faults": {
+			annotations: map[string]string{
+				DropApplyEveryAnnotation: "3",
+				APIErrorEveryAnnotation:  "2",
+				DelayStatusAnnotation:    "30s",
+			},
+			want: &workFaults{uid: "work-uid", dropApplyEvery: 3, apiErrorEvery: 2, statusDelay: 30 * time.Second},
+		},
+		"the status delay is capped": {
+			annotations: map[string]string{DelayStatusAnnotation: "1h"},
+			want:        &workFaults{uid: "work-uid", statusDelay: maxInjectedStatusDelay},
+		},
+		"the invalid annotations are ignored": {
+			annotations: map[string]string{
+				DropApplyEveryAnnotation: "0",
+				APIErrorEveryAnnotation:  "often",
+				DelayStatusAnnotation:    "-1s",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &faultInjector{}
+			got := f.faultsOf(newFaultInjectedWork(tc.annotations))
+			if tc.want != nil {
+				tc.want.injector = f
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(workFaults{}), cmp.Comparer(func(a, b *faultInjector) bool { return a == b })); diff != "" {
+				t.Errorf("faultsOf() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFaultInjectionDisabled(t *testing.T) {
+	var f *faultInjector
+	faults := f.faultsOf(newFaultInjectedWork(map[string]string{DropApplyEveryAnnotation: "1", APIErrorEveryAnnotation: "1"}))
+	if faults != nil {
+		t.Fatalf("faultsOf() = %+v, want nil when the fault injection is disabled", faults)
+	}
+	if faults.dropApply() || faults.apiError() != nil || faults.delayStatus(context.Background()) != nil {
+		t.Errorf("the nil faults inject faults, want none")
+	}
+}
+
+func TestInjectedFaultsAreCountedPerWork(t *testing.T) {
+	r := (&ApplyWorkReconciler{}).WithFaultInjection()
+	work := newFaultInjectedWork(map[string]string{DropApplyEveryAnnotation: "2", APIErrorEveryAnnotation: "3"})
+
+	var gotDropped []bool
+	var gotAPIErrors []bool
+	for i := 0; i < 4; i++ {
+		// the faults are parsed again in every reconciliation, while the counts are kept
+		faults := r.faults.faultsOf(work)
+		gotDropped = append(gotDropped, faults.dropApply())
+		err := workFaultsFromContext(contextWithWorkFaults(context.Background(), faults)).apiError()
+		if err != nil && !apierrors.IsInternalError(err) {
+			t.Fatalf("apiError() = %v, want an internal server error", err)
+		}
+		gotAPIErrors = append(gotAPIErrors, err != nil)
+	}
+	if diff := cmp.Diff([]bool{false, true, false, true}, gotDropped); diff != "" {
+		t.Errorf("dropApply() mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]bool{false, false, true, false}, gotAPIErrors); diff != "" {
+		t.Errorf("apiError() mismatch (-want, +got):\n%s", diff)
+	}
+
+	// the counts are dropped once the work is forgotten
+	r.faults.forget(work.UID)
+	if len(r.faults.counters) != 0 {
+		t.Errorf("forget() left the counters %v, want none", r.faults.counters)
+	}
+}
+
+func TestDelayStatus(t *testing.T) {
+	faults := &workFaults{statusDelay: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := faults.delayStatus(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("delayStatus() with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+	faults.statusDelay = time.Millisecond
+	if err := faults.delayStatus(context.Background()); err != nil {
+		t.Errorf("delayStatus() = %v, want nil", err)
+	}
+}
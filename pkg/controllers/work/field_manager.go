@@ -0,0 +1,114 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// legacyWorkFieldManagerNames are the field managers which the work applier has used for the create, update and patch
+// operations, i.e., the client side apply. The fields owned by them are migrated to the server side apply field manager
+// before the resource is server side applied. Append the former name here when renaming the field manager.
+var legacyWorkFieldManagerNames = sets.New(workFieldManagerName)
+
+// migrateFieldManagers transfers the ownership of the fields in the manifest from the legacy field managers of the
+// work applier to its server side apply field manager, so that the resources which used to be client side applied can
+// be server side applied without spurious conflicts with the legacy field managers.
+// It is a no-op if the resource has no fields owned by the legacy field managers.
+func migrateFieldManagers(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource,
+	manifestObj, curObj *unstructured.Unstructured) error {
+	patch, err := upgradeManagedFieldsPatch(manifestObj, curObj)
+	if err != nil {
+		klog.ErrorS(err, "Failed to migrate the legacy field managers", "gvr", gvr, "manifest", klog.KObj(manifestObj))
+		return controller.NewUnexpectedBehaviorError(err)
+	}
+	if patch == nil {
+		return nil
+	}
+	// the patch replaces the resource version too, so that it fails with conflict if the managed fields have changed
+	if _, err := client.Resource(gvr).Namespace(curObj.GetNamespace()).Patch(ctx, curObj.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to migrate the legacy field managers", "gvr", gvr, "manifest", klog.KObj(manifestObj))
+		return controller.NewAPIServerError(false, err)
+	}
+	klog.V(2).InfoS("Migrated the legacy field managers to server side apply", "gvr", gvr, "manifest", klog.KObj(manifestObj))
+	return nil
+}
+
+// upgradeManagedFieldsPatch returns the JSON patch which migrates the managed fields of the legacy field managers, or
+// nil if there is nothing to migrate.
+//
+// The legacy field managers also own the fields defaulted by the API server when they created the resource, e.g., the
+// replicas of a deployment which is later scaled by an HPA with the same value. The server side apply removes the
+// fields which its field manager owns but are not in the manifest, so only the fields in the manifest are migrated and
+// the rest are left unowned instead of being wiped.
+func upgradeManagedFieldsPatch(manifestObj, curObj *unstructured.Unstructured) ([]byte, error) {
+	obj := curObj.DeepCopy()
+	managedFields := obj.GetManagedFields()
+	for i := range managedFields {
+		entry := &managedFields[i]
+		if !legacyWorkFieldManagerNames.Has(entry.Manager) || entry.Operation != metav1.ManagedFieldsOperationUpdate ||
+			entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("failed to decode the fields managed by %s: %w", entry.Manager, err)
+		}
+		migratedFields := fieldpath.NewSet()
+		fields.Iterate(func(path fieldpath.Path) {
+			if isPathMigrated(manifestObj.Object, path) {
+				migratedFields.Insert(path)
+			}
+		})
+		raw, err := migratedFields.ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the fields managed by %s: %w", entry.Manager, err)
+		}
+		entry.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+	}
+	obj.SetManagedFields(managedFields)
+	return csaupgrade.UpgradeManagedFieldsPatch(obj, legacyWorkFieldManagerNames, workFieldManagerName)
+}
+
+// isPathMigrated tells if the ownership of the field path is migrated to the server side apply field manager.
+// The fields are matched against the manifest by their names only; the lists are migrated as a whole.
+func isPathMigrated(manifest map[string]interface{}, path fieldpath.Path) bool {
+	// the annotations recorded by the client side apply are removed by the server side apply
+	if len(path) == 3 && path[0].FieldName != nil && *path[0].FieldName == "metadata" &&
+		path[1].FieldName != nil && *path[1].FieldName == "annotations" && path[2].FieldName != nil &&
+		(*path[2].FieldName == fleetv1beta1.LastAppliedConfigAnnotation || *path[2].FieldName == fleetv1beta1.ManifestHashAnnotation) {
+		return true
+	}
+	var cur interface{} = manifest
+	for _, element := range path {
+		if element.FieldName == nil {
+			return true
+		}
+		fields, ok := cur.(map[string]interface{})
+		if !ok {
+			return true
+		}
+		if cur, ok = fields[*element.FieldName]; !ok {
+			return false
+		}
+	}
+	return true
+}
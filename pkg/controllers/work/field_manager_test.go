@@ -0,0 +1,138 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestUpgradeManagedFieldsPatch(t *testing.T) {
+	manifestObj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "app",
+				"namespace": "app",
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "app"},
+				},
+			},
+		},
+	}
+	csaFields := `{"f:metadata":{"f:annotations":{".":{},"f:` + fleetv1beta1.LastAppliedConfigAnnotation + `":{}}},` +
+		`"f:spec":{"f:replicas":{},"f:selector":{}}}`
+	hpaFields := `{"f:spec":{"f:replicas":{}}}`
+	newObj := func(entries ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+		obj := manifestObj.DeepCopy()
+		obj.SetResourceVersion("1")
+		obj.SetManagedFields(entries)
+		return obj
+	}
+	csaEntry := metav1.ManagedFieldsEntry{
+		Manager:    workFieldManagerName,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "apps/v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(csaFields)},
+	}
+	ssaEntry := metav1.ManagedFieldsEntry{
+		Manager:    workFieldManagerName,
+		Operation:  metav1.ManagedFieldsOperationApply,
+		APIVersion: "apps/v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:selector":{}}}`)},
+	}
+	hpaEntry := metav1.ManagedFieldsEntry{
+		Manager:     "kube-controller-manager",
+		Operation:   metav1.ManagedFieldsOperationUpdate,
+		APIVersion:  "autoscaling/v1",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(hpaFields)},
+		Subresource: "scale",
+	}
+	tests := map[string]struct {
+		curObj    *unstructured.Unstructured
+		wantPatch bool
+	}{
+		"the resource has never been client side applied": {
+			curObj: newObj(ssaEntry, hpaEntry),
+		},
+		"the resource has no managed fields": {
+			curObj: newObj(),
+		},
+		"the fields in the manifest and the recorded annotations are migrated": {
+			curObj:    newObj(csaEntry, hpaEntry),
+			wantPatch: true,
+		},
+		"the fields are merged into the existing server side apply field manager": {
+			curObj:    newObj(ssaEntry, csaEntry),
+			wantPatch: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			patch, err := upgradeManagedFieldsPatch(manifestObj, tc.curObj)
+			if err != nil {
+				t.Fatalf("upgradeManagedFieldsPatch() = %v, want nil", err)
+			}
+			if gotPatch := patch != nil; gotPatch != tc.wantPatch {
+				t.Fatalf("upgradeManagedFieldsPatch() = %s, want patch %v", patch, tc.wantPatch)
+			}
+			if !tc.wantPatch {
+				return
+			}
+			var rawOps []map[string]json.RawMessage
+			if err := json.Unmarshal(patch, &rawOps); err != nil {
+				t.Fatalf("failed to decode the patch %s: %v", patch, err)
+			}
+			var entries []metav1.ManagedFieldsEntry
+			for _, op := range rawOps {
+				if string(op["path"]) == `"/metadata/managedFields"` {
+					if err := json.Unmarshal(op["value"], &entries); err != nil {
+						t.Fatalf("failed to decode the managed fields %s: %v", op["value"], err)
+					}
+				}
+			}
+			var gotSSAFields map[string]interface{}
+			for _, entry := range entries {
+				if entry.Manager != workFieldManagerName {
+					if entry.Manager == "kube-controller-manager" && string(entry.FieldsV1.Raw) != hpaFields {
+						t.Errorf("fields of the other field manager = %s, want %s", entry.FieldsV1.Raw, hpaFields)
+					}
+					continue
+				}
+				if entry.Operation != metav1.ManagedFieldsOperationApply {
+					t.Errorf("field manager %s still has the %s operation", entry.Manager, entry.Operation)
+					continue
+				}
+				if err := json.Unmarshal(entry.FieldsV1.Raw, &gotSSAFields); err != nil {
+					t.Fatalf("failed to decode the fields %s: %v", entry.FieldsV1.Raw, err)
+				}
+			}
+			spec, _ := gotSSAFields["f:spec"].(map[string]interface{})
+			if _, ok := spec["f:replicas"]; ok {
+				t.Errorf("the replicas not in the manifest are migrated: %v", gotSSAFields)
+			}
+			if _, ok := spec["f:selector"]; !ok {
+				t.Errorf("the selector in the manifest is not migrated: %v", gotSSAFields)
+			}
+			metadata, _ := gotSSAFields["f:metadata"].(map[string]interface{})
+			annotations, _ := metadata["f:annotations"].(map[string]interface{})
+			if _, ok := annotations["f:"+fleetv1beta1.LastAppliedConfigAnnotation]; !ok {
+				t.Errorf("the last applied configuration annotation is not migrated: %v", gotSSAFields)
+			}
+		})
+	}
+}
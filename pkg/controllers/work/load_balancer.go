@@ -0,0 +1,114 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// loadBalancerProbeTimeout is how long the member agent waits for a load balancer to accept a connection.
+const loadBalancerProbeTimeout = 3 * time.Second
+
+// probeLoadBalancer tells if the address accepts TCP connections; it is a variable so that the tests can fake it.
+var probeLoadBalancer = func(address string) error {
+	conn, err := net.DialTimeout("tcp", address, loadBalancerProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// trackLoadBalancerAvailability regards a service of the LoadBalancer type as available if its load balancer is
+// assigned an IP address or a DNS name without any port errors, and, if the service asks for the probe by the
+// ProbeLoadBalancerAnnotation, if one of its addresses accepts TCP connections on all the TCP ports of the service.
+func trackLoadBalancerAvailability(service *v1.Service) ApplyAction {
+	var assigned []v1.LoadBalancerIngress
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP == "" && ingress.Hostname == "" {
+			continue
+		}
+		for _, port := range ingress.Ports {
+			if port.Error != nil {
+				klog.V(2).InfoS("The loadBalancer of the service reports a port error", "service", klog.KObj(service),
+					"port", port.Port, "error", *port.Error)
+				return manifestNotAvailableYetAction
+			}
+		}
+		assigned = append(assigned, ingress)
+	}
+	if len(assigned) == 0 {
+		klog.V(2).InfoS("Still need to wait for loadBalancer service to be assigned an IP or hostname", "service", klog.KObj(service))
+		return manifestNotAvailableYetAction
+	}
+	if service.Annotations[fleetv1beta1.ProbeLoadBalancerAnnotation] != "true" {
+		klog.V(2).InfoS("LoadBalancer service is available", "service", klog.KObj(service))
+		return manifestAvailableAction
+	}
+	for _, ingress := range assigned {
+		if isLoadBalancerReachable(service, ingress) {
+			klog.V(2).InfoS("LoadBalancer service is available and reachable", "service", klog.KObj(service))
+			return manifestAvailableAction
+		}
+	}
+	klog.V(2).InfoS("Still need to wait for loadBalancer service to be reachable", "service", klog.KObj(service))
+	return manifestNotAvailableYetAction
+}
+
+// isLoadBalancerReachable tells if the load balancer accepts TCP connections at the ingress on all the TCP ports of
+// the service.
+func isLoadBalancerReachable(service *v1.Service, ingress v1.LoadBalancerIngress) bool {
+	host := ingress.IP
+	if host == "" {
+		host = ingress.Hostname
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+			continue
+		}
+		address := net.JoinHostPort(host, strconv.Itoa(int(port.Port)))
+		if err := probeLoadBalancer(address); err != nil {
+			klog.V(2).InfoS("The loadBalancer of the service is not reachable", "service", klog.KObj(service),
+				"address", address, "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// buildLoadBalancerStatus returns the status of the load balancer of the object if it is a service of the
+// LoadBalancer type which is assigned an IP address or a DNS name; otherwise, it returns nil.
+func buildLoadBalancerStatus(obj *unstructured.Unstructured) *fleetv1beta1.ServiceLoadBalancerStatus {
+	var service v1.Service
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &service); err != nil {
+		klog.ErrorS(err, "Failed to convert the service to report its loadBalancer", "service", klog.KObj(obj))
+		return nil
+	}
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer {
+		return nil
+	}
+	var status fleetv1beta1.ServiceLoadBalancerStatus
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" || ingress.Hostname != "" {
+			status.Ingress = append(status.Ingress, fleetv1beta1.LoadBalancerIngress{IP: ingress.IP, Hostname: ingress.Hostname})
+		}
+	}
+	if len(status.Ingress) == 0 {
+		return nil
+	}
+	for _, port := range service.Spec.Ports {
+		status.Ports = append(status.Ports, port.Port)
+	}
+	return &status
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestTrackLoadBalancerAvailability(t *testing.T) {
+	newService := func(probe bool, ingress ...v1.LoadBalancerIngress) *v1.Service {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "app"},
+			Spec: v1.ServiceSpec{
+				Type: v1.ServiceTypeLoadBalancer,
+				Ports: []v1.ServicePort{
+					{Port: 80, Protocol: v1.ProtocolTCP},
+					{Port: 443},
+					{Port: 53, Protocol: v1.ProtocolUDP},
+				},
+			},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: ingress}},
+		}
+		if probe {
+			service.Annotations = map[string]string{fleetv1beta1.ProbeLoadBalancerAnnotation: "true"}
+		}
+		return service
+	}
+	reachable := map[string]bool{"10.0.0.1:80": true, "10.0.0.1:443": true, "lb.example.com:80": true}
+
+	tests := map[string]struct {
+		service    *v1.Service
+		want       ApplyAction
+		wantProbed []string
+	}{
+		"no ingress": {
+			service: newService(false),
+			want:    manifestNotAvailableYetAction,
+		},
+		"ingress without an address": {
+			service: newService(false, v1.LoadBalancerIngress{}),
+			want:    manifestNotAvailableYetAction,
+		},
+		"ingress with an IP": {
+			service: newService(false, v1.LoadBalancerIngress{IP: "10.0.0.2"}),
+			want:    manifestAvailableAction,
+		},
+		"ingress with a hostname": {
+			service: newService(false, v1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			want:    manifestAvailableAction,
+		},
+		"ingress with a port error": {
+			service: newService(false, v1.LoadBalancerIngress{
+				IP:    "10.0.0.2",
+				Ports: []v1.PortStatus{{Port: 80, Protocol: v1.ProtocolTCP, Error: ptr.To("CertificateError")}},
+			}),
+			want: manifestNotAvailableYetAction,
+		},
+		"probed ingress reachable on all the TCP ports": {
+			service:    newService(true, v1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			want:       manifestAvailableAction,
+			wantProbed: []string{"10.0.0.1:80", "10.0.0.1:443"},
+		},
+		"probed ingress not reachable on a TCP port": {
+			service:    newService(true, v1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			want:       manifestNotAvailableYetAction,
+			wantProbed: []string{"lb.example.com:80", "lb.example.com:443"},
+		},
+		"one of the probed ingresses reachable": {
+			service:    newService(true, v1.LoadBalancerIngress{IP: "10.0.0.2"}, v1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			want:       manifestAvailableAction,
+			wantProbed: []string{"10.0.0.2:80", "10.0.0.1:80", "10.0.0.1:443"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			original := probeLoadBalancer
+			defer func() { probeLoadBalancer = original }()
+			var probed []string
+			probeLoadBalancer = func(address string) error {
+				probed = append(probed, address)
+				if !reachable[address] {
+					return errors.New("connection refused")
+				}
+				return nil
+			}
+			if got := trackLoadBalancerAvailability(tt.service); got != tt.want {
+				t.Errorf("trackLoadBalancerAvailability() = %s, want %s", got, tt.want)
+			}
+			if diff := cmp.Diff(tt.wantProbed, probed); diff != "" {
+				t.Errorf("trackLoadBalancerAvailability() probed addresses mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildLoadBalancerStatus(t *testing.T) {
+	toUnstructured := func(service *v1.Service) *unstructured.Unstructured {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(service)
+		if err != nil {
+			t.Fatalf("failed to convert the service: %v", err)
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+	tests := map[string]struct {
+		service *v1.Service
+		want    *fleetv1beta1.ServiceLoadBalancerStatus
+	}{
+		"loadBalancer service with addresses": {
+			service: &v1.Service{
+				Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, Ports: []v1.ServicePort{{Port: 80}, {Port: 443}}},
+				Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{
+					{IP: "10.0.0.1"}, {}, {Hostname: "lb.example.com"},
+				}}},
+			},
+			want: &fleetv1beta1.ServiceLoadBalancerStatus{
+				Ingress: []fleetv1beta1.LoadBalancerIngress{{IP: "10.0.0.1"}, {Hostname: "lb.example.com"}},
+				Ports:   []int32{80, 443},
+			},
+		},
+		"loadBalancer service without addresses": {
+			service: &v1.Service{
+				Spec:   v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, Ports: []v1.ServicePort{{Port: 80}}},
+				Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{}}}},
+			},
+		},
+		"clusterIP service": {
+			service: &v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: "10.96.0.10"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := buildLoadBalancerStatus(toUnstructured(tt.service))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("buildLoadBalancerStatus() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
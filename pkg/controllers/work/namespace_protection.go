@@ -0,0 +1,104 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// isNamespace tells if the object is a namespace.
+func isNamespace(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Namespace"
+}
+
+// setNamespaceProtectionFinalizer adds the deletion protection finalizer to the namespace manifest if the apply
+// strategy protects the namespaces.
+// The finalizer is applied along with the rest of the manifest, so it's removed by the next apply once the apply
+// strategy no longer protects the namespaces.
+func setNamespaceProtectionFinalizer(applyStrategy *fleetv1beta1.ApplyStrategy, manifestObj *unstructured.Unstructured) {
+	if applyStrategy == nil || !applyStrategy.ProtectNamespaces || !isNamespace(manifestObj) {
+		return
+	}
+	controllerutil.AddFinalizer(manifestObj, fleetv1beta1.NamespaceProtectionFinalizer)
+}
+
+// releaseNamespaceProtection removes the deletion protection finalizer from the namespace if no placement owns it
+// after the given owners are left, so that the namespace can be deleted.
+// It returns true if the finalizer is removed from the object.
+func releaseNamespaceProtection(obj *unstructured.Unstructured, owners []metav1.OwnerReference) bool {
+	if !isNamespace(obj) || !controllerutil.ContainsFinalizer(obj, fleetv1beta1.NamespaceProtectionFinalizer) {
+		return false
+	}
+	for _, owner := range owners {
+		if owner.APIVersion == fleetv1beta1.GroupVersion.String() && owner.Kind == fleetv1beta1.AppliedWorkKind {
+			// another placement still protects the namespace
+			return false
+		}
+	}
+	return controllerutil.RemoveFinalizer(obj, fleetv1beta1.NamespaceProtectionFinalizer)
+}
+
+// releaseProtectedNamespaces removes the deletion protection finalizer from the namespaces which the appliedWork is
+// the last placement to own, before the appliedWork is deleted and the namespaces are garbage collected along with it.
+func (r *ApplyWorkReconciler) releaseProtectedNamespaces(ctx context.Context, work *fleetv1beta1.Work) error {
+	appliedWork := &fleetv1beta1.AppliedWork{}
+	if err := r.spokeClient.Get(ctx, types.NamespacedName{Name: work.Name}, appliedWork); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		klog.ErrorS(err, "Failed to retrieve the appliedWork", "appliedWork", work.Name)
+		return controller.NewAPIServerError(false, err)
+	}
+	owner := metav1.OwnerReference{
+		APIVersion:         fleetv1beta1.GroupVersion.String(),
+		Kind:               fleetv1beta1.AppliedWorkKind,
+		Name:               appliedWork.GetName(),
+		UID:                appliedWork.GetUID(),
+		BlockOwnerDeletion: ptr.To(false),
+	}
+	for _, resourceMeta := range appliedWork.Status.AppliedResources {
+		if resourceMeta.Group != "" || resourceMeta.Kind != "Namespace" {
+			continue
+		}
+		gvr := schema.GroupVersionResource{Group: resourceMeta.Group, Version: resourceMeta.Version, Resource: resourceMeta.Resource}
+		obj, err := r.spokeDynamicClient.Resource(gvr).Get(ctx, resourceMeta.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil:
+			klog.ErrorS(err, "Failed to get the applied namespace", "work", klog.KObj(work), "namespace", resourceMeta.Name)
+			return controller.NewAPIServerError(false, err)
+		}
+		owners := obj.GetOwnerReferences()
+		idx := indexOwnerRef(owners, owner)
+		if idx == -1 {
+			continue
+		}
+		otherOwners := append(append([]metav1.OwnerReference{}, owners[:idx]...), owners[idx+1:]...)
+		if !releaseNamespaceProtection(obj, otherOwners) {
+			continue
+		}
+		if _, err := r.spokeDynamicClient.Resource(gvr).Update(ctx, obj, metav1.UpdateOptions{FieldManager: workFieldManagerName}); err != nil {
+			klog.ErrorS(err, "Failed to release the deletion protection of the namespace", "work", klog.KObj(work), "namespace", resourceMeta.Name)
+			return controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Released the deletion protection of the namespace", "work", klog.KObj(work), "namespace", resourceMeta.Name)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func newNamespaceObj(finalizers []string, owners ...metav1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Namespace")
+	obj.SetName("app")
+	obj.SetFinalizers(finalizers)
+	obj.SetOwnerReferences(owners)
+	return obj
+}
+
+func TestSetNamespaceProtectionFinalizer(t *testing.T) {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	tests := map[string]struct {
+		applyStrategy  *placementv1beta1.ApplyStrategy
+		obj            *unstructured.Unstructured
+		wantFinalizers []string
+	}{
+		"no apply strategy": {
+			obj: newNamespaceObj(nil),
+		},
+		"namespaces are not protected": {
+			applyStrategy: &placementv1beta1.ApplyStrategy{},
+			obj:           newNamespaceObj(nil),
+		},
+		"namespace is protected": {
+			applyStrategy:  &placementv1beta1.ApplyStrategy{ProtectNamespaces: true},
+			obj:            newNamespaceObj([]string{"kubernetes"}),
+			wantFinalizers: []string{"kubernetes", placementv1beta1.NamespaceProtectionFinalizer},
+		},
+		"other resources are not protected": {
+			applyStrategy: &placementv1beta1.ApplyStrategy{ProtectNamespaces: true},
+			obj:           configMap,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			setNamespaceProtectionFinalizer(tc.applyStrategy, tc.obj)
+			if diff := cmp.Diff(tc.wantFinalizers, tc.obj.GetFinalizers()); diff != "" {
+				t.Errorf("setNamespaceProtectionFinalizer() finalizers mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReleaseNamespaceProtection(t *testing.T) {
+	appliedWorkOwner := metav1.OwnerReference{
+		APIVersion: placementv1beta1.GroupVersion.String(),
+		Kind:       placementv1beta1.AppliedWorkKind,
+		Name:       "other-work",
+	}
+	otherOwner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "config"}
+	tests := map[string]struct {
+		obj            *unstructured.Unstructured
+		owners         []metav1.OwnerReference
+		want           bool
+		wantFinalizers []string
+	}{
+		"released when no placement owns the namespace": {
+			obj:            newNamespaceObj([]string{placementv1beta1.NamespaceProtectionFinalizer, "kubernetes"}),
+			owners:         []metav1.OwnerReference{otherOwner},
+			want:           true,
+			wantFinalizers: []string{"kubernetes"},
+		},
+		"kept when another placement owns the namespace": {
+			obj:            newNamespaceObj([]string{placementv1beta1.NamespaceProtectionFinalizer}),
+			owners:         []metav1.OwnerReference{appliedWorkOwner},
+			wantFinalizers: []string{placementv1beta1.NamespaceProtectionFinalizer},
+		},
+		"namespace is not protected": {
+			obj:            newNamespaceObj([]string{"kubernetes"}),
+			wantFinalizers: []string{"kubernetes"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := releaseNamespaceProtection(tc.obj, tc.owners); got != tc.want {
+				t.Errorf("releaseNamespaceProtection() = %v, want %v", got, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantFinalizers, tc.obj.GetFinalizers()); diff != "" {
+				t.Errorf("releaseNamespaceProtection() finalizers mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeleteStaleManifestReleasesNamespace(t *testing.T) {
+	owner := metav1.OwnerReference{
+		APIVersion: placementv1beta1.GroupVersion.String(),
+		Kind:       placementv1beta1.AppliedWorkKind,
+		Name:       "work",
+		UID:        "work-uid",
+	}
+	otherOwner := owner
+	otherOwner.Name, otherOwner.UID = "other-work", "other-work-uid"
+	namespaceGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	staleManifests := []placementv1beta1.AppliedResourceMeta{
+		{
+			WorkResourceIdentifier: placementv1beta1.WorkResourceIdentifier{
+				Version:  "v1",
+				Kind:     "Namespace",
+				Resource: "namespaces",
+				Name:     "app",
+			},
+		},
+	}
+	tests := map[string]struct {
+		obj         *unstructured.Unstructured
+		wantActions []string
+	}{
+		"the last placement releases the namespace before deleting it": {
+			obj:         newNamespaceObj([]string{placementv1beta1.NamespaceProtectionFinalizer}, owner),
+			wantActions: []string{"get", "update", "delete"},
+		},
+		"the namespace stays protected while another placement owns it": {
+			obj:         newNamespaceObj([]string{placementv1beta1.NamespaceProtectionFinalizer}, owner, otherOwner),
+			wantActions: []string{"get", "update"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dynamicClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{namespaceGVR: "NamespaceList"}, tc.obj)
+			r := &ApplyWorkReconciler{spokeDynamicClient: dynamicClient}
+			if _, err := r.deleteStaleManifest(context.Background(), staleManifests, owner); err != nil {
+				t.Fatalf("deleteStaleManifest() = %v, want nil", err)
+			}
+			var gotActions []string
+			for _, action := range dynamicClient.Actions() {
+				gotActions = append(gotActions, action.GetVerb())
+			}
+			if diff := cmp.Diff(tc.wantActions, gotActions); diff != "" {
+				t.Errorf("deleteStaleManifest() actions mismatch (-want, +got):\n%s", diff)
+			}
+			if len(tc.wantActions) > 0 && tc.wantActions[len(tc.wantActions)-1] == "delete" {
+				return
+			}
+			got, err := dynamicClient.Resource(namespaceGVR).Get(context.Background(), "app", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get the namespace: %v", err)
+			}
+			if diff := cmp.Diff([]string{placementv1beta1.NamespaceProtectionFinalizer}, got.GetFinalizers()); diff != "" {
+				t.Errorf("deleteStaleManifest() finalizers mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// ResourcesObservedReason is the reason string of the work observed condition when all the observations of the
+	// work are resolved on the member cluster.
+	ResourcesObservedReason = "ResourcesObserved"
+	// ResourcesNotObservedReason is the reason string of the work observed condition when some observations of the
+	// work cannot be resolved on the member cluster, e.g. their kinds are not served.
+	ResourcesNotObservedReason = "ResourcesNotObserved"
+
+	// observationRequeueInterval is how often the resources selected by the observations of a work are observed again,
+	// as they are not applied by the member agent and may change at any time.
+	observationRequeueInterval = time.Minute
+)
+
+// observedHealthOrder is the order in which the observed resources are listed, so that the unhealthy ones are kept
+// when the list is truncated.
+var observedHealthOrder = map[fleetv1beta1.ObservedResourceHealth]int{
+	fleetv1beta1.ObservedResourceUnhealthy:     0,
+	fleetv1beta1.ObservedResourceHealthUnknown: 1,
+	fleetv1beta1.ObservedResourceHealthy:       2,
+}
+
+// observeResources finds the resources selected by the observations of the work on the member cluster and reports
+// them in the status of the work; nothing is applied for the observations.
+func (r *ApplyWorkReconciler) observeResources(ctx context.Context, work *fleetv1beta1.Work) {
+	if len(work.Spec.Observations) == 0 {
+		work.Status.ObservedResources = nil
+		work.Status.ObservedResourceCount = 0
+		meta.RemoveStatusCondition(&work.Status.Conditions, fleetv1beta1.WorkConditionTypeObserved)
+		return
+	}
+
+	observed := make(map[fleetv1beta1.ObservedResource]bool)
+	var errs []error
+	for i := range work.Spec.Observations {
+		resources, err := r.observe(ctx, &work.Spec.Observations[i])
+		if err != nil {
+			klog.ErrorS(err, "Failed to observe the resources", "work", klog.KObj(work), "selector", work.Spec.Observations[i])
+			errs = append(errs, err)
+			continue
+		}
+		for _, resource := range resources {
+			observed[resource] = true
+		}
+	}
+	resources := make([]fleetv1beta1.ObservedResource, 0, len(observed))
+	for resource := range observed {
+		resources = append(resources, resource)
+	}
+	sortObservedResources(resources)
+	work.Status.ObservedResourceCount = len(resources)
+	if len(resources) > fleetv1beta1.MaxObservedResourcesPerCluster {
+		resources = resources[:fleetv1beta1.MaxObservedResourcesPerCluster]
+	}
+	work.Status.ObservedResources = resources
+
+	observedCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeObserved,
+		Status:             metav1.ConditionTrue,
+		Reason:             ResourcesObservedReason,
+		Message:            fmt.Sprintf("%d resources are observed", work.Status.ObservedResourceCount),
+		ObservedGeneration: work.Generation,
+	}
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		observedCondition.Status = metav1.ConditionFalse
+		observedCondition.Reason = ResourcesNotObservedReason
+		observedCondition.Message = fmt.Sprintf("Failed to observe some resources: %v", err)
+	}
+	meta.SetStatusCondition(&work.Status.Conditions, observedCondition)
+}
+
+// observe lists the resources selected by a selector on the member cluster along with their health.
+func (r *ApplyWorkReconciler) observe(ctx context.Context, selector *fleetv1beta1.ObservedResourceSelector) ([]fleetv1beta1.ObservedResource, error) {
+	gvk := schema.GroupVersionKind{Group: selector.Group, Version: selector.Version, Kind: selector.Kind}
+	mapping, err := r.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the resource of %s: %w", gvk, err)
+	}
+	opts := metav1.ListOptions{}
+	if selector.LabelSelector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector of %s: %w", gvk, err)
+		}
+		opts.LabelSelector = labelSelector.String()
+	}
+	if selector.Name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", selector.Name).String()
+	}
+	var resourceClient dynamic.ResourceInterface = r.spokeDynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		// all the namespaces are listed if the namespace is empty
+		resourceClient = r.spokeDynamicClient.Resource(mapping.Resource).Namespace(selector.Namespace)
+	}
+	list, err := resourceClient.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", mapping.Resource, err)
+	}
+	resources := make([]fleetv1beta1.ObservedResource, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		resources = append(resources, fleetv1beta1.ObservedResource{
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Health:    observedResourceHealth(mapping.Resource, obj),
+		})
+	}
+	return resources, nil
+}
+
+// observedResourceHealth tracks the health of an observed resource the same way as the availability of an applied one.
+func observedResourceHealth(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) fleetv1beta1.ObservedResourceHealth {
+	action, err := trackResourceAvailability(gvr, obj, nil)
+	if err != nil {
+		klog.ErrorS(err, "Failed to track the health of the observed resource", "gvr", gvr, "resource", klog.KObj(obj))
+		return fleetv1beta1.ObservedResourceHealthUnknown
+	}
+	switch action {
+	case manifestAvailableAction, manifestPausedAction, manifestScaledToZeroAction:
+		return fleetv1beta1.ObservedResourceHealthy
+	case manifestNotAvailableYetAction, manifestProvisioningAction:
+		return fleetv1beta1.ObservedResourceUnhealthy
+	default:
+		return fleetv1beta1.ObservedResourceHealthUnknown
+	}
+}
+
+// sortObservedResources sorts the observed resources by their health, the unhealthy ones first, and then by their
+// identities.
+func sortObservedResources(resources []fleetv1beta1.ObservedResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Health != b.Health {
+			return observedHealthOrder[a.Health] < observedHealthOrder[b.Health]
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+}
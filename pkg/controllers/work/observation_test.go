@@ -0,0 +1,156 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func newObservedDeployment(namespace, name string, replicas, availableReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":       name,
+			"namespace":  namespace,
+			"generation": int64(1),
+			"labels":     map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{"replicas": replicas},
+		"status": map[string]interface{}{
+			"observedGeneration": int64(1),
+			"replicas":           replicas,
+			"updatedReplicas":    replicas,
+			"availableReplicas":  availableReplicas,
+		},
+	}}
+}
+
+func newObservedObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+	}}
+}
+
+func TestObserveResources(t *testing.T) {
+	serviceAccountGVR := schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"}, meta.RESTScopeNamespace)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			utils.DeploymentGVR: "DeploymentList",
+			utils.ConfigMapGVR:  "ConfigMapList",
+			serviceAccountGVR:   "ServiceAccountList",
+		},
+		newObservedDeployment("app", "web", 2, 2),
+		newObservedDeployment("other", "web", 2, 1),
+		newObservedObject("v1", "ConfigMap", "app", "config"),
+		newObservedObject("v1", "ConfigMap", "other", "config"),
+		newObservedObject("v1", "ServiceAccount", "app", "runner"),
+	)
+	deploymentSelector := fleetv1beta1.ObservedResourceSelector{
+		Group:         "apps",
+		Version:       "v1",
+		Kind:          "Deployment",
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+	}
+	tests := map[string]struct {
+		observations  []fleetv1beta1.ObservedResourceSelector
+		wantResources []fleetv1beta1.ObservedResource
+		wantCondition *metav1.Condition
+	}{
+		"the unhealthy resources are listed first": {
+			observations: []fleetv1beta1.ObservedResourceSelector{
+				deploymentSelector,
+				{Version: "v1", Kind: "ConfigMap", Namespace: "app"},
+				{Version: "v1", Kind: "ServiceAccount"},
+				// the deployments selected twice are listed once
+				{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "app"},
+			},
+			wantResources: []fleetv1beta1.ObservedResource{
+				{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "other", Name: "web", Health: fleetv1beta1.ObservedResourceUnhealthy},
+				{Version: "v1", Kind: "ServiceAccount", Namespace: "app", Name: "runner", Health: fleetv1beta1.ObservedResourceHealthUnknown},
+				{Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "config", Health: fleetv1beta1.ObservedResourceHealthy},
+				{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "app", Name: "web", Health: fleetv1beta1.ObservedResourceHealthy},
+			},
+			wantCondition: &metav1.Condition{
+				Type:               fleetv1beta1.WorkConditionTypeObserved,
+				Status:             metav1.ConditionTrue,
+				Reason:             ResourcesObservedReason,
+				Message:            "4 resources are observed",
+				ObservedGeneration: 2,
+			},
+		},
+		"the kinds which are not served are reported": {
+			observations: []fleetv1beta1.ObservedResourceSelector{
+				{Group: "example.com", Version: "v1", Kind: "Widget"},
+				{Version: "v1", Kind: "ConfigMap", Namespace: "other"},
+			},
+			wantResources: []fleetv1beta1.ObservedResource{
+				{Version: "v1", Kind: "ConfigMap", Namespace: "other", Name: "config", Health: fleetv1beta1.ObservedResourceHealthy},
+			},
+			wantCondition: &metav1.Condition{
+				Type:               fleetv1beta1.WorkConditionTypeObserved,
+				Status:             metav1.ConditionFalse,
+				Reason:             ResourcesNotObservedReason,
+				ObservedGeneration: 2,
+			},
+		},
+		"the work without observations reports nothing": {
+			wantResources: nil,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &ApplyWorkReconciler{spokeDynamicClient: dynamicClient, restMapper: restMapper}
+			work := &fleetv1beta1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "observation", Namespace: "fleet-member-1", Generation: 2},
+				Spec:       fleetv1beta1.WorkSpec{Observations: tc.observations},
+				Status: fleetv1beta1.WorkStatus{
+					ObservedResources: []fleetv1beta1.ObservedResource{{Version: "v1", Kind: "ConfigMap", Name: "stale"}},
+				},
+			}
+			r.observeResources(context.Background(), work)
+			if diff := cmp.Diff(tc.wantResources, work.Status.ObservedResources); diff != "" {
+				t.Errorf("observeResources() observed resources mismatch (-want, +got):\n%s", diff)
+			}
+			if work.Status.ObservedResourceCount != len(tc.wantResources) {
+				t.Errorf("observeResources() observed resource count = %d, want %d", work.Status.ObservedResourceCount, len(tc.wantResources))
+			}
+			gotCondition := meta.FindStatusCondition(work.Status.Conditions, fleetv1beta1.WorkConditionTypeObserved)
+			if tc.wantCondition == nil {
+				if gotCondition != nil {
+					t.Errorf("observeResources() observed condition = %+v, want nil", gotCondition)
+				}
+				return
+			}
+			if gotCondition == nil {
+				t.Fatalf("observeResources() observed condition = nil, want %+v", tc.wantCondition)
+			}
+			if gotCondition.Status != tc.wantCondition.Status || gotCondition.Reason != tc.wantCondition.Reason ||
+				gotCondition.ObservedGeneration != tc.wantCondition.ObservedGeneration ||
+				(tc.wantCondition.Message != "" && gotCondition.Message != tc.wantCondition.Message) {
+				t.Errorf("observeResources() observed condition = %+v, want %+v", gotCondition, tc.wantCondition)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// setOwnerPlacementAnnotation marks the manifest as owned by the placement. The manifests of the works which do not
+// belong to any placement are left unmarked.
+func setOwnerPlacementAnnotation(manifestObj *unstructured.Unstructured, placement string) {
+	if placement == "" {
+		return
+	}
+	annotations := manifestObj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[fleetv1beta1.OwnerPlacementAnnotation] = placement
+	manifestObj.SetAnnotations(annotations)
+}
+
+// validatePlacementOwnership checks if the resource on the member cluster is owned by another placement than the one
+// of the manifest, possibly from another hub, before the manifest is applied, so that the placements do not take the
+// resource from each other on every apply.
+// The resource is owned by the placement recorded in its owner placement annotation as long as any appliedWork other
+// than the one of the manifest still owns it; otherwise the annotation is stale and the resource is taken over.
+func validatePlacementOwnership(manifestObj, curObj *unstructured.Unstructured) (ApplyAction, error) {
+	placement := manifestObj.GetAnnotations()[fleetv1beta1.OwnerPlacementAnnotation]
+	ownerPlacement := curObj.GetAnnotations()[fleetv1beta1.OwnerPlacementAnnotation]
+	if placement == "" || ownerPlacement == "" || placement == ownerPlacement {
+		return "", nil
+	}
+
+	owners := manifestObj.GetOwnerReferences()
+	for _, ownerRef := range curObj.GetOwnerReferences() {
+		if ownerRef.APIVersion != fleetv1beta1.GroupVersion.String() || ownerRef.Kind != fleetv1beta1.AppliedWorkKind {
+			continue
+		}
+		if indexOwnerRef(owners, ownerRef) != -1 {
+			continue
+		}
+		err := fmt.Errorf("resource is owned by placement %s through appliedWork %s and cannot be applied by placement %s", ownerPlacement, ownerRef.Name, placement)
+		return ownershipConflictAction, controller.NewUserError(err)
+	}
+	return "", nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+func TestValidatePlacementOwnership(t *testing.T) {
+	appliedWorkOwner := func(name, uid string) metav1.OwnerReference {
+		return metav1.OwnerReference{
+			APIVersion: placementv1beta1.GroupVersion.String(),
+			Kind:       placementv1beta1.AppliedWorkKind,
+			Name:       name,
+			UID:        types.UID(uid),
+		}
+	}
+	newObj := func(placement string, owners ...metav1.OwnerReference) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("app")
+		obj.SetName("config")
+		setOwnerPlacementAnnotation(obj, placement)
+		obj.SetOwnerReferences(owners)
+		return obj
+	}
+	owner := appliedWorkOwner("crp-1-work", "uid-1")
+	tests := map[string]struct {
+		manifestObj *unstructured.Unstructured
+		curObj      *unstructured.Unstructured
+		want        ApplyAction
+		wantErr     error
+	}{
+		"the work does not belong to a placement": {
+			manifestObj: newObj("", owner),
+			curObj:      newObj("crp-2", appliedWorkOwner("crp-2-work", "uid-2")),
+		},
+		"the resource is not marked by any placement": {
+			manifestObj: newObj("crp-1", owner),
+			curObj:      newObj("", appliedWorkOwner("crp-2-work", "uid-2")),
+		},
+		"the resource is owned by the same placement": {
+			manifestObj: newObj("crp-1", owner),
+			curObj:      newObj("crp-1", owner, appliedWorkOwner("crp-1-configmap", "uid-3")),
+		},
+		"the resource is owned by another placement": {
+			manifestObj: newObj("crp-1", owner),
+			curObj:      newObj("crp-2", appliedWorkOwner("crp-2-work", "uid-2")),
+			want:        ownershipConflictAction,
+			wantErr:     controller.ErrUserError,
+		},
+		"the resource is owned by the work and another placement": {
+			manifestObj: newObj("crp-1", owner),
+			curObj:      newObj("crp-2", owner, appliedWorkOwner("crp-2-work", "uid-2")),
+			want:        ownershipConflictAction,
+			wantErr:     controller.ErrUserError,
+		},
+		"the other placement no longer owns the resource": {
+			manifestObj: newObj("crp-1", owner),
+			curObj:      newObj("crp-2", owner),
+		},
+		"the resource marked by another placement is owned by non-fleet appliers only": {
+			manifestObj: newObj("crp-1", owner),
+			curObj: newObj("crp-2", metav1.OwnerReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "app",
+				UID:        "uid-4",
+			}),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := validatePlacementOwnership(tc.manifestObj, tc.curObj)
+			if gotErr, wantErr := err != nil, tc.wantErr != nil; gotErr != wantErr || !errors.Is(err, tc.wantErr) {
+				t.Fatalf("validatePlacementOwnership() got error %v, want error %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("validatePlacementOwnership() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
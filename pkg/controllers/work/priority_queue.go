@@ -0,0 +1,217 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// priorityQueue is a work queue which hands out the items of higher priorities first, and the items of the same
+// priority in the order they are added. Like the default work queue, an item is queued at most once, and an item added
+// while it is being processed is queued again once it is done.
+type priorityQueue struct {
+	cond *sync.Cond
+	// priorityOf returns the priority of an item when it is added.
+	priorityOf func(item interface{}) int32
+
+	items priorityItems
+	// queued are the items waiting in the queue.
+	queued map[interface{}]*priorityItem
+	// dirty are the items to be processed with their priorities, including the ones added while being processed.
+	dirty map[interface{}]int32
+	// processing are the items being processed.
+	processing map[interface{}]struct{}
+	// seq is the sequence number of the last item pushed to the queue.
+	seq uint64
+
+	shuttingDown bool
+	drain        bool
+}
+
+// newPriorityQueue returns a queue which hands out the items by their priorities.
+func newPriorityQueue(priorityOf func(item interface{}) int32) *priorityQueue {
+	return &priorityQueue{
+		cond:       sync.NewCond(&sync.Mutex{}),
+		priorityOf: priorityOf,
+		queued:     make(map[interface{}]*priorityItem),
+		dirty:      make(map[interface{}]int32),
+		processing: make(map[interface{}]struct{}),
+	}
+}
+
+// Add queues the item; the priority of an item which is already queued is updated.
+func (q *priorityQueue) Add(item interface{}) {
+	priority := q.priorityOf(item)
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	q.dirty[item] = priority
+	if queued, ok := q.queued[item]; ok {
+		if queued.priority != priority {
+			queued.priority = priority
+			heap.Fix(&q.items, queued.index)
+		}
+		return
+	}
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+	q.push(item, priority)
+}
+
+// Len returns the number of the items waiting in the queue.
+func (q *priorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.items.Len()
+}
+
+// Get blocks until an item can be processed, and returns the queued item of the highest priority.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.items.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.items.Len() == 0 {
+		return nil, true
+	}
+	item := heap.Pop(&q.items).(*priorityItem).item
+	delete(q.queued, item)
+	delete(q.dirty, item)
+	q.processing[item] = struct{}{}
+	return item, false
+}
+
+// Done marks the item as processed, and queues it again if it was added while being processed.
+func (q *priorityQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, item)
+	if priority, ok := q.dirty[item]; ok {
+		q.push(item, priority)
+		return
+	}
+	if len(q.processing) == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+// ShutDown makes the queue ignore the new items and the waiting Get calls return.
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain shuts down the queue and waits for the items being processed to be done.
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+// ShuttingDown tells if the queue is shut down.
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *priorityQueue) push(item interface{}, priority int32) {
+	q.seq++
+	queued := &priorityItem{item: item, priority: priority, seq: q.seq}
+	heap.Push(&q.items, queued)
+	q.queued[item] = queued
+	q.cond.Broadcast()
+}
+
+// priorityItem is an item waiting in the priority queue.
+type priorityItem struct {
+	item     interface{}
+	priority int32
+	seq      uint64
+	index    int
+}
+
+// priorityItems is a heap of the items whose top is the earliest item of the highest priority.
+type priorityItems []*priorityItem
+
+func (p priorityItems) Len() int { return len(p) }
+
+func (p priorityItems) Less(i, j int) bool {
+	if p[i].priority != p[j].priority {
+		return p[i].priority > p[j].priority
+	}
+	return p[i].seq < p[j].seq
+}
+
+func (p priorityItems) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+	p[i].index = i
+	p[j].index = j
+}
+
+func (p *priorityItems) Push(x interface{}) {
+	item := x.(*priorityItem)
+	item.index = len(*p)
+	*p = append(*p, item)
+}
+
+func (p *priorityItems) Pop() interface{} {
+	old := *p
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*p = old[:n-1]
+	return item
+}
+
+// newWorkQueue returns the queue of the reconciler which hands out the works of higher priorities first, so that the
+// critical placements are applied ahead of the bulk ones when many works are waiting, e.g. after the agent restarts.
+func (r *ApplyWorkReconciler) newWorkQueue(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+	delayingQueue := workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{
+		Name:  controllerName,
+		Queue: newPriorityQueue(r.workPriority),
+	})
+	return workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{
+		Name:          controllerName,
+		DelayingQueue: delayingQueue,
+	})
+}
+
+// workPriority returns the priority of the work of the request from the cache; the works which are not found, e.g.
+// deleted, have the default priority.
+func (r *ApplyWorkReconciler) workPriority(item interface{}) int32 {
+	req, ok := item.(reconcile.Request)
+	if !ok {
+		return 0
+	}
+	work := &fleetv1beta1.Work{}
+	if err := r.client.Get(context.Background(), req.NamespacedName, work); err != nil {
+		klog.V(4).InfoS("Failed to get the priority of the work", "work", req.NamespacedName, "error", err)
+		return 0
+	}
+	return work.Spec.Priority
+}
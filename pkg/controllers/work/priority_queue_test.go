@@ -0,0 +1,101 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	priorities := map[string]int32{"bulk-1": 0, "bulk-2": 0, "patch": 100, "config": 10}
+	tests := map[string]struct {
+		// run adds the items to the queue.
+		run  func(q *priorityQueue)
+		want []string
+	}{
+		"items of higher priorities first": {
+			run: func(q *priorityQueue) {
+				for _, item := range []string{"bulk-1", "config", "bulk-2", "patch"} {
+					q.Add(item)
+				}
+			},
+			want: []string{"patch", "config", "bulk-1", "bulk-2"},
+		},
+		"queued item is not duplicated": {
+			run: func(q *priorityQueue) {
+				for _, item := range []string{"bulk-1", "bulk-2", "bulk-1"} {
+					q.Add(item)
+				}
+			},
+			want: []string{"bulk-1", "bulk-2"},
+		},
+		"priority of a queued item is updated": {
+			run: func(q *priorityQueue) {
+				q.Add("bulk-1")
+				q.Add("bulk-2")
+				priorities["bulk-2"] = 50
+				q.Add("bulk-2")
+				priorities["bulk-2"] = 0
+			},
+			want: []string{"bulk-2", "bulk-1"},
+		},
+		"item added while being processed is queued again once done": {
+			run: func(q *priorityQueue) {
+				q.Add("patch")
+				item, _ := q.Get()
+				q.Add("patch")
+				q.Add("bulk-1")
+				if got := q.Len(); got != 1 {
+					t.Errorf("Len() = %d, want 1", got)
+				}
+				q.Done(item)
+			},
+			want: []string{"patch", "bulk-1"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := newPriorityQueue(func(item interface{}) int32 {
+				return priorities[item.(string)]
+			})
+			tt.run(q)
+			var got []string
+			for q.Len() > 0 {
+				item, shutdown := q.Get()
+				if shutdown {
+					t.Fatalf("Get() shutdown = true, want false")
+				}
+				got = append(got, item.(string))
+				q.Done(item)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("priorityQueue order mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPriorityQueueShutDown(t *testing.T) {
+	q := newPriorityQueue(func(interface{}) int32 { return 0 })
+	q.Add("work")
+	item, _ := q.Get()
+	done := make(chan struct{})
+	go func() {
+		q.ShutDownWithDrain()
+		close(done)
+	}()
+	q.Done(item)
+	<-done
+	q.Add("ignored")
+	if !q.ShuttingDown() {
+		t.Errorf("ShuttingDown() = false, want true")
+	}
+	if _, shutdown := q.Get(); !shutdown {
+		t.Errorf("Get() shutdown = false, want true")
+	}
+}
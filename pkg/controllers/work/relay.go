@@ -0,0 +1,199 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// RelayedPlacementAvailableReason is the reason string of the work available condition when the placement which
+	// relays the work to the member fleet, i.e. the member clusters of the member cluster which is itself a hub, is
+	// available.
+	RelayedPlacementAvailableReason = "RelayedPlacementAvailable"
+	// RelayedPlacementNotAvailableReason is the reason string of the work available condition when the placement which
+	// relays the work to the member fleet is not available yet, or the work cannot be relayed.
+	RelayedPlacementNotAvailableReason = "RelayedPlacementNotAvailable"
+)
+
+// WithPlacementRelay makes the reconciler relay the works to the member fleet when the member cluster is itself a
+// fleet hub: the resources applied to the member cluster are placed on all of its own member clusters by a cluster
+// resource placement named after the placement on the upper hub, and the works are only available once that placement
+// is available.
+func (r *ApplyWorkReconciler) WithPlacementRelay() *ApplyWorkReconciler {
+	r.relayPlacements = true
+	return r
+}
+
+// relayWork makes sure that the placement which relays the works of the placement of the work exists on the member
+// cluster, and reports the availability of the relayed placement on the work once the manifests are available on the
+// member cluster itself.
+func (r *ApplyWorkReconciler) relayWork(ctx context.Context, work *fleetv1beta1.Work) error {
+	crpName := work.GetLabels()[fleetv1beta1.CRPTrackingLabel]
+	if crpName == "" {
+		return nil
+	}
+	crp, err := r.syncRelayedPlacement(ctx, crpName)
+	if err != nil || crp == nil {
+		return err
+	}
+	availableCond := meta.FindStatusCondition(work.Status.Conditions, fleetv1beta1.WorkConditionTypeAvailable)
+	if !condition.IsConditionStatusTrue(availableCond, work.Generation) {
+		return nil
+	}
+	meta.SetStatusCondition(&work.Status.Conditions, buildRelayedAvailableCondition(crp, r.workNameSpace, work.Generation))
+	return nil
+}
+
+// syncRelayedPlacement creates or updates the placement on the member cluster which relays the works of the placement
+// on the upper hub that are not being deleted, or deletes it once there are no such works left.
+// It returns the relayed placement, which is nil if it's deleted; a placement of the same name which is not created by
+// the member agent is returned as is.
+func (r *ApplyWorkReconciler) syncRelayedPlacement(ctx context.Context, crpName string) (*fleetv1beta1.ClusterResourcePlacement, error) {
+	var workList fleetv1beta1.WorkList
+	if err := r.client.List(ctx, &workList, client.InNamespace(r.workNameSpace), client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crpName}); err != nil {
+		klog.ErrorS(err, "Failed to list the works of the placement", "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	selectors, err := relayedResourceSelectors(workList.Items)
+	if err != nil {
+		return nil, controller.NewUnexpectedBehaviorError(err)
+	}
+
+	crp := &fleetv1beta1.ClusterResourcePlacement{}
+	err = r.spokeClient.Get(ctx, types.NamespacedName{Name: crpName}, crp)
+	switch {
+	case apierrors.IsNotFound(err):
+		if len(selectors) == 0 {
+			return nil, nil
+		}
+		crp = &fleetv1beta1.ClusterResourcePlacement{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   crpName,
+				Labels: map[string]string{fleetv1beta1.RelayedFromLabel: r.workNameSpace},
+			},
+			// a nil policy places the resources on all the member clusters of the member fleet
+			Spec: fleetv1beta1.ClusterResourcePlacementSpec{ResourceSelectors: selectors},
+		}
+		if err := r.spokeClient.Create(ctx, crp); err != nil {
+			klog.ErrorS(err, "Failed to create the relayed placement", "clusterResourcePlacement", crpName)
+			return nil, controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Created the placement relaying the works to the member fleet", "clusterResourcePlacement", crpName)
+		return crp, nil
+	case err != nil:
+		klog.ErrorS(err, "Failed to get the relayed placement", "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+
+	if crp.GetLabels()[fleetv1beta1.RelayedFromLabel] != r.workNameSpace {
+		klog.V(2).InfoS("The placement of the same name on the member cluster is not created to relay the works", "clusterResourcePlacement", crpName)
+		return crp, nil
+	}
+	if len(selectors) == 0 {
+		if err := r.spokeClient.Delete(ctx, crp); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the relayed placement", "clusterResourcePlacement", crpName)
+			return nil, controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Deleted the placement relaying the works to the member fleet", "clusterResourcePlacement", crpName)
+		return nil, nil
+	}
+	if equality.Semantic.DeepEqual(crp.Spec.ResourceSelectors, selectors) {
+		return crp, nil
+	}
+	// only the resource selectors are owned by the member agent; the administrators of the member fleet may change
+	// the rest of the spec, e.g. to place the resources on a subset of the member clusters
+	crp.Spec.ResourceSelectors = selectors
+	if err := r.spokeClient.Update(ctx, crp); err != nil {
+		klog.ErrorS(err, "Failed to update the relayed placement", "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(false, err)
+	}
+	klog.V(2).InfoS("Updated the resource selectors of the placement relaying the works to the member fleet", "clusterResourcePlacement", crpName)
+	return crp, nil
+}
+
+// relayedResourceSelectors returns the resource selectors which select the manifests of the works that are not being
+// deleted on the member cluster: a namespaced manifest is selected along with its namespace, and a cluster scoped one
+// by its name.
+func relayedResourceSelectors(works []fleetv1beta1.Work) ([]fleetv1beta1.ClusterResourceSelector, error) {
+	selected := make(map[fleetv1beta1.ClusterResourceSelector]bool)
+	for i := range works {
+		if !works[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, manifest := range works[i].Spec.Workload.Manifests {
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+				return nil, fmt.Errorf("failed to decode a manifest of work %s: %w", works[i].Name, err)
+			}
+			gvk := obj.GroupVersionKind()
+			switch {
+			case obj.GetNamespace() != "":
+				selected[fleetv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: obj.GetNamespace()}] = true
+			case isNamespace(obj):
+				selected[fleetv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: obj.GetName()}] = true
+			default:
+				selected[fleetv1beta1.ClusterResourceSelector{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Name: obj.GetName()}] = true
+			}
+		}
+	}
+	selectors := make([]fleetv1beta1.ClusterResourceSelector, 0, len(selected))
+	for selector := range selected {
+		selectors = append(selectors, selector)
+	}
+	sort.Slice(selectors, func(i, j int) bool {
+		if selectors[i].Group != selectors[j].Group {
+			return selectors[i].Group < selectors[j].Group
+		}
+		if selectors[i].Kind != selectors[j].Kind {
+			return selectors[i].Kind < selectors[j].Kind
+		}
+		return selectors[i].Name < selectors[j].Name
+	})
+	return selectors, nil
+}
+
+// buildRelayedAvailableCondition builds the work available condition from the availability of the placement which
+// relays the work to the member fleet.
+func buildRelayedAvailableCondition(crp *fleetv1beta1.ClusterResourcePlacement, relayedFrom string, observedGeneration int64) metav1.Condition {
+	availableCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             RelayedPlacementNotAvailableReason,
+		ObservedGeneration: observedGeneration,
+	}
+	if crp.GetLabels()[fleetv1beta1.RelayedFromLabel] != relayedFrom {
+		availableCondition.Message = fmt.Sprintf("The placement %s on the member fleet is not created to relay the work", crp.Name)
+		return availableCondition
+	}
+	crpCond := meta.FindStatusCondition(crp.Status.Conditions, string(fleetv1beta1.ClusterResourcePlacementAvailableConditionType))
+	switch {
+	case condition.IsConditionStatusTrue(crpCond, crp.Generation):
+		availableCondition.Status = metav1.ConditionTrue
+		availableCondition.Reason = RelayedPlacementAvailableReason
+		availableCondition.Message = fmt.Sprintf("The work is available on all the %d clusters of the member fleet", len(crp.Status.PlacementStatuses))
+	case crpCond == nil || crpCond.ObservedGeneration != crp.Generation:
+		availableCondition.Message = fmt.Sprintf("The placement %s relaying the work to the member fleet has not reported its availability yet", crp.Name)
+	default:
+		availableCondition.Message = fmt.Sprintf("The placement %s relaying the work to the member fleet is not available: %s", crp.Name, crpCond.Message)
+	}
+	return availableCondition
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	relayWorkNamespace = "fleet-member-hub-1"
+	relayCRPName       = "test-crp"
+)
+
+func newRelayWork(name string, manifests ...string) *placementv1beta1.Work {
+	work := &placementv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: relayWorkNamespace,
+			Labels:    map[string]string{placementv1beta1.CRPTrackingLabel: relayCRPName},
+		},
+	}
+	for _, manifest := range manifests {
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, placementv1beta1.Manifest{
+			RawExtension: runtime.RawExtension{Raw: []byte(manifest)},
+		})
+	}
+	return work
+}
+
+func namespaceSelector(name string) placementv1beta1.ClusterResourceSelector {
+	return placementv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: name}
+}
+
+func TestRelayedResourceSelectors(t *testing.T) {
+	deletingWork := newRelayWork("deleting", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"old"}}`)
+	deletingWork.DeletionTimestamp = ptr.To(metav1.Now())
+	works := []placementv1beta1.Work{
+		*newRelayWork("work-1",
+			`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`,
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"reader"}}`),
+		*newRelayWork("work-2",
+			`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"web"}}`),
+		*deletingWork,
+	}
+	got, err := relayedResourceSelectors(works)
+	if err != nil {
+		t.Fatalf("relayedResourceSelectors() = %v, want nil", err)
+	}
+	want := []placementv1beta1.ClusterResourceSelector{
+		namespaceSelector("app"),
+		namespaceSelector("web"),
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "reader"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("relayedResourceSelectors() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSyncRelayedPlacement(t *testing.T) {
+	relayedCRP := func(labels map[string]string, selectors ...placementv1beta1.ClusterResourceSelector) *placementv1beta1.ClusterResourcePlacement {
+		return &placementv1beta1.ClusterResourcePlacement{
+			ObjectMeta: metav1.ObjectMeta{Name: relayCRPName, Labels: labels},
+			Spec: placementv1beta1.ClusterResourcePlacementSpec{
+				ResourceSelectors: selectors,
+				Policy:            &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickNPlacementType, NumberOfClusters: ptr.To(int32(1))},
+			},
+		}
+	}
+	relayed := map[string]string{placementv1beta1.RelayedFromLabel: relayWorkNamespace}
+	deletingWork := newRelayWork("work-1", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)
+	deletingWork.DeletionTimestamp = ptr.To(metav1.Now())
+	deletingWork.Finalizers = []string{placementv1beta1.WorkFinalizer}
+	tests := map[string]struct {
+		works []client.Object
+		crp   *placementv1beta1.ClusterResourcePlacement
+		// wantCRP is the placement on the member cluster after the sync, or nil if there is none.
+		wantCRP *placementv1beta1.ClusterResourcePlacement
+	}{
+		"create the relayed placement": {
+			works: []client.Object{newRelayWork("work-1", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)},
+			wantCRP: &placementv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{Name: relayCRPName, Labels: relayed},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{namespaceSelector("app")},
+				},
+			},
+		},
+		"update the resource selectors only": {
+			works: []client.Object{
+				newRelayWork("work-1", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`),
+				newRelayWork("work-2", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"web"}}`),
+			},
+			crp:     relayedCRP(relayed, namespaceSelector("app")),
+			wantCRP: relayedCRP(relayed, namespaceSelector("app"), namespaceSelector("web")),
+		},
+		"leave the placement not created by the member agent alone": {
+			works:   []client.Object{newRelayWork("work-1", `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"web"}}`)},
+			crp:     relayedCRP(nil, namespaceSelector("app")),
+			wantCRP: relayedCRP(nil, namespaceSelector("app")),
+		},
+		"delete the relayed placement with the last work": {
+			works: []client.Object{deletingWork},
+			crp:   relayedCRP(relayed, namespaceSelector("app")),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := placementv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			spokeClientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.crp != nil {
+				spokeClientBuilder = spokeClientBuilder.WithObjects(tc.crp)
+			}
+			r := &ApplyWorkReconciler{
+				client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.works...).Build(),
+				spokeClient:   spokeClientBuilder.Build(),
+				workNameSpace: relayWorkNamespace,
+			}
+			if _, err := r.syncRelayedPlacement(context.Background(), relayCRPName); err != nil {
+				t.Fatalf("syncRelayedPlacement() = %v, want nil", err)
+			}
+			gotCRP := &placementv1beta1.ClusterResourcePlacement{}
+			err := r.spokeClient.Get(context.Background(), types.NamespacedName{Name: relayCRPName}, gotCRP)
+			if tc.wantCRP == nil {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("Get() = %v, want not found", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.wantCRP, gotCRP, cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion"), cmpopts.IgnoreTypes(metav1.TypeMeta{})); diff != "" {
+				t.Errorf("syncRelayedPlacement() placement mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildRelayedAvailableCondition(t *testing.T) {
+	newCRP := func(labels map[string]string, conditions ...metav1.Condition) *placementv1beta1.ClusterResourcePlacement {
+		return &placementv1beta1.ClusterResourcePlacement{
+			ObjectMeta: metav1.ObjectMeta{Name: relayCRPName, Labels: labels, Generation: 2},
+			Status: placementv1beta1.ClusterResourcePlacementStatus{
+				Conditions:        conditions,
+				PlacementStatuses: make([]placementv1beta1.ResourcePlacementStatus, 3),
+			},
+		}
+	}
+	relayed := map[string]string{placementv1beta1.RelayedFromLabel: relayWorkNamespace}
+	availableCond := func(status metav1.ConditionStatus, generation int64) metav1.Condition {
+		return metav1.Condition{
+			Type:               string(placementv1beta1.ClusterResourcePlacementAvailableConditionType),
+			Status:             status,
+			ObservedGeneration: generation,
+			Message:            "1 cluster is not available",
+		}
+	}
+	tests := map[string]struct {
+		crp        *placementv1beta1.ClusterResourcePlacement
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		"the relayed placement is available": {
+			crp:        newCRP(relayed, availableCond(metav1.ConditionTrue, 2)),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: RelayedPlacementAvailableReason,
+		},
+		"the relayed placement is not available": {
+			crp:        newCRP(relayed, availableCond(metav1.ConditionFalse, 2)),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: RelayedPlacementNotAvailableReason,
+		},
+		"the availability of the relayed placement is stale": {
+			crp:        newCRP(relayed, availableCond(metav1.ConditionTrue, 1)),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: RelayedPlacementNotAvailableReason,
+		},
+		"the placement is not created by the member agent": {
+			crp:        newCRP(nil, availableCond(metav1.ConditionTrue, 2)),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: RelayedPlacementNotAvailableReason,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := buildRelayedAvailableCondition(tc.crp, relayWorkNamespace, 5)
+			if got.Type != placementv1beta1.WorkConditionTypeAvailable || got.ObservedGeneration != 5 {
+				t.Errorf("buildRelayedAvailableCondition() = %+v, want the available condition of generation 5", got)
+			}
+			if got.Status != tc.wantStatus || got.Reason != tc.wantReason {
+				t.Errorf("buildRelayedAvailableCondition() = %s/%s, want %s/%s", got.Status, got.Reason, tc.wantStatus, tc.wantReason)
+			}
+		})
+	}
+}
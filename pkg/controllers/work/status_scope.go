@@ -0,0 +1,72 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// maxDriftFields is the max number of the changed fields reported in the drift of a manifest.
+const maxDriftFields = 20
+
+// statusReportingScope returns the status reporting scope of the apply strategy of a work.
+func statusReportingScope(applyStrategy *fleetv1beta1.ApplyStrategy) fleetv1beta1.StatusReportingScope {
+	if applyStrategy == nil || applyStrategy.StatusReportingScope == "" {
+		return fleetv1beta1.StatusReportingScopeWorkloadSummaries
+	}
+	return applyStrategy.StatusReportingScope
+}
+
+// buildManifestDrift returns the drift of the manifest given its apply result. A manifest is drifted if the member
+// agent updates it although its work was already applied for the current generation, i.e. it was changed on the member
+// cluster out of band; otherwise, the last drift of the manifest is kept.
+func buildManifestDrift(result *applyResult, existing *fleetv1beta1.ManifestCondition, workApplied bool, now time.Time) *fleetv1beta1.ManifestDrift {
+	if workApplied && result.applyErr == nil && result.audit != nil &&
+		result.audit.operation == fleetv1beta1.AuditOperationUpdate && len(result.audit.changedFields) > 0 {
+		fields := result.audit.changedFields
+		if len(fields) > maxDriftFields {
+			fields = fields[:maxDriftFields]
+		}
+		return &fleetv1beta1.ManifestDrift{
+			ObservedTime: metav1.NewTime(now),
+			Fields:       append([]string(nil), fields...),
+		}
+	}
+	if existing != nil {
+		return existing.Drift
+	}
+	return nil
+}
+
+// isWorkApplied tells if the work was applied for its current generation before it is applied again.
+func isWorkApplied(work *fleetv1beta1.Work) bool {
+	return condition.IsConditionStatusTrue(meta.FindStatusCondition(work.Status.Conditions, fleetv1beta1.WorkConditionTypeApplied), work.Generation)
+}
+
+// scopeWorkStatus leaves the status out of the work status which its status reporting scope does not include. The
+// manifest conditions of the resources which are applied and available are left out with the ConditionsOnly scope,
+// together with the summaries of the workloads.
+func scopeWorkStatus(status *fleetv1beta1.WorkStatus, applyStrategy *fleetv1beta1.ApplyStrategy) {
+	if statusReportingScope(applyStrategy) != fleetv1beta1.StatusReportingScopeConditionsOnly {
+		return
+	}
+	kept := make([]fleetv1beta1.ManifestCondition, 0, len(status.ManifestConditions))
+	for _, manifestCondition := range status.ManifestConditions {
+		if meta.IsStatusConditionTrue(manifestCondition.Conditions, fleetv1beta1.WorkConditionTypeApplied) &&
+			meta.IsStatusConditionTrue(manifestCondition.Conditions, fleetv1beta1.WorkConditionTypeAvailable) {
+			continue
+		}
+		manifestCondition.LoadBalancer = nil
+		kept = append(kept, manifestCondition)
+	}
+	status.ManifestConditions = kept
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestBuildManifestDrift(t *testing.T) {
+	now := time.Now()
+	lastDrift := &fleetv1beta1.ManifestDrift{ObservedTime: metav1.NewTime(now.Add(-time.Hour)), Fields: []string{"data"}}
+	existing := &fleetv1beta1.ManifestCondition{Drift: lastDrift}
+	updated := &auditEntry{operation: fleetv1beta1.AuditOperationUpdate, changedFields: []string{"spec.replicas"}}
+	manyFields := make([]string, maxDriftFields+5)
+	for i := range manyFields {
+		manyFields[i] = string(rune('a' + i))
+	}
+	tests := map[string]struct {
+		result      applyResult
+		existing    *fleetv1beta1.ManifestCondition
+		workApplied bool
+		want        *fleetv1beta1.ManifestDrift
+	}{
+		"resource is changed out of band": {
+			result:      applyResult{audit: updated},
+			existing:    existing,
+			workApplied: true,
+			want:        &fleetv1beta1.ManifestDrift{ObservedTime: metav1.NewTime(now), Fields: []string{"spec.replicas"}},
+		},
+		"resource is updated for a new generation of the work": {
+			result:   applyResult{audit: updated},
+			existing: existing,
+			want:     lastDrift,
+		},
+		"resource is not changed": {
+			result:      applyResult{},
+			existing:    existing,
+			workApplied: true,
+			want:        lastDrift,
+		},
+		"resource is created": {
+			result:      applyResult{audit: &auditEntry{operation: fleetv1beta1.AuditOperationCreate}},
+			workApplied: true,
+		},
+		"too many fields are changed": {
+			result:      applyResult{audit: &auditEntry{operation: fleetv1beta1.AuditOperationUpdate, changedFields: manyFields}},
+			workApplied: true,
+			want:        &fleetv1beta1.ManifestDrift{ObservedTime: metav1.NewTime(now), Fields: manyFields[:maxDriftFields]},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := buildManifestDrift(&tt.result, tt.existing, tt.workApplied, now)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("buildManifestDrift() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScopeWorkStatus(t *testing.T) {
+	loadBalancer := &fleetv1beta1.ServiceLoadBalancerStatus{Ingress: []fleetv1beta1.LoadBalancerIngress{{IP: "10.0.0.1"}}}
+	healthy := fleetv1beta1.ManifestCondition{
+		Identifier: fleetv1beta1.WorkResourceIdentifier{Ordinal: 0, Kind: "Service", Name: "healthy"},
+		Conditions: []metav1.Condition{
+			{Type: fleetv1beta1.WorkConditionTypeApplied, Status: metav1.ConditionTrue},
+			{Type: fleetv1beta1.WorkConditionTypeAvailable, Status: metav1.ConditionTrue},
+		},
+		LoadBalancer: loadBalancer,
+	}
+	unavailable := fleetv1beta1.ManifestCondition{
+		Identifier: fleetv1beta1.WorkResourceIdentifier{Ordinal: 1, Kind: "Service", Name: "unavailable"},
+		Conditions: []metav1.Condition{
+			{Type: fleetv1beta1.WorkConditionTypeApplied, Status: metav1.ConditionTrue},
+			{Type: fleetv1beta1.WorkConditionTypeAvailable, Status: metav1.ConditionFalse},
+		},
+		LoadBalancer: loadBalancer,
+	}
+	failed := fleetv1beta1.ManifestCondition{
+		Identifier: fleetv1beta1.WorkResourceIdentifier{Ordinal: 2, Kind: "ConfigMap", Name: "failed"},
+		Conditions: []metav1.Condition{{Type: fleetv1beta1.WorkConditionTypeApplied, Status: metav1.ConditionFalse}},
+	}
+	unavailableWithoutLoadBalancer := *unavailable.DeepCopy()
+	unavailableWithoutLoadBalancer.LoadBalancer = nil
+	tests := map[string]struct {
+		applyStrategy *fleetv1beta1.ApplyStrategy
+		want          []fleetv1beta1.ManifestCondition
+	}{
+		"default scope": {
+			want: []fleetv1beta1.ManifestCondition{healthy, unavailable, failed},
+		},
+		"drift details": {
+			applyStrategy: &fleetv1beta1.ApplyStrategy{StatusReportingScope: fleetv1beta1.StatusReportingScopeDriftDetails},
+			want:          []fleetv1beta1.ManifestCondition{healthy, unavailable, failed},
+		},
+		"conditions only": {
+			applyStrategy: &fleetv1beta1.ApplyStrategy{StatusReportingScope: fleetv1beta1.StatusReportingScopeConditionsOnly},
+			want:          []fleetv1beta1.ManifestCondition{unavailableWithoutLoadBalancer, failed},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			status := &fleetv1beta1.WorkStatus{
+				ManifestConditions: []fleetv1beta1.ManifestCondition{*healthy.DeepCopy(), *unavailable.DeepCopy(), *failed.DeepCopy()},
+			}
+			scopeWorkStatus(status, tt.applyStrategy)
+			if diff := cmp.Diff(tt.want, status.ManifestConditions); diff != "" {
+				t.Errorf("scopeWorkStatus() manifest conditions mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// skippedManifestRequeueInterval is the interval at which a work with skipped manifests checks again whether they
+// can be applied, e.g. once the storage classes of the skipped persistentVolumeClaims are created.
+const skippedManifestRequeueInterval = time.Minute
+
+// findMissingStorageClass returns the name of the storage class which the persistentVolumeClaim manifest refers to
+// if the apply strategy skips the claims whose storage class does not exist and the class is not found on the member
+// cluster; it returns an empty string if the manifest should be applied.
+// The claims which do not name a storage class, i.e. use the default one, or opt out of the storage classes with an
+// empty name are never skipped.
+func (r *ApplyWorkReconciler) findMissingStorageClass(ctx context.Context, applyStrategy *fleetv1beta1.ApplyStrategy,
+	gvr schema.GroupVersionResource, manifestObj *unstructured.Unstructured) (string, error) {
+	if applyStrategy == nil || !applyStrategy.SkipMissingStorageClass || gvr != utils.PersistentVolumeClaimGVR {
+		return "", nil
+	}
+	storageClassName, found, err := unstructured.NestedString(manifestObj.Object, "spec", "storageClassName")
+	if err != nil {
+		return "", controller.NewUserError(err)
+	}
+	if !found || storageClassName == "" {
+		return "", nil
+	}
+	if _, err := r.spokeDynamicClient.Resource(utils.StorageClassGVR).Get(ctx, storageClassName, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return storageClassName, nil
+		}
+		klog.ErrorS(err, "Failed to get the storage class of the persistentVolumeClaim", "persistentVolumeClaim", klog.KObj(manifestObj), "storageClass", storageClassName)
+		return "", controller.NewAPIServerError(false, err)
+	}
+	return "", nil
+}
+
+// hasSkippedManifests tells if any manifest is skipped.
+func hasSkippedManifests(results []applyResult) bool {
+	for i := range results {
+		if results[i].action == manifestSkippedAction {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package work
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func TestFindMissingStorageClass(t *testing.T) {
+	pvc := func(storageClassName *string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "PersistentVolumeClaim",
+				"metadata": map[string]interface{}{
+					"name":      "data",
+					"namespace": "app",
+				},
+				"spec": map[string]interface{}{},
+			},
+		}
+		if storageClassName != nil {
+			obj.Object["spec"].(map[string]interface{})["storageClassName"] = *storageClassName
+		}
+		return obj
+	}
+	name := func(s string) *string { return &s }
+	storageClass := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion":  "storage.k8s.io/v1",
+			"kind":        "StorageClass",
+			"provisioner": "disk.csi.azure.com",
+			"metadata": map[string]interface{}{
+				"name": "managed-csi",
+			},
+		},
+	}
+	skip := &fleetv1beta1.ApplyStrategy{SkipMissingStorageClass: true}
+	tests := map[string]struct {
+		applyStrategy *fleetv1beta1.ApplyStrategy
+		gvr           schema.GroupVersionResource
+		obj           *unstructured.Unstructured
+		want          string
+	}{
+		"the apply strategy does not skip the claims": {
+			applyStrategy: &fleetv1beta1.ApplyStrategy{},
+			gvr:           utils.PersistentVolumeClaimGVR,
+			obj:           pvc(name("premium")),
+		},
+		"not a claim": {
+			applyStrategy: skip,
+			gvr:           utils.ConfigMapGVR,
+			obj:           &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}},
+		},
+		"the claim uses the default storage class": {
+			applyStrategy: skip,
+			gvr:           utils.PersistentVolumeClaimGVR,
+			obj:           pvc(nil),
+		},
+		"the claim opts out of the storage classes": {
+			applyStrategy: skip,
+			gvr:           utils.PersistentVolumeClaimGVR,
+			obj:           pvc(name("")),
+		},
+		"the storage class exists": {
+			applyStrategy: skip,
+			gvr:           utils.PersistentVolumeClaimGVR,
+			obj:           pvc(name("managed-csi")),
+		},
+		"the storage class does not exist": {
+			applyStrategy: skip,
+			gvr:           utils.PersistentVolumeClaimGVR,
+			obj:           pvc(name("premium")),
+			want:          "premium",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &ApplyWorkReconciler{
+				spokeDynamicClient: fake.NewSimpleDynamicClient(runtime.NewScheme(), storageClass),
+			}
+			got, err := r.findMissingStorageClass(context.Background(), tc.applyStrategy, tc.gvr, tc.obj)
+			if err != nil {
+				t.Fatalf("findMissingStorageClass() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("findMissingStorageClass() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildManifestConditionSkipped(t *testing.T) {
+	conditions := buildManifestCondition(nil, manifestSkippedAction, 1)
+	for _, cond := range conditions {
+		if cond.Status != metav1.ConditionTrue || cond.Reason != ManifestSkippedReason {
+			t.Errorf("buildManifestCondition() %s condition = (%s, %s), want (True, %s)", cond.Type, cond.Status, cond.Reason, ManifestSkippedReason)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// placementActivation tells if a placement with the activation window is active at the time, and when it becomes
+// active or inactive next; the zero time means that it never changes again.
+func placementActivation(window *fleetv1beta1.ActivationWindow, now time.Time) (bool, time.Time) {
+	if window == nil {
+		return true, time.Time{}
+	}
+	var deactivateAt time.Time
+	if window.DeactivateAt != nil {
+		deactivateAt = window.DeactivateAt.Time
+		if !now.Before(deactivateAt) {
+			return false, time.Time{}
+		}
+	}
+	if window.ActivateAt != nil && now.Before(window.ActivateAt.Time) {
+		return false, window.ActivateAt.Time
+	}
+	active, next := true, time.Time{}
+	if window.Daily != nil {
+		active, next = dailyActivation(window.Daily, now)
+	}
+	if !deactivateAt.IsZero() && (next.IsZero() || deactivateAt.Before(next)) {
+		if !active {
+			// the placement is not active again before it is deactivated for good
+			return false, time.Time{}
+		}
+		next = deactivateAt
+	}
+	return active, next
+}
+
+// dailyActivation tells if the time is in the daily window, and when the window starts or ends next.
+func dailyActivation(window *fleetv1beta1.DailyWindow, now time.Time) (bool, time.Time) {
+	start, startErr := time.Parse("15:04", window.Start)
+	end, endErr := time.Parse("15:04", window.End)
+	if startErr != nil || endErr != nil || start.Equal(end) {
+		// the window lasts the whole day; the malformed windows are rejected by the API server
+		return true, time.Time{}
+	}
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	startAt := midnight.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+	endAt := midnight.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute)
+	if endAt.Before(startAt) {
+		// the window spans midnight
+		if now.Before(endAt) {
+			return true, endAt
+		}
+		if now.Before(startAt) {
+			return false, startAt
+		}
+		return true, endAt.AddDate(0, 0, 1)
+	}
+	if now.Before(startAt) {
+		return false, startAt
+	}
+	if now.Before(endAt) {
+		return true, endAt
+	}
+	return false, startAt.AddDate(0, 0, 1)
+}
+
+// getActivationWindow returns the activation window of the placement of the binding.
+func (r *Reconciler) getActivationWindow(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (*fleetv1beta1.ActivationWindow, error) {
+	crpName := resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel]
+	if crpName == "" {
+		return nil, nil
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: crpName}, crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		klog.ErrorS(err, "Failed to get the clusterResourcePlacement of the binding", "resourceBinding", klog.KObj(resourceBinding))
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	return crp.Spec.ActivationWindow, nil
+}
+
+// handleInactivePlacement removes the works of the binding whose placement is inactive, leaving their resources on the
+// target cluster if the inactive action of the window is Orphan, and checks the binding again when the placement
+// becomes active.
+func (r *Reconciler) handleInactivePlacement(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding,
+	window *fleetv1beta1.ActivationWindow, activateAt time.Time) (controllerruntime.Result, error) {
+	bindingRef := klog.KObj(resourceBinding)
+	klog.V(2).InfoS("Removing the works as the placement is inactive", "resourceBinding", bindingRef, "activateAt", activateAt)
+	works, deletingWorks, err := r.listAllWorksAssociated(ctx, resourceBinding)
+	if err != nil {
+		return controllerruntime.Result{}, err
+	}
+	orphan := window.InactiveAction == fleetv1beta1.InactiveActionOrphan
+	for _, work := range works {
+		if orphan && work.Annotations[fleetv1beta1.OrphanResourcesAnnotation] != "true" {
+			if work.Annotations == nil {
+				work.Annotations = make(map[string]string)
+			}
+			work.Annotations[fleetv1beta1.OrphanResourcesAnnotation] = "true"
+			if err := r.Client.Update(ctx, work); err != nil {
+				klog.ErrorS(err, "Failed to mark the work to leave its resources behind", "resourceBinding", bindingRef, "work", klog.KObj(work))
+				return controllerruntime.Result{}, controller.NewUpdateIgnoreConflictError(err)
+			}
+		}
+		if err := r.Client.Delete(ctx, work); err != nil && !apierrors.IsNotFound(err) {
+			return controllerruntime.Result{}, controller.NewAPIServerError(false, err)
+		}
+		deletingWorks++
+	}
+
+	originalBinding := resourceBinding.DeepCopy()
+	setInactiveConditions(resourceBinding, activateAt)
+	setResourcesDeletedCondition(resourceBinding, deletingWorks)
+	if err := r.updateBindingStatus(ctx, originalBinding, resourceBinding); err != nil {
+		klog.ErrorS(err, "Failed to update the resourceBinding status", "resourceBinding", bindingRef)
+		return controllerruntime.Result{}, err
+	}
+	if activateAt.IsZero() {
+		return controllerruntime.Result{}, nil
+	}
+	return controllerruntime.Result{RequeueAfter: time.Until(activateAt)}, nil
+}
+
+// setInactiveConditions sets the conditions of a binding whose placement is inactive until the time; the zero time
+// means that the placement is deactivated for good.
+func setInactiveConditions(resourceBinding *fleetv1beta1.ClusterResourceBinding, activateAt time.Time) {
+	resourceBinding.Status.FailedPlacements = nil
+	resourceBinding.Status.FailedPlacementsOverflow = 0
+	resourceBinding.Status.LoadBalancers = nil
+	message := "The works are removed from the target cluster as the placement is deactivated"
+	if !activateAt.IsZero() {
+		message = fmt.Sprintf("The works are removed from the target cluster as the placement is inactive until %s", activateAt.UTC().Format(time.RFC3339))
+	}
+	resourceBinding.SetConditions(metav1.Condition{
+		Status:             metav1.ConditionFalse,
+		Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+		Reason:             condition.PlacementInactiveReason,
+		Message:            message,
+		ObservedGeneration: resourceBinding.Generation,
+	})
+	meta.RemoveStatusCondition(&resourceBinding.Status.Conditions, string(fleetv1beta1.ResourceBindingApplied))
+	meta.RemoveStatusCondition(&resourceBinding.Status.Conditions, string(fleetv1beta1.ResourceBindingAvailable))
+}
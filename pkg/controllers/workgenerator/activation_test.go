@@ -0,0 +1,140 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestPlacementActivation(t *testing.T) {
+	day := func(d, h, m int) time.Time {
+		return time.Date(2024, time.May, d, h, m, 0, 0, time.UTC)
+	}
+	metaTime := func(t time.Time) *metav1.Time {
+		return &metav1.Time{Time: t}
+	}
+	nightly := &fleetv1beta1.DailyWindow{Start: "22:00", End: "06:00"}
+	office := &fleetv1beta1.DailyWindow{Start: "09:00", End: "17:00"}
+	tests := map[string]struct {
+		window     *fleetv1beta1.ActivationWindow
+		now        time.Time
+		wantActive bool
+		wantNext   time.Time
+	}{
+		"no window": {
+			now:        day(1, 12, 0),
+			wantActive: true,
+		},
+		"before activateAt": {
+			window:   &fleetv1beta1.ActivationWindow{ActivateAt: metaTime(day(2, 0, 0))},
+			now:      day(1, 12, 0),
+			wantNext: day(2, 0, 0),
+		},
+		"after activateAt": {
+			window:     &fleetv1beta1.ActivationWindow{ActivateAt: metaTime(day(1, 0, 0)), DeactivateAt: metaTime(day(3, 0, 0))},
+			now:        day(1, 12, 0),
+			wantActive: true,
+			wantNext:   day(3, 0, 0),
+		},
+		"after deactivateAt": {
+			window: &fleetv1beta1.ActivationWindow{DeactivateAt: metaTime(day(1, 0, 0))},
+			now:    day(1, 12, 0),
+		},
+		"in the daily window": {
+			window:     &fleetv1beta1.ActivationWindow{Daily: office},
+			now:        day(1, 12, 0),
+			wantActive: true,
+			wantNext:   day(1, 17, 0),
+		},
+		"before the daily window": {
+			window:   &fleetv1beta1.ActivationWindow{Daily: office},
+			now:      day(1, 8, 0),
+			wantNext: day(1, 9, 0),
+		},
+		"after the daily window": {
+			window:   &fleetv1beta1.ActivationWindow{Daily: office},
+			now:      day(1, 17, 0),
+			wantNext: day(2, 9, 0),
+		},
+		"in the nightly window before midnight": {
+			window:     &fleetv1beta1.ActivationWindow{Daily: nightly},
+			now:        day(1, 23, 0),
+			wantActive: true,
+			wantNext:   day(2, 6, 0),
+		},
+		"in the nightly window after midnight": {
+			window:     &fleetv1beta1.ActivationWindow{Daily: nightly},
+			now:        day(1, 5, 59),
+			wantActive: true,
+			wantNext:   day(1, 6, 0),
+		},
+		"out of the nightly window": {
+			window:   &fleetv1beta1.ActivationWindow{Daily: nightly},
+			now:      day(1, 12, 0),
+			wantNext: day(1, 22, 0),
+		},
+		"whole day window": {
+			window:     &fleetv1beta1.ActivationWindow{Daily: &fleetv1beta1.DailyWindow{Start: "00:00", End: "00:00"}},
+			now:        day(1, 12, 0),
+			wantActive: true,
+		},
+		"daily window ends after deactivateAt": {
+			window:     &fleetv1beta1.ActivationWindow{Daily: nightly, DeactivateAt: metaTime(day(2, 1, 0))},
+			now:        day(1, 23, 0),
+			wantActive: true,
+			wantNext:   day(2, 1, 0),
+		},
+		"daily window starts after deactivateAt": {
+			window: &fleetv1beta1.ActivationWindow{Daily: nightly, DeactivateAt: metaTime(day(1, 20, 0))},
+			now:    day(1, 12, 0),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotActive, gotNext := placementActivation(tt.window, tt.now)
+			if gotActive != tt.wantActive || !gotNext.Equal(tt.wantNext) {
+				t.Errorf("placementActivation() = (%t, %v), want (%t, %v)", gotActive, gotNext, tt.wantActive, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSetInactiveConditions(t *testing.T) {
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       fleetv1beta1.ResourceBindingSpec{TargetCluster: "member-1"},
+		Status: fleetv1beta1.ResourceBindingStatus{
+			FailedPlacements: []fleetv1beta1.FailedResourcePlacement{{ResourceIdentifier: fleetv1beta1.ResourceIdentifier{Name: "app"}}},
+			Conditions: []metav1.Condition{
+				{Type: string(fleetv1beta1.ResourceBindingApplied), Status: metav1.ConditionTrue, Reason: "Applied", ObservedGeneration: 2},
+				{Type: string(fleetv1beta1.ResourceBindingAvailable), Status: metav1.ConditionTrue, Reason: "Available", ObservedGeneration: 2},
+			},
+		},
+	}
+	setInactiveConditions(binding, time.Date(2024, time.May, 1, 22, 0, 0, 0, time.UTC))
+	want := fleetv1beta1.ResourceBindingStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+				Status:             metav1.ConditionFalse,
+				Reason:             condition.PlacementInactiveReason,
+				Message:            "The works are removed from the target cluster as the placement is inactive until 2024-05-01T22:00:00Z",
+				ObservedGeneration: 2,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, binding.Status, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("setInactiveConditions() status mismatch (-want, +got):\n%s", diff)
+	}
+}
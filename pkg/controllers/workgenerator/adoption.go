@@ -0,0 +1,129 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// adoptRestoredWorks adopts the works of the placement of the binding in the namespace of its target cluster which
+// the binding does not own yet after the hub is restored from a backup, and adds them to the works of the binding:
+//   - the works of the binding whose owner reference points to the UID of the binding before the restore, and
+//   - the works whose parent binding is gone, e.g. because the binding is recreated under another name after the
+//     restore.
+//
+// The adopted works are looked up by their labels rather than their owner references, so that the binding keeps
+// the works which are already applied on the member cluster instead of creating duplicates, e.g. of the envelope works
+// whose names are random, and the member agent does not apply everything again.
+func (r *Reconciler) adoptRestoredWorks(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding, currentWork map[string]*fleetv1beta1.Work) error {
+	crpName := resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel]
+	if crpName == "" {
+		return nil
+	}
+	bindingRef := klog.KObj(resourceBinding)
+	workList := &fleetv1beta1.WorkList{}
+	if err := r.Client.List(ctx, workList, client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, resourceBinding.Spec.TargetCluster)),
+		client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crpName}); err != nil {
+		klog.ErrorS(err, "Failed to list the works of the placement", "resourceBinding", bindingRef, "clusterResourcePlacement", crpName)
+		return controller.NewAPIServerError(true, err)
+	}
+	for i := range workList.Items {
+		work := &workList.Items[i]
+		if work.DeletionTimestamp != nil {
+			continue
+		}
+		if work.Labels[fleetv1beta1.ParentBindingLabel] != resourceBinding.Name {
+			orphaned, err := r.isOrphanedWork(ctx, work)
+			if err != nil {
+				return err
+			}
+			if !orphaned {
+				continue
+			}
+		}
+		if !adoptWork(work, resourceBinding) {
+			continue
+		}
+		if err := r.Client.Update(ctx, work); err != nil {
+			klog.ErrorS(err, "Failed to adopt the restored work", "resourceBinding", bindingRef, "work", klog.KObj(work))
+			return controller.NewUpdateIgnoreConflictError(err)
+		}
+		klog.V(2).InfoS("Adopted the restored work", "resourceBinding", bindingRef, "bindingUID", resourceBinding.UID, "work", klog.KObj(work))
+		currentWork[work.Name] = work.DeepCopy()
+	}
+	return nil
+}
+
+// isOrphanedWork tells if the parent binding of the work is gone.
+func (r *Reconciler) isOrphanedWork(ctx context.Context, work *fleetv1beta1.Work) (bool, error) {
+	parentBinding := work.Labels[fleetv1beta1.ParentBindingLabel]
+	if parentBinding == "" {
+		return true, nil
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: parentBinding}, &fleetv1beta1.ClusterResourceBinding{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		klog.ErrorS(err, "Failed to get the parent binding of the work", "work", klog.KObj(work), "resourceBinding", parentBinding)
+		return false, controller.NewAPIServerError(true, err)
+	}
+	return false, nil
+}
+
+// adoptWork labels the work with the binding and points its owner reference of a binding to the binding. It returns
+// true if the work is changed.
+func adoptWork(work *fleetv1beta1.Work, resourceBinding *fleetv1beta1.ClusterResourceBinding) bool {
+	changed := false
+	if work.Labels[fleetv1beta1.ParentBindingLabel] != resourceBinding.Name {
+		if work.Labels == nil {
+			work.Labels = map[string]string{}
+		}
+		work.Labels[fleetv1beta1.ParentBindingLabel] = resourceBinding.Name
+		changed = true
+	}
+	owner := metav1.OwnerReference{
+		APIVersion:         fleetv1beta1.GroupVersion.String(),
+		Kind:               fleetv1beta1.ClusterResourceBindingKind,
+		Name:               resourceBinding.Name,
+		UID:                resourceBinding.UID,
+		BlockOwnerDeletion: ptr.To(true),
+	}
+	refs := make([]metav1.OwnerReference, 0, len(work.OwnerReferences)+1)
+	found := false
+	for _, ref := range work.OwnerReferences {
+		if ref.Kind != fleetv1beta1.ClusterResourceBindingKind {
+			refs = append(refs, ref)
+			continue
+		}
+		if found || ref.Name != owner.Name || ref.UID != owner.UID {
+			// the owner reference of another binding, or of the binding before the restore
+			changed = true
+			continue
+		}
+		found = true
+		refs = append(refs, ref)
+	}
+	if !found {
+		refs = append(refs, owner)
+		changed = true
+	}
+	if changed {
+		work.OwnerReferences = refs
+	}
+	return changed
+}
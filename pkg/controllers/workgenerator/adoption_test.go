@@ -0,0 +1,137 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestAdoptRestoredWorks(t *testing.T) {
+	const namespace = "fleet-member-cluster-1"
+	bindingOwner := func(name, uid string) metav1.OwnerReference {
+		return metav1.OwnerReference{
+			APIVersion:         fleetv1beta1.GroupVersion.String(),
+			Kind:               fleetv1beta1.ClusterResourceBindingKind,
+			Name:               name,
+			UID:                types.UID(uid),
+			BlockOwnerDeletion: ptr.To(true),
+		}
+	}
+	newWork := func(name, crp, binding string, owner metav1.OwnerReference) *fleetv1beta1.Work {
+		return &fleetv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				Labels:          map[string]string{fleetv1beta1.CRPTrackingLabel: crp, fleetv1beta1.ParentBindingLabel: binding},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+		}
+	}
+	newBinding := func(name, uid string) *fleetv1beta1.ClusterResourceBinding {
+		return &fleetv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				UID:    types.UID(uid),
+				Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: "crp-1"},
+			},
+			Spec: fleetv1beta1.ResourceBindingSpec{TargetCluster: "cluster-1"},
+		}
+	}
+	binding := newBinding("crp-1-cluster-1-new", "new-uid")
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			binding,
+			// the binding of the placement on another cluster
+			newBinding("crp-1-cluster-2", "other-uid"),
+			// the work of the binding which points to its UID before the restore
+			newWork("crp-1-work", "crp-1", binding.Name, bindingOwner(binding.Name, "old-uid")),
+			// the envelope work of the binding before it is recreated under another name
+			newWork("crp-1-configmap-uuid", "crp-1", "crp-1-cluster-1-old", bindingOwner("crp-1-cluster-1-old", "old-uid")),
+			// the work of another binding which still exists
+			newWork("crp-1-other", "crp-1", "crp-1-cluster-2", bindingOwner("crp-1-cluster-2", "other-uid")),
+			// the work of another placement
+			newWork("crp-2-work", "crp-2", "crp-2-cluster-1-old", bindingOwner("crp-2-cluster-1-old", "old-uid")),
+		).
+		Build()
+	r := &Reconciler{Client: c}
+	ctx := context.Background()
+
+	currentWork := map[string]*fleetv1beta1.Work{}
+	if err := r.adoptRestoredWorks(ctx, binding, currentWork); err != nil {
+		t.Fatalf("adoptRestoredWorks() = %v, want nil", err)
+	}
+	var adopted []string
+	for name := range currentWork {
+		adopted = append(adopted, name)
+	}
+	sort.Strings(adopted)
+	if diff := cmp.Diff([]string{"crp-1-configmap-uuid", "crp-1-work"}, adopted); diff != "" {
+		t.Errorf("adopted works mismatch (-want, +got):\n%s", diff)
+	}
+	for _, name := range adopted {
+		work := &fleetv1beta1.Work{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, work); err != nil {
+			t.Fatalf("failed to get the work %s: %v", name, err)
+		}
+		if got := work.Labels[fleetv1beta1.ParentBindingLabel]; got != binding.Name {
+			t.Errorf("parent binding label of the work %s = %s, want %s", name, got, binding.Name)
+		}
+		if diff := cmp.Diff([]metav1.OwnerReference{bindingOwner(binding.Name, "new-uid")}, work.OwnerReferences); diff != "" {
+			t.Errorf("owner references of the work %s mismatch (-want, +got):\n%s", name, diff)
+		}
+	}
+	for _, name := range []string{"crp-1-other", "crp-2-work"} {
+		work := &fleetv1beta1.Work{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, work); err != nil {
+			t.Fatalf("failed to get the work %s: %v", name, err)
+		}
+		if work.Labels[fleetv1beta1.ParentBindingLabel] == binding.Name {
+			t.Errorf("the work %s is adopted, want it untouched", name)
+		}
+	}
+}
+
+func TestAdoptWork(t *testing.T) {
+	binding := &fleetv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding-1", UID: "uid-1"}}
+	owner := metav1.OwnerReference{
+		APIVersion:         fleetv1beta1.GroupVersion.String(),
+		Kind:               fleetv1beta1.ClusterResourceBindingKind,
+		Name:               "binding-1",
+		UID:                "uid-1",
+		BlockOwnerDeletion: ptr.To(true),
+	}
+	work := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:          map[string]string{fleetv1beta1.ParentBindingLabel: "binding-1"},
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+	}
+	if adoptWork(work, binding) {
+		t.Errorf("adoptWork() of the work already owned by the binding = true, want false")
+	}
+	work.OwnerReferences = nil
+	if !adoptWork(work, binding) {
+		t.Errorf("adoptWork() of the work whose owner references are stripped = false, want true")
+	}
+	if diff := cmp.Diff([]metav1.OwnerReference{owner}, work.OwnerReferences); diff != "" {
+		t.Errorf("owner references mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// errClusterGone indicates that the namespace of the target cluster no longer accepts works, as the cluster has left
+// the fleet or is leaving it.
+var errClusterGone = errors.New("the namespace of the target cluster no longer accepts works")
+
+// isClusterNamespaceGoneError tells if the creation of a work failed because the namespace of the target cluster is
+// being terminated or is already deleted.
+func isClusterNamespaceGoneError(err error) bool {
+	return apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) || apierrors.IsNotFound(err)
+}
+
+// handleClusterGone puts the binding whose target cluster has left the fleet, or is leaving it, into the terminal
+// ClusterGone state instead of retrying to synchronize its works to a namespace which no longer accepts them. The
+// binding is not requeued; the scheduler marks it as unscheduled, and reschedules the placement if its policy allows.
+func (r *Reconciler) handleClusterGone(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding, reason string) (controllerruntime.Result, error) {
+	bindingRef := klog.KObj(resourceBinding)
+	klog.V(2).InfoS("Stop synchronizing the works as the target cluster is gone", "clusterResourceBinding", bindingRef, "memberCluster", resourceBinding.Spec.TargetCluster, "reason", reason)
+	r.forgetMemberWriteLimit(resourceBinding, true)
+	originalBinding := resourceBinding.DeepCopy()
+	setClusterGoneConditions(resourceBinding, reason)
+	if err := r.updateBindingStatus(ctx, originalBinding, resourceBinding); err != nil {
+		klog.ErrorS(err, "Failed to update the resourceBinding status", "resourceBinding", bindingRef)
+		return controllerruntime.Result{}, err
+	}
+	return controllerruntime.Result{}, nil
+}
+
+// setClusterGoneConditions sets the conditions of a binding whose target cluster is gone for the given reason.
+func setClusterGoneConditions(resourceBinding *fleetv1beta1.ClusterResourceBinding, reason string) {
+	resourceBinding.Status.FailedPlacements = nil
+	resourceBinding.Status.FailedPlacementsOverflow = 0
+	resourceBinding.Status.LoadBalancers = nil
+	resourceBinding.SetConditions(metav1.Condition{
+		Status:             metav1.ConditionFalse,
+		Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+		Reason:             condition.ClusterGoneReason,
+		Message:            fmt.Sprintf("The works are no longer synchronized as the target cluster %s is gone", resourceBinding.Spec.TargetCluster),
+		ObservedGeneration: resourceBinding.Generation,
+	}, metav1.Condition{
+		Status:             metav1.ConditionTrue,
+		Type:               string(fleetv1beta1.ResourceBindingClusterGone),
+		Reason:             condition.ClusterGoneReason,
+		Message:            fmt.Sprintf("The target cluster %s %s", resourceBinding.Spec.TargetCluster, reason),
+		ObservedGeneration: resourceBinding.Generation,
+	})
+}
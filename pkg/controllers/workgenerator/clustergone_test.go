@@ -0,0 +1,96 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestIsClusterNamespaceGoneError(t *testing.T) {
+	workResource := schema.GroupResource{Group: fleetv1beta1.GroupVersion.Group, Resource: "works"}
+	terminatingErr := apierrors.NewForbidden(workResource, "work-1", errors.New("namespace fleet-member-cluster-1 is being terminated"))
+	terminatingErr.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: corev1.NamespaceTerminatingCause}}
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"namespace is being terminated": {
+			err:  terminatingErr,
+			want: true,
+		},
+		"namespace is not found": {
+			err:  apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "fleet-member-cluster-1"),
+			want: true,
+		},
+		"forbidden for another cause": {
+			err: apierrors.NewForbidden(workResource, "work-1", errors.New("denied by the webhook")),
+		},
+		"conflict": {
+			err: apierrors.NewConflict(workResource, "work-1", errors.New("conflict")),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isClusterNamespaceGoneError(tc.err); got != tc.want {
+				t.Errorf("isClusterNamespaceGoneError() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHandleClusterGone(t *testing.T) {
+	c, patches := newStatusPatchRecorder(t, nil)
+	r := &Reconciler{Client: c}
+	binding := bindingWithStatus("binding-1", []fleetv1beta1.FailedResourcePlacement{{}}, metav1.Condition{
+		Type:   string(fleetv1beta1.ResourceBindingWorkSynchronized),
+		Status: metav1.ConditionTrue,
+		Reason: condition.AllWorkSyncedReason,
+	})
+	binding.Spec.TargetCluster = "cluster-1"
+	binding.Generation = 2
+
+	got, err := r.handleClusterGone(context.Background(), binding, "has left the fleet")
+	if err != nil || got.Requeue || got.RequeueAfter != 0 {
+		t.Fatalf("handleClusterGone() = %+v, %v, want no requeue and nil", got, err)
+	}
+	wantConditions := []metav1.Condition{
+		{
+			Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+			Status:             metav1.ConditionFalse,
+			Reason:             condition.ClusterGoneReason,
+			Message:            "The works are no longer synchronized as the target cluster cluster-1 is gone",
+			ObservedGeneration: 2,
+		},
+		{
+			Type:               string(fleetv1beta1.ResourceBindingClusterGone),
+			Status:             metav1.ConditionTrue,
+			Reason:             condition.ClusterGoneReason,
+			Message:            "The target cluster cluster-1 has left the fleet",
+			ObservedGeneration: 2,
+		},
+	}
+	if diff := cmp.Diff(wantConditions, binding.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("binding conditions mismatch (-want, +got):\n%s", diff)
+	}
+	if binding.Status.FailedPlacements != nil {
+		t.Errorf("failedPlacements = %v, want nil", binding.Status.FailedPlacements)
+	}
+	if len(patches()) == 0 {
+		t.Errorf("the status of the binding is not written")
+	}
+}
@@ -7,6 +7,7 @@ Licensed under the MIT license.
 package workgenerator
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -18,13 +19,13 @@ import (
 	"go.uber.org/atomic"
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -42,13 +43,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/index"
 	"go.goms.io/fleet/pkg/utils/informer"
 	"go.goms.io/fleet/pkg/utils/labels"
+	"go.goms.io/fleet/pkg/utils/manifestsealing"
+	"go.goms.io/fleet/pkg/utils/worksigning"
 )
 
 var (
@@ -69,6 +75,41 @@ type Reconciler struct {
 	// the informer contains the cache for all the resources we need.
 	// to check the resource scope
 	InformerManager informer.Manager
+	// Signer signs the content of the works if set, so that the member agents can verify it before applying.
+	Signer worksigning.Signer
+	// SealAllSecrets seals all the secrets in the works with the keys of the member clusters instead of the ones
+	// annotated with the seal annotation only.
+	SealAllSecrets bool
+	// Sharder shards the placements across the replicas of the hub agent if set; the bindings of the placements of
+	// the other replicas are skipped.
+	Sharder *sharding.Sharder
+	// StatusBatchInterval is the interval over which the status writes of the bindings are batched and coalesced if
+	// set; the status of a binding is written in its reconcile otherwise.
+	StatusBatchInterval time.Duration
+	// MemberWriteQPS is the rate at which the works are written to the namespace of each member cluster if set, so
+	// that a burst of writes on the hub does not overwhelm the API server of a small member cluster; the writes are
+	// not limited otherwise.
+	MemberWriteQPS float64
+	// MemberWriteBurst is the number of the works which can be written to the namespace of a member cluster at once
+	// when MemberWriteQPS is set.
+	MemberWriteBurst int
+	// AdoptRestoredWorks adopts the works which a binding does not own yet after the hub is restored from a backup,
+	// e.g. the works of a binding recreated under another name, instead of creating duplicates of them.
+	AdoptRestoredWorks bool
+	// OverrideRenderCacheSize is the max number of the resources patched by the override rules which are cached, so
+	// that the same overrides applied to the resources on many clusters are only rendered once; the patched resources
+	// are not cached if it is 0.
+	OverrideRenderCacheSize int
+	// EnableMetadataOverrides applies the cluster metadata overrides to the resources after the other overrides, and
+	// regenerates the works of all the bindings when a cluster metadata override changes.
+	EnableMetadataOverrides bool
+
+	// statusWriter batches the status writes of the bindings if StatusBatchInterval is set.
+	statusWriter *bindingStatusWriter
+	// memberWriteLimiter limits the rate of the work writes to each member cluster if MemberWriteQPS is set.
+	memberWriteLimiter *memberWriteLimiter
+	// overrideCache caches the resources patched by the override rules if OverrideRenderCacheSize is set.
+	overrideCache *overrideRenderCache
 }
 
 // Reconcile triggers a single binding reconcile round.
@@ -89,6 +130,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 		return controllerruntime.Result{}, controller.NewAPIServerError(true, err)
 	}
 
+	if !r.Sharder.Owns(ctx, resourceBinding.GetLabels()[fleetv1beta1.CRPTrackingLabel]) {
+		klog.V(4).InfoS("Ignoring clusterResourceBinding of a clusterResourcePlacement owned by another shard", "resourceBinding", bindingRef)
+		return controllerruntime.Result{}, nil
+	}
+
 	// handle the case the binding is deleting
 	if resourceBinding.DeletionTimestamp != nil {
 		return r.handleDelete(ctx, resourceBinding.DeepCopy())
@@ -107,21 +153,36 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 	if err := r.Client.Get(ctx, types.NamespacedName{Name: resourceBinding.Spec.TargetCluster}, &cluster); err != nil {
 		if apierrors.IsNotFound(err) {
 			klog.V(2).InfoS("Skip reconciling clusterResourceBinding when the cluster is deleted", "memberCluster", resourceBinding.Spec.TargetCluster, "clusterResourceBinding", bindingRef)
-			return controllerruntime.Result{}, nil
+			return r.handleClusterGone(ctx, &resourceBinding, "has left the fleet")
 		}
 		klog.ErrorS(err, "Failed to get the memberCluster", "memberCluster", resourceBinding.Spec.TargetCluster, "clusterResourceBinding", bindingRef)
 		return controllerruntime.Result{}, controller.NewAPIServerError(true, err)
 	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return r.handleClusterGone(ctx, &resourceBinding, "is leaving the fleet")
+	}
+
+	// the works are only kept on the target cluster while the placement is in its activation window
+	activationWindow, err := r.getActivationWindow(ctx, &resourceBinding)
+	if err != nil {
+		return controllerruntime.Result{}, err
+	}
+	active, activationChangeAt := placementActivation(activationWindow, time.Now())
+	if !active {
+		return r.handleInactivePlacement(ctx, &resourceBinding, activationWindow, activationChangeAt)
+	}
 
 	// make sure that the resource binding obj has a finalizer
 	if err := r.ensureFinalizer(ctx, &resourceBinding); err != nil {
 		return controllerruntime.Result{}, err
 	}
 
+	// keep the status read from the cache so that an unchanged status is not written again
+	originalBinding := resourceBinding.DeepCopy()
 	workUpdated := false
 	overrideSucceeded := false
 	// list all the corresponding works
-	works, syncErr := r.listAllWorksAssociated(ctx, &resourceBinding)
+	works, deletingWorks, syncErr := r.listAllWorksAssociated(ctx, &resourceBinding)
 	if syncErr == nil {
 		// generate and apply the workUpdated works if we have all the works
 		overrideSucceeded, workUpdated, syncErr = r.syncAllWork(ctx, &resourceBinding, works, cluster)
@@ -144,7 +205,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 		})
 	}
 
-	if syncErr != nil {
+	if errors.Is(syncErr, errClusterGone) {
+		return r.handleClusterGone(ctx, &resourceBinding, "no longer accepts works in its namespace")
+	}
+
+	var throttledErr *workSyncThrottledError
+	var quotaErr *tenantQuotaExceededError
+	if errors.As(syncErr, &throttledErr) {
+		klog.V(2).InfoS("The writes of the works are throttled", "resourceBinding", bindingRef, "retryAfter", throttledErr.retryAfter)
+		// some works may have been written before the writes were throttled
+		resourceBinding.Status.FailedPlacements = nil
+		resourceBinding.Status.FailedPlacementsOverflow = 0
+		resourceBinding.SetConditions(metav1.Condition{
+			Status:             metav1.ConditionFalse,
+			Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+			Reason:             condition.WorkSyncThrottledReason,
+			Message:            "Not all of the works are synchronized yet as their writes to the member cluster are throttled",
+			ObservedGeneration: resourceBinding.Generation,
+		}, metav1.Condition{
+			Status:             metav1.ConditionTrue,
+			Type:               string(fleetv1beta1.ResourceBindingWorkSyncThrottled),
+			Reason:             condition.WorkSyncThrottledReason,
+			Message:            fmt.Sprintf("The writes of the works to the member cluster %s exceed its rate limit and are retried once it allows", resourceBinding.Spec.TargetCluster),
+			ObservedGeneration: resourceBinding.Generation,
+		})
+	} else if errors.As(syncErr, &quotaErr) {
+		klog.V(2).InfoS("The works exceed the placement quota of the tenant", "resourceBinding", bindingRef, "reason", quotaErr.Error())
+		resourceBinding.SetConditions(metav1.Condition{
+			Status:             metav1.ConditionFalse,
+			Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+			Reason:             condition.TenantQuotaExceededReason,
+			Message:            fmt.Sprintf("The works are not synchronized to the latest as %s", quotaErr.Error()),
+			ObservedGeneration: resourceBinding.Generation,
+		})
+	} else if syncErr != nil {
 		klog.ErrorS(syncErr, "Failed to sync all the works", "resourceBinding", bindingRef)
 		errorMessage := syncErr.Error()
 		// unwrap will return nil if syncErr is not wrapped
@@ -154,13 +248,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 		}
 		// remove all the failedPlacement as it does not reflect the latest status
 		resourceBinding.Status.FailedPlacements = nil
+		resourceBinding.Status.FailedPlacementsOverflow = 0
 		if !overrideSucceeded {
+			// the works are not synchronized either, for a hub side reason which tells the missing resource snapshot
+			// apart from the resources which cannot be read or overridden
+			syncReason := condition.OverrideRenderFailedReason
+			if errors.Is(syncErr, errResourceSnapshotNotFound) {
+				syncReason = condition.ResourceSnapshotNotFoundReason
+			}
 			resourceBinding.SetConditions(metav1.Condition{
 				Status:             metav1.ConditionFalse,
 				Type:               string(fleetv1beta1.ResourceBindingOverridden),
 				Reason:             condition.OverriddenFailedReason,
 				Message:            fmt.Sprintf("Failed to apply the override rules on the resources: %s", errorMessage),
 				ObservedGeneration: resourceBinding.Generation,
+			}, metav1.Condition{
+				Status:             metav1.ConditionFalse,
+				Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+				Reason:             syncReason,
+				Message:            fmt.Sprintf("The works are not synchronized as the resources cannot be rendered on the hub cluster: %s", errorMessage),
+				ObservedGeneration: resourceBinding.Generation,
 			})
 		} else {
 			resourceBinding.SetConditions(metav1.Condition{
@@ -171,6 +278,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 				ObservedGeneration: resourceBinding.Generation,
 			})
 		}
+	} else if memberSideCond := buildMemberSideSyncFailedCondition(&cluster, works, workUpdated, &resourceBinding); memberSideCond != nil {
+		klog.V(2).InfoS("The works are written but not synchronized to the member cluster", "resourceBinding", bindingRef, "reason", memberSideCond.Reason)
+		resourceBinding.Status.FailedPlacements = nil
+		resourceBinding.Status.FailedPlacementsOverflow = 0
+		resourceBinding.SetConditions(*memberSideCond)
 	} else {
 		resourceBinding.SetConditions(metav1.Condition{
 			Status:             metav1.ConditionTrue,
@@ -182,6 +294,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 		if workUpdated {
 			// revert the applied condition and failedPlacement if we made any changes to the work
 			resourceBinding.Status.FailedPlacements = nil
+			resourceBinding.Status.FailedPlacementsOverflow = 0
 			resourceBinding.SetConditions(metav1.Condition{
 				Status:             metav1.ConditionFalse,
 				Type:               string(fleetv1beta1.ResourceBindingApplied),
@@ -194,10 +307,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 		}
 	}
 
+	if throttledErr == nil {
+		meta.RemoveStatusCondition(&resourceBinding.Status.Conditions, string(fleetv1beta1.ResourceBindingWorkSyncThrottled))
+	}
+	meta.RemoveStatusCondition(&resourceBinding.Status.Conditions, string(fleetv1beta1.ResourceBindingClusterGone))
+	if syncErr == nil {
+		setResourcesDeletedCondition(&resourceBinding, deletingWorks)
+	}
+
 	// update the resource binding status
-	if updateErr := r.Client.Status().Update(ctx, &resourceBinding); updateErr != nil {
+	if updateErr := r.updateBindingStatus(ctx, originalBinding, &resourceBinding); updateErr != nil {
 		klog.ErrorS(updateErr, "Failed to update the resourceBinding status", "resourceBinding", bindingRef)
-		return controllerruntime.Result{}, controller.NewUpdateIgnoreConflictError(updateErr)
+		return controllerruntime.Result{}, updateErr
+	}
+	if throttledErr != nil {
+		// retry once the rate limit of the member cluster allows instead of backing off as on a failure
+		return controllerruntime.Result{RequeueAfter: throttledErr.retryAfter}, nil
+	}
+	if quotaErr != nil {
+		// check again later as the other placements of the tenant may have released some of the quota
+		return controllerruntime.Result{RequeueAfter: tenantQuotaRecheckInterval}, nil
+	}
+	if !activationChangeAt.IsZero() && (syncErr == nil || errors.Is(syncErr, controller.ErrUserError)) {
+		// remove the works once the placement becomes inactive
+		return controllerruntime.Result{RequeueAfter: time.Until(activationChangeAt)}, nil
 	}
 	if errors.Is(syncErr, controller.ErrUserError) {
 		// Stop retry when the error is caused by user error
@@ -220,11 +353,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req controllerruntime.Reques
 	return controllerruntime.Result{}, syncErr
 }
 
+// updateBindingStatus queues the status of the binding to the status writer if the writes are batched, or writes
+// it right away otherwise; an unchanged status is not written.
+func (r *Reconciler) updateBindingStatus(ctx context.Context, original, resourceBinding *fleetv1beta1.ClusterResourceBinding) error {
+	if r.statusWriter != nil {
+		r.statusWriter.enqueue(original, resourceBinding)
+		return nil
+	}
+	if equality.Semantic.DeepEqual(original.Status, resourceBinding.Status) {
+		klog.V(4).InfoS("The resourceBinding status is unchanged", "resourceBinding", klog.KObj(resourceBinding))
+		return nil
+	}
+	clearFailedPlacements := len(original.Status.FailedPlacements) > 0 && len(resourceBinding.Status.FailedPlacements) == 0
+	return applyBindingStatus(ctx, r.Client, resourceBinding, clearFailedPlacements)
+}
+
 // handleDelete handle a deleting binding
 func (r *Reconciler) handleDelete(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (controllerruntime.Result, error) {
 	klog.V(4).InfoS("Start to handle deleting resource binding", "resourceBinding", klog.KObj(resourceBinding))
+	reverseOrder, err := r.isDeletedInReverseOrder(ctx, resourceBinding)
+	if err != nil {
+		return controllerruntime.Result{}, err
+	}
+	if reverseOrder {
+		return r.deleteWorksInReverseOrder(ctx, resourceBinding)
+	}
 	// list all the corresponding works if exist
-	works, err := r.listAllWorksAssociated(ctx, resourceBinding)
+	works, deletingWorks, err := r.listAllWorksAssociated(ctx, resourceBinding)
 	if err != nil {
 		return controllerruntime.Result{}, err
 	}
@@ -242,20 +397,29 @@ func (r *Reconciler) handleDelete(ctx context.Context, resourceBinding *fleetv1b
 
 	// remove the work finalizer on the binding if all the work objects are deleted
 	if len(works) == 0 {
-		controllerutil.RemoveFinalizer(resourceBinding, fleetv1beta1.WorkFinalizer)
-		if err = r.Client.Update(ctx, resourceBinding); err != nil {
-			klog.ErrorS(err, "Failed to remove the work finalizer from resource binding", "resourceBinding", klog.KObj(resourceBinding))
-			return controllerruntime.Result{}, controller.NewUpdateIgnoreConflictError(err)
-		}
-		klog.V(2).InfoS("The resource binding is deleted", "resourceBinding", klog.KObj(resourceBinding))
-		return controllerruntime.Result{}, nil
+		return controllerruntime.Result{}, r.removeWorkFinalizer(ctx, resourceBinding)
 	}
 	klog.V(2).InfoS("The resource binding still has undeleted work", "resourceBinding", klog.KObj(resourceBinding),
 		"number of associated work", len(works))
+	if err := r.updateResourcesDeletedCondition(ctx, resourceBinding, len(works)+deletingWorks); err != nil {
+		return controllerruntime.Result{}, err
+	}
 	// we watch the work objects deleting events, so we can afford to wait a bit longer here as a fallback case.
 	return controllerruntime.Result{RequeueAfter: 30 * time.Second}, nil
 }
 
+// removeWorkFinalizer removes the work finalizer from the deleting binding once all of its works are deleted.
+func (r *Reconciler) removeWorkFinalizer(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) error {
+	controllerutil.RemoveFinalizer(resourceBinding, fleetv1beta1.WorkFinalizer)
+	if err := r.Client.Update(ctx, resourceBinding); err != nil {
+		klog.ErrorS(err, "Failed to remove the work finalizer from resource binding", "resourceBinding", klog.KObj(resourceBinding))
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	klog.V(2).InfoS("The resource binding is deleted", "resourceBinding", klog.KObj(resourceBinding))
+	r.forgetMemberWriteLimit(resourceBinding, false)
+	return nil
+}
+
 // ensureFinalizer makes sure that the resourceSnapshot CR has a finalizer on it.
 func (r *Reconciler) ensureFinalizer(ctx context.Context, resourceBinding client.Object) error {
 	if controllerutil.ContainsFinalizer(resourceBinding, fleetv1beta1.WorkFinalizer) {
@@ -288,25 +452,34 @@ func (r *Reconciler) ensureFinalizer(ctx context.Context, resourceBinding client
 	return nil
 }
 
-// listAllWorksAssociated finds all the live work objects that are associated with this binding.
-func (r *Reconciler) listAllWorksAssociated(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (map[string]*fleetv1beta1.Work, error) {
+// listAllWorksAssociated finds all the live work objects that are associated with this binding, and counts the ones
+// which are being deleted.
+func (r *Reconciler) listAllWorksAssociated(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (map[string]*fleetv1beta1.Work, int, error) {
 	namespaceMatcher := client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, resourceBinding.Spec.TargetCluster))
-	parentBindingLabelMatcher := client.MatchingLabels{
-		fleetv1beta1.ParentBindingLabel: resourceBinding.Name,
+	parentBindingMatcher := client.MatchingFields{
+		index.WorkBindingField: resourceBinding.Name,
 	}
 	currentWork := make(map[string]*fleetv1beta1.Work)
+	deletingWorks := 0
 	workList := &fleetv1beta1.WorkList{}
-	if err := r.Client.List(ctx, workList, parentBindingLabelMatcher, namespaceMatcher); err != nil {
+	if err := r.Client.List(ctx, workList, parentBindingMatcher, namespaceMatcher); err != nil {
 		klog.ErrorS(err, "Failed to list all the work associated with the resourceSnapshot", "resourceBinding", klog.KObj(resourceBinding))
-		return nil, controller.NewAPIServerError(true, err)
+		return nil, 0, controller.NewAPIServerError(true, err)
 	}
 	for _, work := range workList.Items {
 		if work.DeletionTimestamp == nil {
 			currentWork[work.Name] = work.DeepCopy()
+		} else {
+			deletingWorks++
+		}
+	}
+	if r.AdoptRestoredWorks {
+		if err := r.adoptRestoredWorks(ctx, resourceBinding, currentWork); err != nil {
+			return nil, 0, err
 		}
 	}
 	klog.V(2).InfoS("Get all the work associated", "numOfWork", len(currentWork), "resourceBinding", klog.KObj(resourceBinding))
-	return currentWork, nil
+	return currentWork, deletingWorks, nil
 }
 
 // syncAllWork generates all the work for the resourceSnapshot and apply them to the corresponding target cluster.
@@ -324,6 +497,13 @@ func (r *Reconciler) syncAllWork(ctx context.Context, resourceBinding *fleetv1be
 		return false, false, err
 	}
 
+	var quotaErr *tenantQuotaExceededError
+	if resourceBinding.Spec.State == fleetv1beta1.BindingStateBound {
+		if quotaErr, err = r.checkTenantResourceQuota(ctx, resourceBinding, resourceSnapshots); err != nil {
+			return false, false, err
+		}
+	}
+
 	croMap, err := r.fetchClusterResourceOverrideSnapshots(ctx, resourceBinding)
 	if err != nil {
 		return false, false, err
@@ -334,58 +514,29 @@ func (r *Reconciler) syncAllWork(ctx context.Context, resourceBinding *fleetv1be
 		return false, false, err
 	}
 
+	metadataOverrides, err := r.fetchMetadataOverrides(ctx)
+	if err != nil {
+		return false, false, err
+	}
+
+	generatedWorks, err := r.generateWorks(ctx, resourceBinding, resourceSnapshots, cluster, croMap, roMap, metadataOverrides)
+	if err != nil {
+		return generatedWorks != nil, false, err
+	}
+
 	// issue all the create/update requests for the corresponding works for each snapshot in parallel
 	activeWork := make(map[string]*fleetv1beta1.Work, len(resourceSnapshots))
 	errs, cctx := errgroup.WithContext(ctx)
-	// generate work objects for each resource snapshot
-	for i := range resourceSnapshots {
-		snapshot := resourceSnapshots[i]
-		var newWork []*fleetv1beta1.Work
-		workNamePrefix, err := getWorkNamePrefixFromSnapshotName(snapshot)
-		if err != nil {
-			klog.ErrorS(err, "Encountered a mal-formatted resource snapshot", "resourceSnapshot", klog.KObj(snapshot))
-			return false, false, err
+	for i := range generatedWorks {
+		snapshot := generatedWorks[i].snapshot
+		for _, work := range generatedWorks[i].works {
+			activeWork[work.Name] = work
 		}
-		var simpleManifests []fleetv1beta1.Manifest
-		for j := range snapshot.Spec.SelectedResources {
-			selectedResource := snapshot.Spec.SelectedResources[j]
-			if err := r.applyOverrides(&selectedResource, cluster, croMap, roMap); err != nil {
-				return false, false, err
-			}
-
-			// we need to special treat configMap with envelopeConfigMapAnnotation annotation,
-			// so we need to check the GVK and annotation of the selected resource
-			var uResource unstructured.Unstructured
-			if err := uResource.UnmarshalJSON(selectedResource.Raw); err != nil {
-				klog.ErrorS(err, "work has invalid content", "snapshot", klog.KObj(snapshot), "selectedResource", selectedResource.Raw)
-				return true, false, controller.NewUnexpectedBehaviorError(err)
-			}
-			if uResource.GetObjectKind().GroupVersionKind() == utils.ConfigMapGVK &&
-				len(uResource.GetAnnotations()[fleetv1beta1.EnvelopeConfigMapAnnotation]) != 0 {
-				// get a work object for the enveloped configMap
-				work, err := r.getConfigMapEnvelopWorkObj(ctx, workNamePrefix, resourceBinding, snapshot, &uResource)
-				if err != nil {
-					return true, false, err
-				}
-				activeWork[work.Name] = work
-				newWork = append(newWork, work)
-			} else {
-				simpleManifests = append(simpleManifests, fleetv1beta1.Manifest(selectedResource))
-			}
-		}
-		if len(simpleManifests) == 0 {
-			klog.V(2).InfoS("the snapshot contains enveloped resource only", "snapshot", klog.KObj(snapshot))
+		if quotaErr != nil {
+			// the works are still generated to apply the overrides, but they are not written
+			continue
 		}
-		// generate a work object for the manifests even if there is nothing to place
-		// to allow CRP to collect the status of the placement
-		// TODO (RZ): revisit to see if we need this hack
-		work := generateSnapshotWorkObj(workNamePrefix, resourceBinding, snapshot, simpleManifests)
-		activeWork[work.Name] = work
-		newWork = append(newWork, work)
-
-		// issue all the create/update requests for the corresponding works for each snapshot in parallel
-		for ni := range newWork {
-			w := newWork[ni]
+		for _, w := range generatedWorks[i].works {
 			errs.Go(func() error {
 				updated, err := r.upsertWork(cctx, w, existingWorks[w.Name].DeepCopy(), snapshot)
 				if err != nil {
@@ -399,6 +550,10 @@ func (r *Reconciler) syncAllWork(ctx context.Context, resourceBinding *fleetv1be
 		}
 	}
 
+	if quotaErr != nil {
+		return true, false, quotaErr
+	}
+
 	//  delete the works that are not associated with any resource snapshot
 	for i := range existingWorks {
 		work := existingWorks[i]
@@ -406,6 +561,9 @@ func (r *Reconciler) syncAllWork(ctx context.Context, resourceBinding *fleetv1be
 			continue
 		}
 		errs.Go(func() error {
+			if err := r.throttleWorkWrite(work); err != nil {
+				return err
+			}
 			if err := r.Client.Delete(ctx, work); err != nil {
 				if !apierrors.IsNotFound(err) {
 					klog.ErrorS(err, "Failed to delete the no longer needed work", "work", klog.KObj(work))
@@ -426,6 +584,89 @@ func (r *Reconciler) syncAllWork(ctx context.Context, resourceBinding *fleetv1be
 	return true, updateAny.Load(), nil
 }
 
+// snapshotWorks are the works generated for one resource snapshot.
+type snapshotWorks struct {
+	snapshot *fleetv1beta1.ClusterResourceSnapshot
+	works    []*fleetv1beta1.Work
+}
+
+// generateWorks generates the works of the binding for each resource snapshot with the overrides applied, without
+// writing them to the hub cluster.
+// It returns a nil slice with the error if the overrides cannot be applied, and a non-nil slice with the error if
+// the works cannot be generated after the overrides are applied.
+func (r *Reconciler) generateWorks(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding,
+	resourceSnapshots map[string]*fleetv1beta1.ClusterResourceSnapshot, cluster clusterv1beta1.MemberCluster,
+	croMap map[fleetv1beta1.ResourceIdentifier][]*placementv1alpha1.ClusterResourceOverrideSnapshot,
+	roMap map[fleetv1beta1.ResourceIdentifier][]*placementv1alpha1.ResourceOverrideSnapshot,
+	metadataOverrides []placementv1alpha1.ClusterMetadataOverride) ([]snapshotWorks, error) {
+	generated := make([]snapshotWorks, 0, len(resourceSnapshots))
+	// generate work objects for each resource snapshot
+	for i := range resourceSnapshots {
+		snapshot := resourceSnapshots[i]
+		var newWork []*fleetv1beta1.Work
+		workNamePrefix, err := getWorkNamePrefixFromSnapshotName(snapshot)
+		if err != nil {
+			klog.ErrorS(err, "Encountered a mal-formatted resource snapshot", "resourceSnapshot", klog.KObj(snapshot))
+			return nil, err
+		}
+		var simpleManifests []fleetv1beta1.Manifest
+		var sealingKeyID string
+		for j := range snapshot.Spec.SelectedResources {
+			selectedResource := snapshot.Spec.SelectedResources[j]
+			if err := r.applyOverrides(&selectedResource, cluster, croMap, roMap); err != nil {
+				return nil, err
+			}
+
+			// we need to special treat configMap with envelopeConfigMapAnnotation annotation,
+			// so we need to check the GVK and annotation of the selected resource
+			var uResource unstructured.Unstructured
+			if err := uResource.UnmarshalJSON(selectedResource.Raw); err != nil {
+				klog.ErrorS(err, "work has invalid content", "snapshot", klog.KObj(snapshot), "selectedResource", selectedResource.Raw)
+				return generated, controller.NewUnexpectedBehaviorError(err)
+			}
+			if err := applyMetadataOverrides(&selectedResource, &uResource, cluster, metadataOverrides); err != nil {
+				return nil, err
+			}
+			if envelopeType, isEnvelope := utils.GetEnvelopeType(&uResource); isEnvelope {
+				// get a work object for the enveloped configMap
+				work, err := r.getConfigMapEnvelopWorkObj(ctx, workNamePrefix, resourceBinding, snapshot, &uResource, envelopeType)
+				if err != nil {
+					return generated, err
+				}
+				newWork = append(newWork, work)
+			} else {
+				if manifestsealing.NeedsSealing(&uResource, r.SealAllSecrets) {
+					if sealingKeyID, err = sealManifest(&selectedResource, &uResource, &cluster); err != nil {
+						return generated, err
+					}
+				}
+				simpleManifests = append(simpleManifests, fleetv1beta1.Manifest(selectedResource))
+				if utils.IsExportedService(&uResource) {
+					exportManifest, err := serviceExportManifest(&uResource)
+					if err != nil {
+						klog.ErrorS(err, "Failed to build the serviceExport of the service", "snapshot", klog.KObj(snapshot), "service", klog.KObj(&uResource))
+						return generated, controller.NewUnexpectedBehaviorError(err)
+					}
+					simpleManifests = append(simpleManifests, exportManifest)
+				}
+			}
+		}
+		if len(simpleManifests) == 0 {
+			klog.V(2).InfoS("the snapshot contains enveloped resource only", "snapshot", klog.KObj(snapshot))
+		}
+		// generate a work object for the manifests even if there is nothing to place
+		// to allow CRP to collect the status of the placement
+		// TODO (RZ): revisit to see if we need this hack
+		work := generateSnapshotWorkObj(workNamePrefix, resourceBinding, snapshot, simpleManifests)
+		if sealingKeyID != "" {
+			work.Annotations = map[string]string{fleetv1beta1.SealedManifestAnnotation: sealingKeyID}
+		}
+		newWork = append(newWork, work)
+		generated = append(generated, snapshotWorks{snapshot: snapshot, works: newWork})
+	}
+	return generated, nil
+}
+
 // fetchAllResourceSnapshots gathers all the resource snapshots for the resource binding.
 func (r *Reconciler) fetchAllResourceSnapshots(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (map[string]*fleetv1beta1.ClusterResourceSnapshot, error) {
 	// fetch the master snapshot first
@@ -445,9 +686,12 @@ func (r *Reconciler) fetchAllResourceSnapshots(ctx context.Context, resourceBind
 // getConfigMapEnvelopWorkObj first try to locate a work object for the corresponding envelopObj of type configMap.
 // we create a new one if the work object doesn't exist. We do this to avoid repeatedly delete and create the same work object.
 func (r *Reconciler) getConfigMapEnvelopWorkObj(ctx context.Context, workNamePrefix string, resourceBinding *fleetv1beta1.ClusterResourceBinding,
-	resourceSnapshot *fleetv1beta1.ClusterResourceSnapshot, envelopeObj *unstructured.Unstructured) (*fleetv1beta1.Work, error) {
+	resourceSnapshot *fleetv1beta1.ClusterResourceSnapshot, envelopeObj *unstructured.Unstructured, envelopeType fleetv1beta1.EnvelopeType) (*fleetv1beta1.Work, error) {
 	// we group all the resources in one configMap to one work
 	manifest, err := extractResFromConfigMap(envelopeObj)
+	if err == nil && envelopeType == fleetv1beta1.FluxSourceEnvelopeType {
+		err = bindFluxSource(manifest, envelopeObj.GetAnnotations()[fleetv1beta1.EnvelopeFluxSourceAnnotation])
+	}
 	if err != nil {
 		klog.ErrorS(err, "configMap has invalid content", "snapshot", klog.KObj(resourceSnapshot),
 			"resourceBinding", klog.KObj(resourceBinding), "configMapWrapper", klog.KObj(envelopeObj))
@@ -457,33 +701,22 @@ func (r *Reconciler) getConfigMapEnvelopWorkObj(ctx context.Context, workNamePre
 		"snapshot", klog.KObj(resourceSnapshot), "resourceBinding", klog.KObj(resourceBinding), "configMapWrapper", klog.KObj(envelopeObj))
 	// Try to see if we already have a work represent the same enveloped object for this CRP in the same cluster
 	// The ParentResourceSnapshotIndexLabel can change between snapshots so we have to exclude that label in the match
-	envelopWorkLabelMatcher := client.MatchingLabels{
-		fleetv1beta1.ParentBindingLabel:     resourceBinding.Name,
-		fleetv1beta1.CRPTrackingLabel:       resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel],
-		fleetv1beta1.EnvelopeTypeLabel:      string(fleetv1beta1.ConfigMapEnvelopeType),
-		fleetv1beta1.EnvelopeNameLabel:      envelopeObj.GetName(),
-		fleetv1beta1.EnvelopeNamespaceLabel: envelopeObj.GetNamespace(),
-	}
-	workList := &fleetv1beta1.WorkList{}
-	if err := r.Client.List(ctx, workList, envelopWorkLabelMatcher); err != nil {
-		return nil, controller.NewAPIServerError(true, err)
+	works, err := r.listEnvelopeWorks(ctx, resourceBinding, envelopeType, envelopeObj)
+	if err != nil {
+		return nil, err
 	}
 	// we need to create a new work object
-	if len(workList.Items) == 0 {
+	if len(works) == 0 {
 		// we limit the CRP name length to be 63 (DNS1123LabelMaxLength) characters,
 		// so we have plenty of characters left to fit into 253 (DNS1123SubdomainMaxLength) characters for a CR
-		workName := fmt.Sprintf(fleetv1beta1.WorkNameWithConfigEnvelopeFmt, workNamePrefix, uuid.NewUUID())
-		return &fleetv1beta1.Work{
+		work := &fleetv1beta1.Work{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      workName,
+				Name:      envelopeWorkName(workNamePrefix, envelopeType, envelopeObj),
 				Namespace: fmt.Sprintf(utils.NamespaceNameFormat, resourceBinding.Spec.TargetCluster),
 				Labels: map[string]string{
 					fleetv1beta1.ParentBindingLabel:               resourceBinding.Name,
 					fleetv1beta1.CRPTrackingLabel:                 resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel],
 					fleetv1beta1.ParentResourceSnapshotIndexLabel: resourceSnapshot.Labels[fleetv1beta1.ResourceIndexLabel],
-					fleetv1beta1.EnvelopeTypeLabel:                string(fleetv1beta1.ConfigMapEnvelopeType),
-					fleetv1beta1.EnvelopeNameLabel:                envelopeObj.GetName(),
-					fleetv1beta1.EnvelopeNamespaceLabel:           envelopeObj.GetNamespace(),
 				},
 				OwnerReferences: []metav1.OwnerReference{
 					{
@@ -500,19 +733,24 @@ func (r *Reconciler) getConfigMapEnvelopWorkObj(ctx context.Context, workNamePre
 					Manifests: manifest,
 				},
 				ApplyStrategy: resourceBinding.Spec.ApplyStrategy,
+				Priority:      resourceBinding.Spec.Priority,
 			},
-		}, nil
+		}
+		setEnvelopeIdentity(work, envelopeType, envelopeObj)
+		return work, nil
 	}
-	if len(workList.Items) > 1 {
+	if len(works) > 1 {
 		// return error here won't get us out of this
-		klog.ErrorS(controller.NewUnexpectedBehaviorError(fmt.Errorf("find %d work representing configMap", len(workList.Items))),
+		klog.ErrorS(controller.NewUnexpectedBehaviorError(fmt.Errorf("find %d work representing configMap", len(works))),
 			"snapshot", klog.KObj(resourceSnapshot), "resourceBinding", klog.KObj(resourceBinding), "configMapWrapper", klog.KObj(envelopeObj))
 	}
 	// we just pick the first one if there are more than one.
-	work := workList.Items[0]
+	work := works[0]
 	work.Labels[fleetv1beta1.ParentResourceSnapshotIndexLabel] = resourceSnapshot.Labels[fleetv1beta1.ResourceIndexLabel]
+	setEnvelopeIdentity(&work, envelopeType, envelopeObj)
 	work.Spec.Workload.Manifests = manifest
 	work.Spec.ApplyStrategy = resourceBinding.Spec.ApplyStrategy
+	work.Spec.Priority = resourceBinding.Spec.Priority
 	return &work, nil
 }
 
@@ -542,6 +780,7 @@ func generateSnapshotWorkObj(workName string, resourceBinding *fleetv1beta1.Clus
 				Manifests: manifest,
 			},
 			ApplyStrategy: resourceBinding.Spec.ApplyStrategy,
+			Priority:      resourceBinding.Spec.Priority,
 		},
 	}
 }
@@ -552,8 +791,17 @@ func (r *Reconciler) upsertWork(ctx context.Context, newWork, existingWork *flee
 	workObj := klog.KObj(newWork)
 	resourceSnapshotObj := klog.KObj(resourceSnapshot)
 	if existingWork == nil {
+		if err := r.throttleWorkWrite(newWork); err != nil {
+			return false, err
+		}
+		if err := r.signWork(ctx, newWork); err != nil {
+			return false, err
+		}
 		if err := r.Client.Create(ctx, newWork); err != nil {
 			klog.ErrorS(err, "Failed to create the work associated with the resourceSnapshot", "resourceSnapshot", resourceSnapshotObj, "work", workObj)
+			if isClusterNamespaceGoneError(err) {
+				return false, fmt.Errorf("%w: %v", errClusterGone, err)
+			}
 			return false, controller.NewCreateIgnoreAlreadyExistError(err)
 		}
 		klog.V(2).InfoS("Successfully create the work associated with the resourceSnapshot",
@@ -568,14 +816,49 @@ func (r *Reconciler) upsertWork(ctx context.Context, newWork, existingWork *flee
 	}
 	// we already checked the label in fetchAllResourceSnapShots function so no need to check again
 	resourceIndex, _ := labels.ExtractResourceIndexFromClusterResourceSnapshot(resourceSnapshot)
-	if workResourceIndex == resourceIndex {
+	sealingKeyID := newWork.GetAnnotations()[fleetv1beta1.SealedManifestAnnotation]
+	if workResourceIndex == resourceIndex && (r.Signer == nil || worksigning.IsSignedBy(r.Signer, existingWork)) &&
+		existingWork.GetAnnotations()[fleetv1beta1.SealedManifestAnnotation] == sealingKeyID &&
+		existingWork.GetLabels()[fleetv1beta1.EnvelopeHashLabel] == newWork.GetLabels()[fleetv1beta1.EnvelopeHashLabel] &&
+		existingWork.Spec.Priority == newWork.Spec.Priority {
 		// no need to do anything if the work is generated from the same resource snapshot group since the resource snapshot is immutable.
 		klog.V(2).InfoS("Work is already associated with the desired resourceSnapshot", "resourceIndex", resourceIndex, "work", workObj, "resourceSnapshot", resourceSnapshotObj)
 		return false, nil
 	}
+	if err := r.throttleWorkWrite(existingWork); err != nil {
+		return false, err
+	}
 	// need to update the existing work, only two possible changes:
 	existingWork.Labels[fleetv1beta1.ParentResourceSnapshotIndexLabel] = resourceSnapshot.Labels[fleetv1beta1.ResourceIndexLabel]
 	existingWork.Spec.Workload.Manifests = newWork.Spec.Workload.Manifests
+	// the priority of the placement changes when the placement rolls out with a new priority
+	existingWork.Spec.Priority = newWork.Spec.Priority
+	// the sealed manifests need to be re-sealed when the key of the member cluster changes
+	if sealingKeyID != "" {
+		if existingWork.Annotations == nil {
+			existingWork.Annotations = map[string]string{}
+		}
+		existingWork.Annotations[fleetv1beta1.SealedManifestAnnotation] = sealingKeyID
+	} else {
+		delete(existingWork.Annotations, fleetv1beta1.SealedManifestAnnotation)
+	}
+	// the works of the envelope objects generated before they are labeled with the envelope hash are migrated
+	if _, isEnvelope := newWork.Labels[fleetv1beta1.EnvelopeHashLabel]; isEnvelope {
+		for _, label := range []string{fleetv1beta1.EnvelopeHashLabel, fleetv1beta1.EnvelopeTypeLabel, fleetv1beta1.EnvelopeNamespaceLabel, fleetv1beta1.EnvelopeNameLabel} {
+			if value, ok := newWork.Labels[label]; ok {
+				existingWork.Labels[label] = value
+			} else {
+				delete(existingWork.Labels, label)
+			}
+		}
+		if existingWork.Annotations == nil {
+			existingWork.Annotations = map[string]string{}
+		}
+		existingWork.Annotations[fleetv1beta1.EnvelopeNameAnnotation] = newWork.Annotations[fleetv1beta1.EnvelopeNameAnnotation]
+	}
+	if err := r.signWork(ctx, existingWork); err != nil {
+		return false, err
+	}
 	if err := r.Client.Update(ctx, existingWork); err != nil {
 		klog.ErrorS(err, "Failed to update the work associated with the resourceSnapshot", "resourceSnapshot", resourceSnapshotObj, "work", workObj)
 		return true, controller.NewUpdateIgnoreConflictError(err)
@@ -584,6 +867,47 @@ func (r *Reconciler) upsertWork(ctx context.Context, newWork, existingWork *flee
 	return true, nil
 }
 
+// signWork signs the content of the work if the signer is set.
+func (r *Reconciler) signWork(ctx context.Context, work *fleetv1beta1.Work) error {
+	if r.Signer == nil {
+		return nil
+	}
+	if err := worksigning.SignWork(ctx, r.Signer, work); err != nil {
+		// the signer could be temporarily unavailable, e.g. when the key is in a remote key vault
+		klog.ErrorS(err, "Failed to sign the work", "work", klog.KObj(work))
+		return controller.NewExpectedBehaviorError(err)
+	}
+	return nil
+}
+
+// sealManifest encrypts the data of the secret with the key of the member cluster and returns the ID of the key.
+// The secrets wrapped in the envelope configMaps are not sealed as their plaintext is in the configMaps anyway.
+func sealManifest(selectedResource *fleetv1beta1.ResourceContent, uResource *unstructured.Unstructured, cluster *clusterv1beta1.MemberCluster) (string, error) {
+	if cluster.Status.ManifestEncryptionPublicKey == "" {
+		// never fall back to the plaintext
+		err := fmt.Errorf("member cluster %s has not published its manifest encryption key to seal secret %s/%s",
+			cluster.Name, uResource.GetNamespace(), uResource.GetName())
+		klog.ErrorS(err, "Failed to seal the secret", "memberCluster", klog.KObj(cluster), "secret", klog.KObj(uResource))
+		return "", controller.NewUserError(err)
+	}
+	publicKey, err := manifestsealing.ParsePublicKey(cluster.Status.ManifestEncryptionPublicKey)
+	if err != nil {
+		klog.ErrorS(err, "Member cluster has an invalid manifest encryption key", "memberCluster", klog.KObj(cluster))
+		return "", controller.NewUnexpectedBehaviorError(err)
+	}
+	if err := manifestsealing.Seal(uResource, publicKey); err != nil {
+		klog.ErrorS(err, "Failed to seal the secret", "memberCluster", klog.KObj(cluster), "secret", klog.KObj(uResource))
+		return "", controller.NewUnexpectedBehaviorError(err)
+	}
+	raw, err := uResource.MarshalJSON()
+	if err != nil {
+		klog.ErrorS(err, "Failed to encode the sealed secret", "secret", klog.KObj(uResource))
+		return "", controller.NewUnexpectedBehaviorError(err)
+	}
+	selectedResource.Raw = raw
+	return manifestsealing.KeyID(publicKey), nil
+}
+
 // getWorkNamePrefixFromSnapshotName extract the CRP and sub-index name from the corresponding resource snapshot.
 // The corresponding work name prefix is the CRP name + sub-index if there is a sub-index. Otherwise, it is the CRP name +"-work".
 // For example, if the resource snapshot name is "crp-1-0", the corresponding work name is "crp-0".
@@ -620,6 +944,7 @@ func setBindingStatus(works map[string]*fleetv1beta1.Work, resourceBinding *flee
 		resourceBinding.SetConditions(availableCond)
 	}
 	resourceBinding.Status.FailedPlacements = nil
+	resourceBinding.Status.FailedPlacementsOverflow = 0
 	// collect and set the failed resource placements to the binding if not all the works are available
 	if appliedCond.Status != metav1.ConditionTrue || availableCond.Status != metav1.ConditionTrue {
 		failedResourcePlacements := make([]fleetv1beta1.FailedResourcePlacement, 0, maxFailedResourcePlacementLimit) // preallocate the memory
@@ -631,85 +956,116 @@ func setBindingStatus(works map[string]*fleetv1beta1.Work, resourceBinding *flee
 			failedManifests := extractFailedResourcePlacementsFromWork(w)
 			failedResourcePlacements = append(failedResourcePlacements, failedManifests...)
 		}
-		// cut the list to keep only the max limit
+		// sort the list so that the same failed resource placements are kept no matter the order of the works,
+		// and cut it to keep only the max limit
+		sortFailedResourcePlacements(failedResourcePlacements)
 		if len(failedResourcePlacements) > maxFailedResourcePlacementLimit {
+			resourceBinding.Status.FailedPlacementsOverflow = int32(len(failedResourcePlacements) - maxFailedResourcePlacementLimit)
 			failedResourcePlacements = failedResourcePlacements[0:maxFailedResourcePlacementLimit]
 		}
 		resourceBinding.Status.FailedPlacements = failedResourcePlacements
 		if len(failedResourcePlacements) > 0 {
-			klog.V(2).InfoS("Populated failed manifests", "clusterResourceBinding", bindingRef, "numberOfFailedPlacements", len(failedResourcePlacements))
+			klog.V(2).InfoS("Populated failed manifests", "clusterResourceBinding", bindingRef, "numberOfFailedPlacements", len(failedResourcePlacements),
+				"overflow", resourceBinding.Status.FailedPlacementsOverflow)
 		}
 	}
+	resourceBinding.Status.LoadBalancers = extractLoadBalancersFromWorks(works)
 }
 
-func buildAllWorkAppliedCondition(works map[string]*fleetv1beta1.Work, binding *fleetv1beta1.ClusterResourceBinding) metav1.Condition {
-	allApplied := true
-	var notAppliedWork string
-	for _, w := range works {
-		if !condition.IsConditionStatusTrue(meta.FindStatusCondition(w.Status.Conditions, fleetv1beta1.WorkConditionTypeApplied), w.GetGeneration()) {
-			allApplied = false
-			notAppliedWork = w.Name
-			break
+// sortFailedResourcePlacements sorts the failed resource placements by their GVKs, namespaces and names; the ones of
+// the same resource are sorted by their envelopes and then the types of their failed conditions.
+func sortFailedResourcePlacements(placements []fleetv1beta1.FailedResourcePlacement) {
+	envelopeKey := func(e *fleetv1beta1.EnvelopeIdentifier) string {
+		if e == nil {
+			return ""
 		}
+		return string(e.Type) + "/" + e.Namespace + "/" + e.Name
 	}
-	if allApplied {
-		klog.V(2).InfoS("All works associated with the binding are applied", "binding", klog.KObj(binding))
-		return metav1.Condition{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ResourceBindingApplied),
-			Reason:             condition.AllWorkAppliedReason,
-			Message:            "All corresponding work objects are applied",
-			ObservedGeneration: binding.GetGeneration(),
+	sort.SliceStable(placements, func(i, j int) bool {
+		a, b := placements[i], placements[j]
+		switch {
+		case a.Group != b.Group:
+			return a.Group < b.Group
+		case a.Version != b.Version:
+			return a.Version < b.Version
+		case a.Kind != b.Kind:
+			return a.Kind < b.Kind
+		case a.Namespace != b.Namespace:
+			return a.Namespace < b.Namespace
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		case envelopeKey(a.Envelope) != envelopeKey(b.Envelope):
+			return envelopeKey(a.Envelope) < envelopeKey(b.Envelope)
+		default:
+			return a.Condition.Type < b.Condition.Type
 		}
-	}
-	return metav1.Condition{
-		Status:             metav1.ConditionFalse,
-		Type:               string(fleetv1beta1.ResourceBindingApplied),
-		Reason:             condition.WorkNotAppliedReason,
-		Message:            fmt.Sprintf("Work object %s is not applied", notAppliedWork),
-		ObservedGeneration: binding.GetGeneration(),
-	}
+	})
 }
 
-func buildAllWorkAvailableCondition(works map[string]*fleetv1beta1.Work, binding *fleetv1beta1.ClusterResourceBinding) metav1.Condition {
-	allAvailable := true
-	var notAvailableWork string
-	var notTrackableWork string
-	for _, w := range works {
-		cond := meta.FindStatusCondition(w.Status.Conditions, fleetv1beta1.WorkConditionTypeAvailable)
-		if !condition.IsConditionStatusTrue(cond, w.GetGeneration()) {
-			allAvailable = false
-			notAvailableWork = w.Name
-			break
-		}
-		if cond.Reason == work.WorkNotTrackableReason {
-			notTrackableWork = w.Name
-		}
+var (
+	// allWorkAppliedAggregator aggregates the applied conditions of the works into the applied condition of their binding.
+	allWorkAppliedAggregator = condition.Aggregator{
+		Type: string(fleetv1beta1.ResourceBindingApplied),
+		Reasons: []condition.AggregatedReason{
+			{Reason: condition.WorkNotAppliedReason, Status: metav1.ConditionFalse, Message: "Work objects %s are not applied"},
+			{Reason: condition.AllWorkAppliedReason, Status: metav1.ConditionTrue, Message: "All corresponding work objects are applied"},
+		},
+		ReasonFor: func(child condition.ChildCondition) string {
+			if child.Status() != metav1.ConditionTrue {
+				return condition.WorkNotAppliedReason
+			}
+			return condition.AllWorkAppliedReason
+		},
 	}
-	if allAvailable {
-		klog.V(2).InfoS("All works associated with the binding are available", "binding", klog.KObj(binding))
-		reason := condition.AllWorkAvailableReason
-		message := "All corresponding work objects are available"
-		if len(notTrackableWork) > 0 {
-			reason = work.WorkNotTrackableReason
-			message = fmt.Sprintf("The availability of work object %s is not trackable", notTrackableWork)
-		}
 
-		return metav1.Condition{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ResourceBindingAvailable),
-			Reason:             reason,
-			Message:            message,
-			ObservedGeneration: binding.GetGeneration(),
-		}
+	// allWorkAvailableAggregator aggregates the available conditions of the works into the available condition of
+	// their binding; the works which are not available for other reasons take precedence over the provisioning ones.
+	allWorkAvailableAggregator = condition.Aggregator{
+		Type: string(fleetv1beta1.ResourceBindingAvailable),
+		Reasons: []condition.AggregatedReason{
+			{Reason: condition.WorkNotAvailableReason, Status: metav1.ConditionFalse, Message: "Work objects %s are not available"},
+			{Reason: condition.ProvisioningReason, Status: metav1.ConditionFalse, Message: "Work objects %s are still provisioning"},
+			{Reason: work.WorkNotTrackableReason, Status: metav1.ConditionTrue, Message: "The availability of work objects %s is not trackable"},
+			{Reason: condition.AllWorkAvailableReason, Status: metav1.ConditionTrue, Message: "All corresponding work objects are available"},
+		},
+		ReasonFor: func(child condition.ChildCondition) string {
+			switch {
+			case child.Status() == metav1.ConditionFalse && child.Reason() == work.WorkProvisioningReason:
+				return condition.ProvisioningReason
+			case child.Status() != metav1.ConditionTrue:
+				return condition.WorkNotAvailableReason
+			case child.Reason() == work.WorkNotTrackableReason:
+				return work.WorkNotTrackableReason
+			default:
+				return condition.AllWorkAvailableReason
+			}
+		},
 	}
-	return metav1.Condition{
-		Status:             metav1.ConditionFalse,
-		Type:               string(fleetv1beta1.ResourceBindingAvailable),
-		Reason:             condition.WorkNotAvailableReason,
-		Message:            fmt.Sprintf("Work object %s is not available", notAvailableWork),
-		ObservedGeneration: binding.GetGeneration(),
+)
+
+// workConditions returns the conditions of the given type of the works to aggregate.
+func workConditions(works map[string]*fleetv1beta1.Work, conditionType string) []condition.ChildCondition {
+	children := make([]condition.ChildCondition, 0, len(works))
+	for _, w := range works {
+		children = append(children, condition.ChildCondition{
+			Name:       w.Name,
+			Generation: w.GetGeneration(),
+			Condition:  meta.FindStatusCondition(w.Status.Conditions, conditionType),
+		})
 	}
+	return children
+}
+
+func buildAllWorkAppliedCondition(works map[string]*fleetv1beta1.Work, binding *fleetv1beta1.ClusterResourceBinding) metav1.Condition {
+	cond := allWorkAppliedAggregator.Aggregate(workConditions(works, fleetv1beta1.WorkConditionTypeApplied), binding.GetGeneration())
+	klog.V(2).InfoS("Aggregated the applied condition of the works associated with the binding", "binding", klog.KObj(binding), "reason", cond.Reason)
+	return cond
+}
+
+func buildAllWorkAvailableCondition(works map[string]*fleetv1beta1.Work, binding *fleetv1beta1.ClusterResourceBinding) metav1.Condition {
+	cond := allWorkAvailableAggregator.Aggregate(workConditions(works, fleetv1beta1.WorkConditionTypeAvailable), binding.GetGeneration())
+	klog.V(2).InfoS("Aggregated the available condition of the works associated with the binding", "binding", klog.KObj(binding), "reason", cond.Reason)
+	return cond
 }
 
 func extractResFromConfigMap(uConfigMap *unstructured.Unstructured) ([]fleetv1beta1.Manifest, error) {
@@ -739,6 +1095,40 @@ func extractResFromConfigMap(uConfigMap *unstructured.Unstructured) ([]fleetv1be
 	return manifests, nil
 }
 
+// bindFluxSource makes the Flux Kustomizations and HelmReleases in the manifests reconcile the Flux source referenced
+// by the envelope.
+func bindFluxSource(manifests []fleetv1beta1.Manifest, sourceRefValue string) error {
+	sourceRef, err := utils.ParseFluxSourceReference(sourceRefValue)
+	if err != nil {
+		return err
+	}
+	for i := range manifests {
+		var uObj unstructured.Unstructured
+		if err := uObj.UnmarshalJSON(manifests[i].Raw); err != nil {
+			return err
+		}
+		if err := utils.BindFluxSourceReference(&uObj, sourceRef); err != nil {
+			return err
+		}
+		raw, err := uObj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		manifests[i].Raw = bytes.TrimSpace(raw)
+	}
+	return nil
+}
+
+// serviceExportManifest returns the manifest of the ServiceExport which exports the service on the member cluster, so
+// that the availability of the work also tracks whether the service is imported by the fleet networking agents.
+func serviceExportManifest(service *unstructured.Unstructured) (fleetv1beta1.Manifest, error) {
+	raw, err := utils.NewServiceExport(service).MarshalJSON()
+	if err != nil {
+		return fleetv1beta1.Manifest{}, err
+	}
+	return fleetv1beta1.Manifest{RawExtension: runtime.RawExtension{Raw: bytes.TrimSpace(raw)}}, nil
+}
+
 // extractFailedResourcePlacementsFromWork extracts the failed resource placements from the work.
 func extractFailedResourcePlacementsFromWork(work *fleetv1beta1.Work) []fleetv1beta1.FailedResourcePlacement {
 	appliedCond := meta.FindStatusCondition(work.Status.Conditions, fleetv1beta1.WorkConditionTypeApplied)
@@ -757,7 +1147,7 @@ func extractFailedResourcePlacementsFromWork(work *fleetv1beta1.Work) []fleetv1b
 	var envelopObjName, envelopObjNamespace string
 	if isEnveloped {
 		// If the work  generated by an enveloped object, it must contain those labels.
-		envelopObjName = work.GetLabels()[fleetv1beta1.EnvelopeNameLabel]
+		envelopObjName = envelopeObjName(work)
 		envelopObjNamespace = work.GetLabels()[fleetv1beta1.EnvelopeNamespaceLabel]
 	}
 	res := make([]fleetv1beta1.FailedResourcePlacement, 0, len(work.Status.ManifestConditions))
@@ -824,8 +1214,21 @@ func extractFailedResourcePlacementsFromWork(work *fleetv1beta1.Work) []fleetv1b
 // It watches binding events and also update/delete events for work.
 func (r *Reconciler) SetupWithManager(mgr controllerruntime.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor("work generator")
-	return controllerruntime.NewControllerManagedBy(mgr).Named("work-generator").
-		WithOptions(ctrl.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}). // set the max number of concurrent reconciles
+	if r.MemberWriteQPS > 0 {
+		r.memberWriteLimiter = newMemberWriteLimiter(r.MemberWriteQPS, r.MemberWriteBurst)
+	}
+	r.overrideCache = newOverrideRenderCache(r.OverrideRenderCacheSize)
+	if r.StatusBatchInterval > 0 {
+		r.statusWriter = newBindingStatusWriter(r.Client, r.StatusBatchInterval, r.MaxConcurrentReconciles)
+		if err := mgr.Add(r.statusWriter); err != nil {
+			return err
+		}
+	}
+	b := controllerruntime.NewControllerManagedBy(mgr).Named("work-generator").
+		WithOptions(ctrl.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles, // set the max number of concurrent reconciles
+			NeedLeaderElection:      sharding.ControllerLeaderElection(r.Sharder),
+		}).
 		For(&fleetv1beta1.ClusterResourceBinding{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&fleetv1beta1.Work{}, &handler.Funcs{
 			// we care about work delete event as we want to know when a work is deleted so that we can
@@ -879,9 +1282,10 @@ func (r *Reconciler) SetupWithManager(mgr controllerruntime.Manager) error {
 				oldAvailableStatus := meta.FindStatusCondition(oldWork.Status.Conditions, fleetv1beta1.WorkConditionTypeAvailable)
 				newAvailableStatus := meta.FindStatusCondition(newWork.Status.Conditions, fleetv1beta1.WorkConditionTypeAvailable)
 
-				// we only need to handle the case the applied or available condition is changed between the
-				// new and old work objects. Otherwise, it won't affect the binding applied condition
-				if condition.EqualCondition(oldAppliedStatus, newAppliedStatus) && condition.EqualCondition(oldAvailableStatus, newAvailableStatus) {
+				// we only need to handle the case the work starts being deleted, or the applied or available condition
+				// is changed between the new and old work objects. Otherwise, it won't affect the binding status
+				startedDeleting := oldWork.DeletionTimestamp == nil && newWork.DeletionTimestamp != nil
+				if !startedDeleting && condition.EqualCondition(oldAppliedStatus, newAppliedStatus) && condition.EqualCondition(oldAvailableStatus, newAvailableStatus) {
 					klog.V(2).InfoS("The work applied or available condition didn't flip between true and false, no need to reconcile", "oldWork", klog.KObj(oldWork), "newWork", klog.KObj(newWork))
 					return
 				}
@@ -891,6 +1295,41 @@ func (r *Reconciler) SetupWithManager(mgr controllerruntime.Manager) error {
 					Name: parentBindingName,
 				}})
 			},
-		}).
-		Complete(r)
+		})
+	// the works are generated or removed when the activation window of the placement changes
+	b = b.Watches(&fleetv1beta1.ClusterResourcePlacement{}, handler.EnqueueRequestsFromMapFunc(r.bindingsOfPlacement),
+		builder.WithPredicates(predicate.Funcs{
+			CreateFunc:  func(event.CreateEvent) bool { return false },
+			DeleteFunc:  func(event.DeleteEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldCRP, oldOK := e.ObjectOld.(*fleetv1beta1.ClusterResourcePlacement)
+				newCRP, newOK := e.ObjectNew.(*fleetv1beta1.ClusterResourcePlacement)
+				return oldOK && newOK && !equality.Semantic.DeepEqual(oldCRP.Spec.ActivationWindow, newCRP.Spec.ActivationWindow)
+			},
+		}))
+	if r.EnableMetadataOverrides {
+		// the works of any binding may select the resources of a metadata override
+		b = b.Watches(&placementv1alpha1.ClusterMetadataOverride{}, handler.EnqueueRequestsFromMapFunc(r.allBindings),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	}
+	if r.Sharder != nil {
+		// generate the works of the bindings of the placements that the replica takes over after a rebalance
+		b = b.WatchesRawSource(r.Sharder.Subscribe(handler.EnqueueRequestsFromMapFunc(r.bindingsOfPlacement)))
+	}
+	return b.Complete(r)
+}
+
+// bindingsOfPlacement returns the requests of the bindings of the placement.
+func (r *Reconciler) bindingsOfPlacement(ctx context.Context, crp client.Object) []reconcile.Request {
+	bindingList := &fleetv1beta1.ClusterResourceBindingList{}
+	if err := r.Client.List(ctx, bindingList, client.MatchingFields{index.BindingCRPField: crp.GetName()}); err != nil {
+		klog.ErrorS(err, "Failed to list the bindings of the clusterResourcePlacement", "clusterResourcePlacement", klog.KObj(crp))
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(bindingList.Items))
+	for i := range bindingList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bindingList.Items[i].Name}})
+	}
+	return requests
 }
@@ -19,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
@@ -183,6 +184,12 @@ var _ = Describe("Test Work Generator Controller", func() {
 						Reason:             condition.OverriddenFailedReason,
 						ObservedGeneration: binding.GetGeneration(),
 					},
+					{
+						Type:               string(placementv1beta1.ResourceBindingWorkSynchronized),
+						Status:             metav1.ConditionFalse,
+						Reason:             condition.OverrideRenderFailedReason,
+						ObservedGeneration: binding.GetGeneration(),
+					},
 				},
 			}
 			diff := cmp.Diff(wantStatus, binding.Status, ignoreConditionOption)
@@ -568,6 +575,10 @@ var _ = Describe("Test Work Generator Controller", func() {
 							placementv1beta1.EnvelopeTypeLabel:                string(placementv1beta1.ConfigMapEnvelopeType),
 							placementv1beta1.EnvelopeNameLabel:                "envelop-configmap",
 							placementv1beta1.EnvelopeNamespaceLabel:           "app",
+							placementv1beta1.EnvelopeHashLabel:                testEnvelopeHash(),
+						},
+						Annotations: map[string]string{
+							placementv1beta1.EnvelopeNameAnnotation: "envelop-configmap",
 						},
 					},
 					Spec: placementv1beta1.WorkSpec{
@@ -683,6 +694,10 @@ var _ = Describe("Test Work Generator Controller", func() {
 							placementv1beta1.EnvelopeTypeLabel:                string(placementv1beta1.ConfigMapEnvelopeType),
 							placementv1beta1.EnvelopeNameLabel:                "envelop-configmap",
 							placementv1beta1.EnvelopeNamespaceLabel:           "app",
+							placementv1beta1.EnvelopeHashLabel:                testEnvelopeHash(),
+						},
+						Annotations: map[string]string{
+							placementv1beta1.EnvelopeNameAnnotation: "envelop-configmap",
 						},
 					},
 					Spec: placementv1beta1.WorkSpec{
@@ -1284,6 +1299,12 @@ var _ = Describe("Test Work Generator Controller", func() {
 								Reason:             condition.OverriddenFailedReason,
 								ObservedGeneration: binding.GetGeneration(),
 							},
+							{
+								Type:               string(placementv1beta1.ResourceBindingWorkSynchronized),
+								Status:             metav1.ConditionFalse,
+								Reason:             condition.OverrideRenderFailedReason,
+								ObservedGeneration: binding.GetGeneration(),
+							},
 						},
 					}
 					return cmp.Diff(wantStatus, binding.Status, ignoreConditionOption)
@@ -1339,6 +1360,12 @@ var _ = Describe("Test Work Generator Controller", func() {
 								Reason:             condition.OverriddenFailedReason,
 								ObservedGeneration: binding.GetGeneration(),
 							},
+							{
+								Type:               string(placementv1beta1.ResourceBindingWorkSynchronized),
+								Status:             metav1.ConditionFalse,
+								Reason:             condition.OverrideRenderFailedReason,
+								ObservedGeneration: binding.GetGeneration(),
+							},
 						},
 					}
 					return cmp.Diff(wantStatus, binding.Status, ignoreConditionOption)
@@ -1377,6 +1404,18 @@ var _ = Describe("Test Work Generator Controller", func() {
 			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: binding.Name}, binding)).Should(Succeed())
 			Expect(len(binding.Finalizers)).Should(Equal(0))
 		})
+
+		It("Should report that the cluster is gone", func() {
+			Eventually(func() bool {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: binding.Name}, binding); err != nil {
+					return false
+				}
+				workSyncCond := binding.GetCondition(string(placementv1beta1.ResourceBindingWorkSynchronized))
+				clusterGoneCond := binding.GetCondition(string(placementv1beta1.ResourceBindingClusterGone))
+				return workSyncCond != nil && workSyncCond.Status == metav1.ConditionFalse && workSyncCond.Reason == condition.ClusterGoneReason &&
+					clusterGoneCond != nil && clusterGoneCond.Status == metav1.ConditionTrue
+			}, timeout, interval).Should(BeTrue(), "binding should report that the target cluster is gone")
+		})
 	})
 
 	// TODO: add a test for the apply strategy
@@ -1551,6 +1590,14 @@ func verifyBindStatusNotAppliedWithFailedPlacement(binding *placementv1beta1.Clu
 	}, timeout, interval).Should(BeEmpty(), fmt.Sprintf("binding(%s) mismatch (-want +got)", binding.Name))
 }
 
+// testEnvelopeHash returns the envelope hash of the envelope configMap in the snapshots.
+func testEnvelopeHash() string {
+	envelopeObj := &unstructured.Unstructured{}
+	envelopeObj.SetNamespace("app")
+	envelopeObj.SetName("envelop-configmap")
+	return envelopeHash(placementv1beta1.ConfigMapEnvelopeType, envelopeObj)
+}
+
 func fetchEnvelopedWork(workList *placementv1beta1.WorkList, binding *placementv1beta1.ClusterResourceBinding) {
 	// try to locate the work that contains enveloped object
 	Eventually(func() error {
@@ -6,6 +6,9 @@ Licensed under the MIT license.
 package workgenerator
 
 import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
 	"errors"
 	"testing"
 	"time"
@@ -13,14 +16,257 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/controllers/work"
 	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/manifestsealing"
 )
 
+// fakeSigner signs the digests by returning them as is.
+type fakeSigner struct {
+	keyID string
+}
+
+func (s *fakeSigner) KeyID() string {
+	return s.keyID
+}
+
+func (s *fakeSigner) Sign(_ context.Context, digest []byte) ([]byte, error) {
+	return digest, nil
+}
+
+func TestUpsertWorkSignsWork(t *testing.T) {
+	signer := &fakeSigner{keyID: "key-1"}
+	resourceSnapshot := &fleetv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "crp-1-snapshot",
+			Labels: map[string]string{fleetv1beta1.ResourceIndexLabel: "1"},
+		},
+	}
+	newWork := func(annotations map[string]string) *fleetv1beta1.Work {
+		return &fleetv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "crp-work",
+				Namespace:   "fleet-member-cluster-1",
+				Labels:      map[string]string{fleetv1beta1.ParentResourceSnapshotIndexLabel: "1"},
+				Annotations: annotations,
+			},
+			Spec: fleetv1beta1.WorkSpec{
+				Workload: fleetv1beta1.WorkloadTemplate{
+					Manifests: []fleetv1beta1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)}}},
+				},
+			},
+		}
+	}
+	signedAnnotations := map[string]string{
+		fleetv1beta1.WorkSignatureAnnotation:      "c2lnbmF0dXJl",
+		fleetv1beta1.WorkSignatureKeyIDAnnotation: "key-1",
+	}
+	tests := map[string]struct {
+		existingWork *fleetv1beta1.Work
+		wantUpdated  bool
+		wantKeyID    string
+	}{
+		"work is created": {
+			wantUpdated: true,
+			wantKeyID:   "key-1",
+		},
+		"existing work is not signed": {
+			existingWork: newWork(nil),
+			wantUpdated:  true,
+			wantKeyID:    "key-1",
+		},
+		"existing work is signed by another key": {
+			existingWork: newWork(map[string]string{
+				fleetv1beta1.WorkSignatureAnnotation:      "c2lnbmF0dXJl",
+				fleetv1beta1.WorkSignatureKeyIDAnnotation: "key-0",
+			}),
+			wantUpdated: true,
+			wantKeyID:   "key-1",
+		},
+		"existing work is already signed": {
+			existingWork: newWork(signedAnnotations),
+			wantUpdated:  false,
+			wantKeyID:    "key-1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the scheme: %v", err)
+			}
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tc.existingWork != nil {
+				builder = builder.WithObjects(tc.existingWork)
+			}
+			r := &Reconciler{Client: builder.Build(), Signer: signer}
+			var existingWork *fleetv1beta1.Work
+			if tc.existingWork != nil {
+				existingWork = &fleetv1beta1.Work{}
+				if err := r.Client.Get(context.Background(), types.NamespacedName{Namespace: tc.existingWork.Namespace, Name: tc.existingWork.Name}, existingWork); err != nil {
+					t.Fatalf("failed to get the existing work: %v", err)
+				}
+			}
+			updated, err := r.upsertWork(context.Background(), newWork(nil), existingWork, resourceSnapshot)
+			if err != nil {
+				t.Fatalf("upsertWork() = %v, want nil", err)
+			}
+			if updated != tc.wantUpdated {
+				t.Errorf("upsertWork() updated = %v, want %v", updated, tc.wantUpdated)
+			}
+			got := &fleetv1beta1.Work{}
+			if err := r.Client.Get(context.Background(), types.NamespacedName{Namespace: "fleet-member-cluster-1", Name: "crp-work"}, got); err != nil {
+				t.Fatalf("failed to get the work: %v", err)
+			}
+			if gotKeyID := got.Annotations[fleetv1beta1.WorkSignatureKeyIDAnnotation]; gotKeyID != tc.wantKeyID {
+				t.Errorf("work signature key ID = %q, want %q", gotKeyID, tc.wantKeyID)
+			}
+			if got.Annotations[fleetv1beta1.WorkSignatureAnnotation] == "" {
+				t.Errorf("work signature is empty, want a signature")
+			}
+		})
+	}
+}
+
+func TestSealManifest(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	raw := []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"db-password","namespace":"app"},"data":{"password":"c2VjcmV0"}}`)
+	tests := map[string]struct {
+		publicKey string
+		wantErr   error
+	}{
+		"secret is sealed with the key of the member cluster": {
+			publicKey: manifestsealing.EncodePublicKey(key.PublicKey()),
+		},
+		"member cluster has not published its key": {
+			wantErr: controller.ErrUserError,
+		},
+		"member cluster has an invalid key": {
+			publicKey: "c2VjcmV0",
+			wantErr:   controller.ErrUnexpectedBehavior,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster := &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "member-1"},
+				Status:     clusterv1beta1.MemberClusterStatus{ManifestEncryptionPublicKey: tc.publicKey},
+			}
+			selectedResource := fleetv1beta1.ResourceContent{RawExtension: runtime.RawExtension{Raw: raw}}
+			var uResource unstructured.Unstructured
+			if err := uResource.UnmarshalJSON(raw); err != nil {
+				t.Fatalf("failed to decode the secret: %v", err)
+			}
+			keyID, err := sealManifest(&selectedResource, &uResource, cluster)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("sealManifest() = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sealManifest() = %v, want nil", err)
+			}
+			if want := manifestsealing.KeyID(key.PublicKey()); keyID != want {
+				t.Errorf("sealManifest() key ID = %q, want %q", keyID, want)
+			}
+			var sealed unstructured.Unstructured
+			if err := sealed.UnmarshalJSON(selectedResource.Raw); err != nil {
+				t.Fatalf("failed to decode the sealed secret: %v", err)
+			}
+			if !manifestsealing.IsSealed(&sealed) {
+				t.Fatalf("sealManifest() did not seal the secret")
+			}
+			if err := manifestsealing.Unseal(&sealed, key); err != nil {
+				t.Fatalf("Unseal() = %v, want nil", err)
+			}
+			if _, found, _ := unstructured.NestedString(sealed.Object, "data", manifestsealing.SealedDataKey); found {
+				t.Errorf("unsealed secret still has the sealed data")
+			}
+			if got, _, _ := unstructured.NestedString(sealed.Object, "data", "password"); got != "c2VjcmV0" {
+				t.Errorf("unsealed password = %q, want %q", got, "c2VjcmV0")
+			}
+		})
+	}
+}
+
+func TestBindFluxSource(t *testing.T) {
+	kustomization := `{"apiVersion":"kustomize.toolkit.fluxcd.io/v1","kind":"Kustomization","metadata":{"name":"podinfo","namespace":"app"},"spec":{"path":"./kustomize"}}`
+	boundKustomization := `{"apiVersion":"kustomize.toolkit.fluxcd.io/v1","kind":"Kustomization","metadata":{"name":"podinfo","namespace":"app"},"spec":{"path":"./kustomize","sourceRef":{"kind":"GitRepository","name":"podinfo"}}}`
+	configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"values","namespace":"app"}}`
+	tests := map[string]struct {
+		sourceRef string
+		manifests []string
+		want      []string
+		wantErr   bool
+	}{
+		"kustomization reconciles the source of the envelope": {
+			sourceRef: "GitRepository/podinfo",
+			manifests: []string{kustomization, configMap},
+			want:      []string{boundKustomization, configMap},
+		},
+		"invalid source reference": {
+			sourceRef: "GitRepository",
+			manifests: []string{kustomization},
+			wantErr:   true,
+		},
+		"kustomization references another source": {
+			sourceRef: "OCIRepository/podinfo",
+			manifests: []string{boundKustomization},
+			wantErr:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			manifests := make([]fleetv1beta1.Manifest, len(tc.manifests))
+			for i := range tc.manifests {
+				manifests[i] = fleetv1beta1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(tc.manifests[i])}}
+			}
+			err := bindFluxSource(manifests, tc.sourceRef)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("bindFluxSource() = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			got := make([]string, len(manifests))
+			for i := range manifests {
+				got[i] = string(manifests[i].Raw)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("bindFluxSource() manifests mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServiceExportManifest(t *testing.T) {
+	var service unstructured.Unstructured
+	if err := service.UnmarshalJSON([]byte(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"web","namespace":"app","annotations":{"kubernetes-fleet.io/service-export":"true"}},"spec":{"ports":[{"port":80}]}}`)); err != nil {
+		t.Fatalf("failed to unmarshal the service: %v", err)
+	}
+	got, err := serviceExportManifest(&service)
+	if err != nil {
+		t.Fatalf("serviceExportManifest() = %v, want nil", err)
+	}
+	want := `{"apiVersion":"networking.fleet.azure.com/v1alpha1","kind":"ServiceExport","metadata":{"name":"web","namespace":"app"}}`
+	if diff := cmp.Diff(want, string(got.Raw)); diff != "" {
+		t.Errorf("serviceExportManifest() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
 func TestGetWorkNamePrefixFromSnapshotName(t *testing.T) {
 	tests := map[string]struct {
 		resourceSnapshot *fleetv1beta1.ClusterResourceSnapshot
@@ -432,6 +678,92 @@ func TestBuildAllWorkAvailableCondition(t *testing.T) {
 				ObservedGeneration: 1,
 			},
 		},
+		"One work is provisioning": {
+			works: map[string]*fleetv1beta1.Work{
+				"work1": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "work1",
+					},
+					Status: fleetv1beta1.WorkStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   fleetv1beta1.WorkConditionTypeAvailable,
+								Reason: "any",
+								Status: metav1.ConditionTrue,
+							},
+						},
+					},
+				},
+				"work2": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "work2",
+					},
+					Status: fleetv1beta1.WorkStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   fleetv1beta1.WorkConditionTypeAvailable,
+								Reason: work.WorkProvisioningReason,
+								Status: metav1.ConditionFalse,
+							},
+						},
+					},
+				},
+			},
+			binding: &fleetv1beta1.ClusterResourceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+			},
+			want: metav1.Condition{
+				Status:             metav1.ConditionFalse,
+				Type:               string(fleetv1beta1.ResourceBindingAvailable),
+				Reason:             condition.ProvisioningReason,
+				ObservedGeneration: 1,
+			},
+		},
+		"One work is provisioning and another is not available": {
+			works: map[string]*fleetv1beta1.Work{
+				"work1": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "work1",
+					},
+					Status: fleetv1beta1.WorkStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   fleetv1beta1.WorkConditionTypeAvailable,
+								Reason: work.WorkProvisioningReason,
+								Status: metav1.ConditionFalse,
+							},
+						},
+					},
+				},
+				"work2": {
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "work2",
+					},
+					Status: fleetv1beta1.WorkStatus{
+						Conditions: []metav1.Condition{
+							{
+								Type:   fleetv1beta1.WorkConditionTypeAvailable,
+								Reason: "any",
+								Status: metav1.ConditionFalse,
+							},
+						},
+					},
+				},
+			},
+			binding: &fleetv1beta1.ClusterResourceBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 1,
+				},
+			},
+			want: metav1.Condition{
+				Status:             metav1.ConditionFalse,
+				Type:               string(fleetv1beta1.ResourceBindingAvailable),
+				Reason:             condition.WorkNotAvailableReason,
+				ObservedGeneration: 1,
+			},
+		},
 		"Available condition of one work is unknown": {
 			works: map[string]*fleetv1beta1.Work{
 				"work1": {
@@ -485,6 +817,7 @@ func TestSetBindingStatus(t *testing.T) {
 		works                           map[string]*fleetv1beta1.Work
 		maxFailedResourcePlacementLimit *int
 		want                            []fleetv1beta1.FailedResourcePlacement
+		wantOverflow                    int32
 	}{
 		"NoWorks": {
 			works: map[string]*fleetv1beta1.Work{},
@@ -819,6 +1152,7 @@ func TestSetBindingStatus(t *testing.T) {
 				},
 			},
 			maxFailedResourcePlacementLimit: ptr.To(1),
+			wantOverflow:                    1,
 			want: []fleetv1beta1.FailedResourcePlacement{
 				{
 					ResourceIdentifier: fleetv1beta1.ResourceIdentifier{
@@ -970,44 +1304,53 @@ func TestSetBindingStatus(t *testing.T) {
 	}()
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			maxFailedResourcePlacementLimit = originalMaxFailedResourcePlacementLimit
 			if tt.maxFailedResourcePlacementLimit != nil {
 				maxFailedResourcePlacementLimit = *tt.maxFailedResourcePlacementLimit
 			}
 			binding := &fleetv1beta1.ClusterResourceBinding{}
 			setBindingStatus(tt.works, binding)
+			// the failed placements are sorted, so that the same ones are reported no matter the order of the works
 			got := binding.Status.FailedPlacements
-			// setBindingStatus is using map to populate the failedResourcePlacement.
-			// There is no default order in traversing the map.
-			// When the result of failedResourcePlacement exceeds the limit, the result will be truncated and cannot be
-			// guaranteed.
-			if maxFailedResourcePlacementLimit == len(tt.want) {
-				opt := cmp.Comparer(func(x, y fleetv1beta1.FailedResourcePlacement) bool {
-					return x.Condition.Status == y.Condition.Status // condition should be set as false
-				})
-				if diff := cmp.Diff(got, tt.want, opt); diff != "" {
-					t.Errorf("setBindingStatus got FailedPlacements mismatch (-got +want):\n%s", diff)
-				}
-				return
-			}
-
-			statusCmpOptions := []cmp.Option{
-				cmpopts.SortSlices(func(i, j fleetv1beta1.FailedResourcePlacement) bool {
-					if i.Group < j.Group {
-						return true
-					}
-					if i.Kind < j.Kind {
-						return true
-					}
-					return i.Name < j.Name
-				}),
-			}
-			if diff := cmp.Diff(got, tt.want, statusCmpOptions...); diff != "" {
+			if diff := cmp.Diff(got, tt.want); diff != "" {
 				t.Errorf("setBindingStatus got FailedPlacements mismatch (-got +want):\n%s", diff)
 			}
+			if binding.Status.FailedPlacementsOverflow != tt.wantOverflow {
+				t.Errorf("setBindingStatus got FailedPlacementsOverflow %d, want %d", binding.Status.FailedPlacementsOverflow, tt.wantOverflow)
+			}
 		})
 	}
 }
 
+func TestSortFailedResourcePlacements(t *testing.T) {
+	failed := func(group, kind, namespace, name string, envelope *fleetv1beta1.EnvelopeIdentifier, conditionType string) fleetv1beta1.FailedResourcePlacement {
+		return fleetv1beta1.FailedResourcePlacement{
+			ResourceIdentifier: fleetv1beta1.ResourceIdentifier{
+				Group: group, Version: "v1", Kind: kind, Namespace: namespace, Name: name, Envelope: envelope,
+			},
+			Condition: metav1.Condition{Type: conditionType, Status: metav1.ConditionFalse},
+		}
+	}
+	envelope := &fleetv1beta1.EnvelopeIdentifier{Name: "envelope", Namespace: "app", Type: fleetv1beta1.ConfigMapEnvelopeType}
+	want := []fleetv1beta1.FailedResourcePlacement{
+		failed("", "ConfigMap", "app", "config", nil, fleetv1beta1.WorkConditionTypeApplied),
+		failed("", "ConfigMap", "app", "config", envelope, fleetv1beta1.WorkConditionTypeApplied),
+		failed("", "ConfigMap", "app", "config", envelope, fleetv1beta1.WorkConditionTypeAvailable),
+		failed("", "ConfigMap", "other", "config", nil, fleetv1beta1.WorkConditionTypeAvailable),
+		failed("", "Service", "app", "svc", nil, fleetv1beta1.WorkConditionTypeApplied),
+		failed("apps", "Deployment", "app", "deploy-a", nil, fleetv1beta1.WorkConditionTypeAvailable),
+		failed("apps", "Deployment", "app", "deploy-b", nil, fleetv1beta1.WorkConditionTypeApplied),
+	}
+	for i := 0; i < len(want); i++ {
+		// every rotation of the list is sorted into the same order
+		got := append(append([]fleetv1beta1.FailedResourcePlacement{}, want[i:]...), want[:i]...)
+		sortFailedResourcePlacements(got)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("sortFailedResourcePlacements() of rotation %d mismatch (-want, +got):\n%s", i, diff)
+		}
+	}
+}
+
 func TestExtractFailedResourcePlacementsFromWork(t *testing.T) {
 	var statusCmpOptions = []cmp.Option{
 		// ignore the message as we may change the message in the future
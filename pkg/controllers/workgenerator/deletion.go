@@ -0,0 +1,159 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/index"
+)
+
+// isDeletedInReverseOrder tells if the deleting binding belongs to a deleting placement with the ReverseOrder
+// deletion strategy, whose works are deleted one wave at a time.
+func (r *Reconciler) isDeletedInReverseOrder(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (bool, error) {
+	crpName := resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel]
+	if crpName == "" {
+		return false, nil
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: crpName}, crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		klog.ErrorS(err, "Failed to get the clusterResourcePlacement of the deleting binding", "resourceBinding", klog.KObj(resourceBinding))
+		return false, controller.NewAPIServerError(true, err)
+	}
+	return crp.DeletionTimestamp != nil && utils.IsReverseOrderDeletion(crp), nil
+}
+
+// deleteWorksInReverseOrder deletes the works of the deleting binding in waves: the works of the envelopes first,
+// which hold the workloads, then the works of the resource snapshots in the reverse order of their sub-indexes, as the
+// selected resources are sorted with the namespaces and the custom resource definitions first. A work is marked for
+// the member agent to delete its resources in the reverse order of their dependencies too, and the next wave starts
+// once the works of the current wave are gone. The work finalizer is kept on the binding until all its works are gone,
+// so that the placement controller starts the deletion of the next clusters after the resources are deleted.
+func (r *Reconciler) deleteWorksInReverseOrder(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) (controllerruntime.Result, error) {
+	bindingRef := klog.KObj(resourceBinding)
+	workList := &fleetv1beta1.WorkList{}
+	if err := r.Client.List(ctx, workList, client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, resourceBinding.Spec.TargetCluster)),
+		client.MatchingFields{index.WorkBindingField: resourceBinding.Name}); err != nil {
+		klog.ErrorS(err, "Failed to list all the work associated with the resource binding", "resourceBinding", bindingRef)
+		return controllerruntime.Result{}, controller.NewAPIServerError(true, err)
+	}
+	if len(workList.Items) == 0 {
+		return controllerruntime.Result{}, r.removeWorkFinalizer(ctx, resourceBinding)
+	}
+	if err := r.updateResourcesDeletedCondition(ctx, resourceBinding, len(workList.Items)); err != nil {
+		return controllerruntime.Result{}, err
+	}
+	for i := range workList.Items {
+		if workList.Items[i].DeletionTimestamp != nil {
+			klog.V(2).InfoS("Waiting for the works of the current deletion wave to be deleted", "resourceBinding", bindingRef, "work", klog.KObj(&workList.Items[i]))
+			// we watch the work objects deleting events, so we can afford to wait a bit longer here as a fallback case.
+			return controllerruntime.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+	}
+
+	crpName := resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel]
+	works := workList.Items
+	sort.Slice(works, func(i, j int) bool {
+		rank1, rank2 := workDeletionRank(&works[i], crpName), workDeletionRank(&works[j], crpName)
+		if rank1 != rank2 {
+			return rank1 > rank2
+		}
+		return works[i].Name < works[j].Name
+	})
+	firstRank := workDeletionRank(&works[0], crpName)
+	for i := range works {
+		work := &works[i]
+		if workDeletionRank(work, crpName) != firstRank {
+			break
+		}
+		if work.Annotations[fleetv1beta1.DeletionStrategyAnnotation] != string(fleetv1beta1.DeletionStrategyTypeReverseOrder) {
+			if work.Annotations == nil {
+				work.Annotations = make(map[string]string)
+			}
+			work.Annotations[fleetv1beta1.DeletionStrategyAnnotation] = string(fleetv1beta1.DeletionStrategyTypeReverseOrder)
+			if err := r.Client.Update(ctx, work); err != nil {
+				klog.ErrorS(err, "Failed to mark the work to be deleted in the reverse order", "resourceBinding", bindingRef, "work", klog.KObj(work))
+				return controllerruntime.Result{}, controller.NewUpdateIgnoreConflictError(err)
+			}
+		}
+		if err := r.Client.Delete(ctx, work); err != nil && !apierrors.IsNotFound(err) {
+			return controllerruntime.Result{}, controller.NewAPIServerError(false, err)
+		}
+		klog.V(2).InfoS("Deleted a work in the reverse order", "resourceBinding", bindingRef, "work", klog.KObj(work))
+	}
+	return controllerruntime.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// workDeletionRank returns the rank of a work of the placement in the deletion order; the works of higher ranks are
+// deleted first. The works of the envelopes rank the highest, followed by the works of the resource snapshots by
+// their sub-indexes, and the first work ranks the lowest.
+func workDeletionRank(work *fleetv1beta1.Work, crpName string) int {
+	if _, isEnvelope := work.Labels[fleetv1beta1.EnvelopeTypeLabel]; isEnvelope {
+		return math.MaxInt
+	}
+	if subIndex, err := strconv.Atoi(strings.TrimPrefix(work.Name, crpName+"-")); err == nil && strings.HasPrefix(work.Name, crpName+"-") {
+		return subIndex
+	}
+	return -1
+}
+
+// updateResourcesDeletedCondition writes the ResourcesDeleted condition of the deleting binding, whose remaining works
+// are all being deleted.
+func (r *Reconciler) updateResourcesDeletedCondition(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding, remainingWorks int) error {
+	original := resourceBinding.DeepCopy()
+	setResourcesDeletedCondition(resourceBinding, remainingWorks)
+	if err := r.updateBindingStatus(ctx, original, resourceBinding); err != nil {
+		klog.ErrorS(err, "Failed to update the resourceBinding status", "resourceBinding", klog.KObj(resourceBinding))
+		return err
+	}
+	return nil
+}
+
+// setResourcesDeletedCondition sets the ResourcesDeleted condition of the binding by the number of its works which are
+// still being deleted. The condition is only set once some works of the binding are deleted, e.g. as their resources
+// are no longer selected, and it stays true after all of them are gone.
+func setResourcesDeletedCondition(resourceBinding *fleetv1beta1.ClusterResourceBinding, deletingWorks int) {
+	if deletingWorks > 0 {
+		resourceBinding.SetConditions(metav1.Condition{
+			Status:             metav1.ConditionFalse,
+			Type:               string(fleetv1beta1.ResourceBindingResourcesDeleted),
+			Reason:             condition.ResourcesDeletingReason,
+			Message:            fmt.Sprintf("%d works are still being deleted along with their resources in the member cluster %s", deletingWorks, resourceBinding.Spec.TargetCluster),
+			ObservedGeneration: resourceBinding.Generation,
+		})
+		return
+	}
+	if resourceBinding.GetCondition(string(fleetv1beta1.ResourceBindingResourcesDeleted)) == nil {
+		return
+	}
+	resourceBinding.SetConditions(metav1.Condition{
+		Status:             metav1.ConditionTrue,
+		Type:               string(fleetv1beta1.ResourceBindingResourcesDeleted),
+		Reason:             condition.ResourcesDeletedReason,
+		Message:            fmt.Sprintf("All the works removed from the binding are deleted along with their resources in the member cluster %s", resourceBinding.Spec.TargetCluster),
+		ObservedGeneration: resourceBinding.Generation,
+	})
+}
@@ -0,0 +1,218 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/index"
+)
+
+func TestWorkDeletionRank(t *testing.T) {
+	tests := map[string]struct {
+		work *fleetv1beta1.Work
+		want int
+	}{
+		"first work": {
+			work: &fleetv1beta1.Work{ObjectMeta: metav1.ObjectMeta{Name: "crp-1-work"}},
+			want: -1,
+		},
+		"work of a sub-index": {
+			work: &fleetv1beta1.Work{ObjectMeta: metav1.ObjectMeta{Name: "crp-1-2"}},
+			want: 2,
+		},
+		"work of an envelope": {
+			work: &fleetv1beta1.Work{ObjectMeta: metav1.ObjectMeta{
+				Name:   "crp-1-work-configmap-6c1dd1a7",
+				Labels: map[string]string{fleetv1beta1.EnvelopeTypeLabel: string(fleetv1beta1.ConfigMapEnvelopeType)},
+			}},
+			want: math.MaxInt,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := workDeletionRank(tc.work, "crp-1"); got != tc.want {
+				t.Errorf("workDeletionRank() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteWorksInReverseOrder(t *testing.T) {
+	now := metav1.Now()
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "crp-1",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{fleetv1beta1.ClusterResourcePlacementCleanupFinalizer},
+		},
+		Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+			Strategy: fleetv1beta1.RolloutStrategy{
+				DeletionStrategy: &fleetv1beta1.DeletionStrategy{Type: fleetv1beta1.DeletionStrategyTypeReverseOrder},
+			},
+		},
+	}
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "crp-1-member-1",
+			Labels:            map[string]string{fleetv1beta1.CRPTrackingLabel: "crp-1"},
+			DeletionTimestamp: &now,
+			Finalizers:        []string{fleetv1beta1.WorkFinalizer},
+		},
+		Spec: fleetv1beta1.ResourceBindingSpec{TargetCluster: "member-1"},
+	}
+	newWork := func(name string, labels map[string]string) *fleetv1beta1.Work {
+		workLabels := map[string]string{fleetv1beta1.ParentBindingLabel: binding.Name}
+		for k, v := range labels {
+			workLabels[k] = v
+		}
+		return &fleetv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "fleet-member-member-1",
+				Labels:     workLabels,
+				Finalizers: []string{fleetv1beta1.WorkFinalizer},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(crp, binding,
+			newWork("crp-1-work", nil),
+			newWork("crp-1-1", nil),
+			newWork("crp-1-work-configmap-6c1dd1a7", map[string]string{fleetv1beta1.EnvelopeTypeLabel: string(fleetv1beta1.ConfigMapEnvelopeType)}),
+		).
+		WithIndex(&fleetv1beta1.Work{}, index.WorkBindingField, index.WorkBinding).
+		Build()
+	// the fake client does not support server-side apply, so the applied binding status is recorded instead
+	var appliedStatus fleetv1beta1.ResourceBindingStatus
+	r := &Reconciler{Client: interceptor.NewClient(fakeClient, interceptor.Funcs{
+		SubResourcePatch: func(_ context.Context, _ client.Client, _ string, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+			appliedStatus = obj.(*fleetv1beta1.ClusterResourceBinding).Status
+			return nil
+		},
+	})}
+	ctx := context.Background()
+
+	reverseOrder, err := r.isDeletedInReverseOrder(ctx, binding)
+	if err != nil || !reverseOrder {
+		t.Fatalf("isDeletedInReverseOrder() = %v, %v, want true, nil", reverseOrder, err)
+	}
+	deleteWave := func(wantDeleting []string, wantRemaining int) {
+		if _, err := r.deleteWorksInReverseOrder(ctx, binding); err != nil {
+			t.Fatalf("deleteWorksInReverseOrder() = %v, want nil", err)
+		}
+		wantCond := metav1.Condition{
+			Type:    string(fleetv1beta1.ResourceBindingResourcesDeleted),
+			Status:  metav1.ConditionFalse,
+			Reason:  condition.ResourcesDeletingReason,
+			Message: fmt.Sprintf("%d works are still being deleted along with their resources in the member cluster member-1", wantRemaining),
+		}
+		gotCond := meta.FindStatusCondition(appliedStatus.Conditions, wantCond.Type)
+		if diff := cmp.Diff(&wantCond, gotCond, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+			t.Errorf("ResourcesDeleted condition mismatch (-want, +got):\n%s", diff)
+		}
+		workList := &fleetv1beta1.WorkList{}
+		if err := fakeClient.List(ctx, workList); err != nil {
+			t.Fatalf("failed to list the works: %v", err)
+		}
+		var deleting []string
+		for i := range workList.Items {
+			work := &workList.Items[i]
+			if work.DeletionTimestamp == nil {
+				continue
+			}
+			deleting = append(deleting, work.Name)
+			if got := work.Annotations[fleetv1beta1.DeletionStrategyAnnotation]; got != string(fleetv1beta1.DeletionStrategyTypeReverseOrder) {
+				t.Errorf("deletion strategy annotation of the work %s = %q, want ReverseOrder", work.Name, got)
+			}
+			// the member agent deletes the resources of the work and removes its finalizer
+			work.Finalizers = nil
+			if err := fakeClient.Update(ctx, work); err != nil {
+				t.Fatalf("failed to remove the finalizer of the work: %v", err)
+			}
+		}
+		if diff := cmp.Diff(wantDeleting, deleting); diff != "" {
+			t.Errorf("deleting works mismatch (-want, +got):\n%s", diff)
+		}
+	}
+	deleteWave([]string{"crp-1-work-configmap-6c1dd1a7"}, 3)
+	deleteWave([]string{"crp-1-1"}, 2)
+	deleteWave([]string{"crp-1-work"}, 1)
+
+	// all the works are gone so that the finalizer of the binding is removed
+	if _, err := r.deleteWorksInReverseOrder(ctx, binding); err != nil {
+		t.Fatalf("deleteWorksInReverseOrder() = %v, want nil", err)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: binding.Name}, &fleetv1beta1.ClusterResourceBinding{}); err == nil {
+		t.Errorf("get the binding = nil, want not found")
+	}
+}
+
+func TestSetResourcesDeletedCondition(t *testing.T) {
+	deleting := metav1.Condition{
+		Type:    string(fleetv1beta1.ResourceBindingResourcesDeleted),
+		Status:  metav1.ConditionFalse,
+		Reason:  condition.ResourcesDeletingReason,
+		Message: "2 works are still being deleted along with their resources in the member cluster member-1",
+	}
+	deleted := metav1.Condition{
+		Type:    string(fleetv1beta1.ResourceBindingResourcesDeleted),
+		Status:  metav1.ConditionTrue,
+		Reason:  condition.ResourcesDeletedReason,
+		Message: "All the works removed from the binding are deleted along with their resources in the member cluster member-1",
+	}
+	tests := map[string]struct {
+		conditions    []metav1.Condition
+		deletingWorks int
+		want          []metav1.Condition
+	}{
+		"no works have been deleted": {},
+		"some works are being deleted": {
+			deletingWorks: 2,
+			want:          []metav1.Condition{deleting},
+		},
+		"all the deleting works are gone": {
+			conditions: []metav1.Condition{deleting},
+			want:       []metav1.Condition{deleted},
+		},
+		"the works are deleted again": {
+			conditions:    []metav1.Condition{deleted},
+			deletingWorks: 2,
+			want:          []metav1.Condition{deleting},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			binding := &fleetv1beta1.ClusterResourceBinding{
+				Spec:   fleetv1beta1.ResourceBindingSpec{TargetCluster: "member-1"},
+				Status: fleetv1beta1.ResourceBindingStatus{Conditions: tc.conditions},
+			}
+			setResourcesDeletedCondition(binding, tc.deletingWorks)
+			if diff := cmp.Diff(tc.want, binding.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("setResourcesDeletedCondition() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
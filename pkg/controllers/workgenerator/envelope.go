@@ -0,0 +1,105 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/index"
+)
+
+// envelopeHashLength is the number of the hex characters of the envelope hash, i.e., 128 bits of the sha-256 hash.
+const envelopeHashLength = 32
+
+// envelopeHash returns the hash of the type, namespace and name of the envelope object, which tells apart the envelope
+// objects of the same name in different namespaces and fits in the name and the labels of a work however long the
+// name of the envelope object is.
+func envelopeHash(envelopeType fleetv1beta1.EnvelopeType, envelopeObj *unstructured.Unstructured) string {
+	// the namespaces and names of the objects cannot contain "/"
+	sum := sha256.Sum256([]byte(strings.Join([]string{string(envelopeType), envelopeObj.GetNamespace(), envelopeObj.GetName()}, "/")))
+	return hex.EncodeToString(sum[:])[:envelopeHashLength]
+}
+
+// envelopeWorkName returns the name of the work generated from the envelope object.
+func envelopeWorkName(workNamePrefix string, envelopeType fleetv1beta1.EnvelopeType, envelopeObj *unstructured.Unstructured) string {
+	return fmt.Sprintf(fleetv1beta1.WorkNameWithConfigEnvelopeFmt, workNamePrefix, envelopeHash(envelopeType, envelopeObj))
+}
+
+// setEnvelopeIdentity labels and annotates the work with the identity of the envelope object it is generated from.
+// The name of the envelope object is only set in the label if it is a valid label value.
+func setEnvelopeIdentity(work *fleetv1beta1.Work, envelopeType fleetv1beta1.EnvelopeType, envelopeObj *unstructured.Unstructured) {
+	if work.Labels == nil {
+		work.Labels = map[string]string{}
+	}
+	work.Labels[fleetv1beta1.EnvelopeTypeLabel] = string(envelopeType)
+	work.Labels[fleetv1beta1.EnvelopeNamespaceLabel] = envelopeObj.GetNamespace()
+	work.Labels[fleetv1beta1.EnvelopeHashLabel] = envelopeHash(envelopeType, envelopeObj)
+	if len(validation.IsValidLabelValue(envelopeObj.GetName())) == 0 {
+		work.Labels[fleetv1beta1.EnvelopeNameLabel] = envelopeObj.GetName()
+	} else {
+		delete(work.Labels, fleetv1beta1.EnvelopeNameLabel)
+	}
+	if work.Annotations == nil {
+		work.Annotations = map[string]string{}
+	}
+	work.Annotations[fleetv1beta1.EnvelopeNameAnnotation] = envelopeObj.GetName()
+}
+
+// envelopeObjName returns the name of the envelope object which the work is generated from.
+func envelopeObjName(work *fleetv1beta1.Work) string {
+	if name, ok := work.Annotations[fleetv1beta1.EnvelopeNameAnnotation]; ok {
+		return name
+	}
+	// the works generated before the name is annotated
+	return work.Labels[fleetv1beta1.EnvelopeNameLabel]
+}
+
+// listEnvelopeWorks lists the works of the binding generated from the envelope object by the envelope hash.
+// The works generated before the works are labeled with the envelope hash are looked up by the name and namespace of
+// the envelope object instead; they keep their names and are labeled with the hash once they are updated, so that the
+// resources in them are not deleted and created again on the member cluster.
+func (r *Reconciler) listEnvelopeWorks(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding,
+	envelopeType fleetv1beta1.EnvelopeType, envelopeObj *unstructured.Unstructured) ([]fleetv1beta1.Work, error) {
+	parentBindingMatcher := client.MatchingFields{
+		index.WorkBindingField: resourceBinding.Name,
+	}
+	crpName := resourceBinding.Labels[fleetv1beta1.CRPTrackingLabel]
+	workList := &fleetv1beta1.WorkList{}
+	if err := r.Client.List(ctx, workList, parentBindingMatcher, client.MatchingLabels{
+		fleetv1beta1.CRPTrackingLabel:  crpName,
+		fleetv1beta1.EnvelopeHashLabel: envelopeHash(envelopeType, envelopeObj),
+	}); err != nil {
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	if len(workList.Items) > 0 || len(validation.IsValidLabelValue(envelopeObj.GetName())) > 0 {
+		// the envelope objects whose names are not valid label values never had works labeled with their names
+		return workList.Items, nil
+	}
+	if err := r.Client.List(ctx, workList, parentBindingMatcher, client.MatchingLabels{
+		fleetv1beta1.CRPTrackingLabel:       crpName,
+		fleetv1beta1.EnvelopeTypeLabel:      string(envelopeType),
+		fleetv1beta1.EnvelopeNameLabel:      envelopeObj.GetName(),
+		fleetv1beta1.EnvelopeNamespaceLabel: envelopeObj.GetNamespace(),
+	}); err != nil {
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	if len(workList.Items) > 0 {
+		klog.V(2).InfoS("Found the works of the envelope object without the envelope hash", "resourceBinding", klog.KObj(resourceBinding),
+			"envelopeObj", klog.KObj(envelopeObj), "numberOfWorks", len(workList.Items))
+	}
+	return workList.Items, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/index"
+)
+
+func newEnvelopeObj(namespace, name string) *unstructured.Unstructured {
+	envelopeObj := &unstructured.Unstructured{}
+	envelopeObj.SetAPIVersion("v1")
+	envelopeObj.SetKind("ConfigMap")
+	envelopeObj.SetNamespace(namespace)
+	envelopeObj.SetName(name)
+	envelopeObj.SetAnnotations(map[string]string{fleetv1beta1.EnvelopeConfigMapAnnotation: "true"})
+	envelopeObj.Object["data"] = map[string]interface{}{
+		"namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: app\n",
+	}
+	return envelopeObj
+}
+
+func TestEnvelopeWorkName(t *testing.T) {
+	longName := strings.Repeat("a", validation.DNS1123SubdomainMaxLength)
+	names := map[string]string{
+		"app/envelope":   envelopeWorkName("crp-1-work", fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("app", "envelope")),
+		"other/envelope": envelopeWorkName("crp-1-work", fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("other", "envelope")),
+		"flux":           envelopeWorkName("crp-1-work", fleetv1beta1.FluxSourceEnvelopeType, newEnvelopeObj("app", "envelope")),
+		"long":           envelopeWorkName("crp-1-work", fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("app", longName)),
+	}
+	seen := map[string]string{}
+	for envelope, name := range names {
+		if other, ok := seen[name]; ok {
+			t.Errorf("envelopes %s and %s have the same work name %s", envelope, other, name)
+		}
+		seen[name] = envelope
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			t.Errorf("work name %s of the envelope %s is invalid: %v", name, envelope, errs)
+		}
+	}
+	if got := envelopeWorkName("crp-1-work", fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("app", "envelope")); got != names["app/envelope"] {
+		t.Errorf("envelopeWorkName() = %s, want the same name %s for the same envelope", got, names["app/envelope"])
+	}
+}
+
+func TestSetEnvelopeIdentity(t *testing.T) {
+	longName := strings.Repeat("a", validation.LabelValueMaxLength+1)
+	work := &fleetv1beta1.Work{}
+	setEnvelopeIdentity(work, fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("app", "envelope"))
+	if got := work.Labels[fleetv1beta1.EnvelopeNameLabel]; got != "envelope" {
+		t.Errorf("envelope name label = %q, want %q", got, "envelope")
+	}
+
+	setEnvelopeIdentity(work, fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("app", longName))
+	if got, ok := work.Labels[fleetv1beta1.EnvelopeNameLabel]; ok {
+		t.Errorf("envelope name label = %q, want no label for a name which is not a valid label value", got)
+	}
+	for key, value := range work.Labels {
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			t.Errorf("label %s is invalid: %v", key, errs)
+		}
+	}
+	if got := envelopeObjName(work); got != longName {
+		t.Errorf("envelopeObjName() = %q, want %q", got, longName)
+	}
+}
+
+func TestGetConfigMapEnvelopWorkObj(t *testing.T) {
+	const namespace = "fleet-member-cluster-1"
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: fleetv1beta1.ClusterResourceBindingKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "binding-1", Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: "crp-1"}},
+		Spec:       fleetv1beta1.ResourceBindingSpec{TargetCluster: "cluster-1"},
+	}
+	snapshot := &fleetv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: "crp-1-2-snapshot", Labels: map[string]string{fleetv1beta1.ResourceIndexLabel: "2"}},
+	}
+	envelopeObj := newEnvelopeObj("app", "envelope")
+	legacyWork := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crp-1-work-configmap-6c1dd1a7",
+			Namespace: namespace,
+			Labels: map[string]string{
+				fleetv1beta1.ParentBindingLabel:               binding.Name,
+				fleetv1beta1.CRPTrackingLabel:                 "crp-1",
+				fleetv1beta1.ParentResourceSnapshotIndexLabel: "1",
+				fleetv1beta1.EnvelopeTypeLabel:                string(fleetv1beta1.ConfigMapEnvelopeType),
+				fleetv1beta1.EnvelopeNameLabel:                "envelope",
+				fleetv1beta1.EnvelopeNamespaceLabel:           "app",
+			},
+		},
+	}
+	tests := map[string]struct {
+		existingWorks []client.Object
+		envelopeObj   *unstructured.Unstructured
+		wantName      string
+	}{
+		"the work is created with the hashed name": {
+			envelopeObj: envelopeObj,
+			wantName:    envelopeWorkName("crp-1-work", fleetv1beta1.ConfigMapEnvelopeType, envelopeObj),
+		},
+		"the work generated before the hash label is migrated": {
+			existingWorks: []client.Object{legacyWork},
+			envelopeObj:   envelopeObj,
+			wantName:      legacyWork.Name,
+		},
+		"the work of the envelope of the same name in another namespace is not reused": {
+			existingWorks: []client.Object{legacyWork},
+			envelopeObj:   newEnvelopeObj("other", "envelope"),
+			wantName:      envelopeWorkName("crp-1-work", fleetv1beta1.ConfigMapEnvelopeType, newEnvelopeObj("other", "envelope")),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add the scheme: %v", err)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(tc.existingWorks...).
+				WithIndex(&fleetv1beta1.Work{}, index.WorkBindingField, index.WorkBinding).
+				Build()
+			r := &Reconciler{Client: c}
+			ctx := context.Background()
+
+			work, err := r.getConfigMapEnvelopWorkObj(ctx, "crp-1-work", binding, snapshot, tc.envelopeObj, fleetv1beta1.ConfigMapEnvelopeType)
+			if err != nil {
+				t.Fatalf("getConfigMapEnvelopWorkObj() = %v, want nil", err)
+			}
+			if work.Name != tc.wantName {
+				t.Errorf("work name = %s, want %s", work.Name, tc.wantName)
+			}
+			if got, want := work.Labels[fleetv1beta1.EnvelopeHashLabel], envelopeHash(fleetv1beta1.ConfigMapEnvelopeType, tc.envelopeObj); got != want {
+				t.Errorf("envelope hash label = %q, want %q", got, want)
+			}
+			if got := work.Labels[fleetv1beta1.EnvelopeNamespaceLabel]; got != tc.envelopeObj.GetNamespace() {
+				t.Errorf("envelope namespace label = %q, want %q", got, tc.envelopeObj.GetNamespace())
+			}
+
+			// the existing work is updated with the labels of the envelope hash
+			var existingWork *fleetv1beta1.Work
+			if len(tc.existingWorks) > 0 && work.Name == legacyWork.Name {
+				existingWork = &fleetv1beta1.Work{}
+				if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: legacyWork.Name}, existingWork); err != nil {
+					t.Fatalf("failed to get the existing work: %v", err)
+				}
+			}
+			if _, err := r.upsertWork(ctx, work, existingWork, snapshot); err != nil {
+				t.Fatalf("upsertWork() = %v, want nil", err)
+			}
+			works, err := r.listEnvelopeWorks(ctx, binding, fleetv1beta1.ConfigMapEnvelopeType, tc.envelopeObj)
+			if err != nil {
+				t.Fatalf("listEnvelopeWorks() = %v, want nil", err)
+			}
+			if len(works) != 1 || works[0].Name != tc.wantName || works[0].Labels[fleetv1beta1.EnvelopeHashLabel] == "" {
+				t.Errorf("listEnvelopeWorks() = %v, want the work %s with the envelope hash label", works, tc.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"sort"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// maxLoadBalancerLimit indicates the max number of load balancers to include in the status.
+const maxLoadBalancerLimit = 100
+
+// extractLoadBalancersFromWorks returns the load balancers of the placed services of the LoadBalancer type which the
+// member agent reports in the status of the works, sorted by the namespaces and names of the services and cut to
+// keep only the max limit. The works being deleted are ignored.
+func extractLoadBalancersFromWorks(works map[string]*fleetv1beta1.Work) []fleetv1beta1.PlacedLoadBalancer {
+	var loadBalancers []fleetv1beta1.PlacedLoadBalancer
+	for _, w := range works {
+		if w.DeletionTimestamp != nil {
+			continue
+		}
+		var envelope *fleetv1beta1.EnvelopeIdentifier
+		if envelopeType, isEnveloped := w.GetLabels()[fleetv1beta1.EnvelopeTypeLabel]; isEnveloped {
+			envelope = &fleetv1beta1.EnvelopeIdentifier{
+				Name:      envelopeObjName(w),
+				Namespace: w.GetLabels()[fleetv1beta1.EnvelopeNamespaceLabel],
+				Type:      fleetv1beta1.EnvelopeType(envelopeType),
+			}
+		}
+		for _, manifestCondition := range w.Status.ManifestConditions {
+			if manifestCondition.LoadBalancer == nil {
+				continue
+			}
+			loadBalancer := fleetv1beta1.PlacedLoadBalancer{
+				ResourceIdentifier: fleetv1beta1.ResourceIdentifier{
+					Group:     manifestCondition.Identifier.Group,
+					Version:   manifestCondition.Identifier.Version,
+					Kind:      manifestCondition.Identifier.Kind,
+					Name:      manifestCondition.Identifier.Name,
+					Namespace: manifestCondition.Identifier.Namespace,
+				},
+				ServiceLoadBalancerStatus: *manifestCondition.LoadBalancer,
+			}
+			if envelope != nil {
+				loadBalancer.Envelope = envelope.DeepCopy()
+			}
+			loadBalancers = append(loadBalancers, loadBalancer)
+		}
+	}
+	sort.SliceStable(loadBalancers, func(i, j int) bool {
+		a, b := loadBalancers[i], loadBalancers[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if len(loadBalancers) > maxLoadBalancerLimit {
+		loadBalancers = loadBalancers[:maxLoadBalancerLimit]
+	}
+	return loadBalancers
+}
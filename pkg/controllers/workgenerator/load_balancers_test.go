@@ -0,0 +1,106 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestExtractLoadBalancersFromWorks(t *testing.T) {
+	status := fleetv1beta1.ServiceLoadBalancerStatus{
+		Ingress: []fleetv1beta1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+		Ports:   []int32{80},
+	}
+	serviceCondition := func(namespace, name string, loadBalancer *fleetv1beta1.ServiceLoadBalancerStatus) fleetv1beta1.ManifestCondition {
+		return fleetv1beta1.ManifestCondition{
+			Identifier:   fleetv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "Service", Namespace: namespace, Name: name},
+			LoadBalancer: loadBalancer,
+		}
+	}
+	placed := func(namespace, name string, envelope *fleetv1beta1.EnvelopeIdentifier) fleetv1beta1.PlacedLoadBalancer {
+		return fleetv1beta1.PlacedLoadBalancer{
+			ResourceIdentifier:        fleetv1beta1.ResourceIdentifier{Version: "v1", Kind: "Service", Namespace: namespace, Name: name, Envelope: envelope},
+			ServiceLoadBalancerStatus: status,
+		}
+	}
+	now := metav1.Now()
+
+	tests := map[string]struct {
+		works map[string]*fleetv1beta1.Work
+		want  []fleetv1beta1.PlacedLoadBalancer
+	}{
+		"load balancers of the works sorted by the services": {
+			works: map[string]*fleetv1beta1.Work{
+				"work": {
+					Status: fleetv1beta1.WorkStatus{ManifestConditions: []fleetv1beta1.ManifestCondition{
+						serviceCondition("b", "web", &status),
+						serviceCondition("a", "internal", nil),
+						serviceCondition("a", "web", &status),
+					}},
+				},
+				"envelope-work": {
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							fleetv1beta1.EnvelopeTypeLabel:      string(fleetv1beta1.ConfigMapEnvelopeType),
+							fleetv1beta1.EnvelopeNameLabel:      "envelope",
+							fleetv1beta1.EnvelopeNamespaceLabel: "app",
+						},
+					},
+					Status: fleetv1beta1.WorkStatus{ManifestConditions: []fleetv1beta1.ManifestCondition{
+						serviceCondition("a", "api", &status),
+					}},
+				},
+				"deleting-work": {
+					ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+					Status: fleetv1beta1.WorkStatus{ManifestConditions: []fleetv1beta1.ManifestCondition{
+						serviceCondition("a", "gone", &status),
+					}},
+				},
+			},
+			want: []fleetv1beta1.PlacedLoadBalancer{
+				placed("a", "api", &fleetv1beta1.EnvelopeIdentifier{Name: "envelope", Namespace: "app", Type: fleetv1beta1.ConfigMapEnvelopeType}),
+				placed("a", "web", nil),
+				placed("b", "web", nil),
+			},
+		},
+		"no load balancers": {
+			works: map[string]*fleetv1beta1.Work{
+				"work": {
+					Status: fleetv1beta1.WorkStatus{ManifestConditions: []fleetv1beta1.ManifestCondition{
+						serviceCondition("a", "internal", nil),
+					}},
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := extractLoadBalancersFromWorks(tt.works)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("extractLoadBalancersFromWorks() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("more load balancers than the limit", func(t *testing.T) {
+		conditions := make([]fleetv1beta1.ManifestCondition, 0, maxLoadBalancerLimit+1)
+		for i := 0; i <= maxLoadBalancerLimit; i++ {
+			conditions = append(conditions, serviceCondition("app", fmt.Sprintf("web-%03d", i), &status))
+		}
+		got := extractLoadBalancersFromWorks(map[string]*fleetv1beta1.Work{
+			"work": {Status: fleetv1beta1.WorkStatus{ManifestConditions: conditions}},
+		})
+		if len(got) != maxLoadBalancerLimit || got[len(got)-1].Name != fmt.Sprintf("web-%03d", maxLoadBalancerLimit-1) {
+			t.Errorf("extractLoadBalancersFromWorks() returned %d load balancers, want the first %d", len(got), maxLoadBalancerLimit)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// buildMemberSideSyncFailedCondition returns the false WorkSynchronized condition of a binding whose works are
+// written on the hub cluster but are not synchronized to the target cluster for a member side reason, so that the
+// failures are routed to the owners of the member cluster instead of the ones of the hub cluster:
+//   - the member agent of the target cluster has left the fleet, so that it does not pick the works up;
+//   - the member agent rejects some of the works as a whole, e.g. as their signatures cannot be verified.
+//
+// It returns nil if the works are synchronized. The works are not checked for rejections if they are just updated,
+// as their conditions are reported for the previous generations.
+func buildMemberSideSyncFailedCondition(cluster *clusterv1beta1.MemberCluster, works map[string]*fleetv1beta1.Work, workUpdated bool, binding *fleetv1beta1.ClusterResourceBinding) *metav1.Condition {
+	if joined := cluster.GetAgentCondition(clusterv1beta1.MemberAgent, clusterv1beta1.AgentJoined); joined != nil && joined.Status == metav1.ConditionFalse {
+		return &metav1.Condition{
+			Status:             metav1.ConditionFalse,
+			Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+			Reason:             condition.MemberClusterUnreachableReason,
+			Message:            fmt.Sprintf("The works are not picked up as the member agent of the target cluster %s has not joined the fleet: %s", cluster.Name, joined.Message),
+			ObservedGeneration: binding.Generation,
+		}
+	}
+	if workUpdated {
+		return nil
+	}
+	var rejected []string
+	for name, w := range works {
+		if w.DeletionTimestamp != nil {
+			continue
+		}
+		applied := meta.FindStatusCondition(w.Status.Conditions, fleetv1beta1.WorkConditionTypeApplied)
+		if applied != nil && applied.ObservedGeneration == w.Generation && applied.Status == metav1.ConditionFalse &&
+			applied.Reason == work.WorkSignatureVerificationFailedReason {
+			rejected = append(rejected, name)
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	sort.Strings(rejected)
+	return &metav1.Condition{
+		Status:             metav1.ConditionFalse,
+		Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+		Reason:             condition.WorkApplyRejectedReason,
+		Message:            fmt.Sprintf("The member agent of the target cluster %s rejects the works %v", cluster.Name, rejected),
+		ObservedGeneration: binding.Generation,
+	}
+}
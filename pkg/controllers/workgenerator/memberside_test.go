@@ -0,0 +1,114 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestBuildMemberSideSyncFailedCondition(t *testing.T) {
+	memberAgentStatus := func(joined metav1.ConditionStatus) []clusterv1beta1.AgentStatus {
+		return []clusterv1beta1.AgentStatus{{
+			Type: clusterv1beta1.MemberAgent,
+			Conditions: []metav1.Condition{{
+				Type:   string(clusterv1beta1.AgentJoined),
+				Status: joined,
+				Reason: "MemberAgentLeft",
+			}},
+		}}
+	}
+	workWithApplied := func(name string, generation int64, status metav1.ConditionStatus, reason string) *fleetv1beta1.Work {
+		return &fleetv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: generation},
+			Status: fleetv1beta1.WorkStatus{
+				Conditions: []metav1.Condition{{
+					Type:               fleetv1beta1.WorkConditionTypeApplied,
+					Status:             status,
+					Reason:             reason,
+					ObservedGeneration: 1,
+				}},
+			},
+		}
+	}
+	binding := &fleetv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding-1", Generation: 2}}
+	tests := map[string]struct {
+		agentStatus []clusterv1beta1.AgentStatus
+		works       map[string]*fleetv1beta1.Work
+		workUpdated bool
+		wantReason  string
+	}{
+		"works are synchronized": {
+			agentStatus: memberAgentStatus(metav1.ConditionTrue),
+			works: map[string]*fleetv1beta1.Work{
+				"work-1": workWithApplied("work-1", 1, metav1.ConditionTrue, work.WorkAppliedCompletedReason),
+			},
+		},
+		"member agent has not reported yet": {
+			works: map[string]*fleetv1beta1.Work{
+				"work-1": workWithApplied("work-1", 1, metav1.ConditionFalse, work.WorkAppliedFailedReason),
+			},
+		},
+		"member agent has left the fleet": {
+			agentStatus: memberAgentStatus(metav1.ConditionFalse),
+			wantReason:  condition.MemberClusterUnreachableReason,
+		},
+		"member agent rejects a work": {
+			agentStatus: memberAgentStatus(metav1.ConditionTrue),
+			works: map[string]*fleetv1beta1.Work{
+				"work-1": workWithApplied("work-1", 1, metav1.ConditionTrue, work.WorkAppliedCompletedReason),
+				"work-2": workWithApplied("work-2", 1, metav1.ConditionFalse, work.WorkSignatureVerificationFailedReason),
+			},
+			wantReason: condition.WorkApplyRejectedReason,
+		},
+		"rejection of a previous generation": {
+			works: map[string]*fleetv1beta1.Work{
+				"work-1": workWithApplied("work-1", 2, metav1.ConditionFalse, work.WorkSignatureVerificationFailedReason),
+			},
+		},
+		"works are just updated": {
+			works: map[string]*fleetv1beta1.Work{
+				"work-1": workWithApplied("work-1", 1, metav1.ConditionFalse, work.WorkSignatureVerificationFailedReason),
+			},
+			workUpdated: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster := &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"},
+				Status:     clusterv1beta1.MemberClusterStatus{AgentStatus: tc.agentStatus},
+			}
+			got := buildMemberSideSyncFailedCondition(cluster, tc.works, tc.workUpdated, binding)
+			if tc.wantReason == "" {
+				if got != nil {
+					t.Fatalf("buildMemberSideSyncFailedCondition() = %+v, want nil", got)
+				}
+				return
+			}
+			want := &metav1.Condition{
+				Status:             metav1.ConditionFalse,
+				Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+				Reason:             tc.wantReason,
+				ObservedGeneration: binding.Generation,
+			}
+			if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(metav1.Condition{}, "Message")); diff != "" {
+				t.Errorf("buildMemberSideSyncFailedCondition() mismatch (-want, +got):\n%s", diff)
+			}
+			if !condition.IsMemberSideFailureReason(got.Reason) {
+				t.Errorf("IsMemberSideFailureReason(%s) = false, want true", got.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/overrider"
+	"go.goms.io/fleet/pkg/utils/validator"
+)
+
+// invalidMetadataOverrideReason is the reason of the event reported on a cluster metadata override which is skipped
+// as it is invalid.
+const invalidMetadataOverrideReason = "InvalidClusterMetadataOverride"
+
+// fetchMetadataOverrides lists all the valid cluster metadata overrides sorted by their names, so that they are
+// applied in the same order on every cluster; it returns none if the metadata overrides are not enabled.
+//
+// An invalid override, e.g. one created before the validating webhook was installed or with a label which has become
+// protected since, is skipped and reported on the override itself, as it must not block the works of the placements
+// which it does not even select.
+func (r *Reconciler) fetchMetadataOverrides(ctx context.Context) ([]placementv1alpha1.ClusterMetadataOverride, error) {
+	if !r.EnableMetadataOverrides {
+		return nil, nil
+	}
+	var overrideList placementv1alpha1.ClusterMetadataOverrideList
+	if err := r.Client.List(ctx, &overrideList); err != nil {
+		klog.ErrorS(err, "Failed to list the clusterMetadataOverrides")
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	overrides := make([]placementv1alpha1.ClusterMetadataOverride, 0, len(overrideList.Items))
+	for i := range overrideList.Items {
+		mo := &overrideList.Items[i]
+		if err := validator.ValidateClusterMetadataOverride(*mo); err != nil {
+			klog.ErrorS(err, "Skipped the invalid clusterMetadataOverride", "clusterMetadataOverride", klog.KObj(mo))
+			if r.recorder != nil {
+				r.recorder.Event(mo, corev1.EventTypeWarning, invalidMetadataOverrideReason, fmt.Sprintf("The clusterMetadataOverride is skipped: %v", err))
+			}
+			continue
+		}
+		overrides = append(overrides, *mo)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].Name < overrides[j].Name
+	})
+	return overrides, nil
+}
+
+// applyMetadataOverrides merges the labels and annotations of the metadata override rules which select the resource
+// and the cluster into the resource. The content of the resource is only re-encoded if its metadata is changed.
+// An override which cannot be evaluated is skipped, like the invalid ones which are skipped by fetchMetadataOverrides.
+func applyMetadataOverrides(resource *placementv1beta1.ResourceContent, uResource *unstructured.Unstructured, cluster clusterv1beta1.MemberCluster, overrides []placementv1alpha1.ClusterMetadataOverride) error {
+	changed := false
+	for i := range overrides {
+		mo := &overrides[i]
+		selected, err := isSelectedByMetadataOverride(uResource, mo.Spec.ResourceSelectors)
+		if err != nil {
+			klog.ErrorS(err, "Skipped the clusterMetadataOverride with an invalid resource selector", "clusterMetadataOverride", klog.KObj(mo))
+			continue
+		}
+		if !selected {
+			continue
+		}
+		for _, rule := range mo.Spec.MetadataOverrideRules {
+			matched, err := overrider.IsClusterSelected(cluster, rule.ClusterSelector)
+			if err != nil {
+				klog.ErrorS(err, "Skipped the invalid metadata override rule", "clusterMetadataOverride", klog.KObj(mo))
+				continue
+			}
+			if !matched {
+				continue
+			}
+			if labels, merged := mergeMetadata(uResource.GetLabels(), rule.Labels); merged {
+				uResource.SetLabels(labels)
+				changed = true
+			}
+			if annotations, merged := mergeMetadata(uResource.GetAnnotations(), rule.Annotations); merged {
+				uResource.SetAnnotations(annotations)
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	raw, err := uResource.MarshalJSON()
+	if err != nil {
+		klog.ErrorS(err, "Failed to marshal the resource with its metadata overridden", "resource", klog.KObj(uResource))
+		return controller.NewUnexpectedBehaviorError(err)
+	}
+	resource.Raw = raw
+	return nil
+}
+
+// isSelectedByMetadataOverride returns whether any of the selectors of a metadata override selects the resource.
+func isSelectedByMetadataOverride(uResource *unstructured.Unstructured, selectors []placementv1alpha1.MetadataResourceSelector) (bool, error) {
+	gvk := uResource.GroupVersionKind()
+	for _, selector := range selectors {
+		matched := true
+		for _, field := range []struct {
+			pattern, value string
+			// matchAnyIfEmpty is whether an empty pattern matches any value instead of the empty value only
+			matchAnyIfEmpty bool
+		}{
+			{selector.Group, gvk.Group, false},
+			{selector.Version, gvk.Version, true},
+			{selector.Kind, gvk.Kind, false},
+			{selector.Namespace, uResource.GetNamespace(), true},
+			{selector.Name, uResource.GetName(), true},
+		} {
+			if field.pattern == "" && field.matchAnyIfEmpty {
+				continue
+			}
+			ok, err := path.Match(field.pattern, field.value)
+			if err != nil {
+				return false, fmt.Errorf("invalid pattern %q: %w", field.pattern, err)
+			}
+			if !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mergeMetadata merges the labels or annotations to set into the current ones, and returns whether any of them is
+// changed.
+func mergeMetadata(current, toSet map[string]string) (map[string]string, bool) {
+	merged := false
+	for key, val := range toSet {
+		if cur, found := current[key]; found && cur == val {
+			continue
+		}
+		if current == nil {
+			current = make(map[string]string, len(toSet))
+		}
+		current[key] = val
+		merged = true
+	}
+	return current, merged
+}
+
+// allBindings returns the requests of all the bindings, as a metadata override may select the resources of any
+// placement.
+func (r *Reconciler) allBindings(ctx context.Context, _ client.Object) []reconcile.Request {
+	bindingList := &placementv1beta1.ClusterResourceBindingList{}
+	if err := r.Client.List(ctx, bindingList); err != nil {
+		klog.ErrorS(err, "Failed to list the clusterResourceBindings")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(bindingList.Items))
+	for i := range bindingList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&bindingList.Items[i])})
+	}
+	return requests
+}
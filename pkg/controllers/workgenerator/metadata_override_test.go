@@ -0,0 +1,290 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/validator"
+)
+
+func newMetadataOverride(name string, selectors []placementv1alpha1.MetadataResourceSelector, rules ...placementv1alpha1.MetadataOverrideRule) placementv1alpha1.ClusterMetadataOverride {
+	return placementv1alpha1.ClusterMetadataOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: placementv1alpha1.ClusterMetadataOverrideSpec{
+			ResourceSelectors:     selectors,
+			MetadataOverrideRules: rules,
+		},
+	}
+}
+
+func TestFetchMetadataOverrides(t *testing.T) {
+	selectors := []placementv1alpha1.MetadataResourceSelector{{Group: "*", Kind: "*"}}
+	rule := placementv1alpha1.MetadataOverrideRule{Labels: map[string]string{"team": "web"}}
+	tests := map[string]struct {
+		enabled    bool
+		overrides  []placementv1alpha1.ClusterMetadataOverride
+		want       []string
+		wantEvents int
+	}{
+		"metadata overrides are enabled": {
+			enabled: true,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo-b", selectors, rule),
+				newMetadataOverride("cmo-a", selectors, rule),
+			},
+			want: []string{"cmo-a", "cmo-b"},
+		},
+		"metadata overrides are disabled": {
+			enabled: false,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo-a", selectors, rule),
+			},
+		},
+		"invalid pattern is skipped": {
+			enabled: true,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo-a", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Kind: "Deployment", Name: "web-["}}, rule),
+				newMetadataOverride("cmo-b", selectors, rule),
+			},
+			want:       []string{"cmo-b"},
+			wantEvents: 1,
+		},
+		"protected label is skipped": {
+			enabled: true,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo-a", selectors, placementv1alpha1.MetadataOverrideRule{Labels: map[string]string{placementv1beta1.CRPTrackingLabel: "crp"}}),
+				newMetadataOverride("cmo-b", selectors, rule),
+			},
+			want:       []string{"cmo-b"},
+			wantEvents: 1,
+		},
+	}
+	defer func(paths []string) { validator.OverrideProtectedPaths = paths }(validator.OverrideProtectedPaths)
+	validator.OverrideProtectedPaths = []string{"/metadata/labels/kubernetes-fleet.io~1parent-CRP"}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(serviceScheme(t))
+			for i := range tc.overrides {
+				builder = builder.WithObjects(&tc.overrides[i])
+			}
+			recorder := record.NewFakeRecorder(len(tc.overrides))
+			r := Reconciler{
+				Client:                  builder.Build(),
+				EnableMetadataOverrides: tc.enabled,
+				recorder:                recorder,
+			}
+			got, err := r.fetchMetadataOverrides(context.Background())
+			if err != nil {
+				t.Fatalf("fetchMetadataOverrides() = %v, want no error", err)
+			}
+			var gotNames []string
+			for i := range got {
+				gotNames = append(gotNames, got[i].Name)
+			}
+			if diff := cmp.Diff(tc.want, gotNames); diff != "" {
+				t.Errorf("fetchMetadataOverrides() names mismatch (-want, +got):\n%s", diff)
+			}
+			if len(recorder.Events) != tc.wantEvents {
+				t.Errorf("fetchMetadataOverrides() reported %d events, want %d", len(recorder.Events), tc.wantEvents)
+			}
+		})
+	}
+}
+
+// TestInvalidMetadataOverrideDoesNotBlockBindings verifies that an invalid cluster metadata override, which is listed
+// for every binding, neither fails nor changes the works of the bindings while the valid overrides are still applied.
+func TestInvalidMetadataOverrideDoesNotBlockBindings(t *testing.T) {
+	invalid := newMetadataOverride("cmo-a", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Kind: "Deployment", Name: "web-["}},
+		placementv1alpha1.MetadataOverrideRule{ClusterSelector: &placementv1beta1.ClusterSelector{}, Labels: map[string]string{"ring": "0"}})
+	valid := newMetadataOverride("cmo-b", []placementv1alpha1.MetadataResourceSelector{{Kind: "ConfigMap"}},
+		placementv1alpha1.MetadataOverrideRule{ClusterSelector: &placementv1beta1.ClusterSelector{}, Labels: map[string]string{"team": "web"}})
+	r := Reconciler{
+		Client:                  fake.NewClientBuilder().WithScheme(serviceScheme(t)).WithObjects(&invalid, &valid).Build(),
+		EnableMetadataOverrides: true,
+	}
+	overrides, err := r.fetchMetadataOverrides(context.Background())
+	if err != nil {
+		t.Fatalf("fetchMetadataOverrides() = %v, want no error", err)
+	}
+
+	// The resources below belong to the works of two unrelated bindings on different clusters.
+	tests := map[string]struct {
+		cluster    string
+		resource   string
+		wantLabels map[string]string
+	}{
+		"deployment selected by the invalid override is unchanged": {
+			cluster:    "cluster-1",
+			resource:   `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web-frontend","namespace":"app","labels":{"app":"web"}}}`,
+			wantLabels: map[string]string{"app": "web"},
+		},
+		"config map selected by the valid override is overridden": {
+			cluster:    "cluster-2",
+			resource:   `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`,
+			wantLabels: map[string]string{"team": "web"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource := placementv1beta1.ResourceContent{}
+			resource.Raw = []byte(tc.resource)
+			var uResource unstructured.Unstructured
+			if err := uResource.UnmarshalJSON(resource.Raw); err != nil {
+				t.Fatalf("UnmarshalJSON() = %v, want no error", err)
+			}
+			cluster := clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: tc.cluster}}
+			if err := applyMetadataOverrides(&resource, &uResource, cluster, overrides); err != nil {
+				t.Fatalf("applyMetadataOverrides() = %v, want no error", err)
+			}
+			var got unstructured.Unstructured
+			if err := got.UnmarshalJSON(resource.Raw); err != nil {
+				t.Fatalf("UnmarshalJSON() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantLabels, got.GetLabels()); diff != "" {
+				t.Errorf("applyMetadataOverrides() labels mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyMetadataOverrides(t *testing.T) {
+	cluster := clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cluster-1",
+			Labels: map[string]string{"region": "eastus"},
+		},
+	}
+	eastUS := &placementv1beta1.ClusterSelector{
+		ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{
+			{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eastus"}}},
+		},
+	}
+	westUS := &placementv1beta1.ClusterSelector{
+		ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{
+			{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "westus"}}},
+		},
+	}
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web-frontend","namespace":"app","labels":{"app":"web"}}}`
+
+	tests := map[string]struct {
+		resource        string
+		overrides       []placementv1alpha1.ClusterMetadataOverride
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+		wantUnchanged   bool
+	}{
+		"no metadata override": {
+			resource:      deployment,
+			wantUnchanged: true,
+		},
+		"wildcard selector adds labels and annotations": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo", []placementv1alpha1.MetadataResourceSelector{{Group: "*", Kind: "*"}},
+					placementv1alpha1.MetadataOverrideRule{
+						ClusterSelector: eastUS,
+						Labels:          map[string]string{"region": "eastus"},
+						Annotations:     map[string]string{"owner": "team-web"},
+					}),
+			},
+			wantLabels:      map[string]string{"app": "web", "region": "eastus"},
+			wantAnnotations: map[string]string{"owner": "team-web"},
+		},
+		"name pattern selects the resource": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Kind: "Deployment", Namespace: "app", Name: "web-*"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: &placementv1beta1.ClusterSelector{}, Labels: map[string]string{"app": "frontend"}}),
+			},
+			wantLabels: map[string]string{"app": "frontend"},
+		},
+		"empty group only selects the core group": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo", []placementv1alpha1.MetadataResourceSelector{{Kind: "*"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: &placementv1beta1.ClusterSelector{}, Labels: map[string]string{"tier": "core"}}),
+			},
+			wantUnchanged: true,
+		},
+		"cluster is not selected": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo", []placementv1alpha1.MetadataResourceSelector{{Group: "*", Kind: "*"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: westUS, Labels: map[string]string{"region": "westus"}},
+					placementv1alpha1.MetadataOverrideRule{Labels: map[string]string{"selected": "none"}}),
+			},
+			wantUnchanged: true,
+		},
+		"labels are already set": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo", []placementv1alpha1.MetadataResourceSelector{{Group: "*", Kind: "*"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: eastUS, Labels: map[string]string{"app": "web"}}),
+			},
+			wantUnchanged: true,
+		},
+		"the last rule wins": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo-a", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Kind: "Deploy*"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: eastUS, Labels: map[string]string{"ring": "0"}}),
+				newMetadataOverride("cmo-b", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Version: "v1", Kind: "Deployment"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: eastUS, Labels: map[string]string{"ring": "1"}}),
+			},
+			wantLabels: map[string]string{"app": "web", "ring": "1"},
+		},
+		"invalid pattern is skipped": {
+			resource: deployment,
+			overrides: []placementv1alpha1.ClusterMetadataOverride{
+				newMetadataOverride("cmo-a", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Kind: "["}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: eastUS, Labels: map[string]string{"ring": "0"}}),
+				newMetadataOverride("cmo-b", []placementv1alpha1.MetadataResourceSelector{{Group: "apps", Kind: "Deployment"}},
+					placementv1alpha1.MetadataOverrideRule{ClusterSelector: eastUS, Labels: map[string]string{"ring": "1"}}),
+			},
+			wantLabels: map[string]string{"app": "web", "ring": "1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resource := placementv1beta1.ResourceContent{}
+			resource.Raw = []byte(tc.resource)
+			var uResource unstructured.Unstructured
+			if err := uResource.UnmarshalJSON(resource.Raw); err != nil {
+				t.Fatalf("UnmarshalJSON() = %v, want no error", err)
+			}
+			if err := applyMetadataOverrides(&resource, &uResource, cluster, tc.overrides); err != nil {
+				t.Fatalf("applyMetadataOverrides() = %v, want no error", err)
+			}
+			if tc.wantUnchanged {
+				if string(resource.Raw) != tc.resource {
+					t.Errorf("applyMetadataOverrides() changed the resource to %s, want unchanged", resource.Raw)
+				}
+				return
+			}
+			var got unstructured.Unstructured
+			if err := got.UnmarshalJSON(resource.Raw); err != nil {
+				t.Fatalf("UnmarshalJSON() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantLabels, got.GetLabels()); diff != "" {
+				t.Errorf("applyMetadataOverrides() labels mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, got.GetAnnotations()); diff != "" {
+				t.Errorf("applyMetadataOverrides() annotations mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -129,7 +129,7 @@ func (r *Reconciler) applyOverrides(resource *placementv1beta1.ResourceContent,
 			klog.ErrorS(controller.NewUnexpectedBehaviorError(err), "Found an invalid clusterResourceOverrideSnapshot", "clusterResourceOverrideSnapshot", klog.KObj(snapshot))
 			continue // should not happen
 		}
-		if err := applyOverrideRules(resource, cluster, snapshot.Spec.OverrideSpec.Policy.OverrideRules); err != nil {
+		if err := applyOverrideRules(r.overrideCache, resource, cluster, snapshot.Spec.OverrideSpec.Policy.OverrideRules); err != nil {
 			klog.ErrorS(err, "Failed to apply the override rules", "clusterResourceOverrideSnapshot", klog.KObj(snapshot))
 			return err
 		}
@@ -153,7 +153,7 @@ func (r *Reconciler) applyOverrides(resource *placementv1beta1.ResourceContent,
 				klog.ErrorS(controller.NewUnexpectedBehaviorError(err), "Found an invalid resourceOverrideSnapshot", "resourceOverrideSnapshot", klog.KObj(snapshot))
 				continue // should not happen
 			}
-			if err := applyOverrideRules(resource, cluster, snapshot.Spec.OverrideSpec.Policy.OverrideRules); err != nil {
+			if err := applyOverrideRules(r.overrideCache, resource, cluster, snapshot.Spec.OverrideSpec.Policy.OverrideRules); err != nil {
 				klog.ErrorS(err, "Failed to apply the override rules", "resourceOverrideSnapshot", klog.KObj(snapshot))
 				return err
 			}
@@ -163,7 +163,7 @@ func (r *Reconciler) applyOverrides(resource *placementv1beta1.ResourceContent,
 	return nil
 }
 
-func applyOverrideRules(resource *placementv1beta1.ResourceContent, cluster clusterv1beta1.MemberCluster, rules []placementv1alpha1.OverrideRule) error {
+func applyOverrideRules(cache *overrideRenderCache, resource *placementv1beta1.ResourceContent, cluster clusterv1beta1.MemberCluster, rules []placementv1alpha1.OverrideRule) error {
 	for _, rule := range rules {
 		matched, err := overrider.IsClusterMatched(cluster, rule)
 		if err != nil {
@@ -174,7 +174,7 @@ func applyOverrideRules(resource *placementv1beta1.ResourceContent, cluster clus
 			continue
 		}
 
-		if err := applyJSONPatchOverride(resource, rule.JSONPatchOverrides); err != nil {
+		if err := applyJSONPatchOverride(cache, resource, rule.JSONPatchOverrides); err != nil {
 			klog.ErrorS(err, "Failed to apply JSON patch override")
 			return controller.NewUserError(err)
 		}
@@ -183,7 +183,8 @@ func applyOverrideRules(resource *placementv1beta1.ResourceContent, cluster clus
 }
 
 // applyJSONPatchOverride applies a JSON patch on the selected resources following [RFC 6902](https://datatracker.ietf.org/doc/html/rfc6902).
-func applyJSONPatchOverride(resourceContent *placementv1beta1.ResourceContent, overrides []placementv1alpha1.JSONPatchOverride) error {
+// The patched resource is served from the cache if the same patch has been applied to the same resource before.
+func applyJSONPatchOverride(cache *overrideRenderCache, resourceContent *placementv1beta1.ResourceContent, overrides []placementv1alpha1.JSONPatchOverride) error {
 	if len(overrides) == 0 { // do nothing
 		return nil
 	}
@@ -194,6 +195,15 @@ func applyJSONPatchOverride(resourceContent *placementv1beta1.ResourceContent, o
 		return err
 	}
 
+	var cacheKey overrideRenderCacheKey
+	if cache != nil {
+		cacheKey = overrideRenderCacheKeyOf(resourceContent.Raw, jsonPatchBytes)
+		if patched, found := cache.get(cacheKey); found {
+			resourceContent.Raw = patched
+			return nil
+		}
+	}
+
 	patch, err := jsonpatch.DecodePatch(jsonPatchBytes)
 	if err != nil {
 		klog.ErrorS(err, "Failed to decode the passed JSON document as an RFC 6902 patch")
@@ -205,6 +215,7 @@ func applyJSONPatchOverride(resourceContent *placementv1beta1.ResourceContent, o
 		klog.ErrorS(err, "Failed to apply the JSON patch to the resource")
 		return err
 	}
+	cache.set(cacheKey, patchedObjectJSONBytes)
 	resourceContent.Raw = patchedObjectJSONBytes
 	return nil
 }
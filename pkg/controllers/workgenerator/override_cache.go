@@ -0,0 +1,83 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"go.goms.io/fleet/pkg/metrics"
+)
+
+// overrideRenderCacheKey identifies a resource patched by the JSON patches of an override rule, by the hashes of the
+// content of the resource and of the patches.
+type overrideRenderCacheKey struct {
+	resourceHash [sha256.Size]byte
+	patchHash    [sha256.Size]byte
+}
+
+// overrideRenderCache caches the resources patched by the override rules, keyed by the content of the resources and
+// of the patches. As an override usually applies the same patches to the resources of a placement on many clusters,
+// the resources are only patched once for all of these clusters instead of once for each cluster in every reconcile.
+//
+// A nil overrideRenderCache caches nothing.
+type overrideRenderCache struct {
+	mu      sync.Mutex
+	maxSize int
+	entries map[overrideRenderCacheKey][]byte
+}
+
+// newOverrideRenderCache returns an override render cache which keeps at most maxSize patched resources; it returns
+// nil, i.e., the patched resources are not cached, if maxSize is not positive.
+func newOverrideRenderCache(maxSize int) *overrideRenderCache {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &overrideRenderCache{
+		maxSize: maxSize,
+		entries: make(map[overrideRenderCacheKey][]byte),
+	}
+}
+
+// overrideRenderCacheKeyOf returns the cache key of the resource patched by the patch.
+func overrideRenderCacheKeyOf(resource, patch []byte) overrideRenderCacheKey {
+	return overrideRenderCacheKey{
+		resourceHash: sha256.Sum256(resource),
+		patchHash:    sha256.Sum256(patch),
+	}
+}
+
+// get returns a copy of the cached patched resource for the key.
+func (c *overrideRenderCache) get(key overrideRenderCacheKey) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	patched, found := c.entries[key]
+	c.mu.Unlock()
+	if !found {
+		metrics.WorkGeneratorOverrideCacheMisses.Inc()
+		return nil, false
+	}
+	metrics.WorkGeneratorOverrideCacheHits.Inc()
+	// the patched resource is copied as the resource content of a work may be changed afterwards, e.g. sealed
+	return append([]byte(nil), patched...), true
+}
+
+// set caches a copy of the patched resource for the key.
+func (c *overrideRenderCache) set(key overrideRenderCacheKey, patched []byte) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.entries[key]; !found && len(c.entries) >= c.maxSize {
+		// Drop all the patched resources when the cache is full; the ones of the resources and the overrides which
+		// are gone would otherwise stay in the cache forever.
+		c.entries = make(map[overrideRenderCacheKey][]byte, len(c.entries))
+	}
+	c.entries[key] = append([]byte(nil), patched...)
+}
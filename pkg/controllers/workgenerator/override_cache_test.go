@@ -0,0 +1,90 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestApplyJSONPatchOverrideWithCache(t *testing.T) {
+	configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"app"},"data":{"region":"default"}}`
+	patch := func(region string) []placementv1alpha1.JSONPatchOverride {
+		return []placementv1alpha1.JSONPatchOverride{
+			{
+				Operator: placementv1alpha1.JSONPatchOverrideOpReplace,
+				Path:     "/data/region",
+				Value:    apiextensionsv1.JSON{Raw: []byte(`"` + region + `"`)},
+			},
+		}
+	}
+	cache := newOverrideRenderCache(2)
+	tests := []struct {
+		name      string
+		overrides []placementv1alpha1.JSONPatchOverride
+		want      string
+		wantSize  int
+	}{
+		{
+			name:      "patched resource is cached",
+			overrides: patch("east"),
+			want:      `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"app"},"data":{"region":"east"}}`,
+			wantSize:  1,
+		},
+		{
+			name:      "same patch is served from the cache",
+			overrides: patch("east"),
+			want:      `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"app"},"data":{"region":"east"}}`,
+			wantSize:  1,
+		},
+		{
+			name:      "another patch is cached separately",
+			overrides: patch("west"),
+			want:      `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"app"},"data":{"region":"west"}}`,
+			wantSize:  2,
+		},
+		{
+			name:      "full cache is dropped",
+			overrides: patch("north"),
+			want:      `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"app"},"data":{"region":"north"}}`,
+			wantSize:  1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := &placementv1beta1.ResourceContent{}
+			rc.Raw = []byte(configMap)
+			if err := applyJSONPatchOverride(cache, rc, tc.overrides); err != nil {
+				t.Fatalf("applyJSONPatchOverride() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, string(rc.Raw)); diff != "" {
+				t.Errorf("applyJSONPatchOverride() resource mismatch (-want, +got):\n%s", diff)
+			}
+			// changing the patched resource must not change the cached one
+			rc.Raw[0] = ' '
+			if got := len(cache.entries); got != tc.wantSize {
+				t.Errorf("overrideRenderCache size = %d, want %d", got, tc.wantSize)
+			}
+		})
+	}
+}
+
+func TestOverrideRenderCacheDisabled(t *testing.T) {
+	cache := newOverrideRenderCache(0)
+	if cache != nil {
+		t.Fatalf("newOverrideRenderCache(0) = %v, want nil", cache)
+	}
+	key := overrideRenderCacheKeyOf([]byte("resource"), []byte("patch"))
+	cache.set(key, []byte("patched"))
+	if got, found := cache.get(key); found {
+		t.Errorf("get() = %s, want not found", got)
+	}
+}
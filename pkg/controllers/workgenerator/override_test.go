@@ -25,9 +25,9 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/fleettest"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/controller"
-	"go.goms.io/fleet/test/utils/informer"
 	"go.goms.io/fleet/test/utils/resource"
 )
 
@@ -417,7 +417,7 @@ func TestFetchResourceOverrideSnapshot(t *testing.T) {
 }
 
 func TestApplyOverrides_clusterScopedResource(t *testing.T) {
-	fakeInformer := informer.FakeManager{
+	fakeInformer := fleettest.FakeInformerManager{
 		APIResources: map[schema.GroupVersionKind]bool{
 			{
 				Group:   "",
@@ -719,7 +719,7 @@ func TestApplyOverrides_clusterScopedResource(t *testing.T) {
 }
 
 func TestApplyOverrides_namespacedScopeResource(t *testing.T) {
-	fakeInformer := informer.FakeManager{
+	fakeInformer := fleettest.FakeInformerManager{
 		APIResources: map[schema.GroupVersionKind]bool{
 			{
 				Group:   "",
@@ -1494,7 +1494,7 @@ func TestApplyJSONPatchOverride(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			rc := resource.CreateResourceContentForTest(t, tc.deployment)
-			err := applyJSONPatchOverride(rc, tc.overrides)
+			err := applyJSONPatchOverride(nil, rc, tc.overrides)
 			if gotErr := err != nil; gotErr != tc.wantErr {
 				t.Fatalf("applyJSONPatchOverride() = error %v, want %v", err, tc.wantErr)
 			}
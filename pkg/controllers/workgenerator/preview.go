@@ -0,0 +1,66 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// RenderWorks renders the works which the work generator would write for the binding, i.e. the selected resources of
+// its resource snapshot with its overrides applied and split into the works, without creating or updating any work.
+// The works are sorted by their names.
+func (r *Reconciler) RenderWorks(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding) ([]fleetv1beta1.Work, error) {
+	bindingRef := klog.KObj(resourceBinding)
+	cluster := clusterv1beta1.MemberCluster{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: resourceBinding.Spec.TargetCluster}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, controller.NewUserError(fmt.Errorf("member cluster %s is not found", resourceBinding.Spec.TargetCluster))
+		}
+		klog.ErrorS(err, "Failed to get the memberCluster", "memberCluster", resourceBinding.Spec.TargetCluster, "clusterResourceBinding", bindingRef)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	resourceSnapshots, err := r.fetchAllResourceSnapshots(ctx, resourceBinding)
+	if err != nil {
+		return nil, err
+	}
+	croMap, err := r.fetchClusterResourceOverrideSnapshots(ctx, resourceBinding)
+	if err != nil {
+		return nil, err
+	}
+	roMap, err := r.fetchResourceOverrideSnapshots(ctx, resourceBinding)
+	if err != nil {
+		return nil, err
+	}
+	metadataOverrides, err := r.fetchMetadataOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	generatedWorks, err := r.generateWorks(ctx, resourceBinding, resourceSnapshots, cluster, croMap, roMap, metadataOverrides)
+	if err != nil {
+		return nil, err
+	}
+	var works []fleetv1beta1.Work
+	for i := range generatedWorks {
+		for _, work := range generatedWorks[i].works {
+			works = append(works, *work)
+		}
+	}
+	sort.Slice(works, func(i, j int) bool {
+		return works[i].Name < works[j].Name
+	})
+	klog.V(2).InfoS("Rendered the works of the binding", "numOfWork", len(works), "clusterResourceBinding", bindingRef)
+	return works, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+func TestRenderWorks(t *testing.T) {
+	configMap := fleetv1beta1.ResourceContent{RawExtension: runtime.RawExtension{
+		Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"},"data":{"key":"value"}}`),
+	}}
+	snapshot := &fleetv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "crp-1-snapshot",
+			Labels: map[string]string{
+				fleetv1beta1.CRPTrackingLabel:   "crp",
+				fleetv1beta1.ResourceIndexLabel: "1",
+			},
+			Annotations: map[string]string{
+				fleetv1beta1.ResourceGroupHashAnnotation:         "hash",
+				fleetv1beta1.NumberOfResourceSnapshotsAnnotation: "1",
+			},
+		},
+		Spec: fleetv1beta1.ResourceSnapshotSpec{SelectedResources: []fleetv1beta1.ResourceContent{configMap}},
+	}
+	applyStrategy := &fleetv1beta1.ApplyStrategy{Type: fleetv1beta1.ApplyStrategyTypeServerSideApply}
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "crp-cluster-1",
+			Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: "crp"},
+		},
+		Spec: fleetv1beta1.ResourceBindingSpec{
+			State:                fleetv1beta1.BindingStateBound,
+			TargetCluster:        "cluster-1",
+			ResourceSnapshotName: snapshot.Name,
+			ApplyStrategy:        applyStrategy,
+		},
+	}
+	cluster := &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}}
+
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	if err := clusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(snapshot, binding, cluster).Build()
+	r := &Reconciler{Client: c}
+	ctx := context.Background()
+
+	works, err := r.RenderWorks(ctx, binding)
+	if err != nil {
+		t.Fatalf("RenderWorks() = %v, want nil", err)
+	}
+	wantSpecs := map[string]fleetv1beta1.WorkSpec{
+		"crp-work": {
+			Workload:      fleetv1beta1.WorkloadTemplate{Manifests: []fleetv1beta1.Manifest{fleetv1beta1.Manifest(configMap)}},
+			ApplyStrategy: applyStrategy,
+		},
+	}
+	gotSpecs := make(map[string]fleetv1beta1.WorkSpec, len(works))
+	for i := range works {
+		gotSpecs[works[i].Name] = works[i].Spec
+	}
+	if diff := cmp.Diff(wantSpecs, gotSpecs); diff != "" {
+		t.Errorf("RenderWorks() mismatch (-want, +got):\n%s", diff)
+	}
+
+	var workList fleetv1beta1.WorkList
+	if err := c.List(ctx, &workList, client.InNamespace("fleet-member-cluster-1")); err != nil {
+		t.Fatalf("failed to list the works: %v", err)
+	}
+	if len(workList.Items) != 0 {
+		t.Errorf("RenderWorks() created %d works, want none", len(workList.Items))
+	}
+
+	unknownCluster := binding.DeepCopy()
+	unknownCluster.Spec.TargetCluster = "cluster-2"
+	if _, err := r.RenderWorks(ctx, unknownCluster); !errors.Is(err, controller.ErrUserError) {
+		t.Errorf("RenderWorks() of an unknown cluster = %v, want a user error", err)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+// workSyncThrottledError is returned when a work is not written because the rate limit of its member cluster is
+// exhausted.
+type workSyncThrottledError struct {
+	// retryAfter is the delay after which the rate limit allows the next write.
+	retryAfter time.Duration
+}
+
+func (e *workSyncThrottledError) Error() string {
+	return fmt.Sprintf("the writes of the works to the member cluster are throttled, retrying in %s", e.retryAfter.Round(time.Millisecond))
+}
+
+// memberWriteLimiter limits the rate of the work writes to the namespace of every member cluster with a token bucket
+// of its own, so that a burst of writes on the hub, e.g. a placement rolling out to all the clusters at once, does not
+// overwhelm the API server of a small member cluster with the applies of the works.
+type memberWriteLimiter struct {
+	qps   rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// newMemberWriteLimiter returns a limiter which allows qps writes per second with the given burst to each member
+// cluster.
+func newMemberWriteLimiter(qps float64, burst int) *memberWriteLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &memberWriteLimiter{
+		qps:      rate.Limit(qps),
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// allow takes a token from the bucket of the member cluster whose reserved namespace is given; it returns zero if the
+// write is allowed, or the delay after which a token is available otherwise.
+func (l *memberWriteLimiter) allow(namespace string) time.Duration {
+	l.mu.Lock()
+	limiter, ok := l.limiters[namespace]
+	if !ok {
+		limiter = rate.NewLimiter(l.qps, l.burst)
+		l.limiters[namespace] = limiter
+	}
+	l.mu.Unlock()
+
+	reservation := limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		// give the token back so that the throttled writes do not push the next allowed write further away
+		reservation.Cancel()
+		return delay
+	}
+	return 0
+}
+
+// forget removes the bucket of the member cluster whose reserved namespace is given, e.g. once the cluster has left
+// the fleet, so that the limiter does not keep a bucket for every member cluster that has ever joined.
+func (l *memberWriteLimiter) forget(namespace string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.limiters, namespace)
+}
+
+// forgetIfIdle removes the bucket of the member cluster whose reserved namespace is given if it is full; a full bucket
+// behaves the same as the new one created on the next write, so removing it does not lift the limit of the other
+// bindings writing to the member cluster.
+func (l *memberWriteLimiter) forgetIfIdle(namespace string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limiter, ok := l.limiters[namespace]; ok && limiter.Tokens() >= float64(l.burst) {
+		delete(l.limiters, namespace)
+	}
+}
+
+// throttleWorkWrite returns a workSyncThrottledError if the write of the work exceeds the rate limit of its member
+// cluster; the writes are not limited if the rate limit is not set.
+func (r *Reconciler) throttleWorkWrite(work *fleetv1beta1.Work) error {
+	if r.memberWriteLimiter == nil {
+		return nil
+	}
+	if delay := r.memberWriteLimiter.allow(work.Namespace); delay > 0 {
+		klog.V(2).InfoS("Throttled the write of the work", "work", klog.KObj(work), "retryAfter", delay)
+		return &workSyncThrottledError{retryAfter: delay}
+	}
+	return nil
+}
+
+// forgetMemberWriteLimit removes the rate limit bucket of the target cluster of the binding; the bucket is only removed
+// if it is idle unless the target cluster is gone, as the other bindings may still write to the cluster.
+func (r *Reconciler) forgetMemberWriteLimit(resourceBinding *fleetv1beta1.ClusterResourceBinding, clusterGone bool) {
+	if r.memberWriteLimiter == nil {
+		return
+	}
+	namespace := fmt.Sprintf(utils.NamespaceNameFormat, resourceBinding.Spec.TargetCluster)
+	if clusterGone {
+		r.memberWriteLimiter.forget(namespace)
+		return
+	}
+	r.memberWriteLimiter.forgetIfIdle(namespace)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestMemberWriteLimiter(t *testing.T) {
+	// a token every 1000s so that none is added back during the test
+	limiter := newMemberWriteLimiter(0.001, 2)
+	for i := 0; i < 2; i++ {
+		if delay := limiter.allow("fleet-member-cluster-1"); delay != 0 {
+			t.Fatalf("allow() #%d = %v, want 0", i, delay)
+		}
+	}
+	if delay := limiter.allow("fleet-member-cluster-1"); delay <= 0 {
+		t.Errorf("allow() after the burst = %v, want a positive delay", delay)
+	}
+	// the throttled write does not take the next token
+	if delay := limiter.allow("fleet-member-cluster-1"); delay <= 0 || delay > 1000*time.Second {
+		t.Errorf("allow() after a throttled write = %v, want a delay of at most 1000s", delay)
+	}
+	if delay := limiter.allow("fleet-member-cluster-2"); delay != 0 {
+		t.Errorf("allow() of another member cluster = %v, want 0", delay)
+	}
+}
+
+func TestForgetMemberWriteLimit(t *testing.T) {
+	binding := func(cluster string) *fleetv1beta1.ClusterResourceBinding {
+		return &fleetv1beta1.ClusterResourceBinding{Spec: fleetv1beta1.ResourceBindingSpec{TargetCluster: cluster}}
+	}
+	// a token every 1000s so that none is added back during the test
+	r := &Reconciler{memberWriteLimiter: newMemberWriteLimiter(0.001, 1)}
+	limiters := r.memberWriteLimiter.limiters
+	r.memberWriteLimiter.allow("fleet-member-cluster-1")
+	r.memberWriteLimiter.allow("fleet-member-cluster-2")
+	r.memberWriteLimiter.limiters["fleet-member-cluster-3"] = rate.NewLimiter(0.001, 1)
+
+	// the bucket in use is kept for the other bindings of the member cluster
+	r.forgetMemberWriteLimit(binding("cluster-1"), false)
+	if _, ok := limiters["fleet-member-cluster-1"]; !ok {
+		t.Errorf("forgetMemberWriteLimit() removed the bucket in use of a remaining cluster")
+	}
+	r.forgetMemberWriteLimit(binding("cluster-3"), false)
+	if _, ok := limiters["fleet-member-cluster-3"]; ok {
+		t.Errorf("forgetMemberWriteLimit() kept the idle bucket of a deleted binding")
+	}
+	r.forgetMemberWriteLimit(binding("cluster-2"), true)
+	if _, ok := limiters["fleet-member-cluster-2"]; ok {
+		t.Errorf("forgetMemberWriteLimit() kept the bucket of a cluster which is gone")
+	}
+	if len(limiters) != 1 {
+		t.Errorf("forgetMemberWriteLimit() left %d buckets, want 1", len(limiters))
+	}
+
+	// the writes are not limited without the limiter
+	(&Reconciler{}).forgetMemberWriteLimit(binding("cluster-1"), true)
+}
+
+func TestUpsertWorkThrottled(t *testing.T) {
+	resourceSnapshot := &fleetv1beta1.ClusterResourceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "crp-1-snapshot",
+			Labels: map[string]string{fleetv1beta1.ResourceIndexLabel: "1"},
+		},
+	}
+	newWork := func(name string) *fleetv1beta1.Work {
+		return &fleetv1beta1.Work{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "fleet-member-cluster-1",
+				Labels:    map[string]string{fleetv1beta1.ParentResourceSnapshotIndexLabel: "1"},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	r := &Reconciler{
+		Client:             fake.NewClientBuilder().WithScheme(scheme).Build(),
+		memberWriteLimiter: newMemberWriteLimiter(0.001, 1),
+	}
+	ctx := context.Background()
+
+	if _, err := r.upsertWork(ctx, newWork("work-1"), nil, resourceSnapshot); err != nil {
+		t.Fatalf("upsertWork() of the first work = %v, want nil", err)
+	}
+	// the work is up to date so that nothing is written
+	existingWork := &fleetv1beta1.Work{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "fleet-member-cluster-1", Name: "work-1"}, existingWork); err != nil {
+		t.Fatalf("failed to get the work: %v", err)
+	}
+	if updated, err := r.upsertWork(ctx, newWork("work-1"), existingWork, resourceSnapshot); err != nil || updated {
+		t.Fatalf("upsertWork() of the up-to-date work = %v, %v, want false, nil", updated, err)
+	}
+
+	_, err := r.upsertWork(ctx, newWork("work-2"), nil, resourceSnapshot)
+	var throttledErr *workSyncThrottledError
+	if !errors.As(err, &throttledErr) || throttledErr.retryAfter <= 0 {
+		t.Fatalf("upsertWork() of the second work = %v, want a workSyncThrottledError", err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "fleet-member-cluster-1", Name: "work-2"}, &fleetv1beta1.Work{}); !apierrors.IsNotFound(err) {
+		t.Errorf("get the throttled work = %v, want not found", err)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// workGeneratorConditionTypes are the types of the binding conditions that the work generator owns; the other
+// conditions, e.g. RolloutStarted, are owned by the other controllers and are never written by the work generator.
+var workGeneratorConditionTypes = sets.New(
+	string(fleetv1beta1.ResourceBindingOverridden),
+	string(fleetv1beta1.ResourceBindingWorkSynchronized),
+	string(fleetv1beta1.ResourceBindingWorkSyncThrottled),
+	string(fleetv1beta1.ResourceBindingClusterGone),
+	string(fleetv1beta1.ResourceBindingResourcesDeleted),
+	string(fleetv1beta1.ResourceBindingApplied),
+	string(fleetv1beta1.ResourceBindingAvailable),
+)
+
+// bindingStatusUpdate is a pending status write of a binding.
+type bindingStatusUpdate struct {
+	binding *fleetv1beta1.ClusterResourceBinding
+	// clearFailedPlacements is true if the failed placements must be removed from the binding, as they may be owned
+	// by another field manager, e.g. a hub agent which updated the status before the server-side apply was in use.
+	clearFailedPlacements bool
+}
+
+// bindingStatusWriter batches the status writes of the bindings: the writes issued within an interval are coalesced
+// per binding, so that only the latest status of a binding is written, and are flushed at the end of the interval
+// in parallel.
+type bindingStatusWriter struct {
+	client   client.Client
+	interval time.Duration
+	workers  int
+
+	mu      sync.Mutex
+	pending map[string]*bindingStatusUpdate
+}
+
+// newBindingStatusWriter returns a status writer which flushes the status of the bindings every interval with the
+// given number of workers.
+func newBindingStatusWriter(c client.Client, interval time.Duration, workers int) *bindingStatusWriter {
+	if workers < 1 {
+		workers = 1
+	}
+	return &bindingStatusWriter{
+		client:   c,
+		interval: interval,
+		workers:  workers,
+		pending:  make(map[string]*bindingStatusUpdate),
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface; the writer flushes whatever the work generator
+// of the replica enqueues.
+func (w *bindingStatusWriter) NeedLeaderElection() bool {
+	return false
+}
+
+// Start flushes the pending status writes every interval until the context is done, and once more on exit.
+func (w *bindingStatusWriter) Start(ctx context.Context) error {
+	klog.V(2).InfoS("Starting the binding status writer", "interval", w.interval)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			w.flush(flushCtx)
+			cancel()
+			klog.V(2).InfoS("The binding status writer is stopped")
+			return nil
+		case <-ticker.C:
+			w.flush(ctx)
+		}
+	}
+}
+
+// enqueue queues the status of the binding to write in the next flush, replacing the status queued earlier. The
+// status is not written if it is the same as the original one and no other status is queued for the binding.
+func (w *bindingStatusWriter) enqueue(original, binding *fleetv1beta1.ClusterResourceBinding) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev, queued := w.pending[binding.Name]
+	if !queued && equality.Semantic.DeepEqual(original.Status, binding.Status) {
+		return
+	}
+	update := &bindingStatusUpdate{
+		binding:               binding.DeepCopy(),
+		clearFailedPlacements: len(original.Status.FailedPlacements) > 0 && len(binding.Status.FailedPlacements) == 0,
+	}
+	if queued && prev.clearFailedPlacements && len(binding.Status.FailedPlacements) == 0 {
+		update.clearFailedPlacements = true
+	}
+	w.pending[binding.Name] = update
+}
+
+// flush writes all the pending status; a write which fails is retried in the next flush unless a newer status of
+// the binding is queued meanwhile.
+func (w *bindingStatusWriter) flush(ctx context.Context) {
+	w.mu.Lock()
+	if len(w.pending) == 0 {
+		w.mu.Unlock()
+		return
+	}
+	updates := make([]*bindingStatusUpdate, 0, len(w.pending))
+	for _, update := range w.pending {
+		updates = append(updates, update)
+	}
+	w.pending = make(map[string]*bindingStatusUpdate, len(updates))
+	w.mu.Unlock()
+
+	klog.V(2).InfoS("Flushing the status of the bindings", "count", len(updates))
+	workqueue.ParallelizeUntil(ctx, w.workers, len(updates), func(i int) {
+		update := updates[i]
+		err := applyBindingStatus(ctx, w.client, update.binding, update.clearFailedPlacements)
+		switch {
+		case err == nil:
+		case apierrors.IsNotFound(err) || apierrors.IsConflict(err):
+			// The binding is deleted or replaced by another one of the same name.
+			klog.V(2).InfoS("Dropping the status of a binding which is gone", "resourceBinding", klog.KObj(update.binding), "error", err)
+		default:
+			klog.ErrorS(err, "Failed to write the resourceBinding status, will retry", "resourceBinding", klog.KObj(update.binding))
+			w.mu.Lock()
+			if _, queued := w.pending[update.binding.Name]; !queued {
+				w.pending[update.binding.Name] = update
+			}
+			w.mu.Unlock()
+		}
+	})
+}
+
+// applyBindingStatus writes the status owned by the work generator, i.e. its conditions and the failed placements,
+// to the binding with server-side apply, so that the writes of the other controllers to the status do not conflict
+// with it. If clearFailedPlacements is set, the failed placements are removed first with a merge patch, as the apply
+// only removes the fields owned by the work generator.
+func applyBindingStatus(ctx context.Context, c client.Client, binding *fleetv1beta1.ClusterResourceBinding, clearFailedPlacements bool) error {
+	if clearFailedPlacements {
+		patch := client.RawPatch(types.MergePatchType, []byte(`{"status":{"failedPlacements":null}}`))
+		if err := c.Status().Patch(ctx, binding.DeepCopy(), patch, client.FieldOwner(utils.WorkGeneratorStatusFieldManagerName)); err != nil {
+			return controller.NewAPIServerError(false, err)
+		}
+	}
+	if err := c.Status().Patch(ctx, bindingStatusApplyObject(binding), client.Apply,
+		client.FieldOwner(utils.WorkGeneratorStatusFieldManagerName), client.ForceOwnership); err != nil {
+		return controller.NewAPIServerError(false, err)
+	}
+	return nil
+}
+
+// bindingStatusApplyObject returns the apply configuration of the status of the binding owned by the work generator.
+// The UID of the binding makes the apply fail if the binding is replaced by another one of the same name.
+func bindingStatusApplyObject(binding *fleetv1beta1.ClusterResourceBinding) *fleetv1beta1.ClusterResourceBinding {
+	obj := &fleetv1beta1.ClusterResourceBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fleetv1beta1.GroupVersion.String(),
+			Kind:       fleetv1beta1.ClusterResourceBindingKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: binding.Name,
+			UID:  binding.UID,
+		},
+		Status: fleetv1beta1.ResourceBindingStatus{
+			FailedPlacements:         binding.Status.FailedPlacements,
+			FailedPlacementsOverflow: binding.Status.FailedPlacementsOverflow,
+			LoadBalancers:            binding.Status.LoadBalancers,
+			Conditions:               make([]metav1.Condition, 0, len(binding.Status.Conditions)),
+		},
+	}
+	for _, cond := range binding.Status.Conditions {
+		if workGeneratorConditionTypes.Has(cond.Type) {
+			obj.Status.Conditions = append(obj.Status.Conditions, cond)
+		}
+	}
+	return obj
+}
@@ -0,0 +1,178 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// statusPatch is a status patch sent by the status writer.
+type statusPatch struct {
+	name      string
+	patchType types.PatchType
+	status    fleetv1beta1.ResourceBindingStatus
+}
+
+// newStatusPatchRecorder returns a client which records the status patches instead of sending them, as the fake
+// client does not support server-side apply, and the recorded patches; only the status of the apply patches is
+// recorded.
+func newStatusPatchRecorder(t *testing.T, patchErr func(patchType types.PatchType) error) (client.Client, func() []statusPatch) {
+	var mu sync.Mutex
+	var patches []statusPatch
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	c := interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), interceptor.Funcs{
+		SubResourcePatch: func(_ context.Context, _ client.Client, subResourceName string, obj client.Object, patch client.Patch, _ ...client.SubResourcePatchOption) error {
+			if subResourceName != "status" {
+				t.Errorf("patched subresource %s, want status", subResourceName)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			p := statusPatch{name: obj.GetName(), patchType: patch.Type()}
+			if patch.Type() == types.ApplyPatchType {
+				p.status = obj.(*fleetv1beta1.ClusterResourceBinding).Status
+			}
+			patches = append(patches, p)
+			if patchErr != nil {
+				return patchErr(patch.Type())
+			}
+			return nil
+		},
+	})
+	return c, func() []statusPatch {
+		mu.Lock()
+		defer mu.Unlock()
+		return patches
+	}
+}
+
+func bindingWithStatus(name string, failedPlacements []fleetv1beta1.FailedResourcePlacement, conds ...metav1.Condition) *fleetv1beta1.ClusterResourceBinding {
+	return &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")},
+		Status: fleetv1beta1.ResourceBindingStatus{
+			FailedPlacements: failedPlacements,
+			Conditions:       conds,
+		},
+	}
+}
+
+var (
+	appliedCond = metav1.Condition{
+		Type:   string(fleetv1beta1.ResourceBindingApplied),
+		Status: metav1.ConditionTrue,
+		Reason: condition.AllWorkAppliedReason,
+	}
+	notAppliedCond = metav1.Condition{
+		Type:   string(fleetv1beta1.ResourceBindingApplied),
+		Status: metav1.ConditionFalse,
+		Reason: condition.WorkNeedSyncedReason,
+	}
+	rolloutStartedCond = metav1.Condition{
+		Type:   string(fleetv1beta1.ResourceBindingRolloutStarted),
+		Status: metav1.ConditionTrue,
+		Reason: condition.RolloutStartedReason,
+	}
+	failedPlacements = []fleetv1beta1.FailedResourcePlacement{
+		{ResourceIdentifier: fleetv1beta1.ResourceIdentifier{Kind: "ConfigMap", Name: "app", Namespace: "app"}},
+	}
+)
+
+func TestBindingStatusApplyObject(t *testing.T) {
+	binding := bindingWithStatus("binding", failedPlacements, rolloutStartedCond, notAppliedCond)
+	binding.Spec.TargetCluster = "member-1"
+	binding.ResourceVersion = "3"
+	want := &fleetv1beta1.ClusterResourceBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fleetv1beta1.GroupVersion.String(),
+			Kind:       fleetv1beta1.ClusterResourceBindingKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "binding", UID: "binding-uid"},
+		Status: fleetv1beta1.ResourceBindingStatus{
+			FailedPlacements: failedPlacements,
+			Conditions:       []metav1.Condition{notAppliedCond},
+		},
+	}
+	if diff := cmp.Diff(want, bindingStatusApplyObject(binding)); diff != "" {
+		t.Errorf("bindingStatusApplyObject() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestBindingStatusWriter(t *testing.T) {
+	c, patches := newStatusPatchRecorder(t, nil)
+	w := newBindingStatusWriter(c, time.Second, 2)
+
+	// unchanged status is not written
+	unchanged := bindingWithStatus("unchanged", nil, appliedCond)
+	w.enqueue(unchanged, unchanged.DeepCopy())
+	// the writes of a binding are coalesced to the latest one
+	original := bindingWithStatus("coalesced", failedPlacements, rolloutStartedCond, notAppliedCond)
+	w.enqueue(original, bindingWithStatus("coalesced", failedPlacements, rolloutStartedCond, notAppliedCond, metav1.Condition{
+		Type:   string(fleetv1beta1.ResourceBindingWorkSynchronized),
+		Status: metav1.ConditionTrue,
+		Reason: condition.AllWorkSyncedReason,
+	}))
+	w.enqueue(original, bindingWithStatus("coalesced", nil, rolloutStartedCond, appliedCond))
+	// a status which changes back to the original one is still written to replace the queued one
+	reverted := bindingWithStatus("reverted", nil, notAppliedCond)
+	w.enqueue(reverted, bindingWithStatus("reverted", nil, appliedCond))
+	w.enqueue(reverted, reverted.DeepCopy())
+
+	w.flush(context.Background())
+	want := []statusPatch{
+		{name: "coalesced", patchType: types.MergePatchType},
+		{name: "coalesced", patchType: types.ApplyPatchType, status: fleetv1beta1.ResourceBindingStatus{Conditions: []metav1.Condition{appliedCond}}},
+		{name: "reverted", patchType: types.ApplyPatchType, status: fleetv1beta1.ResourceBindingStatus{Conditions: []metav1.Condition{notAppliedCond}}},
+	}
+	sortPatches := cmpopts.SortSlices(func(a, b statusPatch) bool {
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		return a.patchType < b.patchType
+	})
+	if diff := cmp.Diff(want, patches(), cmp.AllowUnexported(statusPatch{}), sortPatches); diff != "" {
+		t.Errorf("status patches mismatch (-want, +got):\n%s", diff)
+	}
+	if len(w.pending) != 0 {
+		t.Errorf("pending writes = %d after a flush, want 0", len(w.pending))
+	}
+}
+
+func TestBindingStatusWriter_Retry(t *testing.T) {
+	c, patches := newStatusPatchRecorder(t, func(types.PatchType) error { return errors.New("the API server is unavailable") })
+	w := newBindingStatusWriter(c, time.Second, 1)
+	original := bindingWithStatus("binding", nil)
+	w.enqueue(original, bindingWithStatus("binding", nil, appliedCond))
+
+	w.flush(context.Background())
+	if got := len(patches()); got != 1 {
+		t.Fatalf("status patches = %d, want 1", got)
+	}
+	update, queued := w.pending["binding"]
+	if !queued {
+		t.Fatalf("the failed write is not queued for a retry")
+	}
+	if diff := cmp.Diff([]metav1.Condition{appliedCond}, update.binding.Status.Conditions); diff != "" {
+		t.Errorf("queued status mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -35,8 +35,9 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/fleettest"
 	"go.goms.io/fleet/pkg/utils"
-	"go.goms.io/fleet/test/utils/informer"
+	"go.goms.io/fleet/pkg/utils/index"
 )
 
 var (
@@ -107,8 +108,12 @@ var _ = BeforeSuite(func() {
 	// make sure the k8s client is same as the controller client or we can have cache delay
 	By("set k8s client same as the controller manager")
 	k8sClient = mgr.GetClient()
+	// add the field indexes that the hub agent adds for the work generator
+	Expect(index.AddResourceSnapshotGroupIndex(ctx, mgr.GetFieldIndexer())).Should(Succeed())
+	Expect(index.AddBindingCRPIndex(ctx, mgr.GetFieldIndexer())).Should(Succeed())
+	Expect(index.AddWorkBindingIndex(ctx, mgr.GetFieldIndexer())).Should(Succeed())
 	// setup our main reconciler
-	fakeInformer := informer.FakeManager{
+	fakeInformer := fleettest.FakeInformerManager{
 		APIResources: map[schema.GroupVersionKind]bool{
 			{
 				Group:   "apiextensions.k8s.io",
@@ -0,0 +1,115 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/placementquota"
+)
+
+// tenantQuotaRecheckInterval is how often a binding whose works exceed the placement quota of its tenant is checked
+// again, as the usage of the tenant changes with the other placements.
+const tenantQuotaRecheckInterval = time.Minute
+
+// tenantQuotaExceededError is returned when the works of a binding are not generated because the resources they place
+// exceed the resource limit of the placement quota of a tenant of the placement.
+type tenantQuotaExceededError struct {
+	namespace    string
+	maxResources int32
+	used         int
+	requested    int
+}
+
+func (e *tenantQuotaExceededError) Error() string {
+	return fmt.Sprintf("placing %d resources exceeds the limit of %d resources of the placement quota in namespace %s, of which the placements of the tenant place %d already",
+		e.requested, e.maxResources, e.namespace, e.used)
+}
+
+// checkTenantResourceQuota returns a tenantQuotaExceededError if placing the resources of the snapshots on the target
+// cluster of the binding exceeds the resource limit of the placement quota of a tenant of the placement.
+//
+// The resources which the other placements of the tenant place are counted by the selected resources in their status
+// on each of their bound clusters, except the ones whose works are held back by the quota too; the bindings of the
+// placement itself are counted in the order of their target clusters, so that the quota admits the same clusters of
+// the placement in every reconciliation.
+func (r *Reconciler) checkTenantResourceQuota(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding, resourceSnapshots map[string]*fleetv1beta1.ClusterResourceSnapshot) (*tenantQuotaExceededError, error) {
+	crpName := resourceBinding.GetLabels()[fleetv1beta1.CRPTrackingLabel]
+	var crp fleetv1beta1.ClusterResourcePlacement
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: crpName}, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the placement is being deleted along with its bindings
+			return nil, nil
+		}
+		klog.ErrorS(err, "Failed to get the clusterResourcePlacement of the binding", "resourceBinding", klog.KObj(resourceBinding), "clusterResourcePlacement", crpName)
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	quotas, err := placementquota.FetchQuotas(ctx, r.Client, &crp)
+	if err != nil {
+		return nil, err
+	}
+	requested := 0
+	for _, snapshot := range resourceSnapshots {
+		requested += len(snapshot.Spec.SelectedResources)
+	}
+	for _, quota := range quotas {
+		if quota.MaxResources == nil {
+			continue
+		}
+		used, err := r.countTenantResources(ctx, resourceBinding, quota.Namespace, requested)
+		if err != nil {
+			return nil, err
+		}
+		if used+requested > int(*quota.MaxResources) {
+			return &tenantQuotaExceededError{namespace: quota.Namespace, maxResources: *quota.MaxResources, used: used, requested: requested}, nil
+		}
+	}
+	return nil, nil
+}
+
+// countTenantResources counts the resources which the placements of the tenant place, besides the ones of the binding.
+func (r *Reconciler) countTenantResources(ctx context.Context, resourceBinding *fleetv1beta1.ClusterResourceBinding, namespace string, requested int) (int, error) {
+	crps, err := placementquota.TenantPlacements(ctx, r.Client, namespace)
+	if err != nil {
+		return 0, err
+	}
+	crpName := resourceBinding.GetLabels()[fleetv1beta1.CRPTrackingLabel]
+	used := 0
+	for i := range crps {
+		var bindingList fleetv1beta1.ClusterResourceBindingList
+		if err := r.Client.List(ctx, &bindingList, client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crps[i].Name}); err != nil {
+			klog.ErrorS(err, "Failed to list the bindings of the clusterResourcePlacement", "clusterResourcePlacement", klog.KObj(&crps[i]))
+			return 0, controller.NewAPIServerError(true, err)
+		}
+		for j := range bindingList.Items {
+			binding := &bindingList.Items[j]
+			if binding.Name == resourceBinding.Name || !binding.DeletionTimestamp.IsZero() || binding.Spec.State != fleetv1beta1.BindingStateBound {
+				continue
+			}
+			if crps[i].Name == crpName {
+				if binding.Spec.TargetCluster < resourceBinding.Spec.TargetCluster {
+					used += requested
+				}
+				continue
+			}
+			if cond := meta.FindStatusCondition(binding.Status.Conditions, string(fleetv1beta1.ResourceBindingWorkSynchronized)); cond != nil && cond.Reason == condition.TenantQuotaExceededReason {
+				continue
+			}
+			used += len(crps[i].Status.SelectedResources)
+		}
+	}
+	return used, nil
+}
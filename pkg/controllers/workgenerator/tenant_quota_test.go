@@ -0,0 +1,137 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workgenerator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+func TestCheckTenantResourceQuota(t *testing.T) {
+	newCRP := func(name string, selectedResources int) *fleetv1beta1.ClusterResourcePlacement {
+		crp := &fleetv1beta1.ClusterResourcePlacement{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+				ResourceSelectors: []fleetv1beta1.ClusterResourceSelector{{Group: "", Version: "v1", Kind: "Namespace", Name: "team-a"}},
+			},
+		}
+		for i := 0; i < selectedResources; i++ {
+			crp.Status.SelectedResources = append(crp.Status.SelectedResources, fleetv1beta1.ResourceIdentifier{Kind: "ConfigMap"})
+		}
+		return crp
+	}
+	newBinding := func(crp, cluster string, conditions ...metav1.Condition) *fleetv1beta1.ClusterResourceBinding {
+		return &fleetv1beta1.ClusterResourceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   crp + "-" + cluster,
+				Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crp},
+			},
+			Spec:   fleetv1beta1.ResourceBindingSpec{State: fleetv1beta1.BindingStateBound, TargetCluster: cluster},
+			Status: fleetv1beta1.ResourceBindingStatus{Conditions: conditions},
+		}
+	}
+	quota := &fleetv1beta1.PlacementQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "team-a"},
+		Spec:       fleetv1beta1.PlacementQuotaSpec{MaxResources: ptr.To(int32(10))},
+	}
+	// the binding being checked places 3 resources on cluster-b
+	snapshots := map[string]*fleetv1beta1.ClusterResourceSnapshot{
+		"test-crp-1-snapshot": {
+			Spec: fleetv1beta1.ResourceSnapshotSpec{SelectedResources: make([]fleetv1beta1.ResourceContent, 3)},
+		},
+	}
+	tests := map[string]struct {
+		objects []client.Object
+		wantErr *tenantQuotaExceededError
+	}{
+		"no placement quota": {
+			objects: []client.Object{
+				newCRP("test-crp", 3),
+				newBinding("test-crp", "cluster-a"),
+				newBinding("test-crp", "cluster-b"),
+				newBinding("test-crp", "cluster-c"),
+				newBinding("test-crp", "cluster-d"),
+			},
+		},
+		"the resources fit in the quota": {
+			objects: []client.Object{
+				quota,
+				newCRP("test-crp", 3),
+				newCRP("other-crp", 3),
+				newBinding("test-crp", "cluster-a"),
+				newBinding("test-crp", "cluster-b"),
+				newBinding("test-crp", "cluster-c"),
+				newBinding("other-crp", "cluster-a"),
+			},
+		},
+		"the resources of the other placements exceed the quota": {
+			objects: []client.Object{
+				quota,
+				newCRP("test-crp", 3),
+				newCRP("other-crp", 3),
+				newBinding("test-crp", "cluster-a"),
+				newBinding("test-crp", "cluster-b"),
+				newBinding("other-crp", "cluster-a"),
+				newBinding("other-crp", "cluster-b"),
+			},
+			wantErr: &tenantQuotaExceededError{namespace: "team-a", maxResources: 10, used: 9, requested: 3},
+		},
+		"the bindings held back by the quota are not counted": {
+			objects: []client.Object{
+				quota,
+				newCRP("test-crp", 3),
+				newCRP("other-crp", 3),
+				newBinding("test-crp", "cluster-a"),
+				newBinding("test-crp", "cluster-b"),
+				newBinding("other-crp", "cluster-a"),
+				newBinding("other-crp", "cluster-b", metav1.Condition{
+					Type:   string(fleetv1beta1.ResourceBindingWorkSynchronized),
+					Status: metav1.ConditionFalse,
+					Reason: condition.TenantQuotaExceededReason,
+				}),
+			},
+		},
+		"the bindings of the placement on the clusters which sort before are counted first": {
+			objects: []client.Object{
+				&fleetv1beta1.PlacementQuota{
+					ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "team-a"},
+					Spec:       fleetv1beta1.PlacementQuotaSpec{MaxResources: ptr.To(int32(5))},
+				},
+				newCRP("test-crp", 3),
+				newBinding("test-crp", "cluster-a"),
+				newBinding("test-crp", "cluster-b"),
+				newBinding("test-crp", "cluster-c"),
+			},
+			wantErr: &tenantQuotaExceededError{namespace: "team-a", maxResources: 5, used: 3, requested: 3},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			r := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()}
+			gotErr, err := r.checkTenantResourceQuota(context.Background(), newBinding("test-crp", "cluster-b"), snapshots)
+			if err != nil {
+				t.Fatalf("checkTenantResourceQuota() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.wantErr, gotErr, cmp.AllowUnexported(tenantQuotaExceededError{})); diff != "" {
+				t.Errorf("checkTenantResourceQuota() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
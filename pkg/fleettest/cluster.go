@@ -0,0 +1,261 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fleettest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/keys"
+)
+
+// FakeCluster is an in-memory test double of a hub or a member cluster of the fleet, with the same clients as the
+// cluster of the e2e framework.
+//
+// KubeClient and DynamicClient are backed by separate in-memory stores: the objects created with one of them cannot
+// be read with the other.
+type FakeCluster struct {
+	// ClusterName is the name of the cluster, which is also the name of the member cluster on the hub cluster.
+	ClusterName string
+	// Scheme is the scheme of the KubeClient.
+	Scheme *runtime.Scheme
+	// KubeClient is a fake client backed by the objects the cluster is created with; the status subresources of the
+	// fleet APIs registered in the scheme are enabled.
+	KubeClient client.Client
+	// DynamicClient is a fake dynamic client, to which the manifests of the works are applied by ApplyWorks.
+	DynamicClient *dynamicfake.FakeDynamicClient
+	// RestMapper maps the kinds served by the cluster, which are added with ServeResource.
+	RestMapper *meta.DefaultRESTMapper
+
+	mu        sync.Mutex
+	behaviors []applyBehaviorRule
+}
+
+// ApplyBehavior is how the fake member agent applies the manifests of the works in ApplyWorks.
+type ApplyBehavior struct {
+	// ApplyError, if set, fails the apply of the manifests with the error.
+	ApplyError error
+	// NotAvailable, if set, makes the manifests not available yet after they are applied.
+	NotAvailable bool
+}
+
+// applyBehaviorRule is an ApplyBehavior for the manifests matched by the pattern.
+type applyBehaviorRule struct {
+	pattern  keys.KeyPattern
+	behavior ApplyBehavior
+}
+
+// NewFakeCluster returns a fake cluster with the given objects for the KubeClient.
+func NewFakeCluster(name string, scheme *runtime.Scheme, objs ...client.Object) *FakeCluster {
+	var statusObjs []client.Object
+	for _, obj := range []client.Object{
+		&fleetv1beta1.Work{},
+		&fleetv1beta1.AppliedWork{},
+		&fleetv1beta1.ClusterResourcePlacement{},
+		&fleetv1beta1.ClusterResourceBinding{},
+		&clusterv1beta1.MemberCluster{},
+		&clusterv1beta1.InternalMemberCluster{},
+	} {
+		if gvks, _, err := scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+			statusObjs = append(statusObjs, obj)
+		}
+	}
+	return &FakeCluster{
+		ClusterName: name,
+		Scheme:      scheme,
+		KubeClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+			WithStatusSubresource(statusObjs...).Build(),
+		DynamicClient: dynamicfake.NewSimpleDynamicClient(scheme),
+		RestMapper:    meta.NewDefaultRESTMapper(nil),
+	}
+}
+
+// ServeResource makes the kind served by the cluster, so that the manifests of the kind can be applied to it.
+func (c *FakeCluster) ServeResource(gvk schema.GroupVersionKind, namespaced bool) {
+	scope := meta.RESTScopeRoot
+	if namespaced {
+		scope = meta.RESTScopeNamespace
+	}
+	c.RestMapper.Add(gvk, scope)
+}
+
+// SetApplyBehavior sets how the manifests matched by the pattern are applied by ApplyWorks; the behavior set first
+// wins when a manifest is matched by many patterns. The manifests which are not matched are applied and available.
+func (c *FakeCluster) SetApplyBehavior(pattern keys.KeyPattern, behavior ApplyBehavior) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.behaviors = append(c.behaviors, applyBehaviorRule{pattern: pattern, behavior: behavior})
+}
+
+// ApplyWorks stands in for the member agent of the cluster: it applies the manifests of the works in the namespace of
+// the member cluster on the hub cluster to the DynamicClient, and reports the results in the statuses of the works.
+//
+// The manifests of the kinds which are not served by the cluster fail to apply.
+func (c *FakeCluster) ApplyWorks(ctx context.Context, hubClient client.Client) error {
+	var works fleetv1beta1.WorkList
+	if err := hubClient.List(ctx, &works, client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, c.ClusterName))); err != nil {
+		return err
+	}
+	for i := range works.Items {
+		w := &works.Items[i]
+		if w.DeletionTimestamp != nil {
+			continue
+		}
+		c.applyWork(ctx, w)
+		if err := hubClient.Status().Update(ctx, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyWork applies the manifests of the work and sets the status of the work.
+func (c *FakeCluster) applyWork(ctx context.Context, w *fleetv1beta1.Work) {
+	allApplied, allAvailable := true, true
+	manifestConditions := make([]fleetv1beta1.ManifestCondition, 0, len(w.Spec.Workload.Manifests))
+	for i := range w.Spec.Workload.Manifests {
+		manifestCondition, applied, available := c.applyManifest(ctx, i, &w.Spec.Workload.Manifests[i], w.Generation)
+		allApplied = allApplied && applied
+		allAvailable = allAvailable && available
+		manifestConditions = append(manifestConditions, manifestCondition)
+	}
+	w.Status.ManifestConditions = manifestConditions
+
+	appliedCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             work.WorkAppliedCompletedReason,
+		ObservedGeneration: w.Generation,
+	}
+	availableCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             work.WorkAvailableReason,
+		ObservedGeneration: w.Generation,
+	}
+	if !allApplied {
+		appliedCondition.Status = metav1.ConditionFalse
+		appliedCondition.Reason = work.WorkAppliedFailedReason
+	}
+	if !allApplied || !allAvailable {
+		availableCondition.Status = metav1.ConditionFalse
+		availableCondition.Reason = work.WorkNotAvailableYetReason
+	}
+	meta.SetStatusCondition(&w.Status.Conditions, appliedCondition)
+	meta.SetStatusCondition(&w.Status.Conditions, availableCondition)
+}
+
+// applyManifest applies the manifest and returns its condition, and whether it is applied and available.
+func (c *FakeCluster) applyManifest(ctx context.Context, ordinal int, manifest *fleetv1beta1.Manifest, generation int64) (fleetv1beta1.ManifestCondition, bool, bool) {
+	appliedCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeApplied,
+		Status:             metav1.ConditionFalse,
+		Reason:             work.ManifestApplyFailedReason,
+		ObservedGeneration: generation,
+	}
+	availableCondition := metav1.Condition{
+		Type:               fleetv1beta1.WorkConditionTypeAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             work.ManifestApplyFailedReason,
+		ObservedGeneration: generation,
+	}
+	manifestCondition := fleetv1beta1.ManifestCondition{Identifier: fleetv1beta1.WorkResourceIdentifier{Ordinal: ordinal}}
+	setConditions := func() fleetv1beta1.ManifestCondition {
+		meta.SetStatusCondition(&manifestCondition.Conditions, appliedCondition)
+		meta.SetStatusCondition(&manifestCondition.Conditions, availableCondition)
+		return manifestCondition
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(manifest.Raw); err != nil {
+		appliedCondition.Message = fmt.Sprintf("Failed to decode the manifest: %v", err)
+		return setConditions(), false, false
+	}
+	gvk := obj.GroupVersionKind()
+	manifestCondition.Identifier = fleetv1beta1.WorkResourceIdentifier{
+		Ordinal:   ordinal,
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+	mapping, err := c.RestMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		appliedCondition.Message = fmt.Sprintf("The kind of the manifest is not served: %v", err)
+		return setConditions(), false, false
+	}
+	manifestCondition.Identifier.Resource = mapping.Resource.Resource
+
+	behavior := c.applyBehavior(keys.ClusterWideKey{ResourceIdentifier: fleetv1alpha1.ResourceIdentifier{
+		Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName(),
+	}})
+	if behavior.ApplyError == nil {
+		behavior.ApplyError = c.apply(ctx, mapping, obj)
+	}
+	if behavior.ApplyError != nil {
+		appliedCondition.Message = fmt.Sprintf("Failed to apply the manifest: %v", behavior.ApplyError)
+		return setConditions(), false, false
+	}
+
+	appliedCondition.Status = metav1.ConditionTrue
+	appliedCondition.Reason = work.WorkAppliedCompletedReason
+	availableCondition.Status = metav1.ConditionTrue
+	availableCondition.Reason = work.WorkAvailableReason
+	if behavior.NotAvailable {
+		availableCondition.Status = metav1.ConditionFalse
+		availableCondition.Reason = work.WorkNotAvailableYetReason
+	}
+	return setConditions(), true, !behavior.NotAvailable
+}
+
+// apply creates or updates the object with the DynamicClient.
+func (c *FakeCluster) apply(ctx context.Context, mapping *meta.RESTMapping, obj *unstructured.Unstructured) error {
+	var resourceClient dynamic.ResourceInterface = c.DynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resourceClient = c.DynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	}
+	current, err := resourceClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = resourceClient.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	case err != nil:
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	_, err = resourceClient.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// applyBehavior returns the behavior of the first pattern which matches the key.
+func (c *FakeCluster) applyBehavior(key keys.ClusterWideKey) ApplyBehavior {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rule := range c.behaviors {
+		if key.Matches(rule.pattern) {
+			return rule.behavior
+		}
+	}
+	return ApplyBehavior{}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fleettest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/controllers/work"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/keys"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() = %v, want nil", err)
+	}
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() = %v, want nil", err)
+	}
+	return scheme
+}
+
+func newWork(cluster string, manifests ...string) *fleetv1beta1.Work {
+	w := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "work",
+			Namespace:  fmt.Sprintf(utils.NamespaceNameFormat, cluster),
+			Generation: 1,
+		},
+	}
+	for _, manifest := range manifests {
+		w.Spec.Workload.Manifests = append(w.Spec.Workload.Manifests, fleetv1beta1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(manifest)}})
+	}
+	return w
+}
+
+func TestApplyWorks(t *testing.T) {
+	configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"}}`
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"app"}}`
+	widget := `{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"widget"}}`
+	tests := map[string]struct {
+		manifests []string
+		behaviors map[keys.KeyPattern]ApplyBehavior
+		// wantManifests are the applied and available reasons of the manifests.
+		wantManifests [][2]string
+		wantApplied   string
+		wantAvailable string
+	}{
+		"all the manifests are applied and available": {
+			manifests:     []string{configMap, deployment},
+			wantManifests: [][2]string{{work.WorkAppliedCompletedReason, work.WorkAvailableReason}, {work.WorkAppliedCompletedReason, work.WorkAvailableReason}},
+			wantApplied:   work.WorkAppliedCompletedReason,
+			wantAvailable: work.WorkAvailableReason,
+		},
+		"the manifests of the kinds not served fail to apply": {
+			manifests:     []string{configMap, widget},
+			wantManifests: [][2]string{{work.WorkAppliedCompletedReason, work.WorkAvailableReason}, {work.ManifestApplyFailedReason, work.ManifestApplyFailedReason}},
+			wantApplied:   work.WorkAppliedFailedReason,
+			wantAvailable: work.WorkNotAvailableYetReason,
+		},
+		"the apply behaviors are injected": {
+			manifests: []string{configMap, deployment},
+			behaviors: map[keys.KeyPattern]ApplyBehavior{
+				{Group: "apps", Kind: "Deploy*"}: {NotAvailable: true},
+			},
+			wantManifests: [][2]string{{work.WorkAppliedCompletedReason, work.WorkAvailableReason}, {work.WorkAppliedCompletedReason, work.WorkNotAvailableYetReason}},
+			wantApplied:   work.WorkAppliedCompletedReason,
+			wantAvailable: work.WorkNotAvailableYetReason,
+		},
+		"the apply failures are injected": {
+			manifests: []string{configMap},
+			behaviors: map[keys.KeyPattern]ApplyBehavior{
+				{Kind: "ConfigMap", Namespace: "app"}: {ApplyError: errors.New("denied by webhook")},
+			},
+			wantManifests: [][2]string{{work.ManifestApplyFailedReason, work.ManifestApplyFailedReason}},
+			wantApplied:   work.WorkAppliedFailedReason,
+			wantAvailable: work.WorkNotAvailableYetReason,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := newScheme(t)
+			hub := NewFakeCluster("hub", scheme, newWork("member-1", tc.manifests...))
+			member := NewFakeCluster("member-1", scheme)
+			member.ServeResource(utils.ConfigMapGVK, true)
+			member.ServeResource(utils.DeploymentGVK, true)
+			for pattern, behavior := range tc.behaviors {
+				member.SetApplyBehavior(pattern, behavior)
+			}
+
+			if err := member.ApplyWorks(ctx, hub.KubeClient); err != nil {
+				t.Fatalf("ApplyWorks() = %v, want nil", err)
+			}
+
+			var got fleetv1beta1.Work
+			if err := hub.KubeClient.Get(ctx, client.ObjectKey{Namespace: "fleet-member-member-1", Name: "work"}, &got); err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			gotManifests := make([][2]string, 0, len(got.Status.ManifestConditions))
+			for _, manifestCondition := range got.Status.ManifestConditions {
+				gotManifests = append(gotManifests, [2]string{
+					meta.FindStatusCondition(manifestCondition.Conditions, fleetv1beta1.WorkConditionTypeApplied).Reason,
+					meta.FindStatusCondition(manifestCondition.Conditions, fleetv1beta1.WorkConditionTypeAvailable).Reason,
+				})
+			}
+			if diff := cmp.Diff(tc.wantManifests, gotManifests); diff != "" {
+				t.Errorf("ApplyWorks() manifest reasons mismatch (-want, +got):\n%s", diff)
+			}
+			wantConditions := []metav1.Condition{
+				{Type: fleetv1beta1.WorkConditionTypeApplied, Reason: tc.wantApplied, ObservedGeneration: 1},
+				{Type: fleetv1beta1.WorkConditionTypeAvailable, Reason: tc.wantAvailable, ObservedGeneration: 1},
+			}
+			if diff := cmp.Diff(wantConditions, got.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "Status", "LastTransitionTime")); diff != "" {
+				t.Errorf("ApplyWorks() work conditions mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyWorksCreatesTheManifests(t *testing.T) {
+	ctx := context.Background()
+	scheme := newScheme(t)
+	hub := NewFakeCluster("hub", scheme, newWork("member-1", `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"app"},"data":{"key":"value"}}`))
+	member := NewFakeCluster("member-1", scheme)
+	member.ServeResource(utils.ConfigMapGVK, true)
+
+	// the manifests are updated when they are applied again
+	for i := 0; i < 2; i++ {
+		if err := member.ApplyWorks(ctx, hub.KubeClient); err != nil {
+			t.Fatalf("ApplyWorks() = %v, want nil", err)
+		}
+	}
+	got, err := member.DynamicClient.Resource(utils.ConfigMapGVR).Namespace("app").Get(ctx, "config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"key": "value"}, got.Object["data"]); diff != "" {
+		t.Errorf("ApplyWorks() applied data mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,19 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package fleettest provides test doubles of the fleet, so that the controllers built on top of the fleet can be unit
+// tested without spinning up any clusters.
+//
+// FakeInformerManager stands in for the informer manager of the hub agent; the resources it serves, whether their
+// informers are synced and the errors of their listers can all be configured, and the objects set on it are delivered
+// to the registered event handlers like a real informer would do.
+//
+// FakeCluster stands in for a hub or a member cluster with in-memory clients, in place of the cluster of the e2e
+// framework which needs a real cluster; FakeCluster.ApplyWorks stands in for the member agent applying the works of
+// the member cluster.
+//
+// The test doubles are supported for the use of the downstream projects: their behaviors are kept compatible across
+// the releases, and they are tested along with the fleet itself.
+package fleettest
@@ -0,0 +1,272 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fleettest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	"go.goms.io/fleet/pkg/utils/informer"
+)
+
+// FakeInformerManager is a fake informer.Manager backed by in-memory stores.
+//
+// A resource is served once it is added with ServeResource, AddDynamicResources or AddStaticResource; the informer of
+// a served resource is synced unless it is changed with SetInformerSynced, and its lister lists the objects set with
+// SetObject. The resources which are not served have no lister.
+//
+// The zero value is ready to use; it serves no resources.
+type FakeInformerManager struct {
+	// APIResources collects the resources whose scopes are defined by IsClusterScopedResource, for the resources which
+	// are not served.
+	APIResources map[schema.GroupVersionKind]bool
+	// IsClusterScopedResource defines whether the APIResources store the cluster scope resources or not.
+	// If true, the map stores all the cluster scoped resource. If the resource is not in the map, it will be treated
+	// as the namespace scoped resource.
+	// If false, the map stores all the namespace scoped resource. If the resource is not in the map, it will be treated
+	// as the cluster scoped resource.
+	IsClusterScopedResource bool
+	// MetadataOnlyResources collects the resources which are watched with metadata-only informers.
+	MetadataOnlyResources map[schema.GroupVersionResource]bool
+	// DynamicClient is the dynamic client returned by GetClient.
+	DynamicClient dynamic.Interface
+
+	mu        sync.RWMutex
+	resources map[schema.GroupVersionResource]*fakeResource
+}
+
+// fakeResource is a resource served by the FakeInformerManager.
+type fakeResource struct {
+	meta     informer.APIResourceMeta
+	store    cache.Indexer
+	synced   bool
+	listErr  error
+	handlers []cache.ResourceEventHandler
+}
+
+var _ informer.Manager = &FakeInformerManager{}
+
+// ServeResource makes the resource served by the manager with the given objects in its informer cache.
+func (m *FakeInformerManager) ServeResource(resource informer.APIResourceMeta, objs ...runtime.Object) {
+	m.mu.Lock()
+	r := m.serveLocked(resource)
+	m.mu.Unlock()
+	for _, obj := range objs {
+		// the objects served are not delivered to the handlers as there can be none yet
+		_ = r.store.Add(obj)
+	}
+}
+
+// UnserveResource makes the resource no longer served by the manager, e.g. when its CRD is deleted.
+func (m *FakeInformerManager) UnserveResource(resource schema.GroupVersionResource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.resources, resource)
+}
+
+// SetInformerSynced sets whether the informer of the served resource is synced.
+func (m *FakeInformerManager) SetInformerSynced(resource schema.GroupVersionResource, synced bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.resources[resource]; ok {
+		r.synced = synced
+	}
+}
+
+// InjectListerError makes the lister of the served resource return the error; a nil error makes it list the objects
+// again.
+func (m *FakeInformerManager) InjectListerError(resource schema.GroupVersionResource, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.resources[resource]; ok {
+		r.listErr = err
+	}
+}
+
+// SetObject adds or updates the object in the informer cache of the served resource, and delivers the add or update
+// event to the event handlers registered for the resource.
+func (m *FakeInformerManager) SetObject(resource schema.GroupVersionResource, obj runtime.Object) error {
+	r, handlers := m.servedResource(resource)
+	if r == nil {
+		return fmt.Errorf("resource %s is not served", resource)
+	}
+	old, exists, err := r.store.Get(obj)
+	if err != nil {
+		return err
+	}
+	if err := r.store.Update(obj); err != nil {
+		return err
+	}
+	for _, handler := range handlers {
+		if exists {
+			handler.OnUpdate(old, obj)
+		} else {
+			handler.OnAdd(obj, false)
+		}
+	}
+	return nil
+}
+
+// DeleteObject deletes the object from the informer cache of the served resource, and delivers the delete event to
+// the event handlers registered for the resource.
+func (m *FakeInformerManager) DeleteObject(resource schema.GroupVersionResource, obj runtime.Object) error {
+	r, handlers := m.servedResource(resource)
+	if r == nil {
+		return fmt.Errorf("resource %s is not served", resource)
+	}
+	if err := r.store.Delete(obj); err != nil {
+		return err
+	}
+	for _, handler := range handlers {
+		handler.OnDelete(obj)
+	}
+	return nil
+}
+
+// AddDynamicResources serves the resources and registers the handler for them.
+func (m *FakeInformerManager) AddDynamicResources(resources []informer.APIResourceMeta, handler cache.ResourceEventHandler, _ bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, resource := range resources {
+		r := m.serveLocked(resource)
+		if handler != nil {
+			r.handlers = append(r.handlers, handler)
+		}
+	}
+}
+
+// AddStaticResource serves the resource and registers the handler for it.
+func (m *FakeInformerManager) AddStaticResource(resource informer.APIResourceMeta, handler cache.ResourceEventHandler) {
+	m.AddDynamicResources([]informer.APIResourceMeta{resource}, handler, false)
+}
+
+// IsInformerSynced returns whether the resource is served and its informer is synced.
+func (m *FakeInformerManager) IsInformerSynced(resource schema.GroupVersionResource) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.resources[resource]
+	return ok && r.synced
+}
+
+// Start does nothing.
+func (m *FakeInformerManager) Start() {
+}
+
+// Stop does nothing.
+func (m *FakeInformerManager) Stop() {
+}
+
+// Lister returns the lister of the served resource, or nil if the resource is not served.
+func (m *FakeInformerManager) Lister(resource schema.GroupVersionResource) cache.GenericLister {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.resources[resource]
+	if !ok {
+		return nil
+	}
+	if r.listErr != nil {
+		return &failingLister{err: r.listErr}
+	}
+	return cache.NewGenericLister(r.store, resource.GroupResource())
+}
+
+// IsMetadataOnly returns whether the resource is in MetadataOnlyResources.
+func (m *FakeInformerManager) IsMetadataOnly(gvr schema.GroupVersionResource) bool {
+	return m.MetadataOnlyResources[gvr]
+}
+
+// GetNameSpaceScopedResources returns the namespace scoped resources served, sorted by their string forms.
+func (m *FakeInformerManager) GetNameSpaceScopedResources() []schema.GroupVersionResource {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var resources []schema.GroupVersionResource
+	for gvr, r := range m.resources {
+		if !r.meta.IsClusterScoped {
+			resources = append(resources, gvr)
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].String() < resources[j].String()
+	})
+	return resources
+}
+
+// IsClusterScopedResources returns whether the resource is cluster scoped, by its scope if it is served, or by
+// APIResources and IsClusterScopedResource otherwise.
+func (m *FakeInformerManager) IsClusterScopedResources(gvk schema.GroupVersionKind) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, r := range m.resources {
+		if r.meta.GroupVersionKind == gvk {
+			return r.meta.IsClusterScoped
+		}
+	}
+	return m.APIResources[gvk] == m.IsClusterScopedResource
+}
+
+// WaitForCacheSync does nothing.
+func (m *FakeInformerManager) WaitForCacheSync() {
+}
+
+// GetClient returns the DynamicClient.
+func (m *FakeInformerManager) GetClient() dynamic.Interface {
+	return m.DynamicClient
+}
+
+// serveLocked serves the resource if it is not served yet; m.mu must be held.
+func (m *FakeInformerManager) serveLocked(resource informer.APIResourceMeta) *fakeResource {
+	if m.resources == nil {
+		m.resources = make(map[schema.GroupVersionResource]*fakeResource)
+	}
+	r, ok := m.resources[resource.GroupVersionResource]
+	if !ok {
+		r = &fakeResource{
+			meta:   resource,
+			store:  cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+			synced: true,
+		}
+		m.resources[resource.GroupVersionResource] = r
+	}
+	return r
+}
+
+// servedResource returns the served resource with a copy of its handlers, or nil if the resource is not served.
+func (m *FakeInformerManager) servedResource(resource schema.GroupVersionResource) (*fakeResource, []cache.ResourceEventHandler) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.resources[resource]
+	if !ok {
+		return nil, nil
+	}
+	return r, append([]cache.ResourceEventHandler(nil), r.handlers...)
+}
+
+// failingLister is a lister which always fails with the error, in all the namespaces.
+type failingLister struct {
+	err error
+}
+
+// List returns the error.
+func (l *failingLister) List(_ labels.Selector) ([]runtime.Object, error) {
+	return nil, l.err
+}
+
+// Get returns the error.
+func (l *failingLister) Get(_ string) (runtime.Object, error) {
+	return nil, l.err
+}
+
+// ByNamespace returns the lister itself, which fails in the namespace too.
+func (l *failingLister) ByNamespace(_ string) cache.GenericNamespaceLister {
+	return l
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package fleettest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/informer"
+)
+
+var (
+	configMapResource = informer.APIResourceMeta{
+		GroupVersionKind:     utils.ConfigMapGVK,
+		GroupVersionResource: utils.ConfigMapGVR,
+	}
+	namespaceResource = informer.APIResourceMeta{
+		GroupVersionKind:     utils.NamespaceGVK,
+		GroupVersionResource: utils.NamespaceGVR,
+		IsClusterScoped:      true,
+	}
+)
+
+func newConfigMap(namespace, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+	}}
+}
+
+func TestFakeInformerManagerServeResource(t *testing.T) {
+	m := &FakeInformerManager{}
+	if m.Lister(utils.ConfigMapGVR) != nil || m.IsInformerSynced(utils.ConfigMapGVR) {
+		t.Fatalf("the config maps are served before ServeResource(), want not served")
+	}
+
+	m.ServeResource(configMapResource, newConfigMap("app", "config"), newConfigMap("other", "config"))
+	m.ServeResource(namespaceResource)
+	if !m.IsInformerSynced(utils.ConfigMapGVR) {
+		t.Errorf("IsInformerSynced() = false, want true")
+	}
+	if m.IsClusterScopedResources(utils.ConfigMapGVK) || !m.IsClusterScopedResources(utils.NamespaceGVK) {
+		t.Errorf("IsClusterScopedResources() of the config maps and the namespaces = %t, %t, want false, true",
+			m.IsClusterScopedResources(utils.ConfigMapGVK), m.IsClusterScopedResources(utils.NamespaceGVK))
+	}
+	if diff := cmp.Diff([]schema.GroupVersionResource{utils.ConfigMapGVR}, m.GetNameSpaceScopedResources()); diff != "" {
+		t.Errorf("GetNameSpaceScopedResources() mismatch (-want, +got):\n%s", diff)
+	}
+	objs, err := m.Lister(utils.ConfigMapGVR).ByNamespace("app").List(labels.Everything())
+	if err != nil || len(objs) != 1 {
+		t.Errorf("List() in namespace app = %d objects, %v, want 1 object, nil", len(objs), err)
+	}
+
+	m.SetInformerSynced(utils.ConfigMapGVR, false)
+	if m.IsInformerSynced(utils.ConfigMapGVR) {
+		t.Errorf("IsInformerSynced() after SetInformerSynced(false) = true, want false")
+	}
+	listErr := errors.New("list failure")
+	m.InjectListerError(utils.ConfigMapGVR, listErr)
+	if _, err := m.Lister(utils.ConfigMapGVR).ByNamespace("app").Get("config"); !errors.Is(err, listErr) {
+		t.Errorf("Get() after InjectListerError() = %v, want %v", err, listErr)
+	}
+
+	m.UnserveResource(utils.ConfigMapGVR)
+	if m.Lister(utils.ConfigMapGVR) != nil {
+		t.Errorf("Lister() after UnserveResource() is not nil, want nil")
+	}
+}
+
+func TestFakeInformerManagerEvents(t *testing.T) {
+	m := &FakeInformerManager{}
+	var events []string
+	m.AddDynamicResources([]informer.APIResourceMeta{configMapResource}, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			events = append(events, "add "+obj.(*unstructured.Unstructured).GetName())
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			events = append(events, "update "+newObj.(*unstructured.Unstructured).GetName())
+		},
+		DeleteFunc: func(obj interface{}) {
+			events = append(events, "delete "+obj.(*unstructured.Unstructured).GetName())
+		},
+	}, true)
+
+	configMap := newConfigMap("app", "config")
+	for _, set := range []func() error{
+		func() error { return m.SetObject(utils.ConfigMapGVR, configMap) },
+		func() error { return m.SetObject(utils.ConfigMapGVR, configMap) },
+		func() error { return m.DeleteObject(utils.ConfigMapGVR, configMap) },
+	} {
+		if err := set(); err != nil {
+			t.Fatalf("SetObject() or DeleteObject() = %v, want nil", err)
+		}
+	}
+	if diff := cmp.Diff([]string{"add config", "update config", "delete config"}, events); diff != "" {
+		t.Errorf("events mismatch (-want, +got):\n%s", diff)
+	}
+	if err := m.SetObject(utils.NamespaceGVR, configMap); err == nil {
+		t.Errorf("SetObject() of a resource not served = nil, want error")
+	}
+}
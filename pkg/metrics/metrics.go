@@ -74,4 +74,63 @@ var (
 		Name: "scheduling_active_workers",
 		Help: "Number of currently running scheduling loop",
 	}, []string{})
+
+	// SchedulerScoreCacheHits is a prometheus metric which counts the cluster scores served from the score cache
+	// of the scheduler, by the score plugin.
+	SchedulerScoreCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduling_score_cache_hits_total",
+		Help: "Number of cluster scores served from the scheduler score cache",
+	}, []string{"plugin"})
+
+	// SchedulerScoreCacheMisses is a prometheus metric which counts the cluster scores the scheduler computes as they
+	// are not found in the score cache, or are computed with an outdated cluster state, by the score plugin.
+	SchedulerScoreCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scheduling_score_cache_misses_total",
+		Help: "Number of cluster scores missing from the scheduler score cache",
+	}, []string{"plugin"})
+
+	// WorkGeneratorOverrideCacheHits is a prometheus metric which counts the resources patched by the override rules
+	// which the work generator serves from its override render cache.
+	WorkGeneratorOverrideCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "work_generator_override_cache_hits_total",
+		Help: "Number of patched resources served from the work generator override render cache",
+	})
+
+	// WorkGeneratorOverrideCacheMisses is a prometheus metric which counts the resources the work generator patches
+	// by the override rules as they are not found in its override render cache.
+	WorkGeneratorOverrideCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "work_generator_override_cache_misses_total",
+		Help: "Number of patched resources missing from the work generator override render cache",
+	})
+)
+
+// The placement availability SLI metrics.
+var (
+	// PlacementAvailabilitySamples is a prometheus metric which counts the samples of the bindings of a placement, by
+	// whether the binding was fully available when sampled, so that the SLOs can be defined over any window.
+	PlacementAvailabilitySamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "placement_availability_samples_total",
+		Help: "Number of the samples of the bindings of a cluster resource placement, by whether the binding was available",
+	}, []string{"placement", "available"})
+
+	// ClusterPlacementAvailabilitySamples is a prometheus metric which counts the samples of the bindings on a member
+	// cluster, by whether the binding was fully available when sampled.
+	ClusterPlacementAvailabilitySamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cluster_placement_availability_samples_total",
+		Help: "Number of the samples of the bindings on a member cluster, by whether the binding was available",
+	}, []string{"cluster", "available"})
+
+	// PlacementAvailabilitySLI is a prometheus metric which holds the fraction of the samples of the bindings of a
+	// placement in which the binding was fully available, over the window.
+	PlacementAvailabilitySLI = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "placement_availability_sli",
+		Help: "Fraction of the samples of the bindings of a cluster resource placement in which the binding was available, over the window",
+	}, []string{"placement", "window"})
+
+	// ClusterPlacementAvailabilitySLI is a prometheus metric which holds the fraction of the samples of the bindings on
+	// a member cluster in which the binding was fully available, over the window.
+	ClusterPlacementAvailabilitySLI = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cluster_placement_availability_sli",
+		Help: "Fraction of the samples of the bindings on a member cluster in which the binding was available, over the window",
+	}, []string{"cluster", "window"})
 )
@@ -0,0 +1,492 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const karmadaPolicyGroup = "policy.karmada.io"
+
+// The Karmada policy types, which only include the fields that the conversion reads.
+type karmadaResourceSelector struct {
+	APIVersion    string                `json:"apiVersion"`
+	Kind          string                `json:"kind"`
+	Namespace     string                `json:"namespace,omitempty"`
+	Name          string                `json:"name,omitempty"`
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+type karmadaClusterAffinity struct {
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	FieldSelector interface{}           `json:"fieldSelector,omitempty"`
+	ClusterNames  []string              `json:"clusterNames,omitempty"`
+	Exclude       []string              `json:"exclude,omitempty"`
+}
+
+type karmadaToleration struct {
+	Key               string `json:"key,omitempty"`
+	Operator          string `json:"operator,omitempty"`
+	Value             string `json:"value,omitempty"`
+	Effect            string `json:"effect,omitempty"`
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty"`
+}
+
+type karmadaSpreadConstraint struct {
+	SpreadByField string `json:"spreadByField,omitempty"`
+	SpreadByLabel string `json:"spreadByLabel,omitempty"`
+	MaxGroups     int32  `json:"maxGroups,omitempty"`
+	MinGroups     int32  `json:"minGroups,omitempty"`
+}
+
+type karmadaReplicaScheduling struct {
+	ReplicaSchedulingType string `json:"replicaSchedulingType,omitempty"`
+}
+
+type karmadaPlacement struct {
+	ClusterAffinity    *karmadaClusterAffinity   `json:"clusterAffinity,omitempty"`
+	ClusterAffinities  []interface{}             `json:"clusterAffinities,omitempty"`
+	ClusterTolerations []karmadaToleration       `json:"clusterTolerations,omitempty"`
+	SpreadConstraints  []karmadaSpreadConstraint `json:"spreadConstraints,omitempty"`
+	ReplicaScheduling  *karmadaReplicaScheduling `json:"replicaScheduling,omitempty"`
+}
+
+type karmadaPropagationSpec struct {
+	ResourceSelectors []karmadaResourceSelector `json:"resourceSelectors"`
+	Placement         karmadaPlacement          `json:"placement,omitempty"`
+}
+
+type karmadaPlaintextOverrider struct {
+	Path     string      `json:"path"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+type karmadaMetadataOverrider struct {
+	Operator string            `json:"operator"`
+	Value    map[string]string `json:"value,omitempty"`
+}
+
+type karmadaOverriders struct {
+	Plaintext            []karmadaPlaintextOverrider `json:"plaintext,omitempty"`
+	LabelsOverrider      []karmadaMetadataOverrider  `json:"labelsOverrider,omitempty"`
+	AnnotationsOverrider []karmadaMetadataOverrider  `json:"annotationsOverrider,omitempty"`
+	ImageOverrider       []interface{}               `json:"imageOverrider,omitempty"`
+	CommandOverrider     []interface{}               `json:"commandOverrider,omitempty"`
+	ArgsOverrider        []interface{}               `json:"argsOverrider,omitempty"`
+	FieldOverrider       []interface{}               `json:"fieldOverrider,omitempty"`
+}
+
+type karmadaOverrideRule struct {
+	TargetCluster *karmadaClusterAffinity `json:"targetCluster,omitempty"`
+	Overriders    karmadaOverriders       `json:"overriders"`
+}
+
+type karmadaOverrideSpec struct {
+	ResourceSelectors []karmadaResourceSelector `json:"resourceSelectors,omitempty"`
+	OverrideRules     []karmadaOverrideRule     `json:"overrideRules,omitempty"`
+	// TargetCluster and Overriders are the deprecated form of a single override rule.
+	TargetCluster *karmadaClusterAffinity `json:"targetCluster,omitempty"`
+	Overriders    karmadaOverriders       `json:"overriders,omitempty"`
+}
+
+// unsupportedPropagationFields are the fields of the propagation policies which have no fleet counterparts.
+var unsupportedPropagationFields = map[string]string{
+	"priority":                    "fleet does not prioritize the placements which select the same resources",
+	"preemption":                  "fleet placements do not preempt each other",
+	"dependentOverrides":          "fleet does not order the placements by their overrides",
+	"schedulerName":               "fleet has a single scheduler",
+	"failover":                    "fleet does not migrate the resources away from unhealthy clusters",
+	"conflictResolution":          "fleet does not overwrite the resources which already exist on the member clusters unless the apply strategy allows so",
+	"activationPreference":        "fleet places the resources once they are selected",
+	"suspension":                  "fleet does not suspend the placements",
+	"preserveResourcesOnDeletion": "fleet removes the placed resources when the placement is deleted",
+	"association":                 "fleet places the namespaces as a whole",
+}
+
+// convertKarmada converts a Karmada policy.
+func (c *converter) convertKarmada(obj *unstructured.Unstructured) {
+	switch obj.GetKind() {
+	case "PropagationPolicy":
+		c.convertPropagationPolicy(obj, false)
+	case "ClusterPropagationPolicy":
+		c.convertPropagationPolicy(obj, true)
+	case "OverridePolicy":
+		c.convertOverridePolicy(obj, false)
+	case "ClusterOverridePolicy":
+		c.convertOverridePolicy(obj, true)
+	default:
+		c.unsupported("", "the Karmada %s objects have no fleet counterparts; skipped", obj.GetKind())
+	}
+}
+
+// convertPropagationPolicy converts a propagation policy into a cluster resource placement. Fleet places the
+// namespaced resources by their namespaces, so a namespaced propagation policy places its whole namespace.
+func (c *converter) convertPropagationPolicy(obj *unstructured.Unstructured, clusterScoped bool) {
+	var spec karmadaPropagationSpec
+	if !c.decodeSpec(obj, &spec) {
+		return
+	}
+	for _, field := range sortedKeys(unsupportedPropagationFields) {
+		value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", field)
+		// Abort, the default conflict resolution, is what fleet does
+		if !found || isZero(value) || (field == "conflictResolution" && value == "Abort") {
+			continue
+		}
+		c.unsupported("spec."+field, "%s; dropped", unsupportedPropagationFields[field])
+	}
+
+	crp := &placementv1beta1.ClusterResourcePlacement{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: placementv1beta1.GroupVersion.String(),
+			Kind:       placementv1beta1.ClusterResourcePlacementKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: obj.GetName()},
+	}
+	if clusterScoped {
+		crp.Spec.ResourceSelectors = c.convertClusterResourceSelectors(spec.ResourceSelectors)
+	} else {
+		// the name of a namespaced policy is only unique in its namespace
+		crp.Name = fmt.Sprintf("%s-%s", obj.GetNamespace(), obj.GetName())
+		crp.Spec.ResourceSelectors = []placementv1beta1.ClusterResourceSelector{namespaceSelector(obj.GetNamespace())}
+		c.note("spec.resourceSelectors", "fleet places the namespace %s as a whole, including the resources that the policy does not select", obj.GetNamespace())
+	}
+	if len(crp.Spec.ResourceSelectors) == 0 {
+		c.unsupported("spec.resourceSelectors", "no resource selector can be converted; skipped")
+		return
+	}
+	crp.Spec.Policy = c.convertPlacement(&spec.Placement)
+	c.result.Objects = append(c.result.Objects, crp)
+}
+
+// convertClusterResourceSelectors converts the resource selectors of a cluster propagation policy.
+func (c *converter) convertClusterResourceSelectors(selectors []karmadaResourceSelector) []placementv1beta1.ClusterResourceSelector {
+	var converted []placementv1beta1.ClusterResourceSelector
+	selectedNamespaces := make(map[string]bool)
+	for i, selector := range selectors {
+		field := fmt.Sprintf("spec.resourceSelectors[%d]", i)
+		gv, ok := c.parseGroupVersion(field, selector.APIVersion)
+		if !ok {
+			continue
+		}
+		switch {
+		case selector.Namespace != "":
+			if !selectedNamespaces[selector.Namespace] {
+				selectedNamespaces[selector.Namespace] = true
+				converted = append(converted, namespaceSelector(selector.Namespace))
+			}
+			c.note(field, "fleet places the namespace %s as a whole, including the resources that the policy does not select", selector.Namespace)
+			continue
+		case selector.Kind == "Namespace":
+			c.note(field, "fleet places the selected namespaces with all the resources in them")
+		case !clusterScopedKinds[selector.Kind]:
+			c.note(field, "fleet only selects the cluster-scoped resources; the selector is kept assuming %s is cluster-scoped", selector.Kind)
+		}
+		converted = append(converted, placementv1beta1.ClusterResourceSelector{
+			Group:         gv.Group,
+			Version:       gv.Version,
+			Kind:          selector.Kind,
+			Name:          selector.Name,
+			LabelSelector: selector.LabelSelector,
+		})
+	}
+	return converted
+}
+
+// convertPlacement converts the placement of a propagation policy into a placement policy.
+func (c *converter) convertPlacement(placement *karmadaPlacement) *placementv1beta1.PlacementPolicy {
+	policy := &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType}
+	if affinity := placement.ClusterAffinity; affinity != nil {
+		if len(affinity.ClusterNames) > 0 {
+			policy.PlacementType = placementv1beta1.PickFixedPlacementType
+			policy.ClusterNames = affinity.ClusterNames
+			if affinity.LabelSelector != nil || len(affinity.Exclude) > 0 {
+				c.unsupported("spec.placement.clusterAffinity", "fleet picks the fixed clusters by names only; the labelSelector and exclude are dropped")
+			}
+		} else if selector := clusterNamesSelector(affinity.LabelSelector, nil, affinity.Exclude); selector != nil {
+			if len(affinity.Exclude) > 0 {
+				c.note("spec.placement.clusterAffinity.exclude", clusterNameLabelMessage)
+			}
+			policy.Affinity = &placementv1beta1.Affinity{
+				ClusterAffinity: &placementv1beta1.ClusterAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &placementv1beta1.ClusterSelector{
+						ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{LabelSelector: selector}},
+					},
+				},
+			}
+		}
+		if affinity.FieldSelector != nil {
+			c.unsupported("spec.placement.clusterAffinity.fieldSelector", "fleet does not select the clusters by their provider, region or zone fields; label the member clusters and select them by labels instead")
+		}
+	}
+	if len(placement.ClusterAffinities) > 0 {
+		c.unsupported("spec.placement.clusterAffinities", "fleet does not fail over between the groups of clusters; dropped")
+	}
+
+	for i, toleration := range placement.ClusterTolerations {
+		field := fmt.Sprintf("spec.placement.clusterTolerations[%d]", i)
+		if toleration.Effect != "" && toleration.Effect != string(corev1.TaintEffectNoSchedule) {
+			c.unsupported(field, "fleet taints only have the NoSchedule effect; dropped")
+			continue
+		}
+		if toleration.TolerationSeconds != nil {
+			c.unsupported(field+".tolerationSeconds", "fleet tolerations do not expire; dropped")
+		}
+		policy.Tolerations = append(policy.Tolerations, placementv1beta1.Toleration{
+			Key:      toleration.Key,
+			Operator: corev1.TolerationOperator(toleration.Operator),
+			Value:    toleration.Value,
+			Effect:   corev1.TaintEffect(toleration.Effect),
+		})
+	}
+
+	var topologyKeys []string
+	for i, constraint := range placement.SpreadConstraints {
+		field := fmt.Sprintf("spec.placement.spreadConstraints[%d]", i)
+		switch {
+		case constraint.SpreadByLabel != "":
+			topologyKeys = append(topologyKeys, constraint.SpreadByLabel)
+		case constraint.SpreadByField == "cluster" || constraint.SpreadByField == "":
+			if constraint.MaxGroups > 0 {
+				policy.NumberOfClusters = ptr.To(constraint.MaxGroups)
+			}
+		default:
+			c.unsupported(field, "fleet does not spread the resources by the %s field; label the member clusters and spread by labels instead", constraint.SpreadByField)
+		}
+		if constraint.MinGroups > 0 {
+			c.unsupported(field+".minGroups", "fleet does not require a minimum number of groups; dropped")
+		}
+	}
+	if policy.NumberOfClusters != nil {
+		if policy.PlacementType == placementv1beta1.PickFixedPlacementType {
+			c.unsupported("spec.placement.spreadConstraints", "fleet picks all the fixed clusters; the number of clusters is dropped")
+			policy.NumberOfClusters = nil
+		} else {
+			policy.PlacementType = placementv1beta1.PickNPlacementType
+		}
+	}
+	for _, key := range topologyKeys {
+		if policy.PlacementType != placementv1beta1.PickNPlacementType {
+			c.unsupported("spec.placement.spreadConstraints", "fleet spreads the resources by %s only when picking a number of clusters; add a cluster spread constraint with maxGroups", key)
+			continue
+		}
+		policy.TopologySpreadConstraints = append(policy.TopologySpreadConstraints, placementv1beta1.TopologySpreadConstraint{
+			MaxSkew:           ptr.To(int32(1)),
+			TopologyKey:       key,
+			WhenUnsatisfiable: placementv1beta1.DoNotSchedule,
+		})
+	}
+
+	if placement.ReplicaScheduling != nil && placement.ReplicaScheduling.ReplicaSchedulingType == "Divided" {
+		c.unsupported("spec.placement.replicaScheduling", "fleet places the same replicas on each cluster; use the overrides to set the replicas per cluster")
+	}
+	return policy
+}
+
+// convertOverridePolicy converts an override policy into a resource override, or a cluster override policy into a
+// cluster resource override.
+func (c *converter) convertOverridePolicy(obj *unstructured.Unstructured, clusterScoped bool) {
+	var spec karmadaOverrideSpec
+	if !c.decodeSpec(obj, &spec) {
+		return
+	}
+	rules := spec.OverrideRules
+	if len(rules) == 0 && (spec.TargetCluster != nil || !isZero(spec.Overriders)) {
+		rules = []karmadaOverrideRule{{TargetCluster: spec.TargetCluster, Overriders: spec.Overriders}}
+	}
+	policy := &placementv1alpha1.OverridePolicy{}
+	for i := range rules {
+		if rule, ok := c.convertOverrideRule(fmt.Sprintf("spec.overrideRules[%d]", i), &rules[i]); ok {
+			policy.OverrideRules = append(policy.OverrideRules, rule)
+		}
+	}
+	if len(policy.OverrideRules) == 0 {
+		c.unsupported("spec.overrideRules", "no override rule can be converted; skipped")
+		return
+	}
+
+	typeMeta := metav1.TypeMeta{APIVersion: placementv1alpha1.GroupVersion.String()}
+	if clusterScoped {
+		selectors := c.convertClusterOverrideSelectors(spec.ResourceSelectors)
+		if len(selectors) == 0 {
+			c.unsupported("spec.resourceSelectors", "no resource selector can be converted; skipped")
+			return
+		}
+		typeMeta.Kind = "ClusterResourceOverride"
+		c.result.Objects = append(c.result.Objects, &placementv1alpha1.ClusterResourceOverride{
+			TypeMeta:   typeMeta,
+			ObjectMeta: metav1.ObjectMeta{Name: obj.GetName()},
+			Spec:       placementv1alpha1.ClusterResourceOverrideSpec{ClusterResourceSelectors: selectors, Policy: policy},
+		})
+		return
+	}
+	var selectors []placementv1alpha1.ResourceSelector
+	for i, selector := range spec.ResourceSelectors {
+		field := fmt.Sprintf("spec.resourceSelectors[%d]", i)
+		gv, ok := c.parseGroupVersion(field, selector.APIVersion)
+		if !ok {
+			continue
+		}
+		if selector.Name == "" {
+			c.unsupported(field, "fleet overrides select the resources by names only; skipped")
+			continue
+		}
+		if selector.LabelSelector != nil {
+			c.unsupported(field+".labelSelector", "fleet overrides select the resources by names only; dropped")
+		}
+		selectors = append(selectors, placementv1alpha1.ResourceSelector{Group: gv.Group, Version: gv.Version, Kind: selector.Kind, Name: selector.Name})
+	}
+	if len(selectors) == 0 {
+		c.unsupported("spec.resourceSelectors", "no resource selector can be converted; skipped")
+		return
+	}
+	typeMeta.Kind = "ResourceOverride"
+	c.result.Objects = append(c.result.Objects, &placementv1alpha1.ResourceOverride{
+		TypeMeta:   typeMeta,
+		ObjectMeta: metav1.ObjectMeta{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+		Spec:       placementv1alpha1.ResourceOverrideSpec{ResourceSelectors: selectors, Policy: policy},
+	})
+}
+
+// convertClusterOverrideSelectors converts the resource selectors of a cluster override policy.
+func (c *converter) convertClusterOverrideSelectors(selectors []karmadaResourceSelector) []placementv1beta1.ClusterResourceSelector {
+	var converted []placementv1beta1.ClusterResourceSelector
+	for i, selector := range selectors {
+		field := fmt.Sprintf("spec.resourceSelectors[%d]", i)
+		gv, ok := c.parseGroupVersion(field, selector.APIVersion)
+		if !ok {
+			continue
+		}
+		if selector.Namespace != "" {
+			c.unsupported(field, "fleet cluster resource overrides only select the cluster-scoped resources; use a ResourceOverride in the namespace %s instead", selector.Namespace)
+			continue
+		}
+		converted = append(converted, placementv1beta1.ClusterResourceSelector{
+			Group:         gv.Group,
+			Version:       gv.Version,
+			Kind:          selector.Kind,
+			Name:          selector.Name,
+			LabelSelector: selector.LabelSelector,
+		})
+	}
+	return converted
+}
+
+// convertOverrideRule converts an override rule. It returns false if none of its overriders can be converted.
+func (c *converter) convertOverrideRule(field string, rule *karmadaOverrideRule) (placementv1alpha1.OverrideRule, bool) {
+	converted := placementv1alpha1.OverrideRule{ClusterSelector: &placementv1beta1.ClusterSelector{}}
+	if target := rule.TargetCluster; target != nil {
+		if selector := clusterNamesSelector(target.LabelSelector, target.ClusterNames, target.Exclude); selector != nil {
+			converted.ClusterSelector.ClusterSelectorTerms = []placementv1beta1.ClusterSelectorTerm{{LabelSelector: selector}}
+		}
+		if len(target.ClusterNames) > 0 || len(target.Exclude) > 0 {
+			c.note(field+".targetCluster", clusterNameLabelMessage)
+		}
+		if target.FieldSelector != nil {
+			c.unsupported(field+".targetCluster.fieldSelector", "fleet does not select the clusters by their provider, region or zone fields; the rule applies regardless of them")
+		}
+	}
+
+	overriders := &rule.Overriders
+	for i, plaintext := range overriders.Plaintext {
+		patch := placementv1alpha1.JSONPatchOverride{
+			Operator: placementv1alpha1.JSONPatchOverrideOperator(plaintext.Operator),
+			Path:     plaintext.Path,
+		}
+		if plaintext.Value != nil {
+			raw, err := json.Marshal(plaintext.Value)
+			if err != nil {
+				c.unsupported(fmt.Sprintf("%s.overriders.plaintext[%d]", field, i), "invalid value: %v; skipped", err)
+				continue
+			}
+			patch.Value = apiextensionsv1.JSON{Raw: raw}
+		}
+		converted.JSONPatchOverrides = append(converted.JSONPatchOverrides, patch)
+	}
+	converted.JSONPatchOverrides = append(converted.JSONPatchOverrides, metadataPatches("labels", overriders.LabelsOverrider)...)
+	converted.JSONPatchOverrides = append(converted.JSONPatchOverrides, metadataPatches("annotations", overriders.AnnotationsOverrider)...)
+	for _, overrider := range []struct {
+		name  string
+		items []interface{}
+	}{
+		{"imageOverrider", overriders.ImageOverrider},
+		{"commandOverrider", overriders.CommandOverrider},
+		{"argsOverrider", overriders.ArgsOverrider},
+		{"fieldOverrider", overriders.FieldOverrider},
+	} {
+		if len(overrider.items) > 0 {
+			c.unsupported(field+".overriders."+overrider.name, "fleet only overrides the resources with JSON patches; rewrite it as a plaintext overrider")
+		}
+	}
+	if len(converted.JSONPatchOverrides) == 0 {
+		c.unsupported(field, "no overrider can be converted; skipped")
+		return converted, false
+	}
+	return converted, true
+}
+
+// metadataPatches converts the labels or annotations overriders into JSON patches.
+func metadataPatches(field string, overriders []karmadaMetadataOverrider) []placementv1alpha1.JSONPatchOverride {
+	var patches []placementv1alpha1.JSONPatchOverride
+	for _, overrider := range overriders {
+		for _, key := range sortedKeys(overrider.Value) {
+			patch := placementv1alpha1.JSONPatchOverride{
+				Operator: placementv1alpha1.JSONPatchOverrideOperator(overrider.Operator),
+				Path:     fmt.Sprintf("/metadata/%s/%s", field, escapeJSONPointer(key)),
+			}
+			if patch.Operator != placementv1alpha1.JSONPatchOverrideOpRemove {
+				raw, _ := json.Marshal(overrider.Value[key])
+				patch.Value = apiextensionsv1.JSON{Raw: raw}
+			}
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+// escapeJSONPointer escapes a reference token of a JSON pointer, e.g. a label key with slashes.
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// decodeSpec decodes the spec of the object, reporting the object as skipped if it is invalid.
+func (c *converter) decodeSpec(obj *unstructured.Unstructured, spec interface{}) bool {
+	content, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err == nil {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, spec)
+	}
+	if err != nil {
+		c.unsupported("spec", "invalid spec: %v; skipped", err)
+		return false
+	}
+	return true
+}
+
+// isZero returns true if the value is the zero value of its type, e.g. a false flag or an empty list.
+func isZero(value interface{}) bool {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	switch string(raw) {
+	case "null", "false", "0", `""`, "[]", "{}":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,381 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+var (
+	crpTypeMeta = metav1.TypeMeta{APIVersion: placementv1beta1.GroupVersion.String(), Kind: placementv1beta1.ClusterResourcePlacementKind}
+	roTypeMeta  = metav1.TypeMeta{APIVersion: placementv1alpha1.GroupVersion.String(), Kind: "ResourceOverride"}
+	croTypeMeta = metav1.TypeMeta{APIVersion: placementv1alpha1.GroupVersion.String(), Kind: "ClusterResourceOverride"}
+)
+
+func decodeString(t *testing.T, content string) []*unstructured.Unstructured {
+	objs, err := Decode(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Decode() = %v, want nil", err)
+	}
+	return objs
+}
+
+func nameSelector(operator metav1.LabelSelectorOperator, names ...string) metav1.LabelSelectorRequirement {
+	return metav1.LabelSelectorRequirement{Key: placementv1beta1.MemberClusterNameLabel, Operator: operator, Values: names}
+}
+
+// findingFields returns the fields of the findings, marking the unsupported ones with a "!" prefix.
+func findingFields(findings []Finding) []string {
+	var fields []string
+	for _, f := range findings {
+		field := f.Object + " " + f.Field
+		if f.Unsupported {
+			field = "!" + field
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func TestConvertPropagationPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy     string
+		want       []client.Object
+		wantFields []string
+	}{
+		"namespaced policy with a label affinity": {
+			policy: `
+apiVersion: policy.karmada.io/v1alpha1
+kind: PropagationPolicy
+metadata:
+  name: nginx
+  namespace: web
+spec:
+  resourceSelectors:
+    - apiVersion: apps/v1
+      kind: Deployment
+      name: nginx
+  placement:
+    clusterAffinity:
+      labelSelector:
+        matchLabels:
+          env: prod
+      exclude: [member-3]
+    clusterTolerations:
+      - key: dedicated
+        operator: Exists
+        effect: NoSchedule
+      - key: unreachable
+        operator: Exists
+        effect: NoExecute
+  conflictResolution: Abort
+  priority: 10
+`,
+			want: []client.Object{&placementv1beta1.ClusterResourcePlacement{
+				TypeMeta:   crpTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "web-nginx"},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{namespaceSelector("web")},
+					Policy: &placementv1beta1.PlacementPolicy{
+						PlacementType: placementv1beta1.PickAllPlacementType,
+						Affinity: &placementv1beta1.Affinity{ClusterAffinity: &placementv1beta1.ClusterAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &placementv1beta1.ClusterSelector{
+								ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{LabelSelector: &metav1.LabelSelector{
+									MatchLabels:      map[string]string{"env": "prod"},
+									MatchExpressions: []metav1.LabelSelectorRequirement{nameSelector(metav1.LabelSelectorOpNotIn, "member-3")},
+								}}},
+							},
+						}},
+						Tolerations: []placementv1beta1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+					},
+				},
+			}},
+			wantFields: []string{
+				"!PropagationPolicy/web/nginx spec.priority",
+				"PropagationPolicy/web/nginx spec.resourceSelectors",
+				"PropagationPolicy/web/nginx spec.placement.clusterAffinity.exclude",
+				"!PropagationPolicy/web/nginx spec.placement.clusterTolerations[1]",
+			},
+		},
+		"cluster policy with spread constraints": {
+			policy: `
+apiVersion: policy.karmada.io/v1alpha1
+kind: ClusterPropagationPolicy
+metadata:
+  name: shared
+spec:
+  resourceSelectors:
+    - apiVersion: rbac.authorization.k8s.io/v1
+      kind: ClusterRole
+      name: reader
+    - apiVersion: v1
+      kind: ConfigMap
+      namespace: shared
+      name: settings
+    - apiVersion: example.com/v1
+      kind: Widget
+  placement:
+    spreadConstraints:
+      - spreadByField: cluster
+        maxGroups: 3
+        minGroups: 2
+      - spreadByLabel: topology.kubernetes.io/region
+      - spreadByField: zone
+    replicaScheduling:
+      replicaSchedulingType: Duplicated
+`,
+			want: []client.Object{&placementv1beta1.ClusterResourcePlacement{
+				TypeMeta:   crpTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{
+						{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "reader"},
+						namespaceSelector("shared"),
+						{Group: "example.com", Version: "v1", Kind: "Widget"},
+					},
+					Policy: &placementv1beta1.PlacementPolicy{
+						PlacementType:    placementv1beta1.PickNPlacementType,
+						NumberOfClusters: ptr.To(int32(3)),
+						TopologySpreadConstraints: []placementv1beta1.TopologySpreadConstraint{{
+							MaxSkew:           ptr.To(int32(1)),
+							TopologyKey:       "topology.kubernetes.io/region",
+							WhenUnsatisfiable: placementv1beta1.DoNotSchedule,
+						}},
+					},
+				},
+			}},
+			wantFields: []string{
+				"ClusterPropagationPolicy/shared spec.resourceSelectors[1]",
+				"ClusterPropagationPolicy/shared spec.resourceSelectors[2]",
+				"!ClusterPropagationPolicy/shared spec.placement.spreadConstraints[0].minGroups",
+				"!ClusterPropagationPolicy/shared spec.placement.spreadConstraints[2]",
+			},
+		},
+		"fixed clusters with divided replicas": {
+			policy: `
+apiVersion: policy.karmada.io/v1alpha1
+kind: ClusterPropagationPolicy
+metadata:
+  name: crds
+spec:
+  resourceSelectors:
+    - apiVersion: apiextensions.k8s.io/v1
+      kind: CustomResourceDefinition
+  placement:
+    clusterAffinity:
+      clusterNames: [member-1, member-2]
+      labelSelector:
+        matchLabels:
+          env: prod
+    spreadConstraints:
+      - spreadByField: cluster
+        maxGroups: 1
+    replicaScheduling:
+      replicaSchedulingType: Divided
+`,
+			want: []client.Object{&placementv1beta1.ClusterResourcePlacement{
+				TypeMeta:   crpTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "crds"},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}},
+					Policy: &placementv1beta1.PlacementPolicy{
+						PlacementType: placementv1beta1.PickFixedPlacementType,
+						ClusterNames:  []string{"member-1", "member-2"},
+					},
+				},
+			}},
+			wantFields: []string{
+				"!ClusterPropagationPolicy/crds spec.placement.clusterAffinity",
+				"!ClusterPropagationPolicy/crds spec.placement.spreadConstraints",
+				"!ClusterPropagationPolicy/crds spec.placement.replicaScheduling",
+			},
+		},
+		"no convertible selectors": {
+			policy: `
+apiVersion: policy.karmada.io/v1alpha1
+kind: ClusterPropagationPolicy
+metadata:
+  name: invalid
+spec:
+  resourceSelectors:
+    - apiVersion: a/b/c
+      kind: Widget
+  placement: {}
+`,
+			wantFields: []string{
+				"!ClusterPropagationPolicy/invalid spec.resourceSelectors[0]",
+				"!ClusterPropagationPolicy/invalid spec.resourceSelectors",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Convert(decodeString(t, tc.policy))
+			if diff := cmp.Diff(tc.want, got.Objects); diff != "" {
+				t.Errorf("Convert() objects mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFields, findingFields(got.Findings)); diff != "" {
+				t.Errorf("Convert() findings mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertOverridePolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy     string
+		want       []client.Object
+		wantFields []string
+	}{
+		"override policy": {
+			policy: `
+apiVersion: policy.karmada.io/v1alpha1
+kind: OverridePolicy
+metadata:
+  name: nginx
+  namespace: web
+spec:
+  resourceSelectors:
+    - apiVersion: apps/v1
+      kind: Deployment
+      name: nginx
+    - apiVersion: v1
+      kind: Service
+  overrideRules:
+    - targetCluster:
+        clusterNames: [member-1]
+        labelSelector:
+          matchLabels:
+            env: prod
+      overriders:
+        plaintext:
+          - path: /spec/replicas
+            operator: replace
+            value: 3
+        annotationsOverrider:
+          - operator: remove
+            value:
+              example.com/owner: ""
+    - overriders:
+        labelsOverrider:
+          - operator: add
+            value:
+              tier: web
+        imageOverrider:
+          - component: Registry
+            operator: replace
+            value: registry.example.com
+    - overriders:
+        commandOverrider:
+          - containerName: nginx
+            operator: add
+            value: ["--debug"]
+`,
+			want: []client.Object{&placementv1alpha1.ResourceOverride{
+				TypeMeta:   roTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "web"},
+				Spec: placementv1alpha1.ResourceOverrideSpec{
+					ResourceSelectors: []placementv1alpha1.ResourceSelector{{Group: "apps", Version: "v1", Kind: "Deployment", Name: "nginx"}},
+					Policy: &placementv1alpha1.OverridePolicy{OverrideRules: []placementv1alpha1.OverrideRule{
+						{
+							ClusterSelector: &placementv1beta1.ClusterSelector{ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{
+								LabelSelector: &metav1.LabelSelector{
+									MatchLabels:      map[string]string{"env": "prod"},
+									MatchExpressions: []metav1.LabelSelectorRequirement{nameSelector(metav1.LabelSelectorOpIn, "member-1")},
+								},
+							}}},
+							JSONPatchOverrides: []placementv1alpha1.JSONPatchOverride{
+								{Operator: placementv1alpha1.JSONPatchOverrideOpReplace, Path: "/spec/replicas", Value: apiextensionsv1.JSON{Raw: []byte("3")}},
+								{Operator: placementv1alpha1.JSONPatchOverrideOpRemove, Path: "/metadata/annotations/example.com~1owner"},
+							},
+						},
+						{
+							ClusterSelector: &placementv1beta1.ClusterSelector{},
+							JSONPatchOverrides: []placementv1alpha1.JSONPatchOverride{
+								{Operator: placementv1alpha1.JSONPatchOverrideOpAdd, Path: "/metadata/labels/tier", Value: apiextensionsv1.JSON{Raw: []byte(`"web"`)}},
+							},
+						},
+					}},
+				},
+			}},
+			wantFields: []string{
+				"OverridePolicy/web/nginx spec.overrideRules[0].targetCluster",
+				"!OverridePolicy/web/nginx spec.overrideRules[1].overriders.imageOverrider",
+				"!OverridePolicy/web/nginx spec.overrideRules[2].overriders.commandOverrider",
+				"!OverridePolicy/web/nginx spec.overrideRules[2]",
+				"!OverridePolicy/web/nginx spec.resourceSelectors[1]",
+			},
+		},
+		"deprecated cluster override policy": {
+			policy: `
+apiVersion: policy.karmada.io/v1alpha1
+kind: ClusterOverridePolicy
+metadata:
+  name: reader
+spec:
+  resourceSelectors:
+    - apiVersion: rbac.authorization.k8s.io/v1
+      kind: ClusterRole
+      name: reader
+    - apiVersion: v1
+      kind: ConfigMap
+      namespace: shared
+      name: settings
+  targetCluster:
+    labelSelector:
+      matchLabels:
+        env: dev
+  overriders:
+    plaintext:
+      - path: /metadata/labels
+        operator: add
+        value:
+          debug: "true"
+`,
+			want: []client.Object{&placementv1alpha1.ClusterResourceOverride{
+				TypeMeta:   croTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "reader"},
+				Spec: placementv1alpha1.ClusterResourceOverrideSpec{
+					ClusterResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "reader"}},
+					Policy: &placementv1alpha1.OverridePolicy{OverrideRules: []placementv1alpha1.OverrideRule{{
+						ClusterSelector: &placementv1beta1.ClusterSelector{ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+						}}},
+						JSONPatchOverrides: []placementv1alpha1.JSONPatchOverride{
+							{Operator: placementv1alpha1.JSONPatchOverrideOpAdd, Path: "/metadata/labels", Value: apiextensionsv1.JSON{Raw: []byte(`{"debug":"true"}`)}},
+						},
+					}}},
+				},
+			}},
+			wantFields: []string{
+				"!ClusterOverridePolicy/reader spec.resourceSelectors[1]",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Convert(decodeString(t, tc.policy))
+			if diff := cmp.Diff(tc.want, got.Objects); diff != "" {
+				t.Errorf("Convert() objects mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFields, findingFields(got.Findings)); diff != "" {
+				t.Errorf("Convert() findings mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
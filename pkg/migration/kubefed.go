@@ -0,0 +1,299 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	kubeFedTypesGroup   = "types.kubefed.io"
+	kubeFedGroupSuffix  = "kubefed.io"
+	federatedKindPrefix = "Federated"
+)
+
+// The KubeFed federated types, which only include the fields that the conversion reads.
+type kubeFedCluster struct {
+	Name string `json:"name"`
+}
+
+type kubeFedPlacement struct {
+	Clusters        []kubeFedCluster      `json:"clusters,omitempty"`
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+type kubeFedClusterOverride struct {
+	Op    string      `json:"op,omitempty"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type kubeFedOverride struct {
+	ClusterName      string                   `json:"clusterName"`
+	ClusterOverrides []kubeFedClusterOverride `json:"clusterOverrides"`
+}
+
+type kubeFedSpec struct {
+	Template  map[string]interface{} `json:"template,omitempty"`
+	Placement *kubeFedPlacement      `json:"placement,omitempty"`
+	Overrides []kubeFedOverride      `json:"overrides,omitempty"`
+}
+
+// kubeFedAPIVersions are the API versions of the well-known kinds, whose federated templates usually omit them.
+var kubeFedAPIVersions = map[string]string{
+	"Namespace":                "v1",
+	"ConfigMap":                "v1",
+	"Secret":                   "v1",
+	"Service":                  "v1",
+	"ServiceAccount":           "v1",
+	"PersistentVolumeClaim":    "v1",
+	"LimitRange":               "v1",
+	"ResourceQuota":            "v1",
+	"Deployment":               "apps/v1",
+	"StatefulSet":              "apps/v1",
+	"DaemonSet":                "apps/v1",
+	"ReplicaSet":               "apps/v1",
+	"Job":                      "batch/v1",
+	"CronJob":                  "batch/v1",
+	"Ingress":                  "networking.k8s.io/v1",
+	"NetworkPolicy":            "networking.k8s.io/v1",
+	"HorizontalPodAutoscaler":  "autoscaling/v2",
+	"Role":                     "rbac.authorization.k8s.io/v1",
+	"RoleBinding":              "rbac.authorization.k8s.io/v1",
+	"ClusterRole":              "rbac.authorization.k8s.io/v1",
+	"ClusterRoleBinding":       "rbac.authorization.k8s.io/v1",
+	"CustomResourceDefinition": "apiextensions.k8s.io/v1",
+	"StorageClass":             "storage.k8s.io/v1",
+}
+
+// convertKubeFed converts the federated objects into the resources rendered from their templates, the cluster
+// resource placements and the overrides. Fleet places the namespaced resources by their namespaces, so the
+// placement of a namespace is the one of its federated namespace, or of its first federated object otherwise.
+func (c *converter) convertKubeFed(objs []*unstructured.Unstructured) {
+	namespacePlacements := make(map[string]*placementv1beta1.ClusterResourcePlacement)
+	// the federated namespaces decide the placements of their namespaces, so they are converted first
+	var ordered []*unstructured.Unstructured
+	for _, obj := range objs {
+		if obj.GetKind() == federatedKindPrefix+"Namespace" {
+			ordered = append(ordered, obj)
+		}
+	}
+	for _, obj := range objs {
+		if obj.GetKind() != federatedKindPrefix+"Namespace" {
+			ordered = append(ordered, obj)
+		}
+	}
+
+	for _, obj := range ordered {
+		c.object = objectRef(obj)
+		kind, ok := strings.CutPrefix(obj.GetKind(), federatedKindPrefix)
+		if !ok || kind == "" {
+			c.unsupported("", "not a federated type; skipped")
+			continue
+		}
+		var spec kubeFedSpec
+		if !c.decodeSpec(obj, &spec) {
+			continue
+		}
+		resource := c.renderTemplate(obj, kind, spec.Template)
+		if resource == nil {
+			continue
+		}
+		c.result.Objects = append(c.result.Objects, resource)
+
+		policy := c.convertKubeFedPlacement(spec.Placement)
+		if policy != nil {
+			c.placeKubeFedResource(resource, policy, namespacePlacements)
+		}
+		if override := c.convertKubeFedOverrides(resource, spec.Overrides); override != nil {
+			c.result.Objects = append(c.result.Objects, override)
+		}
+	}
+}
+
+// renderTemplate renders the resource from the template of the federated object.
+func (c *converter) renderTemplate(obj *unstructured.Unstructured, kind string, template map[string]interface{}) *unstructured.Unstructured {
+	resource := &unstructured.Unstructured{Object: template}
+	if resource.Object == nil {
+		resource.Object = map[string]interface{}{}
+	}
+	if resource.GetAPIVersion() == "" {
+		apiVersion, ok := kubeFedAPIVersions[kind]
+		if !ok {
+			c.unsupported("spec.template", "the apiVersion of %s is unknown; add it to the template", kind)
+			return nil
+		}
+		resource.SetAPIVersion(apiVersion)
+	}
+	resource.SetKind(kind)
+	resource.SetName(obj.GetName())
+	if kind == "Namespace" || clusterScopedKinds[kind] {
+		resource.SetNamespace("")
+	} else {
+		resource.SetNamespace(obj.GetNamespace())
+	}
+	return resource
+}
+
+// convertKubeFedPlacement converts the placement of a federated object. It returns nil if the object is not placed
+// on any cluster.
+func (c *converter) convertKubeFedPlacement(placement *kubeFedPlacement) *placementv1beta1.PlacementPolicy {
+	switch {
+	case placement == nil || (placement.Clusters != nil && len(placement.Clusters) == 0):
+		c.note("spec.placement", "the object is not placed on any cluster; no placement is created")
+		return nil
+	case len(placement.Clusters) > 0:
+		// KubeFed ignores the cluster selector when the clusters are set
+		policy := &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickFixedPlacementType}
+		for _, cluster := range placement.Clusters {
+			policy.ClusterNames = append(policy.ClusterNames, cluster.Name)
+		}
+		return policy
+	case placement.ClusterSelector == nil:
+		c.note("spec.placement", "the object is not placed on any cluster; no placement is created")
+		return nil
+	}
+	policy := &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType}
+	if len(placement.ClusterSelector.MatchLabels) > 0 || len(placement.ClusterSelector.MatchExpressions) > 0 {
+		policy.Affinity = &placementv1beta1.Affinity{
+			ClusterAffinity: &placementv1beta1.ClusterAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &placementv1beta1.ClusterSelector{
+					ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{LabelSelector: placement.ClusterSelector}},
+				},
+			},
+		}
+	}
+	return policy
+}
+
+// placeKubeFedResource adds the cluster resource placement of the resource. The namespaced resources share the
+// placement of their namespace.
+func (c *converter) placeKubeFedResource(resource *unstructured.Unstructured, policy *placementv1beta1.PlacementPolicy, namespacePlacements map[string]*placementv1beta1.ClusterResourcePlacement) {
+	crp := &placementv1beta1.ClusterResourcePlacement{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: placementv1beta1.GroupVersion.String(),
+			Kind:       placementv1beta1.ClusterResourcePlacementKind,
+		},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{Policy: policy},
+	}
+	namespace := resource.GetNamespace()
+	if resource.GetKind() == "Namespace" {
+		namespace = resource.GetName()
+	}
+	if namespace == "" {
+		gvk := resource.GroupVersionKind()
+		crp.Name = fmt.Sprintf("%s-%s", strings.ToLower(gvk.Kind), resource.GetName())
+		crp.Spec.ResourceSelectors = []placementv1beta1.ClusterResourceSelector{{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Kind:    gvk.Kind,
+			Name:    resource.GetName(),
+		}}
+		c.result.Objects = append(c.result.Objects, crp)
+		return
+	}
+
+	if existing, ok := namespacePlacements[namespace]; ok {
+		if !equality.Semantic.DeepEqual(existing.Spec.Policy, policy) {
+			c.unsupported("spec.placement", "fleet places the namespace %s as a whole; the placement of the namespace is used instead", namespace)
+		}
+		return
+	}
+	crp.Name = namespace
+	crp.Spec.ResourceSelectors = []placementv1beta1.ClusterResourceSelector{namespaceSelector(namespace)}
+	namespacePlacements[namespace] = crp
+	if resource.GetKind() != "Namespace" {
+		c.note("spec.placement", "fleet places the namespace %s as a whole with the placement of this object", namespace)
+	}
+	c.result.Objects = append(c.result.Objects, crp)
+}
+
+// convertKubeFedOverrides converts the per cluster overrides of a federated object into a resource override, or a
+// cluster resource override for the cluster-scoped resources.
+func (c *converter) convertKubeFedOverrides(resource *unstructured.Unstructured, overrides []kubeFedOverride) client.Object {
+	if len(overrides) == 0 {
+		return nil
+	}
+	policy := &placementv1alpha1.OverridePolicy{}
+	for i, override := range overrides {
+		field := fmt.Sprintf("spec.overrides[%d]", i)
+		rule := placementv1alpha1.OverrideRule{
+			ClusterSelector: &placementv1beta1.ClusterSelector{
+				ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{
+					LabelSelector: clusterNamesSelector(nil, []string{override.ClusterName}, nil),
+				}},
+			},
+		}
+		for j, clusterOverride := range override.ClusterOverrides {
+			patch := placementv1alpha1.JSONPatchOverride{
+				Operator: placementv1alpha1.JSONPatchOverrideOperator(clusterOverride.Op),
+				Path:     clusterOverride.Path,
+			}
+			if patch.Operator == "" {
+				patch.Operator = placementv1alpha1.JSONPatchOverrideOpReplace
+			}
+			if clusterOverride.Value != nil {
+				raw, err := json.Marshal(clusterOverride.Value)
+				if err != nil {
+					c.unsupported(fmt.Sprintf("%s.clusterOverrides[%d]", field, j), "invalid value: %v; skipped", err)
+					continue
+				}
+				patch.Value = apiextensionsv1.JSON{Raw: raw}
+			}
+			rule.JSONPatchOverrides = append(rule.JSONPatchOverrides, patch)
+		}
+		if len(rule.JSONPatchOverrides) == 0 {
+			continue
+		}
+		policy.OverrideRules = append(policy.OverrideRules, rule)
+	}
+	if len(policy.OverrideRules) == 0 {
+		return nil
+	}
+	c.note("spec.overrides", clusterNameLabelMessage)
+
+	gvk := resource.GroupVersionKind()
+	name := fmt.Sprintf("%s-%s", strings.ToLower(gvk.Kind), resource.GetName())
+	if resource.GetNamespace() == "" {
+		return &placementv1alpha1.ClusterResourceOverride{
+			TypeMeta:   metav1.TypeMeta{APIVersion: placementv1alpha1.GroupVersion.String(), Kind: "ClusterResourceOverride"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: placementv1alpha1.ClusterResourceOverrideSpec{
+				ClusterResourceSelectors: []placementv1beta1.ClusterResourceSelector{{
+					Group:   gvk.Group,
+					Version: gvk.Version,
+					Kind:    gvk.Kind,
+					Name:    resource.GetName(),
+				}},
+				Policy: policy,
+			},
+		}
+	}
+	return &placementv1alpha1.ResourceOverride{
+		TypeMeta:   metav1.TypeMeta{APIVersion: placementv1alpha1.GroupVersion.String(), Kind: "ResourceOverride"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: resource.GetNamespace()},
+		Spec: placementv1alpha1.ResourceOverrideSpec{
+			ResourceSelectors: []placementv1alpha1.ResourceSelector{{
+				Group:   gvk.Group,
+				Version: gvk.Version,
+				Kind:    gvk.Kind,
+				Name:    resource.GetName(),
+			}},
+			Policy: policy,
+		},
+	}
+}
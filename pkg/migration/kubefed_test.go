@@ -0,0 +1,216 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestConvertKubeFed(t *testing.T) {
+	fixedPolicy := &placementv1beta1.PlacementPolicy{
+		PlacementType: placementv1beta1.PickFixedPlacementType,
+		ClusterNames:  []string{"member-1", "member-2"},
+	}
+	tests := map[string]struct {
+		objects    string
+		want       []client.Object
+		wantFields []string
+	}{
+		"namespaced objects share the placement of their namespace": {
+			objects: `
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedConfigMap
+metadata:
+  name: settings
+  namespace: web
+spec:
+  template:
+    data:
+      mode: prod
+  placement:
+    clusterSelector:
+      matchLabels:
+        env: prod
+  overrides:
+    - clusterName: member-1
+      clusterOverrides:
+        - path: /data/mode
+          value: canary
+        - op: remove
+          path: /data/debug
+---
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedNamespace
+metadata:
+  name: web
+  namespace: web
+spec:
+  placement:
+    clusters:
+      - name: member-1
+      - name: member-2
+---
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedSecret
+metadata:
+  name: token
+  namespace: web
+spec:
+  template:
+    type: Opaque
+  placement:
+    clusters:
+      - name: member-1
+      - name: member-2
+`,
+			want: []client.Object{
+				&unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "web"},
+				}},
+				&placementv1beta1.ClusterResourcePlacement{
+					TypeMeta:   crpTypeMeta,
+					ObjectMeta: metav1.ObjectMeta{Name: "web"},
+					Spec: placementv1beta1.ClusterResourcePlacementSpec{
+						ResourceSelectors: []placementv1beta1.ClusterResourceSelector{namespaceSelector("web")},
+						Policy:            fixedPolicy,
+					},
+				},
+				&unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata":   map[string]interface{}{"name": "settings", "namespace": "web"},
+					"data":       map[string]interface{}{"mode": "prod"},
+				}},
+				&placementv1alpha1.ResourceOverride{
+					TypeMeta:   roTypeMeta,
+					ObjectMeta: metav1.ObjectMeta{Name: "configmap-settings", Namespace: "web"},
+					Spec: placementv1alpha1.ResourceOverrideSpec{
+						ResourceSelectors: []placementv1alpha1.ResourceSelector{{Group: "", Version: "v1", Kind: "ConfigMap", Name: "settings"}},
+						Policy: &placementv1alpha1.OverridePolicy{OverrideRules: []placementv1alpha1.OverrideRule{{
+							ClusterSelector: &placementv1beta1.ClusterSelector{ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{{
+								LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{nameSelector(metav1.LabelSelectorOpIn, "member-1")}},
+							}}},
+							JSONPatchOverrides: []placementv1alpha1.JSONPatchOverride{
+								{Operator: placementv1alpha1.JSONPatchOverrideOpReplace, Path: "/data/mode", Value: apiextensionsv1.JSON{Raw: []byte(`"canary"`)}},
+								{Operator: placementv1alpha1.JSONPatchOverrideOpRemove, Path: "/data/debug"},
+							},
+						}}},
+					},
+				},
+				&unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Secret",
+					"metadata":   map[string]interface{}{"name": "token", "namespace": "web"},
+					"type":       "Opaque",
+				}},
+			},
+			wantFields: []string{
+				"!FederatedConfigMap/web/settings spec.placement",
+				"FederatedConfigMap/web/settings spec.overrides",
+			},
+		},
+		"cluster-scoped object": {
+			objects: `
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedClusterRole
+metadata:
+  name: reader
+spec:
+  template:
+    rules:
+      - apiGroups: [""]
+        resources: [pods]
+        verbs: [get]
+  placement:
+    clusterSelector: {}
+---
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedWidget
+metadata:
+  name: widget
+  namespace: web
+spec:
+  template: {}
+  placement:
+    clusterSelector: {}
+---
+apiVersion: core.kubefed.io/v1beta1
+kind: KubeFedCluster
+metadata:
+  name: member-1
+  namespace: kube-federation-system
+`,
+			want: []client.Object{
+				&unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "rbac.authorization.k8s.io/v1",
+					"kind":       "ClusterRole",
+					"metadata":   map[string]interface{}{"name": "reader"},
+					"rules": []interface{}{map[string]interface{}{
+						"apiGroups": []interface{}{""},
+						"resources": []interface{}{"pods"},
+						"verbs":     []interface{}{"get"},
+					}},
+				}},
+				&placementv1beta1.ClusterResourcePlacement{
+					TypeMeta:   crpTypeMeta,
+					ObjectMeta: metav1.ObjectMeta{Name: "clusterrole-reader"},
+					Spec: placementv1beta1.ClusterResourcePlacementSpec{
+						ResourceSelectors: []placementv1beta1.ClusterResourceSelector{{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "reader"}},
+						Policy:            &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType},
+					},
+				},
+			},
+			wantFields: []string{
+				"!KubeFedCluster/kube-federation-system/member-1 ",
+				"!FederatedWidget/web/widget spec.template",
+			},
+		},
+		"unplaced object": {
+			objects: `
+apiVersion: types.kubefed.io/v1beta1
+kind: FederatedServiceAccount
+metadata:
+  name: robot
+  namespace: web
+spec:
+  template: {}
+  placement:
+    clusters: []
+    clusterSelector: {}
+`,
+			want: []client.Object{
+				&unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ServiceAccount",
+					"metadata":   map[string]interface{}{"name": "robot", "namespace": "web"},
+				}},
+			},
+			wantFields: []string{"FederatedServiceAccount/web/robot spec.placement"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Convert(decodeString(t, tc.objects))
+			if diff := cmp.Diff(tc.want, got.Objects); diff != "" {
+				t.Errorf("Convert() objects mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFields, findingFields(got.Findings)); diff != "" {
+				t.Errorf("Convert() findings mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
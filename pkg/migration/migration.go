@@ -0,0 +1,241 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package migration converts the placement and override policies of other multi-cluster federations, i.e. Karmada
+// and KubeFed, into cluster resource placements and overrides, and reports the constructs which fleet does not
+// support, so that the users can review them before switching to fleet.
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// Finding is a construct of a source object which is not converted as is.
+type Finding struct {
+	// Object identifies the source object, e.g. PropagationPolicy/default/nginx.
+	Object string
+	// Field is the path of the construct in the source object.
+	Field string
+	// Message explains how the construct is handled.
+	Message string
+	// Unsupported is true if the construct is dropped, so that the converted objects behave differently.
+	Unsupported bool
+}
+
+// String returns the finding as a line of the report.
+func (f Finding) String() string {
+	severity := "NOTE"
+	if f.Unsupported {
+		severity = "UNSUPPORTED"
+	}
+	if f.Field == "" {
+		return fmt.Sprintf("%s\t%s: %s", severity, f.Object, f.Message)
+	}
+	return fmt.Sprintf("%s\t%s %s: %s", severity, f.Object, f.Field, f.Message)
+}
+
+// Result is the result of a conversion.
+type Result struct {
+	// Objects are the converted objects, i.e. the cluster resource placements, the overrides and, for KubeFed, the
+	// resources rendered from the templates of the federated objects.
+	Objects []client.Object
+	// Findings are the constructs which are not converted as is.
+	Findings []Finding
+}
+
+// HasUnsupported returns true if any construct is dropped in the conversion.
+func (r *Result) HasUnsupported() bool {
+	for _, f := range r.Findings {
+		if f.Unsupported {
+			return true
+		}
+	}
+	return false
+}
+
+// converter converts the objects of a source.
+type converter struct {
+	result Result
+	// object identifies the object being converted in the findings.
+	object string
+}
+
+func (c *converter) note(field, format string, args ...interface{}) {
+	c.result.Findings = append(c.result.Findings, Finding{Object: c.object, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *converter) unsupported(field, format string, args ...interface{}) {
+	c.result.Findings = append(c.result.Findings, Finding{Object: c.object, Field: field, Message: fmt.Sprintf(format, args...), Unsupported: true})
+}
+
+// Convert converts the Karmada and KubeFed objects into fleet objects. The other objects are skipped.
+func Convert(objs []*unstructured.Unstructured) *Result {
+	c := &converter{}
+	var federated []*unstructured.Unstructured
+	for _, obj := range objs {
+		c.object = objectRef(obj)
+		group := obj.GroupVersionKind().Group
+		switch {
+		case group == karmadaPolicyGroup:
+			c.convertKarmada(obj)
+		case group == kubeFedTypesGroup:
+			// the federated objects are converted together, as fleet places the namespaced ones by their namespaces
+			federated = append(federated, obj)
+		case strings.HasSuffix(group, kubeFedGroupSuffix):
+			c.unsupported("", "the KubeFed %s objects have no fleet counterparts; skipped", obj.GetKind())
+		default:
+			c.unsupported("", "not a Karmada or KubeFed object; skipped")
+		}
+	}
+	c.convertKubeFed(federated)
+	return &c.result
+}
+
+// Decode decodes the objects in the YAML or JSON stream, expanding the lists.
+func Decode(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, err
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+		if err := obj.EachListItem(func(item runtime.Object) error {
+			objs = append(objs, item.(*unstructured.Unstructured))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Write writes the objects as a YAML stream, without their empty status, strategies and creation timestamps.
+func Write(w io.Writer, objs []client.Object) error {
+	var buf bytes.Buffer
+	for i, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", obj.GetName(), err)
+		}
+		unstructured.RemoveNestedField(content, "status")
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+		if strategy, found, _ := unstructured.NestedMap(content, "spec", "strategy"); found && len(strategy) == 0 {
+			unstructured.RemoveNestedField(content, "spec", "strategy")
+		}
+		out, err := yaml.Marshal(content)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", obj.GetName(), err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(out)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func objectRef(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// clusterScopedKinds are the kinds of the well-known cluster-scoped resources.
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"StorageClass":                   true,
+	"PersistentVolume":               true,
+	"PriorityClass":                  true,
+	"IngressClass":                   true,
+	"RuntimeClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+	"MutatingWebhookConfiguration":   true,
+	"APIService":                     true,
+}
+
+// namespaceSelector returns the selector of the namespace, which places all the resources in it.
+func namespaceSelector(namespace string) placementv1beta1.ClusterResourceSelector {
+	return placementv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: namespace}
+}
+
+// clusterNamesSelector returns the label selector which selects or excludes the member clusters by their names.
+// Fleet selects the member clusters by labels only, so the clusters must be labeled with their names.
+func clusterNamesSelector(selector *metav1.LabelSelector, include, exclude []string) *metav1.LabelSelector {
+	if len(include) == 0 && len(exclude) == 0 {
+		return selector
+	}
+	selector = selector.DeepCopy()
+	if selector == nil {
+		selector = &metav1.LabelSelector{}
+	}
+	if len(include) > 0 {
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      placementv1beta1.MemberClusterNameLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   include,
+		})
+	}
+	if len(exclude) > 0 {
+		selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      placementv1beta1.MemberClusterNameLabel,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   exclude,
+		})
+	}
+	return selector
+}
+
+// clusterNameLabelMessage is the note of the conversion which selects the member clusters by their names.
+var clusterNameLabelMessage = fmt.Sprintf("fleet selects the member clusters by labels; label each member cluster with %s=<name> to select it by name", placementv1beta1.MemberClusterNameLabel)
+
+// parseGroupVersion returns the group and version of the API version, reporting the invalid ones.
+func (c *converter) parseGroupVersion(field, apiVersion string) (schema.GroupVersion, bool) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || gv.Version == "" {
+		c.unsupported(field, "invalid apiVersion %q; skipped", apiVersion)
+		return schema.GroupVersion{}, false
+	}
+	return gv, true
+}
+
+// sortedKeys returns the keys of the map in order, so that the conversion is deterministic.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package migration
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestDecode(t *testing.T) {
+	objs := decodeString(t, `
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: policy.karmada.io/v1alpha1
+    kind: PropagationPolicy
+    metadata:
+      name: first
+      namespace: web
+  - apiVersion: policy.karmada.io/v1alpha1
+    kind: PropagationPolicy
+    metadata:
+      name: second
+      namespace: web
+---
+---
+{"apiVersion": "types.kubefed.io/v1beta1", "kind": "FederatedNamespace", "metadata": {"name": "web", "namespace": "web"}}
+`)
+	var got []string
+	for _, obj := range objs {
+		got = append(got, objectRef(obj))
+	}
+	want := []string{"PropagationPolicy/web/first", "PropagationPolicy/web/second", "FederatedNamespace/web/web"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Decode() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	objs := []client.Object{
+		&placementv1beta1.ClusterResourcePlacement{
+			TypeMeta:   crpTypeMeta,
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: placementv1beta1.ClusterResourcePlacementSpec{
+				ResourceSelectors: []placementv1beta1.ClusterResourceSelector{namespaceSelector("web")},
+				Policy:            &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType},
+			},
+		},
+		&placementv1beta1.ClusterResourcePlacement{
+			TypeMeta:   crpTypeMeta,
+			ObjectMeta: metav1.ObjectMeta{Name: "rolling"},
+			Spec: placementv1beta1.ClusterResourcePlacementSpec{
+				ResourceSelectors: []placementv1beta1.ClusterResourceSelector{namespaceSelector("rolling")},
+				Strategy:          placementv1beta1.RolloutStrategy{Type: placementv1beta1.RollingUpdateRolloutStrategyType},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, objs); err != nil {
+		t.Fatalf("Write() = %v, want nil", err)
+	}
+	want := `apiVersion: placement.kubernetes-fleet.io/v1beta1
+kind: ClusterResourcePlacement
+metadata:
+  name: web
+spec:
+  policy:
+    placementType: PickAll
+  resourceSelectors:
+  - group: ""
+    kind: Namespace
+    name: web
+    version: v1
+---
+apiVersion: placement.kubernetes-fleet.io/v1beta1
+kind: ClusterResourcePlacement
+metadata:
+  name: rolling
+spec:
+  resourceSelectors:
+  - group: ""
+    kind: Namespace
+    name: rolling
+    version: v1
+  strategy:
+    type: RollingUpdate
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Write() mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/controller"
 	"go.goms.io/fleet/pkg/utils/informer"
@@ -77,6 +78,10 @@ type ChangeDetector struct {
 	// ConcurrentResourceChangeWorker is the number of resource change work that are
 	// allowed to sync concurrently.
 	ConcurrentResourceChangeWorker int
+
+	// Sharder shards the placements across the replicas of the hub agent if set; the detector then runs on every
+	// replica, as the placement controller skips the placements of the other replicas.
+	Sharder *sharding.Sharder
 }
 
 // Start runs the detector, never stop until stopCh closed. This is called by the controller manager.
@@ -233,9 +238,9 @@ func (d *ChangeDetector) dynamicResourceFilter(obj interface{}) bool {
 }
 
 // NeedLeaderElection implements LeaderElectionRunnable interface.
-// So that the detector could run in the leader election mode.
+// So that the detector could run in the leader election mode unless the placements are sharded.
 func (d *ChangeDetector) NeedLeaderElection() bool {
-	return true
+	return d.Sharder == nil
 }
 
 // newHandlerOnEvents builds a ResourceEventHandler.
@@ -19,6 +19,7 @@ import (
 
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
 )
 
 // handleTombStoneObj handles the case that the delete object is a tombStone instead of the real object
@@ -82,8 +83,8 @@ func (d *ChangeDetector) onWorkUpdated(oldObj, newObj interface{}) {
 	// we never change the placement label of a work
 	if placementName, exist := oldWorkMeta.GetLabels()[utils.LabelWorkPlacementName]; exist {
 		klog.V(3).InfoS("a work object is updated, will enqueue a placement event", "work", klog.KObj(oldWorkMeta), "placement", placementName)
-		// the meta key function handles string
-		d.ClusterResourcePlacementControllerV1Alpha1.Enqueue(placementName)
+		// the meta key function handles string; the work status updates are processed after the user-triggered changes
+		d.ClusterResourcePlacementControllerV1Alpha1.EnqueueWithPriority(placementName, controller.PriorityLow)
 	} else {
 		klog.V(4).InfoS("ignore an updated work object without a placement label", "work", klog.KObj(oldWorkMeta))
 	}
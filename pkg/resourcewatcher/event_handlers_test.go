@@ -314,6 +314,10 @@ func (t *fakeController) Enqueue(_ interface{}) {
 	t.Enqueued = true
 }
 
+func (t *fakeController) EnqueueWithPriority(_ interface{}, _ controller.Priority) {
+	t.Enqueued = true
+}
+
 func (t *fakeController) Run(_ context.Context, _ int) error {
 	//TODO implement me
 	panic("implement me")
@@ -0,0 +1,162 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/annotations"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// The reason to use for the clusters which pass the filters of a PickN placement that has picked enough clusters.
+	notPickedAsEnoughPickedReasonTemplate = "Cluster \"%s\" is not picked as the placement has picked enough clusters (affinity score: %d, topology spread score: %d)"
+)
+
+// ExplainSchedulingFor runs a scheduling cycle for a cluster resource placement (more specifically, a scheduling
+// policy snapshot of it) without creating or updating any binding, and returns the scheduling decisions the cycle
+// makes on all the clusters, including the ones filtered out, in the order of:
+//
+// * the clusters which have been picked by the placement;
+// * the clusters which would be picked in the cycle;
+// * the clusters which pass the filters but are not picked, sorted by their scores; and
+// * the clusters which are filtered out, sorted by their names.
+//
+// Note that the preemption of the placements of lower priorities is not explained.
+func (f *framework) ExplainSchedulingFor(ctx context.Context, crpName string, policy *placementv1beta1.ClusterSchedulingPolicySnapshot) ([]placementv1beta1.ClusterDecision, error) {
+	policyRef := klog.KObj(policy)
+
+	clusters, err := f.collectClusters(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to collect clusters", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, err
+	}
+	bindings, err := f.collectBindings(ctx, crpName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to collect bindings", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, err
+	}
+	bound, scheduled, obsolete, _, _ := classifyBindings(policy, bindings, clusters)
+
+	if policy.Spec.Policy != nil && policy.Spec.Policy.PlacementType == placementv1beta1.PickFixedPlacementType {
+		// Only the target clusters are checked for a scheduling policy of the PickFixed placement type.
+		valid, invalid, notFound := f.crossReferenceClustersWithTargetNames(clusters, policy.Spec.Policy.ClusterNames)
+		return newSchedulingDecisionsForPickFixedPlacementType(valid, invalid, notFound), nil
+	}
+
+	state := NewCycleState(clusters, obsolete, bound, scheduled)
+	numToPick := -1 // All the clusters which pass the filters are picked for the PickAll placement type.
+	if policy.Spec.Policy != nil && policy.Spec.Policy.PlacementType == placementv1beta1.PickNPlacementType {
+		numOfClusters, err := annotations.ExtractNumOfClustersFromPolicySnapshot(policy)
+		if err != nil {
+			klog.ErrorS(err, "Failed to extract number of clusters required from policy snapshot", "clusterSchedulingPolicySnapshot", policyRef)
+			return nil, controller.NewUnexpectedBehaviorError(err)
+		}
+		numToPick = 0
+		if state.desiredBatchSize = numOfClusters - len(bound) - len(scheduled); state.desiredBatchSize > 0 {
+			batchSizeLimit, status := f.runPostBatchPlugins(ctx, state, policy)
+			if status.IsInteralError() {
+				klog.ErrorS(status.AsError(), "Failed to run post batch plugins", "clusterSchedulingPolicySnapshot", policyRef)
+				return nil, controller.NewUnexpectedBehaviorError(status.AsError())
+			}
+			state.batchSizeLimit = batchSizeLimit
+			numToPick = batchSizeLimit
+		}
+	}
+
+	if status := f.runPreFilterPlugins(ctx, state, policy); status.IsInteralError() {
+		klog.ErrorS(status.AsError(), "Failed to run pre filter plugins", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, controller.NewUnexpectedBehaviorError(status.AsError())
+	}
+	passed, filtered, err := f.runFilterPlugins(ctx, state, policy, clusters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to run filter plugins", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, controller.NewUnexpectedBehaviorError(err)
+	}
+
+	var picked, notPicked ScoredClusters
+	if numToPick < 0 {
+		// The Score stage does not run for the PickAll placement type.
+		for _, cluster := range passed {
+			picked = append(picked, &ScoredCluster{Cluster: cluster, Score: &ClusterScore{}})
+		}
+		sort.Sort(picked)
+	} else {
+		if status := f.runPreScorePlugins(ctx, state, policy); status.IsInteralError() {
+			klog.ErrorS(status.AsError(), "Failed to run pre-score plugins", "clusterSchedulingPolicySnapshot", policyRef)
+			return nil, controller.NewUnexpectedBehaviorError(status.AsError())
+		}
+		scored, err := f.runScorePlugins(ctx, state, policy, passed)
+		if err != nil {
+			klog.ErrorS(err, "Failed to run score plugins", "clusterSchedulingPolicySnapshot", policyRef)
+			return nil, controller.NewUnexpectedBehaviorError(err)
+		}
+		picked, notPicked = pickTopNScoredClusters(scored, numToPick)
+	}
+	return newExplainedDecisions(numToPick == 0, picked, notPicked, filtered, bound, scheduled), nil
+}
+
+// newExplainedDecisions returns the scheduling decisions on all the clusters explained in a scheduling cycle.
+func newExplainedDecisions(
+	enoughPicked bool,
+	picked, notPicked ScoredClusters,
+	filtered []*filteredClusterWithStatus,
+	existing ...[]*placementv1beta1.ClusterResourceBinding,
+) []placementv1beta1.ClusterDecision {
+	decisions := make([]placementv1beta1.ClusterDecision, 0, len(picked)+len(notPicked)+len(filtered))
+	for _, bindingSet := range existing {
+		for _, binding := range bindingSet {
+			decisions = append(decisions, binding.Spec.ClusterDecision)
+		}
+	}
+	for _, sc := range picked {
+		decisions = append(decisions, placementv1beta1.ClusterDecision{
+			ClusterName: sc.Cluster.Name,
+			Selected:    true,
+			ClusterScore: &placementv1beta1.ClusterScore{
+				AffinityScore:       ptr.To(int32(sc.Score.AffinityScore)),
+				TopologySpreadScore: ptr.To(int32(sc.Score.TopologySpreadScore)),
+			},
+			Reason: fmt.Sprintf(resourceScheduleSucceededWithScoreMessageFormat, sc.Cluster.Name, sc.Score.AffinityScore, sc.Score.TopologySpreadScore),
+		})
+	}
+	reasonTemplate := notPickedByScoreReasonTemplate
+	if enoughPicked {
+		reasonTemplate = notPickedAsEnoughPickedReasonTemplate
+	}
+	for _, sc := range notPicked {
+		decisions = append(decisions, placementv1beta1.ClusterDecision{
+			ClusterName: sc.Cluster.Name,
+			Selected:    false,
+			ClusterScore: &placementv1beta1.ClusterScore{
+				AffinityScore:       ptr.To(int32(sc.Score.AffinityScore)),
+				TopologySpreadScore: ptr.To(int32(sc.Score.TopologySpreadScore)),
+			},
+			Reason: fmt.Sprintf(reasonTemplate, sc.Cluster.Name, sc.Score.AffinityScore, sc.Score.TopologySpreadScore),
+		})
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].cluster.Name < filtered[j].cluster.Name
+	})
+	for _, fc := range filtered {
+		decisions = append(decisions, placementv1beta1.ClusterDecision{
+			ClusterName: fc.cluster.Name,
+			Selected:    false,
+			Reason:      fc.status.String(),
+		})
+	}
+	if len(decisions) > clustersDecisionArrayLengthLimitInAPI {
+		decisions = decisions[:clustersDecisionArrayLengthLimitInAPI]
+	}
+	return decisions
+}
@@ -0,0 +1,221 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/clustereligibilitychecker"
+	"go.goms.io/fleet/pkg/scheduler/framework/parallelizer"
+)
+
+// TestExplainSchedulingFor tests the ExplainSchedulingFor method.
+func TestExplainSchedulingFor(t *testing.T) {
+	newCluster := func(name, env string, score int) *clusterv1beta1.MemberCluster {
+		return &clusterv1beta1.MemberCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"env": env, "score": strconv.Itoa(score)},
+			},
+		}
+	}
+	boundDecision := placementv1beta1.ClusterDecision{
+		ClusterName: clusterName,
+		Selected:    true,
+		Reason:      "picked in an earlier scheduling cycle",
+	}
+	boundBinding := &placementv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   bindingName,
+			Labels: map[string]string{placementv1beta1.CRPTrackingLabel: crpName},
+		},
+		Spec: placementv1beta1.ResourceBindingSpec{
+			State:                        placementv1beta1.BindingStateBound,
+			SchedulingPolicySnapshotName: policyName,
+			TargetCluster:                clusterName,
+			ClusterDecision:              boundDecision,
+		},
+	}
+	objects := []client.Object{
+		newCluster(clusterName, "prod", 5),
+		newCluster(altClusterName, "prod", 3),
+		newCluster(anotherClusterName, "prod", 7),
+		newCluster("lazyfox", "staging", 9),
+		boundBinding,
+	}
+	pickedDecision := func(name string, score int32) placementv1beta1.ClusterDecision {
+		return placementv1beta1.ClusterDecision{
+			ClusterName:  name,
+			Selected:     true,
+			ClusterScore: &placementv1beta1.ClusterScore{AffinityScore: ptr.To(score), TopologySpreadScore: ptr.To(int32(0))},
+			Reason:       fmt.Sprintf(resourceScheduleSucceededWithScoreMessageFormat, name, score, 0),
+		}
+	}
+	notPickedDecision := func(template, name string, score int32) placementv1beta1.ClusterDecision {
+		return placementv1beta1.ClusterDecision{
+			ClusterName:  name,
+			Selected:     false,
+			ClusterScore: &placementv1beta1.ClusterScore{AffinityScore: ptr.To(score), TopologySpreadScore: ptr.To(int32(0))},
+			Reason:       fmt.Sprintf(template, name, score, 0),
+		}
+	}
+	filteredDecision := placementv1beta1.ClusterDecision{
+		ClusterName: "lazyfox",
+		Selected:    false,
+		Reason:      NewNonErrorStatus(ClusterUnschedulable, dummyPluginName, "the cluster is not a production cluster").String(),
+	}
+
+	testCases := []struct {
+		name          string
+		policy        *placementv1beta1.ClusterSchedulingPolicySnapshot
+		wantDecisions []placementv1beta1.ClusterDecision
+	}{
+		{
+			name: "pickN placement",
+			policy: &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        policyName,
+					Annotations: map[string]string{placementv1beta1.NumberOfClustersAnnotation: "2"},
+				},
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickNPlacementType},
+				},
+			},
+			wantDecisions: []placementv1beta1.ClusterDecision{
+				boundDecision,
+				pickedDecision(anotherClusterName, 7),
+				notPickedDecision(notPickedByScoreReasonTemplate, altClusterName, 3),
+				filteredDecision,
+			},
+		},
+		{
+			name: "pickN placement with enough clusters picked",
+			policy: &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        policyName,
+					Annotations: map[string]string{placementv1beta1.NumberOfClustersAnnotation: "1"},
+				},
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickNPlacementType},
+				},
+			},
+			wantDecisions: []placementv1beta1.ClusterDecision{
+				boundDecision,
+				notPickedDecision(notPickedAsEnoughPickedReasonTemplate, anotherClusterName, 7),
+				notPickedDecision(notPickedAsEnoughPickedReasonTemplate, altClusterName, 3),
+				filteredDecision,
+			},
+		},
+		{
+			name: "pickN placement with a new policy",
+			policy: &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "new-policy",
+					Annotations: map[string]string{placementv1beta1.NumberOfClustersAnnotation: "2"},
+				},
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickNPlacementType},
+				},
+			},
+			wantDecisions: []placementv1beta1.ClusterDecision{
+				pickedDecision(anotherClusterName, 7),
+				pickedDecision(clusterName, 5),
+				notPickedDecision(notPickedByScoreReasonTemplate, altClusterName, 3),
+				filteredDecision,
+			},
+		},
+		{
+			name: "pickAll placement",
+			policy: &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: policyName},
+			},
+			wantDecisions: []placementv1beta1.ClusterDecision{
+				boundDecision,
+				pickedDecision(anotherClusterName, 0),
+				pickedDecision(altClusterName, 0),
+				filteredDecision,
+			},
+		},
+		{
+			name: "pickFixed placement",
+			policy: &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{Name: policyName},
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{
+						PlacementType: placementv1beta1.PickFixedPlacementType,
+						ClusterNames:  []string{altClusterName, "unknown"},
+					},
+				},
+			},
+			wantDecisions: []placementv1beta1.ClusterDecision{
+				{
+					// the clusters in the test have not joined the fleet
+					ClusterName: altClusterName,
+					Selected:    false,
+					Reason:      fmt.Sprintf(pickFixedInvalidClusterReasonTemplate, altClusterName, "cluster is not connected to the fleet: member agent not online yet"),
+				},
+				{
+					ClusterName: "unknown",
+					Selected:    false,
+					Reason:      fmt.Sprintf(pickFixedNotFoundClusterReasonTemplate, "unknown"),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+			profile := NewProfile(dummyProfileName)
+			profile.WithFilterPlugin(&DummyAllPurposePlugin{
+				name: dummyPluginName,
+				filterRunner: func(_ context.Context, state CycleStatePluginReadWriter, _ *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) *Status {
+					if state.HasScheduledOrBoundBindingFor(cluster.Name) {
+						return NewNonErrorStatus(ClusterAlreadySelected, dummyPluginName)
+					}
+					if cluster.Labels["env"] != "prod" {
+						return NewNonErrorStatus(ClusterUnschedulable, dummyPluginName, "the cluster is not a production cluster")
+					}
+					return nil
+				},
+			})
+			profile.WithScorePlugin(&DummyAllPurposePlugin{
+				name: dummyPluginName,
+				scoreRunner: func(_ context.Context, _ CycleStatePluginReadWriter, _ *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) (*ClusterScore, *Status) {
+					score, _ := strconv.Atoi(cluster.Labels["score"])
+					return &ClusterScore{AffinityScore: score}, nil
+				},
+			})
+			f := &framework{
+				profile:                   profile,
+				client:                    fakeClient,
+				uncachedReader:            fakeClient,
+				parallelizer:              parallelizer.NewParallelizer(parallelizer.DefaultNumOfWorkers),
+				clusterEligibilityChecker: clustereligibilitychecker.New(),
+			}
+
+			decisions, err := f.ExplainSchedulingFor(context.Background(), crpName, tc.policy)
+			if err != nil {
+				t.Fatalf("ExplainSchedulingFor() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantDecisions, decisions); diff != "" {
+				t.Errorf("ExplainSchedulingFor() decisions mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"sync/atomic"
 	"time"
 
@@ -83,6 +84,10 @@ type Framework interface {
 	// RunSchedulingCycleFor performs scheduling for a cluster resource placement, specifically
 	// its associated latest scheduling policy snapshot.
 	RunSchedulingCycleFor(ctx context.Context, crpName string, policy *placementv1beta1.ClusterSchedulingPolicySnapshot) (result ctrl.Result, err error)
+
+	// ExplainSchedulingFor runs a scheduling cycle for a cluster resource placement without creating or
+	// updating any binding, and returns the scheduling decisions on all the clusters.
+	ExplainSchedulingFor(ctx context.Context, crpName string, policy *placementv1beta1.ClusterSchedulingPolicySnapshot) ([]placementv1beta1.ClusterDecision, error)
 }
 
 // framework implements the Framework interface.
@@ -114,6 +119,13 @@ type framework struct {
 	//
 	// Note that all picked clusters will always have their associated decisions written to the status.
 	maxUnselectedClusterDecisionCount int
+
+	// maxClusterExplanationCount controls the maximum number of explanations for unselected clusters
+	// added to the policy snapshot status.
+	maxClusterExplanationCount int
+
+	// scoreCache caches the scores of the cacheable score plugins across scheduling cycles.
+	scoreCache *scoreCache
 }
 
 var (
@@ -131,9 +143,17 @@ type frameworkOptions struct {
 	// unselected clusters added to the policy snapshot status.
 	maxUnselectedClusterDecisionCount int
 
+	// maxClusterExplanationCount controls the maximum number of explanations for
+	// unselected clusters added to the policy snapshot status.
+	maxClusterExplanationCount int
+
 	// checker is the cluster eligibility checker the scheduler framework will use to check
 	// if a cluster is eligibile for resource placement.
 	clusterEligibilityChecker *clustereligibilitychecker.ClusterEligibilityChecker
+
+	// scoreCacheSize is the maximum number of scores the scheduler framework caches for the cacheable
+	// score plugins; the scores are not cached if it is zero.
+	scoreCacheSize int
 }
 
 // Option is the function for configuring a scheduler framework.
@@ -143,7 +163,9 @@ type Option func(*frameworkOptions)
 var defaultFrameworkOptions = frameworkOptions{
 	numOfWorkers:                      parallelizer.DefaultNumOfWorkers,
 	maxUnselectedClusterDecisionCount: 20,
+	maxClusterExplanationCount:        100,
 	clusterEligibilityChecker:         clustereligibilitychecker.New(),
+	scoreCacheSize:                    defaultScoreCacheSize,
 }
 
 // WithNumOfWorkers sets the number of workers to use for a scheduler framework.
@@ -160,6 +182,13 @@ func WithMaxClusterDecisionCount(maxUnselectedClusterDecisionCount int) Option {
 	}
 }
 
+// WithMaxClusterExplanationCount sets the maximum number of explanations added to the policy snapshot status.
+func WithMaxClusterExplanationCount(maxClusterExplanationCount int) Option {
+	return func(fo *frameworkOptions) {
+		fo.maxClusterExplanationCount = maxClusterExplanationCount
+	}
+}
+
 // WithClusterEligibilityChecker sets the cluster eligibility checker for a scheduler framework.
 func WithClusterEligibilityChecker(checker *clustereligibilitychecker.ClusterEligibilityChecker) Option {
 	return func(fo *frameworkOptions) {
@@ -167,6 +196,14 @@ func WithClusterEligibilityChecker(checker *clustereligibilitychecker.ClusterEli
 	}
 }
 
+// WithScoreCacheSize sets the maximum number of scores the scheduler framework caches for the cacheable score
+// plugins; zero disables the score cache.
+func WithScoreCacheSize(scoreCacheSize int) Option {
+	return func(fo *frameworkOptions) {
+		fo.scoreCacheSize = scoreCacheSize
+	}
+}
+
 // NewFramework returns a new scheduler framework.
 func NewFramework(profile *Profile, manager ctrl.Manager, opts ...Option) Framework {
 	options := defaultFrameworkOptions
@@ -195,7 +232,9 @@ func NewFramework(profile *Profile, manager ctrl.Manager, opts ...Option) Framew
 		eventRecorder:                     manager.GetEventRecorderFor(fmt.Sprintf(eventRecorderNameTemplate, profile.Name())),
 		parallelizer:                      parallelizer.NewParallelizer(options.numOfWorkers),
 		maxUnselectedClusterDecisionCount: options.maxUnselectedClusterDecisionCount,
+		maxClusterExplanationCount:        options.maxClusterExplanationCount,
 		clusterEligibilityChecker:         options.clusterEligibilityChecker,
+		scoreCache:                        newScoreCache(options.scoreCacheSize),
 	}
 	// initialize all the plugins
 	for _, plugin := range f.profile.registeredPlugins {
@@ -294,6 +333,13 @@ func (f *framework) RunSchedulingCycleFor(ctx context.Context, crpName string, p
 	// result so that we won't have a ever increasing chain of flip flop bindings.
 	bound, scheduled, obsolete, unscheduled, dangling := classifyBindings(policy, bindings, clusters)
 
+	// Fence the bindings against an empty view of the fleet, e.g. a cache which is not filled yet as the scheduler
+	// restarts, which would otherwise unschedule all the bindings at once.
+	if err := f.checkClusterView(ctx, clusters, bound, scheduled, obsolete, dangling); err != nil {
+		klog.ErrorS(err, "Stopped the scheduling cycle as the view of the member clusters is not trusted", "clusterSchedulingPolicySnapshot", policyRef)
+		return ctrl.Result{}, err
+	}
+
 	// Mark all dangling bindings as unscheduled.
 	if err := f.markAsUnscheduledFor(ctx, dangling); err != nil {
 		klog.ErrorS(err, "Failed to mark dangling bindings as unscheduled", "clusterSchedulingPolicySnapshot", policyRef)
@@ -349,6 +395,33 @@ func (f *framework) collectBindings(ctx context.Context, crpName string) ([]plac
 	return bindingList.Items, nil
 }
 
+// checkClusterView makes sure that the scheduler does not take an empty list of the member clusters from its cache as
+// all the clusters having left the fleet while the placement still has bindings on some clusters: the clusters are
+// listed again from the API server, and the scheduling cycle is retried later if the API server still has some.
+func (f *framework) checkClusterView(ctx context.Context, clusters []clusterv1beta1.MemberCluster, bindingGroups ...[]*placementv1beta1.ClusterResourceBinding) error {
+	if len(clusters) > 0 {
+		return nil
+	}
+	hasBinding := false
+	for _, group := range bindingGroups {
+		if len(group) > 0 {
+			hasBinding = true
+			break
+		}
+	}
+	if !hasBinding {
+		return nil
+	}
+	clusterList := &clusterv1beta1.MemberClusterList{}
+	if err := f.uncachedReader.List(ctx, clusterList, &client.ListOptions{Limit: 1}); err != nil {
+		return controller.NewAPIServerError(false, err)
+	}
+	if len(clusterList.Items) > 0 {
+		return controller.NewExpectedBehaviorError(fmt.Errorf("no member cluster is found in the cache while the API server has some"))
+	}
+	return nil
+}
+
 // markAsUnscheduledFor marks a list of bindings as unscheduled.
 func (f *framework) markAsUnscheduledFor(ctx context.Context, bindings []*placementv1beta1.ClusterResourceBinding) error {
 	// issue all the update requests in parallel
@@ -364,7 +437,13 @@ func (f *framework) markAsUnscheduledFor(ctx context.Context, bindings []*placem
 					// Remember the previous unscheduledBinding state so that we might be able to revert this change if this
 					// cluster is being selected again before the resources are removed from it. Need to do a get and set if
 					// we add more annotations to the binding.
-					unscheduledBinding.SetAnnotations(map[string]string{placementv1beta1.PreviousBindingStateAnnotation: string(unscheduledBinding.Spec.State)})
+					//
+					// Also record the generation which the state change advances the binding to, as the explicit signal
+					// for the rollout controller to remove the binding from its cluster.
+					unscheduledBinding.SetAnnotations(map[string]string{
+						placementv1beta1.PreviousBindingStateAnnotation:  string(unscheduledBinding.Spec.State),
+						placementv1beta1.UnscheduledGenerationAnnotation: strconv.FormatInt(unscheduledBinding.Generation+1, 10),
+					})
 					// Mark the unscheduledBinding as unscheduled which can conflict with the rollout controller which also changes the state of a
 					// unscheduledBinding from "scheduled" to "bound".
 					unscheduledBinding.Spec.State = placementv1beta1.BindingStateUnscheduled
@@ -718,6 +797,9 @@ func (f *framework) patchBindings(ctx context.Context, toPatch []*bindingWithPat
 
 // updatePolicySnapshotStatusFromBindings updates the policy snapshot status, in accordance with the list of
 // clusters filtered out by the scheduler, and the list of bindings provisioned by the scheduler.
+//
+// Note that the list of filtered clusters is nil if the filter plugins have not run in the scheduling cycle;
+// the explanations for the unselected clusters in the status are kept as they are in this case.
 func (f *framework) updatePolicySnapshotStatusFromBindings(
 	ctx context.Context,
 	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
@@ -739,22 +821,30 @@ func (f *framework) updatePolicySnapshotStatusFromBindings(
 	newDecisions := newSchedulingDecisionsFromBindings(f.maxUnselectedClusterDecisionCount, notPicked, filtered, existing...)
 	// Prepare new scheduling condition.
 	newCondition := newScheduledConditionFromBindings(policy, numOfClusters, existing...)
+	// Prepare new explanations for the unselected clusters.
+	currentExplanations := policy.Status.ClusterExplanations
+	newExplanations := currentExplanations
+	if filtered != nil {
+		newExplanations = newClusterExplanations(f.maxClusterExplanationCount, currentExplanations, notPicked, filtered)
+	}
 
-	// Compare the new decisions + condition with the old ones.
+	// Compare the new decisions + explanations + condition with the old ones.
 	currentDecisions := policy.Status.ClusterDecisions
 	currentCondition := meta.FindStatusCondition(policy.Status.Conditions, string(placementv1beta1.PolicySnapshotScheduled))
 	if observedCRPGeneration == policy.Status.ObservedCRPGeneration &&
 		equalDecisions(currentDecisions, newDecisions) &&
+		equalExplanations(currentExplanations, newExplanations) &&
 		condition.EqualCondition(currentCondition, &newCondition) {
-		// Skip if there is no change in decisions and conditions.
+		// Skip if there is no change in decisions, explanations and conditions.
 		klog.InfoS(
-			"No change in scheduling decisions and condition, and the observed CRP generation remains the same",
+			"No change in scheduling decisions, explanations and condition, and the observed CRP generation remains the same",
 			"clusterSchedulingPolicySnapshot", policyRef)
 		return nil
 	}
 
 	// Update the status.
 	policy.Status.ClusterDecisions = newDecisions
+	policy.Status.ClusterExplanations = newExplanations
 	policy.Status.ObservedCRPGeneration = observedCRPGeneration
 	meta.SetStatusCondition(&policy.Status.Conditions, newCondition)
 	if err := f.client.Status().Update(ctx, policy, &client.SubResourceUpdateOptions{}); err != nil {
@@ -870,6 +960,18 @@ func (f *framework) runSchedulingCycleForPickNPlacementType(
 		return ctrl.Result{}, err
 	}
 
+	// Preempt the placements of lower priorities if the scheduler cannot find enough clusters for the placement.
+	//
+	// Note that this step only runs if the scheduling policy has a priority; the clusters picked by preemption are
+	// added to the scored clusters, which are then fewer than the batch size limit, so that they are always picked.
+	// The bindings on these clusters are only preempted once the clusters to pick are final.
+	preempting, filtered, preemptionCandidates, err := f.runPreemption(ctx, state, crpName, policy, state.batchSizeLimit-len(scored), filtered)
+	if err != nil {
+		klog.ErrorS(err, "Failed to run preemption", "clusterSchedulingPolicySnapshot", policyRef)
+		return ctrl.Result{}, err
+	}
+	scored = append(scored, preempting...)
+
 	// Pick the top scored clusters.
 	klog.V(2).InfoS("Picking clusters", "clusterSchedulingPolicySnapshot", policyRef)
 
@@ -907,6 +1009,15 @@ func (f *framework) runSchedulingCycleForPickNPlacementType(
 		klog.ErrorS(err, "Failed to cross-reference bindings with picked clusters", "clusterSchedulingPolicySnapshot", policyRef)
 		return ctrl.Result{}, err
 	}
+	preemptionCandidates = pickedPreemptionCandidates(preemptionCandidates, picked)
+	annotatePreemptingDecisions(preemptedPlacements(preemptionCandidates), toCreate, toPatch)
+
+	// Preempt the bindings of the lower priority placements on the picked clusters, before the bindings of the
+	// placement are created on them.
+	if err := f.preempt(ctx, crpName, policy, preemptionCandidates); err != nil {
+		klog.ErrorS(err, "Failed to preempt the bindings of the lower priority placements", "clusterSchedulingPolicySnapshot", policyRef)
+		return ctrl.Result{}, err
+	}
 
 	// Manipulate bindings accordingly.
 	klog.V(2).InfoS("Manipulating bindings", "clusterSchedulingPolicySnapshot", policyRef)
@@ -1165,15 +1276,36 @@ func (f *framework) runScorePluginsFor(ctx context.Context, state *CycleState, p
 	// Pre-allocate score list to avoid races.
 	scoreList = make(map[string]*ClusterScore, len(f.profile.scorePlugins))
 
+	// The generation of the cluster state is computed lazily, only if a plugin has its scores cached.
+	var clusterStateGeneration uint64
+	var clusterStateGenerationComputed bool
+
 	for _, pl := range f.profile.scorePlugins {
 		// Skip the plugin if it is not needed.
 		if state.skippedScorePlugins.Has(pl.Name()) {
 			continue
 		}
+
+		// Reuse the cached score if the plugin allows it and neither the policy nor the cluster has changed.
+		var cacheKey *scoreCacheKey
+		if cpl, ok := pl.(CacheableScorePlugin); ok && f.scoreCache != nil && cpl.ScoreCacheable(policy) {
+			if !clusterStateGenerationComputed {
+				clusterStateGeneration, clusterStateGenerationComputed = clusterStateGenerationOf(cluster), true
+			}
+			cacheKey = &scoreCacheKey{plugin: pl.Name(), policyHash: policyHashOf(policy), cluster: cluster.Name}
+			if score, found := f.scoreCache.get(*cacheKey, clusterStateGeneration); found {
+				scoreList[pl.Name()] = score
+				continue
+			}
+		}
+
 		score, status := pl.Score(ctx, state, policy, cluster)
 		switch {
 		case status.IsSuccess():
 			scoreList[pl.Name()] = score
+			if cacheKey != nil {
+				f.scoreCache.set(*cacheKey, clusterStateGeneration, score)
+			}
 		case status.IsInteralError():
 			return nil, status
 		default:
@@ -1208,8 +1340,11 @@ func (f *framework) runScorePlugins(ctx context.Context, state *CycleState, poli
 		switch {
 		case status.IsSuccess():
 			totalScore := &ClusterScore{}
-			for _, score := range scoreList {
-				totalScore.Add(score)
+			for pluginName, score := range scoreList {
+				totalScore.Add(f.profile.weightedScore(pluginName, score))
+			}
+			if policy.Spec.Policy != nil && policy.Spec.Policy.ScoreWeights != nil {
+				totalScore.ApplyWeights(policy.Spec.Policy.ScoreWeights)
 			}
 			// Use atomic add to avoid races with minimum overhead.
 			newScoredClustersIdx := atomic.AddInt32(&scoredClustersIdx, 1)
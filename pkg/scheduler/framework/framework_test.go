@@ -7,6 +7,7 @@ package framework
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -20,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -27,6 +29,7 @@ import (
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/scheduler/clustereligibilitychecker"
 	"go.goms.io/fleet/pkg/scheduler/framework/parallelizer"
+	"go.goms.io/fleet/pkg/utils/controller"
 )
 
 const (
@@ -237,6 +240,67 @@ func TestCollectBindings(t *testing.T) {
 	}
 }
 
+// TestCheckClusterView tests the checkClusterView method.
+func TestCheckClusterView(t *testing.T) {
+	cluster := clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+	}
+	binding := &placementv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: bindingName,
+		},
+		Spec: placementv1beta1.ResourceBindingSpec{
+			State:         placementv1beta1.BindingStateBound,
+			TargetCluster: clusterName,
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		cachedClusters []clusterv1beta1.MemberCluster
+		apiClusters    []client.Object
+		bindings       []*placementv1beta1.ClusterResourceBinding
+		wantErr        error
+	}{
+		{
+			name:           "clusters are found in the cache",
+			cachedClusters: []clusterv1beta1.MemberCluster{cluster},
+			apiClusters:    []client.Object{&cluster},
+			bindings:       []*placementv1beta1.ClusterResourceBinding{binding},
+		},
+		{
+			name:        "no binding to unschedule",
+			apiClusters: []client.Object{&cluster},
+		},
+		{
+			name:     "all the clusters have left the fleet",
+			bindings: []*placementv1beta1.ClusterResourceBinding{binding},
+		},
+		{
+			name:        "no cluster is found in the cache while the API server has some",
+			apiClusters: []client.Object{&cluster},
+			bindings:    []*placementv1beta1.ClusterResourceBinding{binding},
+			wantErr:     controller.ErrExpectedBehavior,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.apiClusters...).Build()
+			// Construct framework manually instead of using NewFramework() to avoid mocking the controller manager.
+			f := &framework{
+				uncachedReader: fakeClient,
+			}
+			err := f.checkClusterView(context.Background(), tc.cachedClusters, tc.bindings)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("checkClusterView() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
 // TestClassifyBindings tests the classifyBindings function.
 func TestClassifyBindings(t *testing.T) {
 	policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
@@ -379,7 +443,8 @@ func TestClassifyBindings(t *testing.T) {
 func TestMarkAsUnscheduledFor(t *testing.T) {
 	boundBinding := placementv1beta1.ClusterResourceBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: bindingName,
+			Name:       bindingName,
+			Generation: 2,
 		},
 		Spec: placementv1beta1.ResourceBindingSpec{
 			State: placementv1beta1.BindingStateBound,
@@ -414,9 +479,11 @@ func TestMarkAsUnscheduledFor(t *testing.T) {
 	}
 	want := placementv1beta1.ClusterResourceBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: bindingName,
+			Name:       bindingName,
+			Generation: 2,
 			Annotations: map[string]string{
-				placementv1beta1.PreviousBindingStateAnnotation: string(placementv1beta1.BindingStateBound),
+				placementv1beta1.PreviousBindingStateAnnotation:  string(placementv1beta1.BindingStateBound),
+				placementv1beta1.UnscheduledGenerationAnnotation: "3",
 			},
 		},
 		Spec: placementv1beta1.ResourceBindingSpec{
@@ -434,7 +501,8 @@ func TestMarkAsUnscheduledFor(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: altBindingName,
 			Annotations: map[string]string{
-				placementv1beta1.PreviousBindingStateAnnotation: string(placementv1beta1.BindingStateScheduled),
+				placementv1beta1.PreviousBindingStateAnnotation:  string(placementv1beta1.BindingStateScheduled),
+				placementv1beta1.UnscheduledGenerationAnnotation: "1",
 			},
 		},
 		Spec: placementv1beta1.ResourceBindingSpec{
@@ -2079,7 +2147,8 @@ func TestManipulateBindings(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: anotherBindingName,
 			Annotations: map[string]string{
-				placementv1beta1.PreviousBindingStateAnnotation: string(placementv1beta1.BindingStateBound),
+				placementv1beta1.PreviousBindingStateAnnotation:  string(placementv1beta1.BindingStateBound),
+				placementv1beta1.UnscheduledGenerationAnnotation: "1",
 			},
 		},
 		Spec: placementv1beta1.ResourceBindingSpec{
@@ -2759,6 +2828,80 @@ func TestUpdatePolicySnapshotStatusFromBindings(t *testing.T) {
 	}
 }
 
+// TestUpdatePolicySnapshotStatusFromBindingsWithExplanations tests the updatePolicySnapshotStatusFromBindings method
+// with the explanations for the unselected clusters.
+func TestUpdatePolicySnapshotStatusFromBindingsWithExplanations(t *testing.T) {
+	filteredStatus := NewNonErrorStatus(ClusterUnschedulable, dummyPluginName, "filtered")
+	oldExplanations := []placementv1beta1.ClusterExplanation{
+		{
+			ClusterName:        altClusterName,
+			Plugin:             dummyPluginName,
+			Reason:             "old reason",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second)),
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		filtered         []*filteredClusterWithStatus
+		wantExplanations []placementv1beta1.ClusterExplanation
+	}{
+		{
+			name: "filter plugins have run",
+			filtered: []*filteredClusterWithStatus{
+				{cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: anotherClusterName}}, status: filteredStatus},
+			},
+			wantExplanations: []placementv1beta1.ClusterExplanation{
+				{ClusterName: anotherClusterName, Plugin: dummyPluginName, Reason: filteredStatus.String()},
+			},
+		},
+		{
+			name:             "filter plugins have run with no cluster filtered out",
+			filtered:         []*filteredClusterWithStatus{},
+			wantExplanations: nil,
+		},
+		{
+			name:             "filter plugins have not run",
+			wantExplanations: oldExplanations,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        policyName,
+					Annotations: map[string]string{placementv1beta1.CRPGenerationAnnotation: "1"},
+				},
+				Status: placementv1beta1.SchedulingPolicySnapshotStatus{ClusterExplanations: oldExplanations},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithStatusSubresource(policy).
+				WithScheme(scheme.Scheme).
+				WithObjects(policy).
+				Build()
+			f := &framework{
+				client:                     fakeClient,
+				maxClusterExplanationCount: 100,
+			}
+
+			ctx := context.Background()
+			if err := f.updatePolicySnapshotStatusFromBindings(ctx, policy, 0, nil, tc.filtered); err != nil {
+				t.Fatalf("updatePolicySnapshotStatusFromBindings() = %v, want no error", err)
+			}
+
+			updatedPolicy := &placementv1beta1.ClusterSchedulingPolicySnapshot{}
+			if err := f.client.Get(ctx, types.NamespacedName{Name: policyName}, updatedPolicy); err != nil {
+				t.Fatalf("Get policy snapshot, got %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantExplanations, updatedPolicy.Status.ClusterExplanations,
+				cmpopts.IgnoreFields(placementv1beta1.ClusterExplanation{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("policy snapshot status cluster explanations not equal (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
 // TestShouldDownscale tests the shouldDownscale function.
 func TestShouldDownscale(t *testing.T) {
 	testCases := []struct {
@@ -3689,6 +3832,93 @@ func TestNewSchedulingDecisionsFromBindings(t *testing.T) {
 	}
 }
 
+// TestNewClusterExplanations tests the newClusterExplanations function.
+func TestNewClusterExplanations(t *testing.T) {
+	lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	filteredStatus := NewNonErrorStatus(ClusterUnschedulable, dummyPluginName, "filtered")
+	filtered := []*filteredClusterWithStatus{
+		{cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: anotherClusterName}}, status: filteredStatus},
+		{cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: altClusterName}}, status: filteredStatus},
+	}
+	notPicked := ScoredClusters{
+		{
+			Cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName}},
+			Score:   &ClusterScore{AffinityScore: 5, TopologySpreadScore: 1},
+		},
+	}
+	filteredExplanation := func(name string) placementv1beta1.ClusterExplanation {
+		return placementv1beta1.ClusterExplanation{
+			ClusterName: name,
+			Plugin:      dummyPluginName,
+			Reason:      filteredStatus.String(),
+		}
+	}
+	notPickedExplanation := placementv1beta1.ClusterExplanation{
+		ClusterName: clusterName,
+		ClusterScore: &placementv1beta1.ClusterScore{
+			AffinityScore:       ptr.To(int32(5)),
+			TopologySpreadScore: ptr.To(int32(1)),
+		},
+		Reason: fmt.Sprintf(notPickedByScoreReasonTemplate, clusterName, 5, 1),
+	}
+	withTime := func(explanation placementv1beta1.ClusterExplanation) placementv1beta1.ClusterExplanation {
+		explanation.LastTransitionTime = lastTransitionTime
+		return explanation
+	}
+
+	testCases := []struct {
+		name                       string
+		maxClusterExplanationCount int
+		current                    []placementv1beta1.ClusterExplanation
+		want                       []placementv1beta1.ClusterExplanation
+		wantKeptTimes              []bool
+	}{
+		{
+			name:                       "filtered clusters first, sorted by names",
+			maxClusterExplanationCount: 100,
+			want:                       []placementv1beta1.ClusterExplanation{filteredExplanation(anotherClusterName), filteredExplanation(altClusterName), notPickedExplanation},
+			wantKeptTimes:              []bool{false, false, false},
+		},
+		{
+			name:                       "bounded by the max count",
+			maxClusterExplanationCount: 2,
+			want:                       []placementv1beta1.ClusterExplanation{filteredExplanation(anotherClusterName), filteredExplanation(altClusterName)},
+			wantKeptTimes:              []bool{false, false},
+		},
+		{
+			name:                       "no explanations",
+			maxClusterExplanationCount: 0,
+		},
+		{
+			name:                       "keep the last transition times of the unchanged explanations",
+			maxClusterExplanationCount: 100,
+			current: []placementv1beta1.ClusterExplanation{
+				withTime(filteredExplanation(anotherClusterName)),
+				withTime(placementv1beta1.ClusterExplanation{ClusterName: altClusterName, Reason: "score too low"}),
+			},
+			want:          []placementv1beta1.ClusterExplanation{filteredExplanation(anotherClusterName), filteredExplanation(altClusterName), notPickedExplanation},
+			wantKeptTimes: []bool{true, false, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			explanations := newClusterExplanations(tc.maxClusterExplanationCount, tc.current, notPicked, filtered)
+			if diff := cmp.Diff(tc.want, explanations, cmpopts.IgnoreFields(placementv1beta1.ClusterExplanation{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("newClusterExplanations() explanations diff (-want, +got): %s", diff)
+			}
+			for i := range explanations {
+				if kept := explanations[i].LastTransitionTime.Equal(&lastTransitionTime); kept != tc.wantKeptTimes[i] {
+					t.Errorf("newClusterExplanations() explanation for cluster %s kept the last transition time: %t, want %t", explanations[i].ClusterName, kept, tc.wantKeptTimes[i])
+				}
+			}
+			if !equalExplanations(tc.want, explanations) {
+				t.Errorf("equalExplanations() = false, want true")
+			}
+		})
+	}
+}
+
 // TestNewSchedulingDecisionsFrom tests a special case in the newSchedulingDecisionsFrom function,
 // specifically the case where the number of new decisions exceeds the API limit.
 func TestNewSchedulingDecisionsFromOversized(t *testing.T) {
@@ -5059,6 +5289,7 @@ func TestRunScorePlugins(t *testing.T) {
 	testCases := []struct {
 		name               string
 		scorePlugins       []ScorePlugin
+		scoreWeights       *placementv1beta1.ScoreWeights
 		clusters           []*clusterv1beta1.MemberCluster
 		wantScoredClusters ScoredClusters
 		expectedToFail     bool
@@ -5132,6 +5363,81 @@ func TestRunScorePlugins(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "three clusters, two score plugins, all scored with score weights",
+			scorePlugins: []ScorePlugin{
+				&DummyAllPurposePlugin{
+					name: dummyScorePluginNameA,
+					scoreRunner: func(ctx context.Context, state CycleStatePluginReadWriter, policy *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) (score *ClusterScore, status *Status) {
+						switch cluster.Name {
+						case clusterName:
+							return &ClusterScore{
+								TopologySpreadScore: 1,
+							}, nil
+						case altClusterName:
+							return &ClusterScore{
+								TopologySpreadScore: 0,
+							}, nil
+						case anotherClusterName:
+							return &ClusterScore{
+								TopologySpreadScore: 2,
+							}, nil
+						}
+						return &ClusterScore{}, nil
+					},
+				},
+				&DummyAllPurposePlugin{
+					name: dummyScorePluginNameB,
+					scoreRunner: func(ctx context.Context, state CycleStatePluginReadWriter, policy *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) (score *ClusterScore, status *Status) {
+						switch cluster.Name {
+						case clusterName:
+							return &ClusterScore{
+								AffinityScore: 10,
+							}, nil
+						case altClusterName:
+							return &ClusterScore{
+								AffinityScore: 20,
+							}, nil
+						case anotherClusterName:
+							return &ClusterScore{
+								AffinityScore: 15,
+							}, nil
+						}
+						return &ClusterScore{}, nil
+					},
+				},
+			},
+			scoreWeights: &placementv1beta1.ScoreWeights{
+				TopologySpread: ptr.To(int32(5)),
+			},
+			clusters: clusters,
+			wantScoredClusters: ScoredClusters{
+				{
+					Cluster: clusters[0],
+					Score: &ClusterScore{
+						TopologySpreadScore: 5,
+						AffinityScore:       10,
+						WeightedScore:       15,
+					},
+				},
+				{
+					Cluster: clusters[1],
+					Score: &ClusterScore{
+						TopologySpreadScore: 0,
+						AffinityScore:       20,
+						WeightedScore:       20,
+					},
+				},
+				{
+					Cluster: clusters[2],
+					Score: &ClusterScore{
+						TopologySpreadScore: 10,
+						AffinityScore:       15,
+						WeightedScore:       25,
+					},
+				},
+			},
+		},
 		{
 			name: "three clusters, two score plugins, one internal error on specific cluster",
 			scorePlugins: []ScorePlugin{
@@ -5224,6 +5530,12 @@ func TestRunScorePlugins(t *testing.T) {
 					Name: policyName,
 				},
 			}
+			if tc.scoreWeights != nil {
+				policy.Spec.Policy = &placementv1beta1.PlacementPolicy{
+					PlacementType: placementv1beta1.PickNPlacementType,
+					ScoreWeights:  tc.scoreWeights,
+				}
+			}
 
 			scoredClusters, err := f.runScorePlugins(ctx, state, policy, tc.clusters)
 			if tc.expectedToFail {
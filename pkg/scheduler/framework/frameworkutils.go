@@ -163,6 +163,7 @@ func crossReferencePickedClustersAndDeDupBindings(
 			desiredState = placementv1beta1.BindingState(previousState)
 			// remove the annotation just to avoid confusion.
 			delete(currentAnnotation, placementv1beta1.PreviousBindingStateAnnotation)
+			delete(currentAnnotation, placementv1beta1.UnscheduledGenerationAnnotation)
 			binding.SetAnnotations(currentAnnotation)
 		} else {
 			return nil, nil, nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("failed to find the previous state of an unscheduled binding: %+v", binding))
@@ -345,6 +346,96 @@ func newSchedulingDecisionsFromBindings(
 	return newDecisions
 }
 
+// newClusterExplanations returns a list of explanations for the clusters which are not picked in a scheduling cycle,
+// i.e., the clusters filtered out (sorted by their names), and then the clusters which have been scored but are not
+// picked (sorted by their scores).
+//
+// The last transition time of an explanation is kept as it is if the explanation for the cluster does not change.
+func newClusterExplanations(
+	maxClusterExplanationCount int,
+	current []placementv1beta1.ClusterExplanation,
+	notPicked ScoredClusters,
+	filtered []*filteredClusterWithStatus,
+) []placementv1beta1.ClusterExplanation {
+	if maxClusterExplanationCount > clustersDecisionArrayLengthLimitInAPI {
+		maxClusterExplanationCount = clustersDecisionArrayLengthLimitInAPI
+	}
+	if maxClusterExplanationCount < 0 {
+		maxClusterExplanationCount = 0
+	}
+
+	currentByCluster := make(map[string]placementv1beta1.ClusterExplanation, len(current))
+	for _, explanation := range current {
+		currentByCluster[explanation.ClusterName] = explanation
+	}
+	now := metav1.Now()
+	newExplanations := make([]placementv1beta1.ClusterExplanation, 0, maxClusterExplanationCount)
+	add := func(explanation placementv1beta1.ClusterExplanation) {
+		explanation.LastTransitionTime = now
+		if old, ok := currentByCluster[explanation.ClusterName]; ok && equalExplanation(old, explanation) {
+			explanation.LastTransitionTime = old.LastTransitionTime
+		}
+		newExplanations = append(newExplanations, explanation)
+	}
+
+	sortedFiltered := make([]*filteredClusterWithStatus, len(filtered))
+	copy(sortedFiltered, filtered)
+	sort.Slice(sortedFiltered, func(i, j int) bool {
+		return sortedFiltered[i].cluster.Name < sortedFiltered[j].cluster.Name
+	})
+	for i := 0; i < len(sortedFiltered) && len(newExplanations) < maxClusterExplanationCount; i++ {
+		clusterWithStatus := sortedFiltered[i]
+		add(placementv1beta1.ClusterExplanation{
+			ClusterName: clusterWithStatus.cluster.Name,
+			Plugin:      clusterWithStatus.status.SourcePlugin(),
+			Reason:      clusterWithStatus.status.String(),
+		})
+	}
+	for i := 0; i < len(notPicked) && len(newExplanations) < maxClusterExplanationCount; i++ {
+		sc := notPicked[i]
+		add(placementv1beta1.ClusterExplanation{
+			ClusterName: sc.Cluster.Name,
+			ClusterScore: &placementv1beta1.ClusterScore{
+				AffinityScore:       ptr.To(int32(sc.Score.AffinityScore)),
+				TopologySpreadScore: ptr.To(int32(sc.Score.TopologySpreadScore)),
+			},
+			Reason: fmt.Sprintf(notPickedByScoreReasonTemplate, sc.Cluster.Name, sc.Score.AffinityScore, sc.Score.TopologySpreadScore),
+		})
+	}
+	if len(newExplanations) == 0 {
+		return nil
+	}
+	return newExplanations
+}
+
+// equalExplanations returns if two lists of cluster explanations are equal, regardless of their last transition times.
+func equalExplanations(current, desired []placementv1beta1.ClusterExplanation) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range current {
+		if !equalExplanation(current[i], desired[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalExplanation returns if two cluster explanations are equal, regardless of their last transition times.
+func equalExplanation(a, b placementv1beta1.ClusterExplanation) bool {
+	if a.ClusterName != b.ClusterName || a.Plugin != b.Plugin || a.Reason != b.Reason {
+		return false
+	}
+	if (a.ClusterScore == nil) != (b.ClusterScore == nil) {
+		return false
+	}
+	if a.ClusterScore == nil {
+		return true
+	}
+	return ptr.Deref(a.ClusterScore.AffinityScore, 0) == ptr.Deref(b.ClusterScore.AffinityScore, 0) &&
+		ptr.Deref(a.ClusterScore.TopologySpreadScore, 0) == ptr.Deref(b.ClusterScore.TopologySpreadScore, 0)
+}
+
 // newSchedulingCondition returns a new scheduling condition.
 func newScheduledCondition(policy *placementv1beta1.ClusterSchedulingPolicySnapshot, status metav1.ConditionStatus, reason, message string) metav1.Condition {
 	return metav1.Condition{
@@ -662,6 +753,7 @@ func crossReferenceValidTargetsWithBindings(
 				desiredState = placementv1beta1.BindingState(previousState)
 				// remove the annotation just to avoid confusion.
 				delete(currentAnnotation, placementv1beta1.PreviousBindingStateAnnotation)
+				delete(currentAnnotation, placementv1beta1.UnscheduledGenerationAnnotation)
 				unscheduledBinding.SetAnnotations(currentAnnotation)
 			} else {
 				return nil, nil, nil, controller.NewUnexpectedBehaviorError(fmt.Errorf("failed to find the previous state of an unscheduled binding: %+v", unscheduledBinding))
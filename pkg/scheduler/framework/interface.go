@@ -92,3 +92,15 @@ type ScorePlugin interface {
 	// * An InternalError status, if an expected error has occurred
 	Score(ctx context.Context, state CycleStatePluginReadWriter, policy *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) (score *ClusterScore, status *Status)
 }
+
+// CacheableScorePlugin is the interface which score plugins may implement to have the scheduler framework cache
+// their scores across scheduling cycles.
+type CacheableScorePlugin interface {
+	ScorePlugin
+
+	// ScoreCacheable returns true if the score the plugin gives to a cluster for the policy depends only on the
+	// policy and the labels and the properties of the cluster, but not on the other clusters or the bindings; the
+	// framework then reuses the score until the policy or the cluster changes. Note that the PreScore extension
+	// point of the plugin still runs in every scheduling cycle.
+	ScoreCacheable(policy *placementv1beta1.ClusterSchedulingPolicySnapshot) bool
+}
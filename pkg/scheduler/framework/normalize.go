@@ -0,0 +1,38 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// MaxNormalizedScore is the upper bound of the scores normalized from the properties of the clusters.
+	MaxNormalizedScore = 100
+)
+
+// NormalizeRatio normalizes the ratio of a property value to a total, e.g., the available CPU of a cluster to its
+// allocatable CPU, into a score in the range of [0, MaxNormalizedScore], so that the properties of different units
+// and magnitudes can be compared and combined.
+//
+// It returns false if the ratio cannot be computed, i.e., the total is not positive; the ratios out of the range
+// of [0, 1], e.g., when the value is reported after the total changes, are clamped.
+func NormalizeRatio(value, total resource.Quantity) (int, bool) {
+	totalF := total.AsApproximateFloat64()
+	if totalF <= 0 || math.IsInf(totalF, 0) {
+		return 0, false
+	}
+	ratio := value.AsApproximateFloat64() / totalF
+	switch {
+	case math.IsNaN(ratio) || ratio < 0:
+		ratio = 0
+	case ratio > 1:
+		ratio = 1
+	}
+	return int(math.Round(ratio * MaxNormalizedScore)), true
+}
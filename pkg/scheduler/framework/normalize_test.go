@@ -0,0 +1,66 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TestNormalizeRatio tests the NormalizeRatio function.
+func TestNormalizeRatio(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		total     string
+		wantScore int
+		wantOK    bool
+	}{
+		{
+			name:      "ratio of cpu quantities",
+			value:     "1500m",
+			total:     "4",
+			wantScore: 38,
+			wantOK:    true,
+		},
+		{
+			name:      "ratio of memory quantities of different units",
+			value:     "1Gi",
+			total:     "4096Mi",
+			wantScore: 25,
+			wantOK:    true,
+		},
+		{
+			name:      "value larger than the total",
+			value:     "8",
+			total:     "4",
+			wantScore: MaxNormalizedScore,
+			wantOK:    true,
+		},
+		{
+			name:      "negative value",
+			value:     "-1",
+			total:     "4",
+			wantScore: 0,
+			wantOK:    true,
+		},
+		{
+			name:  "zero total",
+			value: "1",
+			total: "0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score, ok := NormalizeRatio(resource.MustParse(tc.value), resource.MustParse(tc.total))
+			if score != tc.wantScore || ok != tc.wantOK {
+				t.Errorf("NormalizeRatio(%s, %s) = (%d, %t), want (%d, %t)", tc.value, tc.total, score, ok, tc.wantScore, tc.wantOK)
+			}
+		})
+	}
+}
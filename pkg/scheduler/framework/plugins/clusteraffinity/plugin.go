@@ -31,12 +31,16 @@ var (
 	// * PreScore
 	// * Score
 	//
+	// Its scores are also cached by the framework when no property sorter is in use.
+	//
 	// Note that successful connection to any of the extension points implies that the
 	// plugin already implements the Plugin interface.
 	_ framework.PreFilterPlugin = &Plugin{}
 	_ framework.FilterPlugin    = &Plugin{}
 	_ framework.PreScorePlugin  = &Plugin{}
 	_ framework.ScorePlugin     = &Plugin{}
+
+	_ framework.CacheableScorePlugin = &Plugin{}
 )
 
 type clusterAffinityPluginOptions struct {
@@ -78,3 +78,18 @@ func (p *Plugin) Score(
 	// All done.
 	return score, nil
 }
+
+// ScoreCacheable allows the framework to cache the scores of the plugin when no preferred cluster affinity term
+// sorts the clusters by a property, as such a score is interpolated between the min. and max. values of the property
+// observed across all the clusters; the other scores depend only on the labels and the properties of the cluster.
+func (p *Plugin) ScoreCacheable(policy *placementv1beta1.ClusterSchedulingPolicySnapshot) bool {
+	if policy.Spec.Policy == nil || policy.Spec.Policy.Affinity == nil || policy.Spec.Policy.Affinity.ClusterAffinity == nil {
+		return true
+	}
+	for _, t := range policy.Spec.Policy.Affinity.ClusterAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if t.Preference.PropertySorter != nil {
+			return false
+		}
+	}
+	return true
+}
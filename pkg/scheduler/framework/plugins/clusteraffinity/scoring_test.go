@@ -644,3 +644,65 @@ func TestPluginScore(t *testing.T) {
 		})
 	}
 }
+
+// TestScoreCacheable tests the ScoreCacheable method of this plugin.
+func TestScoreCacheable(t *testing.T) {
+	policyWith := func(terms ...placementv1beta1.PreferredClusterSelector) *placementv1beta1.ClusterSchedulingPolicySnapshot {
+		return &placementv1beta1.ClusterSchedulingPolicySnapshot{
+			Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+				Policy: &placementv1beta1.PlacementPolicy{
+					Affinity: &placementv1beta1.Affinity{
+						ClusterAffinity: &placementv1beta1.ClusterAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: terms,
+						},
+					},
+				},
+			},
+		}
+	}
+	labelTerm := placementv1beta1.PreferredClusterSelector{
+		Weight: 10,
+		Preference: placementv1beta1.ClusterSelectorTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{regionLabelName: regionLabelValue1}},
+		},
+	}
+	sorterTerm := placementv1beta1.PreferredClusterSelector{
+		Weight: 100,
+		Preference: placementv1beta1.ClusterSelectorTerm{
+			PropertySorter: &placementv1beta1.PropertySorter{
+				Name:      propertyprovider.NodeCountProperty,
+				SortOrder: placementv1beta1.Descending,
+			},
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		policy *placementv1beta1.ClusterSchedulingPolicySnapshot
+		want   bool
+	}{
+		{
+			name:   "no scheduling policy",
+			policy: &placementv1beta1.ClusterSchedulingPolicySnapshot{},
+			want:   true,
+		},
+		{
+			name:   "label selector terms only",
+			policy: policyWith(labelTerm),
+			want:   true,
+		},
+		{
+			name:   "property sorter term",
+			policy: policyWith(labelTerm, sorterTerm),
+			want:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := p.ScoreCacheable(tc.policy); got != tc.want {
+				t.Errorf("ScoreCacheable() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/propertyprovider"
+	"go.goms.io/fleet/pkg/utils/clustergroup"
 )
 
 // clusterRequirement is a type alias for ClusterSelectorTerm in the API, which allows
@@ -108,6 +109,11 @@ func retrievePropertyValueFrom(cluster *clusterv1beta1.MemberCluster, name strin
 //
 // This is an extended method for the ClusterSelectorTerm API.
 func (c *clusterRequirement) Matches(cluster *clusterv1beta1.MemberCluster) (bool, error) {
+	// Match the cluster against the cluster group.
+	if c.ClusterGroup != "" && !clustergroup.Contains(cluster.Labels, c.ClusterGroup) {
+		return false, nil
+	}
+
 	// Match the cluster against the label selector.
 	if c.LabelSelector != nil {
 		ls, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
@@ -264,8 +270,8 @@ func interpolateWeightFor(cluster *clusterv1beta1.MemberCluster, property string
 //
 // This is an extended method for the PreferredClusterSelector API.
 func (c *clusterPreference) Scores(state *pluginState, cluster *clusterv1beta1.MemberCluster) (int32, error) {
-	matched := true
-	if c.Preference.LabelSelector != nil {
+	matched := c.Preference.ClusterGroup == "" || clustergroup.Contains(cluster.Labels, c.Preference.ClusterGroup)
+	if matched && c.Preference.LabelSelector != nil {
 		ls, err := metav1.LabelSelectorAsSelector(c.Preference.LabelSelector)
 		if err != nil {
 			return 0, fmt.Errorf("failed to parse label selector: %w", err)
@@ -0,0 +1,103 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package networkproximity features a scheduler plugin that prefers the clusters in the same region or zone as an
+// anchor, i.e. a member cluster or the hub cluster, so that the latency-sensitive workloads stay close to their data
+// sources.
+package networkproximity
+
+import (
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+const (
+	// defaultPluginName is the default name of the plugin.
+	defaultPluginName = "NetworkProximity"
+)
+
+// Plugin is the scheduler plugin that scores the clusters by their network proximity to an anchor.
+type Plugin struct {
+	// The name of the plugin.
+	name string
+	// The region and zone of the hub cluster.
+	hubRegion string
+	hubZone   string
+
+	// The framework handle.
+	handle framework.Handle
+}
+
+var (
+	// Verify that Plugin can connect to relevant extension points
+	// at compile time.
+	//
+	// This plugin leverages the following the extension points:
+	// * PreScore
+	// * Score
+	//
+	// Note that successful connection to any of the extension points implies that the
+	// plugin already implements the Plugin interface.
+	_ framework.PreScorePlugin = &Plugin{}
+	_ framework.ScorePlugin    = &Plugin{}
+)
+
+// pluginOptions is the options for this plugin.
+type pluginOptions struct {
+	// The name of the plugin.
+	name string
+	// The region and zone of the hub cluster.
+	hubRegion string
+	hubZone   string
+}
+
+// Option helps set up the plugin.
+type Option func(*pluginOptions)
+
+// defaultPluginOptions is the default options for this plugin.
+var defaultPluginOptions = pluginOptions{
+	name: defaultPluginName,
+}
+
+// WithName sets the name of the plugin.
+func WithName(name string) Option {
+	return func(o *pluginOptions) {
+		o.name = name
+	}
+}
+
+// WithHubTopology sets the region and zone of the hub cluster, which is the anchor of the placements that do not
+// name an anchor cluster.
+func WithHubTopology(region, zone string) Option {
+	return func(o *pluginOptions) {
+		o.hubRegion = region
+		o.hubZone = zone
+	}
+}
+
+// New returns a new Plugin.
+func New(opts ...Option) Plugin {
+	options := defaultPluginOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return Plugin{
+		name:      options.name,
+		hubRegion: options.hubRegion,
+		hubZone:   options.hubZone,
+	}
+}
+
+// Name returns the name of the plugin.
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// SetUpWithFramework sets up this plugin with a scheduler framework.
+func (p *Plugin) SetUpWithFramework(handle framework.Handle) {
+	p.handle = handle
+
+	// This plugin does not need to set up any informer.
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package networkproximity
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+// anchorTopology is the region and zone of the anchor of a placement; it is saved as the plugin state.
+type anchorTopology struct {
+	region string
+	zone   string
+}
+
+// PreScore allows the plugin to connect to the PreScore extension point in the scheduling
+// framework.
+func (p *Plugin) PreScore(
+	_ context.Context,
+	state framework.CycleStatePluginReadWriter,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+) (status *framework.Status) {
+	if policy.Spec.Policy == nil || policy.Spec.Policy.NetworkProximity == nil || policy.Spec.Policy.NetworkProximity.Weight == 0 {
+		// There is no network proximity preference specified in the scheduling policy; skip the step.
+		//
+		// Note that this will also skip the Score() extension point for the plugin.
+		return framework.NewNonErrorStatus(framework.Skip, p.Name(), "no network proximity specified")
+	}
+
+	anchor, reason := p.anchorTopologyOf(state, policy.Spec.Policy.NetworkProximity.AnchorCluster)
+	if anchor == nil {
+		// The clusters cannot be compared with the anchor; none of them is preferred.
+		return framework.NewNonErrorStatus(framework.Skip, p.Name(), reason)
+	}
+
+	// Save the plugin state.
+	state.Write(framework.StateKey(p.Name()), anchor)
+
+	// All done.
+	return nil
+}
+
+// anchorTopologyOf returns the region and zone of the anchor, i.e. the named member cluster or the hub cluster if
+// the name is empty, or the reason why the anchor has no region.
+func (p *Plugin) anchorTopologyOf(state framework.CycleStatePluginReadWriter, anchorCluster string) (*anchorTopology, string) {
+	if anchorCluster == "" {
+		if p.hubRegion == "" {
+			return nil, "the region of the hub cluster is not configured"
+		}
+		return &anchorTopology{region: p.hubRegion, zone: p.hubZone}, ""
+	}
+
+	clusters := state.ListClusters()
+	for idx := range clusters {
+		if clusters[idx].Name != anchorCluster {
+			continue
+		}
+		labels := clusters[idx].Labels
+		if labels[corev1.LabelTopologyRegion] == "" {
+			return nil, fmt.Sprintf("anchor cluster %s has no region label", anchorCluster)
+		}
+		return &anchorTopology{region: labels[corev1.LabelTopologyRegion], zone: labels[corev1.LabelTopologyZone]}, ""
+	}
+	return nil, fmt.Sprintf("anchor cluster %s is not found", anchorCluster)
+}
+
+// Score allows the plugin to connect to the Score extension point in the scheduling framework.
+func (p *Plugin) Score(
+	_ context.Context,
+	state framework.CycleStatePluginReadWriter,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+	cluster *clusterv1beta1.MemberCluster,
+) (score *framework.ClusterScore, status *framework.Status) {
+	// Read the plugin state.
+	val, err := state.Read(framework.StateKey(p.Name()))
+	if err != nil {
+		// This branch should never be reached, as a state has been set
+		// in the PreScore stage.
+		return nil, framework.FromError(err, p.Name(), "failed to read plugin state")
+	}
+	anchor, ok := val.(*anchorTopology)
+	if !ok {
+		return nil, framework.FromError(fmt.Errorf("failed to cast value %v to the right type", val), p.Name())
+	}
+
+	weight := int(policy.Spec.Policy.NetworkProximity.Weight)
+	region, zone := cluster.Labels[corev1.LabelTopologyRegion], cluster.Labels[corev1.LabelTopologyZone]
+	switch {
+	case region != anchor.region:
+		return &framework.ClusterScore{}, nil
+	case anchor.zone != "" && zone == anchor.zone:
+		return &framework.ClusterScore{AffinityScore: weight}, nil
+	default:
+		return &framework.ClusterScore{AffinityScore: weight / 2}, nil
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package networkproximity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+const (
+	anchorClusterName = "bravelion"
+)
+
+var (
+	ignoreStatusErrorField = cmpopts.IgnoreFields(framework.Status{}, "err")
+)
+
+func newCluster(name, region, zone string) clusterv1beta1.MemberCluster {
+	labels := map[string]string{}
+	if region != "" {
+		labels[corev1.LabelTopologyRegion] = region
+	}
+	if zone != "" {
+		labels[corev1.LabelTopologyZone] = zone
+	}
+	return clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newPolicy(proximity *placementv1beta1.NetworkProximity) *placementv1beta1.ClusterSchedulingPolicySnapshot {
+	return &placementv1beta1.ClusterSchedulingPolicySnapshot{
+		Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+			Policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickNPlacementType,
+				NetworkProximity: proximity,
+			},
+		},
+	}
+}
+
+// TestPreScore tests the PreScore extension point of the plugin.
+func TestPreScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		hubRegion  string
+		hubZone    string
+		clusters   []clusterv1beta1.MemberCluster
+		policy     *placementv1beta1.ClusterSchedulingPolicySnapshot
+		wantStatus *framework.Status
+		wantState  *anchorTopology
+	}{
+		{
+			name:       "no network proximity",
+			hubRegion:  "eastus",
+			policy:     newPolicy(nil),
+			wantStatus: framework.NewNonErrorStatus(framework.Skip, defaultPluginName, "no network proximity specified"),
+		},
+		{
+			name:       "zero weight",
+			hubRegion:  "eastus",
+			policy:     newPolicy(&placementv1beta1.NetworkProximity{}),
+			wantStatus: framework.NewNonErrorStatus(framework.Skip, defaultPluginName, "no network proximity specified"),
+		},
+		{
+			name:      "hub anchor",
+			hubRegion: "eastus",
+			hubZone:   "eastus-1",
+			policy:    newPolicy(&placementv1beta1.NetworkProximity{Weight: 10}),
+			wantState: &anchorTopology{region: "eastus", zone: "eastus-1"},
+		},
+		{
+			name:       "hub anchor without a region",
+			policy:     newPolicy(&placementv1beta1.NetworkProximity{Weight: 10}),
+			wantStatus: framework.NewNonErrorStatus(framework.Skip, defaultPluginName, "the region of the hub cluster is not configured"),
+		},
+		{
+			name:      "cluster anchor",
+			hubRegion: "eastus",
+			clusters: []clusterv1beta1.MemberCluster{
+				newCluster("smartcat", "eastus", "eastus-1"),
+				newCluster(anchorClusterName, "westus", "westus-2"),
+			},
+			policy:    newPolicy(&placementv1beta1.NetworkProximity{AnchorCluster: anchorClusterName, Weight: 10}),
+			wantState: &anchorTopology{region: "westus", zone: "westus-2"},
+		},
+		{
+			name:       "cluster anchor is not found",
+			clusters:   []clusterv1beta1.MemberCluster{newCluster("smartcat", "eastus", "eastus-1")},
+			policy:     newPolicy(&placementv1beta1.NetworkProximity{AnchorCluster: anchorClusterName, Weight: 10}),
+			wantStatus: framework.NewNonErrorStatus(framework.Skip, defaultPluginName, "anchor cluster bravelion is not found"),
+		},
+		{
+			name:       "cluster anchor without a region",
+			clusters:   []clusterv1beta1.MemberCluster{newCluster(anchorClusterName, "", "")},
+			policy:     newPolicy(&placementv1beta1.NetworkProximity{AnchorCluster: anchorClusterName, Weight: 10}),
+			wantStatus: framework.NewNonErrorStatus(framework.Skip, defaultPluginName, "anchor cluster bravelion has no region label"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New(WithHubTopology(tc.hubRegion, tc.hubZone))
+			state := framework.NewCycleState(tc.clusters, nil)
+			status := p.PreScore(context.Background(), state, tc.policy)
+			if diff := cmp.Diff(tc.wantStatus, status, cmp.AllowUnexported(framework.Status{}), ignoreStatusErrorField); diff != "" {
+				t.Fatalf("PreScore() status diff (-want, +got):\n%s", diff)
+			}
+			if tc.wantState == nil {
+				return
+			}
+			val, err := state.Read(framework.StateKey(p.Name()))
+			if err != nil {
+				t.Fatalf("Read(state) = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantState, val, cmp.AllowUnexported(anchorTopology{})); diff != "" {
+				t.Errorf("PreScore() state diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestScore tests the Score extension point of the plugin.
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		anchor  *anchorTopology
+		cluster clusterv1beta1.MemberCluster
+		want    *framework.ClusterScore
+	}{
+		{
+			name:    "same zone",
+			anchor:  &anchorTopology{region: "eastus", zone: "eastus-1"},
+			cluster: newCluster("smartcat", "eastus", "eastus-1"),
+			want:    &framework.ClusterScore{AffinityScore: 10},
+		},
+		{
+			name:    "same region, another zone",
+			anchor:  &anchorTopology{region: "eastus", zone: "eastus-1"},
+			cluster: newCluster("smartcat", "eastus", "eastus-2"),
+			want:    &framework.ClusterScore{AffinityScore: 5},
+		},
+		{
+			name:    "same region, anchor without a zone",
+			anchor:  &anchorTopology{region: "eastus"},
+			cluster: newCluster("smartcat", "eastus", ""),
+			want:    &framework.ClusterScore{AffinityScore: 5},
+		},
+		{
+			name:    "another region",
+			anchor:  &anchorTopology{region: "eastus", zone: "eastus-1"},
+			cluster: newCluster("smartcat", "westus", "eastus-1"),
+			want:    &framework.ClusterScore{},
+		},
+		{
+			name:    "cluster without labels",
+			anchor:  &anchorTopology{region: "eastus", zone: "eastus-1"},
+			cluster: newCluster("smartcat", "", ""),
+			want:    &framework.ClusterScore{},
+		},
+	}
+
+	p := New()
+	policy := newPolicy(&placementv1beta1.NetworkProximity{Weight: 10})
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			state := framework.NewCycleState(nil, nil)
+			state.Write(framework.StateKey(p.Name()), tc.anchor)
+			got, status := p.Score(context.Background(), state, policy, &tc.cluster)
+			if !status.IsSuccess() {
+				t.Fatalf("Score() = %v, want success", status)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Score() diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
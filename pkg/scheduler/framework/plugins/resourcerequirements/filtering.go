@@ -0,0 +1,72 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resourcerequirements
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+// PreFilter allows the plugin to connect to the PreFilter extension point in the scheduling framework.
+func (p *Plugin) PreFilter(
+	_ context.Context,
+	_ framework.CycleStatePluginReadWriter,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+) (status *framework.Status) {
+	if len(policy.ResourceRequirements()) == 0 {
+		// There are no resource requirements to check; the Filter stage is skipped for all clusters.
+		return framework.NewNonErrorStatus(framework.Skip, p.Name(), "no resource requirements are declared")
+	}
+	return nil
+}
+
+// Filter allows the plugin to connect to the Filter extension point in the scheduling framework.
+func (p *Plugin) Filter(
+	_ context.Context,
+	state framework.CycleStatePluginReadWriter,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+	cluster *clusterv1beta1.MemberCluster,
+) (status *framework.Status) {
+	if state.HasScheduledOrBoundBindingFor(cluster.Name) {
+		// The resources of the placement are already (being) placed on the cluster, and are part of its usage; the
+		// cluster is kept so that a busy cluster does not evict the placement.
+		return nil
+	}
+	if reason, ok := fitsAvailableResources(policy.ResourceRequirements(), &cluster.Status.ResourceUsage); !ok {
+		return framework.NewNonErrorStatus(framework.ClusterUnschedulable, p.Name(), reason)
+	}
+	return nil
+}
+
+// fitsAvailableResources checks if the resource usage of a cluster leaves enough room for the requirements, and
+// returns the reason if not.
+func fitsAvailableResources(requirements corev1.ResourceList, usage *clusterv1beta1.ResourceUsage) (string, bool) {
+	available := usage.Available
+	if len(available) == 0 {
+		// The available resources are only reported when a property provider is enabled; fall back to the
+		// allocatable resources, which is the best that the hub knows in this case.
+		available = usage.Allocatable
+	}
+	// check the resources in order so that the reason is stable
+	for _, name := range sets.List(sets.KeySet(requirements)) {
+		required := requirements[name]
+		quantity, found := available[name]
+		if !found {
+			return fmt.Sprintf("the cluster does not report the available %s", name), false
+		}
+		if quantity.Cmp(required) < 0 {
+			return fmt.Sprintf("the cluster has %s %s available, less than the required %s", quantity.String(), name, required.String()), false
+		}
+	}
+	return "", true
+}
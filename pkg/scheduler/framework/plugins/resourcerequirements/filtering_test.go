@@ -0,0 +1,141 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resourcerequirements
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+const (
+	clusterName = "cluster-1"
+)
+
+func TestPreFilter(t *testing.T) {
+	tests := map[string]struct {
+		policy   *placementv1beta1.PlacementPolicy
+		wantSkip bool
+	}{
+		"no policy": {
+			wantSkip: true,
+		},
+		"no resource requirements": {
+			policy:   &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType},
+			wantSkip: true,
+		},
+		"resource requirements": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:        placementv1beta1.PickAllPlacementType,
+				ResourceRequirements: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := New()
+			policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{Policy: tc.policy},
+			}
+			status := p.PreFilter(context.Background(), framework.NewCycleState(nil, nil), policy)
+			if status.IsSkip() != tc.wantSkip {
+				t.Errorf("PreFilter() = %v, want skip %t", status, tc.wantSkip)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	requirements := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	}
+	tests := map[string]struct {
+		usage clusterv1beta1.ResourceUsage
+		bound bool
+		want  *framework.Status
+	}{
+		"enough available resources": {
+			usage: clusterv1beta1.ResourceUsage{
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+		},
+		"not enough available cpu": {
+			usage: clusterv1beta1.ResourceUsage{
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1500m"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			want: framework.NewNonErrorStatus(framework.ClusterUnschedulable, defaultPluginName, "the cluster has 1500m cpu available, less than the required 2"),
+		},
+		"available memory not reported": {
+			usage: clusterv1beta1.ResourceUsage{
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+			want: framework.NewNonErrorStatus(framework.ClusterUnschedulable, defaultPluginName, "the cluster does not report the available memory"),
+		},
+		"falls back to the allocatable resources": {
+			usage: clusterv1beta1.ResourceUsage{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+			want: framework.NewNonErrorStatus(framework.ClusterUnschedulable, defaultPluginName, "the cluster has 2Gi memory available, less than the required 4Gi"),
+		},
+		"the placement is bound to the cluster already": {
+			usage: clusterv1beta1.ResourceUsage{
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+			bound: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := New()
+			cluster := &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+				Status:     clusterv1beta1.MemberClusterStatus{ResourceUsage: tc.usage},
+			}
+			var bound []*placementv1beta1.ClusterResourceBinding
+			if tc.bound {
+				bound = append(bound, &placementv1beta1.ClusterResourceBinding{
+					Spec: placementv1beta1.ResourceBindingSpec{State: placementv1beta1.BindingStateBound, TargetCluster: clusterName},
+				})
+			}
+			state := framework.NewCycleState([]clusterv1beta1.MemberCluster{*cluster}, nil, bound)
+			policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{
+						PlacementType:        placementv1beta1.PickAllPlacementType,
+						ResourceRequirements: requirements,
+					},
+				},
+			}
+			got := p.Filter(context.Background(), state, policy, cluster)
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(framework.Status{})); diff != "" {
+				t.Errorf("Filter() status mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
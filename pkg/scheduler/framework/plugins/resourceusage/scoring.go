@@ -0,0 +1,59 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resourceusage
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+// scoredResources are the resources whose availability the plugin scores the clusters by.
+var scoredResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// Score allows the plugin to connect to the Score extension point in the scheduling framework.
+func (p *Plugin) Score(
+	_ context.Context,
+	_ framework.CycleStatePluginReadWriter,
+	_ *placementv1beta1.ClusterSchedulingPolicySnapshot,
+	cluster *clusterv1beta1.MemberCluster,
+) (score *framework.ClusterScore, status *framework.Status) {
+	return &framework.ClusterScore{ResourceUsageScore: availabilityScore(&cluster.Status.ResourceUsage)}, nil
+}
+
+// ScoreCacheable allows the framework to cache the scores of the plugin, as a score depends only on the resource
+// usage of the cluster.
+func (p *Plugin) ScoreCacheable(_ *placementv1beta1.ClusterSchedulingPolicySnapshot) bool {
+	return true
+}
+
+// availabilityScore returns the average of the normalized ratios of the available to the allocatable quantities of
+// the scored resources of a cluster.
+//
+// A cluster which does not report the available quantity of any scored resource, e.g., when no property provider
+// is enabled in the member agent, scores 0, i.e., it is treated as a fully loaded cluster.
+func availabilityScore(usage *clusterv1beta1.ResourceUsage) int {
+	total, count := 0, 0
+	for _, name := range scoredResources {
+		available, hasAvailable := usage.Available[name]
+		allocatable, hasAllocatable := usage.Allocatable[name]
+		if !hasAvailable || !hasAllocatable {
+			continue
+		}
+		if s, ok := framework.NormalizeRatio(available, allocatable); ok {
+			total += s
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / count
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package resourceusage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage clusterv1beta1.ResourceUsage
+		want  *framework.ClusterScore
+	}{
+		{
+			name: "both cpu and memory are reported",
+			usage: clusterv1beta1.ResourceUsage{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("6"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			want: &framework.ClusterScore{ResourceUsageScore: 50},
+		},
+		{
+			name: "only cpu is reported",
+			usage: clusterv1beta1.ResourceUsage{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+			want: &framework.ClusterScore{ResourceUsageScore: 25},
+		},
+		{
+			name: "the allocatable cpu is zero",
+			usage: clusterv1beta1.ResourceUsage{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("0"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+				Available: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("0"),
+					corev1.ResourceMemory: resource.MustParse("24Gi"),
+				},
+			},
+			want: &framework.ClusterScore{ResourceUsageScore: 75},
+		},
+		{
+			name: "the available resources are not reported",
+			usage: clusterv1beta1.ResourceUsage{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+				},
+			},
+			want: &framework.ClusterScore{ResourceUsageScore: 0},
+		},
+	}
+
+	p := New()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "member-1"},
+				Status:     clusterv1beta1.MemberClusterStatus{ResourceUsage: tc.usage},
+			}
+			got, status := p.Score(context.Background(), framework.NewCycleState(nil, nil), &placementv1beta1.ClusterSchedulingPolicySnapshot{}, cluster)
+			if !status.IsSuccess() {
+				t.Fatalf("Score() = %v, want success", status)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Score() diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package tenantquota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+	"go.goms.io/fleet/pkg/utils/placementquota"
+)
+
+// clusterBudget is how many more clusters the placements of a tenant may target.
+type clusterBudget struct {
+	// namespace is the namespace of the tenant.
+	namespace string
+	// maxClusters is the cluster limit of the placement quota of the tenant.
+	maxClusters int32
+	// targeted is the clusters which the placements of the tenant target already; picking them again does not
+	// count towards the limit.
+	targeted sets.Set[string]
+	// remaining is the number of the other clusters which can still be picked.
+	remaining int
+}
+
+// resourceRequestBudget is how much more of each limited resource the placements of a tenant may request.
+type resourceRequestBudget struct {
+	// namespace is the namespace of the tenant.
+	namespace string
+	// maxResourceRequests is the resource request limits of the placement quota of the tenant.
+	maxResourceRequests corev1.ResourceList
+	// remaining is the amount of each limited resource which can still be requested.
+	remaining corev1.ResourceList
+}
+
+// pluginState is the budgets of the tenants of the placement in a scheduling cycle.
+type pluginState struct {
+	// mu guards the budgets, as the Filter stage runs for the clusters in parallel.
+	mu      sync.Mutex
+	budgets []*clusterBudget
+	// requestBudgets are only prepared if the placement declares its resource requirements.
+	requestBudgets []*resourceRequestBudget
+}
+
+// PreFilter allows the plugin to connect to the PreFilter extension point in the scheduling framework.
+func (p *Plugin) PreFilter(
+	ctx context.Context,
+	state framework.CycleStatePluginReadWriter,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+) (status *framework.Status) {
+	crpName := policy.Labels[placementv1beta1.CRPTrackingLabel]
+	var crp placementv1beta1.ClusterResourcePlacement
+	if err := p.handle.Client().Get(ctx, client.ObjectKey{Name: crpName}, &crp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return framework.NewNonErrorStatus(framework.Skip, p.Name(), "the placement is not found")
+		}
+		return framework.FromError(err, p.Name(), "failed to get the placement")
+	}
+	quotas, err := placementquota.FetchQuotas(ctx, p.handle.Client(), &crp)
+	if err != nil {
+		return framework.FromError(err, p.Name(), "failed to fetch the placement quotas of the tenants of the placement")
+	}
+
+	requirements := policy.ResourceRequirements()
+	ps := &pluginState{}
+	for _, quota := range quotas {
+		if quota.MaxClusters == nil && (len(quota.MaxResourceRequests) == 0 || len(requirements) == 0) {
+			continue
+		}
+		budget, requestBudget, err := p.prepareBudgets(ctx, state, crpName, requirements, quota)
+		if err != nil {
+			return framework.FromError(err, p.Name(), "failed to count the clusters targeted by the placements of the tenant")
+		}
+		if budget != nil {
+			ps.budgets = append(ps.budgets, budget)
+		}
+		if requestBudget != nil {
+			ps.requestBudgets = append(ps.requestBudgets, requestBudget)
+		}
+	}
+	if len(ps.budgets) == 0 && len(ps.requestBudgets) == 0 {
+		// There is no limit to enforce; the Filter stage is skipped for all clusters.
+		return framework.NewNonErrorStatus(framework.Skip, p.Name(), "no cluster or resource request limit of the placement quotas to enforce")
+	}
+	state.Write(framework.StateKey(p.Name()), ps)
+	return nil
+}
+
+// prepareBudgets counts the clusters which the placements of the tenant target, including the ones which the
+// placement being scheduled targets already, and the resources which they request on these clusters; a budget is nil
+// if the quota does not limit it.
+func (p *Plugin) prepareBudgets(
+	ctx context.Context,
+	state framework.CycleStatePluginReadWriter,
+	crpName string,
+	requirements corev1.ResourceList,
+	quota placementquota.Quota,
+) (*clusterBudget, *resourceRequestBudget, error) {
+	targeted := sets.New[string]()
+	requested := corev1.ResourceList{}
+	crps, err := placementquota.TenantPlacements(ctx, p.handle.Client(), quota.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range crps {
+		if crps[i].Name == crpName {
+			// The bindings of the placement being scheduled are already in the cycle state.
+			continue
+		}
+		var bindingList placementv1beta1.ClusterResourceBindingList
+		if err := p.handle.Client().List(ctx, &bindingList, client.MatchingLabels{placementv1beta1.CRPTrackingLabel: crps[i].Name}); err != nil {
+			return nil, nil, err
+		}
+		var crpRequirements corev1.ResourceList
+		if crps[i].Spec.Policy != nil {
+			crpRequirements = crps[i].Spec.Policy.ResourceRequirements
+		}
+		for j := range bindingList.Items {
+			binding := &bindingList.Items[j]
+			if binding.DeletionTimestamp.IsZero() && binding.Spec.State != placementv1beta1.BindingStateUnscheduled {
+				targeted.Insert(binding.Spec.TargetCluster)
+				addResourceList(requested, crpRequirements)
+			}
+		}
+	}
+	for _, cluster := range state.ListClusters() {
+		if state.HasScheduledOrBoundBindingFor(cluster.Name) || state.HasObsoleteBindingFor(cluster.Name) {
+			targeted.Insert(cluster.Name)
+			addResourceList(requested, requirements)
+		}
+	}
+
+	var budget *clusterBudget
+	if quota.MaxClusters != nil {
+		budget = &clusterBudget{
+			namespace:   quota.Namespace,
+			maxClusters: *quota.MaxClusters,
+			targeted:    targeted,
+			remaining:   int(*quota.MaxClusters) - targeted.Len(),
+		}
+	}
+	var requestBudget *resourceRequestBudget
+	if len(quota.MaxResourceRequests) != 0 && len(requirements) != 0 {
+		remaining := make(corev1.ResourceList, len(quota.MaxResourceRequests))
+		for name, limit := range quota.MaxResourceRequests {
+			left := limit.DeepCopy()
+			left.Sub(requested[name])
+			remaining[name] = left
+		}
+		requestBudget = &resourceRequestBudget{
+			namespace:           quota.Namespace,
+			maxResourceRequests: quota.MaxResourceRequests,
+			remaining:           remaining,
+		}
+	}
+	return budget, requestBudget, nil
+}
+
+// addResourceList adds the quantities of the resources in b to the ones in a.
+func addResourceList(a, b corev1.ResourceList) {
+	for name, quantity := range b {
+		sum := a[name]
+		sum.Add(quantity)
+		a[name] = sum
+	}
+}
+
+// Filter allows the plugin to connect to the Filter extension point in the scheduling framework.
+//
+// Note that the plugin runs last at the Filter stage, so that the budgets are only spent on the clusters which pass
+// all the other filters; when there are more such clusters than a budget allows, the clusters which reach the plugin
+// first are picked.
+func (p *Plugin) Filter(
+	_ context.Context,
+	state framework.CycleStatePluginReadWriter,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+	cluster *clusterv1beta1.MemberCluster,
+) (status *framework.Status) {
+	val, err := state.Read(framework.StateKey(p.Name()))
+	if err != nil {
+		return framework.FromError(err, p.Name(), "failed to read the plugin state")
+	}
+	ps, ok := val.(*pluginState)
+	if !ok {
+		return framework.FromError(fmt.Errorf("unexpected plugin state type %T", val), p.Name(), "failed to read the plugin state")
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, budget := range ps.budgets {
+		if !budget.targeted.Has(cluster.Name) && budget.remaining <= 0 {
+			reason := fmt.Sprintf("the placements of the tenant in namespace %s have reached the limit of %d clusters of its placement quota", budget.namespace, budget.maxClusters)
+			return framework.NewNonErrorStatus(framework.ClusterUnschedulable, p.Name(), reason)
+		}
+	}
+	// The placement requests its resources again only on the clusters which it does not target yet.
+	requests := !state.HasScheduledOrBoundBindingFor(cluster.Name) && !state.HasObsoleteBindingFor(cluster.Name)
+	requirements := policy.ResourceRequirements()
+	if requests {
+		for _, budget := range ps.requestBudgets {
+			for _, name := range sets.List(sets.KeySet(budget.remaining)) {
+				required, found := requirements[name]
+				if !found {
+					continue
+				}
+				if remaining := budget.remaining[name]; remaining.Cmp(required) < 0 {
+					limit := budget.maxResourceRequests[name]
+					reason := fmt.Sprintf("the placements of the tenant in namespace %s would request more than the limit of %s %s of its placement quota", budget.namespace, limit.String(), name)
+					return framework.NewNonErrorStatus(framework.ClusterUnschedulable, p.Name(), reason)
+				}
+			}
+		}
+	}
+	for _, budget := range ps.budgets {
+		if !budget.targeted.Has(cluster.Name) {
+			budget.remaining--
+		}
+	}
+	if requests {
+		for _, budget := range ps.requestBudgets {
+			for name, remaining := range budget.remaining {
+				if required, found := requirements[name]; found {
+					remaining.Sub(required)
+					budget.remaining[name] = remaining
+				}
+			}
+		}
+	}
+	return nil
+}
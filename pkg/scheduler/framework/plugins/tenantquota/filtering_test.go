@@ -0,0 +1,313 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package tenantquota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/clustereligibilitychecker"
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+const (
+	crpName      = "test-crp"
+	otherCRPName = "other-crp"
+	tenantNS     = "team-a"
+)
+
+// MockHandle mocks the framework.Handle interface to set up the plugin.
+type MockHandle struct {
+	client client.Client
+}
+
+var (
+	_ framework.Handle = &MockHandle{}
+)
+
+func (mh *MockHandle) Client() client.Client               { return mh.client }
+func (mh *MockHandle) Manager() ctrl.Manager               { return nil }
+func (mh *MockHandle) UncachedReader() client.Reader       { return nil }
+func (mh *MockHandle) EventRecorder() record.EventRecorder { return nil }
+func (mh *MockHandle) ClusterEligibilityChecker() *clustereligibilitychecker.ClusterEligibilityChecker {
+	return nil
+}
+
+func newCRP(name string, selector placementv1beta1.ClusterResourceSelector) *placementv1beta1.ClusterResourcePlacement {
+	return &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{selector},
+		},
+	}
+}
+
+func newBinding(crp, cluster string, state placementv1beta1.BindingState) *placementv1beta1.ClusterResourceBinding {
+	return &placementv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   crp + "-" + cluster,
+			Labels: map[string]string{placementv1beta1.CRPTrackingLabel: crp},
+		},
+		Spec: placementv1beta1.ResourceBindingSpec{
+			State:         state,
+			TargetCluster: cluster,
+		},
+	}
+}
+
+func TestPreFilterAndFilter(t *testing.T) {
+	namespaceSelector := placementv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: tenantNS}
+	clusters := []clusterv1beta1.MemberCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-4"}},
+	}
+	quota := func(namespace string, maxClusters *int32) *placementv1beta1.PlacementQuota {
+		return &placementv1beta1.PlacementQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: namespace},
+			Spec:       placementv1beta1.PlacementQuotaSpec{MaxClusters: maxClusters},
+		}
+	}
+	tests := map[string]struct {
+		objects []client.Object
+		// bound are the clusters which the placement being scheduled targets already.
+		bound []string
+		// wantSkip tells whether the PreFilter stage skips the Filter stage.
+		wantSkip bool
+		// wantUnschedulable are the clusters which the Filter stage filters out when the clusters are filtered in order.
+		wantUnschedulable []string
+	}{
+		"no placement quota": {
+			objects:  []client.Object{newCRP(crpName, namespaceSelector)},
+			wantSkip: true,
+		},
+		"the placement quota of another namespace": {
+			objects: []client.Object{
+				newCRP(crpName, namespaceSelector),
+				quota("team-b", ptr.To(int32(1))),
+			},
+			wantSkip: true,
+		},
+		"the placement quota has no cluster limit": {
+			objects: []client.Object{
+				newCRP(crpName, namespaceSelector),
+				quota(tenantNS, nil),
+			},
+			wantSkip: true,
+		},
+		"the clusters targeted by another placement of the tenant count towards the limit": {
+			objects: []client.Object{
+				newCRP(crpName, namespaceSelector),
+				newCRP(otherCRPName, namespaceSelector),
+				newBinding(otherCRPName, "cluster-3", placementv1beta1.BindingStateBound),
+				newBinding(otherCRPName, "cluster-4", placementv1beta1.BindingStateUnscheduled),
+				quota(tenantNS, ptr.To(int32(2))),
+			},
+			wantUnschedulable: []string{"cluster-2", "cluster-4"},
+		},
+		"the clusters targeted by the placement count towards the limit": {
+			objects: []client.Object{
+				newCRP(crpName, namespaceSelector),
+				quota(tenantNS, ptr.To(int32(3))),
+			},
+			bound:             []string{"cluster-2", "cluster-4"},
+			wantUnschedulable: []string{"cluster-3"},
+		},
+		"the placement of another tenant does not count towards the limit": {
+			objects: []client.Object{
+				newCRP(crpName, placementv1beta1.ClusterResourceSelector{
+					Group: "", Version: "v1", Kind: "Namespace",
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				}),
+				newCRP(otherCRPName, placementv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: "team-b"}),
+				newBinding(otherCRPName, "cluster-1", placementv1beta1.BindingStateBound),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantNS, Labels: map[string]string{"team": "a"}}},
+				quota(tenantNS, ptr.To(int32(1))),
+			},
+			wantUnschedulable: []string{"cluster-2", "cluster-3", "cluster-4"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := placementv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			p := New()
+			p.SetUpWithFramework(&MockHandle{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()})
+			var bound []*placementv1beta1.ClusterResourceBinding
+			for _, cluster := range tc.bound {
+				bound = append(bound, newBinding(crpName, cluster, placementv1beta1.BindingStateBound))
+			}
+			state := framework.NewCycleState(clusters, nil, bound)
+			policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-policy",
+					Labels: map[string]string{placementv1beta1.CRPTrackingLabel: crpName},
+				},
+			}
+
+			status := p.PreFilter(context.Background(), state, policy)
+			if status.IsSkip() != tc.wantSkip {
+				t.Fatalf("PreFilter() = %v, want skip %t", status, tc.wantSkip)
+			}
+			if tc.wantSkip {
+				return
+			}
+			if !status.IsSuccess() {
+				t.Fatalf("PreFilter() = %v, want success", status)
+			}
+			var gotUnschedulable []string
+			for i := range clusters {
+				status := p.Filter(context.Background(), state, policy, &clusters[i])
+				switch {
+				case status.IsClusterUnschedulable():
+					gotUnschedulable = append(gotUnschedulable, clusters[i].Name)
+				case !status.IsSuccess():
+					t.Fatalf("Filter(%s) = %v, want success or unschedulable", clusters[i].Name, status)
+				}
+			}
+			if diff := cmp.Diff(tc.wantUnschedulable, gotUnschedulable); diff != "" {
+				t.Errorf("Filter() unschedulable clusters mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPreFilterAndFilterResourceRequests(t *testing.T) {
+	namespaceSelector := placementv1beta1.ClusterResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: tenantNS}
+	clusters := []clusterv1beta1.MemberCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-3"}},
+	}
+	cpu := func(quantity string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(quantity)}
+	}
+	quota := func(maxResourceRequests corev1.ResourceList) *placementv1beta1.PlacementQuota {
+		return &placementv1beta1.PlacementQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: tenantNS},
+			Spec:       placementv1beta1.PlacementQuotaSpec{MaxResourceRequests: maxResourceRequests},
+		}
+	}
+	otherCRP := newCRP(otherCRPName, namespaceSelector)
+	otherCRP.Spec.Policy = &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType, ResourceRequirements: cpu("2")}
+	tests := map[string]struct {
+		objects      []client.Object
+		requirements corev1.ResourceList
+		// bound are the clusters which the placement being scheduled targets already.
+		bound []string
+		// wantSkip tells whether the PreFilter stage skips the Filter stage.
+		wantSkip bool
+		// wantUnschedulable are the clusters which the Filter stage filters out when the clusters are filtered in order.
+		wantUnschedulable []string
+	}{
+		"the placement declares no resource requirements": {
+			objects:  []client.Object{newCRP(crpName, namespaceSelector), quota(cpu("1"))},
+			wantSkip: true,
+		},
+		"the placement quota limits another resource": {
+			objects: []client.Object{
+				newCRP(crpName, namespaceSelector),
+				quota(corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}),
+			},
+			requirements: cpu("1"),
+		},
+		"the requests on each cluster count towards the limit": {
+			objects:           []client.Object{newCRP(crpName, namespaceSelector), quota(cpu("2500m"))},
+			requirements:      cpu("1"),
+			wantUnschedulable: []string{"cluster-3"},
+		},
+		"the requests of another placement of the tenant count towards the limit": {
+			objects: []client.Object{
+				newCRP(crpName, namespaceSelector),
+				otherCRP,
+				newBinding(otherCRPName, "cluster-1", placementv1beta1.BindingStateBound),
+				newBinding(otherCRPName, "cluster-2", placementv1beta1.BindingStateUnscheduled),
+				quota(cpu("4")),
+			},
+			requirements:      cpu("1"),
+			wantUnschedulable: []string{"cluster-3"},
+		},
+		"the clusters targeted by the placement are not requested again": {
+			objects:           []client.Object{newCRP(crpName, namespaceSelector), quota(cpu("2"))},
+			requirements:      cpu("1"),
+			bound:             []string{"cluster-2", "cluster-3"},
+			wantUnschedulable: []string{"cluster-1"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := placementv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			p := New()
+			p.SetUpWithFramework(&MockHandle{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()})
+			var bound []*placementv1beta1.ClusterResourceBinding
+			for _, cluster := range tc.bound {
+				bound = append(bound, newBinding(crpName, cluster, placementv1beta1.BindingStateBound))
+			}
+			state := framework.NewCycleState(clusters, nil, bound)
+			policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-policy",
+					Labels: map[string]string{placementv1beta1.CRPTrackingLabel: crpName},
+				},
+				Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+					Policy: &placementv1beta1.PlacementPolicy{
+						PlacementType:        placementv1beta1.PickAllPlacementType,
+						ResourceRequirements: tc.requirements,
+					},
+				},
+			}
+
+			status := p.PreFilter(context.Background(), state, policy)
+			if status.IsSkip() != tc.wantSkip {
+				t.Fatalf("PreFilter() = %v, want skip %t", status, tc.wantSkip)
+			}
+			if tc.wantSkip {
+				return
+			}
+			if !status.IsSuccess() {
+				t.Fatalf("PreFilter() = %v, want success", status)
+			}
+			var gotUnschedulable []string
+			for i := range clusters {
+				status := p.Filter(context.Background(), state, policy, &clusters[i])
+				switch {
+				case status.IsClusterUnschedulable():
+					gotUnschedulable = append(gotUnschedulable, clusters[i].Name)
+				case !status.IsSuccess():
+					t.Fatalf("Filter(%s) = %v, want success or unschedulable", clusters[i].Name, status)
+				}
+			}
+			if diff := cmp.Diff(tc.wantUnschedulable, gotUnschedulable); diff != "" {
+				t.Errorf("Filter() unschedulable clusters mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// PreemptedReason is the reason of the Preempted condition of the policy snapshot of a placement which is
+	// preempted from some of its clusters.
+	PreemptedReason = "PreemptedByHigherPriorityPlacement"
+
+	preemptedMessageFormat = "The placement is preempted from cluster(s) %v by clusterResourcePlacement %s of priority %d"
+
+	// ClusterDecision schedule message template for the clusters picked by preemption.
+	resourceScheduleSucceededByPreemptionMessageFormat = "Successfully scheduled resources for placement in \"%s\" (affinity score: %d, topology spread score: %d): picked by scheduling policy after preempting the lower priority placement(s) %s"
+)
+
+// victim is a binding of a placement of a lower priority, which the scheduler removes from its cluster to make room
+// for the placement being scheduled.
+type victim struct {
+	binding *placementv1beta1.ClusterResourceBinding
+	policy  *placementv1beta1.ClusterSchedulingPolicySnapshot
+}
+
+// preemptionCandidate is a cluster which can accommodate the placement being scheduled once the victims are removed
+// from it.
+type preemptionCandidate struct {
+	cluster *clusterv1beta1.MemberCluster
+	// victims are sorted by their priorities in the ascending order.
+	victims []*victim
+}
+
+// highestVictimPriority returns the highest priority of the victims of the candidate.
+func (c *preemptionCandidate) highestVictimPriority() int32 {
+	return c.victims[len(c.victims)-1].policy.Priority()
+}
+
+// victimPlacementNames returns the names of the placements of the victims of the candidate.
+func (c *preemptionCandidate) victimPlacementNames() []string {
+	names := make([]string, 0, len(c.victims))
+	for _, v := range c.victims {
+		names = append(names, v.binding.Labels[placementv1beta1.CRPTrackingLabel])
+	}
+	return names
+}
+
+// runPreemption runs the preemption phase for a scheduling policy of the PickN placement type which has a positive
+// priority, when the scheduler cannot find enough clusters for it. It picks up to the given number of clusters among
+// the filtered ones, which accommodate the placement once the bindings of the placements of lower priorities are
+// removed from them, and returns the picked clusters scored. The clusters which remain filtered are returned as well,
+// along with the preemption candidates of the picked clusters.
+//
+// No binding is preempted here; the victims of the candidates are only marked as unscheduled by preempt once the
+// clusters picked for the placement are final, so that a failure in between does not evict them for nothing.
+//
+// Only the resource requirements are considered when removing a binding from a cluster, i.e. the scheduler regards the
+// resources that the placement of the binding declares as available on the cluster, as the other filters do not
+// depend on the placements on the cluster.
+func (f *framework) runPreemption(
+	ctx context.Context,
+	state *CycleState,
+	crpName string,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+	count int,
+	filtered []*filteredClusterWithStatus,
+) (picked ScoredClusters, stillFiltered []*filteredClusterWithStatus, candidates []*preemptionCandidate, err error) {
+	policyRef := klog.KObj(policy)
+	if count <= 0 || policy.Priority() <= 0 || len(filtered) == 0 {
+		return nil, filtered, nil, nil
+	}
+
+	victimsByCluster, err := f.collectVictims(ctx, crpName, policy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to collect the bindings to preempt", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, nil, nil, err
+	}
+
+	candidates = make([]*preemptionCandidate, 0, len(filtered))
+	for _, fc := range filtered {
+		victims, ok := f.selectVictimsOn(ctx, state, policy, fc.cluster, victimsByCluster[fc.cluster.Name])
+		if ok {
+			candidates = append(candidates, &preemptionCandidate{cluster: fc.cluster, victims: victims})
+		}
+	}
+	if len(candidates) == 0 {
+		klog.V(2).InfoS("No cluster can accommodate the placement by preemption", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, filtered, nil, nil
+	}
+
+	// Prefer the clusters whose victims have the lowest priorities, and then the ones with the fewest victims, so that
+	// the preemption disrupts as little as possible.
+	sort.Slice(candidates, func(i, j int) bool {
+		pi, pj := candidates[i].highestVictimPriority(), candidates[j].highestVictimPriority()
+		if pi != pj {
+			return pi < pj
+		}
+		if len(candidates[i].victims) != len(candidates[j].victims) {
+			return len(candidates[i].victims) < len(candidates[j].victims)
+		}
+		return candidates[i].cluster.Name < candidates[j].cluster.Name
+	})
+	if len(candidates) > count {
+		candidates = candidates[:count]
+	}
+
+	clusters := make([]*clusterv1beta1.MemberCluster, 0, len(candidates))
+	pickedNames := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		clusters = append(clusters, c.cluster)
+		pickedNames[c.cluster.Name] = true
+	}
+	picked, err = f.runScorePlugins(ctx, state, policy, clusters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to score the clusters picked by preemption", "clusterSchedulingPolicySnapshot", policyRef)
+		return nil, nil, nil, controller.NewUnexpectedBehaviorError(err)
+	}
+
+	stillFiltered = make([]*filteredClusterWithStatus, 0, len(filtered)-len(candidates))
+	for _, fc := range filtered {
+		if !pickedNames[fc.cluster.Name] {
+			stillFiltered = append(stillFiltered, fc)
+		}
+	}
+	return picked, stillFiltered, candidates, nil
+}
+
+// pickedPreemptionCandidates returns the preemption candidates whose clusters are picked for the placement.
+func pickedPreemptionCandidates(candidates []*preemptionCandidate, picked ScoredClusters) []*preemptionCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+	pickedNames := make(map[string]bool, len(picked))
+	for _, sc := range picked {
+		pickedNames[sc.Cluster.Name] = true
+	}
+	pickedCandidates := make([]*preemptionCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if pickedNames[c.cluster.Name] {
+			pickedCandidates = append(pickedCandidates, c)
+		}
+	}
+	return pickedCandidates
+}
+
+// preemptedPlacements returns the names of the placements preempted by the candidates, keyed by their clusters.
+func preemptedPlacements(candidates []*preemptionCandidate) map[string][]string {
+	preempted := make(map[string][]string, len(candidates))
+	for _, c := range candidates {
+		preempted[c.cluster.Name] = c.victimPlacementNames()
+	}
+	return preempted
+}
+
+// collectVictims returns the bindings which the placement may preempt, keyed by their target clusters, i.e. the
+// scheduled or bound bindings of the other placements which declare their resource requirements and are not of the
+// PickFixed placement type; selectVictimsOn only picks the ones of lower priorities among them. The bindings of each cluster are sorted by the priorities of their
+// placements in the ascending order, and then by their names.
+func (f *framework) collectVictims(ctx context.Context, crpName string, policy *placementv1beta1.ClusterSchedulingPolicySnapshot) (map[string][]*victim, error) {
+	bindingList := &placementv1beta1.ClusterResourceBindingList{}
+	if err := f.client.List(ctx, bindingList); err != nil {
+		return nil, controller.NewAPIServerError(true, err)
+	}
+
+	policies := make(map[string]*placementv1beta1.ClusterSchedulingPolicySnapshot)
+	victimsByCluster := make(map[string][]*victim)
+	for i := range bindingList.Items {
+		binding := &bindingList.Items[i]
+		if binding.Labels[placementv1beta1.CRPTrackingLabel] == crpName || binding.DeletionTimestamp != nil ||
+			(binding.Spec.State != placementv1beta1.BindingStateScheduled && binding.Spec.State != placementv1beta1.BindingStateBound) {
+			continue
+		}
+		victimPolicy, found := policies[binding.Spec.SchedulingPolicySnapshotName]
+		if !found {
+			victimPolicy = &placementv1beta1.ClusterSchedulingPolicySnapshot{}
+			if err := f.client.Get(ctx, types.NamespacedName{Name: binding.Spec.SchedulingPolicySnapshotName}, victimPolicy); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, controller.NewAPIServerError(true, err)
+				}
+				victimPolicy = nil
+			}
+			policies[binding.Spec.SchedulingPolicySnapshotName] = victimPolicy
+		}
+		if victimPolicy == nil || len(victimPolicy.ResourceRequirements()) == 0 ||
+			(victimPolicy.Spec.Policy != nil && victimPolicy.Spec.Policy.PlacementType == placementv1beta1.PickFixedPlacementType) {
+			continue
+		}
+		victimsByCluster[binding.Spec.TargetCluster] = append(victimsByCluster[binding.Spec.TargetCluster], &victim{binding: binding, policy: victimPolicy})
+	}
+
+	for _, victims := range victimsByCluster {
+		sort.Slice(victims, func(i, j int) bool {
+			if victims[i].policy.Priority() != victims[j].policy.Priority() {
+				return victims[i].policy.Priority() < victims[j].policy.Priority()
+			}
+			return victims[i].binding.Name < victims[j].binding.Name
+		})
+	}
+	return victimsByCluster, nil
+}
+
+// selectVictimsOn returns the fewest victims of the lowest priorities on the cluster, whose removal lets the cluster
+// pass all the filter plugins for the placement, and false if no such victims exist. Only the victims whose priorities
+// are strictly lower than the priority of the placement are considered.
+func (f *framework) selectVictimsOn(
+	ctx context.Context,
+	state *CycleState,
+	policy *placementv1beta1.ClusterSchedulingPolicySnapshot,
+	cluster *clusterv1beta1.MemberCluster,
+	victims []*victim,
+) ([]*victim, bool) {
+	if len(victims) == 0 {
+		return nil, false
+	}
+	simulated := cluster.DeepCopy()
+	for i, v := range victims {
+		if v.policy.Priority() >= policy.Priority() {
+			// the victims are sorted by their priorities, so none of the remaining ones can be preempted either
+			return nil, false
+		}
+		releaseResources(&simulated.Status.ResourceUsage, v.policy.ResourceRequirements())
+		if status := f.runFilterPluginsFor(ctx, state, policy, simulated); status.IsSuccess() {
+			return victims[:i+1], true
+		}
+	}
+	return nil, false
+}
+
+// releaseResources adds the resources back to the available resources of the cluster, or to its allocatable
+// resources if the cluster does not report the available ones, which the scheduler regards as available then.
+func releaseResources(usage *clusterv1beta1.ResourceUsage, released corev1.ResourceList) {
+	target := &usage.Available
+	if len(usage.Available) == 0 {
+		target = &usage.Allocatable
+	}
+	if *target == nil {
+		return
+	}
+	for name, quantity := range released {
+		if current, found := (*target)[name]; found {
+			current.Add(quantity)
+			(*target)[name] = current
+		}
+	}
+}
+
+// preempt marks the bindings of the victims as unscheduled and records the preemption in the Preempted condition of
+// the policy snapshots of their placements. It is called once the clusters picked for the placement are final.
+func (f *framework) preempt(ctx context.Context, crpName string, policy *placementv1beta1.ClusterSchedulingPolicySnapshot, candidates []*preemptionCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	bindings := make([]*placementv1beta1.ClusterResourceBinding, 0, len(candidates))
+	clustersByPolicy := make(map[string][]string)
+	for _, c := range candidates {
+		for _, v := range c.victims {
+			bindings = append(bindings, v.binding)
+			clustersByPolicy[v.policy.Name] = append(clustersByPolicy[v.policy.Name], c.cluster.Name)
+			klog.V(2).InfoS("Preempting the binding of a lower priority placement", "clusterSchedulingPolicySnapshot", klog.KObj(policy),
+				"clusterResourceBinding", klog.KObj(v.binding), "cluster", c.cluster.Name, "priority", v.policy.Priority())
+		}
+	}
+	if err := f.markAsUnscheduledFor(ctx, bindings); err != nil {
+		return controller.NewAPIServerError(false, err)
+	}
+
+	for policyName, clusters := range clustersByPolicy {
+		sort.Strings(clusters)
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			victimPolicy := &placementv1beta1.ClusterSchedulingPolicySnapshot{}
+			if err := f.client.Get(ctx, types.NamespacedName{Name: policyName}, victimPolicy); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			victimPolicy.SetConditions(metav1.Condition{
+				Type:               string(placementv1beta1.PolicySnapshotPreempted),
+				Status:             metav1.ConditionTrue,
+				Reason:             PreemptedReason,
+				Message:            fmt.Sprintf(preemptedMessageFormat, clusters, crpName, policy.Priority()),
+				ObservedGeneration: victimPolicy.Generation,
+			})
+			return f.client.Status().Update(ctx, victimPolicy)
+		})
+		if err != nil {
+			klog.ErrorS(err, "Failed to record the preemption", "clusterSchedulingPolicySnapshot", policyName)
+			return controller.NewAPIServerError(false, err)
+		}
+	}
+	return nil
+}
+
+// annotatePreemptingDecisions explains in the scheduling decisions of the bindings on the clusters picked by
+// preemption which placements are preempted from the clusters.
+func annotatePreemptingDecisions(preempted map[string][]string, toCreate []*placementv1beta1.ClusterResourceBinding, toPatch []*bindingWithPatch) {
+	if len(preempted) == 0 {
+		return
+	}
+	bindings := make([]*placementv1beta1.ClusterResourceBinding, 0, len(toCreate)+len(toPatch))
+	bindings = append(bindings, toCreate...)
+	for _, p := range toPatch {
+		bindings = append(bindings, p.updated)
+	}
+	for _, binding := range bindings {
+		names, found := preempted[binding.Spec.TargetCluster]
+		if !found {
+			continue
+		}
+		decision := &binding.Spec.ClusterDecision
+		var affinityScore, topologySpreadScore int32
+		if decision.ClusterScore != nil {
+			affinityScore = ptr.Deref(decision.ClusterScore.AffinityScore, 0)
+			topologySpreadScore = ptr.Deref(decision.ClusterScore.TopologySpreadScore, 0)
+		}
+		decision.Reason = fmt.Sprintf(resourceScheduleSucceededByPreemptionMessageFormat, decision.ClusterName, affinityScore, topologySpreadScore, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/scheduler/framework/parallelizer"
+)
+
+// newPreemptionTestPolicy returns a policy snapshot of a PickN placement with the priority and the cpu requirement.
+func newPreemptionTestPolicy(name string, priority int32, cpu string) *placementv1beta1.ClusterSchedulingPolicySnapshot {
+	return &placementv1beta1.ClusterSchedulingPolicySnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+		Spec: placementv1beta1.SchedulingPolicySnapshotSpec{
+			Policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:        placementv1beta1.PickNPlacementType,
+				Priority:             priority,
+				ResourceRequirements: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			},
+		},
+	}
+}
+
+func newPreemptionTestBinding(crp, cluster, policyName string) *placementv1beta1.ClusterResourceBinding {
+	return &placementv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-%s", crp, cluster),
+			Labels: map[string]string{placementv1beta1.CRPTrackingLabel: crp},
+		},
+		Spec: placementv1beta1.ResourceBindingSpec{
+			State:                        placementv1beta1.BindingStateBound,
+			SchedulingPolicySnapshotName: policyName,
+			TargetCluster:                cluster,
+		},
+	}
+}
+
+func newPreemptionTestCluster(name, availableCPU string) *clusterv1beta1.MemberCluster {
+	return &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: clusterv1beta1.MemberClusterStatus{
+			ResourceUsage: clusterv1beta1.ResourceUsage{
+				Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(availableCPU)},
+			},
+		},
+	}
+}
+
+// TestRunPreemption tests the runPreemption method.
+func TestRunPreemption(t *testing.T) {
+	// the cluster fits the placement once the binding of the batch placement is removed from it.
+	oneVictimCluster := newPreemptionTestCluster(clusterName, "1")
+	// the cluster fits the placement once the bindings of both lower priority placements are removed from it.
+	twoVictimsCluster := newPreemptionTestCluster(altClusterName, "0")
+	// the cluster only runs a placement of a higher priority.
+	noVictimCluster := newPreemptionTestCluster(anotherClusterName, "0")
+
+	objects := []client.Object{
+		newPreemptionTestPolicy("batch-0", 0, "2"),
+		newPreemptionTestPolicy("reports-0", 50, "2"),
+		newPreemptionTestPolicy("critical-0", 200, "3"),
+		newPreemptionTestPolicy("unspecified-0", 0, "0"),
+		newPreemptionTestBinding("batch", clusterName, "batch-0"),
+		newPreemptionTestBinding("batch", altClusterName, "batch-0"),
+		newPreemptionTestBinding("reports", altClusterName, "reports-0"),
+		newPreemptionTestBinding("critical", anotherClusterName, "critical-0"),
+		newPreemptionTestBinding("unspecified", anotherClusterName, "unspecified-0"),
+	}
+
+	testCases := []struct {
+		name             string
+		priority         int32
+		count            int
+		wantPicked       []string
+		wantFiltered     []string
+		wantPreempted    map[string][]string
+		wantUnscheduled  []string
+		wantPreemptedMsg map[string]string
+	}{
+		{
+			name:          "pick the cluster with the lowest priority victims",
+			priority:      100,
+			count:         1,
+			wantPicked:    []string{clusterName},
+			wantFiltered:  []string{altClusterName, anotherClusterName},
+			wantPreempted: map[string][]string{clusterName: {"batch"}},
+			wantUnscheduled: []string{
+				"batch-" + clusterName,
+			},
+			wantPreemptedMsg: map[string]string{
+				"batch-0": fmt.Sprintf(preemptedMessageFormat, []string{clusterName}, crpName, 100),
+			},
+		},
+		{
+			name:          "pick the clusters with more victims",
+			priority:      100,
+			count:         3,
+			wantPicked:    []string{clusterName, altClusterName},
+			wantFiltered:  []string{anotherClusterName},
+			wantPreempted: map[string][]string{clusterName: {"batch"}, altClusterName: {"batch", "reports"}},
+			wantUnscheduled: []string{
+				"batch-" + clusterName, "batch-" + altClusterName, "reports-" + altClusterName,
+			},
+			wantPreemptedMsg: map[string]string{
+				"batch-0":   fmt.Sprintf(preemptedMessageFormat, []string{clusterName, altClusterName}, crpName, 100),
+				"reports-0": fmt.Sprintf(preemptedMessageFormat, []string{altClusterName}, crpName, 100),
+			},
+		},
+		{
+			name:         "no preemption without a priority",
+			count:        1,
+			wantFiltered: []string{clusterName, altClusterName, anotherClusterName},
+		},
+		{
+			name:         "victims of the same priority",
+			priority:     50,
+			count:        3,
+			wantPicked:   []string{clusterName},
+			wantFiltered: []string{altClusterName, anotherClusterName},
+			// the reports placement cannot be preempted by a placement of the same priority
+			wantPreempted:   map[string][]string{clusterName: {"batch"}},
+			wantUnscheduled: []string{"batch-" + clusterName},
+			wantPreemptedMsg: map[string]string{
+				"batch-0": fmt.Sprintf(preemptedMessageFormat, []string{clusterName}, crpName, 50),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			copied := make([]client.Object, 0, len(objects))
+			for _, obj := range objects {
+				copied = append(copied, obj.DeepCopyObject().(client.Object))
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(copied...).
+				WithStatusSubresource(&placementv1beta1.ClusterSchedulingPolicySnapshot{}).
+				Build()
+
+			profile := NewProfile(dummyProfileName)
+			profile.WithFilterPlugin(&DummyAllPurposePlugin{
+				name: dummyPluginName,
+				filterRunner: func(_ context.Context, _ CycleStatePluginReadWriter, policy *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) *Status {
+					available := cluster.Status.ResourceUsage.Available[corev1.ResourceCPU]
+					if available.Cmp(policy.ResourceRequirements()[corev1.ResourceCPU]) < 0 {
+						return NewNonErrorStatus(ClusterUnschedulable, dummyPluginName)
+					}
+					return nil
+				},
+			})
+			profile.WithScorePlugin(&DummyAllPurposePlugin{
+				name: dummyPluginName,
+				scoreRunner: func(_ context.Context, _ CycleStatePluginReadWriter, _ *placementv1beta1.ClusterSchedulingPolicySnapshot, _ *clusterv1beta1.MemberCluster) (*ClusterScore, *Status) {
+					return &ClusterScore{}, nil
+				},
+			})
+			f := &framework{
+				profile:      profile,
+				client:       fakeClient,
+				parallelizer: parallelizer.NewParallelizer(parallelizer.DefaultNumOfWorkers),
+			}
+
+			policy := newPreemptionTestPolicy(policyName, tc.priority, "3")
+			filtered := []*filteredClusterWithStatus{
+				{cluster: oneVictimCluster, status: NewNonErrorStatus(ClusterUnschedulable, dummyPluginName)},
+				{cluster: twoVictimsCluster, status: NewNonErrorStatus(ClusterUnschedulable, dummyPluginName)},
+				{cluster: noVictimCluster, status: NewNonErrorStatus(ClusterUnschedulable, dummyPluginName)},
+			}
+			ctx := context.Background()
+			state := NewCycleState([]clusterv1beta1.MemberCluster{}, []*placementv1beta1.ClusterResourceBinding{})
+			picked, stillFiltered, candidates, err := f.runPreemption(ctx, state, crpName, policy, tc.count, filtered)
+			if err != nil {
+				t.Fatalf("runPreemption() = %v, want no error", err)
+			}
+
+			var pickedNames, filteredNames []string
+			for _, sc := range picked {
+				pickedNames = append(pickedNames, sc.Cluster.Name)
+			}
+			for _, fc := range stillFiltered {
+				filteredNames = append(filteredNames, fc.cluster.Name)
+			}
+			if diff := cmp.Diff(tc.wantPicked, pickedNames); diff != "" {
+				t.Errorf("runPreemption() picked clusters mismatch (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFiltered, filteredNames); diff != "" {
+				t.Errorf("runPreemption() filtered clusters mismatch (-want, +got):\n%s", diff)
+			}
+			var gotPreempted map[string][]string
+			if len(candidates) > 0 {
+				gotPreempted = preemptedPlacements(candidates)
+			}
+			if diff := cmp.Diff(tc.wantPreempted, gotPreempted); diff != "" {
+				t.Errorf("runPreemption() preempted placements mismatch (-want, +got):\n%s", diff)
+			}
+
+			unscheduledBindings := func() []string {
+				bindingList := &placementv1beta1.ClusterResourceBindingList{}
+				if err := fakeClient.List(ctx, bindingList); err != nil {
+					t.Fatalf("List() bindings = %v, want no error", err)
+				}
+				var unscheduled []string
+				for _, binding := range bindingList.Items {
+					if binding.Spec.State == placementv1beta1.BindingStateUnscheduled {
+						unscheduled = append(unscheduled, binding.Name)
+					}
+				}
+				return unscheduled
+			}
+			// no binding is preempted before the clusters picked for the placement are final
+			if unscheduled := unscheduledBindings(); len(unscheduled) != 0 {
+				t.Errorf("runPreemption() unscheduled bindings %v, want none", unscheduled)
+			}
+
+			if err := f.preempt(ctx, crpName, policy, pickedPreemptionCandidates(candidates, picked)); err != nil {
+				t.Fatalf("preempt() = %v, want no error", err)
+			}
+			if diff := cmp.Diff(tc.wantUnscheduled, unscheduledBindings(), cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("preempt() unscheduled bindings mismatch (-want, +got):\n%s", diff)
+			}
+
+			for _, name := range []string{"batch-0", "reports-0", "critical-0"} {
+				snapshot := &placementv1beta1.ClusterSchedulingPolicySnapshot{}
+				if err := fakeClient.Get(ctx, types.NamespacedName{Name: name}, snapshot); err != nil {
+					t.Fatalf("Get() policy snapshot %s = %v, want no error", name, err)
+				}
+				var gotMsg string
+				if cond := snapshot.GetCondition(string(placementv1beta1.PolicySnapshotPreempted)); cond != nil {
+					gotMsg = cond.Message
+				}
+				if gotMsg != tc.wantPreemptedMsg[name] {
+					t.Errorf("Preempted condition message of policy snapshot %s = %q, want %q", name, gotMsg, tc.wantPreemptedMsg[name])
+				}
+			}
+		})
+	}
+}
+
+// TestAnnotatePreemptingDecisions tests the annotatePreemptingDecisions function.
+func TestAnnotatePreemptingDecisions(t *testing.T) {
+	affinityScore, topologySpreadScore := int32(1), int32(2)
+	newBinding := func(cluster string) *placementv1beta1.ClusterResourceBinding {
+		return &placementv1beta1.ClusterResourceBinding{
+			Spec: placementv1beta1.ResourceBindingSpec{
+				TargetCluster: cluster,
+				ClusterDecision: placementv1beta1.ClusterDecision{
+					ClusterName:  cluster,
+					Selected:     true,
+					ClusterScore: &placementv1beta1.ClusterScore{AffinityScore: &affinityScore, TopologySpreadScore: &topologySpreadScore},
+					Reason:       fmt.Sprintf(resourceScheduleSucceededWithScoreMessageFormat, cluster, affinityScore, topologySpreadScore),
+				},
+			},
+		}
+	}
+	toCreate := []*placementv1beta1.ClusterResourceBinding{newBinding(clusterName), newBinding(altClusterName)}
+	toPatch := []*bindingWithPatch{{updated: newBinding(anotherClusterName)}}
+
+	annotatePreemptingDecisions(map[string][]string{clusterName: {"batch", "reports"}, anotherClusterName: {"batch"}}, toCreate, toPatch)
+
+	wantReasons := map[string]string{
+		clusterName:        fmt.Sprintf(resourceScheduleSucceededByPreemptionMessageFormat, clusterName, 1, 2, "batch, reports"),
+		altClusterName:     fmt.Sprintf(resourceScheduleSucceededWithScoreMessageFormat, altClusterName, 1, 2),
+		anotherClusterName: fmt.Sprintf(resourceScheduleSucceededByPreemptionMessageFormat, anotherClusterName, 1, 2, "batch"),
+	}
+	for _, binding := range append(toCreate, toPatch[0].updated) {
+		if got := binding.Spec.ClusterDecision.Reason; got != wantReasons[binding.Spec.TargetCluster] {
+			t.Errorf("decision reason of cluster %s = %q, want %q", binding.Spec.TargetCluster, got, wantReasons[binding.Spec.TargetCluster])
+		}
+	}
+}
+
+// TestPickedPreemptionCandidates tests the pickedPreemptionCandidates function.
+func TestPickedPreemptionCandidates(t *testing.T) {
+	candidates := []*preemptionCandidate{
+		{cluster: newPreemptionTestCluster(clusterName, "0")},
+		{cluster: newPreemptionTestCluster(altClusterName, "0")},
+	}
+	picked := ScoredClusters{
+		{Cluster: newPreemptionTestCluster(altClusterName, "0"), Score: &ClusterScore{}},
+		{Cluster: newPreemptionTestCluster(anotherClusterName, "0"), Score: &ClusterScore{}},
+	}
+
+	got := pickedPreemptionCandidates(candidates, picked)
+	if len(got) != 1 || got[0].cluster.Name != altClusterName {
+		t.Errorf("pickedPreemptionCandidates() = %v, want the candidate of cluster %s only", got, altClusterName)
+	}
+	if got := pickedPreemptionCandidates(nil, picked); got != nil {
+		t.Errorf("pickedPreemptionCandidates() = %v, want nil without candidates", got)
+	}
+}
@@ -8,9 +8,9 @@ package framework
 // Profile specifies the scheduling profile a framework uses; it includes the plugins in use
 // by the framework at each extension point in order.
 //
-// At this moment, since Fleet does not support runtime profiles, all plugins are registered
-// directly to one universal profile, in their instantiated forms, rather than decoupled using
-// a factory registry and instantiated along with the profile's associated framework.
+// The plugins are registered to a profile in their instantiated forms; a profile can either be
+// assembled directly, as the default profile is, or built from a ProfileConfig with the plugin
+// factories in a Registry.
 type Profile struct {
 	name string
 
@@ -24,6 +24,10 @@ type Profile struct {
 	// This helps to avoid setting up same plugin multiple times with the framework if the plugin
 	// registers at multiple extension points.
 	registeredPlugins map[string]Plugin
+
+	// scoreWeights is a map of the weights of the score plugins, keyed by their names; the scores of a
+	// plugin without a weight are not scaled.
+	scoreWeights map[string]int
 }
 
 // WithPostBatchPlugin registers a PostBatchPlugin to the profile.
@@ -61,6 +65,26 @@ func (profile *Profile) WithScorePlugin(plugin ScorePlugin) *Profile {
 	return profile
 }
 
+// WithWeightedScorePlugin registers a ScorePlugin to the profile, whose scores are multiplied by the weight.
+func (profile *Profile) WithWeightedScorePlugin(plugin ScorePlugin, weight int) *Profile {
+	if profile.scoreWeights == nil {
+		profile.scoreWeights = map[string]int{}
+	}
+	profile.scoreWeights[plugin.Name()] = weight
+	return profile.WithScorePlugin(plugin)
+}
+
+// weightedScore returns the score of a score plugin multiplied by the weight of the plugin.
+//
+// Note that the score is not modified, as it might have been cached.
+func (profile *Profile) weightedScore(pluginName string, score *ClusterScore) *ClusterScore {
+	weight, found := profile.scoreWeights[pluginName]
+	if !found || weight == 1 {
+		return score
+	}
+	return score.Scale(weight)
+}
+
 // Name returns the name of the profile.
 func (profile *Profile) Name() string {
 	return profile.name
@@ -0,0 +1,94 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"fmt"
+)
+
+// PluginFactory instantiates a plugin; each profile built from a Registry gets its own instances
+// of the plugins.
+type PluginFactory func() Plugin
+
+// Registry is the plugins that scheduling profiles can be built from, keyed by the names of the
+// plugins.
+type Registry map[string]PluginFactory
+
+// PluginConfig specifies a plugin enabled in a scheduling profile.
+type PluginConfig struct {
+	// Name is the name of the plugin in the registry.
+	Name string `json:"name"`
+
+	// Weight multiplies the scores of the plugin; it is only valid for the plugins which connect to the
+	// Score extension point. Default is 1.
+	Weight *int `json:"weight,omitempty"`
+}
+
+// ProfileConfig specifies a scheduling profile by the plugins it enables.
+type ProfileConfig struct {
+	// Name is the name of the profile.
+	Name string `json:"name"`
+
+	// Plugins are the plugins enabled in the profile; each plugin connects to all the extension
+	// points it implements, in the order of the list.
+	Plugins []PluginConfig `json:"plugins"`
+}
+
+// NewProfile builds a scheduling profile from its config with the plugins in the registry.
+func (r Registry) NewProfile(config ProfileConfig) (*Profile, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("the name of the profile is empty")
+	}
+	profile := NewProfile(config.Name)
+	for _, pluginConfig := range config.Plugins {
+		factory, found := r[pluginConfig.Name]
+		if !found {
+			return nil, fmt.Errorf("profile %s: unknown plugin %s", config.Name, pluginConfig.Name)
+		}
+		if _, found := profile.registeredPlugins[pluginConfig.Name]; found {
+			return nil, fmt.Errorf("profile %s: plugin %s is enabled more than once", config.Name, pluginConfig.Name)
+		}
+		plugin := factory()
+		if plugin.Name() != pluginConfig.Name {
+			return nil, fmt.Errorf("profile %s: plugin %s is registered as %s", config.Name, plugin.Name(), pluginConfig.Name)
+		}
+
+		connected := false
+		if pl, ok := plugin.(PostBatchPlugin); ok {
+			profile.WithPostBatchPlugin(pl)
+			connected = true
+		}
+		if pl, ok := plugin.(PreFilterPlugin); ok {
+			profile.WithPreFilterPlugin(pl)
+			connected = true
+		}
+		if pl, ok := plugin.(FilterPlugin); ok {
+			profile.WithFilterPlugin(pl)
+			connected = true
+		}
+		if pl, ok := plugin.(PreScorePlugin); ok {
+			profile.WithPreScorePlugin(pl)
+			connected = true
+		}
+		pl, isScorePlugin := plugin.(ScorePlugin)
+		switch {
+		case pluginConfig.Weight != nil && !isScorePlugin:
+			return nil, fmt.Errorf("profile %s: plugin %s does not score the clusters and cannot have a weight", config.Name, pluginConfig.Name)
+		case pluginConfig.Weight != nil && *pluginConfig.Weight < 1:
+			return nil, fmt.Errorf("profile %s: the weight of plugin %s is %d, want at least 1", config.Name, pluginConfig.Name, *pluginConfig.Weight)
+		case pluginConfig.Weight != nil:
+			profile.WithWeightedScorePlugin(pl, *pluginConfig.Weight)
+			connected = true
+		case isScorePlugin:
+			profile.WithScorePlugin(pl)
+			connected = true
+		}
+		if !connected {
+			return nil, fmt.Errorf("profile %s: plugin %s does not connect to any extension point", config.Name, pluginConfig.Name)
+		}
+	}
+	return profile, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	filterOnlyPluginName = "filterOnlyPlugin"
+)
+
+// filterOnlyPlugin is a no-op plugin which connects to the Filter extension point only.
+type filterOnlyPlugin struct{}
+
+func (p *filterOnlyPlugin) Name() string { return filterOnlyPluginName }
+
+func (p *filterOnlyPlugin) Filter(_ context.Context, _ CycleStatePluginReadWriter, _ *placementv1beta1.ClusterSchedulingPolicySnapshot, _ *clusterv1beta1.MemberCluster) *Status {
+	return nil
+}
+
+func (p *filterOnlyPlugin) SetUpWithFramework(_ Handle) {}
+
+// TestRegistryNewProfile tests the building of the profiles from their configs.
+func TestRegistryNewProfile(t *testing.T) {
+	registry := Registry{
+		dummyPluginName:      func() Plugin { return &DummyAllPurposePlugin{name: dummyPluginName} },
+		filterOnlyPluginName: func() Plugin { return &filterOnlyPlugin{} },
+	}
+	weight := func(w int) *int { return &w }
+
+	testCases := []struct {
+		name    string
+		config  ProfileConfig
+		wantErr string
+		// wantScore is the weighted score of the all purpose plugin, whose raw score is 1 for every field.
+		wantScore *ClusterScore
+	}{
+		{
+			name: "plugins with a weight",
+			config: ProfileConfig{
+				Name:    dummyProfileName,
+				Plugins: []PluginConfig{{Name: filterOnlyPluginName}, {Name: dummyPluginName, Weight: weight(3)}},
+			},
+			wantScore: &ClusterScore{TopologySpreadScore: 3, AffinityScore: 3, ObsoletePlacementAffinityScore: 3, ResourceUsageScore: 3},
+		},
+		{
+			name: "plugins without a weight",
+			config: ProfileConfig{
+				Name:    dummyProfileName,
+				Plugins: []PluginConfig{{Name: dummyPluginName}},
+			},
+			wantScore: &ClusterScore{TopologySpreadScore: 1, AffinityScore: 1, ObsoletePlacementAffinityScore: 1, ResourceUsageScore: 1},
+		},
+		{
+			name:    "unknown plugin",
+			config:  ProfileConfig{Name: dummyProfileName, Plugins: []PluginConfig{{Name: "unknown"}}},
+			wantErr: "unknown plugin unknown",
+		},
+		{
+			name:    "plugin enabled twice",
+			config:  ProfileConfig{Name: dummyProfileName, Plugins: []PluginConfig{{Name: dummyPluginName}, {Name: dummyPluginName}}},
+			wantErr: "enabled more than once",
+		},
+		{
+			name:    "weight of a plugin which does not score",
+			config:  ProfileConfig{Name: dummyProfileName, Plugins: []PluginConfig{{Name: filterOnlyPluginName, Weight: weight(2)}}},
+			wantErr: "cannot have a weight",
+		},
+		{
+			name:    "zero weight",
+			config:  ProfileConfig{Name: dummyProfileName, Plugins: []PluginConfig{{Name: dummyPluginName, Weight: weight(0)}}},
+			wantErr: "want at least 1",
+		},
+		{
+			name:    "empty name",
+			config:  ProfileConfig{Plugins: []PluginConfig{{Name: dummyPluginName}}},
+			wantErr: "name of the profile is empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, err := registry.NewProfile(tc.config)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("NewProfile() = %v, want error containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewProfile() = %v, want no error", err)
+			}
+			if profile.Name() != tc.config.Name {
+				t.Errorf("NewProfile() name = %s, want %s", profile.Name(), tc.config.Name)
+			}
+			if len(profile.scorePlugins) != 1 || profile.scorePlugins[0].Name() != dummyPluginName {
+				t.Fatalf("NewProfile() score plugins = %v, want the all purpose plugin only", profile.scorePlugins)
+			}
+			if len(profile.filterPlugins) != len(tc.config.Plugins) {
+				t.Errorf("NewProfile() got %d filter plugins, want %d", len(profile.filterPlugins), len(tc.config.Plugins))
+			}
+			rawScore := &ClusterScore{TopologySpreadScore: 1, AffinityScore: 1, ObsoletePlacementAffinityScore: 1, ResourceUsageScore: 1}
+			if diff := cmp.Diff(tc.wantScore, profile.weightedScore(dummyPluginName, rawScore)); diff != "" {
+				t.Errorf("weightedScore() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -6,7 +6,10 @@ Licensed under the MIT license.
 package framework
 
 import (
+	"k8s.io/utils/ptr"
+
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 )
 
 // ClusterScore is the scores the scheduler assigns to a cluster.
@@ -25,6 +28,19 @@ type ClusterScore struct {
 	// a preference for already selected clusters when all the other conditions are the same,
 	// so as to minimize interruption between different scheduling runs.
 	ObsoletePlacementAffinityScore int
+	// ResourceUsageScore reflects how much of the allocatable CPU and memory of a cluster is still
+	// available; its value range should be [0, MaxNormalizedScore], where a higher score signals a
+	// less loaded cluster.
+	//
+	// Note that this score has the lowest precedence; it only decides between the clusters that are
+	// otherwise equally preferred, so that the placements land on the least loaded clusters.
+	ResourceUsageScore int
+	// WeightedScore is the sum of the topology spread, affinity and resource usage scores, each multiplied
+	// by its weight in the score weights of the scheduling policy.
+	//
+	// Note that this score is only set if the scheduling policy has score weights, in which case it has
+	// the highest precedence; the other scores only decide between the clusters of the same weighted score.
+	WeightedScore int
 }
 
 // Add adds a ClusterScore to another ClusterScore.
@@ -34,6 +50,32 @@ func (s1 *ClusterScore) Add(s2 *ClusterScore) {
 	s1.TopologySpreadScore += s2.TopologySpreadScore
 	s1.AffinityScore += s2.AffinityScore
 	s1.ObsoletePlacementAffinityScore += s2.ObsoletePlacementAffinityScore
+	s1.ResourceUsageScore += s2.ResourceUsageScore
+	s1.WeightedScore += s2.WeightedScore
+}
+
+// Scale returns a new ClusterScore whose scores are the ones of the ClusterScore multiplied by the weight.
+//
+// Note that this will panic if the score is nil.
+func (s1 *ClusterScore) Scale(weight int) *ClusterScore {
+	return &ClusterScore{
+		TopologySpreadScore:            s1.TopologySpreadScore * weight,
+		AffinityScore:                  s1.AffinityScore * weight,
+		ObsoletePlacementAffinityScore: s1.ObsoletePlacementAffinityScore * weight,
+		ResourceUsageScore:             s1.ResourceUsageScore * weight,
+		WeightedScore:                  s1.WeightedScore * weight,
+	}
+}
+
+// ApplyWeights multiplies the scores of a ClusterScore by the score weights of a scheduling policy, and
+// sums up the weighted scores as its weighted score; the scores without a weight are weighted 1.
+//
+// Note that this will panic if the score is nil.
+func (s1 *ClusterScore) ApplyWeights(weights *placementv1beta1.ScoreWeights) {
+	s1.TopologySpreadScore *= int(ptr.Deref(weights.TopologySpread, 1))
+	s1.AffinityScore *= int(ptr.Deref(weights.Affinity, 1))
+	s1.ResourceUsageScore *= int(ptr.Deref(weights.ResourceUsage, 1))
+	s1.WeightedScore = s1.TopologySpreadScore + s1.AffinityScore + s1.ResourceUsageScore
 }
 
 // Equal returns true if a ClusterScore is equal to another.
@@ -49,7 +91,9 @@ func (s1 *ClusterScore) Equal(s2 *ClusterScore) bool {
 		// Both are not nils.
 		return s1.TopologySpreadScore == s2.TopologySpreadScore &&
 			s1.AffinityScore == s2.AffinityScore &&
-			s1.ObsoletePlacementAffinityScore == s2.ObsoletePlacementAffinityScore
+			s1.ObsoletePlacementAffinityScore == s2.ObsoletePlacementAffinityScore &&
+			s1.ResourceUsageScore == s2.ResourceUsageScore &&
+			s1.WeightedScore == s2.WeightedScore
 	}
 }
 
@@ -57,6 +101,10 @@ func (s1 *ClusterScore) Equal(s2 *ClusterScore) bool {
 //
 // Note that this will panic if either score is nil.
 func (s1 *ClusterScore) Less(s2 *ClusterScore) bool {
+	if s1.WeightedScore != s2.WeightedScore {
+		return s1.WeightedScore < s2.WeightedScore
+	}
+
 	if s1.TopologySpreadScore != s2.TopologySpreadScore {
 		return s1.TopologySpreadScore < s2.TopologySpreadScore
 	}
@@ -65,7 +113,11 @@ func (s1 *ClusterScore) Less(s2 *ClusterScore) bool {
 		return s1.AffinityScore < s2.AffinityScore
 	}
 
-	return s1.ObsoletePlacementAffinityScore < s2.ObsoletePlacementAffinityScore
+	if s1.ObsoletePlacementAffinityScore != s2.ObsoletePlacementAffinityScore {
+		return s1.ObsoletePlacementAffinityScore < s2.ObsoletePlacementAffinityScore
+	}
+
+	return s1.ResourceUsageScore < s2.ResourceUsageScore
 }
 
 // ScoredCluster is a cluster with a score.
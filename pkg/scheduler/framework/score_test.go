@@ -11,8 +11,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 )
 
 // TestClusterScoreToAdd tests the Add() method of ClusterScore.
@@ -27,6 +29,7 @@ func TestClusterScoreAdd(t *testing.T) {
 		TopologySpreadScore:            1,
 		AffinityScore:                  5,
 		ObsoletePlacementAffinityScore: 1,
+		ResourceUsageScore:             40,
 	}
 
 	s1.Add(s2)
@@ -34,6 +37,7 @@ func TestClusterScoreAdd(t *testing.T) {
 		TopologySpreadScore:            1,
 		AffinityScore:                  5,
 		ObsoletePlacementAffinityScore: 1,
+		ResourceUsageScore:             40,
 	}
 	if diff := cmp.Diff(s1, want); diff != "" {
 		t.Fatalf("Add() diff (-got, +want): %s", diff)
@@ -151,6 +155,46 @@ func TestClusterScoreLess(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "s1 is less than s2 in resource usage score",
+			s1: &ClusterScore{
+				TopologySpreadScore: 1,
+				AffinityScore:       10,
+				ResourceUsageScore:  20,
+			},
+			s2: &ClusterScore{
+				TopologySpreadScore: 1,
+				AffinityScore:       10,
+				ResourceUsageScore:  80,
+			},
+			want: true,
+		},
+		{
+			name: "s1 is less than s2 in active or creating binding score, with a higher resource usage score",
+			s1: &ClusterScore{
+				ObsoletePlacementAffinityScore: 0,
+				ResourceUsageScore:             80,
+			},
+			s2: &ClusterScore{
+				ObsoletePlacementAffinityScore: 1,
+				ResourceUsageScore:             20,
+			},
+			want: true,
+		},
+		{
+			name: "s1 is less than s2 in weighted score, with a higher topology spread score",
+			s1: &ClusterScore{
+				TopologySpreadScore: 1,
+				AffinityScore:       10,
+				WeightedScore:       11,
+			},
+			s2: &ClusterScore{
+				TopologySpreadScore: 0,
+				AffinityScore:       20,
+				WeightedScore:       20,
+			},
+			want: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -166,6 +210,57 @@ func TestClusterScoreLess(t *testing.T) {
 	}
 }
 
+// TestClusterScoreApplyWeights tests the ApplyWeights method.
+func TestClusterScoreApplyWeights(t *testing.T) {
+	testCases := []struct {
+		name    string
+		weights *placementv1beta1.ScoreWeights
+		want    *ClusterScore
+	}{
+		{
+			name:    "no weights set",
+			weights: &placementv1beta1.ScoreWeights{},
+			want: &ClusterScore{
+				TopologySpreadScore:            -2,
+				AffinityScore:                  10,
+				ObsoletePlacementAffinityScore: 1,
+				ResourceUsageScore:             40,
+				WeightedScore:                  48,
+			},
+		},
+		{
+			name: "all weights set",
+			weights: &placementv1beta1.ScoreWeights{
+				TopologySpread: ptr.To(int32(3)),
+				Affinity:       ptr.To(int32(2)),
+				ResourceUsage:  ptr.To(int32(0)),
+			},
+			want: &ClusterScore{
+				TopologySpreadScore:            -6,
+				AffinityScore:                  20,
+				ObsoletePlacementAffinityScore: 1,
+				ResourceUsageScore:             0,
+				WeightedScore:                  14,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score := &ClusterScore{
+				TopologySpreadScore:            -2,
+				AffinityScore:                  10,
+				ObsoletePlacementAffinityScore: 1,
+				ResourceUsageScore:             40,
+			}
+			score.ApplyWeights(tc.weights)
+			if diff := cmp.Diff(tc.want, score); diff != "" {
+				t.Errorf("ApplyWeights() diff (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
 func TestClusterScoreLessWhenEqual(t *testing.T) {
 	s1 := &ClusterScore{
 		TopologySpreadScore:            0,
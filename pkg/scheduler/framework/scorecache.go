@@ -0,0 +1,149 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/metrics"
+)
+
+const (
+	// defaultScoreCacheSize is the default maximum number of scores kept in the score cache.
+	defaultScoreCacheSize = 100000
+)
+
+// scoreCacheKey identifies the score a plugin gives to a cluster for a scheduling policy.
+type scoreCacheKey struct {
+	plugin     string
+	policyHash string
+	cluster    string
+}
+
+// scoreCacheEntry is a cached score, along with the generation of the cluster state it is computed with.
+type scoreCacheEntry struct {
+	clusterStateGeneration uint64
+	score                  ClusterScore
+}
+
+// scoreCache caches the scores of the cacheable score plugins across scheduling cycles, keyed by the hash of the
+// scheduling policy and the generation of the cluster state, i.e., the labels and the properties of the cluster.
+// As the scores of these plugins depend on nothing else, re-scheduling many placements after a minor change of a
+// cluster only recomputes the scores for that cluster.
+//
+// A nil scoreCache caches nothing.
+type scoreCache struct {
+	mu      sync.Mutex
+	maxSize int
+	entries map[scoreCacheKey]scoreCacheEntry
+}
+
+// newScoreCache returns a score cache which keeps at most maxSize scores; it returns nil, i.e., the scores are not
+// cached, if maxSize is not positive.
+func newScoreCache(maxSize int) *scoreCache {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &scoreCache{
+		maxSize: maxSize,
+		entries: make(map[scoreCacheKey]scoreCacheEntry),
+	}
+}
+
+// get returns the cached score for the key if it is computed with the given generation of the cluster state.
+func (c *scoreCache) get(key scoreCacheKey, clusterStateGeneration uint64) (*ClusterScore, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+	if !found || entry.clusterStateGeneration != clusterStateGeneration {
+		metrics.SchedulerScoreCacheMisses.WithLabelValues(key.plugin).Inc()
+		return nil, false
+	}
+	metrics.SchedulerScoreCacheHits.WithLabelValues(key.plugin).Inc()
+	score := entry.score
+	return &score, true
+}
+
+// set caches the score for the key, replacing the score computed with an older generation of the cluster state.
+func (c *scoreCache) set(key scoreCacheKey, clusterStateGeneration uint64, score *ClusterScore) {
+	if c == nil || score == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.entries[key]; !found && len(c.entries) >= c.maxSize {
+		// Drop all the scores when the cache is full; the scores of the policies which are gone would otherwise
+		// stay in the cache forever.
+		c.entries = make(map[scoreCacheKey]scoreCacheEntry, len(c.entries))
+	}
+	c.entries[key] = scoreCacheEntry{
+		clusterStateGeneration: clusterStateGeneration,
+		score:                  *score,
+	}
+}
+
+// policyHashOf returns the hash of the scheduling policy in the policy snapshot.
+func policyHashOf(policy *placementv1beta1.ClusterSchedulingPolicySnapshot) string {
+	return string(policy.Spec.PolicyHash)
+}
+
+// clusterStateGenerationOf returns the generation of the state of a cluster that the cacheable score plugins
+// depend on, i.e., the hash of its labels, its non-resource properties and its resource usage. Unlike the resource
+// version of the cluster, it does not change with the heartbeats and the observation times of the properties.
+func clusterStateGenerationOf(cluster *clusterv1beta1.MemberCluster) uint64 {
+	h := fnv.New64a()
+	writeString := func(s string) {
+		_ = binary.Write(h, binary.BigEndian, uint64(len(s)))
+		_, _ = h.Write([]byte(s))
+	}
+	writeSorted := func(m map[string]string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		_ = binary.Write(h, binary.BigEndian, uint64(len(keys)))
+		for _, k := range keys {
+			writeString(k)
+			writeString(m[k])
+		}
+	}
+
+	writeSorted(cluster.Labels)
+	properties := make(map[string]string, len(cluster.Status.Properties))
+	for name, p := range cluster.Status.Properties {
+		properties[string(name)] = p.Value
+	}
+	writeSorted(properties)
+	usage := cluster.Status.ResourceUsage
+	for _, rl := range []map[string]string{
+		quantitiesOf(usage.Capacity),
+		quantitiesOf(usage.Allocatable),
+		quantitiesOf(usage.Available),
+	} {
+		writeSorted(rl)
+	}
+	return h.Sum64()
+}
+
+// quantitiesOf returns the string forms of the quantities in a resource list.
+func quantitiesOf(rl corev1.ResourceList) map[string]string {
+	m := make(map[string]string, len(rl))
+	for name, q := range rl {
+		m[string(name)] = q.String()
+	}
+	return m
+}
@@ -0,0 +1,162 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// cacheableDummyScorePlugin is a dummy score plugin whose scores can be cached.
+type cacheableDummyScorePlugin struct {
+	DummyAllPurposePlugin
+	cacheable bool
+}
+
+// ScoreCacheable implements the CacheableScorePlugin interface.
+func (p *cacheableDummyScorePlugin) ScoreCacheable(_ *placementv1beta1.ClusterSchedulingPolicySnapshot) bool {
+	return p.cacheable
+}
+
+func TestScoreCache(t *testing.T) {
+	key := scoreCacheKey{plugin: "plugin", policyHash: "hash", cluster: clusterName}
+	otherKey := scoreCacheKey{plugin: "plugin", policyHash: "hash", cluster: altClusterName}
+	c := newScoreCache(1)
+
+	if _, found := c.get(key, 1); found {
+		t.Fatalf("get() on an empty cache found a score, want none")
+	}
+	c.set(key, 1, &ClusterScore{AffinityScore: 10})
+	score, found := c.get(key, 1)
+	if !found {
+		t.Fatalf("get() found no score, want the cached one")
+	}
+	if diff := cmp.Diff(&ClusterScore{AffinityScore: 10}, score); diff != "" {
+		t.Errorf("get() score mismatch (-want, +got):\n%s", diff)
+	}
+	if _, found := c.get(key, 2); found {
+		t.Errorf("get() with a new cluster state generation found a score, want none")
+	}
+
+	// The cache is full; adding another score drops the cached ones.
+	c.set(otherKey, 1, &ClusterScore{AffinityScore: 20})
+	if _, found := c.get(key, 1); found {
+		t.Errorf("get() found a score dropped from the full cache, want none")
+	}
+	if _, found := c.get(otherKey, 1); !found {
+		t.Errorf("get() found no score, want the latest cached one")
+	}
+
+	var nilCache *scoreCache
+	nilCache.set(key, 1, &ClusterScore{AffinityScore: 10})
+	if _, found := nilCache.get(key, 1); found {
+		t.Errorf("get() on a nil cache found a score, want none")
+	}
+	if got := newScoreCache(0); got != nil {
+		t.Errorf("newScoreCache(0) = %v, want nil", got)
+	}
+}
+
+func TestClusterStateGenerationOf(t *testing.T) {
+	cluster := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   clusterName,
+			Labels: map[string]string{"region": "eastus"},
+		},
+		Status: clusterv1beta1.MemberClusterStatus{
+			Properties: map[clusterv1beta1.PropertyName]clusterv1beta1.PropertyValue{
+				"node-count": {Value: "3", ObservationTime: metav1.NewTime(time.Now())},
+			},
+			ResourceUsage: clusterv1beta1.ResourceUsage{
+				Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			},
+		},
+	}
+	generation := clusterStateGenerationOf(cluster)
+
+	heartbeat := cluster.DeepCopy()
+	heartbeat.ResourceVersion = "2"
+	heartbeat.Status.Properties["node-count"] = clusterv1beta1.PropertyValue{Value: "3", ObservationTime: metav1.NewTime(time.Now().Add(time.Minute))}
+	heartbeat.Status.ResourceUsage.ObservationTime = metav1.NewTime(time.Now().Add(time.Minute))
+	if got := clusterStateGenerationOf(heartbeat); got != generation {
+		t.Errorf("clusterStateGenerationOf() changed with the observation times only")
+	}
+
+	relabeled := cluster.DeepCopy()
+	relabeled.Labels["region"] = "westus"
+	if got := clusterStateGenerationOf(relabeled); got == generation {
+		t.Errorf("clusterStateGenerationOf() did not change with the labels")
+	}
+
+	scaled := cluster.DeepCopy()
+	scaled.Status.ResourceUsage.Available[corev1.ResourceCPU] = resource.MustParse("2")
+	if got := clusterStateGenerationOf(scaled); got == generation {
+		t.Errorf("clusterStateGenerationOf() did not change with the resource usage")
+	}
+}
+
+func TestRunScorePluginsFor_ScoreCache(t *testing.T) {
+	scoreCalls := map[string]int{}
+	newPlugin := func(name string, cacheable bool) *cacheableDummyScorePlugin {
+		return &cacheableDummyScorePlugin{
+			DummyAllPurposePlugin: DummyAllPurposePlugin{
+				name: name,
+				scoreRunner: func(_ context.Context, _ CycleStatePluginReadWriter, _ *placementv1beta1.ClusterSchedulingPolicySnapshot, cluster *clusterv1beta1.MemberCluster) (*ClusterScore, *Status) {
+					scoreCalls[name]++
+					return &ClusterScore{AffinityScore: int(cluster.Generation)}, nil
+				},
+			},
+			cacheable: cacheable,
+		}
+	}
+	profile := NewProfile(dummyProfileName)
+	profile.WithScorePlugin(newPlugin("cacheable", true))
+	profile.WithScorePlugin(newPlugin("uncacheable", false))
+	f := &framework{
+		profile:    profile,
+		scoreCache: newScoreCache(defaultScoreCacheSize),
+	}
+
+	policy := &placementv1beta1.ClusterSchedulingPolicySnapshot{
+		Spec: placementv1beta1.SchedulingPolicySnapshotSpec{PolicyHash: []byte("hash")},
+	}
+	cluster := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName, Generation: 1, Labels: map[string]string{"region": "eastus"}},
+	}
+	run := func(cluster *clusterv1beta1.MemberCluster, wantScore int) {
+		state := NewCycleState([]clusterv1beta1.MemberCluster{}, []*placementv1beta1.ClusterResourceBinding{})
+		scoreList, status := f.runScorePluginsFor(context.Background(), state, policy, cluster)
+		if !status.IsSuccess() {
+			t.Fatalf("runScorePluginsFor() = %v, want success", status)
+		}
+		if got := scoreList["cacheable"].AffinityScore; got != wantScore {
+			t.Errorf("runScorePluginsFor() cacheable score = %d, want %d", got, wantScore)
+		}
+	}
+
+	run(cluster, 1)
+	// The cacheable score is served from the cache, even though the cluster spec has changed.
+	updated := cluster.DeepCopy()
+	updated.Generation = 2
+	run(updated, 1)
+	// A label change invalidates the cached score.
+	updated.Labels["region"] = "westus"
+	run(updated, 2)
+
+	wantScoreCalls := map[string]int{"cacheable": 2, "uncacheable": 3}
+	if diff := cmp.Diff(wantScoreCalls, scoreCalls); diff != "" {
+		t.Errorf("score calls mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"go.goms.io/fleet/pkg/scheduler/framework"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/clusteraffinity"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/clustereligibility"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/networkproximity"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/resourcerequirements"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/resourceusage"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/sameplacementaffinity"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/tainttoleration"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/tenantquota"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/topologyspreadconstraints"
+)
+
+// Config is the config of the scheduler profiles which the placements can select by name, besides the default
+// profile.
+type Config struct {
+	// Profiles are the scheduler profiles.
+	Profiles []framework.ProfileConfig `json:"profiles"`
+}
+
+// NewRegistry returns the registry of the in-tree plugins, keyed by their default names.
+func NewRegistry(opts Options) framework.Registry {
+	factories := []framework.PluginFactory{
+		func() framework.Plugin { p := clusteraffinity.New(); return &p },
+		func() framework.Plugin { p := clustereligibility.New(); return &p },
+		func() framework.Plugin { p := sameplacementaffinity.New(); return &p },
+		func() framework.Plugin { p := topologyspreadconstraints.New(); return &p },
+		func() framework.Plugin { p := tainttoleration.New(); return &p },
+		func() framework.Plugin { p := resourcerequirements.New(); return &p },
+		func() framework.Plugin { p := tenantquota.New(); return &p },
+		func() framework.Plugin { p := resourceusage.New(); return &p },
+		func() framework.Plugin {
+			p := networkproximity.New(networkproximity.WithHubTopology(opts.HubRegion, opts.HubZone))
+			return &p
+		},
+	}
+	registry := make(framework.Registry, len(factories))
+	for _, factory := range factories {
+		registry[factory().Name()] = factory
+	}
+	return registry
+}
+
+// requiredPlugins are the plugins which every profile must enable, as without them the scheduler could pick the
+// clusters which cannot take any placement, or pick a cluster for a placement twice.
+func requiredPlugins() sets.Set[string] {
+	eligibility := clustereligibility.New()
+	samePlacement := sameplacementaffinity.New()
+	return sets.New(eligibility.Name(), samePlacement.Name())
+}
+
+// LoadConfig reads the config of the scheduler profiles from a YAML or JSON file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the scheduler profiles config: %w", err)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the scheduler profiles config: %w", err)
+	}
+	return config, nil
+}
+
+// NewProfiles builds the scheduler profiles of the config with the plugins in the registry.
+func NewProfiles(config *Config, registry framework.Registry) ([]*framework.Profile, error) {
+	names := sets.New(defaultProfileName)
+	required := requiredPlugins()
+	profiles := make([]*framework.Profile, 0, len(config.Profiles))
+	for _, profileConfig := range config.Profiles {
+		if names.Has(profileConfig.Name) {
+			return nil, fmt.Errorf("profile %s is defined more than once", profileConfig.Name)
+		}
+		names.Insert(profileConfig.Name)
+
+		enabled := sets.New[string]()
+		for _, pluginConfig := range profileConfig.Plugins {
+			enabled.Insert(pluginConfig.Name)
+		}
+		if missing := required.Difference(enabled); missing.Len() > 0 {
+			return nil, fmt.Errorf("profile %s must enable plugins %v", profileConfig.Name, sets.List(missing))
+		}
+
+		profile, err := registry.NewProfile(profileConfig)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
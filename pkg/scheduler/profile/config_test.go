@@ -0,0 +1,112 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.goms.io/fleet/pkg/scheduler/framework"
+)
+
+func TestLoadConfigAndNewProfiles(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    string
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name: "valid profiles",
+			config: `
+profiles:
+- name: least-loaded
+  plugins:
+  - name: ClusterAffinity
+  - name: ClusterEligibility
+  - name: SamePlacementAntiAffinity
+  - name: TaintToleration
+  - name: ResourceUsage
+    weight: 2
+- name: minimal
+  plugins:
+  - name: ClusterEligibility
+  - name: SamePlacementAntiAffinity
+`,
+			wantNames: []string{"least-loaded", "minimal"},
+		},
+		{
+			name: "missing required plugins",
+			config: `
+profiles:
+- name: no-eligibility
+  plugins:
+  - name: SamePlacementAntiAffinity
+`,
+			wantErr: "must enable plugins [ClusterEligibility]",
+		},
+		{
+			name: "duplicate profiles",
+			config: `
+profiles:
+- name: minimal
+  plugins: [{name: ClusterEligibility}, {name: SamePlacementAntiAffinity}]
+- name: minimal
+  plugins: [{name: ClusterEligibility}, {name: SamePlacementAntiAffinity}]
+`,
+			wantErr: "defined more than once",
+		},
+		{
+			name: "default profile name",
+			config: `
+profiles:
+- name: DefaultProfile
+  plugins: [{name: ClusterEligibility}, {name: SamePlacementAntiAffinity}]
+`,
+			wantErr: "defined more than once",
+		},
+		{
+			name: "unknown field",
+			config: `
+profiles:
+- name: minimal
+  plugin: [{name: ClusterEligibility}]
+`,
+			wantErr: "failed to parse",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.yaml")
+			if err := os.WriteFile(path, []byte(tc.config), 0600); err != nil {
+				t.Fatalf("WriteFile() = %v, want no error", err)
+			}
+			config, err := LoadConfig(path)
+			if err == nil {
+				var profiles []*framework.Profile
+				profiles, err = NewProfiles(config, NewRegistry(Options{}))
+				if err == nil {
+					if len(profiles) != len(tc.wantNames) {
+						t.Fatalf("NewProfiles() got %d profiles, want %d", len(profiles), len(tc.wantNames))
+					}
+					for i, p := range profiles {
+						if p.Name() != tc.wantNames[i] {
+							t.Errorf("NewProfiles()[%d] name = %s, want %s", i, p.Name(), tc.wantNames[i])
+						}
+					}
+				}
+			}
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("LoadConfig() and NewProfiles() = %v, want no error", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Fatalf("LoadConfig() and NewProfiles() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
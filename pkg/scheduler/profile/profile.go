@@ -10,8 +10,12 @@ import (
 	"go.goms.io/fleet/pkg/scheduler/framework"
 	"go.goms.io/fleet/pkg/scheduler/framework/plugins/clusteraffinity"
 	"go.goms.io/fleet/pkg/scheduler/framework/plugins/clustereligibility"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/networkproximity"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/resourcerequirements"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/resourceusage"
 	"go.goms.io/fleet/pkg/scheduler/framework/plugins/sameplacementaffinity"
 	"go.goms.io/fleet/pkg/scheduler/framework/plugins/tainttoleration"
+	"go.goms.io/fleet/pkg/scheduler/framework/plugins/tenantquota"
 	"go.goms.io/fleet/pkg/scheduler/framework/plugins/topologyspreadconstraints"
 )
 
@@ -20,8 +24,16 @@ const (
 	defaultProfileName = "DefaultProfile"
 )
 
+// Options are the options of the in-tree plugins which the scheduling profiles are built with.
+type Options struct {
+	// HubRegion and HubZone are the region and zone of the hub cluster, which the network proximity plugin prefers
+	// the clusters close to if a placement does not name an anchor cluster.
+	HubRegion string
+	HubZone   string
+}
+
 // NewDefaultProfile creates a default scheduling profile.
-func NewDefaultProfile() *framework.Profile {
+func NewDefaultProfile(opts Options) *framework.Profile {
 	p := framework.NewProfile(defaultProfileName)
 
 	// default plugin list
@@ -30,11 +42,15 @@ func NewDefaultProfile() *framework.Profile {
 	samePlacementAffinityPlugin := sameplacementaffinity.New()
 	topologySpreadConstraintsPlugin := topologyspreadconstraints.New()
 	taintTolerationPlugin := tainttoleration.New()
+	resourceRequirementsPlugin := resourcerequirements.New()
+	tenantQuotaPlugin := tenantquota.New()
+	resourceUsagePlugin := resourceusage.New()
+	networkProximityPlugin := networkproximity.New(networkproximity.WithHubTopology(opts.HubRegion, opts.HubZone))
 
 	p.WithPostBatchPlugin(&topologySpreadConstraintsPlugin).
-		WithPreFilterPlugin(&clusterAffinityPlugin).WithPreFilterPlugin(&topologySpreadConstraintsPlugin).
-		WithFilterPlugin(&clusterAffinityPlugin).WithFilterPlugin(&clusterEligibilityPlugin).WithFilterPlugin(&taintTolerationPlugin).WithFilterPlugin(&samePlacementAffinityPlugin).WithFilterPlugin(&topologySpreadConstraintsPlugin).
-		WithPreScorePlugin(&clusterAffinityPlugin).WithPreScorePlugin(&topologySpreadConstraintsPlugin).
-		WithScorePlugin(&clusterAffinityPlugin).WithScorePlugin(&samePlacementAffinityPlugin).WithScorePlugin(&topologySpreadConstraintsPlugin)
+		WithPreFilterPlugin(&clusterAffinityPlugin).WithPreFilterPlugin(&topologySpreadConstraintsPlugin).WithPreFilterPlugin(&resourceRequirementsPlugin).WithPreFilterPlugin(&tenantQuotaPlugin).
+		WithFilterPlugin(&clusterAffinityPlugin).WithFilterPlugin(&clusterEligibilityPlugin).WithFilterPlugin(&taintTolerationPlugin).WithFilterPlugin(&samePlacementAffinityPlugin).WithFilterPlugin(&topologySpreadConstraintsPlugin).WithFilterPlugin(&resourceRequirementsPlugin).WithFilterPlugin(&tenantQuotaPlugin).
+		WithPreScorePlugin(&clusterAffinityPlugin).WithPreScorePlugin(&topologySpreadConstraintsPlugin).WithPreScorePlugin(&networkProximityPlugin).
+		WithScorePlugin(&clusterAffinityPlugin).WithScorePlugin(&samePlacementAffinityPlugin).WithScorePlugin(&topologySpreadConstraintsPlugin).WithScorePlugin(&resourceUsagePlugin).WithScorePlugin(&networkProximityPlugin)
 	return p
 }
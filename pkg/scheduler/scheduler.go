@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -27,21 +29,32 @@ import (
 	"go.goms.io/fleet/pkg/metrics"
 	"go.goms.io/fleet/pkg/scheduler/framework"
 	"go.goms.io/fleet/pkg/scheduler/queue"
+	"go.goms.io/fleet/pkg/sharding"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/annotations"
+	"go.goms.io/fleet/pkg/utils/condition"
 	"go.goms.io/fleet/pkg/utils/controller"
 )
 
+const (
+	// schedulerProfileNotFoundReason is the reason of the Scheduled condition of a policy snapshot
+	// which selects a scheduler profile that is not configured.
+	schedulerProfileNotFoundReason = "SchedulerProfileNotFound"
+)
+
 // Scheduler is the scheduler for Fleet workloads.
 type Scheduler struct {
 	// name is the name of the scheduler.
 	name string
 
-	// framework is the scheduling framework in use by the scheduler.
-	//
-	// At this stage, a scheduler is always associated with one scheduling framework; in the long
-	// run, multiple frameworks may be supported, to allow the usage of varying scheduling configurations
-	// for different types of workloads.
+	// framework is the scheduling framework in use by the scheduler for the placements which do not
+	// select a scheduler profile.
 	framework framework.Framework
 
+	// profileFrameworks are the scheduling frameworks of the scheduler profiles which the placements
+	// can select by name, keyed by the profile names.
+	profileFrameworks map[string]framework.Framework
+
 	// queue is the work queue in use by the scheduler; the scheduler pulls items from the queue and
 	// performs scheduling in accordance with them.
 	queue queue.ClusterResourcePlacementSchedulingQueue
@@ -64,6 +77,10 @@ type Scheduler struct {
 
 	// eventRecorder is the event recorder in use by the scheduler.
 	eventRecorder record.EventRecorder
+
+	// sharder shards the placements across the replicas of the hub agent if set; the scheduler skips the placements
+	// of the other replicas.
+	sharder *sharding.Sharder
 }
 
 // NewScheduler creates a scheduler.
@@ -73,6 +90,7 @@ func NewScheduler(
 	queue queue.ClusterResourcePlacementSchedulingQueue,
 	manager ctrl.Manager,
 	workerNumber int,
+	sharder *sharding.Sharder,
 ) *Scheduler {
 	return &Scheduler{
 		name:           name,
@@ -83,7 +101,28 @@ func NewScheduler(
 		manager:        manager,
 		workerNumber:   workerNumber,
 		eventRecorder:  manager.GetEventRecorderFor(name),
+		sharder:        sharder,
+	}
+}
+
+// WithProfileFrameworks sets the scheduling frameworks of the scheduler profiles which the placements
+// can select by name, keyed by the profile names.
+func (s *Scheduler) WithProfileFrameworks(frameworks map[string]framework.Framework) *Scheduler {
+	s.profileFrameworks = frameworks
+	return s
+}
+
+// frameworkFor returns the scheduling framework of the scheduler profile which a policy snapshot
+// selects, or false if the profile is not configured.
+//
+// Note that the profile is read from the policy snapshot rather than the CRP, so that a change of
+// the profile takes effect along with a new policy snapshot.
+func (s *Scheduler) frameworkFor(policy *fleetv1beta1.ClusterSchedulingPolicySnapshot) (framework.Framework, bool) {
+	if policy.Spec.Policy == nil || policy.Spec.Policy.SchedulerProfile == "" {
+		return s.framework, true
 	}
+	f, found := s.profileFrameworks[policy.Spec.Policy.SchedulerProfile]
+	return f, found
 }
 
 // ScheduleOnce performs scheduling for one single item pulled from the work queue.
@@ -143,6 +182,13 @@ func (s *Scheduler) scheduleOnce(ctx context.Context, worker int) {
 		return
 	}
 
+	// Skip the CRP if it is owned by another replica; the replica schedules it instead.
+	if !s.sharder.OwnsPlacement(crp) {
+		klog.V(2).InfoS("Skipping the cluster resource placement owned by another shard", "clusterResourcePlacement", crpRef)
+		s.queue.Forget(crpName)
+		return
+	}
+
 	// Check if the CRP has been marked for deletion, and if it has the scheduler cleanup finalizer.
 	if crp.DeletionTimestamp != nil {
 		if controllerutil.ContainsFinalizer(crp, fleetv1beta1.SchedulerCRPCleanupFinalizer) {
@@ -161,6 +207,17 @@ func (s *Scheduler) scheduleOnce(ctx context.Context, worker int) {
 		return
 	}
 
+	// Skip the CRP if it still has scheduling gates; the CRP watcher enqueues it again once all the gates
+	// are removed.
+	//
+	// Note that the scheduling gates can only be set when the CRP is created, so there are no bindings
+	// to clean up for a gated CRP.
+	if len(crp.Spec.SchedulingGates) > 0 {
+		klog.V(2).InfoS("Skipping the cluster resource placement with scheduling gates", "clusterResourcePlacement", crpRef, "schedulingGates", crp.Spec.SchedulingGates)
+		s.queue.Forget(crpName)
+		return
+	}
+
 	// The CRP has not been marked for deletion; run the scheduling cycle for it.
 
 	// Verify that it has an active policy snapshot.
@@ -183,12 +240,27 @@ func (s *Scheduler) scheduleOnce(ctx context.Context, worker int) {
 		return
 	}
 
+	// Find the scheduling framework of the scheduler profile that the CRP selects.
+	f, found := s.frameworkFor(latestPolicySnapshot)
+	if !found {
+		if err := s.markSchedulerProfileNotFound(ctx, latestPolicySnapshot); err != nil {
+			klog.ErrorS(err, "Failed to report the unknown scheduler profile", "clusterResourcePlacement", crpRef)
+			// Requeue for later processing.
+			s.queue.AddRateLimited(crpName)
+			return
+		}
+		// No requeue is needed; the scheduler will be triggered again when the CRP selects another
+		// profile, which produces a new policy snapshot, or when the hub agent restarts with the profile.
+		s.queue.Forget(crpName)
+		return
+	}
+
 	// Run the scheduling cycle.
 	//
 	// Note that the scheduler will enter this cycle as long as the CRP is active and an active
 	// policy snapshot has been produced.
 	cycleStartTime := time.Now()
-	res, err := s.framework.RunSchedulingCycleFor(ctx, crp.Name, latestPolicySnapshot)
+	res, err := f.RunSchedulingCycleFor(ctx, crp.Name, latestPolicySnapshot)
 	if err != nil {
 		klog.ErrorS(err, "Failed to run scheduling cycle", "clusterResourcePlacement", crpRef)
 		// Requeue for later processing.
@@ -268,6 +340,14 @@ func (s *Scheduler) Run(ctx context.Context) {
 func (s *Scheduler) cleanUpAllBindingsFor(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement) error {
 	crpRef := klog.KObj(crp)
 
+	// The bindings of a CRP with the ReverseOrder deletion strategy are deleted in waves by the CRP controller,
+	// which holds its own cleanup finalizer on the CRP until all the bindings are gone; the scheduler leaves them
+	// alone.
+	if utils.IsReverseOrderDeletion(crp) {
+		klog.V(2).InfoS("Leaving the bindings to be deleted in waves by the cluster resource placement controller", "clusterResourcePlacement", crpRef)
+		return s.removeSchedulerCleanUpFinalizer(ctx, crp)
+	}
+
 	// List all bindings derived from the CRP.
 	//
 	// Note that the listing is performed using the uncached client; this is to ensure that all related
@@ -304,9 +384,14 @@ func (s *Scheduler) cleanUpAllBindingsFor(ctx context.Context, crp *fleetv1beta1
 	}
 
 	// All bindings have been deleted; remove the scheduler cleanup finalizer from the CRP.
+	return s.removeSchedulerCleanUpFinalizer(ctx, crp)
+}
+
+// removeSchedulerCleanUpFinalizer removes the scheduler cleanup finalizer from a CRP.
+func (s *Scheduler) removeSchedulerCleanUpFinalizer(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement) error {
 	controllerutil.RemoveFinalizer(crp, fleetv1beta1.SchedulerCRPCleanupFinalizer)
 	if err := s.client.Update(ctx, crp); err != nil {
-		klog.ErrorS(err, "Failed to remove scheduler cleanup finalizer from cluster resource placement", "clusterResourcePlacement", crpRef)
+		klog.ErrorS(err, "Failed to remove scheduler cleanup finalizer from cluster resource placement", "clusterResourcePlacement", klog.KObj(crp))
 		return controller.NewUpdateIgnoreConflictError(err)
 	}
 
@@ -375,6 +460,39 @@ func (s *Scheduler) addSchedulerCleanUpFinalizer(ctx context.Context, crp *fleet
 	return nil
 }
 
+// markSchedulerProfileNotFound reports on a policy snapshot that it cannot be scheduled, as the
+// scheduler profile it selects is not configured with the scheduler.
+func (s *Scheduler) markSchedulerProfileNotFound(ctx context.Context, policy *fleetv1beta1.ClusterSchedulingPolicySnapshot) error {
+	policyRef := klog.KObj(policy)
+	profileName := policy.Spec.Policy.SchedulerProfile
+	klog.ErrorS(controller.NewUserError(fmt.Errorf("scheduler profile %s is not found", profileName)),
+		"The cluster resource placement selects an unknown scheduler profile", "clusterSchedulingPolicySnapshot", policyRef)
+
+	observedCRPGeneration, err := annotations.ExtractObservedCRPGenerationFromPolicySnapshot(policy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to retrieve CRP generation from annotation", "clusterSchedulingPolicySnapshot", policyRef)
+		return controller.NewUnexpectedBehaviorError(err)
+	}
+	newCondition := metav1.Condition{
+		Type:               string(fleetv1beta1.PolicySnapshotScheduled),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: policy.Generation,
+		Reason:             schedulerProfileNotFoundReason,
+		Message:            fmt.Sprintf("The scheduler profile %s is not configured with the scheduler", profileName),
+	}
+	currentCondition := meta.FindStatusCondition(policy.Status.Conditions, string(fleetv1beta1.PolicySnapshotScheduled))
+	if observedCRPGeneration == policy.Status.ObservedCRPGeneration && condition.EqualCondition(currentCondition, &newCondition) {
+		return nil
+	}
+	policy.Status.ObservedCRPGeneration = observedCRPGeneration
+	meta.SetStatusCondition(&policy.Status.Conditions, newCondition)
+	if err := s.client.Status().Update(ctx, policy); err != nil {
+		klog.ErrorS(err, "Failed to update policy snapshot status", "clusterSchedulingPolicySnapshot", policyRef)
+		return controller.NewUpdateIgnoreConflictError(err)
+	}
+	return nil
+}
+
 // observeSchedulingCycleMetrics adds a data point to the scheduling cycle duration metric.
 func observeSchedulingCycleMetrics(startTime time.Time, isFailed, needsRequeue bool) {
 	metrics.SchedulingCycleDurationMilliseconds.
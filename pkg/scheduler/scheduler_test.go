@@ -24,6 +24,7 @@ import (
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/metrics"
+	"go.goms.io/fleet/pkg/scheduler/framework"
 )
 
 const (
@@ -122,6 +123,59 @@ func TestCleanUpAllBindingsFor(t *testing.T) {
 	}
 }
 
+// TestCleanUpAllBindingsForReverseOrderDeletion tests the cleanUpAllBindingsFor method with a CRP whose bindings are
+// deleted in waves by the CRP controller.
+func TestCleanUpAllBindingsForReverseOrderDeletion(t *testing.T) {
+	now := metav1.Now()
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              crpName,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{fleetv1beta1.SchedulerCRPCleanupFinalizer, fleetv1beta1.ClusterResourcePlacementCleanupFinalizer},
+		},
+		Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+			Strategy: fleetv1beta1.RolloutStrategy{
+				DeletionStrategy: &fleetv1beta1.DeletionStrategy{Type: fleetv1beta1.DeletionStrategyTypeReverseOrder},
+			},
+		},
+	}
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: bindingName,
+			Labels: map[string]string{
+				fleetv1beta1.CRPTrackingLabel: crpName,
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(crp, binding).
+		Build()
+	s := &Scheduler{
+		client:         fakeClient,
+		uncachedReader: fakeClient,
+	}
+
+	ctx := context.Background()
+	if err := s.cleanUpAllBindingsFor(ctx, crp); err != nil {
+		t.Fatalf("cleanUpAllBindingsFor() = %v, want no error", err)
+	}
+
+	updatedCRP := &fleetv1beta1.ClusterResourcePlacement{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: crpName}, updatedCRP); err != nil {
+		t.Fatalf("Get() CRP = %v, want no error", err)
+	}
+	if diff := cmp.Diff([]string{fleetv1beta1.ClusterResourcePlacementCleanupFinalizer}, updatedCRP.Finalizers); diff != "" {
+		t.Errorf("CRP finalizers diff (-want, +got): %s", diff)
+	}
+
+	// the binding is left to the CRP controller
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: bindingName}, &fleetv1beta1.ClusterResourceBinding{}); err != nil {
+		t.Errorf("Get() binding = %v, want no error", err)
+	}
+}
+
 // TestLookupLatestPolicySnapshot tests the lookupLatestPolicySnapshot method.
 func TestLookupLatestPolicySnapshot(t *testing.T) {
 	crp := &fleetv1beta1.ClusterResourcePlacement{
@@ -277,6 +331,93 @@ func TestAddSchedulerCleanUpFinalizer(t *testing.T) {
 	}
 }
 
+// TestFrameworkFor tests the frameworkFor method.
+func TestFrameworkFor(t *testing.T) {
+	s := &Scheduler{
+		profileFrameworks: map[string]framework.Framework{"least-loaded": nil},
+	}
+	testCases := []struct {
+		name      string
+		policy    *fleetv1beta1.PlacementPolicy
+		wantFound bool
+	}{
+		{
+			name:      "no policy",
+			wantFound: true,
+		},
+		{
+			name:      "default profile",
+			policy:    &fleetv1beta1.PlacementPolicy{PlacementType: fleetv1beta1.PickAllPlacementType},
+			wantFound: true,
+		},
+		{
+			name:      "configured profile",
+			policy:    &fleetv1beta1.PlacementPolicy{SchedulerProfile: "least-loaded"},
+			wantFound: true,
+		},
+		{
+			name:   "unknown profile",
+			policy: &fleetv1beta1.PlacementPolicy{SchedulerProfile: "unknown"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &fleetv1beta1.ClusterSchedulingPolicySnapshot{Spec: fleetv1beta1.SchedulingPolicySnapshotSpec{Policy: tc.policy}}
+			if _, found := s.frameworkFor(policy); found != tc.wantFound {
+				t.Errorf("frameworkFor() found = %t, want %t", found, tc.wantFound)
+			}
+		})
+	}
+}
+
+// TestMarkSchedulerProfileNotFound tests the markSchedulerProfileNotFound method.
+func TestMarkSchedulerProfileNotFound(t *testing.T) {
+	policySnapshot := &fleetv1beta1.ClusterSchedulingPolicySnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        policySnapshotName,
+			Generation:  1,
+			Annotations: map[string]string{fleetv1beta1.CRPGenerationAnnotation: "2"},
+		},
+		Spec: fleetv1beta1.SchedulingPolicySnapshotSpec{
+			Policy: &fleetv1beta1.PlacementPolicy{SchedulerProfile: "unknown"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(policySnapshot).
+		WithStatusSubresource(policySnapshot).
+		Build()
+	s := &Scheduler{
+		client:         fakeClient,
+		uncachedReader: fakeClient,
+	}
+
+	ctx := context.Background()
+	if err := s.markSchedulerProfileNotFound(ctx, policySnapshot); err != nil {
+		t.Fatalf("markSchedulerProfileNotFound() = %v, want no error", err)
+	}
+
+	got := &fleetv1beta1.ClusterSchedulingPolicySnapshot{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: policySnapshotName}, got); err != nil {
+		t.Fatalf("Get() policy snapshot = %v, want no error", err)
+	}
+	wantStatus := fleetv1beta1.SchedulingPolicySnapshotStatus{
+		ObservedCRPGeneration: 2,
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(fleetv1beta1.PolicySnapshotScheduled),
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: 1,
+				Reason:             schedulerProfileNotFoundReason,
+				Message:            "The scheduler profile unknown is not configured with the scheduler",
+			},
+		},
+	}
+	if diff := cmp.Diff(got.Status, wantStatus, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("policy snapshot status diff (-got, +want): %s", diff)
+	}
+}
+
 func TestObserveSchedulingCycleMetrics(t *testing.T) {
 	metricMetadata := `
 		# HELP scheduling_cycle_duration_milliseconds The duration of a scheduling cycle run in milliseconds
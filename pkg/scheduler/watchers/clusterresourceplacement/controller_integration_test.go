@@ -31,6 +31,7 @@ const (
 const (
 	crpName        = "crp-1"
 	noFinalizerCRP = "crp-2"
+	gatedCRP       = "crp-3"
 )
 
 var (
@@ -226,4 +227,50 @@ var _ = Describe("scheduler cluster resource placement source controller", Seria
 			keyCollector.Reset()
 		})
 	})
+
+	Context("crp scheduling gates removed", func() {
+		BeforeAll(func() {
+			Consistently(noKeyEnqueuedActual, consistentlyDuration, consistentlyInterval).Should(Succeed(), "Workqueue is not empty")
+
+			crp := &fleetv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: gatedCRP,
+				},
+				Spec: fleetv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: resourceSelectors,
+					SchedulingGates: []fleetv1beta1.PlacementSchedulingGate{
+						{Name: "example.com/budget-approval"},
+						{Name: "example.com/security-review"},
+					},
+				},
+			}
+			Expect(hubClient.Create(ctx, crp)).Should(Succeed(), "Failed to create cluster resource placement")
+
+			crp.Spec.SchedulingGates = crp.Spec.SchedulingGates[1:]
+			Expect(hubClient.Update(ctx, crp)).Should(Succeed(), "Failed to update cluster resource placement")
+		})
+
+		It("should not enqueue the CRP when some scheduling gates remain", func() {
+			Consistently(noKeyEnqueuedActual, consistentlyDuration, consistentlyInterval).Should(Succeed(), "Workqueue is not empty")
+		})
+
+		It("should enqueue the CRP when the last scheduling gate is removed", func() {
+			crp := &fleetv1beta1.ClusterResourcePlacement{}
+			Expect(hubClient.Get(ctx, client.ObjectKey{Name: gatedCRP}, crp)).Should(Succeed(), "Failed to get cluster resource placement")
+			crp.Spec.SchedulingGates = nil
+			Expect(hubClient.Update(ctx, crp)).Should(Succeed(), "Failed to update cluster resource placement")
+
+			Eventually(func() error {
+				if isAllPresent, absentKeys := keyCollector.IsPresent(gatedCRP); !isAllPresent {
+					return fmt.Errorf("expected key(s) %v is not found", absentKeys)
+				}
+				return nil
+			}, eventuallyDuration, eventuallyInterval).Should(Succeed(), "Workqueue is empty")
+		})
+
+		AfterAll(func() {
+			Expect(hubClient.Delete(ctx, &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: gatedCRP}})).Should(Succeed(), "Failed to delete cluster resource placement")
+			keyCollector.Reset()
+		})
+	})
 })
@@ -4,7 +4,7 @@ Licensed under the MIT license.
 */
 
 // Package clusterresourceplacement features a controller that enqueues CRPs for the
-// scheduler to process where the CRP is marked for deletion.
+// scheduler to process where the CRP is marked for deletion, or its scheduling gates are all removed.
 package clusterresourceplacement
 
 import (
@@ -12,24 +12,31 @@ import (
 	"fmt"
 	"time"
 
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrloption "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/scheduler/queue"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils/controller"
 )
 
-// Reconciler reconciles the deletion of a CRP.
+// Reconciler reconciles the deletion and the removal of the scheduling gates of a CRP.
 type Reconciler struct {
 	// Client is the client the controller uses to access the hub cluster.
 	client.Client
 	// SchedulerWorkQueue is the workqueue in use by the scheduler.
 	SchedulerWorkQueue queue.ClusterResourcePlacementSchedulingQueueWriter
+	// Sharder shards the placements across the replicas of the hub agent if set; the watcher then runs on every
+	// replica, as the scheduler skips the placements of the other replicas.
+	Sharder *sharding.Sharder
 }
 
 // Reconcile reconciles the CRP.
@@ -54,6 +61,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		// The CRP has been deleted and still has the scheduler finalizer;
 		// enqueue it for the scheduler to process.
 		r.SchedulerWorkQueue.AddRateLimited(queue.ClusterResourcePlacementKey(crp.Name))
+		return ctrl.Result{}, nil
+	}
+
+	// Check if the CRP is active and has no scheduling gates left.
+	if crp.DeletionTimestamp == nil && len(crp.Spec.SchedulingGates) == 0 {
+		// The scheduling gates of the CRP have been removed; enqueue it for the scheduler
+		// to schedule.
+		r.SchedulerWorkQueue.Add(queue.ClusterResourcePlacementKey(crp.Name))
 	}
 
 	// No action is needed for the scheduler to take in other cases.
@@ -87,12 +102,28 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return true
 			}
 
+			// Check if the last scheduling gate has been removed.
+			oldCRP, oldOK := e.ObjectOld.(*fleetv1beta1.ClusterResourcePlacement)
+			newCRP, newOK := e.ObjectNew.(*fleetv1beta1.ClusterResourcePlacement)
+			if oldOK && newOK && len(oldCRP.Spec.SchedulingGates) > 0 && len(newCRP.Spec.SchedulingGates) == 0 {
+				return true
+			}
+
 			return false
 		},
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(ctrloption.Options{NeedLeaderElection: sharding.ControllerLeaderElection(r.Sharder)}).
 		For(&fleetv1beta1.ClusterResourcePlacement{}).
-		WithEventFilter(customPredicate).
-		Complete(r)
+		WithEventFilter(customPredicate)
+	if r.Sharder != nil {
+		// Schedule the CRPs that the replica takes over after a rebalance.
+		b = b.WatchesRawSource(r.Sharder.Subscribe(handler.Funcs{
+			GenericFunc: func(_ context.Context, e event.GenericEvent, _ workqueue.RateLimitingInterface) {
+				r.SchedulerWorkQueue.Add(queue.ClusterResourcePlacementKey(e.Object.GetName()))
+			},
+		}))
+	}
+	return b.Complete(r)
 }
@@ -16,11 +16,13 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrloption "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/scheduler/queue"
+	"go.goms.io/fleet/pkg/sharding"
 	"go.goms.io/fleet/pkg/utils/controller"
 )
 
@@ -30,6 +32,9 @@ type Reconciler struct {
 	client.Client
 	// SchedulerWorkQueue is the workqueue in use by the scheduler.
 	SchedulerWorkQueue queue.ClusterResourcePlacementSchedulingQueueWriter
+	// Sharder shards the placements across the replicas of the hub agent if set; the watcher then runs on every
+	// replica, as the scheduler skips the placements of the other replicas.
+	Sharder *sharding.Sharder
 }
 
 // Reconcile reconciles the cluster scheduling policy snapshot.
@@ -174,6 +179,7 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(ctrloption.Options{NeedLeaderElection: sharding.ControllerLeaderElection(r.Sharder)}).
 		For(&fleetv1beta1.ClusterSchedulingPolicySnapshot{}).
 		WithEventFilter(customPredicate).
 		Complete(r)
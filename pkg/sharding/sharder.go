@@ -0,0 +1,404 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package sharding features a sharder which splits the cluster resource placements across the replicas of the hub
+// agent, so that the scheduling, the rollout and the work generation of the placements scale beyond a single leader.
+package sharding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// memberLabel is the label of the leases with which the replicas of the hub agent announce that they are alive.
+	memberLabel = "kubernetes-fleet.io/hub-agent-shard-member"
+	// memberLeaseNamePrefix is the prefix of the names of the member leases.
+	memberLeaseNamePrefix = "hub-agent-member-"
+	// shardLeaseNamePrefix is the prefix of the names of the shard leases, which are suffixed with the shard index.
+	shardLeaseNamePrefix = "hub-agent-shard-"
+	// rebalanceBufferSize is the size of the channels through which the rebalanced placements are sent.
+	rebalanceBufferSize = 1024
+)
+
+// Sharder decides which replica of the hub agent owns a cluster resource placement. The placements are split into a
+// fixed number of shards: a placement labeled with kubernetes-fleet.io/shard=<n> belongs to the shard n modulo the
+// number of the shards, and the other placements are spread by the hash of their names. A replica owns the placements
+// of a shard only while it holds the lease of the shard, which it acquires and renews with the leader election of
+// client-go, so that each shard has at most one holder and the handoff of a shard waits for the previous holder to
+// release the lease or to let it expire.
+//
+// Every replica also renews a member lease in the fleet namespace to announce that it is alive. The shards are mapped
+// to the live replicas by the rendezvous hash, and a replica only contends for the shards mapped to it, so that only
+// the shards of the joining or leaving replica move when the replicas change. The member leases only decide which
+// replica contends for a shard; the replicas which see different members at the same time still cannot hold a shard
+// together.
+//
+// A nil Sharder owns all the placements.
+type Sharder struct {
+	// client reads the placements from the cache and writes the member lease of the replica.
+	client client.Client
+	// uncachedReader lists the member leases without caching all the leases of the hub.
+	uncachedReader client.Reader
+	// leaseClient acquires and renews the shard leases.
+	leaseClient   coordinationv1client.LeasesGetter
+	namespace     string
+	identity      string
+	shardCount    int
+	leaseDuration time.Duration
+
+	mu          sync.RWMutex
+	members     []string
+	held        map[int]bool
+	electors    map[int]*shardElector
+	subscribers []chan event.GenericEvent
+
+	now func() time.Time
+}
+
+// shardElector runs the leader election of a shard lease.
+type shardElector struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSharder returns a sharder which splits the placements into the shards and spreads the shards across the replicas
+// renewing leases in the namespace; the identity, e.g. the pod name, must be unique among the replicas.
+func NewSharder(client client.Client, uncachedReader client.Reader, leaseClient coordinationv1client.LeasesGetter, namespace, identity string, shardCount int, leaseDuration time.Duration) *Sharder {
+	return &Sharder{
+		client:         client,
+		uncachedReader: uncachedReader,
+		leaseClient:    leaseClient,
+		namespace:      namespace,
+		identity:       identity,
+		shardCount:     shardCount,
+		leaseDuration:  leaseDuration,
+		held:           map[int]bool{},
+		electors:       map[int]*shardElector{},
+		now:            time.Now,
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, as every replica renews its own lease.
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the member lease of the replica and contends for the shards mapped to the replica until the context
+// is done; the shard leases and the member lease are released on exit so that the other replicas take over the
+// shards without waiting for the leases to expire.
+func (s *Sharder) Start(ctx context.Context) error {
+	klog.V(2).InfoS("Starting the placement sharder", "identity", s.identity, "shardCount", s.shardCount, "leaseDuration", s.leaseDuration)
+	defer klog.V(2).InfoS("The placement sharder is stopped", "identity", s.identity)
+	ticker := time.NewTicker(s.leaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		if err := s.sync(ctx); err != nil {
+			klog.ErrorS(err, "Failed to sync the placement shards, will retry", "identity", s.identity)
+		}
+		select {
+		case <-ctx.Done():
+			s.stopElectors()
+			s.releaseLease()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Subscribe returns a source which sends the placements of the shards that the replica acquires to the handler. It
+// must be called before the sharder starts.
+func (s *Sharder) Subscribe(h handler.EventHandler) source.Source {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan event.GenericEvent, rebalanceBufferSize)
+	s.subscribers = append(s.subscribers, ch)
+	return source.Channel(ch, h)
+}
+
+// OwnsPlacement returns true if the replica holds the shard of the placement.
+func (s *Sharder) OwnsPlacement(crp client.Object) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.held[shardOf(s.shardCount, crp.GetName(), crp.GetLabels())]
+}
+
+// Owns returns true if the replica owns the placement of the name. The placement is read from the cache for its
+// shard label; a placement which is gone is sharded by its name only.
+func (s *Sharder) Owns(ctx context.Context, crpName string) bool {
+	if s == nil {
+		return true
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{}
+	if err := s.client.Get(ctx, types.NamespacedName{Name: crpName}, crp); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get the clusterResourcePlacement to find its shard", "clusterResourcePlacement", crpName)
+		}
+		crp.Name = crpName
+	}
+	return s.OwnsPlacement(crp)
+}
+
+// ControllerLeaderElection returns whether the controllers of the placements need the leader election: they run on
+// every replica when the placements are sharded, or follow the default of the manager otherwise.
+func ControllerLeaderElection(s *Sharder) *bool {
+	if s == nil {
+		return nil
+	}
+	return ptr.To(false)
+}
+
+// sync renews the member lease of the replica, updates the live replicas from the member leases, and starts or stops
+// contending for the shards as they are mapped to the replica or not.
+func (s *Sharder) sync(ctx context.Context) error {
+	if err := s.renewLease(ctx); err != nil {
+		return err
+	}
+	var leases coordinationv1.LeaseList
+	if err := s.uncachedReader.List(ctx, &leases, client.InNamespace(s.namespace), client.HasLabels{memberLabel}); err != nil {
+		return controller.NewAPIServerError(false, err)
+	}
+	members := liveMembers(leases.Items, s.now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Equal(s.members, members) {
+		klog.InfoS("The hub agent replicas changed, rebalancing the placement shards", "identity", s.identity, "members", members, "oldMembers", s.members)
+		s.members = members
+	}
+	for shard := 0; shard < s.shardCount; shard++ {
+		elector, running := s.electors[shard]
+		if running {
+			select {
+			case <-elector.done:
+				// the replica lost the lease of the shard, and contends for it again if the shard is still mapped to it
+				delete(s.electors, shard)
+				running = false
+			default:
+			}
+		}
+		mapped := ownerOf(members, shard) == s.identity
+		switch {
+		case mapped && !running:
+			s.startElector(ctx, shard)
+		case !mapped && running:
+			// stop owning the placements of the shard before its lease is released to the new owner
+			klog.V(2).InfoS("Handing off the placement shard", "shard", shard, "identity", s.identity)
+			s.held[shard] = false
+			elector.cancel()
+			delete(s.electors, shard)
+		}
+	}
+	return nil
+}
+
+// startElector starts contending for the lease of the shard; the caller must hold the lock.
+func (s *Sharder) startElector(ctx context.Context, shard int) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.electors[shard] = &shardElector{cancel: cancel, done: done}
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: s.namespace, Name: shardLeaseNamePrefix + strconv.Itoa(shard)},
+		Client:     s.leaseClient,
+		LockConfig: resourcelock.ResourceLockConfig{Identity: s.identity},
+	}
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   s.leaseDuration,
+		RenewDeadline:   s.leaseDuration * 2 / 3,
+		RetryPeriod:     s.leaseDuration / 5,
+		ReleaseOnCancel: true,
+		Name:            lock.LeaseMeta.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leadingCtx context.Context) {
+				s.mu.Lock()
+				// the callback runs in its own goroutine, and may run after the replica already lost the lease
+				if leadingCtx.Err() != nil {
+					s.mu.Unlock()
+					return
+				}
+				s.held[shard] = true
+				s.mu.Unlock()
+				klog.InfoS("Acquired the placement shard", "shard", shard, "identity", s.identity)
+				s.rebalance(leadingCtx, shard)
+			},
+			OnStoppedLeading: func() {
+				s.mu.Lock()
+				s.held[shard] = false
+				s.mu.Unlock()
+				klog.V(2).InfoS("Stopped holding the placement shard", "shard", shard, "identity", s.identity)
+			},
+		},
+	})
+	if err != nil {
+		// the config is built from the validated options, so this should never happen
+		klog.ErrorS(err, "Failed to create the leader elector of the placement shard", "shard", shard)
+		cancel()
+		close(done)
+		return
+	}
+	go func() {
+		defer close(done)
+		le.Run(ctx)
+	}()
+}
+
+// stopElectors stops contending for all the shards and waits for their leases to be released.
+func (s *Sharder) stopElectors() {
+	s.mu.Lock()
+	electors := s.electors
+	s.electors = map[int]*shardElector{}
+	for shard, elector := range electors {
+		s.held[shard] = false
+		elector.cancel()
+	}
+	s.mu.Unlock()
+	for _, elector := range electors {
+		<-elector.done
+	}
+}
+
+// renewLease creates or renews the member lease of the replica.
+func (s *Sharder) renewLease(ctx context.Context) error {
+	now := metav1.NewMicroTime(s.now())
+	lease := &coordinationv1.Lease{}
+	key := types.NamespacedName{Namespace: s.namespace, Name: memberLeaseNamePrefix + s.identity}
+	if err := s.uncachedReader.Get(ctx, key, lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return controller.NewAPIServerError(false, err)
+		}
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{memberLabel: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(s.leaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := s.client.Create(ctx, lease); err != nil {
+			return controller.NewAPIServerError(false, err)
+		}
+		return nil
+	}
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	if err := s.client.Update(ctx, lease); err != nil {
+		return controller.NewAPIServerError(false, err)
+	}
+	return nil
+}
+
+// releaseLease deletes the member lease of the replica on exit.
+func (s *Sharder) releaseLease() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.leaseDuration)
+	defer cancel()
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: memberLeaseNamePrefix + s.identity}}
+	if err := s.client.Delete(ctx, lease); err != nil && !apierrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to release the member lease of the placement sharder", "lease", klog.KObj(lease))
+	}
+}
+
+// rebalance sends the placements of the shard which the replica just acquired to the subscribers, so that the
+// controllers reconcile them; the events of these placements were dropped while another replica held the shard.
+func (s *Sharder) rebalance(ctx context.Context, shard int) {
+	var crpList fleetv1beta1.ClusterResourcePlacementList
+	if err := s.client.List(ctx, &crpList); err != nil {
+		klog.ErrorS(err, "Failed to list the clusterResourcePlacements to rebalance", "shard", shard)
+		return
+	}
+	s.mu.RLock()
+	subscribers := s.subscribers
+	s.mu.RUnlock()
+	for i := range crpList.Items {
+		crp := &crpList.Items[i]
+		if shardOf(s.shardCount, crp.Name, crp.Labels) != shard {
+			continue
+		}
+		klog.V(2).InfoS("Taking over the clusterResourcePlacement", "clusterResourcePlacement", klog.KObj(crp), "shard", shard, "identity", s.identity)
+		for _, ch := range subscribers {
+			select {
+			case ch <- event.GenericEvent{Object: crp}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// liveMembers returns the sorted identities of the holders of the member leases which have not expired.
+func liveMembers(leases []coordinationv1.Lease, now time.Time) []string {
+	members := make([]string, 0, len(leases))
+	for i := range leases {
+		spec := leases[i].Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		if spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second).Before(now) {
+			continue
+		}
+		members = append(members, *spec.HolderIdentity)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// shardOf returns the shard of the placement: the shard label modulo the number of the shards if it is set, or the
+// hash of the name otherwise.
+func shardOf(shardCount int, crpName string, labels map[string]string) int {
+	if shardCount <= 0 {
+		return 0
+	}
+	if shard, err := strconv.Atoi(labels[fleetv1beta1.ShardLabel]); err == nil && shard >= 0 {
+		return shard % shardCount
+	}
+	sum := sha256.Sum256([]byte(crpName))
+	return int(binary.BigEndian.Uint64(sum[:8]) % uint64(shardCount))
+}
+
+// ownerOf returns the member which the shard is mapped to by the rendezvous hash, or an empty string if there is no
+// member.
+func ownerOf(members []string, shard int) string {
+	var owner string
+	var maxScore uint64
+	for _, member := range members {
+		sum := sha256.Sum256([]byte(member + "/" + strconv.Itoa(shard)))
+		if score := binary.BigEndian.Uint64(sum[:8]); owner == "" || score > maxScore {
+			owner, maxScore = member, score
+		}
+	}
+	return owner
+}
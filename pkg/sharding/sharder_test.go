@@ -0,0 +1,275 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func memberLease(identity string, renewTime time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      memberLeaseNamePrefix + identity,
+			Namespace: "fleet-system",
+			Labels:    map[string]string{memberLabel: "true"},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(identity),
+			LeaseDurationSeconds: ptr.To(int32(15)),
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+}
+
+func TestLiveMembers(t *testing.T) {
+	leases := []coordinationv1.Lease{
+		*memberLease("hub-agent-c", now.Add(-5*time.Second)),
+		*memberLease("hub-agent-a", now),
+		*memberLease("hub-agent-expired", now.Add(-time.Minute)),
+		{Spec: coordinationv1.LeaseSpec{HolderIdentity: ptr.To("hub-agent-not-renewed")}},
+	}
+	want := []string{"hub-agent-a", "hub-agent-c"}
+	if diff := cmp.Diff(want, liveMembers(leases, now)); diff != "" {
+		t.Errorf("liveMembers() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	tests := map[string]struct {
+		labels map[string]string
+		want   int
+	}{
+		"shard label": {
+			labels: map[string]string{fleetv1beta1.ShardLabel: "1"},
+			want:   1,
+		},
+		"shard label out of range": {
+			labels: map[string]string{fleetv1beta1.ShardLabel: "9"},
+			want:   1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := shardOf(4, "crp", tc.labels); got != tc.want {
+				t.Errorf("shardOf() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+
+	counts := make([]int, 4)
+	for i := 0; i < 1000; i++ {
+		counts[shardOf(4, fmt.Sprintf("crp-%d", i), nil)]++
+	}
+	for shard, count := range counts {
+		if count < 200 {
+			t.Errorf("shard %d has %d of 1000 placements, want them spread evenly", shard, count)
+		}
+	}
+}
+
+func TestOwnerOf(t *testing.T) {
+	if got := ownerOf(nil, 0); got != "" {
+		t.Errorf("ownerOf() = %q, want no owner without members", got)
+	}
+	oldMembers := []string{"hub-agent-a", "hub-agent-b", "hub-agent-c"}
+	newMembers := []string{"hub-agent-a", "hub-agent-b", "hub-agent-c", "hub-agent-d"}
+	owned := map[string]int{}
+	for shard := 0; shard < 1000; shard++ {
+		oldOwner, newOwner := ownerOf(oldMembers, shard), ownerOf(newMembers, shard)
+		// only the shards moving to the new replica may change their owners
+		if oldOwner != newOwner && newOwner != "hub-agent-d" {
+			t.Fatalf("ownerOf(%d) moved from %s to %s, want it to stay or move to hub-agent-d", shard, oldOwner, newOwner)
+		}
+		owned[newOwner]++
+	}
+	for _, member := range newMembers {
+		if owned[member] < 150 {
+			t.Errorf("%s owns %d of 1000 shards, want them spread evenly", member, owned[member])
+		}
+	}
+}
+
+func TestOwnsPlacement_NilSharder(t *testing.T) {
+	var s *Sharder
+	crp := &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp"}}
+	if !s.OwnsPlacement(crp) {
+		t.Errorf("OwnsPlacement() = false, want true for a nil sharder")
+	}
+	if !s.Owns(context.Background(), "crp") {
+		t.Errorf("Owns() = false, want true for a nil sharder")
+	}
+	if got := ControllerLeaderElection(s); got != nil {
+		t.Errorf("ControllerLeaderElection() = %v, want nil for a nil sharder", *got)
+	}
+}
+
+func TestSync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the client-go scheme: %v", err)
+	}
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement scheme: %v", err)
+	}
+	members := []string{"hub-agent-a", "hub-agent-b"}
+	// find a shard of each replica, and pin a placement to each of them
+	shards := map[string]int{}
+	for shard := 0; len(shards) < len(members); shard++ {
+		if _, ok := shards[ownerOf(members, shard)]; !ok {
+			shards[ownerOf(members, shard)] = shard
+		}
+	}
+	crps := []client.Object{
+		&fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "pinned-to-a", Labels: map[string]string{fleetv1beta1.ShardLabel: strconv.Itoa(shards["hub-agent-a"])}}},
+		&fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "pinned-to-b", Labels: map[string]string{fleetv1beta1.ShardLabel: strconv.Itoa(shards["hub-agent-b"])}}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(append(crps, memberLease("hub-agent-b", now))...).
+		Build()
+	leaseClient := kubefake.NewSimpleClientset().CoordinationV1()
+	shardCount := max(shards["hub-agent-a"], shards["hub-agent-b"]) + 1
+	s := NewSharder(fakeClient, fakeClient, leaseClient, "fleet-system", "hub-agent-a", shardCount, 3*time.Second)
+	s.now = func() time.Time { return now }
+	ch := make(chan event.GenericEvent, 10)
+	s.subscribers = append(s.subscribers, ch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		s.stopElectors()
+	}()
+	if err := s.sync(ctx); err != nil {
+		t.Fatalf("sync() = %v, want nil", err)
+	}
+
+	var lease coordinationv1.Lease
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "fleet-system", Name: memberLeaseNamePrefix + "hub-agent-a"}, &lease); err != nil {
+		t.Fatalf("failed to get the member lease of the replica: %v", err)
+	}
+	if got := *lease.Spec.HolderIdentity; got != "hub-agent-a" {
+		t.Errorf("member lease holder = %s, want hub-agent-a", got)
+	}
+	if diff := cmp.Diff(members, s.members); diff != "" {
+		t.Errorf("members mismatch (-want, +got):\n%s", diff)
+	}
+
+	select {
+	case e := <-ch:
+		if e.Object.GetName() != "pinned-to-a" {
+			t.Errorf("rebalanced placement = %s, want pinned-to-a", e.Object.GetName())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("no placement is rebalanced to the replica")
+	}
+	select {
+	case e := <-ch:
+		t.Errorf("got an unexpected rebalanced placement %s", e.Object.GetName())
+	case <-time.After(100 * time.Millisecond):
+	}
+	if !s.OwnsPlacement(crps[0]) || s.OwnsPlacement(crps[1]) {
+		t.Errorf("OwnsPlacement() = %t, %t, want true, false", s.OwnsPlacement(crps[0]), s.OwnsPlacement(crps[1]))
+	}
+	if !s.Owns(ctx, "pinned-to-a") {
+		t.Errorf("Owns(pinned-to-a) = false, want true")
+	}
+	shardLease, err := leaseClient.Leases("fleet-system").Get(ctx, shardLeaseNamePrefix+strconv.Itoa(shards["hub-agent-a"]), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the shard lease: %v", err)
+	}
+	if got := ptr.Deref(shardLease.Spec.HolderIdentity, ""); got != "hub-agent-a" {
+		t.Errorf("shard lease holder = %s, want hub-agent-a", got)
+	}
+
+	// the replica takes over the shards of a replica which leaves
+	if err := fakeClient.Delete(ctx, memberLease("hub-agent-b", now)); err != nil {
+		t.Fatalf("failed to delete the member lease: %v", err)
+	}
+	if err := s.sync(ctx); err != nil {
+		t.Fatalf("sync() = %v, want nil", err)
+	}
+	if !s.OwnsPlacement(crps[1]) {
+		// the shard of hub-agent-b is acquired asynchronously
+		if err := wait.PollUntilContextTimeout(ctx, 50*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+			return s.OwnsPlacement(crps[1]), nil
+		}); err != nil {
+			t.Errorf("OwnsPlacement(pinned-to-b) = false, want true after hub-agent-b leaves")
+		}
+	}
+
+	// the shard is handed off and its lease released once the replica is no longer mapped to it
+	if err := fakeClient.Create(ctx, memberLease("hub-agent-b", now)); err != nil {
+		t.Fatalf("failed to create the member lease: %v", err)
+	}
+	if err := s.sync(ctx); err != nil {
+		t.Fatalf("sync() = %v, want nil", err)
+	}
+	if s.OwnsPlacement(crps[1]) {
+		t.Errorf("OwnsPlacement(pinned-to-b) = true, want false once hub-agent-b joins again")
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 50*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		shardLease, err := leaseClient.Leases("fleet-system").Get(ctx, shardLeaseNamePrefix+strconv.Itoa(shards["hub-agent-b"]), metav1.GetOptions{})
+		return err == nil && ptr.Deref(shardLease.Spec.HolderIdentity, "") == "", nil
+	}); err != nil {
+		t.Errorf("the lease of the shard of hub-agent-b is not released")
+	}
+}
+
+func TestSync_ShardHeldByAnotherReplica(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the client-go scheme: %v", err)
+	}
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the placement scheme: %v", err)
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp", Labels: map[string]string{fleetv1beta1.ShardLabel: "0"}}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crp).Build()
+	// the previous holder of the shard has not released its lease, e.g. it is partitioned from the other replicas
+	held := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: shardLeaseNamePrefix + "0", Namespace: "fleet-system"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To("hub-agent-old"),
+			LeaseDurationSeconds: ptr.To(int32(3600)),
+			AcquireTime:          &metav1.MicroTime{Time: time.Now()},
+			RenewTime:            &metav1.MicroTime{Time: time.Now()},
+		},
+	}
+	leaseClient := kubefake.NewSimpleClientset(held).CoordinationV1()
+	s := NewSharder(fakeClient, fakeClient, leaseClient, "fleet-system", "hub-agent-a", 1, 3*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		s.stopElectors()
+	}()
+	if err := s.sync(ctx); err != nil {
+		t.Fatalf("sync() = %v, want nil", err)
+	}
+	time.Sleep(time.Second)
+	if s.OwnsPlacement(crp) {
+		t.Errorf("OwnsPlacement() = true, want false while another replica holds the shard lease")
+	}
+}
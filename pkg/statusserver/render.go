@@ -0,0 +1,158 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package statusserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+	"go.goms.io/fleet/pkg/utils/manifestsealing"
+)
+
+const (
+	// renderPath is the path of the works which would be placed on one member cluster for the latest resources of a
+	// placement.
+	renderPath = clusterPath + "/render"
+
+	// redactedValue replaces the values of the secrets which are not sealed in the rendered works.
+	redactedValue = "REDACTED"
+)
+
+// secretGVK is the GVK of the secrets whose values are redacted in the rendered works.
+var secretGVK = corev1.SchemeGroupVersion.WithKind("Secret")
+
+// BindingPlanner plans the binding of a placement to a member cluster which rolls out the latest resources of the
+// placement, without updating any binding.
+type BindingPlanner interface {
+	DesiredBinding(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement, clusterName string) (*fleetv1beta1.ClusterResourceBinding, error)
+}
+
+// WorkRenderer renders the works of a binding, without creating or updating any work.
+type WorkRenderer interface {
+	RenderWorks(ctx context.Context, binding *fleetv1beta1.ClusterResourceBinding) ([]fleetv1beta1.Work, error)
+}
+
+// ClusterRenderPreview is the works which would be placed on one member cluster for the latest resources of a
+// placement, i.e. the selected resources with the overrides applied and split into the works.
+type ClusterRenderPreview struct {
+	// ClusterName is the name of the member cluster.
+	ClusterName string `json:"clusterName"`
+	// BindingName is the name of the binding of the placement to the cluster; it is empty if the cluster is not
+	// selected by the placement.
+	BindingName string `json:"bindingName,omitempty"`
+	// ResourceSnapshotName is the name of the latest resource snapshot of the placement which is rendered.
+	ResourceSnapshotName string `json:"resourceSnapshotName"`
+	// ClusterResourceOverrideSnapshots are the names of the cluster resource override snapshots applied.
+	ClusterResourceOverrideSnapshots []string `json:"clusterResourceOverrideSnapshots,omitempty"`
+	// ResourceOverrideSnapshots are the names of the resource override snapshots applied.
+	ResourceOverrideSnapshots []fleetv1beta1.NamespacedName `json:"resourceOverrideSnapshots,omitempty"`
+	// Works are the rendered works sorted by their names.
+	Works []RenderedWork `json:"works"`
+}
+
+// RenderedWork is a work which would be placed on the member cluster.
+type RenderedWork struct {
+	Name string                `json:"name"`
+	Spec fleetv1beta1.WorkSpec `json:"spec"`
+}
+
+// WithRenderer enables the rendering of the works which would be placed on a member cluster for the latest resources
+// of a placement.
+func (s *Server) WithRenderer(planner BindingPlanner, renderer WorkRenderer) *Server {
+	s.planner = planner
+	s.renderer = renderer
+	return s
+}
+
+func (s *Server) serveRender(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cluster := r.PathValue("cluster")
+	crp, err := s.getPlacement(ctx, r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	binding, err := s.planner.DesiredBinding(ctx, crp, cluster)
+	if err != nil {
+		writeRenderError(w, err)
+		return
+	}
+	works, err := s.renderer.RenderWorks(ctx, binding)
+	if err != nil {
+		writeRenderError(w, err)
+		return
+	}
+	preview := ClusterRenderPreview{
+		ClusterName:                      cluster,
+		BindingName:                      binding.Name,
+		ResourceSnapshotName:             binding.Spec.ResourceSnapshotName,
+		ClusterResourceOverrideSnapshots: binding.Spec.ClusterResourceOverrideSnapshots,
+		ResourceOverrideSnapshots:        binding.Spec.ResourceOverrideSnapshots,
+		Works:                            make([]RenderedWork, 0, len(works)),
+	}
+	for i := range works {
+		spec := works[i].Spec
+		redactSecrets(&spec)
+		preview.Works = append(preview.Works, RenderedWork{Name: works[i].Name, Spec: spec})
+	}
+	writeJSON(w, &preview)
+}
+
+// redactSecrets replaces the values of the secrets in the work which are not sealed, as the rendered works are served
+// without authentication; the sealed secrets can only be read by their member clusters and are kept as they are.
+func redactSecrets(spec *fleetv1beta1.WorkSpec) {
+	for i := range spec.Workload.Manifests {
+		manifest := &spec.Workload.Manifests[i]
+		var obj unstructured.Unstructured
+		if err := obj.UnmarshalJSON(manifest.Raw); err != nil || obj.GroupVersionKind() != secretGVK || manifestsealing.IsSealed(&obj) {
+			continue
+		}
+		redacted := false
+		for _, field := range []string{"data", "stringData"} {
+			values, found, err := unstructured.NestedMap(obj.Object, field)
+			if err != nil || !found {
+				continue
+			}
+			for key := range values {
+				values[key] = redactedValue
+			}
+			if err := unstructured.SetNestedMap(obj.Object, values, field); err == nil {
+				redacted = true
+			}
+		}
+		if !redacted {
+			continue
+		}
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			klog.ErrorS(err, "Failed to encode the redacted secret", "secret", klog.KObj(&obj))
+			manifest.Raw = nil
+			continue
+		}
+		manifest.Raw = raw
+	}
+}
+
+// writeRenderError writes the error which fails the rendering; the errors caused by the placement, e.g. an override
+// which cannot be applied, are reported as bad requests and the transient ones, e.g. a resource snapshot being
+// created, as conflicts to be retried.
+func writeRenderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, controller.ErrUserError):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, controller.ErrExpectedBehavior):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, err)
+	}
+}
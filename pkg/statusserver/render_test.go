@@ -0,0 +1,140 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package statusserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// fakePlanner plans the bindings to the latest resource snapshot, or fails with its error.
+type fakePlanner struct {
+	err error
+}
+
+func (p *fakePlanner) DesiredBinding(_ context.Context, crp *fleetv1beta1.ClusterResourcePlacement, clusterName string) (*fleetv1beta1.ClusterResourceBinding, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: crp.Name + "-" + clusterName},
+		Spec: fleetv1beta1.ResourceBindingSpec{
+			TargetCluster:                    clusterName,
+			ResourceSnapshotName:             crp.Name + "-1-snapshot",
+			ClusterResourceOverrideSnapshots: []string{"cro-1"},
+		},
+	}, nil
+}
+
+// fakeRenderer renders the manifests into one work.
+type fakeRenderer struct {
+	manifests []fleetv1beta1.Manifest
+}
+
+func (r *fakeRenderer) RenderWorks(_ context.Context, _ *fleetv1beta1.ClusterResourceBinding) ([]fleetv1beta1.Work, error) {
+	return []fleetv1beta1.Work{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "crp-work"},
+			Spec:       fleetv1beta1.WorkSpec{Workload: fleetv1beta1.WorkloadTemplate{Manifests: r.manifests}},
+		},
+	}, nil
+}
+
+func TestServeRender(t *testing.T) {
+	manifest := func(raw string) fleetv1beta1.Manifest {
+		return fleetv1beta1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(raw)}}
+	}
+	configMap := manifest(`{"apiVersion":"v1","data":{"key":"value"},"kind":"ConfigMap","metadata":{"name":"config"}}`)
+	secret := manifest(`{"apiVersion":"v1","data":{"password":"c2VjcmV0"},"kind":"Secret","metadata":{"name":"creds"},"stringData":{"token":"secret"}}`)
+	sealedSecret := manifest(fmt.Sprintf(`{"apiVersion":"v1","data":{"sealed":"Y2lwaGVy"},"kind":"Secret","metadata":{"annotations":{"%s":"key"},"name":"sealed"}}`,
+		fleetv1beta1.SealedManifestAnnotation))
+	redactedSecret := manifest(`{"apiVersion":"v1","data":{"password":"REDACTED"},"kind":"Secret","metadata":{"name":"creds"},"stringData":{"token":"REDACTED"}}`)
+
+	crp := &fleetv1beta1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "crp"}}
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() = %v, want nil", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crp).Build()
+	renderer := &fakeRenderer{manifests: []fleetv1beta1.Manifest{configMap, secret, sealedSecret}}
+
+	tests := map[string]struct {
+		server      *Server
+		path        string
+		wantCode    int
+		wantPreview *ClusterRenderPreview
+	}{
+		"rendered works": {
+			server:   New(fakeClient, "").WithRenderer(&fakePlanner{}, renderer),
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters/member-1/render",
+			wantCode: http.StatusOK,
+			wantPreview: &ClusterRenderPreview{
+				ClusterName:                      "member-1",
+				BindingName:                      "crp-member-1",
+				ResourceSnapshotName:             "crp-1-snapshot",
+				ClusterResourceOverrideSnapshots: []string{"cro-1"},
+				Works: []RenderedWork{
+					{
+						Name: "crp-work",
+						Spec: fleetv1beta1.WorkSpec{Workload: fleetv1beta1.WorkloadTemplate{
+							Manifests: []fleetv1beta1.Manifest{configMap, redactedSecret, sealedSecret},
+						}},
+					},
+				},
+			},
+		},
+		"placement not found": {
+			server:   New(fakeClient, "").WithRenderer(&fakePlanner{}, renderer),
+			path:     "/v1beta1/clusterresourceplacements/gone/clusters/member-1/render",
+			wantCode: http.StatusNotFound,
+		},
+		"the overrides cannot be applied": {
+			server:   New(fakeClient, "").WithRenderer(&fakePlanner{err: controller.NewUserError(fmt.Errorf("invalid override"))}, renderer),
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters/member-1/render",
+			wantCode: http.StatusBadRequest,
+		},
+		"the resource snapshots are being created": {
+			server:   New(fakeClient, "").WithRenderer(&fakePlanner{err: controller.NewExpectedBehaviorError(fmt.Errorf("no latest snapshot"))}, renderer),
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters/member-1/render",
+			wantCode: http.StatusConflict,
+		},
+		"rendering is not enabled": {
+			server:   New(fakeClient, ""),
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters/member-1/render",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("GET %s = %d, want %d: %s", tt.path, rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantPreview != nil {
+				var got ClusterRenderPreview
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("failed to decode the render preview: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantPreview, &got); diff != "" {
+					t.Errorf("GET %s render preview mismatch (-want, +got):\n%s", tt.path, diff)
+				}
+			}
+		})
+	}
+}
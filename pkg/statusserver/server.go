@@ -0,0 +1,246 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package statusserver features an HTTP server which serves the placement detail of a cluster resource placement on
+// its member clusters on demand, e.g. the status of every work and manifest of the placement on one cluster, so that
+// the large per-cluster detail does not have to be embedded in the status of the placement. It can also preview the
+// works which would be placed on a member cluster for the latest resources of a placement.
+package statusserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+const (
+	// clustersPath is the path of the placement statuses of a placement on all its selected clusters.
+	clustersPath = "/v1beta1/clusterresourceplacements/{name}/clusters"
+	// clusterPath is the path of the placement detail of a placement on one member cluster.
+	clusterPath = clustersPath + "/{cluster}"
+
+	// shutdownTimeout is how long the server waits for the requests being served when it stops.
+	shutdownTimeout = 10 * time.Second
+)
+
+// ClusterPlacementList is the placement status of a placement on each of its selected clusters.
+type ClusterPlacementList struct {
+	// Items are the placement statuses sorted by the names of the clusters.
+	Items []fleetv1beta1.ResourcePlacementStatus `json:"items"`
+}
+
+// ClusterPlacementDetail is the placement detail of a placement on one member cluster.
+type ClusterPlacementDetail struct {
+	// ClusterName is the name of the member cluster.
+	ClusterName string `json:"clusterName"`
+	// PlacementStatus is the placement status on the cluster as reported by the placement; it is absent if the
+	// cluster is not selected.
+	PlacementStatus *fleetv1beta1.ResourcePlacementStatus `json:"placementStatus,omitempty"`
+	// Bindings are the bindings of the placement to the cluster; there may be more than one while a binding is
+	// being replaced.
+	Bindings []BindingDetail `json:"bindings,omitempty"`
+	// Works are the works of the placement in the reserved namespace of the cluster, with the status of every
+	// manifest, which is not truncated like the failed placements of the placement status.
+	Works []WorkDetail `json:"works,omitempty"`
+}
+
+// BindingDetail is a binding of the placement to the cluster.
+type BindingDetail struct {
+	Name   string                             `json:"name"`
+	Spec   fleetv1beta1.ResourceBindingSpec   `json:"spec"`
+	Status fleetv1beta1.ResourceBindingStatus `json:"status"`
+}
+
+// WorkDetail is a work of the placement in the reserved namespace of the cluster.
+type WorkDetail struct {
+	Name   string                  `json:"name"`
+	Status fleetv1beta1.WorkStatus `json:"status"`
+}
+
+// Server serves the placement detail of the cluster resource placements from the cache of the hub agent. It is a
+// manager runnable which runs on every replica of the hub agent, whether it is the leader or not.
+type Server struct {
+	client client.Reader
+	addr   string
+
+	// planner and renderer render the works which would be placed on a member cluster if set.
+	planner  BindingPlanner
+	renderer WorkRenderer
+}
+
+// New returns a server which serves the placement detail on the address.
+func New(c client.Reader, addr string) *Server {
+	return &Server{client: c, addr: addr}
+}
+
+// Handler returns the handler of the placement detail requests.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+clustersPath, s.serveClusters)
+	mux.HandleFunc("GET "+clusterPath, s.serveCluster)
+	if s.planner != nil && s.renderer != nil {
+		mux.HandleFunc("GET "+renderPath, s.serveRender)
+	}
+	return mux
+}
+
+// Start serves the requests until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s for the placement status server: %w", s.addr, err)
+	}
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			klog.ErrorS(err, "Failed to shut down the placement status server")
+		}
+	}()
+	klog.InfoS("Serving the placement detail", "address", listener.Addr().String())
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection tells the manager to run the server on all the replicas.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) serveClusters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	crp, err := s.getPlacement(ctx, r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	statuses, err := s.placementStatuses(ctx, crp)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list := ClusterPlacementList{Items: make([]fleetv1beta1.ResourcePlacementStatus, 0, len(statuses))}
+	for _, status := range statuses {
+		list.Items = append(list.Items, *status)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].ClusterName < list.Items[j].ClusterName
+	})
+	writeJSON(w, &list)
+}
+
+func (s *Server) serveCluster(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cluster := r.PathValue("cluster")
+	crp, err := s.getPlacement(ctx, r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	statuses, err := s.placementStatuses(ctx, crp)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	detail := ClusterPlacementDetail{ClusterName: cluster, PlacementStatus: statuses[cluster]}
+
+	var bindings fleetv1beta1.ClusterResourceBindingList
+	if err := s.client.List(ctx, &bindings, client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crp.Name}); err != nil {
+		writeError(w, err)
+		return
+	}
+	for i := range bindings.Items {
+		if b := &bindings.Items[i]; b.Spec.TargetCluster == cluster {
+			detail.Bindings = append(detail.Bindings, BindingDetail{Name: b.Name, Spec: b.Spec, Status: b.Status})
+		}
+	}
+	sort.Slice(detail.Bindings, func(i, j int) bool {
+		return detail.Bindings[i].Name < detail.Bindings[j].Name
+	})
+
+	var works fleetv1beta1.WorkList
+	if err := s.client.List(ctx, &works, client.InNamespace(fmt.Sprintf(utils.NamespaceNameFormat, cluster)),
+		client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crp.Name}); err != nil {
+		writeError(w, err)
+		return
+	}
+	for i := range works.Items {
+		detail.Works = append(detail.Works, WorkDetail{Name: works.Items[i].Name, Status: works.Items[i].Status})
+	}
+	sort.Slice(detail.Works, func(i, j int) bool {
+		return detail.Works[i].Name < detail.Works[j].Name
+	})
+
+	if detail.PlacementStatus == nil && len(detail.Bindings) == 0 && len(detail.Works) == 0 {
+		http.Error(w, fmt.Sprintf("cluster %q is not selected by the placement %q", cluster, crp.Name), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, &detail)
+}
+
+func (s *Server) getPlacement(ctx context.Context, name string) (*fleetv1beta1.ClusterResourcePlacement, error) {
+	var crp fleetv1beta1.ClusterResourcePlacement
+	if err := s.client.Get(ctx, types.NamespacedName{Name: name}, &crp); err != nil {
+		return nil, err
+	}
+	return &crp, nil
+}
+
+// placementStatuses returns the placement statuses of the crp on its selected clusters keyed by the names of the
+// clusters; they are read from the perClusterPlacementStatuses if the status of the crp is compacted.
+func (s *Server) placementStatuses(ctx context.Context, crp *fleetv1beta1.ClusterResourcePlacement) (map[string]*fleetv1beta1.ResourcePlacementStatus, error) {
+	statuses := make(map[string]*fleetv1beta1.ResourcePlacementStatus)
+	if crp.Status.PlacementStatusSummary == nil {
+		for i := range crp.Status.PlacementStatuses {
+			// the placement statuses without cluster names report the clusters which cannot be scheduled
+			if status := &crp.Status.PlacementStatuses[i]; status.ClusterName != "" {
+				statuses[status.ClusterName] = status
+			}
+		}
+		return statuses, nil
+	}
+	var perClusterStatuses fleetv1beta1.PerClusterPlacementStatusList
+	if err := s.client.List(ctx, &perClusterStatuses, client.MatchingLabels{fleetv1beta1.CRPTrackingLabel: crp.Name}); err != nil {
+		return nil, err
+	}
+	for i := range perClusterStatuses.Items {
+		status := &perClusterStatuses.Items[i].PlacementStatus
+		statuses[status.ClusterName] = status
+	}
+	return statuses, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.ErrorS(err, "Failed to write the placement detail")
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if apierrors.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	klog.ErrorS(err, "Failed to read the placement detail")
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package statusserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+func TestServeCluster(t *testing.T) {
+	appliedStatus := func(cluster string) fleetv1beta1.ResourcePlacementStatus {
+		return fleetv1beta1.ResourcePlacementStatus{
+			ClusterName: cluster,
+			Conditions: []metav1.Condition{
+				{Type: string(fleetv1beta1.ResourcesAppliedConditionType), Status: metav1.ConditionTrue, Reason: "Applied"},
+			},
+		}
+	}
+	crp := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: "crp"},
+		Status: fleetv1beta1.ClusterResourcePlacementStatus{
+			PlacementStatuses: []fleetv1beta1.ResourcePlacementStatus{appliedStatus("member-2"), appliedStatus("member-1"), {}},
+		},
+	}
+	compactedCRP := &fleetv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: "compacted"},
+		Status: fleetv1beta1.ClusterResourcePlacementStatus{
+			PlacementStatusSummary: &fleetv1beta1.PlacementStatusSummary{SelectedClusters: 1},
+		},
+	}
+	perClusterStatus := &fleetv1beta1.PerClusterPlacementStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      compactedCRP.Name,
+			Namespace: fmt.Sprintf(utils.NamespaceNameFormat, "member-1"),
+			Labels:    map[string]string{fleetv1beta1.CRPTrackingLabel: compactedCRP.Name},
+		},
+		PlacementStatus: appliedStatus("member-1"),
+	}
+	binding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "crp-member-1", Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crp.Name}},
+		Spec:       fleetv1beta1.ResourceBindingSpec{State: fleetv1beta1.BindingStateBound, TargetCluster: "member-1"},
+	}
+	otherBinding := &fleetv1beta1.ClusterResourceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "crp-member-2", Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crp.Name}},
+		Spec:       fleetv1beta1.ResourceBindingSpec{State: fleetv1beta1.BindingStateBound, TargetCluster: "member-2"},
+	}
+	workStatus := fleetv1beta1.WorkStatus{
+		ManifestConditions: []fleetv1beta1.ManifestCondition{
+			{
+				Identifier: fleetv1beta1.WorkResourceIdentifier{Version: "v1", Kind: "ConfigMap", Namespace: "app", Name: "config"},
+				Conditions: []metav1.Condition{{Type: fleetv1beta1.WorkConditionTypeApplied, Status: metav1.ConditionTrue, Reason: "Applied"}},
+			},
+		},
+	}
+	work := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crp-work",
+			Namespace: fmt.Sprintf(utils.NamespaceNameFormat, "member-1"),
+			Labels:    map[string]string{fleetv1beta1.CRPTrackingLabel: crp.Name},
+		},
+		Status: workStatus,
+	}
+
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() = %v, want nil", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects([]client.Object{crp, compactedCRP, perClusterStatus, binding, otherBinding, work}...).Build()
+	handler := New(fakeClient, "").Handler()
+
+	wantMember1Status := appliedStatus("member-1")
+	tests := map[string]struct {
+		path       string
+		wantCode   int
+		wantDetail *ClusterPlacementDetail
+		wantList   *ClusterPlacementList
+	}{
+		"cluster detail": {
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters/member-1",
+			wantCode: http.StatusOK,
+			wantDetail: &ClusterPlacementDetail{
+				ClusterName:     "member-1",
+				PlacementStatus: &wantMember1Status,
+				Bindings:        []BindingDetail{{Name: binding.Name, Spec: binding.Spec}},
+				Works:           []WorkDetail{{Name: work.Name, Status: workStatus}},
+			},
+		},
+		"cluster detail of a compacted placement": {
+			path:     "/v1beta1/clusterresourceplacements/compacted/clusters/member-1",
+			wantCode: http.StatusOK,
+			wantDetail: &ClusterPlacementDetail{
+				ClusterName:     "member-1",
+				PlacementStatus: &wantMember1Status,
+			},
+		},
+		"cluster not selected": {
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters/member-3",
+			wantCode: http.StatusNotFound,
+		},
+		"placement not found": {
+			path:     "/v1beta1/clusterresourceplacements/gone/clusters/member-1",
+			wantCode: http.StatusNotFound,
+		},
+		"placement statuses": {
+			path:     "/v1beta1/clusterresourceplacements/crp/clusters",
+			wantCode: http.StatusOK,
+			wantList: &ClusterPlacementList{Items: []fleetv1beta1.ResourcePlacementStatus{appliedStatus("member-1"), appliedStatus("member-2")}},
+		},
+		"placement statuses of a compacted placement": {
+			path:     "/v1beta1/clusterresourceplacements/compacted/clusters",
+			wantCode: http.StatusOK,
+			wantList: &ClusterPlacementList{Items: []fleetv1beta1.ResourcePlacementStatus{appliedStatus("member-1")}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantCode {
+				t.Fatalf("GET %s = %d, want %d: %s", tt.path, rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantDetail != nil {
+				var got ClusterPlacementDetail
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("failed to decode the placement detail: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantDetail, &got); diff != "" {
+					t.Errorf("GET %s placement detail mismatch (-want, +got):\n%s", tt.path, diff)
+				}
+			}
+			if tt.wantList != nil {
+				var got ClusterPlacementList
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("failed to decode the placement statuses: %v", err)
+				}
+				if diff := cmp.Diff(tt.wantList, &got); diff != "" {
+					t.Errorf("GET %s placement statuses mismatch (-want, +got):\n%s", tt.path, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package argocd generates the Argo CD resource health checks of the fleet placement APIs, so that Argo CD managing
+// the hub cluster shows the health and the progress of the placements.
+package argocd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/condition"
+)
+
+// healthCustomizationKeyFmt is the format of the key of a health check in the argocd-cm configMap,
+// i.e. resource.customizations.health.<group>_<kind>.
+const healthCustomizationKeyFmt = "resource.customizations.health.%s_%s"
+
+// healthCheck describes how the health of an object is derived from its conditions.
+type healthCheck struct {
+	// ConditionTypes are the conditions in the order of the placement lifecycle. The object is healthy when all of
+	// them are true for its current generation.
+	ConditionTypes []string
+	// ProgressingWhenFalse are the conditions which are false while the rollout is in progress, e.g. when the rollout
+	// is gated by the rollout strategy, instead of failing.
+	ProgressingWhenFalse []string
+	// Progress is the Lua expression which summarizes the progress of a healthy object.
+	Progress string
+}
+
+// healthCheckTemplate is the Lua health check. Argo CD runs it with the object in the obj variable and expects the
+// health status in the returned table.
+var healthCheckTemplate = template.Must(template.New("health").Parse(`local hs = {}
+local conditions = {}
+if obj.status ~= nil and obj.status.conditions ~= nil then
+  for _, c in ipairs(obj.status.conditions) do
+    conditions[c.type] = c
+  end
+end
+local progressingWhenFalse = {
+{{- range .ProgressingWhenFalse }}
+  ["{{ . }}"] = true,
+{{- end }}
+}
+local conditionTypes = {
+{{- range .ConditionTypes }}
+  "{{ . }}",
+{{- end }}
+}
+for _, t in ipairs(conditionTypes) do
+  local c = conditions[t]
+  if c == nil or c.observedGeneration ~= obj.metadata.generation then
+    hs.status = "Progressing"
+    hs.message = "Waiting for " .. t
+    return hs
+  end
+  if c.status == "False" and not progressingWhenFalse[t] then
+    hs.status = "Degraded"
+    hs.message = c.message
+    return hs
+  end
+  if c.status ~= "True" then
+    hs.status = "Progressing"
+    hs.message = c.message
+    return hs
+  end
+end
+hs.status = "Healthy"
+hs.message = {{ .Progress }}
+return hs
+`))
+
+// placementProgress counts the clusters on which the resources are available.
+const placementProgress = `(function()
+  local total, available = 0, 0
+  if obj.status.placementStatuses ~= nil then
+    for _, ps in ipairs(obj.status.placementStatuses) do
+      total = total + 1
+      if ps.conditions ~= nil then
+        for _, c in ipairs(ps.conditions) do
+          if c.type == "Available" and c.status == "True" then
+            available = available + 1
+          end
+        end
+      end
+    end
+  end
+  return string.format("Resources are available on %d/%d clusters", available, total)
+end)()`
+
+// bindingProgress names the cluster of the binding.
+const bindingProgress = `"Resources are available on cluster " .. obj.spec.targetCluster`
+
+// ClusterResourcePlacementHealthCheck returns the Lua health check of the ClusterResourcePlacement.
+func ClusterResourcePlacementHealthCheck() (string, error) {
+	conditionTypes := []string{string(fleetv1beta1.ClusterResourcePlacementScheduledConditionType)}
+	for c := condition.RolloutStartedCondition; c < condition.TotalCondition; c++ {
+		conditionTypes = append(conditionTypes, string(c.ClusterResourcePlacementConditionType()))
+	}
+	return renderHealthCheck(healthCheck{
+		ConditionTypes:       conditionTypes,
+		ProgressingWhenFalse: []string{string(fleetv1beta1.ClusterResourcePlacementRolloutStartedConditionType)},
+		Progress:             placementProgress,
+	})
+}
+
+// ClusterResourceBindingHealthCheck returns the Lua health check of the ClusterResourceBinding, which is the health
+// of the placement on a single cluster.
+func ClusterResourceBindingHealthCheck() (string, error) {
+	var conditionTypes []string
+	for c := condition.RolloutStartedCondition; c < condition.TotalCondition; c++ {
+		conditionTypes = append(conditionTypes, string(c.ResourceBindingConditionType()))
+	}
+	return renderHealthCheck(healthCheck{
+		ConditionTypes:       conditionTypes,
+		ProgressingWhenFalse: []string{string(fleetv1beta1.ResourceBindingRolloutStarted)},
+		Progress:             bindingProgress,
+	})
+}
+
+// ResourceCustomizations returns the health checks keyed by their keys in the argocd-cm configMap.
+func ResourceCustomizations() (map[string]string, error) {
+	crpHealthCheck, err := ClusterResourcePlacementHealthCheck()
+	if err != nil {
+		return nil, err
+	}
+	bindingHealthCheck, err := ClusterResourceBindingHealthCheck()
+	if err != nil {
+		return nil, err
+	}
+	group := fleetv1beta1.GroupVersion.Group
+	return map[string]string{
+		fmt.Sprintf(healthCustomizationKeyFmt, group, fleetv1beta1.ClusterResourcePlacementKind): crpHealthCheck,
+		fmt.Sprintf(healthCustomizationKeyFmt, group, fleetv1beta1.ClusterResourceBindingKind):   bindingHealthCheck,
+	}, nil
+}
+
+func renderHealthCheck(check healthCheck) (string, error) {
+	var buf bytes.Buffer
+	if err := healthCheckTemplate.Execute(&buf, check); err != nil {
+		return "", fmt.Errorf("failed to render the health check: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
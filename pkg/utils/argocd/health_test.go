@@ -0,0 +1,73 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package argocd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceCustomizations(t *testing.T) {
+	customizations, err := ResourceCustomizations()
+	if err != nil {
+		t.Fatalf("ResourceCustomizations() = %v, want nil", err)
+	}
+	tests := map[string]struct {
+		key                    string
+		wantConditionTypes     []string
+		wantProgressingOnFalse string
+	}{
+		"cluster resource placement": {
+			key: "resource.customizations.health.placement.kubernetes-fleet.io_ClusterResourcePlacement",
+			wantConditionTypes: []string{
+				"ClusterResourcePlacementScheduled",
+				"ClusterResourcePlacementRolloutStarted",
+				"ClusterResourcePlacementOverridden",
+				"ClusterResourcePlacementWorkSynchronized",
+				"ClusterResourcePlacementApplied",
+				"ClusterResourcePlacementAvailable",
+			},
+			wantProgressingOnFalse: "ClusterResourcePlacementRolloutStarted",
+		},
+		"cluster resource binding": {
+			key:                    "resource.customizations.health.placement.kubernetes-fleet.io_ClusterResourceBinding",
+			wantConditionTypes:     []string{"RolloutStarted", "Overridden", "WorkSynchronized", "Applied", "Available"},
+			wantProgressingOnFalse: "RolloutStarted",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, ok := customizations[tc.key]
+			if !ok {
+				t.Fatalf("ResourceCustomizations() has no %s", tc.key)
+			}
+			// the condition types are listed in the order of the placement lifecycle
+			var gotConditionTypes []string
+			inConditionTypes := false
+			for _, line := range strings.Split(script, "\n") {
+				switch {
+				case line == "local conditionTypes = {":
+					inConditionTypes = true
+				case inConditionTypes && line == "}":
+					inConditionTypes = false
+				case inConditionTypes:
+					gotConditionTypes = append(gotConditionTypes, strings.Trim(strings.TrimSpace(line), `",`))
+				}
+			}
+			if diff := cmp.Diff(tc.wantConditionTypes, gotConditionTypes); diff != "" {
+				t.Errorf("health check condition types mismatch (-want, +got):\n%s", diff)
+			}
+			if want := `["` + tc.wantProgressingOnFalse + `"] = true,`; !strings.Contains(script, want) {
+				t.Errorf("health check does not treat %s as progressing when false", tc.wantProgressingOnFalse)
+			}
+			if !strings.HasSuffix(script, "return hs") {
+				t.Errorf("health check does not return the health status")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package clustergroup provides utils to tell the member clusters of the cluster groups. The hub agent keeps the label
+// of each group on its member clusters, so that a group is matched by the labels of the clusters alone.
+package clustergroup
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+)
+
+// memberLabelValue is the value of the label of a group on its member clusters.
+const memberLabelValue = "true"
+
+// Label returns the label which the member clusters of the group are labeled with.
+func Label(group string) string {
+	return clusterv1beta1.ClusterGroupLabelPrefix + group
+}
+
+// Contains tells if the member cluster with the labels is in the group.
+func Contains(clusterLabels map[string]string, group string) bool {
+	return clusterLabels[Label(group)] == memberLabelValue
+}
+
+// Requirement returns the label requirement which selects the member clusters of the group.
+func Requirement(group string) (*labels.Requirement, error) {
+	return labels.NewRequirement(Label(group), selection.Equals, []string{memberLabelValue})
+}
+
+// Validate returns an error if the name of the group cannot be the name of its label.
+func Validate(group string) error {
+	if errs := validation.IsQualifiedName(Label(group)); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster group name %q: %s", group, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// IsMember tells if the member cluster is in the group, i.e. the group lists its name or selects it by the labels
+// other than the ones of the groups.
+func IsMember(group *clusterv1beta1.ClusterGroup, cluster *clusterv1beta1.MemberCluster) (bool, error) {
+	for _, name := range group.Spec.Clusters {
+		if name == cluster.Name {
+			return true, nil
+		}
+	}
+	if group.Spec.ClusterSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(group.Spec.ClusterSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid cluster selector of cluster group %s: %w", group.Name, err)
+	}
+	clusterLabels := make(labels.Set, len(cluster.Labels))
+	for k, v := range cluster.Labels {
+		if !strings.HasPrefix(k, clusterv1beta1.ClusterGroupLabelPrefix) {
+			clusterLabels[k] = v
+		}
+	}
+	return selector.Matches(clusterLabels), nil
+}
+
+// SetMembership adds the label of the group to the member cluster, or removes it, and tells if the labels are changed.
+func SetMembership(cluster *clusterv1beta1.MemberCluster, group string, member bool) bool {
+	key := Label(group)
+	value, found := cluster.Labels[key]
+	switch {
+	case member && value == memberLabelValue:
+		return false
+	case member:
+		if cluster.Labels == nil {
+			cluster.Labels = make(map[string]string)
+		}
+		cluster.Labels[key] = memberLabelValue
+		return true
+	case found:
+		delete(cluster.Labels, key)
+		return true
+	default:
+		return false
+	}
+}
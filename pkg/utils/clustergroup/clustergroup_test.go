@@ -0,0 +1,74 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package clustergroup
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+)
+
+func TestIsMember(t *testing.T) {
+	group := &clusterv1beta1.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge-retail"},
+		Spec: clusterv1beta1.ClusterGroupSpec{
+			Clusters: []string{"store-1"},
+			ClusterSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: clusterv1beta1.ClusterGroupLabelPrefix + "edge", Operator: metav1.LabelSelectorOpDoesNotExist},
+					{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"edge"}},
+				},
+			},
+		},
+	}
+	tests := map[string]struct {
+		cluster *clusterv1beta1.MemberCluster
+		want    bool
+	}{
+		"listed by name": {
+			cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: "store-1"}},
+			want:    true,
+		},
+		"selected by labels": {
+			cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: "store-2", Labels: map[string]string{"tier": "edge"}}},
+			want:    true,
+		},
+		"the labels of the groups are ignored": {
+			cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{
+				Name:   "store-3",
+				Labels: map[string]string{"tier": "edge", clusterv1beta1.ClusterGroupLabelPrefix + "edge": "true"},
+			}},
+			want: true,
+		},
+		"not selected": {
+			cluster: &clusterv1beta1.MemberCluster{ObjectMeta: metav1.ObjectMeta{Name: "dc-1", Labels: map[string]string{"tier": "core"}}},
+			want:    false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := IsMember(group, tc.cluster)
+			if err != nil {
+				t.Fatalf("IsMember() = %v, want nil", err)
+			}
+			if got != tc.want {
+				t.Errorf("IsMember() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("prod-eu"); err != nil {
+		t.Errorf("Validate(prod-eu) = %v, want nil", err)
+	}
+	if err := Validate(strings.Repeat("a", 64)); err == nil {
+		t.Errorf("Validate() of a name longer than 63 characters = nil, want error")
+	}
+}
@@ -17,6 +17,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -66,6 +67,9 @@ const (
 	PlacementFieldManagerName          = "cluster-placement-controller"
 	MCControllerFieldManagerName       = "member-cluster-controller"
 	OverrideControllerFieldManagerName = "override-controller"
+	// WorkGeneratorStatusFieldManagerName is the field manager with which the work generator applies the status of
+	// the bindings.
+	WorkGeneratorStatusFieldManagerName = "work-generator-status"
 )
 
 // TODO(ryanzhang): move this to the api directory
@@ -113,6 +117,31 @@ var (
 		APIGroups: []string{NetworkingGroupName},
 		Resources: []string{"*"},
 	}
+	// MemberAgentWorkRule allows the member agent to read the works in its reserved namespace and to manage their finalizers.
+	MemberAgentWorkRule = rbacv1.PolicyRule{
+		Verbs:     []string{"get", "list", "watch", "update", "patch"},
+		APIGroups: []string{placementv1beta1.GroupVersion.Group},
+		Resources: []string{placementv1beta1.WorkResource},
+	}
+	// MemberAgentWorkStatusRule allows the member agent to report the apply status of the works in its reserved namespace.
+	MemberAgentWorkStatusRule = rbacv1.PolicyRule{
+		Verbs:     []string{"get", "update", "patch"},
+		APIGroups: []string{placementv1beta1.GroupVersion.Group},
+		Resources: []string{placementv1beta1.WorkResource + "/status"},
+	}
+	// MemberAgentMembershipRule allows the member agent to read its membership, i.e. the internalMemberCluster, in its
+	// reserved namespace.
+	MemberAgentMembershipRule = rbacv1.PolicyRule{
+		Verbs:     []string{"get", "list", "watch"},
+		APIGroups: []string{clusterv1beta1.GroupVersion.Group},
+		Resources: []string{clusterv1beta1.InternalMemberClusterResource},
+	}
+	// MemberAgentMembershipStatusRule allows the member agent to report the status of its membership.
+	MemberAgentMembershipStatusRule = rbacv1.PolicyRule{
+		Verbs:     []string{"get", "update", "patch"},
+		APIGroups: []string{clusterv1beta1.GroupVersion.Group},
+		Resources: []string{clusterv1beta1.InternalMemberClusterResource + "/status"},
+	}
 )
 
 // Those are the GVR/GVK of the fleet related resources.
@@ -255,6 +284,18 @@ var (
 		Resource: "services",
 	}
 
+	ServiceExportGVK = schema.GroupVersionKind{
+		Group:   fleetnetworkingv1alpha1.GroupVersion.Group,
+		Version: fleetnetworkingv1alpha1.GroupVersion.Version,
+		Kind:    "ServiceExport",
+	}
+
+	ServiceExportGVR = schema.GroupVersionResource{
+		Group:    fleetnetworkingv1alpha1.GroupVersion.Group,
+		Version:  fleetnetworkingv1alpha1.GroupVersion.Version,
+		Resource: "serviceexports",
+	}
+
 	WorkV1Alpha1MetaGVK = metav1.GroupVersionKind{
 		Group:   workv1alpha1.GroupVersion.Group,
 		Version: workv1alpha1.GroupVersion.Version,
@@ -321,6 +362,12 @@ var (
 		Resource: "jobs",
 	}
 
+	CronJobGVR = schema.GroupVersionResource{
+		Group:    batchv1.GroupName,
+		Version:  batchv1.SchemeGroupVersion.Version,
+		Resource: "cronjobs",
+	}
+
 	ConfigMapGVR = schema.GroupVersionResource{
 		Group:    corev1.GroupName,
 		Version:  corev1.SchemeGroupVersion.Version,
@@ -356,6 +403,30 @@ var (
 		Version:  rbacv1.SchemeGroupVersion.Version,
 		Resource: "clusterrolebindings",
 	}
+
+	PersistentVolumeClaimGVR = schema.GroupVersionResource{
+		Group:    corev1.GroupName,
+		Version:  corev1.SchemeGroupVersion.Version,
+		Resource: string(corev1.ResourcePersistentVolumeClaims),
+	}
+
+	PersistentVolumeClaimMetaGVK = metav1.GroupVersionKind{
+		Group:   corev1.GroupName,
+		Version: corev1.SchemeGroupVersion.Version,
+		Kind:    "PersistentVolumeClaim",
+	}
+
+	StorageClassGVR = schema.GroupVersionResource{
+		Group:    storagev1.GroupName,
+		Version:  storagev1.SchemeGroupVersion.Version,
+		Resource: "storageclasses",
+	}
+
+	StorageClassMetaGVK = metav1.GroupVersionKind{
+		Group:   storagev1.GroupName,
+		Version: storagev1.SchemeGroupVersion.Version,
+		Kind:    "StorageClass",
+	}
 )
 
 // RandSecureInt returns a uniform random value in [1, max] or panic.
@@ -454,6 +525,33 @@ func ShouldPropagateObj(informerManager informer.Manager, uObj *unstructured.Uns
 	return true, nil
 }
 
+// GetEnvelopeType returns the type of the envelope if the object is an envelope configMap.
+func GetEnvelopeType(uObj *unstructured.Unstructured) (placementv1beta1.EnvelopeType, bool) {
+	if uObj.GroupVersionKind() != ConfigMapGVK {
+		return "", false
+	}
+	annotations := uObj.GetAnnotations()
+	switch {
+	case len(annotations[placementv1beta1.EnvelopeFluxSourceAnnotation]) != 0:
+		return placementv1beta1.FluxSourceEnvelopeType, true
+	case len(annotations[placementv1beta1.EnvelopeConfigMapAnnotation]) != 0:
+		return placementv1beta1.ConfigMapEnvelopeType, true
+	}
+	return "", false
+}
+
+// IsPlacementSourceSelector tells if the resource selector selects the resources rendered from a placement source,
+// instead of the placement source itself.
+func IsPlacementSourceSelector(selector placementv1beta1.ClusterResourceSelector) bool {
+	return selector.Group == placementv1beta1.GroupVersion.Group && selector.Kind == placementv1beta1.PlacementSourceKind
+}
+
+// IsReverseOrderDeletion tells if the placed resources of the placement are deleted in waves in the reverse order of
+// the clusters and of their dependencies when the placement is deleted.
+func IsReverseOrderDeletion(crp *placementv1beta1.ClusterResourcePlacement) bool {
+	return crp.Spec.Strategy.DeletionStrategy != nil && crp.Spec.Strategy.DeletionStrategy.Type == placementv1beta1.DeletionStrategyTypeReverseOrder
+}
+
 // IsReservedNamespace indicates if an argued namespace is reserved.
 func IsReservedNamespace(namespace string) bool {
 	return strings.HasPrefix(namespace, fleetPrefix) || strings.HasPrefix(namespace, kubePrefix)
@@ -0,0 +1,124 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package condition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultMaxNamesInMessage is the default number of the names of the children listed in the message of an
+// aggregated condition; the rest of them are only counted.
+const DefaultMaxNamesInMessage = 5
+
+// ChildCondition is a condition of a child object to aggregate into a condition of its parent object, e.g. the
+// applied condition of a work of a binding.
+type ChildCondition struct {
+	// Name is the name of the child object, which is listed in the message of the aggregated condition.
+	Name string
+	// Generation is the current generation of the child object.
+	Generation int64
+	// Condition is the condition of the child object; it is nil if the child has not reported it yet.
+	Condition *metav1.Condition
+}
+
+// Status returns the status of the child condition; it is Unknown if the condition is not reported yet or is
+// reported for an older generation of the child object.
+func (c ChildCondition) Status() metav1.ConditionStatus {
+	if c.Condition == nil || c.Condition.ObservedGeneration != c.Generation {
+		return metav1.ConditionUnknown
+	}
+	return c.Condition.Status
+}
+
+// Reason returns the reason of the child condition; it is empty if the status of the child condition is Unknown.
+func (c ChildCondition) Reason() string {
+	if c.Status() == metav1.ConditionUnknown {
+		return ""
+	}
+	return c.Condition.Reason
+}
+
+// AggregatedReason is a reason which the aggregated condition can take.
+type AggregatedReason struct {
+	// Reason is the reason of the aggregated condition.
+	Reason string
+	// Status is the status of the aggregated condition with the reason.
+	Status metav1.ConditionStatus
+	// Message is the message of the aggregated condition with the reason; a single %s in it is replaced with the
+	// summary of the names of the children which contribute the reason, or a single %d with their number.
+	Message string
+}
+
+// Aggregator aggregates the conditions of many child objects into one condition of their parent object.
+type Aggregator struct {
+	// Type is the type of the aggregated condition.
+	Type string
+	// Reasons are the reasons which the aggregated condition can take, from the highest precedence to the lowest.
+	// The aggregated condition takes the reason of the highest precedence among the reasons contributed by the
+	// children, or the last reason if there are no children.
+	Reasons []AggregatedReason
+	// ReasonFor returns the reason which a child contributes to the aggregated condition; it must be one of Reasons.
+	ReasonFor func(child ChildCondition) string
+	// MaxNamesInMessage is the number of the names of the children listed in the message of the aggregated
+	// condition; DefaultMaxNamesInMessage is used if it is not positive.
+	MaxNamesInMessage int
+}
+
+// Aggregate aggregates the conditions of the children into one condition for the given generation of the parent.
+func (a *Aggregator) Aggregate(children []ChildCondition, generation int64) metav1.Condition {
+	precedence := make(map[string]int, len(a.Reasons))
+	for i := range a.Reasons {
+		precedence[a.Reasons[i].Reason] = i
+	}
+	// the last reason is taken if no child contributes a higher one
+	picked := len(a.Reasons) - 1
+	namesByReason := make(map[int][]string)
+	for _, child := range children {
+		i, ok := precedence[a.ReasonFor(child)]
+		if !ok {
+			continue
+		}
+		namesByReason[i] = append(namesByReason[i], child.Name)
+		if i < picked {
+			picked = i
+		}
+	}
+
+	reason := a.Reasons[picked]
+	message := reason.Message
+	if strings.Contains(message, "%s") {
+		maxNames := a.MaxNamesInMessage
+		if maxNames <= 0 {
+			maxNames = DefaultMaxNamesInMessage
+		}
+		message = fmt.Sprintf(message, SummarizeNames(namesByReason[picked], maxNames))
+	} else if strings.Contains(message, "%d") {
+		message = fmt.Sprintf(message, len(namesByReason[picked]))
+	}
+	return metav1.Condition{
+		Type:               a.Type,
+		Status:             reason.Status,
+		Reason:             reason.Reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	}
+}
+
+// SummarizeNames returns the sorted names joined by commas, listing at most maxNames of them and counting the rest,
+// e.g. "a, b, c and 2 more".
+func SummarizeNames(names []string, maxNames int) string {
+	sorted := make([]string, len(names))
+	copy(sorted, names)
+	sort.Strings(sorted)
+	if maxNames <= 0 || len(sorted) <= maxNames {
+		return strings.Join(sorted, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(sorted[:maxNames], ", "), len(sorted)-maxNames)
+}
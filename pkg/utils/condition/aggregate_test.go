@@ -0,0 +1,159 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package condition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestAggregate(t *testing.T) {
+	aggregator := Aggregator{
+		Type: "Ready",
+		Reasons: []AggregatedReason{
+			{Reason: "Failed", Status: metav1.ConditionFalse, Message: "Children %s failed"},
+			{Reason: "Pending", Status: metav1.ConditionUnknown, Message: "Children %s are pending"},
+			{Reason: "AllReady", Status: metav1.ConditionTrue, Message: "All children are ready"},
+		},
+		ReasonFor: func(child ChildCondition) string {
+			switch child.Status() {
+			case metav1.ConditionTrue:
+				return "AllReady"
+			case metav1.ConditionFalse:
+				return "Failed"
+			default:
+				return "Pending"
+			}
+		},
+		MaxNamesInMessage: 2,
+	}
+	ready := func(name string) ChildCondition {
+		return ChildCondition{Name: name, Generation: 1, Condition: &metav1.Condition{Status: metav1.ConditionTrue, ObservedGeneration: 1}}
+	}
+	failed := func(name string) ChildCondition {
+		return ChildCondition{Name: name, Generation: 1, Condition: &metav1.Condition{Status: metav1.ConditionFalse, ObservedGeneration: 1}}
+	}
+	tests := map[string]struct {
+		children []ChildCondition
+		want     metav1.Condition
+	}{
+		"no children": {
+			want: metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "AllReady", Message: "All children are ready", ObservedGeneration: 3},
+		},
+		"all children are ready": {
+			children: []ChildCondition{ready("a"), ready("b")},
+			want:     metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "AllReady", Message: "All children are ready", ObservedGeneration: 3},
+		},
+		"the children reported for an older generation are pending": {
+			children: []ChildCondition{
+				ready("a"),
+				{Name: "b", Generation: 2, Condition: &metav1.Condition{Status: metav1.ConditionTrue, ObservedGeneration: 1}},
+				{Name: "c", Generation: 1},
+			},
+			want: metav1.Condition{Type: "Ready", Status: metav1.ConditionUnknown, Reason: "Pending", Message: "Children b, c are pending", ObservedGeneration: 3},
+		},
+		"the reason of the highest precedence is taken and the names are summarized": {
+			children: []ChildCondition{failed("e"), ready("a"), failed("d"), {Name: "b", Generation: 1}, failed("c")},
+			want:     metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Failed", Message: "Children c, d and 1 more failed", ObservedGeneration: 3},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := aggregator.Aggregate(tc.children, 3)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Aggregate() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClusterResourcePlacementAggregator(t *testing.T) {
+	cluster := func(name string, status metav1.ConditionStatus) ChildCondition {
+		return ChildCondition{Name: name, Generation: 2, Condition: &metav1.Condition{Status: status, ObservedGeneration: 2}}
+	}
+	tests := map[string]struct {
+		clusters []ChildCondition
+		want     metav1.Condition
+	}{
+		"all the clusters are applied": {
+			clusters: []ChildCondition{cluster("a", metav1.ConditionTrue), cluster("b", metav1.ConditionTrue)},
+			want: metav1.Condition{
+				Type:               string(fleetv1beta1.ClusterResourcePlacementAppliedConditionType),
+				Status:             metav1.ConditionTrue,
+				Reason:             ApplySucceededReason,
+				Message:            "The selected resources are successfully applied to 2 cluster(s)",
+				ObservedGeneration: 2,
+			},
+		},
+		"a cluster failed to apply": {
+			clusters: []ChildCondition{cluster("a", metav1.ConditionTrue), cluster("b", metav1.ConditionFalse)},
+			want: metav1.Condition{
+				Type:               string(fleetv1beta1.ClusterResourcePlacementAppliedConditionType),
+				Status:             metav1.ConditionFalse,
+				Reason:             ApplyFailedReason,
+				Message:            "Failed to apply resources to 1 cluster(s), please check the `failedPlacements` status",
+				ObservedGeneration: 2,
+			},
+		},
+		"the unknown clusters take precedence over the failed ones": {
+			clusters: []ChildCondition{cluster("a", metav1.ConditionUnknown), cluster("b", metav1.ConditionFalse), {Name: "c", Generation: 2}},
+			want: metav1.Condition{
+				Type:               string(fleetv1beta1.ClusterResourcePlacementAppliedConditionType),
+				Status:             metav1.ConditionUnknown,
+				Reason:             ApplyPendingReason,
+				Message:            "There are still 2 cluster(s) in the process of applying the resources on the member cluster",
+				ObservedGeneration: 2,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := AppliedCondition.ClusterResourcePlacementAggregator().Aggregate(tc.clusters, 2)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Aggregate() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeNames(t *testing.T) {
+	tests := map[string]struct {
+		names    []string
+		maxNames int
+		want     string
+	}{
+		"no names": {
+			maxNames: 2,
+			want:     "",
+		},
+		"all the names are listed": {
+			names:    []string{"b", "a"},
+			maxNames: 2,
+			want:     "a, b",
+		},
+		"the rest of the names are counted": {
+			names:    []string{"d", "b", "c", "a"},
+			maxNames: 2,
+			want:     "a, b and 2 more",
+		},
+		"unbounded": {
+			names:    []string{"c", "b", "a"},
+			maxNames: 0,
+			want:     "a, b, c",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := SummarizeNames(tc.names, tc.maxNames); got != tc.want {
+				t.Errorf("SummarizeNames() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -7,8 +7,6 @@ Licensed under the MIT license.
 package condition
 
 import (
-	"fmt"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
@@ -81,6 +79,62 @@ const (
 	// SyncWorkFailedReason is the reason string of placement condition if some works failed to synchronize.
 	SyncWorkFailedReason = "SyncWorkFailed"
 
+	// WorkSyncThrottledReason is the reason string of placement condition if the writes of some works are throttled
+	// by the rate limit of the member cluster.
+	WorkSyncThrottledReason = "WorkSyncThrottled"
+
+	// TenantQuotaExceededReason is the reason string of placement condition if the works are not synchronized because
+	// the resources they place exceed the placement quota of a tenant of the placement.
+	TenantQuotaExceededReason = "TenantQuotaExceeded"
+
+	// ClusterGoneReason is the reason string of placement condition if the target cluster has left the fleet or is
+	// leaving it, so that the works are no longer synchronized to it.
+	ClusterGoneReason = "ClusterGone"
+
+	// ResourceSnapshotNotFoundReason is the reason string of placement condition if the works are not synchronized
+	// because the resource snapshot which the binding points to is not found on the hub cluster.
+	ResourceSnapshotNotFoundReason = "ResourceSnapshotNotFound"
+
+	// OverrideRenderFailedReason is the reason string of placement condition if the works are not synchronized because
+	// the selected resources cannot be read from the snapshots or overridden on the hub cluster.
+	OverrideRenderFailedReason = "OverrideRenderFailed"
+
+	// MemberClusterUnreachableReason is the reason string of placement condition if the works are written on the hub
+	// cluster but the member agent of the target cluster has not joined the fleet, so that it does not pick them up.
+	MemberClusterUnreachableReason = "MemberClusterUnreachable"
+
+	// WorkApplyRejectedReason is the reason string of placement condition if the member agent of the target cluster
+	// rejects some of the works as a whole, e.g. as their signatures cannot be verified.
+	WorkApplyRejectedReason = "WorkApplyRejected"
+
+	// PlacementInactiveReason is the reason string of placement condition if the works are not synchronized because
+	// the placement is outside of its activation window.
+	PlacementInactiveReason = "PlacementInactive"
+
+	// ResourcesDeletingReason is the reason string of binding condition if some works of the binding, i.e. the works of
+	// the resources no longer selected or of the deleting binding, are still being deleted from the target cluster.
+	ResourcesDeletingReason = "ResourcesDeleting"
+
+	// ResourcesDeletedReason is the reason string of binding condition if all the works removed from the binding are
+	// deleted from the target cluster.
+	ResourcesDeletedReason = "ResourcesDeleted"
+
+	// CanaryRollingOutReason is the reason string of the canary condition if the latest resources are still rolling
+	// out to the canary clusters, i.e. not bound or not available yet.
+	CanaryRollingOutReason = "CanaryRollingOut"
+
+	// CanarySoakingReason is the reason string of the canary condition if the latest resources are available on the
+	// canary clusters but have not soaked for the soak time yet.
+	CanarySoakingReason = "CanarySoaking"
+
+	// CanarySucceededReason is the reason string of the canary condition if the latest resources are available on the
+	// canary clusters for the soak time.
+	CanarySucceededReason = "CanarySucceeded"
+
+	// StaleStatusReason is the reason string of placement condition if the target cluster is unreachable, i.e. its
+	// member agent has not sent heartbeats for a while, so that the last reported status may be outdated.
+	StaleStatusReason = "Stale"
+
 	// WorkNeedSyncedReason is the reason string of placement condition if some works are in the processing of synchronizing.
 	WorkNeedSyncedReason = "StillNeedToSyncWork"
 
@@ -93,10 +147,26 @@ const (
 	// WorkNotAvailableReason is the reason string of placement condition if some works are not available.
 	WorkNotAvailableReason = "NotAllWorkAreAvailable"
 
+	// ProvisioningReason is the reason string of placement condition if the resources which are not available yet
+	// are all provisioning infrastructure according to their availability rules, e.g. the Crossplane claims.
+	ProvisioningReason = "Provisioning"
+
 	// AllWorkAvailableReason is the reason string of placement condition if all works are available.
 	AllWorkAvailableReason = "AllWorkAreAvailable"
 )
 
+// IsMemberSideFailureReason tells if the reason of a false WorkSynchronized condition is caused by the target
+// cluster, i.e. the cluster is gone or unreachable, or its member agent rejects the works, instead of by the hub
+// cluster, e.g. a missing resource snapshot, a failed override or an exceeded quota.
+func IsMemberSideFailureReason(reason string) bool {
+	switch reason {
+	case ClusterGoneReason, MemberClusterUnreachableReason, WorkApplyRejectedReason:
+		return true
+	default:
+		return false
+	}
+}
+
 // EqualCondition compares one condition with another; it ignores the LastTransitionTime and Message fields,
 // and will consider the ObservedGeneration values from the two conditions a match if the current
 // condition is newer.
@@ -246,134 +316,59 @@ func (c ResourceCondition) UnknownResourceConditionPerCluster(generation int64)
 	}[c]
 }
 
-// UnknownClusterResourcePlacementCondition returns the unknown cluster resource placement condition.
-func (c ResourceCondition) UnknownClusterResourcePlacementCondition(generation int64, clusterCount int) metav1.Condition {
-	return []metav1.Condition{
-		{
-			Status:             metav1.ConditionUnknown,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementRolloutStartedConditionType),
-			Reason:             RolloutStartedUnknownReason,
-			Message:            fmt.Sprintf("There are still %d cluster(s) in the process of deciding whether to roll out the latest resources or not", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionUnknown,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementOverriddenConditionType),
-			Reason:             OverriddenPendingReason,
-			Message:            fmt.Sprintf("There are still %d cluster(s) in the process of overriding the selected resources if there is any override defined", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionUnknown,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType),
-			Reason:             WorkSynchronizedUnknownReason,
-			Message:            fmt.Sprintf("There are still %d cluster(s) in the process of creating or updating the work object(s) in the hub cluster", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionUnknown,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementAppliedConditionType),
-			Reason:             ApplyPendingReason,
-			Message:            fmt.Sprintf("There are still %d cluster(s) in the process of applying the resources on the member cluster", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionUnknown,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementAvailableConditionType),
-			Reason:             AvailableUnknownReason,
-			Message:            fmt.Sprintf("There are still %d cluster(s) in the process of checking the availability of the selected resources", clusterCount),
-			ObservedGeneration: generation,
-		},
-	}[c]
+// ClusterResourcePlacementAggregator returns the aggregator which rolls the per cluster conditions of the resource
+// condition up into the condition of the cluster resource placement: it is Unknown if any cluster is still unknown,
+// False if any cluster is false, and True otherwise.
+func (c ResourceCondition) ClusterResourcePlacementAggregator() *Aggregator {
+	return &Aggregator{
+		Type:      string(c.ClusterResourcePlacementConditionType()),
+		Reasons:   clusterResourcePlacementReasons[c],
+		ReasonFor: clusterResourcePlacementReasonFor(c),
+	}
 }
 
-// FalseClusterResourcePlacementCondition returns the false cluster resource placement condition.
-func (c ResourceCondition) FalseClusterResourcePlacementCondition(generation int64, clusterCount int) metav1.Condition {
-	return []metav1.Condition{
-		{
-			Status:             metav1.ConditionFalse,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementRolloutStartedConditionType),
-			Reason:             RolloutNotStartedYetReason,
-			Message:            fmt.Sprintf("The rollout is being blocked by the rollout strategy in %d cluster(s)", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionFalse,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementOverriddenConditionType),
-			Reason:             OverriddenFailedReason,
-			Message:            fmt.Sprintf("Failed to override resources in %d cluster(s)", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionFalse,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType),
-			Reason:             WorkNotSynchronizedYetReason,
-			Message:            fmt.Sprintf("There are %d cluster(s) which have not finished creating or updating work(s) yet", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionFalse,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementAppliedConditionType),
-			Reason:             ApplyFailedReason,
-			Message:            fmt.Sprintf("Failed to apply resources to %d cluster(s), please check the `failedPlacements` status", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionFalse,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementAvailableConditionType),
-			Reason:             NotAvailableYetReason,
-			Message:            fmt.Sprintf("The selected resources in %d cluster(s) are still not available yet", clusterCount),
-			ObservedGeneration: generation,
-		},
-	}[c]
+// clusterResourcePlacementReasonFor returns the function which maps the status of a per cluster condition to the
+// reason of the aggregated condition.
+func clusterResourcePlacementReasonFor(c ResourceCondition) func(child ChildCondition) string {
+	reasons := clusterResourcePlacementReasons[c]
+	return func(child ChildCondition) string {
+		switch child.Status() {
+		case metav1.ConditionFalse:
+			return reasons[1].Reason
+		case metav1.ConditionTrue:
+			return reasons[2].Reason
+		default:
+			return reasons[0].Reason
+		}
+	}
 }
 
-// TrueClusterResourcePlacementCondition returns the true cluster resource placement condition.
-func (c ResourceCondition) TrueClusterResourcePlacementCondition(generation int64, clusterCount int) metav1.Condition {
-	return []metav1.Condition{
-		{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementRolloutStartedConditionType),
-			Reason:             RolloutStartedReason,
-			Message:            fmt.Sprintf("All %d cluster(s) start rolling out the latest resource", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementOverriddenConditionType),
-			Reason:             OverriddenSucceededReason,
-			Message:            fmt.Sprintf("The selected resources are successfully overridden in %d cluster(s)", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementWorkSynchronizedConditionType),
-			Reason:             WorkSynchronizedReason,
-			Message:            fmt.Sprintf("Works(s) are succcesfully created or updated in %d target cluster(s)' namespaces", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementAppliedConditionType),
-			Reason:             ApplySucceededReason,
-			Message:            fmt.Sprintf("The selected resources are successfully applied to %d cluster(s)", clusterCount),
-			ObservedGeneration: generation,
-		},
-		{
-			Status:             metav1.ConditionTrue,
-			Type:               string(fleetv1beta1.ClusterResourcePlacementAvailableConditionType),
-			Reason:             AvailableReason,
-			Message:            fmt.Sprintf("The selected resources in %d cluster(s) are available now", clusterCount),
-			ObservedGeneration: generation,
-		},
-	}[c]
+// clusterResourcePlacementReasons are the unknown, false and true reasons of the cluster resource placement
+// conditions, in the order of their precedence; the %d in the messages is replaced with the number of the clusters.
+var clusterResourcePlacementReasons = [TotalCondition][]AggregatedReason{
+	RolloutStartedCondition: {
+		{Status: metav1.ConditionUnknown, Reason: RolloutStartedUnknownReason, Message: "There are still %d cluster(s) in the process of deciding whether to roll out the latest resources or not"},
+		{Status: metav1.ConditionFalse, Reason: RolloutNotStartedYetReason, Message: "The rollout is being blocked by the rollout strategy in %d cluster(s)"},
+		{Status: metav1.ConditionTrue, Reason: RolloutStartedReason, Message: "All %d cluster(s) start rolling out the latest resource"},
+	},
+	OverriddenCondition: {
+		{Status: metav1.ConditionUnknown, Reason: OverriddenPendingReason, Message: "There are still %d cluster(s) in the process of overriding the selected resources if there is any override defined"},
+		{Status: metav1.ConditionFalse, Reason: OverriddenFailedReason, Message: "Failed to override resources in %d cluster(s)"},
+		{Status: metav1.ConditionTrue, Reason: OverriddenSucceededReason, Message: "The selected resources are successfully overridden in %d cluster(s)"},
+	},
+	WorkSynchronizedCondition: {
+		{Status: metav1.ConditionUnknown, Reason: WorkSynchronizedUnknownReason, Message: "There are still %d cluster(s) in the process of creating or updating the work object(s) in the hub cluster"},
+		{Status: metav1.ConditionFalse, Reason: WorkNotSynchronizedYetReason, Message: "There are %d cluster(s) which have not finished creating or updating work(s) yet"},
+		{Status: metav1.ConditionTrue, Reason: WorkSynchronizedReason, Message: "Works(s) are succcesfully created or updated in %d target cluster(s)' namespaces"},
+	},
+	AppliedCondition: {
+		{Status: metav1.ConditionUnknown, Reason: ApplyPendingReason, Message: "There are still %d cluster(s) in the process of applying the resources on the member cluster"},
+		{Status: metav1.ConditionFalse, Reason: ApplyFailedReason, Message: "Failed to apply resources to %d cluster(s), please check the `failedPlacements` status"},
+		{Status: metav1.ConditionTrue, Reason: ApplySucceededReason, Message: "The selected resources are successfully applied to %d cluster(s)"},
+	},
+	AvailableCondition: {
+		{Status: metav1.ConditionUnknown, Reason: AvailableUnknownReason, Message: "There are still %d cluster(s) in the process of checking the availability of the selected resources"},
+		{Status: metav1.ConditionFalse, Reason: NotAvailableYetReason, Message: "The selected resources in %d cluster(s) are still not available yet"},
+		{Status: metav1.ConditionTrue, Reason: AvailableReason, Message: "The selected resources in %d cluster(s) are available now"},
+	},
 }
-
-type conditionStatus int // internal type to be used when populating the CRP status
-
-const (
-	UnknownConditionStatus conditionStatus = iota
-	FalseConditionStatus
-	TrueConditionStatus
-	TotalConditionStatus
-)
@@ -111,6 +111,49 @@ func TestEqualCondition(t *testing.T) {
 	}
 }
 
+func TestIsMemberSideFailureReason(t *testing.T) {
+	tests := map[string]struct {
+		reason string
+		want   bool
+	}{
+		"resource snapshot not found is a hub side failure": {
+			reason: ResourceSnapshotNotFoundReason,
+			want:   false,
+		},
+		"override render failure is a hub side failure": {
+			reason: OverrideRenderFailedReason,
+			want:   false,
+		},
+		"exceeded tenant quota is a hub side failure": {
+			reason: TenantQuotaExceededReason,
+			want:   false,
+		},
+		"failed work sync is a hub side failure": {
+			reason: SyncWorkFailedReason,
+			want:   false,
+		},
+		"cluster gone is a member side failure": {
+			reason: ClusterGoneReason,
+			want:   true,
+		},
+		"unreachable member cluster is a member side failure": {
+			reason: MemberClusterUnreachableReason,
+			want:   true,
+		},
+		"rejected work is a member side failure": {
+			reason: WorkApplyRejectedReason,
+			want:   true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsMemberSideFailureReason(tt.reason); got != tt.want {
+				t.Errorf("IsMemberSideFailureReason(%s) = %v, want %v", tt.reason, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestEqualConditionIgnoreReason tests the EqualConditionIgnoreReason function.
 func TestEqualConditionIgnoreReason(t *testing.T) {
 	testCases := []struct {
@@ -22,6 +22,7 @@ import (
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	"go.goms.io/fleet/pkg/utils/controller/metrics"
+	fieldindex "go.goms.io/fleet/pkg/utils/index"
 	"go.goms.io/fleet/pkg/utils/keys"
 	"go.goms.io/fleet/pkg/utils/labels"
 )
@@ -124,9 +125,14 @@ func NewDeleteIgnoreNotFoundError(err error) error {
 // The item will be re-queued if "ReconcileFunc" returns an error, maximum re-queue times defined by "maxRetries" above,
 // after that the item will be discarded from the queue.
 type Controller interface {
-	// Enqueue generates the key of 'obj' according to a 'KeyFunc' then adds the 'item' to queue immediately.
+	// Enqueue generates the key of 'obj' according to a 'KeyFunc' then adds the 'item' to queue immediately
+	// with the high priority.
 	Enqueue(obj interface{})
 
+	// EnqueueWithPriority generates the key of 'obj' according to a 'KeyFunc' then adds the 'item' to queue
+	// immediately with the given priority; the high priority items are always processed first.
+	EnqueueWithPriority(obj interface{}, priority Priority)
+
 	// Run starts a certain number of concurrent workers to reconcile the items and will never stop until
 	// the context is closed or canceled
 	Run(ctx context.Context, workerNumber int) error
@@ -159,8 +165,8 @@ type controller struct {
 	// reconcileFunc is the function that process keys from the queue.
 	reconcileFunc ReconcileFunc
 
-	// queue allowing parallel processing of resources.
-	queue workqueue.RateLimitingInterface
+	// queue allowing parallel processing of resources, with the user-triggered changes processed first.
+	queue *priorityQueue
 }
 
 // NewController returns a controller which can process resource periodically. We create the queue during the creation
@@ -170,18 +176,22 @@ func NewController(Name string, KeyFunc KeyFunc, ReconcileFunc ReconcileFunc, ra
 		name:          Name,
 		keyFunc:       KeyFunc,
 		reconcileFunc: ReconcileFunc,
-		queue:         workqueue.NewNamedRateLimitingQueue(rateLimiter, Name),
+		queue:         newPriorityQueue(Name, rateLimiter),
 	}
 }
 
 func (w *controller) Enqueue(obj interface{}) {
+	w.EnqueueWithPriority(obj, PriorityHigh)
+}
+
+func (w *controller) EnqueueWithPriority(obj interface{}, priority Priority) {
 	key, err := w.keyFunc(obj)
 	if err != nil {
 		klog.ErrorS(err, "failed to enqueue a resource", "controller", w.name)
 		return
 	}
 
-	w.queue.Add(key)
+	w.queue.Add(key, priority)
 }
 
 // Run can only be run once as we will shut down the queue on stop.
@@ -284,6 +294,8 @@ func (w *controller) initMetrics(workerNumber int) {
 	metrics.FleetReconcileTotal.WithLabelValues(w.name, labelRequeue).Add(0)
 	metrics.FleetReconcileTotal.WithLabelValues(w.name, labelSuccess).Add(0)
 	metrics.FleetWorkerCount.WithLabelValues(w.name).Set(float64(workerNumber))
+	metrics.FleetWorkQueueDepth.WithLabelValues(w.name, PriorityHigh.String()).Add(0)
+	metrics.FleetWorkQueueDepth.WithLabelValues(w.name, PriorityLow.String()).Add(0)
 }
 
 // NamespaceKeyFunc generates a namespaced key for any objects.
@@ -320,12 +332,12 @@ func FetchAllClusterResourceSnapshots(ctx context.Context, k8Client client.Clien
 			klog.ErrorS(err, "Master resource snapshot has invalid resource index", "clusterResourceSnapshot", klog.KObj(masterResourceSnapshot))
 			return nil, NewUnexpectedBehaviorError(err)
 		}
-		resourceIndexLabelMatcher := client.MatchingLabels{
-			fleetv1beta1.ResourceIndexLabel: strconv.Itoa(index),
-			fleetv1beta1.CRPTrackingLabel:   crp,
+		// the snapshots are looked up by the index so that the snapshots of all the placements are not scanned
+		resourceIndexMatcher := client.MatchingFields{
+			fieldindex.ResourceSnapshotGroupField: fieldindex.ResourceSnapshotGroupKey(crp, index),
 		}
 		resourceSnapshotList := &fleetv1beta1.ClusterResourceSnapshotList{}
-		if err := k8Client.List(ctx, resourceSnapshotList, resourceIndexLabelMatcher); err != nil {
+		if err := k8Client.List(ctx, resourceSnapshotList, resourceIndexMatcher); err != nil {
 			klog.ErrorS(err, "Failed to list all the resource snapshot", "clusterResourcePlacement", crp)
 			return nil, NewAPIServerError(true, err)
 		}
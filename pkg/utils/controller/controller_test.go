@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	fieldindex "go.goms.io/fleet/pkg/utils/index"
 )
 
 func TestNewUnexpectedBehaviorError(t *testing.T) {
@@ -428,6 +429,7 @@ func TestFetchAllClusterResourceSnapshots(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().
 				WithScheme(scheme).
 				WithObjects(objects...).
+				WithIndex(&fleetv1beta1.ClusterResourceSnapshot{}, fieldindex.ResourceSnapshotGroupField, fieldindex.ResourceSnapshotGroup).
 				Build()
 			got, err := FetchAllClusterResourceSnapshots(context.Background(), fakeClient, crp, tc.master)
 			if gotErr, wantErr := err != nil, tc.wantErr != nil; gotErr != wantErr || !errors.Is(err, tc.wantErr) {
@@ -49,6 +49,13 @@ var (
 		Name: "fleet_workload_active_workers",
 		Help: "Number of currently used workers per controller",
 	}, []string{"controller"})
+
+	// FleetWorkQueueDepth is a prometheus metric which holds the number of
+	// the items in each priority lane of the queue per controller.
+	FleetWorkQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fleet_workload_queue_depth",
+		Help: "Number of the queued items per controller and priority",
+	}, []string{"controller", "priority"})
 )
 
 func init() {
@@ -58,5 +65,6 @@ func init() {
 		FleetReconcileTime,
 		FleetWorkerCount,
 		FleetActiveWorkers,
+		FleetWorkQueueDepth,
 	)
 }
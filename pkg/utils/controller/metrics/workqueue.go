@@ -0,0 +1,119 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// WorkQueueMetricsProvider provides the standard client-go work queue metrics, e.g. workqueue_depth and
+// workqueue_adds_total, for the queues of the fleet controllers, which are not client-go queues. The metrics are
+// reported with the collectors that controller-runtime registers for its own queues, so that they are labeled by the
+// queue name as before.
+var WorkQueueMetricsProvider workqueue.MetricsProvider = newWorkQueueMetricsProvider()
+
+type workQueueMetricsProvider struct {
+	depth                   *prometheus.GaugeVec
+	adds                    *prometheus.CounterVec
+	latency                 *prometheus.HistogramVec
+	workDuration            *prometheus.HistogramVec
+	unfinished              *prometheus.GaugeVec
+	longestRunningProcessor *prometheus.GaugeVec
+	retries                 *prometheus.CounterVec
+}
+
+func newWorkQueueMetricsProvider() *workQueueMetricsProvider {
+	// the same options as the ones of controller-runtime, otherwise the collectors are not the same ones
+	return &workQueueMetricsProvider{
+		depth: registerOrExisting(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.DepthKey,
+			Help:      "Current depth of workqueue",
+		}, []string{"name"})),
+		adds: registerOrExisting(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.AddsKey,
+			Help:      "Total number of adds handled by workqueue",
+		}, []string{"name"})),
+		latency: registerOrExisting(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.QueueLatencyKey,
+			Help:      "How long in seconds an item stays in workqueue before being requested",
+			Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 12),
+		}, []string{"name"})),
+		workDuration: registerOrExisting(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.WorkDurationKey,
+			Help:      "How long in seconds processing an item from workqueue takes.",
+			Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 12),
+		}, []string{"name"})),
+		unfinished: registerOrExisting(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.UnfinishedWorkKey,
+			Help: "How many seconds of work has been done that " +
+				"is in progress and hasn't been observed by work_duration. Large " +
+				"values indicate stuck threads. One can deduce the number of stuck " +
+				"threads by observing the rate at which this increases.",
+		}, []string{"name"})),
+		longestRunningProcessor: registerOrExisting(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.LongestRunningProcessorKey,
+			Help: "How many seconds has the longest running " +
+				"processor for workqueue been running.",
+		}, []string{"name"})),
+		retries: registerOrExisting(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metrics.WorkQueueSubsystem,
+			Name:      metrics.RetriesKey,
+			Help:      "Total number of retries handled by workqueue",
+		}, []string{"name"})),
+	}
+}
+
+// registerOrExisting registers the collector, or returns the same collector that is already registered.
+func registerOrExisting[T prometheus.Collector](collector T) T {
+	if err := metrics.Registry.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
+
+func (p *workQueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+func (p *workQueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.adds.WithLabelValues(name)
+}
+
+func (p *workQueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.latency.WithLabelValues(name)
+}
+
+func (p *workQueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.workDuration.WithLabelValues(name)
+}
+
+func (p *workQueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.unfinished.WithLabelValues(name)
+}
+
+func (p *workQueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.longestRunningProcessor.WithLabelValues(name)
+}
+
+func (p *workQueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.retries.WithLabelValues(name)
+}
@@ -0,0 +1,349 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"go.goms.io/fleet/pkg/utils/controller/metrics"
+)
+
+// Priority is the priority of an item in the queue of a controller.
+type Priority int
+
+const (
+	// PriorityLow is the priority of the items enqueued for periodic resyncs, status-only updates and retries.
+	PriorityLow Priority = iota
+	// PriorityHigh is the priority of the items enqueued for user-triggered changes, e.g. a new placement or a new
+	// snapshot; these items are always processed before the low priority ones.
+	PriorityHigh
+)
+
+// String returns the name of the priority, which is used as the metric label.
+func (p Priority) String() string {
+	if p == PriorityHigh {
+		return "high"
+	}
+	return "low"
+}
+
+// unfinishedWorkUpdatePeriod is how often the metrics of the items being processed are updated, the same as the
+// client-go queues.
+const unfinishedWorkUpdatePeriod = 500 * time.Millisecond
+
+// delayedItem is an item waiting to be added to the queue.
+type delayedItem struct {
+	readyAt time.Time
+	timer   *time.Timer
+}
+
+// priorityQueue is a rate limiting work queue with two priority lanes. Like the queues of client-go, an item is
+// stored at most once, and is never processed by more than one worker at the same time; an item added while it is
+// being processed is processed again once it is done. An item added with a higher priority than the one it is
+// queued with is moved to the higher priority lane.
+//
+// The items added after a delay, i.e. the retries and the requeues, are added with the low priority.
+//
+// The queue reports the standard client-go work queue metrics besides its depth per priority.
+type priorityQueue struct {
+	name        string
+	rateLimiter workqueue.RateLimiter
+	metrics     *queueMetrics
+
+	cond *sync.Cond
+	// lanes holds the queued items, indexed by their priority.
+	lanes [PriorityHigh + 1][]QueueKey
+	// dirty holds the priorities of the items which need processing.
+	dirty map[QueueKey]Priority
+	// processing holds the items which are being processed.
+	processing map[QueueKey]struct{}
+	// delayed holds the items waiting to be added.
+	delayed      map[QueueKey]*delayedItem
+	shuttingDown bool
+	// stopCh stops updating the metrics of the items being processed once the queue is shut down.
+	stopCh chan struct{}
+}
+
+// newPriorityQueue returns a priority queue which delays the retries of the items with the rate limiter.
+func newPriorityQueue(name string, rateLimiter workqueue.RateLimiter) *priorityQueue {
+	q := &priorityQueue{
+		name:        name,
+		rateLimiter: rateLimiter,
+		metrics:     newQueueMetrics(name, metrics.WorkQueueMetricsProvider),
+		cond:        sync.NewCond(&sync.Mutex{}),
+		dirty:       make(map[QueueKey]Priority),
+		processing:  make(map[QueueKey]struct{}),
+		delayed:     make(map[QueueKey]*delayedItem),
+		stopCh:      make(chan struct{}),
+	}
+	go q.updateUnfinishedWorkLoop()
+	return q
+}
+
+// Add marks the item as needing processing with the given priority.
+func (q *priorityQueue) Add(key QueueKey, priority Priority) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if queued, found := q.dirty[key]; found {
+		if priority <= queued {
+			return
+		}
+		q.dirty[key] = priority
+		if _, isProcessing := q.processing[key]; !isProcessing {
+			q.remove(queued, key)
+			q.push(priority, key)
+		}
+		return
+	}
+	q.dirty[key] = priority
+	if _, isProcessing := q.processing[key]; isProcessing {
+		// The item is queued again once it is done.
+		return
+	}
+	q.metrics.add(key)
+	q.push(priority, key)
+}
+
+// AddAfter adds the item with the low priority after the given duration, unless it is already waiting to be added
+// sooner.
+func (q *priorityQueue) AddAfter(key QueueKey, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(key, PriorityLow)
+		return
+	}
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	readyAt := time.Now().Add(duration)
+	if waiting, found := q.delayed[key]; found {
+		if !waiting.readyAt.After(readyAt) {
+			return
+		}
+		waiting.timer.Stop()
+	}
+	item := &delayedItem{readyAt: readyAt}
+	item.timer = time.AfterFunc(duration, func() {
+		q.cond.L.Lock()
+		if q.delayed[key] == item {
+			delete(q.delayed, key)
+		}
+		q.cond.L.Unlock()
+		q.Add(key, PriorityLow)
+	})
+	q.delayed[key] = item
+}
+
+// AddRateLimited adds the item with the low priority after the rate limiter says it is ok.
+func (q *priorityQueue) AddRateLimited(key QueueKey) {
+	q.cond.L.Lock()
+	if !q.shuttingDown {
+		q.metrics.retry()
+	}
+	q.cond.L.Unlock()
+	q.AddAfter(key, q.rateLimiter.When(key))
+}
+
+// Forget indicates that the item is finished being retried.
+func (q *priorityQueue) Forget(key QueueKey) {
+	q.rateLimiter.Forget(key)
+}
+
+// Get blocks until it can return an item to be processed, preferring the high priority ones. It returns true if the
+// queue is shut down and drained.
+func (q *priorityQueue) Get() (QueueKey, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.len() == 0 {
+		return nil, true
+	}
+	priority := PriorityHigh
+	for len(q.lanes[priority]) == 0 {
+		priority--
+	}
+	key := q.lanes[priority][0]
+	q.lanes[priority][0] = nil
+	q.lanes[priority] = q.lanes[priority][1:]
+	metrics.FleetWorkQueueDepth.WithLabelValues(q.name, priority.String()).Dec()
+	q.metrics.get(key)
+	q.processing[key] = struct{}{}
+	delete(q.dirty, key)
+	return key, false
+}
+
+// Done marks the item as done processing, and queues it again if it is added while being processed.
+func (q *priorityQueue) Done(key QueueKey) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, key)
+	q.metrics.done(key)
+	if priority, found := q.dirty[key]; found {
+		q.metrics.add(key)
+		q.push(priority, key)
+	}
+}
+
+// Len returns the number of the items queued with the given priority.
+func (q *priorityQueue) Len(priority Priority) int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.lanes[priority])
+}
+
+// ShutDown makes the queue ignore the new items and the workers quit once the queue is drained.
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if !q.shuttingDown {
+		close(q.stopCh)
+	}
+	q.shuttingDown = true
+	for key, waiting := range q.delayed {
+		waiting.timer.Stop()
+		delete(q.delayed, key)
+	}
+	q.cond.Broadcast()
+}
+
+// ShuttingDown returns whether the queue is shut down.
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+// len returns the number of the queued items; the caller must hold the lock.
+func (q *priorityQueue) len() int {
+	n := 0
+	for _, lane := range q.lanes {
+		n += len(lane)
+	}
+	return n
+}
+
+// push appends the item to the lane of the priority; the caller must hold the lock.
+func (q *priorityQueue) push(priority Priority, key QueueKey) {
+	q.lanes[priority] = append(q.lanes[priority], key)
+	metrics.FleetWorkQueueDepth.WithLabelValues(q.name, priority.String()).Inc()
+	q.cond.Signal()
+}
+
+// remove removes the item from the lane of the priority; the caller must hold the lock.
+func (q *priorityQueue) remove(priority Priority, key QueueKey) {
+	lane := q.lanes[priority]
+	for i := range lane {
+		if lane[i] == key {
+			q.lanes[priority] = append(lane[:i], lane[i+1:]...)
+			metrics.FleetWorkQueueDepth.WithLabelValues(q.name, priority.String()).Dec()
+			return
+		}
+	}
+}
+
+// updateUnfinishedWorkLoop updates the metrics of the items being processed periodically until the queue is shut down.
+func (q *priorityQueue) updateUnfinishedWorkLoop() {
+	ticker := time.NewTicker(unfinishedWorkUpdatePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stopCh:
+			return
+		case <-ticker.C:
+			q.cond.L.Lock()
+			q.metrics.updateUnfinishedWork()
+			q.cond.L.Unlock()
+		}
+	}
+}
+
+// queueMetrics reports the standard client-go work queue metrics of a queue; the caller must hold the lock of the
+// queue.
+type queueMetrics struct {
+	depth                   workqueue.GaugeMetric
+	adds                    workqueue.CounterMetric
+	latency                 workqueue.HistogramMetric
+	workDuration            workqueue.HistogramMetric
+	unfinishedWorkSeconds   workqueue.SettableGaugeMetric
+	longestRunningProcessor workqueue.SettableGaugeMetric
+	retries                 workqueue.CounterMetric
+
+	// addTimes holds when the queued items are added.
+	addTimes map[QueueKey]time.Time
+	// processingStartTimes holds when the items being processed are handed out.
+	processingStartTimes map[QueueKey]time.Time
+}
+
+func newQueueMetrics(name string, provider workqueue.MetricsProvider) *queueMetrics {
+	return &queueMetrics{
+		depth:                   provider.NewDepthMetric(name),
+		adds:                    provider.NewAddsMetric(name),
+		latency:                 provider.NewLatencyMetric(name),
+		workDuration:            provider.NewWorkDurationMetric(name),
+		unfinishedWorkSeconds:   provider.NewUnfinishedWorkSecondsMetric(name),
+		longestRunningProcessor: provider.NewLongestRunningProcessorSecondsMetric(name),
+		retries:                 provider.NewRetriesMetric(name),
+		addTimes:                make(map[QueueKey]time.Time),
+		processingStartTimes:    make(map[QueueKey]time.Time),
+	}
+}
+
+// add records that the item is queued.
+func (m *queueMetrics) add(key QueueKey) {
+	m.adds.Inc()
+	m.depth.Inc()
+	if _, found := m.addTimes[key]; !found {
+		m.addTimes[key] = time.Now()
+	}
+}
+
+// get records that the item is handed out to be processed.
+func (m *queueMetrics) get(key QueueKey) {
+	m.depth.Dec()
+	now := time.Now()
+	m.processingStartTimes[key] = now
+	if addedAt, found := m.addTimes[key]; found {
+		m.latency.Observe(now.Sub(addedAt).Seconds())
+		delete(m.addTimes, key)
+	}
+}
+
+// done records that the item is done processing.
+func (m *queueMetrics) done(key QueueKey) {
+	if startedAt, found := m.processingStartTimes[key]; found {
+		m.workDuration.Observe(time.Since(startedAt).Seconds())
+		delete(m.processingStartTimes, key)
+	}
+}
+
+// retry records that an item is retried.
+func (m *queueMetrics) retry() {
+	m.retries.Inc()
+}
+
+// updateUnfinishedWork updates how long the items being processed have been processed.
+func (m *queueMetrics) updateUnfinishedWork() {
+	var total, longest float64
+	now := time.Now()
+	for _, startedAt := range m.processingStartTimes {
+		elapsed := now.Sub(startedAt).Seconds()
+		total += elapsed
+		if elapsed > longest {
+			longest = elapsed
+		}
+	}
+	m.unfinishedWorkSeconds.Set(total)
+	m.longestRunningProcessor.Set(longest)
+}
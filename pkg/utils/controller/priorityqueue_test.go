@@ -0,0 +1,139 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/util/workqueue"
+
+	"go.goms.io/fleet/pkg/utils/controller/metrics"
+)
+
+func drain(t *testing.T, q *priorityQueue, n int) []QueueKey {
+	got := make([]QueueKey, 0, n)
+	for i := 0; i < n; i++ {
+		key, shutdown := q.Get()
+		if shutdown {
+			t.Fatalf("Get() = shutdown, want an item")
+		}
+		got = append(got, key)
+		q.Done(key)
+	}
+	return got
+}
+
+func TestPriorityQueue_Order(t *testing.T) {
+	q := newPriorityQueue("test", workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	q.Add("resync-1", PriorityLow)
+	q.Add("resync-2", PriorityLow)
+	q.Add("new-crp", PriorityHigh)
+	// A duplicate item is queued once.
+	q.Add("new-crp", PriorityHigh)
+	// An item added with a lower priority keeps its priority.
+	q.Add("new-crp", PriorityLow)
+	// An item added with a higher priority is promoted.
+	q.Add("resync-2", PriorityHigh)
+
+	if got := q.Len(PriorityHigh); got != 2 {
+		t.Errorf("Len(PriorityHigh) = %d, want 2", got)
+	}
+	if got := q.Len(PriorityLow); got != 1 {
+		t.Errorf("Len(PriorityLow) = %d, want 1", got)
+	}
+	want := []QueueKey{"new-crp", "resync-2", "resync-1"}
+	if diff := cmp.Diff(want, drain(t, q, 3)); diff != "" {
+		t.Errorf("Get() order mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestPriorityQueue_AddWhileProcessing(t *testing.T) {
+	q := newPriorityQueue("test", workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	q.Add("crp", PriorityLow)
+	key, _ := q.Get()
+	// The item is not handed to another worker while it is being processed.
+	q.Add("crp", PriorityHigh)
+	q.Add("other", PriorityLow)
+	if got := q.Len(PriorityHigh); got != 0 {
+		t.Errorf("Len(PriorityHigh) = %d while the item is being processed, want 0", got)
+	}
+	q.Done(key)
+
+	want := []QueueKey{"crp", "other"}
+	if diff := cmp.Diff(want, drain(t, q, 2)); diff != "" {
+		t.Errorf("Get() order mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestPriorityQueue_AddAfter(t *testing.T) {
+	q := newPriorityQueue("test", workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	q.AddAfter("crp", time.Hour)
+	// The earlier one of the delayed adds wins.
+	q.AddAfter("crp", 10*time.Millisecond)
+	q.AddAfter("crp", time.Hour)
+
+	key, shutdown := q.Get()
+	if shutdown || key != "crp" {
+		t.Fatalf("Get() = %v, %t, want crp, false", key, shutdown)
+	}
+	q.Done(key)
+	if got := len(q.delayed); got != 0 {
+		t.Errorf("delayed items = %d, want 0", got)
+	}
+}
+
+func TestPriorityQueue_ShutDown(t *testing.T) {
+	q := newPriorityQueue("test", workqueue.DefaultControllerRateLimiter())
+	q.Add("crp", PriorityHigh)
+	q.AddAfter("delayed", time.Hour)
+	q.ShutDown()
+
+	// The queued items are drained before the workers quit.
+	if diff := cmp.Diff([]QueueKey{"crp"}, drain(t, q, 1)); diff != "" {
+		t.Errorf("Get() mismatch (-want, +got):\n%s", diff)
+	}
+	q.Add("ignored", PriorityHigh)
+	if _, shutdown := q.Get(); !shutdown {
+		t.Errorf("Get() = not shutdown, want shutdown")
+	}
+}
+
+func TestPriorityQueue_WorkQueueMetrics(t *testing.T) {
+	q := newPriorityQueue("test-metrics", workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	provider := metrics.WorkQueueMetricsProvider
+
+	q.Add("a", PriorityLow)
+	q.Add("b", PriorityLow)
+	// Promoting a queued item does not add it again.
+	q.Add("a", PriorityHigh)
+	if got := testutil.ToFloat64(provider.NewAddsMetric("test-metrics").(prometheus.Counter)); got != 2 {
+		t.Errorf("workqueue_adds_total = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(provider.NewDepthMetric("test-metrics").(prometheus.Gauge)); got != 2 {
+		t.Errorf("workqueue_depth = %v, want 2", got)
+	}
+
+	drain(t, q, 2)
+	if got := testutil.ToFloat64(provider.NewDepthMetric("test-metrics").(prometheus.Gauge)); got != 0 {
+		t.Errorf("workqueue_depth after draining = %v, want 0", got)
+	}
+
+	q.AddRateLimited("a")
+	if got := testutil.ToFloat64(provider.NewRetriesMetric("test-metrics").(prometheus.Counter)); got != 1 {
+		t.Errorf("workqueue_retries_total = %v, want 1", got)
+	}
+}
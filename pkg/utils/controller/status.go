@@ -0,0 +1,69 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StatusUpdateBackoff is the backoff used by UpdateStatusWithRetry between the retries.
+//
+// It is jittered so that the writers which conflict with each other do not retry at the same time again, and it
+// gives up after a few hundred milliseconds in total, so that a worker is not blocked for long when the API server
+// is overloaded; the object is requeued with the error instead.
+var StatusUpdateBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 10 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.5,
+}
+
+// UpdateStatusWithRetry applies mutateFn to the object and updates its status, retrying on conflicts and on the
+// transient errors of the API server, i.e. when it is unavailable, times out or throttles the request.
+//
+// On a conflict, the latest object is fetched into obj and mutateFn is applied to it again before the next retry, so
+// that the changes made by the other writers in the meantime, e.g. the conditions owned by other controllers, are
+// preserved; mutateFn should therefore only set the fields owned by the caller, e.g. with meta.SetStatusCondition.
+//
+// The retries stop with the conflict error if the generation of the object has changed in the meantime, as the
+// status computed by the caller is for an older spec and the object should be reconciled again instead.
+func UpdateStatusWithRetry[T client.Object](ctx context.Context, c client.Client, obj T, mutateFn func(T), opts ...client.SubResourceUpdateOption) error {
+	generation := obj.GetGeneration()
+	mutateFn(obj)
+	generationChanged := false
+	var lastErr error
+	return retry.OnError(StatusUpdateBackoff,
+		func(err error) bool {
+			if apierrors.IsConflict(err) {
+				return !generationChanged
+			}
+			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
+		},
+		func() error {
+			if apierrors.IsConflict(lastErr) {
+				key := client.ObjectKeyFromObject(obj)
+				if err := c.Get(ctx, key, obj); err != nil {
+					return err
+				}
+				if obj.GetGeneration() != generation {
+					klog.V(2).InfoS("Stop retrying the status update as the object has a new generation",
+						"object", key, "generation", generation, "latestGeneration", obj.GetGeneration())
+					generationChanged = true
+					return lastErr
+				}
+				mutateFn(obj)
+			}
+			lastErr = c.Status().Update(ctx, obj, opts...)
+			return lastErr
+		})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestUpdateStatusWithRetry(t *testing.T) {
+	rolloutStarted := metav1.Condition{
+		Type:               string(fleetv1beta1.ResourceBindingRolloutStarted),
+		Status:             metav1.ConditionTrue,
+		Reason:             "RolloutStarted",
+		ObservedGeneration: 1,
+	}
+	workSynchronized := metav1.Condition{
+		Type:               string(fleetv1beta1.ResourceBindingWorkSynchronized),
+		Status:             metav1.ConditionTrue,
+		Reason:             "WorkSynchronized",
+		ObservedGeneration: 1,
+	}
+	tests := map[string]struct {
+		// concurrentWrite is made by another writer after the caller reads the binding.
+		concurrentWrite func(binding *fleetv1beta1.ClusterResourceBinding)
+		// updateErrs are returned by the status updates in order before the update goes through.
+		updateErrs     []error
+		wantErr        func(error) bool
+		wantGeneration int64
+		wantConditions []metav1.Condition
+	}{
+		"no conflict": {
+			wantGeneration: 1,
+			wantConditions: []metav1.Condition{rolloutStarted},
+		},
+		"the conditions written by another writer are kept on conflicts": {
+			concurrentWrite: func(binding *fleetv1beta1.ClusterResourceBinding) {
+				binding.SetConditions(workSynchronized)
+			},
+			wantGeneration: 1,
+			wantConditions: []metav1.Condition{workSynchronized, rolloutStarted},
+		},
+		"the retries stop when the generation changes": {
+			concurrentWrite: func(binding *fleetv1beta1.ClusterResourceBinding) {
+				binding.Generation = 2
+			},
+			wantErr:        apierrors.IsConflict,
+			wantGeneration: 2,
+		},
+		"transient errors are retried": {
+			updateErrs:     []error{apierrors.NewTooManyRequests("throttled", 1), apierrors.NewServerTimeout(fleetv1beta1.GroupVersion.WithResource("clusterresourcebindings").GroupResource(), "update", 1)},
+			wantGeneration: 1,
+			wantConditions: []metav1.Condition{rolloutStarted},
+		},
+		"other errors are not retried": {
+			updateErrs: []error{errors.New("internal error"), nil},
+			wantErr: func(err error) bool {
+				return err.Error() == "internal error"
+			},
+			wantGeneration: 1,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+				t.Fatalf("AddToScheme() = %v, want nil", err)
+			}
+			updateErrs := tc.updateErrs
+			stored := &fleetv1beta1.ClusterResourceBinding{ObjectMeta: metav1.ObjectMeta{Name: "binding", Generation: 1}}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stored).
+				WithStatusSubresource(&fleetv1beta1.ClusterResourceBinding{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						if len(updateErrs) > 0 {
+							err := updateErrs[0]
+							updateErrs = updateErrs[1:]
+							if err != nil {
+								return err
+							}
+						}
+						return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+					},
+				}).Build()
+
+			binding := &fleetv1beta1.ClusterResourceBinding{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(stored), binding); err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			if tc.concurrentWrite != nil {
+				latest := binding.DeepCopy()
+				tc.concurrentWrite(latest)
+				if err := fakeClient.Update(ctx, latest); err != nil {
+					t.Fatalf("Update() = %v, want nil", err)
+				}
+				// the status of the object returned by the update is the stored one
+				tc.concurrentWrite(latest)
+				if err := fakeClient.Status().Update(ctx, latest); err != nil {
+					t.Fatalf("Status().Update() = %v, want nil", err)
+				}
+			}
+
+			err := UpdateStatusWithRetry(ctx, fakeClient, binding, func(binding *fleetv1beta1.ClusterResourceBinding) {
+				binding.SetConditions(rolloutStarted)
+			})
+			if tc.wantErr == nil && err != nil {
+				t.Fatalf("UpdateStatusWithRetry() = %v, want nil", err)
+			}
+			if tc.wantErr != nil && (err == nil || !tc.wantErr(err)) {
+				t.Fatalf("UpdateStatusWithRetry() = %v, want a different error", err)
+			}
+
+			got := &fleetv1beta1.ClusterResourceBinding{}
+			if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(stored), got); err != nil {
+				t.Fatalf("Get() = %v, want nil", err)
+			}
+			if got.Generation != tc.wantGeneration {
+				t.Errorf("UpdateStatusWithRetry() generation = %d, want %d", got.Generation, tc.wantGeneration)
+			}
+			if diff := cmp.Diff(tc.wantConditions, got.Status.Conditions, cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("UpdateStatusWithRetry() conditions mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,12 @@ const (
 	// DefaultUnavailablePeriodSeconds is the default period of time we consider a newly applied workload as unavailable.
 	DefaultUnavailablePeriodSeconds = 60
 
+	// DefaultCanaryClustersValue is the default number of the canary clusters in the canary config.
+	DefaultCanaryClustersValue = "10%"
+
+	// DefaultCanarySoakSeconds is the default period of time the new resources soak on the canary clusters.
+	DefaultCanarySoakSeconds = 300
+
 	// DefaultMaxSkewValue is the default degree to which resources may be unevenly distributed.
 	DefaultMaxSkewValue = 1
 
@@ -62,7 +68,7 @@ func SetDefaultsClusterResourcePlacement(obj *fleetv1beta1.ClusterResourcePlacem
 	if strategy.Type == "" {
 		strategy.Type = fleetv1beta1.RollingUpdateRolloutStrategyType
 	}
-	if strategy.Type == fleetv1beta1.RollingUpdateRolloutStrategyType {
+	if strategy.Type == fleetv1beta1.RollingUpdateRolloutStrategyType || strategy.Type == fleetv1beta1.CanaryRolloutStrategyType {
 		if strategy.RollingUpdate == nil {
 			strategy.RollingUpdate = &fleetv1beta1.RollingUpdateConfig{}
 		}
@@ -76,6 +82,17 @@ func SetDefaultsClusterResourcePlacement(obj *fleetv1beta1.ClusterResourcePlacem
 			strategy.RollingUpdate.UnavailablePeriodSeconds = ptr.To(DefaultUnavailablePeriodSeconds)
 		}
 	}
+	if strategy.Type == fleetv1beta1.CanaryRolloutStrategyType {
+		if strategy.Canary == nil {
+			strategy.Canary = &fleetv1beta1.CanaryConfig{}
+		}
+		if strategy.Canary.Clusters == nil {
+			strategy.Canary.Clusters = ptr.To(intstr.FromString(DefaultCanaryClustersValue))
+		}
+		if strategy.Canary.SoakSeconds == nil {
+			strategy.Canary.SoakSeconds = ptr.To(DefaultCanarySoakSeconds)
+		}
+	}
 
 	if obj.Spec.Strategy.ApplyStrategy == nil {
 		obj.Spec.Strategy.ApplyStrategy = &fleetv1beta1.ApplyStrategy{}
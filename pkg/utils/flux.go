@@ -0,0 +1,81 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Those are the API groups and kinds of the Flux objects that fleet understands.
+const (
+	FluxSourceGroup    = "source.toolkit.fluxcd.io"
+	FluxKustomizeGroup = "kustomize.toolkit.fluxcd.io"
+	FluxHelmGroup      = "helm.toolkit.fluxcd.io"
+
+	FluxKustomizationKind = "Kustomization"
+	FluxHelmReleaseKind   = "HelmRelease"
+)
+
+var (
+	// FluxSourceKinds are the kinds of the Flux sources which the Kustomizations and the HelmReleases can reconcile.
+	FluxSourceKinds = sets.New("GitRepository", "OCIRepository", "HelmRepository", "Bucket")
+
+	fluxGroups = sets.New(FluxSourceGroup, FluxKustomizeGroup, FluxHelmGroup)
+)
+
+// IsFluxGroup tells if the API group is one of the Flux toolkit groups whose objects report a Ready condition.
+func IsFluxGroup(group string) bool {
+	return fluxGroups.Has(group)
+}
+
+// FluxSourceReference identifies the Flux source referenced by a Flux source envelope.
+type FluxSourceReference struct {
+	Kind string
+	Name string
+}
+
+// ParseFluxSourceReference parses the Flux source reference in the format of {kind}/{name}.
+func ParseFluxSourceReference(value string) (FluxSourceReference, error) {
+	kind, name, ok := strings.Cut(value, "/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return FluxSourceReference{}, fmt.Errorf("invalid Flux source reference %q, want {kind}/{name}", value)
+	}
+	if !FluxSourceKinds.Has(kind) {
+		return FluxSourceReference{}, fmt.Errorf("invalid Flux source kind %q, want one of %v", kind, sets.List(FluxSourceKinds))
+	}
+	return FluxSourceReference{Kind: kind, Name: name}, nil
+}
+
+// BindFluxSourceReference makes the Flux Kustomization or HelmRelease reconcile the referenced source by setting
+// its source reference if it is not set. It fails if the object already references another source. The other
+// objects are left untouched.
+func BindFluxSourceReference(uObj *unstructured.Unstructured, sourceRef FluxSourceReference) error {
+	var path []string
+	switch uObj.GroupVersionKind().GroupKind().String() {
+	case FluxKustomizationKind + "." + FluxKustomizeGroup:
+		path = []string{"spec", "sourceRef"}
+	case FluxHelmReleaseKind + "." + FluxHelmGroup:
+		path = []string{"spec", "chart", "spec", "sourceRef"}
+	default:
+		return nil
+	}
+	current, found, err := unstructured.NestedStringMap(uObj.Object, path...)
+	if err != nil {
+		return fmt.Errorf("invalid source reference of %s %s/%s: %w", uObj.GetKind(), uObj.GetNamespace(), uObj.GetName(), err)
+	}
+	if found && (current["kind"] != sourceRef.Kind || current["name"] != sourceRef.Name) {
+		return fmt.Errorf("%s %s/%s references the Flux source %s/%s instead of %s/%s of the envelope", uObj.GetKind(),
+			uObj.GetNamespace(), uObj.GetName(), current["kind"], current["name"], sourceRef.Kind, sourceRef.Name)
+	}
+	if found {
+		return nil
+	}
+	return unstructured.SetNestedStringMap(uObj.Object, map[string]string{"kind": sourceRef.Kind, "name": sourceRef.Name}, path...)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseFluxSourceReference(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    FluxSourceReference
+		wantErr bool
+	}{
+		"git repository": {
+			value: "GitRepository/podinfo",
+			want:  FluxSourceReference{Kind: "GitRepository", Name: "podinfo"},
+		},
+		"missing name": {
+			value:   "GitRepository/",
+			wantErr: true,
+		},
+		"missing kind": {
+			value:   "podinfo",
+			wantErr: true,
+		},
+		"unknown kind": {
+			value:   "ConfigMap/podinfo",
+			wantErr: true,
+		},
+		"namespaced name": {
+			value:   "GitRepository/flux-system/podinfo",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseFluxSourceReference(tc.value)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ParseFluxSourceReference() = %v, want error %t", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ParseFluxSourceReference() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBindFluxSourceReference(t *testing.T) {
+	sourceRef := FluxSourceReference{Kind: "GitRepository", Name: "podinfo"}
+	boundSourceRef := map[string]interface{}{"kind": "GitRepository", "name": "podinfo"}
+	tests := map[string]struct {
+		obj     *unstructured.Unstructured
+		want    *unstructured.Unstructured
+		wantErr bool
+	}{
+		"kustomization without source": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       FluxKustomizationKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec":       map[string]interface{}{"path": "./kustomize"},
+			}},
+			want: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       FluxKustomizationKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec":       map[string]interface{}{"path": "./kustomize", "sourceRef": boundSourceRef},
+			}},
+		},
+		"helm release without source": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "helm.toolkit.fluxcd.io/v2beta1",
+				"kind":       FluxHelmReleaseKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec": map[string]interface{}{
+					"chart": map[string]interface{}{"spec": map[string]interface{}{"chart": "./charts/podinfo"}},
+				},
+			}},
+			want: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "helm.toolkit.fluxcd.io/v2beta1",
+				"kind":       FluxHelmReleaseKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec": map[string]interface{}{
+					"chart": map[string]interface{}{"spec": map[string]interface{}{"chart": "./charts/podinfo", "sourceRef": boundSourceRef}},
+				},
+			}},
+		},
+		"kustomization with the same source": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       FluxKustomizationKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec":       map[string]interface{}{"sourceRef": boundSourceRef},
+			}},
+			want: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       FluxKustomizationKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec":       map[string]interface{}{"sourceRef": boundSourceRef},
+			}},
+		},
+		"kustomization with another source": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+				"kind":       FluxKustomizationKind,
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+				"spec": map[string]interface{}{
+					"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "podinfo"},
+				},
+			}},
+			wantErr: true,
+		},
+		"other object": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+			}},
+			want: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "podinfo"},
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := BindFluxSourceReference(tc.obj, sourceRef)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("BindFluxSourceReference() = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, tc.obj); diff != "" {
+				t.Errorf("BindFluxSourceReference() object mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
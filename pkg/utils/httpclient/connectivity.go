@@ -0,0 +1,71 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"k8s.io/client-go/rest"
+)
+
+// ParseProxyURL parses the URL of an HTTP, HTTPS or SOCKS5 proxy.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: the scheme must be http, https or socks5", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: the host is missing", proxyURL)
+	}
+	return u, nil
+}
+
+// ParsePrivateEndpoint parses the URL of a private endpoint, e.g. a private link, of an api server.
+func ParsePrivateEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid private endpoint %q: it must be an https URL", endpoint)
+	}
+	return u, nil
+}
+
+// ApplyConnectivity configures the rest config to reach the api server through the proxy and/or the private endpoint.
+// The api server certificate is still verified against the host name of the original server URL when the private
+// endpoint is used. Empty values are ignored, in which case client-go falls back to the proxy environment variables.
+func ApplyConnectivity(config *rest.Config, proxyURL, privateEndpoint string) error {
+	if proxyURL != "" {
+		u, err := ParseProxyURL(proxyURL)
+		if err != nil {
+			return err
+		}
+		config.Proxy = http.ProxyURL(u)
+	}
+	if privateEndpoint != "" {
+		u, err := ParsePrivateEndpoint(privateEndpoint)
+		if err != nil {
+			return err
+		}
+		server, err := url.Parse(config.Host)
+		if err != nil {
+			return fmt.Errorf("invalid server URL %q: %w", config.Host, err)
+		}
+		if config.TLSClientConfig.ServerName == "" {
+			config.TLSClientConfig.ServerName = server.Hostname()
+		}
+		config.Host = u.String()
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestApplyConnectivity(t *testing.T) {
+	tests := map[string]struct {
+		proxyURL        string
+		privateEndpoint string
+		wantHost        string
+		wantServerName  string
+		wantProxy       string
+		wantErr         bool
+	}{
+		"no proxy or private endpoint": {
+			wantHost: "https://hub.domain.com",
+		},
+		"http proxy": {
+			proxyURL:  "http://proxy.domain.com:3128",
+			wantHost:  "https://hub.domain.com",
+			wantProxy: "http://proxy.domain.com:3128",
+		},
+		"socks5 proxy": {
+			proxyURL:  "socks5://proxy.domain.com:1080",
+			wantHost:  "https://hub.domain.com",
+			wantProxy: "socks5://proxy.domain.com:1080",
+		},
+		"invalid proxy scheme": {
+			proxyURL: "ftp://proxy.domain.com",
+			wantErr:  true,
+		},
+		"proxy without host": {
+			proxyURL: "http://",
+			wantErr:  true,
+		},
+		"private endpoint": {
+			privateEndpoint: "https://10.0.0.4:443",
+			wantHost:        "https://10.0.0.4:443",
+			wantServerName:  "hub.domain.com",
+		},
+		"private endpoint with proxy": {
+			proxyURL:        "https://proxy.domain.com",
+			privateEndpoint: "https://hub.privatelink.domain.com",
+			wantHost:        "https://hub.privatelink.domain.com",
+			wantServerName:  "hub.domain.com",
+			wantProxy:       "https://proxy.domain.com",
+		},
+		"private endpoint without https": {
+			privateEndpoint: "http://10.0.0.4",
+			wantErr:         true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := &rest.Config{Host: "https://hub.domain.com"}
+			err := ApplyConnectivity(config, tc.proxyURL, tc.privateEndpoint)
+			if tc.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.wantHost, config.Host)
+			assert.Equal(t, tc.wantServerName, config.TLSClientConfig.ServerName)
+			if tc.wantProxy == "" {
+				assert.Nil(t, config.Proxy)
+				return
+			}
+			req, _ := http.NewRequest(http.MethodGet, config.Host, nil)
+			proxy, err := config.Proxy(req)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.wantProxy, proxy.String())
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package index provides the field indexes of the fleet objects in the informer cache, so that the hot reconcilers
+// look up the objects of a placement or a binding without scanning all the objects of their kind in the cache.
+package index
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// BindingCRPField indexes the clusterResourceBindings by the name of their placement.
+	BindingCRPField = "bindingCRP"
+	// WorkBindingField indexes the works by the name of their binding.
+	WorkBindingField = "workBinding"
+	// ResourceSnapshotCRPField indexes the clusterResourceSnapshots by the name of their placement.
+	ResourceSnapshotCRPField = "resourceSnapshotCRP"
+	// ResourceSnapshotGroupField indexes the clusterResourceSnapshots by the name of their placement and their
+	// resource index, i.e. by the group of the snapshots which are taken of the selected resources at once.
+	ResourceSnapshotGroupField = "resourceSnapshotGroup"
+)
+
+// BindingCRP returns the name of the placement of a clusterResourceBinding to index it with.
+func BindingCRP(obj client.Object) []string {
+	return labelValue(obj, fleetv1beta1.CRPTrackingLabel)
+}
+
+// WorkBinding returns the name of the binding of a work to index it with.
+func WorkBinding(obj client.Object) []string {
+	return labelValue(obj, fleetv1beta1.ParentBindingLabel)
+}
+
+// ResourceSnapshotCRP returns the name of the placement of a clusterResourceSnapshot to index it with.
+func ResourceSnapshotCRP(obj client.Object) []string {
+	return labelValue(obj, fleetv1beta1.CRPTrackingLabel)
+}
+
+// ResourceSnapshotGroup returns the group key of a clusterResourceSnapshot to index it with.
+func ResourceSnapshotGroup(obj client.Object) []string {
+	crp, index := obj.GetLabels()[fleetv1beta1.CRPTrackingLabel], obj.GetLabels()[fleetv1beta1.ResourceIndexLabel]
+	if crp == "" || index == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s/%s", crp, index)}
+}
+
+// ResourceSnapshotGroupKey returns the key with which the snapshots of the placement at the resource index are
+// looked up in the ResourceSnapshotGroupField index.
+func ResourceSnapshotGroupKey(crp string, index int) string {
+	return fmt.Sprintf("%s/%s", crp, strconv.Itoa(index))
+}
+
+// AddBindingCRPIndex adds the BindingCRPField index to the cache.
+func AddBindingCRPIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &fleetv1beta1.ClusterResourceBinding{}, BindingCRPField, BindingCRP)
+}
+
+// AddWorkBindingIndex adds the WorkBindingField index to the cache.
+func AddWorkBindingIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &fleetv1beta1.Work{}, WorkBindingField, WorkBinding)
+}
+
+// AddResourceSnapshotCRPIndex adds the ResourceSnapshotCRPField index to the cache.
+func AddResourceSnapshotCRPIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &fleetv1beta1.ClusterResourceSnapshot{}, ResourceSnapshotCRPField, ResourceSnapshotCRP)
+}
+
+// AddResourceSnapshotGroupIndex adds the ResourceSnapshotGroupField index to the cache.
+func AddResourceSnapshotGroupIndex(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &fleetv1beta1.ClusterResourceSnapshot{}, ResourceSnapshotGroupField, ResourceSnapshotGroup)
+}
+
+// labelValue returns the value of the label of the object to index it with, or nothing if the label is not set.
+func labelValue(obj client.Object, key string) []string {
+	if value := obj.GetLabels()[key]; value != "" {
+		return []string{value}
+	}
+	return nil
+}
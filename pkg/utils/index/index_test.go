@@ -0,0 +1,82 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package index
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestResourceSnapshotGroup(t *testing.T) {
+	tests := map[string]struct {
+		labels map[string]string
+		want   []string
+	}{
+		"snapshot of a placement": {
+			labels: map[string]string{fleetv1beta1.CRPTrackingLabel: "crp-1", fleetv1beta1.ResourceIndexLabel: "3"},
+			want:   []string{ResourceSnapshotGroupKey("crp-1", 3)},
+		},
+		"snapshot without the resource index": {
+			labels: map[string]string{fleetv1beta1.CRPTrackingLabel: "crp-1"},
+		},
+		"snapshot without the placement": {
+			labels: map[string]string{fleetv1beta1.ResourceIndexLabel: "3"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			snapshot := &fleetv1beta1.ClusterResourceSnapshot{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}}
+			if diff := cmp.Diff(tc.want, ResourceSnapshotGroup(snapshot)); diff != "" {
+				t.Errorf("ResourceSnapshotGroup() mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResourceSnapshotGroupLookup(t *testing.T) {
+	newSnapshot := func(name, crp, index string) *fleetv1beta1.ClusterResourceSnapshot {
+		return &fleetv1beta1.ClusterResourceSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{fleetv1beta1.CRPTrackingLabel: crp, fleetv1beta1.ResourceIndexLabel: index},
+			},
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := fleetv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add the scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			newSnapshot("crp-1-1-snapshot", "crp-1", "1"),
+			newSnapshot("crp-1-1-snapshot-0", "crp-1", "1"),
+			newSnapshot("crp-1-2-snapshot", "crp-1", "2"),
+			// the snapshot of another placement
+			newSnapshot("crp-11-snapshot", "crp-11", "1"),
+		).
+		WithIndex(&fleetv1beta1.ClusterResourceSnapshot{}, ResourceSnapshotGroupField, ResourceSnapshotGroup).
+		Build()
+
+	snapshotList := &fleetv1beta1.ClusterResourceSnapshotList{}
+	if err := c.List(context.Background(), snapshotList, client.MatchingFields{ResourceSnapshotGroupField: ResourceSnapshotGroupKey("crp-1", 1)}); err != nil {
+		t.Fatalf("List() = %v, want nil", err)
+	}
+	var got []string
+	for i := range snapshotList.Items {
+		got = append(got, snapshotList.Items[i].Name)
+	}
+	if diff := cmp.Diff([]string{"crp-1-1-snapshot", "crp-1-1-snapshot-0"}, got); diff != "" {
+		t.Errorf("snapshots of the group mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -11,9 +11,16 @@ import (
 	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
@@ -44,8 +51,13 @@ type Manager interface {
 
 	// Lister returns a generic lister used to get 'resource' from informer's store.
 	// The informer for 'resource' will be created if not exist, but without any event handler.
+	// The objects of a metadata-only resource have their apiVersion, kind and metadata only.
 	Lister(resource schema.GroupVersionResource) cache.GenericLister
 
+	// IsMetadataOnly returns if the informer of the dynamic resource caches the metadata of the objects only, in
+	// which case the full objects must be fetched from the API server with the dynamic client.
+	IsMetadataOnly(resource schema.GroupVersionResource) bool
+
 	// GetNameSpaceScopedResources returns the list of namespace scoped resources we are watching.
 	GetNameSpaceScopedResources() []schema.GroupVersionResource
 
@@ -59,18 +71,46 @@ type Manager interface {
 	GetClient() dynamic.Interface
 }
 
+// Option is the function for configuring an informer manager.
+type Option func(*informerManagerImpl)
+
+// WithMetadataOnlyResources makes the informer manager watch the dynamic resources for which isMetadataOnly returns
+// true with metadata-only informers built on the metadata client, so that only the metadata of their objects, e.g. of
+// the large secrets and config maps, are cached; it is enough to detect the changes of the objects by their resource
+// versions and labels.
+func WithMetadataOnlyResources(client metadata.Interface, isMetadataOnly func(gvk schema.GroupVersionKind) bool) Option {
+	return func(s *informerManagerImpl) {
+		s.metadataInformerFactory = metadatainformer.NewSharedInformerFactory(client, s.defaultResync)
+		s.isMetadataOnly = isMetadataOnly
+	}
+}
+
+// WithRESTMapperScopes makes the informer manager look up the scopes of the resources it does not watch with the REST
+// mapper, e.g. when the resources are discovered and watched by the resource change detector in another process.
+func WithRESTMapperScopes(restMapper meta.RESTMapper) Option {
+	return func(s *informerManagerImpl) {
+		s.restMapper = restMapper
+	}
+}
+
 // NewInformerManager constructs a new instance of informerManagerImpl.
 // defaultResync with value '0' means no re-sync.
-func NewInformerManager(client dynamic.Interface, defaultResync time.Duration, parentCh <-chan struct{}) Manager {
+func NewInformerManager(client dynamic.Interface, defaultResync time.Duration, parentCh <-chan struct{}, opts ...Option) Manager {
 	// TODO: replace this with plain context
 	ctx, cancel := ContextForChannel(parentCh)
-	return &informerManagerImpl{
-		dynamicClient:   client,
-		ctx:             ctx,
-		cancel:          cancel,
-		informerFactory: dynamicinformer.NewDynamicSharedInformerFactory(client, defaultResync),
-		apiResources:    make(map[schema.GroupVersionKind]*APIResourceMeta),
+	s := &informerManagerImpl{
+		dynamicClient:     client,
+		ctx:               ctx,
+		cancel:            cancel,
+		defaultResync:     defaultResync,
+		informerFactory:   dynamicinformer.NewDynamicSharedInformerFactory(client, defaultResync),
+		apiResources:      make(map[schema.GroupVersionKind]*APIResourceMeta),
+		metadataResources: make(map[schema.GroupVersionResource]bool),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // APIResourceMeta contains the gvk and associated metadata about an api resource
@@ -101,12 +141,25 @@ type informerManagerImpl struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	defaultResync time.Duration
+
 	// informerFactory is the client-go built-in informer factory that can create an informer given a gvr.
 	informerFactory dynamicinformer.DynamicSharedInformerFactory
 
+	// metadataInformerFactory creates the metadata-only informers of the resources for which isMetadataOnly returns
+	// true; both are nil if no resource is watched with a metadata-only informer.
+	metadataInformerFactory metadatainformer.SharedInformerFactory
+	isMetadataOnly          func(gvk schema.GroupVersionKind) bool
+
 	// the apiResources map collects all the api resources we watch
-	apiResources  map[schema.GroupVersionKind]*APIResourceMeta
-	resourcesLock sync.RWMutex
+	apiResources map[schema.GroupVersionKind]*APIResourceMeta
+	// the metadataResources map collects the dynamic resources we watch with metadata-only informers
+	metadataResources map[schema.GroupVersionResource]bool
+	resourcesLock     sync.RWMutex
+
+	// restMapper looks up the scopes of the resources which are not in apiResources; it is nil if the scopes of the
+	// resources are only known once they are watched.
+	restMapper meta.RESTMapper
 }
 
 func (s *informerManagerImpl) AddDynamicResources(dynResources []APIResourceMeta, handler cache.ResourceEventHandler, listComplete bool) {
@@ -117,9 +170,15 @@ func (s *informerManagerImpl) AddDynamicResources(dynResources []APIResourceMeta
 		if !exist {
 			newRes.isPresent = true
 			s.apiResources[newRes.GroupVersionKind] = &newRes
+			if s.isMetadataOnly != nil && s.isMetadataOnly(newRes.GroupVersionKind) {
+				s.metadataResources[newRes.GroupVersionResource] = true
+				if err := s.metadataInformerFactory.ForResource(newRes.GroupVersionResource).Informer().SetTransform(metadataOnlyTransform(newRes.GroupVersionKind)); err != nil {
+					klog.ErrorS(err, "Failed to set the transform of a metadata-only informer", "res", newRes)
+				}
+			}
 			// TODO (rzhang): remember the ResourceEventHandlerRegistration and remove it when the resource is deleted
 			// TODO: handle error which only happens if the informer is stopped
-			_, _ = s.informerFactory.ForResource(newRes.GroupVersionResource).Informer().AddEventHandler(handler)
+			_, _ = s.informerFor(newRes.GroupVersionResource).Informer().AddEventHandler(handler)
 			klog.InfoS("Added an informer for a new resource", "res", newRes)
 		} else if !dynRes.isPresent {
 			// we just mark it as enabled as we should not add another eventhandler to the informer as it's still
@@ -170,15 +229,33 @@ func (s *informerManagerImpl) AddStaticResource(resource APIResourceMeta, handle
 
 func (s *informerManagerImpl) IsInformerSynced(resource schema.GroupVersionResource) bool {
 	// TODO: use a lazy initialized sync map to reduce the number of informer sync look ups
-	return s.informerFactory.ForResource(resource).Informer().HasSynced()
+	return s.informerFor(resource).Informer().HasSynced()
 }
 
 func (s *informerManagerImpl) Lister(resource schema.GroupVersionResource) cache.GenericLister {
-	return s.informerFactory.ForResource(resource).Lister()
+	return s.informerFor(resource).Lister()
+}
+
+func (s *informerManagerImpl) IsMetadataOnly(resource schema.GroupVersionResource) bool {
+	s.resourcesLock.RLock()
+	defer s.resourcesLock.RUnlock()
+	return s.metadataResources[resource]
+}
+
+// informerFor returns the metadata-only informer of the resource if it is watched with one, or the dynamic informer
+// otherwise.
+func (s *informerManagerImpl) informerFor(resource schema.GroupVersionResource) informers.GenericInformer {
+	if s.IsMetadataOnly(resource) {
+		return s.metadataInformerFactory.ForResource(resource)
+	}
+	return s.informerFactory.ForResource(resource)
 }
 
 func (s *informerManagerImpl) Start() {
 	s.informerFactory.Start(s.ctx.Done())
+	if s.metadataInformerFactory != nil {
+		s.metadataInformerFactory.Start(s.ctx.Done())
+	}
 }
 
 func (s *informerManagerImpl) GetClient() dynamic.Interface {
@@ -187,6 +264,9 @@ func (s *informerManagerImpl) GetClient() dynamic.Interface {
 
 func (s *informerManagerImpl) WaitForCacheSync() {
 	s.informerFactory.WaitForCacheSync(s.ctx.Done())
+	if s.metadataInformerFactory != nil {
+		s.metadataInformerFactory.WaitForCacheSync(s.ctx.Done())
+	}
 }
 
 func (s *informerManagerImpl) GetNameSpaceScopedResources() []schema.GroupVersionResource {
@@ -207,16 +287,47 @@ func (s *informerManagerImpl) IsClusterScopedResources(gvk schema.GroupVersionKi
 	defer s.resourcesLock.RUnlock()
 
 	resMeta, exist := s.apiResources[gvk]
-	if !exist {
+	if exist {
+		return resMeta.IsClusterScoped
+	}
+	if s.restMapper == nil {
 		return false
 	}
-	return resMeta.IsClusterScoped
+	mapping, err := s.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		klog.V(2).InfoS("Failed to look up the scope of the resource", "gvk", gvk, "err", err)
+		return false
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot
 }
 
 func (s *informerManagerImpl) Stop() {
 	s.cancel()
 }
 
+// metadataOnlyTransform returns the transform of a metadata-only informer which turns the partial object metadata
+// into an unstructured object of the kind with the metadata only, so that the event handlers and the listers see the
+// objects of the metadata-only informers the same way as the ones of the dynamic informers. The managed fields are
+// dropped to save memory.
+func metadataOnlyTransform(gvk schema.GroupVersionKind) cache.TransformFunc {
+	return func(obj interface{}) (interface{}, error) {
+		partial, ok := obj.(*metav1.PartialObjectMetadata)
+		if !ok {
+			// e.g. a tombstone of an object which is transformed already
+			return obj, nil
+		}
+		objMeta := partial.ObjectMeta.DeepCopy()
+		objMeta.ManagedFields = nil
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(objMeta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the metadata of %s %s: %w", gvk, klog.KObj(partial), err)
+		}
+		uObj := &unstructured.Unstructured{Object: map[string]interface{}{"metadata": content}}
+		uObj.SetGroupVersionKind(gvk)
+		return uObj, nil
+	}
+}
+
 // ContextForChannel derives a child context from a parent channel.
 //
 // The derived context's Done channel is closed when the returned cancel function
@@ -6,15 +6,51 @@ Licensed under the MIT license.
 package keys
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"path"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
 
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 )
 
+var (
+	// ErrNotRuntimeObject indicates the object is not a runtime object.
+	ErrNotRuntimeObject = errors.New("object is not a runtime object")
+
+	// ErrNoObjectMeta indicates the object has no object metadata.
+	ErrNoObjectMeta = errors.New("object has no object metadata")
+
+	// ErrUnknownGroupVersionKind indicates the group, version and kind of the object can neither be read from its
+	// TypeMeta nor inferred from the scheme.
+	ErrUnknownGroupVersionKind = errors.New("group version kind of the object is unknown")
+
+	// ErrEmptyTombstone indicates the tombstone of the informer cache carries no deleted object.
+	ErrEmptyTombstone = errors.New("tombstone has no deleted object")
+
+	// ErrInvalidKey indicates the string is not a key printed by ClusterWideKey.String.
+	ErrInvalidKey = errors.New("invalid cluster wide key")
+
+	// ErrInvalidKeyPattern indicates some globs of the key pattern are malformed.
+	ErrInvalidKeyPattern = errors.New("invalid cluster wide key pattern")
+)
+
+const (
+	// keyFieldSeparator separates the group version, the kind and the namespace key of a printed ClusterWideKey.
+	keyFieldSeparator = ", "
+	// keyKindPrefix is the prefix of the kind of a printed ClusterWideKey.
+	keyKindPrefix = "kind="
+)
+
 // ClusterWideKey is the object key which is a unique identifier under a cluster, across all resources.
 type ClusterWideKey struct {
 	fleetv1alpha1.ResourceIdentifier
@@ -26,6 +62,90 @@ func (k ClusterWideKey) String() string {
 	return fmt.Sprintf("%s, kind=%s, %s", k.GroupVersion().String(), k.Kind, k.NamespaceKey())
 }
 
+// Hash returns a stable hash of the key, which is the same across processes and releases for the same key.
+func (k ClusterWideKey) Hash() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(k.String())))
+}
+
+// Matches returns whether the key is matched by the pattern.
+// A malformed glob of the pattern matches nothing; use KeyPattern.Validate to reject such patterns beforehand.
+func (k ClusterWideKey) Matches(pattern KeyPattern) bool {
+	return matchGlob(pattern.Group, k.Group) && matchGlob(pattern.Version, k.Version) &&
+		matchGlob(pattern.Kind, k.Kind) && matchGlob(pattern.Namespace, k.Namespace) && matchGlob(pattern.Name, k.Name)
+}
+
+// ParseClusterWideKey parses a key printed by ClusterWideKey.String with format:
+// "<GroupVersion>, kind=<Kind>, <NamespaceKey>"
+func ParseClusterWideKey(str string) (ClusterWideKey, error) {
+	key := ClusterWideKey{}
+
+	fields := strings.Split(str, keyFieldSeparator)
+	if len(fields) != 3 {
+		return key, fmt.Errorf("%w: %q does not have the format \"<GroupVersion>, kind=<Kind>, <NamespaceKey>\"", ErrInvalidKey, str)
+	}
+	gv, err := schema.ParseGroupVersion(fields[0])
+	if err != nil || gv.Version == "" {
+		return key, fmt.Errorf("%w: %q has an invalid group version %q", ErrInvalidKey, str, fields[0])
+	}
+	kind, found := strings.CutPrefix(fields[1], keyKindPrefix)
+	if !found || kind == "" {
+		return key, fmt.Errorf("%w: %q has an invalid kind %q", ErrInvalidKey, str, fields[1])
+	}
+	var namespace, name string
+	switch parts := strings.Split(fields[2], "/"); len(parts) {
+	case 1:
+		name = parts[0]
+	case 2:
+		namespace, name = parts[0], parts[1]
+		if namespace == "" {
+			return key, fmt.Errorf("%w: %q has an empty namespace", ErrInvalidKey, str)
+		}
+	default:
+		return key, fmt.Errorf("%w: %q has an invalid namespace key %q", ErrInvalidKey, str, fields[2])
+	}
+	if name == "" {
+		return key, fmt.Errorf("%w: %q has an empty name", ErrInvalidKey, str)
+	}
+
+	key.Group = gv.Group
+	key.Version = gv.Version
+	key.Kind = kind
+	key.Namespace = namespace
+	key.Name = name
+	return key, nil
+}
+
+// KeyPattern matches the ClusterWideKeys by the globs of their fields, with the syntax of path.Match,
+// e.g. "*.k8s.io" matches all the groups ending with ".k8s.io".
+// An empty glob matches any value, including the empty group of the core API group and the empty namespace of the
+// cluster scoped resources.
+type KeyPattern struct {
+	Group     string
+	Version   string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Validate returns an error if any glob of the pattern is malformed.
+func (p KeyPattern) Validate() error {
+	for _, glob := range []string{p.Group, p.Version, p.Kind, p.Namespace, p.Name} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidKeyPattern, glob, err)
+		}
+	}
+	return nil
+}
+
+// matchGlob returns whether the value is matched by the glob; an empty glob matches any value.
+func matchGlob(glob, value string) bool {
+	if glob == "" {
+		return true
+	}
+	matched, err := path.Match(glob, value)
+	return err == nil && matched
+}
+
 // NamespaceKey returns the traditional key of an object.
 func (k *ClusterWideKey) NamespaceKey() string {
 	if len(k.Namespace) > 0 {
@@ -61,19 +181,33 @@ func (k *ClusterWideKey) GroupKind() schema.GroupKind {
 }
 
 // GetClusterWideKeyForObject generates a ClusterWideKey for object.
+// The group, version and kind of a typed object whose TypeMeta is not set are inferred from the client-go scheme.
 func GetClusterWideKeyForObject(obj interface{}) (ClusterWideKey, error) {
+	return GetClusterWideKeyForObjectWithScheme(obj, clientgoscheme.Scheme)
+}
+
+// GetClusterWideKeyForObjectWithScheme generates a ClusterWideKey for object, which can be a typed object, an
+// unstructured object, a partial object metadata or a tombstone of the informer cache.
+// The group, version and kind of the object are read from its TypeMeta, or inferred from the scheme if the TypeMeta
+// of a typed object is not set.
+func GetClusterWideKeyForObjectWithScheme(obj interface{}, scheme *runtime.Scheme) (ClusterWideKey, error) {
 	key := ClusterWideKey{}
 
+	obj, err := unwrapTombstone(obj)
+	if err != nil {
+		return key, err
+	}
 	runtimeObject, ok := obj.(runtime.Object)
-	if !ok { // should not happen
-		return key, fmt.Errorf("object %+v is not a runtime object", obj)
+	if !ok {
+		return key, fmt.Errorf("%w: %T", ErrNotRuntimeObject, obj)
 	}
-	object := runtimeObject.DeepCopyObject()
-	gvk := object.GetObjectKind().GroupVersionKind()
-
-	metaInfo, err := meta.Accessor(object)
-	if err != nil { // should not happen
-		return key, fmt.Errorf("object %s has no meta: %w", gvk.String(), err)
+	metaInfo, err := meta.Accessor(runtimeObject)
+	if err != nil {
+		return key, fmt.Errorf("%w: %T: %v", ErrNoObjectMeta, obj, err)
+	}
+	gvk, err := groupVersionKindForObject(runtimeObject, scheme)
+	if err != nil {
+		return key, err
 	}
 
 	key.Group = gvk.Group
@@ -90,12 +224,61 @@ func GetNamespaceKeyForObject(obj interface{}) (string, error) {
 	if key, ok := obj.(string); ok {
 		return key, nil
 	}
+	obj, err := unwrapTombstone(obj)
+	if err != nil {
+		return "", err
+	}
 	metaInfo, err := meta.Accessor(obj)
-	if err != nil { // should not happen
-		return "", fmt.Errorf("object %+v has no meta: %w", obj, err)
+	if err != nil {
+		return "", fmt.Errorf("%w: %T: %v", ErrNoObjectMeta, obj, err)
 	}
 	if len(metaInfo.GetNamespace()) > 0 {
 		return metaInfo.GetNamespace() + "/" + metaInfo.GetName(), nil
 	}
 	return metaInfo.GetName(), nil
 }
+
+// unwrapTombstone returns the last known state of the deleted object if obj is a tombstone of the informer cache,
+// or obj itself otherwise.
+func unwrapTombstone(obj interface{}) (interface{}, error) {
+	var tombstone cache.DeletedFinalStateUnknown
+	switch t := obj.(type) {
+	case cache.DeletedFinalStateUnknown:
+		tombstone = t
+	case *cache.DeletedFinalStateUnknown:
+		if t == nil {
+			return nil, fmt.Errorf("%w: nil tombstone", ErrEmptyTombstone)
+		}
+		tombstone = *t
+	default:
+		return obj, nil
+	}
+	if tombstone.Obj == nil {
+		return nil, fmt.Errorf("%w: the tombstone of %s has no object", ErrEmptyTombstone, tombstone.Key)
+	}
+	return tombstone.Obj, nil
+}
+
+// groupVersionKindForObject returns the group, version and kind of the object.
+func groupVersionKindForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersionKind, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if !gvk.Empty() {
+		return gvk, nil
+	}
+	switch obj.(type) {
+	case *unstructured.Unstructured, *metav1.PartialObjectMetadata:
+		// the scheme knows nothing about the kinds of these objects, which are only carried by their TypeMeta
+		return gvk, fmt.Errorf("%w: %T has no apiVersion or kind set", ErrUnknownGroupVersionKind, obj)
+	}
+	if scheme == nil {
+		return gvk, fmt.Errorf("%w: %T has no TypeMeta set and no scheme is given", ErrUnknownGroupVersionKind, obj)
+	}
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return gvk, fmt.Errorf("%w: %v", ErrUnknownGroupVersionKind, err)
+	}
+	if len(gvks) != 1 {
+		return gvk, fmt.Errorf("%w: %T is registered as multiple kinds %v", ErrUnknownGroupVersionKind, obj, gvks)
+	}
+	return gvks[0], nil
+}
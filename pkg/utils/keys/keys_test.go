@@ -6,12 +6,18 @@ Licensed under the MIT license.
 package keys
 
 import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
@@ -60,6 +66,7 @@ func TestClusterWideKeyFunc(t *testing.T) {
 		name         string
 		object       interface{}
 		expectErr    bool
+		wantErr      error
 		expectKeyStr string
 	}{
 		{
@@ -70,6 +77,59 @@ func TestClusterWideKeyFunc(t *testing.T) {
 			expectErr:    false,
 			expectKeyStr: "fleet.azure.com/v1alpha1, kind=ClusterResourcePlacement, foo/bar",
 		},
+		{
+			name: "unstructured object without apiVersion and kind",
+			object: &unstructured.Unstructured{
+				Object: map[string]interface{}{"metadata": map[string]interface{}{"name": "bar"}},
+			},
+			expectErr: true,
+			wantErr:   ErrUnknownGroupVersionKind,
+		},
+		{
+			name: "partial object metadata",
+			object: &metav1.PartialObjectMetadata{
+				TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+			},
+			expectErr:    false,
+			expectKeyStr: "v1, kind=ConfigMap, foo/bar",
+		},
+		{
+			name:      "partial object metadata without TypeMeta",
+			object:    &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}},
+			expectErr: true,
+			wantErr:   ErrUnknownGroupVersionKind,
+		},
+		{
+			name:         "typed object without TypeMeta",
+			object:       &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"}},
+			expectErr:    false,
+			expectKeyStr: "v1, kind=ConfigMap, foo/bar",
+		},
+		{
+			name:      "typed object without TypeMeta which is not registered in the scheme",
+			object:    &fleetv1alpha1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "bar"}},
+			expectErr: true,
+			wantErr:   ErrUnknownGroupVersionKind,
+		},
+		{
+			name:         "tombstone",
+			object:       cache.DeletedFinalStateUnknown{Key: "foo/bar", Obj: roleObj},
+			expectErr:    false,
+			expectKeyStr: "rbac.authorization.k8s.io/v1, kind=Role, foo/bar",
+		},
+		{
+			name:         "tombstone pointer",
+			object:       &cache.DeletedFinalStateUnknown{Key: "foo/bar", Obj: roleObj},
+			expectErr:    false,
+			expectKeyStr: "rbac.authorization.k8s.io/v1, kind=Role, foo/bar",
+		},
+		{
+			name:      "tombstone without object",
+			object:    cache.DeletedFinalStateUnknown{Key: "foo/bar"},
+			expectErr: true,
+			wantErr:   ErrEmptyTombstone,
+		},
 		{
 			name:         "namespace scoped resource",
 			object:       roleObj,
@@ -86,11 +146,13 @@ func TestClusterWideKeyFunc(t *testing.T) {
 			name:      "runtime object without meta",
 			object:    secretObj,
 			expectErr: true,
+			wantErr:   ErrNoObjectMeta,
 		},
 		{
 			name:      "non runtime object should be error",
 			object:    "non-runtime-object",
 			expectErr: true,
+			wantErr:   ErrNotRuntimeObject,
 		},
 		{
 			name:      "nil object should be error",
@@ -107,9 +169,15 @@ func TestClusterWideKeyFunc(t *testing.T) {
 				if tc.expectErr == false {
 					t.Fatalf("not expect error but error happed: %v", err)
 				}
+				if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expect error %v, but got: %v", tc.wantErr, err)
+				}
 
 				return
 			}
+			if tc.expectErr {
+				t.Fatalf("expect error but got key: %s", key.String())
+			}
 
 			if key.String() != tc.expectKeyStr {
 				t.Fatalf("expect key string: %s, but got: %s", tc.expectKeyStr, key.String())
@@ -146,6 +214,12 @@ func TestNamespaceKeyFunc(t *testing.T) {
 			expectErr:    false,
 			expectKeyStr: "bar",
 		},
+		{
+			name:         "tombstone",
+			object:       cache.DeletedFinalStateUnknown{Key: "foo/bar", Obj: roleObj},
+			expectErr:    false,
+			expectKeyStr: "foo/bar",
+		},
 		{
 			name:         "string works",
 			object:       "string-key",
@@ -190,3 +264,149 @@ func TestNamespaceKeyFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterWideKeyWithSchemeFunc(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := fleetv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() = %v, want nil", err)
+	}
+	object := &fleetv1alpha1.ClusterResourcePlacement{ObjectMeta: metav1.ObjectMeta{Name: "bar"}}
+
+	key, err := GetClusterWideKeyForObjectWithScheme(object, scheme)
+	if err != nil {
+		t.Fatalf("GetClusterWideKeyForObjectWithScheme() = %v, want nil", err)
+	}
+	if want := "fleet.azure.com/v1alpha1, kind=ClusterResourcePlacement, bar"; key.String() != want {
+		t.Fatalf("GetClusterWideKeyForObjectWithScheme() = %s, want %s", key.String(), want)
+	}
+	if _, err := GetClusterWideKeyForObjectWithScheme(object, nil); !errors.Is(err, ErrUnknownGroupVersionKind) {
+		t.Fatalf("GetClusterWideKeyForObjectWithScheme() with no scheme = %v, want %v", err, ErrUnknownGroupVersionKind)
+	}
+}
+
+func TestParseClusterWideKey(t *testing.T) {
+	tests := map[string]struct {
+		str     string
+		want    ClusterWideKey
+		wantErr error
+	}{
+		"namespace scoped resource": {
+			str: "apps/v1, kind=Deployment, foo/bar",
+			want: ClusterWideKey{fleetv1alpha1.ResourceIdentifier{
+				Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "foo", Name: "bar",
+			}},
+		},
+		"cluster scoped resource of the core group": {
+			str:  "v1, kind=Namespace, bar",
+			want: ClusterWideKey{fleetv1alpha1.ResourceIdentifier{Version: "v1", Kind: "Namespace", Name: "bar"}},
+		},
+		"missing fields": {
+			str:     "apps/v1, kind=Deployment",
+			wantErr: ErrInvalidKey,
+		},
+		"empty version": {
+			str:     ", kind=Deployment, foo/bar",
+			wantErr: ErrInvalidKey,
+		},
+		"invalid group version": {
+			str:     "apps/v1/v2, kind=Deployment, foo/bar",
+			wantErr: ErrInvalidKey,
+		},
+		"missing kind prefix": {
+			str:     "apps/v1, Deployment, foo/bar",
+			wantErr: ErrInvalidKey,
+		},
+		"empty namespace": {
+			str:     "apps/v1, kind=Deployment, /bar",
+			wantErr: ErrInvalidKey,
+		},
+		"empty name": {
+			str:     "apps/v1, kind=Deployment, foo/",
+			wantErr: ErrInvalidKey,
+		},
+		"too many slashes": {
+			str:     "apps/v1, kind=Deployment, foo/bar/baz",
+			wantErr: ErrInvalidKey,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseClusterWideKey(tc.str)
+			if gotErr, wantErr := err != nil, tc.wantErr != nil; gotErr != wantErr || !errors.Is(err, tc.wantErr) {
+				t.Fatalf("ParseClusterWideKey() = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseClusterWideKey() mismatch (-want, +got):\n%s", diff)
+			}
+			if got.String() != tc.str {
+				t.Errorf("ParseClusterWideKey().String() = %s, want %s", got.String(), tc.str)
+			}
+		})
+	}
+}
+
+func TestClusterWideKeyMatches(t *testing.T) {
+	key := ClusterWideKey{fleetv1alpha1.ResourceIdentifier{
+		Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole", Name: "admin",
+	}}
+	tests := map[string]struct {
+		pattern KeyPattern
+		want    bool
+	}{
+		"empty pattern matches everything": {
+			pattern: KeyPattern{},
+			want:    true,
+		},
+		"group glob": {
+			pattern: KeyPattern{Group: "*.k8s.io"},
+			want:    true,
+		},
+		"group and kind globs": {
+			pattern: KeyPattern{Group: "*.k8s.io", Kind: "Cluster*"},
+			want:    true,
+		},
+		"kind mismatch": {
+			pattern: KeyPattern{Group: "*.k8s.io", Kind: "Role"},
+			want:    false,
+		},
+		"namespace glob does not match the cluster scoped resource": {
+			pattern: KeyPattern{Namespace: "kube-*"},
+			want:    false,
+		},
+		"malformed glob matches nothing": {
+			pattern: KeyPattern{Kind: "[Cluster"},
+			want:    false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := key.Matches(tc.pattern); got != tc.want {
+				t.Errorf("Matches(%+v) = %v, want %v", tc.pattern, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKeyPatternValidate(t *testing.T) {
+	if err := (KeyPattern{Group: "*.k8s.io", Kind: "Role?"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := (KeyPattern{Namespace: "[kube"}).Validate(); !errors.Is(err, ErrInvalidKeyPattern) {
+		t.Errorf("Validate() = %v, want %v", err, ErrInvalidKeyPattern)
+	}
+}
+
+func TestClusterWideKeyHash(t *testing.T) {
+	key := ClusterWideKey{fleetv1alpha1.ResourceIdentifier{Version: "v1", Kind: "ConfigMap", Namespace: "foo", Name: "bar"}}
+	other := ClusterWideKey{fleetv1alpha1.ResourceIdentifier{Version: "v1", Kind: "ConfigMap", Namespace: "foo", Name: "baz"}}
+	// the hash must stay the same across releases as it may be persisted
+	if want := fmt.Sprintf("%x", sha256.Sum256([]byte("v1, kind=ConfigMap, foo/bar"))); key.Hash() != want {
+		t.Errorf("Hash() = %s, want %s", key.Hash(), want)
+	}
+	if key.Hash() == other.Hash() {
+		t.Errorf("Hash() of %s and %s are both %s, want different", key, other, key.Hash())
+	}
+}
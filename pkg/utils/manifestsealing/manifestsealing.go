@@ -0,0 +1,258 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package manifestsealing encrypts the data of the sensitive manifests, i.e. secrets, in the works with the key of the
+// target member cluster, so that the hub etcd and any observer of the works never see the plaintext. Only the member
+// agent which owns the private key can decrypt them.
+//
+// Note that the resource snapshots on the hub cluster still hold the plaintext of the selected secrets.
+package manifestsealing
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+const (
+	// SealedDataKey is the key in the data of a sealed secret which holds the encrypted data of the original secret.
+	SealedDataKey = "kubernetes-fleet.io.sealed-data"
+
+	// privateKeySecretDataKey is the key in the data of the secret which stores the private key of the member cluster.
+	privateKeySecretDataKey = "privateKey"
+)
+
+// sealedContent is the content of a secret that is encrypted.
+type sealedContent struct {
+	Data       map[string]interface{} `json:"data,omitempty"`
+	StringData map[string]interface{} `json:"stringData,omitempty"`
+}
+
+// EncodePublicKey returns the base64 encoding of the public key which is published in the member cluster status.
+func EncodePublicKey(publicKey *ecdh.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(publicKey.Bytes())
+}
+
+// ParsePublicKey parses the base64 encoded X25519 public key published in the member cluster status.
+func ParsePublicKey(encoded string) (*ecdh.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the public key: %w", err)
+	}
+	publicKey, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// KeyID returns the ID of a public key, which is the hex encoded prefix of its SHA-256 hash.
+func KeyID(publicKey *ecdh.PublicKey) string {
+	sum := sha256.Sum256(publicKey.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+// NeedsSealing tells if the manifest is a secret that needs to be sealed, either because all the secrets are sealed
+// or because the secret opts in with the seal annotation.
+func NeedsSealing(obj *unstructured.Unstructured, sealAllSecrets bool) bool {
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != "" || gvk.Kind != "Secret" {
+		return false
+	}
+	return sealAllSecrets || obj.GetAnnotations()[fleetv1beta1.SealManifestAnnotation] == "true"
+}
+
+// IsSealed tells if the manifest is sealed.
+func IsSealed(obj *unstructured.Unstructured) bool {
+	_, ok := obj.GetAnnotations()[fleetv1beta1.SealedManifestAnnotation]
+	return ok
+}
+
+// Seal encrypts the data and the stringData of the secret with the public key of the member cluster in place. The
+// sealed secret keeps its metadata, carries the encrypted content under the SealedDataKey of its data and is marked
+// with the ID of the key.
+func Seal(obj *unstructured.Unstructured, publicKey *ecdh.PublicKey) error {
+	data, _, err := unstructured.NestedMap(obj.Object, "data")
+	if err != nil {
+		return fmt.Errorf("failed to get the data of secret %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	stringData, _, err := unstructured.NestedMap(obj.Object, "stringData")
+	if err != nil {
+		return fmt.Errorf("failed to get the stringData of secret %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	plaintext, err := json.Marshal(sealedContent{Data: data, StringData: stringData})
+	if err != nil {
+		return fmt.Errorf("failed to encode the content of secret %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	ephemeralKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate the ephemeral key: %w", err)
+	}
+	shared, err := ephemeralKey.ECDH(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to compute the shared secret: %w", err)
+	}
+	aead, err := newAEAD(shared, ephemeralKey.PublicKey(), publicKey)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate the nonce: %w", err)
+	}
+	// the ciphertext is bound to the secret so that it cannot be moved to another secret
+	ciphertext := aead.Seal(nil, nonce, plaintext, additionalData(obj))
+
+	sealed := make([]byte, 0, len(ephemeralKey.PublicKey().Bytes())+len(nonce)+len(ciphertext))
+	sealed = append(sealed, ephemeralKey.PublicKey().Bytes()...)
+	sealed = append(sealed, nonce...)
+	sealed = append(sealed, ciphertext...)
+
+	unstructured.RemoveNestedField(obj.Object, "stringData")
+	obj.Object["data"] = map[string]interface{}{
+		SealedDataKey: base64.StdEncoding.EncodeToString(sealed),
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[fleetv1beta1.SealedManifestAnnotation] = KeyID(publicKey)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// Unseal decrypts the sealed secret with the private key of the member cluster in place.
+func Unseal(obj *unstructured.Unstructured, privateKey *ecdh.PrivateKey) error {
+	if privateKey == nil {
+		return errors.New("the manifest is sealed but the manifest decryption is not enabled")
+	}
+	if keyID := obj.GetAnnotations()[fleetv1beta1.SealedManifestAnnotation]; keyID != KeyID(privateKey.PublicKey()) {
+		return fmt.Errorf("the manifest is sealed with key %q which does not match the key %q of the member cluster", keyID, KeyID(privateKey.PublicKey()))
+	}
+	encoded, _, err := unstructured.NestedString(obj.Object, "data", SealedDataKey)
+	if err != nil {
+		return fmt.Errorf("failed to get the sealed data: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("failed to decode the sealed data: %w", err)
+	}
+	keySize := len(privateKey.PublicKey().Bytes())
+	if len(sealed) < keySize {
+		return errors.New("the sealed data is truncated")
+	}
+	ephemeralPublicKey, err := ecdh.X25519().NewPublicKey(sealed[:keySize])
+	if err != nil {
+		return fmt.Errorf("failed to parse the ephemeral public key: %w", err)
+	}
+	shared, err := privateKey.ECDH(ephemeralPublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to compute the shared secret: %w", err)
+	}
+	aead, err := newAEAD(shared, ephemeralPublicKey, privateKey.PublicKey())
+	if err != nil {
+		return err
+	}
+	if len(sealed) < keySize+aead.NonceSize() {
+		return errors.New("the sealed data is truncated")
+	}
+	nonce, ciphertext := sealed[keySize:keySize+aead.NonceSize()], sealed[keySize+aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData(obj))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the sealed data: %w", err)
+	}
+	var content sealedContent
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return fmt.Errorf("failed to decode the sealed content: %w", err)
+	}
+
+	unstructured.RemoveNestedField(obj.Object, "data")
+	if content.Data != nil {
+		obj.Object["data"] = content.Data
+	}
+	if content.StringData != nil {
+		obj.Object["stringData"] = content.StringData
+	}
+	annotations := obj.GetAnnotations()
+	delete(annotations, fleetv1beta1.SealedManifestAnnotation)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// newAEAD derives the AES-256-GCM key from the X25519 shared secret, the ephemeral public key and the public key of
+// the member cluster.
+func newAEAD(shared []byte, ephemeralPublicKey, recipientPublicKey *ecdh.PublicKey) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write(shared)
+	h.Write(ephemeralPublicKey.Bytes())
+	h.Write(recipientPublicKey.Bytes())
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func additionalData(obj *unstructured.Unstructured) []byte {
+	return []byte(obj.GetNamespace() + "/" + obj.GetName())
+}
+
+// LoadOrCreatePrivateKey loads the private key of the member cluster from the secret, or generates one and stores it
+// in the secret if it does not exist yet.
+func LoadOrCreatePrivateKey(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string) (*ecdh.PrivateKey, error) {
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		privateKey, err := ecdh.X25519().NewPrivateKey(secret.Data[privateKeySecretDataKey])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the private key in secret %s/%s: %w", namespace, name, err)
+		}
+		return privateKey, nil
+	case !apierrors.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the private key: %w", err)
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			privateKeySecretDataKey: privateKey.Bytes(),
+		},
+	}
+	if _, err := kubeClient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// another agent replica has created the key in the meantime
+			return LoadOrCreatePrivateKey(ctx, kubeClient, namespace, name)
+		}
+		return nil, fmt.Errorf("failed to create secret %s/%s: %w", namespace, name, err)
+	}
+	klog.V(2).InfoS("Generated the manifest decryption key", "secret", klog.KObj(secret), "keyID", KeyID(privateKey.PublicKey()))
+	return privateKey, nil
+}
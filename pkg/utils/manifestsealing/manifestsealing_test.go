@@ -0,0 +1,184 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package manifestsealing
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func newTestSecret() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "db-password",
+				"namespace": "app",
+				"annotations": map[string]interface{}{
+					fleetv1beta1.SealManifestAnnotation: "true",
+				},
+			},
+			"type": "Opaque",
+			"data": map[string]interface{}{
+				"password": "c2VjcmV0",
+			},
+			"stringData": map[string]interface{}{
+				"user": "admin",
+			},
+		},
+	}
+}
+
+func newTestKey(t *testing.T) *ecdh.PrivateKey {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	return key
+}
+
+func TestSealAndUnseal(t *testing.T) {
+	key := newTestKey(t)
+	otherKey := newTestKey(t)
+	tests := map[string]struct {
+		mutate  func(obj *unstructured.Unstructured)
+		key     *ecdh.PrivateKey
+		wantErr bool
+	}{
+		"sealed secret is unsealed": {
+			key: key,
+		},
+		"decryption is not enabled": {
+			wantErr: true,
+		},
+		"sealed with another key": {
+			key:     otherKey,
+			wantErr: true,
+		},
+		"sealed data is moved to another secret": {
+			mutate: func(obj *unstructured.Unstructured) {
+				obj.SetName("another-secret")
+			},
+			key:     key,
+			wantErr: true,
+		},
+		"sealed data is truncated": {
+			mutate: func(obj *unstructured.Unstructured) {
+				obj.Object["data"] = map[string]interface{}{SealedDataKey: "c2VjcmV0"}
+			},
+			key:     key,
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := newTestSecret()
+			if err := Seal(obj, key.PublicKey()); err != nil {
+				t.Fatalf("Seal() = %v, want nil", err)
+			}
+			if !IsSealed(obj) {
+				t.Fatalf("IsSealed() = false, want true")
+			}
+			if _, found := obj.Object["stringData"]; found {
+				t.Errorf("sealed secret has stringData, want none")
+			}
+			if got, want := obj.GetAnnotations()[fleetv1beta1.SealedManifestAnnotation], KeyID(key.PublicKey()); got != want {
+				t.Errorf("sealed key ID = %q, want %q", got, want)
+			}
+			if tc.mutate != nil {
+				tc.mutate(obj)
+			}
+
+			err := Unseal(obj, tc.key)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Unseal() = %v, want error %t", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(newTestSecret(), obj); diff != "" {
+				t.Errorf("Unseal() secret mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNeedsSealing(t *testing.T) {
+	configMap := newTestSecret()
+	configMap.SetKind("ConfigMap")
+	notAnnotated := newTestSecret()
+	notAnnotated.SetAnnotations(nil)
+	tests := map[string]struct {
+		obj            *unstructured.Unstructured
+		sealAllSecrets bool
+		want           bool
+	}{
+		"annotated secret": {
+			obj:  newTestSecret(),
+			want: true,
+		},
+		"secret without the annotation": {
+			obj:  notAnnotated,
+			want: false,
+		},
+		"secret without the annotation when all the secrets are sealed": {
+			obj:            notAnnotated,
+			sealAllSecrets: true,
+			want:           true,
+		},
+		"annotated configMap": {
+			obj:            configMap,
+			sealAllSecrets: true,
+			want:           false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := NeedsSealing(tc.obj, tc.sealAllSecrets); got != tc.want {
+				t.Errorf("NeedsSealing() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newTestKey(t)
+	got, err := ParsePublicKey(EncodePublicKey(key.PublicKey()))
+	if err != nil {
+		t.Fatalf("ParsePublicKey() = %v, want nil", err)
+	}
+	if !got.Equal(key.PublicKey()) {
+		t.Errorf("ParsePublicKey() = %v, want %v", got, key.PublicKey())
+	}
+	if _, err := ParsePublicKey("c2VjcmV0"); err == nil {
+		t.Errorf("ParsePublicKey() = nil, want error for an invalid key")
+	}
+}
+
+func TestLoadOrCreatePrivateKey(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := fake.NewSimpleClientset()
+	created, err := LoadOrCreatePrivateKey(ctx, kubeClient, "fleet-system", "fleet-manifest-decryption-key")
+	if err != nil {
+		t.Fatalf("LoadOrCreatePrivateKey() = %v, want nil", err)
+	}
+	loaded, err := LoadOrCreatePrivateKey(ctx, kubeClient, "fleet-system", "fleet-manifest-decryption-key")
+	if err != nil {
+		t.Fatalf("LoadOrCreatePrivateKey() = %v, want nil", err)
+	}
+	if !loaded.Equal(created) {
+		t.Errorf("LoadOrCreatePrivateKey() loaded a different key from the created one")
+	}
+}
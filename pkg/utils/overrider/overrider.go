@@ -14,19 +14,35 @@ import (
 
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
 	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils/clustergroup"
 )
 
 // IsClusterMatched checks if the cluster is matched with the override rules.
 func IsClusterMatched(cluster clusterv1beta1.MemberCluster, rule placementv1alpha1.OverrideRule) (bool, error) {
-	if rule.ClusterSelector == nil { // it means matching no member clusters
+	return IsClusterSelected(cluster, rule.ClusterSelector)
+}
+
+// IsClusterSelected checks if the cluster is selected by the cluster selector of an override rule.
+func IsClusterSelected(cluster clusterv1beta1.MemberCluster, clusterSelector *placementv1beta1.ClusterSelector) (bool, error) {
+	if clusterSelector == nil { // it means matching no member clusters
 		return false, nil
 	}
 
-	if len(rule.ClusterSelector.ClusterSelectorTerms) == 0 {
+	if len(clusterSelector.ClusterSelectorTerms) == 0 {
 		return true, nil // it means matching all member clusters
 	}
 
-	for _, term := range rule.ClusterSelector.ClusterSelectorTerms {
+	for _, term := range clusterSelector.ClusterSelectorTerms {
+		if term.ClusterGroup != "" {
+			if !clustergroup.Contains(cluster.Labels, term.ClusterGroup) {
+				continue
+			}
+			if term.LabelSelector == nil {
+				// the term selects the cluster group only
+				return true, nil
+			}
+		}
 		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
 		if err != nil {
 			return false, fmt.Errorf("invalid cluster label selector %v: %w", term.LabelSelector, err)
@@ -76,6 +76,45 @@ func TestIsClusterMatched(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "rule with cluster group only",
+			cluster: clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cluster-1",
+					Labels: map[string]string{clusterv1beta1.ClusterGroupLabelPrefix + "prod-eu": "true"},
+				},
+			},
+			rule: placementv1alpha1.OverrideRule{
+				ClusterSelector: &placementv1beta1.ClusterSelector{
+					ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{
+						{
+							ClusterGroup: "prod-eu",
+						},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "rule with cluster group and label selector, not in the group",
+			cluster: clusterv1beta1.MemberCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cluster-1",
+					Labels: map[string]string{"key1": "value1"},
+				},
+			},
+			rule: placementv1alpha1.OverrideRule{
+				ClusterSelector: &placementv1beta1.ClusterSelector{
+					ClusterSelectorTerms: []placementv1beta1.ClusterSelectorTerm{
+						{
+							ClusterGroup:  "prod-eu",
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"key1": "value1"}},
+						},
+					},
+				},
+			},
+			want: false,
+		},
 		{
 			name: "rule with empty cluster label selector",
 			cluster: clusterv1beta1.MemberCluster{
@@ -0,0 +1,152 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementquota provides utils to look up the placement quotas of the tenants that the placements belong to.
+// A placement belongs to the tenant of each namespace it selects.
+package placementquota
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+// Quota is the limits that the placement quotas in the namespace of a tenant enforce on the placements of the tenant.
+type Quota struct {
+	// Namespace is the namespace of the tenant.
+	Namespace string
+	// MaxClusters is the smallest maxClusters of the quotas in the namespace, or nil if none of them sets it.
+	MaxClusters *int32
+	// MaxResources is the smallest maxResources of the quotas in the namespace, or nil if none of them sets it.
+	MaxResources *int32
+	// MaxResourceRequests is the smallest maxResourceRequests of each resource of the quotas in the namespace; the
+	// resources which none of them lists are not limited.
+	MaxResourceRequests corev1.ResourceList
+}
+
+// FetchQuotas returns the quotas of the namespaces which the placement selects, sorted by the namespace; the
+// namespaces without any placement quota are skipped.
+func FetchQuotas(ctx context.Context, c client.Reader, crp *fleetv1beta1.ClusterResourcePlacement) ([]Quota, error) {
+	var quotaList fleetv1beta1.PlacementQuotaList
+	if err := c.List(ctx, &quotaList); err != nil {
+		klog.ErrorS(err, "Failed to list the placement quotas")
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	if len(quotaList.Items) == 0 {
+		// no tenant has a quota, which is the common case
+		return nil, nil
+	}
+	namespaces, err := SelectedNamespaces(ctx, c, crp)
+	if err != nil {
+		return nil, err
+	}
+	quotas := make(map[string]*Quota)
+	for i := range quotaList.Items {
+		pq := &quotaList.Items[i]
+		if !namespaces.Has(pq.Namespace) {
+			continue
+		}
+		quota, ok := quotas[pq.Namespace]
+		if !ok {
+			quota = &Quota{Namespace: pq.Namespace}
+			quotas[pq.Namespace] = quota
+		}
+		quota.MaxClusters = smaller(quota.MaxClusters, pq.Spec.MaxClusters)
+		quota.MaxResources = smaller(quota.MaxResources, pq.Spec.MaxResources)
+		quota.MaxResourceRequests = smallerResourceList(quota.MaxResourceRequests, pq.Spec.MaxResourceRequests)
+	}
+	res := make([]Quota, 0, len(quotas))
+	for _, namespace := range sets.List(sets.KeySet(quotas)) {
+		res = append(res, *quotas[namespace])
+	}
+	return res, nil
+}
+
+// TenantPlacements returns the placements which select the namespace, i.e. the placements of the tenant.
+func TenantPlacements(ctx context.Context, c client.Reader, namespace string) ([]fleetv1beta1.ClusterResourcePlacement, error) {
+	var crpList fleetv1beta1.ClusterResourcePlacementList
+	if err := c.List(ctx, &crpList); err != nil {
+		klog.ErrorS(err, "Failed to list the clusterResourcePlacements")
+		return nil, controller.NewAPIServerError(true, err)
+	}
+	var res []fleetv1beta1.ClusterResourcePlacement
+	for i := range crpList.Items {
+		namespaces, err := SelectedNamespaces(ctx, c, &crpList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		if namespaces.Has(namespace) {
+			res = append(res, crpList.Items[i])
+		}
+	}
+	return res, nil
+}
+
+// SelectedNamespaces returns the names of the namespaces which the placement selects by their names or labels.
+func SelectedNamespaces(ctx context.Context, c client.Reader, crp *fleetv1beta1.ClusterResourcePlacement) (sets.Set[string], error) {
+	namespaces := sets.New[string]()
+	for _, selector := range crp.Spec.ResourceSelectors {
+		if selector.Group != utils.NamespaceMetaGVK.Group || selector.Kind != utils.NamespaceMetaGVK.Kind {
+			continue
+		}
+		if selector.Name != "" {
+			namespaces.Insert(selector.Name)
+			continue
+		}
+		// the same as the resource selector, a nil label selector selects all the namespaces
+		labelSelector := labels.Everything()
+		if selector.LabelSelector != nil {
+			var err error
+			if labelSelector, err = metav1.LabelSelectorAsSelector(selector.LabelSelector); err != nil {
+				// should have been rejected by the webhook
+				return nil, controller.NewUnexpectedBehaviorError(err)
+			}
+		}
+		var namespaceList corev1.NamespaceList
+		if err := c.List(ctx, &namespaceList, &client.ListOptions{LabelSelector: labelSelector}); err != nil {
+			klog.ErrorS(err, "Failed to list the namespaces selected by the clusterResourcePlacement", "clusterResourcePlacement", klog.KObj(crp))
+			return nil, controller.NewAPIServerError(true, err)
+		}
+		for i := range namespaceList.Items {
+			namespaces.Insert(namespaceList.Items[i].Name)
+		}
+	}
+	return namespaces, nil
+}
+
+// smaller returns the smaller of the two limits, where nil means no limit.
+func smaller(a, b *int32) *int32 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+// smallerResourceList returns the smaller limit of each resource in the two lists, where a missing resource means no
+// limit.
+func smallerResourceList(a, b corev1.ResourceList) corev1.ResourceList {
+	if len(b) == 0 {
+		return a
+	}
+	res := a.DeepCopy()
+	if res == nil {
+		res = make(corev1.ResourceList, len(b))
+	}
+	for name, limit := range b {
+		if current, found := res[name]; !found || limit.Cmp(current) < 0 {
+			res[name] = limit.DeepCopy()
+		}
+	}
+	return res
+}
@@ -0,0 +1,29 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package utils
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// IsExportedService tells if the object is a Service which asks to be exported with the multi-cluster services of
+// fleet networking.
+func IsExportedService(uObj *unstructured.Unstructured) bool {
+	gvk := uObj.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Service" && uObj.GetAnnotations()[placementv1beta1.ServiceExportAnnotation] == "true"
+}
+
+// NewServiceExport returns the ServiceExport which exports the Service from the member cluster it is placed on. The
+// fleet networking member agent then imports the Service into the ServiceImport of the same name on the hub cluster.
+func NewServiceExport(service *unstructured.Unstructured) *unstructured.Unstructured {
+	export := &unstructured.Unstructured{}
+	export.SetGroupVersionKind(ServiceExportGVK)
+	export.SetNamespace(service.GetNamespace())
+	export.SetName(service.GetName())
+	return export
+}
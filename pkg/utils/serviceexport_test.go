@@ -0,0 +1,44 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestIsExportedService(t *testing.T) {
+	tests := map[string]struct {
+		obj  string
+		want bool
+	}{
+		"exported service": {
+			obj:  `{"apiVersion":"v1","kind":"Service","metadata":{"name":"web","annotations":{"kubernetes-fleet.io/service-export":"true"}}}`,
+			want: true,
+		},
+		"service not exported": {
+			obj: `{"apiVersion":"v1","kind":"Service","metadata":{"name":"web","annotations":{"kubernetes-fleet.io/service-export":"false"}}}`,
+		},
+		"service without annotations": {
+			obj: `{"apiVersion":"v1","kind":"Service","metadata":{"name":"web"}}`,
+		},
+		"other kind with the annotation": {
+			obj: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web","annotations":{"kubernetes-fleet.io/service-export":"true"}}}`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var uObj unstructured.Unstructured
+			if err := uObj.UnmarshalJSON([]byte(tc.obj)); err != nil {
+				t.Fatalf("failed to unmarshal the object: %v", err)
+			}
+			if got := IsExportedService(&uObj); got != tc.want {
+				t.Errorf("IsExportedService() = %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/pkg/propertyprovider"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/clustergroup"
 	"go.goms.io/fleet/pkg/utils/controller"
 	"go.goms.io/fleet/pkg/utils/informer"
 )
@@ -111,6 +113,14 @@ func ValidateClusterResourcePlacement(clusterResourcePlacement *placementv1beta1
 			return apiErrors.NewAggregate(allErr) // skip next check if we cannot get GVR
 		}
 
+		// a placement source is selected by its name and is not watched by the resource informer
+		if utils.IsPlacementSourceSelector(selector) {
+			if len(selector.Name) == 0 {
+				allErr = append(allErr, fmt.Errorf("the name field is required to select a placement source in selector %+v", selector))
+			}
+			continue
+		}
+
 		if ResourceInformer != nil {
 			gvk := schema.GroupVersionKind{
 				Group:   selector.Group,
@@ -137,6 +147,30 @@ func ValidateClusterResourcePlacement(clusterResourcePlacement *placementv1beta1
 		allErr = append(allErr, fmt.Errorf("the rollout Strategy field  is invalid: %w", err))
 	}
 
+	if err := validateBindingGates(clusterResourcePlacement.Spec.BindingGates); err != nil {
+		allErr = append(allErr, fmt.Errorf("the binding gates are invalid: %w", err))
+	}
+
+	return apiErrors.NewAggregate(allErr)
+}
+
+// validateBindingGates validates that the names of the binding gates can be the types of the binding conditions which
+// open them, and are not the types of the conditions written by fleet.
+func validateBindingGates(gates []placementv1beta1.PlacementBindingGate) error {
+	allErr := make([]error, 0)
+	for _, gate := range gates {
+		if msgs := validation.IsQualifiedName(gate.Name); len(msgs) > 0 {
+			allErr = append(allErr, fmt.Errorf("the name of the binding gate %s is invalid: %s", gate.Name, strings.Join(msgs, ";")))
+			continue
+		}
+		switch placementv1beta1.ResourceBindingConditionType(gate.Name) {
+		case placementv1beta1.ResourceBindingRolloutStarted, placementv1beta1.ResourceBindingOverridden,
+			placementv1beta1.ResourceBindingWorkSynchronized, placementv1beta1.ResourceBindingWorkSyncThrottled,
+			placementv1beta1.ResourceBindingClusterGone, placementv1beta1.ResourceBindingResourcesDeleted,
+			placementv1beta1.ResourceBindingApplied, placementv1beta1.ResourceBindingAvailable:
+			allErr = append(allErr, fmt.Errorf("the name of the binding gate %s is reserved for the binding conditions of fleet", gate.Name))
+		}
+	}
 	return apiErrors.NewAggregate(allErr)
 }
 
@@ -201,6 +235,21 @@ func validatePolicyForPickFixedPlacementType(policy *placementv1beta1.PlacementP
 	if policy.Tolerations != nil {
 		allErr = append(allErr, fmt.Errorf("tolerations needs to be empty for policy type %s, only valid for PickAll/PickN", placementv1beta1.PickFixedPlacementType))
 	}
+	if len(policy.ResourceRequirements) > 0 {
+		allErr = append(allErr, fmt.Errorf("resource requirements needs to be empty for policy type %s, only valid for PickAll/PickN", placementv1beta1.PickFixedPlacementType))
+	}
+	if policy.SchedulerProfile != "" {
+		allErr = append(allErr, fmt.Errorf("scheduler profile needs to be empty for policy type %s, only valid for PickAll/PickN", placementv1beta1.PickFixedPlacementType))
+	}
+	if policy.Priority != 0 {
+		allErr = append(allErr, fmt.Errorf("priority needs to be zero for policy type %s, only valid for PickAll/PickN", placementv1beta1.PickFixedPlacementType))
+	}
+	if policy.ScoreWeights != nil {
+		allErr = append(allErr, fmt.Errorf("score weights needs to be nil for policy type %s, only valid for PickN policy type", placementv1beta1.PickFixedPlacementType))
+	}
+	if policy.NetworkProximity != nil {
+		allErr = append(allErr, fmt.Errorf("network proximity needs to be nil for policy type %s, only valid for PickN policy type", placementv1beta1.PickFixedPlacementType))
+	}
 
 	return apiErrors.NewAggregate(allErr)
 }
@@ -220,7 +269,14 @@ func validatePolicyForPickAllPlacementType(policy *placementv1beta1.PlacementPol
 	if len(policy.TopologySpreadConstraints) > 0 {
 		allErr = append(allErr, fmt.Errorf("topology spread constraints needs to be empty for policy type %s, only valid for PickN policy type", placementv1beta1.PickAllPlacementType))
 	}
+	if policy.ScoreWeights != nil {
+		allErr = append(allErr, fmt.Errorf("score weights needs to be nil for policy type %s, only valid for PickN policy type", placementv1beta1.PickAllPlacementType))
+	}
+	if policy.NetworkProximity != nil {
+		allErr = append(allErr, fmt.Errorf("network proximity needs to be nil for policy type %s, only valid for PickN policy type", placementv1beta1.PickAllPlacementType))
+	}
 	allErr = append(allErr, validateTolerations(policy.Tolerations))
+	allErr = append(allErr, validateResourceRequirements(policy.ResourceRequirements))
 
 	return apiErrors.NewAggregate(allErr)
 }
@@ -245,6 +301,7 @@ func validatePolicyForPickNPolicyType(policy *placementv1beta1.PlacementPolicy)
 		allErr = append(allErr, validateTopologySpreadConstraints(policy.TopologySpreadConstraints))
 	}
 	allErr = append(allErr, validateTolerations(policy.Tolerations))
+	allErr = append(allErr, validateResourceRequirements(policy.ResourceRequirements))
 
 	return apiErrors.NewAggregate(allErr)
 }
@@ -301,6 +358,17 @@ func validateTolerations(tolerations []placementv1beta1.Toleration) error {
 	return apiErrors.NewAggregate(allErr)
 }
 
+// validateResourceRequirements validates that the resource requirements of a placement are not negative.
+func validateResourceRequirements(requirements corev1.ResourceList) error {
+	allErr := make([]error, 0)
+	for name, quantity := range requirements {
+		if quantity.Sign() < 0 {
+			allErr = append(allErr, fmt.Errorf("resource requirement %s cannot be negative, got %s", name, quantity.String()))
+		}
+	}
+	return apiErrors.NewAggregate(allErr)
+}
+
 func IsTolerationsUpdatedOrDeleted(oldTolerations []placementv1beta1.Toleration, newTolerations []placementv1beta1.Toleration) bool {
 	newTolerationsMap := make(map[placementv1beta1.Toleration]bool)
 	for _, newToleration := range newTolerations {
@@ -314,6 +382,21 @@ func IsTolerationsUpdatedOrDeleted(oldTolerations []placementv1beta1.Toleration,
 	return false
 }
 
+// IsSchedulingGateAdded returns true if any of the new scheduling gates is not in the old ones; the scheduling gates
+// can only be removed after the placement is created.
+func IsSchedulingGateAdded(oldGates []placementv1beta1.PlacementSchedulingGate, newGates []placementv1beta1.PlacementSchedulingGate) bool {
+	oldGatesMap := make(map[string]bool)
+	for _, oldGate := range oldGates {
+		oldGatesMap[oldGate.Name] = true
+	}
+	for _, newGate := range newGates {
+		if !oldGatesMap[newGate.Name] {
+			return true
+		}
+	}
+	return false
+}
+
 func validateTopologySpreadConstraints(topologyConstraints []placementv1beta1.TopologySpreadConstraint) error {
 	allErr := make([]error, 0)
 	for _, tc := range topologyConstraints {
@@ -329,6 +412,9 @@ func validateClusterSelector(clusterSelector *placementv1beta1.ClusterSelector)
 	for _, clusterSelectorTerm := range clusterSelector.ClusterSelectorTerms {
 		// Since label selector is a required field in ClusterSelectorTerm, not checking to see if it's an empty object.
 		allErr = append(allErr, validateLabelSelector(clusterSelectorTerm.LabelSelector, "cluster selector"))
+		if clusterSelectorTerm.ClusterGroup != "" {
+			allErr = append(allErr, clustergroup.Validate(clusterSelectorTerm.ClusterGroup))
+		}
 
 		// Affinity is RequiredDuringSchedulingIgnoredDuringExecution, so check that PropertySorter is nil.
 		if clusterSelectorTerm.PropertySorter != nil {
@@ -348,6 +434,9 @@ func validatePreferredClusterSelectors(preferredClusterSelectors []placementv1be
 	for _, preferredClusterSelector := range preferredClusterSelectors {
 		// API server validation on object occurs before webhook is triggered hence not validating weight.
 		allErr = append(allErr, validateLabelSelector(preferredClusterSelector.Preference.LabelSelector, "preferred cluster selector"))
+		if preferredClusterSelector.Preference.ClusterGroup != "" {
+			allErr = append(allErr, clustergroup.Validate(preferredClusterSelector.Preference.ClusterGroup))
+		}
 
 		// Affinity is PreferredDuringSchedulingIgnoredDuringExecution, so check that PropertySelector is nil.
 		if preferredClusterSelector.Preference.PropertySelector != nil {
@@ -371,7 +460,8 @@ func validateLabelSelector(labelSelector *metav1.LabelSelector, parent string) e
 func validateRolloutStrategy(rolloutStrategy placementv1beta1.RolloutStrategy) error {
 	allErr := make([]error, 0)
 
-	if rolloutStrategy.Type != "" && rolloutStrategy.Type != placementv1beta1.RollingUpdateRolloutStrategyType {
+	if rolloutStrategy.Type != "" && rolloutStrategy.Type != placementv1beta1.RollingUpdateRolloutStrategyType &&
+		rolloutStrategy.Type != placementv1beta1.CanaryRolloutStrategyType {
 		allErr = append(allErr, fmt.Errorf("unsupported rollout strategy type `%s`", rolloutStrategy.Type))
 	}
 
@@ -399,6 +489,27 @@ func validateRolloutStrategy(rolloutStrategy placementv1beta1.RolloutStrategy) e
 		}
 	}
 
+	if rolloutStrategy.Canary != nil {
+		if rolloutStrategy.Type != placementv1beta1.CanaryRolloutStrategyType {
+			allErr = append(allErr, errors.New("canary config is only valid for Canary rollout strategy type"))
+		}
+		if rolloutStrategy.StagedUpdateRunName != "" {
+			allErr = append(allErr, errors.New("canary config cannot be used together with stagedUpdateRunName"))
+		}
+		if rolloutStrategy.Canary.SoakSeconds != nil && *rolloutStrategy.Canary.SoakSeconds < 0 {
+			allErr = append(allErr, fmt.Errorf("soakSeconds must be greater than or equal to 0, got %d", *rolloutStrategy.Canary.SoakSeconds))
+		}
+		if rolloutStrategy.Canary.Clusters != nil {
+			value, err := intstr.GetScaledValueFromIntOrPercent(rolloutStrategy.Canary.Clusters, 10, true)
+			if err != nil {
+				allErr = append(allErr, fmt.Errorf("canary clusters `%+v` is invalid: %w", rolloutStrategy.Canary.Clusters, err))
+			}
+			if value < 1 {
+				allErr = append(allErr, fmt.Errorf("canary clusters must be greater than or equal to 1, got `%+v`", rolloutStrategy.Canary.Clusters))
+			}
+		}
+	}
+
 	// server-side apply strategy type is only valid for server-side apply strategy type
 	if rolloutStrategy.ApplyStrategy != nil {
 		if rolloutStrategy.ApplyStrategy.Type != placementv1beta1.ApplyStrategyTypeServerSideApply && rolloutStrategy.ApplyStrategy.ServerSideApplyConfig != nil {
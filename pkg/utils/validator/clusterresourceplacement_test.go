@@ -13,15 +13,17 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
+	"go.goms.io/fleet/pkg/fleettest"
 	"go.goms.io/fleet/pkg/utils"
 	"go.goms.io/fleet/pkg/utils/informer"
-	testinformer "go.goms.io/fleet/test/utils/informer"
 )
 
 var (
@@ -66,6 +68,13 @@ func (m testMapper) RESTMapping(gk schema.GroupKind, _ ...string) (*meta.RESTMap
 			Scope:            nil,
 		}, nil
 	}
+	if gk.Kind == placementv1beta1.PlacementSourceKind {
+		return &meta.RESTMapping{
+			Resource:         placementv1beta1.GroupVersion.WithResource("placementsources"),
+			GroupVersionKind: placementv1beta1.GroupVersion.WithKind(placementv1beta1.PlacementSourceKind),
+			Scope:            nil,
+		}, nil
+	}
 	return nil, errors.New("test error: mapping does not exist")
 }
 
@@ -92,7 +101,7 @@ func TestValidateClusterResourcePlacementAlpha(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{IsClusterScopedResource: false},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
 			wantErr:          false,
 		},
 		"invalid Resource Selector with name & label selector": {
@@ -114,7 +123,7 @@ func TestValidateClusterResourcePlacementAlpha(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{IsClusterScopedResource: false},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
 			wantErr:          true,
 			wantErrMsg:       "the labelSelector and name fields are mutually exclusive in selector",
 		},
@@ -138,7 +147,7 @@ func TestValidateClusterResourcePlacementAlpha(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{IsClusterScopedResource: false},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
 			wantErr:          true,
 			wantErrMsg:       "for 'in', 'notin' operators, values set can't be empty",
 		},
@@ -158,7 +167,7 @@ func TestValidateClusterResourcePlacementAlpha(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{IsClusterScopedResource: true},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: true},
 			wantErr:          true,
 			wantErrMsg:       "the resource is not found in schema (please retry) or it is not a cluster scoped resource",
 		},
@@ -216,7 +225,7 @@ func TestValidateClusterResourcePlacementAlpha(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{IsClusterScopedResource: false},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
 			wantErr:          true,
 			wantErrMsg:       "for 'in', 'notin' operators, values set can't be empty",
 		},
@@ -254,7 +263,7 @@ func TestValidateClusterResourcePlacement(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{
+			resourceInformer: &fleettest.FakeInformerManager{
 				APIResources:            map[schema.GroupVersionKind]bool{ClusterRoleGVK: true},
 				IsClusterScopedResource: true},
 			wantErr: false,
@@ -272,7 +281,7 @@ func TestValidateClusterResourcePlacement(t *testing.T) {
 				},
 			},
 			wantErr: true,
-			resourceInformer: &testinformer.FakeManager{
+			resourceInformer: &fleettest.FakeInformerManager{
 				APIResources:            map[schema.GroupVersionKind]bool{ClusterRoleGVK: true},
 				IsClusterScopedResource: true},
 			wantErrMsg: "the name field cannot have length exceeding 63",
@@ -299,7 +308,7 @@ func TestValidateClusterResourcePlacement(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{
+			resourceInformer: &fleettest.FakeInformerManager{
 				APIResources:            map[schema.GroupVersionKind]bool{ClusterRoleGVK: true},
 				IsClusterScopedResource: true},
 			wantErr:    true,
@@ -321,7 +330,7 @@ func TestValidateClusterResourcePlacement(t *testing.T) {
 					},
 				},
 			},
-			resourceInformer: &testinformer.FakeManager{IsClusterScopedResource: false},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
 			wantErr:          true,
 			wantErrMsg:       "failed to get GVR of the selector",
 		},
@@ -342,11 +351,52 @@ func TestValidateClusterResourcePlacement(t *testing.T) {
 				},
 			},
 			wantErr: true,
-			resourceInformer: &testinformer.FakeManager{
+			resourceInformer: &fleettest.FakeInformerManager{
 				APIResources:            map[schema.GroupVersionKind]bool{utils.DeploymentGVK: true},
 				IsClusterScopedResource: false},
 			wantErrMsg: "resource is not found in schema (please retry) or it is not a cluster scoped resource",
 		},
+		"valid placement source selector": {
+			crp: &placementv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-crp",
+				},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{
+						{
+							Group:   placementv1beta1.GroupVersion.Group,
+							Version: placementv1beta1.GroupVersion.Version,
+							Kind:    placementv1beta1.PlacementSourceKind,
+							Name:    "test-source",
+						},
+					},
+				},
+			},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
+			wantErr:          false,
+		},
+		"invalid placement source selector without name": {
+			crp: &placementv1beta1.ClusterResourcePlacement{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-crp",
+				},
+				Spec: placementv1beta1.ClusterResourcePlacementSpec{
+					ResourceSelectors: []placementv1beta1.ClusterResourceSelector{
+						{
+							Group:   placementv1beta1.GroupVersion.Group,
+							Version: placementv1beta1.GroupVersion.Version,
+							Kind:    placementv1beta1.PlacementSourceKind,
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"test-key": "test-value"},
+							},
+						},
+					},
+				},
+			},
+			resourceInformer: &fleettest.FakeInformerManager{IsClusterScopedResource: false},
+			wantErr:          true,
+			wantErrMsg:       "the name field is required to select a placement source",
+		},
 		"nil resource informer": {
 			crp: &placementv1beta1.ClusterResourcePlacement{
 				ObjectMeta: metav1.ObjectMeta{
@@ -480,6 +530,53 @@ func TestValidateClusterResourcePlacement_RolloutStrategy(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "serverSideApplyConfig is only valid for ServerSideApply strategy type",
 		},
+		"valid rollout strategy - canary": {
+			strategy: placementv1beta1.RolloutStrategy{
+				Type: placementv1beta1.CanaryRolloutStrategyType,
+				Canary: &placementv1beta1.CanaryConfig{
+					Clusters:    ptr.To(intstr.FromString("10%")),
+					SoakSeconds: ptr.To(300),
+				},
+			},
+			wantErr: false,
+		},
+		"invalid rollout strategy - canary config not valid when type is not canary": {
+			strategy: placementv1beta1.RolloutStrategy{
+				Type:   placementv1beta1.RollingUpdateRolloutStrategyType,
+				Canary: &placementv1beta1.CanaryConfig{},
+			},
+			wantErr:    true,
+			wantErrMsg: "canary config is only valid for Canary rollout strategy type",
+		},
+		"invalid rollout strategy - canary with staged update run": {
+			strategy: placementv1beta1.RolloutStrategy{
+				Type:                placementv1beta1.CanaryRolloutStrategyType,
+				Canary:              &placementv1beta1.CanaryConfig{},
+				StagedUpdateRunName: "test-run",
+			},
+			wantErr:    true,
+			wantErrMsg: "canary config cannot be used together with stagedUpdateRunName",
+		},
+		"invalid rollout strategy - negative canary SoakSeconds": {
+			strategy: placementv1beta1.RolloutStrategy{
+				Type: placementv1beta1.CanaryRolloutStrategyType,
+				Canary: &placementv1beta1.CanaryConfig{
+					SoakSeconds: ptr.To(-1),
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "soakSeconds must be greater than or equal to 0, got -1",
+		},
+		"invalid rollout strategy - zero canary Clusters": {
+			strategy: placementv1beta1.RolloutStrategy{
+				Type: placementv1beta1.CanaryRolloutStrategyType,
+				Canary: &placementv1beta1.CanaryConfig{
+					Clusters: ptr.To(intstr.FromInt(0)),
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "canary clusters must be greater than or equal to 1, got `0`",
+		},
 	}
 
 	for testName, testCase := range tests {
@@ -596,6 +693,89 @@ func TestValidateClusterResourcePlacement_PickFixedPlacementPolicy(t *testing.T)
 			wantErr:    true,
 			wantErrMsg: "tolerations needs to be empty for policy type PickFixed, only valid for PickAll/PickN",
 		},
+		"invalid placement policy - PickFixed with non empty resource requirements": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType: placementv1beta1.PickFixedPlacementType,
+				ClusterNames:  []string{"test-cluster"},
+				ResourceRequirements: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+			wantErr:    true,
+			wantErrMsg: "resource requirements needs to be empty for policy type PickFixed, only valid for PickAll/PickN",
+		},
+		"invalid placement policy - PickFixed with scheduler profile": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickFixedPlacementType,
+				ClusterNames:     []string{"test-cluster"},
+				SchedulerProfile: "least-loaded",
+			},
+			wantErr:    true,
+			wantErrMsg: "scheduler profile needs to be empty for policy type PickFixed, only valid for PickAll/PickN",
+		},
+		"invalid placement policy - PickFixed with priority": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType: placementv1beta1.PickFixedPlacementType,
+				ClusterNames:  []string{"test-cluster"},
+				Priority:      100,
+			},
+			wantErr:    true,
+			wantErrMsg: "priority needs to be zero for policy type PickFixed, only valid for PickAll/PickN",
+		},
+		"invalid placement policy - PickFixed with score weights": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType: placementv1beta1.PickFixedPlacementType,
+				ClusterNames:  []string{"test-cluster"},
+				ScoreWeights:  &placementv1beta1.ScoreWeights{Affinity: ptr.To(int32(2))},
+			},
+			wantErr:    true,
+			wantErrMsg: "score weights needs to be nil for policy type PickFixed, only valid for PickN policy type",
+		},
+		"invalid placement policy - PickAll with score weights": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType: placementv1beta1.PickAllPlacementType,
+				ScoreWeights:  &placementv1beta1.ScoreWeights{Affinity: ptr.To(int32(2))},
+			},
+			wantErr:    true,
+			wantErrMsg: "score weights needs to be nil for policy type PickAll, only valid for PickN policy type",
+		},
+		"valid placement policy - PickN with score weights": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickNPlacementType,
+				NumberOfClusters: ptr.To(int32(2)),
+				ScoreWeights: &placementv1beta1.ScoreWeights{
+					Affinity:       ptr.To(int32(2)),
+					TopologySpread: ptr.To(int32(0)),
+					ResourceUsage:  ptr.To(int32(1)),
+				},
+			},
+			wantErr: false,
+		},
+		"invalid placement policy - PickFixed with network proximity": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickFixedPlacementType,
+				ClusterNames:     []string{"test-cluster"},
+				NetworkProximity: &placementv1beta1.NetworkProximity{Weight: 10},
+			},
+			wantErr:    true,
+			wantErrMsg: "network proximity needs to be nil for policy type PickFixed, only valid for PickN policy type",
+		},
+		"invalid placement policy - PickAll with network proximity": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickAllPlacementType,
+				NetworkProximity: &placementv1beta1.NetworkProximity{Weight: 10},
+			},
+			wantErr:    true,
+			wantErrMsg: "network proximity needs to be nil for policy type PickAll, only valid for PickN policy type",
+		},
+		"valid placement policy - PickN with network proximity": {
+			policy: &placementv1beta1.PlacementPolicy{
+				PlacementType:    placementv1beta1.PickNPlacementType,
+				NumberOfClusters: ptr.To(int32(2)),
+				NetworkProximity: &placementv1beta1.NetworkProximity{AnchorCluster: "test-cluster", Weight: 10},
+			},
+			wantErr: false,
+		},
 	}
 
 	for testName, testCase := range tests {
@@ -1565,6 +1745,82 @@ func TestValidateTolerations(t *testing.T) {
 	}
 }
 
+func TestValidateResourceRequirements(t *testing.T) {
+	tests := map[string]struct {
+		requirements corev1.ResourceList
+		wantErr      bool
+		wantErrMsg   string
+	}{
+		"no resource requirements": {},
+		"valid resource requirements": {
+			requirements: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+		"negative resource requirement": {
+			requirements: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("-1Gi"),
+			},
+			wantErr:    true,
+			wantErrMsg: "resource requirement memory cannot be negative, got -1Gi",
+		},
+	}
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			gotErr := validateResourceRequirements(testCase.requirements)
+			if (gotErr != nil) != testCase.wantErr {
+				t.Errorf("validateResourceRequirements() error = %v, wantErr %v", gotErr, testCase.wantErr)
+			}
+			if testCase.wantErr && !strings.Contains(gotErr.Error(), testCase.wantErrMsg) {
+				t.Errorf("validateResourceRequirements() got %v, should contain want %s", gotErr, testCase.wantErrMsg)
+			}
+		})
+	}
+}
+
+func TestValidateBindingGates(t *testing.T) {
+	tests := map[string]struct {
+		gates      []placementv1beta1.PlacementBindingGate
+		wantErr    bool
+		wantErrMsg string
+	}{
+		"no binding gates": {},
+		"valid binding gates": {
+			gates: []placementv1beta1.PlacementBindingGate{
+				{Name: "example.com/change-recorded", Point: placementv1beta1.BeforeApplyBindingGatePoint},
+				{Name: "ChangeApproved", Point: placementv1beta1.BeforeUnbindBindingGatePoint},
+			},
+		},
+		"invalid name": {
+			gates: []placementv1beta1.PlacementBindingGate{
+				{Name: "example.com/change recorded", Point: placementv1beta1.BeforeApplyBindingGatePoint},
+			},
+			wantErr:    true,
+			wantErrMsg: "the name of the binding gate example.com/change recorded is invalid",
+		},
+		"reserved name": {
+			gates: []placementv1beta1.PlacementBindingGate{
+				{Name: "Applied", Point: placementv1beta1.BeforeApplyBindingGatePoint},
+			},
+			wantErr:    true,
+			wantErrMsg: "the name of the binding gate Applied is reserved",
+		},
+	}
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			gotErr := validateBindingGates(testCase.gates)
+			if (gotErr != nil) != testCase.wantErr {
+				t.Errorf("validateBindingGates() error = %v, wantErr %v", gotErr, testCase.wantErr)
+			}
+			if testCase.wantErr && !strings.Contains(gotErr.Error(), testCase.wantErrMsg) {
+				t.Errorf("validateBindingGates() got %v, should contain want %s", gotErr, testCase.wantErrMsg)
+			}
+		})
+	}
+}
+
 func TestIsTolerationsUpdatedOrDeleted(t *testing.T) {
 	tests := map[string]struct {
 		oldTolerations []placementv1beta1.Toleration
@@ -1726,3 +1982,37 @@ func TestIsTolerationsUpdatedOrDeleted(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSchedulingGateAdded(t *testing.T) {
+	tests := map[string]struct {
+		oldGates []placementv1beta1.PlacementSchedulingGate
+		newGates []placementv1beta1.PlacementSchedulingGate
+		want     bool
+	}{
+		"old scheduling gates is nil": {
+			newGates: []placementv1beta1.PlacementSchedulingGate{{Name: "example.com/budget-approval"}},
+			want:     true,
+		},
+		"new scheduling gates is nil": {
+			oldGates: []placementv1beta1.PlacementSchedulingGate{{Name: "example.com/budget-approval"}},
+			want:     false,
+		},
+		"one scheduling gate was removed": {
+			oldGates: []placementv1beta1.PlacementSchedulingGate{{Name: "example.com/budget-approval"}, {Name: "example.com/security-review"}},
+			newGates: []placementv1beta1.PlacementSchedulingGate{{Name: "example.com/security-review"}},
+			want:     false,
+		},
+		"one scheduling gate was replaced": {
+			oldGates: []placementv1beta1.PlacementSchedulingGate{{Name: "example.com/budget-approval"}},
+			newGates: []placementv1beta1.PlacementSchedulingGate{{Name: "example.com/security-review"}},
+			want:     true,
+		},
+	}
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			if got := IsSchedulingGateAdded(testCase.oldGates, testCase.newGates); got != testCase.want {
+				t.Errorf("IsSchedulingGateAdded() got = %v, want = %v", got, testCase.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	"go.goms.io/fleet/pkg/utils/httpclient"
 )
 
 var (
@@ -17,7 +18,25 @@ var (
 
 // ValidateMemberCluster validates member cluster fields and returns error.
 func ValidateMemberCluster(mc clusterv1beta1.MemberCluster) error {
-	return validateTaints(mc.Spec.Taints)
+	return apiErrors.NewAggregate([]error{validateTaints(mc.Spec.Taints), validateConnectivity(mc.Spec.Connectivity)})
+}
+
+func validateConnectivity(connectivity *clusterv1beta1.MemberClusterConnectivity) error {
+	if connectivity == nil {
+		return nil
+	}
+	allErr := make([]error, 0)
+	if connectivity.ProxyURL != "" {
+		if _, err := httpclient.ParseProxyURL(connectivity.ProxyURL); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	if connectivity.PrivateEndpoint != "" {
+		if _, err := httpclient.ParsePrivateEndpoint(connectivity.PrivateEndpoint); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	return apiErrors.NewAggregate(allErr)
 }
 
 func validateTaints(taints []clusterv1beta1.Taint) error {
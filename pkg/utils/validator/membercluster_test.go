@@ -98,3 +98,47 @@ func TestValidateTaints(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConnectivity(t *testing.T) {
+	tests := map[string]struct {
+		connectivity *clusterv1beta1.MemberClusterConnectivity
+		wantErr      bool
+		wantErrMsg   string
+	}{
+		"nil connectivity": {
+			wantErr: false,
+		},
+		"valid proxy and private endpoint": {
+			connectivity: &clusterv1beta1.MemberClusterConnectivity{
+				ProxyURL:        "socks5://proxy.domain.com:1080",
+				PrivateEndpoint: "https://member.privatelink.domain.com",
+			},
+			wantErr: false,
+		},
+		"invalid proxy scheme": {
+			connectivity: &clusterv1beta1.MemberClusterConnectivity{
+				ProxyURL: "ftp://proxy.domain.com",
+			},
+			wantErr:    true,
+			wantErrMsg: "the scheme must be http, https or socks5",
+		},
+		"invalid private endpoint": {
+			connectivity: &clusterv1beta1.MemberClusterConnectivity{
+				PrivateEndpoint: "http://10.0.0.4",
+			},
+			wantErr:    true,
+			wantErrMsg: "it must be an https URL",
+		},
+	}
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			gotErr := validateConnectivity(testCase.connectivity)
+			if (gotErr != nil) != testCase.wantErr {
+				t.Errorf("validateConnectivity() error = %v, wantErr %v", gotErr, testCase.wantErr)
+			}
+			if testCase.wantErr && !strings.Contains(gotErr.Error(), testCase.wantErrMsg) {
+				t.Errorf("validateConnectivity() got %v, should contain want %s", gotErr, testCase.wantErrMsg)
+			}
+		})
+	}
+}
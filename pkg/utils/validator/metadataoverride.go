@@ -0,0 +1,100 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package validator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/util/errors"
+
+	fleetv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// ValidateClusterMetadataOverride validates cluster metadata override fields and returns error.
+func ValidateClusterMetadataOverride(cmo fleetv1alpha1.ClusterMetadataOverride) error {
+	allErr := make([]error, 0)
+	for _, selector := range cmo.Spec.ResourceSelectors {
+		if err := validateMetadataResourceSelector(selector); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	for _, rule := range cmo.Spec.MetadataOverrideRules {
+		if err := validateOverrideClusterSelector(rule.ClusterSelector); err != nil {
+			allErr = append(allErr, err)
+		}
+		if err := validateProtectedMetadataKeys("labels", rule.Labels); err != nil {
+			allErr = append(allErr, err)
+		}
+		if err := validateProtectedMetadataKeys("annotations", rule.Annotations); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	return apierrors.NewAggregate(allErr)
+}
+
+// validateMetadataResourceSelector checks if all the patterns of the resource selector are well-formed.
+func validateMetadataResourceSelector(selector fleetv1alpha1.MetadataResourceSelector) error {
+	allErr := make([]error, 0)
+	for _, pattern := range []string{selector.Group, selector.Version, selector.Kind, selector.Namespace, selector.Name} {
+		// path.Match checks the whole pattern even if the name does not match
+		if _, err := path.Match(pattern, ""); err != nil {
+			allErr = append(allErr, fmt.Errorf("invalid resource selector %+v: invalid pattern %q: %w", selector, pattern, err))
+		}
+	}
+	return apierrors.NewAggregate(allErr)
+}
+
+// validateOverrideClusterSelector checks if the cluster selector of an override rule only uses label selectors or
+// cluster groups.
+func validateOverrideClusterSelector(clusterSelector *fleetv1beta1.ClusterSelector) error {
+	if clusterSelector == nil {
+		return nil
+	}
+	allErr := make([]error, 0)
+	for _, selector := range clusterSelector.ClusterSelectorTerms {
+		// Check that only label selector is supported
+		if selector.PropertySelector != nil || selector.PropertySorter != nil {
+			allErr = append(allErr, fmt.Errorf("invalid clusterSelector %v: only labelSelector is supported", selector))
+			continue
+		}
+		if selector.LabelSelector == nil {
+			// a term may select the clusters of a cluster group only
+			if selector.ClusterGroup == "" {
+				allErr = append(allErr, fmt.Errorf("invalid clusterSelector %v: labelSelector is required", selector))
+			}
+		} else if err := validateLabelSelector(selector.LabelSelector, "cluster selector"); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	return apierrors.NewAggregate(allErr)
+}
+
+// validateProtectedMetadataKeys checks if any of the labels or annotations to set is under a protected path.
+func validateProtectedMetadataKeys(field string, metadata map[string]string) error {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	allErr := make([]error, 0)
+	for _, key := range keys {
+		if protectedPath, protected := FindProtectedMetadataKey(field, key); protected {
+			allErr = append(allErr, fmt.Errorf("invalid %s %q: the key overlaps with the protected path %s", field, key, protectedPath))
+		}
+	}
+	return apierrors.NewAggregate(allErr)
+}
+
+// FindProtectedMetadataKey returns the protected path which overlaps with the label or annotation of the given key,
+// where the field is either "labels" or "annotations".
+func FindProtectedMetadataKey(field, key string) (string, bool) {
+	escaped := strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+	return findOverrideProtectedPath("/metadata/" + field + "/" + escaped)
+}
@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fleetv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestValidateClusterMetadataOverride(t *testing.T) {
+	validSelector := fleetv1alpha1.MetadataResourceSelector{Group: "apps", Kind: "Deployment", Name: "web-*"}
+	tests := map[string]struct {
+		selector   fleetv1alpha1.MetadataResourceSelector
+		rule       fleetv1alpha1.MetadataOverrideRule
+		wantErrMsg error
+	}{
+		"valid cluster metadata override": {
+			selector: validSelector,
+			rule: fleetv1alpha1.MetadataOverrideRule{
+				ClusterSelector: &fleetv1beta1.ClusterSelector{},
+				Labels:          map[string]string{"team": "web"},
+				Annotations:     map[string]string{"example.com/owner": "web"},
+			},
+		},
+		"malformed name pattern": {
+			selector:   fleetv1alpha1.MetadataResourceSelector{Group: "apps", Kind: "Deployment", Name: "web-["},
+			rule:       fleetv1alpha1.MetadataOverrideRule{Labels: map[string]string{"team": "web"}},
+			wantErrMsg: errors.New(`invalid pattern "web-["`),
+		},
+		"malformed kind pattern": {
+			selector:   fleetv1alpha1.MetadataResourceSelector{Group: "apps", Kind: `Deploy\`},
+			rule:       fleetv1alpha1.MetadataOverrideRule{Labels: map[string]string{"team": "web"}},
+			wantErrMsg: errors.New(`invalid pattern "Deploy\\"`),
+		},
+		"invalid cluster label selector": {
+			selector: validSelector,
+			rule: fleetv1alpha1.MetadataOverrideRule{
+				ClusterSelector: &fleetv1beta1.ClusterSelector{
+					ClusterSelectorTerms: []fleetv1beta1.ClusterSelectorTerm{
+						{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"invalid key!": "value"}}},
+					},
+				},
+				Labels: map[string]string{"team": "web"},
+			},
+			wantErrMsg: errors.New("the labelSelector in cluster selector"),
+		},
+		"protected label": {
+			selector:   validSelector,
+			rule:       fleetv1alpha1.MetadataOverrideRule{Labels: map[string]string{"kubernetes-fleet.io/parent-CRP": "crp"}},
+			wantErrMsg: errors.New(`invalid labels "kubernetes-fleet.io/parent-CRP": the key overlaps with the protected path /metadata/labels/kubernetes-fleet.io~1parent-CRP`),
+		},
+		"protected annotation": {
+			selector:   validSelector,
+			rule:       fleetv1alpha1.MetadataOverrideRule{Annotations: map[string]string{"owner": "web"}},
+			wantErrMsg: errors.New(`invalid annotations "owner": the key overlaps with the protected path /metadata/annotations/owner`),
+		},
+	}
+	originalProtectedPaths := OverrideProtectedPaths
+	OverrideProtectedPaths = []string{"/metadata/labels/kubernetes-fleet.io~1parent-CRP", "/metadata/annotations/owner"}
+	defer func() {
+		OverrideProtectedPaths = originalProtectedPaths
+	}()
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			cmo := fleetv1alpha1.ClusterMetadataOverride{
+				Spec: fleetv1alpha1.ClusterMetadataOverrideSpec{
+					ResourceSelectors:     []fleetv1alpha1.MetadataResourceSelector{tt.selector},
+					MetadataOverrideRules: []fleetv1alpha1.MetadataOverrideRule{tt.rule},
+				},
+			}
+			got := ValidateClusterMetadataOverride(cmo)
+			if gotErr, wantErr := got != nil, tt.wantErrMsg != nil; gotErr != wantErr {
+				t.Fatalf("ValidateClusterMetadataOverride() = %v, want %v", got, tt.wantErrMsg)
+			}
+			if got != nil && !strings.Contains(got.Error(), tt.wantErrMsg.Error()) {
+				t.Errorf("ValidateClusterMetadataOverride() = %v, want %v", got, tt.wantErrMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/util/errors"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+// ValidatePlacementSource validates placement source fields and returns error.
+func ValidatePlacementSource(ps fleetv1beta1.PlacementSource) error {
+	allErr := make([]error, 0)
+	switch {
+	case ps.Spec.Git != nil && ps.Spec.OCI != nil:
+		allErr = append(allErr, errors.New("the git and oci sources are mutually exclusive"))
+	case ps.Spec.Git == nil && ps.Spec.OCI == nil:
+		allErr = append(allErr, errors.New("the placement source has no source specified"))
+	}
+	if ps.Spec.Git != nil {
+		if err := ValidatePlacementSourceSecretRef(ps.Spec.Git.SecretRef); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	if ps.Spec.OCI != nil {
+		if err := ValidatePlacementSourceSecretRef(ps.Spec.OCI.SecretRef); err != nil {
+			allErr = append(allErr, err)
+		}
+	}
+	return apierrors.NewAggregate(allErr)
+}
+
+// ValidatePlacementSourceSecretRef checks if the credentials secret of a placement source is in the fleet system
+// namespace. The hub agent reads the secret with its own permissions and sends it to the URL of the source, so a
+// secret in any other namespace would be disclosed to whoever can create a placement source.
+func ValidatePlacementSourceSecretRef(ref *fleetv1beta1.NamespacedName) error {
+	if ref == nil || ref.Namespace == utils.FleetSystemNamespace {
+		return nil
+	}
+	return fmt.Errorf("invalid secretRef %s/%s: the secret must be in the %s namespace", ref.Namespace, ref.Name, utils.FleetSystemNamespace)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func TestValidatePlacementSource(t *testing.T) {
+	tests := map[string]struct {
+		spec       fleetv1beta1.PlacementSourceSpec
+		wantErrMsg string
+	}{
+		"git source with a secret in the fleet system namespace": {
+			spec: fleetv1beta1.PlacementSourceSpec{
+				Git: &fleetv1beta1.GitSource{
+					URL:       "https://example.com/app.git",
+					SecretRef: &fleetv1beta1.NamespacedName{Namespace: "fleet-system", Name: "git-credentials"},
+				},
+			},
+		},
+		"oci source without a secret": {
+			spec: fleetv1beta1.PlacementSourceSpec{
+				OCI: &fleetv1beta1.OCISource{URL: "oci://example.com/app"},
+			},
+		},
+		"git source with a secret in another namespace": {
+			spec: fleetv1beta1.PlacementSourceSpec{
+				Git: &fleetv1beta1.GitSource{
+					URL:       "https://example.com/app.git",
+					SecretRef: &fleetv1beta1.NamespacedName{Namespace: "kube-system", Name: "bootstrap-token"},
+				},
+			},
+			wantErrMsg: "invalid secretRef kube-system/bootstrap-token: the secret must be in the fleet-system namespace",
+		},
+		"oci source with a secret in another namespace": {
+			spec: fleetv1beta1.PlacementSourceSpec{
+				OCI: &fleetv1beta1.OCISource{
+					URL:       "oci://example.com/app",
+					SecretRef: &fleetv1beta1.NamespacedName{Namespace: "team-a", Name: "registry"},
+				},
+			},
+			wantErrMsg: "invalid secretRef team-a/registry: the secret must be in the fleet-system namespace",
+		},
+		"both git and oci sources": {
+			spec: fleetv1beta1.PlacementSourceSpec{
+				Git: &fleetv1beta1.GitSource{URL: "https://example.com/app.git"},
+				OCI: &fleetv1beta1.OCISource{URL: "oci://example.com/app"},
+			},
+			wantErrMsg: "the git and oci sources are mutually exclusive",
+		},
+		"no source": {
+			wantErrMsg: "the placement source has no source specified",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidatePlacementSource(fleetv1beta1.PlacementSource{Spec: tc.spec})
+			if tc.wantErrMsg == "" {
+				if err != nil {
+					t.Fatalf("ValidatePlacementSource() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErrMsg) {
+				t.Fatalf("ValidatePlacementSource() = %v, want error containing %q", err, tc.wantErrMsg)
+			}
+		})
+	}
+}
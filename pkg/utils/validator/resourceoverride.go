@@ -14,8 +14,13 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/util/errors"
 
 	fleetv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	"go.goms.io/fleet/pkg/utils"
 )
 
+// OverrideProtectedPaths is the list of JSON pointer paths configured on the hub that no override is allowed to touch,
+// e.g. /spec/template/spec/containers/*/image. A "*" matches any single segment of the path.
+var OverrideProtectedPaths []string
+
 // ValidateResourceOverride validates resource override fields and returns error.
 func ValidateResourceOverride(ro fleetv1alpha1.ResourceOverride, roList *fleetv1alpha1.ResourceOverrideList) error {
 	allErr := make([]error, 0)
@@ -40,11 +45,16 @@ func ValidateResourceOverride(ro fleetv1alpha1.ResourceOverride, roList *fleetv1
 	return apierrors.NewAggregate(allErr)
 }
 
-// validateResourceSelectors checks if override is selecting a unique resource.
+// validateResourceSelectors checks if override is selecting a unique resource in its own namespace.
 func validateResourceSelectors(ro fleetv1alpha1.ResourceOverride) error {
 	selectorMap := make(map[fleetv1alpha1.ResourceSelector]bool)
 	allErr := make([]error, 0)
 	for _, selector := range ro.Spec.ResourceSelectors {
+		// A resource override can only select the resources in its namespace, while a namespace is cluster scoped and
+		// can only be overridden by a cluster resource override.
+		if selector.Group == utils.NamespaceMetaGVK.Group && selector.Kind == utils.NamespaceMetaGVK.Kind {
+			allErr = append(allErr, fmt.Errorf("invalid resource selector %+v: namespace cannot be selected by a resource override, use a cluster resource override instead", selector))
+		}
 		// Check if there are any duplicate selectors.
 		if selectorMap[selector] {
 			allErr = append(allErr, fmt.Errorf("resource selector %+v already exists, and must be unique", selector))
@@ -56,6 +66,7 @@ func validateResourceSelectors(ro fleetv1alpha1.ResourceOverride) error {
 
 // validateResourceOverrideResourceLimit checks if there is only 1 resource override per resource,
 // assuming the resource will be selected by the name only.
+// The overrides in the other namespaces are ignored as they select the resources in their own namespaces.
 func validateResourceOverrideResourceLimit(ro fleetv1alpha1.ResourceOverride, roList *fleetv1alpha1.ResourceOverrideList) error {
 	// Check if roList is nil or empty, no need to check for resource limit.
 	if roList == nil || len(roList.Items) == 0 {
@@ -64,6 +75,9 @@ func validateResourceOverrideResourceLimit(ro fleetv1alpha1.ResourceOverride, ro
 	overrideMap := make(map[fleetv1alpha1.ResourceSelector]string)
 	// Add overrides and its selectors to the map.
 	for _, override := range roList.Items {
+		if override.GetNamespace() != ro.GetNamespace() {
+			continue
+		}
 		selectors := override.Spec.ResourceSelectors
 		for _, selector := range selectors {
 			overrideMap[selector] = override.GetName()
@@ -88,19 +102,8 @@ func validateResourceOverrideResourceLimit(ro fleetv1alpha1.ResourceOverride, ro
 func validateOverridePolicy(policy *fleetv1alpha1.OverridePolicy) error {
 	allErr := make([]error, 0)
 	for _, rule := range policy.OverrideRules {
-		if rule.ClusterSelector != nil {
-			for _, selector := range rule.ClusterSelector.ClusterSelectorTerms {
-				// Check that only label selector is supported
-				if selector.PropertySelector != nil || selector.PropertySorter != nil {
-					allErr = append(allErr, fmt.Errorf("invalid clusterSelector %v: only labelSelector is supported", selector))
-					continue
-				}
-				if selector.LabelSelector == nil {
-					allErr = append(allErr, fmt.Errorf("invalid clusterSelector %v: labelSelector is required", selector))
-				} else if err := validateLabelSelector(selector.LabelSelector, "cluster selector"); err != nil {
-					allErr = append(allErr, err)
-				}
-			}
+		if err := validateOverrideClusterSelector(rule.ClusterSelector); err != nil {
+			allErr = append(allErr, err)
 		}
 
 		if err := validateJSONPatchOverride(rule.JSONPatchOverrides); err != nil {
@@ -122,6 +125,10 @@ func validateJSONPatchOverride(jsonPatchOverrides []fleetv1alpha1.JSONPatchOverr
 			allErr = append(allErr, fmt.Errorf("invalid JSONPatchOverride %s: %w", patch, err))
 		}
 
+		if protectedPath, protected := findOverrideProtectedPath(patch.Path); protected {
+			allErr = append(allErr, fmt.Errorf("invalid JSONPatchOverride %s: path overlaps with the protected path %s", patch, protectedPath))
+		}
+
 		if patch.Operator == fleetv1alpha1.JSONPatchOverrideOpRemove && len(patch.Value.Raw) != 0 {
 			allErr = append(allErr, fmt.Errorf("invalid JSONPatchOverride %s: remove operation cannot have value", patch))
 		}
@@ -160,3 +167,39 @@ func validateJSONPatchOverridePath(path string) error {
 	}
 	return nil
 }
+
+// findOverrideProtectedPath returns the protected path which overlaps with the given path.
+// A path overlaps with a protected path when either of them is the prefix of the other, as overriding the parent of a
+// protected field can modify the protected field too. The segments are compared after their escapes are decoded, so
+// that a field is matched however its path is written.
+func findOverrideProtectedPath(path string) (string, bool) {
+	parts := splitJSONPointer(path)
+	for _, protectedPath := range OverrideProtectedPaths {
+		protectedParts := splitJSONPointer(protectedPath)
+		n := len(parts)
+		if len(protectedParts) < n {
+			n = len(protectedParts)
+		}
+		overlapped := true
+		for i := 0; i < n; i++ {
+			if protectedParts[i] != "*" && protectedParts[i] != parts[i] {
+				overlapped = false
+				break
+			}
+		}
+		if overlapped {
+			return protectedPath, true
+		}
+	}
+	return "", false
+}
+
+// splitJSONPointer splits the JSON pointer into its segments with the escapes decoded as defined in RFC 6901, i.e.
+// "~1" is decoded into "/" before "~0" is decoded into "~".
+func splitJSONPointer(path string) []string {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range parts {
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(parts[i], "~1", "/"), "~0", "~")
+	}
+	return parts
+}
@@ -56,6 +56,22 @@ func TestValidateResourceSelectors(t *testing.T) {
 			},
 			wantErrMsg: nil,
 		},
+		"namespace selected": {
+			ro: fleetv1alpha1.ResourceOverride{
+				Spec: fleetv1alpha1.ResourceOverrideSpec{
+					ResourceSelectors: []fleetv1alpha1.ResourceSelector{
+						{
+							Group:   "",
+							Version: "v1",
+							Kind:    "Namespace",
+							Name:    "team-b",
+						},
+					},
+				},
+			},
+			wantErrMsg: fmt.Errorf("invalid resource selector %+v: namespace cannot be selected by a resource override, use a cluster resource override instead",
+				fleetv1alpha1.ResourceSelector{Group: "", Version: "v1", Kind: "Namespace", Name: "team-b"}),
+		},
 	}
 	for testName, tt := range tests {
 		t.Run(testName, func(t *testing.T) {
@@ -77,6 +93,26 @@ func TestValidateResourceOverrideResourceLimit(t *testing.T) {
 		overrideCount int
 		wantErrMsg    error
 	}{
+		"one override, selecting the same resource by other override in another namespace": {
+			ro: fleetv1alpha1.ResourceOverride{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "override-2",
+					Namespace: "team-b",
+				},
+				Spec: fleetv1alpha1.ResourceOverrideSpec{
+					ResourceSelectors: []fleetv1alpha1.ResourceSelector{
+						{
+							Group:   "group",
+							Version: "v1",
+							Kind:    "kind",
+							Name:    "example-0",
+						},
+					},
+				},
+			},
+			overrideCount: 1,
+			wantErrMsg:    nil,
+		},
 		"create one resource override for resource foo": {
 			ro: fleetv1alpha1.ResourceOverride{
 				ObjectMeta: metav1.ObjectMeta{
@@ -891,3 +927,77 @@ func TestValidateJSONPatchOverridePath(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateJSONPatchOverride_ProtectedPaths(t *testing.T) {
+	protectedPaths := []string{
+		"/spec/template/spec/containers/*/image",
+		"/spec/template/spec/securityContext",
+		"/metadata/annotations/fleet.example.com~1owner",
+		"/metadata/labels/team~ops",
+	}
+	tests := map[string]struct {
+		path       string
+		wantErrMsg error
+	}{
+		"valid json patch override - unprotected path": {
+			path:       "/spec/replicas",
+			wantErrMsg: nil,
+		},
+		"valid json patch override - sibling of the protected path": {
+			path:       "/spec/template/spec/containers/0/resources",
+			wantErrMsg: nil,
+		},
+		"invalid json patch override - protected path matched by wildcard": {
+			path:       "/spec/template/spec/containers/0/image",
+			wantErrMsg: errors.New("path overlaps with the protected path /spec/template/spec/containers/*/image"),
+		},
+		"invalid json patch override - child of the protected path": {
+			path:       "/spec/template/spec/securityContext/runAsUser",
+			wantErrMsg: errors.New("path overlaps with the protected path /spec/template/spec/securityContext"),
+		},
+		"invalid json patch override - parent of the protected path": {
+			path:       "/spec/template/spec/containers",
+			wantErrMsg: errors.New("path overlaps with the protected path /spec/template/spec/containers/*/image"),
+		},
+		"invalid json patch override - appending to the array containing the protected path": {
+			path:       "/spec/template/spec/containers/-",
+			wantErrMsg: errors.New("path overlaps with the protected path /spec/template/spec/containers/*/image"),
+		},
+		"invalid json patch override - protected key with an escaped slash": {
+			path:       "/metadata/annotations/fleet.example.com~1owner",
+			wantErrMsg: errors.New("path overlaps with the protected path /metadata/annotations/fleet.example.com~1owner"),
+		},
+		"invalid json patch override - protected key with an escaped tilde": {
+			path:       "/metadata/labels/team~0ops",
+			wantErrMsg: errors.New("path overlaps with the protected path /metadata/labels/team~ops"),
+		},
+		"valid json patch override - escaped tilde followed by 1 is not a slash": {
+			path:       "/metadata/annotations/fleet.example.com~01owner",
+			wantErrMsg: nil,
+		},
+	}
+	originalProtectedPaths := OverrideProtectedPaths
+	OverrideProtectedPaths = protectedPaths
+	defer func() {
+		OverrideProtectedPaths = originalProtectedPaths
+	}()
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			jsonPatchOverrides := []fleetv1alpha1.JSONPatchOverride{
+				{
+					Operator: fleetv1alpha1.JSONPatchOverrideOpReplace,
+					Path:     tt.path,
+					Value:    apiextensionsv1.JSON{Raw: []byte(`"value"`)},
+				},
+			}
+			got := validateJSONPatchOverride(jsonPatchOverrides)
+			if gotErr, wantErr := got != nil, tt.wantErrMsg != nil; gotErr != wantErr {
+				t.Fatalf("validateJSONPatchOverride() = %v, want %v", got, tt.wantErrMsg)
+			}
+
+			if got != nil && !strings.Contains(got.Error(), tt.wantErrMsg.Error()) {
+				t.Errorf("validateJSONPatchOverride() = %v, want %v", got, tt.wantErrMsg)
+			}
+		})
+	}
+}
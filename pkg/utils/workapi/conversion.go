@@ -0,0 +1,117 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package workapi converts the fleet Work and AppliedWork APIs from and to the sig-multicluster Work API, so that the
+// third-party tooling written against the standard API works with fleet.
+//
+// The fleet APIs are a superset of the standard API. The apply strategy of a work, which has no counterpart in the
+// standard API, is preserved in an annotation so that a round trip does not lose it. The audit records of an
+// AppliedWork are dropped as they are only meaningful to the fleet member agent.
+package workapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	workv1alpha1 "sigs.k8s.io/work-api/pkg/apis/v1alpha1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// ConvertWorkToUpstream converts a fleet work to the sig-multicluster Work API.
+func ConvertWorkToUpstream(in *fleetv1beta1.Work) (*workv1alpha1.Work, error) {
+	in = in.DeepCopy()
+	out := &workv1alpha1.Work{
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.SetGroupVersionKind(workv1alpha1.SchemeGroupVersion.WithKind(workv1alpha1.WorkKind))
+	for _, manifest := range in.Spec.Workload.Manifests {
+		out.Spec.Workload.Manifests = append(out.Spec.Workload.Manifests, workv1alpha1.Manifest{RawExtension: manifest.RawExtension})
+	}
+	if in.Spec.ApplyStrategy != nil {
+		applyStrategy, err := json.Marshal(in.Spec.ApplyStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the apply strategy of work %s/%s: %w", in.Namespace, in.Name, err)
+		}
+		if out.Annotations == nil {
+			out.Annotations = map[string]string{}
+		}
+		out.Annotations[fleetv1beta1.ApplyStrategyAnnotation] = string(applyStrategy)
+	}
+
+	out.Status.Conditions = in.Status.Conditions
+	for _, manifestCondition := range in.Status.ManifestConditions {
+		out.Status.ManifestConditions = append(out.Status.ManifestConditions, workv1alpha1.ManifestCondition{
+			Identifier: workv1alpha1.ResourceIdentifier(manifestCondition.Identifier),
+			Conditions: manifestCondition.Conditions,
+		})
+	}
+	return out, nil
+}
+
+// ConvertWorkFromUpstream converts a work of the sig-multicluster Work API to a fleet work.
+func ConvertWorkFromUpstream(in *workv1alpha1.Work) (*fleetv1beta1.Work, error) {
+	in = in.DeepCopy()
+	out := &fleetv1beta1.Work{
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.SetGroupVersionKind(fleetv1beta1.GroupVersion.WithKind(fleetv1beta1.WorkKind))
+	for _, manifest := range in.Spec.Workload.Manifests {
+		out.Spec.Workload.Manifests = append(out.Spec.Workload.Manifests, fleetv1beta1.Manifest{RawExtension: manifest.RawExtension})
+	}
+	if applyStrategy, ok := out.Annotations[fleetv1beta1.ApplyStrategyAnnotation]; ok {
+		out.Spec.ApplyStrategy = &fleetv1beta1.ApplyStrategy{}
+		if err := json.Unmarshal([]byte(applyStrategy), out.Spec.ApplyStrategy); err != nil {
+			return nil, fmt.Errorf("failed to decode the apply strategy of work %s/%s: %w", in.Namespace, in.Name, err)
+		}
+		delete(out.Annotations, fleetv1beta1.ApplyStrategyAnnotation)
+		if len(out.Annotations) == 0 {
+			out.Annotations = nil
+		}
+	}
+
+	out.Status.Conditions = in.Status.Conditions
+	for _, manifestCondition := range in.Status.ManifestConditions {
+		out.Status.ManifestConditions = append(out.Status.ManifestConditions, fleetv1beta1.ManifestCondition{
+			Identifier: fleetv1beta1.WorkResourceIdentifier(manifestCondition.Identifier),
+			Conditions: manifestCondition.Conditions,
+		})
+	}
+	return out, nil
+}
+
+// ConvertAppliedWorkToUpstream converts a fleet appliedWork to the sig-multicluster Work API.
+func ConvertAppliedWorkToUpstream(in *fleetv1beta1.AppliedWork) *workv1alpha1.AppliedWork {
+	in = in.DeepCopy()
+	out := &workv1alpha1.AppliedWork{
+		ObjectMeta: in.ObjectMeta,
+		Spec:       workv1alpha1.AppliedWorkSpec(in.Spec),
+	}
+	out.SetGroupVersionKind(workv1alpha1.SchemeGroupVersion.WithKind(workv1alpha1.AppliedWorkKind))
+	for _, resource := range in.Status.AppliedResources {
+		out.Status.AppliedResources = append(out.Status.AppliedResources, workv1alpha1.AppliedResourceMeta{
+			ResourceIdentifier: workv1alpha1.ResourceIdentifier(resource.WorkResourceIdentifier),
+			UID:                resource.UID,
+		})
+	}
+	return out
+}
+
+// ConvertAppliedWorkFromUpstream converts an appliedWork of the sig-multicluster Work API to a fleet appliedWork.
+func ConvertAppliedWorkFromUpstream(in *workv1alpha1.AppliedWork) *fleetv1beta1.AppliedWork {
+	in = in.DeepCopy()
+	out := &fleetv1beta1.AppliedWork{
+		ObjectMeta: in.ObjectMeta,
+		Spec:       fleetv1beta1.AppliedWorkSpec(in.Spec),
+	}
+	out.SetGroupVersionKind(fleetv1beta1.GroupVersion.WithKind(fleetv1beta1.AppliedWorkKind))
+	for _, resource := range in.Status.AppliedResources {
+		out.Status.AppliedResources = append(out.Status.AppliedResources, fleetv1beta1.AppliedResourceMeta{
+			WorkResourceIdentifier: fleetv1beta1.WorkResourceIdentifier(resource.ResourceIdentifier),
+			UID:                    resource.UID,
+		})
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package workapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1alpha1 "sigs.k8s.io/work-api/pkg/apis/v1alpha1"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+var (
+	testIdentifier = fleetv1beta1.WorkResourceIdentifier{
+		Ordinal:   0,
+		Group:     "apps",
+		Version:   "v1",
+		Kind:      "Deployment",
+		Resource:  "deployments",
+		Namespace: "app",
+		Name:      "web",
+	}
+	testConditions = []metav1.Condition{
+		{
+			Type:               fleetv1beta1.WorkConditionTypeApplied,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ManifestCreated",
+			ObservedGeneration: 1,
+		},
+	}
+)
+
+func newTestWork(applyStrategy *fleetv1beta1.ApplyStrategy) *fleetv1beta1.Work {
+	return &fleetv1beta1.Work{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fleetv1beta1.GroupVersion.String(),
+			Kind:       fleetv1beta1.WorkKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "crp-work",
+			Namespace:   "fleet-member-cluster-1",
+			Labels:      map[string]string{fleetv1beta1.CRPTrackingLabel: "crp"},
+			Annotations: map[string]string{"owner": "team-a"},
+		},
+		Spec: fleetv1beta1.WorkSpec{
+			Workload: fleetv1beta1.WorkloadTemplate{
+				Manifests: []fleetv1beta1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"app"}}`)}},
+				},
+			},
+			ApplyStrategy: applyStrategy,
+		},
+		Status: fleetv1beta1.WorkStatus{
+			Conditions: testConditions,
+			ManifestConditions: []fleetv1beta1.ManifestCondition{
+				{Identifier: testIdentifier, Conditions: testConditions},
+			},
+		},
+	}
+}
+
+func TestWorkRoundTrip(t *testing.T) {
+	tests := map[string]struct {
+		work *fleetv1beta1.Work
+	}{
+		"work without apply strategy": {
+			work: newTestWork(nil),
+		},
+		"work with apply strategy": {
+			work: newTestWork(&fleetv1beta1.ApplyStrategy{
+				Type:                  fleetv1beta1.ApplyStrategyTypeServerSideApply,
+				ServerSideApplyConfig: &fleetv1beta1.ServerSideApplyConfig{ForceConflicts: true},
+			}),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			upstream, err := ConvertWorkToUpstream(tc.work)
+			if err != nil {
+				t.Fatalf("ConvertWorkToUpstream() = %v, want nil", err)
+			}
+			if got, want := upstream.GroupVersionKind(), workv1alpha1.SchemeGroupVersion.WithKind(workv1alpha1.WorkKind); got != want {
+				t.Errorf("ConvertWorkToUpstream() GVK = %v, want %v", got, want)
+			}
+			got, err := ConvertWorkFromUpstream(upstream)
+			if err != nil {
+				t.Fatalf("ConvertWorkFromUpstream() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.work, got); diff != "" {
+				t.Errorf("work round trip mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertWorkFromUpstreamInvalidApplyStrategy(t *testing.T) {
+	upstream := &workv1alpha1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "crp-work",
+			Namespace:   "fleet-member-cluster-1",
+			Annotations: map[string]string{fleetv1beta1.ApplyStrategyAnnotation: "invalid"},
+		},
+	}
+	if _, err := ConvertWorkFromUpstream(upstream); err == nil {
+		t.Errorf("ConvertWorkFromUpstream() = nil, want error")
+	}
+}
+
+func TestAppliedWorkRoundTrip(t *testing.T) {
+	appliedWork := &fleetv1beta1.AppliedWork{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fleetv1beta1.GroupVersion.String(),
+			Kind:       fleetv1beta1.AppliedWorkKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "crp-work"},
+		Spec: fleetv1beta1.AppliedWorkSpec{
+			WorkName:      "crp-work",
+			WorkNamespace: "fleet-member-cluster-1",
+		},
+		Status: fleetv1beta1.AppliedWorkStatus{
+			AppliedResources: []fleetv1beta1.AppliedResourceMeta{
+				{WorkResourceIdentifier: testIdentifier, UID: "uid-1"},
+			},
+		},
+	}
+	got := ConvertAppliedWorkFromUpstream(ConvertAppliedWorkToUpstream(appliedWork))
+	if diff := cmp.Diff(appliedWork, got); diff != "" {
+		t.Errorf("appliedWork round trip mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+// strictDecode decodes the JSON and fails on any field that the target type does not know.
+func strictDecode(t *testing.T, data []byte, out interface{}) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		t.Fatalf("failed to decode %s strictly into %T: %v", data, out, err)
+	}
+}
+
+// TestWorkAPIConformance makes sure that the fleet APIs stay wire compatible with the sig-multicluster Work API, i.e.
+// the standard tooling can read the fleet objects except for the fleet only fields and vice versa.
+func TestWorkAPIConformance(t *testing.T) {
+	t.Run("fleet work is a standard work", func(t *testing.T) {
+		data, err := json.Marshal(newTestWork(nil))
+		if err != nil {
+			t.Fatalf("failed to encode the work: %v", err)
+		}
+		var upstream workv1alpha1.Work
+		strictDecode(t, data, &upstream)
+		want, err := ConvertWorkToUpstream(newTestWork(nil))
+		if err != nil {
+			t.Fatalf("ConvertWorkToUpstream() = %v, want nil", err)
+		}
+		want.TypeMeta = upstream.TypeMeta
+		if diff := cmp.Diff(want, &upstream); diff != "" {
+			t.Errorf("decoded work mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("standard work is a fleet work", func(t *testing.T) {
+		upstream, err := ConvertWorkToUpstream(newTestWork(nil))
+		if err != nil {
+			t.Fatalf("ConvertWorkToUpstream() = %v, want nil", err)
+		}
+		data, err := json.Marshal(upstream)
+		if err != nil {
+			t.Fatalf("failed to encode the work: %v", err)
+		}
+		var work fleetv1beta1.Work
+		strictDecode(t, data, &work)
+		want := newTestWork(nil)
+		want.TypeMeta = work.TypeMeta
+		if diff := cmp.Diff(want, &work); diff != "" {
+			t.Errorf("decoded work mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("standard appliedWork is a fleet appliedWork", func(t *testing.T) {
+		data, err := json.Marshal(workv1alpha1.AppliedWork{
+			ObjectMeta: metav1.ObjectMeta{Name: "crp-work"},
+			Spec:       workv1alpha1.AppliedWorkSpec{WorkName: "crp-work", WorkNamespace: "fleet-member-cluster-1"},
+			Status: workv1alpha1.AppliedtWorkStatus{
+				AppliedResources: []workv1alpha1.AppliedResourceMeta{
+					{ResourceIdentifier: workv1alpha1.ResourceIdentifier(testIdentifier), UID: "uid-1"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to encode the appliedWork: %v", err)
+		}
+		var appliedWork fleetv1beta1.AppliedWork
+		strictDecode(t, data, &appliedWork)
+	})
+
+	t.Run("condition types", func(t *testing.T) {
+		// the standard Work API reports the state of the workload with the Applied and Available conditions
+		if fleetv1beta1.WorkConditionTypeApplied != "Applied" || fleetv1beta1.WorkConditionTypeAvailable != "Available" {
+			t.Errorf("work condition types = %q, %q, want Applied, Available",
+				fleetv1beta1.WorkConditionTypeApplied, fleetv1beta1.WorkConditionTypeAvailable)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package worksigning
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+const (
+	keyVaultScope      = "https://vault.azure.net/.default"
+	keyVaultAPIVersion = "7.4"
+)
+
+// azureKeyVaultSigner signs with an EC P-256 key in Azure Key Vault, so that the private key never leaves the vault.
+type azureKeyVaultSigner struct {
+	keyURL     string
+	keyID      string
+	credential azcore.TokenCredential
+	httpClient *http.Client
+}
+
+// NewAzureKeyVaultSigner returns a Signer which signs with the EC P-256 key version identified by the URL, e.g.
+// https://<vault>.vault.azure.net/keys/<name>/<version>.
+func NewAzureKeyVaultSigner(ctx context.Context, keyURL string, credential azcore.TokenCredential) (Signer, error) {
+	return newAzureKeyVaultSigner(ctx, keyURL, credential, http.DefaultClient)
+}
+
+func newAzureKeyVaultSigner(ctx context.Context, keyURL string, credential azcore.TokenCredential, httpClient *http.Client) (Signer, error) {
+	u, err := url.Parse(keyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key URL %q: %w", keyURL, err)
+	}
+	if segments := strings.Split(strings.Trim(u.Path, "/"), "/"); u.Scheme != "https" || len(segments) != 3 || segments[0] != "keys" {
+		return nil, fmt.Errorf("invalid key URL %q: it must be https://<vault>/keys/<name>/<version>", keyURL)
+	}
+	s := &azureKeyVaultSigner{
+		keyURL:     strings.TrimSuffix(keyURL, "/"),
+		credential: credential,
+		httpClient: httpClient,
+	}
+	var bundle struct {
+		Key struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"key"`
+	}
+	if err := s.do(ctx, http.MethodGet, s.keyURL, nil, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to get the key %s: %w", keyURL, err)
+	}
+	if !strings.HasPrefix(bundle.Key.Kty, "EC") || bundle.Key.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported key %s of type %s and curve %s, only EC P-256 keys are supported", keyURL, bundle.Key.Kty, bundle.Key.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(bundle.Key.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate of the key %s: %w", keyURL, err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(bundle.Key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate of the key %s: %w", keyURL, err)
+	}
+	publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if s.keyID, err = PublicKeyID(publicKey); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// KeyID implements the Signer interface.
+func (s *azureKeyVaultSigner) KeyID() string {
+	return s.keyID
+}
+
+// Sign implements the Signer interface.
+func (s *azureKeyVaultSigner) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	request := map[string]string{
+		"alg":   "ES256",
+		"value": base64.RawURLEncoding.EncodeToString(digest),
+	}
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := s.do(ctx, http.MethodPost, s.keyURL+"/sign", request, &result); err != nil {
+		return nil, err
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(result.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature returned by the key vault: %w", err)
+	}
+	if len(raw) != 64 {
+		return nil, fmt.Errorf("invalid signature length %d returned by the key vault", len(raw))
+	}
+	// the key vault returns the concatenation of R and S while we use the ASN.1 encoding like the crypto library.
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(raw[:32]),
+		S: new(big.Int).SetBytes(raw[32:]),
+	})
+}
+
+func (s *azureKeyVaultSigner) do(ctx context.Context, method, endpoint string, in, out interface{}) error {
+	token, err := s.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{keyVaultScope}})
+	if err != nil {
+		return fmt.Errorf("failed to get a token for the key vault: %w", err)
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint+"?api-version="+keyVaultAPIVersion, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the key vault returned status %d: %s", resp.StatusCode, data)
+	}
+	return json.Unmarshal(data, out)
+}
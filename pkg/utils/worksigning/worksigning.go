@@ -0,0 +1,238 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package worksigning signs the content of the works on the hub cluster and verifies it on the member clusters, so
+// that manifests injected through a compromised write path of the hub API server are never applied.
+package worksigning
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+// Signer signs the digest of the content of the works.
+type Signer interface {
+	// KeyID returns the ID of the signing key which is derived from its public key, see PublicKeyID.
+	KeyID() string
+	// Sign signs the SHA-256 digest.
+	Sign(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// Verifier verifies the signatures of the works against a set of trusted public keys.
+type Verifier struct {
+	publicKeys map[string]crypto.PublicKey
+}
+
+// PublicKeyID returns the ID of a public key, which is the hex encoded prefix of the SHA-256 hash of its DER encoding.
+func PublicKeyID(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// Digest computes the SHA-256 digest of the signed content of the work: its namespace and name, so that a signed
+// work cannot be replayed in another member cluster, and its whole spec, so that neither the manifests nor how they
+// are applied, e.g. the apply strategy and its allow list, can be changed without breaking the signature. The content
+// is re-encoded in a canonical form, as the API server may change the key order and the whitespaces of the raw JSON.
+func Digest(work *fleetv1beta1.Work) ([]byte, error) {
+	content, err := json.Marshal(struct {
+		Namespace string                `json:"namespace"`
+		Name      string                `json:"name"`
+		Spec      fleetv1beta1.WorkSpec `json:"spec"`
+	}{
+		Namespace: work.Namespace,
+		Name:      work.Name,
+		Spec:      work.Spec,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the content of work %s/%s: %w", work.Namespace, work.Name, err)
+	}
+	// decoding into generic values and encoding again sorts the keys of the objects, including the ones in the raw
+	// manifests, and drops the whitespaces; the numbers are kept as they are written
+	var canonical interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&canonical); err != nil {
+		return nil, fmt.Errorf("failed to decode the content of work %s/%s: %w", work.Namespace, work.Name, err)
+	}
+	if content, err = json.Marshal(canonical); err != nil {
+		return nil, fmt.Errorf("failed to encode the content of work %s/%s: %w", work.Namespace, work.Name, err)
+	}
+	sum := sha256.Sum256(content)
+	return sum[:], nil
+}
+
+// SignWork signs the content of the work and records the signature in its annotations.
+func SignWork(ctx context.Context, signer Signer, work *fleetv1beta1.Work) error {
+	digest, err := Digest(work)
+	if err != nil {
+		return err
+	}
+	signature, err := signer.Sign(ctx, digest)
+	if err != nil {
+		return fmt.Errorf("failed to sign work %s/%s: %w", work.Namespace, work.Name, err)
+	}
+	if work.Annotations == nil {
+		work.Annotations = make(map[string]string, 2)
+	}
+	work.Annotations[fleetv1beta1.WorkSignatureAnnotation] = base64.StdEncoding.EncodeToString(signature)
+	work.Annotations[fleetv1beta1.WorkSignatureKeyIDAnnotation] = signer.KeyID()
+	return nil
+}
+
+// IsSignedBy tells if the work has a signature made by the signer.
+// It does not verify the signature as the signature may not be reproducible.
+func IsSignedBy(signer Signer, work *fleetv1beta1.Work) bool {
+	return work.Annotations[fleetv1beta1.WorkSignatureAnnotation] != "" &&
+		work.Annotations[fleetv1beta1.WorkSignatureKeyIDAnnotation] == signer.KeyID()
+}
+
+// NewVerifier returns a Verifier which trusts the PEM encoded public keys in the files.
+func NewVerifier(publicKeyFiles []string) (*Verifier, error) {
+	if len(publicKeyFiles) == 0 {
+		return nil, errors.New("at least one public key is required")
+	}
+	v := &Verifier{publicKeys: make(map[string]crypto.PublicKey, len(publicKeyFiles))}
+	for _, file := range publicKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the public key file %s: %w", file, err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("public key file %s is not PEM encoded", file)
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the public key file %s: %w", file, err)
+		}
+		if err := v.addPublicKey(publicKey); err != nil {
+			return nil, fmt.Errorf("invalid public key file %s: %w", file, err)
+		}
+	}
+	return v, nil
+}
+
+func (v *Verifier) addPublicKey(publicKey crypto.PublicKey) error {
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return fmt.Errorf("unsupported public key type %T, only ECDSA and Ed25519 keys are supported", publicKey)
+	}
+	keyID, err := PublicKeyID(publicKey)
+	if err != nil {
+		return err
+	}
+	v.publicKeys[keyID] = publicKey
+	return nil
+}
+
+// VerifyWork verifies that the content of the work is signed by one of the trusted keys.
+func (v *Verifier) VerifyWork(work *fleetv1beta1.Work) error {
+	encodedSignature, ok := work.Annotations[fleetv1beta1.WorkSignatureAnnotation]
+	if !ok {
+		return errors.New("the work is not signed")
+	}
+	keyID := work.Annotations[fleetv1beta1.WorkSignatureKeyIDAnnotation]
+	publicKey, ok := v.publicKeys[keyID]
+	if !ok {
+		return fmt.Errorf("the work is signed by an untrusted key %q", keyID)
+	}
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return fmt.Errorf("the signature is not base64 encoded: %w", err)
+	}
+	digest, err := Digest(work)
+	if err != nil {
+		return err
+	}
+	var valid bool
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest, signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, digest, signature)
+	}
+	if !valid {
+		return fmt.Errorf("the signature of the work does not match the key %q", keyID)
+	}
+	return nil
+}
+
+// keySigner signs with a local ECDSA or Ed25519 private key, e.g. one generated by "openssl genpkey" or exported
+// from a KMS.
+type keySigner struct {
+	keyID      string
+	privateKey crypto.Signer
+}
+
+// NewKeyFileSigner returns a Signer which signs with the PEM encoded PKCS #8 or SEC 1 private key in the file.
+func NewKeyFileSigner(privateKeyFile string) (Signer, error) {
+	data, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the private key file %s: %w", privateKeyFile, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key file %s is not PEM encoded", privateKeyFile)
+	}
+	var privateKey crypto.Signer
+	if block.Type == "EC PRIVATE KEY" {
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	} else {
+		var key interface{}
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err == nil {
+			switch k := key.(type) {
+			case *ecdsa.PrivateKey:
+				privateKey = k
+			case ed25519.PrivateKey:
+				privateKey = k
+			default:
+				err = fmt.Errorf("unsupported private key type %T, only ECDSA and Ed25519 keys are supported", key)
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key file %s: %w", privateKeyFile, err)
+	}
+	keyID, err := PublicKeyID(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &keySigner{keyID: keyID, privateKey: privateKey}, nil
+}
+
+// KeyID implements the Signer interface.
+func (s *keySigner) KeyID() string {
+	return s.keyID
+}
+
+// Sign implements the Signer interface.
+func (s *keySigner) Sign(_ context.Context, digest []byte) ([]byte, error) {
+	if _, ok := s.privateKey.(ed25519.PrivateKey); ok {
+		// Ed25519 signs the digest as the message
+		return s.privateKey.Sign(rand.Reader, digest, crypto.Hash(0))
+	}
+	return s.privateKey.Sign(rand.Reader, digest, crypto.SHA256)
+}
@@ -0,0 +1,264 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package worksigning
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+)
+
+func newTestWork(rawManifests ...string) *fleetv1beta1.Work {
+	work := &fleetv1beta1.Work{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crp-work",
+			Namespace: "fleet-member-cluster-1",
+		},
+		Spec: fleetv1beta1.WorkSpec{
+			ApplyStrategy: &fleetv1beta1.ApplyStrategy{
+				Type:             fleetv1beta1.ApplyStrategyTypeClientSideApply,
+				AllowedResources: &fleetv1beta1.ApplyAllowList{Namespaces: []string{"app"}},
+			},
+		},
+	}
+	for _, raw := range rawManifests {
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, fleetv1beta1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(raw)}})
+	}
+	return work
+}
+
+// writeKeyPair writes the private key and the public key of the signer into PEM files and returns their paths.
+func writeKeyPair(t *testing.T, privateKey crypto.Signer) (string, string) {
+	dir := t.TempDir()
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal the private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		t.Fatalf("failed to marshal the public key: %v", err)
+	}
+	privateFile := filepath.Join(dir, "key.pem")
+	publicFile := filepath.Join(dir, "key.pub")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatalf("failed to write the private key: %v", err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600); err != nil {
+		t.Fatalf("failed to write the public key: %v", err)
+	}
+	return privateFile, publicFile
+}
+
+func TestSignAndVerifyWork(t *testing.T) {
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, ed25519Key, _ := ed25519.GenerateKey(rand.Reader)
+	untrustedKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecdsaPrivateFile, ecdsaPublicFile := writeKeyPair(t, ecdsaKey)
+	ed25519PrivateFile, ed25519PublicFile := writeKeyPair(t, ed25519Key)
+	untrustedPrivateFile, _ := writeKeyPair(t, untrustedKey)
+
+	verifier, err := NewVerifier([]string{ecdsaPublicFile, ed25519PublicFile})
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v, want nil", err)
+	}
+	const manifest = `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"app"},"data":{"key":"value"}}`
+	tests := map[string]struct {
+		keyFile string
+		tamper  func(work *fleetv1beta1.Work)
+		wantErr string
+	}{
+		"signed by an ECDSA key": {
+			keyFile: ecdsaPrivateFile,
+		},
+		"signed by an Ed25519 key": {
+			keyFile: ed25519PrivateFile,
+		},
+		"manifests are re-encoded by the API server": {
+			keyFile: ecdsaPrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Spec.Workload.Manifests[0].Raw = []byte(`{"kind": "ConfigMap", "apiVersion": "v1", "data": {"key": "value"}, "metadata": {"namespace": "app", "name": "cm"}}`)
+			},
+		},
+		"manifests are changed": {
+			keyFile: ecdsaPrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Spec.Workload.Manifests[0].Raw = []byte(strings.Replace(manifest, "value", "injected", 1))
+			},
+			wantErr: "does not match",
+		},
+		"manifests are added": {
+			keyFile: ed25519PrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, work.Spec.Workload.Manifests[0])
+			},
+			wantErr: "does not match",
+		},
+		"apply strategy is changed": {
+			keyFile: ecdsaPrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Spec.ApplyStrategy.Type = fleetv1beta1.ApplyStrategyTypeServerSideApply
+			},
+			wantErr: "does not match",
+		},
+		"allow list is widened": {
+			keyFile: ed25519PrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Spec.ApplyStrategy.AllowedResources = nil
+			},
+			wantErr: "does not match",
+		},
+		"work is replayed in another member cluster": {
+			keyFile: ecdsaPrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Namespace = "fleet-member-cluster-2"
+			},
+			wantErr: "does not match",
+		},
+		"work is not signed": {
+			keyFile: ecdsaPrivateFile,
+			tamper: func(work *fleetv1beta1.Work) {
+				work.Annotations = nil
+			},
+			wantErr: "not signed",
+		},
+		"signed by an untrusted key": {
+			keyFile: untrustedPrivateFile,
+			wantErr: "untrusted key",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			signer, err := NewKeyFileSigner(tc.keyFile)
+			if err != nil {
+				t.Fatalf("NewKeyFileSigner() = %v, want nil", err)
+			}
+			work := newTestWork(manifest)
+			if err := SignWork(context.Background(), signer, work); err != nil {
+				t.Fatalf("SignWork() = %v, want nil", err)
+			}
+			if !IsSignedBy(signer, work) {
+				t.Errorf("IsSignedBy() = false, want true")
+			}
+			if tc.tamper != nil {
+				tc.tamper(work)
+			}
+			err = verifier.VerifyWork(work)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifyWork() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("VerifyWork() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	dir := t.TempDir()
+	invalidFile := filepath.Join(dir, "invalid.pub")
+	if err := os.WriteFile(invalidFile, []byte("not a key"), 0600); err != nil {
+		t.Fatalf("failed to write the file: %v", err)
+	}
+	tests := map[string]struct {
+		files []string
+	}{
+		"no public key":        {},
+		"file does not exist":  {files: []string{filepath.Join(dir, "missing.pub")}},
+		"file is not PEM data": {files: []string{invalidFile}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewVerifier(tc.files); err == nil {
+				t.Errorf("NewVerifier() = nil, want error")
+			}
+		})
+	}
+}
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestAzureKeyVaultSigner(t *testing.T) {
+	privateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, publicFile := writeKeyPair(t, privateKey)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("api-version") != keyVaultAPIVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/keys/fleet/v1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"key": map[string]string{
+					"kty": "EC-HSM",
+					"crv": "P-256",
+					"x":   base64.RawURLEncoding.EncodeToString(privateKey.X.FillBytes(make([]byte, 32))),
+					"y":   base64.RawURLEncoding.EncodeToString(privateKey.Y.FillBytes(make([]byte, 32))),
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/keys/fleet/v1/sign":
+			var req map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			digest, _ := base64.RawURLEncoding.DecodeString(req["value"])
+			sr, ss, _ := ecdsa.Sign(rand.Reader, privateKey, digest)
+			raw := append(sr.FillBytes(make([]byte, 32)), ss.FillBytes(make([]byte, 32))...)
+			_ = json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(raw)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := newAzureKeyVaultSigner(ctx, server.URL+"/keys/fleet", fakeCredential{}, server.Client()); err == nil {
+		t.Errorf("newAzureKeyVaultSigner() with a key URL without version = nil, want error")
+	}
+	if _, err := newAzureKeyVaultSigner(ctx, server.URL+"/keys/other/v1", fakeCredential{}, server.Client()); err == nil {
+		t.Errorf("newAzureKeyVaultSigner() with a missing key = nil, want error")
+	}
+	signer, err := newAzureKeyVaultSigner(ctx, server.URL+"/keys/fleet/v1", fakeCredential{}, server.Client())
+	if err != nil {
+		t.Fatalf("newAzureKeyVaultSigner() = %v, want nil", err)
+	}
+	verifier, err := NewVerifier([]string{publicFile})
+	if err != nil {
+		t.Fatalf("NewVerifier() = %v, want nil", err)
+	}
+	work := newTestWork(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"app"}}`)
+	if err := SignWork(ctx, signer, work); err != nil {
+		t.Fatalf("SignWork() = %v, want nil", err)
+	}
+	if err := verifier.VerifyWork(work); err != nil {
+		t.Errorf("VerifyWork() = %v, want nil", err)
+	}
+}
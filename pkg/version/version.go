@@ -0,0 +1,11 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package version features the version of the Fleet agents, which is set when the agents are built.
+package version
+
+// Version is the version of the agent, e.g. the tag of its image. It is set at build time with
+// -ldflags "-X go.goms.io/fleet/pkg/version.Version=<version>".
+var Version = "unknown"
@@ -1,10 +1,12 @@
 package webhook
 
 import (
+	"go.goms.io/fleet/pkg/webhook/clustermetadataoverride"
 	"go.goms.io/fleet/pkg/webhook/clusterresourceoverride"
 	"go.goms.io/fleet/pkg/webhook/clusterresourceplacement"
 	"go.goms.io/fleet/pkg/webhook/fleetresourcehandler"
 	"go.goms.io/fleet/pkg/webhook/membercluster"
+	"go.goms.io/fleet/pkg/webhook/placementsource"
 	"go.goms.io/fleet/pkg/webhook/pod"
 	"go.goms.io/fleet/pkg/webhook/replicaset"
 	"go.goms.io/fleet/pkg/webhook/resourceoverride"
@@ -21,4 +23,6 @@ func init() {
 	AddToManagerFuncs = append(AddToManagerFuncs, membercluster.Add)
 	AddToManagerFuncs = append(AddToManagerFuncs, clusterresourceoverride.Add)
 	AddToManagerFuncs = append(AddToManagerFuncs, resourceoverride.Add)
+	AddToManagerFuncs = append(AddToManagerFuncs, clustermetadataoverride.Add)
+	AddToManagerFuncs = append(AddToManagerFuncs, placementsource.Add)
 }
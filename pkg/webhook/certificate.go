@@ -0,0 +1,135 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// certRotationCheckInterval is how often we check whether the self-signed serving certificate needs to be rotated.
+	certRotationCheckInterval = time.Hour
+)
+
+// certRenewBeforeExpiryRatio decides when the self-signed serving certificate is rotated.
+// We rotate the certificate when less than 1/certRenewBeforeExpiryRatio of its validity is left.
+const certRenewBeforeExpiryRatio = 5
+
+// rotateCertificatePeriodically checks the self-signed serving certificate periodically and rotates it before it expires.
+// It runs on every replica as each replica serves its own certificate.
+func (w *Config) rotateCertificatePeriodically(ctx context.Context, k8sClient client.Client) {
+	ticker := time.NewTicker(certRotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			klog.V(2).InfoS("stop rotating the webhook serving certificate")
+			return
+		case <-ticker.C:
+			w.certLock.Lock()
+			if err := w.rotateCertificateIfNeeded(ctx, k8sClient, w.isLeader()); err != nil {
+				// we will retry in the next round as long as the certificate is not rotated
+				klog.ErrorS(err, "failed to rotate the webhook serving certificate", "notAfter", w.certNotAfter)
+			}
+			w.certLock.Unlock()
+		}
+	}
+}
+
+// rotateCertificateIfNeeded generates a new self-signed serving certificate when the current one is about to expire.
+// To avoid any outage, the new CA is added to the CA bundle of the webhook configurations before the webhook server
+// starts to serve the new certificate. The old CA stays in the bundle until the next rotation.
+// Only the leader updates the webhook configurations; the other replicas just rotate their local certificate and keep
+// the CA bundle to set up the webhook configurations with once they are elected.
+func (w *Config) rotateCertificateIfNeeded(ctx context.Context, k8sClient client.Client, isLeader bool) error {
+	if time.Until(w.certNotAfter) > w.certValidity/certRenewBeforeExpiryRatio {
+		return nil
+	}
+	klog.V(2).InfoS("the webhook serving certificate is about to expire, rotating it", "notAfter", w.certNotAfter)
+	caPEM, certPEM, keyPEM, err := w.genSelfSignedCert()
+	if err != nil {
+		return fmt.Errorf("fail to generate self-signed cert: %w", err)
+	}
+	notAfter, err := certificateNotAfter(certPEM)
+	if err != nil {
+		return err
+	}
+
+	caBundle := make([]byte, 0, len(caPEM)+len(w.servingCAPEM))
+	caBundle = append(caBundle, caPEM...)
+	caBundle = append(caBundle, w.servingCAPEM...)
+	if isLeader {
+		if err := w.injectCABundle(ctx, k8sClient, caBundle); err != nil {
+			return err
+		}
+	}
+	if err := writeCertAndKeyFile(certPEM, keyPEM, w.certDir); err != nil {
+		return fmt.Errorf("fail to write certificate and key files: %w", err)
+	}
+	w.caPEM = caBundle
+	w.servingCAPEM = caPEM
+	w.certNotAfter = notAfter
+	klog.V(2).InfoS("successfully rotated the webhook serving certificate", "notAfter", notAfter)
+	return nil
+}
+
+// isLeader returns whether the manager of the webhook server has been elected as the leader.
+func (w *Config) isLeader() bool {
+	select {
+	case <-w.mgr.Elected():
+		return true
+	default:
+		return false
+	}
+}
+
+// injectCABundle updates the CA bundle of all the fleet webhook configurations.
+func (w *Config) injectCABundle(ctx context.Context, k8sClient client.Client, caBundle []byte) error {
+	configNames := []string{fleetValidatingWebhookCfgName}
+	if w.enableGuardRail {
+		configNames = append(configNames, fleetGuardRailWebhookCfgName)
+	}
+	for _, configName := range configNames {
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			var config admv1.ValidatingWebhookConfiguration
+			if err := k8sClient.Get(ctx, client.ObjectKey{Name: configName}, &config); err != nil {
+				return err
+			}
+			for i := range config.Webhooks {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle
+			}
+			return k8sClient.Update(ctx, &config)
+		})
+		if err != nil {
+			return fmt.Errorf("fail to inject the CA bundle into the validating webhook configuration %s: %w", configName, err)
+		}
+		klog.V(2).InfoS("successfully injected the CA bundle", "name", configName)
+	}
+	return nil
+}
+
+// certificateNotAfter returns the expiration time of the PEM encoded certificate.
+func certificateNotAfter(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, errors.New("invalid certificate data")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fail to parse the certificate: %w", err)
+	}
+	return cert.NotAfter, nil
+}
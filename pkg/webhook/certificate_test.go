@@ -0,0 +1,166 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"go.goms.io/fleet/cmd/hubagent/options"
+)
+
+func TestRotateCertificateIfNeeded(t *testing.T) {
+	url := options.WebhookClientConnectionType("url")
+	validity := 24 * time.Hour
+	tests := map[string]struct {
+		enableGuardRail bool
+		follower        bool
+		remaining       time.Duration
+		wantRotated     bool
+	}{
+		"certificate is not about to expire": {
+			remaining:   validity / 2,
+			wantRotated: false,
+		},
+		"certificate is about to expire": {
+			remaining:   validity / 10,
+			wantRotated: true,
+		},
+		"certificate is about to expire with guard rail enabled": {
+			enableGuardRail: true,
+			remaining:       time.Minute,
+			wantRotated:     true,
+		},
+		"certificate of a follower is about to expire": {
+			follower:    true,
+			remaining:   validity / 10,
+			wantRotated: true,
+		},
+	}
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			certDir := t.TempDir()
+			w := &Config{
+				serviceNamespace:     "fleet-system",
+				serviceName:          "fleetwebhook",
+				servicePort:          9443,
+				clientConnectionType: &url,
+				certMode:             options.SelfSigned,
+				certDir:              certDir,
+				certValidity:         validity,
+				enableGuardRail:      tt.enableGuardRail,
+			}
+			oldCAPEM, err := w.genCertificate(certDir)
+			if err != nil {
+				t.Fatalf("genCertificate() = %v, want nil", err)
+			}
+			w.caPEM = oldCAPEM
+			w.certNotAfter = time.Now().Add(tt.remaining)
+			oldNotAfter := w.certNotAfter
+			oldCert, err := os.ReadFile(filepath.Join(certDir, fleetWebhookCertFileName))
+			if err != nil {
+				t.Fatalf("failed to read the certificate file: %v", err)
+			}
+
+			scheme := runtime.NewScheme()
+			if err := admv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add scheme: %v", err)
+			}
+			objects := []client.Object{
+				&admv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: fleetValidatingWebhookCfgName},
+					Webhooks:   w.buildFleetValidatingWebhooks(),
+				},
+			}
+			if tt.enableGuardRail {
+				objects = append(objects, &admv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: fleetGuardRailWebhookCfgName},
+					Webhooks:   w.buildFleetGuardRailValidatingWebhooks(),
+				})
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			if err := w.rotateCertificateIfNeeded(context.Background(), fakeClient, !tt.follower); err != nil {
+				t.Fatalf("rotateCertificateIfNeeded() = %v, want nil", err)
+			}
+
+			newCert, err := os.ReadFile(filepath.Join(certDir, fleetWebhookCertFileName))
+			if err != nil {
+				t.Fatalf("failed to read the certificate file: %v", err)
+			}
+			if gotRotated := !bytes.Equal(oldCert, newCert); gotRotated != tt.wantRotated {
+				t.Fatalf("rotateCertificateIfNeeded() rotated = %v, want %v", gotRotated, tt.wantRotated)
+			}
+			if !tt.wantRotated {
+				if !w.certNotAfter.Equal(oldNotAfter) {
+					t.Errorf("rotateCertificateIfNeeded() certNotAfter = %v, want %v", w.certNotAfter, oldNotAfter)
+				}
+				return
+			}
+
+			if !w.certNotAfter.After(time.Now().Add(validity / 2)) {
+				t.Errorf("rotateCertificateIfNeeded() certNotAfter = %v, want a renewed expiration time", w.certNotAfter)
+			}
+			if !bytes.HasSuffix(w.caPEM, oldCAPEM) {
+				t.Errorf("rotateCertificateIfNeeded() CA bundle does not contain the old CA")
+			}
+			if tt.follower {
+				// only the leader updates the webhook configurations
+				var config admv1.ValidatingWebhookConfiguration
+				if err := fakeClient.Get(context.Background(), client.ObjectKey{Name: fleetValidatingWebhookCfgName}, &config); err != nil {
+					t.Fatalf("failed to get the webhook configuration: %v", err)
+				}
+				for _, webhook := range config.Webhooks {
+					if !bytes.Equal(webhook.ClientConfig.CABundle, oldCAPEM) {
+						t.Errorf("webhook %s has CA bundle updated by a follower", webhook.Name)
+					}
+				}
+				return
+			}
+			// the new serving certificate must be trusted by the injected CA bundle
+			block, _ := pem.Decode(newCert)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatalf("failed to parse the new certificate: %v", err)
+			}
+			pool := x509.NewCertPool()
+			for _, configName := range []string{fleetValidatingWebhookCfgName, fleetGuardRailWebhookCfgName} {
+				if configName == fleetGuardRailWebhookCfgName && !tt.enableGuardRail {
+					continue
+				}
+				var config admv1.ValidatingWebhookConfiguration
+				if err := fakeClient.Get(context.Background(), client.ObjectKey{Name: configName}, &config); err != nil {
+					t.Fatalf("failed to get the webhook configuration %s: %v", configName, err)
+				}
+				for _, webhook := range config.Webhooks {
+					if !bytes.Equal(webhook.ClientConfig.CABundle, w.caPEM) {
+						t.Errorf("webhook %s of %s has CA bundle not updated", webhook.Name, configName)
+					}
+				}
+				pool.AppendCertsFromPEM(config.Webhooks[0].ClientConfig.CABundle)
+			}
+			if _, err := cert.Verify(x509.VerifyOptions{
+				DNSName:   "fleetwebhook.fleet-system.svc",
+				Roots:     pool,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}); err != nil {
+				t.Errorf("new certificate is not trusted by the CA bundle: %v", err)
+			}
+		})
+	}
+}
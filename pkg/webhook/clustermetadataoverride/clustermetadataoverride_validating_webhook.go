@@ -0,0 +1,54 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package clustermetadataoverride provides a validating webhook for the ClusterMetadataOverride custom resource in the fleet API group.
+package clustermetadataoverride
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/validator"
+)
+
+var (
+	// ValidationPath is the webhook service path which admission requests are routed to for validating ClusterMetadataOverride resources.
+	ValidationPath = fmt.Sprintf(utils.ValidationPathFmt, fleetv1alpha1.GroupVersion.Group, fleetv1alpha1.GroupVersion.Version, "clustermetadataoverride")
+)
+
+type clusterMetadataOverrideValidator struct {
+	decoder webhook.AdmissionDecoder
+}
+
+// Add registers the webhook for K8s bulit-in object types.
+func Add(mgr manager.Manager) error {
+	hookServer := mgr.GetWebhookServer()
+	hookServer.Register(ValidationPath, &webhook.Admission{Handler: &clusterMetadataOverrideValidator{admission.NewDecoder(mgr.GetScheme())}})
+	return nil
+}
+
+// Handle clusterMetadataOverrideValidator checks to see if cluster metadata override is valid
+func (v *clusterMetadataOverrideValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	var cmo fleetv1alpha1.ClusterMetadataOverride
+	klog.V(2).InfoS("Validating webhook handling cluster metadata override", "operation", req.Operation)
+	if err := v.decoder.Decode(req, &cmo); err != nil {
+		klog.ErrorS(err, "Failed to decode cluster metadata override object for validating fields", "userName", req.UserInfo.Username, "groups", req.UserInfo.Groups)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := validator.ValidateClusterMetadataOverride(cmo); err != nil {
+		klog.V(2).ErrorS(err, "ClusterMetadataOverride has invalid fields, request is denied", "operation", req.Operation)
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("clusterMetadataOverride has valid fields")
+}
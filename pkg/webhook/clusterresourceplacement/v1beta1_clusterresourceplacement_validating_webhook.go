@@ -59,6 +59,10 @@ func (v *clusterResourcePlacementValidator) Handle(_ context.Context, req admiss
 			if validator.IsTolerationsUpdatedOrDeleted(oldCRP.Tolerations(), crp.Tolerations()) {
 				return admission.Denied("tolerations have been updated/deleted, only additions to tolerations are allowed")
 			}
+			// handle update case where scheduling gates were added
+			if validator.IsSchedulingGateAdded(oldCRP.Spec.SchedulingGates, crp.Spec.SchedulingGates) {
+				return admission.Denied("scheduling gates have been added, only removals of scheduling gates are allowed")
+			}
 		}
 	}
 	klog.V(2).InfoS("user is allowed to modify v1beta1 cluster resource placement", "operation", req.Operation, "user", req.UserInfo.Username, "group", req.UserInfo.Groups, "namespacedName", types.NamespacedName{Name: crp.Name})
@@ -0,0 +1,54 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package placementsource provides a validating webhook for the PlacementSource custom resource in the fleet API group.
+package placementsource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	fleetv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/validator"
+)
+
+var (
+	// ValidationPath is the webhook service path which admission requests are routed to for validating PlacementSource resources.
+	ValidationPath = fmt.Sprintf(utils.ValidationPathFmt, fleetv1beta1.GroupVersion.Group, fleetv1beta1.GroupVersion.Version, "placementsource")
+)
+
+type placementSourceValidator struct {
+	decoder webhook.AdmissionDecoder
+}
+
+// Add registers the webhook for K8s bulit-in object types.
+func Add(mgr manager.Manager) error {
+	hookServer := mgr.GetWebhookServer()
+	hookServer.Register(ValidationPath, &webhook.Admission{Handler: &placementSourceValidator{admission.NewDecoder(mgr.GetScheme())}})
+	return nil
+}
+
+// Handle placementSourceValidator checks to see if placement source is valid
+func (v *placementSourceValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	var ps fleetv1beta1.PlacementSource
+	klog.V(2).InfoS("Validating webhook handling placement source", "operation", req.Operation)
+	if err := v.decoder.Decode(req, &ps); err != nil {
+		klog.ErrorS(err, "Failed to decode placement source object for validating fields", "userName", req.UserInfo.Username, "groups", req.UserInfo.Groups)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := validator.ValidatePlacementSource(ps); err != nil {
+		klog.V(2).ErrorS(err, "PlacementSource has invalid fields, request is denied", "operation", req.Operation)
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("placementSource has valid fields")
+}
@@ -49,7 +49,8 @@ func (v *resourceOverrideValidator) Handle(ctx context.Context, req admission.Re
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	// List of resource overrides.
+	// List of resource overrides in all the namespaces, as the override count limit is fleet wide; the validator only
+	// checks the conflicts with the overrides in the same namespace.
 	roList, err := listResourceOverride(ctx, v.client)
 	if err != nil {
 		return admission.Errored(http.StatusBadRequest, err)
@@ -68,7 +69,7 @@ func (v *resourceOverrideValidator) Handle(ctx context.Context, req admission.Re
 	return admission.Allowed("resourceOverride has valid fields")
 }
 
-// listResourceOverride returns a list of cluster resource overrides.
+// listResourceOverride returns a list of resource overrides.
 func listResourceOverride(ctx context.Context, client client.Client) (*fleetv1alpha1.ResourceOverrideList, error) {
 	roList := &fleetv1alpha1.ResourceOverrideList{}
 	if err := client.List(ctx, roList); err != nil {
@@ -18,6 +18,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	admv1 "k8s.io/api/admissionregistration/v1"
@@ -35,6 +36,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,10 +49,12 @@ import (
 	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
 	fleetv1alpha1 "go.goms.io/fleet/apis/v1alpha1"
 	"go.goms.io/fleet/cmd/hubagent/options"
+	"go.goms.io/fleet/pkg/webhook/clustermetadataoverride"
 	"go.goms.io/fleet/pkg/webhook/clusterresourceoverride"
 	"go.goms.io/fleet/pkg/webhook/clusterresourceplacement"
 	"go.goms.io/fleet/pkg/webhook/fleetresourcehandler"
 	"go.goms.io/fleet/pkg/webhook/membercluster"
+	"go.goms.io/fleet/pkg/webhook/placementsource"
 	"go.goms.io/fleet/pkg/webhook/pod"
 	"go.goms.io/fleet/pkg/webhook/replicaset"
 	"go.goms.io/fleet/pkg/webhook/resourceoverride"
@@ -62,6 +66,12 @@ const (
 	fleetValidatingWebhookCfgName = "fleet-validating-webhook-configuration"
 	fleetGuardRailWebhookCfgName  = "fleet-guard-rail-webhook-configuration"
 
+	// certManagerInjectCAAnnotation is the annotation used by the cert-manager CA injector to inject the CA bundle
+	// of the certificate into the webhook configurations.
+	certManagerInjectCAAnnotation = "cert-manager.io/inject-ca-from"
+	// certManagerCertificateNameFmt is the name format of the cert-manager certificate of the webhook service.
+	certManagerCertificateNameFmt = "%s-cert"
+
 	crdResourceName                      = "customresourcedefinitions"
 	bindingResourceName                  = "bindings"
 	configMapResourceName                = "configmaps"
@@ -101,6 +111,8 @@ const (
 	podResourceName                      = "pods"
 	clusterResourceOverrideName          = "clusterresourceoverrides"
 	resourceOverrideName                 = "resourceoverrides"
+	clusterMetadataOverrideName          = "clustermetadataoverrides"
+	placementSourceName                  = "placementsources"
 )
 
 var (
@@ -142,10 +154,23 @@ type Config struct {
 
 	clientConnectionType *options.WebhookClientConnectionType
 
+	// certificate info
+	certMode     options.WebhookCertMode
+	certDir      string
+	certValidity time.Duration
+	// servingCAPEM is the PEM encoded CA which signs the current serving certificate.
+	servingCAPEM []byte
+	// certNotAfter is the expiration time of the current serving certificate.
+	certNotAfter time.Time
+	// certLock guards the CA bundle and the certificate info, which every replica rotates while the leader sets up
+	// the webhook configurations with them.
+	certLock sync.Mutex
+
 	enableGuardRail bool
 }
 
-func NewWebhookConfig(mgr manager.Manager, webhookServiceName string, port int, clientConnectionType *options.WebhookClientConnectionType, certDir string, enableGuardRail bool) (*Config, error) {
+func NewWebhookConfig(mgr manager.Manager, webhookServiceName string, port int, clientConnectionType *options.WebhookClientConnectionType,
+	certMode options.WebhookCertMode, certDir string, certValidity time.Duration, enableGuardRail bool) (*Config, error) {
 	// We assume the Pod namespace should be passed to env through downward API in the Pod spec.
 	namespace := os.Getenv("POD_NAMESPACE")
 	if namespace == "" {
@@ -158,8 +183,16 @@ func NewWebhookConfig(mgr manager.Manager, webhookServiceName string, port int,
 		serviceName:          webhookServiceName,
 		serviceURL:           fmt.Sprintf("https://%s.%s.svc.cluster.local:%d", webhookServiceName, namespace, port),
 		clientConnectionType: clientConnectionType,
+		certMode:             certMode,
+		certDir:              certDir,
+		certValidity:         certValidity,
 		enableGuardRail:      enableGuardRail,
 	}
+	if certMode == options.CertManager {
+		// cert-manager mounts the serving certificate into the certDir and injects the CA bundle.
+		klog.V(2).InfoS("using the cert-manager issued serving certificate", "certDir", certDir)
+		return &w, nil
+	}
 	caPEM, err := w.genCertificate(certDir)
 	if err != nil {
 		return nil, err
@@ -168,8 +201,25 @@ func NewWebhookConfig(mgr manager.Manager, webhookServiceName string, port int,
 	return &w, err
 }
 
+// NeedLeaderElection implements the LeaderElectionRunnable interface, so that every replica rotates the serving
+// certificate it serves; only the webhook configurations are set up by the leader.
+func (w *Config) NeedLeaderElection() bool {
+	return false
+}
+
 func (w *Config) Start(ctx context.Context) error {
+	if w.certMode == options.SelfSigned {
+		// the rotation exits on context cancellation.
+		go w.rotateCertificatePeriodically(ctx, w.mgr.GetClient())
+	}
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-w.mgr.Elected():
+	}
 	klog.V(2).InfoS("setting up webhooks in apiserver from the leader")
+	w.certLock.Lock()
+	defer w.certLock.Unlock()
 	if err := w.createFleetWebhookConfiguration(ctx); err != nil {
 		klog.ErrorS(err, "unable to setup webhook configurations in apiserver")
 		return err
@@ -179,18 +229,18 @@ func (w *Config) Start(ctx context.Context) error {
 
 // createFleetWebhookConfiguration creates the ValidatingWebhookConfiguration object for the webhook.
 func (w *Config) createFleetWebhookConfiguration(ctx context.Context) error {
-	if err := w.createValidatingWebhookConfiguration(ctx, w.buildFleetValidatingWebhooks(), fleetValidatingWebhookCfgName); err != nil {
+	if err := w.createValidatingWebhookConfiguration(ctx, w.mgr.GetClient(), w.buildFleetValidatingWebhooks(), fleetValidatingWebhookCfgName); err != nil {
 		return err
 	}
 	if w.enableGuardRail {
-		if err := w.createValidatingWebhookConfiguration(ctx, w.buildFleetGuardRailValidatingWebhooks(), fleetGuardRailWebhookCfgName); err != nil {
+		if err := w.createValidatingWebhookConfiguration(ctx, w.mgr.GetClient(), w.buildFleetGuardRailValidatingWebhooks(), fleetGuardRailWebhookCfgName); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (w *Config) createValidatingWebhookConfiguration(ctx context.Context, webhooks []admv1.ValidatingWebhook, configName string) error {
+func (w *Config) createValidatingWebhookConfiguration(ctx context.Context, k8sClient client.Client, webhooks []admv1.ValidatingWebhook, configName string) error {
 	validatingWebhookConfig := admv1.ValidatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: configName,
@@ -200,23 +250,46 @@ func (w *Config) createValidatingWebhookConfiguration(ctx context.Context, webho
 		},
 		Webhooks: webhooks,
 	}
+	if w.certMode == options.CertManager {
+		validatingWebhookConfig.Annotations = map[string]string{
+			certManagerInjectCAAnnotation: fmt.Sprintf("%s/"+certManagerCertificateNameFmt, w.serviceNamespace, w.serviceName),
+		}
+	}
 
 	// We need to ensure this webhook configuration is garbage collected if Fleet is uninstalled from the cluster.
 	// Since the fleet-system namespace is a prerequisite for core Fleet components, we bind to this namespace.
-	if err := bindWebhookConfigToFleetSystem(ctx, w.mgr.GetClient(), &validatingWebhookConfig); err != nil {
+	if err := bindWebhookConfigToFleetSystem(ctx, k8sClient, &validatingWebhookConfig); err != nil {
 		return err
 	}
 
-	if err := w.mgr.GetClient().Create(ctx, &validatingWebhookConfig); err != nil {
+	if err := k8sClient.Create(ctx, &validatingWebhookConfig); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
 			return err
 		}
 		klog.V(2).InfoS("validating webhook configuration exists, need to overwrite", "name", configName)
-		// Here we simply use delete/create pattern to implement full overwrite
-		if err := w.mgr.GetClient().Delete(ctx, &validatingWebhookConfig); err != nil {
-			return err
-		}
-		if err = w.mgr.GetClient().Create(ctx, &validatingWebhookConfig); err != nil {
+		// The existing configuration is updated in place rather than deleted and recreated, so that the webhooks are
+		// never missing and, in the cert-manager mode, the CA bundle injected by cert-manager is kept.
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			var existing admv1.ValidatingWebhookConfiguration
+			if err := k8sClient.Get(ctx, client.ObjectKey{Name: configName}, &existing); err != nil {
+				return err
+			}
+			if w.certMode == options.CertManager {
+				injectedCABundles := make(map[string][]byte, len(existing.Webhooks))
+				for _, webhook := range existing.Webhooks {
+					injectedCABundles[webhook.Name] = webhook.ClientConfig.CABundle
+				}
+				for i := range validatingWebhookConfig.Webhooks {
+					validatingWebhookConfig.Webhooks[i].ClientConfig.CABundle = injectedCABundles[validatingWebhookConfig.Webhooks[i].Name]
+				}
+			}
+			existing.Labels = validatingWebhookConfig.Labels
+			existing.Annotations = validatingWebhookConfig.Annotations
+			existing.OwnerReferences = validatingWebhookConfig.OwnerReferences
+			existing.Webhooks = validatingWebhookConfig.Webhooks
+			return k8sClient.Update(ctx, &existing)
+		})
+		if err != nil {
 			return err
 		}
 		klog.V(2).InfoS("successfully overwritten validating webhook configuration", "name", configName)
@@ -346,6 +419,40 @@ func (w *Config) buildFleetValidatingWebhooks() []admv1.ValidatingWebhook {
 			},
 			TimeoutSeconds: longWebhookTimeout,
 		},
+		{
+			Name:                    "fleet.clustermetadataoverride.validating",
+			ClientConfig:            w.createClientConfig(clustermetadataoverride.ValidationPath),
+			FailurePolicy:           &failFailurePolicy,
+			SideEffects:             &sideEffortsNone,
+			AdmissionReviewVersions: admissionReviewVersions,
+			Rules: []admv1.RuleWithOperations{
+				{
+					Operations: []admv1.OperationType{
+						admv1.Create,
+						admv1.Update,
+					},
+					Rule: createRule([]string{placementv1alpha1.GroupVersion.Group}, []string{placementv1alpha1.GroupVersion.Version}, []string{clusterMetadataOverrideName}, &clusterScope),
+				},
+			},
+			TimeoutSeconds: longWebhookTimeout,
+		},
+		{
+			Name:                    "fleet.placementsource.validating",
+			ClientConfig:            w.createClientConfig(placementsource.ValidationPath),
+			FailurePolicy:           &failFailurePolicy,
+			SideEffects:             &sideEffortsNone,
+			AdmissionReviewVersions: admissionReviewVersions,
+			Rules: []admv1.RuleWithOperations{
+				{
+					Operations: []admv1.OperationType{
+						admv1.Create,
+						admv1.Update,
+					},
+					Rule: createRule([]string{placementv1beta1.GroupVersion.Group}, []string{placementv1beta1.GroupVersion.Version}, []string{placementSourceName}, &clusterScope),
+				},
+			},
+			TimeoutSeconds: longWebhookTimeout,
+		},
 	}
 
 	return webHooks
@@ -590,14 +697,32 @@ func (w *Config) genCertificate(certDir string) ([]byte, error) {
 		klog.ErrorS(err, "fail to generate certificate and key files")
 		return nil, err
 	}
+	if w.certNotAfter, err = certificateNotAfter(certPEM); err != nil {
+		klog.ErrorS(err, "fail to parse the generated certificate")
+		return nil, err
+	}
+	w.servingCAPEM = caPEM
 	return caPEM, nil
 }
 
 // genSelfSignedCert generates the self signed Certificate/Key pair
 func (w *Config) genSelfSignedCert() (caPEMByte, certPEMByte, keyPEMByte []byte, err error) {
+	// every rotated certificate gets its own serial number
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	caSerialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certSerialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	notBefore := time.Now()
+	notAfter := notBefore.Add(w.certValidity)
+
 	// CA config
 	ca := &x509.Certificate{
-		SerialNumber: big.NewInt(2022),
+		SerialNumber: caSerialNumber,
 		Subject: pkix.Name{
 			CommonName:         "fleet.azure.com",
 			OrganizationalUnit: []string{"Azure Kubernetes Service"},
@@ -606,8 +731,8 @@ func (w *Config) genSelfSignedCert() (caPEMByte, certPEMByte, keyPEMByte []byte,
 			Province:           []string{"Washington"},
 			Country:            []string{"United States of America"},
 		},
-		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(10, 0, 0), // Set expiration time to be 10 years for now
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
 		IsCA:                  true,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -643,7 +768,7 @@ func (w *Config) genSelfSignedCert() (caPEMByte, certPEMByte, keyPEMByte []byte,
 	// server cert config
 	cert := &x509.Certificate{
 		DNSNames:     dnsNames,
-		SerialNumber: big.NewInt(2022),
+		SerialNumber: certSerialNumber,
 		Subject: pkix.Name{
 			CommonName:         fmt.Sprintf("%s.cert.server", w.serviceName),
 			OrganizationalUnit: []string{"Azure Kubernetes Service"},
@@ -652,8 +777,8 @@ func (w *Config) genSelfSignedCert() (caPEMByte, certPEMByte, keyPEMByte []byte,
 			Province:           []string{"Washington"},
 			Country:            []string{"United States of America"},
 		},
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().AddDate(10, 0, 0),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
 		SubjectKeyId: []byte{1, 2, 3, 4, 5},
 		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		KeyUsage:     x509.KeyUsageDigitalSignature,
@@ -701,34 +826,58 @@ func genCertAndKeyFile(certData, keyData []byte, certDir string) error {
 	if err := os.MkdirAll(certDir, 0755); err != nil {
 		return fmt.Errorf("could not create directory %q to store certificates: %w", certDir, err)
 	}
-	certPath := filepath.Join(certDir, fleetWebhookCertFileName)
-	f, err := os.OpenFile(filepath.Clean(certPath), os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0600)
-	if err != nil {
-		return fmt.Errorf("could not open %q: %w", certPath, err)
+	if err := writeCertAndKeyFile(certData, keyData, certDir); err != nil {
+		return err
 	}
-	defer f.Close()
+	klog.V(2).InfoS("successfully generate certificate and key files")
+	return nil
+}
+
+// writeCertAndKeyFile writes the serving certificate/key files so that the certificate watcher of the webhook server
+// can pick up the changes.
+//
+// Each file is written to a temporary file first and then renamed over the old one, so that the webhook server never
+// reads a partially written file. The files are replaced one by one, so the old certificate is briefly paired with
+// the new key; the certificate watcher fails to load the mismatched pair and keeps serving the one it loaded before
+// until the certificate is replaced too.
+func writeCertAndKeyFile(certData, keyData []byte, certDir string) error {
 	certBlock, _ := pem.Decode(certData)
 	if certBlock == nil {
 		return fmt.Errorf("invalid certificate data")
 	}
-	if err := pem.Encode(f, certBlock); err != nil {
+	keyBlock, _ := pem.Decode(keyData)
+	if keyBlock == nil {
+		return fmt.Errorf("invalid key data")
+	}
+	if err := writeFileAtomically(filepath.Join(certDir, fleetWebhookKeyFileName), pem.EncodeToMemory(keyBlock)); err != nil {
 		return err
 	}
+	return writeFileAtomically(filepath.Join(certDir, fleetWebhookCertFileName), pem.EncodeToMemory(certBlock))
+}
 
-	keyPath := filepath.Join(certDir, fleetWebhookKeyFileName)
-	kf, err := os.OpenFile(filepath.Clean(keyPath), os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0600)
+// writeFileAtomically replaces the file at path with the data through a temporary file in the same directory.
+func writeFileAtomically(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
 	if err != nil {
-		return fmt.Errorf("could not open %q: %w", keyPath, err)
+		return fmt.Errorf("could not create a temporary file for %q: %w", path, err)
 	}
-
-	keyBlock, _ := pem.Decode(keyData)
-	if keyBlock == nil {
-		return fmt.Errorf("invalid key data")
+	tmpPath := f.Name()
+	// the temporary file is removed if it is not renamed
+	defer os.Remove(tmpPath)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %q: %w", tmpPath, err)
 	}
-	if err := pem.Encode(kf, keyBlock); err != nil {
-		return err
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("could not sync %q: %w", tmpPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close %q: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, filepath.Clean(path)); err != nil {
+		return fmt.Errorf("could not rename %q to %q: %w", tmpPath, path, err)
 	}
-	klog.V(2).InfoS("successfully generate certificate and key files")
 	return nil
 }
 
@@ -1,9 +1,21 @@
 package webhook
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"go.goms.io/fleet/cmd/hubagent/options"
 	"go.goms.io/fleet/pkg/utils"
@@ -12,17 +24,17 @@ import (
 func TestBuildFleetValidatingWebhooks(t *testing.T) {
 	url := options.WebhookClientConnectionType("url")
 	testCases := map[string]struct {
-		config     Config
+		config     *Config
 		wantLength int
 	}{
 		"valid input": {
-			config: Config{
+			config: &Config{
 				serviceNamespace:     "test-namespace",
 				servicePort:          8080,
 				serviceURL:           "test-url",
 				clientConnectionType: &url,
 			},
-			wantLength: 7,
+			wantLength: 9,
 		},
 	}
 
@@ -37,11 +49,11 @@ func TestBuildFleetValidatingWebhooks(t *testing.T) {
 func TestBuildFleetGuardRailValidatingWebhooks(t *testing.T) {
 	url := options.WebhookClientConnectionType("url")
 	testCases := map[string]struct {
-		config     Config
+		config     *Config
 		wantLength int
 	}{
 		"valid input": {
-			config: Config{
+			config: &Config{
 				serviceNamespace:     "test-namespace",
 				servicePort:          8080,
 				serviceURL:           "test-url",
@@ -58,3 +70,125 @@ func TestBuildFleetGuardRailValidatingWebhooks(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateValidatingWebhookConfiguration(t *testing.T) {
+	url := options.WebhookClientConnectionType("url")
+	injectedCABundle := []byte("injected-ca-bundle")
+	testCases := map[string]struct {
+		certMode     options.WebhookCertMode
+		caPEM        []byte
+		existing     bool
+		wantCABundle []byte
+	}{
+		"self-signed mode creates the configuration": {
+			certMode:     options.SelfSigned,
+			caPEM:        []byte("self-signed-ca"),
+			wantCABundle: []byte("self-signed-ca"),
+		},
+		"self-signed mode overwrites the CA bundle of the existing configuration": {
+			certMode:     options.SelfSigned,
+			caPEM:        []byte("self-signed-ca"),
+			existing:     true,
+			wantCABundle: []byte("self-signed-ca"),
+		},
+		"cert-manager mode keeps the injected CA bundle of the existing configuration": {
+			certMode:     options.CertManager,
+			existing:     true,
+			wantCABundle: injectedCABundle,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			ctx := context.Background()
+			w := &Config{
+				serviceNamespace:     "fleet-system",
+				serviceName:          "fleetwebhook",
+				servicePort:          9443,
+				clientConnectionType: &url,
+				certMode:             testCase.certMode,
+				caPEM:                testCase.caPEM,
+			}
+			scheme := runtime.NewScheme()
+			if err := admv1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add scheme: %v", err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add scheme: %v", err)
+			}
+			objects := []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "fleet-system"}}}
+			if testCase.existing {
+				webhooks := w.buildFleetValidatingWebhooks()
+				for i := range webhooks {
+					webhooks[i].ClientConfig.CABundle = injectedCABundle
+				}
+				objects = append(objects, &admv1.ValidatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{Name: fleetValidatingWebhookCfgName, UID: "existing"},
+					Webhooks:   webhooks,
+				})
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			if err := w.createValidatingWebhookConfiguration(ctx, fakeClient, w.buildFleetValidatingWebhooks(), fleetValidatingWebhookCfgName); err != nil {
+				t.Fatalf("createValidatingWebhookConfiguration() = %v, want nil", err)
+			}
+
+			var got admv1.ValidatingWebhookConfiguration
+			if err := fakeClient.Get(ctx, client.ObjectKey{Name: fleetValidatingWebhookCfgName}, &got); err != nil {
+				t.Fatalf("failed to get the webhook configuration: %v", err)
+			}
+			if testCase.existing && got.UID != "existing" {
+				t.Errorf("createValidatingWebhookConfiguration() recreated the existing configuration")
+			}
+			if len(got.OwnerReferences) != 1 {
+				t.Errorf("createValidatingWebhookConfiguration() owner references = %v, want the fleet-system namespace", got.OwnerReferences)
+			}
+			if _, ok := got.Annotations[certManagerInjectCAAnnotation]; ok != (testCase.certMode == options.CertManager) {
+				t.Errorf("createValidatingWebhookConfiguration() annotations = %v, cert-manager mode = %v", got.Annotations, testCase.certMode == options.CertManager)
+			}
+			for _, webhook := range got.Webhooks {
+				if !bytes.Equal(webhook.ClientConfig.CABundle, testCase.wantCABundle) {
+					t.Errorf("webhook %s has CA bundle %q, want %q", webhook.Name, webhook.ClientConfig.CABundle, testCase.wantCABundle)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCertAndKeyFile(t *testing.T) {
+	certDir := t.TempDir()
+	w := &Config{
+		serviceNamespace: "fleet-system",
+		serviceName:      "fleetwebhook",
+		certValidity:     time.Hour,
+	}
+	for i := 0; i < 2; i++ {
+		_, certPEM, keyPEM, err := w.genSelfSignedCert()
+		if err != nil {
+			t.Fatalf("genSelfSignedCert() = %v, want nil", err)
+		}
+		if err := writeCertAndKeyFile(certPEM, keyPEM, certDir); err != nil {
+			t.Fatalf("writeCertAndKeyFile() = %v, want nil", err)
+		}
+		if _, err := tls.LoadX509KeyPair(filepath.Join(certDir, fleetWebhookCertFileName), filepath.Join(certDir, fleetWebhookKeyFileName)); err != nil {
+			t.Fatalf("the written certificate/key pair cannot be loaded: %v", err)
+		}
+		gotCert, err := os.ReadFile(filepath.Join(certDir, fleetWebhookCertFileName))
+		if err != nil {
+			t.Fatalf("failed to read the certificate file: %v", err)
+		}
+		if !bytes.Equal(gotCert, certPEM) {
+			t.Errorf("writeCertAndKeyFile() wrote a different certificate")
+		}
+	}
+	entries, err := os.ReadDir(certDir)
+	if err != nil {
+		t.Fatalf("failed to read the certificate directory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("writeCertAndKeyFile() left %d files in the certificate directory, want 2", len(entries))
+	}
+	if err := writeCertAndKeyFile([]byte("invalid"), nil, certDir); err == nil {
+		t.Errorf("writeCertAndKeyFile() = nil, want an error for invalid certificate data")
+	}
+}
@@ -7,6 +7,8 @@ package framework
 
 import (
 	"os"
+	"sync"
+	"time"
 
 	"github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -25,6 +27,39 @@ var (
 	kubeconfigPath = os.Getenv("KUBECONFIG")
 )
 
+const (
+	// DefaultEventuallyTimeout is the default timeout of the assertions which wait for an expected state.
+	DefaultEventuallyTimeout = time.Minute * 3
+	// DefaultConsistentlyDuration is the default duration of the assertions which check a state is kept.
+	DefaultConsistentlyDuration = time.Second * 10
+	// DefaultPollingInterval is the default interval between two polls of an assertion.
+	DefaultPollingInterval = time.Millisecond * 250
+	// DefaultCleanupTimeout is the default timeout of cleaning up the resources tracked on a cluster.
+	DefaultCleanupTimeout = time.Minute
+)
+
+// OperationTimeouts are the timeouts of the operations on a test cluster.
+type OperationTimeouts struct {
+	// Eventually is the timeout of the assertions which wait for an expected state.
+	Eventually time.Duration
+	// Consistently is the duration of the assertions which check a state is kept.
+	Consistently time.Duration
+	// PollingInterval is the interval between two polls of an assertion.
+	PollingInterval time.Duration
+	// Cleanup is the timeout of cleaning up the resources tracked on the cluster.
+	Cleanup time.Duration
+}
+
+// DefaultOperationTimeouts returns the default timeouts of the operations on a test cluster.
+func DefaultOperationTimeouts() OperationTimeouts {
+	return OperationTimeouts{
+		Eventually:      DefaultEventuallyTimeout,
+		Consistently:    DefaultConsistentlyDuration,
+		PollingInterval: DefaultPollingInterval,
+		Cleanup:         DefaultCleanupTimeout,
+	}
+}
+
 // Cluster object defines the required clients based on the kubeconfig of the test cluster.
 type Cluster struct {
 	Scheme                                   *runtime.Scheme
@@ -36,14 +71,28 @@ type Cluster struct {
 	HubURL                                   string
 	RestMapper                               meta.RESTMapper
 	PricingProvider                          trackers.PricingProvider
+	// Timeouts are the timeouts of the operations on the cluster, e.g. the assertions run by EventuallyOnClusters.
+	Timeouts OperationTimeouts
+
+	// tracker tracks the resources created on the cluster for cleanup.
+	tracker *resourceTracker
+}
+
+// resourceTracker tracks the resources created on a cluster for cleanup, in the order of their creation.
+type resourceTracker struct {
+	mu      sync.Mutex
+	tracked []client.Object
 }
 
+// NewCluster returns a test cluster with the default operation timeouts; call GetClusterClient to set up its clients.
 func NewCluster(name, svcAccountName string, scheme *runtime.Scheme, pp trackers.PricingProvider) *Cluster {
 	return &Cluster{
 		Scheme:                                   scheme,
 		ClusterName:                              name,
 		PresentingServiceAccountInHubClusterName: svcAccountName,
 		PricingProvider:                          pp,
+		Timeouts:                                 DefaultOperationTimeouts(),
+		tracker:                                  &resourceTracker{},
 	}
 }
 
@@ -0,0 +1,154 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultParallelism is the default number of clusters the multi-cluster helpers operate on at the same time.
+	DefaultParallelism = 8
+)
+
+// ClusterActual returns a function which checks if a cluster is in the expected state, in the same way as the
+// actuals passed to gomega.Eventually do, i.e., it returns nil if the cluster is in the expected state.
+type ClusterActual func(cluster *Cluster) func() error
+
+// EventuallyOnClusters checks that all the clusters reach the expected state within the Eventually timeout of each
+// cluster, checking up to parallelism clusters at the same time; all the clusters are checked in parallel if
+// parallelism is not positive. The assertion fails with the errors of all the clusters which do not reach the
+// expected state.
+//
+// Note that the clusters are checked in separate goroutines, so the actual must not make gomega assertions itself.
+func EventuallyOnClusters(ctx context.Context, clusters []*Cluster, parallelism int, actual ClusterActual, description string) {
+	err := runOnClusters(ctx, clusters, parallelism, func(ctx context.Context, cluster *Cluster) error {
+		return pollUntilSucceeded(ctx, cluster.Timeouts.PollingInterval, cluster.Timeouts.Eventually, actual(cluster))
+	})
+	gomega.Expect(err).Should(gomega.Succeed(), description)
+}
+
+// ConsistentlyOnClusters checks that all the clusters stay in the expected state for the Consistently duration of
+// each cluster, checking up to parallelism clusters at the same time; all the clusters are checked in parallel if
+// parallelism is not positive. The assertion fails with the errors of all the clusters which leave the expected
+// state.
+//
+// Note that the clusters are checked in separate goroutines, so the actual must not make gomega assertions itself.
+func ConsistentlyOnClusters(ctx context.Context, clusters []*Cluster, parallelism int, actual ClusterActual, description string) {
+	err := runOnClusters(ctx, clusters, parallelism, func(ctx context.Context, cluster *Cluster) error {
+		return pollUntilFailed(ctx, cluster.Timeouts.PollingInterval, cluster.Timeouts.Consistently, actual(cluster))
+	})
+	gomega.Expect(err).Should(gomega.Succeed(), description)
+}
+
+// TrackForCleanup tracks resources created on the cluster, so that CleanupClusters deletes them. The cluster must
+// be created with NewCluster.
+func (c *Cluster) TrackForCleanup(objs ...client.Object) {
+	c.tracker.mu.Lock()
+	defer c.tracker.mu.Unlock()
+	c.tracker.tracked = append(c.tracker.tracked, objs...)
+}
+
+// CleanupClusters deletes the resources tracked on all the clusters, in the reverse order of their tracking, and
+// waits for them to be gone within the Cleanup timeout of each cluster, cleaning up to parallelism clusters at the
+// same time; all the clusters are cleaned up in parallel if parallelism is not positive. The resources are no longer
+// tracked once they are gone.
+func CleanupClusters(ctx context.Context, clusters []*Cluster, parallelism int) {
+	err := runOnClusters(ctx, clusters, parallelism, func(ctx context.Context, cluster *Cluster) error {
+		return cluster.cleanup(ctx)
+	})
+	gomega.Expect(err).Should(gomega.Succeed(), "Failed to clean up the tracked resources")
+}
+
+// cleanup deletes the resources tracked on the cluster and waits for them to be gone.
+func (c *Cluster) cleanup(ctx context.Context) error {
+	c.tracker.mu.Lock()
+	tracked := c.tracker.tracked
+	c.tracker.tracked = nil
+	c.tracker.mu.Unlock()
+
+	var errs []error
+	for i := len(tracked) - 1; i >= 0; i-- {
+		if err := c.KubeClient.Delete(ctx, tracked[i]); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete %T %s: %w", tracked[i], client.ObjectKeyFromObject(tracked[i]), err))
+		}
+	}
+	var remaining []client.Object
+	for _, obj := range tracked {
+		obj := obj
+		err := pollUntilSucceeded(ctx, c.Timeouts.PollingInterval, c.Timeouts.Cleanup, func() error {
+			if err := c.KubeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); !apierrors.IsNotFound(err) {
+				return fmt.Errorf("%T %s is not deleted yet: %w", obj, client.ObjectKeyFromObject(obj), err)
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+			remaining = append(remaining, obj)
+		}
+	}
+	if len(remaining) > 0 {
+		// keep tracking the resources which are not gone, so that the cleanup can be retried
+		c.TrackForCleanup(remaining...)
+	}
+	return errors.Join(errs...)
+}
+
+// runOnClusters runs an operation on the clusters, up to parallelism clusters at the same time, and returns the
+// errors of all the clusters on which the operation fails.
+func runOnClusters(ctx context.Context, clusters []*Cluster, parallelism int, operation func(ctx context.Context, cluster *Cluster) error) error {
+	if parallelism <= 0 || parallelism > len(clusters) {
+		parallelism = len(clusters)
+	}
+	if parallelism == 0 {
+		return nil
+	}
+	errs := make([]error, len(clusters))
+	workqueue.ParallelizeUntil(ctx, parallelism, len(clusters), func(i int) {
+		if err := operation(ctx, clusters[i]); err != nil {
+			errs[i] = fmt.Errorf("cluster %s: %w", clusters[i].ClusterName, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// pollUntilSucceeded polls a check until it succeeds, and returns its last error if it does not succeed in time.
+func pollUntilSucceeded(ctx context.Context, interval, timeout time.Duration, check func() error) error {
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(context.Context) (bool, error) {
+		lastErr = check()
+		return lastErr == nil, nil
+	})
+	if err != nil && lastErr != nil {
+		return fmt.Errorf("timed out after %s: %w", timeout, lastErr)
+	}
+	return err
+}
+
+// pollUntilFailed polls a check until it fails, and returns its error if it fails in time.
+func pollUntilFailed(ctx context.Context, interval, duration time.Duration, check func() error) error {
+	var checkErr error
+	err := wait.PollUntilContextTimeout(ctx, interval, duration, true, func(context.Context) (bool, error) {
+		checkErr = check()
+		return checkErr != nil, nil
+	})
+	if checkErr != nil {
+		return fmt.Errorf("failed within %s: %w", duration, checkErr)
+	}
+	if err != nil && !wait.Interrupted(err) {
+		return err
+	}
+	return nil
+}
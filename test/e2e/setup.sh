@@ -183,6 +183,7 @@ do
             --set namespace=fleet-system \
             --set enableV1Alpha1APIs=false \
             --set enableV1Beta1APIs=true \
+            --set enableFaultInjection=true \
             --set propertyProvider=$PROPERTY_PROVIDER \
             --set region=${REGIONS[$i]}
     else
@@ -199,6 +200,7 @@ do
             --set namespace=fleet-system \
             --set enableV1Alpha1APIs=false \
             --set enableV1Beta1APIs=true \
+            --set enableFaultInjection=true \
             --set propertyProvider=$PROPERTY_PROVIDER
     fi
 done
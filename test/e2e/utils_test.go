@@ -690,21 +690,13 @@ func checkIfAllMemberClustersHaveLeft() {
 }
 
 func checkIfPlacedWorkResourcesOnAllMemberClusters() {
-	for idx := range allMemberClusters {
-		memberCluster := allMemberClusters[idx]
-
-		workResourcesPlacedActual := workNamespaceAndConfigMapPlacedOnClusterActual(memberCluster)
-		Eventually(workResourcesPlacedActual, eventuallyDuration, eventuallyInterval).Should(Succeed(), "Failed to place work resources on member cluster %s", memberCluster.ClusterName)
-	}
+	framework.EventuallyOnClusters(ctx, allMemberClusters, framework.DefaultParallelism,
+		workNamespaceAndConfigMapPlacedOnClusterActual, "Failed to place work resources on member clusters")
 }
 
 func checkIfPlacedWorkResourcesOnAllMemberClustersConsistently() {
-	for idx := range allMemberClusters {
-		memberCluster := allMemberClusters[idx]
-
-		workResourcesPlacedActual := workNamespaceAndConfigMapPlacedOnClusterActual(memberCluster)
-		Consistently(workResourcesPlacedActual, consistentlyDuration, consistentlyInterval).Should(Succeed(), "Failed to place work resources on member cluster %s", memberCluster.ClusterName)
-	}
+	framework.ConsistentlyOnClusters(ctx, allMemberClusters, framework.DefaultParallelism,
+		workNamespaceAndConfigMapPlacedOnClusterActual, "Failed to place work resources on member clusters")
 }
 
 func checkIfPlacedNamespaceResourceOnAllMemberClusters() {
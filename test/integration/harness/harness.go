@@ -0,0 +1,361 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+// Package harness provides an integration test harness of the placement pipeline: it runs the hub agent controllers,
+// i.e. the placement controller, the scheduler, the rollout controller and the work generator, against a hub cluster
+// started with envtest, and simulates the member agents of the member clusters with the fake clusters of fleettest.
+//
+// The end-to-end behaviors of the placements, e.g. the staged rollouts, the evictions and the drifts of the placed
+// resources, can thus be tested in minutes without any kind clusters; note that the envtest hub cluster runs no
+// built-in controllers, e.g. the garbage collector, so the objects are not cascade deleted.
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	clusterv1beta1 "go.goms.io/fleet/apis/cluster/v1beta1"
+	placementv1alpha1 "go.goms.io/fleet/apis/placement/v1alpha1"
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/cmd/hubagent/options"
+	"go.goms.io/fleet/cmd/hubagent/workload"
+	"go.goms.io/fleet/pkg/fleettest"
+	"go.goms.io/fleet/pkg/utils"
+	"go.goms.io/fleet/pkg/utils/controller"
+)
+
+const (
+	// DefaultSyncInterval is the default interval at which the simulated member agents apply the works and send
+	// their heartbeats.
+	DefaultSyncInterval = time.Second
+
+	simulatedAgentReason = "SimulatedMemberAgent"
+)
+
+// ErrUnknownMemberCluster is returned when a member cluster is not run by the harness.
+var ErrUnknownMemberCluster = errors.New("the member cluster is not run by the harness")
+
+// servedResource is a kind served by the member clusters.
+type servedResource struct {
+	gvk        schema.GroupVersionKind
+	namespaced bool
+}
+
+// defaultServedResources are the kinds served by the member clusters by default; more kinds can be served with the
+// ServeResource method of the member clusters.
+var defaultServedResources = []servedResource{
+	{gvk: utils.NamespaceGVK},
+	{gvk: utils.ConfigMapGVK, namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("Secret"), namespaced: true},
+	{gvk: corev1.SchemeGroupVersion.WithKind("Service"), namespaced: true},
+	{gvk: utils.DeploymentGVK, namespaced: true},
+}
+
+// MemberClusterOptions are the options of a member cluster run by the harness.
+type MemberClusterOptions struct {
+	// Name is the name of the member cluster.
+	Name string
+	// Labels are the labels of the member cluster, with which the placements select it.
+	Labels map[string]string
+}
+
+// Options are the options of the harness.
+type Options struct {
+	// CRDDirectoryPaths are the paths of the CRDs installed in the hub cluster; it defaults to the CRDs of the
+	// repository.
+	CRDDirectoryPaths []string
+	// MemberClusters are the member clusters joined to the fleet when the harness starts.
+	MemberClusters []MemberClusterOptions
+	// SyncInterval is the interval at which the simulated member agents apply the works and send their heartbeats;
+	// it defaults to DefaultSyncInterval.
+	SyncInterval time.Duration
+	// ConfigureHubAgent, if set, customizes the options of the hub agent controllers, e.g. to enable the optional
+	// controllers, before they are set up.
+	ConfigureHubAgent func(*options.Options)
+}
+
+// Harness runs the placement pipeline against an envtest hub cluster with simulated member clusters.
+type Harness struct {
+	// Config is the config of the hub cluster.
+	Config *rest.Config
+	// Client is an uncached client of the hub cluster.
+	Client client.Client
+	// Scheme is the scheme of the Client, with all the fleet APIs registered.
+	Scheme *k8sruntime.Scheme
+
+	env          *envtest.Environment
+	members      map[string]*fleettest.FakeCluster
+	syncInterval time.Duration
+	cancel       context.CancelFunc
+	controllers  sync.WaitGroup
+	managerErr   chan error
+
+	mu     sync.Mutex
+	paused map[string]bool
+}
+
+// Start starts the hub cluster and the controllers, and joins the member clusters to the fleet.
+//
+// The harness must be stopped with Stop once the tests are done, even if Start fails.
+func Start(ctx context.Context, opts Options) (*Harness, error) {
+	if len(opts.CRDDirectoryPaths) == 0 {
+		opts.CRDDirectoryPaths = []string{defaultCRDDirectoryPath()}
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = DefaultSyncInterval
+	}
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Harness{
+		Scheme: scheme,
+		env: &envtest.Environment{
+			CRDDirectoryPaths:     opts.CRDDirectoryPaths,
+			ErrorIfCRDPathMissing: true,
+			Scheme:                scheme,
+			UseExistingCluster:    ptr.To(false),
+		},
+		members:      make(map[string]*fleettest.FakeCluster, len(opts.MemberClusters)),
+		syncInterval: opts.SyncInterval,
+		paused:       make(map[string]bool),
+	}
+	if h.Config, err = h.env.Start(); err != nil {
+		return h, fmt.Errorf("failed to start the hub cluster: %w", err)
+	}
+	if h.Client, err = client.New(h.Config, client.Options{Scheme: scheme}); err != nil {
+		return h, fmt.Errorf("failed to create the hub client: %w", err)
+	}
+
+	for _, member := range opts.MemberClusters {
+		if err := h.joinMemberCluster(ctx, member); err != nil {
+			return h, fmt.Errorf("failed to join member cluster %s: %w", member.Name, err)
+		}
+	}
+
+	mgr, err := ctrl.NewManager(h.Config, ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: "0"},
+		HealthProbeBindAddress: "0",
+		LeaderElection:         false,
+	})
+	if err != nil {
+		return h, fmt.Errorf("failed to create the controller manager: %w", err)
+	}
+	hubAgentOpts := options.NewOptions()
+	hubAgentOpts.LeaderElection.LeaderElect = false
+	hubAgentOpts.EnableV1Alpha1APIs = false
+	hubAgentOpts.EnableV1Beta1APIs = true
+	// the member clusters are joined by the harness rather than the member cluster controller, which would wait for
+	// the member agents to report their statuses in the internal member clusters
+	hubAgentOpts.Controllers = []string{"*", "-" + options.MemberClusterController}
+	if opts.ConfigureHubAgent != nil {
+		opts.ConfigureHubAgent(hubAgentOpts)
+	}
+
+	var managerCtx context.Context
+	managerCtx, h.cancel = context.WithCancel(ctx)
+	if err := workload.SetupControllers(managerCtx, &h.controllers, mgr, h.Config, hubAgentOpts); err != nil {
+		return h, fmt.Errorf("failed to set up the hub agent controllers: %w", err)
+	}
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		wait.UntilWithContext(ctx, h.syncMemberClusters, h.syncInterval)
+		return nil
+	})); err != nil {
+		return h, fmt.Errorf("failed to set up the simulated member agents: %w", err)
+	}
+	h.managerErr = make(chan error, 1)
+	go func() {
+		h.managerErr <- mgr.Start(managerCtx)
+	}()
+	return h, nil
+}
+
+// Stop stops the controllers and the hub cluster.
+func (h *Harness) Stop() error {
+	if h == nil {
+		return nil
+	}
+	var errs []error
+	if h.cancel != nil {
+		h.cancel()
+		h.controllers.Wait()
+		if h.managerErr != nil {
+			if err := <-h.managerErr; err != nil {
+				errs = append(errs, fmt.Errorf("the controller manager failed: %w", err))
+			}
+		}
+	}
+	// the hub cluster cannot be stopped if it has failed to start
+	if h.Config != nil {
+		if err := h.env.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop the hub cluster: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MemberCluster returns the simulated member cluster with the name, to which the works of the member cluster are
+// applied; the placed resources can be read, or drifted, with its DynamicClient.
+func (h *Harness) MemberCluster(name string) (*fleettest.FakeCluster, error) {
+	member, ok := h.members[name]
+	if !ok {
+		return nil, fmt.Errorf("member cluster %s: %w", name, ErrUnknownMemberCluster)
+	}
+	return member, nil
+}
+
+// MemberClusterNames returns the sorted names of the member clusters run by the harness.
+func (h *Harness) MemberClusterNames() []string {
+	names := make([]string, 0, len(h.members))
+	for name := range h.members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PauseMemberCluster sets whether the simulated member agent of the member cluster is paused; a paused member agent
+// neither applies the works nor sends heartbeats, as if the member cluster were disconnected from the fleet.
+func (h *Harness) PauseMemberCluster(name string, paused bool) error {
+	if _, ok := h.members[name]; !ok {
+		return fmt.Errorf("member cluster %s: %w", name, ErrUnknownMemberCluster)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.paused[name] = paused
+	return nil
+}
+
+// joinMemberCluster creates the member cluster with its namespace on the hub cluster, and reports it as joined and
+// healthy.
+func (h *Harness) joinMemberCluster(ctx context.Context, opts MemberClusterOptions) error {
+	mc := &clusterv1beta1.MemberCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   opts.Name,
+			Labels: opts.Labels,
+		},
+		Spec: clusterv1beta1.MemberClusterSpec{
+			Identity: rbacv1.Subject{
+				Kind: rbacv1.ServiceAccountKind,
+				Name: "member-agent-sa",
+			},
+		},
+	}
+	if err := h.Client.Create(ctx, mc); err != nil {
+		return err
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(utils.NamespaceNameFormat, opts.Name)}}
+	if err := h.Client.Create(ctx, namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	if err := h.sendHeartbeat(ctx, opts.Name); err != nil {
+		return err
+	}
+
+	member := fleettest.NewFakeCluster(opts.Name, h.Scheme)
+	for _, resource := range defaultServedResources {
+		member.ServeResource(resource.gvk, resource.namespaced)
+	}
+	h.members[opts.Name] = member
+	return nil
+}
+
+// syncMemberClusters runs the simulated member agents of the member clusters which are not paused once.
+func (h *Harness) syncMemberClusters(ctx context.Context) {
+	for _, name := range h.MemberClusterNames() {
+		h.mu.Lock()
+		paused := h.paused[name]
+		h.mu.Unlock()
+		if paused {
+			continue
+		}
+		if err := h.sendHeartbeat(ctx, name); err != nil {
+			klog.ErrorS(err, "Failed to send the heartbeat of the simulated member agent", "memberCluster", name)
+		}
+		if err := h.members[name].ApplyWorks(ctx, h.Client); err != nil {
+			klog.ErrorS(err, "Failed to apply the works of the simulated member agent", "memberCluster", name)
+		}
+	}
+}
+
+// sendHeartbeat reports the member agent of the member cluster as joined and healthy, with a new heartbeat.
+func (h *Harness) sendHeartbeat(ctx context.Context, name string) error {
+	var mc clusterv1beta1.MemberCluster
+	if err := h.Client.Get(ctx, client.ObjectKey{Name: name}, &mc); err != nil {
+		return err
+	}
+	return controller.UpdateStatusWithRetry(ctx, h.Client, &mc, func(mc *clusterv1beta1.MemberCluster) {
+		now := metav1.Now()
+		var agentStatus *clusterv1beta1.AgentStatus
+		for i := range mc.Status.AgentStatus {
+			if mc.Status.AgentStatus[i].Type == clusterv1beta1.MemberAgent {
+				agentStatus = &mc.Status.AgentStatus[i]
+			}
+		}
+		if agentStatus == nil {
+			mc.Status.AgentStatus = append(mc.Status.AgentStatus, clusterv1beta1.AgentStatus{Type: clusterv1beta1.MemberAgent})
+			agentStatus = &mc.Status.AgentStatus[len(mc.Status.AgentStatus)-1]
+		}
+		agentStatus.LastReceivedHeartbeat = now
+		for _, conditionType := range []clusterv1beta1.AgentConditionType{clusterv1beta1.AgentJoined, clusterv1beta1.AgentHealthy} {
+			meta.SetStatusCondition(&agentStatus.Conditions, metav1.Condition{
+				Type:               string(conditionType),
+				Status:             metav1.ConditionTrue,
+				Reason:             simulatedAgentReason,
+				ObservedGeneration: mc.Generation,
+			})
+		}
+	})
+}
+
+// newScheme returns the scheme with the APIs used by the hub agent.
+func newScheme() (*k8sruntime.Scheme, error) {
+	scheme := k8sruntime.NewScheme()
+	for _, addToScheme := range []func(*k8sruntime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apiextensionsv1.AddToScheme,
+		clusterv1beta1.AddToScheme,
+		placementv1alpha1.AddToScheme,
+		placementv1beta1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+	return scheme, nil
+}
+
+// defaultCRDDirectoryPath returns the path of the CRDs of the repository.
+func defaultCRDDirectoryPath() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "config", "crd", "bases")
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) Microsoft Corporation.
+Licensed under the MIT license.
+*/
+
+package harness
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	placementv1beta1 "go.goms.io/fleet/apis/placement/v1beta1"
+	"go.goms.io/fleet/pkg/utils"
+)
+
+const (
+	eventuallyTimeout  = 2 * time.Minute
+	eventuallyInterval = time.Second
+)
+
+func TestPlacementIsAppliedAndDriftIsCorrected(t *testing.T) {
+	ctx := context.Background()
+	h, err := Start(ctx, Options{
+		MemberClusters: []MemberClusterOptions{
+			{Name: "member-1", Labels: map[string]string{"env": "prod"}},
+			{Name: "member-2", Labels: map[string]string{"env": "canary"}},
+		},
+	})
+	defer func() {
+		if err := h.Stop(); err != nil {
+			t.Errorf("Stop() = %v, want nil", err)
+		}
+	}()
+	if err != nil {
+		t.Fatalf("Start() = %v, want nil", err)
+	}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "app"},
+		Data:       map[string]string{"key": "value"},
+	}
+	crp := &placementv1beta1.ClusterResourcePlacement{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: placementv1beta1.ClusterResourcePlacementSpec{
+			ResourceSelectors: []placementv1beta1.ClusterResourceSelector{
+				{Group: "", Version: "v1", Kind: "Namespace", Name: "app"},
+			},
+			Policy: &placementv1beta1.PlacementPolicy{PlacementType: placementv1beta1.PickAllPlacementType},
+		},
+	}
+	if err := h.Client.Create(ctx, namespace); err != nil {
+		t.Fatalf("Create() namespace = %v, want nil", err)
+	}
+	if err := h.Client.Create(ctx, configMap); err != nil {
+		t.Fatalf("Create() config map = %v, want nil", err)
+	}
+	if err := h.Client.Create(ctx, crp); err != nil {
+		t.Fatalf("Create() placement = %v, want nil", err)
+	}
+
+	for _, name := range h.MemberClusterNames() {
+		member, err := h.MemberCluster(name)
+		if err != nil {
+			t.Fatalf("MemberCluster(%s) = %v, want nil", name, err)
+		}
+		resourceClient := member.DynamicClient.Resource(utils.ConfigMapGVR).Namespace("app")
+		if err := wait.PollUntilContextTimeout(ctx, eventuallyInterval, eventuallyTimeout, true, func(ctx context.Context) (bool, error) {
+			_, err := resourceClient.Get(ctx, "config", metav1.GetOptions{})
+			return err == nil, nil
+		}); err != nil {
+			t.Fatalf("the config map is not applied to member cluster %s: %v", name, err)
+		}
+
+		// drift the config map, which the simulated member agent corrects when it applies the works again
+		drifted, err := resourceClient.Get(ctx, "config", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get() = %v, want nil", err)
+		}
+		drifted.Object["data"] = map[string]interface{}{"key": "drifted"}
+		if _, err := resourceClient.Update(ctx, drifted, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("Update() = %v, want nil", err)
+		}
+		if err := wait.PollUntilContextTimeout(ctx, eventuallyInterval, eventuallyTimeout, true, func(ctx context.Context) (bool, error) {
+			got, err := resourceClient.Get(ctx, "config", metav1.GetOptions{})
+			if err != nil {
+				return false, nil
+			}
+			data, _ := got.Object["data"].(map[string]interface{})
+			return data["key"] == "value", nil
+		}); err != nil {
+			t.Errorf("the drift of the config map is not corrected in member cluster %s: %v", name, err)
+		}
+	}
+}
+
+func TestPauseUnknownMemberCluster(t *testing.T) {
+	h := &Harness{}
+	if err := h.PauseMemberCluster("unknown", true); !errors.Is(err, ErrUnknownMemberCluster) {
+		t.Errorf("PauseMemberCluster() = %v, want %v", err, ErrUnknownMemberCluster)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"go.goms.io/fleet/pkg/utils/controller"
 )
 
 // FakeController is a fake controller which only stores one key.
@@ -40,6 +42,11 @@ func (f *FakeController) Enqueue(obj interface{}) {
 	f.mu.Unlock()
 }
 
+// EnqueueWithPriority enqueues a string type key regardless of the priority.
+func (f *FakeController) EnqueueWithPriority(obj interface{}, _ controller.Priority) {
+	f.Enqueue(obj)
+}
+
 // Run does nothing.
 func (f *FakeController) Run(_ context.Context, _ int) error {
 	return nil